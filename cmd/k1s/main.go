@@ -7,22 +7,48 @@
 // Usage:
 //
 //	k1s [options]
+//	k1s [options] KIND/NAME | KIND NAME
+//	kubectl k1s [options] KIND/NAME | KIND NAME
+//	k1s k1s://<context>/<namespace>/<kind>/<name>[?container=NAME&view=VIEW]
+//	k1s telemetry summarize [--dir DIR]
+//	k1s check [-n NAMESPACE] [-o text|json] [KIND/NAME | KIND NAME]
 //
 // Options:
 //
 //	-h, --help         Show help message
 //	-v, --version      Show version information
 //	-n, --namespace    Go directly to resources view for specified namespace
+//	-c, --context      Connect to the specified kubeconfig context
+//	--kubeconfig       Use this kubeconfig file instead of KUBECONFIG or ~/.kube/config
+//	--read-only        Disable every action that changes the cluster
+//	--no-color         Render without colors, like NO_COLOR
+//	--refresh          Refresh interval, in seconds or as a duration like 30s
+//	--clipboard        How to copy: auto, native or osc52 (for SSH sessions)
+//	--record FILE      Append snapshots of what k1s fetches to FILE, for bug reports
+//	--replay FILE      Replay a recording offline instead of connecting to a cluster
+//	--replay-at TIME   Replay the snapshot taken at or before TIME (RFC3339)
+//
+// Installed on the PATH as kubectl-k1s, k1s is also a kubectl plugin.
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/check"
+	"github.com/andrebassi/k1s/internal/adapters/deeplink"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/telemetry"
 	"github.com/andrebassi/k1s/internal/adapters/tui"
 )
 
@@ -34,15 +60,19 @@ var (
 )
 
 // preflightChecks verifies that kubectl is installed and kubeconfig is valid.
+// The kubeconfig is the --kubeconfig file if given, otherwise the files in
+// KUBECONFIG merged, otherwise ~/.kube/config.
+// If contextName is set (from --context or a k1s:// link) it must exist in
+// the kubeconfig; otherwise the current context is checked.
 // Returns an error if any check fails.
-func preflightChecks() error {
+func preflightChecks(kubeconfig, contextName string) error {
 	// Check if kubectl is installed
 	if _, err := exec.LookPath("kubectl"); err != nil {
 		return fmt.Errorf("kubectl not found in PATH. Please install kubectl: https://kubernetes.io/docs/tasks/tools/")
 	}
 
 	// Check if kubeconfig exists and has a valid context
-	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules := repository.KubeconfigRules(kubeconfig)
 	configOverrides := &clientcmd.ConfigOverrides{}
 	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
 
@@ -51,6 +81,13 @@ func preflightChecks() error {
 		return fmt.Errorf("failed to load kubeconfig: %v", err)
 	}
 
+	if contextName != "" {
+		if _, exists := rawConfig.Contexts[contextName]; !exists {
+			return fmt.Errorf("context '%s' not found in kubeconfig. Run: kubectl config get-contexts", contextName)
+		}
+		return nil
+	}
+
 	if rawConfig.CurrentContext == "" {
 		return fmt.Errorf("no current context set in kubeconfig. Run: kubectl config use-context <context-name>")
 	}
@@ -64,10 +101,29 @@ func preflightChecks() error {
 }
 
 // main initializes and runs the k1s TUI application.
-// It parses command-line arguments for namespace selection and help/version flags,
+// It parses command-line arguments for namespace and context selection and help/version flags,
 // then starts the bubbletea program with alternate screen and mouse support.
 func main() {
 	var namespace string
+	var kubeContext string
+	var kubeconfig string
+	var readOnly bool
+	var noColor bool
+	var refresh int
+	var clipboard string
+	var record string
+	var replay string
+	var replayAt time.Time
+	var link *deeplink.Link
+	var targetArgs []string
+
+	// Subcommands run without the TUI or a cluster connection
+	if len(os.Args) > 1 && os.Args[1] == "telemetry" {
+		os.Exit(runTelemetry(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(runCheck(os.Args[2:]))
+	}
 
 	// Parse command-line arguments manually to avoid external dependencies.
 	for i := 1; i < len(os.Args); i++ {
@@ -88,33 +144,146 @@ func main() {
 				fmt.Fprintf(os.Stderr, "Error: -n/--namespace requires an argument\n")
 				os.Exit(1)
 			}
+		case "-c", "--context":
+			if i+1 < len(os.Args) {
+				kubeContext = os.Args[i+1]
+				i++ // Skip the next argument
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: -c/--context requires an argument\n")
+				os.Exit(1)
+			}
+		case "--kubeconfig":
+			if i+1 < len(os.Args) {
+				kubeconfig = os.Args[i+1]
+				i++ // Skip the next argument
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --kubeconfig requires an argument\n")
+				os.Exit(1)
+			}
+		case "--read-only":
+			readOnly = true
+		case "--no-color":
+			noColor = true
+		case "--refresh":
+			if i+1 >= len(os.Args) {
+				fmt.Fprintf(os.Stderr, "Error: --refresh requires an argument\n")
+				os.Exit(1)
+			}
+			refresh = mustParseRefresh(os.Args[i+1])
+			i++ // Skip the next argument
+		case "--clipboard":
+			if i+1 >= len(os.Args) {
+				fmt.Fprintf(os.Stderr, "Error: --clipboard requires an argument\n")
+				os.Exit(1)
+			}
+			clipboard = os.Args[i+1]
+			i++ // Skip the next argument
+		case "--record", "--replay", "--replay-at":
+			if i+1 >= len(os.Args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires an argument\n", os.Args[i])
+				os.Exit(1)
+			}
+			switch os.Args[i] {
+			case "--record":
+				record = os.Args[i+1]
+			case "--replay":
+				replay = os.Args[i+1]
+			default:
+				replayAt = mustParseReplayAt(os.Args[i+1])
+			}
+			i++ // Skip the next argument
 		default:
-			// Check for -n=value format
-			if len(os.Args[i]) > 3 && os.Args[i][:3] == "-n=" {
+			// A k1s:// link opens a pod or workload directly
+			if deeplink.IsLink(os.Args[i]) {
+				parsed, err := deeplink.Parse(os.Args[i])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				link = parsed
+			} else if len(os.Args[i]) > 3 && os.Args[i][:3] == "-n=" {
+				// Check for -n=value format
 				namespace = os.Args[i][3:]
 			} else if len(os.Args[i]) > 12 && os.Args[i][:12] == "--namespace=" {
 				namespace = os.Args[i][12:]
+			} else if len(os.Args[i]) > 3 && os.Args[i][:3] == "-c=" {
+				kubeContext = os.Args[i][3:]
+			} else if len(os.Args[i]) > 10 && os.Args[i][:10] == "--context=" {
+				kubeContext = os.Args[i][10:]
+			} else if len(os.Args[i]) > 13 && os.Args[i][:13] == "--kubeconfig=" {
+				kubeconfig = os.Args[i][13:]
+			} else if len(os.Args[i]) > 10 && os.Args[i][:10] == "--refresh=" {
+				refresh = mustParseRefresh(os.Args[i][10:])
+			} else if len(os.Args[i]) > 12 && os.Args[i][:12] == "--clipboard=" {
+				clipboard = os.Args[i][12:]
+			} else if len(os.Args[i]) > 9 && os.Args[i][:9] == "--record=" {
+				record = os.Args[i][9:]
+			} else if len(os.Args[i]) > 9 && os.Args[i][:9] == "--replay=" {
+				replay = os.Args[i][9:]
+			} else if len(os.Args[i]) > 12 && os.Args[i][:12] == "--replay-at=" {
+				replayAt = mustParseReplayAt(os.Args[i][12:])
+			} else if !strings.HasPrefix(os.Args[i], "-") {
+				// KIND/NAME or KIND NAME, as kubectl takes them
+				targetArgs = append(targetArgs, os.Args[i])
 			} else {
 				fmt.Fprintf(os.Stderr, "Unknown option: %s\n", os.Args[i])
-				fmt.Fprintf(os.Stderr, "Use -h for help\n")
+				fmt.Fprintf(os.Stderr, "Use %s -h for help\n", programName())
 				os.Exit(1)
 			}
 		}
 	}
 
-	// Run preflight checks before starting the TUI
-	if err := preflightChecks(); err != nil {
+	initialKind, initialName, err := parseTarget(targetArgs)
+	if err == nil && initialKind != "" && link != nil {
+		err = fmt.Errorf("a link and %s cannot be opened together", strings.Join(targetArgs, " "))
+	}
+	if err == nil {
+		err = checkReplayOptions(replay, record, kubeContext, kubeconfig, link, replayAt)
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Use %s -h for help\n", programName())
 		os.Exit(1)
 	}
 
+	// Run preflight checks before starting the TUI; a replay needs neither
+	// kubectl nor a kubeconfig
+	contextName := kubeContext
+	if link != nil {
+		contextName = link.Context
+	}
+	if replay == "" {
+		if err := preflightChecks(kubeconfig, contextName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	model, err := tui.NewWithOptions(tui.Options{
-		Namespace: namespace,
+		Namespace:  namespace,
+		Context:    kubeContext,
+		Kubeconfig: kubeconfig,
+		Version:    version,
+		Link:       link,
+		ReadOnly:   readOnly,
+		NoColor:    noColor,
+		Refresh:    refresh,
+		Clipboard:  clipboard,
+		Record:     record,
+		Replay:     replay,
+		ReplayAt:   replayAt,
+
+		InitialKind: initialKind,
+		InitialName: initialName,
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing application: %v\n", err)
 		os.Exit(1)
 	}
+	// Left on the terminal so they can be read after quitting
+	for _, w := range model.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
 
 	p := tea.NewProgram(
 		model,
@@ -122,12 +291,230 @@ func main() {
 		tea.WithMouseCellMotion(),
 	)
 
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	model.Close()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running application: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// parseTarget parses the pod or workload to open from the arguments that
+// are not options, given the way kubectl takes them: "deploy/api" or
+// "deploy api". No arguments is no target.
+func parseTarget(args []string) (string, string, error) {
+	var kind, name string
+	switch {
+	case len(args) == 0:
+		return "", "", nil
+	case len(args) == 1 && strings.Contains(args[0], "/"):
+		kind, name, _ = strings.Cut(args[0], "/")
+	case len(args) == 2 && !strings.Contains(args[0], "/"):
+		kind, name = args[0], args[1]
+	case len(args) == 1:
+		return "", "", fmt.Errorf("%q needs a kind, as in pod/%s or deployment/%s", args[0], args[0], args[0])
+	default:
+		return "", "", fmt.Errorf("expected KIND/NAME or KIND NAME, got %q", strings.Join(args, " "))
+	}
+	if kind == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("expected KIND/NAME or KIND NAME, got %q", strings.Join(args, " "))
+	}
+	if _, err := repository.ParseTargetKind(kind); err != nil {
+		return "", "", err
+	}
+	return kind, name, nil
+}
+
+// programName is how k1s was invoked: "kubectl k1s" when run as the
+// kubectl-k1s plugin, "k1s" otherwise.
+func programName() string {
+	if strings.HasPrefix(filepath.Base(os.Args[0]), "kubectl-") {
+		return "kubectl k1s"
+	}
+	return "k1s"
+}
+
+// mustParseRefresh parses the --refresh interval, whole seconds or a
+// duration such as 30s or 1m, exiting on an invalid value.
+func mustParseRefresh(value string) int {
+	seconds, err := strconv.Atoi(value)
+	if err != nil {
+		d, derr := time.ParseDuration(value)
+		if derr != nil || d%time.Second != 0 {
+			seconds = 0
+		} else {
+			seconds = int(d / time.Second)
+		}
+	}
+	if seconds < 1 {
+		fmt.Fprintf(os.Stderr, "Error: --refresh must be a whole number of seconds, at least 1 (got %q)\n", value)
+		os.Exit(1)
+	}
+	return seconds
+}
+
+// mustParseReplayAt parses the --replay-at time, exiting on an invalid
+// value.
+func mustParseReplayAt(value string) time.Time {
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --replay-at must be an RFC3339 time such as 2024-05-16T10:42:00Z (got %q)\n", value)
+		os.Exit(1)
+	}
+	return at
+}
+
+// checkReplayOptions rejects the options that don't go with a replay,
+// which has no cluster to connect to or to record.
+func checkReplayOptions(replay, record, kubeContext, kubeconfig string, link *deeplink.Link, replayAt time.Time) error {
+	switch {
+	case replay == "" && !replayAt.IsZero():
+		return fmt.Errorf("--replay-at needs --replay")
+	case replay == "":
+		return nil
+	case record != "":
+		return fmt.Errorf("--record and --replay cannot be used together")
+	case kubeContext != "" || kubeconfig != "" || link != nil:
+		return fmt.Errorf("--replay uses the context of the recording; --context, --kubeconfig and links cannot be given")
+	}
+	return nil
+}
+
+// runTelemetry handles "k1s telemetry <command>" and returns the exit code.
+// The only command is summarize, which aggregates the local telemetry files
+// into a report on stdout.
+func runTelemetry(args []string) int {
+	if len(args) == 0 || args[0] != "summarize" {
+		fmt.Fprintf(os.Stderr, "Usage: k1s telemetry summarize [--dir DIR]\n")
+		return 1
+	}
+
+	dir := ""
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == "--dir" && i+1 < len(args):
+			dir = args[i+1]
+			i++
+		case len(args[i]) > 6 && args[i][:6] == "--dir=":
+			dir = args[i][6:]
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown option: %s\n", args[i])
+			return 1
+		}
+	}
+
+	// Fall back to the configured directory, then the default one
+	if dir == "" {
+		if cfg, err := configs.Load(); err == nil {
+			dir = cfg.Telemetry.Dir
+		}
+	}
+	if dir == "" {
+		var err error
+		if dir, err = telemetry.DefaultDir(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	summary, err := telemetry.Summarize(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Directory: %s\n\n", dir)
+	summary.WriteReport(os.Stdout)
+	return 0
+}
+
+// checkTimeout bounds a whole "k1s check" run, so that a script is not left
+// waiting on an unreachable cluster.
+const checkTimeout = 60 * time.Second
+
+// runCheck handles "k1s check", which diagnoses the pods of a namespace, or
+// of a workload given as KIND/NAME or KIND NAME, without the TUI. The
+// report goes to stdout as text or, with -o json, as JSON. Returns 0 when
+// every pod is healthy, 1 when one is not and 2 when the check could not
+// run. Without -n, the namespace of the kubeconfig context is used, as
+// kubectl does.
+func runCheck(args []string) int {
+	var namespace, kubeContext, kubeconfig string
+	output := "text"
+	var targetArgs []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "-n", "--namespace", "-c", "--context", "--kubeconfig", "-o", "--output":
+			if !hasValue {
+				if i+1 >= len(args) {
+					fmt.Fprintf(os.Stderr, "Error: %s requires an argument\n", name)
+					return 2
+				}
+				value = args[i+1]
+				i++ // Skip the next argument
+			}
+			switch name {
+			case "-n", "--namespace":
+				namespace = value
+			case "-c", "--context":
+				kubeContext = value
+			case "--kubeconfig":
+				kubeconfig = value
+			default:
+				output = value
+			}
+		default:
+			if strings.HasPrefix(arg, "-") {
+				fmt.Fprintf(os.Stderr, "Unknown option: %s\n", arg)
+				fmt.Fprintf(os.Stderr, "Usage: %s check [-n NAMESPACE] [-o text|json] [KIND/NAME | KIND NAME]\n", programName())
+				return 2
+			}
+			targetArgs = append(targetArgs, arg)
+		}
+	}
+	if output != "text" && output != "json" {
+		fmt.Fprintf(os.Stderr, "Error: --output must be text or json (got %q)\n", output)
+		return 2
+	}
+	kind, name, err := parseTarget(targetArgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	client, err := repository.NewClientFor(kubeconfig, kubeContext)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if namespace == "" {
+		namespace = client.ContextNamespace()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+	report, err := check.Run(ctx, client.Clientset(), namespace, kind, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	if output == "json" {
+		if err := report.WriteJSON(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	} else {
+		report.WriteText(os.Stdout)
+	}
+	if !report.Healthy {
+		return 1
+	}
+	return 0
+}
+
 // printHelp displays the comprehensive help message including usage,
 // keyboard shortcuts, features, and configuration options.
 func printHelp() {
@@ -137,11 +524,51 @@ One screen to see why your pod is broken.
 
 USAGE:
     k1s [OPTIONS]
+    k1s [OPTIONS] KIND/NAME
+    k1s [OPTIONS] KIND NAME
+    k1s LINK
+    k1s telemetry summarize [--dir DIR]
+    k1s check [-n NS] [-c CTX] [--kubeconfig PATH] [-o text|json] [KIND/NAME]
 
 OPTIONS:
     -h, --help            Show this help message
     -v, --version         Show version information
     -n, --namespace NS    Go directly to resources view for namespace NS
+    -c, --context CTX     Connect to kubeconfig context CTX instead of the current one
+    --kubeconfig PATH     Use the kubeconfig at PATH instead of KUBECONFIG
+    --read-only           Disable every action that changes the cluster (delete,
+                          scale, restart, exec, edit, copy, cordon, drain, ...)
+    --no-color            Render without colors, using bold, underline and
+                          reverse video instead (also set by NO_COLOR)
+    --refresh SECONDS     Refresh every SECONDS (or a duration like 30s) instead
+                          of refresh_interval_seconds; Z pauses refreshing
+    --clipboard MODE      auto (default): system clipboard, falling back to the
+                          terminal's OSC52 sequence, as over SSH; native; osc52
+    --record FILE         Append a snapshot of the namespace to FILE at every
+                          refresh, for reproducing a bug report (Secret values
+                          are left out)
+    --replay FILE         Replay a recording offline, read-only, instead of
+                          connecting to a cluster
+    --replay-at TIME      Replay the snapshot taken at or before TIME (RFC3339)
+                          instead of the last one
+
+LINKS:
+    k1s://<context>/<namespace>/<kind>/<name>[?container=NAME&view=VIEW]
+
+    Opens a pod or workload directly. Kind is pod, deployment, statefulset
+    or daemonset; view is logs, events, metrics or details. Copy a link to
+    the current pod from the pod actions menu (a). Quote links in the shell:
+        k1s 'k1s://prod/default/pod/api-7d9f?container=app&view=logs'
+
+KUBECTL PLUGIN:
+    Installed on the PATH as kubectl-k1s, k1s runs as "kubectl k1s" and
+    opens a pod or workload given the way kubectl takes them. Kind is pod,
+    deployment, statefulset, daemonset or job, or a plural or short name
+    (po, deploy, sts, ds); a workload opens on its worst pod. Without -n,
+    the namespace of the kubeconfig context is used:
+        ln -s "$(command -v k1s)" ~/.local/bin/kubectl-k1s
+        kubectl k1s -n prod deployment/payments
+        kubectl k1s pod payments-abc123
 
 DASHBOARD LAYOUT:
     ┌─────────────────────┬─────────────────────┐
@@ -164,21 +591,34 @@ KEYBOARD SHORTCUTS:
     1-4              Focus panel directly
     F                Toggle fullscreen
     r                Refresh data
+    H                Highlight changes since the last refresh
+    Z                Pause or resume refreshing (paused shows [PAUSED])
+    !                Warnings of the namespace in the last 15m, by object;
+                     Enter jumps to the pod or workload
+    E                Error log: every failed request of the session
+    o                Workload columns (restarts, age, pods, node, images, a label)
+    </>              Scroll the workload columns when they don't fit
+    C                Cluster switcher: contexts with cluster health and version;
+                     f marks favorites, r probes again
     ?                Show help
     q                Quit
 
   Logs Panel:
-    f                Toggle follow mode
+    f                Toggle follow mode (streams new lines as they are written)
     /                Search/filter logs
     c                Clear filter
     e                Jump to next error
     [/]              Switch container (multi-container pods)
     T                Cycle time filter (All, 5m, 15m, 1h, 6h)
     P                Toggle previous container logs
+    D                Compare previous and current logs
+    Ctrl+S           Save the displayed logs to a file
     Enter            Fullscreen → Enter again to copy
 
   Events Panel:
     w                Toggle warnings only
+    x                Export displayed events as CSV
+    Ctrl+S           Save the displayed events to a file
     Enter            Fullscreen → Enter again to copy
 
   Pod Details Panel:
@@ -191,7 +631,7 @@ KEYBOARD SHORTCUTS:
     Enter            Show kubectl describe output
 
   Action Menus:
-    a                Pod actions (delete, exec, port-forward, describe)
+    a                Pod actions (delete, exec, port-forward, describe, copy link)
     y                Copy kubectl command to clipboard
 
 FEATURES:
@@ -206,10 +646,24 @@ FEATURES:
     • Multi-container pod support
 
 CONFIGURATION:
-    Config file: ~/.config/k1s/configs.json
+    Config file: ~/.config/k1s/config.json
+                 {"default_namespace": "web", "log_line_limit": 200,
+                  "refresh_interval_seconds": 5, "events_warnings_only": true,
+                  "theme": "color-blind", "clipboard": "osc52",
+                  "osc52_max_bytes": 74994,
+                  "workload_columns": ["age", "pods", "label:app"]}
+                 Flags override environment variables, which override the file
+    Telemetry:   opt in with {"telemetry": {"usage": true, "crash_reports": true}}
+                 Events stay local in ~/.local/state/k1s/telemetry/ and are
+                 never sent anywhere; "k1s telemetry summarize" prints a report
     Environment:
-      KUBECONFIG        Path to kubeconfig (default: ~/.kube/config)
-      K1S_NAMESPACE     Initial namespace (default: default)
+      KUBECONFIG            Kubeconfig paths, ":"-separated and merged (default: ~/.kube/config)
+      K1S_NAMESPACE         Initial namespace (default: last used)
+      K1S_CONTEXT           Initial context (default: last used)
+      K1S_LOG_TAIL_LINES    Lines of log history to fetch (default: 200)
+      K1S_REFRESH_INTERVAL  Refresh interval in seconds (default: 5)
+      K1S_READ_ONLY         Set to true for read-only mode, like --read-only
+      NO_COLOR              Set to any value to render without colors, like --no-color
 
 For more information, visit: https://github.com/andrebassi/k1s
 `
@@ -1,14 +1,17 @@
 // Package config provides configuration management for k1s.
 //
-// Configuration is stored as JSON in ~/.config/k1s/configs.json and includes
+// Configuration is stored as JSON in ~/.config/k1s/config.json and includes
 // user preferences such as last used namespace, context, theme, and favorites.
 // The package provides automatic persistence and default values for all settings.
 package configs
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 )
 
 // Config holds the user preferences and application state that persists
@@ -17,6 +20,10 @@ type Config struct {
 	// LastNamespace is the most recently used Kubernetes namespace.
 	LastNamespace string `json:"last_namespace"`
 
+	// DefaultNamespace is the namespace to start in. When unset, k1s starts
+	// in LastNamespace.
+	DefaultNamespace string `json:"default_namespace,omitempty"`
+
 	// LastContext is the most recently used Kubernetes context.
 	LastContext string `json:"last_context"`
 
@@ -26,14 +33,266 @@ type Config struct {
 	// FavoriteItems contains user-bookmarked resources for quick access.
 	FavoriteItems []string `json:"favorite_items"`
 
-	// LogLineLimit specifies the maximum number of log lines to fetch per container.
+	// FavoriteContexts lists the Kubernetes contexts marked as favorites
+	// in the cluster switcher, which lists them first.
+	FavoriteContexts []string `json:"favorite_contexts,omitempty"`
+
+	// LogLineLimit specifies how many lines of log history are fetched
+	// (the log tail).
 	LogLineLimit int `json:"log_line_limit"`
 
 	// RefreshInterval specifies the data refresh interval in seconds.
 	RefreshInterval int `json:"refresh_interval_seconds"`
 
-	// Theme specifies the color theme name (reserved for future use).
+	// EventsWarningsOnly makes the events panel start with only Warning
+	// events shown. Toggled interactively and saved on quit.
+	EventsWarningsOnly bool `json:"events_warnings_only"`
+
+	// LogTimeFilter is the logs panel time filter ("5m", "15m", "1h", "6h";
+	// empty for all logs). Changed interactively and saved on quit.
+	LogTimeFilter string `json:"log_time_filter,omitempty"`
+
+	// Theme is the color theme: "default", "high-contrast", "color-blind"
+	// or "no-color". Changed interactively and saved on quit; NO_COLOR
+	// overrides it for the session without saving.
 	Theme string `json:"theme"`
+
+	// Confirmations maps action identifiers (see Action* constants) to the
+	// confirmation level they require. Unset actions use their default level.
+	Confirmations map[string]ConfirmLevel `json:"confirmations,omitempty"`
+
+	// QuickActions runs scale and restart without a confirmation dialog,
+	// whatever Confirmations says; a context's own confirmations still
+	// apply. It never applies to deletes, and protected namespaces still
+	// require typed confirmation.
+	QuickActions bool `json:"quick_actions,omitempty"`
+
+	// Contexts holds settings that apply only to one Kubernetes context,
+	// keyed by context name. They take precedence over the global settings.
+	Contexts map[string]ContextSettings `json:"contexts,omitempty"`
+
+	// ProtectedNamespaces lists namespaces where every mutating action
+	// requires typed confirmation. Unset means DefaultProtectedNamespaces;
+	// an empty list protects nothing.
+	ProtectedNamespaces []string `json:"protected_namespaces,omitempty"`
+
+	// Telemetry controls local usage metrics and crash reports. Both are
+	// off by default and nothing is ever sent over the network.
+	Telemetry TelemetrySettings `json:"telemetry"`
+
+	// Clipboard is how text is copied: "auto" (the system clipboard, and
+	// OSC52 when that fails, as over SSH), "native" or "osc52". Unset means
+	// "auto"; --clipboard overrides it.
+	Clipboard string `json:"clipboard,omitempty"`
+
+	// OSC52MaxBytes is the most bytes copied through OSC52; longer text is
+	// truncated, since terminals drop sequences past their own limit.
+	OSC52MaxBytes int `json:"osc52_max_bytes,omitempty"`
+
+	// LogBufferLines is how many log lines the logs panel keeps while
+	// following; past it the oldest lines are dropped.
+	LogBufferLines int `json:"log_buffer_lines,omitempty"`
+
+	// ForceDeleteNamespaceAfter is how many seconds a namespace must have
+	// been Terminating before force delete, which skips its finalizers, is
+	// offered for it.
+	ForceDeleteNamespaceAfter int `json:"force_delete_namespace_after_seconds,omitempty"`
+
+	// ListTimeout is how many seconds a list or get of the API server may
+	// take before it is abandoned and the view offers to retry it.
+	ListTimeout int `json:"list_timeout_seconds,omitempty"`
+
+	// LogTimeout is ListTimeout for log fetches, which read whole
+	// containers' output. Followed logs stream with no timeout.
+	LogTimeout int `json:"log_timeout_seconds,omitempty"`
+
+	// WorkloadColumns lists the optional columns of the workloads table:
+	// "restarts", "age", "pods", "node", "images" and "label:<key>" for the
+	// value of one label. Unset shows age and pods. Changed interactively
+	// with the columns picker (o) and saved on quit.
+	WorkloadColumns []string `json:"workload_columns"`
+}
+
+// Clipboard modes for Config.Clipboard.
+const (
+	ClipboardAuto   = "auto"
+	ClipboardNative = "native"
+	ClipboardOSC52  = "osc52"
+)
+
+// DefaultOSC52MaxBytes is OSC52MaxBytes when unset: its base64 encoding
+// just fits the 100000 bytes many terminals accept in a sequence.
+const DefaultOSC52MaxBytes = 74994
+
+// DefaultLogBufferLines is LogBufferLines when unset.
+const DefaultLogBufferLines = 10000
+
+// DefaultForceDeleteNamespaceAfter is ForceDeleteNamespaceAfter when unset:
+// long enough for the namespace controller to delete ordinary contents.
+const DefaultForceDeleteNamespaceAfter = 300
+
+// DefaultListTimeout and DefaultLogTimeout are ListTimeout and LogTimeout
+// when unset.
+const (
+	DefaultListTimeout = 10
+	DefaultLogTimeout  = 30
+)
+
+// ValidClipboard reports whether mode is one of the Clipboard* modes.
+func ValidClipboard(mode string) bool {
+	switch mode {
+	case ClipboardAuto, ClipboardNative, ClipboardOSC52:
+		return true
+	}
+	return false
+}
+
+// TelemetrySettings opts in to local-only telemetry files, which can be
+// aggregated with "k1s telemetry summarize".
+type TelemetrySettings struct {
+	// Usage records anonymized counts of actions and views opened.
+	Usage bool `json:"usage"`
+
+	// CrashReports records panics with their stack trace.
+	CrashReports bool `json:"crash_reports"`
+
+	// Dir overrides the default directory (~/.local/state/k1s/telemetry).
+	Dir string `json:"dir,omitempty"`
+}
+
+// ContextSettings holds per-context overrides of the global settings.
+type ContextSettings struct {
+	// Confirmations overrides Config.Confirmations for this context.
+	Confirmations map[string]ConfirmLevel `json:"confirmations,omitempty"`
+
+	// ProtectedNamespaces overrides Config.ProtectedNamespaces for this
+	// context when set.
+	ProtectedNamespaces []string `json:"protected_namespaces,omitempty"`
+}
+
+// ConfirmLevel controls how much confirmation a mutating action requires.
+type ConfirmLevel string
+
+// Confirmation levels, from least to most friction.
+const (
+	ConfirmNone  ConfirmLevel = "none"          // Run immediately
+	ConfirmYesNo ConfirmLevel = "confirm"       // Yes/No dialog
+	ConfirmTyped ConfirmLevel = "typed-confirm" // Type the resource name to proceed
+)
+
+// Action identifiers used as keys in Confirmations.
+const (
+	ActionDeletePod            = "delete-pod"
+	ActionScale                = "scale"
+	ActionRestart              = "restart"
+	ActionForceDeleteNamespace = "force-delete-namespace"
+	ActionDeleteNamespace      = "delete-namespace" // Always asks for the name to be typed
+	ActionDrainNode            = "drain-node"
+	ActionCordonNode           = "cordon-node" // Cordon and uncordon
+	ActionEdit                 = "edit"
+	ActionRemoveSchedulingGate = "remove-scheduling-gate"
+	ActionDeleteConfigMap      = "delete-configmap"
+	ActionDeleteSecret         = "delete-secret"
+	ActionPromoteRollout       = "promote-rollout"
+	ActionAbortRollout         = "abort-rollout" // Abort and retry
+	ActionTriggerCronJob       = "trigger-cronjob"
+	ActionRollbackDeployment   = "rollback-deployment"
+	ActionSetPartition         = "set-partition"    // Advance a partitioned StatefulSet rollout
+	ActionDebugContainer       = "debug-container"  // Inject an ephemeral debug container
+	ActionForceDeletePod       = "force-delete-pod" // Always asks, since the dialog spells out the risks
+	ActionSetImage             = "set-image"        // Change a container image, rolling out new pods
+	ActionSetResources         = "set-resources"    // Change a container's requests and limits, rolling out new pods
+)
+
+// IsValid reports whether the level is one of the known confirmation levels.
+func (l ConfirmLevel) IsValid() bool {
+	switch l {
+	case ConfirmNone, ConfirmYesNo, ConfirmTyped:
+		return true
+	}
+	return false
+}
+
+// defaultConfirmLevel returns the built-in level for an action.
+// Scaling has always run without a dialog; everything else asks first.
+func defaultConfirmLevel(action string) ConfirmLevel {
+	if action == ActionScale {
+		return ConfirmNone
+	}
+	return ConfirmYesNo
+}
+
+// ConfirmLevelFor resolves the confirmation level for an action in the given
+// Kubernetes context. Precedence: per-context setting, then QuickActions for
+// scale and restart, then global setting, then the action's default.
+// Unknown level values are ignored. A force delete of a pod asks at least
+// yes/no whatever the setting, and deleting a namespace, with everything
+// in it, always asks for its name to be typed.
+func (c *Config) ConfirmLevelFor(kubeContext, action string) ConfirmLevel {
+	if action == ActionDeleteNamespace {
+		return ConfirmTyped
+	}
+	level := c.configuredConfirmLevel(kubeContext, action)
+	if action == ActionForceDeletePod && level == ConfirmNone {
+		return ConfirmYesNo
+	}
+	return level
+}
+
+func (c *Config) configuredConfirmLevel(kubeContext, action string) ConfirmLevel {
+	if settings, ok := c.Contexts[kubeContext]; ok {
+		if level := settings.Confirmations[action]; level.IsValid() {
+			return level
+		}
+	}
+	if c.QuickActions && (action == ActionScale || action == ActionRestart) {
+		return ConfirmNone
+	}
+	if level := c.Confirmations[action]; level.IsValid() {
+		return level
+	}
+	return defaultConfirmLevel(action)
+}
+
+// ConfirmLevelIn resolves the confirmation level for an action on an object
+// in namespace. It is ConfirmLevelFor, escalated to ConfirmTyped when the
+// namespace is protected, so no per-action setting can lower the friction
+// there. Cluster-scoped actions pass an empty namespace.
+func (c *Config) ConfirmLevelIn(kubeContext, namespace, action string) ConfirmLevel {
+	if c.IsProtectedNamespace(kubeContext, namespace) {
+		return ConfirmTyped
+	}
+	return c.ConfirmLevelFor(kubeContext, action)
+}
+
+// DefaultProtectedNamespaces are protected when no list is configured.
+var DefaultProtectedNamespaces = []string{"kube-system", "kube-public", "kube-node-lease", "istio-system"}
+
+// ProtectedNamespacesFor returns the protected namespaces in a Kubernetes
+// context. Precedence: per-context list, then global list, then
+// DefaultProtectedNamespaces.
+func (c *Config) ProtectedNamespacesFor(kubeContext string) []string {
+	if settings, ok := c.Contexts[kubeContext]; ok && settings.ProtectedNamespaces != nil {
+		return settings.ProtectedNamespaces
+	}
+	if c.ProtectedNamespaces != nil {
+		return c.ProtectedNamespaces
+	}
+	return DefaultProtectedNamespaces
+}
+
+// IsProtectedNamespace reports whether namespace is protected in the given
+// Kubernetes context. Bulk operations are not allowed in protected namespaces.
+func (c *Config) IsProtectedNamespace(kubeContext, namespace string) bool {
+	if namespace == "" {
+		return false
+	}
+	for _, ns := range c.ProtectedNamespacesFor(kubeContext) {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
 }
 
 // DefaultConfig returns a new Config with sensible default values.
@@ -41,14 +300,138 @@ type Config struct {
 // specific values are not set.
 func DefaultConfig() *Config {
 	return &Config{
-		LastNamespace:    "default",
-		LastResourceType: "deployments",
-		LogLineLimit:     500,
-		RefreshInterval:  5,
-		Theme:            "default",
+		LastNamespace:      "default",
+		LastResourceType:   "deployments",
+		LogLineLimit:       200,
+		RefreshInterval:    5,
+		EventsWarningsOnly: true,
+		Theme:              "default",
+		Clipboard:          ClipboardAuto,
+		OSC52MaxBytes:      DefaultOSC52MaxBytes,
+		LogBufferLines:     DefaultLogBufferLines,
+
+		ForceDeleteNamespaceAfter: DefaultForceDeleteNamespaceAfter,
+		ListTimeout:               DefaultListTimeout,
+		LogTimeout:                DefaultLogTimeout,
+	}
+}
+
+// fillDefaults replaces settings a config file left unusable (zero or
+// negative counts) with their defaults.
+func (c *Config) fillDefaults() {
+	defaults := DefaultConfig()
+	if c.LogLineLimit <= 0 {
+		c.LogLineLimit = defaults.LogLineLimit
+	}
+	if c.RefreshInterval <= 0 {
+		c.RefreshInterval = defaults.RefreshInterval
+	}
+	if c.Clipboard == "" {
+		c.Clipboard = defaults.Clipboard
+	}
+	if c.OSC52MaxBytes <= 0 {
+		c.OSC52MaxBytes = defaults.OSC52MaxBytes
+	}
+	if c.LogBufferLines <= 0 {
+		c.LogBufferLines = defaults.LogBufferLines
+	}
+	if c.ForceDeleteNamespaceAfter <= 0 {
+		c.ForceDeleteNamespaceAfter = defaults.ForceDeleteNamespaceAfter
+	}
+	if c.ListTimeout <= 0 {
+		c.ListTimeout = defaults.ListTimeout
+	}
+	if c.LogTimeout <= 0 {
+		c.LogTimeout = defaults.LogTimeout
 	}
 }
 
+// Environment variables that override the config file. Command-line flags
+// take precedence over both.
+const (
+	EnvNamespace       = "K1S_NAMESPACE"
+	EnvContext         = "K1S_CONTEXT"
+	EnvLogLineLimit    = "K1S_LOG_TAIL_LINES"
+	EnvRefreshInterval = "K1S_REFRESH_INTERVAL"
+	EnvReadOnly        = "K1S_READ_ONLY" // Read by ReadOnlyEnv; not a config file setting
+	EnvNoColor         = "NO_COLOR"      // Read by NoColorEnv; see https://no-color.org
+)
+
+// ApplyEnv overrides settings with the environment variables above, read
+// through getenv (usually os.Getenv). Unset variables are ignored. Values
+// that are not positive integers where one is expected are skipped and
+// reported in the returned error; the other variables still apply.
+func (c *Config) ApplyEnv(getenv func(string) string) error {
+	if ns := getenv(EnvNamespace); ns != "" {
+		c.DefaultNamespace = ns
+	}
+	if ctx := getenv(EnvContext); ctx != "" {
+		c.LastContext = ctx
+	}
+
+	var errs []error
+	for _, v := range []struct {
+		name   string
+		target *int
+	}{
+		{EnvLogLineLimit, &c.LogLineLimit},
+		{EnvRefreshInterval, &c.RefreshInterval},
+	} {
+		s := getenv(v.name)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			errs = append(errs, fmt.Errorf("ignoring %s=%q: not a positive integer", v.name, s))
+			continue
+		}
+		*v.target = n
+	}
+	return errors.Join(errs...)
+}
+
+// WithEnv returns a copy of the config with ApplyEnv applied, leaving c
+// unchanged. The copy holds the settings of the session while c is what
+// gets saved, so that environment overrides never end up in the file.
+func (c *Config) WithEnv(getenv func(string) string) (*Config, error) {
+	session := *c
+	err := session.ApplyEnv(getenv)
+	return &session, err
+}
+
+// ReadOnlyEnv reports whether EnvReadOnly, read through getenv, turns on
+// read-only mode. It is kept out of Config so it is never saved to the
+// config file. Values strconv.ParseBool rejects leave read-only mode off
+// and are reported in the returned error.
+func ReadOnlyEnv(getenv func(string) string) (bool, error) {
+	s := getenv(EnvReadOnly)
+	if s == "" {
+		return false, nil
+	}
+	readOnly, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("ignoring %s=%q: not a boolean", EnvReadOnly, s)
+	}
+	return readOnly, nil
+}
+
+// NoColorEnv reports whether EnvNoColor, read through getenv, asks for no
+// colors: any non-empty value does. Like EnvReadOnly it is kept out of
+// Config so it never changes the saved theme.
+func NoColorEnv(getenv func(string) string) bool {
+	return getenv(EnvNoColor) != ""
+}
+
+// StartNamespace returns the namespace to start in: DefaultNamespace when
+// set, otherwise the last used one.
+func (c *Config) StartNamespace() string {
+	if c.DefaultNamespace != "" {
+		return c.DefaultNamespace
+	}
+	return c.LastNamespace
+}
+
 // userHomeDirFunc is a function variable for os.UserHomeDir.
 // It can be overridden in tests.
 var userHomeDirFunc = os.UserHomeDir
@@ -64,24 +447,29 @@ var jsonMarshalFunc = func(v any, prefix, indent string) ([]byte, error) {
 var configPathFunc = defaultConfigPath
 
 // defaultConfigPath returns the default path to the configuration file.
-// The path follows XDG conventions: ~/.config/k1s/configs.json
+// The path follows XDG conventions: ~/.config/k1s/config.json
 func defaultConfigPath() (string, error) {
 	home, err := userHomeDirFunc()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ".config", "k1s", "configs.json"), nil
+	return filepath.Join(home, ".config", "k1s", "config.json"), nil
 }
 
+// legacyConfigName is the file name older versions saved the config under.
+// It is read when config.json does not exist yet; the next Save writes
+// config.json.
+const legacyConfigName = "configs.json"
+
 // configPath returns the path to the configuration file.
 func configPath() (string, error) {
 	return configPathFunc()
 }
 
-// Load reads the configuration from disk and returns it.
-// If the configuration file doesn't exist or is invalid, it returns
-// a default configuration without error. This ensures the application
-// always starts with a valid configuration.
+// Load reads the configuration from disk and returns it. It always
+// returns a usable configuration: a missing file gives the defaults, and
+// an unreadable or invalid file gives the defaults together with an error
+// describing the problem, so the caller can warn about it and carry on.
 func Load() (*Config, error) {
 	path, err := configPath()
 	if err != nil {
@@ -89,17 +477,22 @@ func Load() (*Config, error) {
 	}
 
 	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		path = filepath.Join(filepath.Dir(path), legacyConfigName)
+		data, err = os.ReadFile(path)
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
 			return DefaultConfig(), nil
 		}
-		return nil, err
+		return DefaultConfig(), fmt.Errorf("failed to read config %s: %w", path, err)
 	}
 
 	cfg := DefaultConfig()
 	if err := json.Unmarshal(data, cfg); err != nil {
-		return DefaultConfig(), nil
+		return DefaultConfig(), fmt.Errorf("invalid config %s, using defaults: %w", path, err)
 	}
+	cfg.fillDefaults()
 	return cfg, nil
 }
 
@@ -171,3 +564,16 @@ func (c *Config) IsFavorite(item string) bool {
 	}
 	return false
 }
+
+// ToggleFavoriteContext marks a Kubernetes context as a favorite, or
+// unmarks it when it is one, and reports whether it now is.
+func (c *Config) ToggleFavoriteContext(name string) bool {
+	for i, f := range c.FavoriteContexts {
+		if f == name {
+			c.FavoriteContexts = append(c.FavoriteContexts[:i], c.FavoriteContexts[i+1:]...)
+			return false
+		}
+	}
+	c.FavoriteContexts = append(c.FavoriteContexts, name)
+	return true
+}
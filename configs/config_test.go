@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -118,6 +119,19 @@ func TestIsFavorite(t *testing.T) {
 	}
 }
 
+func TestToggleFavoriteContext(t *testing.T) {
+	cfg := DefaultConfig()
+	if !cfg.ToggleFavoriteContext("prod") || !cfg.ToggleFavoriteContext("staging") {
+		t.Fatal("ToggleFavoriteContext should report a new favorite")
+	}
+	if cfg.ToggleFavoriteContext("prod") {
+		t.Error("ToggleFavoriteContext should report an unmarked favorite")
+	}
+	if len(cfg.FavoriteContexts) != 1 || cfg.FavoriteContexts[0] != "staging" {
+		t.Errorf("FavoriteContexts = %v, want [staging]", cfg.FavoriteContexts)
+	}
+}
+
 func TestSetters(t *testing.T) {
 	cfg := DefaultConfig()
 
@@ -137,15 +151,192 @@ func TestSetters(t *testing.T) {
 	}
 }
 
+func TestConfirmLevelFor(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Confirmations = map[string]ConfirmLevel{
+		ActionDeletePod:      ConfirmNone,
+		ActionRestart:        ConfirmTyped,
+		ActionEdit:           "bogus",
+		ActionForceDeletePod: ConfirmNone,
+
+		ActionDeleteNamespace: ConfirmYesNo,
+	}
+	cfg.Contexts = map[string]ContextSettings{
+		"prod": {Confirmations: map[string]ConfirmLevel{
+			ActionDeletePod: ConfirmTyped,
+			ActionScale:     ConfirmYesNo,
+			ActionRestart:   "bogus",
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		context string
+		action  string
+		want    ConfirmLevel
+	}{
+		{"global overrides default", "dev", ActionDeletePod, ConfirmNone},
+		{"context overrides global", "prod", ActionDeletePod, ConfirmTyped},
+		{"context overrides default", "prod", ActionScale, ConfirmYesNo},
+		{"invalid context value falls back to global", "prod", ActionRestart, ConfirmTyped},
+		{"invalid global value falls back to default", "dev", ActionEdit, ConfirmYesNo},
+		{"scale defaults to none", "dev", ActionScale, ConfirmNone},
+		{"unset action defaults to confirm", "dev", ActionForceDeleteNamespace, ConfirmYesNo},
+		{"unknown context uses global", "", ActionRestart, ConfirmTyped},
+		{"force delete always asks", "dev", ActionForceDeletePod, ConfirmYesNo},
+		{"namespace delete always typed", "dev", ActionDeleteNamespace, ConfirmTyped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.ConfirmLevelFor(tt.context, tt.action); got != tt.want {
+				t.Errorf("ConfirmLevelFor(%q, %q) = %q, want %q", tt.context, tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirmLevelFor_EmptyConfig(t *testing.T) {
+	cfg := &Config{}
+	if got := cfg.ConfirmLevelFor("any", ActionDrainNode); got != ConfirmYesNo {
+		t.Errorf("ConfirmLevelFor() on empty config = %q, want %q", got, ConfirmYesNo)
+	}
+}
+
+func TestConfirmLevelFor_QuickActions(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.QuickActions = true
+	cfg.Contexts = map[string]ContextSettings{
+		"prod": {Confirmations: map[string]ConfirmLevel{ActionRestart: ConfirmTyped}},
+	}
+
+	cfg.Confirmations = map[string]ConfirmLevel{ActionScale: ConfirmTyped}
+
+	for _, action := range []string{ActionScale, ActionRestart} {
+		if got := cfg.ConfirmLevelFor("dev", action); got != ConfirmNone {
+			t.Errorf("ConfirmLevelFor(%q) with quick actions = %q, want %q", action, got, ConfirmNone)
+		}
+	}
+	// The context's own level wins over quick actions
+	if got := cfg.ConfirmLevelFor("prod", ActionRestart); got != ConfirmTyped {
+		t.Errorf("restart in prod with quick actions = %q, want the context's %q", got, ConfirmTyped)
+	}
+	if got := cfg.ConfirmLevelFor("prod", ActionScale); got != ConfirmNone {
+		t.Errorf("scale in prod with quick actions = %q, want %q", got, ConfirmNone)
+	}
+	for _, action := range []string{ActionDeletePod, ActionForceDeletePod, ActionDeleteSecret} {
+		if got := cfg.ConfirmLevelFor("prod", action); got != ConfirmYesNo {
+			t.Errorf("ConfirmLevelFor(%q) with quick actions = %q, want %q", action, got, ConfirmYesNo)
+		}
+	}
+	if got := cfg.ConfirmLevelIn("prod", "kube-system", ActionScale); got != ConfirmTyped {
+		t.Errorf("scale in a protected namespace with quick actions = %q, want %q", got, ConfirmTyped)
+	}
+}
+
+func TestConfirmationsJSON(t *testing.T) {
+	data := []byte(`{"confirmations":{"delete-pod":"none"},"contexts":{"prod":{"confirmations":{"delete-pod":"typed-confirm"}}}}`)
+	cfg := DefaultConfig()
+	if err := json.Unmarshal(data, cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got := cfg.ConfirmLevelFor("prod", ActionDeletePod); got != ConfirmTyped {
+		t.Errorf("prod delete-pod = %q, want %q", got, ConfirmTyped)
+	}
+	if got := cfg.ConfirmLevelFor("dev", ActionDeletePod); got != ConfirmNone {
+		t.Errorf("dev delete-pod = %q, want %q", got, ConfirmNone)
+	}
+}
+
+func TestConfirmLevelIn(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Confirmations = map[string]ConfirmLevel{
+		ActionDeletePod: ConfirmNone,
+	}
+	cfg.ProtectedNamespaces = []string{"kube-system", "payments"}
+	cfg.Contexts = map[string]ContextSettings{
+		"prod": {
+			Confirmations:       map[string]ConfirmLevel{ActionScale: ConfirmNone},
+			ProtectedNamespaces: []string{"prod-core"},
+		},
+		"kind": {ProtectedNamespaces: []string{}},
+	}
+
+	tests := []struct {
+		name      string
+		context   string
+		namespace string
+		action    string
+		want      ConfirmLevel
+	}{
+		{"protected escalates global none", "dev", "kube-system", ActionDeletePod, ConfirmTyped},
+		{"protected escalates default none", "dev", "payments", ActionScale, ConfirmTyped},
+		{"unprotected uses global", "dev", "default", ActionDeletePod, ConfirmNone},
+		{"context list replaces global list", "prod", "kube-system", ActionDeletePod, ConfirmNone},
+		{"context list escalates context none", "prod", "prod-core", ActionScale, ConfirmTyped},
+		{"empty context list protects nothing", "kind", "kube-system", ActionRestart, ConfirmYesNo},
+		{"cluster-scoped action is not escalated", "dev", "", ActionDrainNode, ConfirmYesNo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.ConfirmLevelIn(tt.context, tt.namespace, tt.action); got != tt.want {
+				t.Errorf("ConfirmLevelIn(%q, %q, %q) = %q, want %q", tt.context, tt.namespace, tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtectedNamespacesFor_Defaults(t *testing.T) {
+	cfg := DefaultConfig()
+	for _, ns := range []string{"kube-system", "kube-public", "kube-node-lease", "istio-system"} {
+		if !cfg.IsProtectedNamespace("any", ns) {
+			t.Errorf("%s should be protected by default", ns)
+		}
+	}
+	if cfg.IsProtectedNamespace("any", "default") {
+		t.Error("default should not be protected")
+	}
+}
+
+func TestProtectedNamespacesJSON(t *testing.T) {
+	data := []byte(`{"protected_namespaces":[],"contexts":{"prod":{"protected_namespaces":["billing"]}}}`)
+	cfg := DefaultConfig()
+	if err := json.Unmarshal(data, cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if cfg.IsProtectedNamespace("dev", "kube-system") {
+		t.Error("an empty global list should protect nothing")
+	}
+	if !cfg.IsProtectedNamespace("prod", "billing") {
+		t.Error("prod should protect billing")
+	}
+}
+
+func TestTelemetryOptIn(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.Telemetry.Usage || cfg.Telemetry.CrashReports {
+		t.Error("telemetry must be disabled by default")
+	}
+
+	data := []byte(`{"telemetry":{"usage":true,"crash_reports":true,"dir":"/var/tmp/k1s"}}`)
+	cfg := DefaultConfig()
+	if err := json.Unmarshal(data, cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !cfg.Telemetry.Usage || !cfg.Telemetry.CrashReports || cfg.Telemetry.Dir != "/var/tmp/k1s" {
+		t.Errorf("Telemetry = %+v", cfg.Telemetry)
+	}
+}
+
 func TestDefaultConfigPath(t *testing.T) {
 	path, err := defaultConfigPath()
 	if err != nil {
 		t.Fatalf("defaultConfigPath() error = %v", err)
 	}
 
-	// Should end with .config/k1s/configs.json
-	if filepath.Base(path) != "configs.json" {
-		t.Errorf("defaultConfigPath() = %q, should end with configs.json", path)
+	// Should end with .config/k1s/config.json
+	if filepath.Base(path) != "config.json" {
+		t.Errorf("defaultConfigPath() = %q, should end with config.json", path)
 	}
 
 	dir := filepath.Dir(path)
@@ -192,7 +383,7 @@ func setupTestConfig(t *testing.T) (string, func()) {
 		t.Fatalf("Failed to create temp dir: %v", err)
 	}
 
-	configFile := filepath.Join(tmpDir, "configs.json")
+	configFile := filepath.Join(tmpDir, "config.json")
 
 	// Save original function
 	originalFunc := configPathFunc
@@ -234,7 +425,7 @@ func TestLoadExistingFile(t *testing.T) {
 	tmpDir, cleanup := setupTestConfig(t)
 	defer cleanup()
 
-	configFile := filepath.Join(tmpDir, "configs.json")
+	configFile := filepath.Join(tmpDir, "config.json")
 
 	// Create a config file
 	cfg := &Config{
@@ -279,17 +470,20 @@ func TestLoadInvalidJSON(t *testing.T) {
 	tmpDir, cleanup := setupTestConfig(t)
 	defer cleanup()
 
-	configFile := filepath.Join(tmpDir, "configs.json")
+	configFile := filepath.Join(tmpDir, "config.json")
 
 	// Write invalid JSON
 	if err := os.WriteFile(configFile, []byte("{ invalid json }"), 0644); err != nil {
 		t.Fatalf("Failed to write invalid config: %v", err)
 	}
 
-	// Load should return default config on invalid JSON
+	// Load should return default config on invalid JSON, with an error to warn about
 	cfg, err := Load()
-	if err != nil {
-		t.Fatalf("Load() should not error on invalid JSON: %v", err)
+	if err == nil {
+		t.Error("Load() should report invalid JSON")
+	}
+	if cfg == nil {
+		t.Fatal("Load() should return the default config on invalid JSON")
 	}
 
 	// Should return default config
@@ -315,17 +509,20 @@ func TestLoadReadError(t *testing.T) {
 	}
 
 	// Load should return error when file can't be read (but exists)
-	_, err = Load()
+	cfg, err := Load()
 	if err == nil {
 		t.Error("Load() should error when given a directory path")
 	}
+	if cfg == nil || cfg.LastNamespace != "default" {
+		t.Errorf("Load() = %+v, want the default config alongside the error", cfg)
+	}
 }
 
 func TestSave(t *testing.T) {
 	tmpDir, cleanup := setupTestConfig(t)
 	defer cleanup()
 
-	configFile := filepath.Join(tmpDir, "configs.json")
+	configFile := filepath.Join(tmpDir, "config.json")
 
 	cfg := DefaultConfig()
 	cfg.LastNamespace = "save-test"
@@ -364,7 +561,7 @@ func TestSaveCreatesDirectory(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	// Use a nested path that doesn't exist yet
-	configFile := filepath.Join(tmpDir, "nested", "dir", "configs.json")
+	configFile := filepath.Join(tmpDir, "nested", "dir", "config.json")
 
 	configPathFunc = func() (string, error) {
 		return configFile, nil
@@ -417,7 +614,7 @@ func TestSaveMkdirError(t *testing.T) {
 
 	// Use a path where we can't create directories (e.g., under /proc on Linux or invalid path)
 	configPathFunc = func() (string, error) {
-		return "/dev/null/invalid/path/configs.json", nil
+		return "/dev/null/invalid/path/config.json", nil
 	}
 
 	cfg := DefaultConfig()
@@ -446,3 +643,170 @@ func TestSaveMarshalError(t *testing.T) {
 		t.Error("Save() should error when json marshal fails")
 	}
 }
+
+func TestLoadLegacyFile(t *testing.T) {
+	tmpDir, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	data := []byte(`{"last_namespace": "legacy"}`)
+	if err := os.WriteFile(filepath.Join(tmpDir, "configs.json"), data, 0644); err != nil {
+		t.Fatalf("Failed to write legacy config: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.LastNamespace != "legacy" {
+		t.Errorf("Load() LastNamespace = %q, want the legacy file's %q", cfg.LastNamespace, "legacy")
+	}
+}
+
+func TestLoadFillsDefaults(t *testing.T) {
+	tmpDir, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	data := []byte(`{"log_line_limit": 0, "refresh_interval_seconds": -1, "events_warnings_only": false}`)
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	defaults := DefaultConfig()
+	if cfg.LogLineLimit != defaults.LogLineLimit || cfg.RefreshInterval != defaults.RefreshInterval {
+		t.Errorf("Load() = %d lines every %ds, want the defaults", cfg.LogLineLimit, cfg.RefreshInterval)
+	}
+	if cfg.EventsWarningsOnly {
+		t.Error("Load() should keep events_warnings_only = false from the file")
+	}
+	if cfg.Clipboard != ClipboardAuto || cfg.OSC52MaxBytes != DefaultOSC52MaxBytes {
+		t.Errorf("Load() clipboard = %q, %d bytes, want the defaults", cfg.Clipboard, cfg.OSC52MaxBytes)
+	}
+	if cfg.LogBufferLines != DefaultLogBufferLines {
+		t.Errorf("Load() log buffer = %d lines, want %d", cfg.LogBufferLines, DefaultLogBufferLines)
+	}
+	if cfg.ForceDeleteNamespaceAfter != DefaultForceDeleteNamespaceAfter {
+		t.Errorf("Load() force delete after = %ds, want %ds", cfg.ForceDeleteNamespaceAfter, DefaultForceDeleteNamespaceAfter)
+	}
+	if cfg.ListTimeout != DefaultListTimeout || cfg.LogTimeout != DefaultLogTimeout {
+		t.Errorf("Load() timeouts = %ds for lists, %ds for logs, want %ds and %ds", cfg.ListTimeout, cfg.LogTimeout, DefaultListTimeout, DefaultLogTimeout)
+	}
+}
+
+func TestValidClipboard(t *testing.T) {
+	for _, mode := range []string{ClipboardAuto, ClipboardNative, ClipboardOSC52} {
+		if !ValidClipboard(mode) {
+			t.Errorf("ValidClipboard(%q) = false, want true", mode)
+		}
+	}
+	for _, mode := range []string{"", "OSC52", "xclip"} {
+		if ValidClipboard(mode) {
+			t.Errorf("ValidClipboard(%q) = true, want false", mode)
+		}
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		EnvNamespace:       "staging",
+		EnvContext:         "prod-cluster",
+		EnvLogLineLimit:    "1000",
+		EnvRefreshInterval: "soon",
+	}
+	cfg := DefaultConfig()
+	err := cfg.ApplyEnv(func(k string) string { return env[k] })
+
+	if cfg.StartNamespace() != "staging" {
+		t.Errorf("StartNamespace() = %q, want staging", cfg.StartNamespace())
+	}
+	if cfg.LastContext != "prod-cluster" {
+		t.Errorf("LastContext = %q, want prod-cluster", cfg.LastContext)
+	}
+	if cfg.LogLineLimit != 1000 {
+		t.Errorf("LogLineLimit = %d, want 1000", cfg.LogLineLimit)
+	}
+	if err == nil || !strings.Contains(err.Error(), EnvRefreshInterval) {
+		t.Errorf("ApplyEnv() error = %v, want it to name %s", err, EnvRefreshInterval)
+	}
+	if cfg.RefreshInterval != DefaultConfig().RefreshInterval {
+		t.Errorf("RefreshInterval = %d, want the invalid value ignored", cfg.RefreshInterval)
+	}
+}
+
+func TestWithEnv(t *testing.T) {
+	env := map[string]string{
+		EnvNamespace:       "staging",
+		EnvLogLineLimit:    "1000",
+		EnvRefreshInterval: "30",
+	}
+	cfg := DefaultConfig()
+	cfg.LastNamespace = "team-a"
+	session, err := cfg.WithEnv(func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("WithEnv() error = %v", err)
+	}
+
+	if session.StartNamespace() != "staging" || session.LogLineLimit != 1000 || session.RefreshInterval != 30 {
+		t.Errorf("session = %+v, want the environment applied", session)
+	}
+	if cfg.DefaultNamespace != "" || cfg.LogLineLimit != 200 || cfg.RefreshInterval != 5 {
+		t.Errorf("config = %+v, want it unchanged so the overrides are not saved", cfg)
+	}
+	if session.LastNamespace != "team-a" {
+		t.Errorf("session.LastNamespace = %q, want the file's value", session.LastNamespace)
+	}
+}
+
+func TestReadOnlyEnv(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{"", false, false},
+		{"1", true, false},
+		{"true", true, false},
+		{"false", false, false},
+		{"yes", false, true},
+	}
+	for _, tt := range tests {
+		got, err := ReadOnlyEnv(func(k string) string {
+			if k == EnvReadOnly {
+				return tt.value
+			}
+			return ""
+		})
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ReadOnlyEnv(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNoColorEnv(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "1": true, "0": true, "true": true} {
+		got := NoColorEnv(func(k string) string {
+			if k == EnvNoColor {
+				return value
+			}
+			return ""
+		})
+		if got != want {
+			t.Errorf("NoColorEnv(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestStartNamespace(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.LastNamespace = "team-a"
+	if got := cfg.StartNamespace(); got != "team-a" {
+		t.Errorf("StartNamespace() = %q, want the last namespace", got)
+	}
+	cfg.DefaultNamespace = "team-b"
+	if got := cfg.StartNamespace(); got != "team-b" {
+		t.Errorf("StartNamespace() = %q, want the default namespace", got)
+	}
+}
@@ -0,0 +1,217 @@
+// Package check runs the k1s pod diagnosis without the TUI, for scripts,
+// CI jobs and chat bots: "k1s check" reports the health of the pods of a
+// namespace or workload as text or JSON.
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// Report is the diagnosis of the pods of a namespace, or of a workload.
+type Report struct {
+	Namespace string      `json:"namespace"`
+	Workload  string      `json:"workload,omitempty"` // e.g. "deployment/api"; empty for the whole namespace
+	Healthy   bool        `json:"healthy"`            // Every pod is healthy
+	Pods      []PodReport `json:"pods"`
+}
+
+// PodReport is the status of a pod with the evidence behind it.
+type PodReport struct {
+	Name          string                    `json:"name"`
+	Status        string                    `json:"status"`
+	Ready         string                    `json:"ready"`
+	Restarts      int32                     `json:"restarts"`
+	Health        repository.PodHealthClass `json:"health"`
+	Healthy       bool                      `json:"healthy"`
+	WarningEvents []WarningEvent            `json:"warningEvents"`
+	ProbeFailures []ProbeFailure            `json:"probeFailures"`
+	Diagnosis     *Diagnosis                `json:"diagnosis"` // nil when nothing is wrong
+	Issues        []Issue                   `json:"issues"`
+}
+
+// WarningEvent is a Warning event of a pod.
+type WarningEvent struct {
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// ProbeFailure is a probe failing since its container last started.
+type ProbeFailure struct {
+	Container   string `json:"container"`
+	Probe       string `json:"probe"` // "Startup", "Liveness" or "Readiness"
+	Failures    int32  `json:"failures"`
+	LastFailure string `json:"lastFailure,omitempty"`
+}
+
+// Diagnosis is the most likely root cause found by repository.DiagnoseCrash.
+type Diagnosis struct {
+	Cause     repository.CrashCause `json:"cause"`
+	Container string                `json:"container,omitempty"`
+	Summary   string                `json:"summary"`
+	Reasoning []string              `json:"reasoning"`
+}
+
+// Issue is a problem found by repository.AnalyzePodIssues.
+type Issue struct {
+	Issue       string   `json:"issue"`
+	Severity    string   `json:"severity"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// Run diagnoses the pods of namespace, or only those of the workload of
+// kind named name when both are given (kind as kubectl takes it, see
+// repository.ParseTargetKind). Pods are sorted by name.
+func Run(ctx context.Context, clientset kubernetes.Interface, namespace, kind, name string) (*Report, error) {
+	report := &Report{Namespace: namespace, Healthy: true}
+
+	var pods []repository.PodInfo
+	if kind != "" {
+		target, err := repository.ResolveTarget(ctx, clientset, namespace, kind, name)
+		if err != nil {
+			return nil, err
+		}
+		report.Workload = strings.ToLower(kind) + "/" + name
+		if target.Workload == nil {
+			pods = []repository.PodInfo{*target.Pod}
+		} else if pods, err = repository.GetWorkloadPods(ctx, clientset, *target.Workload); err != nil {
+			return nil, fmt.Errorf("failed to list pods of %s: %w", report.Workload, err)
+		}
+	} else {
+		var err error
+		if pods, err = repository.ListAllPods(ctx, clientset, namespace); err != nil {
+			return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	report.Pods = make([]PodReport, 0, len(pods))
+	for i := range pods {
+		events, err := repository.GetPodEvents(ctx, clientset, namespace, pods[i].Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get events of pod %s: %w", pods[i].Name, err)
+		}
+		pod := diagnosePod(&pods[i], podEvents(events, pods[i].Name))
+		report.Healthy = report.Healthy && pod.Healthy
+		report.Pods = append(report.Pods, pod)
+	}
+	return report, nil
+}
+
+// podEvents keeps the events about the pod itself. The field selector of
+// GetPodEvents matches by name only, so an object of another kind with the
+// same name would show up too.
+func podEvents(events []repository.EventInfo, name string) []repository.EventInfo {
+	kept := events[:0]
+	for _, e := range events {
+		if e.Object == "Pod/"+name {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// diagnosePod runs the analyzers of the dashboard on a pod. Ready,
+// completed and terminating pods are healthy.
+func diagnosePod(pod *repository.PodInfo, events []repository.EventInfo) PodReport {
+	health := repository.ClassifyPod(*pod)
+	r := PodReport{
+		Name:          pod.Name,
+		Status:        pod.Status,
+		Ready:         pod.Ready,
+		Restarts:      pod.Restarts,
+		Health:        health,
+		Healthy:       health == repository.HealthReady || health == repository.HealthCompleted || health == repository.HealthTerminating,
+		WarningEvents: []WarningEvent{},
+		ProbeFailures: []ProbeFailure{},
+		Issues:        []Issue{},
+	}
+
+	for _, e := range events {
+		if e.Type == "Warning" {
+			r.WarningEvents = append(r.WarningEvents, WarningEvent{Reason: e.Reason, Message: e.Message, Count: e.Count, LastSeen: e.LastSeen})
+		}
+	}
+	for _, s := range repository.BuildProbeStatus(pod, events) {
+		if s.State == repository.ProbeFailing {
+			r.ProbeFailures = append(r.ProbeFailures, ProbeFailure{Container: s.Container, Probe: s.Probe, Failures: s.Failures, LastFailure: s.LastFailure})
+		}
+	}
+	if d := repository.DiagnoseCrash(pod, events); d != nil {
+		r.Diagnosis = &Diagnosis{Cause: d.Cause, Container: d.Container, Summary: d.Summary, Reasoning: d.Reasoning}
+	}
+	for _, h := range repository.AnalyzePodIssues(pod, events) {
+		r.Issues = append(r.Issues, Issue{Issue: h.Issue, Severity: h.Severity, Suggestions: h.Suggestions})
+	}
+	return r
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteText writes a summary for people: a line per pod, with the
+// diagnosis, failing probes and warning events of the unhealthy ones.
+func (r *Report) WriteText(w io.Writer) {
+	scope := "namespace " + r.Namespace
+	if r.Workload != "" {
+		scope = r.Workload + " in " + scope
+	}
+	unhealthy := 0
+	for _, p := range r.Pods {
+		if !p.Healthy {
+			unhealthy++
+		}
+	}
+	fmt.Fprintf(w, "k1s check: %s\n", scope)
+	fmt.Fprintf(w, "  %d pods, %d unhealthy\n", len(r.Pods), unhealthy)
+	if len(r.Pods) == 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "No pods found.")
+		return
+	}
+
+	for _, p := range r.Pods {
+		mark := "✓"
+		if !p.Healthy {
+			mark = "✗"
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "%s %s  %s  ready %s  restarts %d\n", mark, p.Name, p.Status, p.Ready, p.Restarts)
+		if p.Healthy {
+			continue
+		}
+		if p.Diagnosis != nil {
+			fmt.Fprintf(w, "    Diagnosis: %s\n", p.Diagnosis.Summary)
+			for _, line := range p.Diagnosis.Reasoning {
+				fmt.Fprintf(w, "      - %s\n", line)
+			}
+		}
+		for _, f := range p.ProbeFailures {
+			fmt.Fprintf(w, "    Probe: %s %s failed %d times", f.Container, strings.ToLower(f.Probe), f.Failures)
+			if f.LastFailure != "" {
+				fmt.Fprintf(w, ": %s", f.LastFailure)
+			}
+			fmt.Fprintln(w)
+		}
+		for _, e := range p.WarningEvents {
+			fmt.Fprintf(w, "    Event: %s (x%d): %s\n", e.Reason, e.Count, e.Message)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var checkTime = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func checkPod(name string, status corev1.ContainerStatus) *corev1.Pod {
+	limits := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+	status.Name = "app"
+	status.Image = "registry.example.com/app:v1"
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": name}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:      "app",
+			Image:     "registry.example.com/app:v1",
+			Resources: corev1.ResourceRequirements{Requests: limits, Limits: limits},
+		}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{status}},
+	}
+}
+
+// checkFixture is a namespace with a healthy pod and one in a crash loop.
+func checkFixture() *fake.Clientset {
+	healthy := checkPod("api", corev1.ContainerStatus{
+		Ready: true,
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(checkTime)}},
+	})
+	crashing := checkPod("worker", corev1.ContainerStatus{
+		RestartCount: 7,
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+			Reason:  "CrashLoopBackOff",
+			Message: "back-off 5m0s restarting failed container=app",
+		}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason:     "Error",
+			ExitCode:   1,
+			StartedAt:  metav1.NewTime(checkTime.Add(-time.Minute)),
+			FinishedAt: metav1.NewTime(checkTime.Add(-50 * time.Second)),
+		}},
+	})
+	backOff := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "worker.backoff", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "worker", Namespace: "default"},
+		Type:           "Warning",
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container app in pod worker_default(1234)",
+		Count:          31,
+		FirstTimestamp: metav1.NewTime(checkTime.Add(-time.Hour)),
+		LastTimestamp:  metav1.NewTime(checkTime.Add(-50 * time.Second)),
+	}
+	pulled := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "api.pulled", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api", Namespace: "default"},
+		Type:           "Normal",
+		Reason:         "Pulled",
+		Message:        `Container image "registry.example.com/app:v1" already present on machine`,
+		Count:          1,
+		FirstTimestamp: metav1.NewTime(checkTime),
+		LastTimestamp:  metav1.NewTime(checkTime),
+	}
+	return fake.NewSimpleClientset(crashing, healthy, backOff, pulled)
+}
+
+func TestRun_JSONGolden(t *testing.T) {
+	report, err := Run(context.Background(), checkFixture(), "default", "", "")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Healthy {
+		t.Error("report should be unhealthy with a crash looping pod")
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "check.golden"))
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Errorf("JSON output mismatch\ngot:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestRun_Workload(t *testing.T) {
+	report, err := Run(context.Background(), checkFixture(), "default", "pod", "api")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !report.Healthy || len(report.Pods) != 1 || report.Pods[0].Name != "api" || report.Workload != "pod/api" {
+		t.Errorf("Run(pod/api) = %+v, want the healthy pod only", report)
+	}
+
+	if _, err := Run(context.Background(), checkFixture(), "default", "deployment", "missing"); err == nil {
+		t.Error("Run() should fail for a workload that doesn't exist")
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	report, err := Run(context.Background(), checkFixture(), "default", "", "")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	var buf bytes.Buffer
+	report.WriteText(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"k1s check: namespace default",
+		"2 pods, 1 unhealthy",
+		"✓ api  Running",
+		"✗ worker  CrashLoopBackOff",
+		"Event: BackOff (x31)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("text report missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Pulled") {
+		t.Errorf("healthy pods should not list their events:\n%s", out)
+	}
+}
@@ -0,0 +1,144 @@
+// Package deeplink parses and builds k1s:// links that point at a pod or
+// workload, so a view can be shared and reopened with "k1s <link>":
+//
+//	k1s://<context>/<namespace>/<kind>/<name>?container=app&view=logs
+//
+// Each path segment is URL path-escaped, so contexts containing slashes or
+// colons (e.g. EKS ARNs) round-trip safely.
+package deeplink
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Scheme is the prefix every link starts with.
+const Scheme = "k1s://"
+
+// Supported kinds.
+const (
+	KindPod         = "pod"
+	KindDeployment  = "deployment"
+	KindStatefulSet = "statefulset"
+	KindDaemonSet   = "daemonset"
+)
+
+// Supported views, matching the dashboard panels.
+const (
+	ViewLogs    = "logs"
+	ViewEvents  = "events"
+	ViewMetrics = "metrics"
+	ViewDetails = "details"
+)
+
+var validKinds = map[string]bool{
+	KindPod:         true,
+	KindDeployment:  true,
+	KindStatefulSet: true,
+	KindDaemonSet:   true,
+}
+
+var validViews = map[string]bool{
+	ViewLogs:    true,
+	ViewEvents:  true,
+	ViewMetrics: true,
+	ViewDetails: true,
+}
+
+// Link identifies a pod or workload and, optionally, the container and
+// dashboard panel to open.
+type Link struct {
+	Context   string
+	Namespace string
+	Kind      string
+	Name      string
+	Container string // Optional
+	View      string // Optional, one of the View constants
+}
+
+// IsLink reports whether s looks like a k1s:// link.
+func IsLink(s string) bool {
+	return strings.HasPrefix(s, Scheme)
+}
+
+// String serializes the link. Empty optional fields are omitted.
+func (l Link) String() string {
+	var b strings.Builder
+	b.WriteString(Scheme)
+	for i, seg := range []string{l.Context, l.Namespace, l.Kind, l.Name} {
+		if i > 0 {
+			b.WriteByte('/')
+		}
+		b.WriteString(url.PathEscape(seg))
+	}
+
+	query := url.Values{}
+	if l.Container != "" {
+		query.Set("container", l.Container)
+	}
+	if l.View != "" {
+		query.Set("view", l.View)
+	}
+	if len(query) > 0 {
+		b.WriteByte('?')
+		b.WriteString(query.Encode())
+	}
+	return b.String()
+}
+
+// IsPod reports whether the link points at a pod rather than a workload.
+func (l Link) IsPod() bool {
+	return l.Kind == KindPod
+}
+
+// Parse parses a k1s:// link. The kind is case-insensitive; unknown query
+// parameters are ignored so newer links still open in older versions.
+func Parse(raw string) (*Link, error) {
+	if !IsLink(raw) {
+		return nil, fmt.Errorf("invalid link %q: must start with %s", raw, Scheme)
+	}
+	rest := strings.TrimPrefix(raw, Scheme)
+
+	// url.Parse is not used: it would treat the context as a host, which
+	// breaks on contexts containing colons or escaped slashes
+	path, rawQuery, _ := strings.Cut(rest, "?")
+	segments := strings.Split(path, "/")
+	if len(segments) != 4 {
+		return nil, fmt.Errorf("invalid link %q: expected %scontext/namespace/kind/name", raw, Scheme)
+	}
+
+	parts := make([]string, len(segments))
+	names := []string{"context", "namespace", "kind", "name"}
+	for i, seg := range segments {
+		v, err := url.PathUnescape(seg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid link %q: bad escape in %s: %w", raw, names[i], err)
+		}
+		if v == "" {
+			return nil, fmt.Errorf("invalid link %q: %s is empty", raw, names[i])
+		}
+		parts[i] = v
+	}
+
+	link := &Link{
+		Context:   parts[0],
+		Namespace: parts[1],
+		Kind:      strings.ToLower(parts[2]),
+		Name:      parts[3],
+	}
+	if !validKinds[link.Kind] {
+		return nil, fmt.Errorf("invalid link %q: unsupported kind %q (use pod, deployment, statefulset or daemonset)", raw, parts[2])
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid link %q: bad query: %w", raw, err)
+	}
+	link.Container = query.Get("container")
+	link.View = strings.ToLower(query.Get("view"))
+	if link.View != "" && !validViews[link.View] {
+		return nil, fmt.Errorf("invalid link %q: unsupported view %q (use logs, events, metrics or details)", raw, link.View)
+	}
+	return link, nil
+}
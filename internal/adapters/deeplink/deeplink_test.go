@@ -0,0 +1,129 @@
+package deeplink
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    Link
+		wantErr string
+	}{
+		{
+			name: "pod with container and view",
+			raw:  "k1s://prod/default/pod/api-7d9f?container=app&view=logs",
+			want: Link{Context: "prod", Namespace: "default", Kind: KindPod, Name: "api-7d9f", Container: "app", View: ViewLogs},
+		},
+		{
+			name: "workload without query",
+			raw:  "k1s://kind-dev/shop/deployment/web",
+			want: Link{Context: "kind-dev", Namespace: "shop", Kind: KindDeployment, Name: "web"},
+		},
+		{
+			name: "kind and view are case-insensitive",
+			raw:  "k1s://dev/ns/StatefulSet/db?view=Events",
+			want: Link{Context: "dev", Namespace: "ns", Kind: KindStatefulSet, Name: "db", View: ViewEvents},
+		},
+		{
+			name: "escaped context with colons and slash",
+			raw:  "k1s://arn:aws:eks:us-east-1:123:cluster%2Fprod/default/daemonset/agent",
+			want: Link{Context: "arn:aws:eks:us-east-1:123:cluster/prod", Namespace: "default", Kind: KindDaemonSet, Name: "agent"},
+		},
+		{
+			name: "escaped query values",
+			raw:  "k1s://dev/ns/pod/p?container=side%20car",
+			want: Link{Context: "dev", Namespace: "ns", Kind: KindPod, Name: "p", Container: "side car"},
+		},
+		{
+			name: "unknown query parameters are ignored",
+			raw:  "k1s://dev/ns/pod/p?tail=100&view=metrics",
+			want: Link{Context: "dev", Namespace: "ns", Kind: KindPod, Name: "p", View: ViewMetrics},
+		},
+		{name: "missing scheme", raw: "https://dev/ns/pod/p", wantErr: "must start with k1s://"},
+		{name: "too few segments", raw: "k1s://dev/ns/pod", wantErr: "expected k1s://context/namespace/kind/name"},
+		{name: "too many segments", raw: "k1s://dev/ns/pod/p/extra", wantErr: "expected k1s://context/namespace/kind/name"},
+		{name: "empty namespace", raw: "k1s://dev//pod/p", wantErr: "namespace is empty"},
+		{name: "empty name", raw: "k1s://dev/ns/pod/", wantErr: "name is empty"},
+		{name: "unknown kind", raw: "k1s://dev/ns/job/p", wantErr: `unsupported kind "job"`},
+		{name: "unknown view", raw: "k1s://dev/ns/pod/p?view=shell", wantErr: `unsupported view "shell"`},
+		{name: "bad escape", raw: "k1s://dev%zz/ns/pod/p", wantErr: "bad escape in context"},
+		{name: "bad query", raw: "k1s://dev/ns/pod/p?view=%zz", wantErr: "bad query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLink_String(t *testing.T) {
+	tests := []struct {
+		link Link
+		want string
+	}{
+		{
+			link: Link{Context: "prod", Namespace: "default", Kind: KindPod, Name: "api", Container: "app", View: ViewLogs},
+			want: "k1s://prod/default/pod/api?container=app&view=logs",
+		},
+		{
+			link: Link{Context: "prod", Namespace: "default", Kind: KindDeployment, Name: "api"},
+			want: "k1s://prod/default/deployment/api",
+		},
+		{
+			link: Link{Context: "arn:aws:eks:us-east-1:123:cluster/prod", Namespace: "ns", Kind: KindPod, Name: "p"},
+			want: "k1s://arn:aws:eks:us-east-1:123:cluster%2Fprod/ns/pod/p",
+		},
+		{
+			link: Link{Context: "dev", Namespace: "ns", Kind: KindPod, Name: "p", Container: "a&b"},
+			want: "k1s://dev/ns/pod/p?container=a%26b",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.link.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	links := []Link{
+		{Context: "gke_proj_us-central1_prod", Namespace: "kube-system", Kind: KindDaemonSet, Name: "fluentd"},
+		{Context: "user@cluster", Namespace: "a", Kind: KindPod, Name: "b", Container: "c d", View: ViewDetails},
+		{Context: "weird/ctx?#%", Namespace: "ns", Kind: KindStatefulSet, Name: "db-0", View: ViewEvents},
+	}
+	for _, l := range links {
+		got, err := Parse(l.String())
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", l.String(), err)
+		}
+		if *got != l {
+			t.Errorf("round trip = %+v, want %+v", *got, l)
+		}
+	}
+}
+
+func TestIsLink(t *testing.T) {
+	if !IsLink("k1s://a/b/pod/c") {
+		t.Error("IsLink() = false for a k1s link")
+	}
+	if IsLink("-n") || IsLink("configure") {
+		t.Error("IsLink() = true for a non-link argument")
+	}
+}
@@ -0,0 +1,145 @@
+// Package fuzzy ranks strings against a typed query the way command
+// palettes do. A query is split on spaces into terms, and each term must
+// appear in a candidate as a case-insensitive subsequence: "prod pay"
+// matches "prod/deployment/payments". Matches starting words and runs of
+// consecutive characters score higher, gaps between them lower.
+//
+// The package has no dependencies beyond the standard library.
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Scoring of a matched character.
+const (
+	scoreMatch       = 1 // Any matched character
+	bonusBoundary    = 8 // Character starting a word: after a separator, or an upper case letter after a lower case one
+	bonusFirst       = 4 // Character starting the candidate
+	bonusConsecutive = 5 // Character right after the previous one matched
+	penaltyGap       = 1 // Per character skipped between two matched ones
+	maxGapPenalty    = 6 // Most a single gap costs, so one long skip doesn't sink a match
+)
+
+// Match is a candidate that matched a query.
+type Match struct {
+	Index int // Index of the candidate in the list given to Filter
+	Score int // Higher is better
+}
+
+// Score returns how well candidate matches query, higher being better, and
+// false when some term of the query doesn't match. An empty query matches
+// every candidate with a score of 0.
+func Score(query, candidate string) (int, bool) {
+	runes := []rune(candidate)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	total := 0
+	for _, term := range strings.Fields(query) {
+		score, ok := scoreTerm([]rune(strings.ToLower(term)), runes, lower)
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+// scoreTerm returns the best score of term as a subsequence of candidate,
+// given in its original case for word boundaries and in lower case for
+// matching.
+//
+// best[j] holds the best score of the term so far with its last character
+// matched at j; each character of the term tries every later position of
+// the candidate.
+func scoreTerm(term, candidate, lower []rune) (int, bool) {
+	n := len(candidate)
+	if len(term) == 0 {
+		return 0, true
+	}
+	if len(term) > n {
+		return 0, false
+	}
+
+	const none = -1 << 30
+	best := make([]int, n)
+	next := make([]int, n)
+	for j := range best {
+		best[j] = none
+		if lower[j] == term[0] {
+			best[j] = charBonus(candidate, j)
+		}
+	}
+	for i := 1; i < len(term); i++ {
+		for j := range next {
+			next[j] = none
+			if lower[j] != term[i] {
+				continue
+			}
+			for k := 0; k < j; k++ {
+				if best[k] == none {
+					continue
+				}
+				score := best[k] + charBonus(candidate, j)
+				if k == j-1 {
+					score += bonusConsecutive
+				} else {
+					score -= min((j-k-1)*penaltyGap, maxGapPenalty)
+				}
+				next[j] = max(next[j], score)
+			}
+		}
+		best, next = next, best
+	}
+
+	top := none
+	for _, score := range best {
+		top = max(top, score)
+	}
+	return top, top != none
+}
+
+// charBonus is the score of matching the character at j of candidate.
+func charBonus(candidate []rune, j int) int {
+	score := scoreMatch
+	switch {
+	case j == 0:
+		score += bonusFirst + bonusBoundary
+	case isSeparator(candidate[j-1]):
+		score += bonusBoundary
+	case unicode.IsUpper(candidate[j]) && unicode.IsLower(candidate[j-1]):
+		score += bonusBoundary
+	}
+	return score
+}
+
+func isSeparator(r rune) bool {
+	switch r {
+	case '/', '-', '_', '.', ':', ' ':
+		return true
+	}
+	return false
+}
+
+// Filter returns the candidates matching query, best first. Among equal
+// scores the shorter candidate comes first, then the one listed first.
+func Filter(query string, candidates []string) []Match {
+	var matches []Match
+	for i, c := range candidates {
+		if score, ok := Score(query, c); ok {
+			matches = append(matches, Match{Index: i, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].Score != matches[b].Score {
+			return matches[a].Score > matches[b].Score
+		}
+		return len(candidates[matches[a].Index]) < len(candidates[matches[b].Index])
+	})
+	return matches
+}
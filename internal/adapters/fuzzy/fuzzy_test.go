@@ -0,0 +1,82 @@
+package fuzzy
+
+import "testing"
+
+func TestScore_Matches(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		candidate string
+		want      bool
+	}{
+		{"empty query", "", "anything", true},
+		{"subsequence", "pmt", "payments", true},
+		{"case-insensitive", "PAY", "Payments", true},
+		{"terms anywhere", "prod pay", "prod/deployment/payments", true},
+		{"terms in any order", "pay prod", "prod/deployment/payments", true},
+		{"out of order", "tmp", "payments", false},
+		{"missing term", "prod pay", "staging/deployment/payments", false},
+		{"longer than candidate", "paymentsx", "payments", false},
+		{"empty candidate", "a", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Score(tt.query, tt.candidate); ok != tt.want {
+				t.Errorf("Score(%q, %q) matched = %v, want %v", tt.query, tt.candidate, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestScore_Ranking(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		better, worse string
+	}{
+		{"consecutive over scattered", "pay", "payments", "pxaxy"},
+		{"word start over middle", "gw", "api-gateway", "hedgewall"},
+		{"prefix over inside", "web", "web-frontend", "cobweb"},
+		{"camel case boundary", "ms", "metricsServer", "mismatch-old"},
+		{"short gap over long gap", "ab", "a-b", "a-----------b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			better, ok1 := Score(tt.query, tt.better)
+			worse, ok2 := Score(tt.query, tt.worse)
+			if !ok1 || !ok2 || better <= worse {
+				t.Errorf("Score(%q): %q = %d, %q = %d; want the first higher", tt.query, tt.better, better, tt.worse, worse)
+			}
+		})
+	}
+}
+
+func TestScore_BestAlignment(t *testing.T) {
+	// The greedy leftmost match would take the "p" and "a" of "api"
+	aligned, _ := Score("pay", "api-payments")
+	direct, _ := Score("pay", "payments")
+	if aligned < direct-bonusFirst {
+		t.Errorf("Score(pay, api-payments) = %d, want close to Score(pay, payments) = %d", aligned, direct)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	candidates := []string{
+		"staging/deployment/payments",
+		"prod",
+		"prod/deployment/payments",
+		"prod/deployment/web",
+		"prod/statefulset/payments-db",
+	}
+	matches := Filter("prod pay", candidates)
+	if len(matches) != 2 {
+		t.Fatalf("Filter() = %+v, want 2 matches", matches)
+	}
+	if candidates[matches[0].Index] != "prod/deployment/payments" || candidates[matches[1].Index] != "prod/statefulset/payments-db" {
+		t.Errorf("Filter() order = %q, %q", candidates[matches[0].Index], candidates[matches[1].Index])
+	}
+
+	if all := Filter("", candidates); len(all) != len(candidates) || candidates[all[0].Index] != "prod" {
+		t.Errorf("an empty query should keep every candidate, shortest first: %+v", all)
+	}
+}
@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// APIs that features of k1s depend on, beyond core/v1 and apps/v1.
+var (
+	HPAv2GVR          = schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}
+	HPAv1GVR          = schema.GroupVersionResource{Group: "autoscaling", Version: "v1", Resource: "horizontalpodautoscalers"}
+	EndpointSliceGVR  = schema.GroupVersionResource{Group: "discovery.k8s.io", Version: "v1", Resource: "endpointslices"}
+	RolloutsGVR       = rolloutGVR
+	VirtualServiceGVR = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1beta1", Resource: "virtualservices"}
+)
+
+// MaxVersionSkew is how many minor versions the API server may be apart
+// from the client libraries before the status bar warns about it, as
+// client-go only supports that many.
+const MaxVersionSkew = 2
+
+// APIs is what an API server serves, found by discovery: its version and
+// the resources of every group version.
+type APIs struct {
+	ServerVersion *version.Info
+	resources     map[schema.GroupVersionResource]bool
+	failed        map[schema.GroupVersion]bool // Group versions whose discovery failed
+}
+
+// DiscoverAPIs asks the API server for its version and the resources it
+// serves. Group versions whose discovery fails, as with a broken
+// aggregated API, are recorded as such rather than failing the scan.
+func DiscoverAPIs(disc discovery.DiscoveryInterface) (*APIs, error) {
+	info, err := disc.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	apis := &APIs{
+		ServerVersion: info,
+		resources:     make(map[schema.GroupVersionResource]bool),
+		failed:        make(map[schema.GroupVersion]bool),
+	}
+	_, lists, err := disc.ServerGroupsAndResources()
+	if err != nil {
+		var groupErr *discovery.ErrGroupDiscoveryFailed
+		if !errors.As(err, &groupErr) {
+			return nil, fmt.Errorf("failed to discover API resources: %w", err)
+		}
+		for gv := range groupErr.Groups {
+			apis.failed[gv] = true
+		}
+	}
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if !strings.Contains(r.Name, "/") { // Not a subresource
+				apis.resources[gv.WithResource(r.Name)] = true
+			}
+		}
+	}
+	return apis, nil
+}
+
+// Supports reports whether the server serves gvr. Before discovery (a nil
+// APIs), and for group versions whose discovery failed, everything is
+// taken as served, so that features are only hidden on evidence.
+func (a *APIs) Supports(gvr schema.GroupVersionResource) bool {
+	if a == nil {
+		return true
+	}
+	return a.resources[gvr] || a.failed[gvr.GroupVersion()]
+}
+
+// HPAVersion returns the autoscaling version to read HPAs with: "v2", or
+// "v1" on servers older than 1.23, "" when neither is served.
+func (a *APIs) HPAVersion() string {
+	switch {
+	case a.Supports(HPAv2GVR):
+		return "v2"
+	case a.Supports(HPAv1GVR):
+		return "v1"
+	}
+	return ""
+}
+
+// VersionSkew returns how many minor versions the server is ahead of the
+// client libraries, negative when behind. It returns false when either
+// version can't be parsed, or the major versions differ.
+func (a *APIs) VersionSkew() (int, bool) {
+	if a == nil || a.ServerVersion == nil {
+		return 0, false
+	}
+	clientMajor, clientMinor, ok := ClientVersion()
+	if !ok {
+		return 0, false
+	}
+	major, minor, ok := parseServerVersion(a.ServerVersion)
+	if !ok || major != clientMajor {
+		return 0, false
+	}
+	return minor - clientMinor, true
+}
+
+// SkewWarning describes the skew between the server and the client
+// libraries when it exceeds MaxVersionSkew, "" otherwise.
+func (a *APIs) SkewWarning() string {
+	skew, ok := a.VersionSkew()
+	if !ok || (skew <= MaxVersionSkew && skew >= -MaxVersionSkew) {
+		return ""
+	}
+	major, minor, _ := ClientVersion()
+	direction := "behind"
+	if skew > 0 {
+		direction = "ahead of"
+	}
+	if skew < 0 {
+		skew = -skew
+	}
+	return fmt.Sprintf("%d minors %s client %d.%d", skew, direction, major, minor)
+}
+
+// parseServerVersion reads the major and minor of a server version.
+// Providers append to the minor, as in "27+" on EKS and GKE, so the
+// leading digits are taken.
+func parseServerVersion(info *version.Info) (major, minor int, ok bool) {
+	digits := func(s string) (int, bool) {
+		end := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+		if end == -1 {
+			end = len(s)
+		}
+		n, err := strconv.Atoi(s[:end])
+		return n, err == nil
+	}
+	major, okMajor := digits(info.Major)
+	minor, okMinor := digits(info.Minor)
+	return major, minor, okMajor && okMinor
+}
+
+// ClientVersion returns the Kubernetes release the client libraries k1s is
+// built with match: client-go v0.N is Kubernetes 1.N.
+func ClientVersion() (major, minor int, ok bool) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return 0, 0, false
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != "k8s.io/client-go" {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(dep.Version, "v"), ".", 3)
+		if len(parts) < 2 || parts[0] != "0" {
+			return 0, 0, false
+		}
+		minor, err := strconv.Atoi(parts[1])
+		return 1, minor, err == nil
+	}
+	return 0, 0, false
+}
+
+// apiScan keeps the APIs of a Client, discovered in the background while
+// the UI reads them.
+type apiScan struct {
+	mu   sync.Mutex
+	apis *APIs
+}
+
+// DiscoverAPIs scans the API server of the client and keeps the result
+// for APIs and Supports. Building a client doesn't contact the cluster,
+// so the scan runs once it answers. A replayed recording has nothing to
+// discover and keeps every API.
+func (c *Client) DiscoverAPIs() (*APIs, error) {
+	if c.replay != nil {
+		return nil, nil
+	}
+	apis, err := DiscoverAPIs(c.clientset.Discovery())
+	if err != nil {
+		return nil, err
+	}
+	if c.apis != nil {
+		c.apis.mu.Lock()
+		c.apis.apis = apis
+		c.apis.mu.Unlock()
+	}
+	return apis, nil
+}
+
+// APIs returns the result of the last DiscoverAPIs, nil before the first.
+func (c *Client) APIs() *APIs {
+	if c.apis == nil {
+		return nil
+	}
+	c.apis.mu.Lock()
+	defer c.apis.mu.Unlock()
+	return c.apis.apis
+}
+
+// Supports reports whether the cluster serves gvr, true until the APIs
+// have been discovered.
+func (c *Client) Supports(gvr schema.GroupVersionResource) bool {
+	return c.APIs().Supports(gvr)
+}
+
+// ListHPAs lists the HorizontalPodAutoscalers of a namespace with the
+// autoscaling version the cluster serves: v1 on old clusters, with only
+// the CPU target. Clusters without HPAs list none.
+func (c *Client) ListHPAs(ctx context.Context, namespace string) ([]HPAInfo, error) {
+	switch c.APIs().HPAVersion() {
+	case "v2":
+		return ListHPAs(ctx, c.clientset, namespace)
+	case "v1":
+		return ListHPAsV1(ctx, c.clientset, namespace)
+	}
+	return nil, nil
+}
+
+// gatedDynamicClient is a dynamic client that answers requests for
+// resources the cluster doesn't serve with NotFound itself, so that
+// optional CRDs such as Istio's and Argo Rollouts' cost no request, and no
+// error, on every refresh of clusters without them.
+type gatedDynamicClient struct {
+	dynamic.Interface
+	apis *APIs
+}
+
+func (g gatedDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	resource := g.Interface.Resource(gvr)
+	if g.apis.Supports(gvr) {
+		return resource
+	}
+	return unservedResource{NamespaceableResourceInterface: resource, gvr: gvr}
+}
+
+// unservedResource fails reads of a resource the cluster doesn't serve
+// without a request, as the API server would.
+type unservedResource struct {
+	dynamic.NamespaceableResourceInterface
+	gvr schema.GroupVersionResource
+}
+
+func (r unservedResource) Namespace(string) dynamic.ResourceInterface { return r }
+
+func (r unservedResource) Get(_ context.Context, name string, _ metav1.GetOptions, _ ...string) (*unstructured.Unstructured, error) {
+	return nil, apierrors.NewNotFound(r.gvr.GroupResource(), name)
+}
+
+func (r unservedResource) List(context.Context, metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return nil, apierrors.NewNotFound(r.gvr.GroupResource(), "")
+}
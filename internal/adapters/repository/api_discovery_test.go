@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// servedAPIs returns a clientset whose discovery serves a 1.<serverMinor>
+// server with the given resources, keyed by group version.
+func servedAPIs(serverMinor string, resources map[string][]string) *fake.Clientset {
+	clientset := fake.NewSimpleClientset()
+	disc := clientset.Discovery().(*fakediscovery.FakeDiscovery)
+	disc.FakedServerVersion = &version.Info{Major: "1", Minor: serverMinor, GitVersion: "v1." + serverMinor + ".3"}
+	for gv, names := range resources {
+		list := &metav1.APIResourceList{GroupVersion: gv}
+		for _, name := range names {
+			list.APIResources = append(list.APIResources, metav1.APIResource{Name: name})
+		}
+		disc.Resources = append(disc.Resources, list)
+	}
+	return clientset
+}
+
+func TestDiscoverAPIs_Fallbacks(t *testing.T) {
+	tests := []struct {
+		name           string
+		resources      map[string][]string
+		hpa            string
+		endpointSlices bool
+	}{
+		{
+			name: "modern cluster",
+			resources: map[string][]string{
+				"autoscaling/v1":      {"horizontalpodautoscalers"},
+				"autoscaling/v2":      {"horizontalpodautoscalers", "horizontalpodautoscalers/status"},
+				"discovery.k8s.io/v1": {"endpointslices"},
+			},
+			hpa:            "v2",
+			endpointSlices: true,
+		},
+		{
+			name: "1.20 cluster",
+			resources: map[string][]string{
+				"autoscaling/v1":           {"horizontalpodautoscalers"},
+				"autoscaling/v2beta2":      {"horizontalpodautoscalers"},
+				"discovery.k8s.io/v1beta1": {"endpointslices"},
+			},
+			hpa: "v1",
+		},
+		{
+			name:      "no autoscaling",
+			resources: map[string][]string{"v1": {"pods", "endpoints"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apis, err := DiscoverAPIs(servedAPIs("29", tt.resources).Discovery())
+			if err != nil {
+				t.Fatalf("DiscoverAPIs() error = %v", err)
+			}
+			if got := apis.HPAVersion(); got != tt.hpa {
+				t.Errorf("HPAVersion() = %q, want %q", got, tt.hpa)
+			}
+			if got := apis.Supports(EndpointSliceGVR); got != tt.endpointSlices {
+				t.Errorf("Supports(endpointslices) = %v, want %v", got, tt.endpointSlices)
+			}
+			if apis.Supports(RolloutsGVR) || apis.Supports(VirtualServiceGVR) {
+				t.Error("CRDs that aren't installed should not be supported")
+			}
+		})
+	}
+
+	var unknown *APIs
+	if !unknown.Supports(RolloutsGVR) || unknown.HPAVersion() != "v2" {
+		t.Error("before discovery every API should be taken as served")
+	}
+}
+
+func TestAPIs_SkewWarning(t *testing.T) {
+	major, minor, ok := ClientVersion()
+	if !ok {
+		t.Skip("client-go version not in the build info")
+	}
+	tests := []struct {
+		serverMinor string
+		want        string
+	}{
+		{fmt.Sprint(minor), ""},
+		{fmt.Sprintf("%d+", minor-2), ""},
+		{fmt.Sprintf("%d+", minor-5), fmt.Sprintf("5 minors behind client %d.%d", major, minor)},
+		{fmt.Sprint(minor + 3), fmt.Sprintf("3 minors ahead of client %d.%d", major, minor)},
+	}
+	for _, tt := range tests {
+		apis := &APIs{ServerVersion: &version.Info{Major: fmt.Sprint(major), Minor: tt.serverMinor}}
+		if got := apis.SkewWarning(); got != tt.want {
+			t.Errorf("SkewWarning() for minor %s = %q, want %q", tt.serverMinor, got, tt.want)
+		}
+	}
+
+	apis := &APIs{ServerVersion: &version.Info{Major: "1", Minor: ""}}
+	if _, ok := apis.VersionSkew(); ok {
+		t.Error("an unparsable server version should have no skew")
+	}
+}
+
+func TestClient_ListHPAs_V1Fallback(t *testing.T) {
+	target := int32(70)
+	current := int32(45)
+	clientset := servedAPIs("20", map[string][]string{"autoscaling/v1": {"horizontalpodautoscalers"}})
+	_ = clientset.Tracker().Add(&autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef:                 autoscalingv1.CrossVersionObjectReference{Kind: "Deployment", Name: "web"},
+			MaxReplicas:                    5,
+			TargetCPUUtilizationPercentage: &target,
+		},
+		Status: autoscalingv1.HorizontalPodAutoscalerStatus{CurrentReplicas: 2, CurrentCPUUtilizationPercentage: &current},
+	})
+	client := &Client{clientset: clientset, apis: &apiScan{}}
+	if _, err := client.DiscoverAPIs(); err != nil {
+		t.Fatalf("DiscoverAPIs() error = %v", err)
+	}
+
+	hpas, err := client.ListHPAs(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("ListHPAs() error = %v", err)
+	}
+	if len(hpas) != 1 || hpas[0].Reference != "Deployment/web" || hpas[0].MinReplicas != 1 || hpas[0].Replicas != 2 {
+		t.Fatalf("ListHPAs() = %+v, want the v1 HPA", hpas)
+	}
+	if hpas[0].Targets != "cpu: 45%/70%" {
+		t.Errorf("targets = %q, want the CPU target", hpas[0].Targets)
+	}
+	for _, action := range clientset.Actions() {
+		if action.GetResource().Version == "v2" {
+			t.Errorf("autoscaling/v2 requested on a cluster without it: %v", action)
+		}
+	}
+}
+
+func TestDynamicClient_Gated(t *testing.T) {
+	dynamicClient := rolloutClient(canaryRollout(nil, nil))
+	client := &Client{clientset: servedAPIs("29", map[string][]string{"argoproj.io/v1alpha1": {"rollouts"}}), dynamicClient: dynamicClient, apis: &apiScan{}}
+
+	// Before discovery, requests go to the cluster
+	_, _ = client.DynamicClient().Resource(VirtualServiceGVR).Namespace("default").Get(context.Background(), "shop", metav1.GetOptions{})
+	if len(dynamicClient.Actions()) != 1 {
+		t.Fatalf("actions = %d, want the get sent", len(dynamicClient.Actions()))
+	}
+
+	if _, err := client.DiscoverAPIs(); err != nil {
+		t.Fatalf("DiscoverAPIs() error = %v", err)
+	}
+	_, err := client.DynamicClient().Resource(VirtualServiceGVR).Namespace("default").List(context.Background(), metav1.ListOptions{})
+	if !apierrors.IsNotFound(err) || len(dynamicClient.Actions()) != 1 {
+		t.Errorf("VirtualServices = %v after %d actions, want NotFound without a request", err, len(dynamicClient.Actions()))
+	}
+	if _, err := client.DynamicClient().Resource(RolloutsGVR).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{}); err != nil {
+		t.Errorf("served Rollouts should be read: %v", err)
+	}
+}
+
+func TestListServiceEndpointSlices_EndpointsFallback(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.1", TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "web-1"}}},
+			NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.2"}},
+			Ports:             []corev1.EndpointPort{{Name: "http", Port: 8080, Protocol: corev1.ProtocolTCP}},
+		}},
+	})
+	clientset.PrependReactor("list", "endpointslices", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "discovery.k8s.io", Resource: "endpointslices"}, "")
+	})
+
+	slices, err := listServiceEndpointSlices(context.Background(), clientset, "default", "web")
+	if err != nil {
+		t.Fatalf("listServiceEndpointSlices() error = %v", err)
+	}
+	if countReadyEndpoints(slices) != 1 || len(slices.Items) != 1 || len(slices.Items[0].Endpoints) != 2 {
+		t.Fatalf("slices = %+v, want one slice with a ready and a not ready endpoint", slices.Items)
+	}
+	if ready, _ := endpointAddresses(slices, "http"); len(ready) != 1 || ready[0] != "10.0.0.1:8080" {
+		t.Errorf("ready addresses = %v", ready)
+	}
+
+	state := podEndpointState(slices.Items, PodInfo{Name: "web-1", IP: "10.0.0.1"})
+	if state != PodEndpointServing {
+		t.Errorf("pod state = %s, want serving", state)
+	}
+
+	if slices, err := listServiceEndpointSlices(context.Background(), clientset, "default", "api"); err != nil || len(slices.Items) != 0 {
+		t.Errorf("a Service without Endpoints = %+v, %v, want none", slices, err)
+	}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// APIErrorDetail is what the API server said about a failed request.
+type APIErrorDetail struct {
+	Code     int32               // HTTP status, e.g. 403
+	Reason   metav1.StatusReason // e.g. Forbidden
+	Group    string              // API group of the resource, "" for the core group
+	Resource string              // e.g. deployments
+	Name     string              // Object the request was about, "" for lists
+	Message  string
+}
+
+// DescribeAPIError extracts the detail of an error returned by the API
+// server. ok is false for other errors, such as a refused connection or
+// ErrReadOnly.
+func DescribeAPIError(err error) (detail APIErrorDetail, ok bool) {
+	var apiStatus apierrors.APIStatus
+	if err == nil || !errors.As(err, &apiStatus) {
+		return APIErrorDetail{}, false
+	}
+	status := apiStatus.Status()
+	detail = APIErrorDetail{
+		Code:    status.Code,
+		Reason:  status.Reason,
+		Message: status.Message,
+	}
+	if d := status.Details; d != nil {
+		// apierrors puts the resource, not the kind, in Kind
+		detail.Group, detail.Resource, detail.Name = d.Group, d.Kind, d.Name
+	}
+	return detail, true
+}
+
+// GroupResource is the resource as kubectl names it, e.g.
+// "deployments.apps", or "pods" for the core group.
+func (d APIErrorDetail) GroupResource() string {
+	if d.Group == "" {
+		return d.Resource
+	}
+	return d.Resource + "." + d.Group
+}
+
+// Summary is the status and resource of the error, e.g.
+// `HTTP 403 Forbidden, deployments.apps "web"`.
+func (d APIErrorDetail) Summary() string {
+	reason := string(d.Reason)
+	if reason == "" {
+		reason = http.StatusText(int(d.Code))
+	}
+	s := fmt.Sprintf("HTTP %d %s", d.Code, reason)
+	if resource := d.GroupResource(); resource != "" {
+		s += ", " + resource
+		if d.Name != "" {
+			s += fmt.Sprintf(" %q", d.Name)
+		}
+	}
+	return s
+}
+
+// Retryable reports whether the request is likely to succeed when made
+// again: the object changed meanwhile, or the server was busy.
+func (d APIErrorDetail) Retryable() bool {
+	switch d.Reason {
+	case metav1.StatusReasonConflict, metav1.StatusReasonTooManyRequests,
+		metav1.StatusReasonServiceUnavailable, metav1.StatusReasonServerTimeout, metav1.StatusReasonTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestDescribeAPIError(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "",
+		errors.New(`User "dev" cannot list resource "deployments" in API group "apps" in the namespace "prod"`))
+
+	tests := []struct {
+		name        string
+		err         error
+		wantOK      bool
+		wantSummary string
+	}{
+		{"forbidden list", forbidden, true, "HTTP 403 Forbidden, deployments.apps"},
+		{"wrapped", fmt.Errorf("list workloads: %w", forbidden), true, "HTTP 403 Forbidden, deployments.apps"},
+		{"not found in the core group", apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "web-0"), true, `HTTP 404 NotFound, pods "web-0"`},
+		{"no details", apierrors.NewServiceUnavailable("try later"), true, "HTTP 503 ServiceUnavailable"},
+		{"not from the API server", errors.New("connection refused"), false, ""},
+		{"read-only", ErrReadOnly, false, ""},
+		{"nil", nil, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail, ok := DescribeAPIError(tt.err)
+			if ok != tt.wantOK {
+				t.Fatalf("DescribeAPIError() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && detail.Summary() != tt.wantSummary {
+				t.Errorf("Summary() = %q, want %q", detail.Summary(), tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestDescribeAPIError_FromClientset(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("list", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "", errors.New("denied"))
+	})
+
+	_, err := clientset.AppsV1().Deployments("prod").List(context.Background(), metav1.ListOptions{})
+	detail, ok := DescribeAPIError(err)
+	if !ok {
+		t.Fatalf("DescribeAPIError(%v) ok = false", err)
+	}
+	if detail.Code != 403 || detail.GroupResource() != "deployments.apps" {
+		t.Errorf("DescribeAPIError() = %+v, want 403 on deployments.apps", detail)
+	}
+}
+
+func TestAPIErrorDetail_Retryable(t *testing.T) {
+	conflict, _ := DescribeAPIError(apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "app", errors.New("modified")))
+	forbidden, _ := DescribeAPIError(apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("denied")))
+	if !conflict.Retryable() {
+		t.Error("a conflict should be retryable")
+	}
+	if forbidden.Retryable() {
+		t.Error("a forbidden request should not be retryable")
+	}
+}
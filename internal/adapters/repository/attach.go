@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// DetachKeys end an attach session without stopping the container's
+// process: Ctrl+P followed by Ctrl+Q, as in docker attach.
+var DetachKeys = [2]byte{0x10, 0x11}
+
+// ErrNoTTY is returned by AttachToContainer when a TTY is asked for but
+// the container was not started with tty: true, so there is no terminal
+// to attach to.
+var ErrNoTTY = errors.New("container does not allocate a TTY")
+
+// ErrDetached is returned by AttachToContainer when the session ends with
+// DetachKeys, leaving the process running.
+var ErrDetached = errors.New("detached")
+
+// AttachToContainer attaches the streams of opts to the main process of a
+// running container, like kubectl attach, and blocks until the process
+// exits, ctx is cancelled or DetachKeys are typed. With opts.TTY set, the
+// container must allocate a TTY (ErrNoTTY otherwise).
+func AttachToContainer(ctx context.Context, config *rest.Config, namespace, pod, container string, opts ExecOptions) error {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create clientset: %w", err)
+	}
+	p, err := clientset.CoreV1().Pods(namespace).Get(ctx, pod, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod: %w", err)
+	}
+	spec, err := checkAttachable(p, container, opts.TTY)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stdin := opts.Stdin
+	if !spec.Stdin {
+		// Input would be dropped by the kubelet; don't offer it
+		stdin = nil
+	}
+	var detached atomic.Bool
+	if stdin != nil {
+		stdin = &detachReader{r: stdin, onDetach: func() {
+			detached.Store(true)
+			cancel()
+		}}
+	}
+	stderr := opts.Stderr
+	if opts.TTY {
+		stderr = nil
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("attach").
+		VersionedParams(&corev1.PodAttachOptions{
+			Container: container,
+			Stdin:     stdin != nil,
+			Stdout:    opts.Stdout != nil,
+			Stderr:    stderr != nil,
+			TTY:       opts.TTY,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create executor: %w", err)
+	}
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:             stdin,
+		Stdout:            opts.Stdout,
+		Stderr:            stderr,
+		Tty:               opts.TTY,
+		TerminalSizeQueue: opts.TerminalSizes,
+	})
+	if detached.Load() {
+		return ErrDetached
+	}
+	return err
+}
+
+// checkAttachable returns the spec of a container of pod that can be
+// attached to: one that exists and is running and, when tty is set,
+// allocates a TTY.
+func checkAttachable(pod *corev1.Pod, container string, tty bool) (*corev1.Container, error) {
+	var spec *corev1.Container
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == container {
+			spec = &pod.Spec.Containers[i]
+		}
+	}
+	if spec == nil {
+		return nil, fmt.Errorf("no container %q in pod %s", container, pod.Name)
+	}
+	running := false
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == container && cs.State.Running != nil {
+			running = true
+		}
+	}
+	if !running {
+		return nil, fmt.Errorf("container %q is not running", container)
+	}
+	if tty && !spec.TTY {
+		return nil, fmt.Errorf("%w: %q was not started with tty: true; its output is in the logs", ErrNoTTY, container)
+	}
+	return spec, nil
+}
+
+// detachReader passes input through until it reads DetachKeys, which it
+// swallows, calls onDetach and then reports EOF. A Ctrl+P not followed by
+// Ctrl+Q is passed through.
+type detachReader struct {
+	r        io.Reader
+	onDetach func()
+	pending  bool   // A Ctrl+P was read and held back
+	buf      []byte // Input read but not returned yet
+	err      error  // Error of r, returned once buf is drained
+	detached bool
+}
+
+func (d *detachReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.detached {
+			return 0, io.EOF
+		}
+		if d.err != nil {
+			return 0, d.err
+		}
+		chunk := make([]byte, max(len(p), 1))
+		n, err := d.r.Read(chunk)
+		for _, b := range chunk[:n] {
+			if d.pending {
+				d.pending = false
+				if b == DetachKeys[1] {
+					d.detached = true
+					d.onDetach()
+					break
+				}
+				d.buf = append(d.buf, DetachKeys[0])
+			}
+			if b == DetachKeys[0] {
+				d.pending = true
+				continue
+			}
+			d.buf = append(d.buf, b)
+		}
+		if err != nil && !d.detached {
+			if d.pending {
+				d.buf = append(d.buf, DetachKeys[0])
+				d.pending = false
+			}
+			d.err = err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func attachPod(tty bool, running bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "repl", Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "python", Stdin: true, TTY: tty}}},
+		Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "python"}}},
+	}
+	if running {
+		pod.Status.ContainerStatuses[0].State.Running = &corev1.ContainerStateRunning{}
+	} else {
+		pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+	}
+	return pod
+}
+
+func TestCheckAttachable(t *testing.T) {
+	if _, err := checkAttachable(attachPod(true, true), "python", true); err != nil {
+		t.Errorf("running container with a TTY: %v", err)
+	}
+	if _, err := checkAttachable(attachPod(false, true), "python", false); err != nil {
+		t.Errorf("attaching without a TTY to a container without one: %v", err)
+	}
+
+	_, err := checkAttachable(attachPod(false, true), "python", true)
+	if !errors.Is(err, ErrNoTTY) || !strings.Contains(err.Error(), "tty: true") {
+		t.Errorf("container without a TTY: error = %v, want ErrNoTTY explained", err)
+	}
+	if _, err := checkAttachable(attachPod(true, false), "python", true); err == nil || !strings.Contains(err.Error(), "not running") {
+		t.Errorf("waiting container: error = %v, want not running", err)
+	}
+	if _, err := checkAttachable(attachPod(true, true), "sidecar", true); err == nil {
+		t.Error("an unknown container should be an error")
+	}
+}
+
+func TestDetachReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		want     string
+		detached bool
+	}{
+		{"plain input", "print(1)\r", "print(1)\r", false},
+		{"detach keys", "1+1\r\x10\x11exit()\r", "1+1\r", true},
+		{"lone ctrl+p", "\x10a\x10\x10\x11b", "\x10a\x10", true},
+		{"ctrl+p at eof", "a\x10", "a\x10", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detached := false
+			// One byte per read, so the keys span reads
+			r := &detachReader{r: iotest.OneByteReader(strings.NewReader(tt.input)), onDetach: func() { detached = true }}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if string(got) != tt.want || detached != tt.detached {
+				t.Errorf("read %q, detached %v; want %q, %v", got, detached, tt.want, tt.detached)
+			}
+		})
+	}
+}
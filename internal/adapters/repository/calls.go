@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CallKind is what a call to the API server fetches, which sets how long
+// it may take.
+type CallKind int
+
+const (
+	CallList CallKind = iota // Lists and gets
+	CallLogs                 // Log fetches, which read whole containers' output
+)
+
+// CallTimeouts bounds how long each kind of call may take before it is
+// abandoned.
+type CallTimeouts struct {
+	List time.Duration
+	Logs time.Duration
+}
+
+// DefaultCallTimeouts are the timeouts of calls when none are configured.
+var DefaultCallTimeouts = CallTimeouts{List: 10 * time.Second, Logs: 30 * time.Second}
+
+// For returns the timeout of a kind of call.
+func (t CallTimeouts) For(kind CallKind) time.Duration {
+	if kind == CallLogs {
+		return t.Logs
+	}
+	return t.List
+}
+
+// CallScope groups the calls made on behalf of one view, such as the
+// navigator of a namespace or the dashboard of a pod, so that every call
+// still in flight is abandoned at once when the user leaves it. Each call
+// also gets the deadline of its kind, so a hung API server fails it rather
+// than leaving the view loading forever.
+type CallScope struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	timeouts CallTimeouts
+
+	mu        sync.Mutex
+	cancelled bool
+}
+
+// NewCallScope returns a scope whose calls are bounded by timeouts.
+func NewCallScope(timeouts CallTimeouts) *CallScope {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &CallScope{ctx: ctx, cancel: cancel, timeouts: timeouts}
+}
+
+// Context returns the context of a call of kind, done when the call's
+// timeout is over or the scope is cancelled. The caller must call cancel
+// once the call returns.
+func (s *CallScope) Context(kind CallKind) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(s.ctx, s.timeouts.For(kind))
+}
+
+// Timeouts returns the timeouts of the scope's calls.
+func (s *CallScope) Timeouts() CallTimeouts {
+	return s.timeouts
+}
+
+// Cancel abandons every call of the scope in flight, and those made from
+// now on.
+func (s *CallScope) Cancel() {
+	s.mu.Lock()
+	s.cancelled = true
+	s.mu.Unlock()
+	s.cancel()
+}
+
+// Cancelled reports whether Cancel was called, so that the result of a
+// call abandoned with it can be dropped.
+func (s *CallScope) Cancelled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cancelled
+}
+
+// Renew cancels the scope and returns a new one with the same timeouts,
+// for the view that replaces it.
+func (s *CallScope) Renew() *CallScope {
+	s.Cancel()
+	return NewCallScope(s.timeouts)
+}
+
+// IsCallTimeout reports whether err is a call that ran past its deadline.
+// client-go doesn't always wrap the context's error, so its message is
+// checked too.
+func IsCallTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, context.DeadlineExceeded.Error()) ||
+		// From client-go's rate limiter when the wait would outlast the deadline
+		strings.Contains(msg, "would exceed context deadline")
+}
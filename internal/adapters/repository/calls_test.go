@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// blockingAPIServer answers no request: each one blocks until the client
+// abandons it. started receives the path of every request as it arrives,
+// abandoned once its client has gone.
+func blockingAPIServer(t *testing.T) (clientset kubernetes.Interface, started, abandoned chan string) {
+	t.Helper()
+	started, abandoned = make(chan string, 10), make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- r.URL.Path
+		<-r.Context().Done()
+		abandoned <- r.URL.Path
+	}))
+	t.Cleanup(server.Close)
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clientset, started, abandoned
+}
+
+// waitFor returns what ch receives, failing the test after a few seconds.
+func waitFor(t *testing.T, ch <-chan string, what string) string {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+		return ""
+	}
+}
+
+func TestCallScope_CancelAbandonsCalls(t *testing.T) {
+	clientset, started, abandoned := blockingAPIServer(t)
+	scope := NewCallScope(CallTimeouts{List: time.Minute, Logs: time.Minute})
+
+	errs := make(chan error, 2)
+	go func() {
+		ctx, cancel := scope.Context(CallList)
+		defer cancel()
+		_, _, err := ListPodsPage(ctx, clientset, "default", DefaultPageSize, "")
+		errs <- err
+	}()
+	go func() {
+		ctx, cancel := scope.Context(CallLogs)
+		defer cancel()
+		_, err := GetPodLogs(ctx, clientset, "default", "web-1", LogOptions{Container: "app"})
+		errs <- err
+	}()
+	waitFor(t, started, "the first call")
+	waitFor(t, started, "the second call")
+
+	// Leaving the view
+	scope.Cancel()
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("abandoned call error = %v, want context.Canceled", err)
+			}
+			if IsCallTimeout(err) {
+				t.Errorf("an abandoned call is not a timeout: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("a call was still blocked after its scope was cancelled")
+		}
+	}
+	waitFor(t, abandoned, "the server to see the first call abandoned")
+	waitFor(t, abandoned, "the server to see the second call abandoned")
+	if !scope.Cancelled() {
+		t.Error("Cancelled() = false after Cancel()")
+	}
+}
+
+func TestCallScope_Timeout(t *testing.T) {
+	clientset, started, abandoned := blockingAPIServer(t)
+	scope := NewCallScope(CallTimeouts{List: 50 * time.Millisecond, Logs: 100 * time.Millisecond})
+
+	calls := []struct {
+		name string
+		kind CallKind
+		call func(ctx context.Context) error
+	}{
+		{"list", CallList, func(ctx context.Context) error {
+			_, err := ListWorkloads(ctx, clientset, "default", ResourceDeployments)
+			return err
+		}},
+		{"logs", CallLogs, func(ctx context.Context) error {
+			_, err := GetPodLogs(ctx, clientset, "default", "web-1", LogOptions{Container: "app"})
+			return err
+		}},
+	}
+	for _, c := range calls {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := scope.Context(c.kind)
+			defer cancel()
+			start := time.Now()
+			err := c.call(ctx)
+			if !IsCallTimeout(err) {
+				t.Fatalf("error = %v, want a call timeout", err)
+			}
+			if elapsed, limit := time.Since(start), scope.Timeouts().For(c.kind); elapsed < limit || elapsed > limit+5*time.Second {
+				t.Errorf("call returned after %v, want right after its %v timeout", elapsed, limit)
+			}
+			path := waitFor(t, started, "the call")
+			if got := waitFor(t, abandoned, "the server to see the call abandoned"); got != path {
+				t.Errorf("abandoned %s, want %s", got, path)
+			}
+		})
+	}
+	if scope.Cancelled() {
+		t.Error("timeouts should leave the scope usable")
+	}
+}
+
+func TestCallScope_Renew(t *testing.T) {
+	scope := NewCallScope(DefaultCallTimeouts)
+	renewed := scope.Renew()
+	if !scope.Cancelled() || renewed.Cancelled() {
+		t.Fatalf("Renew() should cancel the old scope only: old %v, new %v", scope.Cancelled(), renewed.Cancelled())
+	}
+	if renewed.Timeouts() != DefaultCallTimeouts {
+		t.Errorf("Renew() timeouts = %+v, want %+v", renewed.Timeouts(), DefaultCallTimeouts)
+	}
+
+	ctx, cancel := scope.Context(CallList)
+	defer cancel()
+	if ctx.Err() == nil {
+		t.Error("a call made after Cancel() should be done at once")
+	}
+}
+
+func TestIsCallTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"deadline", context.DeadlineExceeded, true},
+		{"wrapped deadline", fmt.Errorf("failed to list pods: %w", context.DeadlineExceeded), true},
+		{"deadline in the message", fmt.Errorf(`Get "https://10.0.0.1/api/v1/pods": context deadline exceeded`), true},
+		{"rate limiter", fmt.Errorf("client rate limiter Wait returned an error: rate: Wait(n=1) would exceed context deadline"), true},
+		{"cancelled", context.Canceled, false},
+		{"other", errors.New("connection refused"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCallTimeout(tt.err); got != tt.want {
+				t.Errorf("IsCallTimeout(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
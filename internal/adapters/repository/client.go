@@ -14,14 +14,13 @@ package repository
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 	metricsv "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
@@ -30,19 +29,90 @@ import (
 // including standard resources, custom resources (via dynamic client), and metrics.
 type Client struct {
 	clientset     kubernetes.Interface
-	metricsClient *metricsv.Clientset
+	metricsClient MetricsClientInterface
 	dynamicClient dynamic.Interface
 	config        *rest.Config
 	context       string
 	namespace     string
+	kubeconfig    string               // Explicit kubeconfig path; empty to use KUBECONFIG or ~/.kube/config
+	warnings      []string             // Problems found loading the kubeconfig, such as missing files
+	readOnly      *readOnlyGuard       // Rejects changes to the cluster when enabled; nil for never
+	credentials   *credentialRefresher // Retries requests rejected with 401 with rebuilt credentials
+	contextNS     string               // Namespace the kubeconfig context sets, "default" when none
+	metricsProbe  *metricsProbe        // Last result of ProbeMetrics; nil for never probed
+	apis          *apiScan             // Last result of DiscoverAPIs; nil for never discovered
+	replay        *SessionSnapshot     // Recorded snapshot served instead of a cluster; nil for a live client
 }
 
-// NewClient creates a new Kubernetes client using the default kubeconfig.
-// It first attempts to use ~/.kube/config, falling back to in-cluster config
-// if running inside a Kubernetes cluster.
+// NewClient creates a new Kubernetes client for the current context of the
+// kubeconfig files in KUBECONFIG, or ~/.kube/config when it is unset,
+// falling back to in-cluster config if running inside a Kubernetes cluster.
 func NewClient() (*Client, error) {
-	kubeconfig := filepath.Join(homedir.HomeDir(), ".kube", "config")
-	return NewClientWithKubeconfig(kubeconfig)
+	return NewClientFor("", "")
+}
+
+// NewClientForContext creates a new Kubernetes client for a named kubeconfig
+// context instead of the current one, without changing the kubeconfig.
+// It returns an error if the context does not exist.
+func NewClientForContext(contextName string) (*Client, error) {
+	return NewClientFor("", contextName)
+}
+
+// NewClientFor creates a new Kubernetes client from the kubeconfig files
+// chosen by KubeconfigRules(kubeconfig), merged, for contextName or the
+// current context when empty. Missing KUBECONFIG entries are skipped and
+// reported by Warnings. Without a context name, a kubeconfig that can't be
+// used falls back to in-cluster config; a named context must exist.
+func NewClientFor(kubeconfig, contextName string) (*Client, error) {
+	rules := KubeconfigRules(kubeconfig)
+	rawConfig, err := rules.Load()
+	if err != nil && contextName != "" {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	if contextName != "" {
+		if _, ok := rawConfig.Contexts[contextName]; !ok {
+			return nil, fmt.Errorf("unknown context %q", contextName)
+		}
+	}
+
+	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: contextName})
+	config, err := kubeConfig.ClientConfig()
+	inCluster := false
+	switch {
+	case err != nil && contextName != "":
+		return nil, fmt.Errorf("failed to create kubernetes config for context %q: %w", contextName, err)
+	case err != nil:
+		config, err = rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kubernetes config: %w", err)
+		}
+		inCluster = true
+	}
+
+	client, err := NewClientFromConfig(config, "")
+	if err != nil {
+		return nil, err
+	}
+	client.context = contextName
+	if contextName == "" && rawConfig != nil {
+		client.context = rawConfig.CurrentContext
+	}
+	if ns, _, err := kubeConfig.Namespace(); err == nil && !inCluster {
+		client.contextNS = ns
+	}
+
+	// Rebuild credentials for the same context, even if the kubeconfig's
+	// current context changes meanwhile; in-cluster tokens are re-read too
+	resolved := client.context
+	client.credentials.rebuild = func() (*rest.Config, error) {
+		if inCluster {
+			return rest.InClusterConfig()
+		}
+		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(KubeconfigRules(kubeconfig), &clientcmd.ConfigOverrides{CurrentContext: resolved}).ClientConfig()
+	}
+	client.kubeconfig = kubeconfig
+	client.warnings = KubeconfigWarnings(rules)
+	return client, nil
 }
 
 // NewClientWithKubeconfig creates a new Kubernetes client using the specified kubeconfig path.
@@ -72,6 +142,12 @@ func NewClientFromConfig(config *rest.Config, kubeconfigPath string) (*Client, e
 	// Apply standard settings
 	config.Timeout = 30 * time.Second
 	config.WarningHandler = rest.NoWarnings{}
+	// The read-only guard wraps the credential refresher, so that requests
+	// retried with rebuilt credentials were checked by it too
+	credentials := &credentialRefresher{}
+	config.Wrap(credentials.wrap)
+	guard := &readOnlyGuard{}
+	config.Wrap(guard.wrap)
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
@@ -79,8 +155,12 @@ func NewClientFromConfig(config *rest.Config, kubeconfigPath string) (*Client, e
 		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
-	// Metrics client may fail if metrics-server is not installed
-	metricsClient, _ := metricsv.NewForConfig(config)
+	// Metrics client may fail if metrics-server is not installed; a nil
+	// *Clientset would not compare equal to a nil interface
+	var metricsClient MetricsClientInterface
+	if mc, err := metricsv.NewForConfig(config); err == nil {
+		metricsClient = mc
+	}
 
 	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
@@ -97,8 +177,8 @@ func NewClientFromConfig(config *rest.Config, kubeconfigPath string) (*Client, e
 			currentContext = rawConfig.CurrentContext
 		}
 	} else {
-		// Fall back to default loading rules
-		rawConfig, _ := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+		// Fall back to KUBECONFIG or ~/.kube/config
+		rawConfig, _ := KubeconfigRules("").Load()
 		if rawConfig != nil {
 			currentContext = rawConfig.CurrentContext
 		}
@@ -111,12 +191,21 @@ func NewClientFromConfig(config *rest.Config, kubeconfigPath string) (*Client, e
 		config:        config,
 		context:       currentContext,
 		namespace:     "default",
+		readOnly:      guard,
+		credentials:   credentials,
+		metricsProbe:  &metricsProbe{},
+		apis:          &apiScan{},
 	}, nil
 }
 
 // DynamicClient returns the dynamic client for custom resource operations.
 // Use this for Istio resources, custom CRDs, and other non-standard resources.
+// Once the APIs are discovered, reads of resources the cluster doesn't
+// serve fail with NotFound without a request.
 func (c *Client) DynamicClient() dynamic.Interface {
+	if apis := c.APIs(); apis != nil && c.dynamicClient != nil {
+		return gatedDynamicClient{Interface: c.dynamicClient, apis: apis}
+	}
 	return c.dynamicClient
 }
 
@@ -128,10 +217,22 @@ func (c *Client) Clientset() kubernetes.Interface {
 
 // MetricsClient returns the metrics client for resource usage data.
 // May return nil if metrics-server is not available in the cluster.
-func (c *Client) MetricsClient() *metricsv.Clientset {
+func (c *Client) MetricsClient() MetricsClientInterface {
 	return c.metricsClient
 }
 
+// Config returns the REST config the clients were built from.
+// Use this for streaming subresources such as exec.
+func (c *Client) Config() *rest.Config {
+	return c.config
+}
+
+// Warnings returns the problems found loading the kubeconfig, such as
+// KUBECONFIG entries that don't exist.
+func (c *Client) Warnings() []string {
+	return c.warnings
+}
+
 // Context returns the current Kubernetes context name.
 func (c *Client) Context() string {
 	return c.context
@@ -142,6 +243,16 @@ func (c *Client) Namespace() string {
 	return c.namespace
 }
 
+// ContextNamespace returns the namespace the kubeconfig context sets, as
+// kubectl uses it when no namespace is given: "default" when the context
+// sets none.
+func (c *Client) ContextNamespace() string {
+	if c.contextNS == "" {
+		return "default"
+	}
+	return c.contextNS
+}
+
 // SetNamespace changes the currently selected namespace.
 func (c *Client) SetNamespace(ns string) {
 	c.namespace = ns
@@ -152,11 +263,14 @@ func (c *Client) ListNamespaces(ctx context.Context) ([]NamespaceInfo, error) {
 	return ListNamespaces(ctx, c.clientset)
 }
 
-// ListContexts returns all available Kubernetes contexts from kubeconfig
-// along with the currently active context name.
+// ListContexts returns all available Kubernetes contexts, merged across
+// the client's kubeconfig files, along with the currently active context
+// name.
 func (c *Client) ListContexts() ([]string, string, error) {
-	rules := clientcmd.NewDefaultClientConfigLoadingRules()
-	config, err := rules.Load()
+	if c.replay != nil {
+		return []string{c.context}, c.context, nil
+	}
+	config, err := KubeconfigRules(c.kubeconfig).Load()
 	if err != nil {
 		//coverage:ignore
 		return nil, "", err
@@ -169,14 +283,127 @@ func (c *Client) ListContexts() ([]string, string, error) {
 	return contexts, config.CurrentContext, nil
 }
 
+// SwitchContext points the client at another kubeconfig context, rebuilding
+// the clientset, dynamic client and metrics client from the context's
+// cluster and credentials, and resets the namespace to "default". Building
+// clients does not contact the cluster, so an unreachable context is only
+// noticed by the next request. The context is looked up in the same
+// kubeconfig files the client was created from, and read-only mode carries
+// over. On error the client is left unchanged.
+func (c *Client) SwitchContext(name string) error {
+	if c.replay != nil {
+		return fmt.Errorf("cannot switch contexts while replaying a recording")
+	}
+	next, err := NewClientFor(c.kubeconfig, name)
+	if err != nil {
+		return err
+	}
+	next.SetReadOnly(c.ReadOnly())
+	*c = *next
+	return nil
+}
+
 // DeletePod deletes a pod by name in the specified namespace.
 func (c *Client) DeletePod(ctx context.Context, namespace, name string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
 	return DeletePod(ctx, c.clientset, namespace, name)
 }
 
+// DeletePods deletes pods of a namespace concurrently, attempting every
+// pod. See DeletePods.
+func (c *Client) DeletePods(ctx context.Context, namespace string, names []string, onResult func(PodDeleteResult)) ([]PodDeleteResult, error) {
+	if c.ReadOnly() {
+		return nil, ErrReadOnly
+	}
+	return DeletePods(ctx, c.clientset, namespace, names, onResult)
+}
+
+// DeletePodForce deletes a pod stuck terminating without waiting for its
+// kubelet. See DeletePodForce.
+func (c *Client) DeletePodForce(ctx context.Context, namespace, name string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	return DeletePodForce(ctx, c.clientset, namespace, name)
+}
+
+// RestartPod deletes a single pod and waits for its controller to replace
+// it, returning the replacement. See RestartPod.
+func (c *Client) RestartPod(ctx context.Context, namespace, name string) (*PodInfo, error) {
+	if c.ReadOnly() {
+		return nil, ErrReadOnly
+	}
+	return RestartPod(ctx, c.clientset, namespace, name)
+}
+
+// AddNetshootContainer injects a netshoot debug container into a pod and
+// returns its name once it runs. See AddNetshootContainer.
+func (c *Client) AddNetshootContainer(ctx context.Context, namespace, pod string) (string, error) {
+	if c.ReadOnly() {
+		return "", ErrReadOnly
+	}
+	return AddNetshootContainer(ctx, c.clientset, namespace, pod)
+}
+
+// AddEphemeralContainer injects a debug container running image into a pod,
+// sharing the process namespace of target, and returns its name once it
+// runs. See AddEphemeralContainer.
+func (c *Client) AddEphemeralContainer(ctx context.Context, namespace, pod, image, target string) (string, error) {
+	if c.ReadOnly() {
+		return "", ErrReadOnly
+	}
+	return AddEphemeralContainer(ctx, c.clientset, namespace, pod, image, target)
+}
+
+// CanI reports whether a ServiceAccount may perform verb on a resource of
+// group in namespace, by impersonating it for a SelfSubjectAccessReview.
+// Returns ErrImpersonationForbidden when the current user may not
+// impersonate the account.
+func (c *Client) CanI(ctx context.Context, namespace, serviceAccount, verb, group, resource string) (bool, string, error) {
+	if c.config == nil {
+		return false, "", fmt.Errorf("no REST config to impersonate %s", serviceAccount)
+	}
+	config := rest.CopyConfig(c.config)
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: serviceAccountUser(namespace, serviceAccount),
+		Groups:   serviceAccountGroups(namespace),
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		//coverage:ignore
+		return false, "", fmt.Errorf("failed to create impersonating client: %w", err)
+	}
+	allowed, reason, err := CanI(ctx, clientset, namespace, verb, group, resource)
+	if apierrors.IsForbidden(err) {
+		return false, "", fmt.Errorf("%w: %v", ErrImpersonationForbidden, err)
+	}
+	return allowed, reason, err
+}
+
+// CheckAccess answers checks for a ServiceAccount in namespace with CanI
+// and returns them with Allowed and Reason set. Stops at the first error,
+// e.g. ErrImpersonationForbidden, returning the checks answered so far.
+func (c *Client) CheckAccess(ctx context.Context, namespace, serviceAccount string, checks []AccessCheck) ([]AccessCheck, error) {
+	answered := make([]AccessCheck, 0, len(checks))
+	for _, check := range checks {
+		allowed, reason, err := c.CanI(ctx, namespace, serviceAccount, check.Verb, check.Group, check.Resource)
+		if err != nil {
+			return answered, err
+		}
+		check.Allowed, check.Reason = allowed, reason
+		answered = append(answered, check)
+	}
+	return answered, nil
+}
+
 // ScaleWorkload scales a workload (Deployment, StatefulSet, or Rollout) to the specified replica count.
 // DaemonSets, Jobs, and CronJobs cannot be scaled and will return nil without error.
 func (c *Client) ScaleWorkload(ctx context.Context, namespace, name string, resourceType ResourceType, replicas int32) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
 	switch resourceType {
 	case ResourceDeployments:
 		return ScaleDeployment(ctx, c.clientset, namespace, name, replicas)
@@ -193,6 +420,9 @@ func (c *Client) ScaleWorkload(ctx context.Context, namespace, name string, reso
 // This is done by updating the pod template annotation, forcing new pods to be created.
 // Jobs and CronJobs do not support restart and will return nil without error.
 func (c *Client) RestartWorkload(ctx context.Context, namespace, name string, resourceType ResourceType) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
 	switch resourceType {
 	case ResourceDeployments:
 		return RestartDeployment(ctx, c.clientset, namespace, name)
@@ -204,3 +434,39 @@ func (c *Client) RestartWorkload(ctx context.Context, namespace, name string, re
 		return nil // Jobs and CronJobs don't have restart concept
 	}
 }
+
+// CreateNamespace creates a namespace; see the CreateNamespace function.
+func (c *Client) CreateNamespace(ctx context.Context, name string, labels map[string]string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	return CreateNamespace(ctx, c.clientset, name, labels)
+}
+
+// DeleteNamespace deletes a namespace and everything in it; see the
+// DeleteNamespace function.
+func (c *Client) DeleteNamespace(ctx context.Context, name string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	return DeleteNamespace(ctx, c.clientset, name)
+}
+
+// SetWorkloadImage changes the image of a container of a Deployment,
+// StatefulSet, DaemonSet or Rollout; see the SetWorkloadImage function.
+func (c *Client) SetWorkloadImage(ctx context.Context, namespace, name string, resourceType ResourceType, container, image string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	return SetWorkloadImage(ctx, c.clientset, c.dynamicClient, namespace, name, resourceType, container, image)
+}
+
+// SetWorkloadResources sets the CPU and memory requests and limits of a
+// container of a Deployment, StatefulSet, DaemonSet or Rollout; see the
+// SetWorkloadResources function.
+func (c *Client) SetWorkloadResources(ctx context.Context, namespace, name string, resourceType ResourceType, container string, requests, limits ResourceAmounts) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	return SetWorkloadResources(ctx, c.clientset, c.dynamicClient, namespace, name, resourceType, container, requests, limits)
+}
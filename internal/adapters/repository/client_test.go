@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -174,38 +175,122 @@ func TestNewClientWithKubeconfig_InvalidPath_FallsBackToInCluster(t *testing.T)
 // NewClient Tests (wrapper function)
 // ============================================
 
-func TestNewClient_UsesDefaultKubeconfig(t *testing.T) {
-	// Save and restore KUBECONFIG env var
-	oldKubeconfig := os.Getenv("KUBECONFIG")
-	defer os.Setenv("KUBECONFIG", oldKubeconfig)
-
-	// Create a temp kubeconfig that NewClient should NOT use (it uses ~/.kube/config)
-	tmpDir := t.TempDir()
-	kubeconfigPath := filepath.Join(tmpDir, "config")
-	kubeconfigContent := `apiVersion: v1
+// writeKubeconfig writes a kubeconfig with one context using its own
+// cluster and user, and returns its path.
+func writeKubeconfig(t *testing.T, dir, file, contextName, server, current string) string {
+	t.Helper()
+	content := fmt.Sprintf(`apiVersion: v1
 kind: Config
-current-context: env-context
+current-context: %[3]s
 clusters:
 - cluster:
-    server: https://127.0.0.1:6443
-  name: env-cluster
+    server: %[2]s
+  name: %[1]s-cluster
 contexts:
 - context:
-    cluster: env-cluster
-    user: env-user
-  name: env-context
+    cluster: %[1]s-cluster
+    user: %[1]s-user
+  name: %[1]s
 users:
-- name: env-user
-`
-	_ = os.WriteFile(kubeconfigPath, []byte(kubeconfigContent), 0600)
-	os.Setenv("KUBECONFIG", kubeconfigPath)
+- name: %[1]s-user
+  user:
+    token: %[1]s-token
+`, contextName, server, current)
+	path := filepath.Join(dir, file)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write temp kubeconfig: %v", err)
+	}
+	return path
+}
+
+func TestNewClient_MergesKubeconfigList(t *testing.T) {
+	tmpDir := t.TempDir()
+	personal := writeKubeconfig(t, tmpDir, "config", "personal", "https://personal.example.com:6443", "personal")
+	work := writeKubeconfig(t, tmpDir, "work", "work", "https://work.example.com:6443", "work")
 
-	// NewClient uses ~/.kube/config directly, not KUBECONFIG env var
-	// This test documents that behavior
-	_, err := NewClient()
+	oldKubeconfig := os.Getenv("KUBECONFIG")
+	os.Setenv("KUBECONFIG", personal+string(filepath.ListSeparator)+work)
+	defer os.Setenv("KUBECONFIG", oldKubeconfig)
+
+	client, err := NewClient()
 	if err != nil {
-		// Expected to fail if no ~/.kube/config or not in cluster
-		t.Logf("NewClient() error (expected if no kubeconfig): %v", err)
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	// The first file to set current-context wins
+	if client.Context() != "personal" || client.config.Host != "https://personal.example.com:6443" {
+		t.Errorf("NewClient() = %s on %s, want the current context of the first file", client.Context(), client.config.Host)
+	}
+	if len(client.Warnings()) != 0 {
+		t.Errorf("Warnings() = %v, want none", client.Warnings())
+	}
+
+	contexts, current, err := client.ListContexts()
+	if err != nil {
+		t.Fatalf("ListContexts() error = %v", err)
+	}
+	sort.Strings(contexts)
+	if strings.Join(contexts, ",") != "personal,work" || current != "personal" {
+		t.Errorf("ListContexts() = %v, %q, want contexts of both files", contexts, current)
+	}
+
+	// Switching picks the cluster and user of the second file
+	if err := client.SwitchContext("work"); err != nil {
+		t.Fatalf("SwitchContext() error = %v", err)
+	}
+	if client.config.Host != "https://work.example.com:6443" || client.config.BearerToken != "work-token" {
+		t.Errorf("after SwitchContext(work): host %q, token %q", client.config.Host, client.config.BearerToken)
+	}
+}
+
+func TestNewClient_SkipsMissingKubeconfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	missing := filepath.Join(tmpDir, "missing")
+	work := writeKubeconfig(t, tmpDir, "work", "work", "https://work.example.com:6443", "work")
+
+	oldKubeconfig := os.Getenv("KUBECONFIG")
+	os.Setenv("KUBECONFIG", missing+string(filepath.ListSeparator)+work)
+	defer os.Setenv("KUBECONFIG", oldKubeconfig)
+
+	client, err := NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() with a missing file in KUBECONFIG error = %v", err)
+	}
+	if client.Context() != "work" {
+		t.Errorf("Context() = %q, want work", client.Context())
+	}
+	warnings := client.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], missing) {
+		t.Errorf("Warnings() = %v, want one about %s", warnings, missing)
+	}
+}
+
+func TestNewClientFor_ExplicitKubeconfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	explicit := writeKubeconfig(t, tmpDir, "explicit", "explicit", "https://explicit.example.com:6443", "explicit")
+	env := writeKubeconfig(t, tmpDir, "env", "env", "https://env.example.com:6443", "env")
+
+	oldKubeconfig := os.Getenv("KUBECONFIG")
+	os.Setenv("KUBECONFIG", env)
+	defer os.Setenv("KUBECONFIG", oldKubeconfig)
+
+	// The flag wins over KUBECONFIG, whose contexts are not merged in
+	client, err := NewClientFor(explicit, "")
+	if err != nil {
+		t.Fatalf("NewClientFor() error = %v", err)
+	}
+	if client.Context() != "explicit" || client.config.Host != "https://explicit.example.com:6443" {
+		t.Errorf("NewClientFor() = %s on %s, want the explicit kubeconfig", client.Context(), client.config.Host)
+	}
+	contexts, _, err := client.ListContexts()
+	if err != nil || len(contexts) != 1 || contexts[0] != "explicit" {
+		t.Errorf("ListContexts() = %v, %v, want only the explicit file's context", contexts, err)
+	}
+	if err := client.SwitchContext("env"); err == nil {
+		t.Error("SwitchContext() should not find contexts outside the explicit kubeconfig")
+	}
+
+	if _, err := NewClientFor(explicit, "env"); err == nil {
+		t.Error("NewClientFor() with a context from another file should fail")
 	}
 }
 
@@ -499,6 +584,70 @@ users:
 	}
 }
 
+func TestClient_SwitchContext(t *testing.T) {
+	kubeconfigContent := `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- cluster:
+    server: https://dev.example.com:6443
+  name: dev-cluster
+- cluster:
+    server: https://prod.example.com:6443
+  name: prod-cluster
+contexts:
+- context:
+    cluster: dev-cluster
+    user: admin
+  name: dev
+- context:
+    cluster: prod-cluster
+    user: admin
+  name: prod
+users:
+- name: admin
+`
+
+	tmpDir := t.TempDir()
+	kubeconfigPath := filepath.Join(tmpDir, "config")
+	if err := os.WriteFile(kubeconfigPath, []byte(kubeconfigContent), 0600); err != nil {
+		t.Fatalf("Failed to write temp kubeconfig: %v", err)
+	}
+	oldKubeconfig := os.Getenv("KUBECONFIG")
+	os.Setenv("KUBECONFIG", kubeconfigPath)
+	defer os.Setenv("KUBECONFIG", oldKubeconfig)
+
+	client, err := NewClientForContext("dev")
+	if err != nil {
+		t.Fatalf("NewClientForContext() error = %v", err)
+	}
+	client.SetNamespace("payments")
+	devClientset := client.Clientset()
+
+	if err := client.SwitchContext("prod"); err != nil {
+		t.Fatalf("SwitchContext() error = %v", err)
+	}
+	if client.Context() != "prod" {
+		t.Errorf("Context() = %q, want prod", client.Context())
+	}
+	if client.Namespace() != "default" {
+		t.Errorf("Namespace() = %q, want default after switching", client.Namespace())
+	}
+	if client.Clientset() == devClientset {
+		t.Error("SwitchContext() should rebuild the clientset")
+	}
+	if client.config.Host != "https://prod.example.com:6443" {
+		t.Errorf("config.Host = %q, want the prod cluster", client.config.Host)
+	}
+
+	if err := client.SwitchContext("missing"); err == nil {
+		t.Error("SwitchContext() with an unknown context should fail")
+	}
+	if client.Context() != "prod" {
+		t.Errorf("Context() = %q, want prod kept after a failed switch", client.Context())
+	}
+}
+
 // ============================================
 // Dynamic Client Tests (for Rollouts/Istio)
 // ============================================
@@ -1008,6 +1157,45 @@ func TestGetAllContainerLogs_MultipleContainers(t *testing.T) {
 	t.Logf("Got %d log lines from all containers", len(logs))
 }
 
+func TestGetAllContainerLogs_InitContainers(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "stuck-pod", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "migrate"}},
+			Containers:     []corev1.Container{{Name: "app"}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{Name: "migrate", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+			},
+		},
+	}
+	clientset := fake.NewSimpleClientset(pod)
+	ctx := context.Background()
+
+	containers := func() map[string]bool {
+		logs, err := GetAllContainerLogs(ctx, clientset, "default", "stuck-pod", 100)
+		if err != nil {
+			t.Fatalf("GetAllContainerLogs() error = %v", err)
+		}
+		seen := make(map[string]bool)
+		for _, l := range logs {
+			seen[l.Container] = true
+		}
+		return seen
+	}
+	if seen := containers(); !seen["migrate"] {
+		t.Errorf("containers = %v, want the init container while the pod is stuck in init", seen)
+	}
+
+	pod.Status = corev1.PodStatus{Phase: corev1.PodRunning}
+	clientset.CoreV1().Pods("default").UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+	if seen := containers(); seen["migrate"] || !seen["app"] {
+		t.Errorf("containers = %v, want only app once the pod runs", seen)
+	}
+}
+
 func TestGetAllContainerLogs_PodNotFound(t *testing.T) {
 	clientset := fake.NewSimpleClientset()
 	ctx := context.Background()
@@ -1438,7 +1626,8 @@ func TestGetIstioResources_EmptyNamespace(t *testing.T) {
 	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
 		scheme,
 		map[schema.GroupVersionResource]string{
-			vsGVR: "VirtualServiceList",
+			vsGVR:              "VirtualServiceList",
+			destinationRuleGVR: "DestinationRuleList",
 		},
 	)
 
@@ -1446,7 +1635,7 @@ func TestGetIstioResources_EmptyNamespace(t *testing.T) {
 	services := []ServiceInfo{{Name: "test-svc"}}
 
 	// No VirtualServices in the namespace
-	vs, gw := getIstioResources(ctx, dynamicClient, "empty-ns", services)
+	vs, gw, _ := getIstioResources(ctx, dynamicClient, "empty-ns", services)
 	if len(vs) != 0 || len(gw) != 0 {
 		t.Error("Expected empty results for empty namespace")
 	}
@@ -1496,8 +1685,9 @@ func TestGetIstioResources_WithVirtualService(t *testing.T) {
 	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
 		scheme,
 		map[schema.GroupVersionResource]string{
-			vsGVR: "VirtualServiceList",
-			gwGVR: "GatewayList",
+			vsGVR:              "VirtualServiceList",
+			destinationRuleGVR: "DestinationRuleList",
+			gwGVR:              "GatewayList",
 		},
 	)
 
@@ -1508,7 +1698,7 @@ func TestGetIstioResources_WithVirtualService(t *testing.T) {
 	}
 
 	services := []ServiceInfo{{Name: "test-svc"}}
-	vsInfos, _ := getIstioResources(ctx, dynamicClient, "default", services)
+	vsInfos, _, _ := getIstioResources(ctx, dynamicClient, "default", services)
 
 	if len(vsInfos) != 1 {
 		t.Errorf("Expected 1 VirtualService, got %d", len(vsInfos))
@@ -2100,9 +2290,10 @@ func TestGetRelatedResources_WithRolloutOwner(t *testing.T) {
 	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
 		scheme,
 		map[schema.GroupVersionResource]string{
-			rolloutGVR: "RolloutList",
-			vsGVR:      "VirtualServiceList",
-			gwGVR:      "GatewayList",
+			rolloutGVR:         "RolloutList",
+			vsGVR:              "VirtualServiceList",
+			destinationRuleGVR: "DestinationRuleList",
+			gwGVR:              "GatewayList",
 		},
 	)
 
@@ -2301,8 +2492,9 @@ func TestGetIstioResources_WithGateway(t *testing.T) {
 	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
 		scheme,
 		map[schema.GroupVersionResource]string{
-			vsGVR: "VirtualServiceList",
-			gwGVR: "GatewayList",
+			vsGVR:              "VirtualServiceList",
+			destinationRuleGVR: "DestinationRuleList",
+			gwGVR:              "GatewayList",
 		},
 	)
 
@@ -2317,7 +2509,7 @@ func TestGetIstioResources_WithGateway(t *testing.T) {
 	}
 
 	services := []ServiceInfo{{Name: "web-svc"}}
-	vsInfos, gwInfos := getIstioResources(ctx, dynamicClient, "default", services)
+	vsInfos, gwInfos, _ := getIstioResources(ctx, dynamicClient, "default", services)
 
 	if len(vsInfos) != 1 {
 		t.Errorf("Expected 1 VirtualService, got %d", len(vsInfos))
@@ -2411,8 +2603,9 @@ func TestGetIstioResources_WithNamespacedGateway(t *testing.T) {
 	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
 		scheme,
 		map[schema.GroupVersionResource]string{
-			vsGVR: "VirtualServiceList",
-			gwGVR: "GatewayList",
+			vsGVR:              "VirtualServiceList",
+			destinationRuleGVR: "DestinationRuleList",
+			gwGVR:              "GatewayList",
 		},
 	)
 
@@ -2421,7 +2614,7 @@ func TestGetIstioResources_WithNamespacedGateway(t *testing.T) {
 	_, _ = dynamicClient.Resource(gwGVR).Namespace("istio-system").Create(ctx, gateway, metav1.CreateOptions{})
 
 	services := []ServiceInfo{{Name: "api-svc"}}
-	vsInfos, gwInfos := getIstioResources(ctx, dynamicClient, "production", services)
+	vsInfos, gwInfos, _ := getIstioResources(ctx, dynamicClient, "production", services)
 
 	if len(vsInfos) != 1 {
 		t.Errorf("Expected 1 VirtualService, got %d", len(vsInfos))
@@ -2481,8 +2674,9 @@ func TestGetIstioResources_NoMatchingService(t *testing.T) {
 	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
 		scheme,
 		map[schema.GroupVersionResource]string{
-			vsGVR: "VirtualServiceList",
-			gwGVR: "GatewayList",
+			vsGVR:              "VirtualServiceList",
+			destinationRuleGVR: "DestinationRuleList",
+			gwGVR:              "GatewayList",
 		},
 	)
 
@@ -2490,7 +2684,7 @@ func TestGetIstioResources_NoMatchingService(t *testing.T) {
 	_, _ = dynamicClient.Resource(vsGVR).Namespace("default").Create(ctx, vs, metav1.CreateOptions{})
 
 	services := []ServiceInfo{{Name: "web-svc"}} // Different service
-	vsInfos, _ := getIstioResources(ctx, dynamicClient, "default", services)
+	vsInfos, _, _ := getIstioResources(ctx, dynamicClient, "default", services)
 
 	// VS should not be included since it doesn't route to web-svc
 	if len(vsInfos) != 0 {
@@ -3257,7 +3451,12 @@ func TestGetNode_WithDetails(t *testing.T) {
 			CreationTimestamp: metav1.Now(),
 		},
 		Spec: corev1.NodeSpec{
-			ProviderID: "aws:///us-west-2a/i-1234567890abcdef0",
+			ProviderID:    "aws:///us-west-2a/i-1234567890abcdef0",
+			Unschedulable: true,
+			Taints: []corev1.Taint{
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/disk-pressure", Effect: corev1.TaintEffectNoSchedule},
+			},
 		},
 		Status: corev1.NodeStatus{
 			NodeInfo: corev1.NodeSystemInfo{
@@ -3267,6 +3466,9 @@ func TestGetNode_WithDetails(t *testing.T) {
 			},
 			Conditions: []corev1.NodeCondition{
 				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue},
+				{Type: corev1.NodePIDPressure, Status: corev1.ConditionFalse},
 			},
 			Addresses: []corev1.NodeAddress{
 				{Type: corev1.NodeInternalIP, Address: "10.0.0.2"},
@@ -3280,7 +3482,29 @@ func TestGetNode_WithDetails(t *testing.T) {
 		},
 	}
 
-	clientset := fake.NewSimpleClientset(node)
+	podOnNode := func(name string, phase corev1.PodPhase, cpu, mem string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PodSpec{
+				NodeName: "node-with-details",
+				Containers: []corev1.Container{{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(mem),
+					}},
+				}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(
+		node,
+		podOnNode("web", corev1.PodRunning, "500m", "512Mi"),
+		podOnNode("api", corev1.PodPending, "250m", "512Mi"),
+		podOnNode("job", corev1.PodSucceeded, "2", "4Gi"), // Terminated pods free their requests
+	)
 	ctx := context.Background()
 
 	result, err := GetNode(ctx, clientset, "node-with-details")
@@ -3295,6 +3519,29 @@ func TestGetNode_WithDetails(t *testing.T) {
 	if result.Name != "node-with-details" {
 		t.Errorf("Name = %q, want 'node-with-details'", result.Name)
 	}
+	if result.PodCount != 3 {
+		t.Errorf("PodCount = %d, want 3", result.PodCount)
+	}
+	if result.AllocatableCPU != "4" || result.AllocatableMemory != "8Gi" {
+		t.Errorf("Allocatable = %s/%s, want 4/8Gi", result.AllocatableCPU, result.AllocatableMemory)
+	}
+	if result.RequestedCPU != "750m" || result.RequestedMemory != "1.0Gi" {
+		t.Errorf("Requested = %s/%s, want 750m/1.0Gi", result.RequestedCPU, result.RequestedMemory)
+	}
+	if !result.Unschedulable {
+		t.Error("Unschedulable should be true for a cordoned node")
+	}
+	if len(result.Taints) != 2 || result.Taints[0].String() != "dedicated=gpu:NoSchedule" ||
+		result.Taints[1].String() != "node.kubernetes.io/disk-pressure:NoSchedule" {
+		t.Errorf("Taints = %v", result.Taints)
+	}
+	if result.MemoryPressure || !result.DiskPressure || result.PIDPressure {
+		t.Errorf("pressure = memory %v, disk %v, pid %v; want only disk",
+			result.MemoryPressure, result.DiskPressure, result.PIDPressure)
+	}
+	if p := result.Pressures(); len(p) != 1 || p[0] != "DiskPressure" {
+		t.Errorf("Pressures() = %v, want [DiskPressure]", p)
+	}
 }
 
 // ============================================
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// errModified is the conflict detail when an object changed after it was read.
+var errModified = errors.New("the object has been modified since it was read")
+
+// DeleteConfigMap deletes a ConfigMap.
+func DeleteConfigMap(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+	if err := clientset.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to delete configmap: %w", err)
+	}
+	return nil
+}
+
+// DeleteSecret deletes a Secret.
+func DeleteSecret(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+	if err := clientset.CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	return nil
+}
+
+// UpdateConfigMapData sets one key of a ConfigMap. resourceVersion is the
+// version the value was read at (ConfigMapData.ResourceVersion): if the
+// ConfigMap changed since, a conflict error is returned (see IsConflict)
+// instead of overwriting the other change. An empty resourceVersion
+// updates whatever version is current.
+func UpdateConfigMapData(ctx context.Context, clientset kubernetes.Interface, namespace, name, key, value, resourceVersion string) error {
+	cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get configmap: %w", err)
+	}
+	if resourceVersion != "" && cm.ResourceVersion != resourceVersion {
+		return fmt.Errorf("failed to update configmap: %w",
+			apierrors.NewConflict(corev1.Resource("configmaps"), name, errModified))
+	}
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[key] = value
+	if _, err := clientset.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update configmap: %w", err)
+	}
+	return nil
+}
+
+// UpdateSecretData sets one key of a Secret, like UpdateConfigMapData.
+// value is the decoded value, as in SecretData; it is base64-encoded on
+// its way to the API server like every Secret value.
+func UpdateSecretData(ctx context.Context, clientset kubernetes.Interface, namespace, name, key, value, resourceVersion string) error {
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get secret: %w", err)
+	}
+	if resourceVersion != "" && secret.ResourceVersion != resourceVersion {
+		return fmt.Errorf("failed to update secret: %w",
+			apierrors.NewConflict(corev1.Resource("secrets"), name, errModified))
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[key] = []byte(value)
+	if _, err := clientset.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	return nil
+}
+
+// IsConflict reports whether err is a conflict from a concurrent update,
+// after which the update can be retried against the latest version.
+func IsConflict(err error) bool {
+	return apierrors.IsConflict(err)
+}
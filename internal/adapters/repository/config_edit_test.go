@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func configEditFixtures() *fake.Clientset {
+	return fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-config", Namespace: "default", ResourceVersion: "7"},
+			Data:       map[string]string{"LOG_LEVEL": "info", "app.yaml": "port: 8080\n"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-secret", Namespace: "default", ResourceVersion: "3"},
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+		},
+	)
+}
+
+func TestUpdateConfigMapData(t *testing.T) {
+	ctx := context.Background()
+	clientset := configEditFixtures()
+
+	if err := UpdateConfigMapData(ctx, clientset, "default", "web-config", "LOG_LEVEL", "debug", "7"); err != nil {
+		t.Fatalf("UpdateConfigMapData() error = %v", err)
+	}
+	cm, _ := clientset.CoreV1().ConfigMaps("default").Get(ctx, "web-config", metav1.GetOptions{})
+	if cm.Data["LOG_LEVEL"] != "debug" || cm.Data["app.yaml"] != "port: 8080\n" {
+		t.Errorf("Data = %v, want only LOG_LEVEL changed", cm.Data)
+	}
+}
+
+func TestUpdateConfigMapData_Conflict(t *testing.T) {
+	ctx := context.Background()
+	clientset := configEditFixtures()
+
+	err := UpdateConfigMapData(ctx, clientset, "default", "web-config", "LOG_LEVEL", "debug", "6")
+	if !IsConflict(err) {
+		t.Fatalf("UpdateConfigMapData() error = %v, want a conflict", err)
+	}
+	cm, _ := clientset.CoreV1().ConfigMaps("default").Get(ctx, "web-config", metav1.GetOptions{})
+	if cm.Data["LOG_LEVEL"] != "info" {
+		t.Error("a conflicting update should not be applied")
+	}
+
+	// Retrying without a version overwrites the latest one
+	if err := UpdateConfigMapData(ctx, clientset, "default", "web-config", "LOG_LEVEL", "debug", ""); err != nil {
+		t.Fatalf("UpdateConfigMapData() retry error = %v", err)
+	}
+}
+
+func TestUpdateConfigMapData_NotFound(t *testing.T) {
+	err := UpdateConfigMapData(context.Background(), fake.NewSimpleClientset(), "default", "missing", "k", "v", "")
+	if err == nil || IsConflict(err) {
+		t.Errorf("UpdateConfigMapData() error = %v, want not found", err)
+	}
+}
+
+func TestUpdateSecretData(t *testing.T) {
+	ctx := context.Background()
+	clientset := configEditFixtures()
+
+	if err := UpdateSecretData(ctx, clientset, "default", "web-secret", "password", "s3cr3t\n", "3"); err != nil {
+		t.Fatalf("UpdateSecretData() error = %v", err)
+	}
+	// The decoded value is stored as is; the API encodes it on the wire
+	secret, err := GetSecret(ctx, clientset, "default", "web-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.Data["password"] != "s3cr3t\n" {
+		t.Errorf("password = %q, want %q", secret.Data["password"], "s3cr3t\n")
+	}
+
+	if err := UpdateSecretData(ctx, clientset, "default", "web-secret", "password", "x", "2"); !IsConflict(err) {
+		t.Errorf("UpdateSecretData() error = %v, want a conflict", err)
+	}
+}
+
+func TestDeleteConfigMapAndSecret(t *testing.T) {
+	ctx := context.Background()
+	clientset := configEditFixtures()
+
+	if err := DeleteConfigMap(ctx, clientset, "default", "web-config"); err != nil {
+		t.Fatalf("DeleteConfigMap() error = %v", err)
+	}
+	if _, err := GetConfigMap(ctx, clientset, "default", "web-config"); err == nil {
+		t.Error("configmap should be deleted")
+	}
+	if err := DeleteSecret(ctx, clientset, "default", "web-secret"); err != nil {
+		t.Fatalf("DeleteSecret() error = %v", err)
+	}
+	if err := DeleteSecret(ctx, clientset, "default", "web-secret"); err == nil {
+		t.Error("deleting a missing secret should fail")
+	}
+}
+
+func TestGetConfigMap_ResourceVersion(t *testing.T) {
+	cm, err := GetConfigMap(context.Background(), configEditFixtures(), "default", "web-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm.ResourceVersion != "7" {
+		t.Errorf("ResourceVersion = %q, want 7", cm.ResourceVersion)
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FieldManagerUpdate is the last write of one field manager to a ConfigMap
+// or Secret, read from its managedFields: the closest thing to an audit log
+// an object carries.
+type FieldManagerUpdate struct {
+	Manager   string    // Field manager, e.g. "kubectl-edit" or "helm"
+	Operation string    // "Apply" or "Update"
+	Time      time.Time // When the manager last changed its fields
+	Keys      []string  // Data keys the manager owns, sorted
+}
+
+// fieldManagerUpdates reads the managedFields of a ConfigMap or Secret,
+// most recent first. Entries without a time, or owning only metadata such
+// as labels, are skipped.
+func fieldManagerUpdates(entries []metav1.ManagedFieldsEntry) []FieldManagerUpdate {
+	var updates []FieldManagerUpdate
+	for _, e := range entries {
+		if e.Time == nil || e.Subresource != "" {
+			continue
+		}
+		keys := managedDataKeys(e.FieldsV1)
+		if len(keys) == 0 {
+			continue
+		}
+		updates = append(updates, FieldManagerUpdate{
+			Manager:   e.Manager,
+			Operation: string(e.Operation),
+			Time:      e.Time.Time,
+			Keys:      keys,
+		})
+	}
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].Time.After(updates[j].Time)
+	})
+	return updates
+}
+
+// managedDataKeys returns the data keys of a FieldsV1 set, which lists
+// them as {"f:data": {"f:<key>": {}}}; binaryData and stringData keys
+// count too.
+func managedDataKeys(fields *metav1.FieldsV1) []string {
+	if fields == nil {
+		return nil
+	}
+	var set map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(fields.Raw, &set); err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var keys []string
+	for _, field := range []string{"f:data", "f:binaryData", "f:stringData"} {
+		for key := range set[field] {
+			name, ok := strings.CutPrefix(key, "f:")
+			if ok && !seen[name] {
+				seen[name] = true
+				keys = append(keys, name)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// KeyChange is how one key of a ConfigMap or Secret differs between two
+// versions of its data.
+type KeyChange struct {
+	Key    string
+	Change string // "added", "removed" or "changed"
+	Old    string // Previous value; "" when added, or for Secrets
+	New    string // Current value; "" when removed, or for Secrets
+}
+
+// Kinds of KeyChange.
+const (
+	KeyAdded   = "added"
+	KeyRemoved = "removed"
+	KeyChanged = "changed"
+)
+
+// DiffConfigData compares two versions of the data of a ConfigMap or
+// Secret, by key. For a Secret only the kind of change is reported, never
+// the values. The changes are sorted by key.
+func DiffConfigData(old, current map[string]string, secret bool) []KeyChange {
+	var changes []KeyChange
+	for key, value := range current {
+		prev, ok := old[key]
+		switch {
+		case !ok:
+			changes = append(changes, KeyChange{Key: key, Change: KeyAdded, New: value})
+		case prev != value:
+			changes = append(changes, KeyChange{Key: key, Change: KeyChanged, Old: prev, New: value})
+		}
+	}
+	for key, value := range old {
+		if _, ok := current[key]; !ok {
+			changes = append(changes, KeyChange{Key: key, Change: KeyRemoved, Old: value})
+		}
+	}
+	if secret {
+		for i := range changes {
+			changes[i].Old, changes[i].New = "", ""
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDiffConfigData(t *testing.T) {
+	old := map[string]string{"host": "db-1", "port": "5432", "debug": "true"}
+	current := map[string]string{"host": "db-2", "port": "5432", "pool": "10"}
+
+	got := DiffConfigData(old, current, false)
+	want := []KeyChange{
+		{Key: "debug", Change: KeyRemoved, Old: "true"},
+		{Key: "host", Change: KeyChanged, Old: "db-1", New: "db-2"},
+		{Key: "pool", Change: KeyAdded, New: "10"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffConfigData() = %+v, want %+v", got, want)
+	}
+
+	for _, c := range DiffConfigData(old, current, true) {
+		if c.Old != "" || c.New != "" {
+			t.Errorf("secret change %+v should not carry values", c)
+		}
+	}
+	if got := DiffConfigData(old, old, false); len(got) != 0 {
+		t.Errorf("DiffConfigData() of equal data = %+v, want none", got)
+	}
+}
+
+func TestGetConfigMap_Updates(t *testing.T) {
+	edited := metav1.NewTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	applied := metav1.NewTime(edited.Add(-time.Hour))
+	clientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", ManagedFields: []metav1.ManagedFieldsEntry{
+			{Manager: "helm", Operation: metav1.ManagedFieldsOperationApply, Time: &applied,
+				FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{".":{},"f:host":{},"f:port":{}},"f:metadata":{"f:labels":{}}}`)}},
+			{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &edited,
+				FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:host":{}}}`)}},
+			{Manager: "kubectl-label", Operation: metav1.ManagedFieldsOperationUpdate, Time: &edited,
+				FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:team":{}}}}`)}},
+		}},
+		Data: map[string]string{"host": "db-2", "port": "5432"},
+	})
+
+	cm, err := GetConfigMap(context.Background(), clientset, "default", "app")
+	if err != nil {
+		t.Fatalf("GetConfigMap() error = %v", err)
+	}
+	want := []FieldManagerUpdate{
+		{Manager: "kubectl-edit", Operation: "Update", Time: edited.Time, Keys: []string{"host"}},
+		{Manager: "helm", Operation: "Apply", Time: applied.Time, Keys: []string{"host", "port"}},
+	}
+	if !reflect.DeepEqual(cm.Updates, want) {
+		t.Errorf("Updates = %+v, want %+v", cm.Updates, want)
+	}
+}
@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ConfigProjection is where the keys of a ConfigMap or Secret land in the
+// containers of a pod: the files of the volumes mounting it and the
+// environment variables taken from it.
+type ConfigProjection struct {
+	Kind        string // "ConfigMap" or "Secret"
+	Name        string
+	Namespace   string
+	Found       bool // The object exists; without it every reference is missing
+	Data        map[string]string
+	Projections []KeyProjection // In container order: volumes, then envFrom, then env
+	Unused      []string        // Keys of the object no container gets, sorted
+}
+
+// KeyProjection is one key of a ConfigMap or Secret as one container sees
+// it.
+type KeyProjection struct {
+	Key       string
+	Container string
+	Env       bool   // Target is an environment variable, else a file
+	Target    string // Variable name, or absolute file path
+	Source    string // How it gets there, e.g. "volume config", "envFrom", "env"
+	Optional  bool   // The reference is optional, so a missing key is left out rather than failing the pod
+	Missing   bool   // The key or the object doesn't exist
+	Skipped   bool   // Left out by the kubelet: not a valid variable name, or not in an optional object
+	Note      string // Why it is missing or skipped, or a caveat such as a subPath mount not being updated
+}
+
+// envVarName matches the names envFrom accepts; other keys are skipped
+// with an InvalidEnvironmentVariableNames event.
+var envVarName = regexp.MustCompile(`^[-._a-zA-Z][-._a-zA-Z0-9]*$`)
+
+// GetConfigProjection fetches a pod and the ConfigMap or Secret name, and
+// resolves where its keys land in the pod's containers. A ConfigMap or
+// Secret that doesn't exist is not an error: its references are reported
+// missing.
+func GetConfigProjection(ctx context.Context, clientset kubernetes.Interface, namespace, podName, kind, name string) (*ConfigProjection, error) {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	var data map[string]string
+	switch kind {
+	case "ConfigMap":
+		cm, err := GetConfigMap(ctx, clientset, namespace, name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get configmap: %w", err)
+		}
+		if err == nil {
+			data = cm.Data
+		}
+	case "Secret":
+		secret, err := GetSecret(ctx, clientset, namespace, name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get secret: %w", err)
+		}
+		if err == nil {
+			data = secret.Data
+		}
+	default:
+		return nil, fmt.Errorf("unsupported kind %q", kind)
+	}
+
+	projection := ResolveConfigProjection(pod.Spec, kind, name, data)
+	projection.Namespace = namespace
+	return projection, nil
+}
+
+// ResolveConfigProjection resolves where the keys of the ConfigMap or
+// Secret kind/name land in the containers of spec, init containers
+// included, given its data; a nil data means the object doesn't exist.
+//
+// Volumes, projected ones included, put each key in a file under the mount
+// path, or only the keys of their items at the items' paths. A subPath
+// mount exposes the single file at that path. envFrom sets a variable per
+// key, named with the prefix, and env sets one for a single key.
+// References to keys or objects that don't exist are reported missing,
+// unless optional: then they are skipped, as the kubelet does.
+func ResolveConfigProjection(spec corev1.PodSpec, kind, name string, data map[string]string) *ConfigProjection {
+	p := &ConfigProjection{Kind: kind, Name: name, Found: data != nil, Data: data}
+	used := make(map[string]bool)
+
+	// The files each volume referencing the object holds, by volume name
+	volumes := make(map[string][]volumeFile)
+	for _, v := range spec.Volumes {
+		if files, ok := configVolumeFiles(v, kind, name, data); ok {
+			volumes[v.Name] = append(volumes[v.Name], files...)
+		}
+	}
+
+	containers := append(append([]corev1.Container(nil), spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		for _, m := range c.VolumeMounts {
+			files, ok := volumes[m.Name]
+			if !ok {
+				continue
+			}
+			for _, f := range files {
+				target := path.Join(m.MountPath, f.path)
+				note := f.note
+				if m.SubPath != "" {
+					// Only the file at subPath is mounted, at the mount path,
+					// and it is not updated when the object changes
+					if f.path != m.SubPath {
+						continue
+					}
+					target = m.MountPath
+					if note == "" {
+						note = "subPath mount: not updated when the " + kind + " changes"
+					}
+				}
+				p.add(used, KeyProjection{
+					Key: f.key, Container: c.Name, Target: target, Source: "volume " + m.Name,
+					Optional: f.optional, Missing: f.missing, Skipped: f.missing && f.optional, Note: note,
+				})
+			}
+		}
+
+		for _, from := range c.EnvFrom {
+			ref, optional := envFromRef(from, kind, name)
+			if !ref {
+				continue
+			}
+			if data == nil {
+				p.add(used, KeyProjection{
+					Container: c.Name, Env: true, Target: from.Prefix + "*", Source: "envFrom",
+					Optional: optional, Missing: true, Skipped: optional, Note: missingNote(kind, optional),
+				})
+				continue
+			}
+			for _, key := range sortedKeys(data) {
+				proj := KeyProjection{Key: key, Container: c.Name, Env: true, Target: from.Prefix + key, Source: "envFrom", Optional: optional}
+				if !envVarName.MatchString(proj.Target) {
+					proj.Skipped, proj.Note = true, "not a valid environment variable name"
+				}
+				p.add(used, proj)
+			}
+		}
+
+		for _, env := range c.Env {
+			key, optional, ok := envKeyRef(env, kind, name)
+			if !ok {
+				continue
+			}
+			proj := KeyProjection{Key: key, Container: c.Name, Env: true, Target: env.Name, Source: "env", Optional: optional}
+			if _, exists := data[key]; !exists {
+				proj.Missing, proj.Skipped = true, optional
+				proj.Note = missingKeyNote(kind, data == nil, optional)
+			}
+			p.add(used, proj)
+		}
+	}
+
+	if data != nil {
+		for _, key := range sortedKeys(data) {
+			if !used[key] {
+				p.Unused = append(p.Unused, key)
+			}
+		}
+	}
+	return p
+}
+
+// add records a projection, and the key as used when the container gets
+// it.
+func (p *ConfigProjection) add(used map[string]bool, proj KeyProjection) {
+	if proj.Key != "" && !proj.Missing && !proj.Skipped {
+		used[proj.Key] = true
+	}
+	p.Projections = append(p.Projections, proj)
+}
+
+// Value returns the value of key, and whether the object has it.
+func (p *ConfigProjection) Value(key string) (string, bool) {
+	v, ok := p.Data[key]
+	return v, ok
+}
+
+// Broken reports whether a required key or object is missing, which keeps
+// the containers from starting.
+func (p *ConfigProjection) Broken() bool {
+	for _, proj := range p.Projections {
+		if proj.Missing && !proj.Optional {
+			return true
+		}
+	}
+	return false
+}
+
+// volumeFile is a key of a ConfigMap or Secret as a file of a volume.
+type volumeFile struct {
+	key      string
+	path     string // Relative to the volume root
+	optional bool
+	missing  bool
+	note     string
+}
+
+// configVolumeFiles returns the files a volume gets from the object
+// kind/name, and whether it references it at all.
+func configVolumeFiles(v corev1.Volume, kind, name string, data map[string]string) ([]volumeFile, bool) {
+	type source struct {
+		items    []corev1.KeyToPath
+		optional *bool
+	}
+	var sources []source
+	switch {
+	case kind == "ConfigMap" && v.ConfigMap != nil && v.ConfigMap.Name == name:
+		sources = append(sources, source{v.ConfigMap.Items, v.ConfigMap.Optional})
+	case kind == "Secret" && v.Secret != nil && v.Secret.SecretName == name:
+		sources = append(sources, source{v.Secret.Items, v.Secret.Optional})
+	case v.Projected != nil:
+		for _, s := range v.Projected.Sources {
+			if kind == "ConfigMap" && s.ConfigMap != nil && s.ConfigMap.Name == name {
+				sources = append(sources, source{s.ConfigMap.Items, s.ConfigMap.Optional})
+			}
+			if kind == "Secret" && s.Secret != nil && s.Secret.Name == name {
+				sources = append(sources, source{s.Secret.Items, s.Secret.Optional})
+			}
+		}
+	}
+	if len(sources) == 0 {
+		return nil, false
+	}
+
+	var files []volumeFile
+	for _, s := range sources {
+		optional := s.optional != nil && *s.optional
+		switch {
+		case len(s.items) > 0:
+			for _, item := range s.items {
+				f := volumeFile{key: item.Key, path: item.Path, optional: optional}
+				if _, ok := data[item.Key]; !ok {
+					f.missing, f.note = true, missingKeyNote(kind, data == nil, optional)
+				}
+				files = append(files, f)
+			}
+		case data == nil:
+			files = append(files, volumeFile{path: "", optional: optional, missing: true, note: missingNote(kind, optional)})
+		default:
+			for _, key := range sortedKeys(data) {
+				files = append(files, volumeFile{key: key, path: key, optional: optional})
+			}
+		}
+	}
+	return files, true
+}
+
+// envFromRef reports whether an envFrom source takes the object kind/name,
+// and whether it is optional.
+func envFromRef(from corev1.EnvFromSource, kind, name string) (bool, bool) {
+	switch {
+	case kind == "ConfigMap" && from.ConfigMapRef != nil && from.ConfigMapRef.Name == name:
+		return true, from.ConfigMapRef.Optional != nil && *from.ConfigMapRef.Optional
+	case kind == "Secret" && from.SecretRef != nil && from.SecretRef.Name == name:
+		return true, from.SecretRef.Optional != nil && *from.SecretRef.Optional
+	}
+	return false, false
+}
+
+// envKeyRef returns the key of the object kind/name an env var takes its
+// value from, whether it is optional, and whether it takes one at all.
+func envKeyRef(env corev1.EnvVar, kind, name string) (string, bool, bool) {
+	if env.ValueFrom == nil {
+		return "", false, false
+	}
+	switch ref := env.ValueFrom; {
+	case kind == "ConfigMap" && ref.ConfigMapKeyRef != nil && ref.ConfigMapKeyRef.Name == name:
+		return ref.ConfigMapKeyRef.Key, ref.ConfigMapKeyRef.Optional != nil && *ref.ConfigMapKeyRef.Optional, true
+	case kind == "Secret" && ref.SecretKeyRef != nil && ref.SecretKeyRef.Name == name:
+		return ref.SecretKeyRef.Key, ref.SecretKeyRef.Optional != nil && *ref.SecretKeyRef.Optional, true
+	}
+	return "", false, false
+}
+
+func missingNote(kind string, optional bool) string {
+	if optional {
+		return kind + " not found, skipped as optional"
+	}
+	return kind + " not found: the container can't start"
+}
+
+func missingKeyNote(kind string, objectMissing, optional bool) string {
+	if objectMissing {
+		return missingNote(kind, optional)
+	}
+	if optional {
+		return "key not found, skipped as optional"
+	}
+	return "key not found: the container can't start"
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// projectionsByTarget indexes projections by container and target.
+func projectionsByTarget(p *ConfigProjection) map[string]KeyProjection {
+	byTarget := make(map[string]KeyProjection)
+	for _, proj := range p.Projections {
+		byTarget[proj.Container+" "+proj.Target] = proj
+	}
+	return byTarget
+}
+
+func TestResolveConfigProjection_Volumes(t *testing.T) {
+	spec := corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "web"},
+			}}},
+			// Items remap keys to other paths, and leave the rest out
+			{Name: "nginx", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "web"},
+				Items: []corev1.KeyToPath{
+					{Key: "nginx.conf", Path: "conf.d/default.conf"},
+					{Key: "mime.types", Path: "mime.types"},
+				},
+			}}},
+			{Name: "other", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "unrelated"},
+			}}},
+		},
+		Containers: []corev1.Container{{
+			Name: "app",
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "config", MountPath: "/etc/web"},
+				{Name: "config", MountPath: "/app/settings.yaml", SubPath: "settings.yaml"},
+				{Name: "nginx", MountPath: "/etc/nginx"},
+				{Name: "other", MountPath: "/etc/other"},
+			},
+		}},
+	}
+	data := map[string]string{"settings.yaml": "debug: false", "nginx.conf": "server {}", "unused.txt": "x"}
+
+	p := ResolveConfigProjection(spec, "ConfigMap", "web", data)
+	byTarget := projectionsByTarget(p)
+
+	for target, key := range map[string]string{
+		"app /etc/web/settings.yaml":         "settings.yaml",
+		"app /etc/web/nginx.conf":            "nginx.conf",
+		"app /app/settings.yaml":             "settings.yaml",
+		"app /etc/nginx/conf.d/default.conf": "nginx.conf",
+	} {
+		if proj, ok := byTarget[target]; !ok || proj.Key != key || proj.Missing {
+			t.Errorf("%s = %+v, want key %s", target, proj, key)
+		}
+	}
+	if proj := byTarget["app /app/settings.yaml"]; proj.Note == "" {
+		t.Error("the subPath mount should note it is not updated")
+	}
+	if _, ok := byTarget["app /app/settings.yaml/nginx.conf"]; ok {
+		t.Error("a subPath mount should expose only its own file")
+	}
+	if proj := byTarget["app /etc/nginx/mime.types"]; !proj.Missing || proj.Skipped || !p.Broken() {
+		t.Errorf("mime.types = %+v, want a required key that is missing", proj)
+	}
+	if _, ok := byTarget["app /etc/other"]; ok {
+		t.Error("volumes of other ConfigMaps should be left out")
+	}
+	if len(p.Unused) != 0 {
+		t.Errorf("Unused = %v, want none: the volume mounts every key", p.Unused)
+	}
+}
+
+func TestResolveConfigProjection_Env(t *testing.T) {
+	spec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{
+			Name: "migrate",
+			Env: []corev1.EnvVar{{Name: "DATABASE_URL", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "url"},
+			}}},
+		}},
+		Containers: []corev1.Container{{
+			Name: "app",
+			EnvFrom: []corev1.EnvFromSource{{
+				Prefix:    "DB_",
+				SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}},
+			}},
+			Env: []corev1.EnvVar{
+				{Name: "REPLICA_URL", ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "replica-url", Optional: boolPtr(true)},
+				}},
+				{Name: "PLAIN", Value: "x"},
+			},
+		}},
+	}
+	data := map[string]string{"url": "postgres://db", "password": "s3cret", "tls.crt": "...", "9lives": "x"}
+
+	p := ResolveConfigProjection(spec, "Secret", "db", data)
+	byTarget := projectionsByTarget(p)
+
+	if proj := byTarget["migrate DATABASE_URL"]; proj.Key != "url" || !proj.Env || proj.Source != "env" {
+		t.Errorf("DATABASE_URL = %+v, want the url key", proj)
+	}
+	if proj := byTarget["app DB_password"]; proj.Key != "password" || proj.Source != "envFrom" || proj.Skipped {
+		t.Errorf("DB_password = %+v, want the password key with its prefix", proj)
+	}
+	if proj := byTarget["app DB_tls.crt"]; proj.Skipped {
+		t.Errorf("DB_tls.crt = %+v, dots are valid in variable names", proj)
+	}
+	if proj := byTarget["app REPLICA_URL"]; !proj.Missing || !proj.Skipped || !proj.Optional {
+		t.Errorf("REPLICA_URL = %+v, want an optional missing key skipped", proj)
+	}
+	if p.Broken() {
+		t.Error("an optional missing key should not break the pod")
+	}
+	if len(p.Unused) != 0 {
+		t.Errorf("Unused = %v, envFrom takes every key", p.Unused)
+	}
+}
+
+func TestResolveConfigProjection_MissingObject(t *testing.T) {
+	spec := corev1.PodSpec{
+		Volumes: []corev1.Volume{{Name: "certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+			SecretName: "tls", Optional: boolPtr(true),
+		}}}},
+		Containers: []corev1.Container{{
+			Name:         "app",
+			VolumeMounts: []corev1.VolumeMount{{Name: "certs", MountPath: "/certs"}},
+			EnvFrom:      []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "tls"}}}},
+		}},
+	}
+
+	p := ResolveConfigProjection(spec, "Secret", "tls", nil)
+	if p.Found || len(p.Projections) != 2 {
+		t.Fatalf("projections = %+v, want the volume and envFrom references", p.Projections)
+	}
+	if volume := p.Projections[0]; volume.Target != "/certs" || !volume.Missing || !volume.Skipped {
+		t.Errorf("volume = %+v, want an optional missing Secret skipped", volume)
+	}
+	if envFrom := p.Projections[1]; !envFrom.Missing || envFrom.Skipped || !p.Broken() {
+		t.Errorf("envFrom = %+v, want a required missing Secret", envFrom)
+	}
+}
+
+func TestResolveConfigProjection_Unused(t *testing.T) {
+	spec := corev1.PodSpec{Containers: []corev1.Container{{
+		Name: "app",
+		Env: []corev1.EnvVar{{Name: "LEVEL", ValueFrom: &corev1.EnvVarSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "web"}, Key: "level"},
+		}}},
+	}}}
+
+	p := ResolveConfigProjection(spec, "ConfigMap", "web", map[string]string{"level": "info", "region": "eu", "zone": "a"})
+	if len(p.Unused) != 2 || p.Unused[0] != "region" || p.Unused[1] != "zone" {
+		t.Errorf("Unused = %v, want region and zone", p.Unused)
+	}
+}
+
+func TestGetConfigProjection(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "app",
+			EnvFrom: []corev1.EnvFromSource{
+				{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "web"}}},
+				{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "gone"}}},
+			},
+		}}},
+	}
+	clientset := fake.NewSimpleClientset(pod,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}, Data: map[string]string{"LEVEL": "info"}},
+	)
+
+	p, err := GetConfigProjection(context.Background(), clientset, "shop", "web-1", "ConfigMap", "web")
+	if err != nil {
+		t.Fatalf("GetConfigProjection failed: %v", err)
+	}
+	if len(p.Projections) != 1 || p.Projections[0].Target != "LEVEL" || p.Namespace != "shop" {
+		t.Errorf("projection = %+v, want LEVEL from envFrom", p)
+	}
+
+	p, err = GetConfigProjection(context.Background(), clientset, "shop", "web-1", "Secret", "gone")
+	if err != nil {
+		t.Fatalf("a missing Secret should not be an error: %v", err)
+	}
+	if p.Found || !p.Broken() {
+		t.Errorf("projection = %+v, want the missing Secret reported", p)
+	}
+
+	if _, err := GetConfigProjection(context.Background(), clientset, "shop", "web-2", "ConfigMap", "web"); err == nil {
+		t.Error("GetConfigProjection should fail for a missing pod")
+	}
+}
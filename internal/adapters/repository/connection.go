@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+// ConnectionCheckTimeout bounds the version request of CheckConnection, so
+// that a dead cluster is reported in seconds rather than after the dial
+// timeout.
+const ConnectionCheckTimeout = 5 * time.Second
+
+// ConnectionProblem is why the API server could not be reached.
+type ConnectionProblem int
+
+const (
+	ConnectionUnknown ConnectionProblem = iota // Not one of the others
+	ConnectionDNS                              // The server's host name does not resolve
+	ConnectionTLS                              // The server's certificate is not trusted, or the handshake failed
+	ConnectionAuth                             // The credentials were rejected or could not be obtained
+	ConnectionTimeout                          // The server did not answer in time
+	ConnectionRefused                          // Nothing listens at the server's address
+)
+
+func (p ConnectionProblem) String() string {
+	switch p {
+	case ConnectionDNS:
+		return "DNS"
+	case ConnectionTLS:
+		return "TLS"
+	case ConnectionAuth:
+		return "authentication"
+	case ConnectionTimeout:
+		return "timeout"
+	case ConnectionRefused:
+		return "connection refused"
+	}
+	return "unknown"
+}
+
+// Hint is what to check for the problem.
+func (p ConnectionProblem) Hint() string {
+	switch p {
+	case ConnectionDNS:
+		return "The server's host name does not resolve. Check the server URL, your VPN, and your DNS settings."
+	case ConnectionTLS:
+		return "The server's certificate could not be verified. Check certificate-authority-data in the kubeconfig, or whether a proxy intercepts TLS."
+	case ConnectionAuth:
+		return "The cluster rejected the credentials, or they could not be obtained. Log in again (e.g. refresh your OIDC or cloud CLI session)."
+	case ConnectionTimeout:
+		return "The server did not answer in time. Check that the cluster is up and reachable from this network (VPN, firewall, proxy)."
+	case ConnectionRefused:
+		return "Nothing accepts connections at the server's address. Check that the cluster is running and the port is right."
+	}
+	return "Check that the cluster is up and that the kubeconfig context points at it."
+}
+
+// ClassifyConnectionError tells why a request to the API server failed.
+func ClassifyConnectionError(err error) ConnectionProblem {
+	var dnsErr *net.DNSError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCert x509.CertificateInvalidError
+	var verifyErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var netErr net.Error
+
+	switch {
+	case err == nil:
+		return ConnectionUnknown
+	case apierrors.IsUnauthorized(err), apierrors.IsForbidden(err),
+		// An exec credential plugin that failed, e.g. an expired cloud CLI session
+		strings.Contains(err.Error(), "getting credentials"):
+		return ConnectionAuth
+	case errors.As(err, &dnsErr):
+		return ConnectionDNS
+	case errors.As(err, &unknownAuthority), errors.As(err, &hostnameErr), errors.As(err, &invalidCert),
+		errors.As(err, &verifyErr), errors.As(err, &recordErr):
+		return ConnectionTLS
+	case errors.Is(err, context.DeadlineExceeded), apierrors.IsTimeout(err), apierrors.IsServerTimeout(err),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ConnectionTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ConnectionRefused
+	}
+	return ConnectionUnknown
+}
+
+// IsConnectionError reports whether err means the API server could not be
+// reached at all, as opposed to an error the server answered with.
+func IsConnectionError(err error) bool {
+	if _, ok := DescribeAPIError(err); ok || err == nil {
+		return false
+	}
+	switch ClassifyConnectionError(err) {
+	case ConnectionDNS, ConnectionTLS, ConnectionTimeout, ConnectionRefused:
+		return true
+	}
+	return false
+}
+
+// CheckConnection asks the API server for its version.
+func CheckConnection(disc discovery.DiscoveryInterface) (*version.Info, error) {
+	return disc.ServerVersion()
+}
+
+// CheckConnection asks the API server for its version, giving up after
+// timeout, to tell a reachable cluster from a dead one before anything is
+// loaded.
+func (c *Client) CheckConnection(timeout time.Duration) (*version.Info, error) {
+	if c.replay != nil {
+		return CheckConnection(c.clientset.Discovery())
+	}
+	config := rest.CopyConfig(c.config)
+	config.Timeout = timeout
+	disc, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return CheckConnection(disc)
+}
+
+// ContextProbeTimeout bounds the version request of ProbeContext, shorter
+// than ConnectionCheckTimeout since every context is probed at once.
+const ContextProbeTimeout = 3 * time.Second
+
+// ContextHealth is how the cluster of a kubeconfig context answered a
+// probe.
+type ContextHealth struct {
+	Context string
+	Server  string        // URL of the API server, "" when the context is unusable
+	Version string        // GitVersion of the API server, e.g. v1.29.2; "" when unreachable
+	Latency time.Duration // How long the version request took
+	Problem ConnectionProblem
+	Err     error // Why the cluster could not be reached, nil when it answered
+}
+
+// ProbeContext checks the cluster of a context of the client's kubeconfig
+// files without switching to it, by asking its API server for its version,
+// giving up after timeout.
+func (c *Client) ProbeContext(name string, timeout time.Duration) ContextHealth {
+	health := ContextHealth{Context: name}
+	if c.replay != nil {
+		// Nothing to reach but the recording
+		health.Server = c.Server()
+		if info, err := c.CheckConnection(timeout); err == nil {
+			health.Version = info.GitVersion
+		}
+		return health
+	}
+	probe, err := NewClientFor(c.kubeconfig, name)
+	if err == nil {
+		health.Server = probe.Server()
+		start := time.Now()
+		var info *version.Info
+		info, err = probe.CheckConnection(timeout)
+		health.Latency = time.Since(start)
+		if err == nil {
+			health.Version = info.GitVersion
+		}
+	}
+	health.Err = err
+	health.Problem = ClassifyConnectionError(err)
+	return health
+}
+
+// Server returns the URL of the API server.
+func (c *Client) Server() string {
+	return c.config.Host
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+// timeoutError is a net.Error that timed out, as a dial or read deadline
+// returns.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// requestError wraps err the way client-go returns a failed request.
+func requestError(err error) error {
+	return &url.Error{Op: "Get", URL: "https://api.example.com:6443/version", Err: err}
+}
+
+func TestClassifyConnectionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ConnectionProblem
+	}{
+		{"unknown host", requestError(&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "api.example.com", IsNotFound: true}}), ConnectionDNS},
+		{"unknown authority", requestError(x509.UnknownAuthorityError{}), ConnectionTLS},
+		{"wrong host name", requestError(x509.HostnameError{Certificate: &x509.Certificate{}, Host: "api.example.com"}), ConnectionTLS},
+		{"expired certificate", requestError(x509.CertificateInvalidError{Reason: x509.Expired}), ConnectionTLS},
+		{"unauthorized", apierrors.NewUnauthorized("Unauthorized"), ConnectionAuth},
+		{"exec plugin failed", requestError(errors.New("getting credentials: exec: executable aws failed with exit code 255")), ConnectionAuth},
+		{"dial timeout", requestError(&net.OpError{Op: "dial", Net: "tcp", Err: timeoutError{}}), ConnectionTimeout},
+		{"context deadline", requestError(context.DeadlineExceeded), ConnectionTimeout},
+		{"server timeout", apierrors.NewTimeoutError("request did not complete", 1), ConnectionTimeout},
+		{"refused", requestError(&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}), ConnectionRefused},
+		{"other", errors.New("something else"), ConnectionUnknown},
+		{"nil", nil, ConnectionUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyConnectionError(tt.err); got != tt.want {
+				t.Errorf("ClassifyConnectionError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	refused := requestError(&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)})
+	if !IsConnectionError(refused) {
+		t.Error("a refused connection should be a connection error")
+	}
+	if !IsConnectionError(fmt.Errorf("list pods: %w", refused)) {
+		t.Error("a wrapped refused connection should be a connection error")
+	}
+	// The server answered these
+	for _, err := range []error{
+		apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("denied")),
+		apierrors.NewTimeoutError("request did not complete", 1),
+		nil,
+	} {
+		if IsConnectionError(err) {
+			t.Errorf("IsConnectionError(%v) = true, want false", err)
+		}
+	}
+}
+
+func TestClient_CheckConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"major":"1","minor":"29","gitVersion":"v1.29.2"}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromConfig(&rest.Config{Host: server.URL}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Server() != server.URL {
+		t.Errorf("Server() = %q, want %q", client.Server(), server.URL)
+	}
+	info, err := client.CheckConnection(ConnectionCheckTimeout)
+	if err != nil || info.GitVersion != "v1.29.2" {
+		t.Fatalf("CheckConnection() = %v, %v, want v1.29.2", info, err)
+	}
+
+	t.Run("refused", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		client, err := NewClientFromConfig(&rest.Config{Host: closed.URL}, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = client.CheckConnection(ConnectionCheckTimeout)
+		if got := ClassifyConnectionError(err); got != ConnectionRefused {
+			t.Errorf("CheckConnection() error %v classified %v, want %v", err, got, ConnectionRefused)
+		}
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+		}))
+		defer stalled.Close()
+		client, err := NewClientFromConfig(&rest.Config{Host: stalled.URL}, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = client.CheckConnection(50 * time.Millisecond)
+		if got := ClassifyConnectionError(err); got != ConnectionTimeout {
+			t.Errorf("CheckConnection() error %v classified %v, want %v", err, got, ConnectionTimeout)
+		}
+	})
+}
+
+func TestClient_ProbeContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"major":"1","minor":"29","gitVersion":"v1.29.2"}`)
+	}))
+	defer server.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	content := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: up
+clusters:
+- name: up
+  cluster:
+    server: %s
+- name: down
+  cluster:
+    server: %s
+users:
+- name: dev
+contexts:
+- name: up
+  context: {cluster: up, user: dev}
+- name: down
+  context: {cluster: down, user: dev}
+`, server.URL, closed.URL)
+	if err := os.WriteFile(kubeconfig, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewClientFor(kubeconfig, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	up := client.ProbeContext("up", ContextProbeTimeout)
+	if up.Err != nil || up.Version != "v1.29.2" || up.Server != server.URL {
+		t.Errorf("ProbeContext(up) = %+v, want v1.29.2 at %s", up, server.URL)
+	}
+	down := client.ProbeContext("down", ContextProbeTimeout)
+	if down.Err == nil || down.Problem != ConnectionRefused {
+		t.Errorf("ProbeContext(down) = %+v, want connection refused", down)
+	}
+	if missing := client.ProbeContext("missing", ContextProbeTimeout); missing.Err == nil {
+		t.Error("ProbeContext of an unknown context should fail")
+	}
+	// Probing does not switch the client
+	if client.Context() != "up" {
+		t.Errorf("Context() = %q after probing, want up", client.Context())
+	}
+}
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/rest"
+)
+
+// MaxFilePreviewBytes is how much of a file ReadContainerFile reads by default.
+const MaxFilePreviewBytes = 512 * 1024
+
+// binarySniffBytes is how much of a file IsBinary looks at, like git does.
+const binarySniffBytes = 8000
+
+var (
+	// ErrFileNotFound is returned when a path does not exist in the container.
+	ErrFileNotFound = errors.New("no such file or directory")
+	// ErrIsDirectory is returned by ReadContainerFile when the path is a directory.
+	ErrIsDirectory = errors.New("is a directory")
+	// ErrNoFileTools is returned when the container has neither the command
+	// needed nor busybox, as in distroless and scratch images.
+	ErrNoFileTools = errors.New("container has no ls, cat or tar")
+)
+
+// ContainerFile is an entry of a directory listed in a container.
+type ContainerFile struct {
+	Name       string
+	Path       string
+	Mode       string // e.g. "-rw-r--r--"
+	Size       int64
+	ModTime    string // As printed by ls, e.g. "Jan  2 15:04"
+	IsDir      bool
+	IsLink     bool
+	LinkTarget string
+}
+
+// ContainerFileContent is the start of a file read from a container.
+type ContainerFileContent struct {
+	Data      []byte
+	Truncated bool // The file is longer than the limit it was read with
+	Binary    bool
+}
+
+// execRunner runs a command in a container, writing its output to stdout
+// and stderr.
+type execRunner func(ctx context.Context, command []string, stdout, stderr io.Writer) error
+
+func podExecRunner(config *rest.Config, namespace, pod, container string) execRunner {
+	return func(ctx context.Context, command []string, stdout, stderr io.Writer) error {
+		return ExecIntoPod(ctx, config, namespace, pod, container, command, ExecOptions{Stdout: stdout, Stderr: stderr})
+	}
+}
+
+// ListContainerDir lists a directory in a container with ls -la, through
+// busybox when the image has no ls. Directories are listed first.
+func ListContainerDir(ctx context.Context, config *rest.Config, namespace, pod, container, dir string) ([]ContainerFile, error) {
+	return listContainerDir(ctx, podExecRunner(config, namespace, pod, container), dir)
+}
+
+func listContainerDir(ctx context.Context, run execRunner, dir string) ([]ContainerFile, error) {
+	dir = path.Clean("/" + dir)
+	// The trailing slash lists the contents of a symlinked directory
+	target := dir
+	if target != "/" {
+		target += "/"
+	}
+
+	var stdout bytes.Buffer
+	if err := runFileCommand(ctx, run, dir, []string{"ls", "-la", target}, &stdout); err != nil {
+		return nil, err
+	}
+
+	files := parseLsOutput(dir, stdout.String())
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].IsDir != files[j].IsDir {
+			return files[i].IsDir
+		}
+		return files[i].Name < files[j].Name
+	})
+	return files, nil
+}
+
+// ReadContainerFile reads up to limit bytes of a file in a container with
+// cat, through busybox when the image has no cat. Reading stops at the
+// limit, so large files are not transferred whole.
+func ReadContainerFile(ctx context.Context, config *rest.Config, namespace, pod, container, filePath string, limit int64) (*ContainerFileContent, error) {
+	return readContainerFile(ctx, podExecRunner(config, namespace, pod, container), filePath, limit)
+}
+
+func readContainerFile(ctx context.Context, run execRunner, filePath string, limit int64) (*ContainerFileContent, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	out := &cappedWriter{limit: limit, onFull: cancel}
+	err := runFileCommand(ctx, run, filePath, []string{"cat", filePath}, out)
+	// Hitting the limit aborts the stream, which is not an error
+	if err != nil && !out.truncated {
+		return nil, err
+	}
+	return &ContainerFileContent{
+		Data:      out.buf.Bytes(),
+		Truncated: out.truncated,
+		Binary:    IsBinary(out.buf.Bytes()),
+	}, nil
+}
+
+// CopyFromPod copies a file or directory out of a container to a local
+// path, streaming it with tar like kubectl cp does. When dest is an
+// existing directory the copy is placed inside it. Symlinks are skipped.
+// Returns the local path written.
+func CopyFromPod(ctx context.Context, config *rest.Config, namespace, pod, container, src, dest string) (string, error) {
+	return copyFromPod(ctx, podExecRunner(config, namespace, pod, container), src, dest)
+}
+
+func copyFromPod(ctx context.Context, run execRunner, src, dest string) (string, error) {
+	src = path.Clean("/" + src)
+	if src == "/" {
+		return "", errors.New("cannot copy the whole container filesystem")
+	}
+	dir, base := path.Split(src)
+
+	target := filepath.Clean(dest)
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		target = filepath.Join(dest, base)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	runErr := make(chan error, 1)
+	go func() {
+		err := runFileCommand(ctx, run, src, []string{"tar", "cf", "-", "-C", dir, base}, pw)
+		pw.CloseWithError(err)
+		runErr <- err
+	}()
+
+	err := untarTo(pr, base, target)
+	// Unblock the command if extracting stopped early
+	pr.CloseWithError(err)
+	cancel()
+	if rerr := <-runErr; rerr != nil && err == nil {
+		err = rerr
+	}
+	if err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// untarTo extracts a tar stream whose entries are base or below it to
+// target, refusing entries that would land outside of target.
+func untarTo(r io.Reader, base, target string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		rel := strings.TrimPrefix(path.Clean(hdr.Name), base)
+		out := filepath.Join(target, filepath.FromSlash(rel))
+		if out != target && !strings.HasPrefix(out, target+string(filepath.Separator)) {
+			return fmt.Errorf("refusing to write %q outside of %s", hdr.Name, target)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(out, 0o755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+		case tar.TypeReg:
+			if err := writeFileFrom(tr, out, hdr.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFileFrom(r io.Reader, name string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm|0o200)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return f.Close()
+}
+
+// runFileCommand runs command in the container, then through busybox if
+// the command itself does not exist. Errors printed by the command, such
+// as "No such file or directory", are returned as errors about target.
+func runFileCommand(ctx context.Context, run execRunner, target string, command []string, stdout io.Writer) error {
+	for _, cmd := range [][]string{command, append([]string{"busybox"}, command...)} {
+		var stderr bytes.Buffer
+		err := run(ctx, cmd, stdout, &stderr)
+		if err == nil {
+			return nil
+		}
+		if !isMissingShell(err) {
+			return fileCommandError(target, stderr.String(), err)
+		}
+	}
+	return fmt.Errorf("%w (tried %s and busybox %s)", ErrNoFileTools, command[0], command[0])
+}
+
+func fileCommandError(target, stderr string, err error) error {
+	msg := strings.TrimSpace(stderr)
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.Contains(lower, "no such file"):
+		return fmt.Errorf("%s: %w", target, ErrFileNotFound)
+	case strings.Contains(lower, "is a directory"):
+		return fmt.Errorf("%s: %w", target, ErrIsDirectory)
+	case msg != "":
+		return errors.New(msg)
+	}
+	return err
+}
+
+// parseLsOutput parses the output of ls -la, as printed by GNU coreutils
+// and busybox. "." and ".." are left out.
+func parseLsOutput(dir, output string) []ContainerFile {
+	var files []ContainerFile
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || strings.HasPrefix(line, "total ") {
+			continue
+		}
+
+		// mode links owner group size month day time name; device files
+		// have "major, minor" in place of the size
+		n := 8
+		if fields, _ := cutFields(line, 5); len(fields) == 5 && strings.HasSuffix(fields[4], ",") {
+			n = 9
+		}
+		fields, name := cutFields(line, n)
+		if len(fields) < n || name == "" || len(fields[0]) < 10 {
+			continue
+		}
+
+		f := ContainerFile{
+			Mode:    fields[0],
+			ModTime: strings.Join(fields[n-3:], " "),
+			IsDir:   fields[0][0] == 'd',
+			IsLink:  fields[0][0] == 'l',
+		}
+		if n == 8 {
+			f.Size, _ = strconv.ParseInt(fields[4], 10, 64)
+		}
+		if f.IsLink {
+			name, f.LinkTarget, _ = strings.Cut(name, " -> ")
+		}
+		if name == "." || name == ".." {
+			continue
+		}
+		f.Name = name
+		f.Path = path.Join(dir, name)
+		files = append(files, f)
+	}
+	return files
+}
+
+// cutFields splits the first n whitespace separated fields off line and
+// returns them with the rest of the line, spaces included.
+func cutFields(line string, n int) ([]string, string) {
+	var fields []string
+	rest := line
+	for len(fields) < n {
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			break
+		}
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			fields = append(fields, rest)
+			rest = ""
+			break
+		}
+		fields = append(fields, rest[:end])
+		rest = rest[end:]
+	}
+	return fields, strings.TrimLeft(rest, " \t")
+}
+
+// IsBinary reports whether data looks like the start of a binary file:
+// it has a NUL byte early on.
+func IsBinary(data []byte) bool {
+	if len(data) > binarySniffBytes {
+		data = data[:binarySniffBytes]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// cappedWriter keeps the first limit bytes written to it and calls onFull
+// once more arrive.
+type cappedWriter struct {
+	buf       bytes.Buffer
+	limit     int64
+	truncated bool
+	onFull    func()
+}
+
+func (w *cappedWriter) Write(p []byte) (int, error) {
+	if w.truncated {
+		return 0, io.ErrShortWrite
+	}
+	room := w.limit - int64(w.buf.Len())
+	if int64(len(p)) <= room {
+		return w.buf.Write(p)
+	}
+	w.buf.Write(p[:room])
+	w.truncated = true
+	if w.onFull != nil {
+		w.onFull()
+	}
+	return int(room), io.ErrShortWrite
+}
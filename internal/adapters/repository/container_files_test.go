@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeRunner answers exec'd commands from a table keyed by the command line.
+// Commands not in the table fail the way a missing binary does.
+type fakeRunner struct {
+	stdout map[string]string
+	stderr map[string]string
+	calls  []string
+}
+
+func (f *fakeRunner) run(ctx context.Context, command []string, stdout, stderr io.Writer) error {
+	line := strings.Join(command, " ")
+	f.calls = append(f.calls, line)
+	if msg, ok := f.stderr[line]; ok {
+		io.WriteString(stderr, msg)
+		return errors.New("command terminated with exit code 1")
+	}
+	out, ok := f.stdout[line]
+	if !ok {
+		return errors.New(`exec: "` + command[0] + `": executable file not found in $PATH`)
+	}
+	_, err := io.WriteString(stdout, out)
+	return err
+}
+
+func TestParseLsOutput(t *testing.T) {
+	gnu := `total 16
+drwxrwxrwx 3 root root 4096 Jan  2 15:04 .
+drwxr-xr-x 1 root root 4096 Jan  2 15:04 ..
+drwxr-xr-x 2 root root 4096 Jan  2 15:04 ..2024_01_02
+lrwxrwxrwx 1 root root   31 Jan  2 15:04 ..data -> ..2024_01_02
+lrwxrwxrwx 1 root root   15 Jan  2 15:04 app.yaml -> ..data/app.yaml
+-rw-r--r-- 1 app  app  1234 Mar 10  2023 my notes.txt
+crw-rw-rw- 1 root root 1, 3 Jan  2 15:04 null
+`
+	files := parseLsOutput("/etc/config", gnu)
+	if len(files) != 5 {
+		t.Fatalf("parseLsOutput() returned %d files, want 5: %+v", len(files), files)
+	}
+	if !files[0].IsDir || files[0].Path != "/etc/config/..2024_01_02" {
+		t.Errorf("files[0] = %+v, want directory ..2024_01_02", files[0])
+	}
+	if !files[2].IsLink || files[2].Name != "app.yaml" || files[2].LinkTarget != "..data/app.yaml" {
+		t.Errorf("files[2] = %+v, want link app.yaml -> ..data/app.yaml", files[2])
+	}
+	if files[3].Name != "my notes.txt" || files[3].Size != 1234 || files[3].ModTime != "Mar 10 2023" {
+		t.Errorf("files[3] = %+v, want 'my notes.txt' of 1234 bytes", files[3])
+	}
+	if files[4].Name != "null" || files[4].Mode[0] != 'c' {
+		t.Errorf("files[4] = %+v, want device null", files[4])
+	}
+}
+
+func TestListContainerDir_FallsBackToBusybox(t *testing.T) {
+	runner := &fakeRunner{stdout: map[string]string{
+		"busybox ls -la /etc/": "-rw-r--r--    1 root     root            10 Jan  2 15:04 hosts\n" +
+			"drwxr-xr-x    2 root     root          4096 Jan  2 15:04 ssl\n",
+	}}
+
+	files, err := listContainerDir(context.Background(), runner.run, "/etc")
+	if err != nil {
+		t.Fatalf("listContainerDir() error = %v", err)
+	}
+	if len(runner.calls) != 2 {
+		t.Errorf("calls = %v, want ls then busybox ls", runner.calls)
+	}
+	if len(files) != 2 || files[0].Name != "ssl" || files[1].Name != "hosts" {
+		t.Errorf("files = %+v, want ssl (directory first) then hosts", files)
+	}
+}
+
+func TestListContainerDir_NotFound(t *testing.T) {
+	runner := &fakeRunner{stderr: map[string]string{
+		"ls -la /missing/": "ls: cannot access '/missing/': No such file or directory",
+	}}
+
+	_, err := listContainerDir(context.Background(), runner.run, "/missing")
+	if !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("listContainerDir() error = %v, want ErrFileNotFound", err)
+	}
+}
+
+func TestListContainerDir_NoTools(t *testing.T) {
+	runner := &fakeRunner{}
+	_, err := listContainerDir(context.Background(), runner.run, "/")
+	if !errors.Is(err, ErrNoFileTools) {
+		t.Errorf("listContainerDir() error = %v, want ErrNoFileTools", err)
+	}
+}
+
+func TestReadContainerFile(t *testing.T) {
+	runner := &fakeRunner{
+		stdout: map[string]string{
+			"cat /etc/hostname": "web-1\n",
+			"cat /bin/app":      "\x7fELF\x00\x00binary",
+			"cat /var/log/big":  strings.Repeat("x", 100),
+		},
+		stderr: map[string]string{
+			"cat /etc": "cat: read error: Is a directory",
+		},
+	}
+	ctx := context.Background()
+
+	content, err := readContainerFile(ctx, runner.run, "/etc/hostname", 64)
+	if err != nil {
+		t.Fatalf("readContainerFile() error = %v", err)
+	}
+	if string(content.Data) != "web-1\n" || content.Binary || content.Truncated {
+		t.Errorf("content = %+v, want the text file", content)
+	}
+
+	content, err = readContainerFile(ctx, runner.run, "/bin/app", 64)
+	if err != nil || !content.Binary {
+		t.Errorf("readContainerFile(binary) = %+v, %v, want Binary", content, err)
+	}
+
+	content, err = readContainerFile(ctx, runner.run, "/var/log/big", 10)
+	if err != nil {
+		t.Fatalf("readContainerFile(big) error = %v", err)
+	}
+	if len(content.Data) != 10 || !content.Truncated {
+		t.Errorf("content = %d bytes, truncated %v, want 10 bytes truncated", len(content.Data), content.Truncated)
+	}
+
+	if _, err := readContainerFile(ctx, runner.run, "/etc", 64); !errors.Is(err, ErrIsDirectory) {
+		t.Errorf("readContainerFile(dir) error = %v, want ErrIsDirectory", err)
+	}
+}
+
+func tarStream(t *testing.T, entries map[string]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, body := range entries {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(body)), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(name, "/") {
+			hdr = &tar.Header{Name: name, Mode: 0o755, Typeflag: tar.TypeDir}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			tw.Write([]byte(body))
+		}
+	}
+	tw.Close()
+	return buf.String()
+}
+
+func TestCopyFromPod_Directory(t *testing.T) {
+	runner := &fakeRunner{stdout: map[string]string{
+		"tar cf - -C /etc/ config": tarStream(t, map[string]string{
+			"config/":         "",
+			"config/app.yaml": "port: 8080\n",
+		}),
+	}}
+	dest := t.TempDir()
+
+	target, err := copyFromPod(context.Background(), runner.run, "/etc/config", dest)
+	if err != nil {
+		t.Fatalf("copyFromPod() error = %v", err)
+	}
+	if target != filepath.Join(dest, "config") {
+		t.Errorf("target = %q, want the directory inside dest", target)
+	}
+	data, err := os.ReadFile(filepath.Join(dest, "config", "app.yaml"))
+	if err != nil || string(data) != "port: 8080\n" {
+		t.Errorf("copied file = %q, %v", data, err)
+	}
+}
+
+func TestCopyFromPod_File(t *testing.T) {
+	runner := &fakeRunner{stdout: map[string]string{
+		"tar cf - -C /etc/ hostname": tarStream(t, map[string]string{"hostname": "web-1\n"}),
+	}}
+	dest := filepath.Join(t.TempDir(), "host.txt")
+
+	if _, err := copyFromPod(context.Background(), runner.run, "/etc/hostname", dest); err != nil {
+		t.Fatalf("copyFromPod() error = %v", err)
+	}
+	if data, err := os.ReadFile(dest); err != nil || string(data) != "web-1\n" {
+		t.Errorf("copied file = %q, %v", data, err)
+	}
+}
+
+func TestCopyFromPod_RefusesPathTraversal(t *testing.T) {
+	runner := &fakeRunner{stdout: map[string]string{
+		"tar cf - -C /etc/ hostname": tarStream(t, map[string]string{"../../evil": "x"}),
+	}}
+	dest := filepath.Join(t.TempDir(), "out")
+
+	if _, err := copyFromPod(context.Background(), runner.run, "/etc/hostname", dest); err == nil {
+		t.Error("copyFromPod() expected an error for an entry outside of dest")
+	}
+}
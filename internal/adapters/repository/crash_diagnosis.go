@@ -0,0 +1,356 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// CrashCause is the root cause class found by DiagnoseCrash.
+type CrashCause string
+
+const (
+	CauseOOMKilled     CrashCause = "OOMKilled"
+	CauseKilled        CrashCause = "Killed"        // Exit 137 without an OOM kill
+	CauseTerminated    CrashCause = "Terminated"    // Exit 143 (SIGTERM)
+	CauseAppError      CrashCause = "Error"         // Exit 1 or another exit code
+	CauseImagePull     CrashCause = "ImagePull"     // ErrImagePull, ImagePullBackOff
+	CauseUnschedulable CrashCause = "Unschedulable" // Requests don't fit on any node
+	CauseProbeFailure  CrashCause = "ProbeFailure"  // Liveness, startup or readiness probe failing
+)
+
+// CrashDiagnosis is the most likely reason a pod is crashing or not
+// starting, correlated from container states, the last termination and
+// events.
+type CrashDiagnosis struct {
+	Cause     CrashCause
+	Container string   // Affected container; empty for scheduling failures
+	Summary   string   // One line, e.g. "OOMKilled: container main exceeded 512Mi limit"
+	Reasoning []string // Evidence behind the summary and what to check next
+}
+
+// insufficientPattern extracts the resources named in a FailedScheduling
+// message, e.g. "0/3 nodes are available: 3 Insufficient memory."
+var insufficientPattern = regexp.MustCompile(`Insufficient ([A-Za-z0-9./-]+)`)
+
+// imagePullReasons are the waiting reasons of a container whose image
+// cannot be pulled.
+var imagePullReasons = map[string]bool{
+	"ErrImagePull":     true,
+	"ImagePullBackOff": true,
+	"InvalidImageName": true,
+}
+
+// DiagnoseCrash returns the most likely root cause of a failing pod, or nil
+// when nothing is wrong. Checks run in order of certainty: scheduling
+// failures due to resource requests, image pull errors, OOM kills, failing
+// liveness or startup probes (which explain the exit codes 137 and 143
+// that follow), other exit codes and finally failing readiness probes.
+// Events are expected most recent first, as GetPodEvents returns them.
+func DiagnoseCrash(pod *PodInfo, events []EventInfo) *CrashDiagnosis {
+	if pod == nil {
+		return nil
+	}
+	if d := diagnoseScheduling(pod, events); d != nil {
+		return d
+	}
+
+	containers := append(append([]ContainerInfo{}, pod.InitContainers...), pod.Containers...)
+	for _, c := range containers {
+		if d := diagnoseImagePull(c, events); d != nil {
+			return d
+		}
+	}
+	for _, c := range containers {
+		if t := crashTermination(c); t != nil && t.Reason == "OOMKilled" {
+			return diagnoseOOM(c, t)
+		}
+	}
+	for _, probe := range []string{"Liveness", "Startup"} {
+		if d := diagnoseProbe(pod, events, probe); d != nil {
+			return d
+		}
+	}
+	for _, c := range containers {
+		if t := crashTermination(c); t != nil {
+			return diagnoseExitCode(c, t, pod.TerminationGracePeriod)
+		}
+	}
+	return diagnoseProbe(pod, events, "Readiness")
+}
+
+// diagnoseScheduling reports a Pending pod that no node has room for.
+func diagnoseScheduling(pod *PodInfo, events []EventInfo) *CrashDiagnosis {
+	if pod.Node != "" {
+		return nil
+	}
+	for _, e := range events {
+		if e.Reason != "FailedScheduling" {
+			continue
+		}
+		matches := insufficientPattern.FindAllStringSubmatch(e.Message, -1)
+		if len(matches) == 0 {
+			return nil // Most recent scheduling failure is not about resources
+		}
+
+		cpu, mem := podInfoRequests(*pod)
+		var short, requests []string
+		for _, m := range matches {
+			name := strings.TrimRight(m[1], ".,")
+			if containsString(short, name) {
+				continue
+			}
+			short = append(short, name)
+			switch name {
+			case "cpu":
+				requests = append(requests, "cpu "+resource.NewMilliQuantity(cpu, resource.DecimalSI).String())
+			case "memory":
+				requests = append(requests, "memory "+resource.NewQuantity(mem, resource.BinarySI).String())
+			}
+		}
+
+		summary := "FailedScheduling: no node has enough " + strings.Join(short, " and ")
+		if len(requests) > 0 {
+			summary += " for requests of " + strings.Join(requests, ", ")
+		}
+		return &CrashDiagnosis{
+			Cause:   CauseUnschedulable,
+			Summary: summary,
+			Reasoning: []string{
+				"Scheduler: " + e.Message,
+				"The pod's requests are larger than the free allocatable capacity of every node",
+				"Lower the requests, free capacity by scaling down other workloads, or add nodes",
+			},
+		}
+	}
+	return nil
+}
+
+// diagnoseImagePull reports a container whose image cannot be pulled.
+func diagnoseImagePull(c ContainerInfo, events []EventInfo) *CrashDiagnosis {
+	if c.State != "Waiting" || !imagePullReasons[c.Reason] {
+		return nil
+	}
+
+	// ImagePullBackOff only says "Back-off pulling image", the cause is in
+	// the last Failed event for the image
+	message := c.Message
+	for _, e := range events {
+		if e.Reason == "Failed" && strings.Contains(e.Message, c.Image) {
+			message = e.Message
+			break
+		}
+	}
+
+	cause := "image could not be pulled"
+	next := "Check the image name and that the node can reach the registry"
+	switch {
+	case c.Reason == "InvalidImageName":
+		cause = "image name is invalid"
+		next = "Fix the image reference in the pod spec"
+	default:
+		switch ClassifyImagePullError(message) {
+		case PullManifestUnknown:
+			cause = "image tag not found"
+			next = "Check that the tag was pushed to " + imageRegistry(c.Image)
+		case PullUnauthorized:
+			cause = "registry denied access"
+			next = "Check the imagePullSecrets of the pod and its ServiceAccount"
+		case PullUnreachable:
+			cause = "registry unreachable"
+			next = "Check DNS and network access from the node to " + imageRegistry(c.Image)
+		case PullRateLimited:
+			cause = "registry rate limit hit"
+			next = "Authenticate pulls or use a mirror to raise the rate limit"
+		}
+	}
+
+	reasoning := []string{fmt.Sprintf("Container %s is %s for image %s", c.Name, c.Reason, c.Image)}
+	if message != "" {
+		reasoning = append(reasoning, "Error: "+message)
+	}
+	return &CrashDiagnosis{
+		Cause:     CauseImagePull,
+		Container: c.Name,
+		Summary:   c.Reason + ": " + cause,
+		Reasoning: append(reasoning, next),
+	}
+}
+
+// crashTermination returns the termination that explains a failing
+// container: its current state when it terminated with an error, otherwise
+// the previous instance's while the container is not ready (waiting in
+// CrashLoopBackOff or restarted and not up yet). Returns nil for a healthy
+// container, even if it restarted in the past.
+func crashTermination(c ContainerInfo) *TerminationInfo {
+	if c.State == "Terminated" && c.ExitCode != nil && (*c.ExitCode != 0 || c.Reason == "OOMKilled") {
+		return &TerminationInfo{Reason: c.Reason, Message: c.Message, ExitCode: *c.ExitCode, FinishedAt: c.FinishedAt}
+	}
+	if c.LastTermination != nil && !c.Ready && c.State != "Terminated" {
+		return c.LastTermination
+	}
+	return nil
+}
+
+// diagnoseOOM reports a container killed for exceeding its memory limit.
+func diagnoseOOM(c ContainerInfo, t *TerminationInfo) *CrashDiagnosis {
+	limit := c.Resources.MemoryLimit
+	d := &CrashDiagnosis{Cause: CauseOOMKilled, Container: c.Name}
+	if limit == "" || limit == "0" {
+		d.Summary = fmt.Sprintf("OOMKilled: container %s ran out of memory with no limit set", c.Name)
+		d.Reasoning = []string{
+			fmt.Sprintf("Last exit code %d (%s) at %s", t.ExitCode, t.Reason, t.FinishedAt),
+			"Without a memory limit the container was killed because the node ran out of memory",
+			"Set a memory limit and check the memory usage in Resource Usage",
+		}
+		return d
+	}
+	d.Summary = fmt.Sprintf("OOMKilled: container %s exceeded %s limit", c.Name, limit)
+	d.Reasoning = []string{
+		fmt.Sprintf("Last exit code %d (%s) at %s", t.ExitCode, t.Reason, t.FinishedAt),
+		fmt.Sprintf("Memory limit is %s (request %s)", limit, orNone(c.Resources.MemoryRequest)),
+		"Raise the limit or look for a memory leak in the previous container's logs",
+	}
+	return d
+}
+
+// diagnoseProbe reports the most recent failure of the given probe type
+// ("Liveness", "Startup" or "Readiness") for a container that has it.
+func diagnoseProbe(pod *PodInfo, events []EventInfo, probe string) *CrashDiagnosis {
+	prefix := probe + " probe failed"
+	for _, e := range events {
+		if e.Reason != "Unhealthy" || !strings.HasPrefix(e.Message, prefix) {
+			continue
+		}
+		c, info := probedContainer(pod, probe, events)
+		if c == nil || c.Ready {
+			return nil // Old failures of a container that has recovered
+		}
+
+		d := &CrashDiagnosis{
+			Cause:     CauseProbeFailure,
+			Container: c.Name,
+			Summary:   fmt.Sprintf("%s probe failing on %s", probe, probeTarget(info)),
+		}
+		failure := e.Message
+		if e.Count > 1 {
+			failure += fmt.Sprintf(" (%d times)", e.Count)
+		}
+		d.Reasoning = []string{failure}
+		switch probe {
+		case "Readiness":
+			d.Reasoning = append(d.Reasoning, fmt.Sprintf("Container %s is removed from Service endpoints until the probe passes", c.Name))
+		default:
+			d.Reasoning = append(d.Reasoning, fmt.Sprintf("The kubelet restarts container %s after %d consecutive failures", c.Name, info.FailureThreshold))
+			if t := crashTermination(*c); t != nil {
+				d.Reasoning = append(d.Reasoning, fmt.Sprintf("Last exit code %d (%s) comes from the probe kill", t.ExitCode, t.Reason))
+			}
+		}
+		d.Reasoning = append(d.Reasoning, fmt.Sprintf("Probe waits %ds, then checks every %ds with a %ds timeout", info.InitialDelay, info.Period, info.Timeout))
+		d.Reasoning = append(d.Reasoning, "Check that the endpoint answers in time, or raise initialDelaySeconds or the timeout")
+		d.Reasoning = append(d.Reasoning, "Probes in Pod Details shows the status and last failure of every probe")
+		return d
+	}
+	return nil
+}
+
+// probedContainer picks the container a probe event is about. Unhealthy
+// events don't name the container, so one named in a kubelet Killing event
+// ("Container main failed liveness probe") is preferred, then the first
+// container that has the probe.
+func probedContainer(pod *PodInfo, probe string, events []EventInfo) (*ContainerInfo, *ProbeInfo) {
+	var first *ContainerInfo
+	var firstInfo *ProbeInfo
+	for i := range pod.Containers {
+		c := &pod.Containers[i]
+		info := containerProbe(c, probe)
+		if info == nil {
+			continue
+		}
+		for _, e := range events {
+			if e.Reason == "Killing" && strings.Contains(e.Message, "Container "+c.Name+" failed "+strings.ToLower(probe)) {
+				return c, info
+			}
+		}
+		if first == nil {
+			first, firstInfo = c, info
+		}
+	}
+	return first, firstInfo
+}
+
+// containerProbe returns the container's probe of the given type.
+func containerProbe(c *ContainerInfo, probe string) *ProbeInfo {
+	switch probe {
+	case "Liveness":
+		return c.LivenessProbe
+	case "Startup":
+		return c.StartupProbe
+	case "Readiness":
+		return c.ReadinessProbe
+	}
+	return nil
+}
+
+// probeTarget describes what a probe checks, e.g. "/health:8080".
+func probeTarget(p *ProbeInfo) string {
+	port := fmt.Sprint(p.Port)
+	if p.PortName != "" {
+		port = p.PortName
+	}
+	switch p.Type {
+	case "HTTP":
+		return fmt.Sprintf("%s:%s", p.Path, port)
+	case "TCP":
+		return "tcp:" + port
+	case "gRPC":
+		return "grpc:" + port
+	case "Exec":
+		return "exec " + strings.Join(p.Command, " ")
+	}
+	return strings.ToLower(p.Type)
+}
+
+// diagnoseExitCode explains a container's last non-OOM exit.
+func diagnoseExitCode(c ContainerInfo, t *TerminationInfo, gracePeriod int64) *CrashDiagnosis {
+	d := &CrashDiagnosis{Cause: CauseAppError, Container: c.Name}
+	last := fmt.Sprintf("Last exit code %d (%s) at %s", t.ExitCode, orNone(t.Reason), t.FinishedAt)
+	switch t.ExitCode {
+	case 137:
+		d.Cause = CauseKilled
+		d.Summary = fmt.Sprintf("Exit 137: container %s was killed with SIGKILL", c.Name)
+		d.Reasoning = []string{
+			last,
+			fmt.Sprintf("Not reported as OOMKilled: the process ignored SIGTERM for the %ds grace period, or something else killed it", gracePeriod),
+		}
+	case 143:
+		d.Cause = CauseTerminated
+		d.Summary = fmt.Sprintf("Exit 143: container %s stopped on SIGTERM", c.Name)
+		d.Reasoning = []string{
+			last,
+			"The kubelet or the application itself sent SIGTERM; check events for evictions or probe kills",
+		}
+	case 1:
+		d.Summary = fmt.Sprintf("Exit 1: container %s failed with an application error", c.Name)
+		d.Reasoning = []string{
+			last,
+			"Exit code 1 is a generic application error, often bad configuration or a missing dependency at startup",
+		}
+	case 0:
+		d.Summary = fmt.Sprintf("Exit 0: container %s keeps completing and being restarted", c.Name)
+		d.Reasoning = []string{
+			last,
+			"The process exits successfully, but the restart policy restarts it; long-running containers must not exit",
+		}
+	default:
+		d.Summary = fmt.Sprintf("Exit %d: container %s crashed", t.ExitCode, c.Name)
+		d.Reasoning = []string{last}
+	}
+	if t.Message != "" {
+		d.Reasoning = append(d.Reasoning, "Termination message: "+t.Message)
+	}
+	d.Reasoning = append(d.Reasoning, "Check the previous container's logs (P in the Logs panel)")
+	return d
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiagnoseCrash(t *testing.T) {
+	exit := func(code int32) *int32 { return &code }
+	httpProbe := &ProbeInfo{Type: "HTTP", Path: "/health", Port: 8080, Period: 10, Timeout: 1, FailureThreshold: 3}
+
+	tests := []struct {
+		name        string
+		pod         PodInfo
+		events      []EventInfo
+		wantCause   CrashCause
+		wantSummary string
+		wantReason  string // Substring expected in the reasoning
+	}{
+		{
+			name: "healthy pod",
+			pod: PodInfo{Node: "node-1", Containers: []ContainerInfo{
+				{Name: "main", State: "Running", Ready: true, RestartCount: 2, LastTermination: &TerminationInfo{Reason: "Error", ExitCode: 1}},
+			}},
+		},
+		{
+			name: "OOMKilled with limit",
+			pod: PodInfo{Node: "node-1", Containers: []ContainerInfo{{
+				Name: "main", State: "Waiting", Reason: "CrashLoopBackOff", RestartCount: 4,
+				Resources:       ResourceRequirements{MemoryRequest: "256Mi", MemoryLimit: "512Mi"},
+				LastTermination: &TerminationInfo{Reason: "OOMKilled", ExitCode: 137},
+			}}},
+			wantCause:   CauseOOMKilled,
+			wantSummary: "OOMKilled: container main exceeded 512Mi limit",
+			wantReason:  "Memory limit is 512Mi (request 256Mi)",
+		},
+		{
+			name: "OOMKilled without limit",
+			pod: PodInfo{Node: "node-1", Containers: []ContainerInfo{{
+				Name: "main", State: "Terminated", Reason: "OOMKilled", ExitCode: exit(137),
+			}}},
+			wantCause:   CauseOOMKilled,
+			wantSummary: "OOMKilled: container main ran out of memory with no limit set",
+		},
+		{
+			name: "exit 137 without OOM",
+			pod: PodInfo{Node: "node-1", TerminationGracePeriod: 30, Containers: []ContainerInfo{{
+				Name: "main", State: "Waiting", Reason: "CrashLoopBackOff", RestartCount: 3,
+				LastTermination: &TerminationInfo{Reason: "Error", ExitCode: 137},
+			}}},
+			wantCause:   CauseKilled,
+			wantSummary: "Exit 137: container main was killed with SIGKILL",
+			wantReason:  "30s grace period",
+		},
+		{
+			name: "exit 143",
+			pod: PodInfo{Node: "node-1", Containers: []ContainerInfo{{
+				Name: "main", State: "Waiting", Reason: "CrashLoopBackOff", RestartCount: 3,
+				LastTermination: &TerminationInfo{Reason: "Error", ExitCode: 143},
+			}}},
+			wantCause:   CauseTerminated,
+			wantSummary: "Exit 143: container main stopped on SIGTERM",
+		},
+		{
+			name: "exit 1 with termination message",
+			pod: PodInfo{Node: "node-1", Containers: []ContainerInfo{{
+				Name: "main", State: "Waiting", Reason: "CrashLoopBackOff", RestartCount: 7,
+				LastTermination: &TerminationInfo{Reason: "Error", ExitCode: 1, Message: "missing DATABASE_URL"},
+			}}},
+			wantCause:   CauseAppError,
+			wantSummary: "Exit 1: container main failed with an application error",
+			wantReason:  "Termination message: missing DATABASE_URL",
+		},
+		{
+			name: "init container exit 1",
+			pod: PodInfo{Node: "node-1",
+				InitContainers: []ContainerInfo{{Name: "migrate", State: "Terminated", Reason: "Error", ExitCode: exit(1)}},
+				Containers:     []ContainerInfo{{Name: "main", State: "Waiting", Reason: "PodInitializing"}},
+			},
+			wantCause:   CauseAppError,
+			wantSummary: "Exit 1: container migrate failed with an application error",
+		},
+		{
+			name: "image tag not found",
+			pod: PodInfo{Node: "node-1", Containers: []ContainerInfo{{
+				Name: "main", Image: "docker.io/library/nginx:1.99", State: "Waiting", Reason: "ImagePullBackOff",
+				Message: `Back-off pulling image "docker.io/library/nginx:1.99"`,
+			}}},
+			events: []EventInfo{{
+				Type: "Warning", Reason: "Failed",
+				Message: `Failed to pull image "docker.io/library/nginx:1.99": rpc error: code = NotFound desc = docker.io/library/nginx:1.99: not found`,
+			}},
+			wantCause:   CauseImagePull,
+			wantSummary: "ImagePullBackOff: image tag not found",
+			wantReason:  "not found",
+		},
+		{
+			name: "image pull unauthorized",
+			pod: PodInfo{Node: "node-1", Containers: []ContainerInfo{{
+				Name: "main", Image: "ghcr.io/org/private:v1", State: "Waiting", Reason: "ErrImagePull",
+				Message: "failed to authorize: failed to fetch anonymous token: unexpected status: 401 Unauthorized",
+			}}},
+			wantCause:   CauseImagePull,
+			wantSummary: "ErrImagePull: registry denied access",
+			wantReason:  "imagePullSecrets",
+		},
+		{
+			name: "insufficient memory",
+			pod: PodInfo{Status: "Pending", Containers: []ContainerInfo{
+				{Name: "main", Resources: ResourceRequirements{CPURequest: "500m", MemoryRequest: "3Gi"}},
+				{Name: "sidecar", Resources: ResourceRequirements{CPURequest: "100m", MemoryRequest: "1Gi"}},
+			}},
+			events: []EventInfo{{
+				Type: "Warning", Reason: "FailedScheduling",
+				Message: "0/3 nodes are available: 3 Insufficient memory. preemption: 0/3 nodes are available: 3 No preemption victims found for incoming pod.",
+			}},
+			wantCause:   CauseUnschedulable,
+			wantSummary: "FailedScheduling: no node has enough memory for requests of memory 4Gi",
+			wantReason:  "3 Insufficient memory",
+		},
+		{
+			name: "insufficient cpu and memory",
+			pod: PodInfo{Status: "Pending", Containers: []ContainerInfo{
+				{Name: "main", Resources: ResourceRequirements{CPURequest: "2", MemoryRequest: "512Mi"}},
+			}},
+			events: []EventInfo{{
+				Type: "Warning", Reason: "FailedScheduling",
+				Message: "0/3 nodes are available: 1 Insufficient cpu, 2 Insufficient memory.",
+			}},
+			wantCause:   CauseUnschedulable,
+			wantSummary: "FailedScheduling: no node has enough cpu and memory for requests of cpu 2, memory 512Mi",
+		},
+		{
+			name: "scheduling failure not about resources",
+			pod:  PodInfo{Status: "Pending"},
+			events: []EventInfo{{
+				Type: "Warning", Reason: "FailedScheduling",
+				Message: "0/3 nodes are available: 3 node(s) didn't match Pod's node affinity/selector.",
+			}},
+		},
+		{
+			name: "liveness probe explains exit 137",
+			pod: PodInfo{Node: "node-1", Containers: []ContainerInfo{
+				{Name: "sidecar", State: "Running", Ready: true, LivenessProbe: &ProbeInfo{Type: "TCP", Port: 15020}},
+				{
+					Name: "main", State: "Running", RestartCount: 5, LivenessProbe: httpProbe,
+					LastTermination: &TerminationInfo{Reason: "Error", ExitCode: 137},
+				},
+			}},
+			events: []EventInfo{
+				{Type: "Warning", Reason: "Unhealthy", Count: 12, Message: "Liveness probe failed: HTTP probe failed with statuscode: 500"},
+				{Type: "Normal", Reason: "Killing", Message: "Container main failed liveness probe, will be restarted"},
+			},
+			wantCause:   CauseProbeFailure,
+			wantSummary: "Liveness probe failing on /health:8080",
+			wantReason:  "comes from the probe kill",
+		},
+		{
+			name: "readiness probe failing",
+			pod: PodInfo{Node: "node-1", Containers: []ContainerInfo{
+				{Name: "main", State: "Running", ReadinessProbe: &ProbeInfo{Type: "HTTP", Path: "/ready", Port: 8080}},
+			}},
+			events: []EventInfo{
+				{Type: "Warning", Reason: "Unhealthy", Message: "Readiness probe failed: Get \"http://10.0.0.5:8080/ready\": connection refused"},
+			},
+			wantCause:   CauseProbeFailure,
+			wantSummary: "Readiness probe failing on /ready:8080",
+			wantReason:  "removed from Service endpoints",
+		},
+		{
+			name: "recovered from readiness failures",
+			pod: PodInfo{Node: "node-1", Containers: []ContainerInfo{
+				{Name: "main", State: "Running", Ready: true, ReadinessProbe: &ProbeInfo{Type: "HTTP", Path: "/ready", Port: 8080}},
+			}},
+			events: []EventInfo{
+				{Type: "Warning", Reason: "Unhealthy", Message: "Readiness probe failed: connection refused"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiagnoseCrash(&tt.pod, tt.events)
+			if tt.wantCause == "" {
+				if got != nil {
+					t.Fatalf("DiagnoseCrash() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("DiagnoseCrash() = nil, want a diagnosis")
+			}
+			if got.Cause != tt.wantCause {
+				t.Errorf("Cause = %q, want %q", got.Cause, tt.wantCause)
+			}
+			if got.Summary != tt.wantSummary {
+				t.Errorf("Summary = %q, want %q", got.Summary, tt.wantSummary)
+			}
+			if reasoning := strings.Join(got.Reasoning, "\n"); !strings.Contains(reasoning, tt.wantReason) {
+				t.Errorf("Reasoning missing %q:\n%s", tt.wantReason, reasoning)
+			}
+		})
+	}
+}
+
+func TestDiagnoseCrash_NilPod(t *testing.T) {
+	if got := DiagnoseCrash(nil, nil); got != nil {
+		t.Errorf("DiagnoseCrash(nil) = %+v, want nil", got)
+	}
+}
+
+func TestProbeTarget(t *testing.T) {
+	tests := []struct {
+		probe *ProbeInfo
+		want  string
+	}{
+		{&ProbeInfo{Type: "HTTP", Path: "/health", Port: 8080}, "/health:8080"},
+		{&ProbeInfo{Type: "TCP", Port: 5432}, "tcp:5432"},
+		{&ProbeInfo{Type: "gRPC", Port: 9090}, "grpc:9090"},
+		{&ProbeInfo{Type: "Exec", Command: []string{"cat", "/tmp/healthy"}}, "exec cat /tmp/healthy"},
+	}
+	for _, tt := range tests {
+		if got := probeTarget(tt.probe); got != tt.want {
+			t.Errorf("probeTarget(%+v) = %q, want %q", tt.probe, got, tt.want)
+		}
+	}
+}
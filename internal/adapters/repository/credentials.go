@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// credentialRefreshInterval is how long rebuilt credentials are reused for
+// further 401 responses before the kubeconfig is read again, so that a burst
+// of failing requests runs the credential plugin once.
+const credentialRefreshInterval = 10 * time.Second
+
+// authFailureThreshold is how many core API requests in a row must be
+// rejected, even after re-authenticating, before AuthFailure reports it.
+// A single rejection may be a glitch, and aggregated APIs such as
+// metrics.k8s.io may reject credentials the core API accepts.
+const authFailureThreshold = 3
+
+// credentialRefresher recovers from expired or rotated credentials, such as
+// an OIDC token that expired mid-session. A request rejected with 401
+// Unauthorized is retried once with credentials rebuilt from the kubeconfig,
+// which re-runs exec credential plugins and picks up tokens rewritten on
+// disk. Later requests keep using the rebuilt credentials.
+type credentialRefresher struct {
+	rebuild func() (*rest.Config, error) // Reloads the config; nil when it did not come from a kubeconfig
+
+	refreshMu   sync.Mutex        // Held while rebuilding, so concurrent 401s rebuild once
+	mu          sync.Mutex        // Guards the fields below
+	fresh       http.RoundTripper // Transport with the rebuilt credentials; nil until the first refresh
+	generation  int               // Counts refreshes, to tell whether a request already used fresh
+	lastRefresh time.Time
+	rejections  int   // Core API requests rejected in a row after re-authenticating
+	failure     error // Why re-authenticating did not help; nil once a core API request succeeds
+
+	retrying atomic.Int32 // Requests being retried with rebuilt credentials
+}
+
+// wrap is a rest.Config WrapTransport that retries 401 responses with
+// rebuilt credentials. It sits below the authentication round trippers, so
+// it sees requests with the stale credentials already set.
+func (r *credentialRefresher) wrap(rt http.RoundTripper) http.RoundTripper {
+	return credentialTransport{refresher: r, next: rt}
+}
+
+// transport returns the transport with rebuilt credentials and its
+// generation, rebuilding them unless that was done in the last
+// credentialRefreshInterval.
+func (r *credentialRefresher) transport() (http.RoundTripper, int, error) {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+	r.mu.Lock()
+	fresh, generation, lastRefresh := r.fresh, r.generation, r.lastRefresh
+	r.mu.Unlock()
+	if fresh != nil && time.Since(lastRefresh) < credentialRefreshInterval {
+		return fresh, generation, nil
+	}
+
+	config, err := r.rebuild()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to reload kubeconfig: %w", err)
+	}
+	config = rest.CopyConfig(config)
+	config.Timeout = 30 * time.Second
+	fresh, err = rest.TransportFor(config)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to rebuild credentials: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fresh = fresh
+	r.generation++
+	r.lastRefresh = time.Now()
+	return fresh, r.generation, nil
+}
+
+// current returns the transport with rebuilt credentials and its
+// generation, or nil before the first refresh.
+func (r *credentialRefresher) current() (http.RoundTripper, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.fresh, r.generation
+}
+
+// reject records a request the cluster rejected even after
+// re-authenticating, or whose credentials could not be rebuilt. Only core
+// API requests count towards the failure.
+func (r *credentialRefresher) reject(req *http.Request, err error) {
+	if !isCoreAPI(req) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rejections++
+	if r.rejections >= authFailureThreshold {
+		r.failure = err
+	}
+}
+
+// accept records a request the cluster did not reject. A core API request
+// clears the failure.
+func (r *credentialRefresher) accept(req *http.Request) {
+	if !isCoreAPI(req) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rejections = 0
+	r.failure = nil
+}
+
+// isCoreAPI reports whether req is for the core API group (/api/v1), as
+// opposed to a named group under /apis that may be served by an
+// aggregated API server with its own authentication.
+func isCoreAPI(req *http.Request) bool {
+	return req.URL.Path == "/api" || strings.HasPrefix(req.URL.Path, "/api/")
+}
+
+// credentialTransport is the http.RoundTripper installed by credentialRefresher.
+type credentialTransport struct {
+	refresher *credentialRefresher
+	next      http.RoundTripper
+}
+
+func (t credentialTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := t.refresher
+	var resp *http.Response
+	var err error
+	fresh, used := r.current()
+	if fresh != nil {
+		resp, err = fresh.RoundTrip(withoutCredentials(req, req.Body))
+	} else {
+		resp, err = t.next.RoundTrip(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		r.accept(req)
+		return resp, nil
+	}
+	// Only retry requests whose body can be sent again
+	if r.rebuild == nil || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	// Exec credential plugins run on the retry, not while rebuilding
+	r.retrying.Add(1)
+	defer r.retrying.Add(-1)
+	fresh, generation, err := r.transport()
+	if err != nil {
+		r.reject(req, err)
+		return resp, nil
+	}
+	if generation == used {
+		// Already sent with these credentials
+		r.reject(req, fmt.Errorf("the cluster rejected the credentials again after re-authenticating (%s)", resp.Status))
+		return resp, nil
+	}
+	var body io.ReadCloser
+	if req.GetBody != nil {
+		if body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	resp, err = fresh.RoundTrip(withoutCredentials(req, body))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		r.reject(req, fmt.Errorf("the cluster rejected the credentials again after re-authenticating (%s)", resp.Status))
+	} else {
+		r.accept(req)
+	}
+	return resp, nil
+}
+
+// withoutCredentials clones req with body and without the Authorization
+// header, which the rebuilt transport would otherwise leave as it is.
+func withoutCredentials(req *http.Request, body io.ReadCloser) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	clone.Header.Del("Authorization")
+	return clone
+}
+
+// Reauthenticating reports whether credentials are being rebuilt after the
+// cluster rejected them, e.g. while an exec credential plugin runs.
+func (c *Client) Reauthenticating() bool {
+	return c.credentials != nil && c.credentials.retrying.Load() > 0
+}
+
+// AuthFailure returns why re-authenticating did not help, either because the
+// kubeconfig could not be reloaded or because the cluster still rejected the
+// credentials, once that happened to several core API requests in a row.
+// Rejections by other API groups leave it nil; their callers get the error.
+// It is nil again once a core API request succeeds.
+func (c *Client) AuthFailure() error {
+	if c.credentials == nil {
+		return nil
+	}
+	c.credentials.mu.Lock()
+	defer c.credentials.mu.Unlock()
+	return c.credentials.failure
+}
+
+// RetryAuth clears the authentication failure so that the next rejected
+// request rebuilds the credentials right away, e.g. after the user logged in
+// again outside k1s.
+func (c *Client) RetryAuth() {
+	if c.credentials == nil {
+		return
+	}
+	c.credentials.mu.Lock()
+	defer c.credentials.mu.Unlock()
+	c.credentials.failure = nil
+	c.credentials.rejections = 0
+	c.credentials.lastRefresh = time.Time{}
+}
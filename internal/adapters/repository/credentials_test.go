@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// unauthorizedStatus is the body of a 401 response from the API server.
+const unauthorizedStatus = `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Unauthorized","code":401}`
+
+// tokenServer answers requests with a bearer token in valid and rejects
+// any other with 401 Unauthorized, counting requests and recording the
+// bodies it received.
+func tokenServer(t *testing.T, valid *atomic.Value, requests *atomic.Int32, bodies *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if bodies != nil {
+			body, _ := io.ReadAll(r.Body)
+			*bodies = append(*bodies, string(body))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer "+valid.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(unauthorizedStatus))
+			return
+		}
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"kind":"SelfSubjectAccessReview","apiVersion":"authorization.k8s.io/v1","status":{"allowed":true}}`))
+			return
+		}
+		w.Write([]byte(`{"kind":"NamespaceList","apiVersion":"v1","items":[{"metadata":{"name":"default"},"status":{"phase":"Active"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// writeTokenKubeconfig writes a kubeconfig for server, a TLS test server,
+// whose "dev" context authenticates with the token "dev-token". Tokens are
+// only sent over TLS.
+func writeTokenKubeconfig(t *testing.T, server string) string {
+	t.Helper()
+	path := writeKubeconfig(t, t.TempDir(), "config", "dev", server, "dev")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read kubeconfig: %v", err)
+	}
+	insecure := strings.Replace(string(content), "    server: ", "    insecure-skip-tls-verify: true\n    server: ", 1)
+	if err := os.WriteFile(path, []byte(insecure), 0600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	return path
+}
+
+// rotateToken rewrites the token of a kubeconfig written by writeKubeconfig,
+// the way an OIDC login refreshes it on disk.
+func rotateToken(t *testing.T, path, token string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read kubeconfig: %v", err)
+	}
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "token:") {
+			lines[i] = "    token: " + token
+		}
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+}
+
+func TestClient_RetriesWithRotatedToken(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("dev-token")
+	var requests atomic.Int32
+	server := tokenServer(t, &valid, &requests, nil)
+	path := writeTokenKubeconfig(t, server.URL)
+
+	client, err := NewClientFor(path, "")
+	if err != nil {
+		t.Fatalf("NewClientFor() error = %v", err)
+	}
+	ctx := context.Background()
+	if _, err := client.ListNamespaces(ctx); err != nil {
+		t.Fatalf("ListNamespaces() error = %v", err)
+	}
+
+	// The token expires and a new one is written to the kubeconfig
+	valid.Store("renewed-token")
+	rotateToken(t, path, "renewed-token")
+	requests.Store(0)
+	namespaces, err := client.ListNamespaces(ctx)
+	if err != nil {
+		t.Fatalf("ListNamespaces() after rotation error = %v, want a retry with the new token", err)
+	}
+	if len(namespaces) != 1 || requests.Load() != 2 {
+		t.Errorf("namespaces = %v after %d requests, want the 401 and one retry", namespaces, requests.Load())
+	}
+	if err := client.AuthFailure(); err != nil {
+		t.Errorf("AuthFailure() = %v, want nil after a successful retry", err)
+	}
+
+	// Later requests use the rebuilt credentials right away
+	requests.Store(0)
+	if _, err := client.ListNamespaces(ctx); err != nil || requests.Load() != 1 {
+		t.Errorf("ListNamespaces() = %v after %d requests, want one request", err, requests.Load())
+	}
+}
+
+func TestClient_RetryReplaysBody(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("dev-token")
+	var requests atomic.Int32
+	var bodies []string
+	server := tokenServer(t, &valid, &requests, &bodies)
+	path := writeTokenKubeconfig(t, server.URL)
+
+	client, err := NewClientFor(path, "")
+	if err != nil {
+		t.Fatalf("NewClientFor() error = %v", err)
+	}
+	valid.Store("renewed-token")
+	rotateToken(t, path, "renewed-token")
+
+	allowed, _, err := CanI(context.Background(), client.Clientset(), "shop", "get", "", "pods")
+	if err != nil || !allowed {
+		t.Fatalf("CanI() = %v, %v, want allowed after the retry", allowed, err)
+	}
+	if len(bodies) != 2 || bodies[0] != bodies[1] || !strings.Contains(bodies[1], `"resource":"pods"`) {
+		t.Errorf("bodies = %q, want the review sent twice", bodies)
+	}
+}
+
+func TestClient_AuthFailure(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("nobody")
+	var requests atomic.Int32
+	server := tokenServer(t, &valid, &requests, nil)
+	path := writeTokenKubeconfig(t, server.URL)
+
+	client, err := NewClientFor(path, "")
+	if err != nil {
+		t.Fatalf("NewClientFor() error = %v", err)
+	}
+	ctx := context.Background()
+	if _, err := client.ListNamespaces(ctx); !apierrors.IsUnauthorized(err) {
+		t.Fatalf("ListNamespaces() error = %v, want Unauthorized", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("requests = %d, want one retry", requests.Load())
+	}
+	if client.Reauthenticating() {
+		t.Error("Reauthenticating() = true after the retry finished")
+	}
+	if err := client.AuthFailure(); err != nil {
+		t.Errorf("AuthFailure() = %v after one rejected request, want nil", err)
+	}
+
+	// Within the refresh interval the rebuilt credentials are not rebuilt again
+	requests.Store(0)
+	for i := 1; i < authFailureThreshold; i++ {
+		client.ListNamespaces(ctx)
+	}
+	if want := int32(authFailureThreshold - 1); requests.Load() != want {
+		t.Errorf("requests = %d, want %d without another retry", requests.Load(), want)
+	}
+	if err := client.AuthFailure(); err == nil || !strings.Contains(err.Error(), "rejected the credentials again") {
+		t.Errorf("AuthFailure() = %v, want the rejected retry once core requests keep failing", err)
+	}
+
+	// After logging in again, retrying clears the failure
+	client.RetryAuth()
+	if err := client.AuthFailure(); err != nil {
+		t.Errorf("AuthFailure() after RetryAuth = %v", err)
+	}
+	valid.Store("renewed-token")
+	rotateToken(t, path, "renewed-token")
+	if _, err := client.ListNamespaces(ctx); err != nil {
+		t.Errorf("ListNamespaces() after RetryAuth error = %v", err)
+	}
+}
+
+func TestNewClientFromConfig_NoRebuildWithoutKubeconfig(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("other")
+	var requests atomic.Int32
+	server := tokenServer(t, &valid, &requests, nil)
+
+	client, err := NewClientFromConfig(&rest.Config{
+		Host:            server.URL,
+		BearerToken:     "stale",
+		TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+	}, "")
+	if err != nil {
+		t.Fatalf("NewClientFromConfig() error = %v", err)
+	}
+	_, err = client.Clientset().CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	if !apierrors.IsUnauthorized(err) || requests.Load() != 1 {
+		t.Errorf("List() = %v after %d requests, want Unauthorized without a retry", err, requests.Load())
+	}
+	if client.AuthFailure() != nil {
+		t.Error("AuthFailure() should stay nil when credentials can't be rebuilt")
+	}
+}
+
+func TestClient_AuthFailureIgnoresOtherAPIGroups(t *testing.T) {
+	// An aggregated API rejects the credentials the core API accepts
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/apis/metrics.k8s.io/") {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(unauthorizedStatus))
+			return
+		}
+		w.Write([]byte(`{"kind":"NamespaceList","apiVersion":"v1","items":[]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClientFor(writeTokenKubeconfig(t, server.URL), "")
+	if err != nil {
+		t.Fatalf("NewClientFor() error = %v", err)
+	}
+	ctx := context.Background()
+	for i := 0; i < authFailureThreshold+1; i++ {
+		_, err := client.MetricsClient().MetricsV1beta1().PodMetricses("shop").List(ctx, metav1.ListOptions{})
+		if !apierrors.IsUnauthorized(err) {
+			t.Fatalf("List() error = %v, want Unauthorized", err)
+		}
+	}
+	if err := client.AuthFailure(); err != nil {
+		t.Errorf("AuthFailure() = %v, want nil while the core API accepts the credentials", err)
+	}
+	if _, err := client.ListNamespaces(ctx); err != nil {
+		t.Errorf("ListNamespaces() error = %v", err)
+	}
+}
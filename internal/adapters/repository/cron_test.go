@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronSchedule_Next(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2024, 5, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		schedule string
+		timeZone string
+		want     time.Time
+	}{
+		{"*/15 * * * *", "", time.Date(2024, 5, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 2 * * *", "", time.Date(2024, 5, 16, 2, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", "", time.Date(2024, 5, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 0", "", time.Date(2024, 5, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", "", time.Date(2024, 5, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", "", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", "", time.Date(2024, 5, 15, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", "", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"5,10 8-9/1 * * *", "", time.Date(2024, 5, 16, 8, 5, 0, 0, time.UTC)},
+		// Day of month and day of week are OR'd when both are restricted
+		{"0 0 20 * fri", "", time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 2 29 2 *", "", time.Date(2028, 2, 29, 2, 0, 0, 0, time.UTC)},
+		// 12:00 in São Paulo is 15:00 UTC
+		{"0 12 * * *", "America/Sao_Paulo", time.Date(2024, 5, 15, 15, 0, 0, 0, time.UTC)},
+		{"CRON_TZ=America/Sao_Paulo 0 12 * * *", "", time.Date(2024, 5, 15, 15, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			s, err := ParseCronSchedule(tt.schedule, tt.timeZone)
+			if err != nil {
+				t.Fatalf("ParseCronSchedule() error = %v", err)
+			}
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCronSchedule_Invalid(t *testing.T) {
+	for _, schedule := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "*/0 * * * *", "0 0 * foo *", "5-1 * * * *"} {
+		if _, err := ParseCronSchedule(schedule, ""); err == nil {
+			t.Errorf("ParseCronSchedule(%q) error = nil, want an error", schedule)
+		}
+	}
+	if _, err := ParseCronSchedule("* * * * *", "Nowhere/Nothing"); err == nil {
+		t.Error("ParseCronSchedule() with an unknown time zone error = nil, want an error")
+	}
+}
+
+func TestCronSchedule_NeverFires(t *testing.T) {
+	s, err := ParseCronSchedule("0 0 30 2 *", "")
+	if err != nil {
+		t.Fatalf("ParseCronSchedule() error = %v", err)
+	}
+	if got := s.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next() = %v, want the zero time for February 30th", got)
+	}
+}
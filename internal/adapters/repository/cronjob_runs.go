@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// JobRun is one run of a CronJob: a Job it created, or one triggered from
+// it by hand.
+type JobRun struct {
+	Name           string
+	Status         string // Running, Complete, Failed or Suspended
+	StartTime      time.Time
+	Age            string        // Since the run started, e.g. "5m"
+	CompletionTime time.Time     // Zero until the Job completes or fails
+	Duration       time.Duration // So far, while running
+	Succeeded      int32
+	Failed         int32
+	Manual         bool   // Created with "Trigger now" or kubectl create job --from
+	Pod            string // Newest pod of the Job, "" once it was deleted (e.g. by ttlSecondsAfterFinished)
+}
+
+// IsFailed reports whether the run failed.
+func (r JobRun) IsFailed() bool {
+	return r.Status == "Failed"
+}
+
+// CronJobSchedule is when a CronJob runs.
+type CronJobSchedule struct {
+	Schedule           string
+	TimeZone           string // spec.timeZone, "" for the controller's
+	Suspended          bool
+	LastScheduleTime   time.Time
+	LastSuccessfulTime time.Time
+	NextScheduleTime   time.Time // Zero while suspended or when the schedule can't be parsed
+	ScheduleError      string    // Why NextScheduleTime could not be computed
+}
+
+// GetCronJobRuns returns the Jobs owned by a CronJob, newest first, with
+// the newest pod of each. Pods already deleted leave Pod empty.
+func GetCronJobRuns(ctx context.Context, clientset kubernetes.Interface, namespace, name string) ([]JobRun, error) {
+	jobs, err := clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: jobNameLabel})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	newestPod := make(map[string]*corev1.Pod)
+	for i := range pods.Items {
+		p := &pods.Items[i]
+		job := p.Labels[jobNameLabel]
+		if cur, ok := newestPod[job]; !ok || p.CreationTimestamp.After(cur.CreationTimestamp.Time) {
+			newestPod[job] = p
+		}
+	}
+
+	var runs []JobRun
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		owner := metav1.GetControllerOf(job)
+		if owner == nil || owner.Kind != "CronJob" || owner.Name != name {
+			continue
+		}
+		run := jobToRun(job, time.Now())
+		if p, ok := newestPod[job.Name]; ok {
+			run.Pod = p.Name
+		}
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartTime.After(runs[j].StartTime)
+	})
+	return runs, nil
+}
+
+// jobToRun describes a Job as a run, measuring a running one up to now.
+func jobToRun(job *batchv1.Job, now time.Time) JobRun {
+	run := JobRun{
+		Name:      job.Name,
+		Status:    "Running",
+		StartTime: job.CreationTimestamp.Time,
+		Succeeded: job.Status.Succeeded,
+		Failed:    job.Status.Failed,
+		Manual:    job.Annotations["cronjob.kubernetes.io/instantiate"] == "manual",
+	}
+	if job.Status.StartTime != nil {
+		run.StartTime = job.Status.StartTime.Time
+	}
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			run.Status = "Complete"
+			run.CompletionTime = c.LastTransitionTime.Time
+		case batchv1.JobFailed:
+			run.Status = "Failed"
+			run.CompletionTime = c.LastTransitionTime.Time
+		case batchv1.JobSuspended:
+			if run.Status == "Running" {
+				run.Status = "Suspended"
+			}
+		}
+	}
+	if job.Status.CompletionTime != nil {
+		run.CompletionTime = job.Status.CompletionTime.Time
+	}
+
+	end := now
+	if !run.CompletionTime.IsZero() {
+		end = run.CompletionTime
+	}
+	run.Age = formatAge(run.StartTime)
+	if !run.StartTime.IsZero() && end.After(run.StartTime) {
+		run.Duration = end.Sub(run.StartTime)
+	}
+	return run
+}
+
+// GetCronJobSchedule returns the schedule of a CronJob with its last
+// schedule time from the status and the next one computed from the cron
+// spec.
+func GetCronJobSchedule(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (*CronJobSchedule, error) {
+	cronJob, err := clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cronjob: %w", err)
+	}
+	return cronJobSchedule(cronJob, time.Now()), nil
+}
+
+func cronJobSchedule(cronJob *batchv1.CronJob, now time.Time) *CronJobSchedule {
+	s := &CronJobSchedule{
+		Schedule:  cronJob.Spec.Schedule,
+		Suspended: cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+	}
+	if cronJob.Spec.TimeZone != nil {
+		s.TimeZone = *cronJob.Spec.TimeZone
+	}
+	if t := cronJob.Status.LastScheduleTime; t != nil {
+		s.LastScheduleTime = t.Time
+	}
+	if t := cronJob.Status.LastSuccessfulTime; t != nil {
+		s.LastSuccessfulTime = t.Time
+	}
+
+	schedule, err := ParseCronSchedule(s.Schedule, s.TimeZone)
+	if err != nil {
+		s.ScheduleError = err.Error()
+		return s
+	}
+	if !s.Suspended {
+		s.NextScheduleTime = schedule.Next(now)
+	}
+	return s
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func cronJobRun(name, cronJob string, start time.Time, condition batchv1.JobConditionType) *batchv1.Job {
+	controller := true
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(start),
+			OwnerReferences: []metav1.OwnerReference{{
+				Kind: "CronJob", Name: cronJob, Controller: &controller,
+			}},
+		},
+		Status: batchv1.JobStatus{StartTime: &metav1.Time{Time: start}},
+	}
+	if condition != "" {
+		end := start.Add(90 * time.Second)
+		job.Status.Conditions = []batchv1.JobCondition{{
+			Type: condition, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(end),
+		}}
+		if condition == batchv1.JobComplete {
+			job.Status.Succeeded = 1
+			job.Status.CompletionTime = &metav1.Time{Time: end}
+		} else {
+			job.Status.Failed = 3
+		}
+	}
+	return job
+}
+
+func jobPod(name, job string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: name, Namespace: "default", Labels: map[string]string{jobNameLabel: job},
+	}}
+}
+
+func TestGetCronJobRuns(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	manual := cronJobRun("nightly-report-manual-abcde", "nightly-report", now.Add(-time.Minute), "")
+	manual.Annotations = map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+
+	clientset := fake.NewSimpleClientset(
+		cronJobRun("nightly-report-1", "nightly-report", now.Add(-48*time.Hour), batchv1.JobComplete),
+		cronJobRun("nightly-report-2", "nightly-report", now.Add(-24*time.Hour), batchv1.JobFailed),
+		manual,
+		cronJobRun("other-1", "other", now.Add(-time.Hour), batchv1.JobComplete),
+		// The pod of nightly-report-1 was deleted by ttlSecondsAfterFinished
+		jobPod("nightly-report-2-xyz", "nightly-report-2"),
+		jobPod("nightly-report-manual-abcde-q1", "nightly-report-manual-abcde"),
+	)
+
+	runs, err := GetCronJobRuns(ctx, clientset, "default", "nightly-report")
+	if err != nil {
+		t.Fatalf("GetCronJobRuns() error = %v", err)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("GetCronJobRuns() returned %d runs, want 3", len(runs))
+	}
+
+	if runs[0].Name != "nightly-report-manual-abcde" || runs[0].Status != "Running" || !runs[0].Manual {
+		t.Errorf("runs[0] = %+v, want the running manual run first", runs[0])
+	}
+	if runs[0].Pod != "nightly-report-manual-abcde-q1" {
+		t.Errorf("runs[0].Pod = %q, want nightly-report-manual-abcde-q1", runs[0].Pod)
+	}
+
+	failed := runs[1]
+	if failed.Name != "nightly-report-2" || !failed.IsFailed() || failed.Failed != 3 || failed.Pod != "nightly-report-2-xyz" {
+		t.Errorf("runs[1] = %+v, want the failed run with its pod", failed)
+	}
+	if failed.Duration != 90*time.Second {
+		t.Errorf("runs[1].Duration = %v, want 1m30s", failed.Duration)
+	}
+
+	done := runs[2]
+	if done.Status != "Complete" || done.Succeeded != 1 || done.Pod != "" {
+		t.Errorf("runs[2] = %+v, want a complete run whose pod was deleted", done)
+	}
+}
+
+func TestCronJobSchedule(t *testing.T) {
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	last := metav1.NewTime(now.Add(-8 * time.Hour))
+
+	cronJob := reportCronJob(false)
+	cronJob.Status.LastScheduleTime = &last
+	s := cronJobSchedule(cronJob, now)
+	if s.Schedule != "0 2 * * *" || !s.LastScheduleTime.Equal(last.Time) {
+		t.Errorf("cronJobSchedule() = %+v", s)
+	}
+	if want := time.Date(2024, 5, 16, 2, 0, 0, 0, time.UTC); !s.NextScheduleTime.Equal(want) {
+		t.Errorf("NextScheduleTime = %v, want %v", s.NextScheduleTime, want)
+	}
+
+	if s := cronJobSchedule(reportCronJob(true), now); !s.Suspended || !s.NextScheduleTime.IsZero() {
+		t.Errorf("suspended cronJobSchedule() = %+v, want no next time", s)
+	}
+
+	bad := reportCronJob(false)
+	bad.Spec.Schedule = "every day"
+	if s := cronJobSchedule(bad, now); s.ScheduleError == "" {
+		t.Error("cronJobSchedule() with an invalid schedule has no ScheduleError")
+	}
+}
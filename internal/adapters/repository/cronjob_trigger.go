@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+)
+
+// jobNameLabel is set by the Job controller on the pods of a Job. The
+// unprefixed label is set by every Kubernetes version.
+const jobNameLabel = "job-name"
+
+// TriggerCronJob runs a CronJob now by creating a Job from its jobTemplate,
+// like kubectl create job --from=cronjob/NAME. The Job is owned by the
+// CronJob, so it is cleaned up with the CronJob's history, and is named
+// <cronjob>-manual-<random>. Suspended CronJobs are triggered too; suspend
+// only stops scheduled runs.
+// Returns the name of the created Job.
+func TriggerCronJob(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (string, error) {
+	cronJob, err := clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get cronjob: %w", err)
+	}
+
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+	controller := true
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        manualJobName(name),
+			Namespace:   namespace,
+			Labels:      cronJob.Spec.JobTemplate.Labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: batchv1.SchemeGroupVersion.String(),
+				Kind:       "CronJob",
+				Name:       cronJob.Name,
+				UID:        cronJob.UID,
+				Controller: &controller,
+			}},
+		},
+		Spec: cronJob.Spec.JobTemplate.Spec,
+	}
+
+	created, err := clientset.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create job: %w", err)
+	}
+	return created.Name, nil
+}
+
+// manualJobName returns <cronjob>-manual-<random>, shortening the CronJob
+// name so the Job name still fits in the job-name label of its pods.
+func manualJobName(cronJob string) string {
+	suffix := "-manual-" + rand.String(5)
+	if limit := 63 - len(suffix); len(cronJob) > limit {
+		cronJob = cronJob[:limit]
+	}
+	return cronJob + suffix
+}
+
+// GetJobPod returns the newest pod of a Job, or nil if the Job controller
+// has not created one yet.
+func GetJobPod(ctx context.Context, clientset kubernetes.Interface, namespace, jobName string) (*PodInfo, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: jobNameLabel + "=" + jobName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	if len(pods.Items) == 0 {
+		return nil, nil
+	}
+	newest := &pods.Items[0]
+	for i := range pods.Items {
+		if pods.Items[i].CreationTimestamp.After(newest.CreationTimestamp.Time) {
+			newest = &pods.Items[i]
+		}
+	}
+	info := podToPodInfo(newest)
+	return &info, nil
+}
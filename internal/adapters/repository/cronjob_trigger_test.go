@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func reportCronJob(suspend bool) *batchv1.CronJob {
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly-report", Namespace: "default", UID: "cj-uid"},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "0 2 * * *",
+			Suspend:  &suspend,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": "report"},
+					Annotations: map[string]string{"team": "data"},
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers:    []corev1.Container{{Name: "report", Image: "report:1.0"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTriggerCronJob(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset(reportCronJob(false))
+
+	name, err := TriggerCronJob(ctx, clientset, "default", "nightly-report")
+	if err != nil {
+		t.Fatalf("TriggerCronJob() error = %v", err)
+	}
+	if !strings.HasPrefix(name, "nightly-report-manual-") || len(name) != len("nightly-report-manual-")+5 {
+		t.Errorf("job name = %q, want nightly-report-manual-<5 chars>", name)
+	}
+
+	job, err := clientset.BatchV1().Jobs("default").Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("job not created: %v", err)
+	}
+	if job.Labels["app"] != "report" {
+		t.Errorf("Labels = %v, want the jobTemplate labels", job.Labels)
+	}
+	if job.Annotations["cronjob.kubernetes.io/instantiate"] != "manual" || job.Annotations["team"] != "data" {
+		t.Errorf("Annotations = %v, want instantiate=manual and the jobTemplate annotations", job.Annotations)
+	}
+	owner := metav1.GetControllerOf(job)
+	if owner == nil || owner.Kind != "CronJob" || owner.Name != "nightly-report" || owner.UID != "cj-uid" || owner.APIVersion != "batch/v1" {
+		t.Errorf("controller = %+v, want the CronJob", owner)
+	}
+	if job.Spec.Template.Spec.Containers[0].Image != "report:1.0" {
+		t.Error("job spec should come from the jobTemplate")
+	}
+}
+
+func TestTriggerCronJob_Suspended(t *testing.T) {
+	clientset := fake.NewSimpleClientset(reportCronJob(true))
+	if _, err := TriggerCronJob(context.Background(), clientset, "default", "nightly-report"); err != nil {
+		t.Fatalf("TriggerCronJob() on a suspended CronJob error = %v", err)
+	}
+	jobs, _ := clientset.BatchV1().Jobs("default").List(context.Background(), metav1.ListOptions{})
+	if len(jobs.Items) != 1 {
+		t.Errorf("created %d jobs, want 1", len(jobs.Items))
+	}
+}
+
+func TestTriggerCronJob_NotFound(t *testing.T) {
+	if _, err := TriggerCronJob(context.Background(), fake.NewSimpleClientset(), "default", "missing"); err == nil {
+		t.Error("TriggerCronJob() on a missing CronJob should fail")
+	}
+}
+
+func TestManualJobName_Truncates(t *testing.T) {
+	name := manualJobName(strings.Repeat("a", 80))
+	if len(name) != 63 || !strings.Contains(name, "-manual-") {
+		t.Errorf("manualJobName() = %q (%d chars), want 63 chars", name, len(name))
+	}
+}
+
+func TestGetJobPod(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	jobPod := func(name string, created time.Time) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "default",
+			Labels:            map[string]string{"job-name": "report-manual-abcde"},
+			CreationTimestamp: metav1.NewTime(created),
+		}}
+	}
+	clientset := fake.NewSimpleClientset(
+		jobPod("report-manual-abcde-old", now.Add(-time.Minute)),
+		jobPod("report-manual-abcde-new", now),
+	)
+
+	pod, err := GetJobPod(ctx, clientset, "default", "report-manual-abcde")
+	if err != nil {
+		t.Fatalf("GetJobPod() error = %v", err)
+	}
+	if pod == nil || pod.Name != "report-manual-abcde-new" {
+		t.Errorf("GetJobPod() = %+v, want the newest pod", pod)
+	}
+
+	pod, err = GetJobPod(ctx, clientset, "default", "other")
+	if err != nil || pod != nil {
+		t.Errorf("GetJobPod() for a job without pods = %+v, %v, want nil", pod, err)
+	}
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DaemonSetNodeMissing is the Status of a node the DaemonSet has no pod on.
+const DaemonSetNodeMissing = "Missing"
+
+// DaemonSetNodes is the rollout of a DaemonSet node by node.
+type DaemonSetNodes struct {
+	Name        string
+	Namespace   string
+	Desired     int32           // Nodes the controller means to run a pod on
+	Ready       int32           // Of those, nodes whose pod is ready
+	Nodes       []DaemonSetNode // Unhealthy nodes first, then by name
+	NotTargeted int             // Nodes left out by the nodeSelector or node affinity
+}
+
+// DaemonSetNode is the pod of a DaemonSet on one node, or why the node has
+// none.
+type DaemonSetNode struct {
+	Node     string
+	Cordoned bool
+	Pod      string // "" when the node has no pod
+	Ready    bool
+	Restarts int32
+	Status   string // As getPodStatus, e.g. CrashLoopBackOff; DaemonSetNodeMissing without a pod
+	Reason   string // Why the pod is not scheduled, from FailedScheduling events or the node's taints
+}
+
+// Healthy reports whether the node runs a ready pod of the DaemonSet.
+func (n DaemonSetNode) Healthy() bool {
+	return n.Pod != "" && n.Ready
+}
+
+// GetDaemonSetNodes fetches a DaemonSet with its pods, the nodes of the
+// cluster and the scheduling failures of its pods, and joins them per
+// node.
+func GetDaemonSetNodes(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (*DaemonSetNodes, error) {
+	ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daemonset: %w", err)
+	}
+
+	var pods []corev1.Pod
+	if ds.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %w", err)
+		}
+		podList, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		pods = podList.Items
+	}
+
+	nodeList, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	// Scheduling failures are reported on the pending pods, and by older
+	// controllers on the DaemonSet itself; without them the gaps are still
+	// listed, with the reason worked out from the nodes
+	var events []corev1.Event
+	for _, selector := range []string{
+		"reason=FailedScheduling",
+		"involvedObject.kind=DaemonSet,involvedObject.name=" + name,
+	} {
+		list, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+		if err == nil {
+			events = append(events, list.Items...)
+		}
+	}
+
+	return BuildDaemonSetNodes(ds, pods, nodeList.Items, events), nil
+}
+
+// BuildDaemonSetNodes joins the pods of a DaemonSet with the nodes they
+// run on. Pods are matched to nodes by spec.nodeName, or for pods not yet
+// scheduled by the node affinity the controller pins them with. Nodes the
+// nodeSelector or required node affinity leave out are only counted; the
+// others without a pod are listed as DaemonSetNodeMissing, with the reason
+// from a FailedScheduling or FailedPlacement event naming the node, or the
+// taint the pods don't tolerate.
+func BuildDaemonSetNodes(ds *appsv1.DaemonSet, pods []corev1.Pod, nodes []corev1.Node, events []corev1.Event) *DaemonSetNodes {
+	result := &DaemonSetNodes{
+		Name:      ds.Name,
+		Namespace: ds.Namespace,
+		Desired:   ds.Status.DesiredNumberScheduled,
+		Ready:     ds.Status.NumberReady,
+	}
+
+	podByNode := make(map[string]*corev1.Pod)
+	for i := range pods {
+		pod := &pods[i]
+		node := podNodeName(pod)
+		if node == "" {
+			continue
+		}
+		// Prefer the pod that is not being replaced
+		if current, ok := podByNode[node]; ok && current.DeletionTimestamp == nil {
+			continue
+		}
+		podByNode[node] = pod
+	}
+
+	// The latest scheduling failure of each pod, and the DaemonSet's own
+	// placement failures by node
+	podFailures := make(map[string]corev1.Event)
+	var placementFailures []corev1.Event
+	for _, e := range events {
+		switch {
+		case e.Reason == "FailedScheduling" && e.InvolvedObject.Kind == "Pod":
+			if prev, ok := podFailures[e.InvolvedObject.Name]; !ok || eventTime(e).After(eventTime(prev)) {
+				podFailures[e.InvolvedObject.Name] = e
+			}
+		case e.InvolvedObject.Kind == "DaemonSet" && e.InvolvedObject.Name == ds.Name &&
+			(e.Reason == "FailedPlacement" || e.Reason == "FailedDaemonPod"):
+			placementFailures = append(placementFailures, e)
+		}
+	}
+
+	spec := ds.Spec.Template.Spec
+	var affinity *corev1.NodeSelector
+	if spec.Affinity != nil && spec.Affinity.NodeAffinity != nil {
+		affinity = spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	}
+
+	for i := range nodes {
+		node := &nodes[i]
+		row := DaemonSetNode{Node: node.Name, Cordoned: node.Spec.Unschedulable}
+
+		if pod, ok := podByNode[node.Name]; ok {
+			row.Pod = pod.Name
+			row.Ready = isPodReady(pod)
+			row.Status = getPodStatus(pod)
+			for _, cs := range pod.Status.ContainerStatuses {
+				row.Restarts += cs.RestartCount
+			}
+			if pod.Spec.NodeName == "" {
+				if e, ok := podFailures[pod.Name]; ok {
+					row.Reason = e.Message
+				}
+			}
+			result.Nodes = append(result.Nodes, row)
+			continue
+		}
+
+		if !labelsMatch(spec.NodeSelector, node.Labels) || !nodeMatchesSelector(affinity, node) {
+			result.NotTargeted++
+			continue
+		}
+		row.Status = DaemonSetNodeMissing
+		for _, e := range placementFailures {
+			if strings.Contains(e.Message, `"`+node.Name+`"`) {
+				row.Reason = e.Message
+			}
+		}
+		if row.Reason == "" {
+			if taint := untoleratedTaint(daemonSetTolerations(spec.Tolerations), node); taint != nil {
+				row.Reason = "taint " + formatTaint(taint) + " not tolerated"
+			}
+		}
+		result.Nodes = append(result.Nodes, row)
+	}
+
+	sort.SliceStable(result.Nodes, func(i, j int) bool {
+		hi, hj := result.Nodes[i].Healthy(), result.Nodes[j].Healthy()
+		if hi != hj {
+			return !hi
+		}
+		return result.Nodes[i].Node < result.Nodes[j].Node
+	})
+	return result
+}
+
+// podNodeName returns the node a pod runs on, or for a pod not yet
+// scheduled the node the DaemonSet controller pinned it to with a
+// metadata.name node affinity term.
+func podNodeName(pod *corev1.Pod) string {
+	if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, field := range term.MatchFields {
+			if field.Key == "metadata.name" && field.Operator == corev1.NodeSelectorOpIn && len(field.Values) == 1 {
+				return field.Values[0]
+			}
+		}
+	}
+	return ""
+}
+
+// daemonSetTolerations adds the tolerations the DaemonSet controller gives
+// every pod it creates to the template's own.
+func daemonSetTolerations(tolerations []corev1.Toleration) []corev1.Toleration {
+	all := append([]corev1.Toleration(nil), tolerations...)
+	for _, key := range []string{
+		"node.kubernetes.io/not-ready",
+		"node.kubernetes.io/unreachable",
+		"node.kubernetes.io/disk-pressure",
+		"node.kubernetes.io/memory-pressure",
+		"node.kubernetes.io/pid-pressure",
+		"node.kubernetes.io/unschedulable",
+	} {
+		all = append(all, corev1.Toleration{Key: key, Operator: corev1.TolerationOpExists})
+	}
+	return all
+}
+
+// eventTime is when an event last happened.
+func eventTime(e corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// logAgent is a DaemonSet for Linux nodes that tolerates nothing beyond
+// the defaults.
+func logAgent() *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "log-agent", Namespace: "infra"},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "log-agent"}},
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+			}},
+		},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 4, NumberReady: 2},
+	}
+}
+
+func daemonSetTestNode(name string, taints ...corev1.Taint) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"kubernetes.io/os": "linux"}},
+		Spec:       corev1.NodeSpec{Taints: taints},
+	}
+}
+
+// daemonSetTestPod is a pod of log-agent on node, running ready unless
+// waiting is a container waiting reason.
+func daemonSetTestPod(name, node, waiting string, restarts int32) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "infra", Labels: map[string]string{"app": "log-agent"}},
+		Spec:       corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "agent",
+				Ready:        true,
+				RestartCount: restarts,
+				State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}},
+		},
+	}
+	if waiting != "" {
+		pod.Status.Conditions[0].Status = corev1.ConditionFalse
+		pod.Status.ContainerStatuses[0].Ready = false
+		pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: waiting}}
+	}
+	return pod
+}
+
+// pinnedPendingPod is a pod the controller created for node that the
+// scheduler could not place.
+func pinnedPendingPod(name, node string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "infra", Labels: map[string]string{"app": "log-agent"}},
+		Spec: corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{node}}},
+			}}},
+		}}},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+}
+
+func TestBuildDaemonSetNodes(t *testing.T) {
+	gpu := corev1.Taint{Key: "nvidia.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+	cordoned := daemonSetTestNode("node-b")
+	cordoned.Spec.Unschedulable = true
+	windows := daemonSetTestNode("win-1")
+	windows.Labels["kubernetes.io/os"] = "windows"
+	nodes := []corev1.Node{
+		daemonSetTestNode("node-a"),
+		cordoned,
+		daemonSetTestNode("node-c"),
+		daemonSetTestNode("node-d"),
+		daemonSetTestNode("gpu-1", gpu),
+		windows,
+	}
+	pods := []corev1.Pod{
+		daemonSetTestPod("log-agent-a", "node-a", "", 0),
+		daemonSetTestPod("log-agent-b", "node-b", "", 1),
+		daemonSetTestPod("log-agent-c", "node-c", "CrashLoopBackOff", 12),
+		pinnedPendingPod("log-agent-d", "node-d"),
+	}
+	now := time.Date(2024, 5, 16, 10, 0, 0, 0, time.UTC)
+	events := []corev1.Event{
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "log-agent-d"},
+			Reason:         "FailedScheduling",
+			Message:        "0/6 nodes are available: 1 Insufficient memory.",
+			LastTimestamp:  metav1.NewTime(now),
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "log-agent-d"},
+			Reason:         "FailedScheduling",
+			Message:        "an older failure",
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Hour)),
+		},
+		// Of another workload
+		{InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-0"}, Reason: "FailedScheduling", Message: "unrelated"},
+	}
+
+	got := BuildDaemonSetNodes(logAgent(), pods, nodes, events)
+
+	if got.NotTargeted != 1 {
+		t.Errorf("NotTargeted = %d, want the Windows node", got.NotTargeted)
+	}
+	var order []string
+	rows := make(map[string]DaemonSetNode)
+	for _, n := range got.Nodes {
+		order = append(order, n.Node)
+		rows[n.Node] = n
+	}
+	// Unhealthy nodes first, then by name
+	if want := "gpu-1,node-c,node-d,node-a,node-b"; strings.Join(order, ",") != want {
+		t.Errorf("nodes = %s, want %s", strings.Join(order, ","), want)
+	}
+
+	if n := rows["node-a"]; n.Pod != "log-agent-a" || !n.Ready || n.Status != "Running" {
+		t.Errorf("node-a = %+v, want a ready log-agent-a", n)
+	}
+	if n := rows["node-b"]; !n.Cordoned || !n.Healthy() || n.Restarts != 1 {
+		t.Errorf("node-b = %+v, want a cordoned node with a healthy pod", n)
+	}
+	if n := rows["node-c"]; n.Ready || n.Status != "CrashLoopBackOff" || n.Restarts != 12 {
+		t.Errorf("node-c = %+v, want a crashlooping pod with 12 restarts", n)
+	}
+	if n := rows["node-d"]; n.Pod != "log-agent-d" || n.Status != "Pending" || n.Reason != "0/6 nodes are available: 1 Insufficient memory." {
+		t.Errorf("node-d = %+v, want the pending pod with its latest FailedScheduling message", n)
+	}
+	if n := rows["gpu-1"]; n.Pod != "" || n.Status != DaemonSetNodeMissing || n.Reason != "taint nvidia.com/gpu=true:NoSchedule not tolerated" {
+		t.Errorf("gpu-1 = %+v, want missing for the untolerated taint", n)
+	}
+}
+
+func TestBuildDaemonSetNodes_PlacementEvent(t *testing.T) {
+	ds := logAgent()
+	events := []corev1.Event{{
+		InvolvedObject: corev1.ObjectReference{Kind: "DaemonSet", Name: "log-agent"},
+		Reason:         "FailedPlacement",
+		Message:        `failed to place pod on "node-a": Node didn't have enough resource: cpu`,
+	}}
+
+	got := BuildDaemonSetNodes(ds, nil, []corev1.Node{daemonSetTestNode("node-a"), daemonSetTestNode("node-b")}, events)
+	if len(got.Nodes) != 2 {
+		t.Fatalf("got %d nodes, want 2", len(got.Nodes))
+	}
+	if n := got.Nodes[0]; n.Node != "node-a" || !strings.Contains(n.Reason, "enough resource") {
+		t.Errorf("node-a = %+v, want the placement failure", n)
+	}
+	if n := got.Nodes[1]; n.Status != DaemonSetNodeMissing || n.Reason != "" {
+		t.Errorf("node-b = %+v, want missing with no known reason", n)
+	}
+}
+
+func TestGetDaemonSetNodes(t *testing.T) {
+	healthy := daemonSetTestPod("log-agent-a", "node-a", "", 0)
+	nodeA, nodeB := daemonSetTestNode("node-a"), daemonSetTestNode("node-b")
+	pending := pinnedPendingPod("log-agent-b", "node-b")
+	clientset := fake.NewSimpleClientset(logAgent(), &healthy, &pending, &nodeA, &nodeB,
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "log-agent-b.1", Namespace: "infra"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "log-agent-b"},
+			Reason:         "FailedScheduling",
+			Message:        "0/2 nodes are available: 1 node(s) didn't match pod anti-affinity rules.",
+		})
+
+	got, err := GetDaemonSetNodes(context.Background(), clientset, "infra", "log-agent")
+	if err != nil {
+		t.Fatalf("GetDaemonSetNodes failed: %v", err)
+	}
+	if len(got.Nodes) != 2 || got.Nodes[0].Node != "node-b" || !strings.Contains(got.Nodes[0].Reason, "anti-affinity") {
+		t.Errorf("nodes = %+v, want node-b first with its scheduling failure", got.Nodes)
+	}
+
+	if _, err := GetDaemonSetNodes(context.Background(), clientset, "infra", "missing"); err == nil {
+		t.Error("GetDaemonSetNodes should fail for a missing DaemonSet")
+	}
+}
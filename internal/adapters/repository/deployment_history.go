@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// revisionAnnotation is set by the Deployment controller on a Deployment
+// and its ReplicaSets to number the pod template revisions.
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// RevisionInfo describes one revision of a Deployment, backed by the
+// ReplicaSet that holds its pod template.
+type RevisionInfo struct {
+	Revision      int64
+	ReplicaSet    string
+	Images        []string // Container images of the revision's pod template
+	Replicas      int32    // Desired replicas of the ReplicaSet; 0 for old revisions
+	ReadyReplicas int32
+	Age           string
+	ChangeCause   string // kubernetes.io/change-cause annotation, if set
+	Current       bool   // The revision the Deployment runs
+}
+
+// GetDeploymentHistory lists the revisions of a Deployment, newest first,
+// like kubectl rollout history. Revisions are the ReplicaSets the
+// Deployment owns, numbered by their deployment.kubernetes.io/revision
+// annotation.
+func GetDeploymentHistory(ctx context.Context, clientset kubernetes.Interface, namespace, name string) ([]RevisionInfo, error) {
+	dep, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+	replicaSets, err := deploymentReplicaSets(ctx, clientset, dep)
+	if err != nil {
+		return nil, err
+	}
+
+	current := revisionOf(dep.Annotations)
+	var history []RevisionInfo
+	for _, rs := range replicaSets {
+		rev := RevisionInfo{
+			Revision:      revisionOf(rs.Annotations),
+			ReplicaSet:    rs.Name,
+			ReadyReplicas: rs.Status.ReadyReplicas,
+			Age:           formatAge(rs.CreationTimestamp.Time),
+			ChangeCause:   rs.Annotations["kubernetes.io/change-cause"],
+		}
+		if rs.Spec.Replicas != nil {
+			rev.Replicas = *rs.Spec.Replicas
+		}
+		for _, c := range rs.Spec.Template.Spec.Containers {
+			rev.Images = append(rev.Images, c.Image)
+		}
+		history = append(history, rev)
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Revision > history[j].Revision
+	})
+
+	// Without the annotation on the Deployment, the newest revision is current
+	for i := range history {
+		history[i].Current = history[i].Revision == current || (current == 0 && i == 0)
+	}
+	return history, nil
+}
+
+// RollbackDeployment rolls a Deployment back to a revision by copying the
+// revision's pod template onto it, like kubectl rollout undo --to-revision.
+// The Deployment controller then rolls out the old template as a new
+// revision.
+func RollbackDeployment(ctx context.Context, clientset kubernetes.Interface, namespace, name string, revision int64) error {
+	dep, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get deployment: %w", err)
+	}
+	if revision == revisionOf(dep.Annotations) {
+		return fmt.Errorf("revision %d is already the current one", revision)
+	}
+	replicaSets, err := deploymentReplicaSets(ctx, clientset, dep)
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range replicaSets {
+		if revisionOf(rs.Annotations) != revision {
+			continue
+		}
+		template := rs.Spec.Template.DeepCopy()
+		// The controller adds the hash label to each ReplicaSet's template
+		delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+		dep.Spec.Template = *template
+		if _, err := clientset.AppsV1().Deployments(namespace).Update(ctx, dep, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to roll back deployment: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("revision %d of deployment %s not found", revision, name)
+}
+
+// deploymentReplicaSets returns the ReplicaSets controlled by dep.
+func deploymentReplicaSets(ctx context.Context, clientset kubernetes.Interface, dep *appsv1.Deployment) ([]appsv1.ReplicaSet, error) {
+	replicaSets, err := clientset.AppsV1().ReplicaSets(dep.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicasets: %w", err)
+	}
+	var owned []appsv1.ReplicaSet
+	for _, rs := range replicaSets.Items {
+		if metav1.IsControlledBy(&rs, dep) {
+			owned = append(owned, rs)
+		}
+	}
+	return owned, nil
+}
+
+// revisionOf parses the revision annotation, 0 when missing or invalid.
+func revisionOf(annotations map[string]string) int64 {
+	rev, err := strconv.ParseInt(annotations[revisionAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return rev
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func historyDeployment(revision string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   "default",
+			UID:         types.UID("web-uid"),
+			Annotations: map[string]string{revisionAnnotation: revision},
+		},
+		Spec: appsv1.DeploymentSpec{Template: podTemplate("web:3", "")},
+	}
+}
+
+func podTemplate(image, hash string) corev1.PodTemplateSpec {
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: image}}},
+	}
+	if hash != "" {
+		template.Labels[appsv1.DefaultDeploymentUniqueLabelKey] = hash
+	}
+	return template
+}
+
+func historyReplicaSet(name, revision, image string, replicas int32, owner *appsv1.Deployment) *appsv1.ReplicaSet {
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: map[string]string{revisionAnnotation: revision},
+		},
+		Spec: appsv1.ReplicaSetSpec{Replicas: &replicas, Template: podTemplate(image, name)},
+	}
+	if owner != nil {
+		rs.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, appsv1.SchemeGroupVersion.WithKind("Deployment"))}
+	}
+	return rs
+}
+
+func TestGetDeploymentHistory(t *testing.T) {
+	dep := historyDeployment("10")
+	clientset := fake.NewSimpleClientset(
+		dep,
+		historyReplicaSet("web-a", "2", "web:1", 0, dep),
+		historyReplicaSet("web-c", "10", "web:3", 3, dep),
+		historyReplicaSet("web-b", "9", "web:2", 0, dep),
+		historyReplicaSet("other", "11", "other:1", 1, nil),
+	)
+
+	history, err := GetDeploymentHistory(context.Background(), clientset, "default", "web")
+	if err != nil {
+		t.Fatalf("GetDeploymentHistory() error = %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("history = %+v, want the 3 owned ReplicaSets", history)
+	}
+	// Numeric order, newest first: 10 before 9 before 2
+	for i, want := range []int64{10, 9, 2} {
+		if history[i].Revision != want {
+			t.Errorf("history[%d].Revision = %d, want %d", i, history[i].Revision, want)
+		}
+	}
+	if !history[0].Current || history[1].Current || history[2].Current {
+		t.Errorf("only revision 10 should be current: %+v", history)
+	}
+	if got := history[0]; got.ReplicaSet != "web-c" || got.Replicas != 3 || len(got.Images) != 1 || got.Images[0] != "web:3" {
+		t.Errorf("history[0] = %+v, want web-c with 3 replicas of web:3", got)
+	}
+}
+
+func TestRollbackDeployment(t *testing.T) {
+	ctx := context.Background()
+	dep := historyDeployment("3")
+	clientset := fake.NewSimpleClientset(
+		dep,
+		historyReplicaSet("web-a", "1", "web:1", 0, dep),
+		historyReplicaSet("web-c", "3", "web:3", 3, dep),
+	)
+
+	if err := RollbackDeployment(ctx, clientset, "default", "web", 1); err != nil {
+		t.Fatalf("RollbackDeployment() error = %v", err)
+	}
+	updated, err := clientset.AppsV1().Deployments("default").Get(ctx, "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if image := updated.Spec.Template.Spec.Containers[0].Image; image != "web:1" {
+		t.Errorf("image = %q, want the template of revision 1 (web:1)", image)
+	}
+	if _, ok := updated.Spec.Template.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; ok {
+		t.Error("the pod-template-hash label should not be copied onto the Deployment")
+	}
+
+	if err := RollbackDeployment(ctx, clientset, "default", "web", 3); err == nil {
+		t.Error("rolling back to the current revision should fail")
+	}
+	if err := RollbackDeployment(ctx, clientset, "default", "web", 7); err == nil {
+		t.Error("rolling back to an unknown revision should fail")
+	}
+}
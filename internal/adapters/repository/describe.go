@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// describeKeyWidth is the width of the "Key:" column in describe output.
+const describeKeyWidth = 18
+
+// DescribeService returns kubectl-describe-like text for a Service: its
+// selector, type and addresses, and for each port the target port and the
+// endpoint addresses behind it, read from the Service's EndpointSlices.
+func DescribeService(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (string, error) {
+	svc, err := clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get service: %w", err)
+	}
+	epSlices, _ := listServiceEndpointSlices(ctx, clientset, namespace, name)
+
+	var b strings.Builder
+	describeField(&b, "Name", svc.Name)
+	describeField(&b, "Namespace", svc.Namespace)
+	describeField(&b, "Labels", describeMap(svc.Labels))
+	describeField(&b, "Selector", describeMap(svc.Spec.Selector))
+	describeField(&b, "Type", string(svc.Spec.Type))
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		describeField(&b, "External Name", svc.Spec.ExternalName)
+	}
+	describeField(&b, "IP", orNone(svc.Spec.ClusterIP))
+	var lbIngress []string
+	for _, ing := range svc.Status.LoadBalancer.Ingress {
+		if ing.IP != "" {
+			lbIngress = append(lbIngress, ing.IP)
+		} else if ing.Hostname != "" {
+			lbIngress = append(lbIngress, ing.Hostname)
+		}
+	}
+	if len(lbIngress) > 0 {
+		describeField(&b, "LoadBalancer", strings.Join(lbIngress, ", "))
+	}
+
+	for _, p := range svc.Spec.Ports {
+		portName := p.Name
+		if portName == "" {
+			portName = "<unset>"
+		}
+		describeField(&b, "Port", fmt.Sprintf("%s  %d/%s", portName, p.Port, p.Protocol))
+		describeField(&b, "TargetPort", fmt.Sprintf("%s/%s", p.TargetPort.String(), p.Protocol))
+		if p.NodePort != 0 {
+			describeField(&b, "NodePort", fmt.Sprintf("%s  %d/%s", portName, p.NodePort, p.Protocol))
+		}
+		ready, notReady := endpointAddresses(epSlices, p.Name)
+		describeField(&b, "Endpoints", orNone(strings.Join(ready, ",")))
+		if len(notReady) > 0 {
+			describeField(&b, "Not Ready", strings.Join(notReady, ","))
+		}
+	}
+	describeField(&b, "Session Affinity", string(svc.Spec.SessionAffinity))
+	return b.String(), nil
+}
+
+// DescribeIngress returns kubectl-describe-like text for an Ingress: its
+// class, TLS hosts and each rule's paths with the backend service and
+// port, followed by the ready endpoints behind that backend.
+func DescribeIngress(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (string, error) {
+	ing, err := clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get ingress: %w", err)
+	}
+
+	class := ""
+	if ing.Spec.IngressClassName != nil {
+		class = *ing.Spec.IngressClassName
+	} else if c, ok := ing.Annotations["kubernetes.io/ingress.class"]; ok {
+		class = c
+	}
+
+	// Each backend service is looked up once
+	backends := make(map[string]string)
+	backend := func(svc *networkingv1.IngressServiceBackend) string {
+		if svc == nil {
+			return "<none>"
+		}
+		port := svc.Port.Name
+		if port == "" {
+			port = fmt.Sprintf("%d", svc.Port.Number)
+		}
+		key := svc.Name + ":" + port
+		if _, ok := backends[key]; !ok {
+			backends[key] = key + " (" + ingressBackendEndpoints(ctx, clientset, namespace, svc) + ")"
+		}
+		return backends[key]
+	}
+
+	var b strings.Builder
+	describeField(&b, "Name", ing.Name)
+	describeField(&b, "Namespace", ing.Namespace)
+	describeField(&b, "Labels", describeMap(ing.Labels))
+	describeField(&b, "Ingress Class", orNone(class))
+	var addresses []string
+	for _, lb := range ing.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			addresses = append(addresses, lb.IP)
+		} else if lb.Hostname != "" {
+			addresses = append(addresses, lb.Hostname)
+		}
+	}
+	describeField(&b, "Address", strings.Join(addresses, ","))
+	defaultBackend := "<default>"
+	if ing.Spec.DefaultBackend != nil {
+		defaultBackend = backend(ing.Spec.DefaultBackend.Service)
+	}
+	describeField(&b, "Default backend", defaultBackend)
+
+	if len(ing.Spec.TLS) > 0 {
+		b.WriteString("TLS:\n")
+		for _, tls := range ing.Spec.TLS {
+			secret := tls.SecretName
+			if secret == "" {
+				secret = "SNI routes"
+			}
+			b.WriteString(fmt.Sprintf("  %s terminates %s\n", secret, strings.Join(tls.Hosts, ",")))
+		}
+	}
+
+	b.WriteString("Rules:\n")
+	b.WriteString(fmt.Sprintf("  %-24s %-20s %s\n", "Host", "Path", "Backends"))
+	b.WriteString(fmt.Sprintf("  %-24s %-20s %s\n", "----", "----", "--------"))
+	for _, rule := range ing.Spec.Rules {
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		b.WriteString("  " + host + "\n")
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, p := range rule.HTTP.Paths {
+			path := p.Path
+			if path == "" {
+				path = "/"
+			}
+			b.WriteString(fmt.Sprintf("  %-24s %-20s %s\n", "", path, backend(p.Backend.Service)))
+		}
+	}
+	describeField(&b, "Annotations", describeMap(ing.Annotations))
+	return b.String(), nil
+}
+
+// ingressBackendEndpoints returns the ready endpoint addresses behind an
+// Ingress backend, matching its port to the Service's port by number or
+// name. Returns "<error: ...>" when the Service does not exist.
+func ingressBackendEndpoints(ctx context.Context, clientset kubernetes.Interface, namespace string, backend *networkingv1.IngressServiceBackend) string {
+	svc, err := clientset.CoreV1().Services(namespace).Get(ctx, backend.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Sprintf("<error: endpoints \"%s\" not found>", backend.Name)
+	}
+	epSlices, _ := listServiceEndpointSlices(ctx, clientset, namespace, backend.Name)
+	for _, p := range svc.Spec.Ports {
+		if (backend.Port.Name != "" && p.Name == backend.Port.Name) ||
+			(backend.Port.Name == "" && p.Port == backend.Port.Number) {
+			ready, _ := endpointAddresses(epSlices, p.Name)
+			return orNone(strings.Join(ready, ","))
+		}
+	}
+	return "<none>"
+}
+
+// DescribeConfigMap returns kubectl-describe-like text for a ConfigMap.
+// Values are not shown, only each data key with its size, so large or
+// sensitive files don't flood the view.
+func DescribeConfigMap(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (string, error) {
+	cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get configmap: %w", err)
+	}
+
+	var b strings.Builder
+	describeField(&b, "Name", cm.Name)
+	describeField(&b, "Namespace", cm.Namespace)
+	describeField(&b, "Labels", describeMap(cm.Labels))
+	describeField(&b, "Annotations", describeMap(cm.Annotations))
+
+	sizes := make(map[string]int, len(cm.Data))
+	for k, v := range cm.Data {
+		sizes[k] = len(v)
+	}
+	describeSizes(&b, "Data", sizes)
+
+	sizes = make(map[string]int, len(cm.BinaryData))
+	for k, v := range cm.BinaryData {
+		sizes[k] = len(v)
+	}
+	describeSizes(&b, "BinaryData", sizes)
+	return b.String(), nil
+}
+
+// DescribeSecret returns kubectl-describe-like text for a Secret: its
+// type and each data key with its size, never the values.
+func DescribeSecret(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (string, error) {
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	var b strings.Builder
+	describeField(&b, "Name", secret.Name)
+	describeField(&b, "Namespace", secret.Namespace)
+	describeField(&b, "Labels", describeMap(secret.Labels))
+	describeField(&b, "Annotations", describeMap(secret.Annotations))
+	describeField(&b, "Type", string(secret.Type))
+
+	sizes := make(map[string]int, len(secret.Data))
+	for k, v := range secret.Data {
+		sizes[k] = len(v)
+	}
+	describeSizes(&b, "Data", sizes)
+	return b.String(), nil
+}
+
+// describeSizes writes a titled section listing keys, sorted, with their
+// sizes in bytes.
+func describeSizes(b *strings.Builder, title string, sizes map[string]int) {
+	b.WriteString("\n" + title + "\n" + strings.Repeat("=", len(title)) + "\n")
+	if len(sizes) == 0 {
+		b.WriteString("<none>\n")
+		return
+	}
+	keys := make([]string, 0, len(sizes))
+	for k := range sizes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		describeField(b, k, fmt.Sprintf("%d bytes", sizes[k]))
+	}
+}
+
+// endpointAddresses returns the "ip:port" addresses of the endpoints for
+// the Service port named portName (empty for an unnamed port), split into
+// ready and not ready.
+func endpointAddresses(epSlices *discoveryv1.EndpointSliceList, portName string) (ready, notReady []string) {
+	if epSlices == nil {
+		return nil, nil
+	}
+	for _, slice := range epSlices.Items {
+		for _, port := range slice.Ports {
+			name := ""
+			if port.Name != nil {
+				name = *port.Name
+			}
+			if name != portName || port.Port == nil {
+				continue
+			}
+			for _, endpoint := range slice.Endpoints {
+				isReady := endpoint.Conditions.Ready != nil && *endpoint.Conditions.Ready
+				for _, addr := range endpoint.Addresses {
+					a := fmt.Sprintf("%s:%d", addr, *port.Port)
+					if isReady {
+						ready = append(ready, a)
+					} else {
+						notReady = append(notReady, a)
+					}
+				}
+			}
+		}
+	}
+	return ready, notReady
+}
+
+// describeField writes "Key:  value", aligning every line of a multi-line
+// value with the first.
+func describeField(b *strings.Builder, key, value string) {
+	indent := "\n" + strings.Repeat(" ", describeKeyWidth+1)
+	b.WriteString(fmt.Sprintf("%-*s %s\n", describeKeyWidth, key+":", strings.ReplaceAll(value, "\n", indent)))
+}
+
+// describeMap formats labels, selectors or annotations one "k=v" per line,
+// sorted by key, or "<none>".
+func describeMap(m map[string]string) string {
+	if len(m) == 0 {
+		return "<none>"
+	}
+	lines := make([]string, 0, len(m))
+	for k, v := range m {
+		lines = append(lines, k+"="+v)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// orNone returns s, or "<none>" when it is empty.
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
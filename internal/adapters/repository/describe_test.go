@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// describeFixtures returns a Service "web" with two ready endpoints and
+// one that is not ready, and an Ingress routing to it.
+func describeFixtures() *fake.Clientset {
+	ready, notReady := true, false
+	portName := "http"
+	port := int32(8080)
+	className := "nginx"
+	pathType := networkingv1.PathTypePrefix
+
+	return fake.NewSimpleClientset(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"team": "a"}},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeClusterIP,
+				ClusterIP: "10.0.0.10",
+				Selector:  map[string]string{"app": "web"},
+				Ports: []corev1.ServicePort{{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromInt(8080),
+					Protocol:   corev1.ProtocolTCP,
+				}},
+				SessionAffinity: corev1.ServiceAffinityNone,
+			},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web-abc",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+			},
+			Ports: []discoveryv1.EndpointPort{{Name: &portName, Port: &port}},
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.1.0.5"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+				{Addresses: []string{"10.1.0.6"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+				{Addresses: []string{"10.1.0.7"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+			},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: &className,
+				TLS:              []networkingv1.IngressTLS{{Hosts: []string{"example.com"}, SecretName: "web-tls"}},
+				Rules: []networkingv1.IngressRule{{
+					Host: "example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{
+								Path:     "/",
+								PathType: &pathType,
+								Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+									Name: "web",
+									Port: networkingv1.ServiceBackendPort{Number: 80},
+								}},
+							},
+							{
+								Path:     "/old",
+								PathType: &pathType,
+								Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+									Name: "legacy",
+									Port: networkingv1.ServiceBackendPort{Name: "http"},
+								}},
+							},
+						},
+					}},
+				}},
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-config", Namespace: "default"},
+			Data:       map[string]string{"app.yaml": "port: 8080\n", "LOG_LEVEL": "debug"},
+			BinaryData: map[string][]byte{"logo.png": make([]byte, 2048)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-tls", Namespace: "default"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{"tls.crt": []byte("certificate"), "tls.key": []byte("hunter2")},
+		},
+	)
+}
+
+func TestDescribeService(t *testing.T) {
+	out, err := DescribeService(context.Background(), describeFixtures(), "default", "web")
+	if err != nil {
+		t.Fatalf("DescribeService() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"Name:              web",
+		"Selector:          app=web",
+		"IP:                10.0.0.10",
+		"Port:              http  80/TCP",
+		"TargetPort:        8080/TCP",
+		"Endpoints:         10.1.0.5:8080,10.1.0.6:8080",
+		"Not Ready:         10.1.0.7:8080",
+		"Session Affinity:  None",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DescribeService() missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestDescribeService_NotFound(t *testing.T) {
+	if _, err := DescribeService(context.Background(), fake.NewSimpleClientset(), "default", "web"); err == nil {
+		t.Error("DescribeService() should fail for a missing service")
+	}
+}
+
+func TestDescribeIngress(t *testing.T) {
+	out, err := DescribeIngress(context.Background(), describeFixtures(), "default", "web")
+	if err != nil {
+		t.Fatalf("DescribeIngress() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"Ingress Class:     nginx",
+		"web-tls terminates example.com",
+		"example.com",
+		"web:80 (10.1.0.5:8080,10.1.0.6:8080)",
+		`legacy:http (<error: endpoints "legacy" not found>)`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DescribeIngress() missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestDescribeConfigMap(t *testing.T) {
+	out, err := DescribeConfigMap(context.Background(), describeFixtures(), "default", "web-config")
+	if err != nil {
+		t.Fatalf("DescribeConfigMap() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"Name:              web-config",
+		"LOG_LEVEL:         5 bytes",
+		"app.yaml:          11 bytes",
+		"logo.png:          2048 bytes",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DescribeConfigMap() missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "debug") {
+		t.Error("DescribeConfigMap() should not show values")
+	}
+}
+
+func TestDescribeSecret(t *testing.T) {
+	out, err := DescribeSecret(context.Background(), describeFixtures(), "default", "web-tls")
+	if err != nil {
+		t.Fatalf("DescribeSecret() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"Name:              web-tls",
+		"Type:              kubernetes.io/tls",
+		"tls.crt:           11 bytes",
+		"tls.key:           7 bytes",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DescribeSecret() missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Error("DescribeSecret() should not show values")
+	}
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DrainPodStatus classifies where an evicted pod could go.
+type DrainPodStatus string
+
+// Possible outcomes for a pod in a drain simulation.
+const (
+	DrainReschedulable    DrainPodStatus = "reschedulable"      // Fits on another node
+	DrainNoCapacity       DrainPodStatus = "no capacity"        // Compatible nodes exist but are full
+	DrainNoCompatibleNode DrainPodStatus = "no compatible node" // Selectors/taints rule out every node
+)
+
+// DrainPodPlan describes the simulated fate of one evictable pod.
+type DrainPodPlan struct {
+	Namespace     string
+	Name          string
+	CPURequest    int64          // Millicores
+	MemoryRequest int64          // Bytes
+	Status        DrainPodStatus // Simulation outcome
+	TargetNode    string         // First-fit node when reschedulable
+}
+
+// DrainSimulation is a dry run of draining a node. Placement uses a simple
+// first-fit over request headroom, so it is an estimate of what the
+// scheduler would do, not a guarantee.
+type DrainSimulation struct {
+	Node               string
+	Pods               []DrainPodPlan // Evictable pods, largest requests first
+	Skipped            []string       // DaemonSet, mirror and finished pods (namespace/name)
+	TotalCPURequest    int64          // Millicores across evictable pods
+	TotalMemoryRequest int64          // Bytes across evictable pods
+	CandidateNodes     int            // Ready, schedulable nodes considered as targets
+}
+
+// Count returns how many pods ended up with the given status.
+func (s *DrainSimulation) Count(status DrainPodStatus) int {
+	count := 0
+	for _, p := range s.Pods {
+		if p.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// drainTarget tracks the remaining headroom of a candidate node.
+type drainTarget struct {
+	node     *corev1.Node
+	cpuFree  int64 // Millicores
+	memFree  int64 // Bytes
+	podsFree int64
+}
+
+// SimulateDrain estimates what would happen if nodeName were drained.
+// Nothing is mutated: it lists the node's evictable pods, computes each other
+// node's allocatable-minus-requested headroom and places pods first-fit,
+// honoring nodeSelector and NoSchedule/NoExecute taints.
+func SimulateDrain(ctx context.Context, clientset kubernetes.Interface, nodeName string) (*DrainSimulation, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	found := false
+	for _, n := range nodes.Items {
+		if n.Name == nodeName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("node %s not found", nodeName)
+	}
+
+	sim := &DrainSimulation{Node: nodeName}
+
+	var evictable []corev1.Pod
+	for _, p := range pods.Items {
+		if p.Spec.NodeName != nodeName {
+			continue
+		}
+		if !isEvictable(&p) {
+			sim.Skipped = append(sim.Skipped, p.Namespace+"/"+p.Name)
+			continue
+		}
+		evictable = append(evictable, p)
+	}
+
+	targets := drainTargets(nodes.Items, pods.Items, nodeName)
+	sim.CandidateNodes = len(targets)
+	sim.Pods = planDrain(evictable, targets)
+	for _, p := range sim.Pods {
+		sim.TotalCPURequest += p.CPURequest
+		sim.TotalMemoryRequest += p.MemoryRequest
+	}
+
+	return sim, nil
+}
+
+// isEvictable reports whether drain would evict the pod.
+// DaemonSet pods are recreated on the same node, mirror pods are managed by
+// the kubelet and finished pods hold no resources.
+func isEvictable(p *corev1.Pod) bool {
+	if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	if _, ok := p.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return false
+	}
+	for _, ref := range p.OwnerReferences {
+		if ref.Kind == "DaemonSet" {
+			return false
+		}
+	}
+	return true
+}
+
+// drainTargets builds the headroom of every Ready, schedulable node except the drained one.
+func drainTargets(nodes []corev1.Node, pods []corev1.Pod, drained string) []*drainTarget {
+	committed := committedRequests(pods)
+	var targets []*drainTarget
+	for i := range nodes {
+		n := &nodes[i]
+		if n.Name == drained || n.Spec.Unschedulable || !isNodeReady(n) {
+			continue
+		}
+		used := committed[n.Name]
+		targets = append(targets, &drainTarget{
+			node:     n,
+			cpuFree:  n.Status.Allocatable.Cpu().MilliValue() - used.cpu,
+			memFree:  n.Status.Allocatable.Memory().Value() - used.mem,
+			podsFree: n.Status.Allocatable.Pods().Value() - used.pods,
+		})
+	}
+	return targets
+}
+
+// planDrain places pods first-fit decreasing onto the targets, consuming
+// their headroom as it goes.
+func planDrain(pods []corev1.Pod, targets []*drainTarget) []DrainPodPlan {
+	plans := make([]DrainPodPlan, 0, len(pods))
+	order := make([]int, len(pods))
+	for i := range pods {
+		order[i] = i
+		cpu, mem := podRequests(&pods[i])
+		plans = append(plans, DrainPodPlan{
+			Namespace:     pods[i].Namespace,
+			Name:          pods[i].Name,
+			CPURequest:    cpu,
+			MemoryRequest: mem,
+		})
+	}
+
+	// Largest pods first gives first-fit a better chance
+	sort.SliceStable(order, func(a, b int) bool {
+		pa, pb := plans[order[a]], plans[order[b]]
+		if pa.CPURequest != pb.CPURequest {
+			return pa.CPURequest > pb.CPURequest
+		}
+		return pa.MemoryRequest > pb.MemoryRequest
+	})
+
+	result := make([]DrainPodPlan, 0, len(pods))
+	for _, idx := range order {
+		plan := plans[idx]
+		pod := &pods[idx]
+		plan.Status = DrainNoCompatibleNode
+		for _, t := range targets {
+			if !podFitsNode(pod, t.node) {
+				continue
+			}
+			plan.Status = DrainNoCapacity
+			if t.cpuFree >= plan.CPURequest && t.memFree >= plan.MemoryRequest && t.podsFree > 0 {
+				t.cpuFree -= plan.CPURequest
+				t.memFree -= plan.MemoryRequest
+				t.podsFree--
+				plan.Status = DrainReschedulable
+				plan.TargetNode = t.node.Name
+				break
+			}
+		}
+		result = append(result, plan)
+	}
+
+	return result
+}
+
+// podFitsNode checks nodeSelector labels, required node affinity and
+// scheduling taints. Preferred affinity, topology spread and pod
+// (anti-)affinity are not evaluated, one reason the result is an estimate.
+func podFitsNode(pod *corev1.Pod, node *corev1.Node) bool {
+	for k, v := range pod.Spec.NodeSelector {
+		if node.Labels[k] != v {
+			return false
+		}
+	}
+	if a := pod.Spec.Affinity; a != nil && a.NodeAffinity != nil &&
+		!nodeMatchesSelector(a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution, node) {
+		return false
+	}
+	return untoleratedTaint(pod.Spec.Tolerations, node) == nil
+}
+
+// podRequests returns the effective CPU (millicores) and memory (bytes) requests.
+// Init containers run sequentially, so the effective request is the larger of
+// the sum of app containers and the biggest init container.
+func podRequests(p *corev1.Pod) (cpu, mem int64) {
+	for _, c := range p.Spec.Containers {
+		cpu += c.Resources.Requests.Cpu().MilliValue()
+		mem += c.Resources.Requests.Memory().Value()
+	}
+	for _, c := range p.Spec.InitContainers {
+		if v := c.Resources.Requests.Cpu().MilliValue(); v > cpu {
+			cpu = v
+		}
+		if v := c.Resources.Requests.Memory().Value(); v > mem {
+			mem = v
+		}
+	}
+	return cpu, mem
+}
+
+// isNodeReady reports whether the node's Ready condition is True.
+func isNodeReady(n *corev1.Node) bool {
+	for _, c := range n.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func drainNode(name, cpu, mem string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(mem),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func drainPod(name, node, cpu, mem string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(mem),
+					},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestPlanDrain(t *testing.T) {
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		node       *corev1.Node
+		wantStatus DrainPodStatus
+		wantTarget string
+	}{
+		{
+			name:       "fits",
+			pod:        drainPod("web", "node-a", "500m", "256Mi"),
+			node:       drainNode("node-b", "2", "4Gi"),
+			wantStatus: DrainReschedulable,
+			wantTarget: "node-b",
+		},
+		{
+			name:       "too big",
+			pod:        drainPod("web", "node-a", "4", "256Mi"),
+			node:       drainNode("node-b", "2", "4Gi"),
+			wantStatus: DrainNoCapacity,
+		},
+		{
+			name: "node selector mismatch",
+			pod: func() *corev1.Pod {
+				p := drainPod("web", "node-a", "100m", "64Mi")
+				p.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+				return p
+			}(),
+			node:       drainNode("node-b", "2", "4Gi"),
+			wantStatus: DrainNoCompatibleNode,
+		},
+		{
+			name: "untolerated taint",
+			pod:  drainPod("web", "node-a", "100m", "64Mi"),
+			node: func() *corev1.Node {
+				n := drainNode("node-b", "2", "4Gi")
+				n.Spec.Taints = []corev1.Taint{{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}}
+				return n
+			}(),
+			wantStatus: DrainNoCompatibleNode,
+		},
+		{
+			name: "tolerated taint",
+			pod: func() *corev1.Pod {
+				p := drainPod("web", "node-a", "100m", "64Mi")
+				p.Spec.Tolerations = []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}}
+				return p
+			}(),
+			node: func() *corev1.Node {
+				n := drainNode("node-b", "2", "4Gi")
+				n.Spec.Taints = []corev1.Taint{{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}}
+				return n
+			}(),
+			wantStatus: DrainReschedulable,
+			wantTarget: "node-b",
+		},
+		{
+			name: "prefer no schedule is ignored",
+			pod:  drainPod("web", "node-a", "100m", "64Mi"),
+			node: func() *corev1.Node {
+				n := drainNode("node-b", "2", "4Gi")
+				n.Spec.Taints = []corev1.Taint{{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule}}
+				return n
+			}(),
+			wantStatus: DrainReschedulable,
+			wantTarget: "node-b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets := drainTargets([]corev1.Node{*tt.node}, nil, "node-a")
+			plans := planDrain([]corev1.Pod{*tt.pod}, targets)
+			if len(plans) != 1 {
+				t.Fatalf("planDrain() returned %d plans, want 1", len(plans))
+			}
+			if plans[0].Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", plans[0].Status, tt.wantStatus)
+			}
+			if plans[0].TargetNode != tt.wantTarget {
+				t.Errorf("TargetNode = %q, want %q", plans[0].TargetNode, tt.wantTarget)
+			}
+		})
+	}
+}
+
+func TestPlanDrain_ConsumesHeadroom(t *testing.T) {
+	targets := drainTargets([]corev1.Node{*drainNode("node-b", "1", "4Gi")}, nil, "node-a")
+	pods := []corev1.Pod{
+		*drainPod("small", "node-a", "300m", "64Mi"),
+		*drainPod("large", "node-a", "600m", "64Mi"),
+		*drainPod("medium", "node-a", "400m", "64Mi"),
+	}
+
+	plans := planDrain(pods, targets)
+
+	// First-fit decreasing: large (600m) then medium (400m) fill the node
+	want := map[string]DrainPodStatus{
+		"large":  DrainReschedulable,
+		"medium": DrainReschedulable,
+		"small":  DrainNoCapacity,
+	}
+	if plans[0].Name != "large" {
+		t.Errorf("plans[0] = %s, want largest pod first", plans[0].Name)
+	}
+	for _, p := range plans {
+		if p.Status != want[p.Name] {
+			t.Errorf("%s: Status = %q, want %q", p.Name, p.Status, want[p.Name])
+		}
+	}
+}
+
+func TestDrainTargets(t *testing.T) {
+	notReady := drainNode("node-c", "2", "4Gi")
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+	cordoned := drainNode("node-d", "2", "4Gi")
+	cordoned.Spec.Unschedulable = true
+
+	nodes := []corev1.Node{
+		*drainNode("node-a", "2", "4Gi"),
+		*drainNode("node-b", "2", "4Gi"),
+		*notReady,
+		*cordoned,
+	}
+	pods := []corev1.Pod{*drainPod("existing", "node-b", "1500m", "1Gi")}
+
+	targets := drainTargets(nodes, pods, "node-a")
+	if len(targets) != 1 || targets[0].node.Name != "node-b" {
+		t.Fatalf("drainTargets() = %d targets, want only node-b", len(targets))
+	}
+	if targets[0].cpuFree != 500 {
+		t.Errorf("cpuFree = %d, want 500", targets[0].cpuFree)
+	}
+	if targets[0].podsFree != 109 {
+		t.Errorf("podsFree = %d, want 109", targets[0].podsFree)
+	}
+}
+
+func TestPodRequests_InitContainers(t *testing.T) {
+	pod := drainPod("web", "node-a", "100m", "64Mi")
+	pod.Spec.InitContainers = []corev1.Container{{
+		Name: "migrate",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		},
+	}}
+
+	cpu, mem := podRequests(pod)
+	if cpu != 1000 {
+		t.Errorf("cpu = %d, want 1000 (init container dominates)", cpu)
+	}
+	if mem != 64*1024*1024 {
+		t.Errorf("mem = %d, want 64Mi", mem)
+	}
+}
+
+func TestSimulateDrain(t *testing.T) {
+	ds := drainPod("fluentd", "node-a", "100m", "64Mi")
+	ds.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "fluentd"}}
+	done := drainPod("job-1", "node-a", "100m", "64Mi")
+	done.Status.Phase = corev1.PodSucceeded
+
+	clientset := fake.NewSimpleClientset(
+		drainNode("node-a", "2", "4Gi"),
+		drainNode("node-b", "2", "4Gi"),
+		drainPod("web", "node-a", "500m", "256Mi"),
+		drainPod("api", "node-a", "3", "256Mi"),
+		drainPod("other", "node-b", "1", "1Gi"),
+		ds,
+		done,
+	)
+
+	ctx := context.Background()
+	sim, err := SimulateDrain(ctx, clientset, "node-a")
+	if err != nil {
+		t.Fatalf("SimulateDrain() error = %v", err)
+	}
+
+	if len(sim.Pods) != 2 {
+		t.Fatalf("Pods = %d, want 2 evictable", len(sim.Pods))
+	}
+	if len(sim.Skipped) != 2 {
+		t.Errorf("Skipped = %v, want daemonset and finished pod", sim.Skipped)
+	}
+	if sim.CandidateNodes != 1 {
+		t.Errorf("CandidateNodes = %d, want 1", sim.CandidateNodes)
+	}
+	if sim.TotalCPURequest != 3500 {
+		t.Errorf("TotalCPURequest = %d, want 3500", sim.TotalCPURequest)
+	}
+	if sim.Count(DrainReschedulable) != 1 || sim.Count(DrainNoCapacity) != 1 {
+		t.Errorf("counts: reschedulable=%d noCapacity=%d, want 1/1",
+			sim.Count(DrainReschedulable), sim.Count(DrainNoCapacity))
+	}
+}
+
+func TestSimulateDrain_NodeNotFound(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	ctx := context.Background()
+	_, err := SimulateDrain(ctx, clientset, "missing")
+	if err == nil {
+		t.Error("SimulateDrain() should return error for nonexistent node")
+	}
+}
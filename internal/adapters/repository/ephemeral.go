@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// BusyboxImage is the small debug image offered next to NetshootImage.
+const BusyboxImage = "busybox:1.36"
+
+// DebugImages are the images AddEphemeralContainer is offered with, the
+// smallest first.
+var DebugImages = []string{BusyboxImage, NetshootImage}
+
+// DebugContainerPrefix starts the name of the debug containers
+// AddEphemeralContainer injects.
+const DebugContainerPrefix = "k1s-debug-"
+
+// ErrEphemeralContainersUnsupported is returned by AddEphemeralContainer
+// when the cluster has no ephemeralcontainers subresource, as before
+// Kubernetes 1.23 without the EphemeralContainers feature gate.
+var ErrEphemeralContainersUnsupported = errors.New("ephemeral containers are not supported by this cluster")
+
+// ErrEphemeralContainersForbidden is returned by AddEphemeralContainer when
+// RBAC does not allow patching pods/ephemeralcontainers.
+var ErrEphemeralContainersForbidden = errors.New("not allowed to add ephemeral containers (needs patch on pods/ephemeralcontainers)")
+
+// ErrDebugContainerNotRunning is returned by AddEphemeralContainer when the
+// debug container stops or can't be started.
+var ErrDebugContainerNotRunning = errors.New("debug container is not running")
+
+// AddEphemeralContainer injects an ephemeral container running image into
+// a pod, like kubectl debug, and waits for it to start, returning its name.
+// With a target container it shares that container's process namespace,
+// so the tools of image can inspect a distroless container's processes
+// and files under /proc/1/root. The container exits on its own after a
+// while, since ephemeral containers can't be removed.
+func AddEphemeralContainer(ctx context.Context, clientset kubernetes.Interface, namespace, pod, image, target string) (string, error) {
+	pods := clientset.CoreV1().Pods(namespace)
+	p, err := pods.Get(ctx, pod, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod: %w", err)
+	}
+	if target != "" && !hasContainer(p, target) {
+		return "", fmt.Errorf("container %q not found in pod %s", target, pod)
+	}
+
+	name := fmt.Sprintf("%s%d", DebugContainerPrefix, time.Now().Unix())
+	container := corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:    name,
+			Image:   image,
+			Command: []string{"sleep", strconv.Itoa(int(netshootLifetime / time.Second))},
+		},
+		TargetContainerName: target,
+	}
+	// A strategic merge patch adds the container to the ones the pod
+	// already has, keyed by name
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{"ephemeralContainers": []corev1.EphemeralContainer{container}},
+	})
+	if err != nil {
+		//coverage:ignore
+		return "", fmt.Errorf("failed to build patch: %w", err)
+	}
+	_, err = pods.Patch(ctx, pod, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "ephemeralcontainers")
+	switch {
+	case apierrors.IsNotFound(err), apierrors.IsMethodNotSupported(err):
+		// The pod exists, so it is the subresource that is missing
+		return "", fmt.Errorf("%w: %v", ErrEphemeralContainersUnsupported, err)
+	case apierrors.IsForbidden(err):
+		return "", fmt.Errorf("%w: %v", ErrEphemeralContainersForbidden, err)
+	case err != nil:
+		return "", fmt.Errorf("failed to add debug container: %w", err)
+	}
+
+	if err := waitForEphemeralContainer(ctx, pods, pod, name, ErrDebugContainerNotRunning); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// hasContainer reports whether p has a regular or init container named name.
+func hasContainer(p *corev1.Pod, name string) bool {
+	for _, c := range append(append([]corev1.Container{}, p.Spec.Containers...), p.Spec.InitContainers...) {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForEphemeralContainer polls a pod until its ephemeral container name
+// runs. A container that terminates or can't pull its image fails with
+// notRunning and the reason.
+func waitForEphemeralContainer(ctx context.Context, pods typedcorev1.PodInterface, pod, name string, notRunning error) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		p, err := pods.Get(ctx, pod, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get pod: %w", err)
+		}
+		for _, status := range p.Status.EphemeralContainerStatuses {
+			if status.Name != name {
+				continue
+			}
+			switch {
+			case status.State.Running != nil:
+				return nil
+			case status.State.Terminated != nil:
+				return fmt.Errorf("%w: %s", notRunning, status.State.Terminated.Reason)
+			case status.State.Waiting != nil && imagePullReasons[status.State.Waiting.Reason]:
+				return fmt.Errorf("%w: %s: %s", notRunning, status.State.Waiting.Reason, status.State.Waiting.Message)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("container %s did not start: %w", name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func debugTargetPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "gcr.io/distroless/static"}}},
+	}
+}
+
+// runEphemeralOnGet reports every ephemeral container of a pod as running
+// when the pod is read back, as the kubelet would once it started them.
+func runEphemeralOnGet(clientset *fake.Clientset) {
+	clientset.PrependReactor("get", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		get := action.(k8stesting.GetAction)
+		obj, err := clientset.Tracker().Get(corev1.SchemeGroupVersion.WithResource("pods"), get.GetNamespace(), get.GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		pod := obj.(*corev1.Pod).DeepCopy()
+		for _, c := range pod.Spec.EphemeralContainers {
+			pod.Status.EphemeralContainerStatuses = append(pod.Status.EphemeralContainerStatuses, corev1.ContainerStatus{
+				Name:  c.Name,
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			})
+		}
+		return true, pod, nil
+	})
+}
+
+func TestAddEphemeralContainer(t *testing.T) {
+	clientset := fake.NewSimpleClientset(debugTargetPod())
+	runEphemeralOnGet(clientset)
+	var patch k8stesting.PatchAction
+	clientset.PrependReactor("patch", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch = action.(k8stesting.PatchAction)
+		return false, nil, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	name, err := AddEphemeralContainer(ctx, clientset, "shop", "web-1", BusyboxImage, "app")
+	if err != nil {
+		t.Fatalf("AddEphemeralContainer() error = %v", err)
+	}
+	if !strings.HasPrefix(name, DebugContainerPrefix) {
+		t.Errorf("name = %q, want the %s prefix", name, DebugContainerPrefix)
+	}
+
+	if patch == nil {
+		t.Fatal("the pod should have been patched")
+	}
+	if patch.GetSubresource() != "ephemeralcontainers" || patch.GetPatchType() != types.StrategicMergePatchType {
+		t.Errorf("patched %q with %s, want a strategic merge patch of ephemeralcontainers", patch.GetSubresource(), patch.GetPatchType())
+	}
+	var body struct {
+		Spec struct {
+			EphemeralContainers []corev1.EphemeralContainer `json:"ephemeralContainers"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(patch.GetPatch(), &body); err != nil {
+		t.Fatalf("patch is not JSON: %v", err)
+	}
+	if len(body.Spec.EphemeralContainers) != 1 {
+		t.Fatalf("patch adds %d containers, want 1: %s", len(body.Spec.EphemeralContainers), patch.GetPatch())
+	}
+	added := body.Spec.EphemeralContainers[0]
+	if added.Name != name || added.Image != BusyboxImage || added.TargetContainerName != "app" {
+		t.Errorf("patch adds %+v, want %s running %s targeting app", added, name, BusyboxImage)
+	}
+
+	got, _ := clientset.CoreV1().Pods("shop").Get(ctx, "web-1", metav1.GetOptions{})
+	info := podToPodInfo(got)
+	if len(info.EphemeralContainers) != 1 || info.EphemeralContainers[0].TargetContainer != "app" || info.EphemeralContainers[0].State != "Running" {
+		t.Errorf("EphemeralContainers = %+v, want the running debug container targeting app", info.EphemeralContainers)
+	}
+}
+
+func TestAddEphemeralContainer_UnknownTarget(t *testing.T) {
+	clientset := fake.NewSimpleClientset(debugTargetPod())
+
+	_, err := AddEphemeralContainer(context.Background(), clientset, "shop", "web-1", BusyboxImage, "sidecar")
+	if err == nil || !strings.Contains(err.Error(), `"sidecar" not found`) {
+		t.Errorf("AddEphemeralContainer() error = %v, want the missing target named", err)
+	}
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "patch" {
+			t.Error("a pod without the target container should not be patched")
+		}
+	}
+}
+
+func TestAddEphemeralContainer_Errors(t *testing.T) {
+	resource := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"subresource missing", apierrors.NewNotFound(resource, "web-1"), ErrEphemeralContainersUnsupported},
+		{"method not supported", apierrors.NewMethodNotSupported(resource, "patch"), ErrEphemeralContainersUnsupported},
+		{"forbidden by RBAC", apierrors.NewForbidden(resource, "web-1", errors.New("cannot patch pods/ephemeralcontainers")), ErrEphemeralContainersForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(debugTargetPod())
+			clientset.PrependReactor("patch", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, tt.err
+			})
+
+			_, err := AddEphemeralContainer(context.Background(), clientset, "shop", "web-1", BusyboxImage, "app")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("AddEphemeralContainer() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddEphemeralContainer_PullFailure(t *testing.T) {
+	clientset := fake.NewSimpleClientset(debugTargetPod())
+	clientset.PrependReactor("get", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj, err := clientset.Tracker().Get(corev1.SchemeGroupVersion.WithResource("pods"), "shop", "web-1")
+		if err != nil {
+			return true, nil, err
+		}
+		pod := obj.(*corev1.Pod).DeepCopy()
+		for _, c := range pod.Spec.EphemeralContainers {
+			pod.Status.EphemeralContainerStatuses = append(pod.Status.EphemeralContainerStatuses, corev1.ContainerStatus{
+				Name:  c.Name,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull", Message: "not found"}},
+			})
+		}
+		return true, pod, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := AddEphemeralContainer(ctx, clientset, "shop", "web-1", "busybox:missing", "app")
+	if !errors.Is(err, ErrDebugContainerNotRunning) || !strings.Contains(err.Error(), "ErrImagePull") {
+		t.Errorf("AddEphemeralContainer() error = %v, want ErrDebugContainerNotRunning with the pull error", err)
+	}
+}
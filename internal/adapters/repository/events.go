@@ -6,17 +6,21 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 )
 
 // EventInfo represents a Kubernetes event with formatted fields.
 // Events provide insight into what's happening with pods and other resources.
 type EventInfo struct {
+	Name      string    // Name of the Event object; a repeated event keeps its name
 	Type      string    // "Normal" or "Warning"
 	Reason    string    // Short reason code (e.g., "Pulled", "Started", "Failed")
 	Message   string    // Human-readable description of the event
 	Source    string    // Component that generated the event (e.g., "kubelet")
+	Host      string    // Node or instance of the component, when it reports one
 	Age       string    // Human-readable age (e.g., "5m", "2h", "3d")
 	Count     int32     // Number of times this event has occurred
 	FirstSeen time.Time // When the event was first observed
@@ -38,6 +42,123 @@ func GetPodEvents(ctx context.Context, clientset kubernetes.Interface, namespace
 	return eventsToEventInfo(events.Items), nil
 }
 
+// WatchPodEvents follows the events of a pod as they are recorded. The
+// events that already exist are sent first, then every added or updated
+// event (a repeated event is sent again with its new Count and LastSeen).
+// Deleted events are not reported.
+//
+// When the watch ends it is resumed from the last resourceVersion seen;
+// when that version has expired (410 Gone) the events are listed again
+// and all of them are resent. Errors are retried with exponential backoff.
+// Events are sent until ctx is done or cancel is called, then the channel
+// is closed.
+func WatchPodEvents(ctx context.Context, clientset kubernetes.Interface, namespace, podName string) (<-chan EventInfo, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	events := make(chan EventInfo, 64)
+
+	go func() {
+		defer close(events)
+
+		selector := "involvedObject.name=" + podName
+		resourceVersion := ""
+		relist := true
+		backoff := streamBackoffMin
+		for {
+			ok := false
+			if relist {
+				list, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+				if err == nil {
+					for _, e := range eventsToEventInfo(list.Items) {
+						if !sendEvent(ctx, events, e) {
+							return
+						}
+					}
+					resourceVersion = list.ResourceVersion
+					relist = false
+					ok = true
+				}
+			}
+			if !relist {
+				var expired bool
+				expired, ok = watchEventsOnce(ctx, clientset, namespace, podName, selector, &resourceVersion, events)
+				relist = expired
+			}
+			if ok {
+				backoff = streamBackoffMin
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if !ok {
+				backoff = min(backoff*2, streamBackoffMax)
+			}
+		}
+	}()
+
+	return events, cancel
+}
+
+// watchEventsOnce runs a single watch from *resourceVersion until it ends,
+// sending added and modified events of the pod and advancing
+// *resourceVersion. Returns expired if the resourceVersion is too old to
+// watch from, and ok if the watch was opened.
+func watchEventsOnce(ctx context.Context, clientset kubernetes.Interface, namespace, podName, selector string, resourceVersion *string, out chan<- EventInfo) (expired, ok bool) {
+	w, err := clientset.CoreV1().Events(namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector:   selector,
+		ResourceVersion: *resourceVersion,
+	})
+	if err != nil {
+		return isExpired(err), false
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, true
+		case ev, open := <-w.ResultChan():
+			if !open {
+				return false, true
+			}
+			switch ev.Type {
+			case watch.Error:
+				return isExpired(apierrors.FromObject(ev.Object)), true
+			case watch.Added, watch.Modified, watch.Deleted, watch.Bookmark:
+				e, isEvent := ev.Object.(*corev1.Event)
+				if !isEvent {
+					continue
+				}
+				*resourceVersion = e.ResourceVersion
+				if ev.Type == watch.Deleted || ev.Type == watch.Bookmark || e.InvolvedObject.Name != podName {
+					continue
+				}
+				if !sendEvent(ctx, out, eventsToEventInfo([]corev1.Event{*e})[0]) {
+					return false, true
+				}
+			}
+		}
+	}
+}
+
+// isExpired reports whether err means a watch's resourceVersion is no
+// longer available and the resource must be listed again.
+func isExpired(err error) bool {
+	return apierrors.IsGone(err) || apierrors.IsResourceExpired(err)
+}
+
+// sendEvent sends e unless ctx is done first. Returns false if it was not sent.
+func sendEvent(ctx context.Context, out chan<- EventInfo, e EventInfo) bool {
+	select {
+	case out <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 // GetWorkloadEvents retrieves events for a workload and its managed pods.
 // This is useful for seeing the full picture of deployment or statefulset health.
 func GetWorkloadEvents(ctx context.Context, clientset kubernetes.Interface, workload WorkloadInfo) ([]EventInfo, error) {
@@ -108,12 +229,19 @@ func eventsToEventInfo(events []corev1.Event) []EventInfo {
 		if lastSeen.IsZero() {
 			lastSeen = firstSeen
 		}
+		// Newer events name their reporter instead of a source
+		source, host := e.Source.Component, e.Source.Host
+		if source == "" {
+			source, host = e.ReportingController, e.ReportingInstance
+		}
 
 		result = append(result, EventInfo{
+			Name:      e.Name,
 			Type:      e.Type,
 			Reason:    e.Reason,
 			Message:   e.Message,
-			Source:    e.Source.Component,
+			Source:    source,
+			Host:      host,
 			Age:       formatAge(lastSeen),
 			Count:     e.Count,
 			FirstSeen: firstSeen,
@@ -154,3 +282,121 @@ func GetRecentWarnings(ctx context.Context, clientset kubernetes.Interface, name
 	}
 	return warnings, nil
 }
+
+// EventGroup is the events recorded about one object with the same reason,
+// shown as a single row with their combined count.
+type EventGroup struct {
+	Object   string      // The object the events are about (e.g., "Pod/my-pod")
+	Reason   string      // Reason shared by the events
+	Type     string      // "Warning" when any of the events is a Warning
+	Count    int32       // Sum of the event counts (an event without a count counts once)
+	LastSeen time.Time   // Most recent LastSeen of the events
+	Events   []EventInfo // The individual events, most recent first
+}
+
+// GroupEvents collapses events with the same involved object and reason
+// into groups. Warning groups come first, then groups are sorted by count
+// (highest first) and by most recently seen.
+func GroupEvents(events []EventInfo) []EventGroup {
+	var groups []EventGroup
+	index := make(map[string]int)
+	for _, e := range events {
+		key := e.Object + "\x00" + e.Reason
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, EventGroup{Object: e.Object, Reason: e.Reason, Type: e.Type})
+		}
+		g := &groups[i]
+		if IsWarningEvent(e) {
+			g.Type = "Warning"
+		}
+		count := e.Count
+		if count < 1 {
+			count = 1
+		}
+		g.Count += count
+		if e.LastSeen.After(g.LastSeen) {
+			g.LastSeen = e.LastSeen
+		}
+		g.Events = append(g.Events, e)
+	}
+
+	for i := range groups {
+		sort.SliceStable(groups[i].Events, func(a, b int) bool {
+			return groups[i].Events[a].LastSeen.After(groups[i].Events[b].LastSeen)
+		})
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		wi, wj := groups[i].Type == "Warning", groups[j].Type == "Warning"
+		if wi != wj {
+			return wi
+		}
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].LastSeen.After(groups[j].LastSeen)
+	})
+	return groups
+}
+
+// RecentWarningsWindow is how far back the namespace warnings badge looks.
+const RecentWarningsWindow = 15 * time.Minute
+
+// ObjectWarnings is the warning events recorded about one object.
+type ObjectWarnings struct {
+	Object   string      // The object the events are about (e.g., "Pod/my-pod")
+	Count    int32       // Sum of the event counts (an event without a count counts once)
+	LastSeen time.Time   // Most recent LastSeen of the events
+	Reasons  []string    // Distinct reasons, most recent first
+	Events   []EventInfo // The individual events, most recent first
+}
+
+// GroupWarningsByObject collapses warning events by involved object. The
+// objects with the most warnings come first, then the most recently seen.
+func GroupWarningsByObject(events []EventInfo) []ObjectWarnings {
+	var groups []ObjectWarnings
+	index := make(map[string]int)
+	for _, e := range events {
+		i, ok := index[e.Object]
+		if !ok {
+			i = len(groups)
+			index[e.Object] = i
+			groups = append(groups, ObjectWarnings{Object: e.Object})
+		}
+		g := &groups[i]
+		count := e.Count
+		if count < 1 {
+			count = 1
+		}
+		g.Count += count
+		if e.LastSeen.After(g.LastSeen) {
+			g.LastSeen = e.LastSeen
+		}
+		g.Events = append(g.Events, e)
+	}
+	for i := range groups {
+		g := &groups[i]
+		sort.SliceStable(g.Events, func(a, b int) bool {
+			return g.Events[a].LastSeen.After(g.Events[b].LastSeen)
+		})
+		seen := make(map[string]bool)
+		for _, e := range g.Events {
+			if !seen[e.Reason] {
+				seen[e.Reason] = true
+				g.Reasons = append(g.Reasons, e.Reason)
+			}
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		if !groups[i].LastSeen.Equal(groups[j].LastSeen) {
+			return groups[i].LastSeen.After(groups[j].LastSeen)
+		}
+		return groups[i].Object < groups[j].Object
+	})
+	return groups
+}
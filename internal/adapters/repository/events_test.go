@@ -2,12 +2,15 @@ package repository
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestGetPodEvents(t *testing.T) {
@@ -325,9 +328,11 @@ func TestEventsToEventInfo_EventTime(t *testing.T) {
 				Kind: "Pod",
 				Name: "test-pod",
 			},
-			Type:      "Normal",
-			Reason:    "Started",
-			EventTime: metav1.MicroTime{Time: now},
+			Type:                "Normal",
+			Reason:              "Started",
+			EventTime:           metav1.MicroTime{Time: now},
+			ReportingController: "kubelet",
+			ReportingInstance:   "node-1",
 			// FirstTimestamp is zero
 		},
 	}
@@ -342,6 +347,10 @@ func TestEventsToEventInfo_EventTime(t *testing.T) {
 	if result[0].FirstSeen.IsZero() {
 		t.Error("FirstSeen should not be zero when EventTime is set")
 	}
+	// Without a source the reporter is the source
+	if result[0].Source != "kubelet" || result[0].Host != "node-1" {
+		t.Errorf("source = %q on %q, want the reporting controller and instance", result[0].Source, result[0].Host)
+	}
 }
 
 func TestGetWorkloadEvents_StatefulSet(t *testing.T) {
@@ -546,3 +555,224 @@ func TestEventInfoStruct(t *testing.T) {
 		t.Errorf("Count = %d, want 5", event.Count)
 	}
 }
+
+// podEvent returns an event about the pod "web" for watch tests.
+func podEvent(name, reason string, count int32) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web"},
+		Type:           "Warning",
+		Reason:         reason,
+		LastTimestamp:  metav1.Now(),
+		Count:          count,
+	}
+}
+
+// nextEvent returns the next event sent on events, failing the test if
+// none arrives within a second.
+func nextEvent(t *testing.T, events <-chan EventInfo) EventInfo {
+	t.Helper()
+	select {
+	case e, ok := <-events:
+		if !ok {
+			t.Fatal("event watch stopped")
+		}
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+	return EventInfo{}
+}
+
+func TestWatchPodEvents(t *testing.T) {
+	shortStreamBackoff(t)
+	clientset := fake.NewSimpleClientset(podEvent("web.1", "BackOff", 1))
+
+	// Every watch gets its own fake watcher so the test can drive it
+	watchers := make(chan *watch.FakeWatcher, 4)
+	clientset.PrependWatchReactor("events", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		w := watch.NewFake()
+		watchers <- w
+		return true, w, nil
+	})
+
+	events, cancel := WatchPodEvents(context.Background(), clientset, "default", "web")
+	defer cancel()
+
+	if e := nextEvent(t, events); e.Name != "web.1" || e.Reason != "BackOff" {
+		t.Fatalf("first event = %s/%s, want the listed web.1/BackOff", e.Name, e.Reason)
+	}
+
+	w := <-watchers
+	w.Add(podEvent("web.2", "Unhealthy", 1))
+	if e := nextEvent(t, events); e.Name != "web.2" || e.Reason != "Unhealthy" {
+		t.Errorf("added event = %s/%s, want web.2/Unhealthy", e.Name, e.Reason)
+	}
+
+	w.Modify(podEvent("web.1", "BackOff", 3))
+	if e := nextEvent(t, events); e.Name != "web.1" || e.Count != 3 {
+		t.Errorf("modified event = %s count %d, want web.1 count 3", e.Name, e.Count)
+	}
+
+	// Events of other objects are not sent
+	other := podEvent("db.1", "Killing", 1)
+	other.InvolvedObject.Name = "db"
+	w.Add(other)
+	w.Add(podEvent("web.3", "Pulled", 1))
+	if e := nextEvent(t, events); e.Name != "web.3" {
+		t.Errorf("event after another pod's = %s, want web.3", e.Name)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatchPodEvents_RelistsWhenExpired(t *testing.T) {
+	shortStreamBackoff(t)
+	clientset := fake.NewSimpleClientset(podEvent("web.1", "BackOff", 1))
+
+	watchers := make(chan *watch.FakeWatcher, 4)
+	clientset.PrependWatchReactor("events", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		w := watch.NewFake()
+		watchers <- w
+		return true, w, nil
+	})
+
+	events, cancel := WatchPodEvents(context.Background(), clientset, "default", "web")
+	defer cancel()
+	nextEvent(t, events)
+
+	// An event the watch never sees is recorded, then the watch's
+	// resourceVersion is reported as gone
+	if err := clientset.Tracker().Add(podEvent("web.2", "Unhealthy", 1)); err != nil {
+		t.Fatalf("failed to add event: %v", err)
+	}
+	w := <-watchers
+	w.Error(&metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   410,
+		Reason: metav1.StatusReasonExpired,
+	})
+
+	// The re-list resends everything, including the event the watch missed
+	seen := make(map[string]bool)
+	for len(seen) < 2 {
+		seen[nextEvent(t, events).Name] = true
+	}
+	if !seen["web.1"] || !seen["web.2"] {
+		t.Errorf("re-listed events = %v, want web.1 and web.2", seen)
+	}
+
+	// The watch resumes after the re-list
+	w = <-watchers
+	w.Add(podEvent("web.3", "Pulled", 1))
+	if e := nextEvent(t, events); e.Name != "web.3" {
+		t.Errorf("event after re-list = %s, want web.3", e.Name)
+	}
+}
+
+func TestEventsToEventInfo_Name(t *testing.T) {
+	events := eventsToEventInfo([]corev1.Event{*podEvent("web.1", "BackOff", 1)})
+	if len(events) != 1 || events[0].Name != "web.1" {
+		t.Errorf("eventsToEventInfo() = %+v, want the event named web.1", events)
+	}
+}
+
+func TestGroupEvents(t *testing.T) {
+	now := time.Now()
+	events := []EventInfo{
+		{Name: "a1", Type: "Normal", Reason: "Pulled", Object: "Pod/web", Count: 1, LastSeen: now.Add(-5 * time.Minute)},
+		{Name: "b1", Type: "Warning", Reason: "BackOff", Object: "Pod/web", Count: 3, LastSeen: now.Add(-4 * time.Minute)},
+		{Name: "c1", Type: "Warning", Reason: "Unhealthy", Object: "Pod/web", Count: 2, LastSeen: now.Add(-1 * time.Minute)},
+		{Name: "b2", Type: "Warning", Reason: "BackOff", Object: "Pod/web", Count: 4, LastSeen: now.Add(-2 * time.Minute)},
+		{Name: "d1", Type: "Warning", Reason: "BackOff", Object: "Pod/api", Count: 0, LastSeen: now},
+		{Name: "a2", Type: "Normal", Reason: "Pulled", Object: "Pod/web", Count: 5, LastSeen: now.Add(-3 * time.Minute)},
+	}
+
+	groups := GroupEvents(events)
+	if len(groups) != 4 {
+		t.Fatalf("got %d groups, want 4", len(groups))
+	}
+
+	want := []struct {
+		object, reason string
+		count          int32
+		events         []string
+	}{
+		{"Pod/web", "BackOff", 7, []string{"b2", "b1"}},
+		{"Pod/web", "Unhealthy", 2, []string{"c1"}},
+		{"Pod/api", "BackOff", 1, []string{"d1"}},
+		{"Pod/web", "Pulled", 6, []string{"a2", "a1"}},
+	}
+	for i, w := range want {
+		g := groups[i]
+		if g.Object != w.object || g.Reason != w.reason || g.Count != w.count {
+			t.Errorf("groups[%d] = %s %s x%d, want %s %s x%d", i, g.Object, g.Reason, g.Count, w.object, w.reason, w.count)
+		}
+		var names []string
+		for _, e := range g.Events {
+			names = append(names, e.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(w.events, ",") {
+			t.Errorf("groups[%d] events = %v, want %v", i, names, w.events)
+		}
+	}
+
+	if !groups[0].LastSeen.Equal(now.Add(-2 * time.Minute)) {
+		t.Errorf("LastSeen = %v, want the latest occurrence", groups[0].LastSeen)
+	}
+	if groups[3].Type != "Normal" {
+		t.Errorf("Pulled group Type = %q, want Normal", groups[3].Type)
+	}
+}
+
+func TestGroupEvents_Empty(t *testing.T) {
+	if groups := GroupEvents(nil); len(groups) != 0 {
+		t.Errorf("GroupEvents(nil) = %v, want no groups", groups)
+	}
+}
+
+func TestGroupWarningsByObject(t *testing.T) {
+	now := time.Now()
+	events := []EventInfo{
+		{Name: "a", Type: "Warning", Reason: "BackOff", Object: "Pod/web", Count: 3, LastSeen: now.Add(-4 * time.Minute)},
+		{Name: "b", Type: "Warning", Reason: "FailedMount", Object: "Pod/db", Count: 1, LastSeen: now},
+		{Name: "c", Type: "Warning", Reason: "Unhealthy", Object: "Pod/web", Count: 0, LastSeen: now.Add(-1 * time.Minute)},
+		{Name: "d", Type: "Warning", Reason: "BackOff", Object: "Pod/web", Count: 2, LastSeen: now.Add(-2 * time.Minute)},
+		{Name: "e", Type: "Warning", Reason: "FailedScheduling", Object: "Pod/api", Count: 1, LastSeen: now.Add(-3 * time.Minute)},
+	}
+
+	groups := GroupWarningsByObject(events)
+	if len(groups) != 3 {
+		t.Fatalf("got %d groups, want 3", len(groups))
+	}
+	want := []struct {
+		object  string
+		count   int32
+		reasons string
+		events  string
+	}{
+		{"Pod/web", 6, "Unhealthy,BackOff", "c,d,a"},
+		{"Pod/db", 1, "FailedMount", "b"},
+		{"Pod/api", 1, "FailedScheduling", "e"},
+	}
+	for i, w := range want {
+		g := groups[i]
+		var names []string
+		for _, e := range g.Events {
+			names = append(names, e.Name)
+		}
+		if g.Object != w.object || g.Count != w.count || strings.Join(g.Reasons, ",") != w.reasons || strings.Join(names, ",") != w.events {
+			t.Errorf("groups[%d] = %s x%d reasons %v events %v, want %s x%d reasons %s events %s",
+				i, g.Object, g.Count, g.Reasons, names, w.object, w.count, w.reasons, w.events)
+		}
+	}
+	if !groups[0].LastSeen.Equal(now.Add(-1 * time.Minute)) {
+		t.Errorf("LastSeen = %v, want the most recent event", groups[0].LastSeen)
+	}
+
+	if groups := GroupWarningsByObject(nil); len(groups) != 0 {
+		t.Errorf("GroupWarningsByObject(nil) = %v, want none", groups)
+	}
+}
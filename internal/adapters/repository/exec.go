@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// ExecShells are the shells ExecShell tries, in order.
+var ExecShells = []string{"/bin/bash", "/bin/sh"}
+
+// ErrNoShell is returned by ExecShell when none of ExecShells exists in the
+// container, as in distroless and scratch images.
+var ErrNoShell = errors.New("no shell found in container")
+
+// ExecOptions are the streams attached to a command run in a container.
+// Nil streams are not attached. With TTY set, the container's stderr is
+// merged into stdout, so Stderr is ignored.
+type ExecOptions struct {
+	Stdin         io.Reader
+	Stdout        io.Writer
+	Stderr        io.Writer
+	TTY           bool
+	TerminalSizes remotecommand.TerminalSizeQueue // Resize events for the TTY; may be nil
+}
+
+// ExecIntoPod runs command in a container of a pod, like kubectl exec, and
+// blocks until the command exits or ctx is cancelled. A non-zero exit of
+// the command is returned as an error.
+func ExecIntoPod(ctx context.Context, config *rest.Config, namespace, pod, container string, command []string, opts ExecOptions) error {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	stderr := opts.Stderr
+	if opts.TTY {
+		stderr = nil
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     opts.Stdin != nil,
+			Stdout:    opts.Stdout != nil,
+			Stderr:    stderr != nil,
+			TTY:       opts.TTY,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:             opts.Stdin,
+		Stdout:            opts.Stdout,
+		Stderr:            stderr,
+		Tty:               opts.TTY,
+		TerminalSizeQueue: opts.TerminalSizes,
+	})
+}
+
+// ExecShell opens an interactive shell in a container, trying each of
+// ExecShells until one exists. It returns ErrNoShell, wrapped with the
+// shells tried, when the container has none of them.
+func ExecShell(ctx context.Context, config *rest.Config, namespace, pod, container string, opts ExecOptions) error {
+	return execShell(func(shell string) error {
+		return ExecIntoPod(ctx, config, namespace, pod, container, []string{shell}, opts)
+	})
+}
+
+// execShell runs the first of ExecShells that exists using run.
+func execShell(run func(shell string) error) error {
+	for _, shell := range ExecShells {
+		err := run(shell)
+		if err == nil || !isMissingShell(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("%w (tried %s)", ErrNoShell, strings.Join(ExecShells, ", "))
+}
+
+// isMissingShell reports whether err means the exec'd binary does not
+// exist. Container runtimes only report this as text, e.g. containerd's
+// `exec: "/bin/bash": stat /bin/bash: no such file or directory`.
+func isMissingShell(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{
+		"no such file or directory",
+		"executable file not found",
+		"not found in $path",
+	} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
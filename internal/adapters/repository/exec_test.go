@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsMissingShell(t *testing.T) {
+	tests := []struct {
+		err  string
+		want bool
+	}{
+		{`OCI runtime exec failed: exec failed: unable to start container process: exec: "/bin/bash": stat /bin/bash: no such file or directory: unknown`, true},
+		{`exec: "/bin/bash": executable file not found in $PATH`, true},
+		{`command terminated with exit code 1`, false},
+		{`error dialing backend: dial tcp 10.0.0.1:10250: i/o timeout`, false},
+	}
+
+	for _, tt := range tests {
+		if got := isMissingShell(errors.New(tt.err)); got != tt.want {
+			t.Errorf("isMissingShell(%q) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestExecShell_FallsBack(t *testing.T) {
+	var tried []string
+	err := execShell(func(shell string) error {
+		tried = append(tried, shell)
+		if shell == "/bin/bash" {
+			return errors.New(`exec: "/bin/bash": stat /bin/bash: no such file or directory`)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("execShell() error = %v", err)
+	}
+	if len(tried) != 2 || tried[0] != "/bin/bash" || tried[1] != "/bin/sh" {
+		t.Errorf("execShell() tried %v, want bash then sh", tried)
+	}
+}
+
+func TestExecShell_NoShell(t *testing.T) {
+	err := execShell(func(shell string) error {
+		return errors.New("executable file not found in $PATH")
+	})
+	if !errors.Is(err, ErrNoShell) {
+		t.Errorf("execShell() error = %v, want ErrNoShell", err)
+	}
+}
+
+func TestExecShell_OtherErrorStops(t *testing.T) {
+	calls := 0
+	want := errors.New("command terminated with exit code 130")
+	err := execShell(func(shell string) error {
+		calls++
+		return want
+	})
+	if err != want || calls != 1 {
+		t.Errorf("execShell() = %v after %d calls, want the first error after 1 call", err, calls)
+	}
+}
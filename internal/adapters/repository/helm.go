@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Labels and annotations Helm 3 sets on what it installs, and on the
+// Secrets it stores releases in.
+const (
+	helmManagedByLabel      = "app.kubernetes.io/managed-by"
+	helmInstanceLabel       = "app.kubernetes.io/instance"
+	helmReleaseAnnotation   = "meta.helm.sh/release-name"
+	helmReleaseSecretType   = "helm.sh/release.v1"
+	helmReleaseSecretPrefix = "sh.helm.release.v1."
+)
+
+// HelmRelease is a revision of a Helm release, as Helm stores it in a
+// Secret of the release's namespace.
+type HelmRelease struct {
+	Name          string
+	Namespace     string
+	Revision      int
+	Status        string // "deployed", "failed", "pending-upgrade", ...
+	Description   string // e.g. "Upgrade complete"
+	Chart         string
+	ChartVersion  string
+	AppVersion    string
+	FirstDeployed time.Time
+	LastDeployed  time.Time
+	Values        map[string]any // Values supplied on install or upgrade, not the chart's defaults
+}
+
+// HelmReleaseName returns the Helm release that installed an object with
+// labels and annotations: the meta.helm.sh/release-name annotation of an
+// object managed by Helm, or its app.kubernetes.io/instance label for
+// charts installed before Helm set the annotation. "" when Helm doesn't
+// manage it.
+func HelmReleaseName(labels, annotations map[string]string) string {
+	if !strings.EqualFold(labels[helmManagedByLabel], "Helm") {
+		return ""
+	}
+	if name := annotations[helmReleaseAnnotation]; name != "" {
+		return name
+	}
+	return labels[helmInstanceLabel]
+}
+
+// GetHelmRelease reads the latest revision of a Helm 3 release from the
+// sh.helm.release.v1.<release>.v<revision> Secrets of its namespace, which
+// needs permission to get Secrets there.
+func GetHelmRelease(ctx context.Context, clientset kubernetes.Interface, namespace, releaseName string) (*HelmRelease, error) {
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "owner=helm,name=" + releaseName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the secrets of release %s: %w", releaseName, err)
+	}
+
+	latest, revision := -1, 0
+	for i, s := range secrets.Items {
+		if s.Type != helmReleaseSecretType || !strings.HasPrefix(s.Name, helmReleaseSecretPrefix+releaseName+".v") {
+			continue
+		}
+		rev, err := strconv.Atoi(strings.TrimPrefix(s.Name, helmReleaseSecretPrefix+releaseName+".v"))
+		if err != nil {
+			continue
+		}
+		if latest < 0 || rev > revision {
+			latest, revision = i, rev
+		}
+	}
+	if latest < 0 {
+		return nil, fmt.Errorf("no Helm release %s in namespace %s", releaseName, namespace)
+	}
+
+	release, err := DecodeHelmRelease(secrets.Items[latest].Data["release"])
+	if err != nil {
+		return nil, fmt.Errorf("release %s revision %d: %w", releaseName, revision, err)
+	}
+	return release, nil
+}
+
+// helmReleaseJSON is the part of Helm's release record k1s reads.
+type helmReleaseJSON struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Info      struct {
+		Status        string    `json:"status"`
+		Description   string    `json:"description"`
+		FirstDeployed time.Time `json:"first_deployed"`
+		LastDeployed  time.Time `json:"last_deployed"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+	Config map[string]any `json:"config"`
+}
+
+// DecodeHelmRelease decodes the "release" key of a Helm release Secret:
+// base64 on top of the Secret's own encoding, around a gzipped JSON record.
+func DecodeHelmRelease(data []byte) (*HelmRelease, error) {
+	raw, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid release encoding: %w", err)
+	}
+	// Helm gzips releases since 3.0; accept plain JSON too
+	if bytes.HasPrefix(raw, []byte{0x1f, 0x8b}) {
+		reader, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid release compression: %w", err)
+		}
+		defer reader.Close()
+		if raw, err = io.ReadAll(reader); err != nil {
+			return nil, fmt.Errorf("invalid release compression: %w", err)
+		}
+	}
+
+	var record helmReleaseJSON
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return nil, fmt.Errorf("invalid release record: %w", err)
+	}
+	return &HelmRelease{
+		Name:          record.Name,
+		Namespace:     record.Namespace,
+		Revision:      record.Version,
+		Status:        record.Info.Status,
+		Description:   record.Info.Description,
+		Chart:         record.Chart.Metadata.Name,
+		ChartVersion:  record.Chart.Metadata.Version,
+		AppVersion:    record.Chart.Metadata.AppVersion,
+		FirstDeployed: record.Info.FirstDeployed,
+		LastDeployed:  record.Info.LastDeployed,
+		Values:        record.Config,
+	}, nil
+}
+
+// sensitiveKeyWords are parts of value names that suggest a secret.
+var sensitiveKeyWords = []string{"password", "passwd", "secret", "token", "apikey", "accesskey", "privatekey", "credential", "connectionstring", "dsn"}
+
+// IsSensitiveKey reports whether a value named key looks like it holds a
+// secret, such as "adminPassword", "api_key" or "auth.token".
+func IsSensitiveKey(key string) bool {
+	k := strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r == '.' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(key))
+	for _, word := range sensitiveKeyWords {
+		if strings.Contains(k, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskSensitiveValues returns a copy of values in which every value under
+// a sensitive-looking key (see IsSensitiveKey) is replaced with mask,
+// whole maps and lists included. Other maps and lists are copied as they
+// are masked.
+func MaskSensitiveValues(values map[string]any, mask string) map[string]any {
+	if values == nil {
+		return nil
+	}
+	masked := make(map[string]any, len(values))
+	for k, v := range values {
+		if IsSensitiveKey(k) && v != nil {
+			masked[k] = mask
+			continue
+		}
+		masked[k] = maskValue(v, mask)
+	}
+	return masked
+}
+
+func maskValue(v any, mask string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return MaskSensitiveValues(v, mask)
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = maskValue(item, mask)
+		}
+		return items
+	}
+	return v
+}
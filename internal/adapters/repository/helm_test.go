@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// helmReleaseSecret builds a release Secret as Helm 3 stores it: the JSON
+// record gzipped, then base64-encoded inside the Secret's data.
+func helmReleaseSecret(t *testing.T, release string, revision int, record string) *corev1.Secret {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(record)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", release, revision),
+			Namespace: "default",
+			Labels:    map[string]string{"owner": "helm", "name": release, "version": fmt.Sprint(revision)},
+		},
+		Type: helmReleaseSecretType,
+		Data: map[string][]byte{"release": []byte(base64.StdEncoding.EncodeToString(buf.Bytes()))},
+	}
+}
+
+const helmReleaseRecord = `{
+	"name": "web",
+	"namespace": "default",
+	"version": %d,
+	"info": {
+		"status": "deployed",
+		"description": "Upgrade complete",
+		"first_deployed": "2024-05-01T10:00:00Z",
+		"last_deployed": "2024-05-02T12:30:00Z"
+	},
+	"chart": {
+		"metadata": {"name": "nginx", "version": "15.4.2", "appVersion": "1.25.3"},
+		"values": {"replicaCount": 1}
+	},
+	"config": {"replicaCount": 3, "auth": {"adminPassword": "hunter2"}}
+}`
+
+func TestGetHelmRelease(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		helmReleaseSecret(t, "web", 1, fmt.Sprintf(helmReleaseRecord, 1)),
+		helmReleaseSecret(t, "web", 10, fmt.Sprintf(helmReleaseRecord, 10)),
+		helmReleaseSecret(t, "web", 2, fmt.Sprintf(helmReleaseRecord, 2)),
+		helmReleaseSecret(t, "api", 3, `{"name": "api", "version": 3}`),
+	)
+
+	release, err := GetHelmRelease(context.Background(), clientset, "default", "web")
+	if err != nil {
+		t.Fatalf("GetHelmRelease() error = %v", err)
+	}
+	if release.Revision != 10 {
+		t.Errorf("Revision = %d, want the latest, 10", release.Revision)
+	}
+	if release.Name != "web" || release.Status != "deployed" || release.Description != "Upgrade complete" {
+		t.Errorf("release = %+v", release)
+	}
+	if release.Chart != "nginx" || release.ChartVersion != "15.4.2" || release.AppVersion != "1.25.3" {
+		t.Errorf("chart = %s %s (app %s), want nginx 15.4.2 (app 1.25.3)", release.Chart, release.ChartVersion, release.AppVersion)
+	}
+	if want := time.Date(2024, 5, 2, 12, 30, 0, 0, time.UTC); !release.LastDeployed.Equal(want) {
+		t.Errorf("LastDeployed = %v, want %v", release.LastDeployed, want)
+	}
+	// The values supplied, not the chart's defaults
+	if release.Values["replicaCount"] != float64(3) {
+		t.Errorf("Values = %v, want replicaCount 3", release.Values)
+	}
+
+	if _, err := GetHelmRelease(context.Background(), clientset, "default", "missing"); err == nil {
+		t.Error("GetHelmRelease() of a missing release should fail")
+	}
+}
+
+func TestDecodeHelmRelease(t *testing.T) {
+	plain := base64.StdEncoding.EncodeToString([]byte(`{"name": "web", "version": 4}`))
+
+	tests := []struct {
+		name         string
+		data         string
+		wantRevision int
+		wantErr      bool
+	}{
+		{"uncompressed record", plain, 4, false},
+		{"not base64", "%%%", 0, true},
+		{"not JSON", base64.StdEncoding.EncodeToString([]byte("release")), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release, err := DecodeHelmRelease([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeHelmRelease() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && release.Revision != tt.wantRevision {
+				t.Errorf("Revision = %d, want %d", release.Revision, tt.wantRevision)
+			}
+		})
+	}
+}
+
+func TestHelmReleaseName(t *testing.T) {
+	helm := map[string]string{"app.kubernetes.io/managed-by": "Helm", "app.kubernetes.io/instance": "web-instance"}
+
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		want        string
+	}{
+		{"release annotation", helm, map[string]string{"meta.helm.sh/release-name": "web"}, "web"},
+		{"instance label", helm, nil, "web-instance"},
+		{"not managed by Helm", map[string]string{"app.kubernetes.io/instance": "web"}, map[string]string{"meta.helm.sh/release-name": "web"}, ""},
+		{"no labels", nil, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HelmReleaseName(tt.labels, tt.annotations); got != tt.want {
+				t.Errorf("HelmReleaseName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskSensitiveValues(t *testing.T) {
+	values := map[string]any{
+		"replicaCount": float64(3),
+		"auth": map[string]any{
+			"adminPassword": "hunter2",
+			"username":      "admin",
+		},
+		"api_key": "abc",
+		"tokens":  []any{"a", "b"},
+		"sidecars": []any{
+			map[string]any{"name": "proxy", "clientSecret": "s3cret"},
+		},
+		"emptyToken": nil,
+	}
+
+	masked := MaskSensitiveValues(values, "***")
+	auth := masked["auth"].(map[string]any)
+	if auth["adminPassword"] != "***" || auth["username"] != "admin" {
+		t.Errorf("auth = %v", auth)
+	}
+	if masked["api_key"] != "***" || masked["tokens"] != "***" || masked["replicaCount"] != float64(3) {
+		t.Errorf("masked = %v", masked)
+	}
+	if sidecar := masked["sidecars"].([]any)[0].(map[string]any); sidecar["clientSecret"] != "***" || sidecar["name"] != "proxy" {
+		t.Errorf("sidecar = %v", sidecar)
+	}
+	if masked["emptyToken"] != nil {
+		t.Errorf("an unset value should stay unset, got %v", masked["emptyToken"])
+	}
+	// The values themselves are left alone
+	if values["auth"].(map[string]any)["adminPassword"] != "hunter2" {
+		t.Error("MaskSensitiveValues() modified its input")
+	}
+}
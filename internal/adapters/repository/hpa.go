@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// hpaTimelineLimit caps how many rescale events an HPA timeline keeps.
+const hpaTimelineLimit = 20
+
+// HPAScaleEvent is one replica change made by an HPA, taken from its
+// SuccessfulRescale events.
+type HPAScaleEvent struct {
+	Time     time.Time
+	Replicas int32  // New replica count; 0 if the message could not be parsed
+	Reason   string // Why the HPA rescaled, e.g. "cpu resource utilization (percentage of request) above target"
+	Message  string
+	Count    int32 // Times the same rescale was repeated
+}
+
+// HPABehavior holds the scale up and scale down rules of an HPA. A nil
+// direction means the Kubernetes defaults apply.
+type HPABehavior struct {
+	ScaleUp   *HPAScalingRules
+	ScaleDown *HPAScalingRules
+}
+
+// HPAScalingRules describes how fast an HPA may scale in one direction.
+type HPAScalingRules struct {
+	StabilizationWindowSeconds *int32 // nil uses the default (0 up, 300 down)
+	SelectPolicy               string // Max, Min or Disabled; empty means Max
+	Policies                   []HPAScalingPolicy
+}
+
+// HPAScalingPolicy is a single rate limit, e.g. 4 Pods every 60 seconds.
+type HPAScalingPolicy struct {
+	Type          string // Pods or Percent
+	Value         int32
+	PeriodSeconds int32
+}
+
+// FindHPAForWorkload returns the HPA scaling the given workload, matched on
+// the kind and name of its scale target, or nil if none does.
+func FindHPAForWorkload(ctx context.Context, clientset kubernetes.Interface, namespace, kind, name string) (*HPAInfo, error) {
+	hpas, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list horizontalpodautoscalers: %w", err)
+	}
+	for _, hpa := range hpas.Items {
+		if hpa.Spec.ScaleTargetRef.Kind == kind && hpa.Spec.ScaleTargetRef.Name == name {
+			info := hpaToHPAInfo(hpa)
+			return &info, nil
+		}
+	}
+	return nil, nil
+}
+
+// hpaScaleEvents returns the most recent rescales of an HPA, oldest first.
+func hpaScaleEvents(ctx context.Context, clientset kubernetes.Interface, namespace, name string) ([]HPAScaleEvent, error) {
+	events, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "involvedObject.name=" + name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	var result []HPAScaleEvent
+	for _, e := range events.Items {
+		if e.InvolvedObject.Kind != "HorizontalPodAutoscaler" || e.InvolvedObject.Name != name || e.Reason != "SuccessfulRescale" {
+			continue
+		}
+		at := e.LastTimestamp.Time
+		if at.IsZero() {
+			at = e.EventTime.Time
+		}
+		if at.IsZero() {
+			at = e.FirstTimestamp.Time
+		}
+		replicas, reason := parseRescaleMessage(e.Message)
+		result = append(result, HPAScaleEvent{
+			Time:     at,
+			Replicas: replicas,
+			Reason:   reason,
+			Message:  e.Message,
+			Count:    e.Count,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	if len(result) > hpaTimelineLimit {
+		result = result[len(result)-hpaTimelineLimit:]
+	}
+	return result, nil
+}
+
+// parseRescaleMessage reads the controller's "New size: 5; reason: ..."
+// message.
+func parseRescaleMessage(message string) (int32, string) {
+	var replicas int32
+	if _, err := fmt.Sscanf(message, "New size: %d;", &replicas); err != nil {
+		return 0, ""
+	}
+	reason := ""
+	if _, after, ok := strings.Cut(message, "reason: "); ok {
+		reason = after
+	}
+	return replicas, reason
+}
+
+func hpaBehavior(behavior *autoscalingv2.HorizontalPodAutoscalerBehavior) *HPABehavior {
+	if behavior == nil || (behavior.ScaleUp == nil && behavior.ScaleDown == nil) {
+		return nil
+	}
+	return &HPABehavior{
+		ScaleUp:   hpaScalingRules(behavior.ScaleUp),
+		ScaleDown: hpaScalingRules(behavior.ScaleDown),
+	}
+}
+
+func hpaScalingRules(rules *autoscalingv2.HPAScalingRules) *HPAScalingRules {
+	if rules == nil {
+		return nil
+	}
+	result := &HPAScalingRules{StabilizationWindowSeconds: rules.StabilizationWindowSeconds}
+	if rules.SelectPolicy != nil {
+		result.SelectPolicy = string(*rules.SelectPolicy)
+	}
+	for _, p := range rules.Policies {
+		result.Policies = append(result.Policies, HPAScalingPolicy{
+			Type:          string(p.Type),
+			Value:         p.Value,
+			PeriodSeconds: p.PeriodSeconds,
+		})
+	}
+	return result
+}
+
+// utilizationRatio divides two utilization percentages, returning 0 when
+// either is missing.
+func utilizationRatio(current, target *int32) float64 {
+	if current == nil || target == nil || *target == 0 {
+		return 0
+	}
+	return float64(*current) / float64(*target)
+}
+
+// quantityRatio divides two quantities, returning 0 when either is missing.
+func quantityRatio(current, target *resource.Quantity) float64 {
+	if current == nil || target == nil || target.IsZero() {
+		return 0
+	}
+	return current.AsApproximateFloat64() / target.AsApproximateFloat64()
+}
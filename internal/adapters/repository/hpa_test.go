@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func webHPA() *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "web", APIVersion: "apps/v1"},
+			MinReplicas:    int32Ptr(2),
+			MaxReplicas:    10,
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: 4, DesiredReplicas: 4},
+	}
+}
+
+func TestGetHPA_MultipleMetrics(t *testing.T) {
+	hpa := webHPA()
+	hpa.Spec.Metrics = []autoscalingv2.MetricSpec{
+		{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name:   corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: int32Ptr(80)},
+			},
+		},
+		{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: "http_requests"},
+				Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: resourceQuantityPtr("100")},
+			},
+		},
+		{
+			Type: autoscalingv2.ObjectMetricSourceType,
+			Object: &autoscalingv2.ObjectMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: "queue_depth"},
+				Target: autoscalingv2.MetricTarget{Type: autoscalingv2.ValueMetricType, Value: resourceQuantityPtr("50")},
+			},
+		},
+	}
+	hpa.Status.CurrentMetrics = []autoscalingv2.MetricStatus{
+		{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricStatus{
+				Name:    corev1.ResourceCPU,
+				Current: autoscalingv2.MetricValueStatus{AverageUtilization: int32Ptr(120)},
+			},
+		},
+		{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricStatus{
+				Metric:  autoscalingv2.MetricIdentifier{Name: "http_requests"},
+				Current: autoscalingv2.MetricValueStatus{AverageValue: resourceQuantityPtr("50")},
+			},
+		},
+	}
+	clientset := fake.NewSimpleClientset(hpa)
+
+	data, err := GetHPA(context.Background(), clientset, "shop", "web")
+	if err != nil {
+		t.Fatalf("GetHPA() error = %v", err)
+	}
+	if len(data.Metrics) != 3 {
+		t.Fatalf("Metrics = %+v, want 3", data.Metrics)
+	}
+
+	want := []struct {
+		name, current, target string
+		ratio                 float64
+	}{
+		{"cpu", "120%", "80%", 1.5},
+		{"http_requests", "50", "100", 0.5},
+		{"queue_depth", "<unknown>", "50", 0},
+	}
+	for i, w := range want {
+		m := data.Metrics[i]
+		if m.Name != w.name || m.Current != w.current || m.Target != w.target || math.Abs(m.Ratio-w.ratio) > 0.001 {
+			t.Errorf("Metrics[%d] = %+v, want %s %s/%s ratio %.1f", i, m, w.name, w.current, w.target, w.ratio)
+		}
+	}
+	if data.Behavior != nil || len(data.Events) != 0 {
+		t.Errorf("Behavior = %+v, Events = %+v, want neither", data.Behavior, data.Events)
+	}
+}
+
+func TestGetHPA_BehaviorAndEvents(t *testing.T) {
+	hpa := webHPA()
+	minPolicy := autoscalingv2.MinChangePolicySelect
+	hpa.Spec.Behavior = &autoscalingv2.HorizontalPodAutoscalerBehavior{
+		ScaleUp: &autoscalingv2.HPAScalingRules{
+			StabilizationWindowSeconds: int32Ptr(0),
+			Policies: []autoscalingv2.HPAScalingPolicy{
+				{Type: autoscalingv2.PodsScalingPolicy, Value: 4, PeriodSeconds: 60},
+				{Type: autoscalingv2.PercentScalingPolicy, Value: 100, PeriodSeconds: 15},
+			},
+		},
+		ScaleDown: &autoscalingv2.HPAScalingRules{
+			StabilizationWindowSeconds: int32Ptr(600),
+			SelectPolicy:               &minPolicy,
+			Policies: []autoscalingv2.HPAScalingPolicy{
+				{Type: autoscalingv2.PercentScalingPolicy, Value: 10, PeriodSeconds: 60},
+			},
+		},
+	}
+
+	now := time.Now()
+	rescale := func(name string, at time.Time, message string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			InvolvedObject: corev1.ObjectReference{Kind: "HorizontalPodAutoscaler", Name: "web"},
+			Reason:         "SuccessfulRescale",
+			Message:        message,
+			LastTimestamp:  metav1.Time{Time: at},
+			Count:          1,
+		}
+	}
+	other := rescale("web.other", now, "New size: 9; reason: other HPA")
+	other.InvolvedObject.Name = "api"
+	failed := rescale("web.failed", now, "failed to get cpu utilization")
+	failed.Reason = "FailedGetResourceMetric"
+
+	clientset := fake.NewSimpleClientset(hpa,
+		rescale("web.down", now.Add(-5*time.Minute), "New size: 4; reason: All metrics below target"),
+		rescale("web.up", now.Add(-30*time.Minute), "New size: 6; reason: cpu resource utilization (percentage of request) above target"),
+		other, failed,
+	)
+
+	data, err := GetHPA(context.Background(), clientset, "shop", "web")
+	if err != nil {
+		t.Fatalf("GetHPA() error = %v", err)
+	}
+
+	b := data.Behavior
+	if b == nil || b.ScaleUp == nil || b.ScaleDown == nil {
+		t.Fatalf("Behavior = %+v, want both directions", b)
+	}
+	if len(b.ScaleUp.Policies) != 2 || b.ScaleUp.Policies[0] != (HPAScalingPolicy{Type: "Pods", Value: 4, PeriodSeconds: 60}) {
+		t.Errorf("ScaleUp.Policies = %+v, want 4 Pods/60s first", b.ScaleUp.Policies)
+	}
+	if b.ScaleUp.SelectPolicy != "" || *b.ScaleUp.StabilizationWindowSeconds != 0 {
+		t.Errorf("ScaleUp = %+v, want no select policy and a 0s window", b.ScaleUp)
+	}
+	if b.ScaleDown.SelectPolicy != "Min" || *b.ScaleDown.StabilizationWindowSeconds != 600 || len(b.ScaleDown.Policies) != 1 {
+		t.Errorf("ScaleDown = %+v, want Min over one policy with a 600s window", b.ScaleDown)
+	}
+
+	if len(data.Events) != 2 {
+		t.Fatalf("Events = %+v, want the two rescales of web", data.Events)
+	}
+	up, down := data.Events[0], data.Events[1]
+	if up.Replicas != 6 || up.Reason != "cpu resource utilization (percentage of request) above target" {
+		t.Errorf("Events[0] = %+v, want the scale up to 6 first", up)
+	}
+	if down.Replicas != 4 || down.Reason != "All metrics below target" {
+		t.Errorf("Events[1] = %+v, want the scale down to 4", down)
+	}
+}
+
+func TestParseRescaleMessage(t *testing.T) {
+	tests := []struct {
+		message      string
+		wantReplicas int32
+		wantReason   string
+	}{
+		{"New size: 12; reason: external metric queue_length above target", 12, "external metric queue_length above target"},
+		{"New size: 1; reason: All metrics below target", 1, "All metrics below target"},
+		{"something else", 0, ""},
+	}
+	for _, tt := range tests {
+		replicas, reason := parseRescaleMessage(tt.message)
+		if replicas != tt.wantReplicas || reason != tt.wantReason {
+			t.Errorf("parseRescaleMessage(%q) = %d, %q, want %d, %q", tt.message, replicas, reason, tt.wantReplicas, tt.wantReason)
+		}
+	}
+}
+
+func TestFindHPAForWorkload(t *testing.T) {
+	sts := webHPA()
+	sts.Name = "db"
+	sts.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{Kind: "StatefulSet", Name: "web"}
+	clientset := fake.NewSimpleClientset(sts, webHPA())
+	ctx := context.Background()
+
+	hpa, err := FindHPAForWorkload(ctx, clientset, "shop", "Deployment", "web")
+	if err != nil {
+		t.Fatalf("FindHPAForWorkload() error = %v", err)
+	}
+	if hpa == nil || hpa.Name != "web" || hpa.Reference != "Deployment/web" || hpa.MinReplicas != 2 {
+		t.Errorf("FindHPAForWorkload() = %+v, want the web HPA", hpa)
+	}
+
+	if hpa, err := FindHPAForWorkload(ctx, clientset, "shop", "Deployment", "api"); err != nil || hpa != nil {
+		t.Errorf("FindHPAForWorkload(api) = %+v, %v, want nil", hpa, err)
+	}
+}
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ImagePullFailure is the classified cause of an image pull error.
+type ImagePullFailure string
+
+const (
+	PullFailureUnknown  ImagePullFailure = ""
+	PullManifestUnknown ImagePullFailure = "manifest unknown"
+	PullUnauthorized    ImagePullFailure = "unauthorized"
+	PullUnreachable     ImagePullFailure = "registry unreachable"
+	PullRateLimited     ImagePullFailure = "rate limited"
+)
+
+// imagePullPatterns maps lowercase substrings of containerd and docker error
+// messages to a failure class. Order matters: a 429 body also says "denied"
+// on some registries, and docker's "repository does not exist or may require
+// 'docker login'" is an auth problem more often than a typo.
+var imagePullPatterns = []struct {
+	failure  ImagePullFailure
+	patterns []string
+}{
+	{PullRateLimited, []string{"toomanyrequests", "429 too many requests", "pull rate limit", "rate limit exceeded"}},
+	{PullUnauthorized, []string{"401 unauthorized", "403 forbidden", "unauthorized", "authentication required", "pull access denied", "requested access to the resource is denied", "failed to authorize", "no basic auth credentials"}},
+	{PullManifestUnknown, []string{"manifest unknown", "not found"}},
+	{PullUnreachable, []string{"no such host", "i/o timeout", "connection refused", "connection reset", "network is unreachable", "no route to host", "tls handshake timeout", "client.timeout exceeded", "context deadline exceeded"}},
+}
+
+// ImagePullDiagnosis explains why a container's image could not be pulled.
+type ImagePullDiagnosis struct {
+	Container string
+	Image     string
+	Registry  string
+	Failure   ImagePullFailure
+	Message   string // Runtime error the classification is based on
+	Hint      string // Targeted next step
+}
+
+// pullSecretInfo is what is known about one imagePullSecret reference.
+type pullSecretInfo struct {
+	name       string
+	found      bool
+	registries []string // Normalized registry hosts with credentials
+}
+
+// ClassifyImagePullError classifies a container waiting message or a Failed
+// event message from the kubelet.
+func ClassifyImagePullError(message string) ImagePullFailure {
+	msg := strings.ToLower(message)
+	for _, p := range imagePullPatterns {
+		for _, pattern := range p.patterns {
+			if strings.Contains(msg, pattern) {
+				return p.failure
+			}
+		}
+	}
+	return PullFailureUnknown
+}
+
+// DiagnoseImagePulls classifies the pull failure of every container stuck in
+// ErrImagePull or ImagePullBackOff. The waiting message of ImagePullBackOff
+// only says "Back-off pulling image", so Failed events mentioning the image
+// are checked too. For auth failures the pod's and ServiceAccount's
+// imagePullSecrets are cross-checked against the image's registry.
+func DiagnoseImagePulls(ctx context.Context, clientset kubernetes.Interface, pod *PodInfo, events []EventInfo) []ImagePullDiagnosis {
+	var diagnoses []ImagePullDiagnosis
+	var secrets []pullSecretInfo
+	secretsLoaded := false
+
+	containers := append(append([]ContainerInfo{}, pod.InitContainers...), pod.Containers...)
+	for _, c := range containers {
+		if c.Reason != "ErrImagePull" && c.Reason != "ImagePullBackOff" {
+			continue
+		}
+
+		d := ImagePullDiagnosis{Container: c.Name, Image: c.Image, Registry: imageRegistry(c.Image)}
+		d.Failure, d.Message = classifyContainerPull(c, events)
+		if d.Failure == PullFailureUnknown {
+			continue
+		}
+
+		if d.Failure == PullUnauthorized && !secretsLoaded {
+			secrets = loadPullSecrets(ctx, clientset, pod)
+			secretsLoaded = true
+		}
+		d.Hint = imagePullHint(d, secrets, serviceAccountName(pod))
+		diagnoses = append(diagnoses, d)
+	}
+	return diagnoses
+}
+
+// classifyContainerPull returns the first classifiable message among the
+// container's waiting message and the Failed events for its image.
+func classifyContainerPull(c ContainerInfo, events []EventInfo) (ImagePullFailure, string) {
+	if f := ClassifyImagePullError(c.Message); f != PullFailureUnknown {
+		return f, c.Message
+	}
+	quoted := fmt.Sprintf("%q", c.Image)
+	for _, e := range events {
+		if e.Reason != "Failed" || !strings.Contains(e.Message, quoted) {
+			continue
+		}
+		if f := ClassifyImagePullError(e.Message); f != PullFailureUnknown {
+			return f, e.Message
+		}
+	}
+	return PullFailureUnknown, ""
+}
+
+func imagePullHint(d ImagePullDiagnosis, secrets []pullSecretInfo, serviceAccount string) string {
+	switch d.Failure {
+	case PullManifestUnknown:
+		return fmt.Sprintf("Tag or digest not found on %s — check %s for typos and that it was pushed", d.Registry, d.Image)
+	case PullUnauthorized:
+		return pullSecretHint(d.Registry, secrets, serviceAccount)
+	case PullUnreachable:
+		return fmt.Sprintf("Nodes cannot reach %s — check DNS, egress firewall rules and proxy settings", d.Registry)
+	case PullRateLimited:
+		if d.Registry == "docker.io" {
+			return "Docker Hub pull rate limit hit — authenticate with an imagePullSecret or use a registry mirror"
+		}
+		return fmt.Sprintf("%s is rate limiting pulls — retry later or pull with credentials", d.Registry)
+	}
+	return ""
+}
+
+// pullSecretHint explains what is wrong with the credentials for registry.
+func pullSecretHint(registry string, secrets []pullSecretInfo, serviceAccount string) string {
+	if len(secrets) == 0 {
+		return fmt.Sprintf("No imagePullSecrets on the pod or ServiceAccount %s — create a docker-registry secret for %s and reference it", serviceAccount, registry)
+	}
+
+	var names, missing []string
+	for _, s := range secrets {
+		names = append(names, s.name)
+		if !s.found {
+			missing = append(missing, s.name)
+		}
+	}
+	for _, s := range secrets {
+		if containsString(s.registries, registry) {
+			return fmt.Sprintf("imagePullSecret %s has credentials for %s — they may be wrong or expired", s.name, registry)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Sprintf("imagePullSecret %s does not exist in the namespace", strings.Join(missing, ", "))
+	}
+	return fmt.Sprintf("None of the imagePullSecrets (%s) have credentials for %s", strings.Join(names, ", "), registry)
+}
+
+// loadPullSecrets resolves the pod's imagePullSecrets followed by its
+// ServiceAccount's, without duplicates.
+func loadPullSecrets(ctx context.Context, clientset kubernetes.Interface, pod *PodInfo) []pullSecretInfo {
+	names := append([]string{}, pod.ImagePullSecrets...)
+	sa, err := clientset.CoreV1().ServiceAccounts(pod.Namespace).Get(ctx, serviceAccountName(pod), metav1.GetOptions{})
+	if err == nil {
+		for _, ref := range sa.ImagePullSecrets {
+			if !containsString(names, ref.Name) {
+				names = append(names, ref.Name)
+			}
+		}
+	}
+
+	secrets := make([]pullSecretInfo, 0, len(names))
+	for _, name := range names {
+		info := pullSecretInfo{name: name}
+		secret, err := clientset.CoreV1().Secrets(pod.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			info.found = true
+			info.registries = dockerConfigRegistries(secret)
+		}
+		secrets = append(secrets, info)
+	}
+	return secrets
+}
+
+// dockerConfigRegistries returns the normalized registry hosts in a
+// kubernetes.io/dockerconfigjson or kubernetes.io/dockercfg secret.
+func dockerConfigRegistries(secret *corev1.Secret) []string {
+	var auths map[string]json.RawMessage
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		var config struct {
+			Auths map[string]json.RawMessage `json:"auths"`
+		}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+			return nil
+		}
+		auths = config.Auths
+	case corev1.SecretTypeDockercfg:
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &auths); err != nil {
+			return nil
+		}
+	}
+
+	var registries []string
+	for host := range auths {
+		registries = append(registries, normalizeRegistry(host))
+	}
+	return registries
+}
+
+// PullSecretStatus is an imagePullSecret referenced by a pod or its
+// ServiceAccount, checked against the secrets of the namespace.
+type PullSecretStatus struct {
+	Name               string
+	FromServiceAccount bool     // Referenced by the ServiceAccount rather than the pod spec
+	Found              bool     // The secret exists in the pod's namespace
+	Type               string   // Secret type; empty when not found
+	Registries         []string // Registry hosts the secret has credentials for
+}
+
+// Problem explains why the kubelet can't use the secret to pull images, or
+// returns "" when it can.
+func (s PullSecretStatus) Problem() string {
+	switch {
+	case !s.Found:
+		return "not found in namespace"
+	case s.Type != string(corev1.SecretTypeDockerConfigJson) && s.Type != string(corev1.SecretTypeDockercfg):
+		return fmt.Sprintf("type %s, want %s", s.Type, corev1.SecretTypeDockerConfigJson)
+	}
+	return ""
+}
+
+// ValidateImagePullSecrets checks the imagePullSecrets of the pod, followed
+// by those of its ServiceAccount, without duplicates: whether each exists
+// in the namespace and is a docker config secret the kubelet can use.
+// A ServiceAccount that can't be read contributes no secrets.
+func ValidateImagePullSecrets(ctx context.Context, clientset kubernetes.Interface, pod *PodInfo) []PullSecretStatus {
+	var statuses []PullSecretStatus
+	seen := map[string]bool{}
+	add := func(name string, fromServiceAccount bool) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		status := PullSecretStatus{Name: name, FromServiceAccount: fromServiceAccount}
+		if secret, err := clientset.CoreV1().Secrets(pod.Namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+			status.Found = true
+			status.Type = string(secret.Type)
+			status.Registries = dockerConfigRegistries(secret)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, name := range pod.ImagePullSecrets {
+		add(name, false)
+	}
+	if sa, err := clientset.CoreV1().ServiceAccounts(pod.Namespace).Get(ctx, serviceAccountName(pod), metav1.GetOptions{}); err == nil {
+		for _, ref := range sa.ImagePullSecrets {
+			add(ref.Name, true)
+		}
+	}
+	return statuses
+}
+
+// ImageReference is an image reference split into its parts.
+type ImageReference struct {
+	Registry   string // Registry host; docker.io when the reference has none
+	Repository string // Path within the registry, as written
+	Tag        string // Tag; "latest" when neither a tag nor a digest is given
+	Digest     string // sha256:... when pinned by digest
+}
+
+// ParseImageReference splits an image reference such as
+// "ghcr.io/org/app:v1" or "nginx@sha256:..." into registry, repository,
+// tag and digest.
+func ParseImageReference(image string) ImageReference {
+	var ref ImageReference
+	name, digest, _ := strings.Cut(image, "@")
+	ref.Digest = digest
+	// A colon after the last slash starts the tag; before it, a registry port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+
+	ref.Registry = imageRegistry(name)
+	ref.Repository = name
+	// Same host detection as imageRegistry
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Repository = rest
+	}
+	return ref
+}
+
+// imageRegistry returns the registry host of an image reference. Images
+// without a registry host ("nginx", "bitnami/redis") come from Docker Hub.
+func imageRegistry(image string) string {
+	first, _, hasSlash := strings.Cut(image, "/")
+	if !hasSlash || (!strings.ContainsAny(first, ".:") && first != "localhost") {
+		return "docker.io"
+	}
+	return normalizeRegistry(first)
+}
+
+// normalizeRegistry turns docker config keys such as
+// "https://index.docker.io/v1/" into a bare host comparable with imageRegistry.
+func normalizeRegistry(host string) string {
+	host = strings.ToLower(host)
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return "docker.io"
+	}
+	return host
+}
+
+func serviceAccountName(pod *PodInfo) string {
+	if pod.ServiceAccount == "" {
+		return "default"
+	}
+	return pod.ServiceAccount
+}
+
+// ImagePullHelper turns a diagnosis into a debug helper for the pod analysis.
+func ImagePullHelper(d ImagePullDiagnosis) DebugHelper {
+	return DebugHelper{
+		Issue:       fmt.Sprintf("Image Pull: %s (%s)", d.Failure, d.Container),
+		Severity:    "High",
+		Suggestions: []string{d.Hint, d.Message},
+	}
+}
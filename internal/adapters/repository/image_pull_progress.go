@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImagePullPhase is where a container is in getting its image.
+type ImagePullPhase string
+
+const (
+	PullPhaseWaiting ImagePullPhase = "waiting" // No pull events; only the waiting reason is known
+	PullPhasePulling ImagePullPhase = "pulling"
+	PullPhasePulled  ImagePullPhase = "pulled"
+)
+
+// PulledImage is what a kubelet Pulled event reports about a finished pull.
+// The message format changed across kubelet versions, so Duration, Waited
+// and Size are zero when the kubelet did not report them.
+type PulledImage struct {
+	Image          string
+	Duration       time.Duration // Time spent pulling
+	Waited         time.Duration // Duration including time queued behind other pulls
+	Size           int64         // Image size in bytes
+	AlreadyPresent bool          // The image was cached on the node
+}
+
+// ImagePullProgress is the image pull state of one container, built from
+// the container's waiting state and the pod's Pulling/Pulled events.
+type ImagePullProgress struct {
+	Container string
+	Image     string
+	Phase     ImagePullPhase
+	Since     time.Time    // When the pull started, or the pod start for PullPhaseWaiting
+	Reason    string       // Waiting reason for PullPhaseWaiting
+	Pulled    *PulledImage // Set for PullPhasePulled
+}
+
+// Go duration as printed by the kubelet, e.g. "450ms", "2.505285918s", "3m12.5s".
+const pullDurationPattern = `[0-9][0-9.]*[a-zµ]+(?:[0-9][0-9.]*[a-zµ]+)*`
+
+var (
+	pulledImageRegexp   = regexp.MustCompile(`(?i)pulled image "([^"]+)"`)
+	presentImageRegexp  = regexp.MustCompile(`(?i)image "([^"]+)" already present`)
+	pullDurationRegexp  = regexp.MustCompile(`\bin (` + pullDurationPattern + `)`)
+	pullWaitedRegexp    = regexp.MustCompile(`\((` + pullDurationPattern + `) including waiting\)`)
+	pullImageSizeRegexp = regexp.MustCompile(`(?i)image size: ([0-9]+) bytes`)
+	pullWaitingReasons  = map[string]bool{"ContainerCreating": true, "PodInitializing": true}
+)
+
+// ParsePulledMessage parses the message of a kubelet Pulled event. It
+// accepts every format seen so far:
+//
+//	Successfully pulled image "app:v1"
+//	Successfully pulled image "app:v1" in 2.505285918s
+//	Successfully pulled image "app:v1" in 3m12.5s (3m14s including waiting)
+//	Successfully pulled image "app:v1" in 3m12.5s (3m14s including waiting). Image size: 123456 bytes.
+//	Container image "app:v1" already present on machine
+//
+// It returns false when the message names no image.
+func ParsePulledMessage(message string) (PulledImage, bool) {
+	if m := presentImageRegexp.FindStringSubmatch(message); m != nil {
+		return PulledImage{Image: m[1], AlreadyPresent: true}, true
+	}
+	m := pulledImageRegexp.FindStringSubmatchIndex(message)
+	if m == nil {
+		return PulledImage{}, false
+	}
+
+	p := PulledImage{Image: message[m[2]:m[3]]}
+	rest := message[m[1]:]
+	if d := pullDurationRegexp.FindStringSubmatch(rest); d != nil {
+		p.Duration, _ = time.ParseDuration(d[1])
+	}
+	if d := pullWaitedRegexp.FindStringSubmatch(rest); d != nil {
+		p.Waited, _ = time.ParseDuration(d[1])
+	}
+	if s := pullImageSizeRegexp.FindStringSubmatch(rest); s != nil {
+		p.Size, _ = strconv.ParseInt(s[1], 10, 64)
+	}
+	return p, true
+}
+
+// ImagePullProgressFor reports the image pull state of every container that
+// is waiting to be created or has a Pulling/Pulled event. Events are matched
+// to containers by the quoted image name, like DiagnoseImagePulls does.
+// Containers stuck in ContainerCreating without any pull event fall back to
+// the waiting reason, timed from the pod start.
+func ImagePullProgressFor(pod *PodInfo, events []EventInfo) []ImagePullProgress {
+	podStart, _ := time.ParseInLocation("2006-01-02 15:04:05", pod.StartTime, time.Local)
+
+	var progress []ImagePullProgress
+	containers := append(append([]ContainerInfo{}, pod.InitContainers...), pod.Containers...)
+	for _, c := range containers {
+		waiting := c.State == "Waiting" && pullWaitingReasons[c.Reason]
+		pulling, pulled := latestPullEvents(c.Image, events)
+
+		p := ImagePullProgress{Container: c.Name, Image: c.Image}
+		switch {
+		case pulled != nil && (pulling == nil || !pulled.LastSeen.Before(pulling.LastSeen)):
+			parsed, _ := ParsePulledMessage(pulled.Message)
+			p.Phase = PullPhasePulled
+			p.Since = pulled.LastSeen
+			p.Pulled = &parsed
+		case pulling != nil && waiting:
+			p.Phase = PullPhasePulling
+			p.Since = pulling.LastSeen
+		case waiting:
+			p.Phase = PullPhaseWaiting
+			p.Since = podStart
+			p.Reason = c.Reason
+		default:
+			continue
+		}
+		progress = append(progress, p)
+	}
+	return progress
+}
+
+// latestPullEvents returns the most recent Pulling and Pulled events for image.
+func latestPullEvents(image string, events []EventInfo) (pulling, pulled *EventInfo) {
+	quoted := fmt.Sprintf("%q", image)
+	for i := range events {
+		e := &events[i]
+		if !strings.Contains(e.Message, quoted) {
+			continue
+		}
+		switch e.Reason {
+		case "Pulling":
+			if pulling == nil || e.LastSeen.After(pulling.LastSeen) {
+				pulling = e
+			}
+		case "Pulled":
+			if pulled == nil || e.LastSeen.After(pulled.LastSeen) {
+				pulled = e
+			}
+		}
+	}
+	return pulling, pulled
+}
+
+// Summary renders the progress as one line for the Pod Details view, e.g.
+// "pulling image registry/app:tag — 3m12s elapsed".
+func (p ImagePullProgress) Summary(now time.Time) string {
+	switch p.Phase {
+	case PullPhasePulling:
+		return fmt.Sprintf("pulling image %s — %s elapsed", p.Image, pullElapsed(p.Since, now))
+	case PullPhasePulled:
+		if p.Pulled == nil {
+			return "pulled"
+		}
+		if p.Pulled.AlreadyPresent {
+			return "already present on node"
+		}
+		s := "pulled"
+		if p.Pulled.Duration > 0 {
+			s += " in " + formatPullDuration(p.Pulled.Duration)
+		}
+		if p.Pulled.Size > 0 {
+			s += " (" + formatImageSize(p.Pulled.Size) + ")"
+		}
+		return s
+	default:
+		if p.Since.IsZero() {
+			return p.Reason
+		}
+		return fmt.Sprintf("%s — %s elapsed", p.Reason, pullElapsed(p.Since, now))
+	}
+}
+
+func pullElapsed(since, now time.Time) string {
+	d := now.Sub(since)
+	if d < 0 {
+		d = 0
+	}
+	return d.Round(time.Second).String()
+}
+
+// formatPullDuration keeps sub-second precision only where it matters.
+func formatPullDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+func formatImageSize(b int64) string {
+	const mi = 1024 * 1024
+	switch {
+	case b >= 1024*mi:
+		return fmt.Sprintf("%.1fGi", float64(b)/float64(1024*mi))
+	case b >= mi:
+		return fmt.Sprintf("%.1fMi", float64(b)/float64(mi))
+	default:
+		return fmt.Sprintf("%dKi", b/1024)
+	}
+}
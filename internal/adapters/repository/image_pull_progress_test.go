@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePulledMessage(t *testing.T) {
+	// Pulled event messages as written by different kubelet versions
+	tests := []struct {
+		name    string
+		message string
+		want    PulledImage
+		ok      bool
+	}{
+		{
+			name:    "no duration",
+			message: `Successfully pulled image "nginx:1.25"`,
+			want:    PulledImage{Image: "nginx:1.25"},
+			ok:      true,
+		},
+		{
+			name:    "duration only",
+			message: `Successfully pulled image "nginx:1.25" in 2.505285918s`,
+			want:    PulledImage{Image: "nginx:1.25", Duration: 2505285918 * time.Nanosecond},
+			ok:      true,
+		},
+		{
+			name:    "including waiting",
+			message: `Successfully pulled image "registry.example.com/app:v1" in 3m12.5s (3m14s including waiting)`,
+			want:    PulledImage{Image: "registry.example.com/app:v1", Duration: 3*time.Minute + 12500*time.Millisecond, Waited: 3*time.Minute + 14*time.Second},
+			ok:      true,
+		},
+		{
+			name:    "image size",
+			message: `Successfully pulled image "registry.example.com/app:v1" in 450ms (1.2s including waiting). Image size: 73400320 bytes.`,
+			want:    PulledImage{Image: "registry.example.com/app:v1", Duration: 450 * time.Millisecond, Waited: 1200 * time.Millisecond, Size: 73400320},
+			ok:      true,
+		},
+		{
+			name:    "already present",
+			message: `Container image "nginx:1.25" already present on machine`,
+			want:    PulledImage{Image: "nginx:1.25", AlreadyPresent: true},
+			ok:      true,
+		},
+		{
+			name:    "unparseable duration is ignored",
+			message: `Successfully pulled image "nginx:1.25" in 1.2.3s`,
+			want:    PulledImage{Image: "nginx:1.25"},
+			ok:      true,
+		},
+		{
+			name:    "no image",
+			message: `Started container app`,
+			ok:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParsePulledMessage(tt.message)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ParsePulledMessage(%q) = %+v, %v, want %+v, %v", tt.message, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestImagePullProgressFor(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.Local)
+	pod := &PodInfo{
+		StartTime: start.Format("2006-01-02 15:04:05"),
+		InitContainers: []ContainerInfo{
+			{Name: "migrate", Image: "app:v1", State: "Terminated", Reason: "Completed"},
+		},
+		Containers: []ContainerInfo{
+			{Name: "app", Image: "registry.example.com/app:v2", State: "Waiting", Reason: "ContainerCreating"},
+			{Name: "sidecar", Image: "envoy:v1", State: "Waiting", Reason: "ContainerCreating"},
+			{Name: "cache", Image: "redis:7", State: "Running"},
+			{Name: "broken", Image: "missing:v1", State: "Waiting", Reason: "ImagePullBackOff"},
+		},
+	}
+	events := []EventInfo{
+		{Reason: "Pulled", Message: `Successfully pulled image "app:v1" in 1.5s (1.5s including waiting). Image size: 2097152 bytes.`, LastSeen: start.Add(5 * time.Second)},
+		{Reason: "Pulling", Message: `Pulling image "app:v1"`, LastSeen: start.Add(time.Second)},
+		{Reason: "Pulling", Message: `Pulling image "registry.example.com/app:v2"`, LastSeen: start.Add(10 * time.Second)},
+		{Reason: "Pulled", Message: `Container image "redis:7" already present on machine`, LastSeen: start.Add(11 * time.Second)},
+	}
+
+	progress := ImagePullProgressFor(pod, events)
+	if len(progress) != 4 {
+		t.Fatalf("ImagePullProgressFor() returned %d entries, want 4: %+v", len(progress), progress)
+	}
+
+	now := start.Add(3*time.Minute + 22*time.Second)
+	want := []struct {
+		container string
+		phase     ImagePullPhase
+		summary   string
+	}{
+		{"migrate", PullPhasePulled, "pulled in 1.5s (2.0Mi)"},
+		{"app", PullPhasePulling, "pulling image registry.example.com/app:v2 — 3m12s elapsed"},
+		{"sidecar", PullPhaseWaiting, "ContainerCreating — 3m22s elapsed"},
+		{"cache", PullPhasePulled, "already present on node"},
+	}
+	for i, w := range want {
+		p := progress[i]
+		if p.Container != w.container || p.Phase != w.phase {
+			t.Errorf("progress[%d] = %s/%s, want %s/%s", i, p.Container, p.Phase, w.container, w.phase)
+		}
+		if got := p.Summary(now); got != w.summary {
+			t.Errorf("progress[%d].Summary() = %q, want %q", i, got, w.summary)
+		}
+	}
+}
+
+func TestImagePullProgressFor_RepullAfterPulled(t *testing.T) {
+	// A newer Pulling event means the image is being pulled again
+	t0 := time.Date(2024, 3, 1, 10, 0, 0, 0, time.Local)
+	pod := &PodInfo{Containers: []ContainerInfo{
+		{Name: "app", Image: "app:v1", State: "Waiting", Reason: "ContainerCreating"},
+	}}
+	events := []EventInfo{
+		{Reason: "Pulled", Message: `Successfully pulled image "app:v1" in 1s`, LastSeen: t0},
+		{Reason: "Pulling", Message: `Pulling image "app:v1"`, LastSeen: t0.Add(time.Minute)},
+	}
+
+	progress := ImagePullProgressFor(pod, events)
+	if len(progress) != 1 || progress[0].Phase != PullPhasePulling {
+		t.Fatalf("ImagePullProgressFor() = %+v, want one pulling entry", progress)
+	}
+}
+
+func TestImagePullProgressSummary_NoStartTime(t *testing.T) {
+	p := ImagePullProgress{Phase: PullPhaseWaiting, Reason: "ContainerCreating"}
+	if got := p.Summary(time.Now()); got != "ContainerCreating" {
+		t.Errorf("Summary() = %q, want waiting reason alone", got)
+	}
+}
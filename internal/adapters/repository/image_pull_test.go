@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClassifyImagePullError(t *testing.T) {
+	// Messages as reported by the kubelet for containerd and docker runtimes
+	tests := []struct {
+		name    string
+		message string
+		want    ImagePullFailure
+	}{
+		{
+			name:    "containerd tag not found",
+			message: `rpc error: code = NotFound desc = failed to pull and unpack image "docker.io/library/nginx:1.99": failed to resolve reference "docker.io/library/nginx:1.99": docker.io/library/nginx:1.99: not found`,
+			want:    PullManifestUnknown,
+		},
+		{
+			name:    "docker manifest unknown",
+			message: `Error response from daemon: manifest for nginx:1.99 not found: manifest unknown: manifest unknown`,
+			want:    PullManifestUnknown,
+		},
+		{
+			name:    "containerd 401",
+			message: `rpc error: code = Unknown desc = failed to pull and unpack image "registry.example.com/team/app:latest": failed to resolve reference "registry.example.com/team/app:latest": pulling from host registry.example.com failed with status code [manifests latest]: 401 Unauthorized`,
+			want:    PullUnauthorized,
+		},
+		{
+			name:    "containerd anonymous token",
+			message: `failed to resolve reference "ghcr.io/org/private:v1": failed to authorize: failed to fetch anonymous token: unexpected status: 401 Unauthorized`,
+			want:    PullUnauthorized,
+		},
+		{
+			name:    "containerd ecr 403",
+			message: `failed to resolve reference "123456789012.dkr.ecr.us-east-1.amazonaws.com/app:1.2.0": unexpected status from HEAD request to https://123456789012.dkr.ecr.us-east-1.amazonaws.com/v2/app/manifests/1.2.0: 403 Forbidden`,
+			want:    PullUnauthorized,
+		},
+		{
+			name:    "docker pull access denied",
+			message: `Error response from daemon: pull access denied for myorg/private, repository does not exist or may require 'docker login': denied: requested access to the resource is denied`,
+			want:    PullUnauthorized,
+		},
+		{
+			name:    "docker authentication required",
+			message: `Error response from daemon: Head "https://gcr.io/v2/project/app/manifests/1.0": unauthorized: authentication required`,
+			want:    PullUnauthorized,
+		},
+		{
+			name:    "ecr no basic auth",
+			message: `Error response from daemon: Get "https://123456789012.dkr.ecr.eu-west-1.amazonaws.com/v2/app/manifests/latest": no basic auth credentials`,
+			want:    PullUnauthorized,
+		},
+		{
+			name:    "containerd dns failure",
+			message: `failed to resolve reference "registry.internal:5000/app:1": failed to do request: Head "https://registry.internal:5000/v2/app/manifests/1": dial tcp: lookup registry.internal: no such host`,
+			want:    PullUnreachable,
+		},
+		{
+			name:    "containerd i/o timeout",
+			message: `failed to do request: Head "https://10.0.0.5:5000/v2/app/manifests/1": dial tcp 10.0.0.5:5000: i/o timeout`,
+			want:    PullUnreachable,
+		},
+		{
+			name:    "connection refused",
+			message: `failed to do request: Head "https://localhost:5000/v2/app/manifests/1": dial tcp 127.0.0.1:5000: connect: connection refused`,
+			want:    PullUnreachable,
+		},
+		{
+			name:    "docker client timeout",
+			message: `Error response from daemon: Get "https://registry.example.com/v2/": net/http: request canceled while waiting for connection (Client.Timeout exceeded while awaiting headers)`,
+			want:    PullUnreachable,
+		},
+		{
+			name:    "tls handshake timeout",
+			message: `Error response from daemon: Get "https://quay.io/v2/": net/http: TLS handshake timeout`,
+			want:    PullUnreachable,
+		},
+		{
+			name:    "containerd docker hub 429",
+			message: `failed to copy: httpReadSeeker: failed open: unexpected status code https://registry-1.docker.io/v2/library/redis/manifests/sha256:4e1a: 429 Too Many Requests - Server message: toomanyrequests: You have reached your pull rate limit. You may increase the limit by authenticating and upgrading: https://www.docker.com/increase-rate-limit`,
+			want:    PullRateLimited,
+		},
+		{
+			name:    "docker rate limit",
+			message: `Error response from daemon: toomanyrequests: You have reached your pull rate limit. You may increase the limit by authenticating and upgrading: https://www.docker.com/increase-rate-limit`,
+			want:    PullRateLimited,
+		},
+		{
+			name:    "back-off carries no cause",
+			message: `Back-off pulling image "nginx:1.99"`,
+			want:    PullFailureUnknown,
+		},
+		{
+			name:    "empty",
+			message: "",
+			want:    PullFailureUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyImagePullError(tt.message); got != tt.want {
+				t.Errorf("ClassifyImagePullError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageRegistry(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                        "docker.io",
+		"nginx:1.25":                   "docker.io",
+		"bitnami/redis:7":              "docker.io",
+		"docker.io/library/nginx":      "docker.io",
+		"ghcr.io/org/app:v1":           "ghcr.io",
+		"localhost/app":                "localhost",
+		"registry.internal:5000/app:1": "registry.internal:5000",
+		"123456789012.dkr.ecr.us-east-1.amazonaws.com/app": "123456789012.dkr.ecr.us-east-1.amazonaws.com",
+	}
+	for image, want := range tests {
+		if got := imageRegistry(image); got != want {
+			t.Errorf("imageRegistry(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestNormalizeRegistry(t *testing.T) {
+	tests := map[string]string{
+		"https://index.docker.io/v1/": "docker.io",
+		"registry-1.docker.io":        "docker.io",
+		"GHCR.io":                     "ghcr.io",
+		"http://registry.local:5000":  "registry.local:5000",
+	}
+	for host, want := range tests {
+		if got := normalizeRegistry(host); got != want {
+			t.Errorf("normalizeRegistry(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestPullSecretHint(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets []pullSecretInfo
+		want    string
+	}{
+		{
+			name: "no secrets",
+			want: "No imagePullSecrets on the pod or ServiceAccount default — create a docker-registry secret for ghcr.io and reference it",
+		},
+		{
+			name:    "missing secret",
+			secrets: []pullSecretInfo{{name: "regcred"}},
+			want:    "imagePullSecret regcred does not exist in the namespace",
+		},
+		{
+			name:    "wrong registry",
+			secrets: []pullSecretInfo{{name: "hub", found: true, registries: []string{"docker.io"}}},
+			want:    "None of the imagePullSecrets (hub) have credentials for ghcr.io",
+		},
+		{
+			name: "credentials present",
+			secrets: []pullSecretInfo{
+				{name: "missing"},
+				{name: "ghcr", found: true, registries: []string{"ghcr.io"}},
+			},
+			want: "imagePullSecret ghcr has credentials for ghcr.io — they may be wrong or expired",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pullSecretHint("ghcr.io", tt.secrets, "default"); got != tt.want {
+				t.Errorf("pullSecretHint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagnoseImagePulls(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: "builder", Namespace: "default"},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "hub"}},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "hub", Namespace: "default"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(`{"auths":{"https://index.docker.io/v1/":{"auth":"dXNlcjpwYXNz"}}}`),
+			},
+		},
+	)
+
+	pod := &PodInfo{
+		Name:           "web",
+		Namespace:      "default",
+		ServiceAccount: "builder",
+		Containers: []ContainerInfo{
+			{Name: "app", Image: "ghcr.io/org/app:v1", Reason: "ImagePullBackOff", Message: `Back-off pulling image "ghcr.io/org/app:v1"`},
+			{Name: "proxy", Image: "envoyproxy/envoy:v9.99", Reason: "ErrImagePull", Message: `rpc error: code = NotFound desc = failed to pull and unpack image "docker.io/envoyproxy/envoy:v9.99": not found`},
+			{Name: "ok", Image: "nginx", State: "Running"},
+		},
+	}
+	events := []EventInfo{
+		{Type: "Normal", Reason: "BackOff", Message: `Back-off pulling image "ghcr.io/org/app:v1"`},
+		{Type: "Warning", Reason: "Failed", Message: `Failed to pull image "ghcr.io/org/app:v1": failed to authorize: failed to fetch anonymous token: unexpected status: 401 Unauthorized`},
+	}
+
+	diagnoses := DiagnoseImagePulls(context.Background(), clientset, pod, events)
+	if len(diagnoses) != 2 {
+		t.Fatalf("DiagnoseImagePulls() returned %d diagnoses, want 2", len(diagnoses))
+	}
+
+	app := diagnoses[0]
+	if app.Container != "app" || app.Failure != PullUnauthorized {
+		t.Errorf("app = %+v, want unauthorized from Failed event", app)
+	}
+	if app.Hint != "None of the imagePullSecrets (hub) have credentials for ghcr.io" {
+		t.Errorf("app hint = %q", app.Hint)
+	}
+
+	proxy := diagnoses[1]
+	if proxy.Failure != PullManifestUnknown || proxy.Registry != "docker.io" {
+		t.Errorf("proxy = %+v, want manifest unknown on docker.io", proxy)
+	}
+}
+
+func TestImagePullHelper(t *testing.T) {
+	helper := ImagePullHelper(ImagePullDiagnosis{Container: "app", Failure: PullRateLimited, Hint: "hint", Message: "429"})
+	if helper.Issue != "Image Pull: rate limited (app)" || helper.Severity != "High" {
+		t.Errorf("helper = %+v", helper)
+	}
+	if len(helper.Suggestions) != 2 || helper.Suggestions[0] != "hint" {
+		t.Errorf("Suggestions = %v", helper.Suggestions)
+	}
+}
+
+func TestValidateImagePullSecrets(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: "builder", Namespace: "default"},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "regcred"}, {Name: "sa-only"}},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "regcred", Namespace: "default"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(`{"auths":{"ghcr.io":{"auth":"dXNlcjpwYXNz"}}}`),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "opaque", Namespace: "default"},
+			Type:       corev1.SecretTypeOpaque,
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sa-only", Namespace: "default"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+		},
+	)
+	pod := &PodInfo{
+		Name:             "web",
+		Namespace:        "default",
+		ServiceAccount:   "builder",
+		ImagePullSecrets: []string{"regcred", "missing", "opaque"},
+	}
+
+	statuses := ValidateImagePullSecrets(context.Background(), clientset, pod)
+	if len(statuses) != 4 {
+		t.Fatalf("statuses = %+v, want 3 from the pod and 1 more from the ServiceAccount", statuses)
+	}
+	byName := map[string]PullSecretStatus{}
+	for _, s := range statuses {
+		byName[s.Name] = s
+	}
+
+	valid := byName["regcred"]
+	if valid.Problem() != "" || valid.FromServiceAccount || len(valid.Registries) != 1 || valid.Registries[0] != "ghcr.io" {
+		t.Errorf("regcred = %+v, want a valid pod secret for ghcr.io", valid)
+	}
+	if missing := byName["missing"]; missing.Found || missing.Problem() != "not found in namespace" {
+		t.Errorf("missing = %+v, problem %q", missing, missing.Problem())
+	}
+	if opaque := byName["opaque"]; opaque.Problem() != "type Opaque, want kubernetes.io/dockerconfigjson" {
+		t.Errorf("opaque problem = %q", opaque.Problem())
+	}
+	if saOnly := byName["sa-only"]; !saOnly.FromServiceAccount || saOnly.Problem() != "" {
+		t.Errorf("sa-only = %+v, want a valid ServiceAccount secret", saOnly)
+	}
+}
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  ImageReference
+	}{
+		{"nginx", ImageReference{Registry: "docker.io", Repository: "nginx", Tag: "latest"}},
+		{"bitnami/redis:7.2", ImageReference{Registry: "docker.io", Repository: "bitnami/redis", Tag: "7.2"}},
+		{"ghcr.io/org/app:v1", ImageReference{Registry: "ghcr.io", Repository: "org/app", Tag: "v1"}},
+		{"localhost:5000/app", ImageReference{Registry: "localhost:5000", Repository: "app", Tag: "latest"}},
+		{"quay.io/org/app@sha256:abc", ImageReference{Registry: "quay.io", Repository: "org/app", Digest: "sha256:abc"}},
+		{"registry.example.com:443/team/app:1.0@sha256:abc", ImageReference{Registry: "registry.example.com:443", Repository: "team/app", Tag: "1.0", Digest: "sha256:abc"}},
+	}
+	for _, tt := range tests {
+		if got := ParseImageReference(tt.image); got != tt.want {
+			t.Errorf("ParseImageReference(%q) = %+v, want %+v", tt.image, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Hops of a route trace, in the order traffic goes through them.
+const (
+	HopController = "Controller" // Ingress controller pods of the ingress class
+	HopService    = "Service"
+	HopEndpoints  = "Endpoints"
+	HopPods       = "Pods"
+)
+
+// defaultIngressClassAnnotation marks the IngressClass used by Ingresses
+// that name none.
+const defaultIngressClassAnnotation = "ingressclass.kubernetes.io/is-default-class"
+
+// ingressControllerPods are the labels of the pods of the common ingress
+// controllers, by the controller an IngressClass names. Controllers that
+// run outside the cluster, such as cloud load balancers, are not listed.
+var ingressControllerPods = map[string]string{
+	"k8s.io/ingress-nginx":           "app.kubernetes.io/name=ingress-nginx",
+	"traefik.io/ingress-controller":  "app.kubernetes.io/name=traefik",
+	"istio.io/ingress-controller":    "istio=ingressgateway",
+	"haproxy.org/ingress-controller": "app.kubernetes.io/name=kubernetes-ingress",
+	"ingress.k8s.aws/alb":            "app.kubernetes.io/name=aws-load-balancer-controller",
+}
+
+// legacyIngressControllers are the controllers of the classes given with
+// the kubernetes.io/ingress.class annotation, which has no IngressClass.
+var legacyIngressControllers = map[string]string{
+	"nginx":   "k8s.io/ingress-nginx",
+	"traefik": "traefik.io/ingress-controller",
+	"istio":   "istio.io/ingress-controller",
+	"haproxy": "haproxy.org/ingress-controller",
+	"alb":     "ingress.k8s.aws/alb",
+}
+
+// TraceHop is one step between an Ingress or VirtualService route and the
+// pods that serve it.
+type TraceHop struct {
+	Kind   string // One of the Hop* constants
+	Name   string // e.g. the Service name, or the controller of the class
+	OK     bool
+	Detail string // What was found, or why traffic stops here
+}
+
+// RouteTrace follows one route, e.g. a host and path of an Ingress, to
+// its pods.
+type RouteTrace struct {
+	Source string // "Ingress/web" or "VirtualService/web"
+	Route  string // e.g. "shop.example.com/api → web:80"
+	Hops   []TraceHop
+}
+
+// OK reports whether traffic gets through every hop.
+func (t RouteTrace) OK() bool {
+	return t.FailedHop() == nil
+}
+
+// FailedHop returns the first hop traffic stops at, or nil.
+func (t RouteTrace) FailedHop() *TraceHop {
+	for i := range t.Hops {
+		if !t.Hops[i].OK {
+			return &t.Hops[i]
+		}
+	}
+	return nil
+}
+
+// TraceIngress traces every host and path of an Ingress in namespace:
+// whether its ingress class has controller pods, then each backend as
+// TraceIngressPath does.
+func TraceIngress(ctx context.Context, clientset kubernetes.Interface, namespace string, ing IngressInfo) ([]RouteTrace, error) {
+	controller, err := traceIngressController(ctx, clientset, ing.Class)
+	if err != nil {
+		return nil, err
+	}
+	var traces []RouteTrace
+	for _, rule := range ing.Rules {
+		for _, path := range rule.Paths {
+			trace, err := TraceIngressPath(ctx, clientset, namespace, ing, rule.Host, path)
+			if err != nil {
+				return nil, err
+			}
+			trace.Hops = append([]TraceHop{controller}, trace.Hops...)
+			traces = append(traces, trace)
+		}
+	}
+	return traces, nil
+}
+
+// TraceIngressPath follows a path of an Ingress rule for host to the pods
+// behind its backend Service: the Service must exist and have the backend
+// port, have ready endpoints, and select pods with a containerPort its
+// targetPort matches.
+func TraceIngressPath(ctx context.Context, clientset kubernetes.Interface, namespace string, ing IngressInfo, host string, path IngressPathInfo) (RouteTrace, error) {
+	if host == "" {
+		host = "*"
+	}
+	trace := RouteTrace{
+		Source: "Ingress/" + ing.Name,
+		Route:  fmt.Sprintf("%s%s → %s:%s", host, path.Path, path.ServiceName, path.ServicePort),
+	}
+	if path.ServiceName == "" {
+		trace.Hops = []TraceHop{{Kind: HopService, Detail: "backend is not a Service"}}
+		return trace, nil
+	}
+	hops, err := traceBackend(ctx, clientset, namespace, path.ServiceName, path.ServicePort)
+	trace.Hops = hops
+	return trace, err
+}
+
+// TraceVirtualServiceRoute follows a route of an Istio VirtualService in
+// namespace to the pods behind its destination, as TraceIngressPath does.
+// The destination host is a Service name, short or qualified with its
+// namespace.
+func TraceVirtualServiceRoute(ctx context.Context, clientset kubernetes.Interface, namespace string, vs VirtualServiceInfo, route VirtualServiceRoute) (RouteTrace, error) {
+	trace := RouteTrace{
+		Source: "VirtualService/" + vs.Name,
+		Route:  fmt.Sprintf("%s → %s:%d", route.Match, route.Destination, route.Port),
+	}
+	parts := strings.Split(route.Destination, ".")
+	if len(parts) > 1 {
+		namespace = parts[1]
+	}
+	port := ""
+	if route.Port > 0 {
+		port = strconv.Itoa(int(route.Port))
+	}
+	hops, err := traceBackend(ctx, clientset, namespace, parts[0], port)
+	trace.Hops = hops
+	return trace, err
+}
+
+// HostsOverlap reports whether a host of a matches a host of b, with
+// wildcards such as "*.example.com" matching any subdomain.
+func HostsOverlap(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if hostMatches(x, y) || hostMatches(y, x) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hostMatches reports whether host matches pattern, a host or a wildcard.
+func hostMatches(pattern, host string) bool {
+	if pattern == "*" || pattern == host {
+		return true
+	}
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+		return strings.HasSuffix(host, suffix)
+	}
+	return false
+}
+
+// traceIngressController checks that the controller of an ingress class
+// has ready pods. An Ingress without a class uses the default IngressClass.
+// Controllers whose pods k1s doesn't know are reported without checking.
+func traceIngressController(ctx context.Context, clientset kubernetes.Interface, class string) (TraceHop, error) {
+	hop := TraceHop{Kind: HopController, Name: class}
+	var controller string
+	if class == "" {
+		classes, err := clientset.NetworkingV1().IngressClasses().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return hop, fmt.Errorf("failed to list ingress classes: %w", err)
+		}
+		for _, c := range classes.Items {
+			if c.Annotations[defaultIngressClassAnnotation] == "true" {
+				hop.Name, controller = c.Name, c.Spec.Controller
+			}
+		}
+		if hop.Name == "" {
+			hop.Detail = "no ingress class set and no default IngressClass"
+			return hop, nil
+		}
+	} else {
+		ingressClass, err := clientset.NetworkingV1().IngressClasses().Get(ctx, class, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err) && legacyIngressControllers[class] != "":
+			// Given with the kubernetes.io/ingress.class annotation
+			controller = legacyIngressControllers[class]
+		case apierrors.IsNotFound(err):
+			hop.Detail = fmt.Sprintf("IngressClass %s not found", class)
+			return hop, nil
+		case err != nil:
+			return hop, fmt.Errorf("failed to get ingress class %s: %w", class, err)
+		default:
+			controller = ingressClass.Spec.Controller
+		}
+	}
+
+	selector, ok := ingressControllerPods[controller]
+	if !ok {
+		hop.OK = true
+		hop.Detail = fmt.Sprintf("controller %s, pods not checked", controller)
+		return hop, nil
+	}
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return hop, fmt.Errorf("failed to list ingress controller pods: %w", err)
+	}
+	ready := 0
+	for i := range pods.Items {
+		if isPodReady(&pods.Items[i]) {
+			ready++
+		}
+	}
+	switch {
+	case len(pods.Items) == 0:
+		hop.Detail = fmt.Sprintf("no %s controller pods (%s)", controller, selector)
+	case ready == 0:
+		hop.Detail = fmt.Sprintf("%s: none of %d controller pods ready", controller, len(pods.Items))
+	default:
+		hop.OK = true
+		hop.Detail = fmt.Sprintf("%s: %d/%d controller pods ready", controller, ready, len(pods.Items))
+	}
+	return hop, nil
+}
+
+// traceBackend follows traffic to port of a Service, a number or a port
+// name; an empty port stands for the only port of the Service. Hops after
+// one that leaves nothing to check, such as a missing Service, are left
+// out.
+func traceBackend(ctx context.Context, clientset kubernetes.Interface, namespace, service, port string) ([]TraceHop, error) {
+	svc, err := clientset.CoreV1().Services(namespace).Get(ctx, service, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []TraceHop{{Kind: HopService, Name: service, Detail: fmt.Sprintf("service %s not found in namespace %s", service, namespace)}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s: %w", service, err)
+	}
+
+	serviceHop := TraceHop{Kind: HopService, Name: service}
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		serviceHop.OK = true
+		serviceHop.Detail = "ExternalName → " + svc.Spec.ExternalName
+		return []TraceHop{serviceHop}, nil
+	}
+	servicePort := findServicePort(svc.Spec.Ports, port)
+	if servicePort == nil {
+		if port == "" {
+			serviceHop.Detail = fmt.Sprintf("no port given and the service has %d", len(svc.Spec.Ports))
+		} else {
+			serviceHop.Detail = fmt.Sprintf("no port %s (has %s)", port, serviceToServiceInfo(svc).Ports)
+		}
+		return []TraceHop{serviceHop}, nil
+	}
+	targetPort := servicePort.TargetPort
+	if targetPort.IntValue() == 0 && targetPort.Type == intstr.Int {
+		targetPort = intstr.FromInt32(servicePort.Port)
+	}
+	serviceHop.OK = true
+	serviceHop.Detail = fmt.Sprintf("port %d → targetPort %s", servicePort.Port, targetPort.String())
+	hops := []TraceHop{serviceHop}
+
+	slices, err := listServiceEndpointSlices(ctx, clientset, namespace, service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpointslices of %s: %w", service, err)
+	}
+	hops = append(hops, traceEndpoints(service, slices.Items))
+
+	if len(svc.Spec.Selector) == 0 {
+		hops = append(hops, TraceHop{Kind: HopPods, Name: service, OK: true, Detail: "no selector, endpoints are managed outside Kubernetes"})
+		return hops, nil
+	}
+	selector := labels.SelectorFromSet(svc.Spec.Selector).String()
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods of %s: %w", service, err)
+	}
+	return append(hops, traceTargetPort(selector, pods.Items, targetPort)), nil
+}
+
+// findServicePort returns the port of a Service with the given number or
+// name, or its only port when port is empty.
+func findServicePort(ports []corev1.ServicePort, port string) *corev1.ServicePort {
+	if port == "" {
+		if len(ports) == 1 {
+			return &ports[0]
+		}
+		return nil
+	}
+	for i := range ports {
+		if ports[i].Name == port || strconv.Itoa(int(ports[i].Port)) == port {
+			return &ports[i]
+		}
+	}
+	return nil
+}
+
+// traceEndpoints checks that the EndpointSlices of a Service list ready
+// endpoints.
+func traceEndpoints(service string, slices []discoveryv1.EndpointSlice) TraceHop {
+	hop := TraceHop{Kind: HopEndpoints, Name: service}
+	if len(slices) == 0 {
+		hop.Detail = "no EndpointSlices"
+		return hop
+	}
+	ready, notReady := 0, 0
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			// A nil Ready condition means ready, per the EndpointSlice API
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready++
+			} else {
+				notReady++
+			}
+		}
+	}
+	switch {
+	case ready == 0 && notReady == 0:
+		hop.Detail = "no endpoints"
+	case ready == 0:
+		hop.Detail = fmt.Sprintf("no ready endpoints (%d not ready)", notReady)
+	case notReady > 0:
+		hop.OK = true
+		hop.Detail = fmt.Sprintf("%d ready, %d not ready", ready, notReady)
+	default:
+		hop.OK = true
+		hop.Detail = fmt.Sprintf("%d ready", ready)
+	}
+	return hop
+}
+
+// traceTargetPort checks that the pods a Service selects exist and that
+// its targetPort matches one of their containerPorts: by name for a named
+// targetPort, by number otherwise. A number is accepted when the
+// containers declare no ports, as declaring them is optional.
+func traceTargetPort(selector string, pods []corev1.Pod, targetPort intstr.IntOrString) TraceHop {
+	hop := TraceHop{Kind: HopPods, Name: selector}
+	if len(pods) == 0 {
+		hop.Detail = fmt.Sprintf("selector %s matches no pods", selector)
+		return hop
+	}
+
+	var declared []string
+	seen := make(map[string]bool)
+	matched := false
+	for _, pod := range pods {
+		for _, c := range pod.Spec.Containers {
+			for _, p := range c.Ports {
+				if targetPort.Type == intstr.String && p.Name == targetPort.StrVal ||
+					targetPort.Type == intstr.Int && p.ContainerPort == targetPort.IntVal {
+					matched = true
+				}
+				label := strconv.Itoa(int(p.ContainerPort))
+				if p.Name != "" {
+					label = p.Name + "/" + label
+				}
+				if !seen[label] {
+					seen[label] = true
+					declared = append(declared, label)
+				}
+			}
+		}
+	}
+	sort.Strings(declared)
+
+	switch {
+	case matched:
+		hop.OK = true
+		hop.Detail = fmt.Sprintf("%d pods, targetPort %s matches a containerPort", len(pods), targetPort.String())
+	case len(declared) == 0 && targetPort.Type == intstr.Int:
+		hop.OK = true
+		hop.Detail = fmt.Sprintf("%d pods, targetPort %s (not declared by the containers)", len(pods), targetPort.String())
+	case len(declared) == 0:
+		hop.Detail = fmt.Sprintf("targetPort %s matches no named containerPort (none declared)", targetPort.String())
+	default:
+		hop.Detail = fmt.Sprintf("targetPort %s matches no containerPort (declared: %s)", targetPort.String(), strings.Join(declared, ", "))
+	}
+	return hop
+}
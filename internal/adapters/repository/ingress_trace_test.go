@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func traceService(name string, port int32, targetPort intstr.IntOrString) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": name},
+			Ports:    []corev1.ServicePort{{Name: "http", Port: port, TargetPort: targetPort, Protocol: corev1.ProtocolTCP}},
+		},
+	}
+}
+
+func traceSlice(service string, ready ...bool) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      service + "-abc",
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+		},
+	}
+	for _, r := range ready {
+		r := r
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{Conditions: discoveryv1.EndpointConditions{Ready: &r}})
+	}
+	return slice
+}
+
+func tracePod(name, namespace string, labels map[string]string, ports ...corev1.ContainerPort) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Ports: ports}}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func nginxClass() *networkingv1.IngressClass {
+	return &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	}
+}
+
+func traceIngressInfo(service, port string) IngressInfo {
+	return IngressInfo{
+		Name:  "shop",
+		Class: "nginx",
+		Rules: []IngressRuleInfo{{
+			Host:  "shop.example.com",
+			Paths: []IngressPathInfo{{Path: "/api", PathType: "Prefix", ServiceName: service, ServicePort: port}},
+		}},
+	}
+}
+
+// hopSummary renders hops as "Kind:ok" or "Kind:fail", for comparison.
+func hopSummary(hops []TraceHop) string {
+	var parts []string
+	for _, h := range hops {
+		state := "fail"
+		if h.OK {
+			state = "ok"
+		}
+		parts = append(parts, h.Kind+":"+state)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestTraceIngress(t *testing.T) {
+	controller := tracePod("ingress-nginx-controller", "ingress-nginx", map[string]string{"app.kubernetes.io/name": "ingress-nginx"})
+	web := tracePod("web-1", "default", map[string]string{"app": "web"}, corev1.ContainerPort{Name: "http", ContainerPort: 8080})
+
+	tests := []struct {
+		name     string
+		objects  []runtime.Object
+		ing      IngressInfo
+		want     string
+		failedAt string // Detail of the failed hop
+	}{
+		{
+			name:    "reaches the pods",
+			objects: []runtime.Object{nginxClass(), controller, traceService("web", 80, intstr.FromInt32(8080)), traceSlice("web", true), web},
+			ing:     traceIngressInfo("web", "80"),
+			want:    "Controller:ok Service:ok Endpoints:ok Pods:ok",
+		},
+		{
+			name:     "missing service",
+			objects:  []runtime.Object{nginxClass(), controller},
+			ing:      traceIngressInfo("web", "80"),
+			want:     "Controller:ok Service:fail",
+			failedAt: "service web not found in namespace default",
+		},
+		{
+			name:     "service port mismatch",
+			objects:  []runtime.Object{nginxClass(), controller, traceService("web", 80, intstr.FromInt32(8080)), traceSlice("web", true), web},
+			ing:      traceIngressInfo("web", "443"),
+			want:     "Controller:ok Service:fail",
+			failedAt: "no port 443 (has 80/TCP)",
+		},
+		{
+			name:     "targetPort mismatch",
+			objects:  []runtime.Object{nginxClass(), controller, traceService("web", 80, intstr.FromInt32(9090)), traceSlice("web", true), web},
+			ing:      traceIngressInfo("web", "http"),
+			want:     "Controller:ok Service:ok Endpoints:ok Pods:fail",
+			failedAt: "targetPort 9090 matches no containerPort (declared: http/8080)",
+		},
+		{
+			name:     "named targetPort mismatch",
+			objects:  []runtime.Object{nginxClass(), controller, traceService("web", 80, intstr.FromString("web")), traceSlice("web", true), web},
+			ing:      traceIngressInfo("web", "80"),
+			want:     "Controller:ok Service:ok Endpoints:ok Pods:fail",
+			failedAt: "targetPort web matches no containerPort (declared: http/8080)",
+		},
+		{
+			name:     "empty endpoints",
+			objects:  []runtime.Object{nginxClass(), controller, traceService("web", 80, intstr.FromInt32(8080)), traceSlice("web"), web},
+			ing:      traceIngressInfo("web", "80"),
+			want:     "Controller:ok Service:ok Endpoints:fail Pods:ok",
+			failedAt: "no endpoints",
+		},
+		{
+			name:     "endpoints not ready",
+			objects:  []runtime.Object{nginxClass(), controller, traceService("web", 80, intstr.FromInt32(8080)), traceSlice("web", false, false), web},
+			ing:      traceIngressInfo("web", "80"),
+			want:     "Controller:ok Service:ok Endpoints:fail Pods:ok",
+			failedAt: "no ready endpoints (2 not ready)",
+		},
+		{
+			name:     "no controller pods",
+			objects:  []runtime.Object{nginxClass(), traceService("web", 80, intstr.FromInt32(8080)), traceSlice("web", true), web},
+			ing:      traceIngressInfo("web", "80"),
+			want:     "Controller:fail Service:ok Endpoints:ok Pods:ok",
+			failedAt: "no k8s.io/ingress-nginx controller pods (app.kubernetes.io/name=ingress-nginx)",
+		},
+		{
+			name:     "selector matches no pods",
+			objects:  []runtime.Object{nginxClass(), controller, traceService("web", 80, intstr.FromInt32(8080)), traceSlice("web", true)},
+			ing:      traceIngressInfo("web", "80"),
+			want:     "Controller:ok Service:ok Endpoints:ok Pods:fail",
+			failedAt: "selector app=web matches no pods",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tt.objects...)
+			traces, err := TraceIngress(context.Background(), clientset, "default", tt.ing)
+			if err != nil {
+				t.Fatalf("TraceIngress() error = %v", err)
+			}
+			if len(traces) != 1 {
+				t.Fatalf("TraceIngress() = %d traces, want 1", len(traces))
+			}
+			trace := traces[0]
+			if trace.Source != "Ingress/shop" || !strings.HasPrefix(trace.Route, "shop.example.com/api → web:") {
+				t.Errorf("trace = %s %s", trace.Source, trace.Route)
+			}
+			if got := hopSummary(trace.Hops); got != tt.want {
+				t.Errorf("hops = %s, want %s", got, tt.want)
+			}
+			failed := trace.FailedHop()
+			if tt.failedAt == "" {
+				if failed != nil || !trace.OK() {
+					t.Errorf("FailedHop() = %+v, want none", failed)
+				}
+				return
+			}
+			if failed == nil || failed.Detail != tt.failedAt {
+				t.Errorf("FailedHop() = %+v, want %q", failed, tt.failedAt)
+			}
+		})
+	}
+}
+
+func TestTraceIngress_DefaultClass(t *testing.T) {
+	ing := traceIngressInfo("web", "80")
+	ing.Class = ""
+
+	clientset := fake.NewSimpleClientset(traceService("web", 80, intstr.FromInt32(8080)))
+	traces, err := TraceIngress(context.Background(), clientset, "default", ing)
+	if err != nil {
+		t.Fatalf("TraceIngress() error = %v", err)
+	}
+	if hop := traces[0].Hops[0]; hop.OK || hop.Detail != "no ingress class set and no default IngressClass" {
+		t.Errorf("controller hop = %+v, want no default class", hop)
+	}
+
+	class := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "gce", Annotations: map[string]string{defaultIngressClassAnnotation: "true"}},
+		Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-gce"},
+	}
+	clientset = fake.NewSimpleClientset(class, traceService("web", 80, intstr.FromInt32(8080)))
+	traces, err = TraceIngress(context.Background(), clientset, "default", ing)
+	if err != nil {
+		t.Fatalf("TraceIngress() error = %v", err)
+	}
+	if hop := traces[0].Hops[0]; !hop.OK || hop.Name != "gce" {
+		t.Errorf("controller hop = %+v, want the default class, unchecked", hop)
+	}
+}
+
+func TestTraceVirtualServiceRoute(t *testing.T) {
+	web := tracePod("web-1", "shop", map[string]string{"app": "web"}, corev1.ContainerPort{ContainerPort: 8080})
+	svc := traceService("web", 80, intstr.FromInt32(8080))
+	svc.Namespace = "shop"
+	slice := traceSlice("web", true)
+	slice.Namespace = "shop"
+	clientset := fake.NewSimpleClientset(svc, slice, web)
+
+	vs := VirtualServiceInfo{Name: "shop", Hosts: []string{"shop.example.com"}}
+	route := VirtualServiceRoute{Match: "uri prefix: /api", Destination: "web.shop.svc.cluster.local", Port: 80}
+	trace, err := TraceVirtualServiceRoute(context.Background(), clientset, "default", vs, route)
+	if err != nil {
+		t.Fatalf("TraceVirtualServiceRoute() error = %v", err)
+	}
+	if got := hopSummary(trace.Hops); got != "Service:ok Endpoints:ok Pods:ok" {
+		t.Errorf("hops = %s, want every hop ok", got)
+	}
+
+	route.Port = 8443
+	trace, err = TraceVirtualServiceRoute(context.Background(), clientset, "default", vs, route)
+	if err != nil {
+		t.Fatalf("TraceVirtualServiceRoute() error = %v", err)
+	}
+	if failed := trace.FailedHop(); failed == nil || failed.Kind != HopService {
+		t.Errorf("FailedHop() = %+v, want the service port", failed)
+	}
+}
+
+func TestHostsOverlap(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want bool
+	}{
+		{[]string{"shop.example.com"}, []string{"shop.example.com"}, true},
+		{[]string{"*.example.com"}, []string{"shop.example.com"}, true},
+		{[]string{"shop.example.com"}, []string{"*"}, true},
+		{[]string{"shop.example.com"}, []string{"blog.example.com"}, false},
+		{nil, []string{"shop.example.com"}, false},
+	}
+	for _, tt := range tests {
+		if got := HostsOverlap(tt.a, tt.b); got != tt.want {
+			t.Errorf("HostsOverlap(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// istioProxyContainer is the name of the sidecar Istio injects.
+const istioProxyContainer = "istio-proxy"
+
+// DestinationRuleInfo is an Istio DestinationRule for one of a pod's
+// Services: how traffic to the host is balanced, encrypted and ejected.
+type DestinationRuleInfo struct {
+	Name             string
+	Host             string
+	TLSMode          string // trafficPolicy.tls.mode: DISABLE, SIMPLE, MUTUAL or ISTIO_MUTUAL; "" when unset
+	LoadBalancer     string // trafficPolicy.loadBalancer.simple, e.g. ROUND_ROBIN
+	OutlierDetection string // Summary of trafficPolicy.outlierDetection; "" when unset
+	Subsets          []DestinationRuleSubset
+}
+
+// DestinationRuleSubset is a named subset of the pods behind a host, such
+// as a version VirtualServices route to.
+type DestinationRuleSubset struct {
+	Name       string
+	Labels     map[string]string
+	TLSMode    string // Subset override of the rule's TLS mode; "" when none
+	MatchesPod bool   // The pod's labels select it into this subset
+}
+
+// SidecarStatus is the state of a pod's Istio sidecar and whether the
+// namespace asks for one.
+type SidecarStatus struct {
+	Present          bool   // The pod has an istio-proxy container
+	Ready            bool   // The istio-proxy container is ready
+	Version          string // Proxy version, from the image tag
+	InjectionEnabled bool   // The namespace enables injection (istio-injection=enabled or istio.io/rev)
+	OptedOut         bool   // The pod disables injection with sidecar.istio.io/inject=false
+}
+
+// Missing reports whether the pod should have a sidecar but has none,
+// usually because it was created before injection was enabled.
+func (s SidecarStatus) Missing() bool {
+	return s.InjectionEnabled && !s.OptedOut && !s.Present
+}
+
+var destinationRuleGVR = schema.GroupVersionResource{
+	Group:    "networking.istio.io",
+	Version:  "v1beta1",
+	Resource: "destinationrules",
+}
+
+// getDestinationRules returns the DestinationRules of namespace whose host
+// is one of services, by short or fully qualified name. Rules that can't
+// be listed, e.g. without the Istio CRDs, are left out.
+func getDestinationRules(ctx context.Context, dynamicClient dynamic.Interface, namespace string, serviceNames map[string]bool) []DestinationRuleInfo {
+	list, err := dynamicClient.Resource(destinationRuleGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+
+	var rules []DestinationRuleInfo
+	for _, item := range list.Items {
+		spec, _ := item.Object["spec"].(map[string]interface{})
+		host, _ := spec["host"].(string)
+		if !serviceNames[strings.Split(host, ".")[0]] && !serviceNames[host] {
+			continue
+		}
+		rule := DestinationRuleInfo{Name: item.GetName(), Host: host}
+		policy, _ := spec["trafficPolicy"].(map[string]interface{})
+		rule.TLSMode = trafficPolicyTLSMode(policy)
+		if lb, ok := policy["loadBalancer"].(map[string]interface{}); ok {
+			rule.LoadBalancer, _ = lb["simple"].(string)
+		}
+		if od, ok := policy["outlierDetection"].(map[string]interface{}); ok {
+			rule.OutlierDetection = outlierDetectionSummary(od)
+		}
+		subsets, _ := spec["subsets"].([]interface{})
+		for _, s := range subsets {
+			subsetMap, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			subset := DestinationRuleSubset{Labels: map[string]string{}}
+			subset.Name, _ = subsetMap["name"].(string)
+			if labels, ok := subsetMap["labels"].(map[string]interface{}); ok {
+				for k, v := range labels {
+					subset.Labels[k] = fmt.Sprint(v)
+				}
+			}
+			subsetPolicy, _ := subsetMap["trafficPolicy"].(map[string]interface{})
+			subset.TLSMode = trafficPolicyTLSMode(subsetPolicy)
+			rule.Subsets = append(rule.Subsets, subset)
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// trafficPolicyTLSMode returns tls.mode of a trafficPolicy, "" when unset.
+func trafficPolicyTLSMode(policy map[string]interface{}) string {
+	tls, _ := policy["tls"].(map[string]interface{})
+	mode, _ := tls["mode"].(string)
+	return mode
+}
+
+// outlierDetectionSummary describes an outlierDetection policy, e.g.
+// "eject after 5 consecutive 5xx errors, checked every 10s, for 30s".
+func outlierDetectionSummary(od map[string]interface{}) string {
+	var parts []string
+	errors, ok := istioNumber(od["consecutive5xxErrors"])
+	if !ok {
+		errors, ok = istioNumber(od["consecutiveErrors"])
+	}
+	if ok {
+		parts = append(parts, fmt.Sprintf("eject after %d consecutive 5xx errors", errors))
+	}
+	if interval, ok := od["interval"].(string); ok {
+		parts = append(parts, "checked every "+interval)
+	}
+	if ejection, ok := od["baseEjectionTime"].(string); ok {
+		parts = append(parts, "for "+ejection)
+	}
+	if max, ok := istioNumber(od["maxEjectionPercent"]); ok {
+		parts = append(parts, fmt.Sprintf("at most %d%% of hosts", max))
+	}
+	if len(parts) == 0 {
+		return "enabled (defaults)"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// istioNumber reads a number of an unstructured object, which decodes as
+// int64 or float64 depending on where it came from.
+func istioNumber(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	}
+	return 0, false
+}
+
+// markPodSubsets sets MatchesPod on the subsets whose labels the pod has.
+func markPodSubsets(rules []DestinationRuleInfo, podLabels map[string]string) {
+	for i := range rules {
+		for j := range rules[i].Subsets {
+			subset := &rules[i].Subsets[j]
+			subset.MatchesPod = len(subset.Labels) > 0
+			for k, v := range subset.Labels {
+				if podLabels[k] != v {
+					subset.MatchesPod = false
+					break
+				}
+			}
+		}
+	}
+}
+
+// GetSidecarStatus returns the Istio sidecar state of a pod in ns, which
+// may be nil when the namespace could not be read. Returns nil when Istio
+// is not involved: no sidecar and no injection enabled.
+func GetSidecarStatus(pod *corev1.Pod, ns *corev1.Namespace) *SidecarStatus {
+	status := &SidecarStatus{}
+	if ns != nil {
+		injection := ns.Labels["istio-injection"]
+		status.InjectionEnabled = injection == "enabled" || (injection != "disabled" && ns.Labels["istio.io/rev"] != "")
+	}
+	status.OptedOut = pod.Annotations["sidecar.istio.io/inject"] == "false" || pod.Labels["sidecar.istio.io/inject"] == "false"
+
+	// Native sidecars are init containers that keep running
+	containers := append(append([]corev1.Container{}, pod.Spec.Containers...), pod.Spec.InitContainers...)
+	for _, c := range containers {
+		if c.Name == istioProxyContainer {
+			status.Present = true
+			status.Version = imageTag(c.Image)
+		}
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.ContainerStatuses...), pod.Status.InitContainerStatuses...)
+	for _, cs := range statuses {
+		if cs.Name == istioProxyContainer {
+			status.Ready = cs.Ready
+		}
+	}
+
+	if !status.Present && !status.InjectionEnabled {
+		return nil
+	}
+	return status
+}
+
+// imageTag returns the tag of an image reference, without any digest;
+// "" when the image has no tag.
+func imageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	slash := strings.LastIndex(image, "/")
+	colon := strings.LastIndex(image, ":")
+	if colon <= slash {
+		return ""
+	}
+	return image[colon+1:]
+}
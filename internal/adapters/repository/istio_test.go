@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestGetDestinationRules(t *testing.T) {
+	rule := func(name, host string, spec map[string]interface{}) *unstructured.Unstructured {
+		spec["host"] = host
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "networking.istio.io/v1beta1",
+			"kind":       "DestinationRule",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+			"spec":       spec,
+		}}
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{destinationRuleGVR: "DestinationRuleList"},
+		rule("reviews", "reviews.default.svc.cluster.local", map[string]interface{}{
+			"trafficPolicy": map[string]interface{}{
+				"tls":          map[string]interface{}{"mode": "ISTIO_MUTUAL"},
+				"loadBalancer": map[string]interface{}{"simple": "LEAST_REQUEST"},
+				"outlierDetection": map[string]interface{}{
+					"consecutive5xxErrors": int64(5),
+					"interval":             "10s",
+					"baseEjectionTime":     "30s",
+				},
+			},
+			"subsets": []interface{}{
+				map[string]interface{}{"name": "v1", "labels": map[string]interface{}{"version": "v1"}},
+				map[string]interface{}{
+					"name":          "v2",
+					"labels":        map[string]interface{}{"version": "v2"},
+					"trafficPolicy": map[string]interface{}{"tls": map[string]interface{}{"mode": "DISABLE"}},
+				},
+			},
+		}),
+		rule("other", "ratings", map[string]interface{}{}),
+	)
+
+	rules := getDestinationRules(context.Background(), dynamicClient, "default", map[string]bool{"reviews": true})
+	if len(rules) != 1 {
+		t.Fatalf("got %d rules, want only the one for the pod's service", len(rules))
+	}
+	dr := rules[0]
+	if dr.Name != "reviews" || dr.TLSMode != "ISTIO_MUTUAL" || dr.LoadBalancer != "LEAST_REQUEST" {
+		t.Errorf("rule = %+v, want reviews with ISTIO_MUTUAL and LEAST_REQUEST", dr)
+	}
+	if want := "eject after 5 consecutive 5xx errors, checked every 10s, for 30s"; dr.OutlierDetection != want {
+		t.Errorf("OutlierDetection = %q, want %q", dr.OutlierDetection, want)
+	}
+	if len(dr.Subsets) != 2 || dr.Subsets[0].Name != "v1" || dr.Subsets[1].TLSMode != "DISABLE" {
+		t.Fatalf("Subsets = %+v, want v1 and v2 with TLS disabled", dr.Subsets)
+	}
+
+	markPodSubsets(rules, map[string]string{"app": "reviews", "version": "v2"})
+	if rules[0].Subsets[0].MatchesPod || !rules[0].Subsets[1].MatchesPod {
+		t.Errorf("Subsets = %+v, want the pod in v2 only", rules[0].Subsets)
+	}
+}
+
+func TestGetSidecarStatus(t *testing.T) {
+	enabled := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"istio-injection": "enabled"}}}
+	revision := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"istio.io/rev": "1-20"}}}
+	plain := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev"}}
+
+	withProxy := &corev1.Pod{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "app", Image: "app:1"},
+			{Name: "istio-proxy", Image: "docker.io/istio/proxyv2:1.20.3"},
+		}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "app", Ready: true},
+			{Name: "istio-proxy", Ready: false},
+		}},
+	}
+	status := GetSidecarStatus(withProxy, enabled)
+	if status == nil || !status.Present || status.Ready || status.Version != "1.20.3" || status.Missing() {
+		t.Errorf("GetSidecarStatus(with proxy) = %+v, want present, not ready, 1.20.3", status)
+	}
+
+	bare := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}}
+	if status := GetSidecarStatus(bare, revision); status == nil || !status.Missing() {
+		t.Errorf("GetSidecarStatus(no proxy, injection on) = %+v, want missing", status)
+	}
+	if status := GetSidecarStatus(bare, plain); status != nil {
+		t.Errorf("GetSidecarStatus(no Istio) = %+v, want nil", status)
+	}
+
+	optedOut := bare.DeepCopy()
+	optedOut.Annotations = map[string]string{"sidecar.istio.io/inject": "false"}
+	if status := GetSidecarStatus(optedOut, enabled); status == nil || status.Missing() {
+		t.Errorf("GetSidecarStatus(opted out) = %+v, want not missing", status)
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	tests := map[string]string{
+		"docker.io/istio/proxyv2:1.20.3":        "1.20.3",
+		"registry:5000/istio/proxyv2":           "",
+		"proxyv2:1.19.0@sha256:abc":             "1.19.0",
+		"gcr.io/istio-release/proxyv2@sha256:a": "",
+	}
+	for image, want := range tests {
+		if got := imageTag(image); got != want {
+			t.Errorf("imageTag(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/homedir"
+)
+
+// KubeconfigRules returns the rules for loading kubeconfig files, with the
+// precedence kubectl uses: an explicit path (the --kubeconfig flag) is the
+// only file loaded; otherwise the files listed in KUBECONFIG, separated by
+// ":" (";" on Windows), are merged, with the first file to set a value
+// winning; otherwise ~/.kube/config. A leading "~" in KUBECONFIG entries is
+// expanded, as the shell leaves it alone when quoted.
+func KubeconfigRules(explicitPath string) *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	for i, path := range rules.Precedence {
+		rules.Precedence[i] = expandHome(path)
+	}
+	rules.ExplicitPath = expandHome(explicitPath)
+	return rules
+}
+
+// KubeconfigWarnings reports the KUBECONFIG entries that don't exist. They
+// are skipped when loading rather than failing startup, as kubectl does.
+// An explicit path is not checked: loading it fails if it is missing.
+func KubeconfigWarnings(rules *clientcmd.ClientConfigLoadingRules) []string {
+	if rules.ExplicitPath != "" {
+		return nil
+	}
+	var warnings []string
+	for _, path := range rules.Precedence {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			warnings = append(warnings, fmt.Sprintf("kubeconfig %s not found, skipped", path))
+		}
+	}
+	return warnings
+}
+
+// expandHome replaces a leading "~" with the user's home directory.
+func expandHome(path string) string {
+	if path == "~" {
+		return homedir.HomeDir()
+	}
+	if rest, ok := strings.CutPrefix(path, "~"+string(filepath.Separator)); ok {
+		return filepath.Join(homedir.HomeDir(), rest)
+	}
+	return path
+}
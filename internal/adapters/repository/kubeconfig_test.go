@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/util/homedir"
+)
+
+func TestKubeconfigRules(t *testing.T) {
+	oldKubeconfig := os.Getenv("KUBECONFIG")
+	defer os.Setenv("KUBECONFIG", oldKubeconfig)
+
+	sep := string(filepath.ListSeparator)
+	os.Setenv("KUBECONFIG", "~/.kube/config"+sep+"/etc/k8s/work")
+	rules := KubeconfigRules("")
+	want := []string{filepath.Join(homedir.HomeDir(), ".kube", "config"), "/etc/k8s/work"}
+	if strings.Join(rules.Precedence, sep) != strings.Join(want, sep) {
+		t.Errorf("Precedence = %v, want %v", rules.Precedence, want)
+	}
+	if rules.ExplicitPath != "" {
+		t.Errorf("ExplicitPath = %q, want none", rules.ExplicitPath)
+	}
+
+	rules = KubeconfigRules("~/other")
+	if rules.ExplicitPath != filepath.Join(homedir.HomeDir(), "other") {
+		t.Errorf("ExplicitPath = %q, want ~ expanded", rules.ExplicitPath)
+	}
+
+	os.Setenv("KUBECONFIG", "")
+	rules = KubeconfigRules("")
+	if len(rules.Precedence) != 1 || rules.Precedence[0] != filepath.Join(homedir.HomeDir(), ".kube", "config") {
+		t.Errorf("Precedence = %v, want the default ~/.kube/config", rules.Precedence)
+	}
+}
+
+func TestKubeconfigWarnings(t *testing.T) {
+	tmpDir := t.TempDir()
+	present := filepath.Join(tmpDir, "config")
+	if err := os.WriteFile(present, []byte("apiVersion: v1\nkind: Config\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(tmpDir, "missing")
+
+	rules := KubeconfigRules("")
+	rules.Precedence = []string{present, missing, ""}
+	warnings := KubeconfigWarnings(rules)
+	if len(warnings) != 1 || warnings[0] != "kubeconfig "+missing+" not found, skipped" {
+		t.Errorf("KubeconfigWarnings() = %v, want one for %s", warnings, missing)
+	}
+
+	rules.ExplicitPath = present
+	if warnings := KubeconfigWarnings(rules); len(warnings) != 0 {
+		t.Errorf("KubeconfigWarnings() with an explicit path = %v, want none", warnings)
+	}
+}
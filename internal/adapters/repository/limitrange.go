@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ContainerLimitRange holds the Container-type constraints of one LimitRange.
+type ContainerLimitRange struct {
+	Name                 string
+	Min                  corev1.ResourceList
+	Max                  corev1.ResourceList
+	Default              corev1.ResourceList // Default limits
+	DefaultRequest       corev1.ResourceList
+	MaxLimitRequestRatio corev1.ResourceList
+}
+
+// LimitRangeViolation is a value the LimitRanger admission plugin would
+// reject, e.g. "memory limit 2Gi exceeds LimitRange max 1Gi".
+type LimitRangeViolation struct {
+	LimitRange string
+	Resource   string // "cpu" or "memory"
+	Message    string
+}
+
+// LimitRangeDefault is a value a LimitRange fills in for an unset request
+// or limit.
+type LimitRangeDefault struct {
+	LimitRange string
+	Resource   string // "cpu" or "memory"
+	Kind       string // "request" or "limit"
+	Value      string
+}
+
+// effectiveResources are a container's request and limit for one resource
+// after LimitRange defaults. A nil quantity means unset.
+type effectiveResources struct {
+	request *resource.Quantity
+	limit   *resource.Quantity
+}
+
+// GetContainerLimitRanges returns the Container-type limits of every
+// LimitRange in the namespace, in the order the API returns them.
+func GetContainerLimitRanges(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ContainerLimitRange, error) {
+	list, err := clientset.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list limit ranges: %w", err)
+	}
+
+	var ranges []ContainerLimitRange
+	for _, lr := range list.Items {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			ranges = append(ranges, ContainerLimitRange{
+				Name:                 lr.Name,
+				Min:                  item.Min,
+				Max:                  item.Max,
+				Default:              item.Default,
+				DefaultRequest:       item.DefaultRequest,
+				MaxLimitRequestRatio: item.MaxLimitRequestRatio,
+			})
+		}
+	}
+	return ranges, nil
+}
+
+// LimitRangeDefaults lists the values LimitRanges would fill in for the
+// container's unset requests and limits. As in the admission plugin, the
+// first LimitRange that provides a default wins.
+func LimitRangeDefaults(res ResourceRequirements, ranges []ContainerLimitRange) []LimitRangeDefault {
+	_, defaults := applyLimitRangeDefaults(res, ranges)
+	return defaults
+}
+
+// ValidateContainerResources checks the container's requests and limits,
+// after defaults are applied, against the min, max and
+// maxLimitRequestRatio of every LimitRange.
+func ValidateContainerResources(res ResourceRequirements, ranges []ContainerLimitRange) []LimitRangeViolation {
+	effective, _ := applyLimitRangeDefaults(res, ranges)
+
+	var violations []LimitRangeViolation
+	for _, lr := range ranges {
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			for _, msg := range checkLimitRange(lr, name, effective[name]) {
+				violations = append(violations, LimitRangeViolation{LimitRange: lr.Name, Resource: string(name), Message: msg})
+			}
+		}
+	}
+	return violations
+}
+
+// checkLimitRange returns the violation messages for one resource.
+func checkLimitRange(lr ContainerLimitRange, name corev1.ResourceName, eff effectiveResources) []string {
+	var msgs []string
+
+	if min, ok := lr.Min[name]; ok {
+		if eff.request == nil {
+			msgs = append(msgs, fmt.Sprintf("%s request required by LimitRange min %s", name, min.String()))
+		} else if eff.request.Cmp(min) < 0 {
+			msgs = append(msgs, fmt.Sprintf("%s request %s is below LimitRange min %s", name, eff.request.String(), min.String()))
+		}
+		if eff.limit != nil && eff.limit.Cmp(min) < 0 {
+			msgs = append(msgs, fmt.Sprintf("%s limit %s is below LimitRange min %s", name, eff.limit.String(), min.String()))
+		}
+	}
+
+	if max, ok := lr.Max[name]; ok {
+		if eff.limit == nil {
+			msgs = append(msgs, fmt.Sprintf("%s limit required by LimitRange max %s", name, max.String()))
+		} else if eff.limit.Cmp(max) > 0 {
+			msgs = append(msgs, fmt.Sprintf("%s limit %s exceeds LimitRange max %s", name, eff.limit.String(), max.String()))
+		}
+		if eff.request != nil && eff.request.Cmp(max) > 0 {
+			msgs = append(msgs, fmt.Sprintf("%s request %s exceeds LimitRange max %s", name, eff.request.String(), max.String()))
+		}
+	}
+
+	if ratio, ok := lr.MaxLimitRequestRatio[name]; ok {
+		switch {
+		case eff.request == nil || eff.request.IsZero():
+			msgs = append(msgs, fmt.Sprintf("%s request required by LimitRange maxLimitRequestRatio %s", name, ratio.String()))
+		case eff.limit == nil:
+			msgs = append(msgs, fmt.Sprintf("%s limit required by LimitRange maxLimitRequestRatio %s", name, ratio.String()))
+		default:
+			// Compare in milli-units, as the admission plugin does
+			actual := float64(eff.limit.MilliValue()) / float64(eff.request.MilliValue())
+			if actual > float64(ratio.MilliValue())/1000 {
+				msgs = append(msgs, fmt.Sprintf("%s limit/request ratio %s exceeds LimitRange maxLimitRequestRatio %s",
+					name, strconv.FormatFloat(math.Round(actual*100)/100, 'f', -1, 64), ratio.String()))
+			}
+		}
+	}
+
+	return msgs
+}
+
+// applyLimitRangeDefaults returns the effective request and limit per
+// resource. Unset limits take the first Default; unset requests take the
+// first DefaultRequest, else the (possibly defaulted) limit, matching how
+// the API server fills requests from limits.
+func applyLimitRangeDefaults(res ResourceRequirements, ranges []ContainerLimitRange) (map[corev1.ResourceName]effectiveResources, []LimitRangeDefault) {
+	values := map[corev1.ResourceName]effectiveResources{
+		corev1.ResourceCPU:    {request: parseSetQuantity(res.CPURequest), limit: parseSetQuantity(res.CPULimit)},
+		corev1.ResourceMemory: {request: parseSetQuantity(res.MemoryRequest), limit: parseSetQuantity(res.MemoryLimit)},
+	}
+
+	var defaults []LimitRangeDefault
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		eff := values[name]
+		if eff.limit == nil {
+			for _, lr := range ranges {
+				if q, ok := lr.Default[name]; ok {
+					eff.limit = &q
+					defaults = append(defaults, LimitRangeDefault{LimitRange: lr.Name, Resource: string(name), Kind: "limit", Value: q.String()})
+					break
+				}
+			}
+		}
+		if eff.request == nil {
+			for _, lr := range ranges {
+				if q, ok := lr.DefaultRequest[name]; ok {
+					eff.request = &q
+					defaults = append(defaults, LimitRangeDefault{LimitRange: lr.Name, Resource: string(name), Kind: "request", Value: q.String()})
+					break
+				}
+			}
+		}
+		if eff.request == nil && eff.limit != nil {
+			q := eff.limit.DeepCopy()
+			eff.request = &q
+		}
+		values[name] = eff
+	}
+	return values, defaults
+}
+
+// parseSetQuantity parses a request or limit, returning nil when it is
+// unset ("" or "0", as reported by podToPodInfo) or invalid.
+func parseSetQuantity(s string) *resource.Quantity {
+	if s == "" || s == "0" {
+		return nil
+	}
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return nil
+	}
+	return &q
+}
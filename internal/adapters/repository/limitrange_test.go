@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func resourceList(cpu, mem string) corev1.ResourceList {
+	list := corev1.ResourceList{}
+	if cpu != "" {
+		list[corev1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if mem != "" {
+		list[corev1.ResourceMemory] = resource.MustParse(mem)
+	}
+	return list
+}
+
+func TestValidateContainerResources(t *testing.T) {
+	limits := ContainerLimitRange{
+		Name:                 "limits",
+		Min:                  resourceList("50m", "64Mi"),
+		Max:                  resourceList("2", "1Gi"),
+		MaxLimitRequestRatio: resourceList("4", ""),
+	}
+
+	tests := []struct {
+		name   string
+		res    ResourceRequirements
+		ranges []ContainerLimitRange
+		want   []string
+	}{
+		{
+			name:   "within bounds",
+			res:    ResourceRequirements{CPURequest: "100m", CPULimit: "400m", MemoryRequest: "128Mi", MemoryLimit: "512Mi"},
+			ranges: []ContainerLimitRange{limits},
+		},
+		{
+			name:   "memory limit over max",
+			res:    ResourceRequirements{CPURequest: "100m", CPULimit: "200m", MemoryRequest: "128Mi", MemoryLimit: "2Gi"},
+			ranges: []ContainerLimitRange{limits},
+			want:   []string{"memory limit 2Gi exceeds LimitRange max 1Gi"},
+		},
+		{
+			name:   "below min",
+			res:    ResourceRequirements{CPURequest: "10m", CPULimit: "20m", MemoryRequest: "128Mi", MemoryLimit: "256Mi"},
+			ranges: []ContainerLimitRange{limits},
+			want: []string{
+				"cpu request 10m is below LimitRange min 50m",
+				"cpu limit 20m is below LimitRange min 50m",
+			},
+		},
+		{
+			name:   "ratio exceeded",
+			res:    ResourceRequirements{CPURequest: "100m", CPULimit: "500m", MemoryRequest: "128Mi", MemoryLimit: "256Mi"},
+			ranges: []ContainerLimitRange{limits},
+			want:   []string{"cpu limit/request ratio 5 exceeds LimitRange maxLimitRequestRatio 4"},
+		},
+		{
+			name:   "ratio at the limit",
+			res:    ResourceRequirements{CPURequest: "100m", CPULimit: "400m", MemoryRequest: "128Mi", MemoryLimit: "256Mi"},
+			ranges: []ContainerLimitRange{limits},
+		},
+		{
+			name:   "fractional ratio",
+			res:    ResourceRequirements{CPURequest: "300m", CPULimit: "1", MemoryRequest: "128Mi", MemoryLimit: "256Mi"},
+			ranges: []ContainerLimitRange{{Name: "ratio", MaxLimitRequestRatio: resourceList("3", "")}},
+			want:   []string{"cpu limit/request ratio 3.33 exceeds LimitRange maxLimitRequestRatio 3"},
+		},
+		{
+			name:   "unset limit with max and no default",
+			res:    ResourceRequirements{CPURequest: "100m", CPULimit: "0", MemoryRequest: "128Mi", MemoryLimit: "256Mi"},
+			ranges: []ContainerLimitRange{limits},
+			want: []string{
+				"cpu limit required by LimitRange max 2",
+				"cpu limit required by LimitRange maxLimitRequestRatio 4",
+			},
+		},
+		{
+			name:   "request defaults to limit",
+			res:    ResourceRequirements{CPULimit: "1", MemoryLimit: "256Mi"},
+			ranges: []ContainerLimitRange{limits},
+		},
+		{
+			name: "defaulted limit is validated",
+			res:  ResourceRequirements{CPURequest: "100m", MemoryRequest: "128Mi", MemoryLimit: "256Mi"},
+			ranges: []ContainerLimitRange{{
+				Name:                 "defaults",
+				Default:              resourceList("1", ""),
+				MaxLimitRequestRatio: resourceList("2", ""),
+			}},
+			want: []string{"cpu limit/request ratio 10 exceeds LimitRange maxLimitRequestRatio 2"},
+		},
+		{
+			name:   "no limit ranges",
+			res:    ResourceRequirements{CPURequest: "0", CPULimit: "0", MemoryRequest: "0", MemoryLimit: "0"},
+			ranges: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidateContainerResources(tt.res, tt.ranges)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ValidateContainerResources() = %+v, want %v", got, tt.want)
+			}
+			for i, v := range got {
+				if v.Message != tt.want[i] {
+					t.Errorf("violation[%d] = %q, want %q", i, v.Message, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLimitRangeDefaults(t *testing.T) {
+	ranges := []ContainerLimitRange{
+		{Name: "first", Default: resourceList("500m", ""), DefaultRequest: resourceList("", "128Mi")},
+		{Name: "second", Default: resourceList("1", "512Mi"), DefaultRequest: resourceList("100m", "256Mi")},
+	}
+
+	got := LimitRangeDefaults(ResourceRequirements{CPURequest: "0", CPULimit: "0", MemoryRequest: "0", MemoryLimit: "1Gi"}, ranges)
+	want := []LimitRangeDefault{
+		{LimitRange: "first", Resource: "cpu", Kind: "limit", Value: "500m"},
+		{LimitRange: "second", Resource: "cpu", Kind: "request", Value: "100m"},
+		{LimitRange: "first", Resource: "memory", Kind: "request", Value: "128Mi"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("LimitRangeDefaults() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("default[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGetContainerLimitRanges(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "default"},
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{
+			{Type: corev1.LimitTypePod, Max: resourceList("4", "4Gi")},
+			{Type: corev1.LimitTypeContainer, Max: resourceList("2", "1Gi"), Default: resourceList("500m", "256Mi")},
+		}},
+	})
+
+	ranges, err := GetContainerLimitRanges(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatalf("GetContainerLimitRanges() error = %v", err)
+	}
+	if len(ranges) != 1 || ranges[0].Name != "limits" {
+		t.Fatalf("ranges = %+v, want only the Container item", ranges)
+	}
+	if max := ranges[0].Max[corev1.ResourceMemory]; max.String() != "1Gi" {
+		t.Errorf("Max memory = %s, want 1Gi", max.String())
+	}
+}
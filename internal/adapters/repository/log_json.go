@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Keys that structured loggers (zap, logrus, slog, pino, bunyan, ...) use
+// for the level, timestamp and message of a JSON log line.
+var (
+	jsonLevelKeys   = []string{"level", "lvl", "severity", "levelname", "log.level"}
+	jsonTimeKeys    = []string{"time", "ts", "timestamp", "@timestamp"}
+	jsonMessageKeys = []string{"msg", "message", "@message"}
+)
+
+// jsonNumericLevels maps pino/bunyan numeric levels to their names.
+var jsonNumericLevels = map[string]string{
+	"10": "trace", "20": "debug", "30": "info", "40": "warn", "50": "error", "60": "fatal",
+}
+
+// LogField is one key=value field of a JSON log line.
+type LogField struct {
+	Key   string
+	Value string // Strings as-is, anything else as compact JSON
+}
+
+// JSONLog is a log line whose content is a JSON object, with the level,
+// timestamp and message picked out of the usual keys.
+type JSONLog struct {
+	Level   string     // Lower-cased, numeric levels named; "" when absent
+	Time    string     // As logged; "" when absent
+	Message string     // "" when absent
+	Fields  []LogField // Every other field, sorted by key
+}
+
+// ParseJSONLog parses content as a JSON log line. Returns false for
+// anything that isn't a JSON object, so plain lines are left alone.
+func ParseJSONLog(content string) (*JSONLog, bool) {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "{") || !strings.HasSuffix(content, "}") {
+		return nil, false
+	}
+	dec := json.NewDecoder(strings.NewReader(content))
+	dec.UseNumber()
+	var raw map[string]any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, false
+	}
+
+	entry := &JSONLog{}
+	picked := make(map[string]bool)
+	pick := func(keys []string) string {
+		for _, k := range keys {
+			if v, ok := raw[k]; ok {
+				picked[k] = true
+				return jsonFieldValue(v)
+			}
+		}
+		return ""
+	}
+	entry.Level = strings.ToLower(pick(jsonLevelKeys))
+	if name, ok := jsonNumericLevels[entry.Level]; ok {
+		entry.Level = name
+	}
+	entry.Time = pick(jsonTimeKeys)
+	entry.Message = pick(jsonMessageKeys)
+
+	for k, v := range raw {
+		if !picked[k] {
+			entry.Fields = append(entry.Fields, LogField{Key: k, Value: jsonFieldValue(v)})
+		}
+	}
+	sort.Slice(entry.Fields, func(i, j int) bool { return entry.Fields[i].Key < entry.Fields[j].Key })
+	return entry, true
+}
+
+// jsonFieldValue formats a decoded JSON value for display.
+func jsonFieldValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return "null"
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// IsError reports whether the line was logged at error level or above.
+func (e *JSONLog) IsError() bool {
+	switch e.Level {
+	case "error", "err", "fatal", "panic", "dpanic", "critical", "crit", "alert", "emergency", "emerg":
+		return true
+	}
+	return false
+}
+
+// Field returns the value of a field by key. The level, time and message
+// are found under any of their usual keys, so "level" also matches a
+// line that logs "severity".
+func (e *JSONLog) Field(key string) (string, bool) {
+	switch {
+	case containsString(jsonLevelKeys, key):
+		return e.Level, e.Level != ""
+	case containsString(jsonTimeKeys, key):
+		return e.Time, e.Time != ""
+	case containsString(jsonMessageKeys, key):
+		return e.Message, e.Message != ""
+	}
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// FieldsText returns the remaining fields as "key=value" pairs separated
+// by spaces, quoting values that are empty or contain spaces.
+func (e *JSONLog) FieldsText() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Key+"="+quoteFieldValue(f.Value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteFieldValue quotes v when it is empty or has spaces, which would
+// make it run into the next pair.
+func quoteFieldValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t") {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// LogFieldQuery is a search on one field of JSON log lines, like
+// level=error or status>=500.
+type LogFieldQuery struct {
+	Key   string
+	Op    string // "=", "!=", ">", ">=", "<" or "<="
+	Value string
+}
+
+var logFieldQueryRegexp = regexp.MustCompile(`^([A-Za-z_@][\w.@-]*)\s*(!=|>=|<=|=|>|<)\s*(.*)$`)
+
+// ParseLogFieldQuery parses a field query. Returns false when query is
+// plain text to search for.
+func ParseLogFieldQuery(query string) (LogFieldQuery, bool) {
+	m := logFieldQueryRegexp.FindStringSubmatch(strings.TrimSpace(query))
+	if m == nil || m[3] == "" {
+		return LogFieldQuery{}, false
+	}
+	return LogFieldQuery{Key: m[1], Op: m[2], Value: strings.Trim(m[3], `"`)}, true
+}
+
+// Match reports whether the line's field satisfies the query. = and !=
+// compare case-insensitively; the other operators compare numbers, and
+// never match a field that isn't one.
+func (q LogFieldQuery) Match(entry *JSONLog) bool {
+	value, ok := entry.Field(q.Key)
+	switch q.Op {
+	case "=":
+		return ok && strings.EqualFold(value, q.Value)
+	case "!=":
+		return !ok || !strings.EqualFold(value, q.Value)
+	}
+	if !ok {
+		return false
+	}
+	got, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	want, err := strconv.ParseFloat(q.Value, 64)
+	if err != nil {
+		return false
+	}
+	switch q.Op {
+	case ">":
+		return got > want
+	case ">=":
+		return got >= want
+	case "<":
+		return got < want
+	case "<=":
+		return got <= want
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package repository
+
+import "testing"
+
+func TestParseJSONLog(t *testing.T) {
+	entry, ok := ParseJSONLog(`{"level":"ERROR","ts":"2024-01-01T12:00:00Z","msg":"request failed","status":502,"path":"/api/v1","user":{"id":7},"retry":true}`)
+	if !ok {
+		t.Fatal("ParseJSONLog() should parse a JSON object")
+	}
+	if entry.Level != "error" || entry.Time != "2024-01-01T12:00:00Z" || entry.Message != "request failed" {
+		t.Errorf("ParseJSONLog() = %+v", entry)
+	}
+	if got, want := entry.FieldsText(), `path=/api/v1 retry=true status=502 user={"id":7}`; got != want {
+		t.Errorf("FieldsText() = %q, want %q", got, want)
+	}
+	if !entry.IsError() {
+		t.Error("IsError() should be true for level error")
+	}
+
+	// pino/bunyan numeric levels and alternate keys
+	entry, ok = ParseJSONLog(`{"level":60,"time":1704110400000,"message":"out of memory"}`)
+	if !ok || entry.Level != "fatal" || entry.Message != "out of memory" || entry.Time != "1704110400000" {
+		t.Errorf("ParseJSONLog() = %+v", entry)
+	}
+
+	for _, content := range []string{
+		"INFO: starting server",
+		`["not", "an", "object"]`,
+		`{"truncated": `,
+		"",
+	} {
+		if _, ok := ParseJSONLog(content); ok {
+			t.Errorf("ParseJSONLog(%q) should not parse", content)
+		}
+	}
+}
+
+func TestJSONLog_FieldsTextQuoting(t *testing.T) {
+	entry, _ := ParseJSONLog(`{"msg":"x","err":"connection refused","empty":""}`)
+	if got, want := entry.FieldsText(), `empty="" err="connection refused"`; got != want {
+		t.Errorf("FieldsText() = %q, want %q", got, want)
+	}
+}
+
+func TestIsErrorLine_JSON(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{`{"level":"info","msg":"retrying","error":null}`, false},
+		{`{"severity":"CRITICAL","message":"disk full"}`, true},
+		{`{"level":50,"msg":"boom"}`, true},
+		{`{"level":"fatal","msg":"cannot start"}`, true},
+		{`{"msg":"request failed"}`, true}, // No level: keywords decide
+	}
+	for _, tt := range tests {
+		if got := isErrorLine(tt.content); got != tt.want {
+			t.Errorf("isErrorLine(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestLogFieldQuery(t *testing.T) {
+	entry, _ := ParseJSONLog(`{"severity":"Error","msg":"upstream timeout","status":504,"path":"/api"}`)
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"level=error", true},
+		{"severity=ERROR", true},
+		{"level=info", false},
+		{"level!=info", true},
+		{"status>=500", true},
+		{"status >= 500", true},
+		{"status<500", false},
+		{"status>504", false},
+		{"status<=504", true},
+		{"path=/api", true},
+		{`msg="upstream timeout"`, true},
+		{"path>=5", false},   // Not a number
+		{"missing=x", false}, // No such field
+		{"missing!=x", true},
+	}
+	for _, tt := range tests {
+		q, ok := ParseLogFieldQuery(tt.query)
+		if !ok {
+			t.Errorf("ParseLogFieldQuery(%q) should parse", tt.query)
+			continue
+		}
+		if got := q.Match(entry); got != tt.want {
+			t.Errorf("%q.Match() = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	for _, query := range []string{"timeout", "a = ", "=500", "connection refused"} {
+		if _, ok := ParseLogFieldQuery(query); ok {
+			t.Errorf("ParseLogFieldQuery(%q) should be plain text", query)
+		}
+	}
+}
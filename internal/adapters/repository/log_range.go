@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LogTimeRange is a window of time to fetch logs for. A zero Until leaves
+// the range open up to now.
+type LogTimeRange struct {
+	Since time.Time
+	Until time.Time
+}
+
+// clockLayouts are the wall clock formats ParseLogTimeRange accepts,
+// read on the day of now.
+var clockLayouts = []string{"15:04:05", "15:04"}
+
+// LogTimeRangeAround returns the range of windowMinutes before and after
+// target, as GetLogsAroundTime uses.
+func LogTimeRangeAround(target time.Time, windowMinutes int) LogTimeRange {
+	window := time.Duration(windowMinutes) * time.Minute
+	return LogTimeRange{Since: target.Add(-window), Until: target.Add(window)}
+}
+
+// ParseLogTimeRange parses a range typed by the user: two times separated
+// by "-" for wall clock times ("10:42-10:55", "10:42:05-10:43"), or by
+// "/", ".." or spaces for RFC3339 times, which contain "-" themselves.
+// Wall clock times are read in now's location on now's date; a start later
+// than now is taken to be yesterday, and an end before the start the next
+// day, so a range may cross midnight. Leaving out the end ("10:42-" or just
+// "10:42") keeps the range open up to now.
+func ParseLogTimeRange(s string, now time.Time) (LogTimeRange, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return LogTimeRange{}, fmt.Errorf("empty time range")
+	}
+
+	start, end := splitLogTimeRange(s)
+	since, clock, err := parseRangeTime(start, now)
+	if err != nil {
+		return LogTimeRange{}, err
+	}
+	if clock && since.After(now) {
+		since = since.AddDate(0, 0, -1)
+	}
+	r := LogTimeRange{Since: since}
+	if end == "" {
+		return r, nil
+	}
+
+	until, clock, err := parseRangeTime(end, since)
+	if err != nil {
+		return LogTimeRange{}, err
+	}
+	if clock && until.Before(since) {
+		until = until.AddDate(0, 0, 1)
+	}
+	if !until.After(since) {
+		return LogTimeRange{}, fmt.Errorf("range ends before it starts: %s", s)
+	}
+	r.Until = until
+	return r, nil
+}
+
+// splitLogTimeRange splits a range into its start and end, the end empty
+// when there is none.
+func splitLogTimeRange(s string) (string, string) {
+	for _, sep := range []string{"/", ".."} {
+		if start, end, ok := strings.Cut(s, sep); ok {
+			return strings.TrimSpace(start), strings.TrimSpace(end)
+		}
+	}
+	if fields := strings.Fields(s); len(fields) == 2 {
+		return fields[0], fields[1]
+	} else if len(fields) == 3 && fields[1] == "-" {
+		return fields[0], fields[2]
+	}
+	// A "-" only separates wall clock times, which have none of their own
+	if !strings.Contains(s, "T") {
+		if start, end, ok := strings.Cut(s, "-"); ok {
+			return strings.TrimSpace(start), strings.TrimSpace(end)
+		}
+	}
+	return s, ""
+}
+
+// parseRangeTime parses an RFC3339 time, or a wall clock time on day's
+// date in day's location, reporting which of the two it was.
+func parseRangeTime(s string, day time.Time) (time.Time, bool, error) {
+	for _, layout := range []string{time.RFC3339Nano, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, false, nil
+		}
+	}
+	for _, layout := range clockLayouts {
+		if t, err := time.ParseInLocation(layout, s, day.Location()); err == nil {
+			y, mo, d := day.Date()
+			return time.Date(y, mo, d, t.Hour(), t.Minute(), t.Second(), 0, day.Location()), true, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("invalid time %q: use HH:MM, HH:MM:SS or RFC3339", s)
+}
+
+// IsZero reports whether the range is unset.
+func (r LogTimeRange) IsZero() bool {
+	return r.Since.IsZero() && r.Until.IsZero()
+}
+
+// Contains reports whether t falls within the range, bounds included.
+func (r LogTimeRange) Contains(t time.Time) bool {
+	return !t.Before(r.Since) && (r.Until.IsZero() || !t.After(r.Until))
+}
+
+// String returns the range as wall clock times in local time, e.g.
+// "10:42:00–10:55:00", with the date when it isn't today.
+func (r LogTimeRange) String() string {
+	until := "now"
+	if !r.Until.IsZero() {
+		until = formatRangeTime(r.Until)
+	}
+	return formatRangeTime(r.Since) + "–" + until
+}
+
+func formatRangeTime(t time.Time) string {
+	t = t.Local()
+	if t.Format("2006-01-02") != time.Now().Format("2006-01-02") {
+		return t.Format("Jan 2 15:04:05")
+	}
+	return t.Format("15:04:05")
+}
+
+// Options returns opts limited to the range.
+func (r LogTimeRange) Options(opts LogOptions) LogOptions {
+	opts.SinceTime = r.Since
+	opts.UntilTime = r.Until
+	return opts
+}
+
+// FilterLogsByTimeRange returns the log lines written within r. Lines
+// without a timestamp can't be placed and are dropped, unless the range is
+// unset.
+func FilterLogsByTimeRange(logs []LogLine, r LogTimeRange) []LogLine {
+	if r.IsZero() {
+		return logs
+	}
+	var result []LogLine
+	for _, log := range logs {
+		if !log.Timestamp.IsZero() && r.Contains(log.Timestamp) {
+			result = append(result, log)
+		}
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLogTimeRange(t *testing.T) {
+	loc := time.FixedZone("test", 2*60*60)
+	now := time.Date(2024, 3, 10, 11, 30, 0, 0, loc)
+	at := func(day, hour, min, sec int) time.Time {
+		return time.Date(2024, 3, day, hour, min, sec, 0, loc)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  LogTimeRange
+	}{
+		{"clock range", "10:42-10:55", LogTimeRange{Since: at(10, 10, 42, 0), Until: at(10, 10, 55, 0)}},
+		{"clock range with seconds and spaces", " 10:42:05 - 10:43 ", LogTimeRange{Since: at(10, 10, 42, 5), Until: at(10, 10, 43, 0)}},
+		{"open end", "10:42-", LogTimeRange{Since: at(10, 10, 42, 0)}},
+		{"start only", "10:42", LogTimeRange{Since: at(10, 10, 42, 0)}},
+		{"start later than now is yesterday", "23:50-00:10", LogTimeRange{Since: at(9, 23, 50, 0), Until: at(10, 0, 10, 0)}},
+		{
+			"RFC3339 pair",
+			"2024-03-09T08:00:00Z/2024-03-09T08:05:00Z",
+			LogTimeRange{Since: time.Date(2024, 3, 9, 8, 0, 0, 0, time.UTC), Until: time.Date(2024, 3, 9, 8, 5, 0, 0, time.UTC)},
+		},
+		{
+			"RFC3339 pair with offsets and spaces",
+			"2024-03-09T08:00:00-05:00 2024-03-09T08:05:00-05:00",
+			LogTimeRange{Since: time.Date(2024, 3, 9, 13, 0, 0, 0, time.UTC), Until: time.Date(2024, 3, 9, 13, 5, 0, 0, time.UTC)},
+		},
+		{
+			"RFC3339 start only",
+			"2024-03-09T08:00:00Z",
+			LogTimeRange{Since: time.Date(2024, 3, 9, 8, 0, 0, 0, time.UTC)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLogTimeRange(tt.input, now)
+			if err != nil {
+				t.Fatalf("ParseLogTimeRange(%q) error = %v", tt.input, err)
+			}
+			if !got.Since.Equal(tt.want.Since) || !got.Until.Equal(tt.want.Until) {
+				t.Errorf("ParseLogTimeRange(%q) = %v..%v, want %v..%v", tt.input, got.Since, got.Until, tt.want.Since, tt.want.Until)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "yesterday", "10:42-later", "2024-03-09T08:05:00Z/2024-03-09T08:00:00Z"} {
+		if _, err := ParseLogTimeRange(input, now); err == nil {
+			t.Errorf("ParseLogTimeRange(%q) should fail", input)
+		}
+	}
+}
+
+func TestLogTimeRange_ContainsAndFilter(t *testing.T) {
+	base := time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+	r := LogTimeRangeAround(base, 2)
+	if !r.Since.Equal(base.Add(-2*time.Minute)) || !r.Until.Equal(base.Add(2*time.Minute)) {
+		t.Fatalf("LogTimeRangeAround() = %v..%v", r.Since, r.Until)
+	}
+	if !r.Contains(r.Since) || !r.Contains(r.Until) || r.Contains(base.Add(3*time.Minute)) {
+		t.Error("Contains() should include both bounds and nothing past them")
+	}
+	if open := (LogTimeRange{Since: base}); !open.Contains(base.Add(24 * time.Hour)) {
+		t.Error("an open range should contain any later time")
+	}
+
+	logs := []LogLine{
+		{Content: "before", Timestamp: base.Add(-5 * time.Minute)},
+		{Content: "inside", Timestamp: base.Add(time.Minute)},
+		{Content: "no timestamp"},
+		{Content: "after", Timestamp: base.Add(5 * time.Minute)},
+	}
+	got := FilterLogsByTimeRange(logs, r)
+	if len(got) != 1 || got[0].Content != "inside" {
+		t.Errorf("FilterLogsByTimeRange() = %+v, want only the line inside", got)
+	}
+	if got := FilterLogsByTimeRange(logs, LogTimeRange{}); len(got) != len(logs) {
+		t.Errorf("FilterLogsByTimeRange() with no range returned %d lines, want %d", len(got), len(logs))
+	}
+}
+
+func TestParseLogStreamUntil(t *testing.T) {
+	input := strings.Join([]string{
+		"2024-03-10T10:00:00Z request 1 handled",
+		"2024-03-10T10:01:00Z request 2 handled",
+		"  continued stack frame",
+		"2024-03-10T10:02:00Z request 3 handled",
+		"2024-03-10T10:03:00Z request 4 handled",
+		"2024-03-10T10:04:00Z request 5 handled",
+	}, "\n")
+	until := time.Date(2024, 3, 10, 10, 2, 0, 0, time.UTC)
+
+	lines, err := parseLogStreamUntil(strings.NewReader(input), "app", until, 0)
+	if err != nil {
+		t.Fatalf("parseLogStreamUntil() error = %v", err)
+	}
+	if len(lines) != 4 || lines[3].Content != "request 3 handled" {
+		t.Fatalf("parseLogStreamUntil() = %+v, want the lines up to request 3", lines)
+	}
+	if lines[2].Container != "app" {
+		t.Errorf("Container = %q, want app", lines[2].Container)
+	}
+
+	lines, _ = parseLogStreamUntil(strings.NewReader(input), "app", until, 2)
+	if len(lines) != 2 || lines[0].Content != "  continued stack frame" || lines[1].Content != "request 3 handled" {
+		t.Errorf("parseLogStreamUntil() with tail 2 = %+v, want the last 2 lines up to request 3", lines)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -18,9 +20,11 @@ import (
 // plus a flag indicating if the line appears to contain an error.
 type LogLine struct {
 	Timestamp time.Time // Parsed timestamp from the log line
+	Pod       string    // Name of the pod that produced this log
 	Container string    // Name of the container that produced this log
 	Content   string    // The actual log message content
 	IsError   bool      // True if the line contains error-related keywords
+	Previous  bool      // Only in the previous container instance (set by CompareLogs)
 }
 
 // LogOptions configures how container logs are retrieved.
@@ -28,8 +32,10 @@ type LogOptions struct {
 	Container  string        // Specific container name (empty for default)
 	TailLines  int64         // Number of lines to fetch from the end
 	Since      time.Duration // Only return logs newer than this duration
+	SinceTime  time.Time     // Only return logs written at or after this time; overrides Since
+	UntilTime  time.Time     // Drop logs written after this time (see GetPodLogs)
 	Previous   bool          // Fetch logs from the previous container instance
-	Follow     bool          // Stream logs in real-time (not implemented in batch mode)
+	Follow     bool          // Stream logs in real-time (see StreamPodLogs; ignored by GetPodLogs)
 	Timestamps bool          // Include timestamps in log output
 }
 
@@ -44,18 +50,26 @@ func DefaultLogOptions() LogOptions {
 
 // GetPodLogs retrieves container logs for a specific pod.
 // It returns parsed log lines with timestamps and error detection.
+//
+// The API has no end time, so with UntilTime the log is read up to the
+// first line written after it, which needs timestamps: they are requested
+// even when opts.Timestamps is false. TailLines then keeps the last lines
+// up to UntilTime rather than the last lines of the log.
 func GetPodLogs(ctx context.Context, clientset kubernetes.Interface, namespace, podName string, opts LogOptions) ([]LogLine, error) {
+	bounded := !opts.UntilTime.IsZero()
 	podLogOpts := &corev1.PodLogOptions{
 		Container:  opts.Container,
 		Previous:   opts.Previous,
-		Timestamps: opts.Timestamps,
+		Timestamps: opts.Timestamps || bounded,
 	}
 
-	if opts.TailLines > 0 {
+	if opts.TailLines > 0 && !bounded {
 		podLogOpts.TailLines = &opts.TailLines
 	}
 
-	if opts.Since > 0 {
+	if !opts.SinceTime.IsZero() {
+		podLogOpts.SinceTime = &metav1.Time{Time: opts.SinceTime}
+	} else if opts.Since > 0 {
 		//coverage:ignore
 		sinceSeconds := int64(opts.Since.Seconds())
 		podLogOpts.SinceSeconds = &sinceSeconds
@@ -69,7 +83,16 @@ func GetPodLogs(ctx context.Context, clientset kubernetes.Interface, namespace,
 	}
 	defer stream.Close() //coverage:ignore
 
-	return parseLogStream(stream, opts.Container, opts.Timestamps) //coverage:ignore
+	var lines []LogLine
+	if bounded {
+		lines, err = parseLogStreamUntil(stream, opts.Container, opts.UntilTime, opts.TailLines)
+	} else {
+		lines, err = parseLogStream(stream, opts.Container, opts.Timestamps) //coverage:ignore
+	}
+	for i := range lines {
+		lines[i].Pod = podName
+	}
+	return lines, err
 }
 
 // parseLogStream reads log lines from a stream and parses them into LogLine structs.
@@ -83,33 +106,194 @@ func parseLogStream(reader io.Reader, container string, hasTimestamps bool) ([]L
 	scanner.Buffer(buf, 1024*1024)
 
 	for scanner.Scan() {
-		line := scanner.Text()
-		logLine := LogLine{
-			Container: container,
-			Content:   line,
-		}
-
-		// Parse timestamp if present (format: 2006-01-02T15:04:05.999999999Z)
-		if hasTimestamps && len(line) > 30 {
-			if ts, err := time.Parse(time.RFC3339Nano, line[:30]); err == nil {
-				logLine.Timestamp = ts
-				logLine.Content = strings.TrimSpace(line[31:])
-			} else if ts, err := time.Parse(time.RFC3339, line[:20]); err == nil {
-				logLine.Timestamp = ts
-				logLine.Content = strings.TrimSpace(line[21:])
+		lines = append(lines, parseLogLine(scanner.Text(), container, hasTimestamps))
+	}
+
+	return lines, scanner.Err()
+}
+
+// parseLogStreamUntil reads timestamped log lines up to the first one
+// written after until, keeping the last tail lines (all when tail is 0).
+// Lines without a timestamp belong to the line before them and are kept
+// with it.
+func parseLogStreamUntil(reader io.Reader, container string, until time.Time, tail int64) ([]LogLine, error) {
+	var lines []LogLine
+	scanner := bufio.NewScanner(reader)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 1024*1024)
+
+	for scanner.Scan() {
+		line := parseLogLine(scanner.Text(), container, true)
+		// A container writes its lines in order, so the rest is later still
+		if line.Timestamp.After(until) {
+			break
+		}
+		lines = append(lines, line)
+		if tail > 0 && int64(len(lines)) > 2*tail {
+			lines = append(lines[:0], lines[int64(len(lines))-tail:]...)
+		}
+	}
+	if tail > 0 && int64(len(lines)) > tail {
+		lines = lines[int64(len(lines))-tail:]
+	}
+	return lines, scanner.Err()
+}
+
+// parseLogLine parses a single raw log line, splitting off the timestamp
+// when present (format: 2006-01-02T15:04:05.999999999Z).
+func parseLogLine(line, container string, hasTimestamps bool) LogLine {
+	logLine := LogLine{
+		Container: container,
+		Content:   line,
+	}
+
+	if hasTimestamps && len(line) > 30 {
+		if ts, err := time.Parse(time.RFC3339Nano, line[:30]); err == nil {
+			logLine.Timestamp = ts
+			logLine.Content = strings.TrimSpace(line[31:])
+		} else if ts, err := time.Parse(time.RFC3339, line[:20]); err == nil {
+			logLine.Timestamp = ts
+			logLine.Content = strings.TrimSpace(line[21:])
+		}
+	}
+
+	logLine.IsError = isErrorLine(logLine.Content)
+	return logLine
+}
+
+// streamBackoffMin and streamBackoffMax bound the delay before a log
+// stream reconnects. They are variables so tests can shorten them.
+var (
+	streamBackoffMin = 500 * time.Millisecond
+	streamBackoffMax = 10 * time.Second
+)
+
+// StreamPodLogs follows a pod's logs as they are written. opts.Container
+// selects one container; empty follows every container of the pod and
+// merges their lines as they arrive. opts.TailLines lines of history are
+// sent first.
+//
+// When a stream ends (the container restarted or the connection dropped)
+// it reconnects with exponential backoff, resuming after the last line
+// seen. Lines are sent until ctx is done or cancel is called; the channel
+// is closed once every stream has stopped.
+func StreamPodLogs(ctx context.Context, clientset kubernetes.Interface, namespace, podName string, opts LogOptions) (<-chan LogLine, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	lines := make(chan LogLine, 256)
+
+	go func() {
+		defer close(lines)
+
+		containers := []string{opts.Container}
+		if opts.Container == "" {
+			pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+			if err != nil {
+				if ctx.Err() == nil {
+					lines <- LogLine{Content: fmt.Sprintf("failed to stream logs: %v", err), IsError: true}
+				}
+				return
+			}
+			containers = containers[:0]
+			for _, c := range pod.Spec.Containers {
+				containers = append(containers, c.Name)
 			}
 		}
 
-		logLine.IsError = isErrorLine(logLine.Content)
-		lines = append(lines, logLine)
+		var wg sync.WaitGroup
+		for _, container := range containers {
+			wg.Add(1)
+			go func(container string) {
+				defer wg.Done()
+				followContainerLogs(ctx, clientset, namespace, podName, container, opts.TailLines, lines)
+			}(container)
+		}
+		wg.Wait()
+	}()
+
+	return lines, cancel
+}
+
+// followContainerLogs streams one container's logs into out until ctx is
+// done, reconnecting with backoff whenever the stream ends.
+func followContainerLogs(ctx context.Context, clientset kubernetes.Interface, namespace, podName, container string, tailLines int64, out chan<- LogLine) {
+	var last time.Time // Timestamp of the last line sent
+	backoff := streamBackoffMin
+	for {
+		podLogOpts := &corev1.PodLogOptions{
+			Container:  container,
+			Follow:     true,
+			Timestamps: true,
+		}
+		if last.IsZero() {
+			if tailLines > 0 {
+				podLogOpts.TailLines = &tailLines
+			}
+			// Resume from here if nothing with a timestamp arrives
+			last = time.Now()
+		} else {
+			since := metav1.NewTime(last)
+			podLogOpts.SinceTime = &since
+		}
+
+		if streamLogsOnce(ctx, clientset, namespace, podName, podLogOpts, &last, out) {
+			backoff = streamBackoffMin
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, streamBackoffMax)
 	}
+}
 
-	return lines, scanner.Err()
+// streamLogsOnce reads a single follow stream until it ends, skipping lines
+// at or before *last (SinceTime only has second precision) and advancing
+// *last. Returns true if any line was sent.
+func streamLogsOnce(ctx context.Context, clientset kubernetes.Interface, namespace, podName string, podLogOpts *corev1.PodLogOptions, last *time.Time, out chan<- LogLine) bool {
+	first := podLogOpts.SinceTime == nil
+	stream, err := clientset.CoreV1().Pods(namespace).GetLogs(podName, podLogOpts).Stream(ctx)
+	if err != nil {
+		return false
+	}
+	defer stream.Close()
+	// Unblock the scanner as soon as the stream is cancelled
+	stop := context.AfterFunc(ctx, func() { stream.Close() })
+	defer stop()
+
+	scanner := bufio.NewScanner(stream)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 1024*1024)
+
+	sent := false
+	for scanner.Scan() {
+		line := parseLogLine(scanner.Text(), podLogOpts.Container, true)
+		line.Pod = podName
+		if !line.Timestamp.IsZero() {
+			if !first && !line.Timestamp.After(*last) {
+				continue
+			}
+			*last = line.Timestamp
+		}
+		select {
+		case out <- line:
+			sent = true
+		case <-ctx.Done():
+			return sent
+		}
+	}
+	return sent
 }
 
 // isErrorLine checks if a log line contains common error indicators.
 // It performs case-insensitive matching against keywords like "error", "fatal", "panic", etc.
+// A JSON line with a level is judged by its level alone, so an info line
+// with an "error":null field is not flagged.
 func isErrorLine(content string) bool {
+	if entry, ok := ParseJSONLog(content); ok && entry.Level != "" {
+		return entry.IsError()
+	}
 	lower := strings.ToLower(content)
 	errorIndicators := []string{
 		"error", "err:", "fatal", "panic", "exception",
@@ -123,29 +307,42 @@ func isErrorLine(content string) bool {
 	return false
 }
 
-// GetAllContainerLogs retrieves logs from all containers in a pod.
+// GetAllContainerLogs retrieves logs from all containers in a pod, and from
+// its init containers while the pod is Pending or stuck initializing.
 // It distributes the tail line limit evenly across containers and merges
 // the results sorted by timestamp.
 func GetAllContainerLogs(ctx context.Context, clientset kubernetes.Interface, namespace, podName string, tailLines int64) ([]LogLine, error) {
+	return GetAllContainerLogsInRange(ctx, clientset, namespace, podName, tailLines, LogTimeRange{})
+}
+
+// GetAllContainerLogsInRange is GetAllContainerLogs limited to the lines
+// written within r; a zero r fetches the latest lines.
+func GetAllContainerLogsInRange(ctx context.Context, clientset kubernetes.Interface, namespace, podName string, tailLines int64, r LogTimeRange) ([]LogLine, error) {
 	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
 	if err != nil {
 		//coverage:ignore
 		return nil, err
 	}
 
+	// While the pod initializes, its init containers hold the useful logs
+	containers := pod.Spec.Containers
+	if pod.Status.Phase == corev1.PodPending || IsInitStatus(getPodStatus(pod)) {
+		containers = append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	}
+
 	var allLogs []LogLine
-	linesPerContainer := tailLines / int64(len(pod.Spec.Containers))
+	linesPerContainer := tailLines / int64(len(containers))
 	if linesPerContainer < 10 {
 		//coverage:ignore
 		linesPerContainer = 10
 	}
 
-	for _, container := range pod.Spec.Containers {
-		opts := LogOptions{
+	for _, container := range containers {
+		opts := r.Options(LogOptions{
 			Container:  container.Name,
 			TailLines:  linesPerContainer,
 			Timestamps: true,
-		}
+		})
 
 		logs, err := GetPodLogs(ctx, clientset, namespace, podName, opts)
 		if err != nil {
@@ -183,6 +380,62 @@ func GetPreviousLogs(ctx context.Context, clientset kubernetes.Interface, namesp
 	return GetPodLogs(ctx, clientset, namespace, podName, opts)
 }
 
+// logDigitsRegexp matches the numbers CompareLogs ignores.
+var logDigitsRegexp = regexp.MustCompile(`[0-9]+`)
+
+// CompareLogs merges the logs of a container's previous instance into its
+// current logs, to show what changed between restarts of a crash looping
+// container. Lines are matched by content with numbers ignored, so
+// timestamps, durations and counters printed by the application do not make
+// otherwise equal lines differ. Matched lines appear once, as the current
+// instance's line. Lines only in the previous instance are kept where they
+// occurred, with Previous set.
+func CompareLogs(previous, current []LogLine) []LogLine {
+	a := make([]string, len(previous))
+	for i, l := range previous {
+		a[i] = logDigitsRegexp.ReplaceAllString(l.Content, "0")
+	}
+	b := make([]string, len(current))
+	for i, l := range current {
+		b[i] = logDigitsRegexp.ReplaceAllString(l.Content, "0")
+	}
+
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	merged := make([]LogLine, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			merged = append(merged, current[j])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			line := previous[i]
+			line.Previous = true
+			merged = append(merged, line)
+			i++
+		default:
+			merged = append(merged, current[j])
+			j++
+		}
+	}
+	return merged
+}
+
 // SearchLogs filters log lines that contain the given query string.
 // The search is case-insensitive. Returns all logs if query is empty.
 func SearchLogs(logs []LogLine, query string) []LogLine {
@@ -213,14 +466,13 @@ func FilterErrorLogs(logs []LogLine) []LogLine {
 
 // GetLogsAroundTime returns log lines within a time window around the target time.
 // Useful for investigating what happened before and after a specific event.
+// See LogTimeRangeAround to fetch the logs of such a window instead.
 func GetLogsAroundTime(logs []LogLine, target time.Time, windowMinutes int) []LogLine {
-	window := time.Duration(windowMinutes) * time.Minute
-	start := target.Add(-window)
-	end := target.Add(window)
+	r := LogTimeRangeAround(target, windowMinutes)
 
 	var result []LogLine
 	for _, log := range logs {
-		if !log.Timestamp.IsZero() && log.Timestamp.After(start) && log.Timestamp.Before(end) {
+		if !log.Timestamp.IsZero() && log.Timestamp.After(r.Since) && log.Timestamp.Before(r.Until) {
 			result = append(result, log)
 		}
 	}
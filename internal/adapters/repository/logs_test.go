@@ -1,9 +1,14 @@
 package repository
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestDefaultLogOptions(t *testing.T) {
@@ -122,6 +127,51 @@ func TestFilterErrorLogs_Empty(t *testing.T) {
 	}
 }
 
+func TestCompareLogs(t *testing.T) {
+	previous := []LogLine{
+		{Content: "starting server v1.2"},
+		{Content: "listening on :8080 after 12ms"},
+		{Content: "connecting to db"},
+		{Content: "panic: nil pointer dereference", IsError: true},
+	}
+	current := []LogLine{
+		{Content: "starting server v1.2"},
+		{Content: "listening on :8080 after 9ms"},
+		{Content: "connecting to cache"},
+	}
+
+	got := CompareLogs(previous, current)
+	want := []struct {
+		content  string
+		previous bool
+	}{
+		{"starting server v1.2", false},
+		{"listening on :8080 after 9ms", false},
+		{"connecting to db", true},
+		{"panic: nil pointer dereference", true},
+		{"connecting to cache", false},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("CompareLogs() returned %d lines, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Content != w.content || got[i].Previous != w.previous {
+			t.Errorf("line %d = %q (previous %v), want %q (previous %v)", i, got[i].Content, got[i].Previous, w.content, w.previous)
+		}
+	}
+	if previous[2].Previous {
+		t.Error("CompareLogs() should not modify its input")
+	}
+}
+
+func TestCompareLogs_NoPrevious(t *testing.T) {
+	current := []LogLine{{Content: "a"}, {Content: "b"}}
+	got := CompareLogs(nil, current)
+	if len(got) != 2 || got[0].Previous || got[1].Previous {
+		t.Errorf("CompareLogs(nil, current) = %+v, want current unchanged", got)
+	}
+}
+
 func TestGetLogsAroundTime(t *testing.T) {
 	now := time.Now()
 	logs := []LogLine{
@@ -376,3 +426,90 @@ func TestLogOptionsStruct(t *testing.T) {
 		t.Error("Timestamps should be true")
 	}
 }
+
+// shortStreamBackoff makes log streams reconnect quickly for the test.
+func shortStreamBackoff(t *testing.T) {
+	minBackoff, maxBackoff := streamBackoffMin, streamBackoffMax
+	streamBackoffMin, streamBackoffMax = time.Millisecond, 5*time.Millisecond
+	t.Cleanup(func() { streamBackoffMin, streamBackoffMax = minBackoff, maxBackoff })
+}
+
+// waitClosed fails the test unless lines is closed within a second.
+func waitClosed(t *testing.T, lines <-chan LogLine) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-lines:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("log stream did not stop")
+		}
+	}
+}
+
+func TestStreamPodLogs_AllContainers(t *testing.T) {
+	shortStreamBackoff(t)
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "app"},
+			{Name: "sidecar"},
+		}},
+	})
+
+	lines, cancel := StreamPodLogs(context.Background(), clientset, "default", "web", LogOptions{TailLines: 10})
+
+	// The fake API serves "fake logs" and ends the stream, so a second
+	// line per container means the stream reconnected
+	seen := make(map[string]int)
+	timeout := time.After(5 * time.Second)
+	for seen["app"] < 2 || seen["sidecar"] < 2 {
+		select {
+		case line := <-lines:
+			if line.Content != "fake logs" {
+				t.Errorf("unexpected line %+v", line)
+			}
+			seen[line.Container]++
+		case <-timeout:
+			t.Fatalf("timed out waiting for reconnects, saw %v", seen)
+		}
+	}
+
+	cancel()
+	waitClosed(t, lines)
+}
+
+func TestStreamPodLogs_SingleContainerParentCancel(t *testing.T) {
+	shortStreamBackoff(t)
+	clientset := fake.NewSimpleClientset()
+	ctx, cancelParent := context.WithCancel(context.Background())
+	lines, cancel := StreamPodLogs(ctx, clientset, "default", "web", LogOptions{Container: "app"})
+	defer cancel()
+
+	select {
+	case line := <-lines:
+		if line.Container != "app" {
+			t.Errorf("Container = %q, want app", line.Container)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a line")
+	}
+
+	cancelParent()
+	waitClosed(t, lines)
+}
+
+func TestStreamPodLogs_PodNotFound(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	lines, cancel := StreamPodLogs(context.Background(), clientset, "default", "missing", LogOptions{})
+	defer cancel()
+
+	line, ok := <-lines
+	if !ok || !line.IsError || !strings.Contains(line.Content, "failed to stream logs") {
+		t.Errorf("first line = %+v, %v; want a stream error", line, ok)
+	}
+	waitClosed(t, lines)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
@@ -19,6 +20,7 @@ type MetricsClientInterface interface {
 type PodMetrics struct {
 	Name       string             // Pod name
 	Namespace  string             // Pod namespace
+	Timestamp  time.Time          // When metrics-server took the sample
 	Containers []ContainerMetrics // Per-container resource usage
 }
 
@@ -27,6 +29,8 @@ type ContainerMetrics struct {
 	Name        string  // Container name
 	CPUUsage    string  // Formatted CPU usage (e.g., "100m", "1.5")
 	MemoryUsage string  // Formatted memory usage (e.g., "128Mi", "1.2Gi")
+	CPUMilli    int64   // CPU usage in millicores
+	MemoryBytes int64   // Memory usage in bytes
 	CPUPercent  float64 // CPU usage as percentage of limit (if set)
 	MemPercent  float64 // Memory usage as percentage of limit (if set)
 }
@@ -46,6 +50,7 @@ func GetPodMetrics(ctx context.Context, metricsClient MetricsClientInterface, na
 	pm := &PodMetrics{
 		Name:      metrics.Name,
 		Namespace: metrics.Namespace,
+		Timestamp: metrics.Timestamp.Time,
 	}
 
 	for _, c := range metrics.Containers {
@@ -56,6 +61,8 @@ func GetPodMetrics(ctx context.Context, metricsClient MetricsClientInterface, na
 			Name:        c.Name,
 			CPUUsage:    formatCPU(cpu.MilliValue()),
 			MemoryUsage: formatMemory(mem.Value()),
+			CPUMilli:    cpu.MilliValue(),
+			MemoryBytes: mem.Value(),
 		})
 	}
 
@@ -80,6 +87,7 @@ func GetNamespaceMetrics(ctx context.Context, metricsClient MetricsClientInterfa
 		pm := PodMetrics{
 			Name:      m.Name,
 			Namespace: m.Namespace,
+			Timestamp: m.Timestamp.Time,
 		}
 
 		for _, c := range m.Containers {
@@ -90,6 +98,8 @@ func GetNamespaceMetrics(ctx context.Context, metricsClient MetricsClientInterfa
 				Name:        c.Name,
 				CPUUsage:    formatCPU(cpu.MilliValue()),
 				MemoryUsage: formatMemory(mem.Value()),
+				CPUMilli:    cpu.MilliValue(),
+				MemoryBytes: mem.Value(),
 			})
 		}
 		result = append(result, pm)
@@ -129,6 +139,18 @@ func formatMemory(bytes int64) string {
 	}
 }
 
+// CPUMillis parses a CPU quantity such as a limit ("500m", "2") into
+// millicores. Returns 0 when it is unset or invalid.
+func CPUMillis(quantity string) int64 {
+	return parseMilli(quantity)
+}
+
+// MemoryBytes parses a memory quantity such as a limit ("512Mi") into
+// bytes. Returns 0 when it is unset or invalid.
+func MemoryBytes(quantity string) int64 {
+	return parseBytes(quantity)
+}
+
 // ResourceUsageSummary provides an aggregated view of pod resource usage.
 // Includes flags for resource pressure conditions.
 type ResourceUsageSummary struct {
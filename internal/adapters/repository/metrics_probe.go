@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+)
+
+// MetricsServerInstallCommand installs the latest metrics-server release.
+const MetricsServerInstallCommand = "kubectl apply -f https://github.com/kubernetes-sigs/metrics-server/releases/latest/download/components.yaml"
+
+// metricsGroupVersion is the API metrics-server serves.
+const metricsGroupVersion = "metrics.k8s.io/v1beta1"
+
+// MetricsState is whether pod metrics can be read from the cluster.
+type MetricsState int
+
+const (
+	MetricsUnknown      MetricsState = iota // Not probed yet
+	MetricsAvailable                        // The metrics API answers
+	MetricsNotInstalled                     // metrics.k8s.io is not served: metrics-server is not installed
+	MetricsFailing                          // metrics.k8s.io is registered but its requests fail
+)
+
+// MetricsStatus is the result of probing the metrics API.
+type MetricsStatus struct {
+	State MetricsState
+	Err   error // Why requests fail, for MetricsFailing
+}
+
+// ProbeMetrics checks that metrics-server serves pod metrics: the
+// metrics.k8s.io group is discovered and the pod metrics of namespace can
+// be listed. A group that is registered but fails, as while metrics-server
+// is starting or its APIService is broken, is MetricsFailing.
+func ProbeMetrics(ctx context.Context, disc discovery.DiscoveryInterface, metricsClient MetricsClientInterface, namespace string) MetricsStatus {
+	_, err := disc.ServerResourcesForGroupVersion(metricsGroupVersion)
+	switch {
+	case apierrors.IsNotFound(err):
+		return MetricsStatus{State: MetricsNotInstalled}
+	case err != nil:
+		return MetricsStatus{State: MetricsFailing, Err: err}
+	case metricsClient == nil:
+		return MetricsStatus{State: MetricsFailing, Err: errors.New("no metrics client")}
+	}
+	_, err = metricsClient.MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return MetricsStatus{State: MetricsFailing, Err: err}
+	}
+	return MetricsStatus{State: MetricsAvailable}
+}
+
+// metricsProbe keeps the last MetricsStatus of a Client, probed in the
+// background while the UI reads it.
+type metricsProbe struct {
+	mu     sync.Mutex
+	status MetricsStatus
+}
+
+// ProbeMetrics probes the metrics API in namespace and keeps the result
+// for MetricsStatus.
+func (c *Client) ProbeMetrics(ctx context.Context, namespace string) MetricsStatus {
+	status := ProbeMetrics(ctx, c.clientset.Discovery(), c.metricsClient, namespace)
+	if c.metricsProbe != nil {
+		c.metricsProbe.mu.Lock()
+		c.metricsProbe.status = status
+		c.metricsProbe.mu.Unlock()
+	}
+	return status
+}
+
+// MetricsStatus returns the result of the last ProbeMetrics, MetricsUnknown
+// before the first.
+func (c *Client) MetricsStatus() MetricsStatus {
+	if c.metricsProbe == nil {
+		return MetricsStatus{}
+	}
+	c.metricsProbe.mu.Lock()
+	defer c.metricsProbe.mu.Unlock()
+	return c.metricsProbe.status
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+// failingDiscovery is a discovery client whose group version lookups fail,
+// as when an aggregated API is registered but its backend is down.
+type failingDiscovery struct {
+	*fakediscovery.FakeDiscovery
+	err error
+}
+
+func (d failingDiscovery) ServerResourcesForGroupVersion(string) (*metav1.APIResourceList, error) {
+	return nil, d.err
+}
+
+func metricsDiscovery() *fakediscovery.FakeDiscovery {
+	clientset := fake.NewSimpleClientset()
+	disc := clientset.Discovery().(*fakediscovery.FakeDiscovery)
+	disc.Resources = []*metav1.APIResourceList{{
+		GroupVersion: metricsGroupVersion,
+		APIResources: []metav1.APIResource{{Name: "pods", Namespaced: true, Kind: "PodMetrics"}},
+	}}
+	return disc
+}
+
+func TestProbeMetrics(t *testing.T) {
+	ctx := context.Background()
+	unavailable := apierrors.NewServiceUnavailable("the server is currently unable to handle the request")
+
+	t.Run("missing API group", func(t *testing.T) {
+		disc := fake.NewSimpleClientset().Discovery()
+		status := ProbeMetrics(ctx, disc, metricsfake.NewSimpleClientset(), "default")
+		if status.State != MetricsNotInstalled || status.Err != nil {
+			t.Errorf("ProbeMetrics() = %+v, want MetricsNotInstalled", status)
+		}
+	})
+
+	t.Run("discovery of the group fails", func(t *testing.T) {
+		disc := failingDiscovery{FakeDiscovery: metricsDiscovery(), err: unavailable}
+		status := ProbeMetrics(ctx, disc, metricsfake.NewSimpleClientset(), "default")
+		if status.State != MetricsFailing || !apierrors.IsServiceUnavailable(status.Err) {
+			t.Errorf("ProbeMetrics() = %+v, want MetricsFailing with the discovery error", status)
+		}
+	})
+
+	t.Run("group present but the API errors", func(t *testing.T) {
+		metricsClient := metricsfake.NewSimpleClientset()
+		metricsClient.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, unavailable
+		})
+		status := ProbeMetrics(ctx, metricsDiscovery(), metricsClient, "default")
+		if status.State != MetricsFailing || !apierrors.IsServiceUnavailable(status.Err) {
+			t.Errorf("ProbeMetrics() = %+v, want MetricsFailing with the list error", status)
+		}
+	})
+
+	t.Run("no metrics client", func(t *testing.T) {
+		status := ProbeMetrics(ctx, metricsDiscovery(), nil, "default")
+		if status.State != MetricsFailing || status.Err == nil {
+			t.Errorf("ProbeMetrics() = %+v, want MetricsFailing", status)
+		}
+	})
+
+	t.Run("available", func(t *testing.T) {
+		metricsClient := metricsfake.NewSimpleClientset()
+		var listed string
+		metricsClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			listed = action.GetNamespace()
+			return false, nil, nil
+		})
+		status := ProbeMetrics(ctx, metricsDiscovery(), metricsClient, "web")
+		if status.State != MetricsAvailable || status.Err != nil {
+			t.Errorf("ProbeMetrics() = %+v, want MetricsAvailable", status)
+		}
+		if listed != "web" {
+			t.Errorf("listed pod metrics in %q, want web", listed)
+		}
+	})
+}
+
+func TestClient_ProbeMetrics(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset(), metricsProbe: &metricsProbe{}}
+	if got := client.MetricsStatus(); got.State != MetricsUnknown {
+		t.Errorf("MetricsStatus() before probing = %+v, want MetricsUnknown", got)
+	}
+
+	// The fake clientset serves no metrics.k8s.io group
+	if got := client.ProbeMetrics(context.Background(), "default"); got.State != MetricsNotInstalled {
+		t.Errorf("ProbeMetrics() = %+v, want MetricsNotInstalled", got)
+	}
+	if got := client.MetricsStatus(); got.State != MetricsNotInstalled {
+		t.Errorf("MetricsStatus() = %+v, want the probed MetricsNotInstalled", got)
+	}
+
+	// Installing metrics-server mid-session is picked up by the next probe;
+	// this client has no metrics client to list with, so it gets no further
+	client.clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{GroupVersion: metricsGroupVersion},
+	}
+	if got := client.ProbeMetrics(context.Background(), "default"); got.State != MetricsFailing {
+		t.Errorf("ProbeMetrics() = %+v, want the group found", got)
+	}
+}
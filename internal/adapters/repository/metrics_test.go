@@ -57,6 +57,24 @@ func TestGetPodMetrics(t *testing.T) {
 	if metrics.Containers[0].Name != "main" {
 		t.Errorf("Container name = %q, want 'main'", metrics.Containers[0].Name)
 	}
+	if c := metrics.Containers[0]; c.CPUMilli != 100 || c.MemoryBytes != 128*1024*1024 {
+		t.Errorf("raw usage = %dm / %d bytes, want 100m / 128Mi", c.CPUMilli, c.MemoryBytes)
+	}
+}
+
+func TestQuantityParsing(t *testing.T) {
+	if got := CPUMillis("1500m"); got != 1500 {
+		t.Errorf("CPUMillis(1500m) = %d, want 1500", got)
+	}
+	if got := CPUMillis("2"); got != 2000 {
+		t.Errorf("CPUMillis(2) = %d, want 2000", got)
+	}
+	if got := MemoryBytes("512Mi"); got != 512*1024*1024 {
+		t.Errorf("MemoryBytes(512Mi) = %d", got)
+	}
+	if got := MemoryBytes(""); got != 0 {
+		t.Errorf("MemoryBytes(\"\") = %d, want 0", got)
+	}
 }
 
 func TestGetPodMetrics_NilClient(t *testing.T) {
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// NamespaceSnapshot is what a namespace comparison looks at: its
+// Deployments, ConfigMaps and the key names of its Secrets.
+type NamespaceSnapshot struct {
+	Namespace   string
+	Deployments []WorkloadInfo
+	ConfigMaps  []ConfigMapInfo
+	Secrets     []SecretInfo
+}
+
+// Presence tells which side of a comparison an object exists in.
+type Presence int
+
+// Presence values.
+const (
+	InBoth Presence = iota
+	LeftOnly
+	RightOnly
+)
+
+// ObjectDiff compares one object by kind and name across two namespaces.
+type ObjectDiff struct {
+	Kind     string // Deployment, ConfigMap or Secret
+	Name     string
+	Presence Presence
+	Fields   []FieldDiff // Fields that differ; empty when identical or on one side only
+}
+
+// Differs reports whether the object is missing on one side or has
+// differing fields.
+func (d ObjectDiff) Differs() bool {
+	return d.Presence != InBoth || len(d.Fields) > 0
+}
+
+// FieldDiff is a field whose value differs between the two namespaces.
+type FieldDiff struct {
+	Field string // e.g. "replicas", "image 1", "envFrom", "keys"
+	Left  string
+	Right string
+}
+
+// NamespaceDiff is the result of comparing two namespaces. Objects are
+// sorted by kind then name, and include identical ones.
+type NamespaceDiff struct {
+	Left    string
+	Right   string
+	Objects []ObjectDiff
+}
+
+// Differences returns the objects that differ.
+func (d NamespaceDiff) Differences() []ObjectDiff {
+	var result []ObjectDiff
+	for _, o := range d.Objects {
+		if o.Differs() {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+// FetchNamespaceSnapshot lists what CompareNamespaces needs from a namespace.
+func FetchNamespaceSnapshot(ctx context.Context, clientset kubernetes.Interface, namespace string) (*NamespaceSnapshot, error) {
+	deployments, err := ListWorkloads(ctx, clientset, namespace, ResourceDeployments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments in %s: %w", namespace, err)
+	}
+	configMaps, err := ListConfigMaps(ctx, clientset, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list configmaps in %s: %w", namespace, err)
+	}
+	secrets, err := ListSecrets(ctx, clientset, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets in %s: %w", namespace, err)
+	}
+	return &NamespaceSnapshot{Namespace: namespace, Deployments: deployments, ConfigMaps: configMaps, Secrets: secrets}, nil
+}
+
+// CompareNamespaces diffs the Deployments, ConfigMaps and Secrets of two
+// namespaces by name. Deployments are compared on replicas, images per
+// container and envFrom sources; ConfigMaps and Secrets on their key names
+// only. ServiceAccount token Secrets are skipped since their names are
+// generated per namespace.
+func CompareNamespaces(left, right NamespaceSnapshot) NamespaceDiff {
+	diff := NamespaceDiff{Left: left.Namespace, Right: right.Namespace}
+
+	var names []string
+	leftDeps := make(map[string]WorkloadInfo)
+	for _, d := range left.Deployments {
+		leftDeps[d.Name] = d
+		names = append(names, d.Name)
+	}
+	rightDeps := make(map[string]WorkloadInfo)
+	for _, d := range right.Deployments {
+		rightDeps[d.Name] = d
+		names = append(names, d.Name)
+	}
+	for _, name := range uniqueSorted(names) {
+		l, inLeft := leftDeps[name]
+		r, inRight := rightDeps[name]
+		obj := ObjectDiff{Kind: "Deployment", Name: name, Presence: presence(inLeft, inRight)}
+		if inLeft && inRight {
+			obj.Fields = deploymentFieldDiffs(l, r)
+		}
+		diff.Objects = append(diff.Objects, obj)
+	}
+
+	leftCMs := make(map[string][]string)
+	for _, cm := range left.ConfigMaps {
+		leftCMs[cm.Name] = cm.KeyNames
+	}
+	rightCMs := make(map[string][]string)
+	for _, cm := range right.ConfigMaps {
+		rightCMs[cm.Name] = cm.KeyNames
+	}
+	diff.Objects = append(diff.Objects, compareKeys("ConfigMap", leftCMs, rightCMs)...)
+
+	diff.Objects = append(diff.Objects, compareKeys("Secret", secretKeys(left.Secrets), secretKeys(right.Secrets))...)
+	return diff
+}
+
+func presence(inLeft, inRight bool) Presence {
+	switch {
+	case inLeft && !inRight:
+		return LeftOnly
+	case inRight && !inLeft:
+		return RightOnly
+	}
+	return InBoth
+}
+
+// uniqueSorted returns names sorted, without duplicates.
+func uniqueSorted(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var result []string
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func deploymentFieldDiffs(left, right WorkloadInfo) []FieldDiff {
+	var fields []FieldDiff
+	if left.Replicas != right.Replicas {
+		fields = append(fields, FieldDiff{Field: "replicas", Left: fmt.Sprint(left.Replicas), Right: fmt.Sprint(right.Replicas)})
+	}
+	for i := 0; i < len(left.Images) || i < len(right.Images); i++ {
+		var l, r string
+		if i < len(left.Images) {
+			l = left.Images[i]
+		}
+		if i < len(right.Images) {
+			r = right.Images[i]
+		}
+		if l != r {
+			fields = append(fields, FieldDiff{Field: fmt.Sprintf("image %d", i+1), Left: orNone(l), Right: orNone(r)})
+		}
+	}
+	if l, r := strings.Join(left.EnvFrom, ", "), strings.Join(right.EnvFrom, ", "); l != r {
+		fields = append(fields, FieldDiff{Field: "envFrom", Left: orNone(l), Right: orNone(r)})
+	}
+	return fields
+}
+
+// compareKeys diffs objects by their key names, reporting the keys found on
+// one side only.
+func compareKeys(kind string, left, right map[string][]string) []ObjectDiff {
+	var names []string
+	for name := range left {
+		names = append(names, name)
+	}
+	for name := range right {
+		names = append(names, name)
+	}
+	var result []ObjectDiff
+	for _, name := range uniqueSorted(names) {
+		l, inLeft := left[name]
+		r, inRight := right[name]
+		obj := ObjectDiff{Kind: kind, Name: name, Presence: presence(inLeft, inRight)}
+		if inLeft && inRight {
+			onlyLeft, onlyRight := keysOnlyIn(l, r), keysOnlyIn(r, l)
+			if len(onlyLeft) > 0 || len(onlyRight) > 0 {
+				obj.Fields = append(obj.Fields, FieldDiff{
+					Field: "keys",
+					Left:  orNone(strings.Join(onlyLeft, ", ")),
+					Right: orNone(strings.Join(onlyRight, ", ")),
+				})
+			}
+		}
+		result = append(result, obj)
+	}
+	return result
+}
+
+// keysOnlyIn returns the keys of a missing from b.
+func keysOnlyIn(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, k := range b {
+		inB[k] = true
+	}
+	var only []string
+	for _, k := range a {
+		if !inB[k] {
+			only = append(only, k)
+		}
+	}
+	return only
+}
+
+func secretKeys(secrets []SecretInfo) map[string][]string {
+	keys := make(map[string][]string)
+	for _, s := range secrets {
+		if s.Type == "kubernetes.io/service-account-token" {
+			continue
+		}
+		keys[s.Name] = s.KeyNames
+	}
+	return keys
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func stagingSnapshot() NamespaceSnapshot {
+	return NamespaceSnapshot{
+		Namespace: "staging",
+		Deployments: []WorkloadInfo{
+			{Name: "api", Replicas: 1, Images: []string{"shop/api:1.5.0", "envoy:1.29"}, EnvFrom: []string{"ConfigMap/api-config", "Secret/api-db"}},
+			{Name: "web", Replicas: 2, Images: []string{"shop/web:2.0.0"}},
+			{Name: "debug-tools", Replicas: 1, Images: []string{"netshoot"}},
+		},
+		ConfigMaps: []ConfigMapInfo{
+			{Name: "api-config", KeyNames: []string{"DB_HOST", "FEATURE_X", "LOG_LEVEL"}},
+			{Name: "kube-root-ca.crt", KeyNames: []string{"ca.crt"}},
+		},
+		Secrets: []SecretInfo{
+			{Name: "api-db", Type: "Opaque", KeyNames: []string{"password", "username"}},
+			{Name: "default-token-abcde", Type: "kubernetes.io/service-account-token", KeyNames: []string{"token"}},
+		},
+	}
+}
+
+func prodSnapshot() NamespaceSnapshot {
+	return NamespaceSnapshot{
+		Namespace: "prod",
+		Deployments: []WorkloadInfo{
+			{Name: "api", Replicas: 6, Images: []string{"shop/api:1.4.2"}, EnvFrom: []string{"ConfigMap/api-config"}},
+			{Name: "web", Replicas: 2, Images: []string{"shop/web:2.0.0"}},
+		},
+		ConfigMaps: []ConfigMapInfo{
+			{Name: "api-config", KeyNames: []string{"DB_HOST", "LOG_LEVEL", "POOL_SIZE"}},
+			{Name: "kube-root-ca.crt", KeyNames: []string{"ca.crt"}},
+			{Name: "prod-only", KeyNames: []string{"x"}},
+		},
+		Secrets: []SecretInfo{
+			{Name: "api-db", Type: "Opaque", KeyNames: []string{"password", "username"}},
+			{Name: "default-token-zyxwv", Type: "kubernetes.io/service-account-token", KeyNames: []string{"token"}},
+		},
+	}
+}
+
+func findObject(t *testing.T, diff NamespaceDiff, kind, name string) ObjectDiff {
+	t.Helper()
+	for _, o := range diff.Objects {
+		if o.Kind == kind && o.Name == name {
+			return o
+		}
+	}
+	t.Fatalf("%s/%s not in diff", kind, name)
+	return ObjectDiff{}
+}
+
+func TestCompareNamespaces(t *testing.T) {
+	diff := CompareNamespaces(stagingSnapshot(), prodSnapshot())
+	if diff.Left != "staging" || diff.Right != "prod" {
+		t.Errorf("sides = %s, %s", diff.Left, diff.Right)
+	}
+
+	var order []string
+	for _, o := range diff.Objects {
+		order = append(order, o.Kind+"/"+o.Name)
+	}
+	want := []string{"Deployment/api", "Deployment/debug-tools", "Deployment/web", "ConfigMap/api-config",
+		"ConfigMap/kube-root-ca.crt", "ConfigMap/prod-only", "Secret/api-db"}
+	if len(order) != len(want) {
+		t.Fatalf("objects = %v, want %v (token secrets skipped)", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("objects = %v, want %v", order, want)
+		}
+	}
+
+	api := findObject(t, diff, "Deployment", "api")
+	wantFields := []FieldDiff{
+		{Field: "replicas", Left: "1", Right: "6"},
+		{Field: "image 1", Left: "shop/api:1.5.0", Right: "shop/api:1.4.2"},
+		{Field: "image 2", Left: "envoy:1.29", Right: "<none>"},
+		{Field: "envFrom", Left: "ConfigMap/api-config, Secret/api-db", Right: "ConfigMap/api-config"},
+	}
+	if len(api.Fields) != len(wantFields) {
+		t.Fatalf("api fields = %+v, want %+v", api.Fields, wantFields)
+	}
+	for i, f := range wantFields {
+		if api.Fields[i] != f {
+			t.Errorf("api field %d = %+v, want %+v", i, api.Fields[i], f)
+		}
+	}
+
+	if web := findObject(t, diff, "Deployment", "web"); web.Differs() {
+		t.Errorf("web = %+v, want identical", web)
+	}
+	if dbg := findObject(t, diff, "Deployment", "debug-tools"); dbg.Presence != LeftOnly || !dbg.Differs() {
+		t.Errorf("debug-tools = %+v, want staging only", dbg)
+	}
+	if only := findObject(t, diff, "ConfigMap", "prod-only"); only.Presence != RightOnly {
+		t.Errorf("prod-only = %+v, want prod only", only)
+	}
+
+	cfg := findObject(t, diff, "ConfigMap", "api-config")
+	if len(cfg.Fields) != 1 || cfg.Fields[0] != (FieldDiff{Field: "keys", Left: "FEATURE_X", Right: "POOL_SIZE"}) {
+		t.Errorf("api-config fields = %+v, want the keys on one side only", cfg.Fields)
+	}
+	if db := findObject(t, diff, "Secret", "api-db"); db.Differs() {
+		t.Errorf("api-db = %+v, want identical key names", db)
+	}
+
+	if got := len(diff.Differences()); got != 4 {
+		t.Errorf("Differences() = %d, want api, debug-tools, api-config and prod-only", got)
+	}
+}
+
+func TestCompareNamespaces_Identical(t *testing.T) {
+	right := stagingSnapshot()
+	right.Namespace = "staging-2"
+	diff := CompareNamespaces(stagingSnapshot(), right)
+	if d := diff.Differences(); len(d) != 0 {
+		t.Errorf("Differences() = %+v, want none", d)
+	}
+}
+
+func TestFetchNamespaceSnapshot(t *testing.T) {
+	replicas := int32(3)
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "prod"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+					{Name: "api", Image: "shop/api:1.4.2", EnvFrom: []corev1.EnvFromSource{
+						{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "api-db"}}},
+						{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "api-config"}}},
+					}},
+					{Name: "proxy", Image: "envoy:1.29", EnvFrom: []corev1.EnvFromSource{
+						{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "api-config"}}},
+					}},
+				}}},
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-config", Namespace: "prod"},
+			Data:       map[string]string{"LOG_LEVEL": "info", "DB_HOST": "db"},
+			BinaryData: map[string][]byte{"cert.der": {1}},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-db", Namespace: "prod"},
+			Data:       map[string][]byte{"username": []byte("shop"), "password": []byte("s3cret")},
+		},
+	)
+
+	snap, err := FetchNamespaceSnapshot(context.Background(), clientset, "prod")
+	if err != nil {
+		t.Fatalf("FetchNamespaceSnapshot() error = %v", err)
+	}
+	if len(snap.Deployments) != 1 {
+		t.Fatalf("Deployments = %+v, want 1", snap.Deployments)
+	}
+	dep := snap.Deployments[0]
+	if len(dep.Images) != 2 || dep.Images[0] != "shop/api:1.4.2" || dep.Images[1] != "envoy:1.29" {
+		t.Errorf("Images = %v", dep.Images)
+	}
+	if len(dep.EnvFrom) != 2 || dep.EnvFrom[0] != "ConfigMap/api-config" || dep.EnvFrom[1] != "Secret/api-db" {
+		t.Errorf("EnvFrom = %v, want sorted and deduplicated", dep.EnvFrom)
+	}
+	if keys := snap.ConfigMaps[0].KeyNames; len(keys) != 3 || keys[0] != "DB_HOST" || keys[1] != "LOG_LEVEL" || keys[2] != "cert.der" {
+		t.Errorf("ConfigMap KeyNames = %v", keys)
+	}
+	if keys := snap.Secrets[0].KeyNames; len(keys) != 2 || keys[0] != "password" || keys[1] != "username" {
+		t.Errorf("Secret KeyNames = %v", keys)
+	}
+}
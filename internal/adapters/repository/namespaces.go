@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ValidateNamespaceName checks that name can name a namespace: an RFC 1123
+// label, at most 63 lowercase alphanumerics or '-', starting and ending
+// with an alphanumeric.
+func ValidateNamespaceName(name string) error {
+	if name == "" {
+		return fmt.Errorf("namespace name is empty")
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace name %q: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// CreateNamespace creates a namespace with the given labels, which may be
+// nil. The name is validated first, so a typo fails without a request.
+func CreateNamespace(ctx context.Context, clientset kubernetes.Interface, name string, labels map[string]string) error {
+	if err := ValidateNamespaceName(name); err != nil {
+		return err
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	if _, err := clientset.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create namespace: %w", err)
+	}
+	return nil
+}
+
+// DeleteNamespace deletes a namespace the ordinary way: it turns
+// Terminating while the namespace controller deletes its contents, and
+// disappears once its finalizers are done. See ForceDeleteNamespace for
+// namespaces that never get there.
+func DeleteNamespace(ctx context.Context, clientset kubernetes.Interface, name string) error {
+	if err := clientset.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to delete namespace: %w", err)
+	}
+	return nil
+}
+
+// CanForceDelete reports whether a namespace has been Terminating for at
+// least after, and so looks stuck rather than still cleaning up. Only then
+// is ForceDeleteNamespace, which skips its finalizers, offered.
+func (n NamespaceInfo) CanForceDelete(after time.Duration, now time.Time) bool {
+	if n.Status != string(corev1.NamespaceTerminating) || n.DeletionTimestamp.IsZero() {
+		return false
+	}
+	return now.Sub(n.DeletionTimestamp) >= after
+}
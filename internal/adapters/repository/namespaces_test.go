@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateNamespaceName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"payments", true},
+		{"team-a-2", true},
+		{"a", true},
+		{strings.Repeat("a", 63), true},
+		{strings.Repeat("a", 64), false},
+		{"", false},
+		{"Payments", false},
+		{"team_a", false},
+		{"-team", false},
+		{"team-", false},
+		{"team.a", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateNamespaceName(tt.name); (err == nil) != tt.valid {
+			t.Errorf("ValidateNamespaceName(%q) = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestCreateNamespace(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	if err := CreateNamespace(context.Background(), clientset, "Team_A", nil); err == nil {
+		t.Fatal("an invalid name should be an error")
+	}
+	if len(clientset.Actions()) != 0 {
+		t.Errorf("actions = %v, want no request for an invalid name", clientset.Actions())
+	}
+
+	if err := CreateNamespace(context.Background(), clientset, "team-a", map[string]string{"team": "a"}); err != nil {
+		t.Fatalf("CreateNamespace() error = %v", err)
+	}
+	ns, err := clientset.CoreV1().Namespaces().Get(context.Background(), "team-a", metav1.GetOptions{})
+	if err != nil || ns.Labels["team"] != "a" {
+		t.Errorf("namespace = %+v, %v, want it created with its labels", ns, err)
+	}
+	if err := CreateNamespace(context.Background(), clientset, "team-a", nil); err == nil {
+		t.Error("creating an existing namespace should be an error")
+	}
+
+	if err := DeleteNamespace(context.Background(), clientset, "team-a"); err != nil {
+		t.Fatalf("DeleteNamespace() error = %v", err)
+	}
+	if err := DeleteNamespace(context.Background(), clientset, "team-a"); err == nil {
+		t.Error("deleting a missing namespace should be an error")
+	}
+}
+
+func TestNamespaceInfo_CanForceDelete(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	after := 5 * time.Minute
+	tests := []struct {
+		name string
+		ns   NamespaceInfo
+		want bool
+	}{
+		{"active", NamespaceInfo{Status: "Active"}, false},
+		{"just deleted", NamespaceInfo{Status: "Terminating", DeletionTimestamp: now.Add(-time.Minute)}, false},
+		{"stuck", NamespaceInfo{Status: "Terminating", DeletionTimestamp: now.Add(-after)}, true},
+		{"terminating without a timestamp", NamespaceInfo{Status: "Terminating"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.ns.CanForceDelete(after, now); got != tt.want {
+			t.Errorf("%s: CanForceDelete() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestListNamespaces_DeletionTimestamp(t *testing.T) {
+	deleted := metav1.NewTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "old", DeletionTimestamp: &deleted}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}},
+	)
+	namespaces, err := ListNamespaces(context.Background(), clientset)
+	if err != nil {
+		t.Fatalf("ListNamespaces() error = %v", err)
+	}
+	if len(namespaces) != 2 || !namespaces[0].DeletionTimestamp.Equal(deleted.Time) || !namespaces[1].DeletionTimestamp.IsZero() {
+		t.Errorf("ListNamespaces() = %+v, want the deletion time of the terminating namespace", namespaces)
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NetworkPolicyInfo summarizes a NetworkPolicy that selects a pod.
+type NetworkPolicyInfo struct {
+	Name         string
+	PolicyTypes  []string // Ingress, Egress
+	IngressRules int      // Number of ingress allow rules
+	EgressRules  int      // Number of egress allow rules
+	DefaultDeny  bool     // Selects every pod and allows nothing in one of its directions
+}
+
+// NetworkPolicyResult is the NetworkPolicies applying to a pod and whether
+// they leave it isolated.
+type NetworkPolicyResult struct {
+	Policies      []NetworkPolicyInfo
+	IngressDenied bool // A default-deny policy applies and no policy allows ingress
+	EgressDenied  bool // A default-deny policy applies and no policy allows egress
+}
+
+// getPodNetworkPolicies lists the NetworkPolicies of the pod's namespace
+// whose podSelector selects the pod. An empty podSelector selects every
+// pod. Errors, such as missing RBAC permissions, yield an empty result.
+func getPodNetworkPolicies(ctx context.Context, clientset kubernetes.Interface, pod PodInfo) NetworkPolicyResult {
+	var result NetworkPolicyResult
+	policies, err := clientset.NetworkingV1().NetworkPolicies(pod.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result
+	}
+
+	var denyIngress, denyEgress, allowIngress, allowEgress bool
+	for _, np := range policies.Items {
+		if !networkPolicySelects(np, pod.Labels) {
+			continue
+		}
+		info := NetworkPolicyInfo{
+			Name:         np.Name,
+			PolicyTypes:  networkPolicyTypes(np),
+			IngressRules: len(np.Spec.Ingress),
+			EgressRules:  len(np.Spec.Egress),
+		}
+		selectsAll := len(np.Spec.PodSelector.MatchLabels) == 0 && len(np.Spec.PodSelector.MatchExpressions) == 0
+		for _, t := range info.PolicyTypes {
+			switch t {
+			case string(networkingv1.PolicyTypeIngress):
+				allowIngress = allowIngress || info.IngressRules > 0
+				if selectsAll && info.IngressRules == 0 {
+					denyIngress, info.DefaultDeny = true, true
+				}
+			case string(networkingv1.PolicyTypeEgress):
+				allowEgress = allowEgress || info.EgressRules > 0
+				if selectsAll && info.EgressRules == 0 {
+					denyEgress, info.DefaultDeny = true, true
+				}
+			}
+		}
+		result.Policies = append(result.Policies, info)
+	}
+	result.IngressDenied = denyIngress && !allowIngress
+	result.EgressDenied = denyEgress && !allowEgress
+	return result
+}
+
+// networkPolicySelects reports whether the policy's podSelector matches
+// podLabels.
+func networkPolicySelects(np networkingv1.NetworkPolicy, podLabels map[string]string) bool {
+	selector := np.Spec.PodSelector
+	if len(selector.MatchExpressions) == 0 {
+		return labelsMatch(selector.MatchLabels, podLabels)
+	}
+	s, err := metav1.LabelSelectorAsSelector(&selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(labels.Set(podLabels))
+}
+
+// networkPolicyTypes returns the directions a policy applies to. When
+// policyTypes is unset, a policy always applies to ingress, and to egress
+// only if it has egress rules.
+func networkPolicyTypes(np networkingv1.NetworkPolicy) []string {
+	var types []string
+	for _, t := range np.Spec.PolicyTypes {
+		types = append(types, string(t))
+	}
+	if len(types) > 0 {
+		return types
+	}
+	types = []string{string(networkingv1.PolicyTypeIngress)}
+	if len(np.Spec.Egress) > 0 {
+		types = append(types, string(networkingv1.PolicyTypeEgress))
+	}
+	return types
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func networkPolicy(name string, selector map[string]string, types []networkingv1.PolicyType, ingress, egress int) *networkingv1.NetworkPolicy {
+	np := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: selector},
+			PolicyTypes: types,
+		},
+	}
+	for i := 0; i < ingress; i++ {
+		np.Spec.Ingress = append(np.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{})
+	}
+	for i := 0; i < egress; i++ {
+		np.Spec.Egress = append(np.Spec.Egress, networkingv1.NetworkPolicyEgressRule{})
+	}
+	return np
+}
+
+var webPod = PodInfo{Name: "web-1", Namespace: "default", Labels: map[string]string{"app": "web", "tier": "frontend"}}
+
+func TestGetRelatedResources_NetworkPolicies(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		networkPolicy("allow-web", map[string]string{"app": "web"}, nil, 2, 0),
+		networkPolicy("db-only", map[string]string{"app": "db"}, nil, 1, 0),
+		networkPolicy("web-egress", map[string]string{"tier": "frontend"},
+			[]networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, 1, 3),
+	)
+
+	related, err := GetRelatedResources(context.Background(), clientset, nil, webPod)
+	if err != nil {
+		t.Fatalf("GetRelatedResources() error = %v", err)
+	}
+	policies := related.NetworkPolicies.Policies
+	if len(policies) != 2 {
+		t.Fatalf("Policies = %+v, want allow-web and web-egress", policies)
+	}
+	byName := map[string]NetworkPolicyInfo{}
+	for _, p := range policies {
+		byName[p.Name] = p
+	}
+	if _, ok := byName["db-only"]; ok {
+		t.Error("a policy selecting other pods should not be listed")
+	}
+	allowWeb := byName["allow-web"]
+	if allowWeb.IngressRules != 2 || len(allowWeb.PolicyTypes) != 1 || allowWeb.PolicyTypes[0] != "Ingress" {
+		t.Errorf("allow-web = %+v, want 2 ingress rules and the default Ingress type", allowWeb)
+	}
+	egress := byName["web-egress"]
+	if egress.EgressRules != 3 || len(egress.PolicyTypes) != 2 {
+		t.Errorf("web-egress = %+v, want 3 egress rules and both types", egress)
+	}
+	if related.NetworkPolicies.IngressDenied || related.NetworkPolicies.EgressDenied {
+		t.Error("no default-deny policy, the pod should not be flagged")
+	}
+}
+
+func TestGetRelatedResources_NetworkPolicies_EmptyNamespace(t *testing.T) {
+	related, err := GetRelatedResources(context.Background(), fake.NewSimpleClientset(), nil, webPod)
+	if err != nil {
+		t.Fatalf("GetRelatedResources() error = %v", err)
+	}
+	if got := related.NetworkPolicies; len(got.Policies) != 0 || got.IngressDenied || got.EgressDenied {
+		t.Errorf("NetworkPolicies = %+v, want none", got)
+	}
+}
+
+func TestGetPodNetworkPolicies_DefaultDeny(t *testing.T) {
+	ctx := context.Background()
+	denyAll := networkPolicy("default-deny", nil,
+		[]networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, 0, 0)
+
+	// Nothing allows traffic to or from the pod
+	result := getPodNetworkPolicies(ctx, fake.NewSimpleClientset(denyAll), webPod)
+	if !result.IngressDenied || !result.EgressDenied {
+		t.Errorf("result = %+v, want ingress and egress denied", result)
+	}
+	if len(result.Policies) != 1 || !result.Policies[0].DefaultDeny {
+		t.Errorf("Policies = %+v, want default-deny flagged", result.Policies)
+	}
+
+	// An allow rule for the pod lifts the ingress flag only
+	allow := networkPolicy("allow-web", map[string]string{"app": "web"}, nil, 1, 0)
+	result = getPodNetworkPolicies(ctx, fake.NewSimpleClientset(denyAll, allow), webPod)
+	if result.IngressDenied || !result.EgressDenied {
+		t.Errorf("result = %+v, want only egress denied", result)
+	}
+
+	// Allow rules for other pods don't help this one
+	other := networkPolicy("allow-db", map[string]string{"app": "db"}, nil, 1, 0)
+	result = getPodNetworkPolicies(ctx, fake.NewSimpleClientset(denyAll, other), webPod)
+	if !result.IngressDenied {
+		t.Error("an allow rule selecting other pods should not lift the flag")
+	}
+}
+
+func TestNetworkPolicySelects_MatchExpressions(t *testing.T) {
+	np := networkingv1.NetworkPolicy{Spec: networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"frontend", "edge"}},
+		}},
+	}}
+	if !networkPolicySelects(np, webPod.Labels) {
+		t.Error("tier in (frontend, edge) should select the web pod")
+	}
+	if networkPolicySelects(np, map[string]string{"tier": "backend"}) {
+		t.Error("tier in (frontend, edge) should not select a backend pod")
+	}
+}
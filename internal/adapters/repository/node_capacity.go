@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NodeCapacity is how much of a node's allocatable capacity the requests
+// of the pods on it take, for capacity planning.
+type NodeCapacity struct {
+	Name              string
+	Ready             bool
+	Unschedulable     bool  // Cordoned
+	AllocatableCPU    int64 // Millicores
+	AllocatableMemory int64 // Bytes
+	RequestedCPU      int64 // Sum of the requests of the non-terminated pods on the node, millicores
+	RequestedMemory   int64 // Bytes
+	Pods              int64 // Non-terminated pods on the node
+	MaxPods           int64 // Allocatable pods
+	Taints            []TaintInfo
+
+	// Failures are the predicates the pod GetNodeCapacity was given fails
+	// on the node; empty when it fits or no pod was given.
+	Failures []PredicateFailure
+}
+
+// FreeCPU is the allocatable CPU not requested yet, in millicores.
+func (n NodeCapacity) FreeCPU() int64 {
+	return n.AllocatableCPU - n.RequestedCPU
+}
+
+// FreeMemory is the allocatable memory not requested yet, in bytes.
+func (n NodeCapacity) FreeMemory() int64 {
+	return n.AllocatableMemory - n.RequestedMemory
+}
+
+// CPUPercent is the percent of allocatable CPU requested.
+func (n NodeCapacity) CPUPercent() float64 {
+	return percentOf(n.RequestedCPU, n.AllocatableCPU)
+}
+
+// MemoryPercent is the percent of allocatable memory requested.
+func (n NodeCapacity) MemoryPercent() float64 {
+	return percentOf(n.RequestedMemory, n.AllocatableMemory)
+}
+
+// Fits reports whether the pod evaluated on the node passes every
+// predicate.
+func (n NodeCapacity) Fits() bool {
+	return len(n.Failures) == 0
+}
+
+// TaintSummary summarizes the taints, e.g. "dedicated=gpu:NoSchedule +1";
+// "" when there are none.
+func (n NodeCapacity) TaintSummary() string {
+	switch len(n.Taints) {
+	case 0:
+		return ""
+	case 1:
+		return n.Taints[0].String()
+	}
+	return fmt.Sprintf("%s +%d", n.Taints[0], len(n.Taints)-1)
+}
+
+// GetNodeCapacity lists every node with its allocatable CPU, memory and
+// pods, and the requests of the non-terminated pods scheduled on it. Given
+// a pod, typically a Pending one, it also evaluates the pod's scheduling
+// predicates on each node; the pod itself does not count against its own
+// node. Nodes are sorted by name.
+func GetNodeCapacity(ctx context.Context, clientset kubernetes.Interface, pod *PodInfo) ([]NodeCapacity, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	scheduled := pods.Items
+	if pod != nil {
+		scheduled = make([]corev1.Pod, 0, len(pods.Items))
+		for _, p := range pods.Items {
+			if p.Namespace != pod.Namespace || p.Name != pod.Name {
+				scheduled = append(scheduled, p)
+			}
+		}
+	}
+	committed := committedRequests(scheduled)
+
+	capacity := make([]NodeCapacity, 0, len(nodes.Items))
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		used := committed[node.Name]
+		c := NodeCapacity{
+			Name:              node.Name,
+			Ready:             isNodeReady(node),
+			Unschedulable:     node.Spec.Unschedulable,
+			AllocatableCPU:    node.Status.Allocatable.Cpu().MilliValue(),
+			AllocatableMemory: node.Status.Allocatable.Memory().Value(),
+			RequestedCPU:      used.cpu,
+			RequestedMemory:   used.mem,
+			Pods:              used.pods,
+			MaxPods:           node.Status.Allocatable.Pods().Value(),
+		}
+		for _, t := range node.Spec.Taints {
+			c.Taints = append(c.Taints, TaintInfo{Key: t.Key, Value: t.Value, Effect: string(t.Effect)})
+		}
+		if pod != nil {
+			c.Failures = evaluateNodeFit(node, used, *pod)
+		}
+		capacity = append(capacity, c)
+	}
+
+	sort.Slice(capacity, func(i, j int) bool {
+		return capacity[i].Name < capacity[j].Name
+	})
+	return capacity, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetNodeCapacity(t *testing.T) {
+	finished := drainPod("done", "node-b", "4", "1Gi")
+	finished.Status.Phase = corev1.PodSucceeded
+	tainted := drainNode("node-b", "4", "8Gi")
+	tainted.Spec.Taints = []corev1.Taint{
+		{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+	}
+	clientset := fake.NewSimpleClientset(
+		tainted,
+		drainNode("node-a", "2", "4Gi"),
+		drainPod("web", "node-a", "1500m", "1Gi"),
+		drainPod("db", "node-b", "1", "2Gi"),
+		drainPod("api", "node-a", "1", "1Gi"), // The pending pod, as if bound already
+		finished,
+	)
+	ctx := context.Background()
+
+	nodes, err := GetNodeCapacity(ctx, clientset, nil)
+	if err != nil {
+		t.Fatalf("GetNodeCapacity() error = %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].Name != "node-a" || nodes[1].Name != "node-b" {
+		t.Fatalf("GetNodeCapacity() = %+v, want node-a and node-b", nodes)
+	}
+	a, b := nodes[0], nodes[1]
+	if a.RequestedCPU != 2500 || a.Pods != 2 || a.FreeCPU() != -500 || a.CPUPercent() != 125 {
+		t.Errorf("node-a = %d CPU requested by %d pods (free %d, %.0f%%), want 2500m by 2 pods", a.RequestedCPU, a.Pods, a.FreeCPU(), a.CPUPercent())
+	}
+	if b.RequestedCPU != 1000 || b.Pods != 1 || b.MaxPods != 110 || b.MemoryPercent() != 25 {
+		t.Errorf("node-b = %dm CPU by %d/%d pods, %.0f%% memory, want the finished pod left out", b.RequestedCPU, b.Pods, b.MaxPods, b.MemoryPercent())
+	}
+	if got := b.TaintSummary(); got != "dedicated=db:NoSchedule +1" {
+		t.Errorf("TaintSummary() = %q", got)
+	}
+	if !a.Fits() || !b.Fits() {
+		t.Error("nodes should have no failures without a pod")
+	}
+
+	pod := &PodInfo{
+		Name:      "api",
+		Namespace: "default",
+		Containers: []ContainerInfo{{
+			Name:      "app",
+			Resources: ResourceRequirements{CPURequest: "500m", MemoryRequest: "1Gi"},
+		}},
+	}
+	nodes, err = GetNodeCapacity(ctx, clientset, pod)
+	if err != nil {
+		t.Fatalf("GetNodeCapacity() error = %v", err)
+	}
+	a, b = nodes[0], nodes[1]
+	if a.RequestedCPU != 1500 || a.Pods != 1 {
+		t.Errorf("node-a = %dm CPU by %d pods, want the pod itself left out", a.RequestedCPU, a.Pods)
+	}
+	if !a.Fits() {
+		t.Errorf("node-a failures = %+v, want it to fit", a.Failures)
+	}
+	if len(b.Failures) != 1 || b.Failures[0].Predicate != PredicateTaintToleration {
+		t.Errorf("node-b failures = %+v, want the untolerated taint", b.Failures)
+	}
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// drainRetryInterval is how often an eviction blocked by a
+// PodDisruptionBudget is retried. A variable so tests can shorten it.
+var drainRetryInterval = 5 * time.Second
+
+// drainConcurrency caps how many pods DrainNode evicts at once, so a
+// node with many pods doesn't flood the API server. A variable so tests
+// can lower it.
+var drainConcurrency = 10
+
+// CordonNode marks a node unschedulable, so no new pods are placed on it.
+func CordonNode(ctx context.Context, clientset kubernetes.Interface, nodeName string) error {
+	if err := setUnschedulable(ctx, clientset, nodeName, true); err != nil {
+		return fmt.Errorf("failed to cordon node: %w", err)
+	}
+	return nil
+}
+
+// UncordonNode marks a node schedulable again.
+func UncordonNode(ctx context.Context, clientset kubernetes.Interface, nodeName string) error {
+	if err := setUnschedulable(ctx, clientset, nodeName, false); err != nil {
+		return fmt.Errorf("failed to uncordon node: %w", err)
+	}
+	return nil
+}
+
+func setUnschedulable(ctx context.Context, clientset kubernetes.Interface, nodeName string, unschedulable bool) error {
+	node, err := clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if node.Spec.Unschedulable == unschedulable {
+		return nil
+	}
+	node.Spec.Unschedulable = unschedulable
+	_, err = clientset.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+	return err
+}
+
+// DrainPodState is where a pod is in a drain.
+type DrainPodState string
+
+// States reported for each pod of a drained node.
+const (
+	DrainPodEvicting DrainPodState = "evicting" // Eviction requested
+	DrainPodBlocked  DrainPodState = "blocked"  // A PodDisruptionBudget refused the eviction; retrying
+	DrainPodEvicted  DrainPodState = "evicted"  // Eviction accepted
+	DrainPodSkipped  DrainPodState = "skipped"  // DaemonSet, mirror or finished pod
+	DrainPodFailed   DrainPodState = "failed"   // Could not be evicted, see Reason
+)
+
+// DrainPodProgress is a change in the state of one pod during a drain.
+type DrainPodProgress struct {
+	Namespace string
+	Name      string
+	State     DrainPodState
+	Reason    string // Why a pod is blocked, skipped or failed
+}
+
+// DrainOptions configures DrainNode.
+type DrainOptions struct {
+	GracePeriodSeconds *int64                 // nil uses each pod's own grace period
+	Timeout            time.Duration          // How long to retry evictions a PodDisruptionBudget blocks; 0 tries once
+	Force              bool                   // Also evict pods no controller will recreate
+	OnProgress         func(DrainPodProgress) // Called on every state change, one call at a time
+}
+
+// DrainResult is the outcome of a drain.
+type DrainResult struct {
+	Node    string
+	Evicted []string           // namespace/name of evicted pods
+	Skipped []string           // namespace/name of DaemonSet, mirror and finished pods
+	Failed  []DrainPodProgress // Pods that could not be evicted, with the reason
+}
+
+// DrainNode cordons a node and evicts its pods through the eviction API,
+// like kubectl drain --ignore-daemonsets. Evictions honor
+// PodDisruptionBudgets: a blocked eviction is retried until opts.Timeout,
+// then reported as failed. Pods without a controller are not evicted
+// unless opts.Force is set, since nothing would recreate them. At most
+// drainConcurrency pods are evicted at a time. Evicted
+// pods may still be terminating when DrainNode returns.
+// An error is returned only when the node can't be cordoned or its pods
+// can't be listed; per-pod failures are in the result.
+func DrainNode(ctx context.Context, clientset kubernetes.Interface, nodeName string, opts DrainOptions) (*DrainResult, error) {
+	if err := CordonNode(ctx, clientset, nodeName); err != nil {
+		return nil, err
+	}
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + nodeName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	result := &DrainResult{Node: nodeName}
+	var mu sync.Mutex
+	report := func(p DrainPodProgress) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch p.State {
+		case DrainPodEvicted:
+			result.Evicted = append(result.Evicted, p.Namespace+"/"+p.Name)
+		case DrainPodSkipped:
+			result.Skipped = append(result.Skipped, p.Namespace+"/"+p.Name)
+		case DrainPodFailed:
+			result.Failed = append(result.Failed, p)
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(p)
+		}
+	}
+
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, drainConcurrency)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != nodeName {
+			continue
+		}
+		progress := DrainPodProgress{Namespace: pod.Namespace, Name: pod.Name}
+		if !isEvictable(pod) {
+			progress.State, progress.Reason = DrainPodSkipped, skipReason(pod)
+			report(progress)
+			continue
+		}
+		if !opts.Force && metav1.GetControllerOf(pod) == nil {
+			progress.State, progress.Reason = DrainPodFailed, "not managed by a controller"
+			report(progress)
+			continue
+		}
+
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			evictPod(ctx, clientset, pod, opts, progress, report)
+		}()
+	}
+	wg.Wait()
+
+	sort.Strings(result.Evicted)
+	sort.Strings(result.Skipped)
+	sort.Slice(result.Failed, func(i, j int) bool {
+		a, b := result.Failed[i], result.Failed[j]
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+	return result, nil
+}
+
+// evictPod evicts one pod, retrying while a PodDisruptionBudget blocks it.
+func evictPod(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, opts DrainOptions, progress DrainPodProgress, report func(DrainPodProgress)) {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+	}
+	if opts.GracePeriodSeconds != nil {
+		eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: opts.GracePeriodSeconds}
+	}
+
+	progress.State = DrainPodEvicting
+	report(progress)
+
+	deadline := time.Now().Add(opts.Timeout)
+	blocked := false
+	reason := "" // Looked up on the first refusal, not on every retry
+	for {
+		err := clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+		switch {
+		case err == nil, apierrors.IsNotFound(err):
+			progress.State, progress.Reason = DrainPodEvicted, ""
+			report(progress)
+			return
+		case apierrors.IsTooManyRequests(err):
+			// The API refuses evictions that would violate a PodDisruptionBudget
+			if reason == "" {
+				reason = "blocked by a PodDisruptionBudget"
+				if pdb := pdbFor(ctx, clientset, pod); pdb != "" {
+					reason = "blocked by PodDisruptionBudget " + pdb
+				}
+			}
+			if !time.Now().Before(deadline) {
+				progress.State, progress.Reason = DrainPodFailed, reason
+				report(progress)
+				return
+			}
+			if !blocked {
+				blocked = true
+				progress.State, progress.Reason = DrainPodBlocked, reason
+				report(progress)
+			}
+		default:
+			progress.State, progress.Reason = DrainPodFailed, err.Error()
+			report(progress)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			progress.State, progress.Reason = DrainPodFailed, ctx.Err().Error()
+			report(progress)
+			return
+		case <-time.After(drainRetryInterval):
+		}
+	}
+}
+
+// pdbFor returns the name of a PodDisruptionBudget selecting the pod, or
+// "" if none is found.
+func pdbFor(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) string {
+	pdbs, err := clientset.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return ""
+	}
+	for _, pdb := range pdbs.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return pdb.Name
+		}
+	}
+	return ""
+}
+
+// skipReason explains why isEvictable rejected a pod.
+func skipReason(p *corev1.Pod) string {
+	if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+		return "finished"
+	}
+	if _, ok := p.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return "mirror pod"
+	}
+	return "DaemonSet pod"
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func drainNodePod(name, ownerKind string, labels map[string]string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if ownerKind != "" {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: name + "-owner", Controller: &controller}}
+	}
+	return pod
+}
+
+// evictionReactor answers evictions: pods in blocked get a 429 as if a
+// PodDisruptionBudget refused them, any other pod is deleted.
+func evictionReactor(clientset *fake.Clientset, blocked map[string]bool) k8stesting.ReactionFunc {
+	var mu sync.Mutex
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		mu.Lock()
+		defer mu.Unlock()
+		if blocked[eviction.Name] {
+			return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+		}
+		return true, nil, clientset.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), eviction.Namespace, eviction.Name)
+	}
+}
+
+func TestCordonUncordonNode(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+
+	if err := CordonNode(ctx, clientset, "node-1"); err != nil {
+		t.Fatalf("CordonNode() error = %v", err)
+	}
+	node, _ := clientset.CoreV1().Nodes().Get(ctx, "node-1", metav1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Error("node should be unschedulable after CordonNode()")
+	}
+
+	if err := UncordonNode(ctx, clientset, "node-1"); err != nil {
+		t.Fatalf("UncordonNode() error = %v", err)
+	}
+	node, _ = clientset.CoreV1().Nodes().Get(ctx, "node-1", metav1.GetOptions{})
+	if node.Spec.Unschedulable {
+		t.Error("node should be schedulable after UncordonNode()")
+	}
+
+	if err := CordonNode(ctx, clientset, "missing"); err == nil {
+		t.Error("CordonNode() on a missing node should fail")
+	}
+}
+
+func TestDrainNode(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		drainNodePod("web-1", "ReplicaSet", map[string]string{"app": "web"}),
+		drainNodePod("db-0", "StatefulSet", map[string]string{"app": "db"}),
+		drainNodePod("fluentd", "DaemonSet", nil),
+		drainNodePod("bare", "", nil),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "elsewhere", Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: "node-2"},
+		},
+		&policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-pdb", Namespace: "default"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				MinAvailable: &intstr.IntOrString{IntVal: 1},
+				Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			},
+		},
+	)
+	clientset.PrependReactor("create", "pods", evictionReactor(clientset, map[string]bool{"db-0": true}))
+
+	var mu sync.Mutex
+	states := make(map[string][]DrainPodState)
+	result, err := DrainNode(ctx, clientset, "node-1", DrainOptions{
+		OnProgress: func(p DrainPodProgress) {
+			mu.Lock()
+			defer mu.Unlock()
+			states[p.Name] = append(states[p.Name], p.State)
+		},
+	})
+	if err != nil {
+		t.Fatalf("DrainNode() error = %v", err)
+	}
+
+	node, _ := clientset.CoreV1().Nodes().Get(ctx, "node-1", metav1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Error("DrainNode() should cordon the node")
+	}
+	if len(result.Evicted) != 1 || result.Evicted[0] != "default/web-1" {
+		t.Errorf("Evicted = %v, want [default/web-1]", result.Evicted)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != "default/fluentd" {
+		t.Errorf("Skipped = %v, want [default/fluentd]", result.Skipped)
+	}
+	if len(result.Failed) != 2 {
+		t.Fatalf("Failed = %+v, want bare and db-0", result.Failed)
+	}
+	if result.Failed[0].Name != "bare" || result.Failed[0].Reason != "not managed by a controller" {
+		t.Errorf("Failed[0] = %+v", result.Failed[0])
+	}
+	if result.Failed[1].Name != "db-0" || !strings.Contains(result.Failed[1].Reason, "db-pdb") {
+		t.Errorf("Failed[1] = %+v, want blocked by db-pdb", result.Failed[1])
+	}
+
+	if _, err := clientset.CoreV1().Pods("default").Get(ctx, "web-1", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Error("web-1 should have been evicted")
+	}
+	if got := states["web-1"]; len(got) != 2 || got[0] != DrainPodEvicting || got[1] != DrainPodEvicted {
+		t.Errorf("web-1 progress = %v, want [evicting evicted]", got)
+	}
+	if _, ok := states["elsewhere"]; ok {
+		t.Error("pods of other nodes should not be drained")
+	}
+}
+
+func TestDrainNode_RetriesBlockedEviction(t *testing.T) {
+	interval := drainRetryInterval
+	drainRetryInterval = 5 * time.Millisecond
+	t.Cleanup(func() { drainRetryInterval = interval })
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		drainNodePod("db-0", "StatefulSet", nil),
+	)
+	// The budget allows the disruption on the third attempt
+	attempts := 0
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		attempts++
+		if attempts < 3 {
+			return true, nil, apierrors.NewTooManyRequests("disruption budget", 0)
+		}
+		return true, nil, nil
+	})
+
+	var states []DrainPodState
+	result, err := DrainNode(context.Background(), clientset, "node-1", DrainOptions{
+		Timeout:    time.Minute,
+		OnProgress: func(p DrainPodProgress) { states = append(states, p.State) },
+	})
+	if err != nil {
+		t.Fatalf("DrainNode() error = %v", err)
+	}
+	if len(result.Evicted) != 1 || len(result.Failed) != 0 {
+		t.Errorf("result = %+v, want db-0 evicted", result)
+	}
+	want := []DrainPodState{DrainPodEvicting, DrainPodBlocked, DrainPodEvicted}
+	if len(states) != len(want) {
+		t.Fatalf("progress = %v, want %v", states, want)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Errorf("progress = %v, want %v", states, want)
+			break
+		}
+	}
+	pdbLists := 0
+	for _, action := range clientset.Actions() {
+		if action.Matches("list", "poddisruptionbudgets") {
+			pdbLists++
+		}
+	}
+	if pdbLists != 1 {
+		t.Errorf("PodDisruptionBudgets listed %d times, want once per blocked pod", pdbLists)
+	}
+}
+
+func TestDrainNode_LimitsConcurrentEvictions(t *testing.T) {
+	concurrency := drainConcurrency
+	drainConcurrency = 2
+	t.Cleanup(func() { drainConcurrency = concurrency })
+
+	objects := []runtime.Object{&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}}
+	for _, name := range []string{"web-1", "web-2", "web-3", "web-4", "web-5"} {
+		objects = append(objects, drainNodePod(name, "ReplicaSet", nil))
+	}
+	clientset := fake.NewSimpleClientset(objects...)
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		time.Sleep(10 * time.Millisecond)
+		return true, nil, nil
+	})
+
+	// Pods between evicting and evicted are the evictions in flight
+	inFlight, peak := 0, 0
+	result, err := DrainNode(context.Background(), clientset, "node-1", DrainOptions{
+		OnProgress: func(p DrainPodProgress) {
+			switch p.State {
+			case DrainPodEvicting:
+				inFlight++
+				peak = max(peak, inFlight)
+			case DrainPodEvicted, DrainPodFailed:
+				inFlight--
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("DrainNode() error = %v", err)
+	}
+	if len(result.Evicted) != 5 {
+		t.Errorf("Evicted = %v, want all 5 pods", result.Evicted)
+	}
+	if peak > 2 {
+		t.Errorf("%d evictions ran at once, want at most 2", peak)
+	}
+}
+
+func TestDrainNode_Force(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		drainNodePod("bare", "", nil),
+	)
+	clientset.PrependReactor("create", "pods", evictionReactor(clientset, nil))
+
+	result, err := DrainNode(context.Background(), clientset, "node-1", DrainOptions{Force: true})
+	if err != nil {
+		t.Fatalf("DrainNode() error = %v", err)
+	}
+	if len(result.Evicted) != 1 {
+		t.Errorf("Evicted = %v, want the unmanaged pod with Force", result.Evicted)
+	}
+}
+
+func TestDrainNode_MissingNode(t *testing.T) {
+	if _, err := DrainNode(context.Background(), fake.NewSimpleClientset(), "missing", DrainOptions{}); err == nil {
+		t.Error("DrainNode() on a missing node should fail")
+	}
+}
@@ -0,0 +1,361 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NodeFit tells whether a pod would fit on a node and, if not, why.
+type NodeFit struct {
+	Node     string
+	Fits     bool
+	Failures []PredicateFailure // Every predicate the pod fails on the node
+	Reason   string             // The failures' reasons, e.g. "Insufficient cpu (300m short)" (empty when it fits)
+}
+
+// Scheduling predicates evaluated for a pod on each node, named after the
+// scheduler plugins that check them.
+const (
+	PredicateNodeReady         = "NodeReady"
+	PredicateNodeUnschedulable = "NodeUnschedulable"
+	PredicateNodeAffinity      = "NodeAffinity" // nodeSelector and required node affinity
+	PredicateTaintToleration   = "TaintToleration"
+	PredicateNodeResourcesFit  = "NodeResourcesFit"
+)
+
+// unschedulableTaintKey is the taint the scheduler treats a cordoned node
+// as having: pods tolerating it may still land there.
+const unschedulableTaintKey = "node.kubernetes.io/unschedulable"
+
+// PredicateFailure is a scheduling predicate a pod fails on a node.
+type PredicateFailure struct {
+	Predicate string // One of the Predicate* constants
+	Reason    string // e.g. "Insufficient cpu (300m short)"
+}
+
+// nodeRequests is the sum of requests committed to a node.
+type nodeRequests struct {
+	cpu  int64 // Millicores
+	mem  int64 // Bytes
+	pods int64
+}
+
+// FindFittingNodes checks every node for room for the pod: allocatable minus
+// requests of the non-terminated pods already on it, plus the scheduling
+// predicates evaluateNodeFit checks. The pod's requests are taken from
+// PodInfo, so edited requests can be previewed before applying them.
+// Fitting nodes come first, then by name.
+func FindFittingNodes(ctx context.Context, clientset kubernetes.Interface, pod PodInfo) ([]NodeFit, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	// The pod itself must not count against its current node
+	var others []corev1.Pod
+	for _, p := range pods.Items {
+		if p.Namespace == pod.Namespace && p.Name == pod.Name {
+			continue
+		}
+		others = append(others, p)
+	}
+	committed := committedRequests(others)
+
+	fits := make([]NodeFit, 0, len(nodes.Items))
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		failures := evaluateNodeFit(node, committed[node.Name], pod)
+		fits = append(fits, NodeFit{
+			Node:     node.Name,
+			Fits:     len(failures) == 0,
+			Failures: failures,
+			Reason:   failureReasons(failures),
+		})
+	}
+
+	sort.SliceStable(fits, func(i, j int) bool {
+		if fits[i].Fits != fits[j].Fits {
+			return fits[i].Fits
+		}
+		return fits[i].Node < fits[j].Node
+	})
+
+	return fits, nil
+}
+
+// evaluateNodeFit returns every predicate the pod fails on the node,
+// given the requests already committed to it, in the order the scheduler
+// filters: readiness, cordon, node affinity, taints, then resources. Only
+// the common causes of a Pending pod are covered, not every scheduler
+// plugin.
+func evaluateNodeFit(node *corev1.Node, used nodeRequests, pod PodInfo) []PredicateFailure {
+	var failures []PredicateFailure
+	fail := func(predicate, format string, args ...interface{}) {
+		failures = append(failures, PredicateFailure{Predicate: predicate, Reason: fmt.Sprintf(format, args...)})
+	}
+	tolerations := tolerationsFromInfo(pod.Tolerations)
+
+	if !isNodeReady(node) {
+		fail(PredicateNodeReady, "node not ready")
+	}
+	if node.Spec.Unschedulable && !toleratesUnschedulable(tolerations) {
+		fail(PredicateNodeUnschedulable, "node cordoned")
+	}
+	for _, k := range sortedKeys(pod.NodeSelector) {
+		if v, ok := node.Labels[k]; !ok || v != pod.NodeSelector[k] {
+			fail(PredicateNodeAffinity, "nodeSelector %s=%s not matched", k, pod.NodeSelector[k])
+		}
+	}
+	if !nodeMatchesSelector(pod.NodeAffinity, node) {
+		fail(PredicateNodeAffinity, "required node affinity not matched")
+	}
+	if taint := untoleratedTaint(tolerations, node); taint != nil {
+		fail(PredicateTaintToleration, "taint %s not tolerated", formatTaint(taint))
+	}
+
+	cpu, mem := podInfoRequests(pod)
+	if free := node.Status.Allocatable.Cpu().MilliValue() - used.cpu; cpu > free {
+		fail(PredicateNodeResourcesFit, "Insufficient cpu (%s short)", formatCPU(cpu-free))
+	}
+	if free := node.Status.Allocatable.Memory().Value() - used.mem; mem > free {
+		fail(PredicateNodeResourcesFit, "Insufficient memory (%s short)", formatMemory(mem-free))
+	}
+	if used.pods >= node.Status.Allocatable.Pods().Value() {
+		fail(PredicateNodeResourcesFit, "Too many pods (%d/%d)", used.pods, node.Status.Allocatable.Pods().Value())
+	}
+	return failures
+}
+
+// toleratesUnschedulable reports whether the tolerations let a pod on a
+// cordoned node, as DaemonSet pods do.
+func toleratesUnschedulable(tolerations []corev1.Toleration) bool {
+	taint := &corev1.Taint{Key: unschedulableTaintKey, Effect: corev1.TaintEffectNoSchedule}
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// failureReasons joins the reasons of failures, e.g. "node cordoned,
+// Insufficient cpu (300m short)".
+func failureReasons(failures []PredicateFailure) string {
+	reasons := make([]string, len(failures))
+	for i, f := range failures {
+		reasons[i] = f.Reason
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// committedRequests sums the requests of non-terminated pods per node.
+// Unscheduled pods are ignored.
+func committedRequests(pods []corev1.Pod) map[string]nodeRequests {
+	committed := make(map[string]nodeRequests)
+	for i := range pods {
+		p := &pods[i]
+		if p.Spec.NodeName == "" || p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		cpu, mem := podRequests(p)
+		r := committed[p.Spec.NodeName]
+		r.cpu += cpu
+		r.mem += mem
+		r.pods++
+		committed[p.Spec.NodeName] = r
+	}
+	return committed
+}
+
+// podInfoRequests returns the effective CPU (millicores) and memory (bytes)
+// requests from PodInfo, using the same init container rule as podRequests.
+func podInfoRequests(pod PodInfo) (cpu, mem int64) {
+	for _, c := range pod.Containers {
+		cpu += parseMilli(c.Resources.CPURequest)
+		mem += parseBytes(c.Resources.MemoryRequest)
+	}
+	for _, c := range pod.InitContainers {
+		if v := parseMilli(c.Resources.CPURequest); v > cpu {
+			cpu = v
+		}
+		if v := parseBytes(c.Resources.MemoryRequest); v > mem {
+			mem = v
+		}
+	}
+	return cpu, mem
+}
+
+func parseMilli(s string) int64 {
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return 0
+	}
+	return q.MilliValue()
+}
+
+func parseBytes(s string) int64 {
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return 0
+	}
+	return q.Value()
+}
+
+func tolerationsFromInfo(infos []TolerationInfo) []corev1.Toleration {
+	tolerations := make([]corev1.Toleration, 0, len(infos))
+	for _, t := range infos {
+		tolerations = append(tolerations, corev1.Toleration{
+			Key:      t.Key,
+			Operator: corev1.TolerationOperator(t.Operator),
+			Value:    t.Value,
+			Effect:   corev1.TaintEffect(t.Effect),
+		})
+	}
+	return tolerations
+}
+
+// untoleratedTaint returns the first NoSchedule/NoExecute taint on the node
+// that none of the tolerations match, or nil.
+func untoleratedTaint(tolerations []corev1.Toleration, node *corev1.Node) *corev1.Taint {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return taint
+		}
+	}
+	return nil
+}
+
+// nodeMatchesSelector evaluates required node affinity: terms are ORed and
+// the expressions within a term are ANDed. A nil selector matches any node.
+func nodeMatchesSelector(sel *corev1.NodeSelector, node *corev1.Node) bool {
+	if sel == nil || len(sel.NodeSelectorTerms) == 0 {
+		return true
+	}
+	for _, term := range sel.NodeSelectorTerms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue // An empty term matches nothing
+		}
+		if termMatches(term, node) {
+			return true
+		}
+	}
+	return false
+}
+
+func termMatches(term corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	for _, req := range term.MatchExpressions {
+		value, ok := node.Labels[req.Key]
+		if !requirementMatches(req, value, ok) {
+			return false
+		}
+	}
+	for _, req := range term.MatchFields {
+		// metadata.name is the only field supported by the scheduler
+		if req.Key != "metadata.name" || !requirementMatches(req, node.Name, true) {
+			return false
+		}
+	}
+	return true
+}
+
+func requirementMatches(req corev1.NodeSelectorRequirement, value string, present bool) bool {
+	switch req.Operator {
+	case corev1.NodeSelectorOpIn:
+		return present && containsString(req.Values, value)
+	case corev1.NodeSelectorOpNotIn:
+		return !present || !containsString(req.Values, value)
+	case corev1.NodeSelectorOpExists:
+		return present
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !present
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !present || len(req.Values) != 1 {
+			return false
+		}
+		have, err1 := strconv.ParseInt(value, 10, 64)
+		want, err2 := strconv.ParseInt(req.Values[0], 10, 64)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if req.Operator == corev1.NodeSelectorOpGt {
+			return have > want
+		}
+		return have < want
+	}
+	return false
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatTaint(t *corev1.Taint) string {
+	if t.Value == "" {
+		return fmt.Sprintf("%s:%s", t.Key, t.Effect)
+	}
+	return fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
+}
+
+// NodeFitHelper summarizes FindFittingNodes results as a debug helper for
+// the pending-pod analysis.
+func NodeFitHelper(fits []NodeFit) DebugHelper {
+	fitting := 0
+	for _, f := range fits {
+		if f.Fits {
+			fitting++
+		}
+	}
+
+	helper := DebugHelper{
+		Issue:    fmt.Sprintf("Node Fit: %d/%d nodes fit this pod", fitting, len(fits)),
+		Severity: "Info",
+	}
+	if fitting == 0 {
+		helper.Severity = "High"
+	}
+	for _, f := range fits {
+		if f.Fits {
+			helper.Suggestions = append(helper.Suggestions, f.Node+": fits")
+		} else {
+			helper.Suggestions = append(helper.Suggestions, f.Node+": "+f.Reason)
+		}
+	}
+	return helper
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCommittedRequests(t *testing.T) {
+	finished := drainPod("job", "node-a", "4", "4Gi")
+	finished.Status.Phase = corev1.PodSucceeded
+	failed := drainPod("crashed", "node-a", "4", "4Gi")
+	failed.Status.Phase = corev1.PodFailed
+	pending := drainPod("pending", "", "4", "4Gi")
+	pending.Status.Phase = corev1.PodPending
+
+	pods := []corev1.Pod{
+		*drainPod("web", "node-a", "500m", "256Mi"),
+		*drainPod("api", "node-a", "250m", "128Mi"),
+		*drainPod("db", "node-b", "1", "1Gi"),
+		*finished,
+		*failed,
+		*pending,
+	}
+
+	committed := committedRequests(pods)
+
+	if got := committed["node-a"]; got.cpu != 750 || got.mem != 384*1024*1024 || got.pods != 2 {
+		t.Errorf("node-a = %+v, want 750m, 384Mi, 2 pods", got)
+	}
+	if got := committed["node-b"]; got.cpu != 1000 || got.pods != 1 {
+		t.Errorf("node-b = %+v, want 1000m, 1 pod", got)
+	}
+	if _, ok := committed[""]; ok {
+		t.Error("unscheduled pods should not be counted")
+	}
+}
+
+func TestEvaluateNodeFit(t *testing.T) {
+	pending := PodInfo{
+		Name:      "api",
+		Namespace: "default",
+		Containers: []ContainerInfo{{
+			Name:      "app",
+			Resources: ResourceRequirements{CPURequest: "1500m", MemoryRequest: "1Gi"},
+		}},
+	}
+	withSelector := pending
+	withSelector.NodeSelector = map[string]string{"disktype": "ssd"}
+	tolerating := pending
+	tolerating.Tolerations = []TolerationInfo{
+		{Key: "dedicated", Operator: "Equal", Value: "gpu", Effect: "NoSchedule"},
+		{Key: unschedulableTaintKey, Operator: "Exists", Effect: "NoSchedule"},
+	}
+
+	tainted := drainNode("gpu", "8", "16Gi")
+	tainted.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+	cordoned := drainNode("cordoned", "8", "16Gi")
+	cordoned.Spec.Unschedulable = true
+	notReady := drainNode("not-ready", "8", "16Gi")
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+	ssd := drainNode("ssd", "8", "16Gi")
+	ssd.Labels["disktype"] = "ssd"
+
+	tests := []struct {
+		name string
+		node *corev1.Node
+		used nodeRequests
+		pod  PodInfo
+		want []PredicateFailure
+	}{
+		{"fits", drainNode("big", "8", "16Gi"), nodeRequests{}, pending, nil},
+		{"insufficient cpu and memory", drainNode("small", "2", "2Gi"), nodeRequests{cpu: 1000, mem: 1536 << 20, pods: 3}, pending, []PredicateFailure{
+			{PredicateNodeResourcesFit, "Insufficient cpu (500m short)"},
+			{PredicateNodeResourcesFit, "Insufficient memory (512.0Mi short)"},
+		}},
+		{"too many pods", drainNode("full", "8", "16Gi"), nodeRequests{pods: 110}, pending, []PredicateFailure{
+			{PredicateNodeResourcesFit, "Too many pods (110/110)"},
+		}},
+		{"taint not tolerated", tainted, nodeRequests{}, pending, []PredicateFailure{
+			{PredicateTaintToleration, "taint dedicated=gpu:NoSchedule not tolerated"},
+		}},
+		{"taint tolerated", tainted, nodeRequests{}, tolerating, nil},
+		{"nodeSelector mismatch", drainNode("hdd", "8", "16Gi"), nodeRequests{}, withSelector, []PredicateFailure{
+			{PredicateNodeAffinity, "nodeSelector disktype=ssd not matched"},
+		}},
+		{"nodeSelector match", ssd, nodeRequests{}, withSelector, nil},
+		{"cordoned", cordoned, nodeRequests{}, pending, []PredicateFailure{
+			{PredicateNodeUnschedulable, "node cordoned"},
+		}},
+		{"cordon tolerated", cordoned, nodeRequests{}, tolerating, nil},
+		{"not ready", notReady, nodeRequests{}, pending, []PredicateFailure{
+			{PredicateNodeReady, "node not ready"},
+		}},
+		{"every failure is reported", tainted, nodeRequests{cpu: 7000}, withSelector, []PredicateFailure{
+			{PredicateNodeAffinity, "nodeSelector disktype=ssd not matched"},
+			{PredicateTaintToleration, "taint dedicated=gpu:NoSchedule not tolerated"},
+			{PredicateNodeResourcesFit, "Insufficient cpu (500m short)"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateNodeFit(tt.node, tt.used, tt.pod)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("evaluateNodeFit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeMatchesSelector(t *testing.T) {
+	node := drainNode("node-a", "1", "1Gi")
+	node.Labels = map[string]string{"zone": "us-east-1a", "cores": "16"}
+
+	term := func(reqs ...corev1.NodeSelectorRequirement) corev1.NodeSelectorTerm {
+		return corev1.NodeSelectorTerm{MatchExpressions: reqs}
+	}
+	req := func(key string, op corev1.NodeSelectorOperator, values ...string) corev1.NodeSelectorRequirement {
+		return corev1.NodeSelectorRequirement{Key: key, Operator: op, Values: values}
+	}
+
+	tests := []struct {
+		name string
+		sel  *corev1.NodeSelector
+		want bool
+	}{
+		{"nil", nil, true},
+		{"in", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{term(req("zone", corev1.NodeSelectorOpIn, "us-east-1a", "us-east-1b"))}}, true},
+		{"not in", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{term(req("zone", corev1.NodeSelectorOpNotIn, "us-east-1a"))}}, false},
+		{"exists", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{term(req("cores", corev1.NodeSelectorOpExists))}}, true},
+		{"does not exist", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{term(req("gpu", corev1.NodeSelectorOpDoesNotExist))}}, true},
+		{"gt", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{term(req("cores", corev1.NodeSelectorOpGt, "8"))}}, true},
+		{"lt", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{term(req("cores", corev1.NodeSelectorOpLt, "8"))}}, false},
+		{"and within term", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+			term(req("zone", corev1.NodeSelectorOpIn, "us-east-1a"), req("gpu", corev1.NodeSelectorOpExists)),
+		}}, false},
+		{"or across terms", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+			term(req("gpu", corev1.NodeSelectorOpExists)),
+			term(req("zone", corev1.NodeSelectorOpIn, "us-east-1a")),
+		}}, true},
+		{"match fields", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchFields: []corev1.NodeSelectorRequirement{req("metadata.name", corev1.NodeSelectorOpIn, "node-b")},
+		}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeMatchesSelector(tt.sel, node); got != tt.want {
+				t.Errorf("nodeMatchesSelector() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindFittingNodes(t *testing.T) {
+	// The pending pod is already known to the API; it must not count against any node
+	self := drainPod("pending", "node-a", "1", "1Gi")
+
+	clientset := fake.NewSimpleClientset(
+		drainNode("node-a", "2", "4Gi"),
+		drainNode("node-b", "2", "4Gi"),
+		drainPod("busy", "node-b", "1800m", "1Gi"),
+		self,
+	)
+
+	pod := PodInfo{
+		Name:      "pending",
+		Namespace: "default",
+		Containers: []ContainerInfo{{
+			Name:      "app",
+			Resources: ResourceRequirements{CPURequest: "500m", MemoryRequest: "512Mi"},
+		}},
+	}
+
+	ctx := context.Background()
+	fits, err := FindFittingNodes(ctx, clientset, pod)
+	if err != nil {
+		t.Fatalf("FindFittingNodes() error = %v", err)
+	}
+	if len(fits) != 2 {
+		t.Fatalf("FindFittingNodes() returned %d results, want 2", len(fits))
+	}
+	if !fits[0].Fits || fits[0].Node != "node-a" {
+		t.Errorf("fits[0] = %+v, want node-a fitting", fits[0])
+	}
+	if fits[1].Fits || fits[1].Reason != "Insufficient cpu (300m short)" {
+		t.Errorf("fits[1] = %+v, want node-b short by 300m", fits[1])
+	}
+
+	// Previewing bigger requests
+	pod.Containers[0].Resources.CPURequest = "2500m"
+	fits, _ = FindFittingNodes(ctx, clientset, pod)
+	for _, f := range fits {
+		if f.Fits {
+			t.Errorf("%s should not fit 2500m", f.Node)
+		}
+	}
+}
+
+func TestPodInfoRequests(t *testing.T) {
+	pod := PodInfo{
+		Containers: []ContainerInfo{
+			{Resources: ResourceRequirements{CPURequest: "250m", MemoryRequest: "128Mi"}},
+			{Resources: ResourceRequirements{CPURequest: "0", MemoryRequest: ""}},
+		},
+		InitContainers: []ContainerInfo{
+			{Resources: ResourceRequirements{CPURequest: "1", MemoryRequest: "64Mi"}},
+		},
+	}
+
+	cpu, mem := podInfoRequests(pod)
+	if cpu != 1000 {
+		t.Errorf("cpu = %d, want 1000 (init container dominates)", cpu)
+	}
+	if mem != 128*1024*1024 {
+		t.Errorf("mem = %d, want 128Mi", mem)
+	}
+}
+
+func TestNodeFitHelper(t *testing.T) {
+	helper := NodeFitHelper([]NodeFit{
+		{Node: "node-a", Reason: "Insufficient cpu (300m short)"},
+		{Node: "node-b", Reason: "node cordoned"},
+	})
+	if helper.Severity != "High" {
+		t.Errorf("Severity = %q, want High when nothing fits", helper.Severity)
+	}
+	if helper.Issue != "Node Fit: 0/2 nodes fit this pod" {
+		t.Errorf("Issue = %q", helper.Issue)
+	}
+	if len(helper.Suggestions) != 2 || helper.Suggestions[0] != "node-a: Insufficient cpu (300m short)" {
+		t.Errorf("Suggestions = %v", helper.Suggestions)
+	}
+
+	helper = NodeFitHelper([]NodeFit{{Node: "node-a", Fits: true}})
+	if helper.Severity != "Info" {
+		t.Errorf("Severity = %q, want Info when a node fits", helper.Severity)
+	}
+}
+
+func TestPodToPodInfo_NodeAffinity(t *testing.T) {
+	pod := drainPod("web", "", "100m", "64Mi")
+	pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpExists}},
+			}},
+		},
+	}}
+
+	info := podToPodInfo(pod)
+	if info.NodeAffinity == nil || len(info.NodeAffinity.NodeSelectorTerms) != 1 {
+		t.Errorf("NodeAffinity = %+v, want required terms copied", info.NodeAffinity)
+	}
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// MemoryHeadroomWarnPercent is the share of its memory limit above which a
+// container is flagged as close to being OOM killed.
+const MemoryHeadroomWarnPercent = 90
+
+// RecentOOMWindow is how long after an OOM kill a recovered pod still
+// counts as recently OOM killed in the workloads table.
+const RecentOOMWindow = time.Hour
+
+// nodeOOMReasons are the reasons of the events the kubelet ("SystemOOM")
+// and node-problem-detector ("OOMKilling") record on a node when its
+// kernel kills a process for memory.
+var nodeOOMReasons = map[string]bool{
+	"SystemOOM":  true,
+	"OOMKilling": true,
+}
+
+// OOMReport is the OOM kill history of a pod's containers and how close
+// each is to its memory limit, with the OOM events of its node.
+type OOMReport struct {
+	Containers []ContainerOOM
+	NodeEvents []EventInfo // OOM events of the pod's node, most recent first
+}
+
+// ContainerOOM is the OOM kill history and memory headroom of a container.
+type ContainerOOM struct {
+	Name       string
+	Restarts   int32
+	LastOOM    *TerminationInfo // The latest instance killed for memory, nil when none is on record
+	Usage      string           // Current memory usage, "" without metrics
+	UsageBytes int64
+	Limit      string // Memory limit, "" when not set
+	LimitBytes int64
+	HasUsage   bool // metrics-server has sampled the container
+}
+
+// PercentOfLimit returns the memory usage as a percentage of the limit, or
+// 0 without a limit or usage.
+func (c ContainerOOM) PercentOfLimit() float64 {
+	if !c.HasUsage {
+		return 0
+	}
+	return percentOf(c.UsageBytes, c.LimitBytes)
+}
+
+// NearLimit reports whether the container uses more than
+// MemoryHeadroomWarnPercent of its memory limit.
+func (c ContainerOOM) NearLimit() bool {
+	return c.LimitBytes > 0 && c.PercentOfLimit() > MemoryHeadroomWarnPercent
+}
+
+// OOMKilled reports whether any container has an OOM kill on record or
+// the node reported OOM events.
+func (r *OOMReport) OOMKilled() bool {
+	if r == nil {
+		return false
+	}
+	for _, c := range r.Containers {
+		if c.LastOOM != nil {
+			return true
+		}
+	}
+	return len(r.NodeEvents) > 0
+}
+
+// NearLimit reports whether any container is close to its memory limit.
+func (r *OOMReport) NearLimit() bool {
+	if r == nil {
+		return false
+	}
+	for _, c := range r.Containers {
+		if c.NearLimit() {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildOOMReport joins the containers of a pod with their memory usage
+// from metrics, which may be nil, and the OOM events among nodeEvents,
+// the events of the pod's node. A container's last OOM kill is its current
+// state when it was just killed, else its last termination; Kubernetes
+// keeps only the latest one, so older kills show in the restart count
+// alone.
+func BuildOOMReport(pod *PodInfo, metrics *PodMetrics, nodeEvents []EventInfo) *OOMReport {
+	report := &OOMReport{}
+	if pod == nil {
+		return report
+	}
+
+	usage := make(map[string]ContainerMetrics)
+	if metrics != nil {
+		for _, cm := range metrics.Containers {
+			usage[cm.Name] = cm
+		}
+	}
+
+	for _, c := range pod.Containers {
+		row := ContainerOOM{
+			Name:     c.Name,
+			Restarts: c.RestartCount,
+			LastOOM:  lastOOMKill(c),
+		}
+		if q := parseSetQuantity(c.Resources.MemoryLimit); q != nil {
+			row.Limit, row.LimitBytes = c.Resources.MemoryLimit, q.Value()
+		}
+		if cm, ok := usage[c.Name]; ok {
+			row.Usage, row.UsageBytes, row.HasUsage = cm.MemoryUsage, cm.MemoryBytes, true
+		}
+		report.Containers = append(report.Containers, row)
+	}
+
+	for _, e := range nodeEvents {
+		if nodeOOMReasons[e.Reason] {
+			report.NodeEvents = append(report.NodeEvents, e)
+		}
+	}
+	sort.SliceStable(report.NodeEvents, func(i, j int) bool {
+		return report.NodeEvents[i].LastSeen.After(report.NodeEvents[j].LastSeen)
+	})
+	return report
+}
+
+// lastOOMKill returns how the latest instance of a container killed for
+// memory ended, nil when none is on record.
+func lastOOMKill(c ContainerInfo) *TerminationInfo {
+	if c.State == "Terminated" && c.Reason == "OOMKilled" {
+		t := &TerminationInfo{Reason: c.Reason, Message: c.Message, FinishedAt: c.FinishedAt}
+		if c.ExitCode != nil {
+			t.ExitCode = *c.ExitCode
+		}
+		return t
+	}
+	if c.LastTermination != nil && c.LastTermination.Reason == "OOMKilled" {
+		return c.LastTermination
+	}
+	return nil
+}
+
+// recentlyOOMKilled reports whether a container of pod was OOM killed
+// within RecentOOMWindow before now. A container in the OOMKilled state is
+// always recent; a last termination without a time is not.
+func recentlyOOMKilled(pod PodInfo, now time.Time) bool {
+	for _, c := range pod.Containers {
+		t := lastOOMKill(c)
+		switch {
+		case t == nil:
+		case c.State == "Terminated":
+			return true
+		case !t.Finished.IsZero() && now.Sub(t.Finished) < RecentOOMWindow:
+			return true
+		}
+	}
+	return false
+}
+
+// GetNodeEvents lists the events recorded on a node, most recent first.
+func GetNodeEvents(ctx context.Context, clientset kubernetes.Interface, node string) ([]EventInfo, error) {
+	events, err := clientset.CoreV1().Events("").List(ctx, metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=Node,involvedObject.name=" + node,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return eventsToEventInfo(events.Items), nil
+}
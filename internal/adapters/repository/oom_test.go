@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuildOOMReport(t *testing.T) {
+	pod := &PodInfo{
+		Name: "api-0",
+		Containers: []ContainerInfo{
+			{
+				Name:            "api",
+				RestartCount:    3,
+				State:           "Running",
+				LastTermination: &TerminationInfo{Reason: "OOMKilled", ExitCode: 137, FinishedAt: "2024-05-16 09:58:00"},
+				Resources:       ResourceRequirements{MemoryLimit: "256Mi"},
+			},
+			// No limit: usage has nothing to be a percentage of
+			{Name: "sidecar", State: "Running", Resources: ResourceRequirements{MemoryLimit: "0"}},
+			// Killed right now, with plenty of headroom left
+			{Name: "worker", State: "Terminated", Reason: "OOMKilled", ExitCode: new(int32), Resources: ResourceRequirements{MemoryLimit: "1Gi"}},
+		},
+	}
+	metrics := &PodMetrics{Containers: []ContainerMetrics{
+		{Name: "api", MemoryUsage: "240.0Mi", MemoryBytes: 240 << 20},
+		{Name: "sidecar", MemoryUsage: "900.0Mi", MemoryBytes: 900 << 20},
+		{Name: "worker", MemoryUsage: "100.0Mi", MemoryBytes: 100 << 20},
+	}}
+	now := time.Date(2024, 5, 16, 10, 0, 0, 0, time.UTC)
+	nodeEvents := []EventInfo{
+		{Reason: "NodeReady"},
+		{Reason: "SystemOOM", Message: "System OOM encountered, victim process: java", LastSeen: now.Add(-time.Hour)},
+		{Reason: "OOMKilling", Message: "Out of memory: Killed process 4242 (java)", LastSeen: now},
+	}
+
+	report := BuildOOMReport(pod, metrics, nodeEvents)
+	if len(report.Containers) != 3 {
+		t.Fatalf("got %d containers, want 3", len(report.Containers))
+	}
+
+	api := report.Containers[0]
+	if api.LastOOM == nil || api.LastOOM.ExitCode != 137 || api.Restarts != 3 {
+		t.Errorf("api = %+v, want its last OOM kill and 3 restarts", api)
+	}
+	if api.LimitBytes != 256<<20 || int(api.PercentOfLimit()) != 93 || !api.NearLimit() {
+		t.Errorf("api uses %.1f%% of %d bytes, want 93%% and near the limit", api.PercentOfLimit(), api.LimitBytes)
+	}
+
+	sidecar := report.Containers[1]
+	if sidecar.Limit != "" || sidecar.PercentOfLimit() != 0 || sidecar.NearLimit() || sidecar.LastOOM != nil {
+		t.Errorf("sidecar = %+v, want no limit and no headroom warning", sidecar)
+	}
+	if !sidecar.HasUsage || sidecar.Usage != "900.0Mi" {
+		t.Errorf("sidecar usage = %q, want it shown without a limit", sidecar.Usage)
+	}
+
+	worker := report.Containers[2]
+	if worker.LastOOM == nil || worker.LastOOM.Reason != "OOMKilled" || worker.NearLimit() {
+		t.Errorf("worker = %+v, want the current OOM kill and no headroom warning", worker)
+	}
+
+	if len(report.NodeEvents) != 2 || report.NodeEvents[0].Reason != "OOMKilling" {
+		t.Errorf("node events = %+v, want the two OOM events, latest first", report.NodeEvents)
+	}
+	if !report.OOMKilled() || !report.NearLimit() {
+		t.Errorf("OOMKilled() = %v, NearLimit() = %v, want both", report.OOMKilled(), report.NearLimit())
+	}
+}
+
+func TestBuildOOMReport_WithoutMetrics(t *testing.T) {
+	pod := &PodInfo{Containers: []ContainerInfo{
+		{Name: "app", State: "Running", Resources: ResourceRequirements{MemoryLimit: "128Mi"}},
+	}}
+
+	report := BuildOOMReport(pod, nil, nil)
+	app := report.Containers[0]
+	if app.HasUsage || app.PercentOfLimit() != 0 || app.NearLimit() {
+		t.Errorf("app = %+v, want no usage without metrics", app)
+	}
+	if report.OOMKilled() || report.NearLimit() {
+		t.Error("a pod never OOM killed and without usage should not be flagged")
+	}
+	if (*OOMReport)(nil).OOMKilled() {
+		t.Error("a nil report should not be OOM killed")
+	}
+}
+
+func TestGetNodeEvents(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "node-a.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "node-a"},
+		Reason:         "OOMKilling",
+		Message:        "Out of memory: Killed process 4242 (java)",
+	})
+
+	events, err := GetNodeEvents(context.Background(), clientset, "node-a")
+	if err != nil {
+		t.Fatalf("GetNodeEvents failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Reason != "OOMKilling" {
+		t.Errorf("events = %+v, want the OOMKilling event", events)
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PDBInfo summarizes a PodDisruptionBudget. Exactly one of MinAvailable and
+// MaxUnavailable is set, as an integer or a percentage.
+type PDBInfo struct {
+	Name               string
+	Namespace          string
+	MinAvailable       string // e.g. "2" or "50%"; empty if MaxUnavailable is used
+	MaxUnavailable     string // e.g. "1" or "25%"; empty if MinAvailable is used
+	CurrentHealthy     int32
+	DesiredHealthy     int32
+	ExpectedPods       int32
+	DisruptionsAllowed int32 // 0 blocks evictions, and so drains
+	Selector           *metav1.LabelSelector
+}
+
+// Blocking reports whether the budget currently refuses every eviction,
+// which is what leaves node drains stuck.
+func (p PDBInfo) Blocking() bool {
+	return p.DisruptionsAllowed == 0
+}
+
+// ListPDBs lists the PodDisruptionBudgets of a namespace.
+func ListPDBs(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]PDBInfo, error) {
+	pdbs, err := clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list poddisruptionbudgets: %w", err)
+	}
+	result := make([]PDBInfo, 0, len(pdbs.Items))
+	for _, pdb := range pdbs.Items {
+		result = append(result, pdbToPDBInfo(pdb))
+	}
+	return result, nil
+}
+
+func pdbToPDBInfo(pdb policyv1.PodDisruptionBudget) PDBInfo {
+	info := PDBInfo{
+		Name:               pdb.Name,
+		Namespace:          pdb.Namespace,
+		CurrentHealthy:     pdb.Status.CurrentHealthy,
+		DesiredHealthy:     pdb.Status.DesiredHealthy,
+		ExpectedPods:       pdb.Status.ExpectedPods,
+		DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+		Selector:           pdb.Spec.Selector,
+	}
+	if pdb.Spec.MinAvailable != nil {
+		info.MinAvailable = pdb.Spec.MinAvailable.String()
+	}
+	if pdb.Spec.MaxUnavailable != nil {
+		info.MaxUnavailable = pdb.Spec.MaxUnavailable.String()
+	}
+	return info
+}
+
+// MatchingPDB returns the first budget whose selector matches podLabels, or
+// nil. In policy/v1 an empty selector matches every pod of the namespace,
+// while a missing one matches none.
+func MatchingPDB(pdbs []PDBInfo, podLabels map[string]string) *PDBInfo {
+	for i := range pdbs {
+		if pdbSelects(pdbs[i].Selector, podLabels) {
+			return &pdbs[i]
+		}
+	}
+	return nil
+}
+
+func pdbSelects(selector *metav1.LabelSelector, podLabels map[string]string) bool {
+	if selector == nil {
+		return false
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(labels.Set(podLabels))
+}
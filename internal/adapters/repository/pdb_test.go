@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func pdb(name string, selector *metav1.LabelSelector, minAvailable, maxUnavailable *intstr.IntOrString, allowed int32) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector:       selector,
+			MinAvailable:   minAvailable,
+			MaxUnavailable: maxUnavailable,
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{
+			CurrentHealthy:     3,
+			DesiredHealthy:     2,
+			ExpectedPods:       3,
+			DisruptionsAllowed: allowed,
+		},
+	}
+}
+
+func TestListPDBs_MinAvailableAndMaxUnavailable(t *testing.T) {
+	two := intstr.FromInt(2)
+	quarter := intstr.FromString("25%")
+	clientset := fake.NewSimpleClientset(
+		pdb("web-min", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}, &two, nil, 1),
+		pdb("db-max", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}, nil, &quarter, 0),
+	)
+
+	pdbs, err := ListPDBs(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatalf("ListPDBs() error = %v", err)
+	}
+	byName := map[string]PDBInfo{}
+	for _, p := range pdbs {
+		byName[p.Name] = p
+	}
+	if got := byName["web-min"]; got.MinAvailable != "2" || got.MaxUnavailable != "" || got.DisruptionsAllowed != 1 || got.Blocking() {
+		t.Errorf("web-min = %+v, want minAvailable 2 and 1 disruption allowed", got)
+	}
+	if got := byName["db-max"]; got.MaxUnavailable != "25%" || got.MinAvailable != "" || !got.Blocking() {
+		t.Errorf("db-max = %+v, want maxUnavailable 25%% and blocking", got)
+	}
+	if got := byName["web-min"]; got.CurrentHealthy != 3 || got.DesiredHealthy != 2 {
+		t.Errorf("web-min health = %d/%d, want 3/2", got.CurrentHealthy, got.DesiredHealthy)
+	}
+}
+
+func TestMatchingPDB(t *testing.T) {
+	pdbs := []PDBInfo{
+		{Name: "no-selector"},
+		{Name: "db", Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+		{Name: "frontend", Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"frontend"}},
+		}}},
+	}
+	if got := MatchingPDB(pdbs, webPod.Labels); got == nil || got.Name != "frontend" {
+		t.Errorf("MatchingPDB() = %+v, want frontend", got)
+	}
+	if got := MatchingPDB(pdbs, map[string]string{"app": "cache"}); got != nil {
+		t.Errorf("MatchingPDB() = %+v, want nil: a missing selector matches nothing", got)
+	}
+
+	// In policy/v1 an empty selector matches every pod
+	all := []PDBInfo{{Name: "all", Selector: &metav1.LabelSelector{}}}
+	if got := MatchingPDB(all, map[string]string{"app": "cache"}); got == nil {
+		t.Error("an empty selector should match every pod")
+	}
+}
+
+func TestGetRelatedResources_PDB(t *testing.T) {
+	one := intstr.FromInt(1)
+	clientset := fake.NewSimpleClientset(
+		pdb("web", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}, nil, &one, 0),
+	)
+	related, err := GetRelatedResources(context.Background(), clientset, nil, webPod)
+	if err != nil {
+		t.Fatalf("GetRelatedResources() error = %v", err)
+	}
+	if related.PDB == nil || related.PDB.Name != "web" || !related.PDB.Blocking() {
+		t.Errorf("PDB = %+v, want the blocking web budget", related.PDB)
+	}
+
+	related, err = GetRelatedResources(context.Background(), fake.NewSimpleClientset(), nil, webPod)
+	if err != nil {
+		t.Fatalf("GetRelatedResources() error = %v", err)
+	}
+	if related.PDB != nil {
+		t.Errorf("PDB = %+v, want nil without budgets", related.PDB)
+	}
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// bulkDeleteConcurrency caps how many pods DeletePods deletes at once. A
+// variable so tests can lower it.
+var bulkDeleteConcurrency = 5
+
+// PodDeleteResult is the outcome of deleting one pod of a bulk delete.
+type PodDeleteResult struct {
+	Namespace string
+	Name      string
+	Err       error // nil when the pod was deleted or already gone
+}
+
+// DeletePods deletes pods of a namespace, at most bulkDeleteConcurrency at
+// a time, calling onResult (when not nil) as each delete finishes, one call
+// at a time. Unlike a single delete it does not stop at the first failure:
+// every pod is attempted. A pod already gone counts as deleted. Results are
+// in the order of names; the error joins the failures, nil when every pod
+// was deleted.
+func DeletePods(ctx context.Context, clientset kubernetes.Interface, namespace string, names []string, onResult func(PodDeleteResult)) ([]PodDeleteResult, error) {
+	results := make([]PodDeleteResult, len(names))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, max(bulkDeleteConcurrency, 1))
+	for i, name := range names {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int, name string) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			err := DeletePod(ctx, clientset, namespace, name)
+			if apierrors.IsNotFound(err) {
+				err = nil
+			}
+			result := PodDeleteResult{Namespace: namespace, Name: name, Err: err}
+			mu.Lock()
+			defer mu.Unlock()
+			results[i] = result
+			if onResult != nil {
+				onResult(result)
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("pod %s: %w", r.Name, r.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
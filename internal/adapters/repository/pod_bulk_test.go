@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestDeletePods_PartialFailure(t *testing.T) {
+	concurrency := bulkDeleteConcurrency
+	bulkDeleteConcurrency = 2
+	t.Cleanup(func() { bulkDeleteConcurrency = concurrency })
+
+	var objects []runtime.Object
+	for _, name := range []string{"web-1", "web-2", "web-3", "locked"} {
+		objects = append(objects, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}})
+	}
+	clientset := fake.NewSimpleClientset(objects...)
+	clientset.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.DeleteAction).GetName() == "locked" {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "locked", errors.New("denied"))
+		}
+		return false, nil, nil
+	})
+
+	var reported int
+	names := []string{"web-1", "locked", "web-2", "gone", "web-3"}
+	results, err := DeletePods(context.Background(), clientset, "default", names, func(PodDeleteResult) { reported++ })
+	if err == nil || !strings.Contains(err.Error(), "pod locked") {
+		t.Fatalf("DeletePods() error = %v, want the failure of locked", err)
+	}
+	if !apierrors.IsForbidden(err) {
+		t.Errorf("DeletePods() error = %v, want the API error kept", err)
+	}
+	if reported != len(names) {
+		t.Errorf("onResult called %d times, want %d", reported, len(names))
+	}
+	for i, r := range results {
+		if r.Name != names[i] {
+			t.Errorf("results[%d] = %s, want the order of names", i, r.Name)
+		}
+		if (r.Err != nil) != (r.Name == "locked") {
+			t.Errorf("results[%d] = %s: %v, want only locked to fail (a pod already gone counts as deleted)", i, r.Name, r.Err)
+		}
+	}
+
+	pods, _ := clientset.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+	if len(pods.Items) != 1 || pods.Items[0].Name != "locked" {
+		t.Errorf("pods left = %v, want only locked", pods.Items)
+	}
+}
+
+func TestDeletePods_AllDeleted(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}})
+	results, err := DeletePods(context.Background(), clientset, "default", []string{"web-1"}, nil)
+	if err != nil || len(results) != 1 || results[0].Err != nil {
+		t.Errorf("DeletePods() = %+v, %v, want web-1 deleted", results, err)
+	}
+}
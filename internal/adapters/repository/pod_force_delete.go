@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DeletePodForce deletes a pod without waiting for the kubelet to confirm
+// its containers stopped, as kubectl delete --grace-period=0 --force does.
+// This is for pods stuck terminating on a node that is gone. The delete is
+// preconditioned on the pod's UID, so a pod recreated under the same name
+// meanwhile, as StatefulSet pods are, is never deleted instead.
+func DeletePodForce(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod: %w", err)
+	}
+	grace := int64(0)
+	uid := pod.UID
+	err = clientset.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		GracePeriodSeconds: &grace,
+		Preconditions:      &metav1.Preconditions{UID: &uid},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to force delete pod: %w", err)
+	}
+	return nil
+}
+
+// PodStuckTerminating reports whether a pod being deleted is still there
+// after its grace period. The API server sets DeletionTimestamp to when the
+// grace period ends, so past it the kubelet should have stopped the pod;
+// it usually hasn't because its node is unreachable.
+func PodStuckTerminating(pod *PodInfo, now time.Time) bool {
+	return pod != nil && !pod.DeletionTimestamp.IsZero() && now.After(pod.DeletionTimestamp)
+}
+
+// PodStillPresent reports whether the pod with uid still exists, as after
+// a delete that its kubelet never finished. An empty uid matches any pod
+// with the name.
+func PodStillPresent(ctx context.Context, clientset kubernetes.Interface, namespace, name, uid string) (bool, error) {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get pod: %w", err)
+	}
+	return uid == "" || pod.UID == types.UID(uid), nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestDeletePodForce(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", UID: "uid-1"},
+	})
+	var opts *metav1.DeleteOptions
+	clientset.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		o := action.(k8stesting.DeleteAction).GetDeleteOptions()
+		opts = &o
+		return false, nil, nil
+	})
+
+	ctx := context.Background()
+	if err := DeletePodForce(ctx, clientset, "default", "web-0"); err != nil {
+		t.Fatalf("DeletePodForce() error = %v", err)
+	}
+	if opts == nil {
+		t.Fatal("DeletePodForce() did not delete the pod")
+	}
+	if opts.GracePeriodSeconds == nil || *opts.GracePeriodSeconds != 0 {
+		t.Errorf("GracePeriodSeconds = %v, want 0", opts.GracePeriodSeconds)
+	}
+	if opts.Preconditions == nil || opts.Preconditions.UID == nil || *opts.Preconditions.UID != "uid-1" {
+		t.Errorf("Preconditions = %+v, want UID uid-1", opts.Preconditions)
+	}
+	if _, err := clientset.CoreV1().Pods("default").Get(ctx, "web-0", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("pod should be gone, Get() error = %v", err)
+	}
+
+	if err := DeletePodForce(ctx, clientset, "default", "web-0"); !apierrors.IsNotFound(err) {
+		t.Errorf("DeletePodForce() of a missing pod error = %v, want NotFound", err)
+	}
+}
+
+func TestPodStuckTerminating(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		pod  *PodInfo
+		want bool
+	}{
+		{"not being deleted", &PodInfo{}, false},
+		{"within its grace period", &PodInfo{DeletionTimestamp: now.Add(10 * time.Second)}, false},
+		{"past its grace period", &PodInfo{DeletionTimestamp: now.Add(-time.Minute)}, true},
+		{"no pod", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PodStuckTerminating(tt.pod, now); got != tt.want {
+				t.Errorf("PodStuckTerminating() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The deadline comes from the pod's deletionTimestamp
+	deleting := metav1.NewTime(now.Add(-time.Minute))
+	info := podToPodInfo(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", UID: "uid-1", DeletionTimestamp: &deleting}})
+	if !PodStuckTerminating(&info, now) || info.UID != "uid-1" {
+		t.Errorf("podToPodInfo() = UID %q, DeletionTimestamp %v", info.UID, info.DeletionTimestamp)
+	}
+}
+
+func TestPodStillPresent(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", UID: "uid-2"},
+	})
+	ctx := context.Background()
+	tests := []struct {
+		name, pod, uid string
+		want           bool
+	}{
+		{"same pod", "web-0", "uid-2", true},
+		{"any pod with the name", "web-0", "", true},
+		{"replaced under the same name", "web-0", "uid-1", false},
+		{"gone", "web-1", "uid-3", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PodStillPresent(ctx, clientset, "default", tt.pod, tt.uid)
+			if err != nil || got != tt.want {
+				t.Errorf("PodStillPresent() = %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+}
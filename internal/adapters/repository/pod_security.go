@@ -0,0 +1,426 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Labels of a namespace setting its Pod Security Admission levels.
+const (
+	podSecurityEnforceLabel        = "pod-security.kubernetes.io/enforce"
+	podSecurityEnforceVersionLabel = "pod-security.kubernetes.io/enforce-version"
+	podSecurityAuditLabel          = "pod-security.kubernetes.io/audit"
+	podSecurityWarnLabel           = "pod-security.kubernetes.io/warn"
+)
+
+// appArmorAnnotationPrefix, followed by a container name, is the
+// annotation setting the container's AppArmor profile.
+const appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+// PodSecurityLevel is a Pod Security Standards profile.
+type PodSecurityLevel string
+
+const (
+	PodSecurityPrivileged PodSecurityLevel = "privileged"
+	PodSecurityBaseline   PodSecurityLevel = "baseline"
+	PodSecurityRestricted PodSecurityLevel = "restricted"
+)
+
+// podSecurityRank orders the levels from the most permissive.
+var podSecurityRank = map[PodSecurityLevel]int{
+	PodSecurityPrivileged: 0,
+	PodSecurityBaseline:   1,
+	PodSecurityRestricted: 2,
+}
+
+// baselineCapabilities are the capabilities the baseline level lets
+// containers add, those container runtimes grant by default.
+var baselineCapabilities = []string{
+	"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+	"NET_BIND_SERVICE", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+}
+
+// PodSecurityAdmission holds the Pod Security Admission levels a
+// namespace's labels set, "" for those not set.
+type PodSecurityAdmission struct {
+	Enforce        PodSecurityLevel // Pods violating it are rejected
+	EnforceVersion string           // Version of the standards enforced, "" for the latest
+	Audit          PodSecurityLevel // Violations are recorded in the audit log
+	Warn           PodSecurityLevel // Violations are returned as warnings
+}
+
+// PodSecurityAdmissionFor reads the Pod Security Admission levels from a
+// namespace's labels.
+func PodSecurityAdmissionFor(labels map[string]string) PodSecurityAdmission {
+	return PodSecurityAdmission{
+		Enforce:        PodSecurityLevel(labels[podSecurityEnforceLabel]),
+		EnforceVersion: labels[podSecurityEnforceVersionLabel],
+		Audit:          PodSecurityLevel(labels[podSecurityAuditLabel]),
+		Warn:           PodSecurityLevel(labels[podSecurityWarnLabel]),
+	}
+}
+
+// GetPodSecurityAdmission returns the Pod Security Admission levels of a
+// namespace.
+func GetPodSecurityAdmission(ctx context.Context, clientset kubernetes.Interface, namespace string) (PodSecurityAdmission, error) {
+	ns, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return PodSecurityAdmission{}, fmt.Errorf("failed to get namespace: %w", err)
+	}
+	return PodSecurityAdmissionFor(ns.Labels), nil
+}
+
+// ContainerSecurity is the effective security context of a container: its
+// own settings, else those of the pod.
+type ContainerSecurity struct {
+	Container                string
+	Init                     bool
+	RunAsUser                *int64 // nil: the image's USER
+	RunAsGroup               *int64 // nil: the image's group
+	RunAsNonRoot             bool
+	FSGroup                  *int64 // From the pod only
+	SupplementalGroups       []int64
+	Privileged               bool
+	AllowPrivilegeEscalation *bool // nil: allowed, the default
+	ReadOnlyRoot             bool
+	CapabilitiesAdd          []string
+	CapabilitiesDrop         []string
+	SeccompProfile           string   // "" when neither the container nor the pod sets one
+	AppArmorProfile          string   // From the pod's annotation, "" when not set
+	FromPod                  []string // Settings inherited from the pod, e.g. "runAsUser"
+	Conflicts                []string // Settings that keep the container from running, with the fix
+}
+
+// EffectiveSecurityContext merges the security context of a container of
+// the pod with the pod's, the container's settings winning.
+func EffectiveSecurityContext(pod *PodInfo, c ContainerInfo) ContainerSecurity {
+	s := ContainerSecurity{Container: c.Name, AppArmorProfile: pod.Annotations[appArmorAnnotationPrefix+c.Name]}
+	own := c.SecurityContext
+	if own == nil {
+		own = &SecurityContextInfo{}
+	}
+	podLevel := pod.SecurityContext
+	if podLevel == nil {
+		podLevel = &PodSecurityContext{}
+	}
+
+	s.RunAsUser = own.RunAsUser
+	if s.RunAsUser == nil && podLevel.RunAsUser != nil {
+		s.RunAsUser = podLevel.RunAsUser
+		s.FromPod = append(s.FromPod, "runAsUser")
+	}
+	s.RunAsGroup = own.RunAsGroup
+	if s.RunAsGroup == nil && podLevel.RunAsGroup != nil {
+		s.RunAsGroup = podLevel.RunAsGroup
+		s.FromPod = append(s.FromPod, "runAsGroup")
+	}
+	runAsNonRoot := own.RunAsNonRoot
+	if runAsNonRoot == nil && podLevel.RunAsNonRoot != nil {
+		runAsNonRoot = podLevel.RunAsNonRoot
+		s.FromPod = append(s.FromPod, "runAsNonRoot")
+	}
+	s.RunAsNonRoot = runAsNonRoot != nil && *runAsNonRoot
+	s.SeccompProfile = own.SeccompProfile
+	if s.SeccompProfile == "" && podLevel.SeccompProfile != "" {
+		s.SeccompProfile = podLevel.SeccompProfile
+		s.FromPod = append(s.FromPod, "seccompProfile")
+	}
+	s.FSGroup = podLevel.FSGroup
+	s.SupplementalGroups = podLevel.SupplementalGroups
+
+	s.Privileged = own.Privileged != nil && *own.Privileged
+	s.AllowPrivilegeEscalation = own.AllowPrivilegeEscalation
+	s.ReadOnlyRoot = own.ReadOnlyRoot != nil && *own.ReadOnlyRoot
+	s.CapabilitiesAdd = own.CapabilitiesAdd
+	s.CapabilitiesDrop = own.CapabilitiesDrop
+	return s
+}
+
+// PodSecurityReport is the effective security context of a pod's
+// containers, and how the pod fares against its namespace's Pod Security
+// Admission.
+type PodSecurityReport struct {
+	Containers []ContainerSecurity // Init containers first
+	Admission  PodSecurityAdmission
+	Violations []PodSecurityViolation // Checks of the enforced level the pod fails
+}
+
+// HasIssues reports whether a container can't run for its security
+// context or the pod violates the enforced level.
+func (r *PodSecurityReport) HasIssues() bool {
+	if r == nil {
+		return false
+	}
+	for _, c := range r.Containers {
+		if len(c.Conflicts) > 0 {
+			return true
+		}
+	}
+	return len(r.Violations) > 0
+}
+
+// BuildPodSecurity merges the security context of each of the pod's init
+// and regular containers with the pod's, flags the settings its events and
+// container states show to conflict with the image or the workload, and
+// checks the pod against the level admission enforces.
+func BuildPodSecurity(pod *PodInfo, events []EventInfo, admission PodSecurityAdmission) *PodSecurityReport {
+	report := &PodSecurityReport{Admission: admission}
+	if pod == nil {
+		return report
+	}
+	for _, c := range pod.InitContainers {
+		s := EffectiveSecurityContext(pod, c)
+		s.Init = true
+		s.Conflicts = securityConflicts(c, s, events)
+		report.Containers = append(report.Containers, s)
+	}
+	for _, c := range pod.Containers {
+		s := EffectiveSecurityContext(pod, c)
+		s.Conflicts = securityConflicts(c, s, events)
+		report.Containers = append(report.Containers, s)
+	}
+	report.Violations = CheckPodSecurity(pod, admission.Enforce)
+	return report
+}
+
+// nonNumericUserPattern extracts the user from the kubelet's error for an
+// image whose USER is a name while runAsNonRoot is set.
+var nonNumericUserPattern = regexp.MustCompile(`non-numeric user \(([^)]*)\)`)
+
+// securityConflicts lists what keeps the container from running under its
+// security context, from the kubelet's CreateContainerConfigError (in the
+// container state, or in a Failed event naming the container) and from
+// what the container reported when it last stopped.
+func securityConflicts(c ContainerInfo, s ContainerSecurity, events []EventInfo) []string {
+	var messages []string
+	if c.Reason == "CreateContainerConfigError" {
+		messages = append(messages, c.Message)
+	}
+	for _, e := range events {
+		if e.Reason == "Failed" && strings.Contains(e.Message, "container: "+c.Name+")") {
+			messages = append(messages, e.Message)
+		}
+	}
+	configError := strings.Join(messages, "\n")
+
+	var conflicts []string
+	switch {
+	case strings.Contains(configError, "image will run as root"):
+		conflicts = append(conflicts, "image runs as root but runAsNonRoot is true: set runAsUser to a non-zero UID or build the image with a non-root USER")
+	case nonNumericUserPattern.MatchString(configError):
+		user := nonNumericUserPattern.FindStringSubmatch(configError)[1]
+		conflicts = append(conflicts, fmt.Sprintf("image USER %q is not numeric, so runAsNonRoot can't be verified: set runAsUser to its UID", user))
+	case s.RunAsNonRoot && s.RunAsUser != nil && *s.RunAsUser == 0,
+		strings.Contains(configError, "runAsUser breaks non-root policy"):
+		conflicts = append(conflicts, "runAsUser is 0 but runAsNonRoot is true: set runAsUser to a non-zero UID")
+	}
+
+	if s.ReadOnlyRoot {
+		stopped := c.Message
+		if c.LastTermination != nil {
+			stopped += "\n" + c.LastTermination.Message
+		}
+		if strings.Contains(strings.ToLower(stopped), "read-only file system") {
+			conflicts = append(conflicts, "wrote to its read-only root filesystem: mount an emptyDir where it writes")
+		}
+	}
+
+	if !hasCapability(s.CapabilitiesAdd, "NET_BIND_SERVICE") &&
+		(hasCapability(s.CapabilitiesDrop, "NET_BIND_SERVICE") || (s.RunAsUser != nil && *s.RunAsUser != 0)) {
+		for _, p := range c.Ports {
+			if p.ContainerPort < 1024 {
+				conflicts = append(conflicts, fmt.Sprintf("port %d is below 1024 without NET_BIND_SERVICE: binding it fails unless the runtime allows unprivileged ports, add the capability or use a higher port", p.ContainerPort))
+				break
+			}
+		}
+	}
+	return conflicts
+}
+
+// hasCapability reports whether caps hold capability, or ALL. Names are
+// matched with or without their CAP_ prefix, in any case.
+func hasCapability(caps []string, capability string) bool {
+	for _, c := range caps {
+		c = strings.TrimPrefix(strings.ToUpper(c), "CAP_")
+		if c == capability || c == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+// PodSecurityViolation is a Pod Security Standards check a pod fails, e.g.
+// `allowPrivilegeEscalation != false (container "app" must set
+// securityContext.allowPrivilegeEscalation=false)`.
+type PodSecurityViolation struct {
+	Level  PodSecurityLevel // Lowest level with the check
+	Check  string
+	Detail string
+}
+
+// String formats the violation as admission warns about it.
+func (v PodSecurityViolation) String() string {
+	return v.Check + " (" + v.Detail + ")"
+}
+
+// CheckPodSecurity returns the checks of level the pod fails, covering the
+// common baseline and restricted rules: host namespaces, ports and paths,
+// privileged containers, capabilities, seccomp and AppArmor profiles,
+// privilege escalation and running as root. An unknown or unset level
+// checks nothing, as does privileged.
+func CheckPodSecurity(pod *PodInfo, level PodSecurityLevel) []PodSecurityViolation {
+	rank, ok := podSecurityRank[level]
+	if pod == nil || !ok || rank == 0 {
+		return nil
+	}
+
+	var all []ContainerInfo
+	all = append(all, pod.InitContainers...)
+	all = append(all, pod.Containers...)
+	all = append(all, pod.EphemeralContainers...)
+	containers := make([]ContainerSecurity, len(all))
+	for i, c := range all {
+		containers[i] = EffectiveSecurityContext(pod, c)
+	}
+	// failing lists the containers for which fails holds
+	failing := func(fails func(c ContainerInfo, s ContainerSecurity) bool) []string {
+		var names []string
+		for i, c := range all {
+			if fails(c, containers[i]) {
+				names = append(names, c.Name)
+			}
+		}
+		return names
+	}
+
+	var violations []PodSecurityViolation
+	add := func(failed PodSecurityLevel, check, detail string) {
+		violations = append(violations, PodSecurityViolation{Level: failed, Check: check, Detail: detail})
+	}
+
+	// Baseline
+	if len(pod.HostNamespaces) > 0 {
+		var set []string
+		for _, ns := range pod.HostNamespaces {
+			field := map[string]string{"network": "hostNetwork", "PID": "hostPID", "IPC": "hostIPC"}[ns]
+			set = append(set, field+"=true")
+		}
+		add(PodSecurityBaseline, "host namespaces", strings.Join(set, ", "))
+	}
+	if names := failing(func(_ ContainerInfo, s ContainerSecurity) bool { return s.Privileged }); len(names) > 0 {
+		add(PodSecurityBaseline, "privileged", containersMust(names, "must not set securityContext.privileged=true"))
+	}
+	var extraCaps []string
+	capNames := failing(func(_ ContainerInfo, s ContainerSecurity) bool {
+		found := false
+		for _, c := range s.CapabilitiesAdd {
+			if !containsString(baselineCapabilities, strings.TrimPrefix(strings.ToUpper(c), "CAP_")) {
+				if !containsString(extraCaps, c) {
+					extraCaps = append(extraCaps, c)
+				}
+				found = true
+			}
+		}
+		return found
+	})
+	if len(capNames) > 0 {
+		add(PodSecurityBaseline, "non-default capabilities",
+			containersMust(capNames, "must not include "+quoteAll(extraCaps)+" in securityContext.capabilities.add"))
+	}
+	var hostPaths []string
+	for _, v := range pod.Volumes {
+		if v.Type == "HostPath" {
+			hostPaths = append(hostPaths, v.Name)
+		}
+	}
+	if len(hostPaths) > 0 {
+		add(PodSecurityBaseline, "hostPath volumes", plural(len(hostPaths), "volume", "volumes")+" "+quoteAll(hostPaths))
+	}
+	var hostPorts []string
+	portNames := failing(func(c ContainerInfo, _ ContainerSecurity) bool {
+		found := false
+		for _, p := range c.Ports {
+			if p.HostPort != 0 {
+				hostPorts = append(hostPorts, fmt.Sprint(p.HostPort))
+				found = true
+			}
+		}
+		return found
+	})
+	if len(portNames) > 0 {
+		add(PodSecurityBaseline, "hostPort", containersMust(portNames, "uses "+plural(len(hostPorts), "hostPort", "hostPorts")+" "+strings.Join(hostPorts, ", ")))
+	}
+	if names := failing(func(_ ContainerInfo, s ContainerSecurity) bool {
+		p := s.AppArmorProfile
+		return p != "" && p != "runtime/default" && !strings.HasPrefix(p, "localhost/")
+	}); len(names) > 0 {
+		add(PodSecurityBaseline, "forbidden AppArmor profile", containersMust(names, "must not set an AppArmor profile other than runtime/default or localhost/*"))
+	}
+	if names := failing(func(_ ContainerInfo, s ContainerSecurity) bool { return s.SeccompProfile == "Unconfined" }); len(names) > 0 {
+		add(PodSecurityBaseline, "seccompProfile", "pod or "+containersMust(names, `must not set securityContext.seccompProfile.type to "Unconfined"`))
+	}
+	if rank < podSecurityRank[PodSecurityRestricted] {
+		return violations
+	}
+
+	// Restricted
+	if names := failing(func(_ ContainerInfo, s ContainerSecurity) bool {
+		return s.AllowPrivilegeEscalation == nil || *s.AllowPrivilegeEscalation
+	}); len(names) > 0 {
+		add(PodSecurityRestricted, "allowPrivilegeEscalation != false", containersMust(names, "must set securityContext.allowPrivilegeEscalation=false"))
+	}
+	if names := failing(func(_ ContainerInfo, s ContainerSecurity) bool {
+		if !containsString(s.CapabilitiesDrop, "ALL") {
+			return true
+		}
+		for _, c := range s.CapabilitiesAdd {
+			if strings.TrimPrefix(strings.ToUpper(c), "CAP_") != "NET_BIND_SERVICE" {
+				return true
+			}
+		}
+		return false
+	}); len(names) > 0 {
+		add(PodSecurityRestricted, "unrestricted capabilities",
+			containersMust(names, `must set securityContext.capabilities.drop=["ALL"] and add only "NET_BIND_SERVICE"`))
+	}
+	if names := failing(func(_ ContainerInfo, s ContainerSecurity) bool { return !s.RunAsNonRoot }); len(names) > 0 {
+		add(PodSecurityRestricted, "runAsNonRoot != true", "pod or "+containersMust(names, "must set securityContext.runAsNonRoot=true"))
+	}
+	if names := failing(func(_ ContainerInfo, s ContainerSecurity) bool { return s.RunAsUser != nil && *s.RunAsUser == 0 }); len(names) > 0 {
+		add(PodSecurityRestricted, "runAsUser=0", "pod or "+containersMust(names, "must not set runAsUser=0"))
+	}
+	if names := failing(func(_ ContainerInfo, s ContainerSecurity) bool {
+		return s.SeccompProfile != "RuntimeDefault" && !strings.HasPrefix(s.SeccompProfile, "Localhost") && s.SeccompProfile != "Unconfined"
+	}); len(names) > 0 {
+		add(PodSecurityRestricted, "seccompProfile", "pod or "+containersMust(names, `must set securityContext.seccompProfile.type to "RuntimeDefault" or "Localhost"`))
+	}
+	return violations
+}
+
+// containersMust words what the named containers must do, e.g.
+// `containers "app", "sidecar" must set ...`.
+func containersMust(names []string, what string) string {
+	return plural(len(names), "container", "containers") + " " + quoteAll(names) + " " + what
+}
+
+// quoteAll quotes each of values and joins them with commas.
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// plural returns singular when n is 1, else pluralForm.
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
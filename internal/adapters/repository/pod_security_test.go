@@ -0,0 +1,399 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// restrictedContainer is a container meeting the restricted level on its own.
+func restrictedContainer(name string) ContainerInfo {
+	return ContainerInfo{Name: name, SecurityContext: &SecurityContextInfo{
+		RunAsNonRoot:             boolPtr(true),
+		AllowPrivilegeEscalation: boolPtr(false),
+		CapabilitiesDrop:         []string{"ALL"},
+		SeccompProfile:           "RuntimeDefault",
+	}}
+}
+
+// withSecurity returns c with its security context changed by edit.
+func withSecurity(c ContainerInfo, edit func(sc *SecurityContextInfo)) ContainerInfo {
+	sc := *c.SecurityContext
+	edit(&sc)
+	c.SecurityContext = &sc
+	return c
+}
+
+func TestCheckPodSecurity(t *testing.T) {
+	tests := []struct {
+		name  string
+		pod   PodInfo
+		level PodSecurityLevel
+		want  []string // Checks failed
+	}{
+		{
+			name:  "restricted container",
+			pod:   PodInfo{Containers: []ContainerInfo{restrictedContainer("app")}},
+			level: PodSecurityRestricted,
+		},
+		{
+			name: "restricted through the pod's security context",
+			pod: PodInfo{
+				SecurityContext: &PodSecurityContext{RunAsNonRoot: boolPtr(true), SeccompProfile: "Localhost/profiles/app.json"},
+				Containers: []ContainerInfo{{Name: "app", SecurityContext: &SecurityContextInfo{
+					AllowPrivilegeEscalation: boolPtr(false),
+					CapabilitiesDrop:         []string{"ALL"},
+					CapabilitiesAdd:          []string{"NET_BIND_SERVICE"},
+				}}},
+			},
+			level: PodSecurityRestricted,
+		},
+		{
+			name:  "default container under baseline",
+			pod:   PodInfo{Containers: []ContainerInfo{{Name: "app"}}},
+			level: PodSecurityBaseline,
+		},
+		{
+			name:  "default container under restricted",
+			pod:   PodInfo{Containers: []ContainerInfo{{Name: "app"}}},
+			level: PodSecurityRestricted,
+			want:  []string{"allowPrivilegeEscalation != false", "unrestricted capabilities", "runAsNonRoot != true", "seccompProfile"},
+		},
+		{
+			name:  "default init container under restricted",
+			pod:   PodInfo{InitContainers: []ContainerInfo{{Name: "migrate"}}, Containers: []ContainerInfo{restrictedContainer("app")}},
+			level: PodSecurityRestricted,
+			want:  []string{"allowPrivilegeEscalation != false", "unrestricted capabilities", "runAsNonRoot != true", "seccompProfile"},
+		},
+		{
+			name: "host access under baseline",
+			pod: PodInfo{
+				HostNamespaces: []string{"network", "PID"},
+				Volumes:        []VolumeInfo{{Name: "docker", Type: "HostPath", Source: "/var/run/docker.sock"}},
+				Containers: []ContainerInfo{{
+					Name:            "agent",
+					Ports:           []ContainerPort{{ContainerPort: 9100, HostPort: 9100}},
+					SecurityContext: &SecurityContextInfo{Privileged: boolPtr(true), CapabilitiesAdd: []string{"SYS_ADMIN", "CHOWN"}},
+				}},
+			},
+			level: PodSecurityBaseline,
+			want:  []string{"host namespaces", "privileged", "non-default capabilities", "hostPath volumes", "hostPort"},
+		},
+		{
+			name: "unconfined profiles under baseline",
+			pod: PodInfo{
+				Annotations: map[string]string{appArmorAnnotationPrefix + "app": "unconfined"},
+				Containers: []ContainerInfo{withSecurity(restrictedContainer("app"), func(sc *SecurityContextInfo) {
+					sc.SeccompProfile = "Unconfined"
+				})},
+			},
+			level: PodSecurityBaseline,
+			want:  []string{"forbidden AppArmor profile", "seccompProfile"},
+		},
+		{
+			name: "runtime default AppArmor profile",
+			pod: PodInfo{
+				Annotations: map[string]string{appArmorAnnotationPrefix + "app": "runtime/default"},
+				Containers:  []ContainerInfo{restrictedContainer("app")},
+			},
+			level: PodSecurityRestricted,
+		},
+		{
+			name: "root user under restricted",
+			pod: PodInfo{Containers: []ContainerInfo{withSecurity(restrictedContainer("app"), func(sc *SecurityContextInfo) {
+				sc.RunAsUser = int64Ptr(0)
+			})}},
+			level: PodSecurityRestricted,
+			want:  []string{"runAsUser=0"},
+		},
+		{
+			name: "added capability under restricted",
+			pod: PodInfo{Containers: []ContainerInfo{withSecurity(restrictedContainer("app"), func(sc *SecurityContextInfo) {
+				sc.CapabilitiesAdd = []string{"CHOWN"}
+			})}},
+			level: PodSecurityRestricted,
+			want:  []string{"unrestricted capabilities"},
+		},
+		{
+			name: "privileged level checks nothing",
+			pod: PodInfo{HostNamespaces: []string{"network"}, Containers: []ContainerInfo{{
+				Name: "app", SecurityContext: &SecurityContextInfo{Privileged: boolPtr(true)},
+			}}},
+			level: PodSecurityPrivileged,
+		},
+		{
+			name:  "no level enforced",
+			pod:   PodInfo{HostNamespaces: []string{"network"}},
+			level: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range CheckPodSecurity(&tt.pod, tt.level) {
+				got = append(got, v.Check)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckPodSecurity() checks = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPodSecurity_Detail(t *testing.T) {
+	pod := &PodInfo{
+		HostNamespaces: []string{"network"},
+		Containers:     []ContainerInfo{{Name: "app"}, {Name: "sidecar"}, restrictedContainer("proxy")},
+	}
+	violations := CheckPodSecurity(pod, PodSecurityRestricted)
+	if len(violations) == 0 {
+		t.Fatal("CheckPodSecurity() found no violations")
+	}
+	if got, want := violations[0].String(), "host namespaces (hostNetwork=true)"; got != want {
+		t.Errorf("first violation = %q, want %q", got, want)
+	}
+	if violations[0].Level != PodSecurityBaseline {
+		t.Errorf("host namespaces level = %q, want baseline", violations[0].Level)
+	}
+	want := `allowPrivilegeEscalation != false (containers "app", "sidecar" must set securityContext.allowPrivilegeEscalation=false)`
+	if got := violations[1].String(); got != want {
+		t.Errorf("second violation = %q, want %q", got, want)
+	}
+}
+
+func TestEffectiveSecurityContext(t *testing.T) {
+	pod := &PodInfo{
+		Annotations: map[string]string{appArmorAnnotationPrefix + "app": "localhost/k8s-app"},
+		SecurityContext: &PodSecurityContext{
+			RunAsUser:      int64Ptr(1000),
+			RunAsNonRoot:   boolPtr(true),
+			FSGroup:        int64Ptr(2000),
+			SeccompProfile: "RuntimeDefault",
+		},
+	}
+	c := ContainerInfo{Name: "app", SecurityContext: &SecurityContextInfo{
+		RunAsUser:        int64Ptr(1001),
+		ReadOnlyRoot:     boolPtr(true),
+		CapabilitiesDrop: []string{"ALL"},
+	}}
+
+	got := EffectiveSecurityContext(pod, c)
+	if got.RunAsUser == nil || *got.RunAsUser != 1001 {
+		t.Errorf("RunAsUser = %v, want the container's 1001", got.RunAsUser)
+	}
+	if !got.RunAsNonRoot || got.SeccompProfile != "RuntimeDefault" || got.FSGroup == nil || *got.FSGroup != 2000 {
+		t.Errorf("pod settings not inherited: %+v", got)
+	}
+	if !got.ReadOnlyRoot || !reflect.DeepEqual(got.CapabilitiesDrop, []string{"ALL"}) {
+		t.Errorf("container settings lost: %+v", got)
+	}
+	if got.AppArmorProfile != "localhost/k8s-app" {
+		t.Errorf("AppArmorProfile = %q, want localhost/k8s-app", got.AppArmorProfile)
+	}
+	if want := []string{"runAsNonRoot", "seccompProfile"}; !reflect.DeepEqual(got.FromPod, want) {
+		t.Errorf("FromPod = %q, want %q", got.FromPod, want)
+	}
+
+	// Neither sets anything
+	if got := EffectiveSecurityContext(&PodInfo{}, ContainerInfo{Name: "app"}); got.RunAsUser != nil || got.RunAsNonRoot || len(got.FromPod) != 0 {
+		t.Errorf("unset security context = %+v, want defaults", got)
+	}
+}
+
+func TestBuildPodSecurity_Conflicts(t *testing.T) {
+	tests := []struct {
+		name      string
+		container ContainerInfo
+		events    []EventInfo
+		want      string // Part of the only conflict, "" for none
+	}{
+		{
+			name: "image runs as root",
+			container: ContainerInfo{
+				Name:            "app",
+				State:           "Waiting",
+				Reason:          "CreateContainerConfigError",
+				Message:         `container has runAsNonRoot and image will run as root (pod: "web-1_default(1234)", container: app)`,
+				SecurityContext: &SecurityContextInfo{RunAsNonRoot: boolPtr(true)},
+			},
+			want: "image runs as root",
+		},
+		{
+			name:      "non-numeric user from an event",
+			container: ContainerInfo{Name: "app", SecurityContext: &SecurityContextInfo{RunAsNonRoot: boolPtr(true)}},
+			events:    []EventInfo{{Type: "Warning", Reason: "Failed", Message: `Error: container has runAsNonRoot and image has non-numeric user (nginx), cannot verify user is non-root (pod: "web-1_default(1234)", container: app)`}},
+			want:      `image USER "nginx" is not numeric`,
+		},
+		{
+			name:      "event about another container",
+			container: ContainerInfo{Name: "app", SecurityContext: &SecurityContextInfo{RunAsNonRoot: boolPtr(true)}},
+			events:    []EventInfo{{Type: "Warning", Reason: "Failed", Message: `Error: container has runAsNonRoot and image will run as root (pod: "web-1_default(1234)", container: sidecar)`}},
+		},
+		{
+			name:      "root user with runAsNonRoot",
+			container: ContainerInfo{Name: "app", SecurityContext: &SecurityContextInfo{RunAsNonRoot: boolPtr(true), RunAsUser: int64Ptr(0)}},
+			want:      "runAsUser is 0 but runAsNonRoot is true",
+		},
+		{
+			name: "write to a read-only root filesystem",
+			container: ContainerInfo{
+				Name:            "app",
+				LastTermination: &TerminationInfo{Reason: "Error", Message: "open /tmp/cache: Read-only file system"},
+				SecurityContext: &SecurityContextInfo{ReadOnlyRoot: boolPtr(true)},
+			},
+			want: "read-only root filesystem",
+		},
+		{
+			name: "low port with capabilities dropped",
+			container: ContainerInfo{
+				Name:            "web",
+				Ports:           []ContainerPort{{ContainerPort: 80}},
+				SecurityContext: &SecurityContextInfo{CapabilitiesDrop: []string{"ALL"}},
+			},
+			want: "port 80 is below 1024 without NET_BIND_SERVICE",
+		},
+		{
+			name: "low port with NET_BIND_SERVICE added back",
+			container: ContainerInfo{
+				Name:            "web",
+				Ports:           []ContainerPort{{ContainerPort: 80}},
+				SecurityContext: &SecurityContextInfo{CapabilitiesDrop: []string{"ALL"}, CapabilitiesAdd: []string{"NET_BIND_SERVICE"}},
+			},
+		},
+		{
+			name: "low port as root",
+			container: ContainerInfo{
+				Name:  "web",
+				Ports: []ContainerPort{{ContainerPort: 80}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := BuildPodSecurity(&PodInfo{Containers: []ContainerInfo{tt.container}}, tt.events, PodSecurityAdmission{})
+			conflicts := report.Containers[0].Conflicts
+			if tt.want == "" {
+				if len(conflicts) != 0 || report.HasIssues() {
+					t.Errorf("conflicts = %q, want none", conflicts)
+				}
+				return
+			}
+			if len(conflicts) != 1 || !strings.Contains(conflicts[0], tt.want) {
+				t.Errorf("conflicts = %q, want one containing %q", conflicts, tt.want)
+			}
+			if !report.HasIssues() {
+				t.Error("HasIssues() = false with a conflict")
+			}
+		})
+	}
+}
+
+func TestBuildPodSecurity(t *testing.T) {
+	pod := &PodInfo{
+		InitContainers: []ContainerInfo{restrictedContainer("migrate")},
+		Containers:     []ContainerInfo{{Name: "app"}},
+	}
+	report := BuildPodSecurity(pod, nil, PodSecurityAdmission{Enforce: PodSecurityBaseline, Warn: PodSecurityRestricted})
+	if len(report.Containers) != 2 || !report.Containers[0].Init || report.Containers[0].Container != "migrate" || report.Containers[1].Init {
+		t.Fatalf("containers = %+v, want the init container first", report.Containers)
+	}
+	// Only the enforced level counts
+	if len(report.Violations) != 0 || report.HasIssues() {
+		t.Errorf("violations = %v, want none under baseline", report.Violations)
+	}
+
+	report = BuildPodSecurity(pod, nil, PodSecurityAdmission{Enforce: PodSecurityRestricted})
+	if len(report.Violations) == 0 || !report.HasIssues() {
+		t.Error("a default container should violate restricted")
+	}
+
+	var nilReport *PodSecurityReport
+	if nilReport.HasIssues() {
+		t.Error("a nil report has no issues")
+	}
+}
+
+func TestGetPodSecurityAdmission(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{
+			"pod-security.kubernetes.io/enforce":         "restricted",
+			"pod-security.kubernetes.io/enforce-version": "v1.29",
+			"pod-security.kubernetes.io/warn":            "restricted",
+		}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev"}},
+	)
+
+	got, err := GetPodSecurityAdmission(context.Background(), clientset, "prod")
+	if err != nil {
+		t.Fatalf("GetPodSecurityAdmission() error = %v", err)
+	}
+	want := PodSecurityAdmission{Enforce: PodSecurityRestricted, EnforceVersion: "v1.29", Warn: PodSecurityRestricted}
+	if got != want {
+		t.Errorf("GetPodSecurityAdmission() = %+v, want %+v", got, want)
+	}
+
+	if got, _ := GetPodSecurityAdmission(context.Background(), clientset, "dev"); got != (PodSecurityAdmission{}) {
+		t.Errorf("unlabeled namespace = %+v, want no levels", got)
+	}
+	if _, err := GetPodSecurityAdmission(context.Background(), clientset, "missing"); err == nil {
+		t.Error("expected an error for a missing namespace")
+	}
+}
+
+func TestPodToPodInfo_SecurityContext(t *testing.T) {
+	profile := "profiles/app.json"
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			HostNetwork: true,
+			HostIPC:     true,
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsUser:      int64Ptr(1000),
+				FSGroup:        int64Ptr(2000),
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			InitContainers: []corev1.Container{{
+				Name:            "init",
+				SecurityContext: &corev1.SecurityContext{RunAsUser: int64Ptr(0)},
+			}},
+			Containers: []corev1.Container{{
+				Name:  "app",
+				Ports: []corev1.ContainerPort{{ContainerPort: 80, HostPort: 8080}},
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: boolPtr(false),
+					Capabilities: &corev1.Capabilities{
+						Add:  []corev1.Capability{"NET_BIND_SERVICE"},
+						Drop: []corev1.Capability{"ALL"},
+					},
+					SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost, LocalhostProfile: &profile},
+				},
+			}},
+		},
+	}
+
+	info := podToPodInfo(pod)
+	if info.SecurityContext == nil || *info.SecurityContext.RunAsUser != 1000 || *info.SecurityContext.FSGroup != 2000 || info.SecurityContext.SeccompProfile != "RuntimeDefault" {
+		t.Errorf("pod security context = %+v", info.SecurityContext)
+	}
+	if want := []string{"network", "IPC"}; !reflect.DeepEqual(info.HostNamespaces, want) {
+		t.Errorf("HostNamespaces = %q, want %q", info.HostNamespaces, want)
+	}
+	sc := info.Containers[0].SecurityContext
+	if sc == nil || *sc.AllowPrivilegeEscalation || sc.SeccompProfile != "Localhost/profiles/app.json" ||
+		!reflect.DeepEqual(sc.CapabilitiesAdd, []string{"NET_BIND_SERVICE"}) || !reflect.DeepEqual(sc.CapabilitiesDrop, []string{"ALL"}) {
+		t.Errorf("container security context = %+v", sc)
+	}
+	if info.Containers[0].Ports[0].HostPort != 8080 {
+		t.Errorf("HostPort = %d, want 8080", info.Containers[0].Ports[0].HostPort)
+	}
+	if init := info.InitContainers[0].SecurityContext; init == nil || *init.RunAsUser != 0 {
+		t.Errorf("init container security context = %+v", init)
+	}
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrMetricsUnavailable is returned when pod metrics can't be read, most
+// often because metrics-server is not installed in the cluster.
+var ErrMetricsUnavailable = errors.New("metrics-server is not available")
+
+// PodUsage is the current resource usage of a pod next to its requests and
+// limits, summed over its containers.
+type PodUsage struct {
+	Name               string
+	Namespace          string
+	Status             string // Pod phase
+	HasMetrics         bool   // False until metrics-server has sampled the pod
+	CPUMilli           int64
+	MemoryBytes        int64
+	CPURequestMilli    int64
+	CPULimitMilli      int64 // 0 if any container has no CPU limit
+	MemoryRequestBytes int64
+	MemoryLimitBytes   int64 // 0 if any container has no memory limit
+}
+
+// CPUPercentOfRequest returns CPU usage as a percentage of the CPU request,
+// or 0 when no request is set.
+func (u PodUsage) CPUPercentOfRequest() float64 {
+	return percentOf(u.CPUMilli, u.CPURequestMilli)
+}
+
+// CPUPercentOfLimit returns CPU usage as a percentage of the CPU limit, or 0
+// when the pod is not limited.
+func (u PodUsage) CPUPercentOfLimit() float64 {
+	return percentOf(u.CPUMilli, u.CPULimitMilli)
+}
+
+// MemoryPercentOfRequest returns memory usage as a percentage of the memory
+// request, or 0 when no request is set.
+func (u PodUsage) MemoryPercentOfRequest() float64 {
+	return percentOf(u.MemoryBytes, u.MemoryRequestBytes)
+}
+
+// MemoryPercentOfLimit returns memory usage as a percentage of the memory
+// limit, or 0 when the pod is not limited. Close to 100 means the pod is
+// about to be OOMKilled.
+func (u PodUsage) MemoryPercentOfLimit() float64 {
+	return percentOf(u.MemoryBytes, u.MemoryLimitBytes)
+}
+
+func percentOf(used, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(used) * 100 / float64(total)
+}
+
+// GetNamespacePodUsage lists the pods of a namespace with their current
+// usage from metrics-server joined with the requests and limits of their
+// spec. Pods metrics-server has not sampled yet are listed without
+// metrics. Returns an error wrapping ErrMetricsUnavailable when pod
+// metrics can't be read.
+func GetNamespacePodUsage(ctx context.Context, clientset kubernetes.Interface, metricsClient MetricsClientInterface, namespace string) ([]PodUsage, error) {
+	if metricsClient == nil {
+		return nil, ErrMetricsUnavailable
+	}
+	metrics, err := GetNamespaceMetrics(ctx, metricsClient, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMetricsUnavailable, err)
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	byName := make(map[string]PodMetrics, len(metrics))
+	for _, pm := range metrics {
+		byName[pm.Name] = pm
+	}
+
+	result := make([]PodUsage, 0, len(pods.Items))
+	for i := range pods.Items {
+		usage := podResources(&pods.Items[i])
+		if pm, ok := byName[usage.Name]; ok {
+			usage.HasMetrics = true
+			for _, c := range pm.Containers {
+				usage.CPUMilli += c.CPUMilli
+				usage.MemoryBytes += c.MemoryBytes
+			}
+		}
+		result = append(result, usage)
+	}
+	return result, nil
+}
+
+// podResources sums the requests and limits of a pod's containers. A
+// container without a limit leaves the whole pod unlimited for that
+// resource. Init containers are not counted, as they are done by the time
+// the pod has usage to compare.
+func podResources(pod *corev1.Pod) PodUsage {
+	usage := PodUsage{Name: pod.Name, Namespace: pod.Namespace, Status: string(pod.Status.Phase)}
+	cpuLimited, memLimited := true, true
+	for _, c := range pod.Spec.Containers {
+		usage.CPURequestMilli += c.Resources.Requests.Cpu().MilliValue()
+		usage.MemoryRequestBytes += c.Resources.Requests.Memory().Value()
+		if cpu, ok := c.Resources.Limits[corev1.ResourceCPU]; ok {
+			usage.CPULimitMilli += cpu.MilliValue()
+		} else {
+			cpuLimited = false
+		}
+		if mem, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+			usage.MemoryLimitBytes += mem.Value()
+		} else {
+			memLimited = false
+		}
+	}
+	if !cpuLimited {
+		usage.CPULimitMilli = 0
+	}
+	if !memLimited {
+		usage.MemoryLimitBytes = 0
+	}
+	return usage
+}
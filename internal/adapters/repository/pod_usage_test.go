@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func usagePod(name string, containers ...corev1.Container) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: containers},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func usageContainer(cpuRequest, cpuLimit, memRequest, memLimit string) corev1.Container {
+	c := corev1.Container{Name: "main", Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
+	}}
+	set := func(list corev1.ResourceList, name corev1.ResourceName, value string) {
+		if value != "" {
+			list[name] = resource.MustParse(value)
+		}
+	}
+	set(c.Resources.Requests, corev1.ResourceCPU, cpuRequest)
+	set(c.Resources.Limits, corev1.ResourceCPU, cpuLimit)
+	set(c.Resources.Requests, corev1.ResourceMemory, memRequest)
+	set(c.Resources.Limits, corev1.ResourceMemory, memLimit)
+	return c
+}
+
+func podMetricsList(usage map[string][2]string) *metricsfake.Clientset {
+	metricsClient := metricsfake.NewSimpleClientset()
+	metricsClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list := &metricsv1beta1.PodMetricsList{}
+		for name, u := range usage {
+			list.Items = append(list.Items, metricsv1beta1.PodMetrics{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Containers: []metricsv1beta1.ContainerMetrics{{
+					Name: "main",
+					Usage: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(u[0]),
+						corev1.ResourceMemory: resource.MustParse(u[1]),
+					},
+				}},
+			})
+		}
+		return true, list, nil
+	})
+	return metricsClient
+}
+
+func TestGetNamespacePodUsage(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		usagePod("web", usageContainer("200m", "1", "256Mi", "512Mi")),
+		usagePod("worker",
+			usageContainer("100m", "500m", "128Mi", "1Gi"),
+			usageContainer("100m", "", "128Mi", "")),
+		usagePod("starting", usageContainer("100m", "", "", "")),
+	)
+	metricsClient := podMetricsList(map[string][2]string{
+		"web":    {"100m", "480Mi"},
+		"worker": {"300m", "64Mi"},
+	})
+
+	usage, err := GetNamespacePodUsage(context.Background(), clientset, metricsClient, "default")
+	if err != nil {
+		t.Fatalf("GetNamespacePodUsage() error = %v", err)
+	}
+	byName := map[string]PodUsage{}
+	for _, u := range usage {
+		byName[u.Name] = u
+	}
+	if len(byName) != 3 {
+		t.Fatalf("usage = %+v, want all 3 pods", usage)
+	}
+
+	web := byName["web"]
+	if !web.HasMetrics || web.CPUMilli != 100 || web.CPULimitMilli != 1000 {
+		t.Errorf("web = %+v, want 100m of a 1 core limit", web)
+	}
+	if got := web.CPUPercentOfRequest(); got != 50 {
+		t.Errorf("web CPU %% of request = %v, want 50", got)
+	}
+	if got := web.MemoryPercentOfLimit(); got < 93 || got > 94 {
+		t.Errorf("web memory %% of limit = %v, want 480Mi/512Mi", got)
+	}
+
+	// One container without limits leaves the pod unlimited, requests still add up
+	worker := byName["worker"]
+	if worker.CPURequestMilli != 200 || worker.MemoryRequestBytes != 256*1024*1024 {
+		t.Errorf("worker requests = %dm, %d bytes, want the sum of both containers", worker.CPURequestMilli, worker.MemoryRequestBytes)
+	}
+	if worker.CPULimitMilli != 0 || worker.MemoryLimitBytes != 0 || worker.MemoryPercentOfLimit() != 0 {
+		t.Errorf("worker = %+v, want no limits", worker)
+	}
+
+	if starting := byName["starting"]; starting.HasMetrics {
+		t.Errorf("starting = %+v, a pod without metrics should be flagged", starting)
+	}
+}
+
+func TestGetNamespacePodUsage_MetricsUnavailable(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset(usagePod("web", usageContainer("100m", "", "", "")))
+
+	if _, err := GetNamespacePodUsage(ctx, clientset, nil, "default"); !errors.Is(err, ErrMetricsUnavailable) {
+		t.Errorf("nil metrics client: error = %v, want ErrMetricsUnavailable", err)
+	}
+
+	metricsClient := metricsfake.NewSimpleClientset()
+	metricsClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("the server could not find the requested resource")
+	})
+	if _, err := GetNamespacePodUsage(ctx, clientset, metricsClient, "default"); !errors.Is(err, ErrMetricsUnavailable) {
+		t.Errorf("metrics API missing: error = %v, want ErrMetricsUnavailable", err)
+	}
+}
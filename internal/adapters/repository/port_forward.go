@@ -0,0 +1,311 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// portForwardReadyTimeout is how long Start waits for a forward to listen.
+const portForwardReadyTimeout = 30 * time.Second
+
+// PortForward is a running port-forward from a local port to a pod.
+type PortForward struct {
+	ID         int
+	Namespace  string
+	Pod        string
+	Service    string // Service the forward was started for, "" for a pod port
+	LocalPort  int
+	RemotePort int
+	StartedAt  time.Time
+}
+
+// Age is how long the forward has been running, e.g. "5m".
+func (p PortForward) Age() string {
+	return formatAge(p.StartedAt)
+}
+
+// PortInUseError is returned by Start when the local port is taken.
+// Suggested is the next free local port, or 0 when none was found.
+type PortInUseError struct {
+	Port      int
+	Suggested int
+}
+
+func (e *PortInUseError) Error() string {
+	return fmt.Sprintf("local port %d is already in use", e.Port)
+}
+
+// forwardFunc forwards localPort to remotePort of a pod until stop is
+// closed, closing ready once it listens.
+type forwardFunc func(config *rest.Config, namespace, pod string, localPort, remotePort int, stop <-chan struct{}, ready chan struct{}) error
+
+// PortForwarder starts port-forwards to pods, like kubectl port-forward,
+// and keeps track of them until they are stopped or the connection to
+// the pod ends.
+type PortForwarder struct {
+	mu       sync.Mutex
+	nextID   int
+	forwards map[int]*activeForward
+	forward  forwardFunc
+}
+
+type activeForward struct {
+	info PortForward
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewPortForwarder creates a PortForwarder with no forwards.
+func NewPortForwarder() *PortForwarder {
+	return &PortForwarder{
+		forwards: make(map[int]*activeForward),
+		forward:  forwardPod,
+	}
+}
+
+// Start forwards localPort on localhost to remotePort of a pod, and returns
+// once the local port listens. service is only recorded, for listing. If
+// localPort is taken, a *PortInUseError suggesting the next free port is
+// returned.
+func (f *PortForwarder) Start(ctx context.Context, config *rest.Config, namespace, pod, service string, localPort, remotePort int) (PortForward, error) {
+	if !LocalPortFree(localPort) {
+		suggested, _ := NextFreePort(localPort + 1)
+		return PortForward{}, &PortInUseError{Port: localPort, Suggested: suggested}
+	}
+
+	stop := make(chan struct{})
+	ready := make(chan struct{})
+	done := make(chan struct{})
+	errCh := make(chan error, 1)
+
+	f.mu.Lock()
+	f.nextID++
+	active := &activeForward{
+		info: PortForward{
+			ID:         f.nextID,
+			Namespace:  namespace,
+			Pod:        pod,
+			Service:    service,
+			LocalPort:  localPort,
+			RemotePort: remotePort,
+			StartedAt:  time.Now(),
+		},
+		stop: stop,
+		done: done,
+	}
+	f.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		err := f.forward(config, namespace, pod, localPort, remotePort, stop, ready)
+		// Forgotten once it ends, whether stopped or the pod went away
+		f.mu.Lock()
+		delete(f.forwards, active.info.ID)
+		f.mu.Unlock()
+		errCh <- err
+	}()
+
+	timer := time.NewTimer(portForwardReadyTimeout)
+	defer timer.Stop()
+	select {
+	case <-ready:
+	case err := <-errCh:
+		if err == nil {
+			err = errors.New("port-forward ended before it was ready")
+		}
+		return PortForward{}, err
+	case <-ctx.Done():
+		close(stop)
+		return PortForward{}, ctx.Err()
+	case <-timer.C:
+		close(stop)
+		return PortForward{}, fmt.Errorf("port-forward to %s was not ready after %s", pod, portForwardReadyTimeout)
+	}
+
+	f.mu.Lock()
+	select {
+	case <-done:
+		// Ended right after becoming ready
+		f.mu.Unlock()
+		return PortForward{}, errors.New("port-forward ended right after it started")
+	default:
+		f.forwards[active.info.ID] = active
+	}
+	f.mu.Unlock()
+	return active.info, nil
+}
+
+// Stop stops a forward and waits for its local port to be released.
+func (f *PortForwarder) Stop(id int) error {
+	f.mu.Lock()
+	active, ok := f.forwards[id]
+	if ok {
+		delete(f.forwards, id)
+	}
+	f.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no port-forward with id %d", id)
+	}
+	close(active.stop)
+	<-active.done
+	return nil
+}
+
+// StopAll stops every forward, as when the program exits.
+func (f *PortForwarder) StopAll() {
+	for _, pf := range f.List() {
+		_ = f.Stop(pf.ID)
+	}
+}
+
+// List returns the running forwards, oldest first.
+func (f *PortForwarder) List() []PortForward {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	forwards := make([]PortForward, 0, len(f.forwards))
+	for _, active := range f.forwards {
+		forwards = append(forwards, active.info)
+	}
+	sort.Slice(forwards, func(i, j int) bool {
+		return forwards[i].ID < forwards[j].ID
+	})
+	return forwards
+}
+
+// forwardPod is the forwardFunc of NewPortForwarder, using the pod's
+// portforward subresource over SPDY.
+func forwardPod(config *rest.Config, namespace, pod string, localPort, remotePort int, stop <-chan struct{}, ready chan struct{}) error {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create clientset: %w", err)
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return fmt.Errorf("failed to create round tripper: %w", err)
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	ports := []string{fmt.Sprintf("%d:%d", localPort, remotePort)}
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, ports, stop, ready, io.Discard, io.Discard)
+	if err != nil {
+		return fmt.Errorf("failed to create port-forward: %w", err)
+	}
+	return forwarder.ForwardPorts()
+}
+
+// LocalPortFree reports whether port can be listened on at localhost.
+func LocalPortFree(port int) bool {
+	l, err := net.Listen("tcp", net.JoinHostPort("localhost", strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	l.Close()
+	return true
+}
+
+// NextFreePort returns the first port from from up that can be listened
+// on at localhost.
+func NextFreePort(from int) (int, error) {
+	if from < 1 {
+		from = 1
+	}
+	for port := from; port <= 65535; port++ {
+		if LocalPortFree(port) {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no free local port from %d", from)
+}
+
+// ResolveServicePort finds where a forward to port of a Service goes, like
+// kubectl port-forward svc/name: a running pod selected by the Service
+// (a ready one when there is one) and the container port its targetPort
+// refers to, which may be a named port.
+func ResolveServicePort(ctx context.Context, clientset kubernetes.Interface, namespace, service string, port int32) (string, int, error) {
+	svc, err := clientset.CoreV1().Services(namespace).Get(ctx, service, metav1.GetOptions{})
+	if err != nil {
+		return "", 0, err
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return "", 0, fmt.Errorf("service %s has no selector", service)
+	}
+
+	var svcPort *corev1.ServicePort
+	for i := range svc.Spec.Ports {
+		if svc.Spec.Ports[i].Port == port {
+			svcPort = &svc.Spec.Ports[i]
+			break
+		}
+	}
+	if svcPort == nil {
+		return "", 0, fmt.Errorf("service %s has no port %d", service, port)
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	var pod *corev1.Pod
+	for i := range pods.Items {
+		p := &pods.Items[i]
+		if p.Status.Phase != corev1.PodRunning || p.DeletionTimestamp != nil {
+			continue
+		}
+		if pod == nil || (isPodReady(p) && !isPodReady(pod)) {
+			pod = p
+		}
+	}
+	if pod == nil {
+		return "", 0, fmt.Errorf("service %s has no running pods", service)
+	}
+
+	remote, err := podPortFor(pod, svcPort.TargetPort, port)
+	if err != nil {
+		return "", 0, err
+	}
+	return pod.Name, remote, nil
+}
+
+// podPortFor returns the container port of pod a Service targetPort refers
+// to; an unset targetPort is the Service port itself.
+func podPortFor(pod *corev1.Pod, target intstr.IntOrString, port int32) (int, error) {
+	switch {
+	case target.Type == intstr.Int && target.IntVal == 0:
+		return int(port), nil
+	case target.Type == intstr.Int:
+		return int(target.IntVal), nil
+	}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == target.StrVal {
+				return int(p.ContainerPort), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("pod %s has no port named %q", pod.Name, target.StrVal)
+}
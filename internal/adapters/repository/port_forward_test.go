@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+// fakeForwarder returns a PortForwarder whose forwards are ready at once
+// and run until stopped, or until ended is closed.
+func fakeForwarder(ended <-chan struct{}) *PortForwarder {
+	f := NewPortForwarder()
+	f.forward = func(_ *rest.Config, _, _ string, _, _ int, stop <-chan struct{}, ready chan struct{}) error {
+		close(ready)
+		select {
+		case <-stop:
+		case <-ended:
+		}
+		return nil
+	}
+	return f
+}
+
+// freePort returns a local port nothing listens on.
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestPortForwarder_StartListStop(t *testing.T) {
+	f := fakeForwarder(nil)
+	ctx := context.Background()
+
+	first, err := f.Start(ctx, &rest.Config{}, "default", "web-1", "", freePort(t), 8080)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	second, err := f.Start(ctx, &rest.Config{}, "default", "web-2", "web", freePort(t), 80)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if first.ID == second.ID {
+		t.Fatalf("forwards share id %d", first.ID)
+	}
+	if first.StartedAt.IsZero() || first.Pod != "web-1" || first.RemotePort != 8080 {
+		t.Errorf("first forward = %+v", first)
+	}
+
+	forwards := f.List()
+	if len(forwards) != 2 || forwards[0].ID != first.ID || forwards[1].Service != "web" {
+		t.Fatalf("List() = %+v, want both forwards oldest first", forwards)
+	}
+
+	if err := f.Stop(first.ID); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if forwards := f.List(); len(forwards) != 1 || forwards[0].ID != second.ID {
+		t.Errorf("List() after Stop = %+v, want only the second forward", forwards)
+	}
+	if err := f.Stop(first.ID); err == nil {
+		t.Error("Stop() of a stopped forward should fail")
+	}
+
+	f.StopAll()
+	if forwards := f.List(); len(forwards) != 0 {
+		t.Errorf("List() after StopAll = %+v, want none", forwards)
+	}
+}
+
+func TestPortForwarder_ForgetsEndedForwards(t *testing.T) {
+	ended := make(chan struct{})
+	f := fakeForwarder(ended)
+
+	pf, err := f.Start(context.Background(), &rest.Config{}, "default", "web", "", freePort(t), 8080)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	f.mu.Lock()
+	done := f.forwards[pf.ID].done
+	f.mu.Unlock()
+
+	// The pod went away
+	close(ended)
+	<-done
+	if forwards := f.List(); len(forwards) != 0 {
+		t.Errorf("List() = %+v, want the ended forward gone", forwards)
+	}
+}
+
+func TestPortForwarder_StartError(t *testing.T) {
+	f := NewPortForwarder()
+	f.forward = func(*rest.Config, string, string, int, int, <-chan struct{}, chan struct{}) error {
+		return errors.New("pods \"web\" not found")
+	}
+
+	if _, err := f.Start(context.Background(), &rest.Config{}, "default", "web", "", freePort(t), 8080); err == nil {
+		t.Fatal("Start() should fail when the forward fails")
+	}
+	if forwards := f.List(); len(forwards) != 0 {
+		t.Errorf("List() = %+v, want no forwards", forwards)
+	}
+}
+
+func TestPortForwarder_PortInUse(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	f := fakeForwarder(nil)
+	_, err = f.Start(context.Background(), &rest.Config{}, "default", "web", "", port, 8080)
+	var inUse *PortInUseError
+	if !errors.As(err, &inUse) {
+		t.Fatalf("Start() error = %v, want *PortInUseError", err)
+	}
+	if inUse.Port != port || inUse.Suggested <= port {
+		t.Errorf("PortInUseError = %+v, want a suggestion above %d", inUse, port)
+	}
+	if !LocalPortFree(inUse.Suggested) {
+		t.Errorf("suggested port %d is not free", inUse.Suggested)
+	}
+	if got, _ := NextFreePort(port); got == port {
+		t.Errorf("NextFreePort(%d) = %d, a port in use", port, got)
+	}
+}
+
+func TestResolveServicePort(t *testing.T) {
+	pod := func(name string, phase corev1.PodPhase, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  "app",
+				Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+			}}},
+			Status: corev1.PodStatus{
+				Phase:      phase,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "web"},
+			Ports: []corev1.ServicePort{
+				{Port: 80, TargetPort: intstr.FromString("http")},
+				{Port: 9090, TargetPort: intstr.FromInt(9091)},
+				{Port: 7000},
+			},
+		},
+	}
+	clientset := fake.NewSimpleClientset(svc,
+		pod("web-pending", corev1.PodPending, false),
+		pod("web-not-ready", corev1.PodRunning, false),
+		pod("web-ready", corev1.PodRunning, true),
+	)
+	ctx := context.Background()
+
+	tests := []struct {
+		port     int32
+		wantPort int
+	}{
+		{80, 8080},
+		{9090, 9091},
+		{7000, 7000},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(int(tt.port)), func(t *testing.T) {
+			podName, remote, err := ResolveServicePort(ctx, clientset, "default", "web", tt.port)
+			if err != nil {
+				t.Fatalf("ResolveServicePort() error = %v", err)
+			}
+			if podName != "web-ready" || remote != tt.wantPort {
+				t.Errorf("ResolveServicePort() = %s:%d, want web-ready:%d", podName, remote, tt.wantPort)
+			}
+		})
+	}
+
+	if _, _, err := ResolveServicePort(ctx, clientset, "default", "web", 443); err == nil {
+		t.Error("ResolveServicePort() of a missing port should fail")
+	}
+	if _, _, err := ResolveServicePort(ctx, fake.NewSimpleClientset(svc), "default", "web", 80); err == nil {
+		t.Error("ResolveServicePort() without running pods should fail")
+	}
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultProbeHealthWindow is the event window used for probe flap rates.
+const DefaultProbeHealthWindow = time.Hour
+
+// WorkloadProbeHealth summarizes readiness probe health for one workload.
+// Built from already-fetched pods and events, so it costs no extra API calls.
+type WorkloadProbeHealth struct {
+	Workload          string   // Owning workload name (pod name for bare pods)
+	FailingContainers int      // Containers running but currently not ready
+	ProbeFailures     int      // Readiness probe failures seen in the window
+	FlapRate          float64  // Ready transitions per hour derived from events
+	Pods              []string // Affected pod names, worst offenders first
+}
+
+// AggregateProbeHealth computes a namespace-level readiness summary.
+// Failing containers come from current pod state; flap rate comes from
+// "Unhealthy" readiness events whose last occurrence falls within window,
+// counting only the share of an event's occurrences within it (see
+// failuresInWindow). Each probe failure counts as one ready transition. Workloads without any
+// failure are omitted and the result is sorted worst offender first.
+func AggregateProbeHealth(pods []PodInfo, events []EventInfo, window time.Duration, now time.Time) []WorkloadProbeHealth {
+	if window <= 0 {
+		window = DefaultProbeHealthWindow
+	}
+	cutoff := now.Add(-window)
+
+	workloadByPod := make(map[string]string, len(pods))
+	failingByPod := make(map[string]int)
+	for _, p := range pods {
+		workloadByPod[p.Name] = podWorkloadName(p)
+		for _, c := range p.Containers {
+			if c.State == "Running" && !c.Ready {
+				failingByPod[p.Name]++
+			}
+		}
+	}
+
+	failuresByPod := make(map[string]int)
+	for _, e := range events {
+		if !isReadinessFailure(e) || e.LastSeen.Before(cutoff) {
+			continue
+		}
+		podName := strings.TrimPrefix(e.Object, "Pod/")
+		if _, ok := workloadByPod[podName]; !ok {
+			continue
+		}
+		failuresByPod[podName] += failuresInWindow(e, cutoff)
+	}
+
+	byWorkload := make(map[string]*WorkloadProbeHealth)
+	for _, p := range pods {
+		failing, failures := failingByPod[p.Name], failuresByPod[p.Name]
+		if failing == 0 && failures == 0 {
+			continue
+		}
+		name := workloadByPod[p.Name]
+		h, ok := byWorkload[name]
+		if !ok {
+			h = &WorkloadProbeHealth{Workload: name}
+			byWorkload[name] = h
+		}
+		h.FailingContainers += failing
+		h.ProbeFailures += failures
+		h.Pods = append(h.Pods, p.Name)
+	}
+
+	result := make([]WorkloadProbeHealth, 0, len(byWorkload))
+	for _, h := range byWorkload {
+		h.FlapRate = float64(h.ProbeFailures) / window.Hours()
+		sort.SliceStable(h.Pods, func(i, j int) bool {
+			a, b := h.Pods[i], h.Pods[j]
+			if failingByPod[a] != failingByPod[b] {
+				return failingByPod[a] > failingByPod[b]
+			}
+			if failuresByPod[a] != failuresByPod[b] {
+				return failuresByPod[a] > failuresByPod[b]
+			}
+			return a < b
+		})
+		result = append(result, *h)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].FailingContainers != result[j].FailingContainers {
+			return result[i].FailingContainers > result[j].FailingContainers
+		}
+		if result[i].ProbeFailures != result[j].ProbeFailures {
+			return result[i].ProbeFailures > result[j].ProbeFailures
+		}
+		return result[i].Workload < result[j].Workload
+	})
+
+	return result
+}
+
+// failuresInWindow returns how many of an event's occurrences fall after
+// cutoff. An event only records its first and last occurrence, so when it
+// started before cutoff its count is prorated over FirstSeen..LastSeen,
+// assuming evenly spaced occurrences. The last occurrence, after cutoff,
+// always counts.
+func failuresInWindow(e EventInfo, cutoff time.Time) int {
+	count := int(e.Count)
+	if count < 1 {
+		count = 1
+	}
+	if e.FirstSeen.IsZero() || !e.FirstSeen.Before(cutoff) || !e.LastSeen.After(e.FirstSeen) {
+		return count
+	}
+	share := float64(e.LastSeen.Sub(cutoff)) / float64(e.LastSeen.Sub(e.FirstSeen))
+	if n := int(math.Round(float64(count) * share)); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// isReadinessFailure reports whether an event is a failed readiness probe.
+func isReadinessFailure(e EventInfo) bool {
+	return e.Reason == "Unhealthy" && strings.Contains(e.Message, "Readiness probe failed")
+}
+
+// podWorkloadName returns the name of the workload that owns a pod.
+// ReplicaSet owners have their pod-template hash stripped so pods of the
+// same Deployment or Rollout across revisions are grouped together.
+func podWorkloadName(p PodInfo) string {
+	if p.OwnerRef == "" {
+		return p.Name
+	}
+	if p.OwnerKind == "ReplicaSet" {
+		if idx := strings.LastIndex(p.OwnerRef, "-"); idx > 0 {
+			return p.OwnerRef[:idx]
+		}
+	}
+	return p.OwnerRef
+}
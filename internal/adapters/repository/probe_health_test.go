@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregateProbeHealth(t *testing.T) {
+	now := time.Now()
+
+	pods := []PodInfo{
+		{
+			Name: "api-7d9f-abc", OwnerRef: "api-7d9f", OwnerKind: "ReplicaSet",
+			Containers: []ContainerInfo{
+				{Name: "app", State: "Running", Ready: false},
+				{Name: "sidecar", State: "Running", Ready: true},
+			},
+		},
+		{
+			Name: "api-7d9f-def", OwnerRef: "api-7d9f", OwnerKind: "ReplicaSet",
+			Containers: []ContainerInfo{{Name: "app", State: "Running", Ready: true}},
+		},
+		{
+			Name: "db-0", OwnerRef: "db", OwnerKind: "StatefulSet",
+			Containers: []ContainerInfo{{Name: "postgres", State: "Running", Ready: true}},
+		},
+		{
+			Name: "worker-1", OwnerRef: "worker-5c6b", OwnerKind: "ReplicaSet",
+			Containers: []ContainerInfo{
+				{Name: "a", State: "Running", Ready: false},
+				{Name: "b", State: "Running", Ready: false},
+			},
+		},
+		{
+			Name:       "healthy",
+			Containers: []ContainerInfo{{Name: "app", State: "Running", Ready: true}},
+		},
+		{
+			Name:       "waiting",
+			Containers: []ContainerInfo{{Name: "app", State: "Waiting", Ready: false}},
+		},
+	}
+
+	events := []EventInfo{
+		{Reason: "Unhealthy", Message: "Readiness probe failed: HTTP 503", Object: "Pod/api-7d9f-def", Count: 4, LastSeen: now.Add(-10 * time.Minute)},
+		{Reason: "Unhealthy", Message: "Readiness probe failed: timeout", Object: "Pod/db-0", Count: 2, LastSeen: now.Add(-5 * time.Minute)},
+		// Outside the window
+		{Reason: "Unhealthy", Message: "Readiness probe failed: timeout", Object: "Pod/db-0", Count: 50, LastSeen: now.Add(-3 * time.Hour)},
+		// Liveness failures do not count towards readiness flapping
+		{Reason: "Unhealthy", Message: "Liveness probe failed: timeout", Object: "Pod/healthy", Count: 9, LastSeen: now},
+		// Events for pods no longer present are ignored
+		{Reason: "Unhealthy", Message: "Readiness probe failed", Object: "Pod/gone", Count: 7, LastSeen: now},
+	}
+
+	result := AggregateProbeHealth(pods, events, time.Hour, now)
+
+	if len(result) != 3 {
+		t.Fatalf("AggregateProbeHealth() returned %d workloads, want 3: %+v", len(result), result)
+	}
+
+	// worker has 2 failing containers, api has 1, db has none but flaps
+	wantOrder := []string{"worker", "api", "db"}
+	for i, w := range wantOrder {
+		if result[i].Workload != w {
+			t.Errorf("result[%d].Workload = %q, want %q", i, result[i].Workload, w)
+		}
+	}
+
+	api := result[1]
+	if api.FailingContainers != 1 {
+		t.Errorf("api FailingContainers = %d, want 1", api.FailingContainers)
+	}
+	if api.ProbeFailures != 4 {
+		t.Errorf("api ProbeFailures = %d, want 4", api.ProbeFailures)
+	}
+	if api.FlapRate != 4 {
+		t.Errorf("api FlapRate = %v, want 4", api.FlapRate)
+	}
+	if len(api.Pods) != 2 || api.Pods[0] != "api-7d9f-abc" {
+		t.Errorf("api Pods = %v, want currently failing pod first", api.Pods)
+	}
+
+	db := result[2]
+	if db.ProbeFailures != 2 || db.FailingContainers != 0 {
+		t.Errorf("db = %+v, want 2 failures and 0 failing containers", db)
+	}
+}
+
+func TestAggregateProbeHealth_Window(t *testing.T) {
+	now := time.Now()
+	pods := []PodInfo{{Name: "web"}}
+	events := []EventInfo{
+		{Reason: "Unhealthy", Message: "Readiness probe failed", Object: "Pod/web", Count: 6, LastSeen: now.Add(-90 * time.Minute)},
+	}
+
+	if result := AggregateProbeHealth(pods, events, time.Hour, now); len(result) != 0 {
+		t.Errorf("expected no workloads within 1h window, got %+v", result)
+	}
+
+	result := AggregateProbeHealth(pods, events, 2*time.Hour, now)
+	if len(result) != 1 {
+		t.Fatalf("expected 1 workload within 2h window, got %d", len(result))
+	}
+	if result[0].FlapRate != 3 {
+		t.Errorf("FlapRate = %v, want 3 per hour", result[0].FlapRate)
+	}
+}
+
+func TestAggregateProbeHealth_ProratesEventsStartedBeforeWindow(t *testing.T) {
+	now := time.Now()
+	pods := []PodInfo{{Name: "web"}, {Name: "api"}}
+	events := []EventInfo{
+		// 40 failures over the last 4 hours: about 10 within the last hour
+		{Reason: "Unhealthy", Message: "Readiness probe failed", Object: "Pod/web", Count: 40, FirstSeen: now.Add(-4 * time.Hour), LastSeen: now},
+		// Started within the window: every failure counts
+		{Reason: "Unhealthy", Message: "Readiness probe failed", Object: "Pod/api", Count: 6, FirstSeen: now.Add(-30 * time.Minute), LastSeen: now},
+	}
+
+	result := AggregateProbeHealth(pods, events, time.Hour, now)
+	failures := make(map[string]int)
+	for _, h := range result {
+		failures[h.Workload] = h.ProbeFailures
+	}
+	if failures["web"] != 10 {
+		t.Errorf("web ProbeFailures = %d, want 10 of the 40 within the window", failures["web"])
+	}
+	if failures["api"] != 6 {
+		t.Errorf("api ProbeFailures = %d, want all 6", failures["api"])
+	}
+
+	// A single late occurrence of a long-running event still counts once
+	events = []EventInfo{
+		{Reason: "Unhealthy", Message: "Readiness probe failed", Object: "Pod/web", Count: 2, FirstSeen: now.Add(-48 * time.Hour), LastSeen: now.Add(-time.Minute)},
+	}
+	if result := AggregateProbeHealth(pods, events, time.Hour, now); len(result) != 1 || result[0].ProbeFailures != 1 {
+		t.Errorf("expected 1 failure within the window, got %+v", result)
+	}
+}
+
+func TestAggregateProbeHealth_ZeroCountAndDefaultWindow(t *testing.T) {
+	now := time.Now()
+	pods := []PodInfo{{Name: "web"}}
+	events := []EventInfo{
+		{Reason: "Unhealthy", Message: "Readiness probe failed", Object: "Pod/web", LastSeen: now},
+	}
+
+	result := AggregateProbeHealth(pods, events, 0, now)
+	if len(result) != 1 || result[0].ProbeFailures != 1 {
+		t.Errorf("zero-count event should count once, got %+v", result)
+	}
+}
+
+func TestAggregateProbeHealth_Empty(t *testing.T) {
+	if result := AggregateProbeHealth(nil, nil, time.Hour, time.Now()); len(result) != 0 {
+		t.Errorf("expected empty result, got %+v", result)
+	}
+}
+
+func TestPodWorkloadName(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  PodInfo
+		want string
+	}{
+		{"bare pod", PodInfo{Name: "debug"}, "debug"},
+		{"replicaset owner", PodInfo{Name: "api-7d9f-x", OwnerRef: "api-7d9f", OwnerKind: "ReplicaSet"}, "api"},
+		{"statefulset owner", PodInfo{Name: "db-0", OwnerRef: "db", OwnerKind: "StatefulSet"}, "db"},
+		{"job owner", PodInfo{Name: "backup-x", OwnerRef: "backup-123", OwnerKind: "Job"}, "backup-123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := podWorkloadName(tt.pod); got != tt.want {
+				t.Errorf("podWorkloadName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
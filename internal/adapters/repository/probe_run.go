@@ -0,0 +1,397 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// ProbeBodyLimit is how much of the response body of a probe run by hand
+// is kept.
+const ProbeBodyLimit = 1024
+
+// probeOutputLimit caps what is read of a probe's output; a health
+// endpoint that streams megabytes is only looked at for its start.
+const probeOutputLimit = 64 * 1024
+
+// probeExecGrace is added to the probe timeout for the exec round trip,
+// so that the tools' own timeout is what normally ends a slow probe.
+const probeExecGrace = 5 * time.Second
+
+// probeMarker starts the lines the probe script adds to the tools' output.
+const probeMarker = "@k1s "
+
+// ProbeOutcome is how a probe run by hand ended.
+type ProbeOutcome string
+
+const (
+	ProbeSucceeded ProbeOutcome = "succeeded"
+	ProbeRefused   ProbeOutcome = "connection refused"
+	ProbeTimedOut  ProbeOutcome = "timed out"
+	ProbeBadStatus ProbeOutcome = "bad status" // HTTP status outside 200-399, gRPC not SERVING or a non-zero exit
+	ProbeErrored   ProbeOutcome = "error"      // Anything else, e.g. a DNS or TLS failure
+)
+
+// ErrNoProbeTool is returned by RunProbe when the container has none of
+// the tools a probe is reproduced with (curl or wget, nc or bash,
+// grpc_health_probe or grpcurl), nor a shell, as in distroless images.
+var ErrNoProbeTool = errors.New("container has no tool to run the probe with")
+
+// ProbeRunResult is the result of a probe run by hand.
+type ProbeRunResult struct {
+	Tool       string // Tool the probe ran with, e.g. "curl"
+	Outcome    ProbeOutcome
+	StatusCode int           // HTTP status, or exit code of an exec probe
+	Status     string        // gRPC serving status, e.g. "SERVING"
+	Latency    time.Duration // As measured by the tool; 0 when it doesn't report it
+	Body       string        // First ProbeBodyLimit bytes of the response, or output of an exec probe
+	Truncated  bool          // The body is longer than ProbeBodyLimit
+	Detail     string        // Error printed by the tool
+}
+
+// Summary describes the result in one line, e.g. "HTTP 503 in 12ms".
+func (r ProbeRunResult) Summary() string {
+	latency := ""
+	if r.Latency > 0 {
+		latency = " in " + r.Latency.Round(time.Millisecond/10).String()
+	}
+	switch r.Outcome {
+	case ProbeSucceeded, ProbeBadStatus:
+		switch {
+		case r.Status != "":
+			return r.Status + latency
+		case r.Tool == "exec":
+			return fmt.Sprintf("exit %d", r.StatusCode)
+		case r.StatusCode != 0:
+			return fmt.Sprintf("HTTP %d%s", r.StatusCode, latency)
+		case r.Outcome == ProbeSucceeded:
+			return "connection open" + latency
+		}
+	case ProbeRefused, ProbeTimedOut:
+		if r.Detail != "" {
+			return string(r.Outcome) + ": " + r.Detail
+		}
+		return string(r.Outcome)
+	}
+	return "failed: " + r.Detail
+}
+
+// BuildProbeCommand returns the command that reproduces a probe from inside
+// the pod, as the kubelet runs it: against the pod IP (or the probe's
+// host), with the probe's scheme, path, headers and timeoutSeconds. HTTPS
+// certificates are not verified, as the kubelet doesn't, and a Host header
+// overrides the host sent. HTTP, TCP and gRPC probes run as a shell script
+// trying the tools an image may have in turn; exec probes run their
+// command.
+func BuildProbeCommand(p *ProbeInfo, podIP string, ports []ContainerPort) ([]string, error) {
+	if p == nil {
+		return nil, fmt.Errorf("no probe")
+	}
+	if p.Type == "Exec" {
+		if len(p.Command) == 0 {
+			return nil, fmt.Errorf("exec probe has no command")
+		}
+		return p.Command, nil
+	}
+
+	port, err := probePort(p, ports)
+	if err != nil {
+		return nil, err
+	}
+	host := p.Host
+	if host == "" || p.Type == "gRPC" {
+		host = podIP
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	if strings.Trim(host, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789.:-") != "" {
+		return nil, fmt.Errorf("invalid probe host %q", host)
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(int(port)))
+	timeout := probeTimeout(p)
+
+	var script string
+	switch p.Type {
+	case "HTTP":
+		script = httpProbeScript(p, address, timeout)
+	case "TCP":
+		script = probeScript(
+			probeTool{"nc", fmt.Sprintf("nc -w %d %s %d </dev/null", timeout, host, port)},
+			probeTool{"bash", fmt.Sprintf("bash -c 'exec 3<>/dev/tcp/%s/%d'", host, port)},
+		)
+	case "gRPC":
+		healthProbe := fmt.Sprintf("grpc_health_probe -addr=%s -connect-timeout=%ds -rpc-timeout=%ds", address, timeout, timeout)
+		grpcurl := fmt.Sprintf("grpcurl -plaintext -max-time %d", timeout)
+		if p.Service != "" {
+			healthProbe += " -service=" + shellQuote(p.Service)
+			grpcurl += " -d " + shellQuote(fmt.Sprintf(`{"service":%q}`, p.Service))
+		}
+		script = probeScript(
+			probeTool{"grpc_health_probe", healthProbe},
+			probeTool{"grpcurl", grpcurl + " " + address + " grpc.health.v1.Health/Check"},
+		)
+	default:
+		return nil, fmt.Errorf("%s probes can't be run by hand", p.Type)
+	}
+	return []string{"sh", "-c", script}, nil
+}
+
+// httpProbeScript requests the probe URL with curl, or wget when the image
+// has no curl. curl appends the status code and the time taken to the
+// body; wget prints the response headers (-S) to stderr.
+func httpProbeScript(p *ProbeInfo, address string, timeout int32) string {
+	scheme := strings.ToLower(p.Scheme)
+	if scheme == "" {
+		scheme = "http"
+	}
+	path := p.Path
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	url := shellQuote(scheme + "://" + address + path)
+
+	curl := fmt.Sprintf("curl -sS -m %d", timeout)
+	wget := fmt.Sprintf("wget -q -S -O - -T %d", timeout)
+	if scheme == "https" {
+		curl += " -k"
+		wget += " --no-check-certificate"
+	}
+	userAgent := true
+	for _, h := range p.Headers {
+		if strings.EqualFold(h.Name, "User-Agent") {
+			userAgent = false
+		}
+		header := shellQuote(h.Name + ": " + h.Value)
+		curl += " -H " + header
+		wget += " --header " + header
+	}
+	if userAgent {
+		curl += " -A kube-probe/k1s"
+		wget += " -U kube-probe/k1s"
+	}
+	curl += ` -w '\n` + probeMarker + `status %{http_code} %{time_total}\n' ` + url
+	return probeScript(probeTool{"curl", curl}, probeTool{"wget", wget + " " + url})
+}
+
+// probeTool is a command a probe can be run with, if the image has it.
+type probeTool struct {
+	name    string
+	command string
+}
+
+// probeScript runs the first of tools the container has, then prints which
+// one it was and its exit code to stderr, or tool "none".
+func probeScript(tools ...probeTool) string {
+	var b strings.Builder
+	for i, t := range tools {
+		if i > 0 {
+			b.WriteString("el")
+		}
+		fmt.Fprintf(&b, "if command -v %s >/dev/null 2>&1; then\n  %s; echo \"%sexit %s $?\" >&2\n", t.name, t.command, probeMarker, t.name)
+	}
+	fmt.Fprintf(&b, "else\n  echo \"%sexit none 127\" >&2\nfi", probeMarker)
+	return b.String()
+}
+
+// probePort returns the port a probe targets, resolving a named port
+// against the container's ports as the kubelet does.
+func probePort(p *ProbeInfo, ports []ContainerPort) (int32, error) {
+	if p.PortName == "" {
+		return p.Port, nil
+	}
+	for _, cp := range ports {
+		if cp.Name == p.PortName {
+			return cp.ContainerPort, nil
+		}
+	}
+	return 0, fmt.Errorf("no container port named %q", p.PortName)
+}
+
+// probeTimeout is the probe's timeoutSeconds, 1 when unset as in the API.
+func probeTimeout(p *ProbeInfo) int32 {
+	if p.Timeout <= 0 {
+		return 1
+	}
+	return p.Timeout
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// RunProbe runs a probe of a container by hand, from inside container
+// (the probed container, or a debug container sharing its network), and
+// reports what the kubelet would have seen.
+func RunProbe(ctx context.Context, config *rest.Config, namespace, pod, container string, p *ProbeInfo, podIP string, ports []ContainerPort) (*ProbeRunResult, error) {
+	return runProbe(ctx, podExecRunner(config, namespace, pod, container), p, podIP, ports)
+}
+
+func runProbe(ctx context.Context, run execRunner, p *ProbeInfo, podIP string, ports []ContainerPort) (*ProbeRunResult, error) {
+	command, err := BuildProbeCommand(p, podIP, ports)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(probeTimeout(p))*time.Second+probeExecGrace)
+	defer cancel()
+
+	stdout := &limitedBuffer{limit: probeOutputLimit}
+	stderr := &limitedBuffer{limit: probeOutputLimit}
+	err = run(ctx, command, stdout, stderr)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &ProbeRunResult{Tool: probeToolName(p), Outcome: ProbeTimedOut, Detail: fmt.Sprintf("no answer within %ds", probeTimeout(p))}, nil
+	}
+	if p.Type == "Exec" {
+		return execProbeResult(stdout.String(), stderr.String(), err)
+	}
+	if err != nil {
+		if isMissingShell(err) {
+			return nil, fmt.Errorf("%w: no sh", ErrNoProbeTool)
+		}
+		return nil, err
+	}
+	return parseProbeOutput(p.Type, stdout.String(), stderr.String())
+}
+
+func probeToolName(p *ProbeInfo) string {
+	if p.Type == "Exec" {
+		return "exec"
+	}
+	return ""
+}
+
+// execProbeResult reads the result of an exec probe from its exit code.
+func execProbeResult(stdout, stderr string, err error) (*ProbeRunResult, error) {
+	result := &ProbeRunResult{Tool: "exec", Outcome: ProbeSucceeded, Detail: strings.TrimSpace(stderr)}
+	result.Body, result.Truncated = truncateBody(stdout + stderr)
+	var exitErr utilexec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.Outcome = ProbeBadStatus
+		result.StatusCode = exitErr.ExitStatus()
+	case isMissingShell(err):
+		return nil, fmt.Errorf("exec probe command not found: %w", err)
+	default:
+		return nil, err
+	}
+	return result, nil
+}
+
+var (
+	httpStatusLine = regexp.MustCompile(`HTTP/[0-9.]+ ([0-9]{3})`)
+	grpcStatus     = regexp.MustCompile(`(?:status: |"status": ")([A-Z_]+)`)
+)
+
+// parseProbeOutput reads the output of a probe script: the tool's exit
+// code and, by tool, the HTTP status or gRPC serving status and latency.
+func parseProbeOutput(probeType, stdout, stderr string) (*ProbeRunResult, error) {
+	tool, exitCode, detail := "", -1, []string{}
+	for _, line := range strings.Split(stderr, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if rest, ok := strings.CutPrefix(line, probeMarker+"exit "); ok {
+			name, code, _ := strings.Cut(rest, " ")
+			tool = name
+			exitCode, _ = strconv.Atoi(code)
+			continue
+		}
+		// wget -S prints the response headers, indented
+		if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, "  ") {
+			detail = append(detail, strings.TrimSpace(line))
+		}
+	}
+	switch tool {
+	case "":
+		return nil, fmt.Errorf("probe script printed no result: %s", strings.TrimSpace(stderr))
+	case "none":
+		return nil, fmt.Errorf("%w (tried %s)", ErrNoProbeTool, map[string]string{
+			"HTTP": "curl, wget",
+			"TCP":  "nc, bash",
+			"gRPC": "grpc_health_probe, grpcurl",
+		}[probeType])
+	}
+
+	result := &ProbeRunResult{Tool: tool, Outcome: ProbeSucceeded, Detail: strings.Join(detail, "; ")}
+	body := stdout
+	switch tool {
+	case "curl":
+		// The status line is after the body, on a line of its own
+		if i := strings.LastIndex(stdout, "\n"+probeMarker+"status "); i >= 0 {
+			body = stdout[:i]
+			fields := strings.Fields(stdout[i+len("\n"+probeMarker+"status "):])
+			if len(fields) == 2 {
+				result.StatusCode, _ = strconv.Atoi(fields[0])
+				if seconds, err := strconv.ParseFloat(fields[1], 64); err == nil {
+					result.Latency = time.Duration(seconds * float64(time.Second)).Round(time.Microsecond)
+				}
+			}
+		}
+	case "wget":
+		if m := httpStatusLine.FindAllStringSubmatch(stderr, -1); len(m) > 0 {
+			result.StatusCode, _ = strconv.Atoi(m[len(m)-1][1])
+		}
+	case "grpc_health_probe", "grpcurl":
+		if m := grpcStatus.FindStringSubmatch(stdout + stderr); m != nil {
+			result.Status = m[1]
+		}
+		body = ""
+	}
+	result.Body, result.Truncated = truncateBody(body)
+
+	lower := strings.ToLower(result.Detail)
+	switch {
+	case strings.Contains(lower, "refused") || (tool == "curl" && exitCode == 7):
+		result.Outcome = ProbeRefused
+	case strings.Contains(lower, "timed out") || strings.Contains(lower, "timeout") ||
+		strings.Contains(lower, "deadline") || (tool == "curl" && exitCode == 28):
+		result.Outcome = ProbeTimedOut
+	case result.StatusCode != 0 && (result.StatusCode < 200 || result.StatusCode >= 400):
+		// The kubelet takes redirects as success too
+		result.Outcome = ProbeBadStatus
+	case result.Status != "" && result.Status != "SERVING":
+		result.Outcome = ProbeBadStatus
+	case exitCode != 0:
+		result.Outcome = ProbeErrored
+		if tool == "grpc_health_probe" && exitCode == 4 {
+			result.Outcome = ProbeBadStatus
+		}
+		if result.Detail == "" {
+			result.Detail = fmt.Sprintf("%s exited with %d", tool, exitCode)
+		}
+	}
+	return result, nil
+}
+
+// truncateBody keeps the first ProbeBodyLimit bytes of a response body.
+func truncateBody(body string) (string, bool) {
+	if len(body) <= ProbeBodyLimit {
+		return body, false
+	}
+	return body[:ProbeBodyLimit], true
+}
+
+// limitedBuffer keeps the first limit bytes written to it and drops the
+// rest, without failing the writes, so that the command runs to its end.
+type limitedBuffer struct {
+	buf   strings.Builder
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
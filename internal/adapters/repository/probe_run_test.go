@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+func TestBuildProbeCommand(t *testing.T) {
+	ports := []ContainerPort{{Name: "http", ContainerPort: 8080}, {Name: "grpc", ContainerPort: 9090}}
+	tests := []struct {
+		name    string
+		probe   ProbeInfo
+		want    []string // Parts the script must contain
+		notWant []string
+	}{
+		{
+			name:    "http on the pod IP",
+			probe:   ProbeInfo{Type: "HTTP", Path: "/healthz", Port: 8080, Timeout: 3},
+			want:    []string{"curl -sS -m 3 ", "'http://10.0.0.7:8080/healthz'", "wget -q -S -O - -T 3 ", "-A kube-probe/k1s"},
+			notWant: []string{" -k", "--no-check-certificate"},
+		},
+		{
+			name: "https with host header and named port",
+			probe: ProbeInfo{Type: "HTTP", Scheme: "HTTPS", Path: "ready", PortName: "http",
+				Headers: []ProbeHeader{{Name: "Host", Value: "api.example.com"}, {Name: "X-Token", Value: "it's"}}},
+			want: []string{"curl -sS -m 1 -k ", "'https://10.0.0.7:8080/ready'", "-H 'Host: api.example.com'",
+				`-H 'X-Token: it'\''s'`, "--header 'Host: api.example.com'", "--no-check-certificate"},
+		},
+		{
+			name:    "user agent header replaces the default",
+			probe:   ProbeInfo{Type: "HTTP", Port: 80, Headers: []ProbeHeader{{Name: "user-agent", Value: "lb"}}},
+			want:    []string{"-H 'user-agent: lb'", "'http://10.0.0.7:80'"},
+			notWant: []string{"kube-probe"},
+		},
+		{
+			name:  "http with a host",
+			probe: ProbeInfo{Type: "HTTP", Host: "127.0.0.1", Port: 80},
+			want:  []string{"'http://127.0.0.1:80'"},
+		},
+		{
+			name:  "tcp",
+			probe: ProbeInfo{Type: "TCP", Port: 5432, Timeout: 2},
+			want:  []string{"nc -w 2 10.0.0.7 5432 </dev/null", "exec 3<>/dev/tcp/10.0.0.7/5432"},
+		},
+		{
+			name:  "grpc with service",
+			probe: ProbeInfo{Type: "gRPC", Port: 9090, Service: "liveness", Timeout: 5},
+			want: []string{"grpc_health_probe -addr=10.0.0.7:9090 -connect-timeout=5s -rpc-timeout=5s -service='liveness'",
+				`grpcurl -plaintext -max-time 5 -d '{"service":"liveness"}' 10.0.0.7:9090 grpc.health.v1.Health/Check`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, err := BuildProbeCommand(&tt.probe, "10.0.0.7", ports)
+			if err != nil {
+				t.Fatalf("BuildProbeCommand() error = %v", err)
+			}
+			if len(command) != 3 || command[0] != "sh" || command[1] != "-c" {
+				t.Fatalf("command = %q, want sh -c script", command)
+			}
+			for _, part := range tt.want {
+				if !strings.Contains(command[2], part) {
+					t.Errorf("script lacks %q:\n%s", part, command[2])
+				}
+			}
+			for _, part := range tt.notWant {
+				if strings.Contains(command[2], part) {
+					t.Errorf("script has %q:\n%s", part, command[2])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildProbeCommand_Errors(t *testing.T) {
+	if command, err := BuildProbeCommand(&ProbeInfo{Type: "Exec", Command: []string{"cat", "/tmp/ok"}}, "", nil); err != nil || strings.Join(command, " ") != "cat /tmp/ok" {
+		t.Errorf("exec probe: command = %q, error = %v; want its own command", command, err)
+	}
+	if _, err := BuildProbeCommand(&ProbeInfo{Type: "HTTP", PortName: "metrics"}, "10.0.0.7", nil); err == nil {
+		t.Error("an unknown named port should be an error")
+	}
+	if _, err := BuildProbeCommand(&ProbeInfo{Type: "TCP", Host: "db; rm -rf /", Port: 1}, "", nil); err == nil {
+		t.Error("a host with shell characters should be an error")
+	}
+	command, _ := BuildProbeCommand(&ProbeInfo{Type: "TCP", Port: 1}, "", nil)
+	if !strings.Contains(command[2], "127.0.0.1 1") {
+		t.Errorf("without a pod IP the probe should target localhost:\n%s", command[2])
+	}
+}
+
+func TestParseProbeOutput(t *testing.T) {
+	tests := []struct {
+		name      string
+		probeType string
+		stdout    string
+		stderr    string
+		want      ProbeRunResult
+	}{
+		{
+			name:      "curl ok",
+			probeType: "HTTP",
+			stdout:    "ok\n@k1s status 200 0.012345\n",
+			stderr:    "@k1s exit curl 0\n",
+			want:      ProbeRunResult{Tool: "curl", Outcome: ProbeSucceeded, StatusCode: 200, Latency: 12345 * time.Microsecond, Body: "ok"},
+		},
+		{
+			name:      "curl redirect counts as success",
+			probeType: "HTTP",
+			stdout:    "\n@k1s status 302 0.001\n",
+			stderr:    "@k1s exit curl 0\n",
+			want:      ProbeRunResult{Tool: "curl", Outcome: ProbeSucceeded, StatusCode: 302, Latency: time.Millisecond},
+		},
+		{
+			name:      "curl 503",
+			probeType: "HTTP",
+			stdout:    `{"db":"down"}` + "\n@k1s status 503 0.002\n",
+			stderr:    "@k1s exit curl 0\n",
+			want:      ProbeRunResult{Tool: "curl", Outcome: ProbeBadStatus, StatusCode: 503, Latency: 2 * time.Millisecond, Body: `{"db":"down"}`},
+		},
+		{
+			name:      "curl refused",
+			probeType: "HTTP",
+			stdout:    "\n@k1s status 000 0.000\n",
+			stderr:    "curl: (7) Failed to connect to 10.0.0.7 port 8080: Connection refused\n@k1s exit curl 7\n",
+			want: ProbeRunResult{Tool: "curl", Outcome: ProbeRefused,
+				Detail: "curl: (7) Failed to connect to 10.0.0.7 port 8080: Connection refused"},
+		},
+		{
+			name:      "curl timeout",
+			probeType: "HTTP",
+			stdout:    "\n@k1s status 000 1.001\n",
+			stderr:    "curl: (28) Operation timed out after 1001 milliseconds with 0 bytes received\n@k1s exit curl 28\n",
+			want: ProbeRunResult{Tool: "curl", Outcome: ProbeTimedOut, Latency: 1001 * time.Millisecond,
+				Detail: "curl: (28) Operation timed out after 1001 milliseconds with 0 bytes received"},
+		},
+		{
+			name:      "wget 500",
+			probeType: "HTTP",
+			stderr:    "  HTTP/1.1 500 Internal Server Error\nwget: server returned error: HTTP/1.1 500 Internal Server Error\n@k1s exit wget 1\n",
+			want: ProbeRunResult{Tool: "wget", Outcome: ProbeBadStatus, StatusCode: 500,
+				Detail: "wget: server returned error: HTTP/1.1 500 Internal Server Error"},
+		},
+		{
+			name:      "wget ok",
+			probeType: "HTTP",
+			stdout:    "healthy",
+			stderr:    "  HTTP/1.1 200 OK\n  Content-Length: 7\n@k1s exit wget 0\n",
+			want:      ProbeRunResult{Tool: "wget", Outcome: ProbeSucceeded, StatusCode: 200, Body: "healthy"},
+		},
+		{
+			name:      "nc open",
+			probeType: "TCP",
+			stderr:    "@k1s exit nc 0\n",
+			want:      ProbeRunResult{Tool: "nc", Outcome: ProbeSucceeded},
+		},
+		{
+			name:      "nc refused",
+			probeType: "TCP",
+			stderr:    "nc: can't connect to remote host (10.0.0.7): Connection refused\n@k1s exit nc 1\n",
+			want:      ProbeRunResult{Tool: "nc", Outcome: ProbeRefused, Detail: "nc: can't connect to remote host (10.0.0.7): Connection refused"},
+		},
+		{
+			name:      "grpc_health_probe not serving",
+			probeType: "gRPC",
+			stderr:    "service unhealthy (responded with \"NOT_SERVING\")\n@k1s exit grpc_health_probe 4\n",
+			want: ProbeRunResult{Tool: "grpc_health_probe", Outcome: ProbeBadStatus,
+				Detail: `service unhealthy (responded with "NOT_SERVING")`},
+		},
+		{
+			name:      "grpc_health_probe serving",
+			probeType: "gRPC",
+			stdout:    "status: SERVING\n",
+			stderr:    "@k1s exit grpc_health_probe 0\n",
+			want:      ProbeRunResult{Tool: "grpc_health_probe", Outcome: ProbeSucceeded, Status: "SERVING"},
+		},
+		{
+			name:      "grpcurl not serving",
+			probeType: "gRPC",
+			stdout:    "{\n  \"status\": \"NOT_SERVING\"\n}\n",
+			stderr:    "@k1s exit grpcurl 0\n",
+			want:      ProbeRunResult{Tool: "grpcurl", Outcome: ProbeBadStatus, Status: "NOT_SERVING"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseProbeOutput(tt.probeType, tt.stdout, tt.stderr)
+			if err != nil {
+				t.Fatalf("parseProbeOutput() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("parseProbeOutput() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProbeOutput_Body(t *testing.T) {
+	body := strings.Repeat("x", ProbeBodyLimit+10)
+	got, err := parseProbeOutput("HTTP", body+"\n@k1s status 200 0.1\n", "@k1s exit curl 0\n")
+	if err != nil {
+		t.Fatalf("parseProbeOutput() error = %v", err)
+	}
+	if len(got.Body) != ProbeBodyLimit || !got.Truncated {
+		t.Errorf("body of %d bytes, truncated %v; want %d, true", len(got.Body), got.Truncated, ProbeBodyLimit)
+	}
+
+	if _, err := parseProbeOutput("TCP", "", "@k1s exit none 127\n"); !errors.Is(err, ErrNoProbeTool) || !strings.Contains(err.Error(), "nc, bash") {
+		t.Errorf("no tools: error = %v, want ErrNoProbeTool naming them", err)
+	}
+}
+
+func TestRunProbe_Exec(t *testing.T) {
+	probe := &ProbeInfo{Type: "Exec", Command: []string{"pg_isready"}}
+	run := func(ctx context.Context, command []string, stdout, stderr io.Writer) error {
+		io.WriteString(stdout, "localhost:5432 - no response\n")
+		return utilexec.CodeExitError{Err: errors.New("command terminated with exit code 2"), Code: 2}
+	}
+	got, err := runProbe(context.Background(), run, probe, "", nil)
+	if err != nil {
+		t.Fatalf("runProbe() error = %v", err)
+	}
+	if got.Outcome != ProbeBadStatus || got.StatusCode != 2 || got.Summary() != "exit 2" || !strings.Contains(got.Body, "no response") {
+		t.Errorf("runProbe() = %+v, want exit 2 with its output", *got)
+	}
+}
+
+func TestRunProbe_NoShell(t *testing.T) {
+	runner := &fakeRunner{}
+	_, err := runProbe(context.Background(), runner.run, &ProbeInfo{Type: "HTTP", Port: 80}, "10.0.0.7", nil)
+	if !errors.Is(err, ErrNoProbeTool) {
+		t.Errorf("runProbe() error = %v, want ErrNoProbeTool", err)
+	}
+}
+
+func TestProbeRunResult_Summary(t *testing.T) {
+	tests := []struct {
+		result ProbeRunResult
+		want   string
+	}{
+		{ProbeRunResult{Outcome: ProbeSucceeded, StatusCode: 200, Latency: 12 * time.Millisecond}, "HTTP 200 in 12ms"},
+		{ProbeRunResult{Outcome: ProbeSucceeded, Tool: "nc"}, "connection open"},
+		{ProbeRunResult{Outcome: ProbeBadStatus, Status: "NOT_SERVING"}, "NOT_SERVING"},
+		{ProbeRunResult{Outcome: ProbeRefused}, "connection refused"},
+		{ProbeRunResult{Outcome: ProbeTimedOut, Detail: "no answer within 1s"}, "timed out: no answer within 1s"},
+		{ProbeRunResult{Outcome: ProbeErrored, Detail: "curl: (6) Could not resolve host"}, "failed: curl: (6) Could not resolve host"},
+	}
+	for _, tt := range tests {
+		if got := tt.result.Summary(); got != tt.want {
+			t.Errorf("Summary() = %q, want %q", got, tt.want)
+		}
+	}
+}
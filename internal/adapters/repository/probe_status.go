@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ProbeState is the computed health of one container probe.
+type ProbeState string
+
+const (
+	ProbePassing ProbeState = "passing"
+	ProbeFailing ProbeState = "failing"
+	ProbeUnknown ProbeState = "unknown" // Container not running, or not started yet
+)
+
+// probeKinds are the probe types in the order the kubelet runs them.
+var probeKinds = []string{"Startup", "Liveness", "Readiness"}
+
+// ProbeStatus is the configuration of a container probe with its status,
+// computed from the container state and the pod's Unhealthy events.
+type ProbeStatus struct {
+	Container     string
+	Probe         string // "Startup", "Liveness" or "Readiness"
+	Config        *ProbeInfo
+	State         ProbeState
+	Failures      int32     // Failed checks in the pod's events, since the container started
+	LastFailure   string    // Message of the most recent failure, e.g. "HTTP probe failed with statuscode: 500"
+	LastFailureAt time.Time // When the most recent failure was seen
+}
+
+// Target describes what the probe checks, e.g. "/health:8080".
+func (s ProbeStatus) Target() string {
+	return probeTarget(s.Config)
+}
+
+// Timing describes when the probe runs and how many failures it tolerates.
+func (s ProbeStatus) Timing() string {
+	return fmt.Sprintf("delay %ds, every %ds, timeout %ds, %d failures",
+		s.Config.InitialDelay, s.Config.Period, s.Config.Timeout, s.Config.FailureThreshold)
+}
+
+// BuildProbeStatus returns the status of every probe of the pod's
+// containers, per container in startup, liveness, readiness order.
+// Unhealthy events don't name the container, so they are attributed as
+// the crash analyzer does (see probedContainer), preferring a container
+// that is not ready for readiness failures. Failures from before the
+// container last started are ignored, as they belong to a previous
+// instance.
+//
+// A readiness probe passes when the container is ready. Startup and
+// liveness probes are failing when they failed since the container
+// started; a startup probe without failures is unknown until the
+// container is ready.
+func BuildProbeStatus(pod *PodInfo, events []EventInfo) []ProbeStatus {
+	if pod == nil {
+		return nil
+	}
+
+	failures := make(map[string][]EventInfo) // Keyed by container/probe
+	for _, probe := range probeKinds {
+		prefix := probe + " probe failed"
+		var matching []EventInfo
+		for _, e := range events {
+			if e.Reason == "Unhealthy" && strings.HasPrefix(e.Message, prefix) {
+				matching = append(matching, e)
+			}
+		}
+		if len(matching) == 0 {
+			continue
+		}
+		if c := probeEventContainer(pod, probe, events); c != nil {
+			failures[c.Name+"/"+probe] = matching
+		}
+	}
+
+	var result []ProbeStatus
+	for i := range pod.Containers {
+		c := &pod.Containers[i]
+		started := containerStartTime(c)
+		for _, probe := range probeKinds {
+			info := containerProbe(c, probe)
+			if info == nil {
+				continue
+			}
+			s := ProbeStatus{Container: c.Name, Probe: probe, Config: info}
+			for _, e := range failures[c.Name+"/"+probe] {
+				if !started.IsZero() && e.LastSeen.Before(started) {
+					continue
+				}
+				s.Failures += max(e.Count, 1)
+				if s.LastFailureAt.IsZero() || e.LastSeen.After(s.LastFailureAt) {
+					s.LastFailure = strings.TrimPrefix(e.Message, probe+" probe failed: ")
+					s.LastFailureAt = e.LastSeen
+				}
+			}
+			s.State = probeState(c, probe, s.Failures > 0)
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// probeState computes the state of a probe of a container.
+func probeState(c *ContainerInfo, probe string, failed bool) ProbeState {
+	if c.State != "Running" {
+		return ProbeUnknown
+	}
+	switch probe {
+	case "Readiness":
+		if c.Ready {
+			return ProbePassing
+		}
+		if failed || c.StartupProbe == nil {
+			return ProbeFailing
+		}
+		// Readiness is not checked until the startup probe passes
+		return ProbeUnknown
+	case "Startup":
+		// A ready container has passed its startup probe
+		if c.Ready {
+			return ProbePassing
+		}
+		if failed {
+			return ProbeFailing
+		}
+		return ProbeUnknown
+	}
+	if failed {
+		return ProbeFailing
+	}
+	return ProbePassing
+}
+
+// probeEventContainer picks the container the Unhealthy events of a probe
+// type are about. A running container that is not ready is the likely
+// source of readiness failures; otherwise the pick is probedContainer's.
+func probeEventContainer(pod *PodInfo, probe string, events []EventInfo) *ContainerInfo {
+	if probe == "Readiness" {
+		for i := range pod.Containers {
+			c := &pod.Containers[i]
+			if c.ReadinessProbe != nil && c.State == "Running" && !c.Ready {
+				return c
+			}
+		}
+	}
+	c, _ := probedContainer(pod, probe, events)
+	return c
+}
+
+// containerStartTime parses when the container last started, zero when
+// unknown. StartedAt is formatted in local time by GetPod.
+func containerStartTime(c *ContainerInfo) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", c.StartedAt, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildProbeStatus(t *testing.T) {
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	startedAt := started.Format("2006-01-02 15:04:05")
+	httpProbe := &ProbeInfo{Type: "HTTP", Path: "/health", Port: 8080, Period: 10, Timeout: 1, FailureThreshold: 3}
+	tcpProbe := &ProbeInfo{Type: "TCP", Port: 5432, Period: 5, Timeout: 1, FailureThreshold: 3}
+	execProbe := &ProbeInfo{Type: "Exec", Command: []string{"cat", "/tmp/ready"}, Period: 5, Timeout: 1, FailureThreshold: 3}
+	grpcProbe := &ProbeInfo{Type: "gRPC", Port: 9090, Period: 10, Timeout: 1, FailureThreshold: 30}
+	unhealthy := func(message string, count int32, seen time.Time) EventInfo {
+		return EventInfo{Reason: "Unhealthy", Type: "Warning", Message: message, Count: count, LastSeen: seen}
+	}
+
+	tests := []struct {
+		name         string
+		container    ContainerInfo
+		events       []EventInfo
+		wantProbe    string
+		wantTarget   string
+		wantState    ProbeState
+		wantFailures int32
+		wantLast     string
+	}{
+		{
+			name:       "HTTP readiness passing",
+			container:  ContainerInfo{Name: "web", State: "Running", Ready: true, StartedAt: startedAt, ReadinessProbe: httpProbe},
+			wantProbe:  "Readiness",
+			wantTarget: "/health:8080",
+			wantState:  ProbePassing,
+		},
+		{
+			name:      "HTTP readiness failing",
+			container: ContainerInfo{Name: "web", State: "Running", StartedAt: startedAt, ReadinessProbe: httpProbe},
+			events: []EventInfo{
+				unhealthy("Readiness probe failed: HTTP probe failed with statuscode: 503", 12, started.Add(5*time.Minute)),
+				unhealthy("Readiness probe failed: Get \"http://10.0.0.1:8080/health\": context deadline exceeded", 3, started.Add(time.Minute)),
+			},
+			wantProbe:    "Readiness",
+			wantTarget:   "/health:8080",
+			wantState:    ProbeFailing,
+			wantFailures: 15,
+			wantLast:     "HTTP probe failed with statuscode: 503",
+		},
+		{
+			name:      "TCP liveness failing",
+			container: ContainerInfo{Name: "db", State: "Running", Ready: true, StartedAt: startedAt, LivenessProbe: tcpProbe},
+			events: []EventInfo{
+				unhealthy("Liveness probe failed: dial tcp 10.0.0.1:5432: connect: connection refused", 2, started.Add(time.Minute)),
+			},
+			wantProbe:    "Liveness",
+			wantTarget:   "tcp:5432",
+			wantState:    ProbeFailing,
+			wantFailures: 2,
+			wantLast:     "dial tcp 10.0.0.1:5432: connect: connection refused",
+		},
+		{
+			name:      "TCP liveness failures of a previous instance",
+			container: ContainerInfo{Name: "db", State: "Running", Ready: true, StartedAt: startedAt, LivenessProbe: tcpProbe},
+			events: []EventInfo{
+				unhealthy("Liveness probe failed: dial tcp 10.0.0.1:5432: connect: connection refused", 3, started.Add(-time.Minute)),
+			},
+			wantProbe:  "Liveness",
+			wantTarget: "tcp:5432",
+			wantState:  ProbePassing,
+		},
+		{
+			name:      "exec readiness in a waiting container",
+			container: ContainerInfo{Name: "worker", State: "Waiting", Reason: "CrashLoopBackOff", ReadinessProbe: execProbe},
+			events: []EventInfo{
+				unhealthy("Readiness probe failed: cat: can't open '/tmp/ready': No such file or directory", 1, started),
+			},
+			wantProbe:    "Readiness",
+			wantTarget:   "exec cat /tmp/ready",
+			wantState:    ProbeUnknown,
+			wantFailures: 1,
+			wantLast:     "cat: can't open '/tmp/ready': No such file or directory",
+		},
+		{
+			name:      "gRPC startup still starting",
+			container: ContainerInfo{Name: "api", State: "Running", StartedAt: startedAt, StartupProbe: grpcProbe},
+			wantProbe: "Startup", wantTarget: "grpc:9090",
+			wantState: ProbeUnknown,
+		},
+		{
+			name:      "gRPC startup failing",
+			container: ContainerInfo{Name: "api", State: "Running", StartedAt: startedAt, StartupProbe: grpcProbe},
+			events: []EventInfo{
+				unhealthy("Startup probe failed: service unhealthy (responded with \"NOT_SERVING\")", 8, started.Add(time.Minute)),
+			},
+			wantProbe:    "Startup",
+			wantTarget:   "grpc:9090",
+			wantState:    ProbeFailing,
+			wantFailures: 8,
+			wantLast:     "service unhealthy (responded with \"NOT_SERVING\")",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &PodInfo{Containers: []ContainerInfo{tt.container}}
+			got := BuildProbeStatus(pod, tt.events)
+			if len(got) != 1 {
+				t.Fatalf("BuildProbeStatus() = %+v, want one probe", got)
+			}
+			s := got[0]
+			if s.Container != tt.container.Name || s.Probe != tt.wantProbe || s.Target() != tt.wantTarget {
+				t.Errorf("probe = %s %s %s, want %s %s %s", s.Container, s.Probe, s.Target(), tt.container.Name, tt.wantProbe, tt.wantTarget)
+			}
+			if s.State != tt.wantState {
+				t.Errorf("State = %s, want %s", s.State, tt.wantState)
+			}
+			if s.Failures != tt.wantFailures || s.LastFailure != tt.wantLast {
+				t.Errorf("failures = %d %q, want %d %q", s.Failures, s.LastFailure, tt.wantFailures, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestBuildProbeStatus_MultipleContainers(t *testing.T) {
+	httpProbe := &ProbeInfo{Type: "HTTP", Path: "/ready", Port: 8080, Period: 10, Timeout: 1, FailureThreshold: 3}
+	pod := &PodInfo{Containers: []ContainerInfo{
+		{Name: "app", State: "Running", Ready: true, LivenessProbe: httpProbe, ReadinessProbe: httpProbe, StartupProbe: httpProbe},
+		{Name: "sidecar", State: "Running", ReadinessProbe: httpProbe},
+	}}
+	events := []EventInfo{{Reason: "Unhealthy", Message: "Readiness probe failed: HTTP probe failed with statuscode: 500", Count: 4}}
+
+	got := BuildProbeStatus(pod, events)
+	if len(got) != 4 {
+		t.Fatalf("BuildProbeStatus() = %+v, want 4 probes", got)
+	}
+	// Per container, in the order the kubelet runs them
+	for i, want := range []string{"app/Startup", "app/Liveness", "app/Readiness", "sidecar/Readiness"} {
+		if got[i].Container+"/"+got[i].Probe != want {
+			t.Errorf("got[%d] = %s/%s, want %s", i, got[i].Container, got[i].Probe, want)
+		}
+	}
+	// Readiness failures belong to the container that is not ready
+	if got[2].State != ProbePassing || got[2].Failures != 0 {
+		t.Errorf("app readiness = %+v, want passing without failures", got[2])
+	}
+	if got[3].State != ProbeFailing || got[3].Failures != 4 {
+		t.Errorf("sidecar readiness = %+v, want 4 failures", got[3])
+	}
+	if BuildProbeStatus(nil, events) != nil {
+		t.Error("nil pod should have no probes")
+	}
+}
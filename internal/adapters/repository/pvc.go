@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PVCDetails combines a PersistentVolumeClaim with the StorageClass and
+// PersistentVolume behind it, plus the claim's own events. When a claim is
+// stuck Pending the reason is usually in one of these.
+type PVCDetails struct {
+	Name             string            // PVC name
+	Namespace        string            // PVC namespace
+	Status           string            // Pending, Bound, Lost
+	RequestedSize    string            // Requested storage (spec)
+	Capacity         string            // Actual capacity once bound
+	AccessModes      []string          // Requested access modes
+	StorageClassName string            // StorageClass referenced by the claim
+	VolumeName       string            // Bound PersistentVolume name
+	StorageClass     *StorageClassInfo // nil when the class is unset or missing
+	Volume           *PersistentVolumeInfo
+	Events           []EventInfo // PVC events, most recent first
+}
+
+// PVCInfo summarizes a PersistentVolumeClaim mounted by a pod, as listed
+// in RelatedResources.
+type PVCInfo struct {
+	Name         string   // PVC name
+	Volume       string   // Pod volume that mounts the claim
+	StorageClass string   // StorageClass referenced by the claim
+	Capacity     string   // Bound volume capacity, or the requested size while unbound
+	AccessModes  []string // Requested access modes
+	Phase        string   // Bound, Pending or Lost; empty when Missing or Error is set
+	VolumeName   string   // Bound PersistentVolume name
+	Missing      bool     // The claim does not exist
+	Error        string   // Why the claim could not be read, e.g. forbidden
+}
+
+// BlocksScheduling reports whether the claim keeps its pod from being
+// scheduled or started: it does not exist, is not bound yet, or lost its
+// volume. A claim that could not be read is not known to block it.
+func (p PVCInfo) BlocksScheduling() bool {
+	if p.Error != "" {
+		return false
+	}
+	return p.Missing || p.Phase != string(corev1.ClaimBound)
+}
+
+// StorageClassInfo holds the StorageClass fields relevant to provisioning.
+type StorageClassInfo struct {
+	Name                 string
+	Provisioner          string
+	VolumeBindingMode    string // Immediate or WaitForFirstConsumer
+	ReclaimPolicy        string
+	AllowVolumeExpansion bool
+}
+
+// PersistentVolumeInfo holds the bound PersistentVolume details.
+type PersistentVolumeInfo struct {
+	Name          string
+	Capacity      string
+	ReclaimPolicy string
+	Status        string
+	NodeAffinity  []string // Required node selector terms, one per term
+}
+
+// WaitsForFirstConsumer reports whether the claim is pending only because
+// its StorageClass delays binding until a consuming pod is scheduled.
+func (d *PVCDetails) WaitsForFirstConsumer() bool {
+	return d.Status == string(corev1.ClaimPending) &&
+		d.StorageClass != nil &&
+		d.StorageClass.VolumeBindingMode == string(storagev1.VolumeBindingWaitForFirstConsumer)
+}
+
+// GetPVCDetails fetches a PVC together with its StorageClass, bound PV and events.
+// Missing StorageClass or PV objects are not errors; they are left nil so the
+// caller can show what is absent.
+func GetPVCDetails(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (*PVCDetails, error) {
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pvc: %w", err)
+	}
+
+	details := &PVCDetails{
+		Name:       pvc.Name,
+		Namespace:  pvc.Namespace,
+		Status:     string(pvc.Status.Phase),
+		VolumeName: pvc.Spec.VolumeName,
+	}
+	if req, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		details.RequestedSize = req.String()
+	}
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		details.Capacity = capacity.String()
+	}
+	for _, mode := range pvc.Spec.AccessModes {
+		details.AccessModes = append(details.AccessModes, string(mode))
+	}
+	if pvc.Spec.StorageClassName != nil {
+		details.StorageClassName = *pvc.Spec.StorageClassName
+	}
+
+	if details.StorageClassName != "" {
+		sc, err := clientset.StorageV1().StorageClasses().Get(ctx, details.StorageClassName, metav1.GetOptions{})
+		if err == nil {
+			details.StorageClass = storageClassToInfo(sc)
+		}
+	}
+
+	if details.VolumeName != "" {
+		pv, err := clientset.CoreV1().PersistentVolumes().Get(ctx, details.VolumeName, metav1.GetOptions{})
+		if err == nil {
+			details.Volume = persistentVolumeToInfo(pv)
+		}
+	}
+
+	events, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "involvedObject.name=" + name,
+	})
+	if err == nil {
+		var pvcEvents []corev1.Event
+		for _, e := range events.Items {
+			if e.InvolvedObject.Kind == "PersistentVolumeClaim" && e.InvolvedObject.Name == name {
+				pvcEvents = append(pvcEvents, e)
+			}
+		}
+		details.Events = eventsToEventInfo(pvcEvents)
+	}
+
+	return details, nil
+}
+
+func storageClassToInfo(sc *storagev1.StorageClass) *StorageClassInfo {
+	info := &StorageClassInfo{
+		Name:              sc.Name,
+		Provisioner:       sc.Provisioner,
+		VolumeBindingMode: string(storagev1.VolumeBindingImmediate), // API default
+		ReclaimPolicy:     string(corev1.PersistentVolumeReclaimDelete),
+	}
+	if sc.VolumeBindingMode != nil {
+		info.VolumeBindingMode = string(*sc.VolumeBindingMode)
+	}
+	if sc.ReclaimPolicy != nil {
+		info.ReclaimPolicy = string(*sc.ReclaimPolicy)
+	}
+	if sc.AllowVolumeExpansion != nil {
+		info.AllowVolumeExpansion = *sc.AllowVolumeExpansion
+	}
+	return info
+}
+
+func persistentVolumeToInfo(pv *corev1.PersistentVolume) *PersistentVolumeInfo {
+	info := &PersistentVolumeInfo{
+		Name:          pv.Name,
+		ReclaimPolicy: string(pv.Spec.PersistentVolumeReclaimPolicy),
+		Status:        string(pv.Status.Phase),
+	}
+	if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+		info.Capacity = capacity.String()
+	}
+	if pv.Spec.NodeAffinity != nil && pv.Spec.NodeAffinity.Required != nil {
+		for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+			var exprs []string
+			for _, expr := range term.MatchExpressions {
+				exprs = append(exprs, fmt.Sprintf("%s %s [%s]", expr.Key, expr.Operator, strings.Join(expr.Values, ", ")))
+			}
+			if len(exprs) > 0 {
+				info.NodeAffinity = append(info.NodeAffinity, strings.Join(exprs, " && "))
+			}
+		}
+	}
+	return info
+}
+
+// getPodPVCs returns the PersistentVolumeClaims mounted by a pod's volumes,
+// including the claims created for generic ephemeral volumes. Claims that
+// do not exist are returned with Missing set, and claims that could not be
+// read, e.g. without RBAC permissions, with the error in Error.
+func getPodPVCs(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) []PVCInfo {
+	var pvcs []PVCInfo
+	for _, vol := range pod.Spec.Volumes {
+		var claimName string
+		switch {
+		case vol.PersistentVolumeClaim != nil:
+			claimName = vol.PersistentVolumeClaim.ClaimName
+		case vol.Ephemeral != nil:
+			claimName = pod.Name + "-" + vol.Name
+		default:
+			continue
+		}
+
+		info := PVCInfo{Name: claimName, Volume: vol.Name}
+		pvc, err := clientset.CoreV1().PersistentVolumeClaims(pod.Namespace).Get(ctx, claimName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				info.Missing = true
+			} else {
+				info.Error = err.Error()
+			}
+			pvcs = append(pvcs, info)
+			continue
+		}
+
+		info.Phase = string(pvc.Status.Phase)
+		info.VolumeName = pvc.Spec.VolumeName
+		if pvc.Spec.StorageClassName != nil {
+			info.StorageClass = *pvc.Spec.StorageClassName
+		}
+		for _, mode := range pvc.Spec.AccessModes {
+			info.AccessModes = append(info.AccessModes, string(mode))
+		}
+		if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+			info.Capacity = capacity.String()
+		} else if req, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			info.Capacity = req.String()
+		}
+		if info.VolumeName != "" {
+			pv, err := clientset.CoreV1().PersistentVolumes().Get(ctx, info.VolumeName, metav1.GetOptions{})
+			if err == nil {
+				if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+					info.Capacity = capacity.String()
+				}
+				if info.StorageClass == "" {
+					info.StorageClass = pv.Spec.StorageClassName
+				}
+			}
+		}
+		pvcs = append(pvcs, info)
+	}
+	return pvcs
+}
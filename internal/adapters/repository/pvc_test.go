@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func testPVC(name, class, volume string, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &class,
+			VolumeName:       volume,
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+}
+
+func testStorageClass(name string, mode storagev1.VolumeBindingMode) *storagev1.StorageClass {
+	expand := true
+	return &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: name},
+		Provisioner:          "ebs.csi.aws.com",
+		VolumeBindingMode:    &mode,
+		AllowVolumeExpansion: &expand,
+	}
+}
+
+func TestGetPVCDetails_Unbound(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		testPVC("data", "gp3", "", corev1.ClaimPending),
+		testStorageClass("gp3", storagev1.VolumeBindingImmediate),
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "data.1", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "PersistentVolumeClaim", Name: "data"},
+			Type:           "Warning",
+			Reason:         "ProvisioningFailed",
+			Message:        "failed to provision volume: UnauthorizedOperation",
+			LastTimestamp:  metav1.Time{Time: time.Now()},
+		},
+		// Event for a pod with the same name must not be included
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "data.2", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "data"},
+			Reason:         "Scheduled",
+		},
+	)
+
+	ctx := context.Background()
+	details, err := GetPVCDetails(ctx, clientset, "default", "data")
+	if err != nil {
+		t.Fatalf("GetPVCDetails() error = %v", err)
+	}
+
+	if details.Status != "Pending" {
+		t.Errorf("Status = %q, want Pending", details.Status)
+	}
+	if details.RequestedSize != "10Gi" {
+		t.Errorf("RequestedSize = %q, want 10Gi", details.RequestedSize)
+	}
+	if details.StorageClass == nil || details.StorageClass.Provisioner != "ebs.csi.aws.com" {
+		t.Fatalf("StorageClass = %+v, want provisioner ebs.csi.aws.com", details.StorageClass)
+	}
+	if !details.StorageClass.AllowVolumeExpansion {
+		t.Error("AllowVolumeExpansion should be true")
+	}
+	if details.Volume != nil {
+		t.Errorf("Volume = %+v, want nil for unbound claim", details.Volume)
+	}
+	if details.WaitsForFirstConsumer() {
+		t.Error("WaitsForFirstConsumer() should be false for Immediate binding")
+	}
+	if len(details.Events) != 1 {
+		t.Fatalf("Events = %d, want 1", len(details.Events))
+	}
+	if details.Events[0].Message != "failed to provision volume: UnauthorizedOperation" {
+		t.Errorf("Event message = %q, want verbatim ProvisioningFailed message", details.Events[0].Message)
+	}
+}
+
+func TestGetPVCDetails_Bound(t *testing.T) {
+	pvc := testPVC("data", "gp3", "pv-123", corev1.ClaimBound)
+	pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}
+
+	clientset := fake.NewSimpleClientset(
+		pvc,
+		testStorageClass("gp3", storagev1.VolumeBindingWaitForFirstConsumer),
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-123"},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity:                      corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+				NodeAffinity: &corev1.VolumeNodeAffinity{
+					Required: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{
+								Key:      "topology.kubernetes.io/zone",
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{"us-east-1a"},
+							}},
+						}},
+					},
+				},
+			},
+			Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound},
+		},
+	)
+
+	ctx := context.Background()
+	details, err := GetPVCDetails(ctx, clientset, "default", "data")
+	if err != nil {
+		t.Fatalf("GetPVCDetails() error = %v", err)
+	}
+
+	if details.Capacity != "10Gi" {
+		t.Errorf("Capacity = %q, want 10Gi", details.Capacity)
+	}
+	if details.Volume == nil {
+		t.Fatal("Volume should be set for bound claim")
+	}
+	if details.Volume.ReclaimPolicy != "Retain" {
+		t.Errorf("ReclaimPolicy = %q, want Retain", details.Volume.ReclaimPolicy)
+	}
+	if len(details.Volume.NodeAffinity) != 1 || details.Volume.NodeAffinity[0] != "topology.kubernetes.io/zone In [us-east-1a]" {
+		t.Errorf("NodeAffinity = %v", details.Volume.NodeAffinity)
+	}
+	// Bound claims never wait, whatever the binding mode
+	if details.WaitsForFirstConsumer() {
+		t.Error("WaitsForFirstConsumer() should be false for bound claim")
+	}
+}
+
+func TestGetPVCDetails_WaitForFirstConsumer(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		testPVC("data", "gp3", "", corev1.ClaimPending),
+		testStorageClass("gp3", storagev1.VolumeBindingWaitForFirstConsumer),
+	)
+
+	ctx := context.Background()
+	details, err := GetPVCDetails(ctx, clientset, "default", "data")
+	if err != nil {
+		t.Fatalf("GetPVCDetails() error = %v", err)
+	}
+
+	if !details.WaitsForFirstConsumer() {
+		t.Error("WaitsForFirstConsumer() should be true for pending WaitForFirstConsumer claim")
+	}
+	if details.StorageClass.VolumeBindingMode != "WaitForFirstConsumer" {
+		t.Errorf("VolumeBindingMode = %q", details.StorageClass.VolumeBindingMode)
+	}
+}
+
+func TestGetPVCDetails_MissingStorageClass(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		testPVC("data", "does-not-exist", "", corev1.ClaimPending),
+	)
+
+	ctx := context.Background()
+	details, err := GetPVCDetails(ctx, clientset, "default", "data")
+	if err != nil {
+		t.Fatalf("GetPVCDetails() error = %v", err)
+	}
+	if details.StorageClassName != "does-not-exist" {
+		t.Errorf("StorageClassName = %q", details.StorageClassName)
+	}
+	if details.StorageClass != nil {
+		t.Error("StorageClass should be nil when the class does not exist")
+	}
+}
+
+func TestGetPVCDetails_NotFound(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	ctx := context.Background()
+	_, err := GetPVCDetails(ctx, clientset, "default", "missing")
+	if err == nil {
+		t.Error("GetPVCDetails() should return error for nonexistent PVC")
+	}
+}
+
+func TestGetRelatedResources_PVCs(t *testing.T) {
+	bound := testPVC("data", "gp3", "pv-123", corev1.ClaimBound)
+	bound.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{Name: "data", VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+				}},
+				{Name: "wal", VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "wal"},
+				}},
+				{Name: "backup", VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "backup"},
+				}},
+				{Name: "config", VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db-config"}},
+				}},
+			},
+		},
+	}
+
+	clientset := fake.NewSimpleClientset(
+		pod,
+		bound,
+		testPVC("wal", "gp3", "", corev1.ClaimPending),
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-123"},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")},
+			},
+		},
+	)
+
+	related, err := GetRelatedResources(context.Background(), clientset, nil, PodInfo{Name: "db-0", Namespace: "default"})
+	if err != nil {
+		t.Fatalf("GetRelatedResources() error = %v", err)
+	}
+	if len(related.PVCs) != 3 {
+		t.Fatalf("PVCs = %+v, want 3 claims", related.PVCs)
+	}
+
+	data := related.PVCs[0]
+	if data.Name != "data" || data.Phase != "Bound" || data.StorageClass != "gp3" || data.VolumeName != "pv-123" {
+		t.Errorf("bound claim = %+v", data)
+	}
+	// The bound volume's capacity wins over the claim's
+	if data.Capacity != "20Gi" {
+		t.Errorf("bound claim Capacity = %q, want 20Gi", data.Capacity)
+	}
+	if len(data.AccessModes) != 1 || data.AccessModes[0] != "ReadWriteOnce" {
+		t.Errorf("bound claim AccessModes = %v", data.AccessModes)
+	}
+	if data.BlocksScheduling() {
+		t.Error("bound claim should not block scheduling")
+	}
+
+	wal := related.PVCs[1]
+	if wal.Phase != "Pending" || wal.Capacity != "10Gi" || !wal.BlocksScheduling() {
+		t.Errorf("pending claim = %+v, want Pending with the requested size, blocking", wal)
+	}
+
+	backup := related.PVCs[2]
+	if !backup.Missing || backup.Volume != "backup" || !backup.BlocksScheduling() {
+		t.Errorf("missing claim = %+v, want Missing, blocking", backup)
+	}
+}
+
+func TestGetRelatedResources_EphemeralVolumePVC(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{Name: "scratch", VolumeSource: corev1.VolumeSource{Ephemeral: &corev1.EphemeralVolumeSource{}}},
+			},
+		},
+	}
+	clientset := fake.NewSimpleClientset(pod, testPVC("worker-scratch", "gp3", "", corev1.ClaimPending))
+
+	related, err := GetRelatedResources(context.Background(), clientset, nil, PodInfo{Name: "worker", Namespace: "default"})
+	if err != nil {
+		t.Fatalf("GetRelatedResources() error = %v", err)
+	}
+	if len(related.PVCs) != 1 || related.PVCs[0].Name != "worker-scratch" || related.PVCs[0].Phase != "Pending" {
+		t.Errorf("PVCs = %+v, want the ephemeral volume's claim", related.PVCs)
+	}
+}
+
+func TestGetRelatedResources_UnreadablePVC(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{Name: "data", VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+				}},
+			},
+		},
+	}
+	clientset := fake.NewSimpleClientset(pod, testPVC("data", "gp3", "", corev1.ClaimPending))
+	clientset.PrependReactor("get", "persistentvolumeclaims", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "persistentvolumeclaims"}, "data", errors.New("denied"))
+	})
+
+	related, err := GetRelatedResources(context.Background(), clientset, nil, PodInfo{Name: "db-0", Namespace: "default"})
+	if err != nil {
+		t.Fatalf("GetRelatedResources() error = %v", err)
+	}
+	if len(related.PVCs) != 1 {
+		t.Fatalf("PVCs = %+v, want 1 claim", related.PVCs)
+	}
+	data := related.PVCs[0]
+	if data.Missing || !strings.Contains(data.Error, "forbidden") || data.BlocksScheduling() {
+		t.Errorf("forbidden claim = %+v, want the error reported, not Missing nor blocking", data)
+	}
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrImpersonationForbidden is returned by Client.CanI when the current user
+// may not impersonate the ServiceAccount.
+var ErrImpersonationForbidden = errors.New("not allowed to impersonate the service account")
+
+// PodRBAC describes what a pod's ServiceAccount is allowed to do, resolved
+// from the RoleBindings and ClusterRoleBindings that reference it.
+type PodRBAC struct {
+	ServiceAccount string
+	Namespace      string
+	Exists         bool     // false when the ServiceAccount is missing
+	AutomountToken bool     // Whether the ServiceAccount mounts its token into pods by default
+	Secrets        []string // Secrets listed on the ServiceAccount, e.g. legacy token secrets
+	Bindings       []RBACBinding
+	Rules          []RBACRule // Rules of every bound role, deduplicated
+	Warnings       []string   // Bindings or roles that could not be read
+}
+
+// RBACBinding is a RoleBinding or ClusterRoleBinding granting a role to
+// the ServiceAccount.
+type RBACBinding struct {
+	Kind     string // RoleBinding or ClusterRoleBinding
+	Name     string
+	RoleKind string // Role or ClusterRole
+	RoleName string
+}
+
+// RBACRule is one policy rule of a bound role.
+type RBACRule struct {
+	Verbs           []string
+	APIGroups       []string
+	Resources       []string
+	ResourceNames   []string
+	NonResourceURLs []string
+}
+
+// String renders the rule like "get,list pods,services (apps)".
+func (r RBACRule) String() string {
+	verbs := strings.Join(r.Verbs, ",")
+	if len(r.NonResourceURLs) > 0 {
+		return verbs + " " + strings.Join(r.NonResourceURLs, ",")
+	}
+	s := verbs + " " + strings.Join(r.Resources, ",")
+	if len(r.ResourceNames) > 0 {
+		s += " [" + strings.Join(r.ResourceNames, ",") + "]"
+	}
+	var groups []string
+	for _, g := range r.APIGroups {
+		if g != "" {
+			groups = append(groups, g)
+		}
+	}
+	if len(groups) > 0 {
+		s += " (" + strings.Join(groups, ",") + ")"
+	}
+	return s
+}
+
+// NoBindings reports whether nothing grants the ServiceAccount permissions,
+// the usual state of the default ServiceAccount.
+func (r PodRBAC) NoBindings() bool {
+	return len(r.Bindings) == 0
+}
+
+// AccessCheck is one question asked with CanI: whether a ServiceAccount
+// may perform Verb on Resource of Group, and the answer.
+type AccessCheck struct {
+	Verb     string
+	Group    string
+	Resource string
+	Allowed  bool
+	Reason   string // Why access is denied, when the authorizer gives one
+}
+
+// String renders the check like "list secrets" or "get deployments.apps".
+func (a AccessCheck) String() string {
+	if a.Group == "" {
+		return a.Verb + " " + a.Resource
+	}
+	return a.Verb + " " + a.Resource + "." + a.Group
+}
+
+// CommonAccessChecks are the requests pods most often get 403 Forbidden
+// for, checked for the ServiceAccount of the selected pod.
+var CommonAccessChecks = []AccessCheck{
+	{Verb: "get", Resource: "pods"},
+	{Verb: "list", Resource: "pods"},
+	{Verb: "watch", Resource: "pods"},
+	{Verb: "get", Resource: "configmaps"},
+	{Verb: "get", Resource: "secrets"},
+	{Verb: "create", Resource: "events"},
+}
+
+// serviceAccountUser is the username a ServiceAccount authenticates as.
+func serviceAccountUser(namespace, serviceAccount string) string {
+	return "system:serviceaccount:" + namespace + ":" + serviceAccount
+}
+
+// serviceAccountGroups are the groups every ServiceAccount belongs to.
+func serviceAccountGroups(namespace string) []string {
+	return []string{"system:serviceaccounts", "system:serviceaccounts:" + namespace, "system:authenticated"}
+}
+
+// GetPodRBAC resolves the permissions of a ServiceAccount. Bindings to the
+// account itself or to the system:serviceaccounts groups are included;
+// ClusterRoleBindings or roles that can't be read, e.g. without RBAC
+// permissions on them, are reported in Warnings.
+func GetPodRBAC(ctx context.Context, clientset kubernetes.Interface, namespace, serviceAccount string) (*PodRBAC, error) {
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	result := &PodRBAC{ServiceAccount: serviceAccount, Namespace: namespace, AutomountToken: true}
+
+	sa, err := clientset.CoreV1().ServiceAccounts(namespace).Get(ctx, serviceAccount, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		result.Exists = true
+		if sa.AutomountServiceAccountToken != nil {
+			result.AutomountToken = *sa.AutomountServiceAccountToken
+		}
+		for _, s := range sa.Secrets {
+			result.Secrets = append(result.Secrets, s.Name)
+		}
+	case !apierrors.IsNotFound(err):
+		result.Warnings = append(result.Warnings, fmt.Sprintf("ServiceAccount: %v", err))
+	}
+
+	roleBindings, err := clientset.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rolebindings: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, rb := range roleBindings.Items {
+		if !bindsServiceAccount(rb.Subjects, namespace, serviceAccount) {
+			continue
+		}
+		result.Bindings = append(result.Bindings, RBACBinding{Kind: "RoleBinding", Name: rb.Name, RoleKind: rb.RoleRef.Kind, RoleName: rb.RoleRef.Name})
+		result.addRoleRules(ctx, clientset, namespace, rb.RoleRef, seen)
+	}
+
+	clusterRoleBindings, err := clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("ClusterRoleBindings: %v", err))
+	} else {
+		for _, crb := range clusterRoleBindings.Items {
+			if !bindsServiceAccount(crb.Subjects, namespace, serviceAccount) {
+				continue
+			}
+			result.Bindings = append(result.Bindings, RBACBinding{Kind: "ClusterRoleBinding", Name: crb.Name, RoleKind: crb.RoleRef.Kind, RoleName: crb.RoleRef.Name})
+			result.addRoleRules(ctx, clientset, "", crb.RoleRef, seen)
+		}
+	}
+
+	sort.SliceStable(result.Bindings, func(i, j int) bool {
+		if result.Bindings[i].Kind != result.Bindings[j].Kind {
+			return result.Bindings[i].Kind == "RoleBinding"
+		}
+		return result.Bindings[i].Name < result.Bindings[j].Name
+	})
+	return result, nil
+}
+
+// addRoleRules appends the rules of a bound role that are not in seen yet.
+// A Role is looked up in namespace; a ClusterRole is cluster-wide.
+func (r *PodRBAC) addRoleRules(ctx context.Context, clientset kubernetes.Interface, namespace string, ref rbacv1.RoleRef, seen map[string]bool) {
+	var rules []rbacv1.PolicyRule
+	switch ref.Kind {
+	case "Role":
+		role, err := clientset.RbacV1().Roles(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("Role %s: %v", ref.Name, err))
+			return
+		}
+		rules = role.Rules
+	case "ClusterRole":
+		role, err := clientset.RbacV1().ClusterRoles().Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("ClusterRole %s: %v", ref.Name, err))
+			return
+		}
+		rules = role.Rules
+	}
+
+	for _, rule := range rules {
+		converted := RBACRule{
+			Verbs:           rule.Verbs,
+			APIGroups:       rule.APIGroups,
+			Resources:       rule.Resources,
+			ResourceNames:   rule.ResourceNames,
+			NonResourceURLs: rule.NonResourceURLs,
+		}
+		key := fmt.Sprint(converted.Verbs, converted.APIGroups, converted.Resources, converted.ResourceNames, converted.NonResourceURLs)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		r.Rules = append(r.Rules, converted)
+	}
+}
+
+// bindsServiceAccount reports whether subjects include the ServiceAccount,
+// directly or through one of the service account groups. The broader
+// system:authenticated group is left out as every cluster binds it to
+// discovery roles.
+func bindsServiceAccount(subjects []rbacv1.Subject, namespace, serviceAccount string) bool {
+	for _, s := range subjects {
+		switch s.Kind {
+		case rbacv1.ServiceAccountKind:
+			if s.Name == serviceAccount && s.Namespace == namespace {
+				return true
+			}
+		case rbacv1.UserKind:
+			if s.Name == serviceAccountUser(namespace, serviceAccount) {
+				return true
+			}
+		case rbacv1.GroupKind:
+			if s.Name == "system:serviceaccounts" || s.Name == "system:serviceaccounts:"+namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CanI asks the API server whether the clientset's user may perform verb on
+// a resource of group in namespace, through a SelfSubjectAccessReview. With
+// a clientset impersonating a ServiceAccount it answers for that account.
+// The reason explains a denial when the authorizer gives one.
+func CanI(ctx context.Context, clientset kubernetes.Interface, namespace, verb, group, resource string) (bool, string, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     group,
+				Resource:  resource,
+			},
+		},
+	}
+	result, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to review access: %w", err)
+	}
+	return result.Status.Allowed, result.Status.Reason, nil
+}
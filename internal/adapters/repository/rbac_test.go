@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetPodRBAC(t *testing.T) {
+	automount := false
+	podReader := rbacv1.PolicyRule{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}}
+	clientset := fake.NewSimpleClientset(
+		&corev1.ServiceAccount{
+			ObjectMeta:                   metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			AutomountServiceAccountToken: &automount,
+			Secrets:                      []corev1.ObjectReference{{Name: "web-token-x7k2p"}},
+		},
+		&rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-reader", Namespace: "shop"},
+			Rules:      []rbacv1.PolicyRule{podReader},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-pods", Namespace: "shop"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "web", Namespace: "shop"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "pod-reader"},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "shop"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "api", Namespace: "shop"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "pod-reader"},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "missing-role", Namespace: "shop"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "system:serviceaccount:shop:web"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "gone"},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "viewer"},
+			Rules: []rbacv1.PolicyRule{
+				podReader,
+				{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"web"}},
+			},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "all-sa-view"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:shop"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "viewer"},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "discovery"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "system:authenticated"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "system:discovery"},
+		},
+	)
+
+	rbac, err := GetPodRBAC(context.Background(), clientset, "shop", "web")
+	if err != nil {
+		t.Fatalf("GetPodRBAC() error = %v", err)
+	}
+	if !rbac.Exists || rbac.AutomountToken || len(rbac.Secrets) != 1 || rbac.Secrets[0] != "web-token-x7k2p" {
+		t.Errorf("ServiceAccount = exists %v, automount %v, secrets %v", rbac.Exists, rbac.AutomountToken, rbac.Secrets)
+	}
+
+	var bindings []string
+	for _, b := range rbac.Bindings {
+		bindings = append(bindings, b.Kind+"/"+b.Name)
+	}
+	if got := strings.Join(bindings, " "); got != "RoleBinding/missing-role RoleBinding/web-pods ClusterRoleBinding/all-sa-view" {
+		t.Errorf("Bindings = %s", got)
+	}
+
+	var rules []string
+	for _, r := range rbac.Rules {
+		rules = append(rules, r.String())
+	}
+	if got := strings.Join(rules, "; "); got != "get,list pods; get deployments [web] (apps)" {
+		t.Errorf("Rules = %s, want the pod reader rule once", got)
+	}
+	if len(rbac.Warnings) != 1 || !strings.Contains(rbac.Warnings[0], "Role gone") {
+		t.Errorf("Warnings = %v, want the missing role", rbac.Warnings)
+	}
+}
+
+func TestGetPodRBAC_DefaultWithoutBindings(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("list", "clusterrolebindings", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+
+	rbac, err := GetPodRBAC(context.Background(), clientset, "shop", "")
+	if err != nil {
+		t.Fatalf("GetPodRBAC() error = %v", err)
+	}
+	if rbac.ServiceAccount != "default" || rbac.Exists || !rbac.AutomountToken {
+		t.Errorf("rbac = %+v, want a missing default ServiceAccount with automount", rbac)
+	}
+	if !rbac.NoBindings() || len(rbac.Rules) != 0 {
+		t.Errorf("Bindings = %v, Rules = %v, want none", rbac.Bindings, rbac.Rules)
+	}
+	if len(rbac.Warnings) != 1 || !strings.Contains(rbac.Warnings[0], "ClusterRoleBindings") {
+		t.Errorf("Warnings = %v, want the ClusterRoleBindings error", rbac.Warnings)
+	}
+}
+
+func TestCanI(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	var got *authorizationv1.ResourceAttributes
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		got = review.Spec.ResourceAttributes
+		review.Status = authorizationv1.SubjectAccessReviewStatus{Allowed: false, Reason: "no RBAC policy matched"}
+		return true, review, nil
+	})
+
+	allowed, reason, err := CanI(context.Background(), clientset, "shop", "list", "", "secrets")
+	if err != nil {
+		t.Fatalf("CanI() error = %v", err)
+	}
+	if allowed || reason != "no RBAC policy matched" {
+		t.Errorf("CanI() = %v, %q, want denied with the reason", allowed, reason)
+	}
+	if got == nil || got.Namespace != "shop" || got.Verb != "list" || got.Resource != "secrets" {
+		t.Errorf("ResourceAttributes = %+v", got)
+	}
+}
+
+func TestClient_CanI_Impersonates(t *testing.T) {
+	var user string
+	var groups []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user = r.Header.Get("Impersonate-User")
+		groups = r.Header.Values("Impersonate-Group")
+		w.Header().Set("Content-Type", "application/json")
+		if user == "system:serviceaccount:shop:locked" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"kind":"SelfSubjectAccessReview","apiVersion":"authorization.k8s.io/v1","status":{"allowed":true}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromConfig(&rest.Config{Host: server.URL}, "")
+	if err != nil {
+		t.Fatalf("NewClientFromConfig() error = %v", err)
+	}
+	ctx := context.Background()
+
+	allowed, _, err := client.CanI(ctx, "shop", "web", "get", "", "pods")
+	if err != nil || !allowed {
+		t.Fatalf("CanI() = %v, %v, want allowed", allowed, err)
+	}
+	if user != "system:serviceaccount:shop:web" || len(groups) != 3 || groups[1] != "system:serviceaccounts:shop" {
+		t.Errorf("impersonated %q %v, want the web ServiceAccount and its groups", user, groups)
+	}
+
+	if _, _, err := client.CanI(ctx, "shop", "locked", "get", "", "pods"); !errors.Is(err, ErrImpersonationForbidden) {
+		t.Errorf("CanI() error = %v, want ErrImpersonationForbidden", err)
+	}
+}
+
+func TestClient_CheckAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var review authorizationv1.SelfSubjectAccessReview
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+			t.Errorf("decoding review: %v", err)
+		}
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = attrs.Resource == "pods"
+		if !review.Status.Allowed {
+			review.Status.Reason = "no RBAC policy matched"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(review)
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromConfig(&rest.Config{Host: server.URL}, "")
+	if err != nil {
+		t.Fatalf("NewClientFromConfig() error = %v", err)
+	}
+	checks, err := client.CheckAccess(context.Background(), "shop", "web", []AccessCheck{
+		{Verb: "get", Resource: "pods"},
+		{Verb: "list", Group: "apps", Resource: "deployments"},
+	})
+	if err != nil {
+		t.Fatalf("CheckAccess() error = %v", err)
+	}
+	if len(checks) != 2 {
+		t.Fatalf("CheckAccess() = %+v, want 2 checks", checks)
+	}
+	if !checks[0].Allowed || checks[0].String() != "get pods" {
+		t.Errorf("checks[0] = %+v, want get pods allowed", checks[0])
+	}
+	if checks[1].Allowed || checks[1].Reason != "no RBAC policy matched" || checks[1].String() != "list deployments.apps" {
+		t.Errorf("checks[1] = %+v, want list deployments.apps denied with the reason", checks[1])
+	}
+}
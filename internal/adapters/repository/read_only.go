@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrReadOnly is returned for any change to the cluster attempted while the
+// client is read-only.
+var ErrReadOnly = errors.New("read-only mode: mutating actions are disabled")
+
+// readOnlyGuard switches a client, and every clientset built from its
+// config, to read-only. It is shared so that SwitchContext keeps the mode.
+type readOnlyGuard struct {
+	enabled atomic.Bool
+}
+
+// wrap is a rest.Config WrapTransport that fails requests other than
+// reads, access reviews and port-forwards with ErrReadOnly while the guard is enabled,
+// so that the free functions taking a clientset can't change the cluster
+// either.
+func (g *readOnlyGuard) wrap(rt http.RoundTripper) http.RoundTripper {
+	return readOnlyTransport{guard: g, next: rt}
+}
+
+// readOnlyTransport is the http.RoundTripper installed by readOnlyGuard.
+type readOnlyTransport struct {
+	guard *readOnlyGuard
+	next  http.RoundTripper
+}
+
+func (t readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.guard.enabled.Load() {
+		switch {
+		case req.Method == http.MethodGet, req.Method == http.MethodHead, req.Method == http.MethodOptions:
+		case isAccessReview(req), isPortForward(req):
+		default:
+			return nil, ErrReadOnly
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// isAccessReview reports whether req creates an authorization review,
+// e.g. the SelfSubjectAccessReview of CanI. Reviews only ask the API
+// server a question and store nothing, so read-only mode allows them.
+func isAccessReview(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.Contains(req.URL.Path, "/apis/authorization.k8s.io/")
+}
+
+// isPortForward reports whether req opens a port-forward to a pod. The
+// forward only connects to a port of the pod and changes nothing, so
+// read-only mode allows it.
+func isPortForward(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/portforward")
+}
+
+// SetReadOnly enables or disables read-only mode. While enabled, DeletePod,
+// ScaleWorkload and RestartWorkload return ErrReadOnly, as does any request
+// other than a read, an access review or a port-forward made through
+// Clientset, DynamicClient or MetricsClient.
+func (c *Client) SetReadOnly(readOnly bool) {
+	if c.readOnly == nil {
+		c.readOnly = &readOnlyGuard{}
+	}
+	c.readOnly.enabled.Store(readOnly)
+}
+
+// ReadOnly reports whether the client is in read-only mode.
+func (c *Client) ReadOnly() bool {
+	return c.readOnly != nil && c.readOnly.enabled.Load()
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestClient_ReadOnly_MutatingMethods(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"}},
+	)
+	client := &Client{clientset: fakeClientset}
+	if client.ReadOnly() {
+		t.Fatal("new client should not be read-only")
+	}
+	client.SetReadOnly(true)
+	if !client.ReadOnly() {
+		t.Fatal("ReadOnly() = false after SetReadOnly(true)")
+	}
+
+	ctx := context.Background()
+	if err := client.DeletePod(ctx, "default", "test-pod"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("DeletePod() error = %v, want ErrReadOnly", err)
+	}
+	if err := client.DeletePodForce(ctx, "default", "test-pod"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("DeletePodForce() error = %v, want ErrReadOnly", err)
+	}
+	if err := client.ScaleWorkload(ctx, "default", "web", ResourceDeployments, 0); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("ScaleWorkload() error = %v, want ErrReadOnly", err)
+	}
+	if err := client.RestartWorkload(ctx, "default", "web", ResourceDeployments); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("RestartWorkload() error = %v, want ErrReadOnly", err)
+	}
+	if _, err := fakeClientset.CoreV1().Pods("default").Get(ctx, "test-pod", metav1.GetOptions{}); err != nil {
+		t.Errorf("pod should not have been deleted: %v", err)
+	}
+
+	client.SetReadOnly(false)
+	if err := client.DeletePod(ctx, "default", "test-pod"); err != nil {
+		t.Errorf("DeletePod() after SetReadOnly(false) error = %v", err)
+	}
+}
+
+func TestNewClientFromConfig_ReadOnlyBlocksWrites(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromConfig(&rest.Config{Host: server.URL}, "")
+	if err != nil {
+		t.Fatalf("NewClientFromConfig() error = %v", err)
+	}
+	client.SetReadOnly(true)
+
+	ctx := context.Background()
+	pods := client.Clientset().CoreV1().Pods("default")
+	if err := pods.Delete(ctx, "test-pod", metav1.DeleteOptions{}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Delete() error = %v, want ErrReadOnly", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("delete reached the server (%d requests)", n)
+	}
+
+	// Reads still go through
+	if _, err := pods.Get(ctx, "test-pod", metav1.GetOptions{}); errors.Is(err, ErrReadOnly) {
+		t.Errorf("Get() error = %v, reads should be allowed", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+
+	client.SetReadOnly(false)
+	if err := pods.Delete(ctx, "test-pod", metav1.DeleteOptions{}); errors.Is(err, ErrReadOnly) {
+		t.Errorf("Delete() after SetReadOnly(false) error = %v", err)
+	}
+}
+
+func TestNewClientFromConfig_ReadOnlyAllowsAccessReviews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"kind":"SelfSubjectAccessReview","apiVersion":"authorization.k8s.io/v1","status":{"allowed":true}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromConfig(&rest.Config{Host: server.URL}, "")
+	if err != nil {
+		t.Fatalf("NewClientFromConfig() error = %v", err)
+	}
+	client.SetReadOnly(true)
+
+	allowed, _, err := CanI(context.Background(), client.Clientset(), "default", "get", "", "pods")
+	if err != nil || !allowed {
+		t.Errorf("CanI() = %v, %v, want the review to go through in read-only mode", allowed, err)
+	}
+	if _, _, err := client.CanI(context.Background(), "default", "web", "get", "", "pods"); err != nil {
+		t.Errorf("Client.CanI() error = %v, want the impersonated review to go through", err)
+	}
+}
+
+func TestReadOnlyTransport_AllowsPortForward(t *testing.T) {
+	guard := &readOnlyGuard{}
+	guard.enabled.Store(true)
+	var reached int
+	rt := guard.wrap(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		reached++
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}))
+
+	forward, _ := http.NewRequest(http.MethodPost, "https://cluster/api/v1/namespaces/default/pods/web/portforward", nil)
+	if _, err := rt.RoundTrip(forward); err != nil {
+		t.Errorf("port-forward error = %v, want it allowed in read-only mode", err)
+	}
+	evict, _ := http.NewRequest(http.MethodPost, "https://cluster/api/v1/namespaces/default/pods/web/eviction", nil)
+	if _, err := rt.RoundTrip(evict); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("eviction error = %v, want ErrReadOnly", err)
+	}
+	if reached != 1 {
+		t.Errorf("%d requests reached the cluster, want 1", reached)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// kindGVRs maps the kinds whose YAML can be exported, pods and the
+// workloads owning them, to their resources.
+var kindGVRs = map[string]schema.GroupVersionResource{
+	"Pod":         {Version: "v1", Resource: "pods"},
+	"Deployment":  {Group: "apps", Version: "v1", Resource: "deployments"},
+	"StatefulSet": {Group: "apps", Version: "v1", Resource: "statefulsets"},
+	"DaemonSet":   {Group: "apps", Version: "v1", Resource: "daemonsets"},
+	"ReplicaSet":  {Group: "apps", Version: "v1", Resource: "replicasets"},
+	"Job":         {Group: "batch", Version: "v1", Resource: "jobs"},
+	"CronJob":     {Group: "batch", Version: "v1", Resource: "cronjobs"},
+	"Rollout":     rolloutGVR,
+}
+
+// GVRForKind returns the resource of a pod or workload kind, such as
+// "Deployment". Returns false for kinds that can't be exported.
+func GVRForKind(kind string) (schema.GroupVersionResource, bool) {
+	gvr, ok := kindGVRs[kind]
+	return gvr, ok
+}
+
+// KindForResourceType returns the kind of a pod or workload resource type,
+// such as "Deployment" for deployments. Returns "" for other types.
+func KindForResourceType(rt ResourceType) string {
+	for kind, gvr := range kindGVRs {
+		if gvr.Resource == string(rt) {
+			return kind
+		}
+	}
+	return ""
+}
+
+// GetResourceYAML fetches the live object and returns it as YAML, like
+// kubectl get -o yaml. With clean, managedFields, status and the metadata
+// the API server sets (uid, resourceVersion, generation,
+// creationTimestamp) are left out, leaving a manifest fit for a ticket or
+// for re-applying.
+func GetResourceYAML(ctx context.Context, dynamicClient dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string, clean bool) (string, error) {
+	if dynamicClient == nil {
+		return "", fmt.Errorf("dynamic client not available")
+	}
+	obj, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s %s: %w", gvr.Resource, name, err)
+	}
+	if clean {
+		cleanObject(obj)
+	}
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize %s %s: %w", gvr.Resource, name, err)
+	}
+	return string(data), nil
+}
+
+// cleanObject removes the fields the API server maintains.
+func cleanObject(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "status")
+	for _, field := range []string{"managedFields", "uid", "resourceVersion", "generation", "creationTimestamp", "selfLink"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func yamlPod() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":              "web-1",
+			"namespace":         "default",
+			"uid":               "1234",
+			"resourceVersion":   "42",
+			"creationTimestamp": "2024-01-01T00:00:00Z",
+			"labels":            map[string]interface{}{"app": "web"},
+			"managedFields": []interface{}{
+				map[string]interface{}{"manager": "kubectl", "operation": "Apply"},
+			},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "web:1"},
+			},
+		},
+		"status": map[string]interface{}{"phase": "Running"},
+	}}
+}
+
+func TestGetResourceYAML(t *testing.T) {
+	gvr, ok := GVRForKind("Pod")
+	if !ok {
+		t.Fatal("GVRForKind(Pod) not found")
+	}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), yamlPod())
+
+	full, err := GetResourceYAML(context.Background(), client, gvr, "default", "web-1", false)
+	if err != nil {
+		t.Fatalf("GetResourceYAML() error = %v", err)
+	}
+	for _, want := range []string{"managedFields:", "manager: kubectl", "status:", "phase: Running", "resourceVersion: \"42\"", "image: web:1"} {
+		if !strings.Contains(full, want) {
+			t.Errorf("full YAML should contain %q, got:\n%s", want, full)
+		}
+	}
+
+	clean, err := GetResourceYAML(context.Background(), client, gvr, "default", "web-1", true)
+	if err != nil {
+		t.Fatalf("GetResourceYAML(clean) error = %v", err)
+	}
+	for _, unwanted := range []string{"managedFields", "status:", "resourceVersion", "uid:", "creationTimestamp"} {
+		if strings.Contains(clean, unwanted) {
+			t.Errorf("clean YAML should not contain %q, got:\n%s", unwanted, clean)
+		}
+	}
+	for _, want := range []string{"name: web-1", "app: web", "image: web:1"} {
+		if !strings.Contains(clean, want) {
+			t.Errorf("clean YAML should keep %q, got:\n%s", want, clean)
+		}
+	}
+}
+
+func TestGetResourceYAML_Errors(t *testing.T) {
+	gvr, _ := GVRForKind("Pod")
+	if _, err := GetResourceYAML(context.Background(), nil, gvr, "default", "web-1", true); err == nil {
+		t.Error("nil dynamic client should fail")
+	}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	if _, err := GetResourceYAML(context.Background(), client, gvr, "default", "missing", true); err == nil {
+		t.Error("a missing object should fail")
+	}
+}
+
+func TestGVRForKind(t *testing.T) {
+	if gvr, ok := GVRForKind("Deployment"); !ok || gvr.Group != "apps" || gvr.Resource != "deployments" {
+		t.Errorf("GVRForKind(Deployment) = %v, %v", gvr, ok)
+	}
+	if gvr, ok := GVRForKind("Rollout"); !ok || gvr != rolloutGVR {
+		t.Errorf("GVRForKind(Rollout) = %v, %v", gvr, ok)
+	}
+	if _, ok := GVRForKind("Secret"); ok {
+		t.Error("secrets should not be exportable")
+	}
+	if kind := KindForResourceType(ResourceStatefulSets); kind != "StatefulSet" {
+		t.Errorf("KindForResourceType(statefulsets) = %q, want StatefulSet", kind)
+	}
+	if kind := KindForResourceType(ResourceServices); kind != "" {
+		t.Errorf("KindForResourceType(services) = %q, want none", kind)
+	}
+}
@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -15,6 +18,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
@@ -30,6 +34,7 @@ const (
 	ResourceDaemonSets   ResourceType = "daemonsets"
 	ResourceJobs         ResourceType = "jobs"
 	ResourceCronJobs     ResourceType = "cronjobs"
+	ResourceServices     ResourceType = "services"
 )
 
 // AllResourceTypes lists all supported workload types in display order.
@@ -39,14 +44,16 @@ var AllResourceTypes = []ResourceType{
 	ResourceDaemonSets,
 	ResourceJobs,
 	ResourceCronJobs,
+	ResourceServices,
 	ResourcePods,
 }
 
 // NamespaceInfo provides information about a Kubernetes namespace.
 // Includes the namespace name and its current phase status.
 type NamespaceInfo struct {
-	Name   string // Namespace name
-	Status string // Phase status (Active, Terminating)
+	Name              string    // Namespace name
+	Status            string    // Phase status (Active, Terminating)
+	DeletionTimestamp time.Time // When deletion was requested; zero unless Terminating
 }
 
 // WorkloadInfo provides a summary view of a Kubernetes workload.
@@ -60,7 +67,14 @@ type WorkloadInfo struct {
 	Age          string            // Human-readable age
 	Status       string            // Current status (Running, Progressing, Failed, etc.)
 	Labels       map[string]string // Selector labels for finding pods
-	RestartCount int32             // Total restart count across all pods
+	RestartCount int32             // Total restart count across all pods; set with Health for workloads
+	Service      *ServiceInfo      // Type, cluster IP and ports; set for Services only
+	Health       *PodHealth        // Breakdown of the workload's pods; nil until loaded
+	Images       []string          // Container images of the pod template in spec order; not set for Services
+	EnvFrom      []string          // "ConfigMap/<name>" and "Secret/<name>" envFrom sources, sorted; set for Deployments only
+	ObjectLabels map[string]string // The workload's own labels (Labels is its selector)
+	Nodes        []string          // Nodes running the workload's pods, sorted; set with Health
+	HelmRelease  string            // Helm release that installed the workload, "" when Helm doesn't manage it
 }
 
 // PodInfo provides comprehensive information about a Kubernetes pod.
@@ -79,21 +93,31 @@ type PodInfo struct {
 	Annotations            map[string]string      // Pod annotations
 	Containers             []ContainerInfo        // Regular containers
 	InitContainers         []ContainerInfo        // Init containers
+	EphemeralContainers    []ContainerInfo        // Debug containers injected with kubectl debug or AddEphemeralContainer
 	Conditions             []corev1.PodCondition  // Pod conditions
 	Phase                  corev1.PodPhase        // Pod phase
 	OwnerRef               string                 // Owner reference name
 	OwnerKind              string                 // Owner reference kind
 	QoSClass               string                 // Quality of Service class
 	ServiceAccount         string                 // Service account name
+	ImagePullSecrets       []string               // Image pull secret names from the pod spec
+	PullSecrets            []PullSecretStatus     // Pull secrets of the pod and its ServiceAccount, validated; nil until loaded
 	Volumes                []VolumeInfo           // Volume definitions
 	RestartPolicy          string                 // Restart policy
 	DNSPolicy              string                 // DNS policy
 	PriorityClassName      string                 // Priority class name
 	Priority               *int32                 // Scheduling priority
 	NodeSelector           map[string]string      // Node selector constraints
+	NodeAffinity           *corev1.NodeSelector   // Required node affinity (nil if none)
 	Tolerations            []TolerationInfo       // Node tolerations
+	SchedulingGates        []string               // Scheduling gates blocking scheduling
+	ReadinessGates         []string               // Condition types that must be True for the pod to be ready
+	SecurityContext        *PodSecurityContext    // Pod-level security context (nil if not set)
+	HostNamespaces         []string               // Node namespaces the pod shares: "network", "PID", "IPC"
 	TerminationGracePeriod int64                  // Termination grace period in seconds
 	StartTime              string                 // Pod start time
+	UID                    string                 // Tells the pod from a later one with the same name
+	DeletionTimestamp      time.Time              // When the grace period of a pod being deleted ends; zero otherwise
 }
 
 // ContainerInfo provides details about a container within a pod.
@@ -109,6 +133,7 @@ type ContainerInfo struct {
 	StartedAt       string               // Container start time
 	FinishedAt      string               // Container finish time (if terminated)
 	ExitCode        *int32               // Exit code (if terminated)
+	LastTermination *TerminationInfo     // How the previous instance ended (nil before the first restart)
 	Resources       ResourceRequirements // Resource requests and limits
 	Ports           []ContainerPort      // Exposed ports
 	LivenessProbe   *ProbeInfo           // Liveness probe configuration
@@ -117,6 +142,17 @@ type ContainerInfo struct {
 	SecurityContext *SecurityContextInfo // Security context settings
 	EnvVarCount     int                  // Number of environment variables
 	VolumeMounts    []VolumeMountInfo    // Volume mount configurations
+	TargetContainer string               // Container whose processes an ephemeral container shares
+}
+
+// TerminationInfo describes how a container instance ended.
+type TerminationInfo struct {
+	Reason     string    // Termination reason (OOMKilled, Error, Completed)
+	Message    string    // Termination message written by the container
+	ExitCode   int32     // Process exit code
+	FinishedAt string    // When the instance finished
+	Finished   time.Time // FinishedAt as a time, zero when unknown
+	Started    time.Time // When the instance started, zero when unknown
 }
 
 // ContainerPort represents an exposed container port.
@@ -124,6 +160,7 @@ type ContainerPort struct {
 	Name          string // Port name (optional)
 	ContainerPort int32  // Port number
 	Protocol      string // Protocol (TCP, UDP)
+	HostPort      int32  // Port opened on the node (0 if none)
 }
 
 // VolumeMountInfo describes a volume mount within a container.
@@ -143,25 +180,50 @@ type TolerationInfo struct {
 
 // ProbeInfo describes a container health probe configuration.
 type ProbeInfo struct {
-	Type             string   // Probe type: HTTP, TCP, Exec, or gRPC
-	Path             string   // HTTP path (for HTTP probes)
-	Port             int32    // Target port
-	Scheme           string   // HTTP scheme (HTTP or HTTPS)
-	Command          []string // Command to execute (for Exec probes)
-	InitialDelay     int32    // Initial delay in seconds
-	Period           int32    // Check period in seconds
-	Timeout          int32    // Timeout in seconds
-	SuccessThreshold int32    // Consecutive successes required
-	FailureThreshold int32    // Consecutive failures required
+	Type             string        // Probe type: HTTP, TCP, Exec, or gRPC
+	Path             string        // HTTP path (for HTTP probes)
+	Port             int32         // Target port
+	PortName         string        // Named target port, resolved against the container ports; "" for numbers
+	Host             string        // Host to connect to (HTTP and TCP); "" for the pod IP
+	Scheme           string        // HTTP scheme (HTTP or HTTPS)
+	Headers          []ProbeHeader // HTTP headers sent with the request
+	Service          string        // gRPC health service name
+	Command          []string      // Command to execute (for Exec probes)
+	InitialDelay     int32         // Initial delay in seconds
+	Period           int32         // Check period in seconds
+	Timeout          int32         // Timeout in seconds
+	SuccessThreshold int32         // Consecutive successes required
+	FailureThreshold int32         // Consecutive failures required
+}
+
+// ProbeHeader is an HTTP header of an HTTP probe.
+type ProbeHeader struct {
+	Name  string
+	Value string
 }
 
 // SecurityContextInfo contains container security settings.
 type SecurityContextInfo struct {
-	RunAsUser    *int64 // User ID to run as
-	RunAsGroup   *int64 // Group ID to run as
-	RunAsNonRoot *bool  // Whether to run as non-root
-	Privileged   *bool  // Whether to run in privileged mode
-	ReadOnlyRoot *bool  // Whether root filesystem is read-only
+	RunAsUser                *int64   // User ID to run as
+	RunAsGroup               *int64   // Group ID to run as
+	RunAsNonRoot             *bool    // Whether to run as non-root
+	Privileged               *bool    // Whether to run in privileged mode
+	ReadOnlyRoot             *bool    // Whether root filesystem is read-only
+	AllowPrivilegeEscalation *bool    // Whether a process may gain more privileges than its parent
+	CapabilitiesAdd          []string // Linux capabilities added, e.g. "NET_BIND_SERVICE"
+	CapabilitiesDrop         []string // Linux capabilities dropped, e.g. "ALL"
+	SeccompProfile           string   // "RuntimeDefault", "Unconfined" or "Localhost/<profile>"; "" if not set
+}
+
+// PodSecurityContext contains the pod-level security settings, which
+// its containers inherit unless they set their own.
+type PodSecurityContext struct {
+	RunAsUser          *int64  // User ID to run as
+	RunAsGroup         *int64  // Group ID to run as
+	RunAsNonRoot       *bool   // Whether to run as non-root
+	FSGroup            *int64  // Group owning the pod's volumes
+	SupplementalGroups []int64 // Groups added to the first process of each container
+	SeccompProfile     string  // As in SecurityContextInfo
 }
 
 // VolumeInfo describes a volume attached to a pod.
@@ -171,6 +233,10 @@ type VolumeInfo struct {
 	Source string // Source name (ConfigMap/Secret/PVC name)
 }
 
+// ProjectedTokenSource is the Source of a Projected volume that mounts a
+// ServiceAccount token, such as the kube-api-access volume.
+const ProjectedTokenSource = "serviceAccountToken"
+
 // ResourceRequirements contains CPU and memory requests and limits.
 type ResourceRequirements struct {
 	CPURequest    string // CPU request (e.g., "100m", "0.5")
@@ -181,30 +247,71 @@ type ResourceRequirements struct {
 
 // ConfigMapInfo provides a summary of a ConfigMap resource.
 type ConfigMapInfo struct {
-	Name string // ConfigMap name
-	Age  string // Human-readable age
-	Keys int    // Number of data keys
+	Name     string   // ConfigMap name
+	Age      string   // Human-readable age
+	Keys     int      // Number of data keys
+	KeyNames []string // Data and binaryData keys, sorted
 }
 
 // NodeInfo provides information about a cluster node.
 type NodeInfo struct {
-	Name       string // Node name
-	Status     string // Node status (Ready, NotReady)
-	Roles      string // Node roles (master, worker, etc.)
-	Age        string // Human-readable age
-	Version    string // Kubelet version
-	InternalIP string // Node internal IP address
-	PodCount   int    // Number of pods on the node
-	CPU        string // CPU capacity
-	Memory     string // Memory capacity
+	Name              string      // Node name
+	Status            string      // Node status (Ready, NotReady)
+	Roles             string      // Node roles (master, worker, etc.)
+	Age               string      // Human-readable age
+	Version           string      // Kubelet version
+	InternalIP        string      // Node internal IP address
+	PodCount          int         // Number of pods on the node
+	CPU               string      // CPU capacity
+	Memory            string      // Memory capacity
+	AllocatableCPU    string      // CPU available to pods
+	AllocatableMemory string      // Memory available to pods
+	RequestedCPU      string      // Sum of pod CPU requests (GetNode only)
+	RequestedMemory   string      // Sum of pod memory requests (GetNode only)
+	Taints            []TaintInfo // Node taints
+	Unschedulable     bool        // Node is cordoned
+	MemoryPressure    bool        // MemoryPressure condition is True
+	DiskPressure      bool        // DiskPressure condition is True
+	PIDPressure       bool        // PIDPressure condition is True
+}
+
+// TaintInfo represents a node taint.
+type TaintInfo struct {
+	Key    string // Taint key
+	Value  string // Taint value (may be empty)
+	Effect string // Taint effect (NoSchedule, PreferNoSchedule, NoExecute)
+}
+
+// String formats the taint like kubectl, e.g. "dedicated=gpu:NoSchedule".
+func (t TaintInfo) String() string {
+	if t.Value == "" {
+		return t.Key + ":" + t.Effect
+	}
+	return t.Key + "=" + t.Value + ":" + t.Effect
+}
+
+// Pressures returns the node's true pressure conditions, e.g. ["MemoryPressure"].
+func (n NodeInfo) Pressures() []string {
+	var pressures []string
+	if n.MemoryPressure {
+		pressures = append(pressures, string(corev1.NodeMemoryPressure))
+	}
+	if n.DiskPressure {
+		pressures = append(pressures, string(corev1.NodeDiskPressure))
+	}
+	if n.PIDPressure {
+		pressures = append(pressures, string(corev1.NodePIDPressure))
+	}
+	return pressures
 }
 
 // SecretInfo provides a summary of a Secret resource.
 type SecretInfo struct {
-	Name string // Secret name
-	Type string // Secret type (Opaque, kubernetes.io/tls, etc.)
-	Age  string // Human-readable age
-	Keys int    // Number of data keys
+	Name     string   // Secret name
+	Type     string   // Secret type (Opaque, kubernetes.io/tls, etc.)
+	Age      string   // Human-readable age
+	Keys     int      // Number of data keys
+	KeyNames []string // Data keys, sorted; values are never kept
 }
 
 // HPAInfo provides a summary of a HorizontalPodAutoscaler resource.
@@ -228,10 +335,14 @@ func ListNamespaces(ctx context.Context, clientset kubernetes.Interface) ([]Name
 
 	var namespaces []NamespaceInfo
 	for _, ns := range nsList.Items {
-		namespaces = append(namespaces, NamespaceInfo{
+		info := NamespaceInfo{
 			Name:   ns.Name,
 			Status: string(ns.Status.Phase),
-		})
+		}
+		if ns.DeletionTimestamp != nil {
+			info.DeletionTimestamp = ns.DeletionTimestamp.Time
+		}
+		namespaces = append(namespaces, info)
 	}
 	sort.Slice(namespaces, func(i, j int) bool {
 		return namespaces[i].Name < namespaces[j].Name
@@ -257,32 +368,63 @@ func ListActiveNamespaceNames(ctx context.Context, clientset kubernetes.Interfac
 	return namespaces, nil
 }
 
+// DefaultPageSize is the number of objects requested per List call when
+// paging through pods and workloads.
+const DefaultPageSize int64 = 500
+
 // ListWorkloads returns all workloads of the specified type in a namespace.
 // Supports pods, deployments, statefulsets, daemonsets, jobs, and cronjobs.
+// Large namespaces are fetched in pages of DefaultPageSize.
 func ListWorkloads(ctx context.Context, clientset kubernetes.Interface, namespace string, resourceType ResourceType) ([]WorkloadInfo, error) {
+	var workloads []WorkloadInfo
+	continueToken := ""
+	for {
+		page, next, err := ListWorkloadsPage(ctx, clientset, namespace, resourceType, DefaultPageSize, continueToken)
+		if err != nil {
+			return nil, err
+		}
+		workloads = append(workloads, page...)
+		if next == "" {
+			return workloads, nil
+		}
+		continueToken = next
+	}
+}
+
+// ListWorkloadsPage returns one page of at most limit workloads of the
+// specified type, starting at continueToken. The returned token is empty
+// once the last page has been read.
+func ListWorkloadsPage(ctx context.Context, clientset kubernetes.Interface, namespace string, resourceType ResourceType, limit int64, continueToken string) ([]WorkloadInfo, string, error) {
+	return listWorkloadsWith(ctx, clientset, namespace, resourceType, metav1.ListOptions{Limit: limit, Continue: continueToken})
+}
+
+// listWorkloadsWith lists workloads of the specified type with opts.
+func listWorkloadsWith(ctx context.Context, clientset kubernetes.Interface, namespace string, resourceType ResourceType, opts metav1.ListOptions) ([]WorkloadInfo, string, error) {
 	switch resourceType {
 	case ResourceDeployments:
-		return listDeployments(ctx, clientset, namespace)
+		return listDeployments(ctx, clientset, namespace, opts)
 	case ResourceStatefulSets:
-		return listStatefulSets(ctx, clientset, namespace)
+		return listStatefulSets(ctx, clientset, namespace, opts)
 	case ResourceDaemonSets:
-		return listDaemonSets(ctx, clientset, namespace)
+		return listDaemonSets(ctx, clientset, namespace, opts)
 	case ResourceJobs:
-		return listJobs(ctx, clientset, namespace)
+		return listJobs(ctx, clientset, namespace, opts)
 	case ResourceCronJobs:
-		return listCronJobs(ctx, clientset, namespace)
+		return listCronJobs(ctx, clientset, namespace, opts)
+	case ResourceServices:
+		return listServices(ctx, clientset, namespace, opts)
 	case ResourcePods:
-		return listPodsAsWorkloads(ctx, clientset, namespace)
+		return listPodsAsWorkloads(ctx, clientset, namespace, opts)
 	default:
-		return nil, fmt.Errorf("unknown resource type: %s", resourceType)
+		return nil, "", fmt.Errorf("unknown resource type: %s", resourceType)
 	}
 }
 
-func listDeployments(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]WorkloadInfo, error) {
-	deps, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+func listDeployments(ctx context.Context, clientset kubernetes.Interface, namespace string, opts metav1.ListOptions) ([]WorkloadInfo, string, error) {
+	deps, err := clientset.AppsV1().Deployments(namespace).List(ctx, opts)
 	if err != nil {
 		//coverage:ignore
-		return nil, err
+		return nil, "", err
 	}
 
 	var workloads []WorkloadInfo
@@ -306,15 +448,68 @@ func listDeployments(ctx context.Context, clientset kubernetes.Interface, namesp
 			Age:       formatAge(d.CreationTimestamp.Time),
 			Status:    status,
 			Labels:    d.Spec.Selector.MatchLabels,
+			Images:    containerImages(d.Spec.Template.Spec.Containers),
+			EnvFrom:   envFromSources(d.Spec.Template.Spec.Containers),
+
+			ObjectLabels: d.Labels,
+			HelmRelease:  HelmReleaseName(d.Labels, d.Annotations),
 		})
 	}
-	return workloads, nil
+	return workloads, deps.Continue, nil
+}
+
+// containerImages returns the image of each container, in spec order.
+func containerImages(containers []corev1.Container) []string {
+	images := make([]string, 0, len(containers))
+	for _, c := range containers {
+		images = append(images, c.Image)
+	}
+	return images
+}
+
+// envFromSources returns the ConfigMaps and Secrets containers load with
+// envFrom, as "ConfigMap/<name>" and "Secret/<name>", sorted and deduplicated.
+func envFromSources(containers []corev1.Container) []string {
+	seen := make(map[string]bool)
+	var sources []string
+	for _, c := range containers {
+		for _, env := range c.EnvFrom {
+			var source string
+			switch {
+			case env.ConfigMapRef != nil:
+				source = "ConfigMap/" + env.ConfigMapRef.Name
+			case env.SecretRef != nil:
+				source = "Secret/" + env.SecretRef.Name
+			default:
+				continue
+			}
+			if !seen[source] {
+				seen[source] = true
+				sources = append(sources, source)
+			}
+		}
+	}
+	sort.Strings(sources)
+	return sources
 }
 
-func listStatefulSets(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]WorkloadInfo, error) {
-	sts, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+// dataKeys returns the keys of a string map and a byte map, sorted.
+func dataKeys(data map[string]string, binary map[string][]byte) []string {
+	keys := make([]string, 0, len(data)+len(binary))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	for k := range binary {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func listStatefulSets(ctx context.Context, clientset kubernetes.Interface, namespace string, opts metav1.ListOptions) ([]WorkloadInfo, string, error) {
+	sts, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, opts)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var workloads []WorkloadInfo
@@ -333,16 +528,20 @@ func listStatefulSets(ctx context.Context, clientset kubernetes.Interface, names
 			Age:       formatAge(s.CreationTimestamp.Time),
 			Status:    status,
 			Labels:    s.Spec.Selector.MatchLabels,
+			Images:    containerImages(s.Spec.Template.Spec.Containers),
+
+			ObjectLabels: s.Labels,
+			HelmRelease:  HelmReleaseName(s.Labels, s.Annotations),
 		})
 	}
-	return workloads, nil
+	return workloads, sts.Continue, nil
 }
 
-func listDaemonSets(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]WorkloadInfo, error) {
-	ds, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+func listDaemonSets(ctx context.Context, clientset kubernetes.Interface, namespace string, opts metav1.ListOptions) ([]WorkloadInfo, string, error) {
+	ds, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, opts)
 	if err != nil {
 		//coverage:ignore
-		return nil, err
+		return nil, "", err
 	}
 
 	var workloads []WorkloadInfo
@@ -362,15 +561,19 @@ func listDaemonSets(ctx context.Context, clientset kubernetes.Interface, namespa
 			Age:       formatAge(d.CreationTimestamp.Time),
 			Status:    status,
 			Labels:    d.Spec.Selector.MatchLabels,
+			Images:    containerImages(d.Spec.Template.Spec.Containers),
+
+			ObjectLabels: d.Labels,
+			HelmRelease:  HelmReleaseName(d.Labels, d.Annotations),
 		})
 	}
-	return workloads, nil
+	return workloads, ds.Continue, nil
 }
 
-func listJobs(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]WorkloadInfo, error) {
-	jobs, err := clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+func listJobs(ctx context.Context, clientset kubernetes.Interface, namespace string, opts metav1.ListOptions) ([]WorkloadInfo, string, error) {
+	jobs, err := clientset.BatchV1().Jobs(namespace).List(ctx, opts)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var workloads []WorkloadInfo
@@ -390,15 +593,19 @@ func listJobs(ctx context.Context, clientset kubernetes.Interface, namespace str
 			Age:       formatAge(j.CreationTimestamp.Time),
 			Status:    status,
 			Labels:    j.Spec.Selector.MatchLabels,
+			Images:    containerImages(j.Spec.Template.Spec.Containers),
+
+			ObjectLabels: j.Labels,
+			HelmRelease:  HelmReleaseName(j.Labels, j.Annotations),
 		})
 	}
-	return workloads, nil
+	return workloads, jobs.Continue, nil
 }
 
-func listCronJobs(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]WorkloadInfo, error) {
-	cjs, err := clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+func listCronJobs(ctx context.Context, clientset kubernetes.Interface, namespace string, opts metav1.ListOptions) ([]WorkloadInfo, string, error) {
+	cjs, err := clientset.BatchV1().CronJobs(namespace).List(ctx, opts)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var workloads []WorkloadInfo
@@ -415,15 +622,58 @@ func listCronJobs(ctx context.Context, clientset kubernetes.Interface, namespace
 			Ready:     fmt.Sprintf("%d active", len(cj.Status.Active)),
 			Age:       formatAge(cj.CreationTimestamp.Time),
 			Status:    status,
+			Images:    containerImages(cj.Spec.JobTemplate.Spec.Template.Spec.Containers),
+
+			ObjectLabels: cj.Labels,
+			HelmRelease:  HelmReleaseName(cj.Labels, cj.Annotations),
 		})
 	}
-	return workloads, nil
+	return workloads, cjs.Continue, nil
 }
 
-func listPodsAsWorkloads(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]WorkloadInfo, error) {
-	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+// listServices lists Services as workloads whose pods are the ones their
+// selector matches. Services without a selector (ExternalName, or with
+// hand-managed endpoints) are marked with Service.NoSelector and have no
+// Labels, so GetWorkloadPods does not match every pod.
+func listServices(ctx context.Context, clientset kubernetes.Interface, namespace string, opts metav1.ListOptions) ([]WorkloadInfo, string, error) {
+	svcs, err := clientset.CoreV1().Services(namespace).List(ctx, opts)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	var workloads []WorkloadInfo
+	for _, svc := range svcs.Items {
+		info := serviceToServiceInfo(&svc)
+		// Use EndpointSlice instead of deprecated Endpoints API, where served
+		epSlices, _ := listServiceEndpointSlices(ctx, clientset, namespace, svc.Name)
+		info.Endpoints = countReadyEndpoints(epSlices)
+
+		ready := fmt.Sprintf("%d ready", info.Endpoints)
+		if info.NoSelector {
+			ready = "no selector"
+		}
+
+		workloads = append(workloads, WorkloadInfo{
+			Name:      svc.Name,
+			Namespace: svc.Namespace,
+			Type:      ResourceServices,
+			Ready:     ready,
+			Age:       formatAge(svc.CreationTimestamp.Time),
+			Status:    info.Type,
+			Labels:    svc.Spec.Selector,
+			Service:   &info,
+
+			ObjectLabels: svc.Labels,
+			HelmRelease:  HelmReleaseName(svc.Labels, svc.Annotations),
+		})
+	}
+	return workloads, svcs.Continue, nil
+}
+
+func listPodsAsWorkloads(ctx context.Context, clientset kubernetes.Interface, namespace string, opts metav1.ListOptions) ([]WorkloadInfo, string, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, "", err
 	}
 
 	var workloads []WorkloadInfo
@@ -440,6 +690,11 @@ func listPodsAsWorkloads(ctx context.Context, clientset kubernetes.Interface, na
 			}
 		}
 
+		var nodes []string
+		if p.Spec.NodeName != "" {
+			nodes = []string{p.Spec.NodeName}
+		}
+
 		workloads = append(workloads, WorkloadInfo{
 			Name:         p.Name,
 			Namespace:    p.Namespace,
@@ -449,9 +704,12 @@ func listPodsAsWorkloads(ctx context.Context, clientset kubernetes.Interface, na
 			Status:       string(p.Status.Phase),
 			Labels:       p.Labels,
 			RestartCount: restartCount,
+			Images:       containerImages(p.Spec.Containers),
+			ObjectLabels: p.Labels,
+			Nodes:        nodes,
 		})
 	}
-	return workloads, nil
+	return workloads, pods.Continue, nil
 }
 
 // ResourceRollouts is the resource type for Argo Rollouts.
@@ -530,6 +788,8 @@ func ListRollouts(ctx context.Context, dynamicClient dynamic.Interface, namespac
 			Age:       formatAge(r.GetCreationTimestamp().Time),
 			Status:    status,
 			Labels:    selectorLabels,
+
+			HelmRelease: HelmReleaseName(labels, r.GetAnnotations()),
 		})
 	}
 	return workloads, nil
@@ -545,6 +805,10 @@ func GetWorkloadPods(ctx context.Context, clientset kubernetes.Interface, worklo
 		}
 		return []PodInfo{podToPodInfo(pod)}, nil
 	}
+	// An empty selector would match every pod in the namespace
+	if workload.Type == ResourceServices && len(workload.Labels) == 0 {
+		return nil, nil
+	}
 
 	labelSelector := labels.SelectorFromSet(workload.Labels).String()
 	pods, err := clientset.CoreV1().Pods(workload.Namespace).List(ctx, metav1.ListOptions{
@@ -571,24 +835,55 @@ func GetPod(ctx context.Context, clientset kubernetes.Interface, namespace, name
 	return &info, nil
 }
 
-// ListAllPods returns all pods in a namespace as PodInfo
+// ListAllPods returns all pods in a namespace as PodInfo.
+// Large namespaces are fetched in pages of DefaultPageSize.
 func ListAllPods(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]PodInfo, error) {
-	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	var podInfos []PodInfo
+	continueToken := ""
+	for {
+		page, next, err := ListPodsPage(ctx, clientset, namespace, DefaultPageSize, continueToken)
+		if err != nil {
+			return nil, err
+		}
+		podInfos = append(podInfos, page...)
+		if next == "" {
+			break
+		}
+		continueToken = next
+	}
+
+	// Sort pods by name for consistent display
+	sortPodsByName(podInfos)
+
+	return podInfos, nil
+}
+
+// ListPodsPage returns one page of at most limit pods, sorted by name,
+// starting at continueToken. The returned token is empty once the last page
+// has been read. The API server returns pages in name order, so appending
+// pages keeps the combined list sorted.
+func ListPodsPage(ctx context.Context, clientset kubernetes.Interface, namespace string, limit int64, continueToken string) ([]PodInfo, string, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		Limit:    limit,
+		Continue: continueToken,
+	})
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var podInfos []PodInfo
 	for _, p := range pods.Items {
 		podInfos = append(podInfos, podToPodInfo(&p))
 	}
+	sortPodsByName(podInfos)
 
-	// Sort pods by name for consistent display
-	sort.Slice(podInfos, func(i, j int) bool {
-		return podInfos[i].Name < podInfos[j].Name
-	})
+	return podInfos, pods.Continue, nil
+}
 
-	return podInfos, nil
+func sortPodsByName(pods []PodInfo) {
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
 }
 
 // ListConfigMaps returns all configmaps in a namespace
@@ -601,9 +896,10 @@ func ListConfigMaps(ctx context.Context, clientset kubernetes.Interface, namespa
 	var cmInfos []ConfigMapInfo
 	for _, cm := range cms.Items {
 		cmInfos = append(cmInfos, ConfigMapInfo{
-			Name: cm.Name,
-			Age:  formatAge(cm.CreationTimestamp.Time),
-			Keys: len(cm.Data),
+			Name:     cm.Name,
+			Age:      formatAge(cm.CreationTimestamp.Time),
+			Keys:     len(cm.Data),
+			KeyNames: dataKeys(cm.Data, cm.BinaryData),
 		})
 	}
 
@@ -616,10 +912,12 @@ func ListConfigMaps(ctx context.Context, clientset kubernetes.Interface, namespa
 
 // ConfigMapData holds full ConfigMap data
 type ConfigMapData struct {
-	Name      string
-	Namespace string
-	Age       string
-	Data      map[string]string
+	Name            string
+	Namespace       string
+	Age             string
+	Data            map[string]string
+	ResourceVersion string               // Version read, to detect concurrent updates on save
+	Updates         []FieldManagerUpdate // Last writes by field manager, most recent first
 }
 
 // GetConfigMap returns full ConfigMap data
@@ -630,10 +928,12 @@ func GetConfigMap(ctx context.Context, clientset kubernetes.Interface, namespace
 	}
 
 	return &ConfigMapData{
-		Name:      cm.Name,
-		Namespace: cm.Namespace,
-		Age:       formatAge(cm.CreationTimestamp.Time),
-		Data:      cm.Data,
+		Name:            cm.Name,
+		Namespace:       cm.Namespace,
+		Age:             formatAge(cm.CreationTimestamp.Time),
+		Data:            cm.Data,
+		ResourceVersion: cm.ResourceVersion,
+		Updates:         fieldManagerUpdates(cm.ManagedFields),
 	}, nil
 }
 
@@ -647,37 +947,96 @@ func ListHPAs(ctx context.Context, clientset kubernetes.Interface, namespace str
 
 	var hpaInfos []HPAInfo
 	for _, hpa := range hpas.Items {
-		// Build reference string (e.g., "Deployment/my-app")
-		reference := fmt.Sprintf("%s/%s", hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name)
+		hpaInfos = append(hpaInfos, hpaToHPAInfo(hpa))
+	}
 
-		// Build targets string showing current/target metrics
-		targets := formatHPATargets(hpa)
+	sort.Slice(hpaInfos, func(i, j int) bool {
+		//coverage:ignore
+		return hpaInfos[i].Name < hpaInfos[j].Name
+	})
 
-		minReplicas := int32(1)
-		if hpa.Spec.MinReplicas != nil {
-			//coverage:ignore
-			minReplicas = *hpa.Spec.MinReplicas
-		}
+	return hpaInfos, nil
+}
 
-		hpaInfos = append(hpaInfos, HPAInfo{
-			Name:        hpa.Name,
-			Reference:   reference,
-			Targets:     targets,
-			MinReplicas: minReplicas,
-			MaxReplicas: hpa.Spec.MaxReplicas,
-			Replicas:    hpa.Status.CurrentReplicas,
-			Age:         formatAge(hpa.CreationTimestamp.Time),
-		})
+// ListHPAsV1 returns the HorizontalPodAutoscalers of a namespace read with
+// autoscaling/v1, for clusters older than 1.23 that don't serve v2. Only
+// the CPU utilization target is known.
+func ListHPAsV1(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]HPAInfo, error) {
+	hpas, err := clientset.AutoscalingV1().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
 	}
 
+	var hpaInfos []HPAInfo
+	for _, hpa := range hpas.Items {
+		hpaInfos = append(hpaInfos, hpaToHPAInfo(hpaV1ToV2(hpa)))
+	}
 	sort.Slice(hpaInfos, func(i, j int) bool {
-		//coverage:ignore
 		return hpaInfos[i].Name < hpaInfos[j].Name
 	})
-
 	return hpaInfos, nil
 }
 
+// hpaV1ToV2 converts an autoscaling/v1 HPA to v2, where its CPU target is
+// a resource metric.
+func hpaV1ToV2(hpa autoscalingv1.HorizontalPodAutoscaler) autoscalingv2.HorizontalPodAutoscaler {
+	v2 := autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: hpa.ObjectMeta,
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				Kind:       hpa.Spec.ScaleTargetRef.Kind,
+				Name:       hpa.Spec.ScaleTargetRef.Name,
+				APIVersion: hpa.Spec.ScaleTargetRef.APIVersion,
+			},
+			MinReplicas: hpa.Spec.MinReplicas,
+			MaxReplicas: hpa.Spec.MaxReplicas,
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: hpa.Status.CurrentReplicas,
+			DesiredReplicas: hpa.Status.DesiredReplicas,
+		},
+	}
+	if target := hpa.Spec.TargetCPUUtilizationPercentage; target != nil {
+		v2.Spec.Metrics = []autoscalingv2.MetricSpec{{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name:   corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: target},
+			},
+		}}
+		if current := hpa.Status.CurrentCPUUtilizationPercentage; current != nil {
+			v2.Status.CurrentMetrics = []autoscalingv2.MetricStatus{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricStatus{
+					Name:    corev1.ResourceCPU,
+					Current: autoscalingv2.MetricValueStatus{AverageUtilization: current},
+				},
+			}}
+		}
+	}
+	return v2
+}
+
+func hpaToHPAInfo(hpa autoscalingv2.HorizontalPodAutoscaler) HPAInfo {
+	minReplicas := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		//coverage:ignore
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+
+	return HPAInfo{
+		Name: hpa.Name,
+		// Build reference string (e.g., "Deployment/my-app")
+		Reference: fmt.Sprintf("%s/%s", hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name),
+		// Build targets string showing current/target metrics
+		Targets:     formatHPATargets(hpa),
+		MinReplicas: minReplicas,
+		MaxReplicas: hpa.Spec.MaxReplicas,
+		Replicas:    hpa.Status.CurrentReplicas,
+		Age:         formatAge(hpa.CreationTimestamp.Time),
+	}
+}
+
 // formatHPATargets formats HPA metrics as a readable string
 func formatHPATargets(hpa autoscalingv2.HorizontalPodAutoscaler) string {
 	var parts []string
@@ -764,6 +1123,8 @@ type HPAData struct {
 	DesiredReplicas int32
 	Metrics         []HPAMetricDetail
 	Conditions      []HPACondition
+	Behavior        *HPABehavior    // nil when spec.behavior is not set
+	Events          []HPAScaleEvent // Recent rescales, oldest first
 	Labels          map[string]string
 	Annotations     map[string]string
 }
@@ -774,6 +1135,7 @@ type HPAMetricDetail struct {
 	Name    string
 	Current string
 	Target  string
+	Ratio   float64 // Current divided by target; 0 when either is unknown
 }
 
 // HPACondition holds HPA condition status
@@ -827,8 +1189,10 @@ func GetHPA(ctx context.Context, clientset kubernetes.Interface, namespace, name
 					if cm.Type == autoscalingv2.ResourceMetricSourceType && cm.Resource != nil && cm.Resource.Name == metric.Resource.Name {
 						if cm.Resource.Current.AverageUtilization != nil {
 							detail.Current = fmt.Sprintf("%d%%", *cm.Resource.Current.AverageUtilization)
+							detail.Ratio = utilizationRatio(cm.Resource.Current.AverageUtilization, metric.Resource.Target.AverageUtilization)
 						} else if cm.Resource.Current.AverageValue != nil {
 							detail.Current = cm.Resource.Current.AverageValue.String()
+							detail.Ratio = quantityRatio(cm.Resource.Current.AverageValue, metric.Resource.Target.AverageValue)
 						}
 						break
 					}
@@ -848,8 +1212,10 @@ func GetHPA(ctx context.Context, clientset kubernetes.Interface, namespace, name
 					if cm.Type == autoscalingv2.ExternalMetricSourceType && cm.External != nil && cm.External.Metric.Name == metric.External.Metric.Name {
 						if cm.External.Current.AverageValue != nil {
 							detail.Current = cm.External.Current.AverageValue.String()
+							detail.Ratio = quantityRatio(cm.External.Current.AverageValue, metric.External.Target.AverageValue)
 						} else if cm.External.Current.Value != nil {
 							detail.Current = cm.External.Current.Value.String()
+							detail.Ratio = quantityRatio(cm.External.Current.Value, metric.External.Target.Value)
 						}
 						break
 					}
@@ -859,7 +1225,18 @@ func GetHPA(ctx context.Context, clientset kubernetes.Interface, namespace, name
 			if metric.Pods != nil {
 				detail.Type = "Pods"
 				detail.Name = metric.Pods.Metric.Name
-				detail.Target = metric.Pods.Target.AverageValue.String()
+				if metric.Pods.Target.AverageValue != nil {
+					detail.Target = metric.Pods.Target.AverageValue.String()
+				}
+				for _, cm := range hpa.Status.CurrentMetrics {
+					if cm.Type == autoscalingv2.PodsMetricSourceType && cm.Pods != nil && cm.Pods.Metric.Name == metric.Pods.Metric.Name {
+						if cm.Pods.Current.AverageValue != nil {
+							detail.Current = cm.Pods.Current.AverageValue.String()
+							detail.Ratio = quantityRatio(cm.Pods.Current.AverageValue, metric.Pods.Target.AverageValue)
+						}
+						break
+					}
+				}
 			}
 		case autoscalingv2.ObjectMetricSourceType:
 			if metric.Object != nil {
@@ -870,6 +1247,18 @@ func GetHPA(ctx context.Context, clientset kubernetes.Interface, namespace, name
 				} else if metric.Object.Target.AverageValue != nil {
 					detail.Target = metric.Object.Target.AverageValue.String()
 				}
+				for _, cm := range hpa.Status.CurrentMetrics {
+					if cm.Type == autoscalingv2.ObjectMetricSourceType && cm.Object != nil && cm.Object.Metric.Name == metric.Object.Metric.Name {
+						if cm.Object.Current.Value != nil {
+							detail.Current = cm.Object.Current.Value.String()
+							detail.Ratio = quantityRatio(cm.Object.Current.Value, metric.Object.Target.Value)
+						} else if cm.Object.Current.AverageValue != nil {
+							detail.Current = cm.Object.Current.AverageValue.String()
+							detail.Ratio = quantityRatio(cm.Object.Current.AverageValue, metric.Object.Target.AverageValue)
+						}
+						break
+					}
+				}
 			}
 		}
 		if detail.Current == "" {
@@ -888,6 +1277,12 @@ func GetHPA(ctx context.Context, clientset kubernetes.Interface, namespace, name
 		})
 	}
 
+	data.Behavior = hpaBehavior(hpa.Spec.Behavior)
+	// Events that can't be listed leave the timeline empty
+	if events, err := hpaScaleEvents(ctx, clientset, namespace, name); err == nil {
+		data.Events = events
+	}
+
 	return data, nil
 }
 
@@ -901,10 +1296,11 @@ func ListSecrets(ctx context.Context, clientset kubernetes.Interface, namespace
 	var secretInfos []SecretInfo
 	for _, s := range secrets.Items {
 		secretInfos = append(secretInfos, SecretInfo{
-			Name: s.Name,
-			Type: string(s.Type),
-			Age:  formatAge(s.CreationTimestamp.Time),
-			Keys: len(s.Data),
+			Name:     s.Name,
+			Type:     string(s.Type),
+			Age:      formatAge(s.CreationTimestamp.Time),
+			Keys:     len(s.Data),
+			KeyNames: dataKeys(nil, s.Data),
 		})
 	}
 
@@ -917,11 +1313,13 @@ func ListSecrets(ctx context.Context, clientset kubernetes.Interface, namespace
 
 // SecretData holds full Secret data with decoded values
 type SecretData struct {
-	Name      string
-	Namespace string
-	Type      string
-	Age       string
-	Data      map[string]string // Decoded from base64
+	Name            string
+	Namespace       string
+	Type            string
+	Age             string
+	Data            map[string]string    // Decoded from base64
+	ResourceVersion string               // Version read, to detect concurrent updates on save
+	Updates         []FieldManagerUpdate // Last writes by field manager, most recent first
 }
 
 // ListNodes returns all nodes in the cluster
@@ -979,7 +1377,7 @@ func ListNodes(ctx context.Context, clientset kubernetes.Interface) ([]NodeInfo,
 		cpu := n.Status.Capacity.Cpu().String()
 		memory := n.Status.Capacity.Memory().String()
 
-		nodeInfos = append(nodeInfos, NodeInfo{
+		info := NodeInfo{
 			Name:       n.Name,
 			Status:     status,
 			Roles:      roleStr,
@@ -989,7 +1387,9 @@ func ListNodes(ctx context.Context, clientset kubernetes.Interface) ([]NodeInfo,
 			PodCount:   podCountByNode[n.Name],
 			CPU:        cpu,
 			Memory:     memory,
-		})
+		}
+		setNodeSchedulingInfo(&info, &n)
+		nodeInfos = append(nodeInfos, info)
 	}
 
 	sort.Slice(nodeInfos, func(i, j int) bool {
@@ -1007,13 +1407,17 @@ func GetNode(ctx context.Context, clientset kubernetes.Interface, nodeName strin
 		return nil, err
 	}
 
-	// Get pod count for this node
-	pods, _ := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
-		FieldSelector: "spec.nodeName=" + nodeName,
-	})
-	podCount := 0
-	if pods != nil {
-		podCount = len(pods.Items)
+	// Get pod count and committed requests for this node
+	pods, _ := ListPodsByNode(ctx, clientset, nodeName)
+	podCount := len(pods)
+	var requestedCPU, requestedMem int64
+	for _, p := range pods {
+		if p.Phase == corev1.PodSucceeded || p.Phase == corev1.PodFailed {
+			continue
+		}
+		cpu, mem := podInfoRequests(p)
+		requestedCPU += cpu
+		requestedMem += mem
 	}
 
 	// Get node status
@@ -1045,17 +1449,50 @@ func GetNode(ctx context.Context, clientset kubernetes.Interface, nodeName strin
 	cpu := n.Status.Capacity.Cpu().String()
 	memory := n.Status.Capacity.Memory().String()
 
-	return &NodeInfo{
-		Name:       n.Name,
-		Status:     status,
-		Roles:      roleStr,
-		Age:        formatAge(n.CreationTimestamp.Time),
-		Version:    n.Status.NodeInfo.KubeletVersion,
-		InternalIP: internalIP,
-		PodCount:   podCount,
-		CPU:        cpu,
-		Memory:     memory,
-	}, nil
+	info := &NodeInfo{
+		Name:            n.Name,
+		Status:          status,
+		Roles:           roleStr,
+		Age:             formatAge(n.CreationTimestamp.Time),
+		Version:         n.Status.NodeInfo.KubeletVersion,
+		InternalIP:      internalIP,
+		PodCount:        podCount,
+		CPU:             cpu,
+		Memory:          memory,
+		RequestedCPU:    formatCPU(requestedCPU),
+		RequestedMemory: formatMemory(requestedMem),
+	}
+	setNodeSchedulingInfo(info, n)
+	return info, nil
+}
+
+// setNodeSchedulingInfo fills in what decides whether pods can be scheduled
+// on the node: allocatable resources, taints, cordoning and pressure
+// conditions.
+func setNodeSchedulingInfo(info *NodeInfo, n *corev1.Node) {
+	info.AllocatableCPU = n.Status.Allocatable.Cpu().String()
+	info.AllocatableMemory = n.Status.Allocatable.Memory().String()
+	info.Unschedulable = n.Spec.Unschedulable
+
+	for _, t := range n.Spec.Taints {
+		info.Taints = append(info.Taints, TaintInfo{
+			Key:    t.Key,
+			Value:  t.Value,
+			Effect: string(t.Effect),
+		})
+	}
+
+	for _, cond := range n.Status.Conditions {
+		pressure := cond.Status == corev1.ConditionTrue
+		switch cond.Type {
+		case corev1.NodeMemoryPressure:
+			info.MemoryPressure = pressure
+		case corev1.NodeDiskPressure:
+			info.DiskPressure = pressure
+		case corev1.NodePIDPressure:
+			info.PIDPressure = pressure
+		}
+	}
 }
 
 // ListPodsByNode returns all pods running on a specific node
@@ -1095,11 +1532,13 @@ func GetSecret(ctx context.Context, clientset kubernetes.Interface, namespace, n
 	}
 
 	return &SecretData{
-		Name:      secret.Name,
-		Namespace: secret.Namespace,
-		Type:      string(secret.Type),
-		Age:       formatAge(secret.CreationTimestamp.Time),
-		Data:      decodedData,
+		Name:            secret.Name,
+		Namespace:       secret.Namespace,
+		Type:            string(secret.Type),
+		Age:             formatAge(secret.CreationTimestamp.Time),
+		Data:            decodedData,
+		ResourceVersion: secret.ResourceVersion,
+		Updates:         fieldManagerUpdates(secret.ManagedFields),
 	}, nil
 }
 
@@ -1324,6 +1763,7 @@ func podToPodInfo(p *corev1.Pod) PodInfo {
 				Name:          port.Name,
 				ContainerPort: port.ContainerPort,
 				Protocol:      string(port.Protocol),
+				HostPort:      port.HostPort,
 			})
 		}
 
@@ -1341,16 +1781,7 @@ func podToPodInfo(p *corev1.Pod) PodInfo {
 		ci.ReadinessProbe = parseProbe(c.ReadinessProbe)
 		ci.StartupProbe = parseProbe(c.StartupProbe)
 
-		// Parse security context
-		if c.SecurityContext != nil {
-			ci.SecurityContext = &SecurityContextInfo{
-				RunAsUser:    c.SecurityContext.RunAsUser,
-				RunAsGroup:   c.SecurityContext.RunAsGroup,
-				RunAsNonRoot: c.SecurityContext.RunAsNonRoot,
-				Privileged:   c.SecurityContext.Privileged,
-				ReadOnlyRoot: c.SecurityContext.ReadOnlyRootFilesystem,
-			}
-		}
+		ci.SecurityContext = parseSecurityContext(c.SecurityContext)
 
 		// Get status from status map
 		if cs, ok := statusMap[c.Name]; ok {
@@ -1373,6 +1804,7 @@ func podToPodInfo(p *corev1.Pod) PodInfo {
 				ci.StartedAt = cs.State.Terminated.StartedAt.Format("2006-01-02 15:04:05")
 				ci.FinishedAt = cs.State.Terminated.FinishedAt.Format("2006-01-02 15:04:05")
 			}
+			ci.LastTermination = terminationInfo(cs.LastTerminationState.Terminated)
 		}
 
 		containers = append(containers, ci)
@@ -1389,6 +1821,15 @@ func podToPodInfo(p *corev1.Pod) PodInfo {
 			Name:            c.Name,
 			Image:           c.Image,
 			ImagePullPolicy: string(c.ImagePullPolicy),
+			SecurityContext: parseSecurityContext(c.SecurityContext),
+		}
+		for _, port := range c.Ports {
+			ci.Ports = append(ci.Ports, ContainerPort{
+				Name:          port.Name,
+				ContainerPort: port.ContainerPort,
+				Protocol:      string(port.Protocol),
+				HostPort:      port.HostPort,
+			})
 		}
 		if cs, ok := initStatusMap[c.Name]; ok {
 			ci.Ready = cs.Ready
@@ -1400,15 +1841,48 @@ func podToPodInfo(p *corev1.Pod) PodInfo {
 				//coverage:ignore
 				ci.State = "Waiting"
 				ci.Reason = cs.State.Waiting.Reason
+				ci.Message = cs.State.Waiting.Message
 			} else if cs.State.Terminated != nil {
 				ci.State = "Terminated"
 				ci.Reason = cs.State.Terminated.Reason
 				ci.ExitCode = &cs.State.Terminated.ExitCode
 			}
+			ci.LastTermination = terminationInfo(cs.LastTerminationState.Terminated)
 		}
 		initContainers = append(initContainers, ci)
 	}
 
+	// Parse ephemeral (debug) containers
+	var ephemeralContainers []ContainerInfo
+	ephemeralStatusMap := make(map[string]corev1.ContainerStatus)
+	for _, cs := range p.Status.EphemeralContainerStatuses {
+		ephemeralStatusMap[cs.Name] = cs
+	}
+	for _, c := range p.Spec.EphemeralContainers {
+		ci := ContainerInfo{
+			Name:            c.Name,
+			Image:           c.Image,
+			ImagePullPolicy: string(c.ImagePullPolicy),
+			TargetContainer: c.TargetContainerName,
+			SecurityContext: parseSecurityContext(c.SecurityContext),
+		}
+		if cs, ok := ephemeralStatusMap[c.Name]; ok {
+			switch {
+			case cs.State.Running != nil:
+				ci.State = "Running"
+				ci.StartedAt = cs.State.Running.StartedAt.Format("2006-01-02 15:04:05")
+			case cs.State.Waiting != nil:
+				ci.State = "Waiting"
+				ci.Reason = cs.State.Waiting.Reason
+			case cs.State.Terminated != nil:
+				ci.State = "Terminated"
+				ci.Reason = cs.State.Terminated.Reason
+				ci.ExitCode = &cs.State.Terminated.ExitCode
+			}
+		}
+		ephemeralContainers = append(ephemeralContainers, ci)
+	}
+
 	ready := 0
 	for _, cs := range p.Status.ContainerStatuses {
 		if cs.Ready {
@@ -1449,6 +1923,11 @@ func podToPodInfo(p *corev1.Pod) PodInfo {
 		case v.Projected != nil:
 			//coverage:ignore
 			vi.Type = "Projected"
+			for _, src := range v.Projected.Sources {
+				if src.ServiceAccountToken != nil {
+					vi.Source = ProjectedTokenSource
+				}
+			}
 		case v.DownwardAPI != nil:
 			//coverage:ignore
 			vi.Type = "DownwardAPI"
@@ -1470,18 +1949,69 @@ func podToPodInfo(p *corev1.Pod) PodInfo {
 		})
 	}
 
+	// Parse scheduling gates
+	var schedulingGates []string
+	for _, g := range p.Spec.SchedulingGates {
+		schedulingGates = append(schedulingGates, g.Name)
+	}
+
+	var readinessGates []string
+	for _, g := range p.Spec.ReadinessGates {
+		readinessGates = append(readinessGates, string(g.ConditionType))
+	}
+
+	// Parse pod-level security
+	var podSecurity *PodSecurityContext
+	if sc := p.Spec.SecurityContext; sc != nil {
+		podSecurity = &PodSecurityContext{
+			RunAsUser:          sc.RunAsUser,
+			RunAsGroup:         sc.RunAsGroup,
+			RunAsNonRoot:       sc.RunAsNonRoot,
+			FSGroup:            sc.FSGroup,
+			SupplementalGroups: sc.SupplementalGroups,
+			SeccompProfile:     seccompProfileName(sc.SeccompProfile),
+		}
+	}
+	var hostNamespaces []string
+	if p.Spec.HostNetwork {
+		hostNamespaces = append(hostNamespaces, "network")
+	}
+	if p.Spec.HostPID {
+		hostNamespaces = append(hostNamespaces, "PID")
+	}
+	if p.Spec.HostIPC {
+		hostNamespaces = append(hostNamespaces, "IPC")
+	}
+
 	// Get termination grace period
 	var terminationGrace int64 = 30 // default
 	if p.Spec.TerminationGracePeriodSeconds != nil {
 		terminationGrace = *p.Spec.TerminationGracePeriodSeconds
 	}
 
+	// Parse image pull secrets
+	var pullSecrets []string
+	for _, s := range p.Spec.ImagePullSecrets {
+		pullSecrets = append(pullSecrets, s.Name)
+	}
+
+	// Get required node affinity
+	var nodeAffinity *corev1.NodeSelector
+	if a := p.Spec.Affinity; a != nil && a.NodeAffinity != nil {
+		nodeAffinity = a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	}
+
 	// Get start time
 	var startTime string
 	if p.Status.StartTime != nil {
 		startTime = p.Status.StartTime.Format("2006-01-02 15:04:05")
 	}
 
+	var deletionTimestamp time.Time
+	if p.DeletionTimestamp != nil {
+		deletionTimestamp = p.DeletionTimestamp.Time
+	}
+
 	return PodInfo{
 		Name:                   p.Name,
 		Namespace:              p.Namespace,
@@ -1496,22 +2026,85 @@ func podToPodInfo(p *corev1.Pod) PodInfo {
 		Annotations:            p.Annotations,
 		Containers:             containers,
 		InitContainers:         initContainers,
+		EphemeralContainers:    ephemeralContainers,
 		Conditions:             p.Status.Conditions,
 		Phase:                  p.Status.Phase,
 		OwnerRef:               ownerRef,
 		OwnerKind:              ownerKind,
 		QoSClass:               string(p.Status.QOSClass),
 		ServiceAccount:         p.Spec.ServiceAccountName,
+		ImagePullSecrets:       pullSecrets,
 		Volumes:                volumes,
 		RestartPolicy:          string(p.Spec.RestartPolicy),
 		DNSPolicy:              string(p.Spec.DNSPolicy),
 		PriorityClassName:      p.Spec.PriorityClassName,
 		Priority:               p.Spec.Priority,
 		NodeSelector:           p.Spec.NodeSelector,
+		NodeAffinity:           nodeAffinity,
 		Tolerations:            tolerations,
+		SchedulingGates:        schedulingGates,
+		ReadinessGates:         readinessGates,
+		SecurityContext:        podSecurity,
+		HostNamespaces:         hostNamespaces,
 		TerminationGracePeriod: terminationGrace,
 		StartTime:              startTime,
+		UID:                    string(p.UID),
+		DeletionTimestamp:      deletionTimestamp,
+	}
+}
+
+// terminationInfo converts a terminated container state, returning nil
+// when there is none.
+func terminationInfo(t *corev1.ContainerStateTerminated) *TerminationInfo {
+	if t == nil {
+		return nil
+	}
+	return &TerminationInfo{
+		Reason:     t.Reason,
+		Message:    t.Message,
+		ExitCode:   t.ExitCode,
+		FinishedAt: t.FinishedAt.Format("2006-01-02 15:04:05"),
+		Finished:   t.FinishedAt.Time,
+		Started:    t.StartedAt.Time,
+	}
+}
+
+// parseSecurityContext converts a container security context, returning
+// nil when there is none.
+func parseSecurityContext(sc *corev1.SecurityContext) *SecurityContextInfo {
+	if sc == nil {
+		return nil
+	}
+	info := &SecurityContextInfo{
+		RunAsUser:                sc.RunAsUser,
+		RunAsGroup:               sc.RunAsGroup,
+		RunAsNonRoot:             sc.RunAsNonRoot,
+		Privileged:               sc.Privileged,
+		ReadOnlyRoot:             sc.ReadOnlyRootFilesystem,
+		AllowPrivilegeEscalation: sc.AllowPrivilegeEscalation,
+		SeccompProfile:           seccompProfileName(sc.SeccompProfile),
+	}
+	if sc.Capabilities != nil {
+		for _, c := range sc.Capabilities.Add {
+			info.CapabilitiesAdd = append(info.CapabilitiesAdd, string(c))
+		}
+		for _, c := range sc.Capabilities.Drop {
+			info.CapabilitiesDrop = append(info.CapabilitiesDrop, string(c))
+		}
+	}
+	return info
+}
+
+// seccompProfileName describes a seccomp profile as its type, followed by
+// the profile's path for a Localhost one.
+func seccompProfileName(profile *corev1.SeccompProfile) string {
+	if profile == nil {
+		return ""
+	}
+	if profile.Type == corev1.SeccompProfileTypeLocalhost && profile.LocalhostProfile != nil {
+		return string(profile.Type) + "/" + *profile.LocalhostProfile
 	}
+	return string(profile.Type)
 }
 
 func parseProbe(probe *corev1.Probe) *ProbeInfo {
@@ -1532,15 +2125,29 @@ func parseProbe(probe *corev1.Probe) *ProbeInfo {
 		pi.Path = probe.HTTPGet.Path
 		pi.Port = probe.HTTPGet.Port.IntVal
 		pi.Scheme = string(probe.HTTPGet.Scheme)
+		pi.Host = probe.HTTPGet.Host
+		if probe.HTTPGet.Port.Type == intstr.String {
+			pi.PortName = probe.HTTPGet.Port.StrVal
+		}
+		for _, h := range probe.HTTPGet.HTTPHeaders {
+			pi.Headers = append(pi.Headers, ProbeHeader{Name: h.Name, Value: h.Value})
+		}
 	} else if probe.TCPSocket != nil {
 		pi.Type = "TCP"
 		pi.Port = probe.TCPSocket.Port.IntVal
+		pi.Host = probe.TCPSocket.Host
+		if probe.TCPSocket.Port.Type == intstr.String {
+			pi.PortName = probe.TCPSocket.Port.StrVal
+		}
 	} else if probe.Exec != nil {
 		pi.Type = "Exec"
 		pi.Command = probe.Exec.Command
 	} else if probe.GRPC != nil {
 		pi.Type = "gRPC"
 		pi.Port = probe.GRPC.Port
+		if probe.GRPC.Service != nil {
+			pi.Service = *probe.GRPC.Service
+		}
 	}
 
 	return pi
@@ -1550,6 +2157,9 @@ func getPodStatus(p *corev1.Pod) string {
 	if p.DeletionTimestamp != nil {
 		return "Terminating"
 	}
+	if status := initContainerStatus(p); status != "" {
+		return status
+	}
 
 	for _, cs := range p.Status.ContainerStatuses {
 		if cs.State.Waiting != nil {
@@ -1565,14 +2175,85 @@ func getPodStatus(p *corev1.Pod) string {
 	return string(p.Status.Phase)
 }
 
+// initContainerStatus returns the status of a pod still initializing, the
+// way kubectl shows it: Init:<reason> for a failing or waiting init
+// container, Init:ExitCode:<code> when it exited without a reason, or
+// Init:N/M with the number of init containers done. Returns "" once every
+// init container completed; sidecars (restartable init containers) count
+// as done once started.
+func initContainerStatus(p *corev1.Pod) string {
+	sidecars := make(map[string]bool)
+	for _, c := range p.Spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			sidecars[c.Name] = true
+		}
+	}
+	total := len(p.Spec.InitContainers)
+	if total == 0 {
+		total = len(p.Status.InitContainerStatuses)
+	}
+
+	for i, cs := range p.Status.InitContainerStatuses {
+		switch {
+		case cs.State.Terminated != nil && cs.State.Terminated.ExitCode == 0:
+			continue
+		case sidecars[cs.Name] && cs.Started != nil && *cs.Started:
+			continue
+		case cs.State.Terminated != nil:
+			if cs.State.Terminated.Reason != "" {
+				return "Init:" + cs.State.Terminated.Reason
+			}
+			if cs.State.Terminated.Signal != 0 {
+				return fmt.Sprintf("Init:Signal:%d", cs.State.Terminated.Signal)
+			}
+			return fmt.Sprintf("Init:ExitCode:%d", cs.State.Terminated.ExitCode)
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && cs.State.Waiting.Reason != "PodInitializing":
+			return "Init:" + cs.State.Waiting.Reason
+		default:
+			return fmt.Sprintf("Init:%d/%d", i, total)
+		}
+	}
+	return ""
+}
+
+// IsInitStatus reports whether a pod status is one of the Init: statuses of
+// a pod stuck in or still running its init containers.
+func IsInitStatus(status string) bool {
+	return strings.HasPrefix(status, "Init:")
+}
+
+// FailingInitContainer returns the init container a pod stuck in init is
+// waiting on: the first one that has neither completed successfully nor
+// started as a ready sidecar. Returns "" if there is none.
+func FailingInitContainer(pod *PodInfo) string {
+	for _, c := range pod.InitContainers {
+		if c.State == "Terminated" && c.ExitCode != nil && *c.ExitCode == 0 {
+			continue
+		}
+		if c.State == "Running" && c.Ready {
+			continue
+		}
+		return c.Name
+	}
+	return ""
+}
+
 type RelatedResources struct {
-	Services        []ServiceInfo
-	Ingresses       []IngressInfo
-	VirtualServices []VirtualServiceInfo
-	Gateways        []GatewayInfo
-	ConfigMaps      []string
-	Secrets         []string
-	Owner           *OwnerInfo
+	Services         []ServiceInfo
+	Ingresses        []IngressInfo
+	VirtualServices  []VirtualServiceInfo
+	Gateways         []GatewayInfo
+	DestinationRules []DestinationRuleInfo // Istio DestinationRules for the pod's Services
+	Sidecar          *SidecarStatus        // Istio sidecar of the pod; nil when Istio is not involved
+	ConfigMaps       []string
+	Secrets          []string
+	PVCs             []PVCInfo
+	Owner            *OwnerInfo
+	NetworkPolicies  NetworkPolicyResult // Policies selecting the pod
+	PDB              *PDBInfo            // PodDisruptionBudget selecting the pod; nil if none
+	HPA              *HPAInfo            // HorizontalPodAutoscaler scaling the owning workload; nil if none
+	RBAC             *PodRBAC            // Permissions of the pod's ServiceAccount; nil if RoleBindings can't be listed
+	Webhooks         []WebhookInfo       // Admission webhooks matching the pod and its namespace; nil if they can't be listed
 }
 
 type GatewayInfo struct {
@@ -1589,11 +2270,16 @@ type GatewayServer struct {
 }
 
 type ServiceInfo struct {
-	Name      string
-	Type      string
-	ClusterIP string
-	Ports     string
-	Endpoints int
+	Name       string
+	Type       string
+	ClusterIP  string
+	Ports      string
+	Endpoints  int
+	NoSelector bool // Endpoints are not selected from pods (ExternalName, manual endpoints)
+
+	PortNumbers []int32 // The ports of Ports, for port-forwarding to the Service
+
+	PodEndpoint PodEndpointState // Whether the pod is a ready endpoint of the Service
 }
 
 type IngressInfo struct {
@@ -1635,15 +2321,18 @@ type VirtualServiceRoute struct {
 type OwnerInfo struct {
 	Kind          string
 	Name          string
-	WorkloadKind  string // Parent of ReplicaSet (Deployment, etc)
+	WorkloadKind  string         // Parent of ReplicaSet (Deployment, etc)
 	WorkloadName  string
-	Replicas      int32  // Desired replicas
-	ReadyReplicas int32  // Ready replicas
+	Replicas      int32          // Desired replicas
+	ReadyReplicas int32          // Ready replicas
+	Rollout       *RolloutStatus // Step, weight and pause state; nil unless the workload is an Argo Rollout
+	Failures      []string       // Messages of the ReplicaFailure conditions of the ReplicaSet and workload
 }
 
 // GetRelatedResources discovers resources related to a pod.
-// Returns services, ingresses, VirtualServices, gateways, ConfigMaps, and Secrets
-// that are connected to the pod through labels or volume mounts.
+// Returns services, ingresses, VirtualServices, gateways, ConfigMaps, Secrets,
+// PersistentVolumeClaims, NetworkPolicies and the PodDisruptionBudget that
+// are connected to the pod through labels or volume mounts.
 func GetRelatedResources(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, pod PodInfo) (*RelatedResources, error) {
 	related := &RelatedResources{}
 
@@ -1656,6 +2345,9 @@ func GetRelatedResources(ctx context.Context, clientset kubernetes.Interface, dy
 		// If owner is ReplicaSet, fetch the parent workload (Deployment, Rollout, etc)
 		if pod.OwnerKind == "ReplicaSet" {
 			rs, err := clientset.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, pod.OwnerRef, metav1.GetOptions{})
+			if err == nil {
+				related.Owner.Failures = replicaFailures(rs.Status.Conditions)
+			}
 			if err == nil && len(rs.OwnerReferences) > 0 {
 				related.Owner.WorkloadKind = rs.OwnerReferences[0].Kind
 				related.Owner.WorkloadName = rs.OwnerReferences[0].Name
@@ -1667,6 +2359,11 @@ func GetRelatedResources(ctx context.Context, clientset kubernetes.Interface, dy
 					if err == nil {
 						related.Owner.Replicas = *dep.Spec.Replicas
 						related.Owner.ReadyReplicas = dep.Status.ReadyReplicas
+						for _, c := range dep.Status.Conditions {
+							if c.Type == appsv1.DeploymentReplicaFailure && c.Status == corev1.ConditionTrue {
+								related.Owner.Failures = append(related.Owner.Failures, c.Message)
+							}
+						}
 					}
 				case "StatefulSet":
 					sts, err := clientset.AppsV1().StatefulSets(pod.Namespace).Get(ctx, related.Owner.WorkloadName, metav1.GetOptions{})
@@ -1685,6 +2382,7 @@ func GetRelatedResources(ctx context.Context, clientset kubernetes.Interface, dy
 						rollout, err := dynamicClient.Resource(rolloutGVR).Namespace(pod.Namespace).Get(ctx, related.Owner.WorkloadName, metav1.GetOptions{})
 						if err == nil { //coverage:ignore
 							related.Owner.Replicas, related.Owner.ReadyReplicas = extractRolloutReplicas(rollout.Object)
+							related.Owner.Rollout = ExtractRolloutStatus(rollout.Object)
 						}
 					}
 				}
@@ -1699,24 +2397,15 @@ func GetRelatedResources(ctx context.Context, clientset kubernetes.Interface, dy
 				continue
 			}
 			if labelsMatch(svc.Spec.Selector, pod.Labels) {
-				var ports []string
-				for _, p := range svc.Spec.Ports {
-					ports = append(ports, fmt.Sprintf("%d/%s", p.Port, p.Protocol))
-				}
+				info := serviceToServiceInfo(&svc)
 
 				// Use EndpointSlice instead of deprecated Endpoints API
-				epSlices, _ := clientset.DiscoveryV1().EndpointSlices(pod.Namespace).List(ctx, metav1.ListOptions{
-					LabelSelector: discoveryv1.LabelServiceName + "=" + svc.Name,
-				})
-				endpointCount := countReadyEndpoints(epSlices)
-
-				related.Services = append(related.Services, ServiceInfo{
-					Name:      svc.Name,
-					Type:      string(svc.Spec.Type),
-					ClusterIP: svc.Spec.ClusterIP,
-					Ports:     strings.Join(ports, ", "),
-					Endpoints: endpointCount,
-				})
+				if endpoints, err := GetServiceEndpointsForPod(ctx, clientset, pod, []string{svc.Name}); err == nil {
+					info.Endpoints = endpoints[0].Ready
+					info.PodEndpoint = endpoints[0].State
+				}
+
+				related.Services = append(related.Services, info)
 			}
 		}
 	}
@@ -1807,9 +2496,10 @@ func GetRelatedResources(ctx context.Context, clientset kubernetes.Interface, dy
 		}
 	}
 
-	// Fetch Istio VirtualServices and Gateways using dynamic client
+	// Fetch Istio VirtualServices, Gateways and DestinationRules using dynamic client
 	if dynamicClient != nil {
-		related.VirtualServices, related.Gateways = getIstioResources(ctx, dynamicClient, pod.Namespace, related.Services)
+		related.VirtualServices, related.Gateways, related.DestinationRules = getIstioResources(ctx, dynamicClient, pod.Namespace, related.Services)
+		markPodSubsets(related.DestinationRules, pod.Labels)
 	}
 
 	podObj, err := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
@@ -1832,11 +2522,38 @@ func GetRelatedResources(ctx context.Context, clientset kubernetes.Interface, dy
 				}
 			}
 		}
+		related.PVCs = getPodPVCs(ctx, clientset, podObj)
+		// Without access to the namespace, injection is taken as not enabled
+		ns, _ := clientset.CoreV1().Namespaces().Get(ctx, pod.Namespace, metav1.GetOptions{})
+		related.Sidecar = GetSidecarStatus(podObj, ns)
+	}
+	related.NetworkPolicies = getPodNetworkPolicies(ctx, clientset, pod)
+	// Budgets that can't be listed, e.g. without RBAC permissions, are left out
+	if pdbs, err := ListPDBs(ctx, clientset, pod.Namespace); err == nil {
+		related.PDB = MatchingPDB(pdbs, pod.Labels)
+	}
+	if related.Owner != nil && related.Owner.WorkloadKind != "" {
+		related.HPA, _ = FindHPAForWorkload(ctx, clientset, pod.Namespace, related.Owner.WorkloadKind, related.Owner.WorkloadName)
 	}
+	related.RBAC, _ = GetPodRBAC(ctx, clientset, pod.Namespace, pod.ServiceAccount)
+	// Webhook configurations are cluster-scoped, and often not readable
+	related.Webhooks, _ = ListWebhookConfigurations(ctx, clientset, pod.Namespace, pod.Labels)
 
 	return related, nil
 }
 
+// replicaFailures returns the messages of the true ReplicaFailure
+// conditions of a ReplicaSet, which say why it can't create pods.
+func replicaFailures(conditions []appsv1.ReplicaSetCondition) []string {
+	var messages []string
+	for _, c := range conditions {
+		if c.Type == appsv1.ReplicaSetReplicaFailure && c.Status == corev1.ConditionTrue {
+			messages = append(messages, c.Message)
+		}
+	}
+	return messages
+}
+
 func labelsMatch(selector, labels map[string]string) bool {
 	for k, v := range selector {
 		if labels[k] != v {
@@ -1869,8 +2586,8 @@ func contains(slice []string, str string) bool {
 	return false
 }
 
-// getIstioResources fetches Istio VirtualServices and Gateways using dynamic client
-func getIstioResources(ctx context.Context, dynamicClient dynamic.Interface, namespace string, services []ServiceInfo) ([]VirtualServiceInfo, []GatewayInfo) {
+// getIstioResources fetches Istio VirtualServices, Gateways and DestinationRules using dynamic client
+func getIstioResources(ctx context.Context, dynamicClient dynamic.Interface, namespace string, services []ServiceInfo) ([]VirtualServiceInfo, []GatewayInfo, []DestinationRuleInfo) {
 	var virtualServices []VirtualServiceInfo
 	var gateways []GatewayInfo
 	gatewaySet := make(map[string]bool) // Track which gateways we need to fetch
@@ -1886,7 +2603,7 @@ func getIstioResources(ctx context.Context, dynamicClient dynamic.Interface, nam
 	vsList, err := dynamicClient.Resource(vsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		//coverage:ignore
-		return virtualServices, gateways
+		return virtualServices, gateways, nil
 	}
 
 	serviceNames := make(map[string]bool)
@@ -2056,7 +2773,7 @@ func getIstioResources(ctx context.Context, dynamicClient dynamic.Interface, nam
 		gateways = append(gateways, gwInfo)
 	}
 
-	return virtualServices, gateways
+	return virtualServices, gateways, getDestinationRules(ctx, dynamicClient, namespace, serviceNames)
 }
 
 func GetDeployment(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (*appsv1.Deployment, error) {
@@ -2075,10 +2792,79 @@ func GetJob(ctx context.Context, clientset kubernetes.Interface, namespace, name
 	return clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
+// GetDesiredReplicas returns the spec replica count of a Deployment or
+// StatefulSet. WorkloadInfo.Replicas is the observed count, which lags
+// behind a scale until the controller catches up, so this is what confirms
+// that a scale took effect.
+func GetDesiredReplicas(ctx context.Context, clientset kubernetes.Interface, namespace, name string, resourceType ResourceType) (int32, error) {
+	var replicas *int32
+	switch resourceType {
+	case ResourceDeployments:
+		d, err := GetDeployment(ctx, clientset, namespace, name)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get deployment: %w", err)
+		}
+		replicas = d.Spec.Replicas
+	case ResourceStatefulSets:
+		s, err := GetStatefulSet(ctx, clientset, namespace, name)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get statefulset: %w", err)
+		}
+		replicas = s.Spec.Replicas
+	default:
+		return 0, fmt.Errorf("desired replicas not supported for %s", resourceType)
+	}
+	if replicas == nil {
+		return 1, nil // API server default
+	}
+	return *replicas, nil
+}
+
 func DeletePod(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
 	return clientset.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 }
 
+// RemoveSchedulingGate removes a single scheduling gate from a pending pod.
+// Gates are normally removed by the controller that added them; this is an
+// escape hatch for when that controller is broken or gone.
+func RemoveSchedulingGate(ctx context.Context, clientset kubernetes.Interface, namespace, podName, gate string) error {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	patch, err := buildSchedulingGatePatch(pod.Spec.SchedulingGates, gate)
+	if err != nil {
+		return err
+	}
+
+	_, err = clientset.CoreV1().Pods(namespace).Patch(
+		ctx, podName, "application/json-patch+json", patch, metav1.PatchOptions{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove scheduling gate: %w", err)
+	}
+	return nil
+}
+
+// buildSchedulingGatePatch builds a JSON patch removing the named gate.
+// The patch tests the gate name at its index first, so a concurrent change
+// to the gate list makes the patch fail instead of removing the wrong gate.
+func buildSchedulingGatePatch(gates []corev1.PodSchedulingGate, gate string) ([]byte, error) {
+	for i, g := range gates {
+		if g.Name != gate {
+			continue
+		}
+		path := fmt.Sprintf("/spec/schedulingGates/%d", i)
+		ops := []map[string]string{
+			{"op": "test", "path": path + "/name", "value": gate},
+			{"op": "remove", "path": path},
+		}
+		return json.Marshal(ops)
+	}
+	return nil, fmt.Errorf("scheduling gate %q not found on pod", gate)
+}
+
 func ScaleDeployment(ctx context.Context, clientset kubernetes.Interface, namespace, name string, replicas int32) error {
 	scale, err := clientset.AppsV1().Deployments(namespace).GetScale(ctx, name, metav1.GetOptions{})
 	if err != nil {
@@ -2239,6 +3025,24 @@ func extractRolloutReplicas(rolloutObj map[string]interface{}) (replicas int32,
 	return replicas, readyReplicas
 }
 
+// serviceToServiceInfo converts a Service, leaving Endpoints to the caller.
+func serviceToServiceInfo(svc *corev1.Service) ServiceInfo {
+	var ports []string
+	var numbers []int32
+	for _, p := range svc.Spec.Ports {
+		ports = append(ports, fmt.Sprintf("%d/%s", p.Port, p.Protocol))
+		numbers = append(numbers, p.Port)
+	}
+	return ServiceInfo{
+		Name:        svc.Name,
+		Type:        string(svc.Spec.Type),
+		ClusterIP:   svc.Spec.ClusterIP,
+		Ports:       strings.Join(ports, ", "),
+		NoSelector:  len(svc.Spec.Selector) == 0,
+		PortNumbers: numbers,
+	}
+}
+
 // countReadyEndpoints counts ready endpoints from EndpointSlices.
 func countReadyEndpoints(epSlices *discoveryv1.EndpointSliceList) int {
 	count := 0
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// pagedPodsReactor serves the given pages in order, handing out a continue
+// token with every page but the last.
+func pagedPodsReactor(pages ...[]string) (k8stesting.ReactionFunc, *int) {
+	calls := 0
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		page := pages[calls]
+		calls++
+		list := &corev1.PodList{}
+		if calls < len(pages) {
+			list.Continue = "token-" + page[len(page)-1]
+		}
+		for _, name := range page {
+			list.Items = append(list.Items, corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			})
+		}
+		return true, list, nil
+	}, &calls
+}
+
+func TestListPodsPage_ReturnsContinueToken(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	reactor, calls := pagedPodsReactor([]string{"pod-b", "pod-a"}, []string{"pod-c"})
+	clientset.PrependReactor("list", "pods", reactor)
+
+	ctx := context.Background()
+	pods, next, err := ListPodsPage(ctx, clientset, "default", 2, "")
+	if err != nil {
+		t.Fatalf("ListPodsPage() error = %v", err)
+	}
+	if len(pods) != 2 || pods[0].Name != "pod-a" || pods[1].Name != "pod-b" {
+		t.Errorf("first page = %v, want sorted [pod-a pod-b]", pods)
+	}
+	if next != "token-pod-a" {
+		t.Errorf("continue token = %q, want %q", next, "token-pod-a")
+	}
+
+	pods, next, err = ListPodsPage(ctx, clientset, "default", 2, next)
+	if err != nil {
+		t.Fatalf("ListPodsPage() error = %v", err)
+	}
+	if len(pods) != 1 || pods[0].Name != "pod-c" {
+		t.Errorf("second page = %v, want [pod-c]", pods)
+	}
+	if next != "" {
+		t.Errorf("continue token = %q, want empty on last page", next)
+	}
+	if *calls != 2 {
+		t.Errorf("list calls = %d, want 2", *calls)
+	}
+}
+
+func TestListAllPods_FollowsContinueTokens(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	reactor, calls := pagedPodsReactor([]string{"pod-a", "pod-b"}, []string{"pod-c", "pod-d"}, []string{"pod-e"})
+	clientset.PrependReactor("list", "pods", reactor)
+
+	pods, err := ListAllPods(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatalf("ListAllPods() error = %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("list calls = %d, want 3", *calls)
+	}
+	want := []string{"pod-a", "pod-b", "pod-c", "pod-d", "pod-e"}
+	if len(pods) != len(want) {
+		t.Fatalf("ListAllPods() returned %d pods, want %d", len(pods), len(want))
+	}
+	for i, name := range want {
+		if pods[i].Name != name {
+			t.Errorf("pods[%d] = %q, want %q", i, pods[i].Name, name)
+		}
+	}
+}
+
+func TestListWorkloads_FollowsContinueTokens(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	calls := 0
+	clientset.PrependReactor("list", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		name, cont := "api", "next"
+		if calls > 1 {
+			name, cont = "web", ""
+		}
+		return true, &appsv1.DeploymentList{
+			ListMeta: metav1.ListMeta{Continue: cont},
+			Items: []appsv1.Deployment{{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+				},
+			}},
+		}, nil
+	})
+
+	workloads, err := ListWorkloads(context.Background(), clientset, "default", ResourceDeployments)
+	if err != nil {
+		t.Fatalf("ListWorkloads() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("list calls = %d, want 2", calls)
+	}
+	if len(workloads) != 2 || workloads[0].Name != "api" || workloads[1].Name != "web" {
+		t.Errorf("ListWorkloads() = %v, want [api web]", workloads)
+	}
+}
+
+func TestListWorkloadsPage_UnknownType(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	if _, _, err := ListWorkloadsPage(context.Background(), clientset, "default", "bogus", DefaultPageSize, ""); err == nil {
+		t.Error("ListWorkloadsPage() expected error for unknown resource type")
+	}
+}
@@ -138,8 +138,79 @@ func TestDeletePod(t *testing.T) {
 	}
 }
 
+func TestBuildSchedulingGatePatch(t *testing.T) {
+	gates := []corev1.PodSchedulingGate{
+		{Name: "example.com/quota"},
+		{Name: "example.com/infra-approval"},
+	}
+
+	patch, err := buildSchedulingGatePatch(gates, "example.com/infra-approval")
+	if err != nil {
+		t.Fatalf("buildSchedulingGatePatch() error = %v", err)
+	}
+	want := `[{"op":"test","path":"/spec/schedulingGates/1/name","value":"example.com/infra-approval"},` +
+		`{"op":"remove","path":"/spec/schedulingGates/1"}]`
+	if string(patch) != want {
+		t.Errorf("patch = %s, want %s", patch, want)
+	}
+
+	if _, err := buildSchedulingGatePatch(gates, "example.com/missing"); err == nil {
+		t.Error("buildSchedulingGatePatch() should return error for unknown gate")
+	}
+	if _, err := buildSchedulingGatePatch(nil, "example.com/quota"); err == nil {
+		t.Error("buildSchedulingGatePatch() should return error for pod without gates")
+	}
+}
+
+func TestRemoveSchedulingGate(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "gated-pod", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main", Image: "nginx"}},
+				SchedulingGates: []corev1.PodSchedulingGate{
+					{Name: "example.com/quota"},
+					{Name: "example.com/infra-approval"},
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodPending},
+		},
+	)
+
+	ctx := context.Background()
+	err := RemoveSchedulingGate(ctx, clientset, "default", "gated-pod", "example.com/infra-approval")
+	if err != nil {
+		t.Fatalf("RemoveSchedulingGate() error = %v", err)
+	}
+
+	pod, err := GetPod(ctx, clientset, "default", "gated-pod")
+	if err != nil {
+		t.Fatalf("GetPod() error = %v", err)
+	}
+	if len(pod.SchedulingGates) != 1 || pod.SchedulingGates[0] != "example.com/quota" {
+		t.Errorf("SchedulingGates = %v, want [example.com/quota]", pod.SchedulingGates)
+	}
+
+	err = RemoveSchedulingGate(ctx, clientset, "default", "gated-pod", "example.com/infra-approval")
+	if err == nil {
+		t.Error("RemoveSchedulingGate() should return error when gate is already gone")
+	}
+}
+
+func TestRemoveSchedulingGate_PodNotFound(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	ctx := context.Background()
+	err := RemoveSchedulingGate(ctx, clientset, "default", "nonexistent", "example.com/quota")
+	if err == nil {
+		t.Error("RemoveSchedulingGate() should return error for nonexistent pod")
+	}
+}
+
 func TestGetPodStatus(t *testing.T) {
 	now := metav1.Now()
+	sidecarPolicy := corev1.ContainerRestartPolicyAlways
+	started := true
 
 	tests := []struct {
 		name     string
@@ -178,6 +249,80 @@ func TestGetPodStatus(t *testing.T) {
 			},
 			expected: "Running",
 		},
+		{
+			name: "init waiting in crash loop",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "wait-db"}, {Name: "migrate"}}},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					InitContainerStatuses: []corev1.ContainerStatus{
+						{Name: "wait-db", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"}}},
+						{Name: "migrate", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+					},
+					ContainerStatuses: []corev1.ContainerStatus{
+						{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+					},
+				},
+			},
+			expected: "Init:CrashLoopBackOff",
+		},
+		{
+			name: "init terminated with reason",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "migrate"}}},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					InitContainerStatuses: []corev1.ContainerStatus{
+						{Name: "migrate", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}}},
+					},
+				},
+			},
+			expected: "Init:Error",
+		},
+		{
+			name: "init terminated without reason",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "migrate"}}},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					InitContainerStatuses: []corev1.ContainerStatus{
+						{Name: "migrate", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 3}}},
+					},
+				},
+			},
+			expected: "Init:ExitCode:3",
+		},
+		{
+			name: "init in progress",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "wait-db"}, {Name: "migrate"}, {Name: "seed"}}},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					InitContainerStatuses: []corev1.ContainerStatus{
+						{Name: "wait-db", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"}}},
+						{Name: "migrate", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+						{Name: "seed", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+					},
+				},
+			},
+			expected: "Init:1/3",
+		},
+		{
+			name: "started sidecar counts as initialized",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "proxy", RestartPolicy: &sidecarPolicy}}},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					InitContainerStatuses: []corev1.ContainerStatus{
+						{Name: "proxy", Started: &started, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+					},
+					ContainerStatuses: []corev1.ContainerStatus{
+						{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+					},
+				},
+			},
+			expected: "Running",
+		},
 	}
 
 	for _, tt := range tests {
@@ -191,6 +191,7 @@ func TestAllResourceTypes(t *testing.T) {
 		ResourceDaemonSets:   true,
 		ResourceJobs:         true,
 		ResourceCronJobs:     true,
+		ResourceServices:     true,
 		ResourcePods:         true,
 	}
 
@@ -9,6 +9,7 @@ import (
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
@@ -56,12 +57,16 @@ func TestListWorkloads_StatefulSets(t *testing.T) {
 			ObjectMeta: metav1.ObjectMeta{
 				Name:              "database",
 				Namespace:         "default",
+				Labels:            map[string]string{"team": "storage"},
 				CreationTimestamp: metav1.Time{Time: time.Now()},
 			},
 			Spec: appsv1.StatefulSetSpec{
 				Selector: &metav1.LabelSelector{
 					MatchLabels: map[string]string{"app": "db"},
 				},
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "db", Image: "postgres:16"}}},
+				},
 			},
 			Status: appsv1.StatefulSetStatus{
 				Replicas:      3,
@@ -83,6 +88,9 @@ func TestListWorkloads_StatefulSets(t *testing.T) {
 	if workloads[0].Status != "Progressing" {
 		t.Errorf("Status = %q, want 'Progressing'", workloads[0].Status)
 	}
+	if w := workloads[0]; len(w.Images) != 1 || w.Images[0] != "postgres:16" || w.ObjectLabels["team"] != "storage" {
+		t.Errorf("Images = %v, ObjectLabels = %v, want postgres:16 and team=storage", w.Images, w.ObjectLabels)
+	}
 }
 
 func TestListWorkloads_DaemonSets(t *testing.T) {
@@ -196,6 +204,7 @@ func TestListWorkloads_Pods(t *testing.T) {
 				CreationTimestamp: metav1.Time{Time: time.Now()},
 			},
 			Spec: corev1.PodSpec{
+				NodeName:   "node-1",
 				Containers: []corev1.Container{{Name: "main"}},
 			},
 			Status: corev1.PodStatus{
@@ -220,6 +229,100 @@ func TestListWorkloads_Pods(t *testing.T) {
 	if workloads[0].RestartCount != 2 {
 		t.Errorf("RestartCount = %d, want 2", workloads[0].RestartCount)
 	}
+	if nodes := workloads[0].Nodes; len(nodes) != 1 || nodes[0] != "node-1" {
+		t.Errorf("Nodes = %v, want [node-1]", nodes)
+	}
+}
+
+func TestListWorkloads_Services(t *testing.T) {
+	ready := true
+	clientset := fake.NewSimpleClientset(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeClusterIP,
+				ClusterIP: "10.0.0.10",
+				Selector:  map[string]string{"app": "web"},
+				Ports:     []corev1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Type:         corev1.ServiceTypeExternalName,
+				ExternalName: "db.example.com",
+			},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web-abc",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+			},
+			Endpoints: []discoveryv1.Endpoint{
+				{Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+				{Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+			},
+		},
+	)
+
+	workloads, err := ListWorkloads(context.Background(), clientset, "default", ResourceServices)
+	if err != nil {
+		t.Fatalf("ListWorkloads() error = %v", err)
+	}
+	byName := make(map[string]WorkloadInfo)
+	for _, w := range workloads {
+		byName[w.Name] = w
+	}
+
+	web := byName["web"]
+	if web.Service == nil {
+		t.Fatal("web should have service details")
+	}
+	if web.Status != "ClusterIP" || web.Service.ClusterIP != "10.0.0.10" || web.Service.Ports != "80/TCP" {
+		t.Errorf("web = %s %s %s, want ClusterIP 10.0.0.10 80/TCP", web.Status, web.Service.ClusterIP, web.Service.Ports)
+	}
+	if web.Ready != "2 ready" || web.Service.Endpoints != 2 {
+		t.Errorf("web Ready = %q, want 2 ready endpoints", web.Ready)
+	}
+	if web.Labels["app"] != "web" {
+		t.Errorf("web Labels = %v, want the selector", web.Labels)
+	}
+
+	db := byName["db"]
+	if db.Service == nil || !db.Service.NoSelector || db.Ready != "no selector" {
+		t.Errorf("db = %+v, want it marked as having no selector", db)
+	}
+}
+
+func TestGetWorkloadPods_Service(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", Labels: map[string]string{"app": "web"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default", Labels: map[string]string{"app": "api"}}},
+	)
+	ctx := context.Background()
+
+	pods, err := GetWorkloadPods(ctx, clientset, WorkloadInfo{
+		Name:      "web",
+		Namespace: "default",
+		Type:      ResourceServices,
+		Labels:    map[string]string{"app": "web"},
+	})
+	if err != nil {
+		t.Fatalf("GetWorkloadPods() error = %v", err)
+	}
+	if len(pods) != 1 || pods[0].Name != "web-1" {
+		t.Errorf("GetWorkloadPods() = %v, want the pod the selector matches", pods)
+	}
+
+	// A service without a selector has no pods, not every pod
+	pods, err = GetWorkloadPods(ctx, clientset, WorkloadInfo{Name: "db", Namespace: "default", Type: ResourceServices})
+	if err != nil {
+		t.Fatalf("GetWorkloadPods() error = %v", err)
+	}
+	if len(pods) != 0 {
+		t.Errorf("GetWorkloadPods() = %d pods, want none for a service without a selector", len(pods))
+	}
 }
 
 func TestListWorkloads_UnknownType(t *testing.T) {
@@ -295,6 +398,34 @@ func TestGetStatefulSet(t *testing.T) {
 	}
 }
 
+func TestGetDesiredReplicas(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(5)},
+			Status:     appsv1.DeploymentStatus{Replicas: 2},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		},
+	)
+	ctx := context.Background()
+
+	// Spec, not the observed status count
+	if got, err := GetDesiredReplicas(ctx, clientset, "default", "web", ResourceDeployments); err != nil || got != 5 {
+		t.Errorf("GetDesiredReplicas(deployment) = %d, %v, want 5", got, err)
+	}
+	if got, err := GetDesiredReplicas(ctx, clientset, "default", "db", ResourceStatefulSets); err != nil || got != 1 {
+		t.Errorf("GetDesiredReplicas(statefulset without replicas) = %d, %v, want 1", got, err)
+	}
+	if _, err := GetDesiredReplicas(ctx, clientset, "default", "missing", ResourceDeployments); err == nil {
+		t.Error("GetDesiredReplicas() should fail for a missing deployment")
+	}
+	if _, err := GetDesiredReplicas(ctx, clientset, "default", "agent", ResourceDaemonSets); err == nil {
+		t.Error("GetDesiredReplicas() should fail for daemonsets")
+	}
+}
+
 func TestGetDaemonSet(t *testing.T) {
 	clientset := fake.NewSimpleClientset(
 		&appsv1.DaemonSet{
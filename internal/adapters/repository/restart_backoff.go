@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+)
+
+// The kubelet's crash loop back-off: the first restart is immediate, then
+// the delay doubles from crashLoopBackoffInitial up to crashLoopBackoffMax.
+// It starts over once an instance ran for crashLoopBackoffReset.
+const (
+	crashLoopBackoffInitial = 10 * time.Second
+	crashLoopBackoffMax     = 5 * time.Minute
+	crashLoopBackoffReset   = 10 * time.Minute
+)
+
+// RestartBackoff estimates how long the kubelet waits before restarting a
+// container that has restarted restartCount times, the last instance having
+// run for ranFor. It is an estimate: the kubelet keeps the real delay to
+// itself, and its clock, jitter and garbage collection of back-off entries
+// may make it differ.
+func RestartBackoff(restartCount int32, ranFor time.Duration) time.Duration {
+	if restartCount <= 1 || ranFor >= crashLoopBackoffReset {
+		return crashLoopBackoffInitial
+	}
+	delay := crashLoopBackoffInitial
+	for i := int32(1); i < restartCount && delay < crashLoopBackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, crashLoopBackoffMax)
+}
+
+// NextRestart estimates when the kubelet restarts a container in
+// CrashLoopBackOff: the back-off delay after its last instance finished.
+// Returns false when the container is not backing off or its last
+// termination time is unknown.
+func NextRestart(c ContainerInfo) (time.Time, bool) {
+	if c.State != "Waiting" || c.Reason != "CrashLoopBackOff" ||
+		c.LastTermination == nil || c.LastTermination.Finished.IsZero() {
+		return time.Time{}, false
+	}
+	var ranFor time.Duration
+	if !c.LastTermination.Started.IsZero() {
+		ranFor = c.LastTermination.Finished.Sub(c.LastTermination.Started)
+	}
+	return c.LastTermination.Finished.Add(RestartBackoff(c.RestartCount, ranFor)), true
+}
+
+// FormatRestartETA describes the time left until an estimated restart at
+// now, e.g. "next restart in ~2m 10s", or "restart due" once it has passed.
+func FormatRestartETA(at, now time.Time) string {
+	left := at.Sub(now).Round(time.Second)
+	if left <= 0 {
+		return "restart due"
+	}
+	if left < time.Minute {
+		return fmt.Sprintf("next restart in ~%ds", int(left.Seconds()))
+	}
+	return fmt.Sprintf("next restart in ~%dm %ds", int(left.Minutes()), int(left.Seconds())%60)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestartBackoff(t *testing.T) {
+	tests := []struct {
+		restarts int32
+		ranFor   time.Duration
+		want     time.Duration
+	}{
+		{0, 0, 10 * time.Second},
+		{1, 0, 10 * time.Second},
+		{2, 0, 20 * time.Second},
+		{3, 0, 40 * time.Second},
+		{4, 0, 80 * time.Second},
+		{5, 0, 160 * time.Second},
+		{6, 0, 5 * time.Minute},
+		{50, time.Second, 5 * time.Minute},
+		{7, 9 * time.Minute, 5 * time.Minute},
+		{7, 10 * time.Minute, 10 * time.Second}, // Ran long enough to start over
+	}
+	for _, tt := range tests {
+		if got := RestartBackoff(tt.restarts, tt.ranFor); got != tt.want {
+			t.Errorf("RestartBackoff(%d, %s) = %s, want %s", tt.restarts, tt.ranFor, got, tt.want)
+		}
+	}
+}
+
+func TestNextRestart(t *testing.T) {
+	finished := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	crashing := ContainerInfo{
+		Name:         "app",
+		State:        "Waiting",
+		Reason:       "CrashLoopBackOff",
+		RestartCount: 3,
+		LastTermination: &TerminationInfo{
+			Reason:   "Error",
+			Started:  finished.Add(-2 * time.Second),
+			Finished: finished,
+		},
+	}
+	at, ok := NextRestart(crashing)
+	if !ok || !at.Equal(finished.Add(40*time.Second)) {
+		t.Errorf("NextRestart() = %s, %v, want 40s after the last termination", at, ok)
+	}
+
+	running := crashing
+	running.State, running.Reason = "Running", ""
+	if _, ok := NextRestart(running); ok {
+		t.Error("NextRestart() of a running container should be false")
+	}
+	unknown := crashing
+	unknown.LastTermination = &TerminationInfo{Reason: "Error"}
+	if _, ok := NextRestart(unknown); ok {
+		t.Error("NextRestart() without a finish time should be false")
+	}
+}
+
+func TestFormatRestartETA(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		left time.Duration
+		want string
+	}{
+		{130 * time.Second, "next restart in ~2m 10s"},
+		{42 * time.Second, "next restart in ~42s"},
+		{0, "restart due"},
+		{-5 * time.Second, "restart due"},
+	}
+	for _, tt := range tests {
+		if got := FormatRestartETA(now.Add(tt.left), now); got != tt.want {
+			t.Errorf("FormatRestartETA(%s) = %q, want %q", tt.left, got, tt.want)
+		}
+	}
+}
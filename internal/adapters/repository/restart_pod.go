@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultRestartGracePeriod is the grace period RestartPod deletes a pod
+// with when its spec sets none, the same as the API server default.
+const DefaultRestartGracePeriod int64 = 30
+
+// ErrNoController is returned by RestartPod for a pod no controller owns,
+// since nothing would create a replacement after deleting it.
+var ErrNoController = errors.New("pod has no controller to recreate it")
+
+// Labels a controller may set differently on a replacement pod.
+var replacementIgnoredLabels = map[string]bool{
+	"pod-template-hash":        true,
+	"controller-revision-hash": true,
+}
+
+// RestartPod restarts a single pod by deleting it with its grace period,
+// then waits for its controller to create the replacement: a new pod with
+// the same controller and labels. Returns the replacement, or ctx's error
+// if none shows up in time. Unlike RestartWorkload, the other pods of the
+// workload are left alone. A watch that closes early is reopened until ctx
+// expires.
+func RestartPod(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (*PodInfo, error) {
+	pods := clientset.CoreV1().Pods(namespace)
+	pod, err := pods.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod: %w", err)
+	}
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil, ErrNoController
+	}
+
+	// Watch before deleting so the replacement can't be missed
+	watcher, err := pods.Watch(ctx, metav1.ListOptions{ResourceVersion: pod.ResourceVersion})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch pods: %w", err)
+	}
+	defer func() { watcher.Stop() }()
+
+	grace := DefaultRestartGracePeriod
+	if pod.Spec.TerminationGracePeriodSeconds != nil {
+		grace = *pod.Spec.TerminationGracePeriodSeconds
+	}
+	uid := pod.UID
+	err = pods.Delete(ctx, name, metav1.DeleteOptions{
+		GracePeriodSeconds: &grace,
+		Preconditions:      &metav1.Preconditions{UID: &uid},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete pod: %w", err)
+	}
+
+	for {
+		replacement, err := awaitReplacement(ctx, watcher, pod, owner)
+		if replacement != nil || err != nil {
+			return replacement, err
+		}
+
+		// The watch closed early, e.g. at the client timeout: catch up on
+		// what it missed, then watch again from there
+		list, err := pods.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		for i := range list.Items {
+			if isReplacementPod(pod, owner, &list.Items[i]) {
+				info := podToPodInfo(&list.Items[i])
+				return &info, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("no replacement for pod %s yet: %w", name, ctx.Err())
+		case <-time.After(restartRewatchDelay):
+		}
+		watcher.Stop()
+		watcher, err = pods.Watch(ctx, metav1.ListOptions{ResourceVersion: list.ResourceVersion})
+		if err != nil {
+			return nil, fmt.Errorf("failed to watch pods: %w", err)
+		}
+	}
+}
+
+// restartRewatchDelay is how long RestartPod waits before watching again
+// after a watch closed, so a watch that keeps closing isn't reopened in a
+// tight loop.
+const restartRewatchDelay = time.Second
+
+// awaitReplacement reads watcher until the replacement of old shows up.
+// Returns nil and no error when the watch closes first.
+func awaitReplacement(ctx context.Context, watcher watch.Interface, old *corev1.Pod, owner *metav1.OwnerReference) (*PodInfo, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("no replacement for pod %s yet: %w", old.Name, ctx.Err())
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil, nil
+			}
+			if event.Type != watch.Added {
+				continue
+			}
+			if candidate, ok := event.Object.(*corev1.Pod); ok && isReplacementPod(old, owner, candidate) {
+				info := podToPodInfo(candidate)
+				return &info, nil
+			}
+		}
+	}
+}
+
+// isReplacementPod reports whether candidate was created by the controller
+// of old to replace it: a different pod with the same controller and labels.
+func isReplacementPod(old *corev1.Pod, owner *metav1.OwnerReference, candidate *corev1.Pod) bool {
+	if candidate.UID == old.UID {
+		return false
+	}
+	candidateOwner := metav1.GetControllerOf(candidate)
+	if candidateOwner == nil || candidateOwner.UID != owner.UID {
+		return false
+	}
+	for k, v := range old.Labels {
+		if !replacementIgnoredLabels[k] && candidate.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
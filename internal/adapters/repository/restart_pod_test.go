@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func ownedPod(name string, uid types.UID, labels map[string]string) *corev1.Pod {
+	controller := true
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       uid,
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       "web-7d9f",
+				UID:        "rs-uid",
+				Controller: &controller,
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+// replaceOnDelete makes the fake clientset act like a ReplicaSet
+// controller: deleting a pod creates the given pods, in order.
+func replaceOnDelete(clientset *fake.Clientset, pods ...*corev1.Pod) {
+	clientset.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		for _, pod := range pods {
+			if err := clientset.Tracker().Create(corev1.SchemeGroupVersion.WithResource("pods"), pod, pod.Namespace); err != nil {
+				return true, nil, err
+			}
+		}
+		return false, nil, nil
+	})
+}
+
+func TestRestartPod_ReturnsReplacement(t *testing.T) {
+	labels := map[string]string{"app": "web", "pod-template-hash": "7d9f"}
+	clientset := fake.NewSimpleClientset(ownedPod("web-7d9f-abcde", "old-uid", labels))
+	// An unrelated pod of another workload shows up first
+	other := ownedPod("api-5c6b-zzzzz", "api-uid", map[string]string{"app": "api"})
+	other.OwnerReferences[0].UID = "api-rs-uid"
+	replaceOnDelete(clientset, other, ownedPod("web-7d9f-fghij", "new-uid", labels))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pod, err := RestartPod(ctx, clientset, "default", "web-7d9f-abcde")
+	if err != nil {
+		t.Fatalf("RestartPod() error = %v", err)
+	}
+	if pod.Name != "web-7d9f-fghij" {
+		t.Errorf("RestartPod() = %q, want the replacement web-7d9f-fghij", pod.Name)
+	}
+	if _, err := clientset.CoreV1().Pods("default").Get(ctx, "web-7d9f-abcde", metav1.GetOptions{}); err == nil {
+		t.Error("old pod should have been deleted")
+	}
+}
+
+func TestRestartPod_SameNameReplacement(t *testing.T) {
+	// StatefulSets recreate the pod under the same name
+	labels := map[string]string{"app": "db", "controller-revision-hash": "db-1"}
+	clientset := fake.NewSimpleClientset(ownedPod("db-0", "old-uid", labels))
+	clientset.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gvr := corev1.SchemeGroupVersion.WithResource("pods")
+		if err := clientset.Tracker().Delete(gvr, "default", "db-0"); err != nil {
+			return true, nil, err
+		}
+		replacement := ownedPod("db-0", "new-uid", map[string]string{"app": "db", "controller-revision-hash": "db-2"})
+		return true, nil, clientset.Tracker().Create(gvr, replacement, "default")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pod, err := RestartPod(ctx, clientset, "default", "db-0")
+	if err != nil {
+		t.Fatalf("RestartPod() error = %v", err)
+	}
+	if pod.Name != "db-0" {
+		t.Errorf("RestartPod() = %q, want db-0", pod.Name)
+	}
+}
+
+func TestRestartPod_WatchClosesEarly(t *testing.T) {
+	labels := map[string]string{"app": "web"}
+	clientset := fake.NewSimpleClientset(ownedPod("web-7d9f-abcde", "old-uid", labels))
+	replaceOnDelete(clientset, ownedPod("web-7d9f-fghij", "new-uid", labels))
+	// The first watch closes before the replacement is reported, as it
+	// does when the client times the request out
+	watches := 0
+	clientset.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		watches++
+		if watches > 1 {
+			return false, nil, nil
+		}
+		closed := watch.NewFake()
+		closed.Stop()
+		return true, closed, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pod, err := RestartPod(ctx, clientset, "default", "web-7d9f-abcde")
+	if err != nil {
+		t.Fatalf("RestartPod() error = %v", err)
+	}
+	if pod.Name != "web-7d9f-fghij" {
+		t.Errorf("RestartPod() = %q, want the replacement web-7d9f-fghij", pod.Name)
+	}
+}
+
+func TestRestartPod_NoController(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "standalone", Namespace: "default"},
+	})
+
+	_, err := RestartPod(context.Background(), clientset, "default", "standalone")
+	if !errors.Is(err, ErrNoController) {
+		t.Fatalf("RestartPod() error = %v, want ErrNoController", err)
+	}
+	if _, err := clientset.CoreV1().Pods("default").Get(context.Background(), "standalone", metav1.GetOptions{}); err != nil {
+		t.Error("a pod without a controller should not be deleted")
+	}
+}
+
+func TestRestartPod_TimesOutWithoutReplacement(t *testing.T) {
+	clientset := fake.NewSimpleClientset(ownedPod("web-7d9f-abcde", "old-uid", map[string]string{"app": "web"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := RestartPod(ctx, clientset, "default", "web-7d9f-abcde"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RestartPod() error = %v, want context.DeadlineExceeded", err)
+	}
+}
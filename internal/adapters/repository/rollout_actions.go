@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// RolloutStatus is where an Argo Rollout is in its update, as shown in
+// the Pod Details owner section.
+type RolloutStatus struct {
+	Phase            string // Healthy, Progressing, Paused, Degraded
+	Canary           bool   // Canary strategy; blue-green rollouts have no steps or weight
+	CurrentStepIndex int32  // Index of the current canary step; equals Steps once all are done
+	Steps            int32  // Number of canary steps
+	CanaryWeight     int32  // Percentage of traffic sent to the canary
+	Paused           bool   // Paused by the user or a pause step
+	PauseReason      string // Why the controller paused, e.g. CanaryPauseStep
+	Aborted          bool
+}
+
+// rolloutGVR is the resource of Argo Rollouts.
+var rolloutGVR = schema.GroupVersionResource{
+	Group:    "argoproj.io",
+	Version:  "v1alpha1",
+	Resource: "rollouts",
+}
+
+// PromoteRollout resumes a paused Argo Rollout, like kubectl argo rollouts
+// promote. A rollout paused by the user is unpaused and pause conditions
+// such as a canary pause step are cleared. A canary that is not paused
+// skips its current step instead.
+func PromoteRollout(ctx context.Context, dynamicClient dynamic.Interface, namespace, name string) error {
+	if dynamicClient == nil {
+		return fmt.Errorf("dynamic client not available")
+	}
+	rollouts := dynamicClient.Resource(rolloutGVR).Namespace(namespace)
+	rollout, err := rollouts.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get rollout: %w", err)
+	}
+
+	userPaused, _, _ := unstructured.NestedBool(rollout.Object, "spec", "paused")
+	pauseConditions, _, _ := unstructured.NestedSlice(rollout.Object, "status", "pauseConditions")
+	status := ExtractRolloutStatus(rollout.Object)
+
+	var statusPatch string
+	switch {
+	case len(pauseConditions) > 0:
+		statusPatch = `{"status":{"pauseConditions":null,"controllerPause":false}}`
+	case !userPaused && status.Canary && status.CurrentStepIndex < status.Steps:
+		statusPatch = fmt.Sprintf(`{"status":{"currentStepIndex":%d}}`, status.CurrentStepIndex+1)
+	case !userPaused:
+		return fmt.Errorf("rollout %s is not paused", name)
+	}
+
+	if userPaused {
+		_, err := rollouts.Patch(ctx, name, "application/merge-patch+json", []byte(`{"spec":{"paused":false}}`), metav1.PatchOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to promote rollout: %w", err)
+		}
+	}
+	if statusPatch != "" {
+		if err := patchRolloutStatus(ctx, dynamicClient, namespace, name, statusPatch); err != nil {
+			return fmt.Errorf("failed to promote rollout: %w", err)
+		}
+	}
+	return nil
+}
+
+// AbortRollout aborts an Argo Rollout's update: traffic goes back to the
+// stable version and the canary is scaled down.
+func AbortRollout(ctx context.Context, dynamicClient dynamic.Interface, namespace, name string) error {
+	if dynamicClient == nil {
+		return fmt.Errorf("dynamic client not available")
+	}
+	if err := patchRolloutStatus(ctx, dynamicClient, namespace, name, `{"status":{"abort":true}}`); err != nil {
+		return fmt.Errorf("failed to abort rollout: %w", err)
+	}
+	return nil
+}
+
+// RetryRollout restarts the update of an aborted Argo Rollout from its
+// first step.
+func RetryRollout(ctx context.Context, dynamicClient dynamic.Interface, namespace, name string) error {
+	if dynamicClient == nil {
+		return fmt.Errorf("dynamic client not available")
+	}
+	if err := patchRolloutStatus(ctx, dynamicClient, namespace, name, `{"status":{"abort":false}}`); err != nil {
+		return fmt.Errorf("failed to retry rollout: %w", err)
+	}
+	return nil
+}
+
+// patchRolloutStatus merge-patches the status subresource of a rollout,
+// which the Argo Rollouts controller reads its user requests from.
+func patchRolloutStatus(ctx context.Context, dynamicClient dynamic.Interface, namespace, name, patch string) error {
+	_, err := dynamicClient.Resource(rolloutGVR).Namespace(namespace).Patch(
+		ctx, name, "application/merge-patch+json", []byte(patch), metav1.PatchOptions{}, "status",
+	)
+	return err
+}
+
+// ExtractRolloutStatus reads the step, weight and pause state of an Argo
+// Rollout object.
+func ExtractRolloutStatus(rolloutObj map[string]interface{}) *RolloutStatus {
+	status := &RolloutStatus{}
+	status.Phase, _, _ = unstructured.NestedString(rolloutObj, "status", "phase")
+	status.Aborted, _, _ = unstructured.NestedBool(rolloutObj, "status", "abort")
+
+	userPaused, _, _ := unstructured.NestedBool(rolloutObj, "spec", "paused")
+	var reasons []string
+	pauseConditions, _, _ := unstructured.NestedSlice(rolloutObj, "status", "pauseConditions")
+	for _, c := range pauseConditions {
+		if cond, ok := c.(map[string]interface{}); ok {
+			if reason, ok := cond["reason"].(string); ok {
+				reasons = append(reasons, reason)
+			}
+		}
+	}
+	status.Paused = userPaused || len(reasons) > 0
+	status.PauseReason = strings.Join(reasons, ", ")
+	if userPaused && status.PauseReason == "" {
+		status.PauseReason = "paused by user"
+	}
+
+	canary, ok, _ := unstructured.NestedMap(rolloutObj, "spec", "strategy", "canary")
+	if !ok {
+		return status
+	}
+	status.Canary = true
+	steps, _, _ := unstructured.NestedSlice(canary, "steps")
+	status.Steps = int32(len(steps))
+	status.CurrentStepIndex = status.Steps
+	if index, ok := nestedInt32(rolloutObj, "status", "currentStepIndex"); ok && index >= 0 {
+		status.CurrentStepIndex = index
+	}
+
+	// With traffic routing the controller reports the actual weight;
+	// otherwise it is the last setWeight step reached
+	if weight, ok := nestedInt32(rolloutObj, "status", "canary", "weights", "canary", "weight"); ok {
+		status.CanaryWeight = weight
+		return status
+	}
+	if status.CurrentStepIndex >= status.Steps {
+		status.CanaryWeight = 100
+		return status
+	}
+	for _, s := range steps[:status.CurrentStepIndex] {
+		if step, ok := s.(map[string]interface{}); ok {
+			if weight, ok := nestedInt32(step, "setWeight"); ok {
+				status.CanaryWeight = weight
+			}
+		}
+	}
+	return status
+}
+
+// nestedInt32 reads a number of an unstructured object, which JSON
+// decoding may have made an int64 or a float64.
+func nestedInt32(obj map[string]interface{}, fields ...string) (int32, bool) {
+	val, ok, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	if !ok {
+		return 0, false
+	}
+	switch n := val.(type) {
+	case int64:
+		return int32(n), true
+	case float64:
+		return int32(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// canaryRollout returns a rollout with a canary strategy of four steps:
+// setWeight 20, pause, setWeight 50, pause.
+func canaryRollout(spec, status map[string]interface{}) *unstructured.Unstructured {
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+	spec["replicas"] = int64(3)
+	spec["strategy"] = map[string]interface{}{
+		"canary": map[string]interface{}{
+			"steps": []interface{}{
+				map[string]interface{}{"setWeight": int64(20)},
+				map[string]interface{}{"pause": map[string]interface{}{}},
+				map[string]interface{}{"setWeight": int64(50)},
+				map[string]interface{}{"pause": map[string]interface{}{"duration": "10m"}},
+			},
+		},
+	}
+	obj := map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+		"spec":       spec,
+	}
+	if status != nil {
+		obj["status"] = status
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func rolloutClient(rollout *unstructured.Unstructured) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{rolloutGVR: "RolloutList"},
+		rollout,
+	)
+}
+
+func getRollout(t *testing.T, client *dynamicfake.FakeDynamicClient) map[string]interface{} {
+	t.Helper()
+	rollout, err := client.Resource(rolloutGVR).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rollout.Object
+}
+
+func TestPromoteRollout_ClearsPauseConditions(t *testing.T) {
+	client := rolloutClient(canaryRollout(nil, map[string]interface{}{
+		"currentStepIndex": int64(1),
+		"controllerPause":  true,
+		"pauseConditions": []interface{}{
+			map[string]interface{}{"reason": "CanaryPauseStep", "startTime": "2024-01-01T00:00:00Z"},
+		},
+	}))
+
+	if err := PromoteRollout(context.Background(), client, "default", "web"); err != nil {
+		t.Fatalf("PromoteRollout() error = %v", err)
+	}
+	obj := getRollout(t, client)
+	if _, found, _ := unstructured.NestedSlice(obj, "status", "pauseConditions"); found {
+		t.Error("pauseConditions should be cleared")
+	}
+	if paused, _, _ := unstructured.NestedBool(obj, "status", "controllerPause"); paused {
+		t.Error("controllerPause should be false")
+	}
+	if index, _ := nestedInt32(obj, "status", "currentStepIndex"); index != 1 {
+		t.Errorf("currentStepIndex = %d, want 1; the controller advances past the pause", index)
+	}
+}
+
+func TestPromoteRollout_UnpausesSpec(t *testing.T) {
+	client := rolloutClient(canaryRollout(map[string]interface{}{"paused": true}, nil))
+
+	if err := PromoteRollout(context.Background(), client, "default", "web"); err != nil {
+		t.Fatalf("PromoteRollout() error = %v", err)
+	}
+	if paused, found, _ := unstructured.NestedBool(getRollout(t, client), "spec", "paused"); !found || paused {
+		t.Errorf("spec.paused = %v (found %v), want false", paused, found)
+	}
+}
+
+func TestPromoteRollout_SkipsCurrentStep(t *testing.T) {
+	client := rolloutClient(canaryRollout(nil, map[string]interface{}{"currentStepIndex": int64(2)}))
+
+	if err := PromoteRollout(context.Background(), client, "default", "web"); err != nil {
+		t.Fatalf("PromoteRollout() error = %v", err)
+	}
+	if index, _ := nestedInt32(getRollout(t, client), "status", "currentStepIndex"); index != 3 {
+		t.Errorf("currentStepIndex = %d, want 3", index)
+	}
+}
+
+func TestPromoteRollout_NotPaused(t *testing.T) {
+	client := rolloutClient(canaryRollout(nil, map[string]interface{}{"currentStepIndex": int64(4)}))
+
+	if err := PromoteRollout(context.Background(), client, "default", "web"); err == nil {
+		t.Error("PromoteRollout() on a finished rollout should fail")
+	}
+	if err := PromoteRollout(context.Background(), client, "default", "missing"); err == nil {
+		t.Error("PromoteRollout() on a missing rollout should fail")
+	}
+}
+
+func TestAbortAndRetryRollout(t *testing.T) {
+	ctx := context.Background()
+	client := rolloutClient(canaryRollout(nil, map[string]interface{}{"currentStepIndex": int64(1)}))
+
+	if err := AbortRollout(ctx, client, "default", "web"); err != nil {
+		t.Fatalf("AbortRollout() error = %v", err)
+	}
+	if abort, _, _ := unstructured.NestedBool(getRollout(t, client), "status", "abort"); !abort {
+		t.Error("status.abort should be true after AbortRollout()")
+	}
+
+	if err := RetryRollout(ctx, client, "default", "web"); err != nil {
+		t.Fatalf("RetryRollout() error = %v", err)
+	}
+	abort, found, _ := unstructured.NestedBool(getRollout(t, client), "status", "abort")
+	if !found || abort {
+		t.Errorf("status.abort = %v (found %v), want false after RetryRollout()", abort, found)
+	}
+}
+
+func TestRolloutActions_NilClient(t *testing.T) {
+	ctx := context.Background()
+	if err := PromoteRollout(ctx, nil, "default", "web"); err == nil {
+		t.Error("PromoteRollout() without a dynamic client should fail")
+	}
+	if err := AbortRollout(ctx, nil, "default", "web"); err == nil {
+		t.Error("AbortRollout() without a dynamic client should fail")
+	}
+	if err := RetryRollout(ctx, nil, "default", "web"); err == nil {
+		t.Error("RetryRollout() without a dynamic client should fail")
+	}
+}
+
+func TestExtractRolloutStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		rollout *unstructured.Unstructured
+		want    RolloutStatus
+	}{
+		{
+			name: "paused at a canary step",
+			rollout: canaryRollout(nil, map[string]interface{}{
+				"phase":            "Paused",
+				"currentStepIndex": int64(1),
+				"pauseConditions":  []interface{}{map[string]interface{}{"reason": "CanaryPauseStep"}},
+			}),
+			want: RolloutStatus{Phase: "Paused", Canary: true, CurrentStepIndex: 1, Steps: 4, CanaryWeight: 20, Paused: true, PauseReason: "CanaryPauseStep"},
+		},
+		{
+			name: "traffic routed weight",
+			rollout: canaryRollout(nil, map[string]interface{}{
+				"currentStepIndex": float64(3),
+				"canary": map[string]interface{}{
+					"weights": map[string]interface{}{"canary": map[string]interface{}{"weight": int64(45)}},
+				},
+			}),
+			want: RolloutStatus{Canary: true, CurrentStepIndex: 3, Steps: 4, CanaryWeight: 45},
+		},
+		{
+			name:    "completed",
+			rollout: canaryRollout(nil, map[string]interface{}{"phase": "Healthy", "currentStepIndex": int64(4)}),
+			want:    RolloutStatus{Phase: "Healthy", Canary: true, CurrentStepIndex: 4, Steps: 4, CanaryWeight: 100},
+		},
+		{
+			name:    "aborted and paused by user",
+			rollout: canaryRollout(map[string]interface{}{"paused": true}, map[string]interface{}{"abort": true, "currentStepIndex": int64(0)}),
+			want:    RolloutStatus{Canary: true, Steps: 4, Paused: true, PauseReason: "paused by user", Aborted: true},
+		},
+		{
+			name: "blue-green",
+			rollout: &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"strategy": map[string]interface{}{"blueGreen": map[string]interface{}{"activeService": "web"}},
+				},
+				"status": map[string]interface{}{"phase": "Healthy"},
+			}},
+			want: RolloutStatus{Phase: "Healthy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractRolloutStatus(tt.rollout.Object)
+			if *got != tt.want {
+				t.Errorf("ExtractRolloutStatus() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// ServiceProbeTimeout bounds each DNS lookup and port connect of a service
+// check, so a black-holed port fails instead of hanging.
+const ServiceProbeTimeout = 3 * time.Second
+
+// NetshootImage is the image of the debug container AddNetshootContainer
+// injects. It has getent, nslookup and nc, which minimal images lack.
+const NetshootImage = "nicolaka/netshoot:latest"
+
+// NetshootContainerPrefix starts the name of the debug containers
+// AddNetshootContainer injects.
+const NetshootContainerPrefix = "k1s-netshoot-"
+
+// netshootLifetime is how long an injected debug container keeps running.
+// Ephemeral containers can't be removed, so it exits on its own.
+const netshootLifetime = time.Hour
+
+// ErrNetshootNotRunning is returned by AddNetshootContainer when the debug
+// container stops or can't be started.
+var ErrNetshootNotRunning = errors.New("netshoot container is not running")
+
+// ServiceCheck is the result of checking a Service from inside a pod: the
+// addresses its name resolves to and whether each port accepts connections.
+type ServiceCheck struct {
+	Service   string
+	Host      string // Name looked up and connected to, e.g. web.default.svc
+	Pod       string
+	Container string // Container the check ran in
+	Addresses []string
+	DNSError  string // Why the name did not resolve; empty when it did
+	Ports     []ServicePortCheck
+}
+
+// ServicePortCheck is the result of connecting to one port of a Service.
+type ServicePortCheck struct {
+	Port      int32
+	Name      string
+	Protocol  string
+	Reachable bool
+	Latency   time.Duration // Time to connect; zero when unknown
+	Error     string        // Why the connect failed, or why it was skipped
+	Skipped   bool          // Not tested, as for UDP ports
+}
+
+// Reachable reports whether the name resolved and every tested port
+// accepted a connection.
+func (c ServiceCheck) Reachable() bool {
+	if c.DNSError != "" {
+		return false
+	}
+	for _, p := range c.Ports {
+		if !p.Skipped && !p.Reachable {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckServiceConnectivity checks a Service from a container of a pod, the
+// way the pod's own clients would reach it: it resolves the Service name
+// with getent or nslookup, then opens a TCP connection to each port with
+// nc. Each probe times out after ServiceProbeTimeout. Failing probes are
+// reported in the result; an error is only returned when the check could
+// not run at all, e.g. ErrNoShell for images without sh.
+func CheckServiceConnectivity(ctx context.Context, clientset kubernetes.Interface, config *rest.Config, namespace, pod, container, service string) (*ServiceCheck, error) {
+	svc, err := clientset.CoreV1().Services(namespace).Get(ctx, service, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	check, err := checkService(ctx, podExecRunner(config, namespace, pod, container), svc)
+	if err != nil {
+		return nil, err
+	}
+	check.Pod = pod
+	check.Container = container
+	return check, nil
+}
+
+// checkService runs the probes for svc in one exec and parses their output.
+func checkService(ctx context.Context, run execRunner, svc *corev1.Service) (*ServiceCheck, error) {
+	check := &ServiceCheck{
+		Service: svc.Name,
+		Host:    svc.Name + "." + svc.Namespace + ".svc",
+	}
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		check.Host = svc.Spec.ExternalName
+	}
+
+	var tcpPorts []string
+	for _, p := range svc.Spec.Ports {
+		port := ServicePortCheck{Port: p.Port, Name: p.Name, Protocol: string(p.Protocol)}
+		if port.Protocol == "" {
+			port.Protocol = string(corev1.ProtocolTCP)
+		}
+		if port.Protocol != string(corev1.ProtocolTCP) {
+			port.Skipped = true
+			port.Error = port.Protocol + " not tested"
+		} else {
+			tcpPorts = append(tcpPorts, strconv.Itoa(int(p.Port)))
+		}
+		check.Ports = append(check.Ports, port)
+	}
+
+	// One probe for the lookup and one per port, plus time to start the exec
+	ctx, cancel := context.WithTimeout(ctx, ServiceProbeTimeout*time.Duration(len(tcpPorts)+1)+5*time.Second)
+	defer cancel()
+
+	seconds := strconv.Itoa(int(ServiceProbeTimeout / time.Second))
+	command := append([]string{"sh", "-c", serviceCheckScript, "k1s", check.Host, seconds}, tcpPorts...)
+	var stdout, stderr bytes.Buffer
+	err := run(ctx, command, &stdout, &stderr)
+	if err != nil && isMissingShell(err) {
+		return nil, fmt.Errorf("%w (the check needs sh)", ErrNoShell)
+	}
+	if err != nil && stdout.Len() == 0 {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("service check failed: %s", msg)
+		}
+		return nil, fmt.Errorf("service check failed: %w", err)
+	}
+
+	parseServiceCheck(check, stdout.String())
+	// Probes cut off by the timeout have no result of their own
+	for i := range check.Ports {
+		p := &check.Ports[i]
+		if !p.Skipped && !p.Reachable && p.Error == "" {
+			p.Error = "no answer"
+			if err != nil {
+				p.Error = "no answer: " + err.Error()
+			}
+		}
+	}
+	return check, nil
+}
+
+// serviceCheckScript resolves $1 and connects to each port after $2, each
+// probe with a timeout of $2 seconds. Results follow "@" marker lines:
+// "@dns" before the lookup output and "@lookup STATUS" after it, then for
+// each port "@port N" and "@done STATUS START END" before the connect
+// output, START and END in nanoseconds when date supports them. "@notool"
+// replaces a result the image has no tool for. A STATUS of 124 is timeout's.
+const serviceCheckScript = `h=$1; t=$2; shift 2
+to=""
+command -v timeout >/dev/null 2>&1 && to="timeout $t"
+echo "@dns"
+if command -v getent >/dev/null 2>&1; then
+  $to getent hosts "$h" 2>&1
+  echo "@lookup $?"
+elif command -v nslookup >/dev/null 2>&1; then
+  $to nslookup "$h" 2>&1
+  echo "@lookup $?"
+else
+  echo "@notool"
+fi
+for p in "$@"; do
+  echo "@port $p"
+  s=$(date +%s%N 2>/dev/null)
+  if command -v nc >/dev/null 2>&1; then
+    out=$(nc -z -w "$t" "$h" "$p" 2>&1)
+  elif command -v bash >/dev/null 2>&1 && command -v timeout >/dev/null 2>&1; then
+    out=$(timeout "$t" bash -c "exec 3<>/dev/tcp/$h/$p" 2>&1)
+  else
+    echo "@notool"
+    continue
+  fi
+  r=$?
+  echo "@done $r $s $(date +%s%N 2>/dev/null)"
+  [ -n "$out" ] && echo "$out"
+done
+exit 0
+`
+
+// parseServiceCheck fills check from the output of serviceCheckScript.
+func parseServiceCheck(check *ServiceCheck, output string) {
+	var section, lookupStatus string
+	var port *ServicePortCheck
+	var dnsLines, portLines []string
+	finishPort := func() {
+		if port != nil && !port.Reachable && port.Error == "" && len(portLines) > 0 {
+			port.Error = strings.Join(portLines, "; ")
+		}
+		portLines = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "@dns":
+			section = "dns"
+			continue
+		case "@port":
+			finishPort()
+			section = "port"
+			port = nil
+			if len(fields) > 1 {
+				port = check.portByNumber(fields[1])
+			}
+			continue
+		case "@notool":
+			if section == "dns" {
+				check.DNSError = "no getent or nslookup in the container"
+			} else if port != nil {
+				port.Error = "no nc or bash in the container"
+			}
+			continue
+		case "@lookup":
+			if len(fields) > 1 {
+				lookupStatus = fields[1]
+			}
+			continue
+		case "@done":
+			if port != nil && len(fields) > 1 {
+				port.Reachable = fields[1] == "0"
+				if port.Reachable && len(fields) > 3 {
+					port.Latency = nanosBetween(fields[2], fields[3])
+				} else if fields[1] == "124" {
+					port.Error = fmt.Sprintf("timed out after %s", ServiceProbeTimeout)
+				} else if !port.Reachable {
+					port.Error = "connection failed"
+				}
+			}
+			continue
+		}
+
+		switch section {
+		case "dns":
+			dnsLines = append(dnsLines, line)
+		case "port":
+			if port != nil && !port.Reachable {
+				// The tool's own message is more telling than the exit status
+				if port.Error == "connection failed" {
+					port.Error = ""
+				}
+				portLines = append(portLines, line)
+			}
+		}
+	}
+	finishPort()
+
+	if check.DNSError == "" {
+		check.Addresses = parseLookupOutput(dnsLines)
+		if len(check.Addresses) == 0 {
+			check.DNSError = lookupError(lookupStatus, dnsLines)
+		}
+	}
+}
+
+// portByNumber returns the port of the check with the given number.
+func (c *ServiceCheck) portByNumber(s string) *ServicePortCheck {
+	for i := range c.Ports {
+		if strconv.Itoa(int(c.Ports[i].Port)) == s {
+			return &c.Ports[i]
+		}
+	}
+	return nil
+}
+
+// nanosBetween returns the time between two nanosecond timestamps printed
+// by date +%s%N, zero when date printed something else.
+func nanosBetween(start, end string) time.Duration {
+	s, err1 := strconv.ParseInt(start, 10, 64)
+	e, err2 := strconv.ParseInt(end, 10, 64)
+	if err1 != nil || err2 != nil || e < s {
+		return 0
+	}
+	return time.Duration(e - s)
+}
+
+// parseLookupOutput returns the addresses in the output of getent hosts or
+// nslookup. The DNS server nslookup prints first is not one of them: only
+// addresses after the "Name:" line count.
+func parseLookupOutput(lines []string) []string {
+	var addresses []string
+	seen := make(map[string]bool)
+	add := func(s string) {
+		if ip := net.ParseIP(s); ip != nil && !seen[ip.String()] {
+			seen[ip.String()] = true
+			addresses = append(addresses, ip.String())
+		}
+	}
+
+	answer := false
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		switch {
+		case strings.HasPrefix(line, "Name:"):
+			answer = true
+		case strings.HasPrefix(line, "Address"):
+			// "Address: 10.0.0.1", or busybox's "Address 1: 10.0.0.1 name"
+			if _, rest, ok := strings.Cut(line, ":"); answer && ok {
+				if f := strings.Fields(rest); len(f) > 0 {
+					add(strings.Split(f[0], "#")[0])
+				}
+			}
+		case len(fields) > 1 && !strings.HasPrefix(line, "Server"):
+			// getent hosts: "10.0.0.1  web.default.svc.cluster.local"
+			add(fields[0])
+		}
+	}
+	return addresses
+}
+
+// lookupError returns why a lookup found no address, from its exit status
+// and output.
+func lookupError(status string, lines []string) string {
+	if status == "124" {
+		return fmt.Sprintf("lookup timed out after %s", ServiceProbeTimeout)
+	}
+	for _, line := range lines {
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "can't find") || strings.Contains(lower, "nxdomain") ||
+			strings.Contains(lower, "can't resolve") || strings.Contains(lower, "timed out") {
+			return line
+		}
+	}
+	return "name not found"
+}
+
+// AddNetshootContainer injects an ephemeral debug container running
+// NetshootImage into a pod and waits for it to start, returning its name.
+// It shares the pod's network, so checks run from it see what the pod's
+// own containers would. A netshoot container injected earlier is reused
+// while it still runs, since ephemeral containers can't be removed.
+func AddNetshootContainer(ctx context.Context, clientset kubernetes.Interface, namespace, pod string) (string, error) {
+	pods := clientset.CoreV1().Pods(namespace)
+	p, err := pods.Get(ctx, pod, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod: %w", err)
+	}
+	for _, status := range p.Status.EphemeralContainerStatuses {
+		if strings.HasPrefix(status.Name, NetshootContainerPrefix) && status.State.Running != nil {
+			return status.Name, nil
+		}
+	}
+
+	name := fmt.Sprintf("%s%d", NetshootContainerPrefix, time.Now().Unix())
+	p.Spec.EphemeralContainers = append(p.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:    name,
+			Image:   NetshootImage,
+			Command: []string{"sleep", strconv.Itoa(int(netshootLifetime / time.Second))},
+		},
+	})
+	if _, err := pods.UpdateEphemeralContainers(ctx, pod, p, metav1.UpdateOptions{}); err != nil {
+		return "", fmt.Errorf("failed to add netshoot container: %w", err)
+	}
+
+	if err := waitForEphemeralContainer(ctx, pods, pod, name, ErrNetshootNotRunning); err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func webService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			ClusterIP: "10.96.4.20",
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP},
+				{Name: "https", Port: 443, Protocol: corev1.ProtocolTCP},
+				{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+			},
+		},
+	}
+}
+
+// scriptRunner answers the service check script with output, recording
+// the arguments it was run with.
+func scriptRunner(output string, err error, args *[]string) execRunner {
+	return func(ctx context.Context, command []string, stdout, stderr io.Writer) error {
+		if args != nil && len(command) > 3 {
+			*args = command[3:]
+		}
+		io.WriteString(stdout, output)
+		return err
+	}
+}
+
+func TestCheckService(t *testing.T) {
+	output := strings.Join([]string{
+		"@dns",
+		"10.96.4.20      web.shop.svc.cluster.local",
+		"@lookup 0",
+		"@port 80",
+		"@done 0 1700000000000000000 1700000000002500000",
+		"@port 443",
+		"@done 1 1700000000000000000 1700000000001000000",
+		"nc: connect to web.shop.svc port 443 (tcp) failed: Connection refused",
+	}, "\n")
+	var args []string
+	check, err := checkService(context.Background(), scriptRunner(output, nil, &args), webService())
+	if err != nil {
+		t.Fatalf("checkService() error = %v", err)
+	}
+
+	if want := []string{"k1s", "web.shop.svc", "3", "80", "443"}; strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("script args = %v, want %v (UDP ports are not probed)", args, want)
+	}
+	if check.Host != "web.shop.svc" || len(check.Addresses) != 1 || check.Addresses[0] != "10.96.4.20" || check.DNSError != "" {
+		t.Errorf("lookup = %q %v %q, want web.shop.svc resolving to 10.96.4.20", check.Host, check.Addresses, check.DNSError)
+	}
+	if len(check.Ports) != 3 {
+		t.Fatalf("Ports = %+v, want 3 ports", check.Ports)
+	}
+	if http := check.Ports[0]; !http.Reachable || http.Latency != 2500*time.Microsecond {
+		t.Errorf("port 80 = %+v, want reachable in 2.5ms", http)
+	}
+	if https := check.Ports[1]; https.Reachable || !strings.Contains(https.Error, "Connection refused") {
+		t.Errorf("port 443 = %+v, want the connect error", https)
+	}
+	if udp := check.Ports[2]; !udp.Skipped || udp.Error != "UDP not tested" {
+		t.Errorf("port 53 = %+v, want skipped", udp)
+	}
+	if check.Reachable() {
+		t.Error("Reachable() should be false with a refused port")
+	}
+}
+
+func TestCheckService_Failures(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		runErr   error
+		wantDNS  string
+		wantPort string
+	}{
+		{
+			name: "nslookup NXDOMAIN and timed out connect",
+			output: "@dns\nServer:\t\t10.96.0.10\nAddress:\t10.96.0.10#53\n\n** server can't find web.shop.svc: NXDOMAIN\n@lookup 1\n" +
+				"@port 80\n@done 124 1 2\n@port 443\n@done 1 N N\n",
+			wantDNS:  "** server can't find web.shop.svc: NXDOMAIN",
+			wantPort: "timed out after 3s",
+		},
+		{
+			name:     "lookup timed out",
+			output:   "@dns\n@lookup 124\n@port 80\n@done 0 %N %N\n",
+			wantDNS:  "lookup timed out after 3s",
+			wantPort: "",
+		},
+		{
+			name:     "no tools",
+			output:   "@dns\n@notool\n@port 80\n@notool\n",
+			wantDNS:  "no getent or nslookup in the container",
+			wantPort: "no nc or bash in the container",
+		},
+		{
+			name:     "cut off by the timeout",
+			output:   "@dns\n10.96.4.20 web.shop.svc.cluster.local\n@lookup 0\n@port 80\n",
+			runErr:   context.DeadlineExceeded,
+			wantPort: "no answer: context deadline exceeded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := checkService(context.Background(), scriptRunner(tt.output, tt.runErr, nil), webService())
+			if err != nil {
+				t.Fatalf("checkService() error = %v", err)
+			}
+			if check.DNSError != tt.wantDNS {
+				t.Errorf("DNSError = %q, want %q", check.DNSError, tt.wantDNS)
+			}
+			if got := check.Ports[0].Error; got != tt.wantPort {
+				t.Errorf("port 80 error = %q, want %q", got, tt.wantPort)
+			}
+			if check.Ports[0].Reachable && check.Ports[0].Latency != 0 {
+				t.Errorf("Latency = %v, want 0 when date has no nanoseconds", check.Ports[0].Latency)
+			}
+		})
+	}
+}
+
+func TestCheckService_NoShell(t *testing.T) {
+	run := func(ctx context.Context, command []string, stdout, stderr io.Writer) error {
+		return errors.New(`exec: "sh": executable file not found in $PATH`)
+	}
+	if _, err := checkService(context.Background(), run, webService()); !errors.Is(err, ErrNoShell) {
+		t.Errorf("checkService() error = %v, want ErrNoShell", err)
+	}
+}
+
+func TestParseLookupOutput(t *testing.T) {
+	busybox := []string{
+		"Server:    10.96.0.10",
+		"Address 1: 10.96.0.10 kube-dns.kube-system.svc.cluster.local",
+		"Name:      web.shop.svc",
+		"Address 1: 10.244.1.7 10-244-1-7.web.shop.svc.cluster.local",
+		"Address 2: 10.244.2.9 10-244-2-9.web.shop.svc.cluster.local",
+	}
+	got := parseLookupOutput(busybox)
+	if strings.Join(got, ",") != "10.244.1.7,10.244.2.9" {
+		t.Errorf("parseLookupOutput(busybox) = %v, want the two pod IPs without the DNS server", got)
+	}
+
+	getent := []string{"fd00::1f  web.shop.svc.cluster.local", "fd00::1f  web.shop.svc.cluster.local"}
+	if got := parseLookupOutput(getent); len(got) != 1 || got[0] != "fd00::1f" {
+		t.Errorf("parseLookupOutput(getent) = %v, want one IPv6 address", got)
+	}
+}
+
+// runEphemeralOnUpdate makes the fake clientset start every ephemeral
+// container added to a pod.
+func runEphemeralOnUpdate(clientset *fake.Clientset) {
+	clientset.PrependReactor("update", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pod := action.(k8stesting.UpdateAction).GetObject().(*corev1.Pod)
+		pod.Status.EphemeralContainerStatuses = nil
+		for _, c := range pod.Spec.EphemeralContainers {
+			pod.Status.EphemeralContainerStatuses = append(pod.Status.EphemeralContainerStatuses, corev1.ContainerStatus{
+				Name:  c.Name,
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			})
+		}
+		return false, nil, nil
+	})
+}
+
+func TestAddNetshootContainer(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"}}
+	clientset := fake.NewSimpleClientset(pod)
+	runEphemeralOnUpdate(clientset)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	name, err := AddNetshootContainer(ctx, clientset, "shop", "web-1")
+	if err != nil {
+		t.Fatalf("AddNetshootContainer() error = %v", err)
+	}
+	if !strings.HasPrefix(name, NetshootContainerPrefix) {
+		t.Errorf("name = %q, want the %s prefix", name, NetshootContainerPrefix)
+	}
+	got, _ := clientset.CoreV1().Pods("shop").Get(ctx, "web-1", metav1.GetOptions{})
+	if len(got.Spec.EphemeralContainers) != 1 || got.Spec.EphemeralContainers[0].Image != NetshootImage {
+		t.Fatalf("EphemeralContainers = %+v, want one netshoot container", got.Spec.EphemeralContainers)
+	}
+
+	// A running netshoot container is reused rather than adding another
+	again, err := AddNetshootContainer(ctx, clientset, "shop", "web-1")
+	if err != nil || again != name {
+		t.Errorf("AddNetshootContainer() again = %q, %v, want %q", again, err, name)
+	}
+	got, _ = clientset.CoreV1().Pods("shop").Get(ctx, "web-1", metav1.GetOptions{})
+	if len(got.Spec.EphemeralContainers) != 1 {
+		t.Errorf("EphemeralContainers = %d, want the container reused", len(got.Spec.EphemeralContainers))
+	}
+}
+
+func TestAddNetshootContainer_PullFailure(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"}}
+	clientset := fake.NewSimpleClientset(pod)
+	clientset.PrependReactor("update", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pod := action.(k8stesting.UpdateAction).GetObject().(*corev1.Pod)
+		for _, c := range pod.Spec.EphemeralContainers {
+			pod.Status.EphemeralContainerStatuses = append(pod.Status.EphemeralContainerStatuses, corev1.ContainerStatus{
+				Name:  c.Name,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "registry unreachable"}},
+			})
+		}
+		return false, nil, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := AddNetshootContainer(ctx, clientset, "shop", "web-1")
+	if !errors.Is(err, ErrNetshootNotRunning) || !strings.Contains(err.Error(), "registry unreachable") {
+		t.Errorf("AddNetshootContainer() error = %v, want ErrNetshootNotRunning with the pull error", err)
+	}
+}
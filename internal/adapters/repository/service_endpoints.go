@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PodEndpointState is whether a pod receives the traffic of a Service.
+type PodEndpointState string
+
+// States of a pod in the EndpointSlices of a Service. The zero value means
+// the slices could not be read.
+const (
+	PodEndpointServing  PodEndpointState = "serving"          // A ready endpoint
+	PodEndpointNotReady PodEndpointState = "not ready"        // An endpoint, but not ready, so it gets no traffic
+	PodEndpointMissing  PodEndpointState = "not in endpoints" // Not listed by any slice
+	PodEndpointNoSlices PodEndpointState = "no endpointslices"
+)
+
+// ServiceEndpoint is the state of a pod in the endpoints of one Service.
+type ServiceEndpoint struct {
+	Service string
+	State   PodEndpointState
+	Ready   int // Ready endpoints of the Service, this pod's or not
+}
+
+// GetServiceEndpointsForPod reports, for each of services, whether the pod
+// is one of its ready endpoints, reading the Service's EndpointSlices. A
+// Service without slices, e.g. one the controller has not reconciled yet,
+// is reported as PodEndpointNoSlices.
+func GetServiceEndpointsForPod(ctx context.Context, clientset kubernetes.Interface, pod PodInfo, services []string) ([]ServiceEndpoint, error) {
+	var result []ServiceEndpoint
+	for _, service := range services {
+		slices, err := listServiceEndpointSlices(ctx, clientset, pod.Namespace, service)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list endpointslices of %s: %w", service, err)
+		}
+		result = append(result, ServiceEndpoint{
+			Service: service,
+			State:   podEndpointState(slices.Items, pod),
+			Ready:   countReadyEndpoints(slices),
+		})
+	}
+	return result, nil
+}
+
+// podEndpointState finds pod in the endpoints of slices, by its target
+// reference or, for slices without one, its IP.
+func podEndpointState(slices []discoveryv1.EndpointSlice, pod PodInfo) PodEndpointState {
+	if len(slices) == 0 {
+		return PodEndpointNoSlices
+	}
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			if !isPodEndpoint(endpoint, pod) {
+				continue
+			}
+			// A nil Ready condition means ready, per the EndpointSlice API
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				return PodEndpointServing
+			}
+			return PodEndpointNotReady
+		}
+	}
+	return PodEndpointMissing
+}
+
+// isPodEndpoint reports whether endpoint stands for pod.
+func isPodEndpoint(endpoint discoveryv1.Endpoint, pod PodInfo) bool {
+	if ref := endpoint.TargetRef; ref != nil && ref.Kind == "Pod" {
+		return ref.Name == pod.Name && ref.Namespace == pod.Namespace
+	}
+	for _, address := range endpoint.Addresses {
+		if pod.IP != "" && address == pod.IP {
+			return true
+		}
+	}
+	return false
+}
+
+// NotReadyReasons explains why a pod is not ready, and so not serving its
+// Services: readiness gates whose condition is not True, containers that
+// are not running, and running containers whose readiness probe fails.
+// Returns nil for a ready pod.
+func NotReadyReasons(pod *PodInfo) []string {
+	conditions := make(map[string]corev1.ConditionStatus)
+	for _, c := range pod.Conditions {
+		conditions[string(c.Type)] = c.Status
+	}
+	if conditions[string(corev1.PodReady)] == corev1.ConditionTrue {
+		return nil
+	}
+
+	var reasons []string
+	for _, gate := range pod.ReadinessGates {
+		switch status, ok := conditions[gate]; {
+		case !ok:
+			reasons = append(reasons, fmt.Sprintf("readiness gate %s has no condition yet", gate))
+		case status != corev1.ConditionTrue:
+			reasons = append(reasons, fmt.Sprintf("readiness gate %s is %s", gate, status))
+		}
+	}
+	for _, c := range pod.Containers {
+		switch {
+		case c.Ready:
+		case c.State != "Running":
+			state := c.State
+			if state == "" {
+				state = "not started"
+			}
+			if c.Reason != "" {
+				state += " (" + c.Reason + ")"
+			}
+			reasons = append(reasons, fmt.Sprintf("container %s is %s", c.Name, state))
+		case c.ReadinessProbe != nil:
+			reasons = append(reasons, fmt.Sprintf("readiness probe of container %s is failing", c.Name))
+		default:
+			reasons = append(reasons, fmt.Sprintf("container %s is not ready", c.Name))
+		}
+	}
+	return reasons
+}
+
+// listServiceEndpointSlices lists the EndpointSlices of a Service. Clusters
+// older than 1.21 don't serve discovery.k8s.io/v1 and answer NotFound; the
+// Service's Endpoints are read instead, converted to a slice per subset.
+func listServiceEndpointSlices(ctx context.Context, clientset kubernetes.Interface, namespace, service string) (*discoveryv1.EndpointSliceList, error) {
+	slices, err := clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + service,
+	})
+	if !apierrors.IsNotFound(err) {
+		return slices, err
+	}
+	endpoints, err := clientset.CoreV1().Endpoints(namespace).Get(ctx, service, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return &discoveryv1.EndpointSliceList{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &discoveryv1.EndpointSliceList{Items: endpointsToSlices(endpoints)}, nil
+}
+
+// endpointsToSlices converts the subsets of an Endpoints object to the
+// EndpointSlices the controller would have made of them.
+func endpointsToSlices(endpoints *corev1.Endpoints) []discoveryv1.EndpointSlice {
+	var slices []discoveryv1.EndpointSlice
+	for _, subset := range endpoints.Subsets {
+		slice := discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      endpoints.Name,
+				Namespace: endpoints.Namespace,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: endpoints.Name},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+		}
+		for _, p := range subset.Ports {
+			p := p
+			slice.Ports = append(slice.Ports, discoveryv1.EndpointPort{Name: &p.Name, Port: &p.Port, Protocol: &p.Protocol})
+		}
+		add := func(addresses []corev1.EndpointAddress, ready bool) {
+			for _, a := range addresses {
+				ready := ready
+				slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+					Addresses:  []string{a.IP},
+					Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+					Hostname:   stringOrNil(a.Hostname),
+					NodeName:   a.NodeName,
+					TargetRef:  a.TargetRef,
+				})
+			}
+		}
+		add(subset.Addresses, true)
+		add(subset.NotReadyAddresses, false)
+		slices = append(slices, slice)
+	}
+	return slices
+}
+
+// stringOrNil returns a pointer to s, or nil when s is empty.
+func stringOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func endpointSlice(service, name string, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "shop",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   endpoints,
+	}
+}
+
+func podEndpoint(pod, ip string, ready bool) discoveryv1.Endpoint {
+	return discoveryv1.Endpoint{
+		Addresses:  []string{ip},
+		Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+		TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: pod, Namespace: "shop"},
+	}
+}
+
+func TestGetServiceEndpointsForPod(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		endpointSlice("web", "web-abc12", podEndpoint("web-1", "10.0.0.5", true), podEndpoint("web-2", "10.0.0.6", true)),
+		endpointSlice("web-canary", "web-canary-x", podEndpoint("web-1", "10.0.0.5", false)),
+		endpointSlice("api", "api-zz", podEndpoint("api-1", "10.0.0.9", true)),
+		// Manually managed endpoints have no target reference
+		endpointSlice("legacy", "legacy-1", discoveryv1.Endpoint{Addresses: []string{"10.0.0.5"}}),
+	)
+	pod := PodInfo{Name: "web-1", Namespace: "shop", IP: "10.0.0.5"}
+
+	got, err := GetServiceEndpointsForPod(context.Background(), clientset, pod, []string{"web", "web-canary", "api", "legacy", "new"})
+	if err != nil {
+		t.Fatalf("GetServiceEndpointsForPod() error = %v", err)
+	}
+	want := []ServiceEndpoint{
+		{Service: "web", State: PodEndpointServing, Ready: 2},
+		{Service: "web-canary", State: PodEndpointNotReady, Ready: 0},
+		{Service: "api", State: PodEndpointMissing, Ready: 1},
+		{Service: "legacy", State: PodEndpointServing, Ready: 0},
+		{Service: "new", State: PodEndpointNoSlices, Ready: 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetServiceEndpointsForPod() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("endpoint[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGetRelatedResources_PodEndpoint(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+	}
+	clientset := fake.NewSimpleClientset(svc, endpointSlice("web", "web-abc12", podEndpoint("web-1", "10.0.0.5", false)))
+
+	related, err := GetRelatedResources(context.Background(), clientset, nil, PodInfo{
+		Name: "web-1", Namespace: "shop", IP: "10.0.0.5", Labels: map[string]string{"app": "web"},
+	})
+	if err != nil {
+		t.Fatalf("GetRelatedResources() error = %v", err)
+	}
+	if len(related.Services) != 1 || related.Services[0].PodEndpoint != PodEndpointNotReady {
+		t.Errorf("Services = %+v, want web with the pod not ready", related.Services)
+	}
+}
+
+func TestNotReadyReasons(t *testing.T) {
+	ready := &PodInfo{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}
+	if got := NotReadyReasons(ready); got != nil {
+		t.Errorf("NotReadyReasons(ready pod) = %v, want nil", got)
+	}
+
+	pod := &PodInfo{
+		ReadinessGates: []string{"target-health.elbv2.k8s.aws/web", "example.com/warm"},
+		Conditions: []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+			{Type: "target-health.elbv2.k8s.aws/web", Status: corev1.ConditionFalse},
+		},
+		Containers: []ContainerInfo{
+			{Name: "app", State: "Running", ReadinessProbe: &ProbeInfo{Type: "HTTP"}},
+			{Name: "proxy", State: "Waiting", Reason: "CrashLoopBackOff"},
+			{Name: "metrics", State: "Running", Ready: true},
+		},
+	}
+	got := strings.Join(NotReadyReasons(pod), "\n")
+	for _, want := range []string{
+		"readiness gate target-health.elbv2.k8s.aws/web is False",
+		"readiness gate example.com/warm has no condition yet",
+		"readiness probe of container app is failing",
+		"container proxy is Waiting (CrashLoopBackOff)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("NotReadyReasons() should contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "metrics") {
+		t.Error("a ready container should not be listed")
+	}
+}
@@ -0,0 +1,447 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+// lastAppliedAnnotation holds the manifest kubectl apply last applied,
+// which for a Secret includes its data.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// SessionSnapshot is the state of a namespace at one point of a recorded
+// session: the objects the views are built from and their metrics. Secret
+// values and managedFields are left out. A kind that could not be listed,
+// such as Secrets without RBAC access, is recorded as skipped.
+type SessionSnapshot struct {
+	Time          time.Time `json:"time"`
+	Context       string    `json:"context"`
+	Namespace     string    `json:"namespace"` // "" for all namespaces
+	ServerVersion string    `json:"serverVersion,omitempty"`
+
+	Namespaces               []corev1.Namespace                      `json:"namespaces,omitempty"`
+	Nodes                    []corev1.Node                           `json:"nodes,omitempty"`
+	Pods                     []corev1.Pod                            `json:"pods,omitempty"`
+	Events                   []corev1.Event                          `json:"events,omitempty"`
+	Services                 []corev1.Service                        `json:"services,omitempty"`
+	EndpointSlices           []discoveryv1.EndpointSlice             `json:"endpointSlices,omitempty"`
+	Ingresses                []networkingv1.Ingress                  `json:"ingresses,omitempty"`
+	ConfigMaps               []corev1.ConfigMap                      `json:"configMaps,omitempty"`
+	Secrets                  []corev1.Secret                         `json:"secrets,omitempty"` // Keys only, values emptied
+	ServiceAccounts          []corev1.ServiceAccount                 `json:"serviceAccounts,omitempty"`
+	PersistentVolumeClaims   []corev1.PersistentVolumeClaim          `json:"persistentVolumeClaims,omitempty"`
+	Deployments              []appsv1.Deployment                     `json:"deployments,omitempty"`
+	StatefulSets             []appsv1.StatefulSet                    `json:"statefulSets,omitempty"`
+	DaemonSets               []appsv1.DaemonSet                      `json:"daemonSets,omitempty"`
+	ReplicaSets              []appsv1.ReplicaSet                     `json:"replicaSets,omitempty"`
+	Jobs                     []batchv1.Job                           `json:"jobs,omitempty"`
+	CronJobs                 []batchv1.CronJob                       `json:"cronJobs,omitempty"`
+	HorizontalPodAutoscalers []autoscalingv2.HorizontalPodAutoscaler `json:"horizontalPodAutoscalers,omitempty"`
+	PodDisruptionBudgets     []policyv1.PodDisruptionBudget          `json:"podDisruptionBudgets,omitempty"`
+	PodMetrics               []metricsv1beta1.PodMetrics             `json:"podMetrics,omitempty"`
+	NodeMetrics              []metricsv1beta1.NodeMetrics            `json:"nodeMetrics,omitempty"`
+
+	Skipped []SnapshotSkip `json:"skipped,omitempty"`
+}
+
+// CaptureSession lists the objects of namespace, and the nodes and
+// namespaces of the cluster, into a snapshot for a recorded session.
+// metricsClient may be nil. Nothing fails the capture: a kind that can't
+// be listed is recorded as skipped.
+func CaptureSession(ctx context.Context, clientset kubernetes.Interface, metricsClient MetricsClientInterface, contextName, namespace string) *SessionSnapshot {
+	snap := &SessionSnapshot{Time: time.Now().UTC(), Context: contextName, Namespace: namespace}
+	listed := func(section string, err error) bool {
+		if err != nil {
+			snap.Skipped = append(snap.Skipped, SnapshotSkip{Section: section, Reason: err.Error()})
+		}
+		return err == nil
+	}
+	opts := metav1.ListOptions{}
+
+	if info, err := clientset.Discovery().ServerVersion(); listed("version", err) {
+		snap.ServerVersion = info.GitVersion
+	}
+	core, apps, batch := clientset.CoreV1(), clientset.AppsV1(), clientset.BatchV1()
+	if list, err := core.Namespaces().List(ctx, opts); listed("namespaces", err) {
+		snap.Namespaces = list.Items
+	}
+	if list, err := core.Nodes().List(ctx, opts); listed("nodes", err) {
+		snap.Nodes = list.Items
+	}
+	if list, err := core.Pods(namespace).List(ctx, opts); listed("pods", err) {
+		snap.Pods = list.Items
+	}
+	if list, err := core.Events(namespace).List(ctx, opts); listed("events", err) {
+		snap.Events = list.Items
+	}
+	if list, err := core.Services(namespace).List(ctx, opts); listed("services", err) {
+		snap.Services = list.Items
+	}
+	if list, err := clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, opts); listed("endpointslices", err) {
+		snap.EndpointSlices = list.Items
+	}
+	if list, err := clientset.NetworkingV1().Ingresses(namespace).List(ctx, opts); listed("ingresses", err) {
+		snap.Ingresses = list.Items
+	}
+	if list, err := core.ConfigMaps(namespace).List(ctx, opts); listed("configmaps", err) {
+		snap.ConfigMaps = list.Items
+	}
+	if list, err := core.Secrets(namespace).List(ctx, opts); listed("secrets", err) {
+		for _, s := range list.Items {
+			snap.Secrets = append(snap.Secrets, redactSecret(s))
+		}
+	}
+	if list, err := core.ServiceAccounts(namespace).List(ctx, opts); listed("serviceaccounts", err) {
+		snap.ServiceAccounts = list.Items
+	}
+	if list, err := core.PersistentVolumeClaims(namespace).List(ctx, opts); listed("persistentvolumeclaims", err) {
+		snap.PersistentVolumeClaims = list.Items
+	}
+	if list, err := apps.Deployments(namespace).List(ctx, opts); listed("deployments", err) {
+		snap.Deployments = list.Items
+	}
+	if list, err := apps.StatefulSets(namespace).List(ctx, opts); listed("statefulsets", err) {
+		snap.StatefulSets = list.Items
+	}
+	if list, err := apps.DaemonSets(namespace).List(ctx, opts); listed("daemonsets", err) {
+		snap.DaemonSets = list.Items
+	}
+	if list, err := apps.ReplicaSets(namespace).List(ctx, opts); listed("replicasets", err) {
+		snap.ReplicaSets = list.Items
+	}
+	if list, err := batch.Jobs(namespace).List(ctx, opts); listed("jobs", err) {
+		snap.Jobs = list.Items
+	}
+	if list, err := batch.CronJobs(namespace).List(ctx, opts); listed("cronjobs", err) {
+		snap.CronJobs = list.Items
+	}
+	if list, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, opts); listed("horizontalpodautoscalers", err) {
+		snap.HorizontalPodAutoscalers = list.Items
+	}
+	if list, err := clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, opts); listed("poddisruptionbudgets", err) {
+		snap.PodDisruptionBudgets = list.Items
+	}
+
+	if metricsClient == nil {
+		listed("metrics", ErrMetricsUnavailable)
+	} else {
+		if list, err := metricsClient.MetricsV1beta1().PodMetricses(namespace).List(ctx, opts); listed("pod metrics", err) {
+			snap.PodMetrics = list.Items
+		}
+		if list, err := metricsClient.MetricsV1beta1().NodeMetricses().List(ctx, opts); listed("node metrics", err) {
+			snap.NodeMetrics = list.Items
+		}
+	}
+
+	for _, obj := range snap.objects() {
+		if accessor, err := meta.Accessor(obj); err == nil {
+			accessor.SetManagedFields(nil)
+		}
+	}
+	return snap
+}
+
+// redactSecret returns a copy of s keeping the names of its keys, with
+// empty values.
+func redactSecret(s corev1.Secret) corev1.Secret {
+	s = *s.DeepCopy()
+	for k := range s.Data {
+		s.Data[k] = []byte{}
+	}
+	s.StringData = nil
+	delete(s.Annotations, lastAppliedAnnotation)
+	return s
+}
+
+// objects returns pointers to the Kubernetes objects of the snapshot,
+// metrics aside.
+func (s *SessionSnapshot) objects() []runtime.Object {
+	var objects []runtime.Object
+	for i := range s.Namespaces {
+		objects = append(objects, &s.Namespaces[i])
+	}
+	for i := range s.Nodes {
+		objects = append(objects, &s.Nodes[i])
+	}
+	for i := range s.Pods {
+		objects = append(objects, &s.Pods[i])
+	}
+	for i := range s.Events {
+		objects = append(objects, &s.Events[i])
+	}
+	for i := range s.Services {
+		objects = append(objects, &s.Services[i])
+	}
+	for i := range s.EndpointSlices {
+		objects = append(objects, &s.EndpointSlices[i])
+	}
+	for i := range s.Ingresses {
+		objects = append(objects, &s.Ingresses[i])
+	}
+	for i := range s.ConfigMaps {
+		objects = append(objects, &s.ConfigMaps[i])
+	}
+	for i := range s.Secrets {
+		objects = append(objects, &s.Secrets[i])
+	}
+	for i := range s.ServiceAccounts {
+		objects = append(objects, &s.ServiceAccounts[i])
+	}
+	for i := range s.PersistentVolumeClaims {
+		objects = append(objects, &s.PersistentVolumeClaims[i])
+	}
+	for i := range s.Deployments {
+		objects = append(objects, &s.Deployments[i])
+	}
+	for i := range s.StatefulSets {
+		objects = append(objects, &s.StatefulSets[i])
+	}
+	for i := range s.DaemonSets {
+		objects = append(objects, &s.DaemonSets[i])
+	}
+	for i := range s.ReplicaSets {
+		objects = append(objects, &s.ReplicaSets[i])
+	}
+	for i := range s.Jobs {
+		objects = append(objects, &s.Jobs[i])
+	}
+	for i := range s.CronJobs {
+		objects = append(objects, &s.CronJobs[i])
+	}
+	for i := range s.HorizontalPodAutoscalers {
+		objects = append(objects, &s.HorizontalPodAutoscalers[i])
+	}
+	for i := range s.PodDisruptionBudgets {
+		objects = append(objects, &s.PodDisruptionBudgets[i])
+	}
+	return objects
+}
+
+// SessionRecorder appends the snapshots of a recorded session to a file,
+// one JSON object per line. It is safe for concurrent use.
+type SessionRecorder struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewSessionRecorder opens path for recording, appending to the snapshots
+// it already holds. The file is only readable by the user, since
+// ConfigMaps and environment variables may still hold sensitive values.
+func NewSessionRecorder(path string) (*SessionRecorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	return &SessionRecorder{file: file}, nil
+}
+
+// Record appends snap to the recording.
+func (r *SessionRecorder) Record(snap *SessionSnapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the recording. A nil recorder is a no-op.
+func (r *SessionRecorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// LoadSessionSnapshots reads the snapshots of a recording, oldest first.
+func LoadSessionSnapshots(path string) ([]SessionSnapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+
+	var snapshots []SessionSnapshot
+	dec := json.NewDecoder(file)
+	for {
+		var snap SessionSnapshot
+		err := dec.Decode(&snap)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot %d in %s: %w", len(snapshots)+1, path, err)
+		}
+		snapshots = append(snapshots, snap)
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshots in %s", path)
+	}
+	return snapshots, nil
+}
+
+// SelectSnapshot returns the index of the snapshot showing the session at
+// at: the last one taken at or before it, or the last one for a zero at.
+func SelectSnapshot(snapshots []SessionSnapshot, at time.Time) (int, error) {
+	if at.IsZero() {
+		return len(snapshots) - 1, nil
+	}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if !snapshots[i].Time.After(at) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("the recording starts at %s, after %s", snapshots[0].Time.Format(time.RFC3339), at.Format(time.RFC3339))
+}
+
+// replayListKinds are the custom resources the app lists through the
+// dynamic client, which the fake one must know to answer with empty lists.
+var replayListKinds = map[schema.GroupVersionResource]string{
+	rolloutGVR:         "RolloutList",
+	destinationRuleGVR: "DestinationRuleList",
+	{Group: "networking.istio.io", Version: "v1beta1", Resource: "virtualservices"}: "VirtualServiceList",
+	{Group: "networking.istio.io", Version: "v1beta1", Resource: "gateways"}:        "GatewayList",
+}
+
+// NewReplayClient returns a read-only client serving the objects of a
+// recorded snapshot instead of a cluster, for reproducing what k1s showed
+// offline. source names the recording, shown as the API server. Logs are
+// not recorded: the fake clientset answers every log request with the
+// same placeholder text. Custom resources, such as Argo Rollouts, are
+// not recorded either.
+func NewReplayClient(snap SessionSnapshot, source string) *Client {
+	clientset := fake.NewSimpleClientset(snap.objects()...)
+	clientset.PrependReactor("list", "*", fieldSelectorReactor(clientset.Tracker()))
+	disc := clientset.Discovery().(*fakediscovery.FakeDiscovery)
+	if snap.ServerVersion != "" {
+		disc.FakedServerVersion = &version.Info{GitVersion: snap.ServerVersion}
+	}
+
+	metricsClient := metricsfake.NewSimpleClientset()
+	podMetrics := metricsv1beta1.SchemeGroupVersion.WithResource("pods")
+	for i := range snap.PodMetrics {
+		_ = metricsClient.Tracker().Create(podMetrics, &snap.PodMetrics[i], snap.PodMetrics[i].Namespace)
+	}
+	nodeMetrics := metricsv1beta1.SchemeGroupVersion.WithResource("nodes")
+	for i := range snap.NodeMetrics {
+		_ = metricsClient.Tracker().Create(nodeMetrics, &snap.NodeMetrics[i], "")
+	}
+	if len(snap.PodMetrics) > 0 || len(snap.NodeMetrics) > 0 {
+		disc.Resources = append(disc.Resources, &metav1.APIResourceList{GroupVersion: metricsGroupVersion})
+	}
+
+	readOnly := &readOnlyGuard{}
+	readOnly.enabled.Store(true)
+	return &Client{
+		clientset:     clientset,
+		metricsClient: metricsClient,
+		dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme.Scheme, replayListKinds, snap.objects()...),
+		config:        &rest.Config{Host: "replay://" + source},
+		context:       snap.Context,
+		namespace:     snap.Namespace,
+		contextNS:     snap.Namespace,
+		readOnly:      readOnly,
+		metricsProbe:  &metricsProbe{},
+		replay:        &snap,
+	}
+}
+
+// Replay returns the snapshot a replay client serves, nil for a client of
+// a cluster.
+func (c *Client) Replay() *SessionSnapshot {
+	return c.replay
+}
+
+// fieldSelectorReactor answers list requests that have a field selector,
+// which the fake clientset ignores, with the matching objects only: the
+// events of a pod, or the pods of a node.
+func fieldSelectorReactor(tracker k8stesting.ObjectTracker) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list, ok := action.(k8stesting.ListActionImpl)
+		if !ok {
+			return false, nil, nil
+		}
+		restrictions := list.GetListRestrictions()
+		if restrictions.Fields == nil || restrictions.Fields.Empty() {
+			return false, nil, nil
+		}
+		obj, err := tracker.List(list.GetResource(), list.Kind, list.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		items, err := meta.ExtractList(obj)
+		if err != nil {
+			return true, nil, err
+		}
+		var kept []runtime.Object
+		for _, item := range items {
+			accessor, err := meta.Accessor(item)
+			if err != nil {
+				continue
+			}
+			if restrictions.Labels != nil && !restrictions.Labels.Matches(labels.Set(accessor.GetLabels())) {
+				continue
+			}
+			if restrictions.Fields.Matches(objectFields(item, accessor)) {
+				kept = append(kept, item)
+			}
+		}
+		if err := meta.SetList(obj, kept); err != nil {
+			return true, nil, err
+		}
+		return true, obj, nil
+	}
+}
+
+// objectFields returns the fields the API server lets lists select on for
+// the kinds k1s selects by field.
+func objectFields(obj runtime.Object, accessor metav1.Object) fields.Set {
+	set := fields.Set{"metadata.name": accessor.GetName(), "metadata.namespace": accessor.GetNamespace()}
+	switch o := obj.(type) {
+	case *corev1.Event:
+		set["involvedObject.kind"] = o.InvolvedObject.Kind
+		set["involvedObject.name"] = o.InvolvedObject.Name
+		set["involvedObject.namespace"] = o.InvolvedObject.Namespace
+		set["involvedObject.uid"] = string(o.InvolvedObject.UID)
+		set["reason"] = o.Reason
+		set["type"] = o.Type
+	case *corev1.Pod:
+		set["spec.nodeName"] = o.Spec.NodeName
+		set["status.phase"] = string(o.Status.Phase)
+	}
+	return set
+}
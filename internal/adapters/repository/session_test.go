@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+// recordedCluster is a namespace with a Deployment whose second pod is
+// crashlooping, events for both pods, a Service and a Secret.
+func recordedCluster() (*fake.Clientset, *metricsfake.Clientset) {
+	created := metav1.NewTime(time.Now().Add(-48 * time.Hour))
+	labels := map[string]string{"app": "web"}
+	pod := func(name string, ready bool) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: labels, CreationTimestamp: created},
+			Spec:       corev1.PodSpec{NodeName: "node-a", Containers: []corev1.Container{{Name: "app", Image: "web:1.2"}}},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "app", Ready: ready, RestartCount: 3,
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: created}},
+				}},
+			},
+		}
+		if !ready {
+			p.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+		}
+		return p
+	}
+	event := func(name, pod, reason string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: pod, Namespace: "shop"},
+			Reason:         reason,
+			Type:           corev1.EventTypeWarning,
+			Count:          4,
+			LastTimestamp:  created,
+		}
+	}
+	port := int32(8080)
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", CreationTimestamp: created},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2), Selector: &metav1.LabelSelector{MatchLabels: labels}},
+			Status:     appsv1.DeploymentStatus{Replicas: 2, ReadyReplicas: 1, AvailableReplicas: 1},
+		},
+		pod("web-1", true),
+		pod("web-2", false),
+		event("web-1.1", "web-1", "Unhealthy"),
+		event("web-2.1", "web-2", "BackOff"),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec:       corev1.ServiceSpec{Selector: labels, Ports: []corev1.ServicePort{{Name: "http", Port: 80}}},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "shop", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+			Ports:      []discoveryv1.EndpointPort{{Name: &[]string{"http"}[0], Port: &port}},
+			Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.5"}, Conditions: discoveryv1.EndpointConditions{Ready: boolPtr(true)}}},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop", Annotations: map[string]string{
+				lastAppliedAnnotation: `{"data":{"password":"aHVudGVyMg=="}}`,
+				"team":                "payments",
+			}},
+			Data: map[string][]byte{"password": []byte("hunter2")},
+		},
+	)
+
+	metricsClient := metricsfake.NewSimpleClientset()
+	_ = metricsClient.Tracker().Create(metricsv1beta1.SchemeGroupVersion.WithResource("pods"), &metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"},
+		Containers: []metricsv1beta1.ContainerMetrics{{Name: "app", Usage: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("120m"),
+			corev1.ResourceMemory: resource.MustParse("96Mi"),
+		}}},
+	}, "shop")
+	return clientset, metricsClient
+}
+
+func TestCaptureSession_RedactsSecrets(t *testing.T) {
+	clientset, metricsClient := recordedCluster()
+	snap := CaptureSession(context.Background(), clientset, metricsClient, "prod", "shop")
+
+	if snap.Context != "prod" || snap.Namespace != "shop" || len(snap.Pods) != 2 || len(snap.PodMetrics) != 1 {
+		t.Fatalf("snapshot = %d pods, %d pod metrics in %s/%s, want both pods of shop in prod", len(snap.Pods), len(snap.PodMetrics), snap.Context, snap.Namespace)
+	}
+	if len(snap.Secrets) != 1 {
+		t.Fatalf("got %d secrets, want 1", len(snap.Secrets))
+	}
+	secret := snap.Secrets[0]
+	if value, ok := secret.Data["password"]; !ok || len(value) != 0 {
+		t.Errorf("Secret data = %q, want the key with an empty value", secret.Data)
+	}
+	if _, ok := secret.Annotations[lastAppliedAnnotation]; ok || secret.Annotations["team"] != "payments" {
+		t.Errorf("Secret annotations = %v, want only the last-applied configuration dropped", secret.Annotations)
+	}
+
+	// The cluster's own Secret is left alone
+	live, _ := clientset.CoreV1().Secrets("shop").Get(context.Background(), "db", metav1.GetOptions{})
+	if string(live.Data["password"]) != "hunter2" {
+		t.Error("CaptureSession should not modify the listed Secret")
+	}
+}
+
+func TestSessionReplay(t *testing.T) {
+	ctx := context.Background()
+	clientset, metricsClient := recordedCluster()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	recorder, err := NewSessionRecorder(path)
+	if err != nil {
+		t.Fatalf("NewSessionRecorder failed: %v", err)
+	}
+	first := CaptureSession(ctx, clientset, metricsClient, "prod", "shop")
+	if err := recorder.Record(first); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	// The pod recovers before the second snapshot
+	_ = clientset.CoreV1().Pods("shop").Delete(ctx, "web-2", metav1.DeleteOptions{})
+	second := CaptureSession(ctx, clientset, metricsClient, "prod", "shop")
+	second.Time = first.Time.Add(10 * time.Second)
+	if err := recorder.Record(second); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	snapshots, err := LoadSessionSnapshots(path)
+	if err != nil {
+		t.Fatalf("LoadSessionSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("got %d snapshots, want 2", len(snapshots))
+	}
+	i, err := SelectSnapshot(snapshots, first.Time.Add(5*time.Second))
+	if err != nil || i != 0 {
+		t.Fatalf("SelectSnapshot() = %d, %v, want the first snapshot", i, err)
+	}
+
+	// Put web-2 back, so that the live cluster matches the first snapshot
+	clientset, metricsClient = recordedCluster()
+	replay := NewReplayClient(snapshots[i], path)
+	if !replay.ReadOnly() || replay.Replay() == nil || replay.Context() != "prod" || replay.Namespace() != "shop" {
+		t.Fatalf("replay client = read-only %v in %s/%s, want a read-only client of prod/shop", replay.ReadOnly(), replay.Context(), replay.Namespace())
+	}
+
+	render := func(name string, live, replayed interface{}, liveErr, replayErr error) {
+		t.Helper()
+		if liveErr != nil || replayErr != nil {
+			t.Fatalf("%s: live error %v, replay error %v", name, liveErr, replayErr)
+		}
+		if !reflect.DeepEqual(live, replayed) {
+			t.Errorf("%s differs on replay:\nlive:   %+v\nreplay: %+v", name, live, replayed)
+		}
+	}
+	liveWorkloads, liveErr := ListWorkloads(ctx, clientset, "shop", ResourceDeployments)
+	replayWorkloads, replayErr := ListWorkloads(ctx, replay.Clientset(), "shop", ResourceDeployments)
+	render("workloads", liveWorkloads, replayWorkloads, liveErr, replayErr)
+
+	livePod, liveErr := GetPod(ctx, clientset, "shop", "web-2")
+	replayPod, replayErr := GetPod(ctx, replay.Clientset(), "shop", "web-2")
+	render("pod", livePod, replayPod, liveErr, replayErr)
+
+	// Unlike the fake clientset, the replay applies field selectors, as
+	// the API server would
+	events, err := GetPodEvents(ctx, replay.Clientset(), "shop", "web-2")
+	if err != nil || len(events) != 1 || events[0].Reason != "BackOff" {
+		t.Errorf("replayed events of web-2 = %+v, %v, want its BackOff event only", events, err)
+	}
+
+	liveMetrics, liveErr := GetPodMetrics(ctx, metricsClient, "shop", "web-1")
+	replayMetrics, replayErr := GetPodMetrics(ctx, replay.MetricsClient(), "shop", "web-1")
+	render("metrics", liveMetrics, replayMetrics, liveErr, replayErr)
+
+	liveDescribe, liveErr := DescribeService(ctx, clientset, "shop", "web")
+	replayDescribe, replayErr := DescribeService(ctx, replay.Clientset(), "shop", "web")
+	render("describe", liveDescribe, replayDescribe, liveErr, replayErr)
+
+	if status := replay.ProbeMetrics(ctx, "shop"); status.State != MetricsAvailable {
+		t.Errorf("replayed metrics status = %+v, want available", status)
+	}
+	if _, err := replay.CheckConnection(time.Second); err != nil {
+		t.Errorf("CheckConnection on replay failed: %v", err)
+	}
+	if err := replay.SwitchContext("staging"); err == nil {
+		t.Error("SwitchContext should fail while replaying")
+	}
+	if err := replay.DeletePod(ctx, "shop", "web-1"); err != ErrReadOnly {
+		t.Errorf("DeletePod on replay = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestSelectSnapshot(t *testing.T) {
+	start := time.Date(2024, 5, 16, 10, 0, 0, 0, time.UTC)
+	snapshots := []SessionSnapshot{{Time: start}, {Time: start.Add(time.Minute)}, {Time: start.Add(2 * time.Minute)}}
+
+	for _, tt := range []struct {
+		at   time.Time
+		want int
+	}{
+		{time.Time{}, 2},
+		{start, 0},
+		{start.Add(90 * time.Second), 1},
+		{start.Add(time.Hour), 2},
+	} {
+		if got, err := SelectSnapshot(snapshots, tt.at); err != nil || got != tt.want {
+			t.Errorf("SelectSnapshot(%v) = %d, %v, want %d", tt.at, got, err, tt.want)
+		}
+	}
+	if _, err := SelectSnapshot(snapshots, start.Add(-time.Second)); err == nil {
+		t.Error("SelectSnapshot before the recording should fail")
+	}
+}
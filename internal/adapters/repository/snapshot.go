@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// snapshotManifestFile is the name of the manifest in a snapshot.
+const snapshotManifestFile = "manifest.json"
+
+// SnapshotOptions configures ExportSnapshot.
+type SnapshotOptions struct {
+	TailLines  int64                                                             // Log lines per container and instance; 0 for all
+	Archive    bool                                                              // Write a .tar.gz file instead of a directory
+	Describe   func(ctx context.Context, namespace, name string) (string, error) // Produces describe.txt; nil skips it
+	OnProgress func(section string)                                              // Called before each section is gathered
+}
+
+// SnapshotSkip records a section left out of a snapshot and why.
+type SnapshotSkip struct {
+	Section string `json:"section"`
+	Reason  string `json:"reason"`
+}
+
+// SnapshotManifest describes the contents of a snapshot. It is written to
+// the snapshot as manifest.json.
+type SnapshotManifest struct {
+	Pod       string         `json:"pod"`
+	Namespace string         `json:"namespace"`
+	Node      string         `json:"node,omitempty"`
+	Status    string         `json:"status"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Files     []string       `json:"files"`
+	Skipped   []SnapshotSkip `json:"skipped,omitempty"`
+}
+
+// snapshotFile is a file gathered for a snapshot.
+type snapshotFile struct {
+	name string
+	data []byte
+}
+
+// ExportSnapshot gathers the debugging context of a pod into path, for
+// handing to someone without access to the cluster: the pod YAML, describe
+// output, the last log lines of each container (and of its previous
+// instance after a restart), events, related resources and metrics, with a
+// manifest.json listing them. A section that can't be gathered, such as
+// metrics without metrics-server, is recorded as skipped in the manifest
+// instead of failing the export. With opts.Archive, path is a .tar.gz
+// file, otherwise a directory. Returns an error only when the pod can't be
+// read or the snapshot can't be written.
+func ExportSnapshot(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, metricsClient MetricsClientInterface, namespace, podName, path string, opts SnapshotOptions) (*SnapshotManifest, error) {
+	pod, err := GetPod(ctx, clientset, namespace, podName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	manifest := &SnapshotManifest{
+		Pod:       pod.Name,
+		Namespace: pod.Namespace,
+		Node:      pod.Node,
+		Status:    pod.Status,
+		CreatedAt: time.Now().UTC(),
+	}
+	var files []snapshotFile
+	progress := func(section string) {
+		if opts.OnProgress != nil {
+			opts.OnProgress(section)
+		}
+	}
+	add := func(section, name string, data []byte, err error) {
+		if err != nil {
+			manifest.Skipped = append(manifest.Skipped, SnapshotSkip{Section: section, Reason: err.Error()})
+			return
+		}
+		files = append(files, snapshotFile{name: name, data: data})
+		manifest.Files = append(manifest.Files, name)
+	}
+	addJSON := func(section, name string, v interface{}, err error) {
+		if err != nil {
+			add(section, name, nil, err)
+			return
+		}
+		data, err := json.MarshalIndent(v, "", "  ")
+		add(section, name, data, err)
+	}
+
+	progress("pod YAML")
+	podGVR, _ := GVRForKind("Pod")
+	podYAML, err := GetResourceYAML(ctx, dynamicClient, podGVR, namespace, podName, false)
+	add("pod", "pod.yaml", []byte(podYAML), err)
+
+	progress("describe")
+	if opts.Describe == nil {
+		add("describe", "describe.txt", nil, fmt.Errorf("describe not available"))
+	} else {
+		text, err := opts.Describe(ctx, namespace, podName)
+		add("describe", "describe.txt", []byte(text), err)
+	}
+
+	for _, c := range append(append([]ContainerInfo{}, pod.InitContainers...), pod.Containers...) {
+		progress("logs of " + c.Name)
+		logs, err := GetPodLogs(ctx, clientset, namespace, podName, LogOptions{Container: c.Name, TailLines: opts.TailLines, Timestamps: true})
+		add("logs/"+c.Name, "logs/"+c.Name+".log", formatSnapshotLogs(logs), err)
+		if c.RestartCount > 0 {
+			logs, err := GetPreviousLogs(ctx, clientset, namespace, podName, c.Name, opts.TailLines)
+			add("logs/"+c.Name+" (previous)", "logs/"+c.Name+".previous.log", formatSnapshotLogs(logs), err)
+		}
+	}
+
+	progress("events")
+	events, err := GetPodEvents(ctx, clientset, namespace, podName)
+	addJSON("events", "events.json", events, err)
+
+	progress("related resources")
+	related, err := GetRelatedResources(ctx, clientset, dynamicClient, *pod)
+	addJSON("related", "related.json", related, err)
+
+	progress("metrics")
+	metrics, err := GetPodMetrics(ctx, metricsClient, namespace, podName)
+	addJSON("metrics", "metrics.json", metrics, err)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot manifest: %w", err)
+	}
+	files = append([]snapshotFile{{name: snapshotManifestFile, data: data}}, files...)
+
+	progress("writing")
+	if opts.Archive {
+		err = writeSnapshotArchive(path, files)
+	} else {
+		err = writeSnapshotDir(path, files)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// formatSnapshotLogs renders log lines as text, each prefixed with its
+// timestamp when known.
+func formatSnapshotLogs(logs []LogLine) []byte {
+	var b strings.Builder
+	for _, l := range logs {
+		if !l.Timestamp.IsZero() {
+			b.WriteString(l.Timestamp.Format(time.RFC3339Nano))
+			b.WriteString(" ")
+		}
+		b.WriteString(l.Content)
+		b.WriteString("\n")
+	}
+	return []byte(b.String())
+}
+
+// writeSnapshotDir writes the files of a snapshot under dir.
+func writeSnapshotDir(dir string, files []snapshotFile) error {
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f.name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create snapshot directory: %w", err)
+		}
+		if err := os.WriteFile(path, f.data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// writeSnapshotArchive writes the files of a snapshot to a .tar.gz file.
+func writeSnapshotArchive(path string, files []snapshotFile) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot archive: %w", err)
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, f := range files {
+		header := &tar.Header{Name: f.name, Mode: 0o644, Size: int64(len(f.data)), ModTime: now}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot archive: %w", err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func snapshotClients() (*fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "web:1"}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", Ready: true, RestartCount: 2},
+			},
+		},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web-1.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1", Namespace: "default"},
+		Type:           "Warning",
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Group: "networking.istio.io", Version: "v1beta1", Resource: "virtualservices"}: "VirtualServiceList",
+			destinationRuleGVR: "DestinationRuleList",
+		},
+		yamlPod(),
+	)
+	return fake.NewSimpleClientset(pod, event), dynamicClient
+}
+
+func readSnapshotArchive(t *testing.T, path string) map[string]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read archive: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("read %s: %v", header.Name, err)
+		}
+		files[header.Name] = string(data)
+	}
+	return files
+}
+
+func TestExportSnapshot_Archive(t *testing.T) {
+	clientset, dynamicClient := snapshotClients()
+	path := filepath.Join(t.TempDir(), "web-1.tar.gz")
+
+	var sections []string
+	manifest, err := ExportSnapshot(context.Background(), clientset, dynamicClient, nil, "default", "web-1", path, SnapshotOptions{
+		TailLines: 100,
+		Archive:   true,
+		Describe: func(ctx context.Context, namespace, name string) (string, error) {
+			return "Name: " + name + "\nNamespace: " + namespace + "\n", nil
+		},
+		OnProgress: func(section string) { sections = append(sections, section) },
+	})
+	if err != nil {
+		t.Fatalf("ExportSnapshot() error = %v", err)
+	}
+	if len(sections) == 0 {
+		t.Error("expected progress callbacks")
+	}
+
+	files := readSnapshotArchive(t, path)
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := []string{
+		"describe.txt",
+		"events.json",
+		"logs/app.log",
+		"logs/app.previous.log",
+		"manifest.json",
+		"pod.yaml",
+		"related.json",
+	}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("archive files = %v, want %v", names, want)
+	}
+
+	if !strings.Contains(files["pod.yaml"], "name: web-1") {
+		t.Errorf("pod.yaml missing pod name:\n%s", files["pod.yaml"])
+	}
+	if !strings.Contains(files["describe.txt"], "Name: web-1") {
+		t.Errorf("describe.txt = %q", files["describe.txt"])
+	}
+	if !strings.Contains(files["events.json"], "BackOff") {
+		t.Errorf("events.json missing event:\n%s", files["events.json"])
+	}
+
+	var got SnapshotManifest
+	if err := json.Unmarshal([]byte(files["manifest.json"]), &got); err != nil {
+		t.Fatalf("manifest.json: %v", err)
+	}
+	if got.Pod != "web-1" || got.Namespace != "default" {
+		t.Errorf("manifest pod = %s/%s", got.Namespace, got.Pod)
+	}
+	if len(got.Files) != len(manifest.Files) {
+		t.Errorf("manifest files = %v, want %v", got.Files, manifest.Files)
+	}
+	if len(got.Skipped) != 1 || got.Skipped[0].Section != "metrics" {
+		t.Errorf("manifest skipped = %+v, want metrics", got.Skipped)
+	}
+}
+
+func TestExportSnapshot_Directory(t *testing.T) {
+	clientset, dynamicClient := snapshotClients()
+	dir := filepath.Join(t.TempDir(), "web-1")
+
+	manifest, err := ExportSnapshot(context.Background(), clientset, dynamicClient, nil, "default", "web-1", dir, SnapshotOptions{})
+	if err != nil {
+		t.Fatalf("ExportSnapshot() error = %v", err)
+	}
+
+	for _, name := range append([]string{"manifest.json"}, manifest.Files...) {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			t.Errorf("missing %s: %v", name, err)
+		}
+	}
+	skipped := map[string]bool{}
+	for _, s := range manifest.Skipped {
+		skipped[s.Section] = true
+	}
+	if !skipped["describe"] || !skipped["metrics"] {
+		t.Errorf("skipped = %+v, want describe and metrics", manifest.Skipped)
+	}
+}
+
+func TestExportSnapshot_PodNotFound(t *testing.T) {
+	clientset, dynamicClient := snapshotClients()
+	_, err := ExportSnapshot(context.Background(), clientset, dynamicClient, nil, "default", "missing", t.TempDir(), SnapshotOptions{})
+	if err == nil {
+		t.Error("expected error for missing pod")
+	}
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// StatefulSetDetails describes what sets a StatefulSet apart from a
+// Deployment: its stable ordinals, the PVCs created for each ordinal from
+// volumeClaimTemplates, and partitioned rolling updates.
+type StatefulSetDetails struct {
+	Name                string
+	Namespace           string
+	Replicas            int32
+	ServiceName         string // Headless Service giving the pods their DNS names
+	PodManagementPolicy string // OrderedReady or Parallel
+	UpdateStrategy      string // RollingUpdate or OnDelete
+	Partition           int32  // Ordinals below it stay on the current revision during a RollingUpdate
+	CurrentRevision     string
+	UpdateRevision      string
+	ClaimTemplates      []ClaimTemplate
+	Ordinals            []StatefulSetOrdinal // One per desired replica, lowest first
+}
+
+// ClaimTemplate is one of the volumeClaimTemplates of a StatefulSet.
+type ClaimTemplate struct {
+	Name         string
+	StorageClass string
+	Size         string
+	AccessModes  string
+}
+
+// StatefulSetOrdinal is the pod and claims of one ordinal of a StatefulSet.
+type StatefulSetOrdinal struct {
+	Ordinal  int32
+	Pod      string
+	Exists   bool   // false while the pod is missing, e.g. not created yet
+	Status   string // Pod phase, "Terminating" or "Missing"
+	Ready    bool
+	Revision string // controller-revision-hash of the pod
+	Updated  bool   // Whether the pod runs the update revision
+	Claims   []OrdinalClaim
+}
+
+// OrdinalClaim is the PVC of an ordinal created from a claim template.
+type OrdinalClaim struct {
+	Template string
+	Name     string // <template>-<statefulset>-<ordinal>
+	Phase    string // Bound, Pending, Lost or "Missing"
+}
+
+// Partitioned reports whether a rolling update is held back by a partition.
+func (d StatefulSetDetails) Partitioned() bool {
+	return d.UpdateStrategy == string(appsv1.RollingUpdateStatefulSetStrategyType) && d.Partition > 0
+}
+
+// UpdatedCount returns how many ordinals run the update revision.
+func (d StatefulSetDetails) UpdatedCount() int {
+	count := 0
+	for _, o := range d.Ordinals {
+		if o.Updated {
+			count++
+		}
+	}
+	return count
+}
+
+// GetStatefulSetDetails fetches a StatefulSet with the pod and PVCs of each
+// of its ordinals.
+func GetStatefulSetDetails(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (*StatefulSetDetails, error) {
+	sts, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statefulset: %w", err)
+	}
+
+	var pods []corev1.Pod
+	if sts.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %w", err)
+		}
+		podList, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		pods = podList.Items
+	}
+
+	var pvcs []corev1.PersistentVolumeClaim
+	if len(sts.Spec.VolumeClaimTemplates) > 0 {
+		pvcList, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pvcs: %w", err)
+		}
+		pvcs = pvcList.Items
+	}
+
+	return BuildStatefulSetDetails(sts, pods, pvcs), nil
+}
+
+// BuildStatefulSetDetails computes the details of a StatefulSet from its
+// pods and the PVCs of its namespace. Pods and claims are matched to
+// ordinals by name, and pods to revisions by their controller-revision-hash
+// label.
+func BuildStatefulSetDetails(sts *appsv1.StatefulSet, pods []corev1.Pod, pvcs []corev1.PersistentVolumeClaim) *StatefulSetDetails {
+	details := &StatefulSetDetails{
+		Name:                sts.Name,
+		Namespace:           sts.Namespace,
+		Replicas:            1,
+		ServiceName:         sts.Spec.ServiceName,
+		PodManagementPolicy: string(sts.Spec.PodManagementPolicy),
+		UpdateStrategy:      string(sts.Spec.UpdateStrategy.Type),
+		CurrentRevision:     sts.Status.CurrentRevision,
+		UpdateRevision:      sts.Status.UpdateRevision,
+	}
+	if sts.Spec.Replicas != nil {
+		details.Replicas = *sts.Spec.Replicas
+	}
+	if details.PodManagementPolicy == "" {
+		details.PodManagementPolicy = string(appsv1.OrderedReadyPodManagement)
+	}
+	if details.UpdateStrategy == "" {
+		details.UpdateStrategy = string(appsv1.RollingUpdateStatefulSetStrategyType)
+	}
+	if ru := sts.Spec.UpdateStrategy.RollingUpdate; ru != nil && ru.Partition != nil {
+		details.Partition = *ru.Partition
+	}
+
+	for _, t := range sts.Spec.VolumeClaimTemplates {
+		template := ClaimTemplate{Name: t.Name}
+		if t.Spec.StorageClassName != nil {
+			template.StorageClass = *t.Spec.StorageClassName
+		}
+		if size, ok := t.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			template.Size = size.String()
+		}
+		var modes []string
+		for _, m := range t.Spec.AccessModes {
+			modes = append(modes, string(m))
+		}
+		template.AccessModes = strings.Join(modes, ",")
+		details.ClaimTemplates = append(details.ClaimTemplates, template)
+	}
+
+	podsByName := make(map[string]*corev1.Pod, len(pods))
+	for i := range pods {
+		podsByName[pods[i].Name] = &pods[i]
+	}
+	pvcPhases := make(map[string]string, len(pvcs))
+	for _, pvc := range pvcs {
+		pvcPhases[pvc.Name] = string(pvc.Status.Phase)
+	}
+
+	var start int32
+	if sts.Spec.Ordinals != nil {
+		start = sts.Spec.Ordinals.Start
+	}
+	for ordinal := start; ordinal < start+details.Replicas; ordinal++ {
+		o := StatefulSetOrdinal{Ordinal: ordinal, Pod: fmt.Sprintf("%s-%d", sts.Name, ordinal), Status: "Missing"}
+		if pod, ok := podsByName[o.Pod]; ok {
+			o.Exists = true
+			o.Status = string(pod.Status.Phase)
+			if pod.DeletionTimestamp != nil {
+				o.Status = "Terminating"
+			}
+			o.Ready = isPodReady(pod)
+			o.Revision = pod.Labels[appsv1.ControllerRevisionHashLabelKey]
+			o.Updated = o.Revision != "" && o.Revision == details.UpdateRevision
+		}
+		for _, t := range sts.Spec.VolumeClaimTemplates {
+			claim := OrdinalClaim{Template: t.Name, Name: fmt.Sprintf("%s-%s-%d", t.Name, sts.Name, ordinal), Phase: "Missing"}
+			if phase, ok := pvcPhases[claim.Name]; ok {
+				claim.Phase = phase
+			}
+			o.Claims = append(o.Claims, claim)
+		}
+		details.Ordinals = append(details.Ordinals, o)
+	}
+	return details
+}
+
+// isPodReady reports whether the pod's Ready condition is true.
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// SetStatefulSetPartition sets the partition of a StatefulSet's rolling
+// update, like kubectl patch does. Ordinals at or above the partition are
+// updated to the new revision; lowering it step by step advances a
+// partitioned rollout, and 0 updates every ordinal. StatefulSets with the
+// OnDelete strategy have no partition.
+func SetStatefulSetPartition(ctx context.Context, clientset kubernetes.Interface, namespace, name string, partition int32) error {
+	if partition < 0 {
+		return fmt.Errorf("invalid partition %d", partition)
+	}
+	sts, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get statefulset: %w", err)
+	}
+	if sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return fmt.Errorf("statefulset %s uses the OnDelete update strategy, which has no partition", name)
+	}
+
+	sts.Spec.UpdateStrategy.Type = appsv1.RollingUpdateStatefulSetStrategyType
+	if sts.Spec.UpdateStrategy.RollingUpdate == nil {
+		sts.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{}
+	}
+	sts.Spec.UpdateStrategy.RollingUpdate.Partition = &partition
+	if _, err := clientset.AppsV1().StatefulSets(namespace).Update(ctx, sts, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to set partition: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// partitionedStatefulSet is a 3-replica StatefulSet mid-way through a
+// partitioned rollout: ordinal 2 runs the new revision, 0 and 1 the old one.
+func partitionedStatefulSet() *appsv1.StatefulSet {
+	replicas := int32(3)
+	partition := int32(2)
+	storageClass := "fast"
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: "db-headless",
+			Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: &storageClass,
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("10Gi"),
+					}},
+				},
+			}},
+		},
+		Status: appsv1.StatefulSetStatus{CurrentRevision: "db-old", UpdateRevision: "db-new"},
+	}
+}
+
+func statefulSetPod(name, revision string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "shop",
+			Labels:    map[string]string{"app": "db", appsv1.ControllerRevisionHashLabelKey: revision},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func statefulSetPVC(name string, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+}
+
+func TestGetStatefulSetDetails(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		partitionedStatefulSet(),
+		statefulSetPod("db-0", "db-old", true),
+		statefulSetPod("db-1", "db-old", true),
+		statefulSetPod("db-2", "db-new", false),
+		statefulSetPVC("data-db-0", corev1.ClaimBound),
+		statefulSetPVC("data-db-1", corev1.ClaimBound),
+		statefulSetPVC("data-db-2", corev1.ClaimPending),
+		statefulSetPVC("data-other-0", corev1.ClaimBound),
+	)
+
+	d, err := GetStatefulSetDetails(context.Background(), clientset, "shop", "db")
+	if err != nil {
+		t.Fatalf("GetStatefulSetDetails() error = %v", err)
+	}
+	if d.UpdateStrategy != "RollingUpdate" || d.Partition != 2 || !d.Partitioned() || d.PodManagementPolicy != "OrderedReady" {
+		t.Errorf("strategy = %s partition %d policy %s", d.UpdateStrategy, d.Partition, d.PodManagementPolicy)
+	}
+	if len(d.ClaimTemplates) != 1 || d.ClaimTemplates[0] != (ClaimTemplate{Name: "data", StorageClass: "fast", Size: "10Gi", AccessModes: "ReadWriteOnce"}) {
+		t.Errorf("ClaimTemplates = %+v", d.ClaimTemplates)
+	}
+
+	// ordinal, revision, updated, ready, claim phase
+	want := []struct {
+		pod      string
+		revision string
+		updated  bool
+		ready    bool
+		claim    string
+	}{
+		{"db-0", "db-old", false, true, "Bound"},
+		{"db-1", "db-old", false, true, "Bound"},
+		{"db-2", "db-new", true, false, "Pending"},
+	}
+	if len(d.Ordinals) != len(want) {
+		t.Fatalf("Ordinals = %+v, want 3", d.Ordinals)
+	}
+	for i, w := range want {
+		o := d.Ordinals[i]
+		if o.Ordinal != int32(i) || o.Pod != w.pod || o.Revision != w.revision || o.Updated != w.updated || o.Ready != w.ready {
+			t.Errorf("ordinal %d = %+v, want %+v", i, o, w)
+		}
+		if len(o.Claims) != 1 || o.Claims[0].Name != "data-"+w.pod || o.Claims[0].Phase != w.claim {
+			t.Errorf("ordinal %d claims = %+v, want data-%s %s", i, o.Claims, w.pod, w.claim)
+		}
+	}
+	if d.UpdatedCount() != 1 {
+		t.Errorf("UpdatedCount() = %d, want 1", d.UpdatedCount())
+	}
+}
+
+func TestBuildStatefulSetDetails_MissingPodAndClaim(t *testing.T) {
+	sts := partitionedStatefulSet()
+	sts.Spec.Ordinals = &appsv1.StatefulSetOrdinals{Start: 1}
+	pods := []corev1.Pod{*statefulSetPod("db-1", "db-new", true)}
+	pvcs := []corev1.PersistentVolumeClaim{*statefulSetPVC("data-db-1", corev1.ClaimBound)}
+
+	d := BuildStatefulSetDetails(sts, pods, pvcs)
+	if len(d.Ordinals) != 3 || d.Ordinals[0].Ordinal != 1 || d.Ordinals[2].Ordinal != 3 {
+		t.Fatalf("Ordinals = %+v, want 1 to 3", d.Ordinals)
+	}
+	missing := d.Ordinals[1]
+	if missing.Exists || missing.Status != "Missing" || missing.Updated || missing.Claims[0].Phase != "Missing" {
+		t.Errorf("ordinal 2 = %+v, want a missing pod and claim", missing)
+	}
+}
+
+func TestSetStatefulSetPartition(t *testing.T) {
+	clientset := fake.NewSimpleClientset(partitionedStatefulSet())
+	ctx := context.Background()
+
+	if err := SetStatefulSetPartition(ctx, clientset, "shop", "db", 1); err != nil {
+		t.Fatalf("SetStatefulSetPartition() error = %v", err)
+	}
+	sts, _ := clientset.AppsV1().StatefulSets("shop").Get(ctx, "db", metav1.GetOptions{})
+	if p := sts.Spec.UpdateStrategy.RollingUpdate.Partition; p == nil || *p != 1 {
+		t.Errorf("partition = %v, want 1", p)
+	}
+
+	if err := SetStatefulSetPartition(ctx, clientset, "shop", "db", -1); err == nil {
+		t.Error("a negative partition should be rejected")
+	}
+
+	sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+	clientset.AppsV1().StatefulSets("shop").Update(ctx, sts, metav1.UpdateOptions{})
+	if err := SetStatefulSetPartition(ctx, clientset, "shop", "db", 0); err == nil || !strings.Contains(err.Error(), "OnDelete") {
+		t.Errorf("SetStatefulSetPartition() error = %v, want OnDelete rejected", err)
+	}
+}
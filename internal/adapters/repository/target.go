@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// targetKinds maps the kinds accepted on the command line, with kubectl's
+// plural forms and short names, to resource types.
+var targetKinds = map[string]ResourceType{
+	"pod":          ResourcePods,
+	"pods":         ResourcePods,
+	"po":           ResourcePods,
+	"deployment":   ResourceDeployments,
+	"deployments":  ResourceDeployments,
+	"deploy":       ResourceDeployments,
+	"statefulset":  ResourceStatefulSets,
+	"statefulsets": ResourceStatefulSets,
+	"sts":          ResourceStatefulSets,
+	"daemonset":    ResourceDaemonSets,
+	"daemonsets":   ResourceDaemonSets,
+	"ds":           ResourceDaemonSets,
+	"job":          ResourceJobs,
+	"jobs":         ResourceJobs,
+}
+
+// ParseTargetKind returns the resource type of a kind as kubectl accepts
+// it: singular, plural or short name, in any case, optionally qualified
+// with its API group, e.g. "deploy" or "deployments.apps".
+func ParseTargetKind(kind string) (ResourceType, error) {
+	k := strings.ToLower(kind)
+	if i := strings.Index(k, "."); i >= 0 {
+		k = k[:i]
+	}
+	resourceType, ok := targetKinds[k]
+	if !ok {
+		return "", fmt.Errorf("unsupported kind %q: use pod, deployment, statefulset, daemonset or job", kind)
+	}
+	return resourceType, nil
+}
+
+// Target is the object a command line such as "kubectl k1s deploy/api"
+// points at: a pod, or a workload along with the pod most worth looking
+// at. Pod is nil for a workload without pods.
+type Target struct {
+	Workload *WorkloadInfo
+	Pod      *PodInfo
+}
+
+// ResolveTarget finds the pod or workload of kind named name in namespace.
+// For a workload, its worst pod (see WorstPod) is looked up too. Objects
+// that don't exist are reported with their kind and namespace.
+func ResolveTarget(ctx context.Context, clientset kubernetes.Interface, namespace, kind, name string) (*Target, error) {
+	resourceType, err := ParseTargetKind(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if resourceType == ResourcePods {
+		pod, err := GetPod(ctx, clientset, namespace, name)
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("pod %s not found in namespace %s", name, namespace)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod %s in namespace %s: %w", name, namespace, err)
+		}
+		return &Target{Pod: pod}, nil
+	}
+
+	workload, err := findWorkload(ctx, clientset, namespace, resourceType, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s in namespace %s: %w", kindName(resourceType), name, namespace, err)
+	}
+	if workload == nil {
+		return nil, fmt.Errorf("%s %s not found in namespace %s", kindName(resourceType), name, namespace)
+	}
+	pods, err := GetWorkloadPods(ctx, clientset, *workload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods of %s %s: %w", kindName(resourceType), name, err)
+	}
+	return &Target{Workload: workload, Pod: WorstPod(pods)}, nil
+}
+
+// findWorkload returns the workload of a type named name, or nil when
+// there is none.
+func findWorkload(ctx context.Context, clientset kubernetes.Interface, namespace string, resourceType ResourceType, name string) (*WorkloadInfo, error) {
+	workloads, _, err := listWorkloadsWith(ctx, clientset, namespace, resourceType, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range workloads {
+		if workloads[i].Name == name {
+			return &workloads[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// ResolveEventObject finds what to open for the object an event is about,
+// given as "Kind/name" like EventInfo.Object. A pod opens with the
+// workload that owns it, found through its ReplicaSet for a Deployment; a
+// ReplicaSet or workload opens on the workload's worst pod. Returns nil
+// when the object no longer exists or is not a pod or workload (a Node,
+// a PVC...), so that only the events can be shown.
+func ResolveEventObject(ctx context.Context, clientset kubernetes.Interface, namespace, object string) (*Target, error) {
+	kind, name, ok := strings.Cut(object, "/")
+	if !ok || name == "" {
+		return nil, nil
+	}
+
+	switch kind {
+	case "Pod":
+		pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		info := podToPodInfo(pod)
+		target := &Target{Pod: &info}
+		owner := metav1.GetControllerOf(pod)
+		if owner == nil {
+			return target, nil
+		}
+		ownerKind, ownerName := owner.Kind, owner.Name
+		if ownerKind == "ReplicaSet" {
+			ownerKind, ownerName, err = replicaSetOwner(ctx, clientset, namespace, ownerName)
+			if err != nil || ownerKind == "" {
+				return target, err
+			}
+		}
+		resourceType, err := ParseTargetKind(ownerKind)
+		if err != nil {
+			// Owned by something k1s doesn't list, e.g. an Argo Rollout
+			return target, nil
+		}
+		target.Workload, err = findWorkload(ctx, clientset, namespace, resourceType, ownerName)
+		return target, err
+	case "ReplicaSet":
+		ownerKind, ownerName, err := replicaSetOwner(ctx, clientset, namespace, name)
+		if err != nil || ownerKind == "" {
+			return nil, err
+		}
+		kind, name = ownerKind, ownerName
+	}
+
+	resourceType, err := ParseTargetKind(kind)
+	if err != nil || resourceType == ResourcePods {
+		return nil, nil
+	}
+	workload, err := findWorkload(ctx, clientset, namespace, resourceType, name)
+	if err != nil || workload == nil {
+		return nil, err
+	}
+	pods, err := GetWorkloadPods(ctx, clientset, *workload)
+	if err != nil {
+		return nil, err
+	}
+	return &Target{Workload: workload, Pod: WorstPod(pods)}, nil
+}
+
+// replicaSetOwner returns the kind and name of the controller of a
+// ReplicaSet, usually a Deployment. Both are empty when the ReplicaSet is
+// gone or has no controller.
+func replicaSetOwner(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (string, string, error) {
+	rs, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	owner := metav1.GetControllerOf(rs)
+	if owner == nil {
+		return "", "", nil
+	}
+	return owner.Kind, owner.Name, nil
+}
+
+// kindName is the singular kind of a resource type, e.g. "deployment".
+func kindName(resourceType ResourceType) string {
+	return strings.TrimSuffix(string(resourceType), "s")
+}
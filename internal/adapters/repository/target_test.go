@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseTargetKind(t *testing.T) {
+	tests := []struct {
+		kind    string
+		want    ResourceType
+		wantErr bool
+	}{
+		{"pod", ResourcePods, false},
+		{"po", ResourcePods, false},
+		{"Deployment", ResourceDeployments, false},
+		{"deploy", ResourceDeployments, false},
+		{"deployments.apps", ResourceDeployments, false},
+		{"sts", ResourceStatefulSets, false},
+		{"ds", ResourceDaemonSets, false},
+		{"jobs", ResourceJobs, false},
+		{"service", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTargetKind(tt.kind)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTargetKind(%q) error = %v, wantErr %v", tt.kind, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseTargetKind(%q) = %q, want %q", tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestResolveTarget(t *testing.T) {
+	labels := map[string]string{"app": "payments"}
+	pod := func(name string, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "prod", Labels: labels},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "app", Ready: true, RestartCount: restarts,
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				}},
+			},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "prod"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "idle", Namespace: "prod"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "idle"}}},
+		},
+		pod("payments-a", 0),
+		pod("payments-b", 4),
+	)
+	ctx := context.Background()
+
+	target, err := ResolveTarget(ctx, clientset, "prod", "deploy", "payments")
+	if err != nil {
+		t.Fatalf("ResolveTarget() error = %v", err)
+	}
+	if target.Workload == nil || target.Workload.Name != "payments" {
+		t.Fatalf("Workload = %+v, want payments", target.Workload)
+	}
+	if target.Pod == nil || target.Pod.Name != "payments-b" {
+		t.Errorf("Pod = %+v, want the worst pod payments-b", target.Pod)
+	}
+
+	target, err = ResolveTarget(ctx, clientset, "prod", "deployment", "idle")
+	if err != nil {
+		t.Fatalf("ResolveTarget() error = %v", err)
+	}
+	if target.Workload == nil || target.Pod != nil {
+		t.Errorf("target = %+v, want the workload without a pod", target)
+	}
+
+	target, err = ResolveTarget(ctx, clientset, "prod", "pod", "payments-a")
+	if err != nil {
+		t.Fatalf("ResolveTarget() error = %v", err)
+	}
+	if target.Workload != nil || target.Pod == nil || target.Pod.Name != "payments-a" {
+		t.Errorf("target = %+v, want pod payments-a", target)
+	}
+
+	for _, tt := range []struct{ kind, name, want string }{
+		{"deploy", "missing", "deployment missing not found in namespace prod"},
+		{"po", "missing", "pod missing not found in namespace prod"},
+		{"svc", "payments", "unsupported kind"},
+	} {
+		_, err := ResolveTarget(ctx, clientset, "prod", tt.kind, tt.name)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ResolveTarget(%s/%s) error = %v, want %q", tt.kind, tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestResolveEventObject(t *testing.T) {
+	controller := true
+	labels := map[string]string{"app": "payments"}
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "prod"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		},
+		&appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "payments-7d9f", Namespace: "prod", OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Deployment", Name: "payments", Controller: &controller},
+			}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "payments-7d9f-abc", Namespace: "prod", Labels: labels, OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "payments-7d9f", Controller: &controller},
+			}},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "standalone", Namespace: "prod"}},
+	)
+	ctx := context.Background()
+
+	target, err := ResolveEventObject(ctx, clientset, "prod", "Pod/payments-7d9f-abc")
+	if err != nil {
+		t.Fatalf("ResolveEventObject() error = %v", err)
+	}
+	if target == nil || target.Pod == nil || target.Pod.Name != "payments-7d9f-abc" {
+		t.Fatalf("target = %+v, want the pod", target)
+	}
+	if target.Workload == nil || target.Workload.Name != "payments" || target.Workload.Type != ResourceDeployments {
+		t.Errorf("Workload = %+v, want deployment payments", target.Workload)
+	}
+
+	target, err = ResolveEventObject(ctx, clientset, "prod", "ReplicaSet/payments-7d9f")
+	if err != nil || target == nil || target.Workload == nil || target.Workload.Name != "payments" {
+		t.Errorf("ResolveEventObject(ReplicaSet) = %+v, %v, want deployment payments", target, err)
+	}
+
+	target, err = ResolveEventObject(ctx, clientset, "prod", "Pod/standalone")
+	if err != nil || target == nil || target.Workload != nil || target.Pod.Name != "standalone" {
+		t.Errorf("ResolveEventObject(standalone pod) = %+v, %v, want the pod alone", target, err)
+	}
+
+	// Deleted objects and kinds without pods only show their events
+	for _, object := range []string{"Pod/gone", "Deployment/gone", "Node/worker-1", "ReplicaSet/gone"} {
+		target, err := ResolveEventObject(ctx, clientset, "prod", object)
+		if err != nil || target != nil {
+			t.Errorf("ResolveEventObject(%s) = %+v, %v, want nil", object, target, err)
+		}
+	}
+}
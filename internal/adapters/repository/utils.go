@@ -2,17 +2,28 @@ package repository
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
 // formatAge converts a timestamp to a human-readable age string.
 // Outputs formats like "45s", "5m", "2h", "3d" depending on the duration.
 func formatAge(t time.Time) string {
+	return FormatAge(t, time.Now())
+}
+
+// FormatAge returns how long before now t was in its largest whole unit,
+// "45s", "5m", "2h" or "3d", for Age columns and live event ages. A zero
+// t is "Unknown"; a t after now, from clock skew, is "0s".
+func FormatAge(t, now time.Time) string {
 	if t.IsZero() {
 		return "Unknown"
 	}
 
-	d := time.Since(t)
+	d := now.Sub(t)
+	if d < 0 {
+		d = 0
+	}
 
 	switch {
 	case d < time.Minute:
@@ -80,12 +91,34 @@ type DebugHelper struct {
 // AnalyzePodIssues examines pod state and events to identify common problems.
 // Returns a list of DebugHelper structs with diagnostic information.
 // Detects issues like CrashLoopBackOff, ImagePullBackOff, Pending pods,
-// OOMKilled containers, and missing resource limits.
+// failing init containers, OOMKilled containers, and missing resource limits.
 func AnalyzePodIssues(pod *PodInfo, events []EventInfo) []DebugHelper {
 	var helpers []DebugHelper
 
+	// A pod stuck in init reports the init container's reason as Init:<reason>
+	status := pod.Status
+	if IsInitStatus(status) {
+		status = strings.TrimPrefix(status, "Init:")
+		// Init:N/M is progress, not a failure
+		if !strings.Contains(status, "/") {
+			container := FailingInitContainer(pod)
+			if container == "" {
+				container = "the init container"
+			}
+			helpers = append(helpers, DebugHelper{
+				Issue:    "Init Container Failing",
+				Severity: "High",
+				Suggestions: []string{
+					fmt.Sprintf("Check the logs of %s (switch containers with [ and ] in the logs panel)", container),
+					"Verify the services, secrets or volumes the init container waits on exist",
+					"Check the init container's command and image",
+				},
+			})
+		}
+	}
+
 	// Check pod status for common problems
-	switch pod.Status {
+	switch status {
 	case "CrashLoopBackOff":
 		helpers = append(helpers, DebugHelper{
 			Issue:    "CrashLoopBackOff",
@@ -135,6 +168,19 @@ func AnalyzePodIssues(pod *PodInfo, events []EventInfo) []DebugHelper {
 		})
 	}
 
+	// Scheduling gates keep a pod Pending without producing any events
+	for _, gate := range pod.SchedulingGates {
+		helpers = append(helpers, DebugHelper{
+			Issue:    "Scheduling Gated",
+			Severity: "High",
+			Suggestions: []string{
+				fmt.Sprintf("Pod has scheduling gate '%s' — a controller must remove it", gate),
+				"Check that the controller owning this gate is running",
+				"Remove the gate manually from Pod Actions if the controller is broken",
+			},
+		})
+	}
+
 	// Check for missing resource limits (best practice warnings)
 	for _, c := range pod.Containers {
 		if c.Resources.MemoryLimit == "0" || c.Resources.MemoryLimit == "" {
@@ -53,6 +53,30 @@ func TestFormatAge(t *testing.T) {
 	}
 }
 
+func TestFormatAge_Boundaries(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{59 * time.Second, "59s"},
+		{60 * time.Second, "1m"},
+		{61 * time.Second, "1m"},
+		{59*time.Minute + 59*time.Second, "59m"},
+		{time.Hour, "1h"},
+		{23*time.Hour + 59*time.Minute, "23h"},
+		{25 * time.Hour, "1d"},
+		{48 * time.Hour, "2d"},
+		{-5 * time.Second, "0s"}, // clock skew
+	}
+	for _, tt := range tests {
+		if got := FormatAge(now.Add(-tt.ago), now); got != tt.want {
+			t.Errorf("FormatAge(%v ago) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+}
+
 func TestTruncateString(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -277,6 +301,42 @@ func TestAnalyzePodIssues(t *testing.T) {
 			},
 			expectIssues: []string{"Pod Pending", "Scheduling Failed"},
 		},
+		{
+			name: "scheduling gated pod",
+			pod: &PodInfo{
+				Status:          "Pending",
+				Containers:      []ContainerInfo{},
+				SchedulingGates: []string{"example.com/infra-approval"},
+			},
+			events:       []EventInfo{},
+			expectIssues: []string{"Pod Pending", "Scheduling Gated"},
+			expectSeverity: map[string]string{
+				"Scheduling Gated": "High",
+			},
+		},
+		{
+			name: "init container in crash loop",
+			pod: &PodInfo{
+				Status: "Init:CrashLoopBackOff",
+				InitContainers: []ContainerInfo{
+					{Name: "migrate", State: "Waiting", Reason: "CrashLoopBackOff"},
+				},
+			},
+			events:       []EventInfo{},
+			expectIssues: []string{"Init Container Failing", "CrashLoopBackOff"},
+			expectSeverity: map[string]string{
+				"Init Container Failing": "High",
+				"CrashLoopBackOff":       "High",
+			},
+		},
+		{
+			name: "init in progress no issues",
+			pod: &PodInfo{
+				Status: "Init:1/2",
+			},
+			events:       []EventInfo{},
+			expectIssues: []string{},
+		},
 		{
 			name: "healthy pod no issues",
 			pod: &PodInfo{
@@ -336,6 +396,32 @@ func TestAnalyzePodIssues(t *testing.T) {
 	}
 }
 
+func TestAnalyzePodIssues_SchedulingGateMessage(t *testing.T) {
+	pod := &PodInfo{
+		Status:          "Pending",
+		SchedulingGates: []string{"example.com/infra-approval", "example.com/quota"},
+	}
+
+	helpers := AnalyzePodIssues(pod, nil)
+
+	var gated []DebugHelper
+	for _, h := range helpers {
+		if h.Issue == "Scheduling Gated" {
+			gated = append(gated, h)
+		}
+	}
+	if len(gated) != 2 {
+		t.Fatalf("got %d Scheduling Gated helpers, want 2", len(gated))
+	}
+	want := "Pod has scheduling gate 'example.com/infra-approval' — a controller must remove it"
+	if gated[0].Suggestions[0] != want {
+		t.Errorf("Suggestions[0] = %q, want %q", gated[0].Suggestions[0], want)
+	}
+	if !containsSubstring(gated[1].Suggestions[0], "example.com/quota") {
+		t.Errorf("Suggestions[0] = %q, want it to mention example.com/quota", gated[1].Suggestions[0])
+	}
+}
+
 func containsSubstring(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
 		(len(s) > 0 && containsStr(s, substr)))
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WatchCondition is what a watch waits for.
+type WatchCondition string
+
+const (
+	// WatchReady fires once the pod, or every pod of the workload, is Ready
+	WatchReady WatchCondition = "ready"
+	// WatchWarning fires on the next Warning event about the pod, or about
+	// the workload or one of its pods
+	WatchWarning WatchCondition = "warning"
+)
+
+// Label names the condition for lists and notifications.
+func (c WatchCondition) Label() string {
+	switch c {
+	case WatchReady:
+		return "Ready"
+	case WatchWarning:
+		return "Next warning"
+	}
+	return string(c)
+}
+
+// Watch waits for a condition on a pod or a workload. The app checks it on
+// every refresh, over the pods and events it fetched, until it fires or is
+// cancelled.
+type Watch struct {
+	ID        int
+	Condition WatchCondition
+	Target    WorkloadInfo // The workload watched, or the pod with Type ResourcePods
+	Since     time.Time    // When the watch was set: older events don't fire it
+}
+
+// TargetName is the watched object as "pod/web-1" or "deployment/web".
+func (w Watch) TargetName() string {
+	kind := KindForResourceType(w.Target.Type)
+	if kind == "" {
+		kind = string(w.Target.Type)
+	}
+	return strings.ToLower(kind) + "/" + w.Target.Name
+}
+
+// Age is how long ago the watch was set, e.g. "5m".
+func (w Watch) Age() string {
+	return formatAge(w.Since)
+}
+
+// EvaluateWatch reports whether the condition of w holds, given the pods of
+// its target and the events of its namespace, and says why for the
+// notification.
+func EvaluateWatch(w Watch, pods []PodInfo, events []EventInfo) (bool, string) {
+	switch w.Condition {
+	case WatchReady:
+		return evaluateReady(w, pods)
+	case WatchWarning:
+		return evaluateWarning(w, pods, events)
+	}
+	return false, ""
+}
+
+// evaluateReady holds once a pod is Ready, or once a workload has at least
+// its desired replicas, all Ready. Pods being deleted are left out, so the
+// old pods of a rolling update don't count.
+func evaluateReady(w Watch, pods []PodInfo) (bool, string) {
+	total, ready := 0, 0
+	for _, pod := range pods {
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		total++
+		if PodInfoReady(pod) {
+			ready++
+		}
+	}
+	if total == 0 || ready < total {
+		return false, ""
+	}
+	if w.Target.Type == ResourcePods {
+		return true, fmt.Sprintf("%s is Ready", w.TargetName())
+	}
+	if int32(ready) < w.Target.Replicas {
+		return false, ""
+	}
+	return true, fmt.Sprintf("%s is Ready: %d/%d pods", w.TargetName(), ready, total)
+}
+
+// PodInfoReady reports whether the pod's Ready condition is true.
+func PodInfoReady(pod PodInfo) bool {
+	for _, c := range pod.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// evaluateWarning holds once a Warning event about the target or one of
+// its pods was last seen after the watch was set. The latest one is
+// reported.
+func evaluateWarning(w Watch, pods []PodInfo, events []EventInfo) (bool, string) {
+	objects := map[string]bool{}
+	if kind := KindForResourceType(w.Target.Type); kind != "" {
+		objects[kind+"/"+w.Target.Name] = true
+	}
+	for _, pod := range pods {
+		objects["Pod/"+pod.Name] = true
+	}
+
+	var latest *EventInfo
+	for i, e := range events {
+		if !IsWarningEvent(e) || !objects[e.Object] || !e.LastSeen.After(w.Since) {
+			continue
+		}
+		if latest == nil || e.LastSeen.After(latest.LastSeen) {
+			latest = &events[i]
+		}
+	}
+	if latest == nil {
+		return false, ""
+	}
+	return true, fmt.Sprintf("%s %s: %s", latest.Object, latest.Reason, latest.Message)
+}
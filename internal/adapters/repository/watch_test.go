@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func watchPod(name string, ready bool) PodInfo {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return PodInfo{Name: name, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}}
+}
+
+func TestEvaluateWatch_Ready(t *testing.T) {
+	deployment := WorkloadInfo{Name: "web", Type: ResourceDeployments, Replicas: 2}
+	terminating := watchPod("web-old", false)
+	terminating.DeletionTimestamp = time.Now()
+
+	tests := []struct {
+		name       string
+		target     WorkloadInfo
+		pods       []PodInfo
+		wantFired  bool
+		wantDetail string
+	}{
+		{"pod ready", WorkloadInfo{Name: "web-1", Type: ResourcePods}, []PodInfo{watchPod("web-1", true)}, true, "pod/web-1 is Ready"},
+		{"pod not ready", WorkloadInfo{Name: "web-1", Type: ResourcePods}, []PodInfo{watchPod("web-1", false)}, false, ""},
+		{"pod without conditions", WorkloadInfo{Name: "web-1", Type: ResourcePods}, []PodInfo{{Name: "web-1"}}, false, ""},
+		{"no pods", deployment, nil, false, ""},
+		{"all replicas ready", deployment, []PodInfo{watchPod("web-1", true), watchPod("web-2", true)}, true, "deployment/web is Ready: 2/2 pods"},
+		{"one not ready", deployment, []PodInfo{watchPod("web-1", true), watchPod("web-2", false)}, false, ""},
+		{"fewer than replicas", deployment, []PodInfo{watchPod("web-1", true)}, false, ""},
+		{"terminating pod left out", deployment, []PodInfo{watchPod("web-1", true), watchPod("web-2", true), terminating}, true, "deployment/web is Ready: 2/2 pods"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := Watch{Condition: WatchReady, Target: tt.target}
+			fired, detail := EvaluateWatch(w, tt.pods, nil)
+			if fired != tt.wantFired || detail != tt.wantDetail {
+				t.Errorf("EvaluateWatch() = %v, %q; want %v, %q", fired, detail, tt.wantFired, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestEvaluateWatch_Warning(t *testing.T) {
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	event := func(object, typ, reason string, at time.Duration) EventInfo {
+		return EventInfo{Object: object, Type: typ, Reason: reason, Message: reason + " message", LastSeen: since.Add(at)}
+	}
+	deployment := WorkloadInfo{Name: "web", Type: ResourceDeployments}
+	pods := []PodInfo{{Name: "web-1"}}
+
+	tests := []struct {
+		name       string
+		target     WorkloadInfo
+		events     []EventInfo
+		wantFired  bool
+		wantDetail string
+	}{
+		{"no events", deployment, nil, false, ""},
+		{"warning on a pod", deployment, []EventInfo{event("Pod/web-1", "Warning", "BackOff", time.Minute)}, true, "Pod/web-1 BackOff: BackOff message"},
+		{"warning on the workload", deployment, []EventInfo{event("Deployment/web", "Warning", "ReplicaFailure", time.Second)}, true, "Deployment/web ReplicaFailure: ReplicaFailure message"},
+		{"warning before the watch", deployment, []EventInfo{event("Pod/web-1", "Warning", "BackOff", -time.Minute)}, false, ""},
+		{"normal event", deployment, []EventInfo{event("Pod/web-1", "Normal", "Pulled", time.Minute)}, false, ""},
+		{"other pod", deployment, []EventInfo{event("Pod/api-1", "Warning", "BackOff", time.Minute)}, false, ""},
+		{"latest reported", deployment, []EventInfo{
+			event("Pod/web-1", "Warning", "Unhealthy", time.Minute),
+			event("Pod/web-1", "Warning", "BackOff", 2*time.Minute),
+		}, true, "Pod/web-1 BackOff: BackOff message"},
+		{"pod target", WorkloadInfo{Name: "web-1", Type: ResourcePods}, []EventInfo{event("Pod/web-1", "Warning", "Failed", time.Minute)}, true, "Pod/web-1 Failed: Failed message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := Watch{Condition: WatchWarning, Target: tt.target, Since: since}
+			fired, detail := EvaluateWatch(w, pods, tt.events)
+			if fired != tt.wantFired || detail != tt.wantDetail {
+				t.Errorf("EvaluateWatch() = %v, %q; want %v, %q", fired, detail, tt.wantFired, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestWatch_TargetName(t *testing.T) {
+	w := Watch{Condition: WatchWarning, Target: WorkloadInfo{Name: "web", Type: ResourceStatefulSets}}
+	if got := w.TargetName(); got != "statefulset/web" {
+		t.Errorf("TargetName() = %q, want statefulset/web", got)
+	}
+	if got := w.Condition.Label(); !strings.Contains(got, "warning") {
+		t.Errorf("Label() = %q", got)
+	}
+}
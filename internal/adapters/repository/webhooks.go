@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// WebhookInfo is an admission webhook that may intercept requests for the
+// objects of a namespace.
+type WebhookInfo struct {
+	Configuration string // Name of the Validating/MutatingWebhookConfiguration
+	Kind          string // "Validating" or "Mutating"
+	Name          string // Name of the webhook, as in "admission webhook ... denied" errors
+	FailurePolicy string // Fail or Ignore; Fail rejects requests while the webhook is down
+	Timeout       int32  // Seconds the API server waits for the webhook
+	Backend       string // Service as namespace/name:port/path, or the URL it calls
+	Resources     []string
+	Operations    []string
+}
+
+// webhookDenial matches the error the API server returns when a webhook
+// rejects a request, e.g. `admission webhook "validate.kyverno.svc" denied
+// the request: ...`.
+var webhookDenial = regexp.MustCompile(`admission webhook "([^"]+)" denied`)
+
+// ListWebhookConfigurations lists the validating and mutating admission
+// webhooks whose namespaceSelector matches namespace and whose
+// objectSelector matches podLabels; a nil podLabels matches any
+// objectSelector. Validating webhooks come first, then by name.
+func ListWebhookConfigurations(ctx context.Context, clientset kubernetes.Interface, namespace string, podLabels map[string]string) ([]WebhookInfo, error) {
+	// Every namespace carries its name as a label, which is all there is
+	// to match without access to the namespace
+	nsLabels := map[string]string{"kubernetes.io/metadata.name": namespace}
+	if ns, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err == nil && ns.Labels != nil {
+		nsLabels = ns.Labels
+	}
+
+	validating, err := clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list validating webhooks: %w", err)
+	}
+	mutating, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mutating webhooks: %w", err)
+	}
+
+	var webhooks []WebhookInfo
+	for _, cfg := range validating.Items {
+		for _, w := range cfg.Webhooks {
+			if WebhookSelects(w.NamespaceSelector, w.ObjectSelector, nsLabels, podLabels) {
+				webhooks = append(webhooks, webhookInfo(cfg.Name, "Validating", w.Name, w.ClientConfig, w.Rules, w.FailurePolicy, w.TimeoutSeconds))
+			}
+		}
+	}
+	for _, cfg := range mutating.Items {
+		for _, w := range cfg.Webhooks {
+			if WebhookSelects(w.NamespaceSelector, w.ObjectSelector, nsLabels, podLabels) {
+				webhooks = append(webhooks, webhookInfo(cfg.Name, "Mutating", w.Name, w.ClientConfig, w.Rules, w.FailurePolicy, w.TimeoutSeconds))
+			}
+		}
+	}
+	sort.SliceStable(webhooks, func(i, j int) bool {
+		if webhooks[i].Kind != webhooks[j].Kind {
+			return webhooks[i].Kind == "Validating"
+		}
+		return webhooks[i].Name < webhooks[j].Name
+	})
+	return webhooks, nil
+}
+
+// WebhookSelects reports whether a webhook with the given selectors
+// intercepts requests for an object labeled podLabels in a namespace
+// labeled nsLabels. A nil selector, like an empty one, matches everything;
+// a nil podLabels matches any objectSelector, for the objects of the
+// namespace in general. An invalid selector matches nothing, as the API
+// server would reject it.
+func WebhookSelects(namespaceSelector, objectSelector *metav1.LabelSelector, nsLabels, podLabels map[string]string) bool {
+	if !labelSelectorMatches(namespaceSelector, nsLabels) {
+		return false
+	}
+	return podLabels == nil || labelSelectorMatches(objectSelector, podLabels)
+}
+
+func labelSelectorMatches(selector *metav1.LabelSelector, set map[string]string) bool {
+	if selector == nil {
+		return true
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(labels.Set(set))
+}
+
+// DeniedWebhook returns the name of the admission webhook that denied the
+// request a message reports, "" when it reports no denial.
+func DeniedWebhook(message string) string {
+	if m := webhookDenial.FindStringSubmatch(message); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// webhookInfo summarizes a webhook of either kind, filling in the API
+// server's defaults for the failure policy and timeout.
+func webhookInfo(configuration, kind, name string, client admissionregistrationv1.WebhookClientConfig, rules []admissionregistrationv1.RuleWithOperations, policy *admissionregistrationv1.FailurePolicyType, timeout *int32) WebhookInfo {
+	info := WebhookInfo{
+		Configuration: configuration,
+		Kind:          kind,
+		Name:          name,
+		FailurePolicy: string(admissionregistrationv1.Fail),
+		Timeout:       10,
+	}
+	if policy != nil {
+		info.FailurePolicy = string(*policy)
+	}
+	if timeout != nil {
+		info.Timeout = *timeout
+	}
+
+	switch {
+	case client.Service != nil:
+		svc := client.Service
+		info.Backend = svc.Namespace + "/" + svc.Name
+		if svc.Port != nil {
+			info.Backend += fmt.Sprintf(":%d", *svc.Port)
+		}
+		if svc.Path != nil {
+			info.Backend += *svc.Path
+		}
+	case client.URL != nil:
+		info.Backend = *client.URL
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range rules {
+		for _, op := range r.Operations {
+			if !seen["op:"+string(op)] {
+				seen["op:"+string(op)] = true
+				info.Operations = append(info.Operations, string(op))
+			}
+		}
+		for _, res := range r.Resources {
+			if !seen["res:"+res] {
+				seen["res:"+res] = true
+				info.Resources = append(info.Resources, res)
+			}
+		}
+	}
+	return info
+}
+
+// Rules summarizes what the webhook intercepts, e.g. "CREATE,UPDATE pods".
+func (w WebhookInfo) Rules() string {
+	return strings.TrimSpace(strings.Join(w.Operations, ",") + " " + strings.Join(w.Resources, ","))
+}
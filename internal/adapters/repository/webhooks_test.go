@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWebhookSelects(t *testing.T) {
+	nsLabels := map[string]string{"kubernetes.io/metadata.name": "shop", "env": "prod"}
+	podLabels := map[string]string{"app": "web"}
+	skipSystem := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "kubernetes.io/metadata.name", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"kube-system"}},
+	}}
+
+	tests := []struct {
+		name              string
+		namespaceSelector *metav1.LabelSelector
+		objectSelector    *metav1.LabelSelector
+		podLabels         map[string]string
+		want              bool
+	}{
+		{"no selectors", nil, nil, podLabels, true},
+		{"empty selectors", &metav1.LabelSelector{}, &metav1.LabelSelector{}, podLabels, true},
+		{"namespace label", &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}, nil, podLabels, true},
+		{"other namespace label", &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}}, nil, podLabels, false},
+		{"namespace expression", skipSystem, nil, podLabels, true},
+		{"object label", nil, &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}, podLabels, true},
+		{"other object label", nil, &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}, podLabels, false},
+		{"object label without a pod", nil, &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}, nil, true},
+		{"invalid selector", &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Near"}}}, nil, podLabels, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WebhookSelects(tt.namespaceSelector, tt.objectSelector, nsLabels, tt.podLabels); got != tt.want {
+				t.Errorf("WebhookSelects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeniedWebhook(t *testing.T) {
+	msg := `Error creating: admission webhook "validate.kyverno.svc-fail" denied the request: policy require-labels failed`
+	if got := DeniedWebhook(msg); got != "validate.kyverno.svc-fail" {
+		t.Errorf("DeniedWebhook() = %q, want validate.kyverno.svc-fail", got)
+	}
+	if got := DeniedWebhook("Created container app"); got != "" {
+		t.Errorf("DeniedWebhook() = %q, want none", got)
+	}
+}
+
+func TestListWebhookConfigurations(t *testing.T) {
+	ignore := admissionregistrationv1.Ignore
+	timeout := int32(3)
+	port := int32(443)
+	path := "/validate"
+	none := admissionregistrationv1.SideEffectClassNone
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"kubernetes.io/metadata.name": "shop", "policy": "enforced"}}},
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{
+				{
+					Name:              "validate.policy.example.com",
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"policy": "enforced"}},
+					ClientConfig: admissionregistrationv1.WebhookClientConfig{
+						Service: &admissionregistrationv1.ServiceReference{Namespace: "policy", Name: "webhook", Port: &port, Path: &path},
+					},
+					Rules: []admissionregistrationv1.RuleWithOperations{{
+						Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+						Rule:       admissionregistrationv1.Rule{Resources: []string{"pods"}},
+					}},
+					SideEffects: &none,
+				},
+				{
+					Name:              "validate.dev-only.example.com",
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+					SideEffects:       &none,
+				},
+			},
+		},
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "sidecars"},
+			Webhooks: []admissionregistrationv1.MutatingWebhook{{
+				Name:           "inject.sidecars.example.com",
+				ObjectSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				ClientConfig:   admissionregistrationv1.WebhookClientConfig{URL: func() *string { u := "https://sidecars.example.com/inject"; return &u }()},
+				FailurePolicy:  &ignore,
+				TimeoutSeconds: &timeout,
+				SideEffects:    &none,
+			}},
+		},
+	)
+
+	webhooks, err := ListWebhookConfigurations(context.Background(), clientset, "shop", map[string]string{"app": "web"})
+	if err != nil {
+		t.Fatalf("ListWebhookConfigurations failed: %v", err)
+	}
+	if len(webhooks) != 2 {
+		t.Fatalf("got %d webhooks, want the two matching shop: %+v", len(webhooks), webhooks)
+	}
+	policy, sidecars := webhooks[0], webhooks[1]
+	if policy.Name != "validate.policy.example.com" || policy.Kind != "Validating" || policy.FailurePolicy != "Fail" || policy.Timeout != 10 {
+		t.Errorf("policy = %+v, want the validating webhook with the default policy and timeout", policy)
+	}
+	if policy.Backend != "policy/webhook:443/validate" || policy.Rules() != "CREATE,UPDATE pods" {
+		t.Errorf("policy backend = %q, rules = %q", policy.Backend, policy.Rules())
+	}
+	if sidecars.Kind != "Mutating" || sidecars.FailurePolicy != "Ignore" || sidecars.Timeout != 3 || sidecars.Backend != "https://sidecars.example.com/inject" {
+		t.Errorf("sidecars = %+v, want the mutating webhook with its URL", sidecars)
+	}
+
+	webhooks, err = ListWebhookConfigurations(context.Background(), clientset, "shop", map[string]string{"app": "db"})
+	if err != nil || len(webhooks) != 1 {
+		t.Errorf("got %d webhooks (%v), want only the namespace-wide one for other pods", len(webhooks), err)
+	}
+}
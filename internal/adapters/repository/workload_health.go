@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// PodHealthClass is the single bucket ClassifyPod puts a pod in.
+type PodHealthClass string
+
+const (
+	HealthReady       PodHealthClass = "Ready"
+	HealthCrashLoop   PodHealthClass = "CrashLoop"
+	HealthImagePull   PodHealthClass = "ImagePull"
+	HealthOOMKilled   PodHealthClass = "OOMKilled"
+	HealthPending     PodHealthClass = "Pending"
+	HealthNotReady    PodHealthClass = "NotReady"    // Running with a container not ready
+	HealthCompleted   PodHealthClass = "Completed"   // Succeeded, as Job pods end
+	HealthTerminating PodHealthClass = "Terminating" // Being deleted, e.g. during a rollout
+)
+
+// healthSeverity orders classes from healthy to broken; WorstPod picks the
+// pod with the highest.
+var healthSeverity = map[PodHealthClass]int{
+	HealthCompleted:   0,
+	HealthTerminating: 0,
+	HealthReady:       1,
+	HealthNotReady:    2,
+	HealthPending:     3,
+	HealthCrashLoop:   4,
+	HealthOOMKilled:   4,
+	HealthImagePull:   4,
+}
+
+// PodHealth is the breakdown of the pods of a workload by health class.
+// Completed and terminating pods are not counted.
+type PodHealth struct {
+	Total     int
+	Ready     int
+	CrashLoop int
+	ImagePull int
+	OOMKilled int
+	Pending   int
+	RecentOOM int // Pods back up after an OOM kill within RecentOOMWindow
+}
+
+// ClassifyPod puts a pod in a single health class. Causes the pod can't
+// recover from on its own win over a plain not ready: an image that can't
+// be pulled, then an OOM kill (also when it led to a crash loop, as it is
+// the more useful cause), then a crash loop, then Pending.
+func ClassifyPod(pod PodInfo) PodHealthClass {
+	if pod.Status == "Terminating" {
+		return HealthTerminating
+	}
+	containers := append(append([]ContainerInfo{}, pod.InitContainers...), pod.Containers...)
+	for _, c := range containers {
+		if c.State == "Waiting" && imagePullReasons[c.Reason] {
+			return HealthImagePull
+		}
+	}
+	for _, c := range containers {
+		if t := crashTermination(c); t != nil && t.Reason == "OOMKilled" {
+			return HealthOOMKilled
+		}
+	}
+	for _, c := range containers {
+		if c.State == "Waiting" && c.Reason == "CrashLoopBackOff" {
+			return HealthCrashLoop
+		}
+	}
+
+	switch pod.Phase {
+	case "Pending":
+		return HealthPending
+	case "Succeeded":
+		return HealthCompleted
+	}
+	if len(pod.Containers) == 0 {
+		return HealthNotReady
+	}
+	for _, c := range pod.Containers {
+		if !c.Ready {
+			return HealthNotReady
+		}
+	}
+	return HealthReady
+}
+
+// SummarizePodHealth counts pods by health class, and the pods of the
+// other classes that were OOM killed within RecentOOMWindow.
+func SummarizePodHealth(pods []PodInfo) PodHealth {
+	return summarizePodHealth(pods, time.Now())
+}
+
+func summarizePodHealth(pods []PodInfo, now time.Time) PodHealth {
+	var h PodHealth
+	for _, pod := range pods {
+		class := ClassifyPod(pod)
+		if class == HealthCompleted || class == HealthTerminating {
+			continue
+		}
+		h.Total++
+		switch class {
+		case HealthReady:
+			h.Ready++
+		case HealthCrashLoop:
+			h.CrashLoop++
+		case HealthImagePull:
+			h.ImagePull++
+		case HealthOOMKilled:
+			h.OOMKilled++
+		case HealthPending:
+			h.Pending++
+		}
+		if class != HealthOOMKilled && recentlyOOMKilled(pod, now) {
+			h.RecentOOM++
+		}
+	}
+	return h
+}
+
+// Healthy reports whether every counted pod is ready.
+func (h PodHealth) Healthy() bool {
+	return h.Ready == h.Total
+}
+
+// Failing reports whether a pod is crash looping, OOM killed or can't pull
+// its image.
+func (h PodHealth) Failing() bool {
+	return h.CrashLoop+h.ImagePull+h.OOMKilled > 0
+}
+
+// String returns a compact summary, e.g. "3/5 ready · 1 CrashLoop · 1 Pending",
+// or "3/3 ready · 1 recent OOM". Classes without pods are left out.
+func (h PodHealth) String() string {
+	parts := []string{fmt.Sprintf("%d/%d ready", h.Ready, h.Total)}
+	for _, c := range []struct {
+		count int
+		class PodHealthClass
+	}{
+		{h.CrashLoop, HealthCrashLoop},
+		{h.OOMKilled, HealthOOMKilled},
+		{h.ImagePull, HealthImagePull},
+		{h.Pending, HealthPending},
+	} {
+		if c.count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", c.count, c.class))
+		}
+	}
+	if h.RecentOOM > 0 {
+		parts = append(parts, fmt.Sprintf("%d recent OOM", h.RecentOOM))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// WorstPod returns the pod most worth looking at: the most severe health
+// class first, then the most restarts, then by name so the choice is
+// stable. Returns nil for no pods.
+func WorstPod(pods []PodInfo) *PodInfo {
+	if len(pods) == 0 {
+		return nil
+	}
+	sorted := append([]PodInfo{}, pods...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, sj := healthSeverity[ClassifyPod(sorted[i])], healthSeverity[ClassifyPod(sorted[j])]
+		if si != sj {
+			return si > sj
+		}
+		if sorted[i].Restarts != sorted[j].Restarts {
+			return sorted[i].Restarts > sorted[j].Restarts
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return &sorted[0]
+}
+
+// AttachPodHealth sets the Health of each workload from the pods its
+// selector matches among pods, which are expected to be the pods of the
+// workloads' namespace, along with the restarts and nodes of those pods.
+// Pods and workloads without a selector are left alone.
+func AttachPodHealth(workloads []WorkloadInfo, pods []PodInfo) {
+	for i := range workloads {
+		w := &workloads[i]
+		if w.Type == ResourcePods || len(w.Labels) == 0 {
+			continue
+		}
+		var matched []PodInfo
+		var restarts int32
+		nodes := make(map[string]bool)
+		for _, pod := range pods {
+			if pod.Namespace == w.Namespace && labelsMatch(w.Labels, pod.Labels) {
+				matched = append(matched, pod)
+				restarts += pod.Restarts
+				if pod.Node != "" {
+					nodes[pod.Node] = true
+				}
+			}
+		}
+		health := SummarizePodHealth(matched)
+		w.Health = &health
+		w.RestartCount = restarts
+		w.Nodes = make([]string, 0, len(nodes))
+		for node := range nodes {
+			w.Nodes = append(w.Nodes, node)
+		}
+		sort.Strings(w.Nodes)
+	}
+}
+
+// LoadPodHealth lists the pods of namespace and attaches their breakdown
+// to workloads, see AttachPodHealth. Lists nothing for a list of pods.
+func LoadPodHealth(ctx context.Context, clientset kubernetes.Interface, namespace string, workloads []WorkloadInfo) error {
+	needed := false
+	for _, w := range workloads {
+		needed = needed || (w.Type != ResourcePods && len(w.Labels) > 0)
+	}
+	if !needed {
+		return nil
+	}
+	pods, err := ListAllPods(ctx, clientset, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	AttachPodHealth(workloads, pods)
+	return nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func readyPod(name string, restarts int32) PodInfo {
+	return PodInfo{
+		Name:       name,
+		Namespace:  "default",
+		Labels:     map[string]string{"app": "web"},
+		Phase:      corev1.PodRunning,
+		Restarts:   restarts,
+		Containers: []ContainerInfo{{Name: "app", State: "Running", Ready: true, RestartCount: restarts}},
+	}
+}
+
+func waitingPod(name, reason string, restarts int32) PodInfo {
+	pod := readyPod(name, restarts)
+	pod.Containers = []ContainerInfo{{Name: "app", State: "Waiting", Reason: reason, RestartCount: restarts}}
+	return pod
+}
+
+func pendingPod(name string) PodInfo {
+	pod := readyPod(name, 0)
+	pod.Phase = corev1.PodPending
+	pod.Containers = []ContainerInfo{{Name: "app"}}
+	return pod
+}
+
+func oomPod(name string) PodInfo {
+	pod := waitingPod(name, "CrashLoopBackOff", 4)
+	pod.Containers[0].LastTermination = &TerminationInfo{Reason: "OOMKilled", ExitCode: 137}
+	return pod
+}
+
+func TestClassifyPod(t *testing.T) {
+	completed := readyPod("job-x", 0)
+	completed.Phase = corev1.PodSucceeded
+	completed.Containers[0] = ContainerInfo{Name: "app", State: "Terminated", Reason: "Completed", ExitCode: new(int32)}
+	terminating := readyPod("old", 0)
+	terminating.Status = "Terminating"
+	notReady := readyPod("warming-up", 0)
+	notReady.Containers[0].Ready = false
+	pullingInit := pendingPod("init-pull")
+	pullingInit.InitContainers = []ContainerInfo{{Name: "migrate", State: "Waiting", Reason: "ImagePullBackOff"}}
+
+	tests := []struct {
+		name string
+		pod  PodInfo
+		want PodHealthClass
+	}{
+		{"ready", readyPod("ok", 2), HealthReady},
+		{"crash loop", waitingPod("crash", "CrashLoopBackOff", 7), HealthCrashLoop},
+		{"image pull", waitingPod("pull", "ErrImagePull", 0), HealthImagePull},
+		{"image pull of an init container", pullingInit, HealthImagePull},
+		{"crash loop after an OOM kill", oomPod("oom"), HealthOOMKilled},
+		{"pending", pendingPod("pending"), HealthPending},
+		{"not ready", notReady, HealthNotReady},
+		{"completed", completed, HealthCompleted},
+		{"terminating", terminating, HealthTerminating},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyPod(tt.pod); got != tt.want {
+				t.Errorf("ClassifyPod() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizePodHealth(t *testing.T) {
+	completed := readyPod("done", 0)
+	completed.Phase = corev1.PodSucceeded
+	pods := []PodInfo{
+		readyPod("web-1", 0),
+		readyPod("web-2", 0),
+		readyPod("web-3", 1),
+		waitingPod("web-4", "CrashLoopBackOff", 9),
+		pendingPod("web-5"),
+		completed,
+	}
+
+	h := SummarizePodHealth(pods)
+	want := PodHealth{Total: 5, Ready: 3, CrashLoop: 1, Pending: 1}
+	if h != want {
+		t.Fatalf("SummarizePodHealth() = %+v, want %+v", h, want)
+	}
+	if got := h.String(); got != "3/5 ready · 1 CrashLoop · 1 Pending" {
+		t.Errorf("String() = %q", got)
+	}
+	if h.Healthy() || !h.Failing() {
+		t.Errorf("Healthy() = %v, Failing() = %v, want not healthy and failing", h.Healthy(), h.Failing())
+	}
+
+	h = SummarizePodHealth([]PodInfo{readyPod("a", 0), readyPod("b", 0)})
+	if got := h.String(); got != "2/2 ready" || !h.Healthy() || h.Failing() {
+		t.Errorf("all ready: String() = %q, Healthy() = %v, Failing() = %v", got, h.Healthy(), h.Failing())
+	}
+
+	h = SummarizePodHealth([]PodInfo{oomPod("a"), waitingPod("b", "ImagePullBackOff", 0)})
+	if got := h.String(); got != "0/2 ready · 1 OOMKilled · 1 ImagePull" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestSummarizePodHealth_RecentOOM(t *testing.T) {
+	now := time.Date(2024, 5, 16, 10, 0, 0, 0, time.UTC)
+	recovered := readyPod("web-1", 1)
+	recovered.Containers[0].LastTermination = &TerminationInfo{Reason: "OOMKilled", ExitCode: 137, Finished: now.Add(-10 * time.Minute)}
+	longAgo := readyPod("web-2", 1)
+	longAgo.Containers[0].LastTermination = &TerminationInfo{Reason: "OOMKilled", ExitCode: 137, Finished: now.Add(-3 * time.Hour)}
+	crashed := readyPod("web-3", 1)
+	crashed.Containers[0].LastTermination = &TerminationInfo{Reason: "Error", ExitCode: 1, Finished: now.Add(-time.Minute)}
+
+	h := summarizePodHealth([]PodInfo{recovered, longAgo, crashed, oomPod("web-4")}, now)
+	want := PodHealth{Total: 4, Ready: 3, OOMKilled: 1, RecentOOM: 1}
+	if h != want {
+		t.Fatalf("summarizePodHealth() = %+v, want %+v", h, want)
+	}
+	if got := h.String(); got != "3/4 ready · 1 OOMKilled · 1 recent OOM" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestWorstPod(t *testing.T) {
+	if WorstPod(nil) != nil {
+		t.Error("WorstPod(nil) should be nil")
+	}
+
+	tests := []struct {
+		name string
+		pods []PodInfo
+		want string
+	}{
+		{
+			"failing beats restarts",
+			[]PodInfo{readyPod("a", 40), waitingPod("b", "CrashLoopBackOff", 3), pendingPod("c")},
+			"b",
+		},
+		{
+			"pending beats ready",
+			[]PodInfo{readyPod("a", 5), pendingPod("b")},
+			"b",
+		},
+		{
+			"most restarts among the same class",
+			[]PodInfo{readyPod("a", 1), readyPod("b", 12), readyPod("c", 3)},
+			"b",
+		},
+		{
+			"name breaks ties",
+			[]PodInfo{waitingPod("z", "CrashLoopBackOff", 2), waitingPod("m", "ErrImagePull", 2)},
+			"m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WorstPod(tt.pods); got == nil || got.Name != tt.want {
+				t.Errorf("WorstPod() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachPodHealth(t *testing.T) {
+	api := readyPod("api-1", 0)
+	api.Labels = map[string]string{"app": "api"}
+	other := waitingPod("web-9", "CrashLoopBackOff", 1)
+	other.Namespace = "staging"
+	web1, web2 := readyPod("web-1", 0), waitingPod("web-2", "CrashLoopBackOff", 4)
+	web1.Node, web2.Node = "node-b", "node-a"
+	pods := []PodInfo{web1, web2, api, other}
+
+	workloads := []WorkloadInfo{
+		{Name: "web", Namespace: "default", Type: ResourceDeployments, Labels: map[string]string{"app": "web"}},
+		{Name: "idle", Namespace: "default", Type: ResourceDeployments, Labels: map[string]string{"app": "idle"}},
+		{Name: "external", Namespace: "default", Type: ResourceServices},
+		{Name: "web-1", Namespace: "default", Type: ResourcePods, Labels: map[string]string{"app": "web"}},
+	}
+	AttachPodHealth(workloads, pods)
+
+	if h := workloads[0].Health; h == nil || h.String() != "1/2 ready · 1 CrashLoop" {
+		t.Errorf("web health = %v, want 1/2 ready · 1 CrashLoop", h)
+	}
+	if w := workloads[0]; w.RestartCount != 4 || strings.Join(w.Nodes, ",") != "node-a,node-b" {
+		t.Errorf("web restarts = %d on %v, want 4 on node-a and node-b", w.RestartCount, w.Nodes)
+	}
+	if h := workloads[1].Health; h == nil || h.Total != 0 {
+		t.Errorf("idle health = %v, want an empty breakdown", h)
+	}
+	if workloads[2].Health != nil || workloads[3].Health != nil {
+		t.Error("workloads without a selector and pods should get no breakdown")
+	}
+}
@@ -0,0 +1,341 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ContainerImage is the image a container of a workload's pod template
+// runs.
+type ContainerImage struct {
+	Container string
+	Image     string
+	Init      bool // An init container
+}
+
+// RolloutProgress is how far a workload is in replacing its pods after a
+// change to its pod template.
+type RolloutProgress struct {
+	Desired  int32 // Pods the workload wants
+	Updated  int32 // Pods running the current template
+	Ready    int32
+	Total    int32 // Pods of every template, old ones included
+	Observed bool  // The controller has seen the latest change
+}
+
+// Done reports whether every pod runs the current template and is ready,
+// with the old pods gone.
+func (p RolloutProgress) Done() bool {
+	return p.Observed && p.Updated >= p.Desired && p.Ready >= p.Desired && p.Total <= p.Updated
+}
+
+// String returns the progress as "2/3 updated, 1/3 ready".
+func (p RolloutProgress) String() string {
+	return fmt.Sprintf("%d/%d updated, %d/%d ready", p.Updated, p.Desired, p.Ready, p.Desired)
+}
+
+// ValidateImage checks an image reference typed to replace a container's
+// image. An empty reference, or one with whitespace, is an error. A
+// reference without a tag or digest is allowed with a warning, since it
+// pulls whatever "latest" is at the time.
+func ValidateImage(image string) (warning string, err error) {
+	if image == "" {
+		return "", fmt.Errorf("image must not be empty")
+	}
+	if strings.ContainsAny(image, " \t\n") {
+		return "", fmt.Errorf("image %q contains whitespace", image)
+	}
+	if strings.Contains(image, "@") {
+		return "", nil
+	}
+	// A colon before the last slash is a registry port, not a tag
+	name := image[strings.LastIndex(image, "/")+1:]
+	if !strings.Contains(name, ":") {
+		return fmt.Sprintf("%s has no tag or digest and resolves to :latest", image), nil
+	}
+	return "", nil
+}
+
+// GetWorkloadImages returns the images of the containers of a workload's
+// pod template, init containers first as they run.
+func GetWorkloadImages(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, namespace, name string, resourceType ResourceType) ([]ContainerImage, error) {
+	if resourceType == ResourceRollouts {
+		rollout, err := fetchRollout(ctx, dynamicClient, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		return rolloutImages(rollout)
+	}
+	template, err := workloadTemplate(ctx, clientset, namespace, name, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	return templateImages(template.Spec), nil
+}
+
+// SetWorkloadImage changes the image of a container of a workload's pod
+// template, which rolls the new image out like kubectl set image.
+// Deployments, StatefulSets and DaemonSets get a strategic merge patch
+// keyed by container name. Rollouts are custom resources, which don't take
+// strategic merge patches, so they get a JSON patch that tests the
+// container's name at its index first.
+func SetWorkloadImage(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, namespace, name string, resourceType ResourceType, container, image string) error {
+	if _, err := ValidateImage(image); err != nil {
+		return err
+	}
+
+	if resourceType == ResourceRollouts {
+		rollout, err := fetchRollout(ctx, dynamicClient, namespace, name)
+		if err != nil {
+			return err
+		}
+		images, err := rolloutImages(rollout)
+		if err != nil {
+			return err
+		}
+		patch, err := buildRolloutImagePatch(images, container, image)
+		if err != nil {
+			return fmt.Errorf("rollout %s: %w", name, err)
+		}
+		_, err = dynamicClient.Resource(rolloutGVR).Namespace(namespace).Patch(ctx, name, types.JSONPatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to set image of rollout %s: %w", name, err)
+		}
+		return nil
+	}
+
+	template, err := workloadTemplate(ctx, clientset, namespace, name, resourceType)
+	if err != nil {
+		return err
+	}
+	patch, err := buildImagePatch(templateImages(template.Spec), container, image)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", KindForResourceType(resourceType), name, err)
+	}
+
+	apps := clientset.AppsV1()
+	switch resourceType {
+	case ResourceDeployments:
+		_, err = apps.Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case ResourceStatefulSets:
+		_, err = apps.StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case ResourceDaemonSets:
+		_, err = apps.DaemonSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set image of %s: %w", name, err)
+	}
+	return nil
+}
+
+// buildImagePatch builds the strategic merge patch setting the image of a
+// container, which merges into the container list by name.
+func buildImagePatch(images []ContainerImage, container, image string) ([]byte, error) {
+	c, _, err := findContainerImage(images, container)
+	if err != nil {
+		return nil, err
+	}
+	list := "containers"
+	if c.Init {
+		list = "initContainers"
+	}
+	patch := map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"spec": map[string]any{
+					list: []map[string]string{{"name": container, "image": image}},
+				},
+			},
+		},
+	}
+	return json.Marshal(patch)
+}
+
+// buildRolloutImagePatch builds a JSON patch replacing the image of a
+// container. The patch tests the container name at its index first, so a
+// concurrent change to the container list makes it fail instead of
+// changing another container.
+func buildRolloutImagePatch(images []ContainerImage, container, image string) ([]byte, error) {
+	c, index, err := findContainerImage(images, container)
+	if err != nil {
+		return nil, err
+	}
+	list := "containers"
+	if c.Init {
+		list = "initContainers"
+	}
+	path := fmt.Sprintf("/spec/template/spec/%s/%d", list, index)
+	ops := []map[string]string{
+		{"op": "test", "path": path + "/name", "value": container},
+		{"op": "replace", "path": path + "/image", "value": image},
+	}
+	return json.Marshal(ops)
+}
+
+// findContainerImage returns the named container and its index in its
+// list, init containers and containers being separate lists.
+func findContainerImage(images []ContainerImage, container string) (ContainerImage, int, error) {
+	index := map[bool]int{}
+	for _, c := range images {
+		if c.Container == container {
+			return c, index[c.Init], nil
+		}
+		index[c.Init]++
+	}
+	return ContainerImage{}, 0, fmt.Errorf("no container %q in the pod template", container)
+}
+
+// GetRolloutProgress returns how far a workload is in rolling out its pod
+// template, as kubectl rollout status judges it.
+func GetRolloutProgress(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, namespace, name string, resourceType ResourceType) (RolloutProgress, error) {
+	switch resourceType {
+	case ResourceDeployments:
+		d, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return RolloutProgress{}, fmt.Errorf("failed to get deployment: %w", err)
+		}
+		return RolloutProgress{
+			Desired:  replicasOrDefault(d.Spec.Replicas),
+			Updated:  d.Status.UpdatedReplicas,
+			Ready:    d.Status.ReadyReplicas,
+			Total:    d.Status.Replicas,
+			Observed: d.Status.ObservedGeneration >= d.Generation,
+		}, nil
+	case ResourceStatefulSets:
+		s, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return RolloutProgress{}, fmt.Errorf("failed to get statefulset: %w", err)
+		}
+		return RolloutProgress{
+			Desired:  replicasOrDefault(s.Spec.Replicas),
+			Updated:  s.Status.UpdatedReplicas,
+			Ready:    s.Status.ReadyReplicas,
+			Total:    s.Status.Replicas,
+			Observed: s.Status.ObservedGeneration >= s.Generation,
+		}, nil
+	case ResourceDaemonSets:
+		ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return RolloutProgress{}, fmt.Errorf("failed to get daemonset: %w", err)
+		}
+		return RolloutProgress{
+			Desired:  ds.Status.DesiredNumberScheduled,
+			Updated:  ds.Status.UpdatedNumberScheduled,
+			Ready:    ds.Status.NumberReady,
+			Total:    ds.Status.CurrentNumberScheduled,
+			Observed: ds.Status.ObservedGeneration >= ds.Generation,
+		}, nil
+	case ResourceRollouts:
+		rollout, err := fetchRollout(ctx, dynamicClient, namespace, name)
+		if err != nil {
+			return RolloutProgress{}, err
+		}
+		desired, found, _ := unstructured.NestedInt64(rollout.Object, "spec", "replicas")
+		if !found {
+			desired = 1
+		}
+		updated, _, _ := unstructured.NestedInt64(rollout.Object, "status", "updatedReplicas")
+		ready, _, _ := unstructured.NestedInt64(rollout.Object, "status", "readyReplicas")
+		total, _, _ := unstructured.NestedInt64(rollout.Object, "status", "replicas")
+		// Argo reports the observed generation as a string
+		observed, _, _ := unstructured.NestedString(rollout.Object, "status", "observedGeneration")
+		return RolloutProgress{
+			Desired:  int32(desired),
+			Updated:  int32(updated),
+			Ready:    int32(ready),
+			Total:    int32(total),
+			Observed: observed == fmt.Sprint(rollout.GetGeneration()),
+		}, nil
+	}
+	return RolloutProgress{}, fmt.Errorf("rollout status not supported for %s", resourceType)
+}
+
+// replicasOrDefault returns the spec replicas, 1 when unset as the API
+// server defaults them.
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// workloadTemplate returns the pod template of a Deployment, StatefulSet or
+// DaemonSet.
+func workloadTemplate(ctx context.Context, clientset kubernetes.Interface, namespace, name string, resourceType ResourceType) (*corev1.PodTemplateSpec, error) {
+	switch resourceType {
+	case ResourceDeployments:
+		d, err := GetDeployment(ctx, clientset, namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment: %w", err)
+		}
+		return &d.Spec.Template, nil
+	case ResourceStatefulSets:
+		s, err := GetStatefulSet(ctx, clientset, namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get statefulset: %w", err)
+		}
+		return &s.Spec.Template, nil
+	case ResourceDaemonSets:
+		ds, err := GetDaemonSet(ctx, clientset, namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get daemonset: %w", err)
+		}
+		return &ds.Spec.Template, nil
+	}
+	return nil, fmt.Errorf("setting the image is not supported for %s", resourceType)
+}
+
+// templateImages lists the images of a pod spec, init containers first.
+func templateImages(spec corev1.PodSpec) []ContainerImage {
+	var images []ContainerImage
+	for _, c := range spec.InitContainers {
+		images = append(images, ContainerImage{Container: c.Name, Image: c.Image, Init: true})
+	}
+	for _, c := range spec.Containers {
+		images = append(images, ContainerImage{Container: c.Name, Image: c.Image})
+	}
+	return images
+}
+
+func fetchRollout(ctx context.Context, dynamicClient dynamic.Interface, namespace, name string) (*unstructured.Unstructured, error) {
+	if dynamicClient == nil {
+		return nil, fmt.Errorf("dynamic client not available")
+	}
+	rollout, err := dynamicClient.Resource(rolloutGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rollout: %w", err)
+	}
+	return rollout, nil
+}
+
+// rolloutImages lists the images of a Rollout's pod template. A Rollout
+// that takes its template from a Deployment (workloadRef) has none of its
+// own: the image is changed on the Deployment.
+func rolloutImages(rollout *unstructured.Unstructured) ([]ContainerImage, error) {
+	if ref, found, _ := unstructured.NestedString(rollout.Object, "spec", "workloadRef", "name"); found {
+		return nil, fmt.Errorf("rollout %s takes its pod template from %s; change the image there", rollout.GetName(), ref)
+	}
+	var images []ContainerImage
+	for _, list := range []string{"initContainers", "containers"} {
+		containers, _, _ := unstructured.NestedSlice(rollout.Object, "spec", "template", "spec", list)
+		for _, c := range containers {
+			m, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+			name, _ := m["name"].(string)
+			image, _ := m["image"].(string)
+			images = append(images, ContainerImage{Container: name, Image: image, Init: list == "initContainers"})
+		}
+	}
+	return images, nil
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func imageTemplate() corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "migrate", Image: "registry.example.com/migrate:v1"}},
+		Containers: []corev1.Container{
+			{Name: "app", Image: "registry.example.com/app:v1"},
+			{Name: "proxy", Image: "envoyproxy/envoy:v1.29"},
+		},
+	}}
+}
+
+// recordPatches records the type and body of every patch the clientset is
+// sent, then lets the fake apply it.
+func recordPatches(clientset *fake.Clientset) *[]k8stesting.PatchAction {
+	var patches []k8stesting.PatchAction
+	clientset.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patches = append(patches, action.(k8stesting.PatchAction))
+		return false, nil, nil
+	})
+	return &patches
+}
+
+func TestSetWorkloadImage(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "web", Namespace: "default"}
+	tests := []struct {
+		name         string
+		resourceType ResourceType
+		object       runtime.Object
+		container    string
+		wantPatch    string
+	}{
+		{
+			name:         "deployment",
+			resourceType: ResourceDeployments,
+			object:       &appsv1.Deployment{ObjectMeta: meta, Spec: appsv1.DeploymentSpec{Template: imageTemplate()}},
+			container:    "app",
+			wantPatch:    `{"spec":{"template":{"spec":{"containers":[{"image":"registry.example.com/app:v2","name":"app"}]}}}}`,
+		},
+		{
+			name:         "statefulset",
+			resourceType: ResourceStatefulSets,
+			object:       &appsv1.StatefulSet{ObjectMeta: meta, Spec: appsv1.StatefulSetSpec{Template: imageTemplate()}},
+			container:    "app",
+			wantPatch:    `{"spec":{"template":{"spec":{"containers":[{"image":"registry.example.com/app:v2","name":"app"}]}}}}`,
+		},
+		{
+			name:         "daemonset",
+			resourceType: ResourceDaemonSets,
+			object:       &appsv1.DaemonSet{ObjectMeta: meta, Spec: appsv1.DaemonSetSpec{Template: imageTemplate()}},
+			container:    "app",
+			wantPatch:    `{"spec":{"template":{"spec":{"containers":[{"image":"registry.example.com/app:v2","name":"app"}]}}}}`,
+		},
+		{
+			name:         "init container",
+			resourceType: ResourceDeployments,
+			object:       &appsv1.Deployment{ObjectMeta: meta, Spec: appsv1.DeploymentSpec{Template: imageTemplate()}},
+			container:    "migrate",
+			wantPatch:    `{"spec":{"template":{"spec":{"initContainers":[{"image":"registry.example.com/app:v2","name":"migrate"}]}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tt.object)
+			patches := recordPatches(clientset)
+
+			err := SetWorkloadImage(context.Background(), clientset, nil, "default", "web", tt.resourceType, tt.container, "registry.example.com/app:v2")
+			if err != nil {
+				t.Fatalf("SetWorkloadImage() error = %v", err)
+			}
+			if len(*patches) != 1 {
+				t.Fatalf("sent %d patches, want 1", len(*patches))
+			}
+			patch := (*patches)[0]
+			if patch.GetPatchType() != types.StrategicMergePatchType || string(patch.GetPatch()) != tt.wantPatch {
+				t.Errorf("patch = %s %s, want strategic merge %s", patch.GetPatchType(), patch.GetPatch(), tt.wantPatch)
+			}
+
+			// The other containers keep their images
+			images, err := GetWorkloadImages(context.Background(), clientset, nil, "default", "web", tt.resourceType)
+			if err != nil {
+				t.Fatalf("GetWorkloadImages() error = %v", err)
+			}
+			for _, c := range images {
+				want := map[string]string{
+					"migrate": "registry.example.com/migrate:v1",
+					"app":     "registry.example.com/app:v1",
+					"proxy":   "envoyproxy/envoy:v1.29",
+				}[c.Container]
+				if c.Container == tt.container {
+					want = "registry.example.com/app:v2"
+				}
+				if c.Image != want {
+					t.Errorf("image of %s = %s, want %s", c.Container, c.Image, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSetWorkloadImage_Errors(t *testing.T) {
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Template: imageTemplate()},
+	}
+	clientset := fake.NewSimpleClientset(deploy)
+	patches := recordPatches(clientset)
+
+	err := SetWorkloadImage(context.Background(), clientset, nil, "default", "web", ResourceDeployments, "sidecar", "busybox:1.36")
+	if err == nil || !strings.Contains(err.Error(), `no container "sidecar"`) {
+		t.Errorf("unknown container error = %v", err)
+	}
+	if err := SetWorkloadImage(context.Background(), clientset, nil, "default", "web", ResourceDeployments, "app", ""); err == nil {
+		t.Error("an empty image should be rejected")
+	}
+	if err := SetWorkloadImage(context.Background(), clientset, nil, "default", "web", ResourceJobs, "app", "busybox:1.36"); err == nil {
+		t.Error("Jobs should not be supported")
+	}
+	if len(*patches) != 0 {
+		t.Errorf("sent %d patches, want none", len(*patches))
+	}
+}
+
+func TestSetWorkloadImage_Rollout(t *testing.T) {
+	rollout := canaryRollout(map[string]interface{}{
+		"template": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "proxy", "image": "envoyproxy/envoy:v1.29"},
+					map[string]interface{}{"name": "app", "image": "registry.example.com/app:v1"},
+				},
+			},
+		},
+	}, nil)
+	client := rolloutClient(rollout)
+
+	err := SetWorkloadImage(context.Background(), nil, client, "default", "web", ResourceRollouts, "app", "registry.example.com/app:v2")
+	if err != nil {
+		t.Fatalf("SetWorkloadImage() error = %v", err)
+	}
+	patch := client.Actions()[len(client.Actions())-1].(k8stesting.PatchAction)
+	want := `[{"op":"test","path":"/spec/template/spec/containers/1/name","value":"app"},{"op":"replace","path":"/spec/template/spec/containers/1/image","value":"registry.example.com/app:v2"}]`
+	if patch.GetPatchType() != types.JSONPatchType || string(patch.GetPatch()) != want {
+		t.Errorf("patch = %s %s, want JSON patch %s", patch.GetPatchType(), patch.GetPatch(), want)
+	}
+	images, err := GetWorkloadImages(context.Background(), nil, client, "default", "web", ResourceRollouts)
+	if err != nil {
+		t.Fatalf("GetWorkloadImages() error = %v", err)
+	}
+	if len(images) != 2 || images[1].Image != "registry.example.com/app:v2" || images[0].Image != "envoyproxy/envoy:v1.29" {
+		t.Errorf("images = %+v, want only app changed", images)
+	}
+
+	// A rollout referencing a Deployment has no template of its own
+	ref := canaryRollout(map[string]interface{}{
+		"workloadRef": map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web-template"},
+	}, nil)
+	err = SetWorkloadImage(context.Background(), nil, rolloutClient(ref), "default", "web", ResourceRollouts, "app", "registry.example.com/app:v2")
+	if err == nil || !strings.Contains(err.Error(), "web-template") {
+		t.Errorf("workloadRef error = %v, want the referenced Deployment named", err)
+	}
+}
+
+func TestValidateImage(t *testing.T) {
+	tests := []struct {
+		image   string
+		wantErr bool
+		warns   bool
+	}{
+		{"registry.example.com/app:v2", false, false},
+		{"registry.example.com:5000/app:v2", false, false},
+		{"app@sha256:0123456789abcdef", false, false},
+		{"registry.example.com:5000/app", false, true},
+		{"nginx", false, true},
+		{"", true, false},
+		{"app: v2", true, false},
+	}
+	for _, tt := range tests {
+		warning, err := ValidateImage(tt.image)
+		if (err != nil) != tt.wantErr || (warning != "") != tt.warns {
+			t.Errorf("ValidateImage(%q) = %q, %v", tt.image, warning, err)
+		}
+	}
+}
+
+func TestGetRolloutProgress(t *testing.T) {
+	replicas := int32(3)
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Generation: 4},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 4, Replicas: 4, UpdatedReplicas: 2, ReadyReplicas: 3},
+	}
+	clientset := fake.NewSimpleClientset(deploy)
+	progress, err := GetRolloutProgress(context.Background(), clientset, nil, "default", "web", ResourceDeployments)
+	if err != nil {
+		t.Fatalf("GetRolloutProgress() error = %v", err)
+	}
+	if progress.Done() || progress.String() != "2/3 updated, 3/3 ready" {
+		t.Errorf("progress = %s, done %v, want in progress", progress, progress.Done())
+	}
+
+	deploy.Status = appsv1.DeploymentStatus{ObservedGeneration: 4, Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3}
+	clientset = fake.NewSimpleClientset(deploy)
+	if progress, _ = GetRolloutProgress(context.Background(), clientset, nil, "default", "web", ResourceDeployments); !progress.Done() {
+		t.Errorf("progress = %+v, want done", progress)
+	}
+
+	// Not done until the controller has seen the change
+	deploy.Generation = 5
+	clientset = fake.NewSimpleClientset(deploy)
+	if progress, _ = GetRolloutProgress(context.Background(), clientset, nil, "default", "web", ResourceDeployments); progress.Done() {
+		t.Errorf("progress = %+v, want pending until observed", progress)
+	}
+
+	rollout := canaryRollout(nil, map[string]interface{}{
+		"observedGeneration": "2", "replicas": int64(3), "updatedReplicas": int64(3), "readyReplicas": int64(3),
+	})
+	rollout.SetGeneration(2)
+	progress, err = GetRolloutProgress(context.Background(), nil, rolloutClient(rollout), "default", "web", ResourceRollouts)
+	if err != nil || !progress.Done() {
+		t.Errorf("rollout progress = %+v, %v, want done", progress, err)
+	}
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// workloadPodsResync is how often StreamWorkloadLogs lists the workload's
+// pods to follow new ones and drop deleted ones. A variable so tests can
+// shorten it.
+var workloadPodsResync = 5 * time.Second
+
+// GetWorkloadLogs retrieves the logs of every pod of a workload, each line
+// tagged with its pod and merged by timestamp. opts.Container selects one
+// container in every pod; empty fetches all containers, splitting
+// opts.TailLines between them like GetAllContainerLogs. Pods whose logs
+// can't be read (e.g. still pending) are skipped.
+func GetWorkloadLogs(ctx context.Context, clientset kubernetes.Interface, workload WorkloadInfo, opts LogOptions) ([]LogLine, error) {
+	pods, err := GetWorkloadPods(ctx, clientset, workload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workload pods: %w", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		allLogs []LogLine
+	)
+	for _, pod := range pods {
+		containers := logContainers(pod, opts.Container)
+		tail := opts.TailLines
+		if len(containers) > 1 {
+			tail = max(tail/int64(len(containers)), 10)
+		}
+		for _, container := range containers {
+			wg.Add(1)
+			go func(pod, container string) {
+				defer wg.Done()
+				containerOpts := opts
+				containerOpts.Container = container
+				containerOpts.TailLines = tail
+				containerOpts.Timestamps = true
+				logs, err := GetPodLogs(ctx, clientset, workload.Namespace, pod, containerOpts)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				allLogs = append(allLogs, logs...)
+				mu.Unlock()
+			}(pod.Name, container)
+		}
+	}
+	wg.Wait()
+
+	MergeLogsByTime(allLogs)
+	return allLogs, nil
+}
+
+// StreamWorkloadLogs follows the logs of every pod of a workload, like
+// StreamPodLogs does for one pod. The pod list is refreshed every
+// workloadPodsResync, so pods created by a scale-up or rollout are
+// followed and deleted pods are dropped without restarting the stream.
+// Lines are tagged with their pod and arrive in the order received.
+func StreamWorkloadLogs(ctx context.Context, clientset kubernetes.Interface, workload WorkloadInfo, opts LogOptions) (<-chan LogLine, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	lines := make(chan LogLine, 256)
+
+	go func() {
+		defer close(lines)
+
+		var wg sync.WaitGroup
+		streams := make(map[string]context.CancelFunc) // By pod name
+		defer func() {
+			for _, stop := range streams {
+				stop()
+			}
+			wg.Wait()
+		}()
+
+		for {
+			pods, err := GetWorkloadPods(ctx, clientset, workload)
+			if err == nil {
+				current := make(map[string]bool, len(pods))
+				for _, pod := range pods {
+					current[pod.Name] = true
+					if _, ok := streams[pod.Name]; ok {
+						continue
+					}
+					podCtx, stop := context.WithCancel(ctx)
+					streams[pod.Name] = stop
+					for _, container := range logContainers(pod, opts.Container) {
+						wg.Add(1)
+						go func(pod, container string) {
+							defer wg.Done()
+							followContainerLogs(podCtx, clientset, workload.Namespace, pod, container, opts.TailLines, lines)
+						}(pod.Name, container)
+					}
+				}
+				for name, stop := range streams {
+					if !current[name] {
+						stop()
+						delete(streams, name)
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(workloadPodsResync):
+			}
+		}
+	}()
+
+	return lines, cancel
+}
+
+// MergeLogsByTime sorts log lines from several pods or containers
+// chronologically. The sort is stable, so lines sharing a timestamp keep
+// the order their source produced them in.
+func MergeLogsByTime(logs []LogLine) {
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Timestamp.Before(logs[j].Timestamp)
+	})
+}
+
+// logContainers returns the containers of pod whose logs to read: just
+// container when set, otherwise all of them.
+func logContainers(pod PodInfo, container string) []string {
+	if container != "" {
+		return []string{container}
+	}
+	names := make([]string, 0, len(pod.Containers))
+	for _, c := range pod.Containers {
+		names = append(names, c.Name)
+	}
+	return names
+}
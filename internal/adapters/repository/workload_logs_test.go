@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func workloadLogsPod(name string, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      name,
+		Namespace: "default",
+		Labels:    map[string]string{"app": "web"},
+	}}
+	for _, c := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: c})
+	}
+	return pod
+}
+
+var webWorkload = WorkloadInfo{Name: "web", Namespace: "default", Type: ResourceDeployments, Labels: map[string]string{"app": "web"}}
+
+func TestGetWorkloadLogs(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		workloadLogsPod("web-a", "app", "sidecar"),
+		workloadLogsPod("web-b", "app", "sidecar"),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}},
+	)
+
+	logs, err := GetWorkloadLogs(context.Background(), clientset, webWorkload, LogOptions{TailLines: 100})
+	if err != nil {
+		t.Fatalf("GetWorkloadLogs() error = %v", err)
+	}
+
+	seen := make(map[string]int)
+	for _, line := range logs {
+		seen[line.Pod+"/"+line.Container]++
+	}
+	for _, want := range []string{"web-a/app", "web-a/sidecar", "web-b/app", "web-b/sidecar"} {
+		if seen[want] != 1 {
+			t.Errorf("lines from %s = %d, want 1 (saw %v)", want, seen[want], seen)
+		}
+	}
+	if len(logs) != 4 {
+		t.Errorf("len(logs) = %d, want 4", len(logs))
+	}
+}
+
+func TestGetWorkloadLogs_Container(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		workloadLogsPod("web-a", "app", "sidecar"),
+		workloadLogsPod("web-b", "app", "sidecar"),
+	)
+
+	logs, err := GetWorkloadLogs(context.Background(), clientset, webWorkload, LogOptions{Container: "app", TailLines: 100})
+	if err != nil {
+		t.Fatalf("GetWorkloadLogs() error = %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("len(logs) = %d, want 2", len(logs))
+	}
+	for _, line := range logs {
+		if line.Container != "app" {
+			t.Errorf("Container = %q, want app", line.Container)
+		}
+	}
+}
+
+func TestMergeLogsByTime(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	logs := []LogLine{
+		{Pod: "web-a", Timestamp: base.Add(2 * time.Second), Content: "a2"},
+		{Pod: "web-a", Timestamp: base, Content: "a0"},
+		{Pod: "web-b", Timestamp: base.Add(time.Second), Content: "b1"},
+		{Pod: "web-b", Timestamp: base, Content: "b0"},
+	}
+	MergeLogsByTime(logs)
+
+	want := []string{"a0", "b0", "b1", "a2"}
+	for i, line := range logs {
+		if line.Content != want[i] {
+			t.Errorf("logs[%d] = %q, want %q", i, line.Content, want[i])
+		}
+	}
+}
+
+func TestStreamWorkloadLogs_FollowsScaling(t *testing.T) {
+	shortStreamBackoff(t)
+	resync := workloadPodsResync
+	workloadPodsResync = 10 * time.Millisecond
+	t.Cleanup(func() { workloadPodsResync = resync })
+
+	clientset := fake.NewSimpleClientset(workloadLogsPod("web-a", "app"))
+	lines, cancel := StreamWorkloadLogs(context.Background(), clientset, webWorkload, LogOptions{TailLines: 10})
+
+	waitPod := func(pod string) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case line := <-lines:
+				if line.Pod == pod {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timed out waiting for a line from %s", pod)
+			}
+		}
+	}
+	waitPod("web-a")
+
+	// Scale up: the new pod is followed without restarting the stream
+	if _, err := clientset.CoreV1().Pods("default").Create(context.Background(), workloadLogsPod("web-b", "app"), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitPod("web-b")
+
+	cancel()
+	waitClosed(t, lines)
+}
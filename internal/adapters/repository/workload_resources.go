@@ -0,0 +1,351 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResourceAmounts is a CPU and a memory quantity, as requests or as limits
+// of a container. "" means none is set.
+type ResourceAmounts struct {
+	CPU    string
+	Memory string
+}
+
+// ContainerResources is what a container of a workload's pod template asks
+// for, next to what its pods use and how often they restarted.
+type ContainerResources struct {
+	Container   string
+	Init        bool // An init container
+	Requests    ResourceAmounts
+	Limits      ResourceAmounts
+	Pods        int   // Pods of the workload running the container
+	Sampled     int   // Pods metrics-server has sampled
+	CPUMilli    int64 // Highest CPU usage among the sampled pods
+	MemoryBytes int64 // Highest memory usage among the sampled pods
+	Restarts    int32 // Restarts summed over the pods
+	OOMKills    int   // Pods whose container was last killed for memory
+}
+
+// ValidateResources checks requests and limits typed for a container: each
+// set quantity must parse and not be negative, and a limit must not be
+// below the request for the same resource, which the API server rejects.
+func ValidateResources(requests, limits ResourceAmounts) error {
+	parsed := map[string]resource.Quantity{}
+	for _, q := range []struct{ name, value string }{
+		{"cpu request", requests.CPU},
+		{"memory request", requests.Memory},
+		{"cpu limit", limits.CPU},
+		{"memory limit", limits.Memory},
+	} {
+		if q.value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(q.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", q.name, q.value, err)
+		}
+		if quantity.Sign() < 0 {
+			return fmt.Errorf("%s %q must not be negative", q.name, q.value)
+		}
+		parsed[q.name] = quantity
+	}
+	for _, name := range []string{"cpu", "memory"} {
+		request, hasRequest := parsed[name+" request"]
+		limit, hasLimit := parsed[name+" limit"]
+		if hasRequest && hasLimit && limit.Cmp(request) < 0 {
+			return fmt.Errorf("%s limit %s is below the request %s", name, limit.String(), request.String())
+		}
+	}
+	return nil
+}
+
+// GetWorkloadResources returns the requests and limits of the containers of
+// a workload's pod template, init containers first, with the usage,
+// restarts and OOM kills of its pods. Usage is left out when metrics-server
+// is not available.
+func GetWorkloadResources(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, metricsClient MetricsClientInterface, workload WorkloadInfo) ([]ContainerResources, error) {
+	var containers []ContainerResources
+	if workload.Type == ResourceRollouts {
+		rollout, err := fetchRollout(ctx, dynamicClient, workload.Namespace, workload.Name)
+		if err != nil {
+			return nil, err
+		}
+		if containers, err = rolloutResources(rollout); err != nil {
+			return nil, err
+		}
+	} else {
+		template, err := workloadTemplate(ctx, clientset, workload.Namespace, workload.Name, workload.Type)
+		if err != nil {
+			return nil, err
+		}
+		containers = templateResources(template.Spec)
+	}
+
+	pods, err := GetWorkloadPods(ctx, clientset, workload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	metrics, _ := GetNamespaceMetrics(ctx, metricsClient, workload.Namespace)
+	addPodResourceUsage(containers, pods, metrics)
+	return containers, nil
+}
+
+// addPodResourceUsage adds to containers the restarts and OOM kills of
+// pods, and the highest usage metrics-server reports for them.
+func addPodResourceUsage(containers []ContainerResources, pods []PodInfo, metrics []PodMetrics) {
+	usage := map[string]map[string]ContainerMetrics{}
+	for _, pm := range metrics {
+		usage[pm.Name] = map[string]ContainerMetrics{}
+		for _, c := range pm.Containers {
+			usage[pm.Name][c.Name] = c
+		}
+	}
+	for i := range containers {
+		c := &containers[i]
+		for _, pod := range pods {
+			statuses := pod.Containers
+			if c.Init {
+				statuses = pod.InitContainers
+			}
+			for _, status := range statuses {
+				if status.Name != c.Container {
+					continue
+				}
+				c.Pods++
+				c.Restarts += status.RestartCount
+				if lastOOMKill(status) != nil {
+					c.OOMKills++
+				}
+				if m, ok := usage[pod.Name][c.Container]; ok {
+					c.Sampled++
+					c.CPUMilli = max(c.CPUMilli, m.CPUMilli)
+					c.MemoryBytes = max(c.MemoryBytes, m.MemoryBytes)
+				}
+			}
+		}
+	}
+}
+
+// SetWorkloadResources sets the CPU and memory requests and limits of a
+// container of a workload's pod template, like kubectl set resources,
+// which rolls out new pods. An empty amount removes the request or limit;
+// other resources, such as ephemeral storage, are kept. Deployments,
+// StatefulSets and DaemonSets get a strategic merge patch keyed by
+// container name; Rollouts a JSON patch that tests the container's name at
+// its index first, as for SetWorkloadImage.
+func SetWorkloadResources(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, namespace, name string, resourceType ResourceType, container string, requests, limits ResourceAmounts) error {
+	if err := ValidateResources(requests, limits); err != nil {
+		return err
+	}
+
+	if resourceType == ResourceRollouts {
+		rollout, err := fetchRollout(ctx, dynamicClient, namespace, name)
+		if err != nil {
+			return err
+		}
+		patch, err := buildRolloutResourcesPatch(rollout, container, requests, limits)
+		if err != nil {
+			return fmt.Errorf("rollout %s: %w", name, err)
+		}
+		_, err = dynamicClient.Resource(rolloutGVR).Namespace(namespace).Patch(ctx, name, types.JSONPatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to set resources of rollout %s: %w", name, err)
+		}
+		return nil
+	}
+
+	template, err := workloadTemplate(ctx, clientset, namespace, name, resourceType)
+	if err != nil {
+		return err
+	}
+	patch, err := buildResourcesPatch(templateImages(template.Spec), container, requests, limits)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", KindForResourceType(resourceType), name, err)
+	}
+
+	apps := clientset.AppsV1()
+	switch resourceType {
+	case ResourceDeployments:
+		_, err = apps.Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case ResourceStatefulSets:
+		_, err = apps.StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case ResourceDaemonSets:
+		_, err = apps.DaemonSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set resources of %s: %w", name, err)
+	}
+	return nil
+}
+
+// resourceAmountsPatch maps amounts to their patch values, null for the
+// ones to remove.
+func resourceAmountsPatch(amounts ResourceAmounts) map[string]any {
+	patch := map[string]any{"cpu": nil, "memory": nil}
+	if amounts.CPU != "" {
+		patch["cpu"] = amounts.CPU
+	}
+	if amounts.Memory != "" {
+		patch["memory"] = amounts.Memory
+	}
+	return patch
+}
+
+// buildResourcesPatch builds the strategic merge patch setting the
+// requests and limits of a container, which merges into the container
+// list by name and into the resource maps by key.
+func buildResourcesPatch(images []ContainerImage, container string, requests, limits ResourceAmounts) ([]byte, error) {
+	c, _, err := findContainerImage(images, container)
+	if err != nil {
+		return nil, err
+	}
+	list := "containers"
+	if c.Init {
+		list = "initContainers"
+	}
+	patch := map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"spec": map[string]any{
+					list: []map[string]any{{
+						"name": container,
+						"resources": map[string]any{
+							"requests": resourceAmountsPatch(requests),
+							"limits":   resourceAmountsPatch(limits),
+						},
+					}},
+				},
+			},
+		},
+	}
+	return json.Marshal(patch)
+}
+
+// buildRolloutResourcesPatch builds a JSON patch replacing the resources of
+// a container with its current ones merged with requests and limits.
+func buildRolloutResourcesPatch(rollout *unstructured.Unstructured, container string, requests, limits ResourceAmounts) ([]byte, error) {
+	images, err := rolloutImages(rollout)
+	if err != nil {
+		return nil, err
+	}
+	c, index, err := findContainerImage(images, container)
+	if err != nil {
+		return nil, err
+	}
+	list := "containers"
+	if c.Init {
+		list = "initContainers"
+	}
+	containers, _, _ := unstructured.NestedSlice(rollout.Object, "spec", "template", "spec", list)
+	current, _ := containers[index].(map[string]any)
+	resources, _, _ := unstructured.NestedMap(current, "resources")
+	if resources == nil {
+		resources = map[string]any{}
+	}
+	for key, amounts := range map[string]ResourceAmounts{"requests": requests, "limits": limits} {
+		values, _, _ := unstructured.NestedMap(resources, key)
+		if values == nil {
+			values = map[string]any{}
+		}
+		for resourceName, value := range resourceAmountsPatch(amounts) {
+			if value == nil {
+				delete(values, resourceName)
+			} else {
+				values[resourceName] = value
+			}
+		}
+		if len(values) == 0 {
+			delete(resources, key)
+		} else {
+			resources[key] = values
+		}
+	}
+
+	path := fmt.Sprintf("/spec/template/spec/%s/%d", list, index)
+	ops := []map[string]any{
+		{"op": "test", "path": path + "/name", "value": container},
+		{"op": "add", "path": path + "/resources", "value": resources},
+	}
+	return json.Marshal(ops)
+}
+
+// templateResources lists the requests and limits of the containers of a
+// pod spec, init containers first.
+func templateResources(spec corev1.PodSpec) []ContainerResources {
+	var containers []ContainerResources
+	add := func(c corev1.Container, init bool) {
+		containers = append(containers, ContainerResources{
+			Container: c.Name,
+			Init:      init,
+			Requests:  resourceAmounts(c.Resources.Requests),
+			Limits:    resourceAmounts(c.Resources.Limits),
+		})
+	}
+	for _, c := range spec.InitContainers {
+		add(c, true)
+	}
+	for _, c := range spec.Containers {
+		add(c, false)
+	}
+	return containers
+}
+
+func resourceAmounts(list corev1.ResourceList) ResourceAmounts {
+	var amounts ResourceAmounts
+	if q, ok := list[corev1.ResourceCPU]; ok {
+		amounts.CPU = q.String()
+	}
+	if q, ok := list[corev1.ResourceMemory]; ok {
+		amounts.Memory = q.String()
+	}
+	return amounts
+}
+
+// rolloutResources lists the requests and limits of the containers of a
+// Rollout's pod template.
+func rolloutResources(rollout *unstructured.Unstructured) ([]ContainerResources, error) {
+	images, err := rolloutImages(rollout)
+	if err != nil {
+		return nil, err
+	}
+	var containers []ContainerResources
+	index := map[bool]int{}
+	for _, image := range images {
+		list := "containers"
+		if image.Init {
+			list = "initContainers"
+		}
+		items, _, _ := unstructured.NestedSlice(rollout.Object, "spec", "template", "spec", list)
+		c := ContainerResources{Container: image.Container, Init: image.Init}
+		if m, ok := items[index[image.Init]].(map[string]any); ok {
+			c.Requests = unstructuredAmounts(m, "requests")
+			c.Limits = unstructuredAmounts(m, "limits")
+		}
+		index[image.Init]++
+		containers = append(containers, c)
+	}
+	return containers, nil
+}
+
+// unstructuredAmounts reads the CPU and memory of resources.<key> of an
+// unstructured container, where quantities may be strings or numbers.
+func unstructuredAmounts(container map[string]any, key string) ResourceAmounts {
+	values, _, _ := unstructured.NestedMap(container, "resources", key)
+	amount := func(name string) string {
+		if v, ok := values[name]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	return ResourceAmounts{CPU: amount("cpu"), Memory: amount("memory")}
+}
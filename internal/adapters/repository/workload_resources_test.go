@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func resourcesTemplate() corev1.PodTemplateSpec {
+	main := usageContainer("100m", "500m", "128Mi", "256Mi")
+	main.Resources.Limits[corev1.ResourceEphemeralStorage] = resource.MustParse("1Gi")
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "migrate"}},
+			Containers:     []corev1.Container{main},
+		},
+	}
+}
+
+func TestSetWorkloadResources(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "web", Namespace: "default"}
+	wantPatch := `{"spec":{"template":{"spec":{"containers":[{"name":"main","resources":{"limits":{"cpu":null,"memory":"512Mi"},"requests":{"cpu":"250m","memory":"512Mi"}}}]}}}}`
+	tests := []struct {
+		name         string
+		resourceType ResourceType
+		object       runtime.Object
+	}{
+		{
+			name:         "deployment",
+			resourceType: ResourceDeployments,
+			object:       &appsv1.Deployment{ObjectMeta: meta, Spec: appsv1.DeploymentSpec{Template: resourcesTemplate()}},
+		},
+		{
+			name:         "statefulset",
+			resourceType: ResourceStatefulSets,
+			object:       &appsv1.StatefulSet{ObjectMeta: meta, Spec: appsv1.StatefulSetSpec{Template: resourcesTemplate()}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tt.object)
+			patches := recordPatches(clientset)
+
+			err := SetWorkloadResources(context.Background(), clientset, nil, "default", "web", tt.resourceType, "main",
+				ResourceAmounts{CPU: "250m", Memory: "512Mi"}, ResourceAmounts{Memory: "512Mi"})
+			if err != nil {
+				t.Fatalf("SetWorkloadResources() error = %v", err)
+			}
+			if len(*patches) != 1 {
+				t.Fatalf("sent %d patches, want 1", len(*patches))
+			}
+			patch := (*patches)[0]
+			if patch.GetPatchType() != types.StrategicMergePatchType || string(patch.GetPatch()) != wantPatch {
+				t.Errorf("patch = %s %s, want strategic merge %s", patch.GetPatchType(), patch.GetPatch(), wantPatch)
+			}
+
+			template, err := workloadTemplate(context.Background(), clientset, "default", "web", tt.resourceType)
+			if err != nil {
+				t.Fatalf("workloadTemplate() error = %v", err)
+			}
+			got := template.Spec.Containers[0].Resources
+			if got.Requests.Cpu().String() != "250m" || got.Limits.Memory().String() != "512Mi" {
+				t.Errorf("resources = %+v, want the new requests and limits", got)
+			}
+			if _, ok := got.Limits[corev1.ResourceCPU]; ok {
+				t.Error("an empty CPU limit should remove it")
+			}
+			if _, ok := got.Limits[corev1.ResourceEphemeralStorage]; !ok {
+				t.Error("other resources should be kept")
+			}
+		})
+	}
+}
+
+func TestSetWorkloadResources_Errors(t *testing.T) {
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Template: resourcesTemplate()},
+	}
+	clientset := fake.NewSimpleClientset(deploy)
+	patches := recordPatches(clientset)
+
+	tests := []struct {
+		name      string
+		container string
+		requests  ResourceAmounts
+		limits    ResourceAmounts
+		wantErr   string
+	}{
+		{"malformed cpu", "main", ResourceAmounts{CPU: "100 m"}, ResourceAmounts{}, `invalid cpu request "100 m"`},
+		{"malformed memory", "main", ResourceAmounts{}, ResourceAmounts{Memory: "1GB"}, `invalid memory limit "1GB"`},
+		{"negative", "main", ResourceAmounts{CPU: "-1"}, ResourceAmounts{}, "must not be negative"},
+		{"limit below request", "main", ResourceAmounts{Memory: "1Gi"}, ResourceAmounts{Memory: "512Mi"}, "memory limit 512Mi is below the request 1Gi"},
+		{"unknown container", "sidecar", ResourceAmounts{CPU: "100m"}, ResourceAmounts{}, `no container "sidecar"`},
+	}
+	for _, tt := range tests {
+		err := SetWorkloadResources(context.Background(), clientset, nil, "default", "web", ResourceDeployments, tt.container, tt.requests, tt.limits)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+	if len(*patches) != 0 {
+		t.Errorf("sent %d patches, want none", len(*patches))
+	}
+}
+
+func TestSetWorkloadResources_Rollout(t *testing.T) {
+	rollout := canaryRollout(map[string]interface{}{
+		"template": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "proxy"},
+					map[string]interface{}{"name": "app", "resources": map[string]interface{}{
+						"requests": map[string]interface{}{"cpu": "100m", "nvidia.com/gpu": int64(1)},
+						"limits":   map[string]interface{}{"cpu": "1"},
+					}},
+				},
+			},
+		},
+	}, nil)
+	client := rolloutClient(rollout)
+
+	err := SetWorkloadResources(context.Background(), nil, client, "default", "web", ResourceRollouts, "app",
+		ResourceAmounts{CPU: "200m", Memory: "256Mi"}, ResourceAmounts{})
+	if err != nil {
+		t.Fatalf("SetWorkloadResources() error = %v", err)
+	}
+	patch := client.Actions()[len(client.Actions())-1].(k8stesting.PatchAction)
+	want := `[{"op":"test","path":"/spec/template/spec/containers/1/name","value":"app"},{"op":"add","path":"/spec/template/spec/containers/1/resources","value":{"requests":{"cpu":"200m","memory":"256Mi","nvidia.com/gpu":1}}}]`
+	if patch.GetPatchType() != types.JSONPatchType || string(patch.GetPatch()) != want {
+		t.Errorf("patch = %s %s, want JSON patch %s", patch.GetPatchType(), patch.GetPatch(), want)
+	}
+}
+
+func TestGetWorkloadResources(t *testing.T) {
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Template: resourcesTemplate()},
+	}
+	web1 := usagePod("web-1", usageContainer("100m", "500m", "128Mi", "256Mi"))
+	web1.Labels = map[string]string{"app": "web"}
+	web1.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:                 "main",
+		RestartCount:         3,
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+	}}
+	web2 := usagePod("web-2", usageContainer("100m", "500m", "128Mi", "256Mi"))
+	web2.Labels = map[string]string{"app": "web"}
+	web2.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "main", RestartCount: 1}}
+	clientset := fake.NewSimpleClientset(deploy, web1, web2)
+	metricsClient := podMetricsList(map[string][2]string{
+		"web-1": {"50m", "250Mi"},
+		"web-2": {"300m", "100Mi"},
+	})
+
+	workload := WorkloadInfo{Name: "web", Namespace: "default", Type: ResourceDeployments, Labels: map[string]string{"app": "web"}}
+	containers, err := GetWorkloadResources(context.Background(), clientset, nil, metricsClient, workload)
+	if err != nil {
+		t.Fatalf("GetWorkloadResources() error = %v", err)
+	}
+	if len(containers) != 2 || containers[0].Container != "migrate" || !containers[0].Init {
+		t.Fatalf("containers = %+v, want the init container first", containers)
+	}
+	main := containers[1]
+	if main.Requests != (ResourceAmounts{CPU: "100m", Memory: "128Mi"}) || main.Limits != (ResourceAmounts{CPU: "500m", Memory: "256Mi"}) {
+		t.Errorf("requests %+v, limits %+v", main.Requests, main.Limits)
+	}
+	if main.Pods != 2 || main.Sampled != 2 || main.CPUMilli != 300 || main.MemoryBytes != 250*1024*1024 {
+		t.Errorf("usage = %+v, want the highest of both pods", main)
+	}
+	if main.Restarts != 4 || main.OOMKills != 1 {
+		t.Errorf("restarts %d, OOM kills %d; want 4, 1", main.Restarts, main.OOMKills)
+	}
+
+	// Without metrics-server the usage is left out
+	containers, err = GetWorkloadResources(context.Background(), clientset, nil, nil, workload)
+	if err != nil || containers[1].Sampled != 0 || containers[1].Pods != 2 {
+		t.Errorf("without metrics: %+v, %v", containers, err)
+	}
+}
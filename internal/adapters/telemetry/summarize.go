@@ -0,0 +1,183 @@
+package telemetry
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Count is a name with the number of times it occurred.
+type Count struct {
+	Name  string
+	Count int
+}
+
+// Summary aggregates the events of a telemetry directory.
+type Summary struct {
+	Files     int       // Files read
+	Events    int       // Valid events
+	Malformed int       // Lines that could not be parsed
+	Sessions  int       // Distinct sessions
+	From      time.Time // Oldest event
+	To        time.Time // Newest event
+	Versions  []Count   // Sessions per k1s version
+	Actions   []Count   // Invocations per action, most used first
+	Views     []Count   // Opens per view, most used first
+	Panics    []Count   // Crashes per panic message, most frequent first
+}
+
+// Summarize reads every telemetry file in dir (current and rotated) and
+// aggregates them. A missing directory yields an empty summary.
+func Summarize(dir string) (*Summary, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return &Summary{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read telemetry directory: %w", err)
+	}
+
+	var events []Event
+	s := &Summary{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		parsed, malformed, err := readEvents(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		s.Files++
+		s.Malformed += malformed
+		events = append(events, parsed...)
+	}
+
+	s.aggregate(events)
+	return s, nil
+}
+
+// readEvents parses one JSONL file, counting lines that are not valid events.
+func readEvents(path string) ([]Event, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var events []Event
+	malformed := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20) // Panic stacks can be long
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil || e.Kind == "" {
+			malformed++
+			continue
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return events, malformed, nil
+}
+
+func (s *Summary) aggregate(events []Event) {
+	sessions := make(map[string]string) // Session -> version
+	actions := make(map[string]int)
+	views := make(map[string]int)
+	panics := make(map[string]int)
+
+	for _, e := range events {
+		s.Events++
+		if s.From.IsZero() || e.Time.Before(s.From) {
+			s.From = e.Time
+		}
+		if e.Time.After(s.To) {
+			s.To = e.Time
+		}
+		sessions[e.Session] = e.Version
+
+		switch e.Kind {
+		case KindAction:
+			actions[e.Name]++
+		case KindView:
+			views[e.Name]++
+		case KindPanic:
+			panics[e.Name]++
+		}
+	}
+
+	versions := make(map[string]int)
+	for _, v := range sessions {
+		if v == "" {
+			v = "unknown"
+		}
+		versions[v]++
+	}
+
+	s.Sessions = len(sessions)
+	s.Versions = sortedCounts(versions)
+	s.Actions = sortedCounts(actions)
+	s.Views = sortedCounts(views)
+	s.Panics = sortedCounts(panics)
+}
+
+// sortedCounts orders counts by frequency, then by name.
+func sortedCounts(m map[string]int) []Count {
+	counts := make([]Count, 0, len(m))
+	for name, n := range m {
+		counts = append(counts, Count{Name: name, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// WriteReport prints the summary as a plain-text report.
+func (s *Summary) WriteReport(w io.Writer) {
+	fmt.Fprintln(w, "k1s telemetry summary")
+	fmt.Fprintln(w)
+	if s.Events == 0 {
+		fmt.Fprintln(w, "No events recorded.")
+		return
+	}
+
+	fmt.Fprintf(w, "  %-12s %s → %s\n", "Period:", s.From.Format("2006-01-02 15:04"), s.To.Format("2006-01-02 15:04"))
+	fmt.Fprintf(w, "  %-12s %d\n", "Sessions:", s.Sessions)
+	fmt.Fprintf(w, "  %-12s %d (%d files)\n", "Events:", s.Events, s.Files)
+	if s.Malformed > 0 {
+		fmt.Fprintf(w, "  %-12s %d\n", "Malformed:", s.Malformed)
+	}
+
+	writeCounts(w, "Versions (sessions)", s.Versions)
+	writeCounts(w, "Actions", s.Actions)
+	writeCounts(w, "Views", s.Views)
+	writeCounts(w, "Crashes", s.Panics)
+}
+
+func writeCounts(w io.Writer, title string, counts []Count) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, title)
+	if len(counts) == 0 {
+		fmt.Fprintln(w, "  (none)")
+		return
+	}
+	for _, c := range counts {
+		fmt.Fprintf(w, "  %6d  %s\n", c.Count, c.Name)
+	}
+}
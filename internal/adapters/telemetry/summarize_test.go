@@ -0,0 +1,122 @@
+package telemetry
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir, name string, lines ...string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "events-20240101T000000.000000000.jsonl",
+		`{"time":"2024-01-01T09:00:00Z","session":"a","version":"1.0.0","kind":"view","name":"dashboard"}`,
+		`{"time":"2024-01-01T09:01:00Z","session":"a","version":"1.0.0","kind":"action","name":"restart"}`,
+		`{"time":"2024-01-01T09:02:00Z","session":"a","version":"1.0.0","kind":"action","name":"describe"}`,
+	)
+	writeFile(t, dir, currentFile,
+		`{"time":"2024-01-02T10:00:00Z","session":"b","version":"1.1.0","kind":"action","name":"describe"}`,
+		`not json`,
+		``,
+		`{"time":"2024-01-02T10:05:00Z","session":"b","version":"1.1.0","kind":"panic","name":"nil map","stack":"goroutine 1"}`,
+		`{"time":"2024-01-02T10:06:00Z","session":"c","kind":"action","name":"describe"}`,
+	)
+	writeFile(t, dir, "notes.txt", "ignored")
+
+	s, err := Summarize(dir)
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+
+	if s.Files != 2 || s.Events != 6 || s.Malformed != 1 || s.Sessions != 3 {
+		t.Errorf("files=%d events=%d malformed=%d sessions=%d, want 2/6/1/3", s.Files, s.Events, s.Malformed, s.Sessions)
+	}
+	if !s.From.Equal(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)) || !s.To.Equal(time.Date(2024, 1, 2, 10, 6, 0, 0, time.UTC)) {
+		t.Errorf("period = %v → %v", s.From, s.To)
+	}
+	if len(s.Actions) != 2 || s.Actions[0] != (Count{"describe", 3}) || s.Actions[1] != (Count{"restart", 1}) {
+		t.Errorf("Actions = %v, want describe 3, restart 1", s.Actions)
+	}
+	if len(s.Views) != 1 || s.Views[0] != (Count{"dashboard", 1}) {
+		t.Errorf("Views = %v", s.Views)
+	}
+	if len(s.Panics) != 1 || s.Panics[0] != (Count{"nil map", 1}) {
+		t.Errorf("Panics = %v", s.Panics)
+	}
+	want := []Count{{"1.0.0", 1}, {"1.1.0", 1}, {"unknown", 1}}
+	if len(s.Versions) != 3 || s.Versions[0] != want[0] || s.Versions[1] != want[1] || s.Versions[2] != want[2] {
+		t.Errorf("Versions = %v, want %v", s.Versions, want)
+	}
+}
+
+func TestSummarize_MissingDir(t *testing.T) {
+	s, err := Summarize(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if s.Events != 0 {
+		t.Errorf("Events = %d, want 0", s.Events)
+	}
+
+	var buf bytes.Buffer
+	s.WriteReport(&buf)
+	if !strings.Contains(buf.String(), "No events recorded.") {
+		t.Errorf("report = %q", buf.String())
+	}
+}
+
+func TestSummarize_RecorderOutput(t *testing.T) {
+	dir := t.TempDir()
+	r := New(dir, Options{Usage: true, CrashReports: true, Version: "dev"})
+	r.Action("scale")
+	r.Action("scale")
+	r.View("pvc-details")
+	r.Close()
+	r.Panic("boom", []byte("stack"))
+
+	s, err := Summarize(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Events != 4 || s.Sessions != 1 || s.Actions[0] != (Count{"scale", 2}) || s.Panics[0].Name != "boom" {
+		t.Errorf("summary = %+v", s)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	s := &Summary{
+		Files:    1,
+		Events:   3,
+		Sessions: 1,
+		From:     time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		To:       time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		Versions: []Count{{"1.0.0", 1}},
+		Actions:  []Count{{"restart", 2}},
+		Views:    []Count{{"dashboard", 1}},
+	}
+
+	var buf bytes.Buffer
+	s.WriteReport(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"2024-01-01 09:00 → 2024-01-01 10:00",
+		"Sessions:    1",
+		"Actions\n       2  restart",
+		"Views\n       1  dashboard",
+		"Crashes\n  (none)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+// Package telemetry records anonymized usage counts and crash reports to
+// local JSONL files. It is strictly opt-in and never transmits anything:
+// the files stay under the state directory until someone collects them,
+// typically via the "k1s telemetry summarize" report.
+//
+// Events carry only action and view identifiers, never namespaces, resource
+// names or cluster details. Recording is non-blocking so the UI is never
+// slowed down by disk I/O; events are dropped if the writer falls behind.
+package telemetry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event kinds.
+const (
+	KindAction = "action" // A feature was invoked, e.g. "restart"
+	KindView   = "view"   // A view or viewer was opened, e.g. "dashboard"
+	KindPanic  = "panic"  // The application crashed
+)
+
+const (
+	currentFile        = "events.jsonl"
+	rotatedPrefix      = "events-"
+	defaultMaxFileSize = 1 << 20 // Rotate the current file at 1 MiB
+	defaultMaxFiles    = 5       // Rotated files kept besides the current one
+	bufferSize         = 256     // Pending events before new ones are dropped
+)
+
+// Event is one line of a telemetry file.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Session string    `json:"session"` // Random per-process ID, not tied to the user
+	Version string    `json:"version,omitempty"`
+	Kind    string    `json:"kind"`
+	Name    string    `json:"name"`
+	Stack   string    `json:"stack,omitempty"` // Panic stack trace
+}
+
+// Options configures a Recorder.
+type Options struct {
+	Usage        bool   // Record action and view counts
+	CrashReports bool   // Record panics
+	Version      string // k1s version stamped on every event
+	MaxFileSize  int64  // Rotation threshold in bytes (default 1 MiB)
+	MaxFiles     int    // Rotated files to keep (default 5)
+}
+
+// Recorder appends events to the telemetry directory from a background
+// goroutine. A nil Recorder is valid and records nothing, so callers don't
+// need to check whether telemetry is enabled.
+type Recorder struct {
+	dir     string
+	opts    Options
+	session string
+	events  chan Event
+	quit    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	mu      sync.Mutex // Serializes writes from the writer goroutine and panics
+	now     func() time.Time
+}
+
+// New starts a recorder writing to dir. It returns nil when neither usage
+// metrics nor crash reports are enabled.
+func New(dir string, opts Options) *Recorder {
+	if !opts.Usage && !opts.CrashReports {
+		return nil
+	}
+	r := newRecorder(dir, opts)
+	go r.run()
+	return r
+}
+
+// newRecorder creates a recorder without starting the writer goroutine.
+func newRecorder(dir string, opts Options) *Recorder {
+	if opts.MaxFileSize <= 0 {
+		opts.MaxFileSize = defaultMaxFileSize
+	}
+	if opts.MaxFiles <= 0 {
+		opts.MaxFiles = defaultMaxFiles
+	}
+	return &Recorder{
+		dir:     dir,
+		opts:    opts,
+		session: newSessionID(),
+		events:  make(chan Event, bufferSize),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+		now:     time.Now,
+	}
+}
+
+// DefaultDir returns $XDG_STATE_HOME/k1s/telemetry, falling back to
+// ~/.local/state/k1s/telemetry.
+func DefaultDir() (string, error) {
+	if state := os.Getenv("XDG_STATE_HOME"); state != "" {
+		return filepath.Join(state, "k1s", "telemetry"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", "k1s", "telemetry"), nil
+}
+
+// Action records that a feature was invoked.
+func (r *Recorder) Action(name string) {
+	r.record(KindAction, name)
+}
+
+// View records that a view was opened.
+func (r *Recorder) View(name string) {
+	r.record(KindView, name)
+}
+
+func (r *Recorder) record(kind, name string) {
+	if r == nil || !r.opts.Usage {
+		return
+	}
+	e := r.event(kind, name)
+	select {
+	case r.events <- e:
+	default:
+		// Writer is behind; dropping is better than blocking the UI
+	}
+}
+
+// Panic writes a crash report synchronously, since the process is about
+// to exit.
+func (r *Recorder) Panic(value any, stack []byte) {
+	if r == nil || !r.opts.CrashReports {
+		return
+	}
+	// Group reports by the first line of the panic value; the stack has the rest
+	name, _, _ := strings.Cut(fmt.Sprint(value), "\n")
+	e := r.event(KindPanic, name)
+	e.Stack = string(stack)
+	_ = r.write(e)
+}
+
+// CapturePanic records a panic and re-panics so the normal crash handling
+// (terminal restore, stack output) still runs. Use it directly with defer:
+//
+//	defer m.telemetry.CapturePanic()
+func (r *Recorder) CapturePanic() {
+	if v := recover(); v != nil {
+		r.Panic(v, debug.Stack())
+		panic(v)
+	}
+}
+
+// Close flushes pending events and stops the writer goroutine.
+func (r *Recorder) Close() {
+	if r == nil {
+		return
+	}
+	r.once.Do(func() { close(r.quit) })
+	<-r.done
+}
+
+func (r *Recorder) event(kind, name string) Event {
+	return Event{
+		Time:    r.now().UTC(),
+		Session: r.session,
+		Version: r.opts.Version,
+		Kind:    kind,
+		Name:    name,
+	}
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+	for {
+		select {
+		case e := <-r.events:
+			_ = r.write(e)
+		case <-r.quit:
+			for {
+				select {
+				case e := <-r.events:
+					_ = r.write(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// write appends one event, rotating the current file first if the event
+// would push it past the size limit.
+func (r *Recorder) write(e Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(r.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+
+	path := filepath.Join(r.dir, currentFile)
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 && info.Size()+int64(len(line)) > r.opts.MaxFileSize {
+		if err := r.rotate(path); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open telemetry file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("failed to write telemetry event: %w", err)
+	}
+	return nil
+}
+
+// rotate renames the current file with a timestamp suffix and removes the
+// oldest rotated files beyond MaxFiles.
+func (r *Recorder) rotate(path string) error {
+	rotated := filepath.Join(r.dir, rotatedPrefix+r.now().UTC().Format("20060102T150405.000000000")+".jsonl")
+	if err := os.Rename(path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate telemetry file: %w", err)
+	}
+
+	files, err := rotatedFiles(r.dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > r.opts.MaxFiles {
+		_ = os.Remove(files[0])
+		files = files[1:]
+	}
+	return nil
+}
+
+// rotatedFiles returns the rotated files in dir, oldest first.
+func rotatedFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read telemetry directory: %w", err)
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), rotatedPrefix) && strings.HasSuffix(e.Name(), ".jsonl") {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func newSessionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
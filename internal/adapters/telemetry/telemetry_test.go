@@ -0,0 +1,182 @@
+package telemetry
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readLines(t *testing.T, path string) []Event {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestNew_DisabledReturnsNil(t *testing.T) {
+	r := New(t.TempDir(), Options{})
+	if r != nil {
+		t.Fatal("New() should return nil when nothing is enabled")
+	}
+
+	// A nil recorder is safe to use
+	r.Action("restart")
+	r.View("dashboard")
+	r.Panic("boom", nil)
+	r.Close()
+}
+
+func TestRecorder_RecordsEvents(t *testing.T) {
+	dir := t.TempDir()
+	r := New(dir, Options{Usage: true, Version: "1.2.3"})
+	r.Action("restart")
+	r.View("dashboard")
+	r.Close()
+
+	events := readLines(t, filepath.Join(dir, currentFile))
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if events[0].Kind != KindAction || events[0].Name != "restart" {
+		t.Errorf("events[0] = %+v, want restart action", events[0])
+	}
+	if events[1].Kind != KindView || events[1].Name != "dashboard" {
+		t.Errorf("events[1] = %+v, want dashboard view", events[1])
+	}
+	if events[0].Version != "1.2.3" || events[0].Session == "" || events[0].Session != events[1].Session {
+		t.Errorf("events should share a session and carry the version: %+v", events)
+	}
+}
+
+func TestRecorder_UsageDisabled(t *testing.T) {
+	dir := t.TempDir()
+	r := New(dir, Options{CrashReports: true})
+	r.Action("restart")
+	r.Close()
+
+	if _, err := os.Stat(filepath.Join(dir, currentFile)); !os.IsNotExist(err) {
+		t.Error("usage events should not be written when only crash reports are enabled")
+	}
+}
+
+func TestRecorder_DoesNotBlockWhenBufferFull(t *testing.T) {
+	// No writer goroutine: the buffer fills and further events are dropped
+	r := newRecorder(t.TempDir(), Options{Usage: true})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < bufferSize*2; i++ {
+			r.Action("scale")
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Action() blocked on a full buffer")
+	}
+	if len(r.events) != bufferSize {
+		t.Errorf("buffered %d events, want %d", len(r.events), bufferSize)
+	}
+}
+
+func TestRecorder_Panic(t *testing.T) {
+	dir := t.TempDir()
+	r := newRecorder(dir, Options{CrashReports: true})
+
+	func() {
+		defer func() {
+			if v := recover(); v != "index out of range\nmore detail" {
+				t.Errorf("CapturePanic() should re-panic with the original value, got %v", v)
+			}
+		}()
+		defer r.CapturePanic()
+		panic("index out of range\nmore detail")
+	}()
+
+	events := readLines(t, filepath.Join(dir, currentFile))
+	if len(events) != 1 || events[0].Kind != KindPanic {
+		t.Fatalf("events = %+v, want one panic", events)
+	}
+	if events[0].Name != "index out of range" {
+		t.Errorf("Name = %q, want first line of the panic value", events[0].Name)
+	}
+	if !strings.Contains(events[0].Stack, "TestRecorder_Panic") {
+		t.Error("Stack should contain the panicking function")
+	}
+}
+
+func TestRecorder_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	r := newRecorder(dir, Options{Usage: true, MaxFileSize: 200, MaxFiles: 2})
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	// Each event is ~120 bytes, so every write after the first rotates
+	for i := 0; i < 6; i++ {
+		if err := r.write(r.event(KindAction, "describe")); err != nil {
+			t.Fatalf("write() error = %v", err)
+		}
+	}
+
+	rotated, err := rotatedFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated) != 2 {
+		t.Errorf("kept %d rotated files, want 2", len(rotated))
+	}
+	info, err := os.Stat(filepath.Join(dir, currentFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > 200 {
+		t.Errorf("current file is %d bytes, want <= 200", info.Size())
+	}
+
+	// Oldest files are removed first
+	for _, f := range rotated {
+		if strings.Contains(f, "T000001") || strings.Contains(f, "T000002") {
+			t.Errorf("oldest rotated file %s should have been removed", f)
+		}
+	}
+}
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state")
+	dir, err := DefaultDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != filepath.Join("/tmp/state", "k1s", "telemetry") {
+		t.Errorf("DefaultDir() = %q", dir)
+	}
+
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("HOME", "/home/dev")
+	dir, _ = DefaultDir()
+	if dir != filepath.Join("/home/dev", ".local", "state", "k1s", "telemetry") {
+		t.Errorf("DefaultDir() = %q", dir)
+	}
+}
@@ -9,34 +9,245 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/configs"
 	"github.com/andrebassi/k1s/internal/adapters/repository"
 	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+	"github.com/andrebassi/k1s/internal/adapters/tui/view"
 )
 
+// restartPodTimeout is how long restartPod waits for the replacement of a
+// deleted pod, enough for the default grace period and a slow scheduler.
+const restartPodTimeout = 2 * time.Minute
+
+// serviceCheckTimeout bounds a service check, including starting a netshoot
+// container, which may have to pull its image first. The probes themselves
+// time out much sooner (see repository.ServiceProbeTimeout).
+const serviceCheckTimeout = 2 * time.Minute
+
+// portForwardTimeout bounds finding a Service's pod and starting a
+// port-forward.
+const portForwardTimeout = 30 * time.Second
+
+// debugContainerTimeout bounds starting a debug container, which may have
+// to pull its image first.
+const debugContainerTimeout = 2 * time.Minute
+
+// confirmLevel resolves the configured confirmation level for an action on
+// an object in namespace, in the current Kubernetes context. Protected
+// namespaces always require typed confirmation.
+func (m *Model) confirmLevel(namespace, action string) configs.ConfirmLevel {
+	return m.settings.ConfirmLevelIn(m.k8sClient.Context(), namespace, action)
+}
+
+// isProtected reports whether namespace is protected in the current
+// Kubernetes context (see configs.Config.IsProtectedNamespace).
+func (m *Model) isProtected(namespace string) bool {
+	return m.settings.IsProtectedNamespace(m.k8sClient.Context(), namespace)
+}
+
+// workloadScale is the ConfirmResult data for a pending scale operation.
+// workload.Replicas is the count before the scale, to undo it.
+type workloadScale struct {
+	workload *repository.WorkloadInfo
+	replicas int32
+}
+
+// requestScale asks for confirmation before scaling a workload.
+// Scaling needs no confirmation by default, in which case the returned
+// command confirms immediately and scaling starts right away.
+func (m *Model) requestScale(workload *repository.WorkloadInfo, replicas int32) tea.Cmd {
+	return m.confirmDialog.Request(
+		m.confirmLevel(workload.Namespace, configs.ActionScale),
+		"Scale "+string(workload.Type),
+		fmt.Sprintf("Scale '%s' to %d replicas?", workload.Name, replicas),
+		"scale",
+		workload.Name,
+		workloadScale{workload: workload, replicas: replicas},
+	)
+}
+
+// showWorkloadActions opens the workload action menu for workload: scale
+// options, plus the revision history of Deployments, ordinals and
+// partitioned rollouts of StatefulSets, promote, abort and retry for Argo
+// Rollouts, or a manual run for CronJobs. Workloads that roll out a pod
+// template can have a container image, requests and limits changed, and be
+// watched until Ready or their next warning. Workloads installed by Helm
+// show their release. Every workload can have its YAML copied or saved. Returns false if the workload type has no
+// actions.
+func (m *Model) showWorkloadActions(workload *repository.WorkloadInfo) bool {
+	kind := repository.KindForResourceType(workload.Type)
+	var title string
+	var items []component.WorkloadActionItem
+	switch workload.Type {
+	case repository.ResourceDeployments:
+		title = "Deployment " + workload.Name
+		items = append(component.ScaleActions(workload.Namespace, workload.Name, string(workload.Type), workload.Replicas),
+			component.HistoryAction(), component.ImageAction(), component.ResourcesAction(), component.HPAAction())
+	case repository.ResourceStatefulSets:
+		title = "StatefulSet " + workload.Name
+		items = append(component.StatefulSetActions(),
+			component.ScaleActions(workload.Namespace, workload.Name, string(workload.Type), workload.Replicas)...)
+		items = append(items, component.ImageAction(), component.ResourcesAction(), component.HPAAction())
+	case repository.ResourceRollouts:
+		// Update controls first, for stuck canaries
+		title = "Rollout " + workload.Name
+		items = append(component.RolloutActions(workload.Namespace, workload.Name),
+			component.ScaleActions(workload.Namespace, workload.Name, string(workload.Type), workload.Replicas)...)
+		items = append(items, component.ImageAction(), component.ResourcesAction(), component.HPAAction())
+	case repository.ResourceDaemonSets:
+		title = "DaemonSet " + workload.Name
+		items = append(component.DaemonSetActions(), component.ImageAction(), component.ResourcesAction())
+	case repository.ResourceCronJobs:
+		title = "CronJob " + workload.Name
+		items = component.CronJobActions(workload.Namespace, workload.Name, workload.Status == "Suspended")
+	default:
+		if kind == "" {
+			return false
+		}
+		title = kind + " " + workload.Name
+	}
+	switch workload.Type {
+	case repository.ResourceDeployments, repository.ResourceStatefulSets, repository.ResourceRollouts, repository.ResourceDaemonSets:
+		items = append(items, component.WorkloadWatchActions()...)
+	}
+	if workload.HelmRelease != "" {
+		items = append(items, component.HelmAction(workload.HelmRelease))
+	}
+	items = append(items, component.WorkloadYAMLActions(workload.Namespace, kind, workload.Name)...)
+	m.workloadMenuTarget = workload
+	m.workloadActionMenu.Show(title, items)
+	return true
+}
+
+// podHint identifies a pod for targeted refreshes and optimistic updates.
+func podHint(namespace, name string) component.MutationHint {
+	return component.MutationHint{Kind: repository.ResourcePods, Namespace: namespace, Name: name}
+}
+
+// workloadHint identifies a workload for targeted refreshes and optimistic updates.
+func workloadHint(workload *repository.WorkloadInfo) component.MutationHint {
+	return component.MutationHint{Kind: workload.Type, Namespace: workload.Namespace, Name: workload.Name}
+}
+
 // deletePod deletes a pod from the cluster.
 // This is an async operation that returns a podDeletedMsg when complete.
 // The pod is deleted using the Kubernetes API with default grace period.
 // Returns a podDeletedMsg with the result (success or error).
-func (m *Model) deletePod(namespace, podName string) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
-		err := m.k8sClient.DeletePod(ctx, namespace, podName)
+func (m *Model) deletePod(req view.DeletePodRequest) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		err := m.k8sClient.DeletePod(ctx, req.Namespace, req.PodName)
+		return podDeletedMsg{
+			namespace:   req.Namespace,
+			podName:     req.PodName,
+			uid:         req.UID,
+			gracePeriod: req.GracePeriod,
+			hint:        podHint(req.Namespace, req.PodName),
+			err:         err,
+		}
+	})
+}
+
+// forceDeletePod deletes a pod stuck terminating with a zero grace period.
+// Returns a podDeletedMsg marked forced.
+func (m *Model) forceDeletePod(namespace, podName string) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		err := m.k8sClient.DeletePodForce(ctx, namespace, podName)
 		return podDeletedMsg{
 			namespace: namespace,
 			podName:   podName,
+			forced:    true,
+			hint:      podHint(namespace, podName),
 			err:       err,
 		}
+	})
+}
+
+// forceDeleteMargin is how long past its grace period a deleted pod may
+// take to go away before k1s offers to force delete it.
+const forceDeleteMargin = 15 * time.Second
+
+// checkPodTerminated looks for a deleted pod again once its grace period
+// and forceDeleteMargin are over.
+// Returns a podStillTerminatingMsg if it is still there, nil otherwise.
+func (m *Model) checkPodTerminated(namespace, podName, uid string, gracePeriod int64) tea.Cmd {
+	wait := time.Duration(gracePeriod)*time.Second + forceDeleteMargin
+	check := call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		present, err := repository.PodStillPresent(ctx, m.k8sClient.Clientset(), namespace, podName, uid)
+		if err != nil || !present {
+			return nil
+		}
+		return podStillTerminatingMsg{namespace: namespace, podName: podName}
+	})
+	return tea.Tick(wait, func(time.Time) tea.Msg { return check() })
+}
+
+// restartPod deletes a pod and waits for its controller to replace it, so
+// the dashboard can re-attach to the new pod.
+// Returns a view.PodRestartedMsg with the replacement or the error.
+func (m *Model) restartPod(namespace, podName string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), restartPodTimeout)
+		defer cancel()
+		pod, err := m.k8sClient.RestartPod(ctx, namespace, podName)
+		return view.PodRestartedMsg{OldName: podName, Pod: pod, Err: err}
+	}
+}
+
+// addDebugContainer injects an ephemeral debug container into a pod and
+// waits for it to run.
+// Returns a view.DebugContainerMsg with its name or the error.
+func (m *Model) addDebugContainer(req view.DebugContainerRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), debugContainerTimeout)
+		defer cancel()
+		name, err := m.k8sClient.AddEphemeralContainer(ctx, req.Namespace, req.PodName, req.Image, req.Target)
+		return view.DebugContainerMsg{PodName: req.PodName, Container: name, Err: err}
 	}
 }
 
+// checkService tests a Service from inside a pod, first injecting a
+// netshoot container to run the check in when asked to.
+// Returns a view.ServiceCheckMsg with the result or the error.
+func (m *Model) checkService(req view.ServiceCheckRequest) tea.Cmd {
+	clientset := m.k8sClient.Clientset()
+	config := m.k8sClient.Config()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), serviceCheckTimeout)
+		defer cancel()
+		container := req.Container
+		if req.Netshoot {
+			name, err := m.k8sClient.AddNetshootContainer(ctx, req.Namespace, req.PodName)
+			if err != nil {
+				return view.ServiceCheckMsg{PodName: req.PodName, Service: req.Service, Err: err}
+			}
+			container = name
+		}
+		check, err := repository.CheckServiceConnectivity(ctx, clientset, config, req.Namespace, req.PodName, container, req.Service)
+		return view.ServiceCheckMsg{PodName: req.PodName, Service: req.Service, Check: check, Err: err}
+	}
+}
+
+// removeSchedulingGate removes a scheduling gate from a pending pod.
+// Used when the controller that owns the gate is broken and the pod
+// would otherwise stay Pending forever.
+// Returns a SchedulingGateRemovedMsg with the result (success or error).
+func (m *Model) removeSchedulingGate(namespace, podName, gate string) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		err := repository.RemoveSchedulingGate(ctx, m.k8sClient.Clientset(), namespace, podName, gate)
+		return view.SchedulingGateRemovedMsg{Gate: gate, Err: err}
+	})
+}
+
 // scaleWorkload scales a workload to the specified number of replicas.
 // Supports Deployments, StatefulSets, and Argo Rollouts.
 // This is an async operation that triggers a rolling update if scaling up,
-// or terminates pods if scaling down.
+// or terminates pods if scaling down. An undoable scale is remembered in the
+// undo buffer once it succeeds, with workload.Replicas as the count to go
+// back to.
 // Returns a workloadActionMsg with the scale action result.
-func (m *Model) scaleWorkload(workload *repository.WorkloadInfo, replicas int32) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
+func (m *Model) scaleWorkload(workload *repository.WorkloadInfo, replicas int32, undoable bool) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		err := m.k8sClient.ScaleWorkload(ctx, workload.Namespace, workload.Name, workload.Type, replicas)
 		return workloadActionMsg{
 			action:       "scale",
@@ -44,9 +255,47 @@ func (m *Model) scaleWorkload(workload *repository.WorkloadInfo, replicas int32)
 			namespace:    workload.Namespace,
 			resourceType: workload.Type,
 			replicas:     replicas,
+			previous:     workload.Replicas,
+			undoable:     undoable,
+			hint:         workloadHint(workload),
 			err:          err,
 		}
+	})
+}
+
+// undoScale scales the workload of the newest scale in the undo buffer
+// back to its previous replica count. It needs no confirmation, since it
+// restores what the workload had moments ago. Returns nil when there is
+// nothing to undo.
+func (m *Model) undoScale() tea.Cmd {
+	u, ok := m.undo.Pop(time.Now())
+	if !ok {
+		return nil
+	}
+	m.undoStatus = ""
+	workload := &repository.WorkloadInfo{Name: u.Hint.Name, Namespace: u.Hint.Namespace, Type: u.Hint.Kind, Replicas: u.To}
+	m.statusMsg = fmt.Sprintf("Undoing: scaling %s back to %d...", u.Hint.Name, u.From)
+	m.mutations.Begin(component.PendingMutation{Hint: u.Hint, Action: component.MutationScale, Replicas: u.From})
+	return m.scaleWorkload(workload, u.From, false)
+}
+
+// observeReplicas forgets the undoable scales of a workload that a refresh
+// shows was scaled outside k1s, given its desired replicas. A workload with
+// a scale in flight is left alone, as the refresh may predate it.
+func (m *Model) observeReplicas(hint component.MutationHint, desired int32) {
+	w := repository.WorkloadInfo{Name: hint.Name, Namespace: hint.Namespace, Type: hint.Kind}
+	if _, pending := m.mutations.ScaleTarget(w); pending {
+		return
 	}
+	m.undo.Observe(hint, desired)
+}
+
+// undoTick schedules the next update of the undo countdown in the status
+// bar.
+func undoTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return undoTickMsg{}
+	})
 }
 
 // restartWorkload performs a rolling restart of a workload.
@@ -55,16 +304,56 @@ func (m *Model) scaleWorkload(workload *repository.WorkloadInfo, replicas int32)
 // Supports Deployments, StatefulSets, and Argo Rollouts.
 // Returns a workloadActionMsg with the restart action result.
 func (m *Model) restartWorkload(workload *repository.WorkloadInfo) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		err := m.k8sClient.RestartWorkload(ctx, workload.Namespace, workload.Name, workload.Type)
 		return workloadActionMsg{
 			action:       "restart",
 			workloadName: workload.Name,
 			namespace:    workload.Namespace,
 			resourceType: workload.Type,
+			hint:         workloadHint(workload),
 			err:          err,
 		}
+	})
+}
+
+// startPortForward starts a port-forward in the background, to the pod
+// or, for a Service port, to the pod and port the Service sends it to.
+// Returns a view.PortForwardMsg with the forward or the error.
+func (m *Model) startPortForward(req view.PortForwardRequest) tea.Cmd {
+	clientset := m.k8sClient.Clientset()
+	config := m.k8sClient.Config()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), portForwardTimeout)
+		defer cancel()
+		pod, remote := req.PodName, int(req.Port)
+		if req.Service != "" {
+			var err error
+			pod, remote, err = repository.ResolveServicePort(ctx, clientset, req.Namespace, req.Service, req.Port)
+			if err != nil {
+				return view.PortForwardMsg{Request: req, Err: err}
+			}
+		}
+		local := req.LocalPort
+		if local == 0 {
+			local = int(req.Port)
+		}
+		forward, err := m.portForwarder.Start(ctx, config, req.Namespace, pod, req.Service, local, remote)
+		return view.PortForwardMsg{Request: req, Forward: forward, Err: err}
+	}
+}
+
+// stopPortForward stops a port-forward, waiting for its local port to be
+// released. Returns a portForwardStoppedMsg.
+func (m *Model) stopPortForward(id int) tea.Cmd {
+	var stopped repository.PortForward
+	for _, pf := range m.portForwarder.List() {
+		if pf.ID == id {
+			stopped = pf
+		}
+	}
+	return func() tea.Msg {
+		return portForwardStoppedMsg{forward: stopped, err: m.portForwarder.Stop(id)}
 	}
 }
 
@@ -83,9 +372,7 @@ func (m *Model) restartWorkload(workload *repository.WorkloadInfo) tea.Cmd {
 //
 // Returns SecretCopyProgress if more namespaces remain, or SecretCopyResult when done.
 func (m *Model) copySecretToSingleNamespace(sourceNs, secretName, targetNs string, remaining []string, successCount, errorCount int) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
-
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		// Small delay so user can see the namespace name
 		time.Sleep(300 * time.Millisecond)
 
@@ -128,7 +415,7 @@ func (m *Model) copySecretToSingleNamespace(sourceNs, secretName, targetNs strin
 			SuccessCount:     successCount,
 			ErrorCount:       errorCount,
 		}
-	}
+	})
 }
 
 // copyConfigMapToSingleNamespace copies a ConfigMap to a target namespace.
@@ -146,9 +433,7 @@ func (m *Model) copySecretToSingleNamespace(sourceNs, secretName, targetNs strin
 //
 // Returns ConfigMapCopyProgress if more namespaces remain, or ConfigMapCopyResult when done.
 func (m *Model) copyConfigMapToSingleNamespace(sourceNs, configMapName, targetNs string, remaining []string, successCount, errorCount int) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
-
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		// Small delay so user can see the namespace name
 		time.Sleep(300 * time.Millisecond)
 
@@ -191,7 +476,7 @@ func (m *Model) copyConfigMapToSingleNamespace(sourceNs, configMapName, targetNs
 			SuccessCount:     successCount,
 			ErrorCount:       errorCount,
 		}
-	}
+	})
 }
 
 // copyDockerRegistryToSingleNamespace copies a Docker Registry secret to a target namespace.
@@ -211,9 +496,7 @@ func (m *Model) copyConfigMapToSingleNamespace(sourceNs, configMapName, targetNs
 //
 // Returns DockerRegistryCopyProgress if more namespaces remain, or DockerRegistryCopyResult when done.
 func (m *Model) copyDockerRegistryToSingleNamespace(sourceNs, secretName, targetNs string, remaining []string, successCount, errorCount int) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
-
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		// Small delay so user can see the namespace name
 		time.Sleep(300 * time.Millisecond)
 
@@ -256,7 +539,7 @@ func (m *Model) copyDockerRegistryToSingleNamespace(sourceNs, secretName, target
 			SuccessCount:     successCount,
 			ErrorCount:       errorCount,
 		}
-	}
+	})
 }
 
 // forceDeleteNamespace forcefully deletes a stuck namespace.
@@ -265,19 +548,34 @@ func (m *Model) copyDockerRegistryToSingleNamespace(sourceNs, secretName, target
 // Used for namespaces stuck in Terminating state.
 // Returns a namespaceDeletedMsg with the result (success or error).
 func (m *Model) forceDeleteNamespace(namespace string) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		err := repository.ForceDeleteNamespace(ctx, m.k8sClient.Clientset(), m.k8sClient.DynamicClient(), namespace)
 		return namespaceDeletedMsg{
 			namespace: namespace,
 			err:       err,
 		}
-	}
+	})
 }
 
 // saveConfig persists the current application configuration to disk.
 // This includes user preferences like last namespace, resource type, and refresh interval.
-// Errors are silently ignored as config save is non-critical.
+// Environment overrides are not saved, and neither is anything when the
+// config file could not be loaded, so the user's file is never replaced
+// by the defaults. Errors are silently ignored as config save is non-critical.
 func (m *Model) saveConfig() {
+	if m.keepConfig {
+		return
+	}
+	// Settings changed interactively are kept for the next session
+	m.config.EventsWarningsOnly = m.dashboard.EventsWarningsOnly()
+	m.config.WorkloadColumns = m.navigator.Columns().Names()
+	if !m.noColor {
+		m.config.Theme = style.CurrentTheme().Name
+	}
+	if f := m.dashboard.LogsTimeFilter(); f == component.TimeFilterAll {
+		m.config.LogTimeFilter = ""
+	} else {
+		m.config.LogTimeFilter = f.String()
+	}
 	_ = m.config.Save()
 }
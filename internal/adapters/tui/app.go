@@ -7,14 +7,21 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/deeplink"
 	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/telemetry"
 	"github.com/andrebassi/k1s/internal/adapters/tui/component"
 	"github.com/andrebassi/k1s/internal/adapters/tui/keys"
 	"github.com/andrebassi/k1s/internal/adapters/tui/style"
@@ -34,17 +41,35 @@ const (
 // It holds all UI components, Kubernetes client, and application state.
 type Model struct {
 	k8sClient          *repository.Client
-	config             *configs.Config
+	config             *configs.Config // Config file contents, saved on quit
+	settings           *configs.Config // Settings of the session: config with environment overrides
+	keepConfig         bool            // The config file could not be loaded; don't overwrite it on quit
 	navigator          component.Navigator
 	dashboard          view.Dashboard
 	help               component.HelpPanel
 	spinner            spinner.Model
 	workloadActionMenu component.WorkloadActionMenu
+	nodeActionMenu     component.NodeActionMenu
+	resultViewer       component.ResultViewer
 	confirmDialog      component.ConfirmDialog
 	configMapViewer        component.ConfigMapViewer
 	secretViewer           component.SecretViewer
 	dockerRegistryViewer   component.DockerRegistryViewer
 	hpaViewer              component.HPAViewer
+	podTopViewer           component.PodTopViewer
+	nodeCapacityViewer     component.NodeCapacityViewer
+	portForwardsViewer     component.PortForwardsViewer
+	watchesViewer          component.WatchesViewer
+	palette                component.Palette
+	warningsViewer         component.WarningsViewer
+	cronJobRunsViewer      component.CronJobRunsViewer
+	daemonSetNodesViewer   component.DaemonSetNodesViewer
+	columnPicker           component.ColumnPicker
+	errorLogViewer         component.ErrorLogViewer
+	clusterSwitcher        component.ClusterSwitcher
+	namespaceCompare       component.NamespaceCompare
+	fileBrowser            component.FileBrowser
+	inputDialog            component.InputDialog
 	isDockerRegistrySecret bool // Track if we're viewing a docker registry secret
 	view                   ViewState
 	width              int
@@ -61,18 +86,108 @@ type Model struct {
 	statusMsg          string // Status message for navigator view
 	nodeSearching      bool   // True when searching nodes
 	nodeSearchQuery    string // Node search query
+	drain              *nodeDrain // Node drain in progress, nil when none
+	bulkDelete         *bulkDelete // Bulk delete of pods in progress, nil when none
+	bulkPods           []repository.PodInfo // Pods the bulk action menu acts on
+	snapshotUpdates    <-chan tea.Msg // Progress of the snapshot export in progress, nil when none
+	workloadMenuTarget *repository.WorkloadInfo // Workload of the open workload action menu
+	triggeredJob       string // Job started from a CronJob whose pod to open, "" when none
+	imageRollout       *imageChange // Image change whose rollout is followed, nil when none
+	workloadResources  []repository.ContainerResources // Requests, limits and usage of the resources menu's workload
+	configVersions     map[string]configVersion // ConfigMaps and Secrets as last shown, to diff against
+	podsContinue       string // Token for the next page of pods, "" when all are loaded
+	workloadsContinue  string // Token for the next page of workloads, "" when all are loaded
+	healthPods         []repository.PodInfo // Pods the workloads' pod breakdown is computed from
+	healthNamespace    string // Namespace healthPods were listed from
+	portForwarder      *repository.PortForwarder // Port-forwards running in the background, stopped on Close
+	refresher          component.RefreshTicker   // Periodic refresh, and whether it is paused
+	recentWarnings     []repository.EventInfo    // Warning events of the current namespace, for the status bar badge
+	notifications      component.Notifications   // Error toast of the status bar and the session's error log
+	watches            []repository.Watch        // Conditions checked on every refresh until they fire, in any view
+	navCalls           *repository.CallScope     // Calls for the navigator's namespace and resource type
+	podCalls           *repository.CallScope     // Calls for the dashboard's pod
+	appCalls           *repository.CallScope     // Calls of no view, such as mutations, only bounded by their timeout
+	timedOut           *timedOutLoad             // Load of the view that timed out, nil when none
+	nextWatchID        int
 
 	// State tracking for reactive log fetching
 	lastShowPrevious bool
+	lastComparing    bool
 	lastLogContainer string
+	lastWorkloadLogs bool
+	lastLogRange     repository.LogTimeRange
 
 	// Flag to indicate we should load resources on init (when -n flag used)
 	startWithResources bool
+
+	// k1s:// link to open on init (nil when started without one)
+	link *deeplink.Link
+
+	// Pod or workload given as kind/name to open on init ("" when none)
+	initialKind string
+	initialName string
+
+	// Optimistic deletes and scales, shared with the navigator for rendering
+	mutations *component.Mutations
+
+	// The last scales, to undo with u, and the countdown shown for the
+	// newest one ("" when the status bar shows something else)
+	undo       *component.UndoBuffer
+	undoStatus string
+
+	// Tint fields that changed since the previous refresh
+	highlightChanges bool
+
+	// Log stream feeding the logs panel in follow mode (nil when polling)
+	logStream    *logStream
+	logStreamSeq int
+
+	// Whether the restart countdown of a crash looping container ticks
+	restartTicking bool
+
+	// Watch feeding the events panel on the dashboard (nil when polling)
+	eventWatch    *eventWatch
+	eventWatchSeq int
+
+	// Opt-in local usage and crash recording (nil when disabled)
+	telemetry *telemetry.Recorder
+
+	// Snapshots of the session for --record (nil when not recording)
+	sessionRecorder *repository.SessionRecorder
+
+	// Configuration problems found at startup (see Warnings)
+	warnings []string
+
+	// Colors turned off by --no-color or NO_COLOR; the theme is then not saved
+	noColor bool
+
+	// Why the API server can't be reached; the diagnostics screen takes
+	// over while it is set, keeping the state beneath for when it is back
+	connLost     error
+	connChecking bool // A connection check is in flight
+	started      bool // The cluster answered and the initial data was requested
 }
 
 // Options configures the application initialization.
 type Options struct {
-	Namespace string // Initial namespace to select (empty for interactive selection)
+	Namespace  string         // Initial namespace to select (empty for interactive selection)
+	Context    string         // Kubeconfig context to connect to (empty for the current context)
+	Kubeconfig string         // Kubeconfig file to use (empty for KUBECONFIG or ~/.kube/config)
+	Version    string         // k1s version, stamped on telemetry events
+	Link       *deeplink.Link // k1s:// link to open; overrides Namespace and Context
+	ReadOnly   bool           // Disable every action that changes the cluster
+	NoColor    bool           // Render without colors, whatever the configured theme
+	Refresh    int            // Refresh interval in seconds (0 for the configured one)
+	Clipboard  string         // Clipboard mode, one of configs.Clipboard* ("" for the configured one)
+	Record     string         // File to append snapshots of the session to ("" for none)
+	Replay     string         // Recording to replay instead of connecting to a cluster ("" for none)
+	ReplayAt   time.Time      // Replay the snapshot taken at or before this time (zero for the last)
+
+	// Pod or workload to open, as in "kubectl k1s deploy/api": kind as
+	// kubectl accepts it (pod, deploy, sts, ...) and name. Without a
+	// Namespace, the kubeconfig context's namespace is used, like kubectl.
+	InitialKind string
+	InitialName string
 }
 
 // New creates a new application model with default options.
@@ -82,19 +197,102 @@ func New() (*Model, error) {
 
 // NewWithOptions creates a new application model with the specified options.
 // If a namespace is provided, the app starts directly in the resources view.
+// If a context is provided, the client connects to it instead of the
+// kubeconfig's current context. If a link is provided, the client uses the
+// link's context and the app opens the linked pod or workload once its
+// namespace has loaded. An initial kind and name open that pod, or the
+// worst pod of that workload, the same way.
+//
+// Settings are merged with the precedence options (command-line flags) >
+// K1S_* environment variables > config file. A config file or environment
+// variable that can't be used does not stop startup; it is reported by
+// Warnings and the defaults are used instead. Read-only mode is on when
+// either opts.ReadOnly or K1S_READ_ONLY enables it. The configured theme is
+// applied, unless opts.NoColor or NO_COLOR turn colors off.
+//
+// With opts.Replay, the client serves a snapshot of a recording made with
+// opts.Record instead of connecting to a cluster, read-only, and nothing
+// is saved to the config file.
 func NewWithOptions(opts Options) (*Model, error) {
-	client, err := repository.NewClient()
+	var warnings []string
+	cfg, loadErr := configs.Load()
+	if loadErr != nil {
+		warnings = append(warnings, loadErr.Error())
+	}
+	// Environment overrides apply to the session only, never to the file
+	settings, err := cfg.WithEnv(os.Getenv)
 	if err != nil {
-		return nil, err
+		warnings = append(warnings, err.Error())
+	}
+	readOnly, err := configs.ReadOnlyEnv(os.Getenv)
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	// A replay has no cluster to change
+	readOnly = readOnly || opts.ReadOnly || opts.Replay != ""
+	if opts.Refresh > 0 {
+		settings.RefreshInterval = opts.Refresh
 	}
+	if opts.Clipboard != "" {
+		if !configs.ValidClipboard(opts.Clipboard) {
+			return nil, fmt.Errorf("unknown clipboard mode %q (want auto, native or osc52)", opts.Clipboard)
+		}
+		settings.Clipboard = opts.Clipboard
+	}
+	if !configs.ValidClipboard(settings.Clipboard) {
+		warnings = append(warnings, fmt.Sprintf("unknown clipboard mode %q, using auto", settings.Clipboard))
+		settings.Clipboard = configs.ClipboardAuto
+	}
+	component.SetClipboard(settings.Clipboard, settings.OSC52MaxBytes)
 
-	cfg, err := configs.Load()
+	theme, ok := style.ThemeByName(settings.Theme)
+	if !ok {
+		warnings = append(warnings, fmt.Sprintf("unknown theme %q, using the default theme", settings.Theme))
+	}
+	noColor := opts.NoColor || configs.NoColorEnv(os.Getenv)
+	if noColor {
+		theme = style.NoColorTheme
+		// The terminal profile NO_COLOR selects drops bold and underline
+		// too, and those are all the no-color theme has left
+		if lipgloss.ColorProfile() == termenv.Ascii {
+			lipgloss.SetColorProfile(termenv.ANSI)
+		}
+	}
+	style.Apply(theme)
+
+	var client *repository.Client
+	switch {
+	case opts.Replay != "":
+		client, err = newReplayClient(opts.Replay, opts.ReplayAt)
+		if err == nil && opts.Namespace == "" {
+			opts.Namespace = client.Namespace()
+		}
+	case opts.Link != nil:
+		client, err = repository.NewClientFor(opts.Kubeconfig, opts.Link.Context)
+		opts.Namespace = opts.Link.Namespace
+	case opts.Context != "":
+		client, err = repository.NewClientFor(opts.Kubeconfig, opts.Context)
+	case settings.LastContext != "":
+		client, err = repository.NewClientFor(opts.Kubeconfig, settings.LastContext)
+		if err != nil {
+			// The saved context may have been removed from the kubeconfig
+			warnings = append(warnings, fmt.Sprintf("context %s unavailable, using the current context: %v", settings.LastContext, err))
+			client, err = repository.NewClientFor(opts.Kubeconfig, "")
+		}
+	default:
+		client, err = repository.NewClientFor(opts.Kubeconfig, "")
+	}
 	if err != nil {
-		cfg = configs.DefaultConfig()
+		return nil, err
+	}
+	warnings = append(warnings, client.Warnings()...)
+
+	if opts.InitialKind != "" && opts.Namespace == "" {
+		opts.Namespace = client.ContextNamespace()
 	}
 
 	// Use provided namespace or fall back to config
-	initialNamespace := cfg.LastNamespace
+	initialNamespace := settings.StartNamespace()
 	startInResources := false
 	if opts.Namespace != "" {
 		initialNamespace = opts.Namespace
@@ -106,46 +304,262 @@ func NewWithOptions(opts Options) (*Model, error) {
 	s.Spinner = spinner.Dot
 	s.Style = style.SpinnerStyle
 
+	mutations := component.NewMutations()
 	navigator := component.NewNavigator()
+	navigator.SetMutations(mutations)
+	columns, err := component.ParseColumns(settings.WorkloadColumns)
+	if err != nil {
+		warnings = append(warnings, err.Error()+", using the default columns")
+	}
+	navigator.SetColumns(columns)
 	if startInResources {
 		navigator.SetMode(component.ModeResources)
 	}
 
-	return &Model{
+	// Telemetry stays local and is only recorded when opted in via config
+	var recorder *telemetry.Recorder
+	if settings.Telemetry.Usage || settings.Telemetry.CrashReports {
+		dir := settings.Telemetry.Dir
+		if dir == "" {
+			dir, _ = telemetry.DefaultDir()
+		}
+		if dir != "" {
+			recorder = telemetry.New(dir, telemetry.Options{
+				Usage:        settings.Telemetry.Usage,
+				CrashReports: settings.Telemetry.CrashReports,
+				Version:      opts.Version,
+			})
+		}
+	}
+
+	var sessionRecorder *repository.SessionRecorder
+	if opts.Record != "" {
+		sessionRecorder, err = repository.NewSessionRecorder(opts.Record)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dashboard := view.NewDashboard()
+	dashboard.SetEventsWarningsOnly(settings.EventsWarningsOnly)
+	dashboard.SetLogsTimeFilter(component.ParseTimeFilter(settings.LogTimeFilter))
+	dashboard.SetLogBufferLines(settings.LogBufferLines)
+	dashboard.SetConfirmLevelFunc(func(namespace, action string) configs.ConfirmLevel {
+		return settings.ConfirmLevelIn(client.Context(), namespace, action)
+	})
+
+	timeouts := repository.CallTimeouts{
+		List: time.Duration(settings.ListTimeout) * time.Second,
+		Logs: time.Duration(settings.LogTimeout) * time.Second,
+	}
+
+	m := &Model{
 		k8sClient:          client,
 		config:             cfg,
+		settings:           settings,
+		keepConfig:         loadErr != nil || opts.Replay != "", // A replay must not become the last context
 		navigator:          navigator,
-		dashboard:          view.NewDashboard(),
+		dashboard:          dashboard,
 		help:               component.NewHelpPanel(),
 		spinner:            s,
 		workloadActionMenu: component.NewWorkloadActionMenu(),
+		nodeActionMenu:     component.NewNodeActionMenu(),
+		resultViewer:       component.NewResultViewer(),
 		confirmDialog:        component.NewConfirmDialog(),
 		configMapViewer:      component.NewConfigMapViewer(),
 		secretViewer:         component.NewSecretViewer(),
 		dockerRegistryViewer: component.NewDockerRegistryViewer(),
 		hpaViewer:            component.NewHPAViewer(),
+		podTopViewer:         component.NewPodTopViewer(),
+		nodeCapacityViewer:   component.NewNodeCapacityViewer(),
+		portForwardsViewer:   component.NewPortForwardsViewer(),
+		watchesViewer:        component.NewWatchesViewer(),
+		palette:              component.NewPalette(),
+		warningsViewer:       component.NewWarningsViewer(),
+		cronJobRunsViewer:    component.NewCronJobRunsViewer(),
+		daemonSetNodesViewer: component.NewDaemonSetNodesViewer(),
+		columnPicker:         component.NewColumnPicker(),
+		errorLogViewer:       component.NewErrorLogViewer(),
+		clusterSwitcher:      component.NewClusterSwitcher(),
+		portForwarder:        repository.NewPortForwarder(),
+		refresher:            component.NewRefreshTicker(time.Duration(settings.RefreshInterval) * time.Second),
+		navCalls:             repository.NewCallScope(timeouts),
+		podCalls:             repository.NewCallScope(timeouts),
+		appCalls:             repository.NewCallScope(timeouts),
+		namespaceCompare:     component.NewNamespaceCompare(),
+		fileBrowser:          component.NewFileBrowser(),
+		inputDialog:          component.NewInputDialog(),
 		view:                 ViewNavigator,
 		loading:            true,
 		keys:               keys.DefaultKeyMap(),
 		startWithResources: startInResources,
-	}, nil
+		link:               opts.Link,
+		initialKind:        opts.InitialKind,
+		initialName:        opts.InitialName,
+		mutations:          mutations,
+		undo:               component.NewUndoBuffer(component.DefaultUndoSize, component.DefaultUndoTTL),
+		telemetry:          recorder,
+		sessionRecorder:    sessionRecorder,
+		warnings:           warnings,
+		statusMsg:          strings.Join(warnings, "; "),
+		noColor:            noColor,
+	}
+	m.setReadOnly(readOnly)
+	return m, nil
+}
+
+// setReadOnly turns read-only mode on or off. The client then refuses to
+// change the cluster, and the menus and confirmations of the app and
+// dashboard disable the actions that would.
+func (m *Model) setReadOnly(readOnly bool) {
+	m.k8sClient.SetReadOnly(readOnly)
+	m.dashboard.SetReadOnly(readOnly)
+	m.workloadActionMenu.SetReadOnly(readOnly)
+	m.nodeActionMenu.SetReadOnly(readOnly)
+	m.confirmDialog.SetReadOnly(readOnly)
+}
+
+// refuseReadOnly reports whether read-only mode forbids an action that
+// changes the cluster without asking for confirmation first, telling the
+// user in the status bar when it does.
+func (m *Model) refuseReadOnly(title string) bool {
+	if !m.k8sClient.ReadOnly() {
+		return false
+	}
+	m.statusMsg = component.ReadOnlyMsg{Title: title}.Status()
+	return true
+}
+
+// notifyError reports a failed request as a toast in the status bar and
+// records it in the error log. op says what was attempted, e.g. "list
+// pods"; namespace is "" for cluster-wide requests. The toast replaces
+// any progress message of the status bar.
+func (m *Model) notifyError(op, namespace string, err error) tea.Cmd {
+	m.statusMsg = ""
+	return m.notify(component.ErrorNotification(op, namespace, err))
+}
+
+// notify shows n as a toast and records it in the error log. A request
+// that could not reach the API server checks the connection, which shows
+// the diagnostics screen if the cluster is gone.
+func (m *Model) notify(n component.Notification) tea.Cmd {
+	cmd := m.notifications.Push(n)
+	if m.errorLogViewer.IsVisible() {
+		m.errorLogViewer.SetEntries(m.notifications.Log())
+	}
+	if repository.IsConnectionError(n.Err) && m.connLost == nil && !m.connChecking {
+		m.connChecking = true
+		return tea.Batch(cmd, m.checkConnection())
+	}
+	return cmd
+}
+
+// Warnings returns the problems with the configuration found at startup,
+// such as an invalid config file. The app runs with defaults in their place.
+func (m *Model) Warnings() []string {
+	return m.warnings
+}
+
+// Close flushes pending telemetry events and closes the session
+// recording. Call it after the program exits.
+func (m *Model) Close() {
+	m.portForwarder.StopAll()
+	m.telemetry.Close()
+	_ = m.sessionRecorder.Close()
+}
+
+// reconcilePods resolves pending pod deletions against a fresh pod list.
+// Deletions that didn't take are reverted (the pod shows normally again)
+// and reported in the status bar.
+func (m *Model) reconcilePods(namespace string, pods []repository.PodInfo) tea.Cmd {
+	for _, outcome := range m.mutations.ReconcilePods(namespace, pods) {
+		if !outcome.Applied {
+			m.statusMsg = outcome.Message()
+			return clearStatusAfter(5 * time.Second)
+		}
+	}
+	return nil
+}
+
+// continuePods records the token for the next page of the namespace's pods
+// and requests that page, showing the "loading more…" footer meanwhile.
+// Once every page is listed, pending pod deletions are reconciled against
+// the complete list.
+func (m *Model) continuePods(namespace, token string) tea.Cmd {
+	m.podsContinue = token
+	m.navigator.SetPodsLoadingMore(token != "")
+	if token != "" {
+		return m.loadMorePods(namespace, token)
+	}
+	return m.reconcilePods(namespace, m.navigator.Pods())
+}
+
+// continueWorkloads records the token for the next page of workloads and
+// requests that page, showing the "loading more…" footer meanwhile.
+func (m *Model) continueWorkloads(token string) tea.Cmd {
+	m.workloadsContinue = token
+	m.navigator.SetWorkloadsLoadingMore(token != "")
+	if token == "" {
+		return nil
+	}
+	return m.loadMoreWorkloads(m.k8sClient.Namespace(), m.navigator.ResourceType(), token)
+}
+
+// attachPodHealth sets the pod breakdown of workloads from the last pod
+// listing of the current namespace, if there is one.
+func (m *Model) attachPodHealth(workloads []repository.WorkloadInfo) {
+	if m.healthPods != nil && m.healthNamespace == m.k8sClient.Namespace() {
+		repository.AttachPodHealth(workloads, m.healthPods)
+	}
 }
 
 func (m Model) Init() tea.Cmd {
+	// Startup warnings are shown in the status bar for a while
+	var clearWarnings tea.Cmd
+	if len(m.warnings) > 0 {
+		clearWarnings = clearStatusAfter(10 * time.Second)
+	}
+	// Nothing is loaded until the cluster answers, so that a dead one
+	// shows the diagnostics screen rather than a raw error
+	return tea.Batch(
+		m.spinner.Tick,
+		clearWarnings,
+		m.checkConnection(),
+	)
+}
+
+// startupLoads requests the initial data once the cluster answered.
+func (m *Model) startupLoads() tea.Cmd {
+	m.started = true
+	if m.link != nil {
+		// Load the namespace first so going back from the linked view works
+		return tea.Batch(
+			m.probeMetrics(),
+			tea.Sequence(m.loadInitialDataWithResources(), m.resolveLink(m.link)),
+		)
+	}
+	if m.initialKind != "" {
+		return tea.Batch(
+			m.probeMetrics(),
+			tea.Sequence(m.loadInitialDataWithResources(), m.resolveTarget(m.initialKind, m.initialName)),
+		)
+	}
 	if m.startWithResources {
 		// When -n flag is used, load resources directly
 		return tea.Batch(
-			m.spinner.Tick,
+			m.probeMetrics(),
 			m.loadInitialDataWithResources(),
 		)
 	}
 	return tea.Batch(
-		m.spinner.Tick,
+		m.probeMetrics(),
 		m.loadInitialData(),
 	)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer m.telemetry.CapturePanic()
+
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
@@ -156,6 +570,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.navigator.SetSize(msg.Width, msg.Height-3) // -2 for border, -1 for status bar
 		m.dashboard.SetSize(msg.Width, msg.Height-3) // -2 for border, -1 for status bar
 		m.help.SetSize(msg.Width, msg.Height)
+		m.resultViewer.SetSize(msg.Width-4, msg.Height-4)
+		m.podTopViewer.SetSize(msg.Width, msg.Height)
+		m.nodeCapacityViewer.SetSize(msg.Width, msg.Height)
+		m.portForwardsViewer.SetSize(msg.Width, msg.Height)
+		m.watchesViewer.SetSize(msg.Width, msg.Height)
+		m.palette.SetSize(msg.Width, msg.Height)
+		m.warningsViewer.SetSize(msg.Width, msg.Height)
+		m.cronJobRunsViewer.SetSize(msg.Width, msg.Height)
+		m.daemonSetNodesViewer.SetSize(msg.Width, msg.Height)
+		m.errorLogViewer.SetSize(msg.Width, msg.Height)
+		m.clusterSwitcher.SetSize(msg.Width, msg.Height)
+		m.namespaceCompare.SetSize(msg.Width, msg.Height)
+		m.fileBrowser.SetSize(msg.Width, msg.Height)
 		return m, nil
 
 	case spinner.TickMsg:
@@ -163,11 +590,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case loadedMsg:
+		if repository.IsCallTimeout(msg.err) {
+			op := fmt.Sprintf("Listing %s in %s", m.navigator.ResourceType(), m.k8sClient.Namespace())
+			return m, m.loadTimedOut(op, repository.CallList, m.loadWorkloads())
+		}
 		m.loading = false
+		if repository.IsConnectionError(msg.err) {
+			// Lost since the connection check; retrying starts over
+			m.started = false
+			m.connLost = msg.err
+			return m, nil
+		}
 		if msg.err != nil {
 			m.err = msg.err
 			return m, nil
 		}
+		m.timedOut = nil
+		// Keep the previous breakdown until the pods have been listed again
+		m.attachPodHealth(msg.workloads)
+		for _, w := range msg.workloads {
+			m.observeReplicas(workloadHint(&w), w.Replicas)
+		}
 		m.navigator.SetWorkloads(msg.workloads)
 		m.navigator.SetNamespaces(msg.namespaces)
 		m.nodes = msg.nodes
@@ -175,19 +618,106 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if len(msg.workloads) == 0 && len(msg.namespaces) > 0 {
 			m.navigator.SetMode(component.ModeNamespace)
 		}
+		var loadHealth tea.Cmd
+		if len(msg.workloads) > 0 {
+			loadHealth = m.loadWorkloadHealth(m.k8sClient.Namespace())
+		}
+		return m, tea.Batch(m.continueWorkloads(msg.continueToken), loadHealth, m.expireChanges())
+
+	case workloadHealthMsg:
+		// Without the pod breakdown the list is still useful
+		if msg.err != nil || msg.namespace != m.k8sClient.Namespace() {
+			return m, nil
+		}
+		m.healthPods = msg.pods
+		m.healthNamespace = msg.namespace
+		m.navigator.AttachPodHealth(msg.pods)
 		return m, nil
 
+	case workloadsPageMsg:
+		// Drop pages of a listing that was replaced in the meantime
+		if msg.token != m.workloadsContinue || msg.namespace != m.k8sClient.Namespace() || msg.resourceType != m.navigator.ResourceType() {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.continueWorkloads("")
+			return m, m.notifyError("list more workloads", msg.namespace, msg.err)
+		}
+		m.attachPodHealth(msg.workloads)
+		for _, w := range msg.workloads {
+			m.observeReplicas(workloadHint(&w), w.Replicas)
+		}
+		m.navigator.AppendWorkloads(msg.workloads)
+		return m, m.continueWorkloads(msg.next)
+
+	case contextsLoadedMsg:
+		if msg.err != nil {
+			m.clusterSwitcher.Hide()
+			return m, m.notifyError("read contexts", "", msg.err)
+		}
+		m.clusterSwitcher.SetContexts(msg.contexts, m.config.FavoriteContexts)
+		return m, m.probeContexts(msg.contexts)
+
+	case contextProbedMsg:
+		m.clusterSwitcher.SetHealth(msg.health)
+		return m, nil
+
+	case component.ContextProbeRequest:
+		return m, m.probeContexts(msg.Contexts)
+
+	case component.FavoriteContextToggled:
+		m.config.ToggleFavoriteContext(msg.Context)
+		m.saveConfig()
+		return m, nil
+
+	case component.ContextSelected:
+		if msg.Context == m.k8sClient.Context() && m.connLost == nil {
+			return m, nil
+		}
+		// Leave the diagnostics screen for the navigator of the new cluster
+		m.connLost = nil
+		m.view = ViewNavigator
+		return m, m.switchContext(msg.Context)
+
+	case component.ClusterSwitcherClosed:
+		return m, nil
+
+	case contextSwitchedMsg:
+		m.loading = false
+		if msg.err != nil {
+			// Stay on the previous cluster rather than showing a dead one
+			*m.k8sClient = msg.previous
+			m.navigator.SetMode(component.ModeNamespace)
+			return m, m.notifyError("switch to context "+msg.context, "", msg.err)
+		}
+		m.resetContextState()
+		// Also the start of a session whose first cluster didn't answer
+		m.started = true
+		m.config.SetLastContext(msg.context)
+		m.navigator.SetNamespaces(msg.namespaces)
+		m.nodes = msg.nodes
+		m.statusMsg = "Switched to context " + msg.context
+		return m, tea.Batch(clearStatusAfter(3*time.Second), m.enterSwitchedNamespace(msg))
+
 	case resourcesLoadedMsg:
+		// A refresh in the background that timed out keeps what is shown
+		if repository.IsCallTimeout(msg.err) && m.loading {
+			return m, m.loadTimedOut("Listing pods in "+m.k8sClient.Namespace(), repository.CallList, m.refreshPods())
+		}
 		m.loading = false
 		if msg.err != nil {
-			m.err = msg.err
-			return m, nil
+			// Stay on the previous view rather than replacing it with the error
+			return m, m.notifyError("list resources", m.k8sClient.Namespace(), msg.err)
 		}
+		m.timedOut = nil
 		m.navigator.SetPods(msg.pods)
 		m.navigator.SetHPAs(msg.hpas)
 		m.navigator.SetConfigMaps(msg.configmaps)
 		m.navigator.SetSecrets(msg.secrets)
+		m.navigator.SetProbeHealth(msg.probeHealth)
 		m.navigator.SetMode(component.ModeResources)
+		ns := m.k8sClient.Namespace()
+		m.navigator.SetLocation(m.isProtected(ns), ns)
 		// Pass workload info for scale controls when no pods
 		// Use msg.workload (from namespace load) or m.workload (from workload selection)
 		workload := msg.workload
@@ -195,31 +725,83 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			workload = m.workload
 		}
 		m.navigator.SetScaleWorkload(workload)
-		return m, nil
+		return m, tea.Batch(m.continuePods(ns, msg.continueToken), m.expireChanges())
 
 	case initialResourcesLoadedMsg:
+		if repository.IsCallTimeout(msg.err) {
+			return m, m.loadTimedOut("Loading namespace "+m.k8sClient.Namespace(), repository.CallList, m.loadInitialDataWithResources())
+		}
 		m.loading = false
+		if repository.IsConnectionError(msg.err) {
+			m.started = false
+			m.connLost = msg.err
+			return m, nil
+		}
 		if msg.err != nil {
 			m.err = msg.err
 			return m, nil
 		}
+		m.timedOut = nil
 		m.navigator.SetNamespaces(msg.namespaces)
 		m.nodes = msg.nodes
 		m.navigator.SetPods(msg.pods)
 		m.navigator.SetHPAs(msg.hpas)
 		m.navigator.SetConfigMaps(msg.configmaps)
 		m.navigator.SetSecrets(msg.secrets)
+		m.navigator.SetProbeHealth(msg.probeHealth)
 		m.navigator.SetMode(component.ModeResources)
-		return m, nil
+		ns := m.k8sClient.Namespace()
+		m.navigator.SetLocation(m.isProtected(ns), ns)
+		return m, tea.Batch(m.continuePods(ns, msg.continueToken), m.expireChanges())
+
+	case podsPageMsg:
+		// Drop pages of a listing that was replaced in the meantime
+		if msg.token != m.podsContinue || msg.namespace != m.k8sClient.Namespace() {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.podsContinue = ""
+			m.navigator.SetPodsLoadingMore(false)
+			return m, m.notifyError("list more pods", msg.namespace, msg.err)
+		}
+		m.navigator.AppendPods(msg.pods)
+		return m, m.continuePods(msg.namespace, msg.next)
+
+	case deepLinkResolvedMsg:
+		m.loading = false
+		if msg.err != nil {
+			return m, m.notifyError("open link", "", msg.err)
+		}
+		if msg.workload != nil {
+			m.workload = msg.workload
+			m.loading = true
+			return m, m.loadPods(msg.workload)
+		}
+		cmd = m.openPodDashboard(msg.pod)
+		if msg.link.Container != "" && !m.dashboard.SelectContainer(msg.link.Container) {
+			m.statusMsg = fmt.Sprintf("Container %s not found in pod %s", msg.link.Container, msg.pod.Name)
+		}
+		if msg.link.View != "" {
+			m.dashboard.FocusView(msg.link.View)
+		}
+		// Follow the linked container rather than all of them
+		return m, tea.Batch(cmd, m.syncLogStream())
+
+	case targetResolvedMsg:
+		m.loading = false
+		if msg.err != nil {
+			return m, m.notifyError("open object", "", msg.err)
+		}
+		return m, m.openTarget(msg.target)
 
 	case configMapDataMsg:
 		m.loading = false
 		if msg.err != nil {
-			m.statusMsg = "Error loading ConfigMap: " + msg.err.Error()
-			return m, nil
+			return m, m.notifyError("get ConfigMap", m.k8sClient.Namespace(), msg.err)
 		}
 		m.configMapViewer.SetSize(m.width, m.height)
 		m.configMapViewer.SetNamespaces(m.navigator.GetActiveNamespaceNames())
+		m.configMapViewer.SetChanges(m.configChanges(false, m.k8sClient.Namespace(), msg.data.Name, msg.data.ResourceVersion, msg.data.Data))
 		m.configMapViewer.Show(msg.data, m.k8sClient.Namespace())
 		return m, nil
 
@@ -227,11 +809,96 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// ConfigMap viewer was closed, nothing special to do
 		return m, nil
 
+	case component.ConfigMapEditRequest:
+		if m.refuseReadOnly("Edit ConfigMap") {
+			m.configMapViewer.SetStatusMsg(m.statusMsg)
+			return m, clearStatusAfter(3 * time.Second)
+		}
+		return m, m.editConfigValue(configEdit{
+			namespace:       msg.Namespace,
+			name:            msg.Name,
+			key:             msg.Key,
+			value:           msg.Value,
+			resourceVersion: msg.ResourceVersion,
+		})
+
+	case component.SecretEditRequest:
+		if m.refuseReadOnly("Edit Secret") {
+			m.secretViewer.SetStatusMsg(m.statusMsg)
+			return m, clearStatusAfter(3 * time.Second)
+		}
+		return m, m.editConfigValue(configEdit{
+			secret:          true,
+			namespace:       msg.Namespace,
+			name:            msg.Name,
+			key:             msg.Key,
+			value:           msg.Value,
+			resourceVersion: msg.ResourceVersion,
+		})
+
+	case component.ConfigMapDeleteRequest:
+		return m, m.requestDeleteConfig(false, msg.Namespace, msg.Name)
+
+	case component.SecretDeleteRequest:
+		return m, m.requestDeleteConfig(true, msg.Namespace, msg.Name)
+
+	case configValueEditedMsg:
+		if msg.err != nil {
+			m.setConfigStatus(msg.edit.secret, "Error: "+msg.err.Error())
+			return m, clearStatusAfter(5 * time.Second)
+		}
+		if msg.value == msg.edit.value {
+			m.setConfigStatus(msg.edit.secret, "No changes to "+msg.edit.key)
+			return m, clearStatusAfter(3 * time.Second)
+		}
+		edit := msg.edit
+		edit.value = msg.value
+		return m, m.requestSaveConfigValue(edit)
+
+	case configValueSavedMsg:
+		if repository.IsConflict(msg.err) {
+			// Someone else updated the object meanwhile: offer to save over it
+			edit := msg.edit
+			edit.resourceVersion = ""
+			title := configKindName(edit.secret) + " changed"
+			message := fmt.Sprintf("'%s' changed since you opened it. Save your edit of '%s' over the latest version?", edit.name, edit.key)
+			if m.isProtected(edit.namespace) {
+				m.confirmDialog.ShowTyped(title, message, "save_config_value", edit.name, edit)
+			} else {
+				m.confirmDialog.Show(title, message, "save_config_value", edit)
+			}
+			return m, nil
+		}
+		if msg.err != nil {
+			// The viewer covers the status bar, so it shows the error too
+			m.setConfigStatus(msg.edit.secret, "Error: "+msg.err.Error())
+			notify := m.notifyError("save "+configKindName(msg.edit.secret)+" "+msg.edit.name, msg.edit.namespace, msg.err)
+			return m, tea.Batch(notify, clearStatusAfter(5*time.Second))
+		}
+		m.setConfigStatus(msg.edit.secret, "Saved "+msg.edit.key)
+		if msg.edit.secret {
+			return m, tea.Batch(m.loadSecretData(msg.edit.name), clearStatusAfter(3*time.Second))
+		}
+		return m, tea.Batch(m.loadConfigMapData(msg.edit.name), clearStatusAfter(3*time.Second))
+
+	case configDeletedMsg:
+		if msg.err != nil {
+			m.setConfigStatus(msg.secret, "Error: "+msg.err.Error())
+			notify := m.notifyError("delete "+configKindName(msg.secret)+" "+msg.name, m.k8sClient.Namespace(), msg.err)
+			return m, tea.Batch(notify, clearStatusAfter(5*time.Second))
+		}
+		if msg.secret {
+			m.secretViewer.Hide()
+		} else {
+			m.configMapViewer.Hide()
+		}
+		m.statusMsg = fmt.Sprintf("Deleted %s %s", configKindName(msg.secret), msg.name)
+		return m, tea.Batch(m.refreshPods(), clearStatusAfter(3*time.Second))
+
 	case secretDataMsg:
 		m.loading = false
 		if msg.err != nil {
-			m.statusMsg = "Error loading Secret: " + msg.err.Error()
-			return m, nil
+			return m, m.notifyError("get Secret", m.k8sClient.Namespace(), msg.err)
 		}
 		// Show appropriate viewer based on secret type
 		if m.isDockerRegistrySecret {
@@ -241,6 +908,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.secretViewer.SetSize(m.width, m.height)
 			m.secretViewer.SetNamespaces(m.navigator.GetActiveNamespaceNames())
+			m.secretViewer.SetChanges(m.configChanges(true, m.k8sClient.Namespace(), msg.data.Name, msg.data.ResourceVersion, msg.data.Data))
 			m.secretViewer.Show(msg.data, m.k8sClient.Namespace())
 		}
 		return m, nil
@@ -252,23 +920,67 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case hpaDataMsg:
 		m.loading = false
 		if msg.err != nil {
-			m.statusMsg = "Error loading HPA: " + msg.err.Error()
-			return m, nil
+			return m, m.notifyError("get HPA", m.k8sClient.Namespace(), msg.err)
 		}
 		m.hpaViewer.SetSize(m.width, m.height)
-		m.hpaViewer.Show(msg.data, m.k8sClient.Namespace())
+		m.hpaViewer.Show(msg.data, msg.data.Namespace)
 		return m, nil
 
 	case component.HPAViewerClosed:
 		// HPA viewer was closed
 		return m, nil
 
+	case metricsStatusMsg:
+		m.dashboard.SetMetricsStatus(msg.status)
+		return m, nil
+
+	case podUsageMsg:
+		// Ignore a refresh that lands after the table was closed or reopened
+		if m.podTopViewer.IsVisible() && m.podTopViewer.Namespace() == msg.namespace {
+			m.podTopViewer.SetUsage(msg.usage, msg.err)
+		}
+		return m, nil
+
+	case component.PodTopViewerClosed:
+		return m, nil
+
+	case nodeCapacityMsg:
+		// Ignore a refresh that lands after the table was closed or reopened
+		if m.nodeCapacityViewer.IsVisible() && m.nodeCapacityViewer.Pod() == msg.pod {
+			m.nodeCapacityViewer.SetCapacity(msg.nodes, msg.err)
+		}
+		return m, nil
+
+	case component.NodeCapacityViewerClosed:
+		return m, nil
+
+	case view.NodeCapacityRequest:
+		m.telemetry.View("node-capacity")
+		m.nodeCapacityViewer.SetSize(m.width, m.height)
+		m.nodeCapacityViewer.Show(msg.Pod)
+		return m, m.loadNodeCapacity(msg.Pod)
+
+	case component.NamespaceCompareRequest:
+		m.telemetry.Action("namespace-compare")
+		return m, m.compareNamespaces(msg.Left, msg.Right)
+
+	case namespaceCompareMsg:
+		m.namespaceCompare.SetDiff(msg.diff, msg.err)
+		return m, nil
+
+	case component.NamespaceCompareClosed:
+		return m, nil
+
 	case component.SecretViewerClosed:
 		// Secret viewer was closed, nothing special to do
 		return m, nil
 
 	case component.SecretCopyRequest:
 		// Handle secret copy request
+		if m.refuseReadOnly("Copy Secret") {
+			m.secretViewer.SetStatusMsg(m.statusMsg)
+			return m, clearStatusAfter(3 * time.Second)
+		}
 		if msg.AllNamespaces {
 			// Filter out source namespace and start progress flow
 			var namespaces []string
@@ -305,17 +1017,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case component.SecretCopyResult:
 		// Show result
 		var statusText string
+		var notify tea.Cmd
 		if msg.Success {
 			statusText = msg.Message
 		} else if msg.Err != nil {
+			notify = m.notifyError("copy Secret", "", msg.Err)
 			statusText = "Error: " + msg.Err.Error()
 		} else {
 			statusText = msg.Message
 		}
-		m.statusMsg = statusText
+		if notify == nil {
+			// A failure is in the toast already
+			m.statusMsg = statusText
+		}
 		m.secretViewer.SetStatusMsg(statusText)
 		// Clear status after showing result
-		return m, clearStatusAfter(3 * time.Second)
+		return m, tea.Batch(notify, clearStatusAfter(3*time.Second))
 
 	case component.ConfigMapCopyProgress:
 		// Continue copying to next namespace
@@ -327,17 +1044,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case component.ConfigMapCopyResult:
 		// Show result
 		var statusText string
+		var notify tea.Cmd
 		if msg.Success {
 			statusText = msg.Message
 		} else if msg.Err != nil {
+			notify = m.notifyError("copy ConfigMap", "", msg.Err)
 			statusText = "Error: " + msg.Err.Error()
 		} else {
 			statusText = msg.Message
 		}
-		m.statusMsg = statusText
+		if notify == nil {
+			// A failure is in the toast already
+			m.statusMsg = statusText
+		}
 		m.configMapViewer.SetStatusMsg(statusText)
 		// Clear status after showing result
-		return m, clearStatusAfter(3 * time.Second)
+		return m, tea.Batch(notify, clearStatusAfter(3*time.Second))
 
 	case component.DockerRegistryCopyProgress:
 		// Continue copying to next namespace
@@ -349,44 +1071,87 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case component.DockerRegistryCopyResult:
 		// Show result
 		var statusText string
+		var notify tea.Cmd
 		if msg.Success {
 			statusText = msg.Message
 		} else if msg.Err != nil {
+			notify = m.notifyError("copy registry Secret", "", msg.Err)
 			statusText = "Error: " + msg.Err.Error()
 		} else {
 			statusText = msg.Message
 		}
-		m.statusMsg = statusText
+		if notify == nil {
+			// A failure is in the toast already
+			m.statusMsg = statusText
+		}
 		m.dockerRegistryViewer.SetStatusMsg(statusText)
 		// Clear status after showing result
-		return m, clearStatusAfter(3 * time.Second)
+		return m, tea.Batch(notify, clearStatusAfter(3*time.Second))
 
 	case nodePodLoadedMsg:
+		if repository.IsCallTimeout(msg.err) && m.loading {
+			return m, m.loadTimedOut("Listing pods on node "+msg.nodeName, repository.CallList, m.loadPodsByNode(msg.nodeName))
+		}
 		m.loading = false
 		if msg.err != nil {
-			m.statusMsg = "Error loading pods: " + msg.err.Error()
-			return m, nil
+			return m, m.notifyError("list pods on node "+msg.nodeName, "", msg.err)
 		}
+		m.timedOut = nil
 		m.selectedNode = msg.nodeName
 		m.navigator.SetPods(msg.pods)
 		m.navigator.SetHPAs(nil)       // Clear HPAs for node view
 		m.navigator.SetConfigMaps(nil) // Clear configmaps for node view
 		m.navigator.SetSecrets(nil)    // Clear secrets for node view
 		m.navigator.SetMode(component.ModeResources)
+		m.navigator.SetLocation(false, "node", msg.nodeName)
+		return m, tea.Batch(m.reconcilePods("", msg.pods), m.expireChanges())
+
+	case accessChecksMsg:
+		if m.pod != nil && m.pod.Name == msg.pod && m.pod.Namespace == msg.namespace {
+			m.dashboard.SetAccessChecks(msg.checks, msg.err)
+		}
 		return m, nil
 
 	case dashboardDataMsg:
+		// Opening the pod timed out; a refresh that did keeps what is shown
+		if m.loading && m.pod != nil && msg.timedOut() {
+			return m, m.loadTimedOut("Loading pod "+m.pod.Name, repository.CallLogs, m.loadDashboardData(m.pod))
+		}
 		m.loading = false
+		m.timedOut = nil
 		// Update pod info for real-time status
 		if msg.pod != nil {
+			// Reattach right away rather than after the stream's
+			// reconnect back-off
+			if m.followedContainerRestarted(m.pod, msg.pod) {
+				m.stopLogStream()
+			}
 			m.pod = msg.pod
 			m.dashboard.SetPod(msg.pod)
 		}
-		m.dashboard.SetLogs(msg.logs)
-		m.dashboard.SetEvents(msg.events)
+		// A followed log stream is fresher than the polled logs, logs
+		// polled before workload mode was toggled are stale, and a time
+		// range is fetched once rather than polled. A panel shown
+		// fullscreen keeps its content while text is being copied from it
+		if m.logStream == nil && !m.dashboard.LogsComparing() && !m.dashboard.LogsFrozen() &&
+			msg.workloadLogs == m.dashboard.LogsWorkloadMode() && m.dashboard.LogsTimeRange().IsZero() {
+			m.dashboard.SetLogs(msg.logs)
+		}
+		// Likewise the event watch
+		if m.eventWatch == nil && !m.dashboard.EventsFrozen() {
+			m.dashboard.SetEvents(msg.events)
+		}
+		m.dashboard.SetMetricsStatus(msg.metricsStatus)
 		m.dashboard.SetMetrics(msg.metrics)
 		m.dashboard.SetRelated(msg.related)
 		m.dashboard.SetHelpers(msg.helpers)
+		m.dashboard.SetDiagnosis(msg.diagnosis)
+		m.dashboard.SetProbes(msg.probes)
+		m.dashboard.SetOOM(msg.oom)
+		m.dashboard.SetSecurity(msg.security)
+		m.dashboard.SetImagePulls(msg.imagePulls)
+		m.dashboard.SetImagePullProgress(msg.pullProgress)
+		m.dashboard.SetLimitRanges(msg.limitRanges)
 		m.dashboard.SetNode(msg.node)
 		// Pass workload info to navigator for scale controls when no pods
 		if msg.related != nil && msg.related.Owner != nil && msg.related.Owner.WorkloadKind != "" {
@@ -409,57 +1174,928 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Replicas:  msg.related.Owner.Replicas,
 			})
 		}
+		cmds := []tea.Cmd{m.expireChanges(), m.syncLogStream(), m.restartCountdown()}
+		for _, failure := range msg.failures {
+			cmds = append(cmds, m.notify(failure))
+		}
+		// SetPod switches the logs to the failing init container once the
+		// pod gets stuck in init
+		return m, tea.Batch(cmds...)
+
+	case logsUpdatedMsg:
+		if m.logStream == nil && !m.dashboard.LogsComparing() &&
+			msg.workload == m.dashboard.LogsWorkloadMode() && msg.timeRange == m.dashboard.LogsTimeRange() {
+			m.dashboard.SetLogs(msg.logs)
+		}
+		return m, nil
+
+	case logsComparedMsg:
+		// Dropped if compare mode was left while loading
+		if m.dashboard.LogsComparing() {
+			m.dashboard.SetComparedLogs(msg.logs, msg.hasPrevious)
+		}
+		return m, nil
+
+	case logLinesMsg:
+		// Lines from a stream that was stopped or replaced are dropped
+		if m.logStream == nil || msg.stream != m.logStream.id {
+			return m, nil
+		}
+		m.dashboard.AppendLogs(msg.lines)
+		if msg.closed {
+			m.logStream = nil
+			return m, nil
+		}
+		return m, waitForLogLines(m.logStream)
+
+	case eventsWatchedMsg:
+		// Events from a watch that was stopped or replaced are dropped
+		if m.eventWatch == nil || msg.watch != m.eventWatch.id {
+			return m, nil
+		}
+		m.dashboard.AddEvents(msg.events)
+		if msg.closed {
+			m.eventWatch = nil
+			return m, nil
+		}
+		return m, tea.Batch(waitForEvents(m.eventWatch), m.expireChanges())
+
+	case view.DeletePodRequest:
+		// Shown struck through until a refresh confirms the deletion
+		m.mutations.Begin(component.PendingMutation{
+			Hint:   podHint(msg.Namespace, msg.PodName),
+			Action: component.MutationDelete,
+		})
+		return m, m.deletePod(msg)
+
+	case view.ForceDeletePodRequest:
+		m.mutations.Begin(component.PendingMutation{
+			Hint:   podHint(msg.Namespace, msg.PodName),
+			Action: component.MutationDelete,
+		})
+		return m, m.forceDeletePod(msg.Namespace, msg.PodName)
+
+	case podStillTerminatingMsg:
+		if m.confirmDialog.IsVisible() {
+			// Don't replace a question the user is answering
+			m.statusMsg = "Pod " + msg.podName + " still terminating; delete it again to force delete"
+			return m, clearStatusAfter(10 * time.Second)
+		}
+		cmd := m.confirmDialog.Request(
+			m.confirmLevel(msg.namespace, configs.ActionForceDeletePod),
+			"Force Delete Pod",
+			view.ForceDeletePodMessage(msg.podName),
+			"force_delete_pod",
+			msg.podName,
+			view.ForceDeletePodRequest{Namespace: msg.namespace, PodName: msg.podName},
+		)
+		return m, cmd
+
+	case view.RestartPodRequest:
+		return m, m.restartPod(msg.Namespace, msg.PodName)
+
+	case view.PodRestartedMsg:
+		if m.view != ViewDashboard || m.pod == nil || m.pod.Name != msg.OldName {
+			// The user moved on while waiting for the replacement
+			if msg.Err != nil {
+				m.statusMsg = "Failed to restart pod: " + msg.Err.Error()
+			} else {
+				m.statusMsg = fmt.Sprintf("Restarted %s, replaced by %s", msg.OldName, msg.Pod.Name)
+			}
+			return m, clearStatusAfter(5 * time.Second)
+		}
+		var cmd tea.Cmd
+		m.dashboard, cmd = m.dashboard.Update(msg)
+		if msg.Err != nil {
+			return m, cmd
+		}
+		// Follow the logs and events of the replacement
+		return m, tea.Batch(cmd, m.openPodDashboard(msg.Pod))
+
+	case view.RestartWorkloadRequest:
+		gvr, ok := repository.GVRForKind(msg.Kind)
+		if !ok {
+			return m, nil
+		}
+		workload := &repository.WorkloadInfo{
+			Name:      msg.Name,
+			Namespace: msg.Namespace,
+			Type:      repository.ResourceType(gvr.Resource),
+		}
+		m.loading = true
+		return m, m.restartWorkload(workload)
+
+	case view.ExecRequest:
+		return m, m.execShell(msg.Namespace, msg.PodName, msg.Container)
+
+	case view.AttachRequest:
+		return m, m.attachContainer(msg.Namespace, msg.PodName, msg.Container)
+
+	case view.ProbeRunRequest:
+		m.telemetry.Action("run-probe")
+		return m, m.runProbe(msg)
+
+	case view.PortForwardRequest:
+		m.telemetry.Action("port-forward")
+		return m, m.startPortForward(msg)
+
+	case view.PortForwardMsg:
+		var inUse *repository.PortInUseError
+		if errors.As(msg.Err, &inUse) && inUse.Suggested > 0 {
+			req := msg.Request
+			req.LocalPort = inUse.Suggested
+			m.confirmDialog.Show(
+				"Port In Use",
+				fmt.Sprintf("Local port %d is already in use.\nForward from localhost:%d instead?", inUse.Port, inUse.Suggested),
+				"port_forward_local_port",
+				req,
+			)
+		}
+		if m.portForwardsViewer.IsVisible() {
+			m.portForwardsViewer.SetForwards(m.portForwarder.List())
+		}
+		if m.view == ViewDashboard {
+			var cmd tea.Cmd
+			m.dashboard, cmd = m.dashboard.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case component.StopPortForwardRequest:
+		return m, m.stopPortForward(msg.ID)
+
+	case portForwardStoppedMsg:
+		m.portForwardsViewer.SetForwards(m.portForwarder.List())
+		if msg.err != nil {
+			m.statusMsg = "Stop failed: " + msg.err.Error()
+		} else {
+			m.statusMsg = fmt.Sprintf("Stopped port-forward from localhost:%d", msg.forward.LocalPort)
+		}
+		return m, clearStatusAfter(3 * time.Second)
+
+	case component.PortForwardsViewerClosed:
+		return m, nil
+
+	case view.RemoveSchedulingGateRequest:
+		return m, m.removeSchedulingGate(msg.Namespace, msg.PodName, msg.Gate)
+
+	case view.HPADetailsRequest:
+		m.telemetry.View("hpa")
+		return m, m.loadHPAData(msg.Namespace, msg.Name)
+
+	case view.PVCDetailsRequest:
+		return m, m.loadPVCDetails(msg.Namespace, msg.Name)
+
+	case view.DescribeResourceRequest:
+		return m, m.describeResource(msg)
+
+	case view.ConfigProjectionRequest:
+		m.telemetry.Action("config-projection")
+		return m, m.loadConfigProjection(msg)
+
+	case view.ServiceCheckRequest:
+		m.telemetry.Action("service-check")
+		return m, m.checkService(msg)
+
+	case view.IngressTraceRequest:
+		m.telemetry.Action("ingress-trace")
+		return m, m.traceIngress(msg)
+
+	case view.WatchRequest:
+		return m, m.addWatch(repository.WorkloadInfo{Name: msg.PodName, Namespace: msg.Namespace, Type: repository.ResourcePods}, msg.Condition)
+
+	case component.CancelWatchRequest:
+		if w, ok := m.removeWatch(msg.ID); ok {
+			m.statusMsg = fmt.Sprintf("Stopped watching %s: %s", w.TargetName(), w.Condition.Label())
+		}
+		return m, clearStatusAfter(3 * time.Second)
+
+	case watchesCheckedMsg:
+		return m, m.watchesChecked(msg)
+
+	case component.WatchesViewerClosed:
+		return m, nil
+
+	case component.PaletteIndexRequest:
+		return m, m.loadPaletteIndex(msg.Namespaces)
+
+	case paletteIndexMsg:
+		// Listed from the cluster before a context switch
+		if msg.context != m.k8sClient.Context() {
+			return m, nil
+		}
+		m.palette.SetIndex(msg.namespace, msg.items, msg.err)
+		return m, nil
+
+	case component.PaletteSelected:
+		return m, m.jumpTo(msg.Item)
+
+	case component.PaletteClosed:
+		return m, nil
+
+	case recentWarningsMsg:
+		// A namespace switched meanwhile will load its own
+		if msg.namespace != m.k8sClient.Namespace() {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.recentWarnings = nil
+			return m, nil
+		}
+		m.recentWarnings = msg.events
+		if m.warningsViewer.IsVisible() {
+			m.warningsViewer.SetWarnings(msg.namespace, msg.events)
+		}
+		return m, nil
+
+	case component.JumpToWarningRequest:
+		m.loading = true
+		return m, m.resolveWarning(msg)
+
+	case warningTargetMsg:
+		m.loading = false
+		if msg.err != nil {
+			return m, m.notifyError("open "+msg.object, m.k8sClient.Namespace(), msg.err)
+		}
+		if msg.target == nil {
+			m.warningsViewer.ShowDetail(msg.object, msg.object+" no longer exists or has no pods to open; its events:")
+			return m, nil
+		}
+		m.warningsViewer.Hide()
+		return m, m.openTarget(msg.target)
+
+	case component.WarningsViewerClosed:
+		return m, nil
+
+	case connectionCheckedMsg:
+		m.connChecking = false
+		if msg.err != nil {
+			m.connLost = msg.err
+			return m, nil
+		}
+		lost := m.connLost != nil
+		m.connLost = nil
+		if !m.started {
+			return m, m.startupLoads()
+		}
+		if lost {
+			// Back where the user was, with fresh data
+			m.statusMsg = "Reconnected to " + m.k8sClient.Context()
+			return m, tea.Batch(m.refresh(), clearStatusAfter(3*time.Second))
+		}
+		return m, nil
+
+	case component.ToastExpired:
+		m.notifications.Expire(msg.ID)
+		return m, nil
+
+	case component.ErrorLogCleared:
+		m.notifications.Clear()
+		return m, nil
+
+	case component.ErrorLogViewerClosed:
+		return m, nil
+
+	case view.DebugContainerRequest:
+		m.telemetry.Action("debug-container")
+		return m, m.addDebugContainer(msg)
+
+	case view.DebugContainerMsg:
+		if m.view != ViewDashboard || m.pod == nil || m.pod.Name != msg.PodName {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.dashboard, cmd = m.dashboard.Update(msg)
+		if msg.Err != nil {
+			return m, cmd
+		}
+		// Show the new container in Pod Details once the shell exits
+		return m, tea.Batch(cmd, m.execShell(m.pod.Namespace, m.pod.Name, msg.Container), m.loadDashboardData(m.pod))
+
+	case view.ResourceYAMLRequest:
+		return m, m.requestResourceYAML(msg.Namespace, msg.Kind, msg.Name, msg.Save)
+
+	case component.ReadOnlyMsg:
+		if m.view == ViewDashboard {
+			var cmd tea.Cmd
+			m.dashboard, cmd = m.dashboard.Update(msg)
+			return m, cmd
+		}
+		m.statusMsg = msg.Status()
+		return m, clearStatusAfter(3 * time.Second)
+
+	case view.FileBrowserRequest:
+		m.fileBrowser.SetSize(m.width, m.height)
+		return m, m.fileBrowser.Show(msg.Namespace, msg.PodName, msg.Container, "/")
+
+	case component.FileBrowserListRequest:
+		return m, m.listContainerDir(msg.Path)
+
+	case component.FileBrowserReadRequest:
+		return m, m.readContainerFile(msg.Path)
+
+	case component.FileBrowserCopyRequest:
+		m.requestContainerCopy(msg.Path)
+		return m, nil
+
+	case containerDirMsg:
+		if m.isFileBrowserTarget(msg.pod, msg.container) {
+			m.fileBrowser.SetEntries(msg.path, msg.entries, msg.err)
+		}
+		return m, nil
+
+	case containerFileMsg:
+		if !m.isFileBrowserTarget(msg.pod, msg.container) {
+			return m, nil
+		}
+		// Links to directories are only known to be directories once read
+		if errors.Is(msg.err, repository.ErrIsDirectory) {
+			return m, m.listContainerDir(msg.path)
+		}
+		m.fileBrowser.SetPreview(msg.path, msg.content, msg.err)
+		return m, nil
+
+	case containerCopyMsg:
+		if msg.err != nil {
+			m.fileBrowser.SetStatus("", fmt.Errorf("copy of %s failed: %w", msg.path, msg.err))
+		} else {
+			m.fileBrowser.SetStatus(fmt.Sprintf("Copied %s to %s", msg.path, msg.local), nil)
+		}
+		return m, nil
+
+	case component.SavePanelRequest:
+		m.requestPanelSave(msg)
+		return m, nil
+
+	case panelSavedMsg:
+		return m, m.handlePanelSaved(msg)
+
+	case view.SnapshotRequest:
+		if m.snapshotUpdates != nil {
+			return m, m.showSnapshotStatus("A snapshot export is already running")
+		}
+		m.requestSnapshot(msg.Namespace, msg.PodName)
+		return m, nil
+
+	case component.InputDialogResult:
+		if target, ok := msg.Data.(yamlTarget); ok && msg.Action == "save_yaml" {
+			m.statusMsg = "Saving " + target.kind + " YAML..."
+			return m, m.exportResourceYAML(target, msg.Value)
+		}
+		if target, ok := msg.Data.(fileCopyTarget); ok && msg.Action == "copy_from_container" {
+			m.fileBrowser.SetStatus("Copying "+target.path+"...", nil)
+			return m, m.copyFromContainer(target, msg.Value)
+		}
+		if target, ok := msg.Data.(snapshotTarget); ok && msg.Action == "save_snapshot" && m.snapshotUpdates == nil {
+			m.showSnapshotStatus("Exporting snapshot of " + target.pod + "...")
+			return m, m.startSnapshot(target, msg.Value)
+		}
+		if change, ok := msg.Data.(imageChange); ok && msg.Action == "set_image" {
+			change.image = strings.TrimSpace(msg.Value)
+			return m, m.requestSetImage(change)
+		}
+		if change, ok := msg.Data.(resourcesChange); ok && msg.Action == "set_resources" {
+			return m, m.requestSetResources(change, msg.Value)
+		}
+		if target, ok := msg.Data.(panelSaveTarget); ok && msg.Action == "save_panel" {
+			if strings.TrimSpace(msg.Value) == "" {
+				return m, nil
+			}
+			return m, savePanel(target, strings.TrimSpace(msg.Value), false)
+		}
+		if msg.Action == "create_namespace" {
+			name, nsLabels, err := parseNamespaceInput(msg.Value)
+			if err != nil {
+				m.statusMsg = err.Error()
+				return m, clearStatusAfter(5 * time.Second)
+			}
+			m.statusMsg = fmt.Sprintf("Creating namespace %s...", name)
+			return m, m.createNamespace(name, nsLabels)
+		}
+		if msg.Action == "column_label" {
+			columns := m.navigator.Columns()
+			if key := strings.TrimSpace(msg.Value); key != "" {
+				columns = columns.WithLabel(key)
+			} else if columns.Enabled(component.ColumnLabel) {
+				columns = columns.Toggle(component.ColumnLabel)
+			}
+			m.navigator.SetColumns(columns)
+			m.columnPicker.SetColumns(columns)
+		}
+		return m, nil
+
+	case component.ColumnsChanged:
+		m.navigator.SetColumns(msg.Columns)
+		return m, nil
+
+	case component.ColumnLabelKeyRequest:
+		m.inputDialog.Show("Label Column", "Show the value of label:", "column_label", msg.Current, nil)
+		return m, nil
+
+	case snapshotProgressMsg:
+		cmd := m.showSnapshotStatus(fmt.Sprintf("Exporting snapshot of %s: %s...", msg.pod, msg.section))
+		return m, tea.Batch(cmd, waitForSnapshot(m.snapshotUpdates))
+
+	case sessionRecordFailedMsg:
+		return m, m.notifyError("record session", m.k8sClient.Namespace(), msg.err)
+
+	case snapshotFinishedMsg:
+		m.snapshotUpdates = nil
+		cmd := m.showSnapshotStatus(snapshotStatus(msg))
+		if m.view != ViewDashboard {
+			cmd = clearStatusAfter(5 * time.Second)
+		}
+		return m, cmd
+
+	case resourceYAMLMsg:
+		result := view.ResourceYAMLResultMsg{Kind: msg.kind, Name: msg.name, Path: msg.path, Err: msg.err}
+		if result.Err == nil && msg.path == "" {
+			copied, err := component.CopyToClipboard(msg.yaml)
+			if err != nil {
+				result.Err = fmt.Errorf("copy failed: %w", err)
+			}
+			result.Via = copied.Via()
+		}
+		if m.view == ViewDashboard {
+			var cmd tea.Cmd
+			m.dashboard, cmd = m.dashboard.Update(result)
+			return m, cmd
+		}
+		m.statusMsg = result.Status()
+		return m, clearStatusAfter(5 * time.Second)
+
+	case view.PVCDetailsMsg:
+		if m.view == ViewDashboard {
+			var cmd tea.Cmd
+			m.dashboard, cmd = m.dashboard.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case view.SchedulingGateRemovedMsg:
+		if m.view == ViewDashboard {
+			var cmd tea.Cmd
+			m.dashboard, cmd = m.dashboard.Update(msg)
+			if msg.Err == nil && m.pod != nil {
+				return m, tea.Batch(cmd, m.loadDashboardData(m.pod))
+			}
+			return m, cmd
+		}
+		return m, nil
+
+	case podDeletedMsg:
+		m.mutations.Settle(msg.hint, msg.err)
+		if msg.err != nil {
+			op := "delete pod " + msg.podName
+			if msg.forced {
+				op = "force delete pod " + msg.podName
+			}
+			return m, m.notifyError(op, msg.namespace, msg.err)
+		}
+		// Offered after a delete rather than for the pod shown, the force
+		// delete leaves alone whatever the user moved on to
+		if msg.forced && (m.view != ViewDashboard || m.pod == nil || m.pod.Name != msg.podName) {
+			m.statusMsg = "Force deleted pod " + msg.podName
+			return m, tea.Batch(m.refreshPods(), clearStatusAfter(5*time.Second))
+		}
+		// Go back to pods list after deletion and re-fetch it right away
+		m.view = ViewNavigator
+		m.pod = nil
+		m.stopLogStream()
+		m.stopEventWatch()
+		m.leavePodScope()
+		m.navigator.SetMode(component.ModeResources)
+		if msg.forced {
+			return m, m.refreshPods()
+		}
+		return m, tea.Batch(m.refreshPods(), m.checkPodTerminated(msg.namespace, msg.podName, msg.uid, msg.gracePeriod))
+
+	case namespaceDeletedMsg:
+		if msg.err != nil {
+			return m, m.notifyError("delete namespace "+msg.namespace, "", msg.err)
+		}
+		m.statusMsg = fmt.Sprintf("Namespace %s deleted", msg.namespace)
+		if msg.terminating {
+			m.statusMsg = fmt.Sprintf("Deleting namespace %s: Terminating until its resources are gone", msg.namespace)
+			m.setNamespaceStatus(msg.namespace, "Terminating")
+		}
+		// Refresh namespace list
+		return m, tea.Batch(m.loadInitialData(), clearStatusAfter(3*time.Second))
+
+	case namespaceCreatedMsg:
+		if msg.err != nil {
+			return m, m.notifyError("create namespace "+msg.namespace, "", msg.err)
+		}
+		m.statusMsg = fmt.Sprintf("Namespace %s created", msg.namespace)
+		m.setNamespaceStatus(msg.namespace, "Active")
+		return m, tea.Batch(m.loadInitialData(), clearStatusAfter(3*time.Second))
+
+	case component.WorkloadActionMenuResult:
+		// Bulk actions on the pods selected in the pod list
+		switch msg.Item.Action {
+		case "bulk-delete":
+			return m, m.requestBulkDelete(m.bulkPods)
+		case "bulk-copy-names":
+			m.telemetry.Action("copy-pod-names")
+			return m, m.copyPodNames(m.bulkPods)
+		}
+		workload := m.workloadMenuTarget
+		if workload == nil {
+			return m, nil
+		}
+		switch msg.Item.Action {
+		case "scale":
+			return m, m.requestScale(workload, msg.Item.Replicas)
+		case "promote", "abort", "retry":
+			return m, m.requestRolloutAction(workload, msg.Item.Action)
+		case "trigger":
+			return m, m.requestTriggerCronJob(workload)
+		case "ds-nodes":
+			m.loading = true
+			return m, m.loadDaemonSetNodes(workload)
+		case "runs":
+			m.loading = true
+			return m, m.loadCronJobRuns(workload)
+		case "history":
+			m.loading = true
+			return m, m.loadDeploymentHistory(workload)
+		case "hpa":
+			m.loading = true
+			m.telemetry.View("hpa")
+			return m, m.loadWorkloadHPA(workload)
+		case "rollback":
+			return m, m.requestRollback(workload, msg.Item.Revision)
+		case "sts-details", "partitions":
+			m.loading = true
+			return m, m.loadStatefulSetDetails(workload, msg.Item.Action == "partitions")
+		case "partition":
+			return m, m.requestPartition(workload, msg.Item.Partition)
+		case "images":
+			m.loading = true
+			return m, m.loadWorkloadImages(workload)
+		case "set-image":
+			m.editImage(workload, msg.Item.Container, msg.Item.Image)
+		case "resources":
+			m.loading = true
+			return m, m.loadWorkloadResources(workload)
+		case "resources-table":
+			m.telemetry.View("resources")
+			m.resultViewer.Show("Resources: "+workload.Name, component.RenderWorkloadResources(m.workloadResources), m.width-4, m.height-4)
+		case "set-resources":
+			m.editResources(workload, msg.Item.Resources)
+		case "helm":
+			m.loading = true
+			return m, m.loadHelmRelease(workload)
+		case "watch":
+			return m, m.addWatch(*workload, msg.Item.Condition)
+		case "copy-yaml", "save-yaml":
+			kind := repository.KindForResourceType(workload.Type)
+			return m, m.requestResourceYAML(workload.Namespace, kind, workload.Name, msg.Item.Action == "save-yaml")
+		case "copy":
+			m.telemetry.Action("copy-command")
+			copied, err := component.CopyToClipboard(msg.Item.Command)
+			if err == nil {
+				m.statusMsg = "Copied: " + msg.Item.Label + copied.Via()
+			} else {
+				m.statusMsg = "Copy failed: " + err.Error()
+			}
+		}
+		return m, nil
+
+	case component.NodeActionMenuResult:
+		m.telemetry.Action(msg.Item.Action)
+		switch msg.Item.Action {
+		case "simulate-drain":
+			m.loading = true
+			m.statusMsg = "Simulating drain of " + msg.Item.Node + "..."
+			return m, m.simulateDrain(msg.Item.Node)
+		case "cordon", "uncordon":
+			return m, m.requestNodeAction(msg.Item)
+		case "drain":
+			if m.drain != nil {
+				m.statusMsg = "Already draining " + m.drain.node
+				return m, clearStatusAfter(3 * time.Second)
+			}
+			return m, m.requestNodeAction(msg.Item)
+		case "copy":
+			copied, err := component.CopyToClipboard(msg.Item.Command)
+			if err == nil {
+				m.statusMsg = "Copied: " + msg.Item.Label + copied.Via()
+			} else {
+				m.statusMsg = "Copy failed: " + err.Error()
+			}
+		}
+		return m, nil
+
+	case deploymentHistoryMsg:
+		m.loading = false
+		if msg.err != nil {
+			return m, m.notifyError("get history of "+msg.workload.Name, msg.workload.Namespace, msg.err)
+		}
+		if len(msg.history) == 0 {
+			m.statusMsg = "No revisions found for " + msg.workload.Name
+			return m, clearStatusAfter(3 * time.Second)
+		}
+		m.showDeploymentHistory(msg.workload, msg.history)
+		return m, nil
+
+	case statefulSetDetailsMsg:
+		m.loading = false
+		if msg.err != nil {
+			return m, m.notifyError("get StatefulSet "+msg.workload.Name, msg.workload.Namespace, msg.err)
+		}
+		if msg.partitions {
+			m.showPartitions(msg.workload, msg.details)
+			return m, nil
+		}
+		m.telemetry.View("statefulset")
+		m.resultViewer.Show("StatefulSet: "+msg.workload.Name, component.RenderStatefulSetDetails(msg.details), m.width-4, m.height-4)
+		return m, nil
+
+	case workloadImagesMsg:
+		m.loading = false
+		if msg.err != nil {
+			return m, m.notifyError("get images of "+msg.workload.Name, msg.workload.Namespace, msg.err)
+		}
+		m.showImages(msg.workload, msg.images)
+		return m, nil
+
+	case imageSetMsg:
+		m.loading = false
+		w := msg.change.workload
+		if msg.err != nil {
+			return m, m.notifyError("set image of "+w.Name, w.Namespace, msg.err)
+		}
+		m.telemetry.Action("set-image")
+		change := msg.change
+		m.imageRollout = &change
+		m.statusMsg = fmt.Sprintf("Set %s of %s to %s, rolling out...", change.container, w.Name, change.image)
+		return m, tea.Batch(m.refreshWorkload(workloadHint(w)), m.followImageRollout(change, 0))
+
+	case imageRolloutMsg:
+		return m, m.handleImageRollout(msg)
+
+	case workloadResourcesMsg:
+		m.loading = false
+		if msg.err != nil {
+			return m, m.notifyError("get resources of "+msg.workload.Name, msg.workload.Namespace, msg.err)
+		}
+		m.showResources(msg.workload, msg.containers)
+		return m, nil
+
+	case helmReleaseMsg:
+		m.loading = false
+		if msg.err != nil {
+			return m, m.notifyError("get Helm release "+msg.workload.HelmRelease, msg.workload.Namespace, msg.err)
+		}
+		m.showHelmRelease(msg.release)
+		return m, nil
+
+	case resourcesSetMsg:
+		m.loading = false
+		w := msg.change.workload
+		if msg.err != nil {
+			return m, m.notifyError("set resources of "+w.Name, w.Namespace, msg.err)
+		}
+		m.telemetry.Action("set-resources")
+		m.statusMsg = fmt.Sprintf("Set resources of %s of %s, rolling out...", msg.change.current.Container, w.Name)
+		return m, tea.Batch(m.refreshWorkload(workloadHint(w)), clearStatusAfter(5*time.Second))
+
+	case cronJobTriggeredMsg:
+		if msg.err != nil {
+			return m, m.notifyError("trigger CronJob "+msg.cronJob, msg.namespace, msg.err)
+		}
+		m.statusMsg = fmt.Sprintf("Created job %s, waiting for its pod...", msg.job)
+		m.triggeredJob = msg.job
+		return m, m.waitForJobPod(msg.namespace, msg.job, 0)
+
+	case worstPodMsg:
+		m.loading = false
+		switch {
+		case msg.err != nil:
+			return m, m.notifyError("list pods of "+msg.workload.Name, msg.workload.Namespace, msg.err)
+		case msg.pod == nil:
+			m.statusMsg = fmt.Sprintf("%s has no pods", msg.workload.Name)
+			return m, clearStatusAfter(3 * time.Second)
+		case m.view != ViewNavigator:
+			return m, nil
+		}
+		// Load the workload's pods too, where Esc leads from the dashboard
+		m.workload = msg.workload
+		return m, tea.Batch(m.loadPods(msg.workload), m.openPodDashboard(msg.pod))
+
+	case cronJobRunsMsg:
+		m.loading = false
+		if msg.err != nil {
+			return m, m.notifyError("list runs of "+msg.cronJob, msg.namespace, msg.err)
+		}
+		m.cronJobRunsViewer.SetSize(m.width, m.height)
+		m.cronJobRunsViewer.Show(msg.namespace, msg.cronJob, msg.schedule, msg.runs)
+		return m, nil
+
+	case component.OpenJobRunRequest:
+		m.loading = true
+		return m, m.loadJobRunPod(msg)
+
+	case jobRunPodMsg:
+		m.loading = false
+		switch {
+		case msg.err != nil:
+			return m, m.notifyError("list pods of Job "+msg.job, m.k8sClient.Namespace(), msg.err)
+		case msg.pod == nil:
+			// Deleted since the runs were listed
+			m.cronJobRunsViewer.PodDeleted(msg.job)
+			return m, nil
+		}
+		m.cronJobRunsViewer.Hide()
+		m.workload = jobWorkload(msg.pod.Namespace, msg.job)
+		cmd = m.openPodDashboard(msg.pod)
+		m.dashboard.FocusView(deeplink.ViewLogs)
+		return m, cmd
+
+	case component.CronJobRunsViewerClosed:
 		return m, nil
 
-	case logsUpdatedMsg:
-		m.dashboard.SetLogs(msg.logs)
+	case daemonSetNodesMsg:
+		m.loading = false
+		if msg.err != nil {
+			return m, m.notifyError("get DaemonSet "+msg.workload.Name, msg.workload.Namespace, msg.err)
+		}
+		m.telemetry.View("daemonset-nodes")
+		m.daemonSetNodesViewer.SetSize(m.width, m.height)
+		m.daemonSetNodesViewer.Show(msg.workload, msg.rollout)
 		return m, nil
 
-	case view.DeletePodRequest:
-		return m, m.deletePod(msg.Namespace, msg.PodName)
+	case component.OpenDaemonSetPodRequest:
+		m.loading = true
+		return m, m.loadDaemonSetPod(msg)
 
-	case podDeletedMsg:
+	case daemonSetPodMsg:
+		m.loading = false
 		if msg.err != nil {
-			m.err = msg.err
-		} else {
-			// Go back to pods list after deletion
-			m.view = ViewNavigator
-			m.pod = nil
-			m.navigator.SetMode(component.ModeResources)
-			return m, m.loadAllResources()
+			return m, m.notifyError("get pod of "+msg.workload.Name, msg.workload.Namespace, msg.err)
 		}
+		m.daemonSetNodesViewer.Hide()
+		return m, m.openTarget(&repository.Target{Workload: msg.workload, Pod: msg.pod})
+
+	case component.DaemonSetNodesViewerClosed:
 		return m, nil
 
-	case namespaceDeletedMsg:
+	case jobPodMsg:
+		if msg.job != m.triggeredJob {
+			return m, nil
+		}
+		if msg.pod == nil && msg.err == nil && msg.attempt < jobPodPollAttempts {
+			return m, m.waitForJobPod(msg.namespace, msg.job, msg.attempt)
+		}
+		m.triggeredJob = ""
+		switch {
+		case msg.err != nil:
+			return m, m.notifyError("list pods of Job "+msg.job, msg.namespace, msg.err)
+		case msg.pod == nil:
+			m.statusMsg = fmt.Sprintf("Job %s has no pod yet", msg.job)
+			return m, clearStatusAfter(5 * time.Second)
+		case m.view != ViewNavigator:
+			// Don't pull the user away from another pod's dashboard
+			m.statusMsg = fmt.Sprintf("Job %s started pod %s", msg.job, msg.pod.Name)
+			return m, clearStatusAfter(5 * time.Second)
+		}
+		m.statusMsg = ""
+		m.workload = jobWorkload(msg.namespace, msg.job)
+		return m, m.openPodDashboard(msg.pod)
+
+	case view.RolloutActionsRequest:
+		m.showWorkloadActions(&repository.WorkloadInfo{
+			Name:      msg.Name,
+			Namespace: msg.Namespace,
+			Type:      repository.ResourceRollouts,
+			Replicas:  msg.Replicas,
+		})
+		return m, nil
+
+	case view.NodeActionsRequest:
+		m.nodeActionMenu.Show("Node: "+msg.Node, component.NodeActions(msg.Node, msg.Cordoned))
+		return m, nil
+
+	case nodeCordonedMsg:
 		if msg.err != nil {
-			m.statusMsg = "Failed to delete namespace: " + msg.err.Error()
+			op := "cordon node "
+			if !msg.cordon {
+				op = "uncordon node "
+			}
+			return m, m.notifyError(op+msg.node, "", msg.err)
+		}
+		if msg.cordon {
+			m.statusMsg = "Cordoned " + msg.node
 		} else {
-			m.statusMsg = fmt.Sprintf("Namespace %s deleted", msg.namespace)
-			// Refresh namespace list
-			return m, tea.Batch(m.loadInitialData(), clearStatusAfter(3*time.Second))
+			m.statusMsg = "Uncordoned " + msg.node
 		}
-		return m, clearStatusAfter(5 * time.Second)
+		return m, tea.Batch(m.refreshNodes(), clearStatusAfter(3*time.Second))
 
-	case component.WorkloadActionMenuResult:
-		workload := m.navigator.SelectedWorkload()
-		if workload == nil {
+	case nodesLoadedMsg:
+		if msg.err == nil {
+			m.nodes = msg.nodes
+		}
+		return m, nil
+
+	case drainProgressMsg:
+		if m.drain == nil || m.drain.node != msg.node {
 			return m, nil
 		}
-		switch msg.Item.Action {
-		case "scale":
-			m.loading = true
-			return m, m.scaleWorkload(workload, msg.Item.Replicas)
-		case "copy":
-			err := component.CopyToClipboard(msg.Item.Command)
-			if err == nil {
-				m.statusMsg = "Copied: " + msg.Item.Label
-			} else {
-				m.statusMsg = "Copy failed: " + err.Error()
+		m.drain.update(msg.pod)
+		m.showDrain(nil)
+		return m, waitForDrain(m.drain.updates)
+
+	case drainFinishedMsg:
+		if m.drain == nil || m.drain.node != msg.node {
+			return m, nil
+		}
+		if msg.err != nil {
+			if m.resultViewer.Title() == m.drain.title() {
+				m.resultViewer.Hide()
+			}
+			m.drain = nil
+			return m, m.notifyError("drain node "+msg.node, "", msg.err)
+		}
+		m.showDrain(msg.result)
+		m.drain = nil
+		m.statusMsg = fmt.Sprintf("Drained %s: %d evicted, %d not evicted", msg.node, len(msg.result.Evicted), len(msg.result.Failed))
+		return m, tea.Batch(m.refreshNodes(), clearStatusAfter(5*time.Second))
+
+	case bulkDeleteProgressMsg:
+		if m.bulkDelete == nil {
+			return m, nil
+		}
+		m.mutations.Settle(podHint(msg.result.Namespace, msg.result.Name), msg.result.Err)
+		m.bulkDelete.done = append(m.bulkDelete.done, msg.result)
+		m.showBulkDelete(false)
+		return m, waitForBulkDelete(m.bulkDelete.updates)
+
+	case bulkDeleteFinishedMsg:
+		if m.bulkDelete == nil {
+			return m, nil
+		}
+		if msg.results == nil && msg.err != nil {
+			// Refused before any pod was attempted, e.g. in read-only mode
+			namespace := m.bulkDelete.namespace
+			for _, name := range m.bulkDelete.names {
+				m.mutations.Settle(podHint(namespace, name), msg.err)
+			}
+			if m.resultViewer.Title() == m.bulkDelete.title() {
+				m.resultViewer.Hide()
 			}
+			m.bulkDelete = nil
+			return m, m.notifyError("delete pods", namespace, msg.err)
 		}
+		m.showBulkDelete(true)
+		failed := 0
+		for _, r := range msg.results {
+			if r.Err != nil {
+				failed++
+			}
+		}
+		m.statusMsg = fmt.Sprintf("Deleted %d of %d pods in %s", len(msg.results)-failed, len(msg.results), m.bulkDelete.namespace)
+		if failed > 0 {
+			m.statusMsg += fmt.Sprintf(", %d failed", failed)
+		}
+		m.bulkDelete = nil
+		return m, tea.Batch(m.refreshPods(), clearStatusAfter(5*time.Second))
+
+	case drainSimulationMsg:
+		m.loading = false
+		if msg.err != nil {
+			return m, m.notifyError("simulate drain", "", msg.err)
+		}
+		m.statusMsg = ""
+		m.resultViewer.Show("Drain Simulation: "+msg.sim.Node, component.RenderDrainSimulation(msg.sim), m.width-4, m.height-4)
 		return m, nil
 
 	case component.ConfirmResult:
+		if msg.Confirmed {
+			m.telemetry.Action(msg.Action)
+		}
+		// Handle workload scale at app level (no dialog unless configured)
+		if msg.Confirmed && msg.Action == "scale" {
+			if req, ok := msg.Data.(workloadScale); ok {
+				m.statusMsg = fmt.Sprintf("Scaling %s to %d...", req.workload.Name, req.replicas)
+				m.mutations.Begin(component.PendingMutation{
+					Hint:     workloadHint(req.workload),
+					Action:   component.MutationScale,
+					Replicas: req.replicas,
+				})
+				return m, m.scaleWorkload(req.workload, req.replicas, true)
+			}
+		}
+		if msg.Confirmed && msg.Action == "overwrite_panel" {
+			if target, ok := msg.Data.(panelSaveTarget); ok {
+				return m, savePanel(target, target.path, true)
+			}
+		}
+		if msg.Confirmed && msg.Action == "bulk_delete_pods" {
+			if req, ok := msg.Data.(bulkDeleteRequest); ok {
+				return m, m.startBulkDelete(req)
+			}
+		}
 		// Handle workload restart at app level
 		if msg.Confirmed && msg.Action == "restart" {
 			if workload, ok := msg.Data.(*repository.WorkloadInfo); ok {
@@ -468,14 +2104,105 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.restartWorkload(workload)
 			}
 		}
+		// Handle Argo Rollout promote, abort and retry
+		if msg.Confirmed && (msg.Action == "promote_rollout" || msg.Action == "abort_rollout" || msg.Action == "retry_rollout") {
+			if workload, ok := msg.Data.(*repository.WorkloadInfo); ok {
+				m.loading = true
+				action := strings.TrimSuffix(msg.Action, "_rollout")
+				return m, m.rolloutAction(workload, action)
+			}
+		}
+		// Handle Deployment rollback
+		if msg.Confirmed && msg.Action == "rollback_deployment" {
+			if rollback, ok := msg.Data.(deploymentRollback); ok {
+				m.loading = true
+				m.statusMsg = fmt.Sprintf("Rolling back %s...", rollback.workload.Name)
+				return m, m.rollbackDeployment(rollback.workload, rollback.revision)
+			}
+		}
+		// Handle StatefulSet partition change
+		if msg.Confirmed && msg.Action == "set_partition" {
+			if p, ok := msg.Data.(statefulSetPartition); ok {
+				m.loading = true
+				m.statusMsg = fmt.Sprintf("Setting partition of %s to %d...", p.workload.Name, p.partition)
+				return m, m.setPartition(p.workload, p.partition)
+			}
+		}
+		// Handle a container image change
+		if msg.Confirmed && msg.Action == "set_image" {
+			if change, ok := msg.Data.(imageChange); ok {
+				m.loading = true
+				m.statusMsg = fmt.Sprintf("Setting image of %s...", change.workload.Name)
+				return m, m.setImage(change)
+			}
+		}
+		// Handle a change of a container's requests and limits
+		if msg.Confirmed && msg.Action == "set_resources" {
+			if change, ok := msg.Data.(resourcesChange); ok {
+				m.loading = true
+				m.statusMsg = fmt.Sprintf("Setting resources of %s...", change.workload.Name)
+				return m, m.setResources(change)
+			}
+		}
+		// Handle manual CronJob run
+		if msg.Confirmed && msg.Action == "trigger_cronjob" {
+			if workload, ok := msg.Data.(*repository.WorkloadInfo); ok {
+				m.statusMsg = fmt.Sprintf("Triggering %s...", workload.Name)
+				return m, m.triggerCronJob(workload)
+			}
+		}
+		// Handle a port-forward retried from the suggested local port
+		if msg.Confirmed && msg.Action == "port_forward_local_port" {
+			if req, ok := msg.Data.(view.PortForwardRequest); ok {
+				return m, m.startPortForward(req)
+			}
+		}
+		// Handle the force delete offered for a pod still terminating
+		if msg.Confirmed && msg.Action == "force_delete_pod" {
+			if req, ok := msg.Data.(view.ForceDeletePodRequest); ok {
+				m.statusMsg = "Force deleting pod " + req.PodName + "..."
+				return m, func() tea.Msg { return req }
+			}
+		}
 		// Handle namespace force delete
 		if msg.Confirmed && msg.Action == "delete_namespace" {
 			if nsInfo, ok := msg.Data.(*repository.NamespaceInfo); ok {
 				m.statusMsg = fmt.Sprintf("Deleting namespace %s...", nsInfo.Name)
+				return m, m.deleteNamespace(nsInfo.Name)
+			}
+		}
+		if msg.Confirmed && msg.Action == "force_delete_namespace" {
+			if nsInfo, ok := msg.Data.(*repository.NamespaceInfo); ok {
+				m.statusMsg = fmt.Sprintf("Force deleting namespace %s...", nsInfo.Name)
 				return m, m.forceDeleteNamespace(nsInfo.Name)
 			}
 		}
-		// Forward other confirm results (exec, port-forward, delete) to dashboard
+		// Handle node cordon, uncordon and drain
+		if msg.Confirmed {
+			if nodeName, ok := msg.Data.(string); ok {
+				switch msg.Action {
+				case "cordon_node", "uncordon_node":
+					return m, m.cordonNode(nodeName, msg.Action == "cordon_node")
+				case "drain_node":
+					m.statusMsg = "Draining " + nodeName + "..."
+					return m, m.startDrain(nodeName)
+				}
+			}
+		}
+		// Handle ConfigMap and Secret edits and deletes
+		if msg.Confirmed {
+			if edit, ok := msg.Data.(configEdit); ok {
+				switch msg.Action {
+				case "save_config_value":
+					m.setConfigStatus(edit.secret, "Saving "+edit.key+"...")
+					return m, m.saveConfigValue(edit)
+				case "delete_configmap", "delete_secret":
+					m.statusMsg = fmt.Sprintf("Deleting %s...", edit.name)
+					return m, m.deleteConfig(edit.secret, edit.namespace, edit.name)
+				}
+			}
+		}
+		// Forward other confirm results (exec, delete) to dashboard
 		if m.view == ViewDashboard {
 			var cmd tea.Cmd
 			m.dashboard, cmd = m.dashboard.Update(msg)
@@ -493,6 +2220,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case view.DescribeOutputMsg:
+		m.telemetry.Action("describe")
 		// Forward describe output to dashboard
 		if m.view == ViewDashboard {
 			var cmd tea.Cmd
@@ -503,23 +2231,89 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case workloadActionMsg:
 		m.loading = false
+		m.mutations.Settle(msg.hint, msg.err)
 		if msg.err != nil {
-			m.statusMsg = "Error: " + msg.err.Error()
-			return m, clearStatusAfter(5 * time.Second)
+			return m, m.notifyError(msg.action+" "+msg.workloadName, msg.namespace, msg.err)
 		}
+		clearStatus := clearStatusAfter(3 * time.Second)
 		switch msg.action {
 		case "scale":
 			m.statusMsg = fmt.Sprintf("Scaled %s to %d replicas", msg.workloadName, msg.replicas)
+			if msg.undoable && msg.previous != msg.replicas {
+				u := component.ScaleUndo{Hint: msg.hint, From: msg.previous, To: msg.replicas, At: time.Now()}
+				m.undo.Push(u)
+				m.statusMsg = m.undo.Message(u, u.At)
+				m.undoStatus = m.statusMsg
+				clearStatus = undoTick()
+			}
 		case "restart":
 			m.statusMsg = fmt.Sprintf("Restart initiated for %s", msg.workloadName)
+		case "promote":
+			m.statusMsg = fmt.Sprintf("Promoted %s", msg.workloadName)
+		case "abort":
+			m.statusMsg = fmt.Sprintf("Aborted update of %s", msg.workloadName)
+		case "retry":
+			m.statusMsg = fmt.Sprintf("Retrying update of %s", msg.workloadName)
+		case "rollback":
+			m.statusMsg = fmt.Sprintf("Rolled back %s to revision %d", msg.workloadName, msg.revision)
+		case "partition":
+			m.statusMsg = fmt.Sprintf("Set partition of %s to %d", msg.workloadName, msg.partition)
 		}
-		// Refresh based on current view
-		if m.view == ViewNavigator && m.navigator.Mode() == component.ModeResources {
-			// Stay on resources view and reload
-			return m, tea.Batch(m.loadAllResources(), clearStatusAfter(3*time.Second))
+		// Re-fetch only what the action affected: the workload row, plus
+		// its pods or the dashboard (events) depending on the current view
+		cmds := []tea.Cmd{m.refreshWorkload(msg.hint), clearStatus}
+		if m.view == ViewDashboard && m.pod != nil {
+			cmds = append(cmds, m.loadDashboardData(m.pod))
+		} else if m.view == ViewNavigator && m.navigator.Mode() == component.ModeResources {
+			cmds = append(cmds, m.refreshPods())
+		}
+		return m, tea.Batch(cmds...)
+
+	case workloadRefreshedMsg:
+		if msg.workload != nil {
+			m.navigator.UpdateWorkload(*msg.workload)
+		}
+		if msg.err != nil {
+			// Can't tell whether a pending scale took; drop the optimistic state
+			m.mutations.Forget(msg.hint)
+			return m, nil
 		}
-		// Refresh workloads list for other views
-		return m, tea.Batch(m.loadWorkloads(), clearStatusAfter(3*time.Second))
+		if outcome, ok := m.mutations.ReconcileReplicas(msg.hint, msg.desired); ok && !outcome.Applied {
+			m.statusMsg = outcome.Message()
+			return m, clearStatusAfter(5 * time.Second)
+		}
+		m.observeReplicas(msg.hint, msg.desired)
+		return m, nil
+
+	case restartTickMsg:
+		if m.view != ViewDashboard || !m.dashboard.BackingOff() {
+			m.restartTicking = false
+			return m, nil
+		}
+		m.dashboard.RefreshRestartCountdown()
+		return m, restartTick()
+
+	case undoTickMsg:
+		// Count down until the scale can no longer be undone, unless
+		// something else took over the status bar
+		if m.undoStatus == "" || m.statusMsg != m.undoStatus {
+			m.undoStatus = ""
+			return m, nil
+		}
+		now := time.Now()
+		u, ok := m.undo.Last(now)
+		if !ok {
+			m.statusMsg, m.undoStatus = "", ""
+			return m, nil
+		}
+		m.statusMsg = m.undo.Message(u, now)
+		m.undoStatus = m.statusMsg
+		return m, undoTick()
+
+	case changesExpiredMsg:
+		// The navigator renders highlights on every frame; events are cached
+		m.dashboard.RefreshHighlights()
+		return m, m.expireChanges()
 
 	case clearStatusMsg:
 		m.statusMsg = ""
@@ -545,47 +2339,130 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			Name:      msg.WorkloadName,
 			Namespace: msg.Namespace,
 			Type:      resourceType,
-			Replicas:  msg.NewReplicas,
+			Replicas:  msg.Replicas,
+		}
+		return m, m.requestScale(workload, msg.NewReplicas)
+
+	case component.RefreshTickMsg:
+		// Event ages count up even while paused or disconnected
+		m.dashboard.RefreshAges()
+		// Nothing to fetch from a cluster that can't be reached
+		if m.connLost != nil {
+			return m, m.refresher.Tick()
+		}
+		// Drop forwards that ended on their own, e.g. when the pod went away
+		if m.portForwardsViewer.IsVisible() {
+			m.portForwardsViewer.SetForwards(m.portForwarder.List())
+		}
+		// Every fetch goes through the ticker, which drops them while paused
+		// The warnings badge and the session recording follow the namespace
+		// in every view of it; watches are checked in every view
+		namespaceWide := tea.Batch(m.loadRecentWarnings(), m.recordSession(), m.checkWatches())
+		// The pod metrics table covers the navigator; refresh only the table
+		if m.podTopViewer.IsVisible() {
+			return m, m.refresher.Tick(m.loadPodUsage(m.podTopViewer.Namespace()), namespaceWide)
+		}
+		if m.nodeCapacityViewer.IsVisible() {
+			return m, m.refresher.Tick(m.loadNodeCapacity(m.nodeCapacityViewer.Pod()), namespaceWide)
 		}
-		m.statusMsg = fmt.Sprintf("Scaling %s to %d...", msg.WorkloadName, msg.NewReplicas)
-		return m, m.scaleWorkload(workload, msg.NewReplicas)
-
-	case tickMsg:
 		if m.view == ViewDashboard && m.pod != nil {
-			return m, tea.Batch(
-				m.loadDashboardData(m.pod),
-				m.tickCmd(),
-			)
+			cmds := []tea.Cmd{m.loadDashboardData(m.pod), namespaceWide}
+			// Keep open PVC details live while the claim is being provisioned
+			if claim := m.dashboard.WatchedPVC(); claim != "" {
+				cmds = append(cmds, m.loadPVCDetails(m.pod.Namespace, claim))
+			}
+			return m, m.refresher.Tick(cmds...)
 		}
 		// Refresh resources list in real-time when viewing resources
 		if m.view == ViewNavigator && m.navigator.Mode() == component.ModeResources {
 			// If viewing pods by node, refresh with node filter
 			if m.selectedNode != "" {
-				return m, tea.Batch(
-					m.loadPodsByNode(m.selectedNode),
-					m.tickCmd(),
-				)
-			}
-			return m, tea.Batch(
-				m.loadAllResources(),
-				m.tickCmd(),
-			)
+				return m, m.refresher.Tick(m.loadPodsByNode(m.selectedNode), namespaceWide)
+			}
+			// Pages still loading would be fetched twice
+			if m.podsContinue != "" {
+				return m, m.refresher.Tick(namespaceWide)
+			}
+			return m, m.refresher.Tick(m.loadAllResources(), namespaceWide)
+		}
+		if m.warningsViewer.IsVisible() {
+			return m, m.refresher.Tick(namespaceWide)
 		}
-		return m, m.tickCmd()
+		return m, m.refresher.Tick(m.checkWatches())
 
 	case tea.KeyMsg:
+		// The authentication error screen only retries or quits
+		if m.k8sClient.AuthFailure() != nil {
+			switch {
+			case msg.String() == "r":
+				m.k8sClient.RetryAuth()
+				return m, m.refresh()
+			case key.Matches(msg, m.keys.Quit):
+				m.saveConfig()
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
+		// The cluster switcher also opens from the diagnostics screen
+		if m.clusterSwitcher.IsVisible() {
+			var cmd tea.Cmd
+			m.clusterSwitcher, cmd = m.clusterSwitcher.Update(msg)
+			return m, cmd
+		}
+
+		// The diagnostics screen retries, switches context or quits
+		if m.connLost != nil {
+			switch {
+			case msg.String() == "r" && !m.connChecking:
+				m.connChecking = true
+				return m, m.checkConnection()
+			case key.Matches(msg, m.keys.Context):
+				// Another cluster may answer
+				return m, m.openClusterSwitcher()
+			case key.Matches(msg, m.keys.Quit):
+				m.saveConfig()
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
 		// Confirm dialog takes highest priority
 		if m.confirmDialog.IsVisible() {
 			m.confirmDialog, cmd = m.confirmDialog.Update(msg)
 			return m, cmd
 		}
 
+		// Input dialog takes priority, it captures all typing
+		if m.inputDialog.IsVisible() {
+			m.inputDialog, cmd = m.inputDialog.Update(msg)
+			return m, cmd
+		}
+
+		// Command palette takes priority, it captures all typing
+		if m.palette.IsVisible() {
+			m.palette, cmd = m.palette.Update(msg)
+			return m, cmd
+		}
+
 		// Workload action menu takes priority
 		if m.workloadActionMenu.IsVisible() {
 			m.workloadActionMenu, cmd = m.workloadActionMenu.Update(msg)
 			return m, cmd
 		}
 
+		// Node action menu takes priority
+		if m.nodeActionMenu.IsVisible() {
+			m.nodeActionMenu, cmd = m.nodeActionMenu.Update(msg)
+			return m, cmd
+		}
+
+		// Result viewer takes priority
+		if m.resultViewer.IsVisible() {
+			m.resultViewer, cmd = m.resultViewer.Update(msg)
+			return m, cmd
+		}
+
 		// Help overlay takes priority
 		if m.help.IsVisible() {
 			if msg.String() == "?" || msg.String() == "esc" {
@@ -600,6 +2477,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.configMapViewer, cmd = m.configMapViewer.Update(msg)
 			// Check for pending copy request
 			if req := m.configMapViewer.GetPendingRequest(); req != nil {
+				if m.refuseReadOnly("Copy ConfigMap") {
+					m.configMapViewer.SetStatusMsg(m.statusMsg)
+					return m, clearStatusAfter(3 * time.Second)
+				}
 				// Handle the copy request directly
 				if req.AllNamespaces {
 					var namespaces []string
@@ -629,17 +2510,87 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// Port-forwards list takes priority
+		if m.portForwardsViewer.IsVisible() {
+			m.portForwardsViewer, cmd = m.portForwardsViewer.Update(msg)
+			return m, cmd
+		}
+
 		// HPA viewer takes priority
 		if m.hpaViewer.IsVisible() {
 			m.hpaViewer, cmd = m.hpaViewer.Update(msg)
 			return m, cmd
 		}
 
+		// Pod metrics table takes priority
+		if m.podTopViewer.IsVisible() {
+			m.podTopViewer, cmd = m.podTopViewer.Update(msg)
+			return m, cmd
+		}
+
+		// Node capacity table takes priority
+		if m.nodeCapacityViewer.IsVisible() {
+			m.nodeCapacityViewer, cmd = m.nodeCapacityViewer.Update(msg)
+			return m, cmd
+		}
+
+		// Watches list takes priority
+		if m.watchesViewer.IsVisible() {
+			m.watchesViewer, cmd = m.watchesViewer.Update(msg)
+			return m, cmd
+		}
+
+		// Namespace warnings take priority
+		if m.warningsViewer.IsVisible() {
+			m.warningsViewer, cmd = m.warningsViewer.Update(msg)
+			return m, cmd
+		}
+
+		// CronJob run history takes priority
+		if m.cronJobRunsViewer.IsVisible() {
+			m.cronJobRunsViewer, cmd = m.cronJobRunsViewer.Update(msg)
+			return m, cmd
+		}
+
+		// DaemonSet nodes viewer takes priority
+		if m.daemonSetNodesViewer.IsVisible() {
+			m.daemonSetNodesViewer, cmd = m.daemonSetNodesViewer.Update(msg)
+			return m, cmd
+		}
+
+		// Columns picker takes priority
+		if m.columnPicker.IsVisible() {
+			m.columnPicker, cmd = m.columnPicker.Update(msg)
+			return m, cmd
+		}
+
+		// Error log takes priority
+		if m.errorLogViewer.IsVisible() {
+			m.errorLogViewer, cmd = m.errorLogViewer.Update(msg)
+			return m, cmd
+		}
+
+		// Namespace comparison takes priority
+		if m.namespaceCompare.IsVisible() {
+			m.namespaceCompare, cmd = m.namespaceCompare.Update(msg)
+			return m, cmd
+		}
+
+		// File browser takes priority
+		if m.fileBrowser.IsVisible() {
+			m.fileBrowser, cmd = m.fileBrowser.Update(msg)
+			return m, cmd
+		}
+
 		// Docker Registry viewer takes priority
 		if m.dockerRegistryViewer.IsVisible() {
 			m.dockerRegistryViewer, cmd = m.dockerRegistryViewer.Update(msg)
 			// Check for pending copy request
 			if req := m.dockerRegistryViewer.GetPendingRequest(); req != nil {
+				if m.refuseReadOnly("Copy Secret") {
+					m.dockerRegistryViewer.SetStatusMsg(m.statusMsg)
+					return m, clearStatusAfter(3 * time.Second)
+				}
 				// Handle the copy request directly
 				if req.AllNamespaces {
 					var namespaces []string
@@ -674,6 +2625,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.secretViewer, cmd = m.secretViewer.Update(msg)
 			// Check for pending copy request
 			if req := m.secretViewer.GetPendingRequest(); req != nil {
+				if m.refuseReadOnly("Copy Secret") {
+					m.secretViewer.SetStatusMsg(m.statusMsg)
+					return m, clearStatusAfter(3 * time.Second)
+				}
 				// Handle the copy request directly
 				if req.AllNamespaces {
 					var namespaces []string
@@ -719,6 +2674,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// While the logs panel takes text (its search or time range), every
+		// key goes to it, then on to the log refresh below
+		if m.view == ViewDashboard && m.dashboard.IsLogsSearching() {
+			break
+		}
+
 		// When dashboard is in fullscreen logs/events mode, pass search-related keys directly
 		if m.view == ViewDashboard && (m.dashboard.IsFullscreenLogs() || m.dashboard.IsFullscreenEvents()) {
 			key := msg.String()
@@ -774,6 +2735,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.nodeCursor = 0
 				return m, nil
 			}
+			// Requests vs allocatable capacity of every node
+			if key.Matches(msg, m.keys.Top) {
+				m.telemetry.View("node-capacity")
+				m.nodeCapacityViewer.SetSize(m.width, m.height)
+				m.nodeCapacityViewer.Show(nil)
+				return m, m.loadNodeCapacity(nil)
+			}
+			// Node actions (drain simulation, cordon, drain)
+			if key.Matches(msg, m.keys.PodActions) {
+				filteredNodes := m.filteredNodes()
+				if m.nodeCursor < len(filteredNodes) {
+					node := filteredNodes[m.nodeCursor]
+					m.nodeActionMenu.Show("Node: "+node.Name, component.NodeActions(node.Name, node.Unschedulable))
+				}
+				return m, nil
+			}
 		}
 
 		// Normal key handling when not searching
@@ -786,9 +2763,66 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.help.Toggle()
 			return m, nil
 
+		case key.Matches(msg, m.keys.PauseRefresh):
+			if m.refresher.TogglePause() {
+				m.statusMsg = "Refresh paused"
+			} else {
+				m.statusMsg = "Refresh resumed"
+			}
+			return m, clearStatusAfter(3 * time.Second)
+
+		case key.Matches(msg, m.keys.PortForwards):
+			m.portForwardsViewer.SetSize(m.width, m.height)
+			m.portForwardsViewer.Show(m.portForwarder.List())
+			return m, nil
+
+		case key.Matches(msg, m.keys.Palette):
+			return m, m.openPalette()
+
+		case key.Matches(msg, m.keys.Watches):
+			// The toast of a fired watch has been seen
+			if toast := m.notifications.Toast(); toast != nil && toast.Sticky {
+				m.notifications.Dismiss()
+			}
+			m.watchesViewer.SetSize(m.width, m.height)
+			m.watchesViewer.Show(m.watches)
+			return m, nil
+
+		case key.Matches(msg, m.keys.Warnings):
+			m.warningsViewer.SetSize(m.width, m.height)
+			m.warningsViewer.Show(m.k8sClient.Namespace(), m.recentWarnings)
+			return m, m.loadRecentWarnings()
+
+		case key.Matches(msg, m.keys.ErrorLog):
+			m.errorLogViewer.SetSize(m.width, m.height)
+			m.errorLogViewer.Show(m.notifications.Log())
+			return m, nil
+
+		case key.Matches(msg, m.keys.Columns) && m.view == ViewNavigator && m.navigator.Mode() == component.ModeWorkloads:
+			m.columnPicker.Show(m.navigator.Columns())
+			return m, nil
+
 		case key.Matches(msg, m.keys.Refresh):
 			return m, m.refresh()
 
+		case key.Matches(msg, m.keys.Theme):
+			theme := style.CycleTheme()
+			m.spinner.Style = style.SpinnerStyle
+			m.dashboard.Restyle()
+			m.statusMsg = "Theme: " + theme.Name
+			return m, clearStatusAfter(3 * time.Second)
+
+		case key.Matches(msg, m.keys.HighlightChanges):
+			m.highlightChanges = !m.highlightChanges
+			m.navigator.SetHighlightChanges(m.highlightChanges)
+			m.dashboard.SetHighlightChanges(m.highlightChanges)
+			if m.highlightChanges {
+				m.statusMsg = "Highlighting changes between refreshes"
+			} else {
+				m.statusMsg = "Change highlighting off"
+			}
+			return m, clearStatusAfter(3 * time.Second)
+
 		case key.Matches(msg, m.keys.Namespace):
 			if m.view == ViewNavigator {
 				m.navigator.SetMode(component.ModeNamespace)
@@ -796,6 +2830,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+		case key.Matches(msg, m.keys.Context):
+			if m.view == ViewNavigator {
+				return m, m.openClusterSwitcher()
+			}
+
 		case key.Matches(msg, m.keys.NextPanel):
 			// In namespace mode, switch between namespace and node panels
 			if m.view == ViewNavigator && m.navigator.Mode() == component.ModeNamespace {
@@ -824,22 +2863,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-		case msg.String() == "d":
-			// In namespace mode, delete Terminating namespaces
+		case msg.String() == "D":
+			// In namespace mode, compare the selected namespace with another one
 			if m.view == ViewNavigator && m.navigator.Mode() == component.ModeNamespace && !m.nodesPanelActive {
-				nsInfo := m.navigator.SelectedNamespaceInfo()
-				if nsInfo != nil && nsInfo.Status != "Active" {
-					// Show confirmation dialog for namespace deletion
-					m.confirmDialog.Show(
-						fmt.Sprintf("Force delete namespace '%s'?", nsInfo.Name),
-						"This will remove all resources and finalizers.",
-						"delete_namespace",
-						nsInfo,
-					)
+				if base := m.navigator.SelectedNamespace(); base != "" {
+					m.namespaceCompare.SetSize(m.width, m.height)
+					m.namespaceCompare.ShowPicker(base, m.navigator.GetActiveNamespaceNames())
+					m.telemetry.View("namespace-compare")
 					return m, nil
 				}
 			}
 
+		case msg.String() == "d":
+			// In namespace mode, delete namespaces, or force delete stuck ones
+			if m.view == ViewNavigator && m.navigator.Mode() == component.ModeNamespace && !m.nodesPanelActive {
+				if nsInfo := m.navigator.SelectedNamespaceInfo(); nsInfo != nil {
+					return m, m.requestDeleteNamespace(nsInfo)
+				}
+			}
+
+		case msg.String() == "N":
+			// In namespace mode, create a namespace
+			if m.view == ViewNavigator && m.navigator.Mode() == component.ModeNamespace && !m.nodesPanelActive {
+				m.showCreateNamespace()
+				return m, nil
+			}
+
 		case key.Matches(msg, m.keys.Up):
 			// Handle node panel navigation
 			if m.view == ViewNavigator && m.navigator.Mode() == component.ModeNamespace && m.nodesPanelActive {
@@ -864,6 +2913,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.view == ViewDashboard && (m.dashboard.IsLogsSearching() || m.dashboard.HasActiveOverlay()) {
 				break // Fall through to dashboard update
 			}
+			// Unselect the pods selected for a bulk action before going back
+			if m.view == ViewNavigator && len(m.navigator.MarkedPods()) > 0 && !m.navigator.IsSearching() {
+				m.navigator.ClearMarks()
+				return m, nil
+			}
 			// If dashboard is fullscreen, just close fullscreen instead of going back
 			if m.view == ViewDashboard && m.dashboard.IsFullscreen() {
 				m.dashboard.CloseFullscreen()
@@ -899,40 +2953,66 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if workload != nil {
 						rt := m.navigator.ResourceType()
 						if rt == repository.ResourceDeployments || rt == repository.ResourceStatefulSets {
-							items := component.ScaleActions(
-								m.k8sClient.Namespace(),
-								workload.Name,
-								string(rt),
-								workload.Replicas,
-							)
-							m.workloadActionMenu.Show("Scale "+workload.Name, items)
+							m.showWorkloadActions(workload)
 							return m, nil
 						}
 					}
 				}
+				// Workload actions (scale, CronJob trigger)
+				if key.Matches(msg, m.keys.PodActions) && m.navigator.Mode() == component.ModeWorkloads {
+					if workload := m.navigator.SelectedWorkload(); workload != nil && m.showWorkloadActions(workload) {
+						return m, nil
+					}
+				}
 				// Restart action
 				if key.Matches(msg, m.keys.Restart) && m.navigator.Mode() == component.ModeWorkloads {
 					workload := m.navigator.SelectedWorkload()
 					if workload != nil {
 						rt := m.navigator.ResourceType()
 						if rt == repository.ResourceDeployments || rt == repository.ResourceStatefulSets || rt == repository.ResourceDaemonSets {
-							m.confirmDialog.Show(
+							cmd := m.confirmDialog.Request(
+								m.confirmLevel(workload.Namespace, configs.ActionRestart),
 								"Restart "+string(rt),
 								"Are you sure you want to restart '"+workload.Name+"'?",
 								"restart",
+								workload.Name,
 								workload,
 							)
-							return m, nil
+							return m, cmd
 						}
 					}
 				}
+				// Bulk actions on the pods selected in the pod list
+				if key.Matches(msg, m.keys.BulkActions) && m.navigator.Mode() == component.ModeResources && m.showBulkActions() {
+					return m, nil
+				}
+				// Undo the last scale, while it can still be undone
+				if key.Matches(msg, m.keys.Undo) {
+					if cmd := m.undoScale(); cmd != nil {
+						return m, cmd
+					}
+				}
+				// Jump to the workload's failing or most restarted pod
+				if key.Matches(msg, m.keys.WorstPod) && m.navigator.Mode() == component.ModeWorkloads {
+					workload := m.navigator.SelectedWorkload()
+					if workload != nil && workload.Type != repository.ResourcePods {
+						m.loading = true
+						return m, m.loadWorstPod(workload)
+					}
+				}
+				// Top-like pod metrics table of the namespace
+				if key.Matches(msg, m.keys.Top) && m.navigator.Mode() == component.ModeResources {
+					ns := m.k8sClient.Namespace()
+					m.podTopViewer.SetSize(m.width, m.height)
+					m.podTopViewer.Show(ns)
+					return m, m.loadPodUsage(ns)
+				}
 				// Scale up ('s') in resources view when no pods but workload exists
 				if msg.String() == "s" && m.navigator.Mode() == component.ModeResources && m.navigator.HasWorkload() {
 					workload := m.navigator.GetScaleWorkload()
 					if workload != nil {
 						newReplicas := int32(1) // Scale to 1 when no pods
-						m.statusMsg = fmt.Sprintf("Scaling %s to %d...", workload.Name, newReplicas)
-						return m, m.scaleWorkload(workload, newReplicas)
+						return m, m.requestScale(workload, newReplicas)
 					}
 				}
 				// Scale down ('d') in resources view when no pods but workload exists
@@ -940,8 +3020,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					workload := m.navigator.GetScaleWorkload()
 					if workload != nil && workload.Replicas > 0 {
 						newReplicas := workload.Replicas - 1
-						m.statusMsg = fmt.Sprintf("Scaling %s to %d...", workload.Name, newReplicas)
-						return m, m.scaleWorkload(workload, newReplicas)
+						return m, m.requestScale(workload, newReplicas)
 					}
 				}
 			}
@@ -950,18 +3029,43 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 
 	case ViewDashboard:
+		// u groups events in the events panel; elsewhere it undoes the last scale
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, m.keys.Undo) &&
+			m.dashboard.Focus() != view.FocusEvents && !m.dashboard.HasActiveOverlay() && !m.dashboard.IsLogsSearching() {
+			if cmd := m.undoScale(); cmd != nil {
+				return m, cmd
+			}
+		}
 		m.dashboard, cmd = m.dashboard.Update(msg)
 		cmds = append(cmds, cmd)
 
 		// Check if log state changed and needs refresh
 		if m.pod != nil {
+			// Follow mode, previous logs and the container all decide the stream
+			cmds = append(cmds, m.syncLogStream())
+
 			currentShowPrevious := m.dashboard.LogsShowPrevious()
+			currentComparing := m.dashboard.LogsComparing()
 			currentContainer := m.dashboard.LogsSelectedContainer()
+			currentWorkload := m.dashboard.LogsWorkloadMode()
+			currentRange := m.dashboard.LogsTimeRange()
 
-			if currentShowPrevious != m.lastShowPrevious || currentContainer != m.lastLogContainer {
+			if currentShowPrevious != m.lastShowPrevious || currentComparing != m.lastComparing ||
+				currentContainer != m.lastLogContainer || currentWorkload != m.lastWorkloadLogs ||
+				currentRange != m.lastLogRange {
 				m.lastShowPrevious = currentShowPrevious
+				m.lastComparing = currentComparing
 				m.lastLogContainer = currentContainer
-				cmds = append(cmds, m.loadLogsForState(m.pod, currentContainer, currentShowPrevious))
+				m.lastWorkloadLogs = currentWorkload
+				m.lastLogRange = currentRange
+				if currentComparing {
+					cmds = append(cmds, m.loadComparedLogs(m.pod, currentContainer))
+				} else if workload := m.logsWorkload(); workload != nil && m.logStream == nil {
+					cmds = append(cmds, m.loadWorkloadLogs(*workload, currentContainer, currentRange))
+				} else if m.logStream == nil {
+					// A (re)started stream brings its own history
+					cmds = append(cmds, m.loadLogsForState(m.pod, currentContainer, currentShowPrevious, currentRange))
+				}
 			}
 		}
 	}
@@ -0,0 +1,83 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains attaching to the main process of a container.
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/view"
+	"k8s.io/client-go/rest"
+)
+
+// podAttach connects the terminal to the main process of a container, such
+// as a REPL or an interactive installer. Like podShell it implements
+// tea.ExecCommand, so the program releases the terminal while attached.
+type podAttach struct {
+	config    *rest.Config
+	namespace string
+	pod       string
+	container string
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (a *podAttach) SetStdin(r io.Reader)  { a.stdin = r }
+func (a *podAttach) SetStdout(w io.Writer) { a.stdout = w }
+func (a *podAttach) SetStderr(w io.Writer) { a.stderr = w }
+
+// Run attaches and blocks until the process exits or the detach keys are
+// typed. The terminal is set up as for a shell: raw mode, with size
+// changes forwarded to the container's TTY.
+func (a *podAttach) Run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The process already runs, so nothing may be printed until it does;
+	// tell how to get out before the screen goes quiet
+	fmt.Fprintf(a.stdout, "Attached to %s/%s; Ctrl+P Ctrl+Q detaches. If you don't see a prompt, press Enter.\r\n", a.pod, a.container)
+
+	opts := repository.ExecOptions{
+		Stdin:  a.stdin,
+		Stdout: a.stdout,
+		Stderr: a.stderr,
+	}
+	if f, ok := a.stdin.(term.File); ok && term.IsTerminal(f.Fd()) {
+		state, err := term.MakeRaw(f.Fd())
+		if err != nil {
+			return fmt.Errorf("failed to set terminal to raw mode: %w", err)
+		}
+		defer term.Restore(f.Fd(), state)
+
+		shell := podShell{stdout: a.stdout}
+		opts.TTY = true
+		opts.TerminalSizes = &terminalSizeQueue{ctx: ctx, fd: shell.sizeFd(f.Fd())}
+	}
+
+	return repository.AttachToContainer(ctx, a.config, a.namespace, a.pod, a.container, opts)
+}
+
+// attachContainer suspends the UI and attaches to a container of a pod.
+// The dashboard is shown again when the process exits or on detach, which
+// is not an error.
+func (m *Model) attachContainer(namespace, podName, container string) tea.Cmd {
+	attach := &podAttach{
+		config:    m.k8sClient.Config(),
+		namespace: namespace,
+		pod:       podName,
+		container: container,
+	}
+	return tea.Exec(attach, func(err error) tea.Msg {
+		if errors.Is(err, repository.ErrDetached) {
+			err = nil
+		}
+		return view.ExecFinishedMsg{Err: err}
+	})
+}
@@ -0,0 +1,146 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains the bulk actions on the pods selected in the pod list.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+)
+
+// bulkDelete is a bulk delete of pods in progress.
+type bulkDelete struct {
+	namespace string
+	names     []string                     // Pods being deleted, in list order
+	done      []repository.PodDeleteResult // Deletes finished so far
+	updates   <-chan tea.Msg               // bulkDeleteProgressMsg values, then a bulkDeleteFinishedMsg
+}
+
+// title is the result viewer title of the delete's progress.
+func (d *bulkDelete) title() string {
+	return fmt.Sprintf("Delete %d pods: %s", len(d.names), d.namespace)
+}
+
+// podNames returns the names of pods.
+func podNames(pods []repository.PodInfo) []string {
+	names := make([]string, len(pods))
+	for i, p := range pods {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// showBulkActions opens the menu of bulk actions on the pods selected in
+// the pod list, without bulk delete in a protected namespace. Returns
+// false when none is selected.
+func (m *Model) showBulkActions() bool {
+	pods := m.navigator.MarkedPods()
+	if len(pods) == 0 {
+		return false
+	}
+	m.bulkPods = pods
+	m.workloadActionMenu.Show(fmt.Sprintf("%d selected pods", len(pods)), component.BulkPodActions(len(pods), m.isProtected(pods[0].Namespace)))
+	return true
+}
+
+// requestBulkDelete asks for confirmation before deleting the selected
+// pods. Pods in a protected namespace are deleted one at a time instead.
+func (m *Model) requestBulkDelete(pods []repository.PodInfo) tea.Cmd {
+	if m.bulkDelete != nil {
+		m.statusMsg = "Already deleting pods in " + m.bulkDelete.namespace
+		return clearStatusAfter(3 * time.Second)
+	}
+	namespace := pods[0].Namespace
+	if m.isProtected(namespace) {
+		m.statusMsg = fmt.Sprintf("Bulk delete is disabled in protected namespace %s", namespace)
+		return clearStatusAfter(3 * time.Second)
+	}
+	names := podNames(pods)
+	level := m.confirmLevel(namespace, configs.ActionDeletePod)
+	if level == configs.ConfirmNone {
+		// Many pods at once always ask
+		level = configs.ConfirmYesNo
+	}
+	return m.confirmDialog.Request(
+		level,
+		"Delete Pods",
+		fmt.Sprintf("Delete %d pods in namespace '%s'?\n%s", len(names), namespace, bulkNamesPreview(names)),
+		"bulk_delete_pods",
+		namespace,
+		bulkDeleteRequest{namespace: namespace, names: names},
+	)
+}
+
+// maxBulkPreview is how many pod names the confirmation lists.
+const maxBulkPreview = 5
+
+// bulkNamesPreview lists the first pod names, with how many more there are.
+func bulkNamesPreview(names []string) string {
+	if len(names) <= maxBulkPreview {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:maxBulkPreview], ", "), len(names)-maxBulkPreview)
+}
+
+// startBulkDelete deletes pods in the background, struck through in the
+// pod list until a refresh confirms each delete, and shows the progress in
+// the result viewer. Returns a command that delivers the first
+// bulkDeleteProgressMsg or the bulkDeleteFinishedMsg.
+func (m *Model) startBulkDelete(req bulkDeleteRequest) tea.Cmd {
+	updates := make(chan tea.Msg, len(req.names)+1)
+	m.bulkDelete = &bulkDelete{namespace: req.namespace, names: req.names, updates: updates}
+	for _, name := range req.names {
+		m.mutations.Begin(component.PendingMutation{
+			Hint:   podHint(req.namespace, name),
+			Action: component.MutationDelete,
+		})
+	}
+	m.navigator.ClearMarks()
+	m.resultViewer.Show(m.bulkDelete.title(), component.RenderBulkDeleteProgress(req.namespace, req.names, nil, false), m.width-4, m.height-4)
+
+	client := m.k8sClient
+	go func() {
+		defer close(updates)
+		results, err := client.DeletePods(context.Background(), req.namespace, req.names, func(r repository.PodDeleteResult) {
+			updates <- bulkDeleteProgressMsg{result: r}
+		})
+		updates <- bulkDeleteFinishedMsg{results: results, err: err}
+	}()
+	return waitForBulkDelete(updates)
+}
+
+// waitForBulkDelete blocks until the delete reports progress or finishes.
+func waitForBulkDelete(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// showBulkDelete refreshes the delete's progress in the result viewer,
+// unless the user closed it.
+func (m *Model) showBulkDelete(finished bool) {
+	if m.resultViewer.IsVisible() && m.resultViewer.Title() == m.bulkDelete.title() {
+		m.resultViewer.SetContent(component.RenderBulkDeleteProgress(m.bulkDelete.namespace, m.bulkDelete.names, m.bulkDelete.done, finished))
+	}
+}
+
+// copyPodNames copies the names of pods to the clipboard, one per line.
+func (m *Model) copyPodNames(pods []repository.PodInfo) tea.Cmd {
+	copied, err := component.CopyToClipboard(strings.Join(podNames(pods), "\n"))
+	if err != nil {
+		m.statusMsg = "Copy failed: " + err.Error()
+	} else {
+		m.statusMsg = fmt.Sprintf("Copied %d pod names%s", len(pods), copied.Via())
+	}
+	return clearStatusAfter(3 * time.Second)
+}
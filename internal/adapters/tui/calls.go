@@ -0,0 +1,103 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains the deadlines and cancellation of calls to the cluster.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// timedOutLoad is a load of the current view that ran past its deadline.
+// The view shows it in place of its content until r retries it.
+type timedOutLoad struct {
+	op      string        // What was being loaded, e.g. "list deployments in prod"
+	timeout time.Duration // Deadline it ran past
+	retry   tea.Cmd       // Load to run again
+}
+
+// call runs fetch with a context of scope bounded by the timeout of kind.
+// A call abandoned because its scope was cancelled, the user having moved
+// on from what it loads, sends no message, so that a late answer never
+// overwrites the view that replaced it.
+func call(scope *repository.CallScope, kind repository.CallKind, fetch func(ctx context.Context) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := scope.Context(kind)
+		defer cancel()
+		msg := fetch(ctx)
+		if scope.Cancelled() {
+			return nil
+		}
+		return msg
+	}
+}
+
+// leaveNavigatorScope abandons the calls in flight for the navigator's
+// namespace or resource type, when either changes.
+func (m *Model) leaveNavigatorScope() {
+	m.navCalls = m.navCalls.Renew()
+	m.timedOut = nil
+	m.loading = false
+}
+
+// leavePodScope abandons the calls in flight for the dashboard's pod, when
+// it is left for another view or another pod.
+func (m *Model) leavePodScope() {
+	m.podCalls = m.podCalls.Renew()
+	m.timedOut = nil
+	m.loading = false
+}
+
+// loadTimedOut shows that op, a load of the current view made with kind's
+// timeout, timed out, to retry with r, and checks the connection: an
+// unreachable cluster times out too, and then gets the diagnostics screen
+// instead.
+func (m *Model) loadTimedOut(op string, kind repository.CallKind, retry tea.Cmd) tea.Cmd {
+	m.loading = false
+	m.timedOut = &timedOutLoad{op: op, timeout: m.navCalls.Timeouts().For(kind), retry: retry}
+	if m.connLost != nil || m.connChecking {
+		return nil
+	}
+	m.connChecking = true
+	return m.checkConnection()
+}
+
+// retryTimedOut runs the load that timed out again.
+func (m *Model) retryTimedOut() tea.Cmd {
+	retry := m.timedOut.retry
+	m.timedOut = nil
+	m.loading = true
+	return retry
+}
+
+// timedOut reports whether a request of the dashboard load ran past its
+// deadline.
+func (msg dashboardDataMsg) timedOut() bool {
+	for _, failure := range msg.failures {
+		if repository.IsCallTimeout(failure.Err) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderTimedOut is the content of a view whose load timed out.
+func (m Model) renderTimedOut(width, height int) string {
+	message := style.StatusError.Render(fmt.Sprintf("%s timed out after %s", m.timedOut.op, m.timedOut.timeout)) +
+		"\n\n" + style.StatusMuted.Render("Press r to retry, Esc to go back")
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, lipgloss.NewStyle().Align(lipgloss.Center).Render(message))
+}
+
+// logsError is the line the logs panel shows when fetching logs failed.
+func logsError(err error) []repository.LogLine {
+	content := "Error fetching logs: " + err.Error()
+	if repository.IsCallTimeout(err) {
+		content = "Fetching logs timed out, press r to retry"
+	}
+	return []repository.LogLine{{Content: content, IsError: true}}
+}
@@ -2,10 +2,12 @@ package component
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
 	"github.com/andrebassi/k1s/internal/adapters/tui/style"
 )
 
@@ -26,9 +28,10 @@ type ActionMenu struct {
 
 // ActionMenuResult is returned when an action is selected
 type ActionMenuResult struct {
-	Item    MenuItem
-	Copied  bool
-	Err     error
+	Item   MenuItem
+	Copied bool
+	Result CopyResult // How it was copied
+	Err    error
 }
 
 func NewActionMenu() ActionMenu {
@@ -66,10 +69,10 @@ func (m ActionMenu) Update(msg tea.Msg) (ActionMenu, tea.Cmd) {
 		case msg.String() == "enter":
 			if m.selected >= 0 && m.selected < len(m.items) {
 				item := m.items[m.selected]
-				err := CopyToClipboard(item.Value)
+				copied, err := CopyToClipboard(item.Value)
 				m.visible = false
 				return m, func() tea.Msg {
-					return ActionMenuResult{Item: item, Copied: true, Err: err}
+					return ActionMenuResult{Item: item, Copied: true, Result: copied, Err: err}
 				}
 			}
 
@@ -79,10 +82,10 @@ func (m ActionMenu) Update(msg tea.Msg) (ActionMenu, tea.Cmd) {
 				idx := int(msg.String()[0] - '1')
 				if idx < len(m.items) {
 					item := m.items[idx]
-					err := CopyToClipboard(item.Value)
+					copied, err := CopyToClipboard(item.Value)
 					m.visible = false
 					return m, func() tea.Msg {
-						return ActionMenuResult{Item: item, Copied: true, Err: err}
+						return ActionMenuResult{Item: item, Copied: true, Result: copied, Err: err}
 					}
 				}
 			}
@@ -228,8 +231,10 @@ func KubectlCommands(namespace, podName, containerName string, containers []stri
 type PodActionItem struct {
 	Label       string
 	Description string
-	Action      string // "delete", "exec", "port-forward", "copy"
+	Action      string // "delete", "exec", "port-forward", "copy", "remove-gate", "rollout-actions", "pvc-details", "copy-yaml", "save-yaml", "browse-files", "attach", "run-probe", "watch"
 	Command     string // kubectl command if applicable
+	Target      string // Action target (e.g. scheduling gate, container name or watch condition)
+	Disabled    bool   // Mutating action in read-only mode: shown greyed out, can't be selected
 }
 
 // PodActionMenuResult is returned when a pod action is selected
@@ -243,6 +248,7 @@ type PodActionMenu struct {
 	items    []PodActionItem
 	selected int
 	visible  bool
+	readOnly bool // Disable mutating actions
 }
 
 func NewPodActionMenu() PodActionMenu {
@@ -280,6 +286,9 @@ func (m PodActionMenu) Update(msg tea.Msg) (PodActionMenu, tea.Cmd) {
 		case msg.String() == "enter":
 			if m.selected >= 0 && m.selected < len(m.items) {
 				item := m.items[m.selected]
+				if item.Disabled {
+					return m, nil
+				}
 				m.visible = false
 				return m, func() tea.Msg {
 					return PodActionMenuResult{Item: item}
@@ -292,6 +301,9 @@ func (m PodActionMenu) Update(msg tea.Msg) (PodActionMenu, tea.Cmd) {
 				idx := int(msg.String()[0] - '1')
 				if idx < len(m.items) {
 					item := m.items[idx]
+					if item.Disabled {
+						return m, nil
+					}
 					m.visible = false
 					return m, func() tea.Msg {
 						return PodActionMenuResult{Item: item}
@@ -324,6 +336,10 @@ func (m PodActionMenu) View() string {
 		shortcut := fmt.Sprintf("[%d] ", i+1)
 		shortcutStyle := lipgloss.NewStyle().Foreground(style.Secondary)
 
+		if item.Disabled {
+			writeDisabledItem(&b, shortcut, item.Label, i == m.selected)
+			continue
+		}
 		if i == m.selected {
 			// Selected item
 			selectedStyle := lipgloss.NewStyle().
@@ -371,12 +387,20 @@ func (m PodActionMenu) View() string {
 }
 
 func (m *PodActionMenu) Show(title string, items []PodActionItem) {
+	if m.readOnly {
+		items = DisableMutatingPodActions(items)
+	}
 	m.title = title
 	m.items = items
 	m.selected = 0
 	m.visible = true
 }
 
+// SetReadOnly disables mutating actions in the menus shown from now on.
+func (m *PodActionMenu) SetReadOnly(readOnly bool) {
+	m.readOnly = readOnly
+}
+
 func (m *PodActionMenu) Hide() {
 	m.visible = false
 }
@@ -389,9 +413,17 @@ func (m PodActionMenu) IsVisible() bool {
 type WorkloadActionItem struct {
 	Label       string
 	Description string
-	Action      string // "scale", "restart", "copy"
+	Action      string // "scale", "restart", "promote", "abort", "retry", "trigger", "history", "rollback", "sts-details", "partitions", "partition", "images", "set-image", "resources", "resources-table", "set-resources", "helm", "watch", "copy", "copy-yaml", "save-yaml"
 	Replicas    int32  // For scale actions
+	Revision    int64  // For rollback actions
+	Partition   int32  // For partition actions
+	Container   string // For set-image and set-resources actions
+	Image       string // Current image, for set-image actions
 	Command     string // kubectl command
+	Disabled    bool   // Mutating action in read-only mode: shown greyed out, can't be selected
+
+	Resources *repository.ContainerResources // Current requests and limits, for set-resources actions
+	Condition repository.WatchCondition      // For watch actions
 }
 
 // WorkloadActionMenuResult is returned when a workload action is selected
@@ -405,6 +437,7 @@ type WorkloadActionMenu struct {
 	items    []WorkloadActionItem
 	selected int
 	visible  bool
+	readOnly bool // Disable mutating actions
 }
 
 func NewWorkloadActionMenu() WorkloadActionMenu {
@@ -435,6 +468,9 @@ func (m WorkloadActionMenu) Update(msg tea.Msg) (WorkloadActionMenu, tea.Cmd) {
 		case msg.String() == "enter":
 			if m.selected >= 0 && m.selected < len(m.items) {
 				item := m.items[m.selected]
+				if item.Disabled {
+					return m, nil
+				}
 				m.visible = false
 				return m, func() tea.Msg {
 					return WorkloadActionMenuResult{Item: item}
@@ -445,6 +481,9 @@ func (m WorkloadActionMenu) Update(msg tea.Msg) (WorkloadActionMenu, tea.Cmd) {
 				idx := int(msg.String()[0] - '1')
 				if idx < len(m.items) {
 					item := m.items[idx]
+					if item.Disabled {
+						return m, nil
+					}
 					m.visible = false
 					return m, func() tea.Msg {
 						return WorkloadActionMenuResult{Item: item}
@@ -470,6 +509,10 @@ func (m WorkloadActionMenu) View() string {
 		shortcut := fmt.Sprintf("[%d] ", i+1)
 		shortcutStyle := lipgloss.NewStyle().Foreground(style.Secondary)
 
+		if item.Disabled {
+			writeDisabledItem(&b, shortcut, item.Label, i == m.selected)
+			continue
+		}
 		if i == m.selected {
 			selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Background).Background(style.Primary)
 			descStyle := lipgloss.NewStyle().Foreground(style.TextMuted).Italic(true)
@@ -505,15 +548,254 @@ func (m WorkloadActionMenu) View() string {
 }
 
 func (m *WorkloadActionMenu) Show(title string, items []WorkloadActionItem) {
+	if m.readOnly {
+		items = DisableMutatingWorkloadActions(items)
+	}
 	m.title = title
 	m.items = items
 	m.selected = 0
 	m.visible = true
 }
 
+// SetReadOnly disables mutating actions in the menus shown from now on.
+func (m *WorkloadActionMenu) SetReadOnly(readOnly bool) { m.readOnly = readOnly }
+
 func (m *WorkloadActionMenu) Hide() { m.visible = false }
 func (m WorkloadActionMenu) IsVisible() bool { return m.visible }
 
+// NodeActionItem represents an action for a node
+type NodeActionItem struct {
+	Label       string
+	Description string
+	Action      string // "simulate-drain", "cordon", "uncordon", "drain", "copy"
+	Node        string // Target node name
+	Command     string // kubectl command
+	Disabled    bool   // Mutating action in read-only mode: shown greyed out, can't be selected
+}
+
+// NodeActionMenuResult is returned when a node action is selected
+type NodeActionMenuResult struct {
+	Item NodeActionItem
+}
+
+// NodeActionMenu for node actions
+type NodeActionMenu struct {
+	title    string
+	items    []NodeActionItem
+	selected int
+	visible  bool
+	readOnly bool // Disable mutating actions
+}
+
+func NewNodeActionMenu() NodeActionMenu {
+	return NodeActionMenu{selected: 0}
+}
+
+func (m NodeActionMenu) Init() tea.Cmd { return nil }
+
+func (m NodeActionMenu) Update(msg tea.Msg) (NodeActionMenu, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case msg.String() == "esc" || msg.String() == "q":
+			m.visible = false
+			return m, nil
+		case msg.String() == "up" || msg.String() == "k":
+			if m.selected > 0 {
+				m.selected--
+			}
+		case msg.String() == "down" || msg.String() == "j":
+			if m.selected < len(m.items)-1 {
+				m.selected++
+			}
+		case msg.String() == "enter":
+			if m.selected >= 0 && m.selected < len(m.items) {
+				item := m.items[m.selected]
+				if item.Disabled {
+					return m, nil
+				}
+				m.visible = false
+				return m, func() tea.Msg {
+					return NodeActionMenuResult{Item: item}
+				}
+			}
+		default:
+			if len(msg.String()) == 1 && msg.String()[0] >= '1' && msg.String()[0] <= '9' {
+				idx := int(msg.String()[0] - '1')
+				if idx < len(m.items) {
+					item := m.items[idx]
+					if item.Disabled {
+						return m, nil
+					}
+					m.visible = false
+					return m, func() tea.Msg {
+						return NodeActionMenuResult{Item: item}
+					}
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m NodeActionMenu) View() string {
+	if !m.visible || len(m.items) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Primary).MarginBottom(1)
+	b.WriteString(titleStyle.Render(m.title))
+	b.WriteString("\n\n")
+
+	for i, item := range m.items {
+		shortcut := fmt.Sprintf("[%d] ", i+1)
+		shortcutStyle := lipgloss.NewStyle().Foreground(style.Secondary)
+
+		if item.Disabled {
+			writeDisabledItem(&b, shortcut, item.Label, i == m.selected)
+			continue
+		}
+		if i == m.selected {
+			selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Background).Background(style.Primary)
+			descStyle := lipgloss.NewStyle().Foreground(style.TextMuted).Italic(true)
+			b.WriteString(shortcutStyle.Render(shortcut))
+			b.WriteString(selectedStyle.Render(item.Label))
+			if item.Description != "" {
+				b.WriteString(" ")
+				b.WriteString(descStyle.Render(item.Description))
+			}
+		} else {
+			normalStyle := lipgloss.NewStyle().Foreground(style.Text)
+			descStyle := lipgloss.NewStyle().Foreground(style.Muted)
+			b.WriteString(shortcutStyle.Render(shortcut))
+			b.WriteString(normalStyle.Render(item.Label))
+			if item.Description != "" {
+				b.WriteString(" ")
+				b.WriteString(descStyle.Render(item.Description))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	hintStyle := lipgloss.NewStyle().Foreground(style.Muted).MarginTop(1)
+	b.WriteString("\n")
+	b.WriteString(hintStyle.Render("Press number or Enter to select • Esc to close"))
+
+	content := b.String()
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Primary).
+		Padding(1, 2)
+	return boxStyle.Render(content)
+}
+
+func (m *NodeActionMenu) Show(title string, items []NodeActionItem) {
+	if m.readOnly {
+		items = DisableMutatingNodeActions(items)
+	}
+	m.title = title
+	m.items = items
+	m.selected = 0
+	m.visible = true
+}
+
+// SetReadOnly disables mutating actions in the menus shown from now on.
+func (m *NodeActionMenu) SetReadOnly(readOnly bool) { m.readOnly = readOnly }
+
+func (m *NodeActionMenu) Hide() { m.visible = false }
+func (m NodeActionMenu) IsVisible() bool { return m.visible }
+
+// writeDisabledItem renders a menu item disabled in read-only mode.
+func writeDisabledItem(b *strings.Builder, shortcut, label string, selected bool) {
+	mutedStyle := lipgloss.NewStyle().Foreground(style.Muted)
+	labelStyle := mutedStyle
+	if selected {
+		labelStyle = labelStyle.Bold(true).Underline(true)
+	}
+	b.WriteString(mutedStyle.Render(shortcut))
+	b.WriteString(labelStyle.Render(label))
+	b.WriteString(" ")
+	b.WriteString(mutedStyle.Italic(true).Render("(read-only)"))
+	b.WriteString("\n")
+}
+
+// Actions that change the cluster, disabled in read-only mode. Exec is
+// included since a shell can change anything the container can, and the
+// file browser since it lists and reads files through exec as well.
+var (
+	mutatingPodActions      = map[string]bool{"delete": true, "exec": true, "browse-files": true, "remove-gate": true, "restart-pod": true, "restart-workload": true, "debug-container": true, "attach": true, "run-probe": true}
+	mutatingWorkloadActions = map[string]bool{"scale": true, "restart": true, "promote": true, "abort": true, "retry": true, "trigger": true, "rollback": true, "partition": true, "images": true, "set-image": true, "set-resources": true, "bulk-delete": true}
+	mutatingNodeActions     = map[string]bool{"cordon": true, "uncordon": true, "drain": true}
+)
+
+// DisableMutatingPodActions returns a copy of items with the actions that
+// change the cluster disabled.
+func DisableMutatingPodActions(items []PodActionItem) []PodActionItem {
+	out := make([]PodActionItem, len(items))
+	for i, item := range items {
+		item.Disabled = item.Disabled || mutatingPodActions[item.Action]
+		out[i] = item
+	}
+	return out
+}
+
+// DisableMutatingWorkloadActions returns a copy of items with the actions
+// that change the cluster disabled.
+func DisableMutatingWorkloadActions(items []WorkloadActionItem) []WorkloadActionItem {
+	out := make([]WorkloadActionItem, len(items))
+	for i, item := range items {
+		item.Disabled = item.Disabled || mutatingWorkloadActions[item.Action]
+		out[i] = item
+	}
+	return out
+}
+
+// DisableMutatingNodeActions returns a copy of items with the actions that
+// change the cluster disabled.
+func DisableMutatingNodeActions(items []NodeActionItem) []NodeActionItem {
+	out := make([]NodeActionItem, len(items))
+	for i, item := range items {
+		item.Disabled = item.Disabled || mutatingNodeActions[item.Action]
+		out[i] = item
+	}
+	return out
+}
+
+// NodeActions returns the available actions for a node. The dry run
+// comes first; cordoned selects Uncordon rather than Cordon.
+func NodeActions(nodeName string, cordoned bool) []NodeActionItem {
+	cordon := NodeActionItem{Label: "Cordon", Description: "(stop scheduling pods)", Action: "cordon", Node: nodeName}
+	if cordoned {
+		cordon = NodeActionItem{Label: "Uncordon", Description: "(allow scheduling pods)", Action: "uncordon", Node: nodeName}
+	}
+	return []NodeActionItem{
+		{
+			Label:       "Simulate Drain",
+			Description: "(dry run, no changes)",
+			Action:      "simulate-drain",
+			Node:        nodeName,
+		},
+		cordon,
+		{
+			Label:       "Drain",
+			Description: "(cordon and evict pods)",
+			Action:      "drain",
+			Node:        nodeName,
+		},
+		{
+			Label:   "Copy drain command",
+			Action:  "copy",
+			Node:    nodeName,
+			Command: fmt.Sprintf("kubectl drain %s --ignore-daemonsets --delete-emptydir-data", nodeName),
+		},
+	}
+}
+
 // ScaleActions returns scale options for a workload
 func ScaleActions(namespace, name, resourceType string, currentReplicas int32) []WorkloadActionItem {
 	items := []WorkloadActionItem{
@@ -546,6 +828,166 @@ func ScaleActions(namespace, name, resourceType string, currentReplicas int32) [
 	return items
 }
 
+// RolloutActions returns the update controls of an Argo Rollout, shown
+// above its scale options
+func RolloutActions(namespace, name string) []WorkloadActionItem {
+	return []WorkloadActionItem{
+		{Label: "Promote", Description: "resume or skip the current step", Action: "promote"},
+		{Label: "Abort", Description: "roll back to the stable version", Action: "abort"},
+		{Label: "Retry", Description: "restart an aborted update", Action: "retry"},
+		{
+			Label:   "Copy promote command",
+			Action:  "copy",
+			Command: fmt.Sprintf("kubectl argo rollouts promote %s -n %s", name, namespace),
+		},
+	}
+}
+
+// CronJobActions returns the actions of a CronJob: running it now, as
+// kubectl create job --from does, and its run history
+func CronJobActions(namespace, name string, suspended bool) []WorkloadActionItem {
+	description := "create a Job from its template"
+	if suspended {
+		description = "suspended; runs anyway"
+	}
+	return []WorkloadActionItem{
+		{Label: "Trigger now", Description: description, Action: "trigger"},
+		{Label: "Run history", Description: "past runs, their logs and the next run", Action: "runs"},
+		{
+			Label:   "Copy trigger command",
+			Action:  "copy",
+			Command: fmt.Sprintf("kubectl create job --from=cronjob/%s %s-manual -n %s", name, name, namespace),
+		},
+	}
+}
+
+// HPAAction opens the HPA scaling the workload, if any
+func HPAAction() WorkloadActionItem {
+	return WorkloadActionItem{Label: "HPA / Scaling timeline", Description: "metrics vs target and recent rescales", Action: "hpa"}
+}
+
+// HistoryAction opens the revision history of a Deployment
+func HistoryAction() WorkloadActionItem {
+	return WorkloadActionItem{Label: "History / Rollback", Description: "revisions and rollback", Action: "history"}
+}
+
+// ImageAction lists the images of the workload's containers to change one
+func ImageAction() WorkloadActionItem {
+	return WorkloadActionItem{Label: "Set image", Description: "change a container's image and follow the rollout", Action: "images"}
+}
+
+// StatefulSetActions open the ordinals of a StatefulSet and the partition
+// of its rolling update
+func StatefulSetActions() []WorkloadActionItem {
+	return []WorkloadActionItem{
+		{Label: "Ordinals / PVCs", Description: "revision and claims per ordinal", Action: "sts-details"},
+		{Label: "Partition rollout", Description: "advance a partitioned update", Action: "partitions"},
+	}
+}
+
+// DaemonSetActions open the pod of a DaemonSet on each node
+func DaemonSetActions() []WorkloadActionItem {
+	return []WorkloadActionItem{
+		{Label: "Pods per node", Description: "rollout by node, and nodes without a pod", Action: "ds-nodes"},
+	}
+}
+
+// BulkPodActions act on the count pods selected in the pod list. Pods in
+// a protected namespace can't be deleted in bulk.
+func BulkPodActions(count int, protected bool) []WorkloadActionItem {
+	var items []WorkloadActionItem
+	if !protected {
+		items = append(items, WorkloadActionItem{Label: fmt.Sprintf("Delete %d pods", count), Description: "concurrently, reporting each pod", Action: "bulk-delete"})
+	}
+	return append(items, WorkloadActionItem{Label: fmt.Sprintf("Copy %d pod names", count), Description: "one per line", Action: "bulk-copy-names"})
+}
+
+// PartitionActions lists the partitions a partitioned rolling update can be
+// advanced to: one ordinal further, or all the way to 0 to update every
+// ordinal.
+func PartitionActions(d *repository.StatefulSetDetails) []WorkloadActionItem {
+	var items []WorkloadActionItem
+	if d.Partitioned() {
+		next := d.Partition - 1
+		items = append(items, WorkloadActionItem{
+			Label:       fmt.Sprintf("Partition %d → %d", d.Partition, next),
+			Description: fmt.Sprintf("update ordinal %d", next),
+			Action:      "partition",
+			Partition:   next,
+		})
+		if next > 0 {
+			items = append(items, WorkloadActionItem{
+				Label:       fmt.Sprintf("Partition %d → 0", d.Partition),
+				Description: "update every ordinal",
+				Action:      "partition",
+				Partition:   0,
+			})
+		}
+	} else {
+		items = append(items, WorkloadActionItem{
+			Label:       fmt.Sprintf("Partition %d", d.Partition),
+			Description: "not partitioned: every ordinal is updated",
+		})
+	}
+	return append(items, WorkloadActionItem{
+		Label:   "Copy partition command",
+		Action:  "copy",
+		Command: fmt.Sprintf(`kubectl patch statefulset %s -n %s -p '{"spec":{"updateStrategy":{"rollingUpdate":{"partition":0}}}}'`, d.Name, d.Namespace),
+	})
+}
+
+// ContainerImageActions lists the containers of a workload's pod template
+// with their current image, each opening the image for editing.
+func ContainerImageActions(namespace, kind, name string, images []repository.ContainerImage) []WorkloadActionItem {
+	var items []WorkloadActionItem
+	for _, c := range images {
+		label := c.Container
+		if c.Init {
+			label += " (init)"
+		}
+		items = append(items, WorkloadActionItem{
+			Label:       label,
+			Description: c.Image,
+			Action:      "set-image",
+			Container:   c.Container,
+			Image:       c.Image,
+		})
+	}
+	command := fmt.Sprintf("kubectl set image %s/%s -n %s CONTAINER=IMAGE", strings.ToLower(kind), name, namespace)
+	if kind == "Rollout" {
+		command = fmt.Sprintf("kubectl argo rollouts set image %s -n %s CONTAINER=IMAGE", name, namespace)
+	}
+	return append(items, WorkloadActionItem{Label: "Copy set image command", Action: "copy", Command: command})
+}
+
+// RevisionActions lists the revisions of a Deployment, newest first, as
+// rollback targets. The current revision is shown but can't be selected
+// for a rollback.
+func RevisionActions(namespace, name string, history []repository.RevisionInfo) []WorkloadActionItem {
+	var items []WorkloadActionItem
+	for _, rev := range history {
+		item := WorkloadActionItem{
+			Label:       fmt.Sprintf("Revision %d  %s", rev.Revision, strings.Join(rev.Images, ", ")),
+			Description: fmt.Sprintf("%d/%d ready, %s", rev.ReadyReplicas, rev.Replicas, rev.Age),
+			Action:      "rollback",
+			Revision:    rev.Revision,
+		}
+		if rev.ChangeCause != "" {
+			item.Description += ", " + rev.ChangeCause
+		}
+		if rev.Current {
+			item.Description = "current, " + item.Description
+			item.Action = ""
+		}
+		items = append(items, item)
+	}
+	return append(items, WorkloadActionItem{
+		Label:   "Copy rollback command",
+		Action:  "copy",
+		Command: fmt.Sprintf("kubectl rollout undo deployment/%s -n %s --to-revision=", name, namespace),
+	})
+}
+
 // PodActions returns the available actions for a pod
 func PodActions(namespace, podName string, containers []string) []PodActionItem {
 	items := []PodActionItem{
@@ -557,38 +999,35 @@ func PodActions(namespace, podName string, containers []string) []PodActionItem
 		},
 	}
 
-	// Add exec options
+	// Add exec options; the shell is /bin/bash, falling back to /bin/sh
 	if len(containers) == 1 {
 		items = append(items, PodActionItem{
-			Label:       "Exec (sh)",
+			Label:       "Exec (shell)",
 			Description: "opens shell in terminal",
 			Action:      "exec",
 			Command:     fmt.Sprintf("kubectl exec -it -n %s %s -- sh", namespace, podName),
-		})
-		items = append(items, PodActionItem{
-			Label:       "Exec (bash)",
-			Description: "opens shell in terminal",
-			Action:      "exec",
-			Command:     fmt.Sprintf("kubectl exec -it -n %s %s -- bash", namespace, podName),
+			Target:      containers[0],
 		})
 	} else if len(containers) > 1 {
-		// Multi-container pod - exec into first container by default
+		// Multi-container pod - one entry per container to pick from
 		for _, container := range containers {
 			items = append(items, PodActionItem{
-				Label:       fmt.Sprintf("Exec into '%s' (sh)", container),
+				Label:       fmt.Sprintf("Exec into '%s'", container),
 				Description: "opens shell in terminal",
 				Action:      "exec",
 				Command:     fmt.Sprintf("kubectl exec -it -n %s %s -c %s -- sh", namespace, podName, container),
+				Target:      container,
 			})
 		}
 	}
 
-	// Add port-forward option - runs in foreground (Ctrl+C to return)
+	// Add port-forward option - runs in the background, F lists forwards
 	items = append(items, PodActionItem{
-		Label:       "Port Forward :8080",
-		Description: "runs in terminal, Ctrl+C to stop",
+		Label:       fmt.Sprintf("Port Forward :%d", DefaultForwardPort),
+		Description: "in the background, F to list",
 		Action:      "port-forward",
-		Command:     fmt.Sprintf("kubectl port-forward -n %s %s 8080:8080", namespace, podName),
+		Command:     fmt.Sprintf("kubectl port-forward -n %s %s %d:%d", namespace, podName, DefaultForwardPort, DefaultForwardPort),
+		Target:      strconv.Itoa(DefaultForwardPort),
 	})
 
 	// Add describe - runs and shows output
@@ -609,3 +1048,359 @@ func PodActions(namespace, podName string, containers []string) []PodActionItem
 
 	return items
 }
+
+// DefaultForwardPort is the pod port PodActions offers to forward, whether
+// or not the pod declares it.
+const DefaultForwardPort = 8080
+
+// PortForwardActions returns a port-forward action for each TCP port the
+// pod's containers declare, other than DefaultForwardPort which PodActions
+// offers, and for each port of the Services in front of the pod. The
+// Target is the pod port, or "svc/<name>:<port>" for a Service port; see
+// ParsePortForwardTarget.
+func PortForwardActions(namespace, podName string, containers []repository.ContainerInfo, related *repository.RelatedResources) []PodActionItem {
+	var items []PodActionItem
+	seen := map[int32]bool{DefaultForwardPort: true}
+	for _, c := range containers {
+		for _, p := range c.Ports {
+			if seen[p.ContainerPort] || (p.Protocol != "" && p.Protocol != "TCP") {
+				continue
+			}
+			seen[p.ContainerPort] = true
+			label := fmt.Sprintf("Port Forward :%d (%s)", p.ContainerPort, c.Name)
+			if p.Name != "" {
+				label = fmt.Sprintf("Port Forward :%d (%s/%s)", p.ContainerPort, c.Name, p.Name)
+			}
+			items = append(items, PodActionItem{
+				Label:       label,
+				Description: "in the background, F to list",
+				Action:      "port-forward",
+				Command:     fmt.Sprintf("kubectl port-forward -n %s %s %d:%d", namespace, podName, p.ContainerPort, p.ContainerPort),
+				Target:      strconv.Itoa(int(p.ContainerPort)),
+			})
+		}
+	}
+	if related == nil {
+		return items
+	}
+	for _, svc := range related.Services {
+		if svc.NoSelector {
+			continue
+		}
+		for _, port := range svc.PortNumbers {
+			items = append(items, PodActionItem{
+				Label:       fmt.Sprintf("Port Forward svc/%s :%d", svc.Name, port),
+				Description: "to a pod of the Service, F to list",
+				Action:      "port-forward",
+				Command:     fmt.Sprintf("kubectl port-forward -n %s svc/%s %d:%d", namespace, svc.Name, port, port),
+				Target:      fmt.Sprintf("svc/%s:%d", svc.Name, port),
+			})
+		}
+	}
+	return items
+}
+
+// ParsePortForwardTarget splits the Target of a port-forward action into
+// the Service ("" for a pod port) and the port.
+func ParsePortForwardTarget(target string) (service string, port int32, ok bool) {
+	portText := target
+	if rest, found := strings.CutPrefix(target, "svc/"); found {
+		i := strings.LastIndex(rest, ":")
+		if i <= 0 {
+			return "", 0, false
+		}
+		service, portText = rest[:i], rest[i+1:]
+	}
+	n, err := strconv.ParseInt(portText, 10, 32)
+	if err != nil || n < 1 || n > 65535 {
+		return "", 0, false
+	}
+	return service, int32(n), true
+}
+
+// DebugContainerActions returns, per container and debug image, an action
+// injecting an ephemeral container that shares the container's processes
+// and opening a shell in it, for images without a shell of their own.
+// Running debug containers can be exec'd into again.
+func DebugContainerActions(namespace, podName string, containers []string, ephemeral []repository.ContainerInfo) []PodActionItem {
+	var items []PodActionItem
+	for _, c := range ephemeral {
+		if c.State != "Running" {
+			continue
+		}
+		items = append(items, PodActionItem{
+			Label:       fmt.Sprintf("Exec into '%s' (ephemeral)", c.Name),
+			Description: "opens shell in terminal",
+			Action:      "exec",
+			Command:     fmt.Sprintf("kubectl exec -it -n %s %s -c %s -- sh", namespace, podName, c.Name),
+			Target:      c.Name,
+		})
+	}
+	for _, container := range containers {
+		for _, image := range repository.DebugImages {
+			// "nicolaka/netshoot:latest" is offered as netshoot
+			short, _, _ := strings.Cut(image[strings.LastIndex(image, "/")+1:], ":")
+			label := fmt.Sprintf("Debug shell (%s)", short)
+			if len(containers) > 1 {
+				label = fmt.Sprintf("Debug '%s' (%s)", container, short)
+			}
+			items = append(items, PodActionItem{
+				Label:       label,
+				Description: "ephemeral container, shares processes",
+				Action:      "debug-container",
+				Command:     fmt.Sprintf("kubectl debug -it -n %s %s --image=%s --target=%s", namespace, podName, image, container),
+				Target:      container + "/" + image,
+			})
+		}
+	}
+	return items
+}
+
+// FileBrowserActions returns one "browse files" action per container, which
+// opens the file browser on that container's filesystem.
+func FileBrowserActions(containers []string) []PodActionItem {
+	var items []PodActionItem
+	for _, container := range containers {
+		label := "Browse files"
+		if len(containers) > 1 {
+			label = fmt.Sprintf("Browse files in '%s'", container)
+		}
+		items = append(items, PodActionItem{
+			Label:       label,
+			Description: "preview and copy files",
+			Action:      "browse-files",
+			Target:      container,
+		})
+	}
+	return items
+}
+
+// AttachActions returns one "attach" action per running container, which
+// connects the terminal to the container's main process, as kubectl attach
+// does, rather than starting a shell beside it.
+func AttachActions(namespace, podName string, containers []repository.ContainerInfo) []PodActionItem {
+	var items []PodActionItem
+	for _, c := range containers {
+		if c.State != "Running" {
+			continue
+		}
+		label := "Attach"
+		if len(containers) > 1 {
+			label = fmt.Sprintf("Attach to '%s'", c.Name)
+		}
+		items = append(items, PodActionItem{
+			Label:       label,
+			Description: "main process, Ctrl+P Ctrl+Q detaches",
+			Action:      "attach",
+			Command:     fmt.Sprintf("kubectl attach -it -n %s %s -c %s", namespace, podName, c.Name),
+			Target:      c.Name,
+		})
+	}
+	return items
+}
+
+// ProbeRunActions returns one "run probe now" action per probe that can be
+// reproduced from inside the pod, which runs it once as the kubelet would
+// and shows what came back.
+func ProbeRunActions(probes []repository.ProbeStatus) []PodActionItem {
+	containers := map[string]bool{}
+	for _, p := range probes {
+		containers[p.Container] = true
+	}
+	var items []PodActionItem
+	for _, p := range probes {
+		if p.Config == nil {
+			continue
+		}
+		label := fmt.Sprintf("Run %s probe now", strings.ToLower(p.Probe))
+		if len(containers) > 1 {
+			label = fmt.Sprintf("Run %s probe of '%s'", strings.ToLower(p.Probe), p.Container)
+		}
+		items = append(items, PodActionItem{
+			Label:       label,
+			Description: p.Target(),
+			Action:      "run-probe",
+			Target:      p.Container + "/" + p.Probe,
+		})
+	}
+	return items
+}
+
+// RestartActions returns the ways to restart a pod owned by a workload:
+// deleting just this pod and re-attaching to its replacement, or rolling
+// the whole workload. Pods without a workload get neither, since nothing
+// would recreate them.
+func RestartActions(namespace, podName, workloadKind, workloadName string) []PodActionItem {
+	if workloadKind == "" {
+		return nil
+	}
+	items := []PodActionItem{{
+		Label:       "Restart pod (delete & re-attach)",
+		Description: "replaces this pod only",
+		Action:      "restart-pod",
+		Command:     fmt.Sprintf("kubectl delete pod -n %s %s", namespace, podName),
+	}}
+	switch workloadKind {
+	case "Deployment", "StatefulSet", "DaemonSet":
+		items = append(items, PodActionItem{
+			Label:       "Restart workload (rollout)",
+			Description: "replaces every pod of " + workloadName,
+			Action:      "restart-workload",
+			Command:     fmt.Sprintf("kubectl rollout restart -n %s %s/%s", namespace, strings.ToLower(workloadKind), workloadName),
+			Target:      workloadKind + "/" + workloadName,
+		})
+	}
+	return items
+}
+
+// ShareLinkAction returns a "copy" action for a k1s:// link to the current
+// view, or nothing when there is no link to share.
+func ShareLinkAction(link string) []PodActionItem {
+	if link == "" {
+		return nil
+	}
+	return []PodActionItem{{
+		Label:       "Copy k1s link",
+		Description: "reopen with: k1s <link>",
+		Action:      "copy",
+		Command:     link,
+	}}
+}
+
+// SchedulingGateActions returns one "remove gate" action per scheduling gate.
+// Gates are listed after the regular pod actions since they are a last resort.
+func SchedulingGateActions(namespace, podName string, gates []string) []PodActionItem {
+	var items []PodActionItem
+	for i, gate := range gates {
+		items = append(items, PodActionItem{
+			Label:       fmt.Sprintf("Remove gate '%s'", gate),
+			Description: "(requires confirmation)",
+			Action:      "remove-gate",
+			Command: fmt.Sprintf(
+				`kubectl patch pod -n %s %s --type=json -p '[{"op":"remove","path":"/spec/schedulingGates/%d"}]'`,
+				namespace, podName, i,
+			),
+			Target: gate,
+		})
+	}
+	return items
+}
+
+// RolloutOwnerActions returns an action opening the promote, abort and
+// retry controls when the pod's workload is an Argo Rollout.
+func RolloutOwnerActions(workloadKind, workloadName string) []PodActionItem {
+	if workloadKind != "Rollout" {
+		return nil
+	}
+	return []PodActionItem{{
+		Label:       "Rollout actions",
+		Description: "promote, abort or retry " + workloadName,
+		Action:      "rollout-actions",
+		Target:      workloadName,
+	}}
+}
+
+// NodeCapacityAction returns an action opening the node capacity table.
+// For a pod no node was assigned to, the table shows which nodes it fits
+// on and why not the others.
+func NodeCapacityAction(unscheduled bool) []PodActionItem {
+	item := PodActionItem{
+		Label:       "Node capacity",
+		Description: "requests vs allocatable on every node",
+		Action:      "node-capacity",
+		Command:     "kubectl describe nodes",
+	}
+	if unscheduled {
+		item.Description = "which nodes the pod fits on, and why not the others"
+	}
+	return []PodActionItem{item}
+}
+
+// HPADetailActions returns an "HPA details" action when an HPA scales the
+// pod's workload.
+func HPADetailActions(namespace string, related *repository.RelatedResources) []PodActionItem {
+	if related == nil || related.HPA == nil {
+		return nil
+	}
+	return []PodActionItem{{
+		Label:       fmt.Sprintf("HPA '%s' details", related.HPA.Name),
+		Description: "metrics vs target, behavior, scaling timeline",
+		Action:      "hpa-details",
+		Command:     fmt.Sprintf("kubectl describe hpa -n %s %s", namespace, related.HPA.Name),
+		Target:      related.HPA.Name,
+	}}
+}
+
+// PVCDetailActions returns one "PVC details" action per claim mounted by the pod.
+func PVCDetailActions(namespace string, volumes []repository.VolumeInfo) []PodActionItem {
+	var items []PodActionItem
+	for _, v := range volumes {
+		if v.Type != "PVC" || v.Source == "" {
+			continue
+		}
+		items = append(items, PodActionItem{
+			Label:       fmt.Sprintf("PVC '%s' details", v.Source),
+			Description: "storage class, volume, events",
+			Action:      "pvc-details",
+			Command:     fmt.Sprintf("kubectl describe pvc -n %s %s", namespace, v.Source),
+			Target:      v.Source,
+		})
+	}
+	return items
+}
+
+// ResourceYAMLActions returns actions copying the YAML of a pod or of the
+// workload owning it to the clipboard, or saving it to a file. The target is
+// "<Kind>/<name>". Returns nothing for kinds that can't be exported.
+func ResourceYAMLActions(namespace, kind, name string) []PodActionItem {
+	if _, ok := repository.GVRForKind(kind); !ok || name == "" {
+		return nil
+	}
+	command := fmt.Sprintf("kubectl get %s -n %s %s -o yaml", strings.ToLower(kind), namespace, name)
+	return []PodActionItem{
+		{
+			Label:       fmt.Sprintf("Copy %s YAML", kind),
+			Description: "without status and managedFields",
+			Action:      "copy-yaml",
+			Command:     command,
+			Target:      kind + "/" + name,
+		},
+		{
+			Label:       fmt.Sprintf("Save %s YAML to file", kind),
+			Description: "full object, prompts for a path",
+			Action:      "save-yaml",
+			Command:     command,
+			Target:      kind + "/" + name,
+		},
+	}
+}
+
+// SnapshotAction returns the action exporting the debugging context of a
+// pod (YAML, describe, logs, events, related resources and metrics) to a
+// directory or .tar.gz for offline analysis.
+func SnapshotAction(podName string) []PodActionItem {
+	if podName == "" {
+		return nil
+	}
+	return []PodActionItem{{
+		Label:       "Export snapshot",
+		Description: "YAML, logs, events and metrics to a file",
+		Action:      "export-snapshot",
+		Target:      podName,
+	}}
+}
+
+// WorkloadYAMLActions returns the workload menu actions copying the YAML of
+// a workload to the clipboard or saving it to a file.
+func WorkloadYAMLActions(namespace, kind, name string) []WorkloadActionItem {
+	var items []WorkloadActionItem
+	for _, item := range ResourceYAMLActions(namespace, kind, name) {
+		items = append(items, WorkloadActionItem{
+			Label:       item.Label,
+			Description: item.Description,
+			Action:      item.Action,
+			Command:     item.Command,
+		})
+	}
+	return items
+}
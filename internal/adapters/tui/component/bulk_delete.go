@@ -0,0 +1,57 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// RenderBulkDeleteProgress renders the progress of deleting the named pods
+// of a namespace: each pod deleting, deleted or failed with its error, and
+// once finished a summary.
+func RenderBulkDeleteProgress(namespace string, names []string, done []repository.PodDeleteResult, finished bool) string {
+	var b strings.Builder
+
+	results := make(map[string]repository.PodDeleteResult, len(done))
+	failed := 0
+	for _, r := range done {
+		results[r.Name] = r
+		if r.Err != nil {
+			failed++
+		}
+	}
+
+	if !finished {
+		b.WriteString(style.StatusPending.Render(fmt.Sprintf("Deleting %d/%d pods in %s... (Esc hides this, the deletes continue)",
+			len(done), len(names), namespace)))
+		b.WriteString("\n\n")
+	} else {
+		b.WriteString(style.SubtitleStyle.Render("Summary"))
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  %-20s %s\n", "Namespace:", namespace))
+		b.WriteString(fmt.Sprintf("  %-20s %s\n", "Deleted:",
+			style.StatusRunning.Render(fmt.Sprintf("%d", len(done)-failed))))
+		b.WriteString(fmt.Sprintf("  %-20s %s\n", "Failed:", drainCountStyle(failed)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(style.SubtitleStyle.Render("Pods"))
+	b.WriteString("\n")
+	for _, name := range names {
+		r, ok := results[name]
+		switch {
+		case !ok:
+			b.WriteString(fmt.Sprintf("  %s %s\n", style.StatusPending.Render(fmt.Sprintf("%-9s", "deleting")), name))
+		case r.Err != nil:
+			b.WriteString(fmt.Sprintf("  %s %s", style.StatusError.Render(fmt.Sprintf("%-9s", "failed")), name))
+			b.WriteString(style.StatusMuted.Render("  " + r.Err.Error()))
+			b.WriteString("\n")
+		default:
+			b.WriteString(fmt.Sprintf("  %s %s\n", style.StatusRunning.Render(fmt.Sprintf("%-9s", "deleted")), name))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,184 @@
+package component
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// ChangeHighlightDuration is how long a changed field stays tinted.
+const ChangeHighlightDuration = 3 * time.Second
+
+// Displayed fields compared between refreshes.
+const (
+	FieldStatus   = "status"
+	FieldReady    = "ready"
+	FieldRestarts = "restarts"
+	FieldCount    = "count"
+)
+
+// ChangeSnapshot maps an object identity to the displayed field values
+// that are compared on the next refresh.
+type ChangeSnapshot map[string]map[string]string
+
+// ChangeTracker diffs each refresh against the previous one and remembers
+// which fields changed until the highlight expires. Objects are matched by
+// identity, so new or removed objects are never highlighted. A nil
+// *ChangeTracker tracks nothing.
+type ChangeTracker struct {
+	enabled  bool
+	ttl      time.Duration
+	now      func() time.Time
+	previous ChangeSnapshot
+	changed  map[string]map[string]time.Time // Identity -> field -> expiry
+}
+
+// NewChangeTracker creates a disabled tracker whose highlights last ttl.
+// now is the clock; tests pass a fake one.
+func NewChangeTracker(ttl time.Duration, now func() time.Time) *ChangeTracker {
+	return &ChangeTracker{
+		ttl:     ttl,
+		now:     now,
+		changed: make(map[string]map[string]time.Time),
+	}
+}
+
+// SetEnabled turns highlighting on or off. Turning it off drops pending
+// highlights; the last snapshot is kept so the next refresh can be diffed.
+func (c *ChangeTracker) SetEnabled(enabled bool) {
+	if c == nil {
+		return
+	}
+	c.enabled = enabled
+	if !enabled {
+		c.changed = make(map[string]map[string]time.Time)
+	}
+}
+
+// Enabled reports whether highlighting is on.
+func (c *ChangeTracker) Enabled() bool {
+	return c != nil && c.enabled
+}
+
+// Observe records a new snapshot. When enabled, every field whose value
+// differs from the previous snapshot is highlighted for the tracker's ttl.
+func (c *ChangeTracker) Observe(snapshot ChangeSnapshot) {
+	if c == nil {
+		return
+	}
+	if c.enabled && c.previous != nil {
+		expiry := c.now().Add(c.ttl)
+		for id, fields := range snapshot {
+			old, ok := c.previous[id]
+			if !ok {
+				continue
+			}
+			for field, value := range fields {
+				if prev, ok := old[field]; ok && prev != value {
+					if c.changed[id] == nil {
+						c.changed[id] = make(map[string]time.Time)
+					}
+					c.changed[id][field] = expiry
+				}
+			}
+		}
+	}
+	c.previous = snapshot
+	c.prune()
+}
+
+// Changed reports whether field of the object id is still highlighted.
+func (c *ChangeTracker) Changed(id, field string) bool {
+	if !c.Enabled() {
+		return false
+	}
+	expiry, ok := c.changed[id][field]
+	return ok && c.now().Before(expiry)
+}
+
+// NextExpiry returns how long until the next highlight expires, so the
+// caller can redraw then. It returns false when nothing is highlighted.
+func (c *ChangeTracker) NextExpiry() (time.Duration, bool) {
+	if !c.Enabled() {
+		return 0, false
+	}
+	c.prune()
+	now := c.now()
+	var next time.Duration
+	found := false
+	for _, fields := range c.changed {
+		for _, expiry := range fields {
+			if d := expiry.Sub(now); !found || d < next {
+				next, found = d, true
+			}
+		}
+	}
+	return next, found
+}
+
+// prune drops expired highlights.
+func (c *ChangeTracker) prune() {
+	now := c.now()
+	for id, fields := range c.changed {
+		for field, expiry := range fields {
+			if !now.Before(expiry) {
+				delete(fields, field)
+			}
+		}
+		if len(fields) == 0 {
+			delete(c.changed, id)
+		}
+	}
+}
+
+// podChangeID identifies a pod across refreshes.
+func podChangeID(p repository.PodInfo) string {
+	return p.Namespace + "/" + p.Name
+}
+
+// workloadChangeID identifies a workload across refreshes.
+func workloadChangeID(w repository.WorkloadInfo) string {
+	return string(w.Type) + "/" + w.Namespace + "/" + w.Name
+}
+
+// eventChangeID identifies a (possibly recurring) event across refreshes.
+func eventChangeID(e repository.EventInfo) string {
+	return e.Type + "/" + e.Reason + "/" + e.Object
+}
+
+// PodSnapshot captures the pod fields shown in the pods table.
+func PodSnapshot(pods []repository.PodInfo) ChangeSnapshot {
+	snapshot := make(ChangeSnapshot, len(pods))
+	for _, p := range pods {
+		snapshot[podChangeID(p)] = map[string]string{
+			FieldStatus:   p.Status,
+			FieldReady:    p.Ready,
+			FieldRestarts: strconv.Itoa(int(p.Restarts)),
+		}
+	}
+	return snapshot
+}
+
+// WorkloadSnapshot captures the workload fields shown in the workloads table.
+func WorkloadSnapshot(workloads []repository.WorkloadInfo) ChangeSnapshot {
+	snapshot := make(ChangeSnapshot, len(workloads))
+	for _, w := range workloads {
+		snapshot[workloadChangeID(w)] = map[string]string{
+			FieldStatus: w.Status,
+			FieldReady:  w.Ready,
+		}
+	}
+	return snapshot
+}
+
+// EventSnapshot captures event counts, so recurring events are highlighted.
+func EventSnapshot(events []repository.EventInfo) ChangeSnapshot {
+	snapshot := make(ChangeSnapshot, len(events))
+	for _, e := range events {
+		snapshot[eventChangeID(e)] = map[string]string{
+			FieldCount: strconv.Itoa(int(e.Count)),
+		}
+	}
+	return snapshot
+}
@@ -7,14 +7,93 @@
 package component
 
 import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/andrebassi/k1s/configs"
 )
 
+// ClipboardOSC52 is the CopyResult method of a copy through the terminal.
+const ClipboardOSC52 = "OSC52"
+
+// Clipboard settings, from SetClipboard
+var (
+	clipboardMode = configs.ClipboardAuto
+	osc52MaxBytes = configs.DefaultOSC52MaxBytes
+	// osc52Output is where the OSC52 sequence is written: the terminal
+	osc52Output io.Writer = os.Stdout
+)
+
+// SetClipboard sets how CopyToClipboard copies: one of the configs
+// Clipboard* modes, and the most bytes sent through OSC52.
+func SetClipboard(mode string, maxBytes int) {
+	clipboardMode = mode
+	if maxBytes > 0 {
+		osc52MaxBytes = maxBytes
+	}
+}
+
+// CopyResult says how text was copied.
+type CopyResult struct {
+	Method    string // Command used (pbcopy, xclip, ...) or ClipboardOSC52
+	Truncated bool   // Only the first Bytes bytes were sent through OSC52
+	Bytes     int
+}
+
+// Via describes the method for a status message, e.g. " via OSC52",
+// warning when the text was truncated.
+func (r CopyResult) Via() string {
+	if r.Truncated {
+		return fmt.Sprintf(" via %s (truncated to %d bytes; raise osc52_max_bytes for more)", r.Method, r.Bytes)
+	}
+	return " via " + r.Method
+}
+
+// Status is the status message of a successful copy.
+func (r CopyResult) Status() string {
+	return "Copied to clipboard" + r.Via()
+}
+
 // CopyToClipboard copies text to the system clipboard.
 // It uses platform-specific commands: pbcopy (macOS), xclip/xsel (Linux), clip (Windows).
-func CopyToClipboard(text string) error {
+// When that fails, as over SSH with no display, the text is sent to the
+// terminal as an OSC52 escape sequence, which most terminals copy to the
+// clipboard of the machine they run on, unless the output is not a
+// terminal. The osc52 mode always does that, the native mode never does.
+func CopyToClipboard(text string) (CopyResult, error) {
+	if clipboardMode == configs.ClipboardOSC52 {
+		return copyOSC52(text)
+	}
+	method, err := copyNative(text)
+	if err == nil {
+		return CopyResult{Method: method, Bytes: len(text)}, nil
+	}
+	if clipboardMode == configs.ClipboardNative || !isTerminal(osc52Output) {
+		return CopyResult{}, err
+	}
+	return copyOSC52(text)
+}
+
+// isTerminal reports whether w is a terminal. Writers that are not files
+// are taken to be one.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return true
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// copyNative copies text with the clipboard command of the platform and
+// returns the command's name.
+func copyNative(text string) (string, error) {
 	var cmd *exec.Cmd
 
 	switch runtime.GOOS {
@@ -35,5 +114,49 @@ func CopyToClipboard(text string) error {
 	}
 
 	cmd.Stdin = strings.NewReader(text)
-	return cmd.Run()
+	return cmd.Args[0], cmd.Run()
+}
+
+// copyOSC52 writes text to the terminal as an OSC52 sequence, cut to
+// osc52MaxBytes since terminals drop longer sequences.
+func copyOSC52(text string) (CopyResult, error) {
+	result := CopyResult{Method: ClipboardOSC52, Bytes: len(text)}
+	if len(text) > osc52MaxBytes {
+		text = truncateUTF8(text, osc52MaxBytes)
+		result.Truncated = true
+		result.Bytes = len(text)
+	}
+	if _, err := io.WriteString(osc52Output, osc52Sequence(text, os.Getenv)); err != nil {
+		return CopyResult{}, fmt.Errorf("OSC52 copy failed: %w", err)
+	}
+	return result, nil
+}
+
+// osc52Sequence is the escape sequence setting the clipboard to text,
+// wrapped so tmux and screen pass it on to the terminal.
+func osc52Sequence(text string, getenv func(string) string) string {
+	return terminalPassthrough("\x1b]52;c;"+base64.StdEncoding.EncodeToString([]byte(text))+"\a", getenv)
+}
+
+// terminalPassthrough wraps an escape sequence so tmux and screen pass it
+// on to the terminal.
+func terminalPassthrough(seq string, getenv func(string) string) string {
+	switch {
+	case getenv("TMUX") != "":
+		return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case getenv("STY") != "":
+		return "\x1bP" + seq + "\x1b\\"
+	}
+	return seq
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
 }
@@ -0,0 +1,272 @@
+package component
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// ClusterSwitcher lists the kubeconfig contexts, favorites first, with the
+// health of each one's cluster as its probe comes back, and switches to
+// the selected one.
+type ClusterSwitcher struct {
+	contexts  []string // Favorites first, then alphabetically
+	current   string   // Context the client is connected to
+	favorites map[string]bool
+	health    map[string]repository.ContextHealth // Probed contexts; missing ones are still probing
+	loaded    bool                                // The kubeconfig has been read
+	cursor    int
+	visible   bool
+	width     int
+	height    int
+}
+
+// ContextSelected is sent when a context is chosen in the cluster switcher
+type ContextSelected struct {
+	Context string
+}
+
+// FavoriteContextToggled is sent when a context is marked or unmarked as a
+// favorite
+type FavoriteContextToggled struct {
+	Context string
+}
+
+// ContextProbeRequest asks app.go to probe the clusters of contexts again
+type ContextProbeRequest struct {
+	Contexts []string
+}
+
+// ClusterSwitcherClosed is sent when the switcher is closed without a
+// choice
+type ClusterSwitcherClosed struct{}
+
+func NewClusterSwitcher() ClusterSwitcher {
+	return ClusterSwitcher{}
+}
+
+func (c ClusterSwitcher) Update(msg tea.Msg) (ClusterSwitcher, tea.Cmd) {
+	if !c.visible {
+		return c, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return c, nil
+	}
+	switch keyMsg.String() {
+	case "esc", "q", "C":
+		c.visible = false
+		return c, func() tea.Msg { return ClusterSwitcherClosed{} }
+	case "up", "k":
+		if c.cursor > 0 {
+			c.cursor--
+		}
+	case "down", "j":
+		if c.cursor < len(c.contexts)-1 {
+			c.cursor++
+		}
+	case "enter":
+		name := c.Selected()
+		if name == "" {
+			return c, nil
+		}
+		c.visible = false
+		return c, func() tea.Msg { return ContextSelected{Context: name} }
+	case "f", "*":
+		name := c.Selected()
+		if name == "" {
+			return c, nil
+		}
+		c.favorites[name] = !c.favorites[name]
+		c.sortContexts(name)
+		return c, func() tea.Msg { return FavoriteContextToggled{Context: name} }
+	case "r":
+		if len(c.contexts) == 0 {
+			return c, nil
+		}
+		c.health = make(map[string]repository.ContextHealth)
+		contexts := append([]string(nil), c.contexts...)
+		return c, func() tea.Msg { return ContextProbeRequest{Contexts: contexts} }
+	}
+	return c, nil
+}
+
+func (c ClusterSwitcher) View() string {
+	if !c.visible {
+		return ""
+	}
+
+	separatorStyle := lipgloss.NewStyle().Foreground(style.TextMuted)
+	itemStyle := lipgloss.NewStyle().Foreground(style.Primary)
+	infoStyle := lipgloss.NewStyle().Foreground(style.Secondary)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Secondary)
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Surface).
+		Padding(0, 1).
+		Width(c.width - 10).
+		Height(c.height - 10)
+	serverWidth := c.width - 90
+	if serverWidth < 20 {
+		serverWidth = 20
+	}
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render(fmt.Sprintf("    %-40s %-16s %-12s %s", "CONTEXT", "STATUS", "VERSION", "SERVER")))
+	content.WriteString("\n")
+	switch {
+	case !c.loaded:
+		content.WriteString(style.StatusMuted.Render("  Reading kubeconfig..."))
+		content.WriteString("\n")
+	case len(c.contexts) == 0:
+		content.WriteString(style.StatusMuted.Render("  No contexts found"))
+		content.WriteString("\n")
+	}
+
+	for i, name := range c.contexts {
+		marker := "  "
+		if c.favorites[name] {
+			marker = "★ "
+		}
+		label := name
+		if name == c.current {
+			label += " (current)"
+		}
+		status, statusStyle := "probing...", style.StatusMuted
+		version, server := "", ""
+		if h, ok := c.health[name]; ok {
+			status, statusStyle = contextStatus(h)
+			version, server = h.Version, h.Server
+		}
+		row := fmt.Sprintf("%s%-40s %s %-12s %s", marker, style.Truncate(label, 40),
+			statusStyle.Render(fmt.Sprintf("%-16s", status)), style.Truncate(version, 12), style.Truncate(server, serverWidth))
+		if i == c.cursor {
+			content.WriteString(style.CursorStyle.Render("> ") + row)
+		} else {
+			content.WriteString("  " + row)
+		}
+		content.WriteString("\n")
+	}
+
+	// The full error of an unreachable cluster says what to fix
+	if h, ok := c.health[c.Selected()]; ok && h.Err != nil {
+		content.WriteString("\n")
+		detail := lipgloss.NewStyle().Width(c.width - 20).Render(h.Err.Error() + "\n" + h.Problem.Hint())
+		content.WriteString(style.StatusMuted.Render(detail))
+		content.WriteString("\n")
+	}
+
+	probing := 0
+	for _, name := range c.contexts {
+		if _, ok := c.health[name]; !ok {
+			probing++
+		}
+	}
+	info := fmt.Sprintf("[%d contexts]", len(c.contexts))
+	if probing > 0 && c.loaded {
+		info = fmt.Sprintf("[%d contexts, probing %d]", len(c.contexts), probing)
+	}
+	breadcrumb := itemStyle.Render("clusters") +
+		separatorStyle.Render(" - ") +
+		infoStyle.Render(info)
+	footer := style.StatusMuted.Render("↑↓:select  Enter:switch  f:favorite  r:probe again  Esc:close")
+
+	return breadcrumb + "\n" + boxStyle.Render(content.String()) + "\n" + footer
+}
+
+// contextStatus describes a probed cluster in a few words, with the style
+// to show them in.
+func contextStatus(h repository.ContextHealth) (string, lipgloss.Style) {
+	if h.Err == nil {
+		return fmt.Sprintf("reachable %s", h.Latency.Round(time.Millisecond)), style.StatusRunning
+	}
+	switch h.Problem {
+	case repository.ConnectionAuth:
+		return "auth error", style.StatusError
+	case repository.ConnectionTimeout:
+		return "timeout", style.StatusError
+	case repository.ConnectionDNS:
+		return "DNS error", style.StatusError
+	case repository.ConnectionTLS:
+		return "TLS error", style.StatusError
+	case repository.ConnectionRefused:
+		return "refused", style.StatusError
+	}
+	return "unreachable", style.StatusError
+}
+
+// Show opens the switcher before the kubeconfig is read, so that it never
+// waits on it; SetContexts fills it in.
+func (c *ClusterSwitcher) Show(current string) {
+	c.current = current
+	c.contexts = nil
+	c.health = make(map[string]repository.ContextHealth)
+	c.loaded = false
+	c.cursor = 0
+	c.visible = true
+}
+
+// SetContexts lists the kubeconfig contexts, with the favorites among them
+// first. The cursor starts on the current context.
+func (c *ClusterSwitcher) SetContexts(contexts, favorites []string) {
+	c.contexts = append([]string(nil), contexts...)
+	c.favorites = make(map[string]bool, len(favorites))
+	for _, f := range favorites {
+		c.favorites[f] = true
+	}
+	c.loaded = true
+	c.sortContexts(c.current)
+}
+
+// sortContexts orders the contexts favorites first and puts the cursor on
+// name.
+func (c *ClusterSwitcher) sortContexts(name string) {
+	sort.SliceStable(c.contexts, func(i, j int) bool {
+		fi, fj := c.favorites[c.contexts[i]], c.favorites[c.contexts[j]]
+		if fi != fj {
+			return fi
+		}
+		return c.contexts[i] < c.contexts[j]
+	})
+	c.cursor = 0
+	for i, ctx := range c.contexts {
+		if ctx == name {
+			c.cursor = i
+		}
+	}
+}
+
+// SetHealth records the probe of a context's cluster.
+func (c *ClusterSwitcher) SetHealth(h repository.ContextHealth) {
+	if c.health != nil {
+		c.health[h.Context] = h
+	}
+}
+
+// Selected returns the context under the cursor, "" when none.
+func (c ClusterSwitcher) Selected() string {
+	if c.cursor >= 0 && c.cursor < len(c.contexts) {
+		return c.contexts[c.cursor]
+	}
+	return ""
+}
+
+func (c *ClusterSwitcher) Hide() {
+	c.visible = false
+}
+
+func (c ClusterSwitcher) IsVisible() bool {
+	return c.visible
+}
+
+func (c *ClusterSwitcher) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
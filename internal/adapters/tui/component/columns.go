@@ -0,0 +1,402 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// Optional columns of the workloads table, as named in the config file
+const (
+	ColumnRestarts = "restarts"
+	ColumnAge      = "age"
+	ColumnPods     = "pods"
+	ColumnNode     = "node"
+	ColumnImages   = "images"
+	ColumnLabel    = "label" // Configured as "label:<key>"
+)
+
+// optionalColumns are the optional columns in the order they are shown.
+var optionalColumns = []struct {
+	id    string
+	title string
+	width int
+}{
+	{ColumnRestarts, "RESTARTS", 8},
+	{ColumnAge, "AGE", 8},
+	{ColumnPods, "PODS", 36},
+	{ColumnNode, "NODE", 24},
+	{ColumnImages, "IMAGES", 32},
+	{ColumnLabel, "LABEL", 20},
+}
+
+// ColumnSet is the optional columns shown in the workloads table.
+type ColumnSet struct {
+	enabled  map[string]bool
+	labelKey string // Label whose value the label column shows
+}
+
+// DefaultColumns is the columns shown when none are configured.
+func DefaultColumns() ColumnSet {
+	return ColumnSet{enabled: map[string]bool{ColumnAge: true, ColumnPods: true}}
+}
+
+// ParseColumns reads the workload_columns setting. Nil is DefaultColumns;
+// an unknown column is an error.
+func ParseColumns(names []string) (ColumnSet, error) {
+	if names == nil {
+		return DefaultColumns(), nil
+	}
+	c := ColumnSet{enabled: make(map[string]bool)}
+	for _, name := range names {
+		id, key, hasKey := strings.Cut(strings.ToLower(strings.TrimSpace(name)), ":")
+		if id == ColumnLabel {
+			if !hasKey || key == "" {
+				return DefaultColumns(), fmt.Errorf("column %q needs a label key, as in label:app", name)
+			}
+			c.labelKey = strings.TrimSpace(name)[len(ColumnLabel)+1:]
+		} else if hasKey || !isOptionalColumn(id) {
+			return DefaultColumns(), fmt.Errorf("unknown column %q (want restarts, age, pods, node, images or label:<key>)", name)
+		}
+		c.enabled[id] = true
+	}
+	return c, nil
+}
+
+func isOptionalColumn(id string) bool {
+	for _, col := range optionalColumns {
+		if col.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Names is the set as the workload_columns setting.
+func (c ColumnSet) Names() []string {
+	names := []string{}
+	for _, col := range optionalColumns {
+		switch {
+		case !c.enabled[col.id]:
+		case col.id == ColumnLabel:
+			names = append(names, ColumnLabel+":"+c.labelKey)
+		default:
+			names = append(names, col.id)
+		}
+	}
+	return names
+}
+
+// Enabled reports whether a column is shown.
+func (c ColumnSet) Enabled(id string) bool {
+	return c.enabled[id]
+}
+
+// LabelKey is the label the label column shows, "" when none was chosen.
+func (c ColumnSet) LabelKey() string {
+	return c.labelKey
+}
+
+// Toggle shows or hides a column. The label column needs a key first.
+func (c ColumnSet) Toggle(id string) ColumnSet {
+	c = c.clone()
+	if id == ColumnLabel && c.labelKey == "" {
+		return c
+	}
+	c.enabled[id] = !c.enabled[id]
+	return c
+}
+
+// WithLabel shows the label column for key.
+func (c ColumnSet) WithLabel(key string) ColumnSet {
+	c = c.clone()
+	c.labelKey = key
+	c.enabled[ColumnLabel] = true
+	return c
+}
+
+func (c ColumnSet) clone() ColumnSet {
+	enabled := make(map[string]bool, len(c.enabled))
+	for id, on := range c.enabled {
+		enabled[id] = on
+	}
+	return ColumnSet{enabled: enabled, labelKey: c.labelKey}
+}
+
+// labelTitle is the header of the label column: the key without its
+// prefix, as kubectl get -L shows it.
+func (c ColumnSet) labelTitle() string {
+	key := c.labelKey
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		key = key[i+1:]
+	}
+	return strings.ToUpper(key)
+}
+
+// ColumnPicker toggles the optional columns of the workloads table.
+type ColumnPicker struct {
+	columns ColumnSet
+	cursor  int
+	visible bool
+}
+
+// ColumnsChanged is sent when a column is toggled in the picker.
+type ColumnsChanged struct {
+	Columns ColumnSet
+}
+
+// ColumnLabelKeyRequest asks app.go for the key of the label column.
+type ColumnLabelKeyRequest struct {
+	Current string
+}
+
+func NewColumnPicker() ColumnPicker {
+	return ColumnPicker{}
+}
+
+func (p ColumnPicker) Update(msg tea.Msg) (ColumnPicker, tea.Cmd) {
+	if !p.visible {
+		return p, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	switch keyMsg.String() {
+	case "esc", "q", "o":
+		p.visible = false
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(optionalColumns)-1 {
+			p.cursor++
+		}
+	case "e":
+		if optionalColumns[p.cursor].id == ColumnLabel {
+			req := ColumnLabelKeyRequest{Current: p.columns.labelKey}
+			return p, func() tea.Msg { return req }
+		}
+	case " ", "enter":
+		id := optionalColumns[p.cursor].id
+		if id == ColumnLabel && p.columns.labelKey == "" {
+			req := ColumnLabelKeyRequest{}
+			return p, func() tea.Msg { return req }
+		}
+		p.columns = p.columns.Toggle(id)
+		changed := ColumnsChanged{Columns: p.columns}
+		return p, func() tea.Msg { return changed }
+	}
+	return p, nil
+}
+
+func (p ColumnPicker) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	var b strings.Builder
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Primary)
+	b.WriteString(titleStyle.Render("Workload columns"))
+	b.WriteString("\n\n")
+	b.WriteString(style.StatusMuted.Render("  [x] NAME  [x] READY  [x] STATUS"))
+	b.WriteString("\n")
+	for i, col := range optionalColumns {
+		check := "[ ]"
+		if p.columns.enabled[col.id] {
+			check = "[x]"
+		}
+		title := col.title
+		if col.id == ColumnLabel {
+			if p.columns.labelKey != "" {
+				title += " " + p.columns.labelKey
+			} else {
+				title += " (choose a key)"
+			}
+		}
+		row := check + " " + title
+		if i == p.cursor {
+			b.WriteString(style.CursorStyle.Render("> " + row))
+		} else {
+			b.WriteString("  " + row)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(style.StatusMuted.Render("Space:toggle  e:label key  Esc:close"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Primary).
+		Padding(1, 2)
+	return boxStyle.Render(b.String())
+}
+
+// Show opens the picker on the current columns.
+func (p *ColumnPicker) Show(columns ColumnSet) {
+	p.columns = columns
+	p.cursor = 0
+	p.visible = true
+}
+
+// SetColumns updates the columns shown as checked, e.g. once a label key
+// was chosen.
+func (p *ColumnPicker) SetColumns(columns ColumnSet) {
+	p.columns = columns
+}
+
+func (p *ColumnPicker) Hide() {
+	p.visible = false
+}
+
+func (p ColumnPicker) IsVisible() bool {
+	return p.visible
+}
+
+// workloadColumn is a column of the workloads table after NAME.
+type workloadColumn struct {
+	id    string
+	title string
+	width int
+}
+
+// workloadLayout is the part of the workloads table that fits the panel:
+// NAME, then the columns from the scroll offset that fit after it.
+type workloadLayout struct {
+	nameWidth   int
+	columns     []workloadColumn
+	hiddenLeft  bool // Columns scrolled out on the left
+	hiddenRight bool // Columns that didn't fit on the right
+}
+
+// workloadColumns is the columns after NAME in the order shown.
+func (c ColumnSet) workloadColumns() []workloadColumn {
+	cols := []workloadColumn{{"ready", "READY", 10}, {"status", "STATUS", 15}}
+	for _, col := range optionalColumns {
+		if !c.enabled[col.id] {
+			continue
+		}
+		title := col.title
+		if col.id == ColumnLabel {
+			title = c.labelTitle()
+		}
+		cols = append(cols, workloadColumn{col.id, title, col.width})
+	}
+	return cols
+}
+
+// layoutWorkloadColumns fits the columns from offset into width. NAME
+// shrinks on narrow terminals; a column that only partly fits is clipped
+// when at least a few characters of it can be shown. A width of 0 fits
+// everything.
+func layoutWorkloadColumns(columns []workloadColumn, offset, width int) workloadLayout {
+	const (
+		cursorWidth = 2
+		markerWidth = 3 // " ◂▸" after the header
+		minName     = 12
+		minClipped  = 5
+	)
+	if offset > len(columns)-1 {
+		offset = len(columns) - 1
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	l := workloadLayout{nameWidth: 32, hiddenLeft: offset > 0}
+	if width <= 0 {
+		l.columns = columns[offset:]
+		return l
+	}
+
+	avail := width - cursorWidth - markerWidth
+	if first := columns[offset].width; avail < l.nameWidth+1+first {
+		l.nameWidth = avail - 1 - first
+		if l.nameWidth < minName {
+			l.nameWidth = minName
+		}
+	}
+	avail -= l.nameWidth
+	for i, col := range columns[offset:] {
+		if avail >= col.width+1 {
+			l.columns = append(l.columns, col)
+			avail -= col.width + 1
+			continue
+		}
+		if avail-1 >= minClipped {
+			col.width = avail - 1
+			l.columns = append(l.columns, col)
+		}
+		l.hiddenRight = true
+		if i == 0 && len(l.columns) == 0 {
+			// Always show one column, however narrow the panel
+			col.width = minClipped
+			l.columns = append(l.columns, col)
+		}
+		break
+	}
+	return l
+}
+
+// header renders the header row with markers for scrolled out columns.
+func (l workloadLayout) header() string {
+	var b strings.Builder
+	b.WriteString("  ")
+	b.WriteString(fitCell("NAME", l.nameWidth))
+	for _, col := range l.columns {
+		b.WriteString(" ")
+		b.WriteString(fitCell(col.title, col.width))
+	}
+	if l.hiddenLeft || l.hiddenRight {
+		b.WriteString(" ")
+		if l.hiddenLeft {
+			b.WriteString("◂")
+		}
+		if l.hiddenRight {
+			b.WriteString("▸")
+		}
+	}
+	return b.String()
+}
+
+// fitCell truncates s to width runes, marking the cut with "...", and pads
+// it to width.
+func fitCell(s string, width int) string {
+	r := []rune(s)
+	if len(r) > width {
+		if width <= 3 {
+			return string(r[:width])
+		}
+		return string(r[:width-3]) + "..."
+	}
+	return s + strings.Repeat(" ", width-len(r))
+}
+
+// nodesCell shows the first node of a workload and how many more run its
+// pods, e.g. "node-a +2".
+func nodesCell(nodes []string) string {
+	switch len(nodes) {
+	case 0:
+		return "-"
+	case 1:
+		return nodes[0]
+	}
+	return fmt.Sprintf("%s +%d", nodes[0], len(nodes)-1)
+}
+
+// imagesCell shows images without their registry and repository path,
+// e.g. "nginx:1.25,envoy:v1.29".
+func imagesCell(images []string) string {
+	if len(images) == 0 {
+		return "-"
+	}
+	short := make([]string, len(images))
+	for i, image := range images {
+		short[i] = image[strings.LastIndex(image, "/")+1:]
+	}
+	return strings.Join(short, ",")
+}
@@ -1,11 +1,24 @@
 package component
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/andrebassi/k1s/configs"
 	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // ============================================
@@ -221,6 +234,117 @@ func TestConfirmDialog_Update_Enter(t *testing.T) {
 	}
 }
 
+func TestConfirmDialog_Typed(t *testing.T) {
+	cd := NewConfirmDialog()
+	cd.ShowTyped("Delete Pod", "Really?", "delete", "web-1", nil)
+
+	// y is text here, not a shortcut
+	cd, cmd := cd.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if cmd != nil || !cd.IsVisible() {
+		t.Fatal("'y' should not confirm a typed confirmation")
+	}
+
+	// Wrong text does not confirm
+	cd, cmd = cd.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || !cd.IsVisible() {
+		t.Fatal("Enter with wrong text should not confirm")
+	}
+
+	cd, _ = cd.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	cd, _ = cd.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("web-1")})
+	if !strings.Contains(stripAnsiCodes(cd.View()), "web-1") {
+		t.Error("typed dialog should show the expected text")
+	}
+	cd, cmd = cd.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Enter with matching text should confirm")
+	}
+	if result := cmd().(ConfirmResult); !result.Confirmed || result.Action != "delete" {
+		t.Errorf("result = %+v, want confirmed delete", result)
+	}
+	if cd.IsVisible() {
+		t.Error("dialog should close after confirming")
+	}
+
+	// Esc cancels
+	cd.ShowTyped("Delete Pod", "Really?", "delete", "web-1", nil)
+	_, cmd = cd.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if result := cmd().(ConfirmResult); result.Confirmed {
+		t.Error("Esc should cancel a typed confirmation")
+	}
+}
+
+func TestConfirmDialog_Request(t *testing.T) {
+	tests := []struct {
+		level       configs.ConfirmLevel
+		target      string
+		wantVisible bool
+		wantTyped   bool
+	}{
+		{configs.ConfirmNone, "web-1", false, false},
+		{configs.ConfirmYesNo, "web-1", true, false},
+		{configs.ConfirmTyped, "web-1", true, true},
+		{configs.ConfirmTyped, "", true, false}, // Nothing to type falls back to yes/no
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.level)+"/"+tt.target, func(t *testing.T) {
+			cd := NewConfirmDialog()
+			cmd := cd.Request(tt.level, "Title", "Message", "delete", tt.target, "data")
+
+			if cd.IsVisible() != tt.wantVisible {
+				t.Errorf("IsVisible() = %v, want %v", cd.IsVisible(), tt.wantVisible)
+			}
+			if (cd.expected != "") != tt.wantTyped {
+				t.Errorf("typed = %v, want %v", cd.expected != "", tt.wantTyped)
+			}
+			if tt.wantVisible && cmd != nil {
+				t.Error("Request() should not return a command when showing the dialog")
+			}
+		})
+	}
+}
+
+func TestConfirmDialog_RequestNoneSkips(t *testing.T) {
+	cd := NewConfirmDialog()
+	cmd := cd.Request(configs.ConfirmNone, "Delete Pod", "Really?", "delete", "web-1", "payload")
+	if cmd == nil {
+		t.Fatal("ConfirmNone should return a command that confirms immediately")
+	}
+
+	result, ok := cmd().(ConfirmResult)
+	if !ok {
+		t.Fatalf("command returned %T, want ConfirmResult", cmd())
+	}
+	if !result.Confirmed || !result.Skipped {
+		t.Errorf("result = %+v, want Confirmed and Skipped", result)
+	}
+	if result.Action != "delete" || result.Data != "payload" {
+		t.Errorf("result = %+v, want action and data passed through", result)
+	}
+}
+
+func TestConfirmDialog_RequestReadOnly(t *testing.T) {
+	cd := NewConfirmDialog()
+	cd.SetReadOnly(true)
+	for _, level := range []configs.ConfirmLevel{configs.ConfirmNone, configs.ConfirmYesNo, configs.ConfirmTyped} {
+		cmd := cd.Request(level, "Delete Pod", "Really?", "delete", "web-1", "payload")
+		if cd.IsVisible() {
+			t.Errorf("%s: dialog should not be shown in read-only mode", level)
+		}
+		if cmd == nil {
+			t.Fatalf("%s: Request() should return a command", level)
+		}
+		msg, ok := cmd().(ReadOnlyMsg)
+		if !ok {
+			t.Fatalf("%s: command returned %T, want ReadOnlyMsg", level, cmd())
+		}
+		if msg.Status() != "Read-only mode: Delete Pod is disabled" {
+			t.Errorf("Status() = %q", msg.Status())
+		}
+	}
+}
+
 // ============================================
 // HelpPanel Tests
 // ============================================
@@ -391,6 +515,20 @@ func TestBreadcrumb_View_SingleItem(t *testing.T) {
 	_ = strings.Contains(view, ">")
 }
 
+func TestBreadcrumb_Badge(t *testing.T) {
+	b := NewBreadcrumb()
+	b.SetItems("kube-system", "pods")
+	b.SetBadge("protected")
+	if !strings.Contains(b.View(), "protected") {
+		t.Error("breadcrumb should show its badge")
+	}
+
+	b.SetBadge("")
+	if strings.Contains(b.View(), "protected") {
+		t.Error("clearing the badge should hide it")
+	}
+}
+
 // ============================================
 // HelpEntry struct test
 // ============================================
@@ -480,6 +618,106 @@ func TestEventsPanel_SetEvents(t *testing.T) {
 	}
 }
 
+func TestEventsPanel_SetEvents_KeepsSelection(t *testing.T) {
+	ep := NewEventsPanel()
+	ep.SetSize(100, 50)
+	ep.SetEvents([]repository.EventInfo{
+		{Type: "Warning", Reason: "BackOff", Object: "Pod/web"},
+		{Type: "Warning", Reason: "Unhealthy", Object: "Pod/web"},
+		{Type: "Warning", Reason: "FailedMount", Object: "Pod/web"},
+	})
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+
+	// A recurring event moves to the top, pushing the selection down
+	ep.SetEvents([]repository.EventInfo{
+		{Type: "Warning", Reason: "FailedMount", Object: "Pod/web", Count: 2},
+		{Type: "Warning", Reason: "BackOff", Object: "Pod/web"},
+		{Type: "Warning", Reason: "Unhealthy", Object: "Pod/web"},
+	})
+	if e := ep.SelectedEvent(); e == nil || e.Reason != "Unhealthy" {
+		t.Errorf("SelectedEvent() = %v, want Unhealthy to stay selected", e)
+	}
+}
+
+func TestEventsPanel_AddEvents(t *testing.T) {
+	now := time.Now()
+	ep := NewEventsPanel()
+	ep.SetSize(100, 50)
+	ep.SetEvents([]repository.EventInfo{
+		{Name: "web.2", Type: "Warning", Reason: "BackOff", Object: "Pod/web", LastSeen: now.Add(-time.Minute)},
+		{Name: "web.1", Type: "Warning", Reason: "Unhealthy", Object: "Pod/web", LastSeen: now.Add(-2 * time.Minute)},
+	})
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+
+	// A new event and an update of a listed one
+	ep.AddEvents([]repository.EventInfo{
+		{Name: "web.3", Type: "Warning", Reason: "FailedMount", Object: "Pod/web", LastSeen: now},
+		{Name: "web.2", Type: "Warning", Reason: "BackOff", Object: "Pod/web", Count: 4, LastSeen: now.Add(-30 * time.Second)},
+	})
+	if ep.EventCount() != 3 {
+		t.Errorf("EventCount() = %d, want 3 (the update replaces its row)", ep.EventCount())
+	}
+	if ep.WarningCount() != 3 {
+		t.Errorf("WarningCount() = %d, want 3", ep.WarningCount())
+	}
+	if !strings.Contains(ep.View(), "[3 warnings]") {
+		t.Error("View() should show the updated warning count")
+	}
+	if e := ep.SelectedEvent(); e == nil || e.Reason != "Unhealthy" {
+		t.Errorf("SelectedEvent() = %v, want Unhealthy to stay selected", e)
+	}
+}
+
+func TestEventsPanel_AddEvents_FollowsNewest(t *testing.T) {
+	now := time.Now()
+	ep := NewEventsPanel()
+	ep.SetSize(100, 50)
+	ep.SetEvents([]repository.EventInfo{
+		{Name: "web.1", Type: "Warning", Reason: "BackOff", Object: "Pod/web", LastSeen: now.Add(-time.Minute)},
+	})
+
+	ep.AddEvents([]repository.EventInfo{
+		{Name: "web.2", Type: "Warning", Reason: "Unhealthy", Object: "Pod/web", LastSeen: now},
+	})
+	if e := ep.SelectedEvent(); e == nil || e.Reason != "Unhealthy" {
+		t.Errorf("SelectedEvent() = %v, want the newest event selected", e)
+	}
+}
+
+func TestEventsPanel_SetWarningsOnly(t *testing.T) {
+	ep := NewEventsPanel()
+	ep.SetSize(100, 50)
+	ep.SetEvents([]repository.EventInfo{
+		{Type: "Warning", Reason: "BackOff"},
+		{Type: "Normal", Reason: "Pulled"},
+	})
+	if !ep.WarningsOnly() {
+		t.Error("WarningsOnly() should default to true")
+	}
+
+	ep.SetWarningsOnly(false)
+	if ep.WarningsOnly() || !strings.Contains(ep.View(), "Pulled") {
+		t.Error("SetWarningsOnly(false) should show normal events")
+	}
+}
+
+func TestParseTimeFilter(t *testing.T) {
+	for _, f := range []TimeFilter{TimeFilterAll, TimeFilter5Min, TimeFilter15Min, TimeFilter1Hour, TimeFilter6Hours} {
+		if got := ParseTimeFilter(f.String()); got != f {
+			t.Errorf("ParseTimeFilter(%q) = %v, want %v", f.String(), got, f)
+		}
+	}
+	if got := ParseTimeFilter("2d"); got != TimeFilterAll {
+		t.Errorf("ParseTimeFilter(\"2d\") = %v, want TimeFilterAll", got)
+	}
+
+	lp := NewLogsPanel()
+	lp.SetTimeFilter(TimeFilter1Hour)
+	if lp.TimeFilter() != TimeFilter1Hour {
+		t.Errorf("TimeFilter() = %v, want TimeFilter1Hour", lp.TimeFilter())
+	}
+}
+
 func TestEventsPanel_View_NotReady(t *testing.T) {
 	ep := NewEventsPanel()
 	view := ep.View()
@@ -592,6 +830,107 @@ func TestEventsPanel_ClearSearch(t *testing.T) {
 	}
 }
 
+func TestEventsPanel_Grouped(t *testing.T) {
+	ep := NewEventsPanel()
+	ep.SetSize(120, 50)
+	ep.SetWarningsOnly(false)
+
+	now := time.Now()
+	ep.SetEvents([]repository.EventInfo{
+		{Name: "p1", Type: "Normal", Reason: "Pulled", Object: "Pod/web", Count: 9, LastSeen: now, Message: "pulled"},
+		{Name: "b1", Type: "Warning", Reason: "BackOff", Object: "Pod/web", Count: 2, LastSeen: now.Add(-time.Minute), Message: "back-off app"},
+		{Name: "b2", Type: "Warning", Reason: "BackOff", Object: "Pod/web", Count: 3, LastSeen: now.Add(-2 * time.Minute), Message: "back-off sidecar"},
+		{Name: "u1", Type: "Warning", Reason: "Unhealthy", Object: "Pod/web", Count: 1, LastSeen: now, Message: "probe failed"},
+	})
+
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if !ep.Grouped() {
+		t.Fatal("u should turn grouping on")
+	}
+	if n := ep.rowCount(); n != 3 {
+		t.Fatalf("grouped rows = %d, want 3", n)
+	}
+	if ev := ep.SelectedEvent(); ev == nil || ev.Name != "b1" {
+		t.Fatalf("SelectedEvent on the BackOff group = %v, want its latest event b1", ev)
+	}
+	if !strings.Contains(ep.viewport.View(), "x5") {
+		t.Errorf("group row should show the combined count x5:\n%s", ep.viewport.View())
+	}
+
+	// Enter expands the group into its occurrences
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if n := ep.rowCount(); n != 5 {
+		t.Fatalf("rows after expanding = %d, want 5", n)
+	}
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if ev := ep.SelectedEvent(); ev == nil || ev.Name != "b2" {
+		t.Fatalf("SelectedEvent on the second occurrence = %v, want b2", ev)
+	}
+
+	// Enter on an occurrence collapses its group and selects it
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if ep.rowCount() != 3 || ep.cursor != 0 {
+		t.Errorf("after collapsing rows = %d cursor = %d, want 3 and 0", ep.rowCount(), ep.cursor)
+	}
+
+	// Warnings-only and search apply before grouping
+	ep.SetWarningsOnly(true)
+	if n := ep.rowCount(); n != 2 {
+		t.Errorf("warnings-only grouped rows = %d, want 2", n)
+	}
+	ep.filter = "sidecar"
+	ep.updateContent()
+	groups := repository.GroupEvents(ep.getDisplayedEvents())
+	if len(groups) != 1 || groups[0].Count != 3 {
+		t.Errorf("filtered groups = %+v, want the BackOff group with count 3", groups)
+	}
+
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if ep.Grouped() {
+		t.Error("u should turn grouping off")
+	}
+}
+
+func TestEventsPanel_LiveAgesAndSort(t *testing.T) {
+	ep := NewEventsPanel()
+	ep.SetSize(160, 50)
+	ep.SetWarningsOnly(false)
+
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	ep.clock = func() time.Time { return now }
+	ep.SetEvents([]repository.EventInfo{
+		{Name: "s1", Type: "Normal", Reason: "Scheduled", Object: "Pod/web-1", Source: "default-scheduler", Count: 1, LastSeen: now.Add(-59 * time.Second), Message: "assigned"},
+		{Name: "b1", Type: "Warning", Reason: "BackOff", Object: "Pod/web-1", Source: "kubelet", Host: "node-1", Count: 12, LastSeen: now.Add(-61 * time.Second), Message: "back-off"},
+	})
+
+	view := ep.viewport.View()
+	for _, want := range []string{"59s ago", "1m ago", "Pod/web-1", "kubelet/node-1", "default-scheduler"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Ages count up on the tick without new events
+	now = now.Add(25 * time.Hour)
+	ep.RefreshAges()
+	if view := ep.viewport.View(); !strings.Contains(view, "1d ago") || strings.Contains(view, "59s ago") {
+		t.Errorf("ages should be recomputed on refresh:\n%s", view)
+	}
+
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if !ep.SortByCount() {
+		t.Fatal("s should sort by count")
+	}
+	if ev := ep.SelectedEvent(); ev == nil || ev.Name != "b1" {
+		t.Errorf("first event by count = %v, want b1", ev)
+	}
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if ev := ep.SelectedEvent(); ev == nil || ev.Name != "s1" {
+		t.Errorf("first event by last seen = %v, want s1", ev)
+	}
+}
+
 func TestEventsPanel_SelectedEvent(t *testing.T) {
 	ep := NewEventsPanel()
 	ep.SetSize(100, 50)
@@ -617,6 +956,188 @@ func TestEventsPanel_SelectedEvent(t *testing.T) {
 	}
 }
 
+// exportFixture covers the CSV edge cases: commas, quotes, newlines, a
+// non-UTC timestamp and an event without kind or times.
+func exportFixture() []repository.EventInfo {
+	t1 := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	t2 := time.Date(2024, 3, 1, 10, 5, 30, 0, time.FixedZone("BRT", -3*3600))
+	return []repository.EventInfo{
+		{Type: "Warning", Reason: "BackOff", Object: "Pod/web-7d9f", Count: 12, FirstSeen: t1, LastSeen: t2, Message: "Back-off restarting failed container app in pod web-7d9f_default(1234)"},
+		{Type: "Warning", Reason: "Failed", Object: "Pod/web-7d9f", Count: 3, FirstSeen: t1, LastSeen: t1, Message: `Failed to pull image "registry.example.com/app:v1, v2": rpc error: code = NotFound`},
+		{Type: "Normal", Reason: "ScalingReplicaSet", Object: "Deployment/web", Count: 1, FirstSeen: t1, LastSeen: t1, Message: "Scaled up replica set web-7d9f to 3\nfrom 1"},
+		{Type: "Normal", Reason: "Unknown", Object: "orphan"},
+	}
+}
+
+func TestWriteEventsCSV_Golden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteEventsCSV(&buf, exportFixture()); err != nil {
+		t.Fatalf("WriteEventsCSV failed: %v", err)
+	}
+
+	want, err := os.ReadFile(filepath.Join("testdata", "events_export.golden"))
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Errorf("CSV output mismatch\ngot:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestExportEventsCSV(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	path, err := ExportEventsCSV(dir, exportFixture(), now)
+	if err != nil {
+		t.Fatalf("ExportEventsCSV failed: %v", err)
+	}
+	if filepath.Base(path) != "k1s-events-20240301-100000.csv" {
+		t.Errorf("unexpected export file name %q", filepath.Base(path))
+	}
+	if !filepath.IsAbs(path) {
+		t.Errorf("export path %q should be absolute", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "type,reason,objectKind,objectName,count,firstSeen,lastSeen,message\n") {
+		t.Errorf("export should start with the header row, got %q", data)
+	}
+}
+
+func TestExportEventsCSV_MissingDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "missing")
+	if _, err := ExportEventsCSV(dir, exportFixture(), time.Now()); err == nil {
+		t.Error("ExportEventsCSV should fail when the directory does not exist")
+	}
+}
+
+func TestEventsPanel_ExportKey(t *testing.T) {
+	ep := NewEventsPanel()
+	ep.SetSize(100, 50)
+	ep.SetExportDir(t.TempDir())
+	ep.SetEvents(exportFixture())
+
+	// Only the displayed events are exported: warnings, by default
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if !strings.HasPrefix(ep.copyStatus, "Exported 2 events to ") {
+		t.Errorf("unexpected status after export: %q", ep.copyStatus)
+	}
+}
+
+func TestLogsPanel_SaveKey(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetSize(100, 50)
+	lp.SetContainers([]string{"api", "sidecar"})
+	lp.SetLogs([]repository.LogLine{
+		{Container: "api", Content: "GET /health 200"},
+		{Container: "api", Content: "GET /orders 500", IsError: true},
+		{Container: "sidecar", Content: "proxy ready"},
+		{Container: "api", Content: "GET /orders 200"},
+	})
+	lp.SetFilter("orders")
+
+	_, cmd := lp.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatal("ctrl+s should request a save")
+	}
+	req, ok := cmd().(SavePanelRequest)
+	if !ok {
+		t.Fatalf("ctrl+s sent %T, want SavePanelRequest", cmd())
+	}
+	if req.Panel != "logs" || req.Filter != "orders" || req.Lines != 2 {
+		t.Errorf("request = %+v, want 2 logs lines filtered by orders", req)
+	}
+
+	export := PanelExport{
+		SavePanelRequest: req,
+		Context:          "prod-cluster",
+		Namespace:        "shop",
+		Pod:              "api-7d9f",
+		Time:             time.Date(2024, 5, 16, 10, 42, 0, 0, time.UTC),
+	}
+	if got := export.FileName(); got != "shop_api-7d9f_all_logs_20240516-104200.txt" {
+		t.Errorf("FileName() = %q", got)
+	}
+
+	path := filepath.Join(t.TempDir(), export.FileName())
+	n, err := WritePanelExport(path, export, false)
+	if err != nil {
+		t.Fatalf("WritePanelExport failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(data) {
+		t.Errorf("reported %d bytes, file has %d", n, len(data))
+	}
+	want := "# Context:   prod-cluster\n" +
+		"# Namespace: shop\n" +
+		"# Pod:       api-7d9f\n" +
+		"# Container: (all)\n" +
+		"# Filter:    orders\n" +
+		"# Exported:  2024-05-16T10:42:00Z\n" +
+		"\n" +
+		"[api] GET /orders 500\n" +
+		"[api] GET /orders 200\n"
+	if string(data) != want {
+		t.Errorf("file content mismatch\ngot:\n%s\nwant:\n%s", data, want)
+	}
+	// The body is the filtered view, exactly
+	if body := strings.SplitN(string(data), "\n\n", 2)[1]; body != lp.getPlainTextLogs() {
+		t.Errorf("body %q does not match the displayed logs %q", body, lp.getPlainTextLogs())
+	}
+}
+
+func TestWritePanelExport_RefusesOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.txt")
+	if err := os.WriteFile(path, []byte("keep me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	export := PanelExport{SavePanelRequest: SavePanelRequest{Panel: "events", Content: "Warning BackOff\n"}}
+
+	if _, err := WritePanelExport(path, export, false); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("WritePanelExport() error = %v, want os.ErrExist", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "keep me" {
+		t.Errorf("existing file was changed to %q", data)
+	}
+
+	if _, err := WritePanelExport(path, export, true); err != nil {
+		t.Fatalf("WritePanelExport() with overwrite failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !strings.HasSuffix(string(data), "\nWarning BackOff\n") {
+		t.Errorf("file not overwritten: %q", data)
+	}
+}
+
+func TestEventsPanel_SaveKey(t *testing.T) {
+	ep := NewEventsPanel()
+	ep.SetSize(100, 50)
+	ep.SetEvents(exportFixture())
+
+	_, cmd := ep.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatal("ctrl+s should request a save")
+	}
+	req, ok := cmd().(SavePanelRequest)
+	if !ok {
+		t.Fatalf("ctrl+s sent %T, want SavePanelRequest", cmd())
+	}
+	// Only the displayed events are saved: warnings, by default
+	if req.Panel != "events" || req.Lines != 2 || req.Content != ep.getPlainTextEvents() {
+		t.Errorf("request = %+v, want the 2 displayed events", req)
+	}
+	export := PanelExport{SavePanelRequest: req, Namespace: "shop", Pod: "api-7d9f", Time: time.Date(2024, 5, 16, 10, 42, 0, 0, time.UTC)}
+	if got := export.FileName(); got != "shop_api-7d9f_events_20240516-104200.txt" {
+		t.Errorf("FileName() = %q", got)
+	}
+}
+
 func TestEventsPanel_GetDisplayedEvents_FilterByType(t *testing.T) {
 	ep := NewEventsPanel()
 	ep.SetSize(100, 50)
@@ -875,6 +1396,127 @@ func TestMetricsPanel_SetSize(t *testing.T) {
 	}
 }
 
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []float64
+		width   int
+		ceiling float64
+		want    string
+	}{
+		{"too few samples", []float64{5}, 10, 0, ""},
+		{"scaled to max", []float64{0, 50, 100}, 10, 0, "▁▅█"},
+		{"scaled to ceiling", []float64{0, 50, 100}, 10, 200, "▁▃▅"},
+		{"value above ceiling", []float64{100, 300}, 10, 200, "▃█"},
+		{"keeps the newest", []float64{0, 0, 0, 100}, 2, 0, "▁█"},
+		{"all zero", []float64{0, 0}, 10, 0, "▁▁"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.width, tt.ceiling); got != tt.want {
+				t.Errorf("Sparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricsHistory(t *testing.T) {
+	var h metricsHistory
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	sample := func(pod string, i int, mem int64) *repository.PodMetrics {
+		return &repository.PodMetrics{
+			Name: pod, Namespace: "default", Timestamp: start.Add(time.Duration(i) * 15 * time.Second),
+			Containers: []repository.ContainerMetrics{{Name: "main", CPUMilli: 100, MemoryBytes: mem}},
+		}
+	}
+
+	for i := 0; i < metricsHistorySize+10; i++ {
+		h.add(sample("web", i, int64(i)))
+	}
+	mem := h.memory("main")
+	if len(mem) != metricsHistorySize {
+		t.Fatalf("window has %d samples, want %d", len(mem), metricsHistorySize)
+	}
+	if mem[0] != 10 || mem[len(mem)-1] != float64(metricsHistorySize+9) {
+		t.Errorf("window should keep the newest samples, got %v..%v", mem[0], mem[len(mem)-1])
+	}
+
+	// The same reading on the next tick is not a new sample
+	h.add(sample("web", metricsHistorySize+9, 0))
+	if len(h.memory("main")) != metricsHistorySize || h.memory("main")[metricsHistorySize-1] == 0 {
+		t.Error("a repeated metrics-server reading should be skipped")
+	}
+
+	// Another pod evicts the window
+	h.add(sample("api", 0, 42))
+	if got := h.memory("main"); len(got) != 1 || got[0] != 42 {
+		t.Errorf("samples of another pod should replace the window, got %v", got)
+	}
+}
+
+func TestMetricsPanel_Sparklines(t *testing.T) {
+	mp := NewMetricsPanel()
+	mp.SetSize(100, 30)
+	mp.SetPod(&repository.PodInfo{Name: "web", Namespace: "default", Containers: []repository.ContainerInfo{
+		{Name: "main", Resources: repository.ResourceRequirements{MemoryLimit: "512Mi"}},
+	}})
+
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		mp.SetMetrics(&repository.PodMetrics{
+			Name: "web", Namespace: "default", Timestamp: start.Add(time.Duration(i) * 15 * time.Second),
+			Containers: []repository.ContainerMetrics{{
+				Name: "main", CPUUsage: "100m", MemoryUsage: "128Mi", CPUMilli: 100, MemoryBytes: int64(i+1) * 100 << 20,
+			}},
+		})
+	}
+	if out := stripAnsiCodes(mp.viewport.View()); !strings.Contains(out, "▂▄▅▆█") {
+		t.Errorf("memory sparkline should climb toward the limit, got:\n%s", out)
+	}
+
+	// Switching pods evicts the history
+	mp.SetPod(&repository.PodInfo{Name: "api", Namespace: "default", Containers: []repository.ContainerInfo{{Name: "main"}}})
+	if len(mp.history.memory("main")) != 0 {
+		t.Error("history should be evicted when the pod changes")
+	}
+
+	// Until metrics-server was probed, missing metrics are being fetched
+	mp.SetMetrics(nil)
+	if out := stripAnsiCodes(mp.View()); !strings.Contains(out, "Waiting for metrics...") {
+		t.Errorf("missing metrics should show the waiting hint, got:\n%s", out)
+	}
+}
+
+func TestMetricsPanel_Status(t *testing.T) {
+	mp := NewMetricsPanel()
+	mp.SetSize(100, 30)
+	mp.SetPod(&repository.PodInfo{Name: "web", Namespace: "default", Containers: []repository.ContainerInfo{{Name: "main"}}})
+
+	mp.SetStatus(repository.MetricsStatus{State: repository.MetricsNotInstalled})
+	out := stripAnsiCodes(mp.View())
+	if !strings.Contains(out, "metrics-server not detected in this cluster") || !strings.Contains(out, "y: copy install command") {
+		t.Errorf("a cluster without metrics-server should say so, got:\n%s", out)
+	}
+	if strings.Contains(out, "Waiting for metrics") || !mp.MetricsServerMissing() {
+		t.Error("a cluster without metrics-server should not wait for metrics")
+	}
+
+	mp.SetStatus(repository.MetricsStatus{State: repository.MetricsFailing, Err: errors.New("the server is currently unable to handle the request")})
+	out = stripAnsiCodes(mp.View())
+	if !strings.Contains(out, "metrics API unavailable: the server is currently unable") || mp.MetricsServerMissing() {
+		t.Errorf("a failing metrics API should show its error, got:\n%s", out)
+	}
+
+	// Installing metrics-server mid-session fills the panel
+	mp.SetStatus(repository.MetricsStatus{State: repository.MetricsAvailable})
+	mp.SetMetrics(&repository.PodMetrics{Name: "web", Namespace: "default",
+		Containers: []repository.ContainerMetrics{{Name: "main", CPUUsage: "100m", MemoryUsage: "128Mi"}}})
+	out = stripAnsiCodes(mp.View())
+	if strings.Contains(out, "metrics API unavailable") || !strings.Contains(out, "128Mi") {
+		t.Errorf("available metrics should replace the status message, got:\n%s", out)
+	}
+}
+
 func TestMetricsPanel_View_NotReady(t *testing.T) {
 	mp := NewMetricsPanel()
 	view := mp.View()
@@ -940,7 +1582,31 @@ func TestMetricsPanel_SetNode(t *testing.T) {
 	}
 }
 
-func TestMetricsPanel_Update(t *testing.T) {
+func TestMetricsPanel_SetNode_SchedulingDetails(t *testing.T) {
+	mp := NewMetricsPanel()
+	mp.SetSize(120, 50)
+	mp.SetPod(&repository.PodInfo{Name: "web", Node: "worker-1"})
+	mp.SetNode(&repository.NodeInfo{
+		Name:              "worker-1",
+		Status:            "Ready",
+		AllocatableCPU:    "4",
+		AllocatableMemory: "8Gi",
+		RequestedCPU:      "750m",
+		RequestedMemory:   "1.0Gi",
+		Unschedulable:     true,
+		MemoryPressure:    true,
+		Taints:            []repository.TaintInfo{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}},
+	})
+
+	content := strings.Join(mp.rightContentLines, "\n")
+	for _, want := range []string{"750m / 4", "1.0Gi / 8Gi", "cordoned", "MemoryPressure", "dedicated=gpu:NoSchedule"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("node column should contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestMetricsPanel_Update(t *testing.T) {
 	mp := NewMetricsPanel()
 	mp.SetSize(100, 50)
 
@@ -1013,6 +1679,214 @@ func TestLogsPanel_SetLogs(t *testing.T) {
 	}
 }
 
+func TestLogsPanel_AppendLogs(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetSize(100, 50)
+	lp.SetLogs([]repository.LogLine{{Content: "first", Container: "app"}})
+
+	lp.AppendLogs([]repository.LogLine{
+		{Content: "second", Container: "app"},
+		{Content: "third", Container: "app"},
+	})
+	if lp.LogCount() != 3 {
+		t.Errorf("LogCount() = %d, want 3", lp.LogCount())
+	}
+
+	// The oldest lines are dropped past the buffer size
+	burst := make([]repository.LogLine, configs.DefaultLogBufferLines)
+	for i := range burst {
+		burst[i] = repository.LogLine{Content: "burst", Container: "app"}
+	}
+	lp.AppendLogs(burst)
+	if lp.LogCount() != configs.DefaultLogBufferLines {
+		t.Errorf("LogCount() = %d, want %d", lp.LogCount(), configs.DefaultLogBufferLines)
+	}
+	if lp.logs.At(0).Content != "burst" {
+		t.Errorf("oldest line = %q, want the earlier lines dropped", lp.logs.At(0).Content)
+	}
+	if lp.DroppedCount() != 3 || !strings.Contains(lp.View(), "buffer full, 3 oldest dropped") {
+		t.Errorf("DroppedCount() = %d, want 3 shown in the header", lp.DroppedCount())
+	}
+}
+
+func TestLogsPanel_RingBuffer(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetBufferSize(4)
+	lp.SetSize(100, 4) // 2 lines in view
+	lp.SetFilter("keep")
+
+	lines := func(contents ...string) []repository.LogLine {
+		var logs []repository.LogLine
+		for _, c := range contents {
+			logs = append(logs, repository.LogLine{Content: c, IsError: strings.Contains(c, "error")})
+		}
+		return logs
+	}
+	lp.SetLogs(lines("keep 1", "skip 2", "keep error 3"))
+	lp.AppendLogs(lines("skip 4", "keep 5", "keep 6"))
+
+	// "keep 1" and "skip 2" were dropped; the filtered index follows
+	got := lp.getFilteredLogs()
+	if len(got) != 3 || got[0].Content != "keep error 3" || got[2].Content != "keep 6" {
+		t.Fatalf("getFilteredLogs() = %+v, want the kept lines still in the buffer", got)
+	}
+	if lp.LogCount() != 4 || lp.ErrorCount() != 1 || lp.DroppedCount() != 2 {
+		t.Errorf("counts = %d lines, %d errors, %d dropped, want 4, 1, 2", lp.LogCount(), lp.ErrorCount(), lp.DroppedCount())
+	}
+
+	// Only the lines in view are rendered, the latest while following
+	view := lp.View()
+	if strings.Contains(view, "keep error 3") || !strings.Contains(view, "keep 5") || !strings.Contains(view, "keep 6") {
+		t.Errorf("view should render the last 2 shown lines:\n%s", view)
+	}
+
+	lp.following = false
+	lp.jumpToNextError()
+	if lp.offset != 0 || !strings.Contains(lp.View(), "keep error 3") {
+		t.Errorf("offset = %d, want the error line in view", lp.offset)
+	}
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if lp.offset != 1 {
+		t.Errorf("offset = %d after down, want 1", lp.offset)
+	}
+	if plain := lp.getPlainTextLogs(); plain != "keep error 3\nkeep 5\nkeep 6\n" {
+		t.Errorf("copied %q, want every shown line, not just those in view", plain)
+	}
+
+	// Shrinking keeps the newest lines and their sequence numbers
+	lp.SetBufferSize(2)
+	if got := lp.getFilteredLogs(); len(got) != 2 || got[0].Content != "keep 5" || lp.DroppedCount() != 4 {
+		t.Errorf("after shrinking: %+v, %d dropped", got, lp.DroppedCount())
+	}
+	lp.AppendLogs(lines("keep 7"))
+	if got := lp.getFilteredLogs(); len(got) != 2 || got[1].Content != "keep 7" {
+		t.Errorf("after appending: %+v", got)
+	}
+}
+
+// BenchmarkLogsPanel_AppendLogs appends to a full buffer: the cost of a
+// line doesn't grow with the lines kept.
+func BenchmarkLogsPanel_AppendLogs(b *testing.B) {
+	for _, size := range []int{1000, 100000} {
+		b.Run(fmt.Sprintf("buffer=%d", size), func(b *testing.B) {
+			lp := NewLogsPanel()
+			lp.SetBufferSize(size)
+			lp.SetSize(120, 40)
+			line := []repository.LogLine{{Content: "GET /healthz 200", Container: "app"}}
+			full := make([]repository.LogLine, size)
+			for i := range full {
+				full[i] = line[0]
+			}
+			lp.SetLogs(full)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				lp.AppendLogs(line)
+			}
+		})
+	}
+}
+
+func TestLogsPanel_WorkloadMode(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetSize(100, 50)
+
+	// Without a workload there is nothing to merge
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	if lp.WorkloadMode() {
+		t.Fatal("'M' should do nothing for a pod without a workload")
+	}
+
+	lp.SetWorkload("web")
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	if !lp.WorkloadMode() || lp.Comparing() {
+		t.Fatal("'M' should merge the workload's pods and leave compare mode")
+	}
+	if !strings.Contains(lp.View(), "all pods of web") {
+		t.Error("header should show the workload")
+	}
+
+	lp.SetLogs([]repository.LogLine{
+		{Pod: "web-a", Content: "starting"},
+		{Pod: "web-b", Content: "panic: boom", IsError: true},
+		{Pod: "web-a", Content: "ready"},
+	})
+	plain := lp.getPlainTextLogs()
+	if !strings.Contains(plain, "[web-a] starting\n") || !strings.Contains(plain, "[web-b] panic: boom\n") {
+		t.Errorf("lines should be prefixed with their pod:\n%s", plain)
+	}
+
+	// Search matches pod names across the merged stream
+	lp.SetFilter("web-a")
+	if got := len(lp.getFilteredLogs()); got != 2 {
+		t.Errorf("filtered %d lines, want 2", got)
+	}
+	lp.SetFilter("")
+
+	lp.following = false
+	lp.SetSize(100, 4)
+	lp.jumpToNextError()
+	if lp.offset != 1 {
+		t.Errorf("offset = %d, want the error from web-b on line 1", lp.offset)
+	}
+
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	if lp.WorkloadMode() {
+		t.Error("'P' should leave workload mode")
+	}
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	lp.SetWorkload("")
+	if lp.WorkloadMode() {
+		t.Error("clearing the workload should leave workload mode")
+	}
+}
+
+func TestLogsPanel_PrettyJSON(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetSize(100, 50)
+	lp.SetLogs([]repository.LogLine{
+		{Content: `{"level":"info","ts":"12:00:01","msg":"request","status":200,"path":"/api"}`},
+		{Content: "plain text line"},
+		{Content: `{"level":"error","msg":"upstream failed","status":502}`, IsError: true},
+	})
+
+	if plain := lp.getPlainTextLogs(); !strings.Contains(plain, `{"level":"info"`) {
+		t.Errorf("JSON lines should be raw until toggled:\n%s", plain)
+	}
+
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+	if !lp.PrettyJSON() {
+		t.Fatal("'J' should toggle pretty JSON")
+	}
+	plain := lp.getPlainTextLogs()
+	for _, want := range []string{
+		"12:00:01 INFO request path=/api status=200\n",
+		"plain text line\n",
+		"ERROR upstream failed status=502\n",
+	} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("pretty logs missing %q:\n%s", want, plain)
+		}
+	}
+	if !strings.Contains(lp.View(), "[JSON]") {
+		t.Error("header should show pretty JSON mode")
+	}
+
+	// Field queries match JSON fields; plain lines fall back to text search
+	lp.SetFilter("status>=500")
+	if got := lp.getFilteredLogs(); len(got) != 1 || !got[0].IsError {
+		t.Errorf("status>=500 matched %v, want the error line", got)
+	}
+	lp.SetFilter("level=info")
+	if got := len(lp.getFilteredLogs()); got != 1 {
+		t.Errorf("level=info matched %d lines, want 1", got)
+	}
+	lp.SetFilter("plain")
+	if got := len(lp.getFilteredLogs()); got != 1 {
+		t.Errorf("plain text search matched %d lines, want 1", got)
+	}
+}
+
 func TestLogsPanel_SetContainers(t *testing.T) {
 	lp := NewLogsPanel()
 	lp.SetSize(100, 50)
@@ -1025,6 +1899,66 @@ func TestLogsPanel_SetContainers(t *testing.T) {
 	}
 }
 
+func TestLogsPanel_InitContainerSuffix(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetSize(100, 50)
+	lp.SetInitContainers([]string{"migrate"})
+	lp.SetContainers([]string{"migrate", "app"})
+
+	lp.SelectContainer("migrate")
+	if !strings.Contains(lp.View(), "[migrate (init)]") {
+		t.Error("header should mark the init container")
+	}
+	lp.SelectContainer("app")
+	if strings.Contains(lp.View(), "(init)") {
+		t.Error("header should not mark a regular container")
+	}
+}
+
+func TestLogsPanel_NextRestart(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetSize(120, 50)
+	lp.SetContainers([]string{"app", "sidecar"})
+	lp.SetNextRestarts(map[string]time.Time{"app": time.Now().Add(130*time.Second + 400*time.Millisecond)})
+
+	if view := stripAnsiCodes(lp.View()); !strings.Contains(view, "next restart in ~2m 10s") {
+		t.Errorf("header should count down to the restart of any container, got %q", view)
+	}
+	lp.SelectContainer("sidecar")
+	if strings.Contains(lp.View(), "next restart") {
+		t.Error("header should not count down for a container that is not backing off")
+	}
+	lp.SelectContainer("app")
+	if !strings.Contains(stripAnsiCodes(lp.View()), "next restart in") {
+		t.Error("header should count down for the selected container")
+	}
+}
+
+func TestLogsPanel_SelectContainer(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetSize(100, 50)
+	lp.SetContainers([]string{"app", "sidecar"})
+
+	if lp.SelectContainer("missing") {
+		t.Error("SelectContainer() should fail for an unknown container")
+	}
+	if !lp.SelectContainer("sidecar") || lp.SelectedContainer() != "sidecar" {
+		t.Fatalf("SelectedContainer() = %q, want sidecar", lp.SelectedContainer())
+	}
+
+	// A refresh with the same containers keeps the selection
+	lp.SetContainers([]string{"app", "sidecar"})
+	if lp.SelectedContainer() != "sidecar" {
+		t.Errorf("SelectedContainer() after refresh = %q, want sidecar", lp.SelectedContainer())
+	}
+
+	// The selection resets to all containers once the container is gone
+	lp.SetContainers([]string{"app"})
+	if lp.SelectedContainer() != "" {
+		t.Errorf("SelectedContainer() = %q, want all containers", lp.SelectedContainer())
+	}
+}
+
 func TestLogsPanel_ToggleFollowing(t *testing.T) {
 	lp := NewLogsPanel()
 	lp.SetSize(100, 50)
@@ -1046,6 +1980,147 @@ func TestLogsPanel_ToggleFollowing(t *testing.T) {
 	}
 }
 
+func TestLogsPanel_CompareMode(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetSize(100, 50)
+
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	if !lp.Comparing() || lp.ShowPrevious() {
+		t.Fatal("'D' should enter compare mode and leave previous logs")
+	}
+
+	lp.SetComparedLogs([]repository.LogLine{
+		{Content: "connecting to db", Previous: true},
+		{Content: "panic: timeout", Previous: true, IsError: true},
+		{Content: "connecting to cache"},
+	}, true)
+	plain := lp.getPlainTextLogs()
+	if !strings.Contains(plain, "- connecting to db\n") || !strings.Contains(plain, "  connecting to cache\n") {
+		t.Errorf("compared logs should mark previous-only lines:\n%s", plain)
+	}
+
+	// Search spans both instances
+	lp.SetFilter("connecting")
+	if got := len(lp.getFilteredLogs()); got != 2 {
+		t.Errorf("filtered %d lines, want 2", got)
+	}
+
+	if strings.Contains(lp.View(), "no previous instance") {
+		t.Error("header should not report a missing previous instance")
+	}
+	lp.SetComparedLogs([]repository.LogLine{{Content: "first start"}}, false)
+	if !strings.Contains(lp.View(), "no previous instance") {
+		t.Error("header should report that the container has not restarted")
+	}
+
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	if lp.Comparing() || !lp.ShowPrevious() {
+		t.Error("'P' should leave compare mode for previous logs")
+	}
+}
+
+func TestLogsPanel_Themes(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	defer func() {
+		lipgloss.SetColorProfile(profile)
+		style.Apply(style.DefaultTheme)
+	}()
+
+	lp := NewLogsPanel()
+	lp.SetSize(100, 20)
+	lp.SetLogs([]repository.LogLine{{Content: "connection refused", IsError: true}})
+
+	style.Apply(style.DefaultTheme)
+	lp.Restyle()
+	def := lp.View()
+	style.Apply(style.ColorBlindTheme)
+	lp.Restyle()
+	cb := lp.View()
+
+	if def == cb {
+		t.Fatal("logs should render differently under the default and color-blind themes")
+	}
+	if !strings.Contains(def, "38;2;248;113;113") || strings.Contains(def, "38;2;213;94;0") {
+		t.Error("default theme should render the error line in its red")
+	}
+	if !strings.Contains(cb, "38;2;213;94;0") || strings.Contains(cb, "38;2;248;113;113") {
+		t.Error("color-blind theme should render the error line in vermillion")
+	}
+}
+
+func TestLogsPanel_TimeRange(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetSize(100, 50)
+	lp.SetTimeFilter(TimeFilter15Min)
+
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	if !lp.EditingTimeRange() || !lp.IsSearching() {
+		t.Fatal("'R' should open the time range input")
+	}
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("10:42-later")})
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !lp.EditingTimeRange() || !lp.TimeRange().IsZero() || !strings.Contains(lp.View(), "invalid time") {
+		t.Fatal("an invalid range should keep the input open with the error")
+	}
+
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("00:00-00:05")})
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	r := lp.TimeRange()
+	if lp.EditingTimeRange() || r.IsZero() || r.Until.Sub(r.Since) != 5*time.Minute {
+		t.Fatalf("TimeRange() = %v, want a 5 minute range", r)
+	}
+	if lp.IsFollowing() || lp.TimeFilter() != TimeFilterAll {
+		t.Error("a time range should stop following and clear the time filter")
+	}
+
+	lp.SetLogs([]repository.LogLine{
+		{Content: "before", Timestamp: r.Since.Add(-time.Minute)},
+		{Content: "inside", Timestamp: r.Since.Add(time.Minute)},
+	})
+	if got := lp.getFilteredLogs(); len(got) != 1 || got[0].Content != "inside" {
+		t.Errorf("getFilteredLogs() = %+v, want only the line inside the range", got)
+	}
+	if !strings.Contains(lp.View(), r.String()) {
+		t.Error("header should show the time range")
+	}
+
+	// Following goes back to the latest logs
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if !lp.TimeRange().IsZero() || !lp.IsFollowing() {
+		t.Error("'f' should clear the time range and follow")
+	}
+
+	// An empty range clears it too
+	lp.SetTimeRange(r)
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !lp.TimeRange().IsZero() {
+		t.Error("an empty range should clear the time range")
+	}
+}
+
+func TestEventsPanel_LogsAroundEvent(t *testing.T) {
+	ep := NewEventsPanel()
+	ep.SetSize(100, 50)
+	if _, cmd := ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}}); cmd != nil {
+		t.Error("'L' without events should do nothing")
+	}
+
+	seen := time.Date(2024, 3, 10, 10, 42, 0, 0, time.UTC)
+	ep.SetEvents([]repository.EventInfo{{Type: "Warning", Reason: "BackOff", FirstSeen: seen}})
+	_, cmd := ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	if cmd == nil {
+		t.Fatal("'L' should request the logs around the event")
+	}
+	if req, ok := cmd().(EventLogsRequest); !ok || !req.Time.Equal(seen) {
+		t.Errorf("'L' sent %+v, want an EventLogsRequest at %v", req, seen)
+	}
+}
+
 func TestLogsPanel_Navigation(t *testing.T) {
 	lp := NewLogsPanel()
 	lp.SetSize(100, 50)
@@ -1420,6 +2495,61 @@ func TestHPAViewer_SetSize(t *testing.T) {
 	}
 }
 
+func TestHPAViewer_Timeline(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	window := int32(600)
+	hv := NewHPAViewer()
+	hv.SetSize(120, 80)
+	hv.Show(&repository.HPAData{
+		Name:      "web-hpa",
+		Namespace: "default",
+		Metrics: []repository.HPAMetricDetail{
+			{Type: "Resource", Name: "cpu", Current: "120%", Target: "80%", Ratio: 1.5},
+			{Type: "Pods", Name: "http_requests", Current: "<unknown>", Target: "100"},
+		},
+		Behavior: &repository.HPABehavior{
+			ScaleDown: &repository.HPAScalingRules{
+				StabilizationWindowSeconds: &window,
+				SelectPolicy:               "Min",
+				Policies:                   []repository.HPAScalingPolicy{{Type: "Percent", Value: 10, PeriodSeconds: 60}},
+			},
+		},
+		Events: []repository.HPAScaleEvent{
+			{Time: at, Replicas: 6, Reason: "cpu above target"},
+			{Time: at.Add(10 * time.Minute), Replicas: 4, Reason: "All metrics below target", Count: 3},
+		},
+	}, "default")
+
+	content := strings.Join(hv.lines, "\n")
+	for _, want := range []string{"150% of target", "Scale Up:", "default", "600s window, select Min", "10% per 60s",
+		"Scaling Timeline", "Mar 01 12:00:00", "↓", "4 replicas", "(x3)", "All metrics below target"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("HPA viewer lines missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Count(content, "of target") != 1 {
+		t.Error("a metric without a current value should have no bar")
+	}
+	if !strings.Contains(hv.buildClipboardContent(), "Scaling Timeline:") {
+		t.Error("clipboard content should include the scaling timeline")
+	}
+}
+
+func TestMetricBar(t *testing.T) {
+	for _, tt := range []struct {
+		ratio  float64
+		filled int
+	}{{0.5, 5}, {1, 10}, {1.5, 15}, {3, 20}} {
+		bar := metricBar(tt.ratio)
+		if got := strings.Count(bar, "█"); got != tt.filled {
+			t.Errorf("metricBar(%v) filled %d cells, want %d", tt.ratio, got, tt.filled)
+		}
+		if !strings.Contains(bar, "│") {
+			t.Errorf("metricBar(%v) = %q, want the target marker", tt.ratio, bar)
+		}
+	}
+}
+
 func TestHPAViewerClosed(t *testing.T) {
 	msg := HPAViewerClosed{}
 	_ = msg // Just ensure the type exists
@@ -1531,6 +2661,88 @@ func TestConfigMapViewer_Update_ActionMenu(t *testing.T) {
 	}
 }
 
+func TestConfigMapViewer_EditAndDelete(t *testing.T) {
+	cv := NewConfigMapViewer()
+	cv.Show(&repository.ConfigMapData{
+		Name:            "test-cm",
+		Data:            map[string]string{"a": "1", "b": "2"},
+		ResourceVersion: "42",
+	}, "default")
+	cv, _ = cv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+
+	_, cmd := cv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if cmd == nil {
+		t.Fatal("'e' should request an edit")
+	}
+	want := ConfigMapEditRequest{Namespace: "default", Name: "test-cm", Key: "b", Value: "2", ResourceVersion: "42"}
+	if got, ok := cmd().(ConfigMapEditRequest); !ok || got != want {
+		t.Errorf("'e' msg = %+v, want %+v", got, want)
+	}
+
+	_, cmd = cv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if cmd == nil {
+		t.Fatal("'d' should request a delete")
+	}
+	if got, ok := cmd().(ConfigMapDeleteRequest); !ok || got != (ConfigMapDeleteRequest{Namespace: "default", Name: "test-cm"}) {
+		t.Errorf("'d' msg = %+v", got)
+	}
+}
+
+func TestConfigMapViewer_ShowKeepsKey(t *testing.T) {
+	cv := NewConfigMapViewer()
+	cv.Show(&repository.ConfigMapData{Name: "test-cm", Data: map[string]string{"a": "1", "b": "2"}}, "default")
+	cv, _ = cv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	cv.SetStatusMsg("Saved b")
+
+	// Reloaded after an edit
+	cv.Show(&repository.ConfigMapData{Name: "test-cm", Data: map[string]string{"a": "1", "b": "3"}}, "default")
+	if cv.keyCursor != 1 || cv.statusMsg != "Saved b" {
+		t.Errorf("keyCursor = %d, statusMsg = %q; want the same key and status", cv.keyCursor, cv.statusMsg)
+	}
+
+	cv.Show(&repository.ConfigMapData{Name: "other", Data: map[string]string{"a": "1", "b": "2"}}, "default")
+	if cv.keyCursor != 0 || cv.statusMsg != "" {
+		t.Error("showing another ConfigMap should start at the first key")
+	}
+}
+
+func TestConfigMapViewer_Changes(t *testing.T) {
+	cv := NewConfigMapViewer()
+	cv.SetSize(120, 40)
+	cv.SetChanges(repository.DiffConfigData(
+		map[string]string{"host": "db-1", "debug": "true"},
+		map[string]string{"host": "db-2", "pool": "10"}, false))
+	cv.Show(&repository.ConfigMapData{
+		Name: "app",
+		Data: map[string]string{"host": "db-2", "pool": "10"},
+		Updates: []repository.FieldManagerUpdate{
+			{Manager: "kubectl-edit", Operation: "Update", Time: time.Now().Add(-5 * time.Minute), Keys: []string{"host"}},
+		},
+	}, "default")
+
+	view := cv.View()
+	for _, want := range []string{"host  (changed)", "was: db-1", "pool  (added)", "Removed since last viewed: debug", "[3 changed since last viewed]", "kubectl-edit  Update    5m ago  host"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view does not show %q:\n%s", want, view)
+		}
+	}
+	if cv.sortedKeys[0] != "host" {
+		t.Errorf("sortedKeys = %v, markers should not change the keys", cv.sortedKeys)
+	}
+}
+
+func TestSecretViewer_ChangesHideValues(t *testing.T) {
+	sv := NewSecretViewer()
+	sv.SetSize(120, 40)
+	sv.SetChanges(repository.DiffConfigData(map[string]string{"password": "hunter2"}, map[string]string{"password": "s3cret"}, true))
+	sv.Show(&repository.SecretData{Name: "db", Data: map[string]string{"password": "s3cret"}}, "default")
+
+	view := sv.View()
+	if !strings.Contains(view, "password  (changed)") || strings.Contains(view, "hunter2") {
+		t.Errorf("view should mark the key changed without the previous value:\n%s", view)
+	}
+}
+
 func TestConfigMapViewer_SetSize(t *testing.T) {
 	cv := NewConfigMapViewer()
 	cv.SetSize(100, 50)
@@ -1688,6 +2900,40 @@ func TestSecretViewer_Update_ActionMenu(t *testing.T) {
 	}
 }
 
+func TestSecretViewer_EditAndDelete(t *testing.T) {
+	sv := NewSecretViewer()
+	sv.Show(&repository.SecretData{
+		Name:            "test-secret",
+		Data:            map[string]string{"password": "hunter2"}, // Decoded
+		ResourceVersion: "7",
+	}, "default")
+
+	_, cmd := sv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if cmd == nil {
+		t.Fatal("'e' should request an edit")
+	}
+	want := SecretEditRequest{Namespace: "default", Name: "test-secret", Key: "password", Value: "hunter2", ResourceVersion: "7"}
+	if got, ok := cmd().(SecretEditRequest); !ok || got != want {
+		t.Errorf("'e' msg = %+v, want %+v", got, want)
+	}
+
+	_, cmd = sv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if cmd == nil {
+		t.Fatal("'d' should request a delete")
+	}
+	if got, ok := cmd().(SecretDeleteRequest); !ok || got != (SecretDeleteRequest{Namespace: "default", Name: "test-secret"}) {
+		t.Errorf("'d' msg = %+v", got)
+	}
+}
+
+func TestSecretViewer_EditNoKeys(t *testing.T) {
+	sv := NewSecretViewer()
+	sv.Show(&repository.SecretData{Name: "empty"}, "default")
+	if _, cmd := sv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}}); cmd != nil {
+		t.Error("'e' should do nothing without keys")
+	}
+}
+
 func TestSecretViewer_SetSize(t *testing.T) {
 	sv := NewSecretViewer()
 	sv.SetSize(100, 50)
@@ -1814,19 +3060,127 @@ func TestNavigator_Mode(t *testing.T) {
 	}
 }
 
-func TestNavigator_SetWorkloads(t *testing.T) {
-	nav := NewNavigator()
-	workloads := []repository.WorkloadInfo{
-		{Name: "deploy-1", Namespace: "default"},
-		{Name: "deploy-2", Namespace: "default"},
+func TestClusterSwitcher(t *testing.T) {
+	cs := NewClusterSwitcher()
+	cs.SetSize(140, 40)
+	cs.Show("prod")
+	if !cs.IsVisible() || !strings.Contains(cs.View(), "Reading kubeconfig") {
+		t.Fatal("the switcher should open before the contexts are read")
 	}
-	nav.SetWorkloads(workloads)
-	if len(nav.workloads) != 2 {
-		t.Errorf("workloads count = %d, want 2", len(nav.workloads))
+
+	cs.SetContexts([]string{"dev", "prod", "staging"}, []string{"staging"})
+	if got := cs.Selected(); got != "prod" {
+		t.Errorf("Selected() = %q, want the current context prod", got)
+	}
+	if cs.contexts[0] != "staging" {
+		t.Errorf("contexts = %v, want the favorite staging first", cs.contexts)
+	}
+	if view := cs.View(); !strings.Contains(view, "probing 3") || !strings.Contains(view, "prod (current)") {
+		t.Errorf("view should show the current context and pending probes:\n%s", view)
 	}
-}
 
-func TestNavigator_SetPods(t *testing.T) {
+	cs.SetHealth(repository.ContextHealth{Context: "prod", Server: "https://prod:6443", Version: "v1.29.2"})
+	cs.SetHealth(repository.ContextHealth{Context: "dev", Err: errors.New("Unauthorized"), Problem: repository.ConnectionAuth})
+	view := cs.View()
+	if !strings.Contains(view, "reachable") || !strings.Contains(view, "v1.29.2") || !strings.Contains(view, "auth error") {
+		t.Errorf("view should show the probed health:\n%s", view)
+	}
+
+	// Favorites move to the top and the cursor follows
+	cs, cmd := cs.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if msg, ok := cmd().(FavoriteContextToggled); !ok || msg.Context != "prod" {
+		t.Errorf("f sent %v, want FavoriteContextToggled for prod", cmd())
+	}
+	if cs.contexts[0] != "prod" || cs.Selected() != "prod" {
+		t.Errorf("contexts = %v with %q selected, want prod first and selected", cs.contexts, cs.Selected())
+	}
+
+	cs, cmd = cs.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if msg, ok := cmd().(ContextProbeRequest); !ok || len(msg.Contexts) != 3 {
+		t.Errorf("r sent %v, want a probe of the 3 contexts", cmd())
+	}
+
+	cs, _ = cs.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	cs, cmd = cs.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(ContextSelected); !ok || msg.Context != "staging" {
+		t.Errorf("Enter sent %v, want ContextSelected for staging", cmd())
+	}
+	if cs.IsVisible() {
+		t.Error("the switcher should close on selection")
+	}
+}
+
+func TestNavigator_SetLocation(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetSize(100, 50)
+	nav.SetMode(ModeResources)
+
+	nav.SetLocation(true, "kube-system")
+	view := nav.View()
+	if !strings.Contains(view, "kube-system") || !strings.Contains(view, "protected") {
+		t.Error("protected namespace should show the namespace and a protected badge")
+	}
+
+	nav.SetLocation(false, "default")
+	if strings.Contains(nav.View(), "protected") {
+		t.Error("unprotected namespace should not show the badge")
+	}
+}
+
+func TestNavigator_SetWorkloads(t *testing.T) {
+	nav := NewNavigator()
+	workloads := []repository.WorkloadInfo{
+		{Name: "deploy-1", Namespace: "default"},
+		{Name: "deploy-2", Namespace: "default"},
+	}
+	nav.SetWorkloads(workloads)
+	if len(nav.workloads) != 2 {
+		t.Errorf("workloads count = %d, want 2", len(nav.workloads))
+	}
+}
+
+func TestNavigator_UpdateWorkload(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetWorkloads([]repository.WorkloadInfo{
+		{Name: "api", Namespace: "default", Type: repository.ResourceDeployments, Ready: "2/2"},
+		{Name: "web", Namespace: "default", Type: repository.ResourceDeployments, Ready: "1/1"},
+	})
+	nav.SetScaleWorkload(&repository.WorkloadInfo{Name: "web", Namespace: "default", Type: repository.ResourceDeployments})
+
+	nav.UpdateWorkload(repository.WorkloadInfo{Name: "web", Namespace: "default", Type: repository.ResourceDeployments, Ready: "1/3", Replicas: 3})
+	if nav.workloads[1].Ready != "1/3" || nav.workloads[0].Ready != "2/2" {
+		t.Errorf("workloads = %+v, want only web updated", nav.workloads)
+	}
+	if nav.GetScaleWorkload().Replicas != 3 {
+		t.Errorf("scale workload replicas = %d, want 3", nav.GetScaleWorkload().Replicas)
+	}
+}
+
+func TestNavigator_ServicesTable(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetSize(140, 40)
+	nav.SetResourceType(repository.ResourceServices)
+	nav.SetMode(ModeWorkloads)
+	nav.SetWorkloads([]repository.WorkloadInfo{
+		{
+			Name: "web", Namespace: "default", Type: repository.ResourceServices, Ready: "2 ready", Status: "ClusterIP",
+			Service: &repository.ServiceInfo{Name: "web", Type: "ClusterIP", ClusterIP: "10.0.0.10", Ports: "80/TCP", Endpoints: 2},
+		},
+		{
+			Name: "db", Namespace: "default", Type: repository.ResourceServices, Ready: "no selector", Status: "ExternalName",
+			Service: &repository.ServiceInfo{Name: "db", Type: "ExternalName", NoSelector: true},
+		},
+	})
+
+	view := nav.View()
+	for _, want := range []string{"CLUSTER-IP", "ENDPOINTS", "10.0.0.10", "80/TCP", "2 ready", "no selector"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("services view should contain %q", want)
+		}
+	}
+}
+
+func TestNavigator_SetPods(t *testing.T) {
 	nav := NewNavigator()
 	pods := []repository.PodInfo{
 		{Name: "pod-1", Namespace: "default"},
@@ -1838,6 +3192,276 @@ func TestNavigator_SetPods(t *testing.T) {
 	}
 }
 
+func TestRelocateCursor(t *testing.T) {
+	tests := []struct {
+		name   string
+		prev   []string
+		cursor int
+		next   []string
+		want   int
+	}{
+		{"unchanged", []string{"a", "b", "c"}, 1, []string{"a", "b", "c"}, 1},
+		{"insertion above cursor", []string{"a", "b", "c"}, 1, []string{"x", "y", "a", "b", "c"}, 3},
+		{"deletion above cursor", []string{"a", "b", "c"}, 2, []string{"b", "c"}, 1},
+		{"reordered by sort change", []string{"a", "b", "c"}, 0, []string{"c", "b", "a"}, 2},
+		{"selected deleted selects row below", []string{"a", "b", "c"}, 1, []string{"a", "c"}, 1},
+		{"selected last deleted selects row above", []string{"a", "b", "c"}, 2, []string{"a", "b"}, 1},
+		{"selected and below deleted", []string{"a", "b", "c", "d"}, 2, []string{"a", "b", "x"}, 1},
+		{"nothing survived keeps position", []string{"a", "b", "c"}, 2, []string{"x", "y"}, 1},
+		{"first load", nil, 0, []string{"a", "b"}, 0},
+		{"emptied", []string{"a", "b"}, 1, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RelocateCursor(tt.prev, tt.cursor, tt.next); got != tt.want {
+				t.Errorf("RelocateCursor(%v, %d, %v) = %d, want %d", tt.prev, tt.cursor, tt.next, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNavigator_SetPods_KeepsSelection(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetMode(ModeResources)
+	nav.SetPods([]repository.PodInfo{
+		{Name: "api-1", Namespace: "default"},
+		{Name: "web-1", Namespace: "default"},
+		{Name: "web-2", Namespace: "default"},
+	})
+	nav.sectionCursors[SectionPods] = 1
+
+	// A new pod sorted above the selection
+	nav.SetPods([]repository.PodInfo{
+		{Name: "api-1", Namespace: "default"},
+		{Name: "api-2", Namespace: "default"},
+		{Name: "web-1", Namespace: "default"},
+		{Name: "web-2", Namespace: "default"},
+	})
+	if p := nav.SelectedPod(); p == nil || p.Name != "web-1" {
+		t.Fatalf("SelectedPod() = %v, want web-1 after insertion above", p)
+	}
+
+	// The selected pod is deleted
+	nav.SetPods([]repository.PodInfo{
+		{Name: "api-1", Namespace: "default"},
+		{Name: "api-2", Namespace: "default"},
+		{Name: "web-2", Namespace: "default"},
+	})
+	if p := nav.SelectedPod(); p == nil || p.Name != "web-2" {
+		t.Errorf("SelectedPod() = %v, want neighbour web-2 after deletion", p)
+	}
+}
+
+func TestNavigator_MarkPods(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetSize(120, 60)
+	nav.SetMode(ModeResources)
+	nav.SetPods([]repository.PodInfo{
+		{Name: "api-1", Namespace: "default"},
+		{Name: "web-1", Namespace: "default"},
+		{Name: "web-2", Namespace: "default"},
+	})
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	all := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}
+	marked := func() []string {
+		var names []string
+		for _, p := range nav.MarkedPods() {
+			names = append(names, p.Name)
+		}
+		return names
+	}
+
+	nav, _ = nav.Update(space)
+	if got := marked(); strings.Join(got, ",") != "api-1" {
+		t.Fatalf("MarkedPods() = %v, want api-1", got)
+	}
+	if p := nav.SelectedPod(); p == nil || p.Name != "web-1" {
+		t.Errorf("SelectedPod() = %v, want the cursor on the next pod", p)
+	}
+	if view := nav.View(); !strings.Contains(view, "1 selected") {
+		t.Errorf("view should count the selected pods, got %q", view)
+	}
+
+	// Select all only selects the pods the filter shows
+	nav.searchQuery = "web"
+	nav, _ = nav.Update(all)
+	if got := marked(); strings.Join(got, ",") != "api-1,web-1,web-2" {
+		t.Fatalf("MarkedPods() = %v, want every pod", got)
+	}
+	nav, _ = nav.Update(all)
+	if got := marked(); strings.Join(got, ",") != "api-1" {
+		t.Fatalf("MarkedPods() = %v, want the visible pods unselected again", got)
+	}
+	nav.searchQuery = ""
+
+	// Refreshes keep the selection, less the pods that are gone
+	nav, _ = nav.Update(all)
+	nav.SetPods([]repository.PodInfo{{Name: "web-1", Namespace: "default"}, {Name: "web-3", Namespace: "default"}})
+	nav.SetMode(ModeResources)
+	if got := marked(); strings.Join(got, ",") != "web-1" {
+		t.Errorf("MarkedPods() = %v, want web-1 kept across the refresh", got)
+	}
+	nav.SetMode(ModeWorkloads)
+	if got := marked(); got != nil {
+		t.Errorf("MarkedPods() = %v, want none after leaving the pod list", got)
+	}
+}
+
+func TestBulkPodActions_ProtectedNamespace(t *testing.T) {
+	actions := func(items []WorkloadActionItem) []string {
+		var got []string
+		for _, item := range items {
+			got = append(got, item.Action)
+		}
+		return got
+	}
+
+	if got := actions(BulkPodActions(3, false)); strings.Join(got, ",") != "bulk-delete,bulk-copy-names" {
+		t.Errorf("BulkPodActions() = %v, want delete and copy", got)
+	}
+	protected := configs.DefaultConfig().IsProtectedNamespace("prod", "kube-system")
+	if got := actions(BulkPodActions(3, protected)); strings.Join(got, ",") != "bulk-copy-names" {
+		t.Errorf("BulkPodActions() in kube-system = %v, want copy only", got)
+	}
+}
+
+func TestRenderBulkDeleteProgress(t *testing.T) {
+	names := []string{"web-1", "web-2", "web-3"}
+	done := []repository.PodDeleteResult{
+		{Namespace: "shop", Name: "web-2"},
+		{Namespace: "shop", Name: "web-1", Err: errors.New("forbidden")},
+	}
+
+	progress := stripAnsiCodes(RenderBulkDeleteProgress("shop", names, done, false))
+	for _, want := range []string{"Deleting 2/3 pods in shop", "deleting  web-3", "deleted   web-2", "failed    web-1  forbidden"} {
+		if !strings.Contains(progress, want) {
+			t.Errorf("progress should contain %q, got %q", want, progress)
+		}
+	}
+
+	summary := stripAnsiCodes(RenderBulkDeleteProgress("shop", names[:2], done, true))
+	if !strings.Contains(summary, "Deleted:             1") || !strings.Contains(summary, "Failed:              1") {
+		t.Errorf("summary should count deleted and failed pods, got %q", summary)
+	}
+}
+
+func TestNavigator_AppendPods(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetSize(120, 60)
+	nav.SetMode(ModeResources)
+	nav.SetPods([]repository.PodInfo{
+		{Name: "api-1", Namespace: "default"},
+		{Name: "api-2", Namespace: "default"},
+	})
+	nav.sectionCursors[SectionPods] = 1
+	nav.SetPodsLoadingMore(true)
+	if view := nav.View(); !strings.Contains(view, "loading more…") || !strings.Contains(view, "PODS (2)") {
+		t.Errorf("view should show the loading footer and count while pages load, got %q", view)
+	}
+
+	nav.AppendPods([]repository.PodInfo{
+		{Name: "web-1", Namespace: "default"},
+		{Name: "web-2", Namespace: "default"},
+	})
+	if len(nav.Pods()) != 4 {
+		t.Errorf("pods count = %d, want 4", len(nav.Pods()))
+	}
+	if p := nav.SelectedPod(); p == nil || p.Name != "api-2" {
+		t.Errorf("SelectedPod() = %v, want api-2 to stay selected", p)
+	}
+
+	nav.SetPodsLoadingMore(false)
+	if view := nav.View(); strings.Contains(view, "loading more…") || !strings.Contains(view, "PODS (4)") {
+		t.Errorf("view should drop the loading footer and show the new count, got %q", view)
+	}
+}
+
+func TestNavigator_AppendWorkloads(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetSize(120, 40)
+	nav.SetMode(ModeWorkloads)
+	nav.SetWorkloads([]repository.WorkloadInfo{
+		{Name: "api", Namespace: "default", Type: repository.ResourceDeployments},
+		{Name: "web", Namespace: "default", Type: repository.ResourceDeployments},
+	})
+	nav.cursor = 1
+	nav.SetWorkloadsLoadingMore(true)
+	if view := nav.View(); !strings.Contains(view, "loading more…") {
+		t.Error("view should show the loading footer while pages load")
+	}
+
+	nav.AppendWorkloads([]repository.WorkloadInfo{
+		{Name: "worker", Namespace: "default", Type: repository.ResourceDeployments},
+	})
+	if len(nav.workloads) != 3 {
+		t.Errorf("workloads count = %d, want 3", len(nav.workloads))
+	}
+	if w := nav.SelectedWorkload(); w == nil || w.Name != "web" {
+		t.Errorf("SelectedWorkload() = %v, want web to stay selected", w)
+	}
+}
+
+func TestNavigator_WorkloadPodHealth(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetSize(140, 40)
+	nav.SetMode(ModeWorkloads)
+	nav.SetWorkloads([]repository.WorkloadInfo{
+		{Name: "api", Namespace: "default", Type: repository.ResourceDeployments, Ready: "2/2",
+			Health: &repository.PodHealth{Total: 2, Ready: 2}},
+		{Name: "web", Namespace: "default", Type: repository.ResourceDeployments, Ready: "3/5",
+			Health: &repository.PodHealth{Total: 5, Ready: 3, CrashLoop: 1, Pending: 1}},
+		{Name: "idle", Namespace: "default", Type: repository.ResourceDeployments, Ready: "0/0",
+			Health: &repository.PodHealth{}},
+	})
+
+	view := nav.View()
+	if !strings.Contains(view, "PODS") {
+		t.Error("view should have a PODS column")
+	}
+	for _, want := range []string{"2/2 ready", "3/5 ready · 1 CrashLoop · 1 Pending"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+	if strings.Contains(view, "0/0 ready") {
+		t.Error("a workload without pods should show no breakdown")
+	}
+}
+
+func TestNavigator_AttachPodHealth(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetWorkloads([]repository.WorkloadInfo{
+		{Name: "api", Namespace: "default", Type: repository.ResourceDeployments, Labels: map[string]string{"app": "api"}},
+		{Name: "web", Namespace: "default", Type: repository.ResourceDeployments, Labels: map[string]string{"app": "web"}},
+	})
+
+	nav.AttachPodHealth([]repository.PodInfo{
+		{Name: "api-1", Namespace: "default", Phase: "Running", Labels: map[string]string{"app": "api"},
+			Containers: []repository.ContainerInfo{{Name: "api", Ready: true}}},
+		{Name: "api-2", Namespace: "default", Phase: "Running", Labels: map[string]string{"app": "api"},
+			Containers: []repository.ContainerInfo{{Name: "api", Ready: true}}},
+		{Name: "web-1", Namespace: "default", Phase: "Pending", Labels: map[string]string{"app": "web"}},
+	})
+
+	if h := nav.workloads[0].Health; h == nil || h.Total != 2 || h.Ready != 2 {
+		t.Errorf("api health = %+v, want 2/2 ready", h)
+	}
+	if h := nav.workloads[1].Health; h == nil || h.Total != 1 || h.Pending != 1 {
+		t.Errorf("web health = %+v, want 1 pending", h)
+	}
+}
+
+func TestNavigator_SetHPAs_KeepsSelection(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetHPAs([]repository.HPAInfo{{Name: "api"}, {Name: "web"}})
+	nav.sectionCursors[SectionHPAs] = 1
+
+	nav.SetHPAs([]repository.HPAInfo{{Name: "web"}, {Name: "api"}, {Name: "worker"}})
+	if h := nav.SelectedHPA(); h == nil || h.Name != "web" {
+		t.Errorf("SelectedHPA() = %v, want web after reordering", h)
+	}
+}
+
 func TestNavigator_SetNamespaces(t *testing.T) {
 	nav := NewNavigator()
 	namespaces := []repository.NamespaceInfo{
@@ -2663,6 +4287,9 @@ func TestPodActions_MultiContainer(t *testing.T) {
 	execCount := 0
 	for _, item := range items {
 		if item.Action == "exec" && strings.Contains(item.Label, "Exec into") {
+			if item.Target != containers[execCount] {
+				t.Errorf("exec item %q targets %q, want %q", item.Label, item.Target, containers[execCount])
+			}
 			execCount++
 		}
 	}
@@ -2692,543 +4319,3349 @@ func TestPodActions_NoContainers(t *testing.T) {
 	}
 }
 
-// ============================================
-// ActionMenu Update Tests
-// ============================================
+func TestSchedulingGateActions(t *testing.T) {
+	gates := []string{"example.com/quota", "example.com/infra-approval"}
+	items := SchedulingGateActions("default", "gated-pod", gates)
 
-func TestActionMenu_Update_EnterSelection(t *testing.T) {
-	menu := NewActionMenu()
-	menu.Show("Test Menu", []MenuItem{
-		{Label: "Option 1", Value: "opt1"},
-		{Label: "Option 2", Value: "opt2"},
-	})
+	if len(items) != len(gates) {
+		t.Fatalf("SchedulingGateActions() returned %d items, want %d", len(items), len(gates))
+	}
+	for i, item := range items {
+		if item.Action != "remove-gate" {
+			t.Errorf("items[%d].Action = %q, want 'remove-gate'", i, item.Action)
+		}
+		if item.Target != gates[i] {
+			t.Errorf("items[%d].Target = %q, want %q", i, item.Target, gates[i])
+		}
+	}
+	if !strings.Contains(items[1].Command, "/spec/schedulingGates/1") {
+		t.Errorf("items[1].Command = %q, want gate index 1", items[1].Command)
+	}
 
-	// Press Enter to select
-	menu, cmd := menu.Update(tea.KeyMsg{Type: tea.KeyEnter})
-	if cmd == nil {
-		t.Error("Enter key should return selection command")
+	if items := SchedulingGateActions("default", "pod", nil); len(items) != 0 {
+		t.Errorf("SchedulingGateActions() with no gates returned %d items", len(items))
 	}
 }
 
-// ============================================
-// PodActionMenu Extended Tests
-// ============================================
-
-func TestPodActionMenu_Update_EnterKey(t *testing.T) {
-	menu := NewPodActionMenu()
-	menu.Show("test-pod", []PodActionItem{
-		{Label: "Delete", Action: "delete"},
-		{Label: "Exec", Action: "exec"},
-	})
+func TestRolloutActions(t *testing.T) {
+	items := RolloutActions("default", "web")
 
-	// Press Enter to select
-	menu, cmd := menu.Update(tea.KeyMsg{Type: tea.KeyEnter})
-	if cmd == nil {
-		t.Error("Enter key should return selection command")
+	var actions []string
+	for _, item := range items {
+		actions = append(actions, item.Action)
+	}
+	if strings.Join(actions, ",") != "promote,abort,retry,copy" {
+		t.Errorf("actions = %v, want promote, abort, retry and copy", actions)
+	}
+	if items[3].Command != "kubectl argo rollouts promote web -n default" {
+		t.Errorf("copy command = %q", items[3].Command)
 	}
 }
 
-func TestPodActionMenu_Update_UpKey(t *testing.T) {
-	menu := NewPodActionMenu()
-	menu.Show("test-pod", []PodActionItem{
-		{Label: "Delete", Action: "delete"},
-		{Label: "Exec", Action: "exec"},
-	})
-
-	// Navigate down first
-	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyDown})
+func TestCronJobActions(t *testing.T) {
+	items := CronJobActions("batch", "nightly-report", false)
+	if len(items) != 3 || items[0].Action != "trigger" || items[1].Action != "runs" || items[2].Action != "copy" {
+		t.Fatalf("CronJobActions() = %+v, want trigger, runs and copy", items)
+	}
+	if items[2].Command != "kubectl create job --from=cronjob/nightly-report nightly-report-manual -n batch" {
+		t.Errorf("copy command = %q", items[1].Command)
+	}
 
-	// Navigate up
-	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyUp})
+	suspended := CronJobActions("batch", "nightly-report", true)
+	if !strings.Contains(suspended[0].Description, "suspended") {
+		t.Errorf("Description = %q, want a suspended warning", suspended[0].Description)
+	}
 }
 
-func TestPodActionMenu_Update_KKey(t *testing.T) {
-	menu := NewPodActionMenu()
-	menu.Show("test-pod", []PodActionItem{
-		{Label: "Delete", Action: "delete"},
-		{Label: "Exec", Action: "exec"},
-	})
-
-	// Navigate down with j
-	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+func TestRevisionActions(t *testing.T) {
+	history := []repository.RevisionInfo{
+		{Revision: 4, Images: []string{"web:4"}, Replicas: 3, ReadyReplicas: 3, Age: "1h", Current: true},
+		{Revision: 2, Images: []string{"web:2", "proxy:1"}, Age: "3d", ChangeCause: "bump proxy"},
+	}
+	items := RevisionActions("default", "web", history)
+	if len(items) != 3 || items[2].Action != "copy" {
+		t.Fatalf("RevisionActions() = %+v, want two revisions and copy", items)
+	}
+	if items[0].Action != "" || !strings.HasPrefix(items[0].Description, "current") {
+		t.Errorf("current revision = %+v, want it shown but not a rollback target", items[0])
+	}
+	if items[1].Action != "rollback" || items[1].Revision != 2 {
+		t.Errorf("items[1] = %+v, want rollback to revision 2", items[1])
+	}
+	if !strings.Contains(items[1].Label, "web:2, proxy:1") || !strings.Contains(items[1].Description, "bump proxy") {
+		t.Errorf("items[1] = %+v, want images and change cause", items[1])
+	}
+	if items[2].Command != "kubectl rollout undo deployment/web -n default --to-revision=" {
+		t.Errorf("copy command = %q", items[2].Command)
+	}
+}
 
-	// Navigate up with k
-	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+func TestResourceYAMLActions(t *testing.T) {
+	items := ResourceYAMLActions("default", "Deployment", "web")
+	if len(items) != 2 || items[0].Action != "copy-yaml" || items[1].Action != "save-yaml" {
+		t.Fatalf("ResourceYAMLActions() = %+v, want copy and save", items)
+	}
+	for _, item := range items {
+		if item.Target != "Deployment/web" {
+			t.Errorf("target = %q, want Deployment/web", item.Target)
+		}
+	}
+	if items[0].Command != "kubectl get deployment -n default web -o yaml" {
+		t.Errorf("command = %q", items[0].Command)
+	}
+	if items := ResourceYAMLActions("default", "Secret", "creds"); len(items) != 0 {
+		t.Errorf("secrets should not be exportable, got %+v", items)
+	}
+	if items := ResourceYAMLActions("default", "", ""); len(items) != 0 {
+		t.Errorf("pods without owner should add no owner items, got %+v", items)
+	}
+	if items := WorkloadYAMLActions("default", "StatefulSet", "db"); len(items) != 2 || items[1].Action != "save-yaml" {
+		t.Errorf("WorkloadYAMLActions() = %+v, want copy and save", items)
+	}
 }
 
-func TestPodActionMenu_Update_QKey(t *testing.T) {
-	menu := NewPodActionMenu()
-	menu.Show("test-pod", []PodActionItem{
-		{Label: "Delete", Action: "delete"},
-	})
+func TestDisableMutatingActions(t *testing.T) {
+	pods := DisableMutatingPodActions(PodActions("default", "web-1", []string{"app"}))
+	for _, item := range pods {
+		want := item.Action == "delete" || item.Action == "exec"
+		if item.Disabled != want {
+			t.Errorf("pod action %q Disabled = %v, want %v", item.Action, item.Disabled, want)
+		}
+	}
 
-	// Press q to close
-	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	workloads := DisableMutatingWorkloadActions(append(ScaleActions("default", "web", "deployments", 2), WorkloadYAMLActions("default", "Deployment", "web")...))
+	for _, item := range workloads {
+		want := item.Action == "scale" || item.Action == "restart"
+		if item.Disabled != want {
+			t.Errorf("workload action %q Disabled = %v, want %v", item.Action, item.Disabled, want)
+		}
+	}
 
-	if menu.IsVisible() {
-		t.Error("q key should close menu")
+	for _, item := range DisableMutatingNodeActions(NodeActions("node-1", false)) {
+		want := item.Action == "cordon" || item.Action == "drain"
+		if item.Disabled != want {
+			t.Errorf("node action %q Disabled = %v, want %v", item.Action, item.Disabled, want)
+		}
 	}
 }
 
-func TestPodActionMenu_View_Visible(t *testing.T) {
+func TestPodActionMenu_ReadOnly(t *testing.T) {
 	menu := NewPodActionMenu()
-	menu.Show("test-pod", []PodActionItem{
-		{Label: "Delete Pod", Action: "delete", Description: "removes pod"},
-		{Label: "Exec into pod", Action: "exec", Description: "opens shell"},
+	menu.SetReadOnly(true)
+	menu.Show("Pod Actions", []PodActionItem{
+		{Label: "Delete Pod", Action: "delete"},
+		{Label: "Copy logs command", Action: "copy", Command: "kubectl logs web-1"},
 	})
 
 	view := menu.View()
-	if view == "" {
-		t.Error("Visible PodActionMenu View() should not return empty string")
+	if !strings.Contains(view, "Delete Pod") || !strings.Contains(view, "(read-only)") {
+		t.Errorf("disabled item should be listed with a (read-only) suffix, got:\n%s", view)
 	}
-	if !strings.Contains(view, "test-pod") {
-		t.Error("View should contain pod name")
+
+	// Disabled items can't be chosen, by Enter or by number
+	menu, cmd := menu.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || !menu.IsVisible() {
+		t.Error("selecting a disabled item should do nothing")
+	}
+	menu, cmd = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	if cmd != nil || !menu.IsVisible() {
+		t.Error("number shortcut of a disabled item should do nothing")
 	}
-}
 
-// ============================================
-// WorkloadActionMenu Extended Tests
-// ============================================
-
-func TestWorkloadActionMenu_Update_EnterKey(t *testing.T) {
-	menu := NewWorkloadActionMenu()
-	menu.Show("test-deployment", []WorkloadActionItem{
-		{Label: "Scale to 0", Action: "scale", Replicas: 0},
-		{Label: "Scale to 1", Action: "scale", Replicas: 1},
-	})
-
-	// Press Enter to select
-	menu, cmd := menu.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	// Other items still work
+	_, cmd = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
 	if cmd == nil {
-		t.Error("Enter key should return selection command")
+		t.Fatal("selecting an enabled item should return a command")
+	}
+	if result, ok := cmd().(PodActionMenuResult); !ok || result.Item.Action != "copy" {
+		t.Errorf("result = %+v, want the copy action", cmd())
+	}
+
+	// Without read-only mode nothing is disabled
+	menu = NewPodActionMenu()
+	menu.Show("Pod Actions", []PodActionItem{{Label: "Delete Pod", Action: "delete"}})
+	if strings.Contains(menu.View(), "(read-only)") {
+		t.Error("items should not be disabled outside read-only mode")
 	}
 }
 
-func TestWorkloadActionMenu_Update_UpKey(t *testing.T) {
-	menu := NewWorkloadActionMenu()
-	menu.Show("test-deployment", []WorkloadActionItem{
-		{Label: "Scale to 0", Action: "scale", Replicas: 0},
-		{Label: "Scale to 1", Action: "scale", Replicas: 1},
-	})
+func TestSnapshotAction(t *testing.T) {
+	items := SnapshotAction("web-1")
+	if len(items) != 1 || items[0].Action != "export-snapshot" || items[0].Target != "web-1" {
+		t.Fatalf("SnapshotAction() = %+v, want one export-snapshot item", items)
+	}
+	if items := SnapshotAction(""); len(items) != 0 {
+		t.Errorf("SnapshotAction(\"\") = %+v, want none", items)
+	}
+}
 
-	// Navigate down first
-	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyDown})
+func TestFileBrowserActions(t *testing.T) {
+	items := FileBrowserActions([]string{"app"})
+	if len(items) != 1 || items[0].Action != "browse-files" || items[0].Target != "app" || items[0].Label != "Browse files" {
+		t.Fatalf("FileBrowserActions() = %+v, want one browse-files item", items)
+	}
+	items = FileBrowserActions([]string{"app", "sidecar"})
+	if len(items) != 2 || items[1].Label != "Browse files in 'sidecar'" {
+		t.Errorf("FileBrowserActions() = %+v, want one item per container", items)
+	}
+	if items := DisableMutatingPodActions(items); !items[0].Disabled {
+		t.Error("browse-files should be disabled in read-only mode, it runs through exec")
+	}
+}
 
-	// Navigate up
-	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyUp})
+func TestAttachActions(t *testing.T) {
+	containers := []repository.ContainerInfo{{Name: "repl", State: "Running"}, {Name: "init-db", State: "Terminated"}}
+	items := AttachActions("default", "repl-0", containers)
+	if len(items) != 1 || items[0].Action != "attach" || items[0].Target != "repl" || items[0].Label != "Attach to 'repl'" {
+		t.Fatalf("AttachActions() = %+v, want one attach item for the running container", items)
+	}
+	if items[0].Command != "kubectl attach -it -n default repl-0 -c repl" {
+		t.Errorf("command = %q", items[0].Command)
+	}
+	if items := DisableMutatingPodActions(items); !items[0].Disabled {
+		t.Error("attach should be disabled in read-only mode, it sends input to the process")
+	}
+	if items := AttachActions("default", "repl-0", containers[1:]); len(items) != 0 {
+		t.Errorf("AttachActions() = %+v, want none for a stopped container", items)
+	}
 }
 
-func TestWorkloadActionMenu_Update_JKKeys(t *testing.T) {
-	menu := NewWorkloadActionMenu()
-	menu.Show("test-deployment", []WorkloadActionItem{
-		{Label: "Scale to 0", Action: "scale", Replicas: 0},
-		{Label: "Scale to 1", Action: "scale", Replicas: 1},
-	})
+func TestContainerResourceActions(t *testing.T) {
+	containers := []repository.ContainerResources{
+		{Container: "migrate", Init: true},
+		{Container: "app", Requests: repository.ResourceAmounts{CPU: "100m", Memory: "128Mi"}, Limits: repository.ResourceAmounts{Memory: "256Mi"}},
+	}
+	items := ContainerResourceActions("default", "Deployment", "web", containers)
+	if len(items) != 4 || items[0].Action != "resources-table" || items[1].Label != "Edit migrate (init)" {
+		t.Fatalf("ContainerResourceActions() = %+v, want the table, one item per container and the command", items)
+	}
+	if items[2].Action != "set-resources" || items[2].Resources != &containers[1] || items[2].Description != "cpu=100m/- memory=128Mi/256Mi" {
+		t.Errorf("items[2] = %+v", items[2])
+	}
+	if items := DisableMutatingWorkloadActions(items); items[0].Disabled || !items[2].Disabled {
+		t.Error("in read-only mode the table should stay available and editing be disabled")
+	}
+}
 
-	// Navigate with j/k
-	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
-	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+func TestParseResourceInput(t *testing.T) {
+	requests := repository.ResourceAmounts{CPU: "100m", Memory: "128Mi"}
+	limits := repository.ResourceAmounts{CPU: "500m", Memory: "256Mi"}
+	tests := []struct {
+		input        string
+		wantRequests repository.ResourceAmounts
+		wantLimits   repository.ResourceAmounts
+		wantErr      bool
+	}{
+		{FormatResourceInput(requests, limits), requests, limits, false},
+		{"cpu=250m/-", repository.ResourceAmounts{CPU: "250m", Memory: "128Mi"}, repository.ResourceAmounts{Memory: "256Mi"}, false},
+		{"mem=512Mi/1Gi", repository.ResourceAmounts{CPU: "100m", Memory: "512Mi"}, repository.ResourceAmounts{CPU: "500m", Memory: "1Gi"}, false},
+		{"memory=200Mi", repository.ResourceAmounts{CPU: "100m", Memory: "200Mi"}, limits, false},
+		{"memory=300Mi", requests, limits, true},
+		{"cpu=1/500m", requests, limits, true},
+		{"memory=lots/-", requests, limits, true},
+		{"gpu=1/1", requests, limits, true},
+		{"cpu", requests, limits, true},
+	}
+	for _, tt := range tests {
+		gotRequests, gotLimits, err := ParseResourceInput(tt.input, requests, limits)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseResourceInput(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (gotRequests != tt.wantRequests || gotLimits != tt.wantLimits) {
+			t.Errorf("ParseResourceInput(%q) = %+v, %+v; want %+v, %+v", tt.input, gotRequests, gotLimits, tt.wantRequests, tt.wantLimits)
+		}
+	}
 }
 
-func TestWorkloadActionMenu_Update_QKey(t *testing.T) {
-	menu := NewWorkloadActionMenu()
-	menu.Show("test-deployment", []WorkloadActionItem{
-		{Label: "Scale to 0", Action: "scale", Replicas: 0},
-	})
+func TestResourceChanges(t *testing.T) {
+	got := ResourceChanges(
+		repository.ResourceAmounts{CPU: "100m", Memory: "128Mi"}, repository.ResourceAmounts{CPU: "500m", Memory: "256Mi"},
+		repository.ResourceAmounts{CPU: "250m", Memory: "128Mi"}, repository.ResourceAmounts{Memory: "256Mi"})
+	want := []string{"cpu request: 100m → 250m", "cpu limit: 500m → none"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ResourceChanges() = %q, want %q", got, want)
+	}
+}
 
-	// Press q to close
-	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+func TestRenderWorkloadResources(t *testing.T) {
+	containers := []repository.ContainerResources{{
+		Container: "app",
+		Requests:  repository.ResourceAmounts{CPU: "100m", Memory: "128Mi"},
+		Limits:    repository.ResourceAmounts{Memory: "256Mi"},
+		Pods:      2, Sampled: 2, CPUMilli: 300, MemoryBytes: 250 * 1024 * 1024,
+		Restarts: 4, OOMKills: 1,
+	}}
+	out := stripAnsiCodes(RenderWorkloadResources(containers))
+	for _, want := range []string{"CPU REQ/LIM", "100m/none", "300m", "128Mi/256Mi", "250Mi", "highest among the workload's pods"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderWorkloadResources() missing %q:\n%s", want, out)
+		}
+	}
+	containers[0].Sampled = 0
+	if out := stripAnsiCodes(RenderWorkloadResources(containers)); !strings.Contains(out, "No usage") {
+		t.Errorf("without metrics the table should say so:\n%s", out)
+	}
+}
+
+func TestProbeRunActions(t *testing.T) {
+	probes := []repository.ProbeStatus{
+		{Container: "app", Probe: "Liveness", Config: &repository.ProbeInfo{Type: "HTTP", Path: "/healthz", PortName: "http"}},
+		{Container: "app", Probe: "Readiness", Config: &repository.ProbeInfo{Type: "TCP", Port: 5432}},
+	}
+	items := ProbeRunActions(probes)
+	if len(items) != 2 || items[0].Label != "Run liveness probe now" || items[0].Target != "app/Liveness" || items[0].Description != "/healthz:http" {
+		t.Fatalf("ProbeRunActions() = %+v, want one action per probe", items)
+	}
+	if items := DisableMutatingPodActions(items); !items[0].Disabled {
+		t.Error("running a probe should be disabled in read-only mode, it execs into the pod")
+	}
+	probes = append(probes, repository.ProbeStatus{Container: "sidecar", Probe: "Readiness", Config: &repository.ProbeInfo{Type: "gRPC", Port: 9090}})
+	if items := ProbeRunActions(probes); items[2].Label != "Run readiness probe of 'sidecar'" {
+		t.Errorf("label = %q, want the container named", items[2].Label)
+	}
+}
+
+func TestRenderProbeRun(t *testing.T) {
+	config := &repository.ProbeInfo{Type: "HTTP", Path: "/ready", Port: 8080, Timeout: 2}
+	result := &repository.ProbeRunResult{
+		Tool:       "wget",
+		Outcome:    repository.ProbeBadStatus,
+		StatusCode: 503,
+		Body:       `{"db":"down"}`,
+		Truncated:  true,
+		Detail:     "wget: server returned error: HTTP/1.1 503 Service Unavailable",
+	}
+	out := stripAnsiCodes(RenderProbeRun("api", "Readiness", config, result, "k1s-netshoot-1"))
+	for _, want := range []string{
+		"Readiness probe of api",
+		"HTTP /ready:8080, timeout 2s",
+		"k1s-netshoot-1 (no probe tools in api)",
+		"bad status",
+		"Status:      503",
+		"Body (first 1024 bytes)",
+		`{"db":"down"}`,
+		"server returned error",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderProbeRun() missing %q:\n%s", want, out)
+		}
+	}
 
-	if menu.IsVisible() {
-		t.Error("q key should close menu")
+	exec := &repository.ProbeRunResult{Tool: "exec", Outcome: repository.ProbeBadStatus, StatusCode: 1, Body: "not ready\n"}
+	out = stripAnsiCodes(RenderProbeRun("api", "Liveness", nil, exec, ""))
+	if !strings.Contains(out, "Exit code:   1") || !strings.Contains(out, "Output") || strings.Contains(out, "Latency") {
+		t.Errorf("exec probe result:\n%s", out)
 	}
 }
 
-func TestWorkloadActionMenu_View_Visible(t *testing.T) {
-	menu := NewWorkloadActionMenu()
-	menu.Show("web-deployment", []WorkloadActionItem{
-		{Label: "Scale to 0", Action: "scale", Replicas: 0},
-		{Label: "Restart", Action: "restart"},
-	})
+func TestDebugContainerActions(t *testing.T) {
+	items := DebugContainerActions("default", "web-1", []string{"app"}, nil)
+	if len(items) != len(repository.DebugImages) {
+		t.Fatalf("DebugContainerActions() = %+v, want one item per debug image", items)
+	}
+	if items[0].Label != "Debug shell (busybox)" || items[0].Target != "app/"+repository.BusyboxImage {
+		t.Errorf("items[0] = %+v, want the busybox debug shell for app", items[0])
+	}
+	if items[1].Label != "Debug shell (netshoot)" || items[1].Command != "kubectl debug -it -n default web-1 --image="+repository.NetshootImage+" --target=app" {
+		t.Errorf("items[1] = %+v, want the netshoot debug shell", items[1])
+	}
 
-	view := menu.View()
-	if view == "" {
-		t.Error("Visible WorkloadActionMenu View() should not return empty string")
+	items = DebugContainerActions("default", "web-1", []string{"app", "sidecar"}, []repository.ContainerInfo{
+		{Name: "k1s-debug-1", State: "Running"},
+		{Name: "k1s-debug-0", State: "Terminated"},
+	})
+	if items[0].Action != "exec" || items[0].Target != "k1s-debug-1" || items[0].Label != "Exec into 'k1s-debug-1' (ephemeral)" {
+		t.Errorf("items[0] = %+v, want exec into the running debug container", items[0])
 	}
-	if !strings.Contains(view, "web-deployment") {
-		t.Error("View should contain workload name")
+	if len(items) != 1+2*len(repository.DebugImages) || items[len(items)-1].Label != "Debug 'sidecar' (netshoot)" {
+		t.Errorf("DebugContainerActions() = %+v, want the running debug container then each container and image", items)
+	}
+	for _, item := range DisableMutatingPodActions(items) {
+		if !item.Disabled {
+			t.Errorf("%q should be disabled in read-only mode", item.Label)
+		}
 	}
 }
 
-// ============================================
-// ConfigMapViewer Extended Tests
-// ============================================
+func TestRestartActions(t *testing.T) {
+	if items := RestartActions("default", "standalone", "", ""); len(items) != 0 {
+		t.Errorf("RestartActions() = %+v, want none for a pod without a workload", items)
+	}
 
-func TestConfigMapViewer_Update_EnterKey(t *testing.T) {
-	cmv := NewConfigMapViewer()
-	cmv.SetSize(80, 40)
-	cmv.Show(&repository.ConfigMapData{
-		Name:      "test-cm",
-		Namespace: "default",
-		Data: map[string]string{
-			"key1": "value1",
-		},
-	}, "default")
+	items := RestartActions("default", "web-7d9f-abcde", "Deployment", "web")
+	if len(items) != 2 {
+		t.Fatalf("RestartActions() returned %d items, want 2", len(items))
+	}
+	if items[0].Action != "restart-pod" || items[0].Label != "Restart pod (delete & re-attach)" {
+		t.Errorf("items[0] = %+v, want the single pod restart", items[0])
+	}
+	if items[1].Action != "restart-workload" || items[1].Label != "Restart workload (rollout)" || items[1].Target != "Deployment/web" {
+		t.Errorf("items[1] = %+v, want the rollout restart of Deployment/web", items[1])
+	}
+	if items[1].Command != "kubectl rollout restart -n default deployment/web" {
+		t.Errorf("items[1].Command = %q", items[1].Command)
+	}
 
-	// Press Enter (copy to clipboard)
-	cmv, _ = cmv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	// Jobs can't be rolled out, but their pods can still be replaced
+	if items := RestartActions("default", "backup-x1", "Job", "backup"); len(items) != 1 || items[0].Action != "restart-pod" {
+		t.Errorf("RestartActions(Job) = %+v, want only the pod restart", items)
+	}
+
+	for _, item := range DisableMutatingPodActions(items) {
+		if !item.Disabled {
+			t.Errorf("%s should be disabled in read-only mode", item.Action)
+		}
+	}
 }
 
-func TestConfigMapViewer_Update_GKey(t *testing.T) {
-	cmv := NewConfigMapViewer()
-	cmv.SetSize(80, 40)
-	cmv.Show(&repository.ConfigMapData{
-		Name:      "test-cm",
-		Namespace: "default",
-		Data: map[string]string{
-			"key1": "value1",
-			"key2": "value2",
-		},
-	}, "default")
+func TestFileBrowser_Navigate(t *testing.T) {
+	f := NewFileBrowser()
+	f.SetSize(120, 40)
+	cmd := f.Show("default", "web-1", "app", "/")
+	if req, ok := cmd().(FileBrowserListRequest); !ok || req.Path != "/" {
+		t.Fatalf("Show() cmd = %v, want a list request for /", cmd())
+	}
+	f.SetEntries("/", []repository.ContainerFile{
+		{Name: "etc", Path: "/etc", Mode: "drwxr-xr-x", IsDir: true},
+		{Name: "hostname", Path: "/hostname", Mode: "-rw-r--r--", Size: 6},
+	}, nil)
 
-	// Press g to go to top
-	cmv, _ = cmv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	f, cmd = f.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if req, ok := cmd().(FileBrowserListRequest); !ok || req.Path != "/etc" {
+		t.Fatalf("enter on a directory = %v, want a list request for /etc", cmd())
+	}
+	f.SetEntries("/etc", []repository.ContainerFile{{Name: "hosts", Path: "/etc/hosts", Mode: "-rw-r--r--"}}, nil)
 
-	// Press G to go to bottom
-	cmv, _ = cmv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	f, cmd = f.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if req, ok := cmd().(FileBrowserReadRequest); !ok || req.Path != "/etc/hosts" {
+		t.Fatalf("enter on a file = %v, want a read request for /etc/hosts", cmd())
+	}
+	f.SetPreview("/etc/hosts", &repository.ContainerFileContent{Data: []byte("127.0.0.1 localhost\n")}, nil)
+	if view := f.View(); !strings.Contains(view, "127.0.0.1 localhost") {
+		t.Errorf("preview should show the file, got:\n%s", view)
+	}
+
+	// Back to the listing, then up to the parent
+	f, _ = f.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	f, cmd = f.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if req, ok := cmd().(FileBrowserListRequest); !ok || req.Path != "/" {
+		t.Fatalf("backspace = %v, want a list request for /", cmd())
+	}
+	f.SetEntries("/", []repository.ContainerFile{
+		{Name: "etc", Path: "/etc", IsDir: true},
+		{Name: "hostname", Path: "/hostname"},
+	}, nil)
+	if e := f.Selected(); e == nil || e.Name != "etc" {
+		t.Errorf("Selected() = %v, want the directory just left", e)
+	}
 }
 
-func TestConfigMapViewer_Update_PageKeys(t *testing.T) {
-	cmv := NewConfigMapViewer()
-	cmv.SetSize(80, 20)
-	cmv.Show(&repository.ConfigMapData{
-		Name:      "test-cm",
-		Namespace: "default",
-		Data: map[string]string{
-			"key1": "long value " + strings.Repeat("x", 500),
-			"key2": "value2",
-		},
-	}, "default")
+func TestFileBrowser_ErrorsInline(t *testing.T) {
+	f := NewFileBrowser()
+	f.SetSize(120, 40)
+	f.Show("default", "web-1", "app", "/")
+	f.SetEntries("/", []repository.ContainerFile{{Name: "gone", Path: "/gone", IsDir: true}}, nil)
 
-	// Press PgDn
-	cmv, _ = cmv.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	f.SetEntries("/gone", nil, fmt.Errorf("/gone: %w", repository.ErrFileNotFound))
+	if !f.IsVisible() {
+		t.Fatal("an error should not close the browser")
+	}
+	view := f.View()
+	if !strings.Contains(view, "no such file or directory") || !strings.Contains(view, "gone/") {
+		t.Errorf("view should keep the listing and show the error, got:\n%s", view)
+	}
+}
 
-	// Press PgUp
-	cmv, _ = cmv.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+func TestFileBrowser_BinaryPreview(t *testing.T) {
+	f := NewFileBrowser()
+	f.SetSize(120, 40)
+	f.Show("default", "web-1", "app", "/bin")
+	f.SetPreview("/bin/app", &repository.ContainerFileContent{Data: []byte("\x7fELF\x00"), Binary: true}, nil)
+	if view := f.View(); !strings.Contains(view, "Binary file") {
+		t.Errorf("binary files should not be previewed, got:\n%s", view)
+	}
+
+	_, cmd := f.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if req, ok := cmd().(FileBrowserCopyRequest); !ok || req.Path != "/bin/app" {
+		t.Errorf("c = %v, want a copy request for /bin/app", cmd())
+	}
 }
 
-// ============================================
-// SecretViewer Extended Tests
-// ============================================
+func TestInputDialog(t *testing.T) {
+	d := NewInputDialog()
+	d.Show("Save Pod YAML", "Write to file:", "save_yaml", "web.yaml", "data")
+	if !d.IsVisible() || !strings.Contains(d.View(), "web.yaml") {
+		t.Fatalf("dialog should be visible with the default value, got:\n%s", d.View())
+	}
 
-func TestSecretViewer_Update_EnterKey(t *testing.T) {
-	sv := NewSecretViewer()
-	sv.SetSize(80, 40)
-	sv.Show(&repository.SecretData{
-		Name:      "test-secret",
-		Namespace: "default",
-		Data: map[string]string{
-			"username": "admin",
-		},
-	}, "default")
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	for _, r := range "/tmp/x.ym" {
+		d, _ = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a', 'm', 'l'}})
+	if d.Value() != "/tmp/x.yaml" {
+		t.Errorf("Value() = %q, want /tmp/x.yaml", d.Value())
+	}
 
-	// Press Enter (copy to clipboard)
-	sv, _ = sv.Update(tea.KeyMsg{Type: tea.KeyEnter})
-}
+	d, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if d.IsVisible() || cmd == nil {
+		t.Fatal("Enter should close the dialog and return the result")
+	}
+	result, ok := cmd().(InputDialogResult)
+	if !ok || result.Action != "save_yaml" || result.Value != "/tmp/x.yaml" || result.Data != "data" {
+		t.Errorf("result = %+v", result)
+	}
 
-func TestSecretViewer_Update_GKey(t *testing.T) {
-	sv := NewSecretViewer()
-	sv.SetSize(80, 40)
-	sv.Show(&repository.SecretData{
-		Name:      "test-secret",
-		Namespace: "default",
-		Data: map[string]string{
-			"username": "admin",
-			"password": "secret",
-		},
-	}, "default")
+	// Empty input can't be submitted, Esc cancels without a result
+	d.Show("Save", "Path:", "save_yaml", "", nil)
+	if d, cmd = d.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !d.IsVisible() {
+		t.Error("Enter on empty input should do nothing")
+	}
+	if d, cmd = d.Update(tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil || d.IsVisible() {
+		t.Error("Esc should close the dialog without a result")
+	}
+}
 
-	// Press g to go to top
-	sv, _ = sv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+func TestRolloutOwnerActions(t *testing.T) {
+	items := RolloutOwnerActions("Rollout", "web")
+	if len(items) != 1 || items[0].Action != "rollout-actions" || items[0].Target != "web" {
+		t.Errorf("RolloutOwnerActions() = %+v, want one rollout-actions item for web", items)
+	}
+	if items := RolloutOwnerActions("Deployment", "web"); len(items) != 0 {
+		t.Errorf("RolloutOwnerActions() for a Deployment returned %d items", len(items))
+	}
+}
 
-	// Press G to go to bottom
-	sv, _ = sv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+func TestHPADetailActions(t *testing.T) {
+	related := &repository.RelatedResources{HPA: &repository.HPAInfo{Name: "web-hpa"}}
+	items := HPADetailActions("default", related)
+	if len(items) != 1 || items[0].Action != "hpa-details" || items[0].Target != "web-hpa" {
+		t.Errorf("HPADetailActions() = %+v, want hpa-details for web-hpa", items)
+	}
+	if items := HPADetailActions("default", &repository.RelatedResources{}); len(items) != 0 {
+		t.Errorf("HPADetailActions() without an HPA returned %d items", len(items))
+	}
 }
 
-// ============================================
-// DockerRegistryViewer Extended Tests
-// ============================================
+func TestPVCDetailActions(t *testing.T) {
+	volumes := []repository.VolumeInfo{
+		{Name: "data", Type: "PVC", Source: "data-claim"},
+		{Name: "config", Type: "ConfigMap", Source: "app-config"},
+		{Name: "tmp", Type: "EmptyDir"},
+	}
+	items := PVCDetailActions("default", volumes)
 
-func TestDockerRegistryViewer_Update_EnterKey(t *testing.T) {
-	drv := NewDockerRegistryViewer()
-	drv.SetSize(80, 40)
-	drv.Show(&repository.SecretData{
-		Name:      "registry-secret",
-		Namespace: "default",
-		Data: map[string]string{
-			".dockerconfigjson": `{"auths":{"registry.io":{"auth":"dXNlcjpwYXNz"}}}`,
-		},
-	}, "default")
+	if len(items) != 1 {
+		t.Fatalf("PVCDetailActions() returned %d items, want 1", len(items))
+	}
+	if items[0].Action != "pvc-details" || items[0].Target != "data-claim" {
+		t.Errorf("item = %+v, want pvc-details for data-claim", items[0])
+	}
+}
 
-	// Press Enter (copy to clipboard)
-	drv, _ = drv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+func TestShareLinkAction(t *testing.T) {
+	items := ShareLinkAction("k1s://dev/default/pod/web?view=logs")
+	if len(items) != 1 {
+		t.Fatalf("ShareLinkAction() returned %d items, want 1", len(items))
+	}
+	if items[0].Action != "copy" || items[0].Command != "k1s://dev/default/pod/web?view=logs" {
+		t.Errorf("item = %+v, want copy of the link", items[0])
+	}
+
+	if items := ShareLinkAction(""); len(items) != 0 {
+		t.Errorf("ShareLinkAction() with no link returned %d items", len(items))
+	}
 }
 
-func TestDockerRegistryViewer_Update_GKey(t *testing.T) {
-	drv := NewDockerRegistryViewer()
-	drv.SetSize(80, 40)
-	drv.Show(&repository.SecretData{
-		Name:      "registry-secret",
-		Namespace: "default",
-		Data: map[string]string{
-			".dockerconfigjson": `{"auths":{"registry.io":{"auth":"dXNlcjpwYXNz"}}}`,
+func TestRenderPVCDetails(t *testing.T) {
+	details := &repository.PVCDetails{
+		Name:             "data",
+		Status:           "Pending",
+		StorageClassName: "gp3",
+		StorageClass: &repository.StorageClassInfo{
+			Name:              "gp3",
+			Provisioner:       "ebs.csi.aws.com",
+			VolumeBindingMode: "WaitForFirstConsumer",
 		},
-	}, "default")
+		Events: []repository.EventInfo{
+			{Type: "Warning", Reason: "ProvisioningFailed", Message: "failed to provision volume: quota exceeded"},
+		},
+	}
 
-	// Press g to go to top
-	drv, _ = drv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	out := stripAnsiCodes(RenderPVCDetails(details))
+	for _, want := range []string{
+		"ebs.csi.aws.com",
+		"will not bind until a pod using it is scheduled",
+		"failed to provision volume: quota exceeded",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderPVCDetails() missing %q", want)
+		}
+	}
 
-	// Press G to go to bottom
-	drv, _ = drv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
-}
+	details.StorageClass = nil
+	out = stripAnsiCodes(RenderPVCDetails(details))
+	if !strings.Contains(out, "StorageClass 'gp3' not found") {
+		t.Error("RenderPVCDetails() should report missing storage class")
+	}
 
-// ============================================
-// Navigator Extended Tests
-// ============================================
+	if RenderPVCDetails(nil) == "" {
+		t.Error("RenderPVCDetails(nil) should render a placeholder")
+	}
+}
 
-func TestNavigator_Update_TabKey(t *testing.T) {
-	nav := NewNavigator()
-	nav.SetMode(ModeResources)
-	nav.SetPods([]repository.PodInfo{
-		{Name: "web-pod", Status: "Running"},
-	})
-	nav.SetHPAs([]repository.HPAInfo{
-		{Name: "web-hpa"},
-	})
+func TestNodeActions(t *testing.T) {
+	items := NodeActions("node-a", false)
+
+	if len(items) == 0 || items[0].Action != "simulate-drain" {
+		t.Fatalf("NodeActions() first item = %+v, want simulate-drain", items)
+	}
+	actions := make(map[string]bool)
+	for _, item := range items {
+		actions[item.Action] = true
+		if item.Node != "node-a" {
+			t.Errorf("%s: Node = %q, want node-a", item.Label, item.Node)
+		}
+	}
+	if !actions["cordon"] || actions["uncordon"] || !actions["drain"] {
+		t.Errorf("NodeActions() actions = %v, want cordon and drain", actions)
+	}
+
+	for _, item := range NodeActions("node-a", true) {
+		if item.Action == "cordon" {
+			t.Error("a cordoned node should offer uncordon, not cordon")
+		}
+	}
+}
+
+func TestRenderDrainProgress(t *testing.T) {
+	pods := []repository.DrainPodProgress{
+		{Namespace: "default", Name: "web-1", State: repository.DrainPodEvicted},
+		{Namespace: "default", Name: "db-0", State: repository.DrainPodBlocked, Reason: "blocked by PodDisruptionBudget db-pdb"},
+	}
+	out := stripAnsiCodes(RenderDrainProgress("node-a", pods, nil))
+	for _, want := range []string{"Draining node-a", "evicted   default/web-1", "blocked   default/db-0", "db-pdb"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderDrainProgress() running missing %q:\n%s", want, out)
+		}
+	}
+
+	failed := repository.DrainPodProgress{Namespace: "default", Name: "db-0", State: repository.DrainPodFailed, Reason: "blocked by PodDisruptionBudget db-pdb"}
+	result := &repository.DrainResult{
+		Node:    "node-a",
+		Evicted: []string{"default/web-1"},
+		Skipped: []string{"kube-system/fluentd"},
+		Failed:  []repository.DrainPodProgress{failed},
+	}
+	out = stripAnsiCodes(RenderDrainProgress("node-a", []repository.DrainPodProgress{pods[0], failed}, result))
+	for _, want := range []string{"Summary", "Could not evict:     1", "Not evicted", "• default/db-0", "stays cordoned"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderDrainProgress() finished missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Draining") {
+		t.Error("a finished drain should not say it is running")
+	}
+}
+
+func TestMetricsPanel_SelectedNode(t *testing.T) {
+	m := NewMetricsPanel()
+	m.SetSize(100, 20)
+	m.SetPod(&repository.PodInfo{Name: "web", Namespace: "default", Node: "node-a"})
+	m.SetNode(&repository.NodeInfo{Name: "node-a"})
+
+	if m.SelectedNode() != nil {
+		t.Error("SelectedNode() should be nil while Container Resources is focused")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if node := m.SelectedNode(); node == nil || node.Name != "node-a" {
+		t.Errorf("SelectedNode() = %v, want node-a once Node Info is focused", node)
+	}
+}
+
+func TestNodeActionMenu_Update_Shortcut(t *testing.T) {
+	menu := NewNodeActionMenu()
+	menu.Show("Node: node-a", NodeActions("node-a", false))
+
+	menu, cmd := menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	if cmd == nil {
+		t.Fatal("number shortcut should return selection command")
+	}
+	result, ok := cmd().(NodeActionMenuResult)
+	if !ok || result.Item.Action != "simulate-drain" {
+		t.Errorf("result = %+v, want simulate-drain", result)
+	}
+	if menu.IsVisible() {
+		t.Error("menu should close after selection")
+	}
+}
+
+func TestRenderDrainSimulation(t *testing.T) {
+	sim := &repository.DrainSimulation{
+		Node: "node-a",
+		Pods: []repository.DrainPodPlan{
+			{Namespace: "default", Name: "web", CPURequest: 500, MemoryRequest: 256 * 1024 * 1024, Status: repository.DrainReschedulable, TargetNode: "node-b"},
+			{Namespace: "default", Name: "gpu-job", CPURequest: 2000, Status: repository.DrainNoCompatibleNode},
+		},
+		Skipped: []string{"kube-system/fluentd-abc"},
+	}
+
+	out := stripAnsiCodes(RenderDrainSimulation(sim))
+	for _, want := range []string{
+		"Estimate",
+		"default/web",
+		"→ node-b",
+		"no compatible node",
+		"kube-system/fluentd-abc",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderDrainSimulation() missing %q", want)
+		}
+	}
+
+	if RenderDrainSimulation(nil) == "" {
+		t.Error("RenderDrainSimulation(nil) should render a placeholder")
+	}
+}
+
+// ============================================
+// ActionMenu Update Tests
+// ============================================
+
+func TestActionMenu_Update_EnterSelection(t *testing.T) {
+	menu := NewActionMenu()
+	menu.Show("Test Menu", []MenuItem{
+		{Label: "Option 1", Value: "opt1"},
+		{Label: "Option 2", Value: "opt2"},
+	})
+
+	// Press Enter to select
+	menu, cmd := menu.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Error("Enter key should return selection command")
+	}
+}
+
+// ============================================
+// PodActionMenu Extended Tests
+// ============================================
+
+func TestPodActionMenu_Update_EnterKey(t *testing.T) {
+	menu := NewPodActionMenu()
+	menu.Show("test-pod", []PodActionItem{
+		{Label: "Delete", Action: "delete"},
+		{Label: "Exec", Action: "exec"},
+	})
+
+	// Press Enter to select
+	menu, cmd := menu.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Error("Enter key should return selection command")
+	}
+}
+
+func TestPodActionMenu_Update_UpKey(t *testing.T) {
+	menu := NewPodActionMenu()
+	menu.Show("test-pod", []PodActionItem{
+		{Label: "Delete", Action: "delete"},
+		{Label: "Exec", Action: "exec"},
+	})
+
+	// Navigate down first
+	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+	// Navigate up
+	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyUp})
+}
+
+func TestPodActionMenu_Update_KKey(t *testing.T) {
+	menu := NewPodActionMenu()
+	menu.Show("test-pod", []PodActionItem{
+		{Label: "Delete", Action: "delete"},
+		{Label: "Exec", Action: "exec"},
+	})
+
+	// Navigate down with j
+	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+
+	// Navigate up with k
+	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+}
+
+func TestPodActionMenu_Update_QKey(t *testing.T) {
+	menu := NewPodActionMenu()
+	menu.Show("test-pod", []PodActionItem{
+		{Label: "Delete", Action: "delete"},
+	})
+
+	// Press q to close
+	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+	if menu.IsVisible() {
+		t.Error("q key should close menu")
+	}
+}
+
+func TestPodActionMenu_View_Visible(t *testing.T) {
+	menu := NewPodActionMenu()
+	menu.Show("test-pod", []PodActionItem{
+		{Label: "Delete Pod", Action: "delete", Description: "removes pod"},
+		{Label: "Exec into pod", Action: "exec", Description: "opens shell"},
+	})
+
+	view := menu.View()
+	if view == "" {
+		t.Error("Visible PodActionMenu View() should not return empty string")
+	}
+	if !strings.Contains(view, "test-pod") {
+		t.Error("View should contain pod name")
+	}
+}
+
+// ============================================
+// WorkloadActionMenu Extended Tests
+// ============================================
+
+func TestWorkloadActionMenu_Update_EnterKey(t *testing.T) {
+	menu := NewWorkloadActionMenu()
+	menu.Show("test-deployment", []WorkloadActionItem{
+		{Label: "Scale to 0", Action: "scale", Replicas: 0},
+		{Label: "Scale to 1", Action: "scale", Replicas: 1},
+	})
+
+	// Press Enter to select
+	menu, cmd := menu.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Error("Enter key should return selection command")
+	}
+}
+
+func TestWorkloadActionMenu_Update_UpKey(t *testing.T) {
+	menu := NewWorkloadActionMenu()
+	menu.Show("test-deployment", []WorkloadActionItem{
+		{Label: "Scale to 0", Action: "scale", Replicas: 0},
+		{Label: "Scale to 1", Action: "scale", Replicas: 1},
+	})
+
+	// Navigate down first
+	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+	// Navigate up
+	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyUp})
+}
+
+func TestWorkloadActionMenu_Update_JKKeys(t *testing.T) {
+	menu := NewWorkloadActionMenu()
+	menu.Show("test-deployment", []WorkloadActionItem{
+		{Label: "Scale to 0", Action: "scale", Replicas: 0},
+		{Label: "Scale to 1", Action: "scale", Replicas: 1},
+	})
+
+	// Navigate with j/k
+	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+}
+
+func TestWorkloadActionMenu_Update_QKey(t *testing.T) {
+	menu := NewWorkloadActionMenu()
+	menu.Show("test-deployment", []WorkloadActionItem{
+		{Label: "Scale to 0", Action: "scale", Replicas: 0},
+	})
+
+	// Press q to close
+	menu, _ = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+	if menu.IsVisible() {
+		t.Error("q key should close menu")
+	}
+}
+
+func TestWorkloadActionMenu_View_Visible(t *testing.T) {
+	menu := NewWorkloadActionMenu()
+	menu.Show("web-deployment", []WorkloadActionItem{
+		{Label: "Scale to 0", Action: "scale", Replicas: 0},
+		{Label: "Restart", Action: "restart"},
+	})
+
+	view := menu.View()
+	if view == "" {
+		t.Error("Visible WorkloadActionMenu View() should not return empty string")
+	}
+	if !strings.Contains(view, "web-deployment") {
+		t.Error("View should contain workload name")
+	}
+}
+
+// ============================================
+// ConfigMapViewer Extended Tests
+// ============================================
+
+func TestConfigMapViewer_Update_EnterKey(t *testing.T) {
+	cmv := NewConfigMapViewer()
+	cmv.SetSize(80, 40)
+	cmv.Show(&repository.ConfigMapData{
+		Name:      "test-cm",
+		Namespace: "default",
+		Data: map[string]string{
+			"key1": "value1",
+		},
+	}, "default")
+
+	// Press Enter (copy to clipboard)
+	cmv, _ = cmv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestConfigMapViewer_Update_GKey(t *testing.T) {
+	cmv := NewConfigMapViewer()
+	cmv.SetSize(80, 40)
+	cmv.Show(&repository.ConfigMapData{
+		Name:      "test-cm",
+		Namespace: "default",
+		Data: map[string]string{
+			"key1": "value1",
+			"key2": "value2",
+		},
+	}, "default")
+
+	// Press g to go to top
+	cmv, _ = cmv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+
+	// Press G to go to bottom
+	cmv, _ = cmv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+}
+
+func TestConfigMapViewer_Update_PageKeys(t *testing.T) {
+	cmv := NewConfigMapViewer()
+	cmv.SetSize(80, 20)
+	cmv.Show(&repository.ConfigMapData{
+		Name:      "test-cm",
+		Namespace: "default",
+		Data: map[string]string{
+			"key1": "long value " + strings.Repeat("x", 500),
+			"key2": "value2",
+		},
+	}, "default")
+
+	// Press PgDn
+	cmv, _ = cmv.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+
+	// Press PgUp
+	cmv, _ = cmv.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+}
+
+// ============================================
+// SecretViewer Extended Tests
+// ============================================
+
+func TestSecretViewer_Update_EnterKey(t *testing.T) {
+	sv := NewSecretViewer()
+	sv.SetSize(80, 40)
+	sv.Show(&repository.SecretData{
+		Name:      "test-secret",
+		Namespace: "default",
+		Data: map[string]string{
+			"username": "admin",
+		},
+	}, "default")
+
+	// Press Enter (copy to clipboard)
+	sv, _ = sv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestSecretViewer_Update_GKey(t *testing.T) {
+	sv := NewSecretViewer()
+	sv.SetSize(80, 40)
+	sv.Show(&repository.SecretData{
+		Name:      "test-secret",
+		Namespace: "default",
+		Data: map[string]string{
+			"username": "admin",
+			"password": "secret",
+		},
+	}, "default")
+
+	// Press g to go to top
+	sv, _ = sv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+
+	// Press G to go to bottom
+	sv, _ = sv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+}
+
+// ============================================
+// DockerRegistryViewer Extended Tests
+// ============================================
+
+func TestDockerRegistryViewer_Update_EnterKey(t *testing.T) {
+	drv := NewDockerRegistryViewer()
+	drv.SetSize(80, 40)
+	drv.Show(&repository.SecretData{
+		Name:      "registry-secret",
+		Namespace: "default",
+		Data: map[string]string{
+			".dockerconfigjson": `{"auths":{"registry.io":{"auth":"dXNlcjpwYXNz"}}}`,
+		},
+	}, "default")
+
+	// Press Enter (copy to clipboard)
+	drv, _ = drv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestDockerRegistryViewer_Update_GKey(t *testing.T) {
+	drv := NewDockerRegistryViewer()
+	drv.SetSize(80, 40)
+	drv.Show(&repository.SecretData{
+		Name:      "registry-secret",
+		Namespace: "default",
+		Data: map[string]string{
+			".dockerconfigjson": `{"auths":{"registry.io":{"auth":"dXNlcjpwYXNz"}}}`,
+		},
+	}, "default")
+
+	// Press g to go to top
+	drv, _ = drv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+
+	// Press G to go to bottom
+	drv, _ = drv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+}
+
+// ============================================
+// Navigator Extended Tests
+// ============================================
+
+func TestNavigator_Update_TabKey(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetMode(ModeResources)
+	nav.SetPods([]repository.PodInfo{
+		{Name: "web-pod", Status: "Running"},
+	})
+	nav.SetHPAs([]repository.HPAInfo{
+		{Name: "web-hpa"},
+	})
 
 	// Press Tab to cycle sections
 	nav, _ = nav.Update(tea.KeyMsg{Type: tea.KeyTab})
 }
 
-func TestNavigator_Update_ShiftTabKey(t *testing.T) {
-	nav := NewNavigator()
-	nav.SetMode(ModeResources)
-	nav.SetPods([]repository.PodInfo{
-		{Name: "web-pod", Status: "Running"},
+func TestNavigator_Update_ShiftTabKey(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetMode(ModeResources)
+	nav.SetPods([]repository.PodInfo{
+		{Name: "web-pod", Status: "Running"},
+	})
+
+	// Press Shift+Tab to cycle sections backwards
+	nav, _ = nav.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+}
+
+func TestNavigator_ProbeHealthDrillDown(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetSize(120, 40)
+	nav.SetMode(ModeResources)
+	nav.SetPods([]repository.PodInfo{
+		{Name: "api-1", Status: "Running"},
+		{Name: "api-2", Status: "Running"},
+		{Name: "db-0", Status: "Running"},
+	})
+	nav.SetProbeHealth([]repository.WorkloadProbeHealth{
+		{Workload: "api", FailingContainers: 1, FlapRate: 2, Pods: []string{"api-2"}},
+	})
+
+	view := nav.View()
+	if !strings.Contains(view, "PROBE HEALTH") {
+		t.Error("View should show probe health summary")
+	}
+
+	nav, _ = nav.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if !nav.ProbeFilterActive() {
+		t.Fatal("'p' should enable probe failure drill-down")
+	}
+	if pod := nav.SelectedPod(); pod == nil || pod.Name != "api-2" {
+		t.Errorf("SelectedPod() = %v, want api-2", pod)
+	}
+
+	nav, _ = nav.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if nav.ProbeFilterActive() {
+		t.Error("second 'p' should disable probe failure drill-down")
+	}
+
+	// Drill-down is cleared once nothing is failing anymore
+	nav, _ = nav.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	nav.SetProbeHealth(nil)
+	if nav.ProbeFilterActive() {
+		t.Error("probe filter should be cleared when probe health is empty")
+	}
+	if strings.Contains(nav.View(), "PROBE HEALTH") {
+		t.Error("View should hide probe health summary when nothing is failing")
+	}
+}
+
+func TestMutations_PodDelete(t *testing.T) {
+	hint := MutationHint{Kind: repository.ResourcePods, Namespace: "default", Name: "web-1"}
+
+	tests := []struct {
+		name        string
+		pods        []repository.PodInfo
+		wantApplied bool
+	}{
+		{name: "pod gone", pods: []repository.PodInfo{{Name: "web-2", Namespace: "default", Status: "Running"}}, wantApplied: true},
+		{name: "pod terminating", pods: []repository.PodInfo{{Name: "web-1", Namespace: "default", Status: "Terminating"}}, wantApplied: true},
+		{name: "pod still running", pods: []repository.PodInfo{{Name: "web-1", Namespace: "default", Status: "Running"}}, wantApplied: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMutations()
+			m.Begin(PendingMutation{Hint: hint, Action: MutationDelete})
+			if !m.Deleting("default", "web-1") {
+				t.Fatal("pod should be shown as deleting right away")
+			}
+
+			// Refreshes before the API call returns don't decide anything
+			if out := m.ReconcilePods("default", tt.pods); len(out) != 0 {
+				t.Fatalf("unsettled mutation was reconciled: %+v", out)
+			}
+
+			m.Settle(hint, nil)
+			out := m.ReconcilePods("default", tt.pods)
+			if len(out) != 1 || out[0].Applied != tt.wantApplied {
+				t.Fatalf("ReconcilePods() = %+v, want Applied=%v", out, tt.wantApplied)
+			}
+			if m.Deleting("default", "web-1") {
+				t.Error("pod should no longer be marked deleting after reconcile")
+			}
+			if !tt.wantApplied && out[0].Message() != "Pod web-1 was not deleted" {
+				t.Errorf("Message() = %q", out[0].Message())
+			}
+		})
+	}
+}
+
+func TestMutations_FailedCallReverts(t *testing.T) {
+	m := NewMutations()
+	hint := MutationHint{Kind: repository.ResourcePods, Namespace: "default", Name: "web-1"}
+	m.Begin(PendingMutation{Hint: hint, Action: MutationDelete})
+
+	if !m.Settle(hint, errors.New("forbidden")) {
+		t.Error("Settle() with an error should report a revert")
+	}
+	if m.Deleting("default", "web-1") {
+		t.Error("failed delete should be reverted immediately")
+	}
+	if out := m.ReconcilePods("default", nil); len(out) != 0 {
+		t.Errorf("reverted mutation should not be reconciled: %+v", out)
+	}
+}
+
+func TestMutations_OtherNamespaceIgnored(t *testing.T) {
+	m := NewMutations()
+	hint := MutationHint{Kind: repository.ResourcePods, Namespace: "prod", Name: "web-1"}
+	m.Begin(PendingMutation{Hint: hint, Action: MutationDelete})
+	m.Settle(hint, nil)
+
+	// A pod list for another namespace says nothing about this pod
+	if out := m.ReconcilePods("default", nil); len(out) != 0 {
+		t.Errorf("ReconcilePods() = %+v, want none", out)
+	}
+	if !m.Deleting("prod", "web-1") {
+		t.Error("mutation should still be pending")
+	}
+
+	// A node's pod list spans namespaces
+	out := m.ReconcilePods("", []repository.PodInfo{{Name: "web-1", Namespace: "prod", Status: "Running"}})
+	if len(out) != 1 || out[0].Applied {
+		t.Errorf("ReconcilePods() = %+v, want one not applied", out)
+	}
+}
+
+func TestUndoBuffer_UndoAfterScale(t *testing.T) {
+	b := NewUndoBuffer(DefaultUndoSize, DefaultUndoTTL)
+	now := time.Date(2024, 5, 16, 10, 0, 0, 0, time.UTC)
+	payments := MutationHint{Kind: repository.ResourceDeployments, Namespace: "shop", Name: "payments"}
+	web := MutationHint{Kind: repository.ResourceDeployments, Namespace: "shop", Name: "web"}
+
+	b.Push(ScaleUndo{Hint: payments, From: 5, To: 2, At: now})
+	b.Push(ScaleUndo{Hint: web, From: 1, To: 3, At: now.Add(time.Second)})
+
+	if got := b.Message(ScaleUndo{Hint: payments, From: 5, To: 2, At: now}, now); got != "Scaled payments 5→2 (u to undo, 30s)" {
+		t.Errorf("Message() = %q", got)
+	}
+
+	// Newest first; refreshes showing the scaled counts keep them undoable
+	b.Observe(payments, 2)
+	b.Observe(web, 3)
+	u, ok := b.Pop(now.Add(5 * time.Second))
+	if !ok || u.Hint != web || u.From != 1 {
+		t.Fatalf("Pop() = %+v, %v, want the scale of web back to 1", u, ok)
+	}
+	u, ok = b.Pop(now.Add(5 * time.Second))
+	if !ok || u.Hint != payments || u.From != 5 {
+		t.Fatalf("Pop() = %+v, %v, want the scale of payments back to 5", u, ok)
+	}
+	if _, ok := b.Pop(now.Add(5 * time.Second)); ok {
+		t.Error("Pop() on an empty buffer should find nothing")
+	}
+}
+
+func TestUndoBuffer_Expiry(t *testing.T) {
+	b := NewUndoBuffer(2, DefaultUndoTTL)
+	now := time.Date(2024, 5, 16, 10, 0, 0, 0, time.UTC)
+	hint := func(name string) MutationHint {
+		return MutationHint{Kind: repository.ResourceDeployments, Namespace: "shop", Name: name}
+	}
+
+	b.Push(ScaleUndo{Hint: hint("a"), From: 1, To: 2, At: now})
+	b.Push(ScaleUndo{Hint: hint("b"), From: 1, To: 2, At: now.Add(10 * time.Second)})
+	u, ok := b.Last(now.Add(25 * time.Second))
+	if !ok || u.Hint.Name != "b" {
+		t.Fatalf("Last() = %+v, %v, want b", u, ok)
+	}
+	if got := b.Message(u, now.Add(25*time.Second)); got != "Scaled b 1→2 (u to undo, 15s)" {
+		t.Errorf("Message() = %q, want the 15s left", got)
+	}
+
+	// Only the last N are remembered
+	b.Push(ScaleUndo{Hint: hint("c"), From: 1, To: 2, At: now.Add(20 * time.Second)})
+	b.Pop(now.Add(20 * time.Second))
+	if u, ok := b.Pop(now.Add(20 * time.Second)); !ok || u.Hint.Name != "b" {
+		t.Fatalf("Pop() = %+v, %v, want b", u, ok)
+	}
+	if _, ok := b.Pop(now.Add(20 * time.Second)); ok {
+		t.Error("the oldest scale should have been forgotten past the buffer size")
+	}
+
+	// Entries past their TTL can't be undone
+	b.Push(ScaleUndo{Hint: hint("d"), From: 1, To: 2, At: now})
+	if _, ok := b.Last(now.Add(DefaultUndoTTL)); ok {
+		t.Error("a scale should not be undoable past its TTL")
+	}
+}
+
+func TestUndoBuffer_ScaledExternally(t *testing.T) {
+	b := NewUndoBuffer(DefaultUndoSize, DefaultUndoTTL)
+	now := time.Date(2024, 5, 16, 10, 0, 0, 0, time.UTC)
+	payments := MutationHint{Kind: repository.ResourceDeployments, Namespace: "shop", Name: "payments"}
+	web := MutationHint{Kind: repository.ResourceDeployments, Namespace: "shop", Name: "web"}
+
+	b.Push(ScaleUndo{Hint: payments, From: 5, To: 2, At: now})
+	b.Push(ScaleUndo{Hint: web, From: 1, To: 3, At: now})
+	b.Push(ScaleUndo{Hint: payments, From: 2, To: 4, At: now})
+
+	// Someone scaled payments to 6 since
+	b.Observe(payments, 6)
+	u, ok := b.Pop(now)
+	if !ok || u.Hint != web {
+		t.Fatalf("Pop() = %+v, %v, want only the scale of web left", u, ok)
+	}
+	if _, ok := b.Pop(now); ok {
+		t.Error("every scale of payments should be forgotten")
+	}
+
+	var nilBuffer *UndoBuffer
+	nilBuffer.Push(ScaleUndo{Hint: web, From: 1, To: 3, At: now})
+	nilBuffer.Observe(web, 3)
+	if _, ok := nilBuffer.Last(now); ok {
+		t.Error("a nil buffer should remember nothing")
+	}
+}
+
+func TestMutations_Scale(t *testing.T) {
+	w := repository.WorkloadInfo{Name: "web", Namespace: "default", Type: repository.ResourceDeployments}
+	hint := MutationHint{Kind: w.Type, Namespace: w.Namespace, Name: w.Name}
+
+	m := NewMutations()
+	m.Begin(PendingMutation{Hint: hint, Action: MutationScale, Replicas: 5})
+	if target, ok := m.ScaleTarget(w); !ok || target != 5 {
+		t.Fatalf("ScaleTarget() = %d, %v, want 5", target, ok)
+	}
+	if _, ok := m.ReconcileReplicas(hint, 5); ok {
+		t.Fatal("unsettled scale should not be reconciled")
+	}
+
+	m.Settle(hint, nil)
+	out, ok := m.ReconcileReplicas(hint, 5)
+	if !ok || !out.Applied {
+		t.Errorf("ReconcileReplicas() = %+v, %v, want applied", out, ok)
+	}
+	if _, ok := m.ScaleTarget(w); ok {
+		t.Error("scale should be resolved after reconcile")
+	}
+
+	// Something (e.g. an HPA) set the replicas back
+	m.Begin(PendingMutation{Hint: hint, Action: MutationScale, Replicas: 5})
+	m.Settle(hint, nil)
+	out, ok = m.ReconcileReplicas(hint, 3)
+	if !ok || out.Applied {
+		t.Fatalf("ReconcileReplicas() = %+v, %v, want not applied", out, ok)
+	}
+	if out.Message() != "web was not scaled to 5 (still 3 replicas)" {
+		t.Errorf("Message() = %q", out.Message())
+	}
+}
+
+func TestMutations_Nil(t *testing.T) {
+	var m *Mutations
+	hint := MutationHint{Kind: repository.ResourcePods, Name: "p"}
+	m.Begin(PendingMutation{Hint: hint})
+	m.Settle(hint, nil)
+	m.Forget(hint)
+	if m.Deleting("", "p") || m.ReconcilePods("", nil) != nil {
+		t.Error("nil Mutations should track nothing")
+	}
+}
+
+func TestNavigator_RendersPendingMutations(t *testing.T) {
+	m := NewMutations()
+	m.Begin(PendingMutation{
+		Hint:   MutationHint{Kind: repository.ResourcePods, Namespace: "default", Name: "web-1"},
+		Action: MutationDelete,
+	})
+	m.Begin(PendingMutation{
+		Hint:     MutationHint{Kind: repository.ResourceDeployments, Namespace: "default", Name: "api"},
+		Action:   MutationScale,
+		Replicas: 4,
+	})
+
+	nav := NewNavigator()
+	nav.SetSize(120, 40)
+	nav.SetMutations(m)
+	nav.SetMode(ModeResources)
+	nav.SetPods([]repository.PodInfo{{Name: "web-1", Namespace: "default", Status: "Running"}})
+	if !strings.Contains(nav.View(), "Deleting") {
+		t.Error("pod being deleted should show as Deleting")
+	}
+
+	nav.SetMode(ModeWorkloads)
+	nav.SetWorkloads([]repository.WorkloadInfo{{Name: "api", Namespace: "default", Type: repository.ResourceDeployments, Status: "Running"}})
+	if !strings.Contains(nav.View(), "Scaling to 4") {
+		t.Error("workload being scaled should show its target")
+	}
+}
+
+// ============================================
+// EventsPanel Extended Tests
+// ============================================
+
+func TestEventsPanel_Update_WKey(t *testing.T) {
+	ep := NewEventsPanel()
+	ep.SetEvents([]repository.EventInfo{
+		{Type: "Normal", Reason: "Scheduled"},
+		{Type: "Warning", Reason: "BackOff"},
+	})
+
+	// Press w to toggle warnings filter
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+}
+
+func TestEventsPanel_Update_CKey(t *testing.T) {
+	ep := NewEventsPanel()
+	ep.SetEvents([]repository.EventInfo{
+		{Type: "Normal", Reason: "Scheduled"},
+	})
+
+	// Enter filter mode
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+
+	// Press c to clear filter
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+}
+
+func TestEventsPanel_Update_EscKey(t *testing.T) {
+	ep := NewEventsPanel()
+	ep.SetEvents([]repository.EventInfo{
+		{Type: "Normal", Reason: "Scheduled"},
+	})
+
+	// Enter filter mode
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+
+	// Press Esc
+	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyEsc})
+}
+
+// ============================================
+// LogsPanel Extended Tests
+// ============================================
+
+func TestLogsPanel_Update_WKey(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetLogs([]repository.LogLine{
+		{Content: "Normal log", IsError: false},
+		{Content: "Error log", IsError: true},
+	})
+
+	// Press w to toggle errors filter
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+}
+
+func TestLogsPanel_Update_CKey(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetLogs([]repository.LogLine{
+		{Content: "Normal log", IsError: false},
+	})
+
+	// Enter filter mode
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+
+	// Press c to clear filter
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+}
+
+func TestLogsPanel_Update_GKey(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetLogs([]repository.LogLine{
+		{Content: "Log 1", IsError: false},
+		{Content: "Log 2", IsError: false},
+	})
+
+	// Press g to go to top
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+
+	// Press G to go to bottom
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+}
+
+func TestLogsPanel_Update_EscKey(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetLogs([]repository.LogLine{
+		{Content: "Normal log", IsError: false},
+	})
+
+	// Enter filter mode
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+
+	// Press Esc
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyEsc})
+}
+
+func TestLogsPanel_Update_PageKeys(t *testing.T) {
+	lp := NewLogsPanel()
+	lp.SetSize(80, 10)
+	lp.SetLogs([]repository.LogLine{
+		{Content: "Log 1"},
+		{Content: "Log 2"},
+		{Content: "Log 3"},
+	})
+
+	// Press PgDn
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+
+	// Press PgUp
+	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+}
+
+// ============================================
+// HPA Viewer Extended Tests
+// ============================================
+
+func TestHPAViewer_Update_EnterKey(t *testing.T) {
+	hv := NewHPAViewer()
+	hv.SetSize(80, 40)
+	hv.Show(&repository.HPAData{
+		Name:      "web-hpa",
+		Namespace: "default",
+	}, "default")
+
+	// Press Enter (copy to clipboard)
+	hv, _ = hv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestHPAViewer_Update_PageKeys(t *testing.T) {
+	hv := NewHPAViewer()
+	hv.SetSize(80, 20)
+	hv.Show(&repository.HPAData{
+		Name:      "web-hpa",
+		Namespace: "default",
+		Metrics: []repository.HPAMetricDetail{
+			{Type: "Resource", Name: "cpu"},
+			{Type: "Resource", Name: "memory"},
+		},
+	}, "default")
+
+	// Press PgDn
+	hv, _ = hv.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+
+	// Press PgUp
+	hv, _ = hv.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+}
+
+// ============================================
+// Struct Tests
+// ============================================
+
+func TestPodActionItem_Struct(t *testing.T) {
+	item := PodActionItem{
+		Label:       "Delete",
+		Description: "removes pod",
+		Action:      "delete",
+		Command:     "kubectl delete pod test",
+	}
+
+	if item.Label != "Delete" {
+		t.Errorf("Label = %q, want %q", item.Label, "Delete")
+	}
+	if item.Action != "delete" {
+		t.Errorf("Action = %q, want %q", item.Action, "delete")
+	}
+}
+
+func TestWorkloadActionItem_Struct(t *testing.T) {
+	item := WorkloadActionItem{
+		Label:    "Scale to 5",
+		Action:   "scale",
+		Replicas: 5,
+		Command:  "kubectl scale",
+	}
+
+	if item.Label != "Scale to 5" {
+		t.Errorf("Label = %q, want %q", item.Label, "Scale to 5")
+	}
+	if item.Replicas != 5 {
+		t.Errorf("Replicas = %d, want %d", item.Replicas, 5)
+	}
+}
+
+func TestPodActionMenuResult_Struct(t *testing.T) {
+	result := PodActionMenuResult{
+		Item: PodActionItem{
+			Label:  "Delete",
+			Action: "delete",
+		},
+	}
+
+	if result.Item.Action != "delete" {
+		t.Errorf("Item.Action = %q, want %q", result.Item.Action, "delete")
+	}
+}
+
+func TestWorkloadActionMenuResult_Struct(t *testing.T) {
+	result := WorkloadActionMenuResult{
+		Item: WorkloadActionItem{
+			Label:    "Restart",
+			Action:   "restart",
+			Replicas: 0,
+		},
+	}
+
+	if result.Item.Action != "restart" {
+		t.Errorf("Item.Action = %q, want %q", result.Item.Action, "restart")
+	}
+}
+
+// ============================================
+// YAML Highlighting and Folding Tests
+// ============================================
+
+const trickyYAML = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web # inline comment
+  annotations:
+    note: "quoted: with colon # not a comment"
+    url: http://example.com:8080/path
+    escaped: "say \"hi\""
+    unterminated: 'oops
+spec:
+  containers:
+  - name: app
+    args:
+    - - nested
+      - list
+    command: ["sh", "-c"]
+    script: |
+      echo "key: value"
+      # not a comment
+
+      exit 0
+  replicas: 3
+  ratio: 0.5
+data: >-
+  folded text
+status:
+  phase: Running
+-
+: weird
+"quoted key": 1
+'`
+
+func TestTokenizeYAML_ReproducesInput(t *testing.T) {
+	inputs := append(strings.Split(trickyYAML, "\n"),
+		"", "-", "- ", ":", "::", "#", "'", "\"", "key:", "\t\tkey:\tvalue", "- - - x", "a: |+2", "é: ü",
+	)
+	for i, tokens := range tokenizeYAML(inputs) {
+		var b strings.Builder
+		for _, tok := range tokens {
+			b.WriteString(tok.text)
+		}
+		if b.String() != inputs[i] {
+			t.Errorf("tokens of %q rebuild to %q", inputs[i], b.String())
+		}
+	}
+}
+
+func TestTokenizeYAMLLine_Kinds(t *testing.T) {
+	tests := []struct {
+		line string
+		text string
+		want yamlTokenKind
+	}{
+		{"  name: web # inline comment", "name", yamlKey},
+		{"  name: web # inline comment", " # inline comment", yamlComment},
+		{"  replicas: 3", "3", yamlNumber},
+		{"  ratio: 0.5", "0.5", yamlNumber},
+		{`    note: "quoted: with colon # not a comment"`, `"quoted: with colon # not a comment"`, yamlString},
+		{"    url: http://example.com:8080/path", "url", yamlKey},
+		{"  - name: app", "name", yamlKey},
+		{"# full line", "# full line", yamlComment},
+		{`"quoted key": 1`, `"quoted key"`, yamlKey},
+	}
+
+	for _, tt := range tests {
+		found := false
+		for _, tok := range tokenizeYAMLLine(tt.line) {
+			if tok.text == tt.text {
+				found = true
+				if tok.kind != tt.want {
+					t.Errorf("%q: token %q kind = %d, want %d", tt.line, tt.text, tok.kind, tt.want)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%q: no token %q", tt.line, tt.text)
+		}
+	}
+
+	// The colon inside the URL must not start a second key
+	keys := 0
+	for _, tok := range tokenizeYAMLLine("    url: http://example.com:8080/path") {
+		if tok.kind == yamlKey {
+			keys++
+		}
+	}
+	if keys != 1 {
+		t.Errorf("url line has %d keys, want 1", keys)
+	}
+}
+
+func TestTokenizeYAML_BlockScalar(t *testing.T) {
+	lines := strings.Split(trickyYAML, "\n")
+	tokens := tokenizeYAML(lines)
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == `echo "key: value"` || trimmed == "# not a comment" || trimmed == "folded text" {
+			for _, tok := range tokens[i] {
+				if tok.kind == yamlKey || tok.kind == yamlComment {
+					t.Errorf("block scalar line %q tokenized as %d", line, tok.kind)
+				}
+			}
+		}
+		if trimmed == "replicas: 3" && tokens[i][1].kind != yamlKey {
+			t.Errorf("block scalar should end before %q", line)
+		}
+	}
+}
+
+func TestYAMLFoldRegions(t *testing.T) {
+	lines := strings.Split(trickyYAML, "\n")
+	regions := yamlFoldRegions(lines)
+
+	var headers []string
+	for _, r := range regions {
+		if r.end <= r.start || r.end >= len(lines) {
+			t.Errorf("invalid region %+v", r)
+		}
+		headers = append(headers, lines[r.start])
+	}
+	want := []string{"metadata:", "spec:", "status:"}
+	if strings.Join(headers, ",") != strings.Join(want, ",") {
+		t.Errorf("fold headers = %v, want %v", headers, want)
+	}
+
+	// spec must include the blank line inside the block scalar and end at ratio
+	for _, r := range regions {
+		if lines[r.start] == "spec:" && strings.TrimSpace(lines[r.end]) != "ratio: 0.5" {
+			t.Errorf("spec region ends at %q, want ratio line", lines[r.end])
+		}
+	}
+
+	// Describe output uses the same shape
+	describe := "Name:         web\nContainers:\n  app:\n    Image: nginx\nEvents:  <none>"
+	regions = yamlFoldRegions(strings.Split(describe, "\n"))
+	if len(regions) != 1 || regions[0].start != 1 || regions[0].end != 3 {
+		t.Errorf("describe regions = %+v, want Containers section", regions)
+	}
+}
+
+func TestHighlightYAML_StableOutput(t *testing.T) {
+	out := HighlightYAML(trickyYAML)
+	if strings.Count(out, "\n") != strings.Count(trickyYAML, "\n") {
+		t.Error("HighlightYAML() must not change the number of lines")
+	}
+	if stripAnsiCodes(out) != stripAnsiCodes(trickyYAML) {
+		t.Error("HighlightYAML() must only add styling")
+	}
+	if HighlightYAML(out) == "" {
+		t.Error("HighlightYAML() should tolerate already styled input")
+	}
+}
+
+func TestResultViewer_YAMLFolding(t *testing.T) {
+	rv := NewResultViewer()
+	rv.ShowYAML("pod/web", trickyYAML, 100, 10)
+	total := rv.viewport.TotalLineCount()
+
+	// Scroll to the metadata header and fold it
+	rv.viewport.SetYOffset(2)
+	rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	if rv.viewport.TotalLineCount() >= total {
+		t.Fatal("z on section header should fold the section")
+	}
+	if !strings.Contains(stripAnsiCodes(rv.viewport.View()), "lines folded") {
+		t.Error("folded section should show a marker")
+	}
+
+	// Same object reopened keeps folds; a different one resets them
+	rv.ShowYAML("pod/web", trickyYAML, 100, 10)
+	if rv.viewport.TotalLineCount() >= total {
+		t.Error("fold state should persist while the same object stays open")
+	}
+	rv.ShowYAML("pod/other", trickyYAML, 100, 10)
+	if rv.viewport.TotalLineCount() != total {
+		t.Error("fold state should reset for a different object")
+	}
+
+	// z again unfolds
+	rv.viewport.SetYOffset(2)
+	rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	if rv.viewport.TotalLineCount() != total {
+		t.Error("second z should unfold the section")
+	}
+}
+
+func TestResultViewer_Links(t *testing.T) {
+	rv := NewResultViewer()
+	links := []ResultLink{{Line: 1, Kind: "Service", Name: "web"}, {Line: 3, Kind: "ConfigMap", Name: "web-config"}}
+	rv.ShowWithLinks("Resource Details: web", "Services\n  • web\nConfigMaps Used\n  • web-config\n", links, 100, 10)
+
+	if rv.SelectedLink() != nil {
+		t.Fatal("no link should be selected when opened")
+	}
+
+	rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyTab})
+	rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if l := rv.SelectedLink(); l == nil || l.Name != "web-config" {
+		t.Fatalf("tab twice should select web-config, got %+v", l)
+	}
+	if !strings.Contains(stripAnsiCodes(rv.viewport.View()), "> • web-config") {
+		t.Error("selected link should be marked with the cursor")
+	}
+	rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if l := rv.SelectedLink(); l == nil || l.Name != "web" {
+		t.Fatalf("shift+tab should select web, got %+v", l)
+	}
+
+	_, cmd := rv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter on a selected link should return a command")
+	}
+	msg, ok := cmd().(ResultViewerOpenLinkMsg)
+	if !ok || msg.Index != 0 || msg.Link.Kind != "Service" || msg.Link.Name != "web" {
+		t.Errorf("enter should open the selected link, got %+v", msg)
+	}
+
+	// Plain Show drops the links
+	rv.Show("Pod: web", "content", 100, 10)
+	if rv.SelectedLink() != nil {
+		t.Error("Show should clear links")
+	}
+}
+
+func TestResultViewer_CheckLink(t *testing.T) {
+	rv := NewResultViewer()
+	links := []ResultLink{{Line: 1, Kind: "Service", Name: "web"}, {Line: 3, Kind: "ConfigMap", Name: "web-config"}}
+	rv.ShowWithLinks("Resource Details: web", "Services\n  • web\nConfigMaps Used\n  • web-config\n", links, 100, 10)
+
+	if _, cmd := rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}}); cmd != nil {
+		t.Error("t without a selected Service should do nothing")
+	}
+	rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if !strings.Contains(rv.View(), "t/T test service") {
+		t.Error("footer should offer the service check on a selected Service")
+	}
+	_, cmd := rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	if cmd == nil {
+		t.Fatal("T on a selected Service should return a command")
+	}
+	msg, ok := cmd().(ResultViewerCheckLinkMsg)
+	if !ok || msg.Link.Name != "web" || !msg.Netshoot {
+		t.Errorf("T should check the Service from netshoot, got %+v", msg)
+	}
+
+	rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if _, cmd := rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}}); cmd != nil {
+		t.Error("t on a ConfigMap should do nothing")
+	}
+
+	// New content keeps the selection and moves the links
+	rv.SetContentWithLinks("Services\n  • web\n    Check: ok\nConfigMaps Used\n  • web-config\n",
+		[]ResultLink{{Line: 1, Kind: "Service", Name: "web"}, {Line: 4, Kind: "ConfigMap", Name: "web-config"}})
+	if l := rv.SelectedLink(); l == nil || l.Name != "web-config" || l.Line != 4 {
+		t.Errorf("SetContentWithLinks() should keep web-config selected at its new line, got %+v", l)
+	}
+}
+
+// ============================================
+// ChangeTracker Tests
+// ============================================
+
+// fakeClock is a settable clock for change highlight tests.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.t }
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestChangeTracker_PodChanges(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+	c := NewChangeTracker(3*time.Second, clock.Now)
+	c.SetEnabled(true)
+
+	c.Observe(PodSnapshot([]repository.PodInfo{
+		{Namespace: "default", Name: "web-1", Status: "Running", Ready: "1/1", Restarts: 0},
+		{Namespace: "default", Name: "web-2", Status: "Running", Ready: "1/1", Restarts: 2},
+	}))
+	if c.Changed("default/web-1", FieldRestarts) {
+		t.Error("the first snapshot should not highlight anything")
+	}
+
+	c.Observe(PodSnapshot([]repository.PodInfo{
+		{Namespace: "default", Name: "web-1", Status: "Running", Ready: "0/1", Restarts: 1},
+		{Namespace: "default", Name: "web-2", Status: "Running", Ready: "1/1", Restarts: 2},
+		{Namespace: "default", Name: "web-3", Status: "Pending", Ready: "0/1"},
+	}))
+	if !c.Changed("default/web-1", FieldRestarts) || !c.Changed("default/web-1", FieldReady) {
+		t.Error("web-1 restarts and ready changed and should be highlighted")
+	}
+	if c.Changed("default/web-1", FieldStatus) {
+		t.Error("web-1 status did not change")
+	}
+	if c.Changed("default/web-2", FieldRestarts) {
+		t.Error("web-2 did not change")
+	}
+	if c.Changed("default/web-3", FieldStatus) {
+		t.Error("new pods should not be highlighted")
+	}
+}
+
+func TestChangeTracker_KeyedByIdentity(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+	c := NewChangeTracker(3*time.Second, clock.Now)
+	c.SetEnabled(true)
+
+	// Same name in another namespace is a different object
+	c.Observe(PodSnapshot([]repository.PodInfo{{Namespace: "a", Name: "web", Status: "Running"}}))
+	c.Observe(PodSnapshot([]repository.PodInfo{{Namespace: "b", Name: "web", Status: "Failed"}}))
+	if c.Changed("b/web", FieldStatus) || c.Changed("a/web", FieldStatus) {
+		t.Error("pods in different namespaces should not be compared")
+	}
+
+	// Workloads of different types with the same name are different objects
+	c = NewChangeTracker(3*time.Second, clock.Now)
+	c.SetEnabled(true)
+	c.Observe(WorkloadSnapshot([]repository.WorkloadInfo{{Type: repository.ResourceDeployments, Namespace: "a", Name: "web", Ready: "3/3"}}))
+	c.Observe(WorkloadSnapshot([]repository.WorkloadInfo{
+		{Type: repository.ResourceDeployments, Namespace: "a", Name: "web", Ready: "2/3"},
+		{Type: repository.ResourceStatefulSets, Namespace: "a", Name: "web", Ready: "0/1"},
+	}))
+	if !c.Changed("deployments/a/web", FieldReady) {
+		t.Error("deployment ready count changed and should be highlighted")
+	}
+	if c.Changed("statefulsets/a/web", FieldReady) {
+		t.Error("a new statefulset should not be highlighted")
+	}
+}
+
+func TestChangeTracker_Expiry(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+	c := NewChangeTracker(3*time.Second, clock.Now)
+	c.SetEnabled(true)
+
+	events := []repository.EventInfo{{Type: "Warning", Reason: "BackOff", Object: "Pod/web-1", Count: 4}}
+	c.Observe(EventSnapshot(events))
+	events[0].Count = 5
+	c.Observe(EventSnapshot(events))
+
+	if d, ok := c.NextExpiry(); !ok || d != 3*time.Second {
+		t.Errorf("NextExpiry() = %v, %v, want 3s, true", d, ok)
+	}
+
+	clock.Advance(2 * time.Second)
+	if !c.Changed("Warning/BackOff/Pod/web-1", FieldCount) {
+		t.Error("highlight should last until the ttl")
+	}
+	if d, ok := c.NextExpiry(); !ok || d != time.Second {
+		t.Errorf("NextExpiry() = %v, %v, want 1s, true", d, ok)
+	}
+
+	clock.Advance(time.Second)
+	if c.Changed("Warning/BackOff/Pod/web-1", FieldCount) {
+		t.Error("highlight should expire after the ttl")
+	}
+	if _, ok := c.NextExpiry(); ok {
+		t.Error("NextExpiry() should report nothing once highlights expire")
+	}
+}
+
+func TestChangeTracker_Disabled(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+	c := NewChangeTracker(3*time.Second, clock.Now)
+
+	c.Observe(PodSnapshot([]repository.PodInfo{{Namespace: "a", Name: "web", Restarts: 0}}))
+	c.Observe(PodSnapshot([]repository.PodInfo{{Namespace: "a", Name: "web", Restarts: 1}}))
+	if c.Changed("a/web", FieldRestarts) {
+		t.Error("a disabled tracker should not highlight")
+	}
+
+	// Enabling diffs against the snapshot taken while disabled
+	c.SetEnabled(true)
+	c.Observe(PodSnapshot([]repository.PodInfo{{Namespace: "a", Name: "web", Restarts: 2}}))
+	if !c.Changed("a/web", FieldRestarts) {
+		t.Error("the first refresh after enabling should be diffed")
+	}
+
+	c.SetEnabled(false)
+	if c.Changed("a/web", FieldRestarts) {
+		t.Error("disabling should drop highlights")
+	}
+
+	var nilTracker *ChangeTracker
+	nilTracker.Observe(PodSnapshot(nil))
+	if nilTracker.Changed("a/web", FieldRestarts) {
+		t.Error("a nil tracker should not highlight")
+	}
+}
+
+func TestNavigator_HighlightChanges(t *testing.T) {
+	nav := NewNavigator()
+	nav.SetSize(120, 50)
+	nav.SetMode(ModeResources)
+	nav.SetHighlightChanges(true)
+
+	nav.SetPods([]repository.PodInfo{{Namespace: "default", Name: "web-1", Status: "Running", Ready: "1/1"}})
+	if _, ok := nav.NextChangeExpiry(); ok {
+		t.Error("nothing should be highlighted after the first refresh")
+	}
+	nav.SetPods([]repository.PodInfo{{Namespace: "default", Name: "web-1", Status: "Running", Ready: "1/1", Restarts: 1}})
+	if d, ok := nav.NextChangeExpiry(); !ok || d <= 0 || d > ChangeHighlightDuration {
+		t.Errorf("NextChangeExpiry() = %v, %v, want a pending highlight", d, ok)
+	}
+}
+
+func TestPortForwardActions(t *testing.T) {
+	containers := []repository.ContainerInfo{
+		{Name: "app", Ports: []repository.ContainerPort{
+			{Name: "http", ContainerPort: 8080, Protocol: "TCP"},
+			{Name: "metrics", ContainerPort: 9090, Protocol: "TCP"},
+			{Name: "dns", ContainerPort: 53, Protocol: "UDP"},
+		}},
+		{Name: "sidecar", Ports: []repository.ContainerPort{{ContainerPort: 9090}, {ContainerPort: 15000}}},
+	}
+	related := &repository.RelatedResources{Services: []repository.ServiceInfo{
+		{Name: "web", PortNumbers: []int32{80}},
+		{Name: "manual", NoSelector: true, PortNumbers: []int32{81}},
+	}}
+
+	items := PortForwardActions("default", "web-1", containers, related)
+	want := []struct{ label, target string }{
+		{"Port Forward :9090 (app/metrics)", "9090"},
+		{"Port Forward :15000 (sidecar)", "15000"},
+		{"Port Forward svc/web :80", "svc/web:80"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("PortForwardActions() = %+v, want %d items", items, len(want))
+	}
+	for i, w := range want {
+		if items[i].Action != "port-forward" || items[i].Label != w.label || items[i].Target != w.target {
+			t.Errorf("items[%d] = %+v, want %q targeting %q", i, items[i], w.label, w.target)
+		}
+	}
+	if items[2].Command != "kubectl port-forward -n default svc/web 80:80" {
+		t.Errorf("items[2].Command = %q", items[2].Command)
+	}
+	for _, item := range DisableMutatingPodActions(items) {
+		if item.Disabled {
+			t.Errorf("%q should stay enabled in read-only mode", item.Label)
+		}
+	}
+}
+
+func TestParsePortForwardTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		service string
+		port    int32
+		ok      bool
+	}{
+		{"8080", "", 8080, true},
+		{"svc/web:80", "web", 80, true},
+		{"svc/web", "", 0, false},
+		{"svc/:80", "", 0, false},
+		{"70000", "", 0, false},
+		{"http", "", 0, false},
+	}
+	for _, tt := range tests {
+		service, port, ok := ParsePortForwardTarget(tt.target)
+		if service != tt.service || port != tt.port || ok != tt.ok {
+			t.Errorf("ParsePortForwardTarget(%q) = %q, %d, %v, want %q, %d, %v", tt.target, service, port, ok, tt.service, tt.port, tt.ok)
+		}
+	}
+}
+
+func TestPortForwardsViewer(t *testing.T) {
+	v := NewPortForwardsViewer()
+	v.SetSize(120, 40)
+	v.Show(nil)
+	if !strings.Contains(v.View(), "No port-forwards running") {
+		t.Errorf("empty viewer should say no forwards are running:\n%s", v.View())
+	}
+
+	v.SetForwards([]repository.PortForward{
+		{ID: 1, Namespace: "default", Pod: "web-1", LocalPort: 8080, RemotePort: 8080, StartedAt: time.Now()},
+		{ID: 4, Namespace: "default", Pod: "web-2", Service: "web", LocalPort: 8081, RemotePort: 80, StartedAt: time.Now()},
 	})
+	view := v.View()
+	for _, want := range []string{"pod/web-1", "svc/web (web-2)", "8081", "[2 running]"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() should contain %q:\n%s", want, view)
+		}
+	}
 
-	// Press Shift+Tab to cycle sections backwards
-	nav, _ = nav.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	v, cmd := v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if cmd == nil {
+		t.Fatal("x should stop the selected forward")
+	}
+	if req, ok := cmd().(StopPortForwardRequest); !ok || req.ID != 4 {
+		t.Errorf("x sent %+v, want StopPortForwardRequest{ID: 4}", req)
+	}
+
+	// The stopped forward is gone from the refreshed list
+	v.SetForwards(v.forwards[:1])
+	if pf := v.Selected(); pf == nil || pf.ID != 1 {
+		t.Errorf("Selected() = %+v, want the remaining forward", pf)
+	}
+
+	v, cmd = v.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if v.IsVisible() || cmd == nil {
+		t.Fatal("esc should close the viewer")
+	}
+	if _, ok := cmd().(PortForwardsViewerClosed); !ok {
+		t.Error("closing should send PortForwardsViewerClosed")
+	}
+}
+
+func TestBlockingPVCs(t *testing.T) {
+	related := &repository.RelatedResources{PVCs: []repository.PVCInfo{
+		{Name: "data", Phase: "Bound"},
+		{Name: "wal", Phase: "Pending"},
+		{Name: "backup", Missing: true},
+	}}
+
+	blocking := BlockingPVCs(&repository.PodInfo{Status: "Pending"}, related)
+	if len(blocking) != 2 || blocking[0].Name != "wal" || blocking[1].Name != "backup" {
+		t.Errorf("BlockingPVCs() = %+v, want wal and backup", blocking)
+	}
+	if got := BlockingPVCs(&repository.PodInfo{Status: "Running"}, related); got != nil {
+		t.Errorf("BlockingPVCs() for a running pod = %+v, want nil", got)
+	}
+
+	m := NewManifestPanel()
+	m.SetSize(80, 40)
+	m.SetPod(&repository.PodInfo{Name: "db-0", Namespace: "default", Status: "Pending"})
+	m.SetRelated(related)
+	if out := m.viewport.View(); !strings.Contains(out, "wal") || strings.Contains(out, "data") {
+		t.Errorf("summary should flag only the blocking claims, got:\n%s", out)
+	}
+}
+
+func TestManifestPanel_ServicePodEndpoint(t *testing.T) {
+	m := NewManifestPanel()
+	m.SetSize(100, 40)
+	m.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default", Status: "Running",
+		Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+		Containers: []repository.ContainerInfo{{Name: "app", State: "Running", ReadinessProbe: &repository.ProbeInfo{Type: "HTTP"}}},
+	})
+	m.SetRelated(&repository.RelatedResources{Services: []repository.ServiceInfo{
+		{Name: "web", Type: "ClusterIP", Endpoints: 2, PodEndpoint: repository.PodEndpointMissing},
+		{Name: "web-headless", Type: "ClusterIP", Endpoints: 3, PodEndpoint: repository.PodEndpointServing},
+	}})
+
+	out := stripAnsiCodes(m.renderRelated())
+	for _, want := range []string{"[2 endpoints] not in endpoints", "[3 endpoints] serving", "not ready: readiness probe of container app is failing"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("related resources should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestManifestPanel_RolloutStatus(t *testing.T) {
+	m := NewManifestPanel()
+	m.SetSize(80, 40)
+	m.SetPod(&repository.PodInfo{Name: "web-abc", Namespace: "default", Status: "Running"})
+	m.SetRelated(&repository.RelatedResources{Owner: &repository.OwnerInfo{
+		Kind: "ReplicaSet", Name: "web-abc", WorkloadKind: "Rollout", WorkloadName: "web", Replicas: 3, ReadyReplicas: 3,
+		Rollout: &repository.RolloutStatus{
+			Phase: "Paused", Canary: true, CurrentStepIndex: 1, Steps: 4, CanaryWeight: 20,
+			Paused: true, PauseReason: "CanaryPauseStep",
+		},
+	}})
+
+	out := stripAnsiCodes(m.viewport.View())
+	for _, want := range []string{"1/4", "canary 20%", "Paused: CanaryPauseStep"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("owner section should show %q, got:\n%s", want, out)
+		}
+	}
+
+	aborted := renderRolloutStatus(&repository.RolloutStatus{Canary: true, Steps: 4, Aborted: true, Paused: true})
+	if !strings.Contains(stripAnsiCodes(aborted), "Aborted") || strings.Contains(aborted, "Paused") {
+		t.Errorf("an aborted rollout should show Aborted, got:\n%s", aborted)
+	}
+	if got := renderRolloutStatus(&repository.RolloutStatus{Phase: "Healthy"}); strings.Contains(got, "Step:") {
+		t.Errorf("a blue-green rollout has no steps, got:\n%s", got)
+	}
+}
+
+func TestManifestPanel_PDB(t *testing.T) {
+	m := NewManifestPanel()
+	m.SetSize(80, 40)
+	m.SetPod(&repository.PodInfo{Name: "web-abc", Namespace: "default", Status: "Running"})
+	m.SetRelated(&repository.RelatedResources{PDB: &repository.PDBInfo{Name: "web-pdb", MaxUnavailable: "1"}})
+
+	out := stripAnsiCodes(m.viewport.View())
+	if !strings.Contains(out, "web-pdb (0 disruptions allowed)") {
+		t.Errorf("Pod Info should show the budget, got:\n%s", out)
+	}
+	if got := renderPDBStatus(&repository.PDBInfo{Name: "web-pdb", DisruptionsAllowed: 2}); !strings.Contains(stripAnsiCodes(got), "2 disruptions allowed") {
+		t.Errorf("renderPDBStatus() = %q", got)
+	}
+}
+
+func TestManifestPanel_Image(t *testing.T) {
+	m := NewManifestPanel()
+	m.SetSize(100, 60)
+	m.SetPod(&repository.PodInfo{
+		Name: "web-abc", Namespace: "default", Status: "ImagePullBackOff",
+		Containers: []repository.ContainerInfo{{Name: "app", Image: "ghcr.io/org/app:v1", ImagePullPolicy: "IfNotPresent"}},
+		PullSecrets: []repository.PullSecretStatus{
+			{Name: "regcred", Found: true, Type: "kubernetes.io/dockerconfigjson"},
+			{Name: "old-cred"},
+			{Name: "opaque", Found: true, Type: "Opaque", FromServiceAccount: true},
+		},
+	})
+
+	out := stripAnsiCodes(m.viewport.View())
+	for _, want := range []string{
+		"Reference:   ghcr.io/org/app:v1", "Registry:    ghcr.io", "Repository:  org/app", "Tag:         v1",
+		"Pull Policy: IfNotPresent", "regcred ✓", "old-cred ✗ not found in namespace",
+		"opaque (ServiceAccount) ✗ type Opaque, want kubernetes.io/dockerconfigjson",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Image section should contain %q, got:\n%s", want, out)
+		}
+	}
+
+	m.SetPod(&repository.PodInfo{Name: "web-abc", Namespace: "default", Containers: []repository.ContainerInfo{{Name: "app", Image: "nginx"}}})
+	out = stripAnsiCodes(m.viewport.View())
+	if !strings.Contains(out, "Registry:    docker.io") || !strings.Contains(out, "Pull Secret: none") {
+		t.Errorf("a Docker Hub image without pull secrets, got:\n%s", out)
+	}
+}
+
+func TestManifestPanel_Probes(t *testing.T) {
+	m := NewManifestPanel()
+	m.SetSize(100, 80)
+	httpProbe := &repository.ProbeInfo{Type: "HTTP", Path: "/health", Port: 8080, InitialDelay: 5, Period: 10, Timeout: 1, FailureThreshold: 3}
+	m.SetPod(&repository.PodInfo{Name: "web-abc", Namespace: "default", Containers: []repository.ContainerInfo{
+		{Name: "app", Image: "web:1", State: "Running", LivenessProbe: httpProbe, ReadinessProbe: httpProbe},
+	}})
+	out := stripAnsiCodes(m.viewport.View())
+	if strings.Contains(out, "Probes") {
+		t.Errorf("Probes section should be hidden until the status is set, got:\n%s", out)
+	}
+
+	m.SetProbes([]repository.ProbeStatus{
+		{Container: "app", Probe: "Liveness", Config: httpProbe, State: repository.ProbePassing},
+		{Container: "app", Probe: "Readiness", Config: httpProbe, State: repository.ProbeFailing, Failures: 7, LastFailure: "HTTP probe failed with statuscode: 503"},
+	})
+	out = stripAnsiCodes(m.viewport.View())
+	for _, want := range []string{
+		"Probes", "Liveness:    ✓ passing /health:8080", "Readiness:   ✗ failing /health:8080",
+		"delay 5s, every 10s, timeout 1s, 3 failures", "7x HTTP probe failed with statuscode: 503",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Probes section should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestManifestPanel_OOM(t *testing.T) {
+	m := NewManifestPanel()
+	m.SetSize(100, 80)
+	pod := &repository.PodInfo{Name: "api-0", Namespace: "default", Containers: []repository.ContainerInfo{
+		{Name: "api", Image: "api:1", State: "Running", RestartCount: 2, Resources: repository.ResourceRequirements{MemoryLimit: "100Mi"}},
+	}}
+	m.SetPod(pod)
+	m.SetOOM(repository.BuildOOMReport(pod, &repository.PodMetrics{Containers: []repository.ContainerMetrics{
+		{Name: "api", MemoryUsage: "50.0Mi", MemoryBytes: 50 << 20},
+	}}, nil))
+	if out := stripAnsiCodes(m.viewport.View()); strings.Contains(out, "OOM") {
+		t.Errorf("OOM section should stay out of the summary for a container with headroom, got:\n%s", out)
+	}
+
+	pod.Containers[0].LastTermination = &repository.TerminationInfo{Reason: "OOMKilled", ExitCode: 137, FinishedAt: "2024-05-16 09:58:00"}
+	m.SetOOM(repository.BuildOOMReport(pod, &repository.PodMetrics{Containers: []repository.ContainerMetrics{
+		{Name: "api", MemoryUsage: "95.0Mi", MemoryBytes: 95 << 20},
+	}}, []repository.EventInfo{{Reason: "OOMKilling", Age: "2m", Message: "Out of memory: Killed process 4242 (api)"}}))
+	out := stripAnsiCodes(m.viewport.View())
+	for _, want := range []string{
+		"OOM", "Restarts:    2", "Last OOM:    OOMKilled (exit 137) at 2024-05-16 09:58:00",
+		"Memory:      ● 95.0Mi / 100Mi (95%)", "Node OOM:    2m Out of memory: Killed process 4242 (api)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("OOM section should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestManifestPanel_Security(t *testing.T) {
+	m := NewManifestPanel()
+	m.SetSize(100, 80)
+	nonRoot := true
+	pod := &repository.PodInfo{Name: "web-1", Namespace: "prod", Containers: []repository.ContainerInfo{
+		{Name: "app", Image: "nginx", SecurityContext: &repository.SecurityContextInfo{RunAsNonRoot: &nonRoot}},
+	}}
+	m.SetPod(pod)
+	m.SetSecurity(repository.BuildPodSecurity(pod, nil, repository.PodSecurityAdmission{Enforce: repository.PodSecurityBaseline}))
+	if out := stripAnsiCodes(m.viewport.View()); strings.Contains(out, "Security") {
+		t.Errorf("Security section should stay out of the summary without issues, got:\n%s", out)
+	}
+
+	pod.Containers[0].Reason = "CreateContainerConfigError"
+	pod.Containers[0].Message = `container has runAsNonRoot and image will run as root (pod: "web-1_prod(1234)", container: app)`
+	m.SetSecurity(repository.BuildPodSecurity(pod, nil, repository.PodSecurityAdmission{Enforce: repository.PodSecurityRestricted}))
+	out := stripAnsiCodes(m.viewport.View())
+	for _, want := range []string{
+		"Security", "app:         ✗ image runs as root but runAsNonRoot is true", "set runAsUser to a non-zero UID",
+		"Admission:   violates restricted, a new pod would be rejected", "allowPrivilegeEscalation != false",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Security section should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestManifestPanel_CrashBanner(t *testing.T) {
+	m := NewManifestPanel()
+	m.SetSize(80, 20)
+	m.SetPod(&repository.PodInfo{Name: "web", Namespace: "default", Status: "CrashLoopBackOff"})
+	height := m.viewport.Height
+
+	m.SetDiagnosis(&repository.CrashDiagnosis{Summary: "OOMKilled: container main exceeded 512Mi limit"})
+	out := stripAnsiCodes(m.View())
+	if !strings.Contains(out, "OOMKilled: container main exceeded 512Mi limit") || !strings.Contains(out, "[i] why") {
+		t.Errorf("banner should show the summary and the expand key, got:\n%s", out)
+	}
+	if m.viewport.Height != height-1 {
+		t.Errorf("viewport height = %d, want %d to make room for the banner", m.viewport.Height, height-1)
+	}
+
+	m.SetDiagnosis(nil)
+	if strings.Contains(stripAnsiCodes(m.View()), "[i] why") || m.viewport.Height != height {
+		t.Error("clearing the diagnosis should remove the banner")
+	}
+}
+
+func podTopNames(v PodTopViewer) string {
+	var names []string
+	for _, r := range v.rows {
+		names = append(names, r.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestPodTopViewer_Sort(t *testing.T) {
+	v := NewPodTopViewer()
+	v.SetSize(160, 40)
+	v.Show("default")
+	v.SetUsage([]repository.PodUsage{
+		{Name: "api", HasMetrics: true, CPUMilli: 200, MemoryBytes: 300, MemoryLimitBytes: 1000},
+		{Name: "worker", HasMetrics: true, CPUMilli: 900, MemoryBytes: 100, MemoryLimitBytes: 100},
+		{Name: "cache", HasMetrics: true, CPUMilli: 50, MemoryBytes: 800},
+	}, nil)
+
+	// Highest CPU first by default
+	if got := podTopNames(v); got != "worker,api,cache" {
+		t.Errorf("default order = %s, want by CPU", got)
+	}
+	key := func(r rune) {
+		v, _ = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	key('m')
+	if got := podTopNames(v); got != "cache,api,worker" {
+		t.Errorf("by memory = %s", got)
+	}
+	key('%')
+	if got := podTopNames(v); got != "worker,api,cache" {
+		t.Errorf("by memory %% of limit = %s, unlimited pods last", got)
+	}
+	key('n')
+	if got := podTopNames(v); got != "api,cache,worker" {
+		t.Errorf("by name = %s", got)
+	}
+	// The same key again flips the order
+	key('n')
+	if got := podTopNames(v); got != "worker,cache,api" {
+		t.Errorf("by name, reversed = %s", got)
+	}
+
+	// A refresh keeps the chosen order
+	v.SetUsage([]repository.PodUsage{{Name: "b"}, {Name: "c"}, {Name: "a"}}, nil)
+	if got := podTopNames(v); got != "c,b,a" {
+		t.Errorf("after refresh = %s, want the reversed name order kept", got)
+	}
+}
+
+func TestPodTopViewer_View(t *testing.T) {
+	v := NewPodTopViewer()
+	v.SetSize(160, 40)
+	v.Show("default")
+	if out := v.View(); !strings.Contains(out, "Loading") {
+		t.Errorf("before data arrives, View() = %q", out)
+	}
+
+	v.SetUsage([]repository.PodUsage{
+		{Name: "web", HasMetrics: true, CPUMilli: 250, CPURequestMilli: 500, CPULimitMilli: 1000,
+			MemoryBytes: 95 * 1024 * 1024, MemoryRequestBytes: 64 * 1024 * 1024, MemoryLimitBytes: 100 * 1024 * 1024},
+		{Name: "pending"},
+	}, nil)
+	out := stripAnsiCodes(v.View())
+	for _, want := range []string{"NAME", "CPU▼", "web", "250m", "500m/1", "50%", "25%", "95Mi", "64Mi/100Mi", "95%", "pending"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("View() should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestPodTopViewer_MetricsUnavailable(t *testing.T) {
+	v := NewPodTopViewer()
+	v.SetSize(160, 40)
+	v.Show("default")
+	v.SetUsage(nil, fmt.Errorf("%w: not found", repository.ErrMetricsUnavailable))
+	out := stripAnsiCodes(v.View())
+	if !strings.Contains(out, "metrics-server is not installed") {
+		t.Errorf("a missing metrics-server should be explained, got:\n%s", out)
+	}
+	if strings.Contains(out, "Error:") {
+		t.Errorf("a missing metrics-server is not an error, got:\n%s", out)
+	}
+
+	v, cmd := v.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if v.IsVisible() || cmd == nil {
+		t.Error("esc should close the table")
+	}
+}
+
+func nodeCapacityNames(v NodeCapacityViewer) string {
+	var names []string
+	for _, n := range v.rows {
+		names = append(names, n.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestNodeCapacityViewer_Sort(t *testing.T) {
+	nodes := []repository.NodeCapacity{
+		{Name: "a", Ready: true, AllocatableCPU: 4000, RequestedCPU: 3500, AllocatableMemory: 8 << 30, RequestedMemory: 1 << 30, Pods: 10, MaxPods: 110},
+		{Name: "b", Ready: true, AllocatableCPU: 4000, RequestedCPU: 1000, AllocatableMemory: 8 << 30, RequestedMemory: 6 << 30, Pods: 100, MaxPods: 110},
+		{Name: "c", Ready: true, AllocatableCPU: 2000, RequestedCPU: 1500, AllocatableMemory: 4 << 30, RequestedMemory: 3 << 30, Pods: 5, MaxPods: 110},
+	}
+	v := NewNodeCapacityViewer()
+	v.SetSize(200, 40)
+	v.Show(nil)
+	v.SetCapacity(append([]repository.NodeCapacity(nil), nodes...), nil)
+
+	// Most free CPU first by default
+	if got := nodeCapacityNames(v); got != "b,a,c" {
+		t.Errorf("default order = %s, want by free CPU", got)
+	}
+	key := func(r rune) {
+		v, _ = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	key('m')
+	if got := nodeCapacityNames(v); got != "a,b,c" {
+		t.Errorf("by free memory = %s", got)
+	}
+	key('p')
+	if got := nodeCapacityNames(v); got != "c,a,b" {
+		t.Errorf("by free pods = %s", got)
+	}
+
+	// For a pod, the nodes it fits on come first
+	v.Show(&repository.PodInfo{Name: "api", Namespace: "shop"})
+	nodes[1].Failures = []repository.PredicateFailure{{Predicate: repository.PredicateTaintToleration, Reason: "taint dedicated=db:NoSchedule not tolerated"}}
+	v.SetCapacity(append([]repository.NodeCapacity(nil), nodes...), nil)
+	if got := nodeCapacityNames(v); got != "c,a,b" {
+		t.Errorf("for a pod = %s, want fitting nodes first", got)
+	}
+}
+
+func TestNodeCapacityViewer_View(t *testing.T) {
+	v := NewNodeCapacityViewer()
+	v.SetSize(220, 40)
+	v.Show(&repository.PodInfo{Name: "api", Namespace: "shop"})
+	v.SetCapacity([]repository.NodeCapacity{
+		{Name: "node-a", Ready: true, AllocatableCPU: 2000, RequestedCPU: 1500, AllocatableMemory: 4 << 30, RequestedMemory: 1 << 30, Pods: 3, MaxPods: 110},
+		{Name: "node-b", Ready: true, Unschedulable: true, AllocatableCPU: 2000, AllocatableMemory: 4 << 30, MaxPods: 110, Failures: []repository.PredicateFailure{
+			{Predicate: repository.PredicateNodeUnschedulable, Reason: "node cordoned"},
+			{Predicate: repository.PredicateNodeResourcesFit, Reason: "Insufficient cpu (500m short)"},
+		}},
+	}, nil)
+
+	view := stripAnsiCodes(v.View())
+	for _, want := range []string{"shop/api > node capacity", "[fits 1/2 nodes]", "1500m/2", "75%", "3/110", "✓", "node-b (cordoned)", "✗ node cordoned; Insufficient cpu (500m short)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestNamespaceCompare_Picker(t *testing.T) {
+	c := NewNamespaceCompare()
+	c.SetSize(160, 40)
+	c.ShowPicker("staging", []string{"default", "prod", "prod-eu", "staging"})
+	if out := stripAnsiCodes(c.View()); strings.Contains(out, "> staging") || !strings.Contains(out, "Compare staging with") {
+		t.Errorf("the base namespace should not be offered, got:\n%s", out)
+	}
+
+	for _, r := range "eu" {
+		c, _ = c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if got := c.filteredNamespaces(); len(got) != 1 || got[0] != "prod-eu" {
+		t.Fatalf("filtered = %v, want prod-eu", got)
+	}
+
+	c, cmd := c.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should request the comparison")
+	}
+	if req, ok := cmd().(NamespaceCompareRequest); !ok || req.Left != "staging" || req.Right != "prod-eu" {
+		t.Errorf("request = %+v, want staging ⇄ prod-eu", req)
+	}
+	if out := stripAnsiCodes(c.View()); !strings.Contains(out, "Loading both namespaces") {
+		t.Errorf("should show loading after picking, got:\n%s", out)
+	}
+}
+
+func TestNamespaceCompare_Diff(t *testing.T) {
+	c := NewNamespaceCompare()
+	c.SetSize(160, 40)
+	c.ShowPicker("staging", []string{"prod"})
+	c, _ = c.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	c.SetDiff(&repository.NamespaceDiff{
+		Left:  "staging",
+		Right: "prod",
+		Objects: []repository.ObjectDiff{
+			{Kind: "Deployment", Name: "api", Presence: repository.InBoth, Fields: []repository.FieldDiff{
+				{Field: "image 1", Left: "shop/api:1.5.0", Right: "shop/api:1.4.2"},
+			}},
+			{Kind: "Deployment", Name: "web", Presence: repository.InBoth},
+			{Kind: "ConfigMap", Name: "prod-only", Presence: repository.RightOnly},
+		},
+	}, nil)
+
+	out := stripAnsiCodes(c.View())
+	for _, want := range []string{"image 1", "shop/api:1.5.0", "shop/api:1.4.2", "prod-only", "missing", "2 of 3 objects differ"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("View() missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "web") {
+		t.Errorf("identical objects should be hidden by default:\n%s", out)
+	}
+
+	c, _ = c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if out := stripAnsiCodes(c.View()); !strings.Contains(out, "web") || !strings.Contains(out, "identical") {
+		t.Errorf("a should show identical objects:\n%s", out)
+	}
+
+	c.SetDiff(nil, errors.New("forbidden"))
+	if out := stripAnsiCodes(c.View()); !strings.Contains(out, "Comparison failed: forbidden") {
+		t.Errorf("should show the error, got:\n%s", out)
+	}
+}
+
+func TestRenderStatefulSetDetails(t *testing.T) {
+	d := &repository.StatefulSetDetails{
+		Name:                "db",
+		Namespace:           "shop",
+		UpdateStrategy:      "RollingUpdate",
+		Partition:           2,
+		PodManagementPolicy: "OrderedReady",
+		CurrentRevision:     "db-old",
+		UpdateRevision:      "db-new",
+		ClaimTemplates:      []repository.ClaimTemplate{{Name: "data", Size: "10Gi", AccessModes: "ReadWriteOnce"}},
+		Ordinals: []repository.StatefulSetOrdinal{
+			{Ordinal: 0, Pod: "db-0", Exists: true, Status: "Running", Ready: true, Revision: "db-old",
+				Claims: []repository.OrdinalClaim{{Template: "data", Name: "data-db-0", Phase: "Bound"}}},
+			{Ordinal: 1, Pod: "db-1", Status: "Missing",
+				Claims: []repository.OrdinalClaim{{Template: "data", Name: "data-db-1", Phase: "Missing"}}},
+			{Ordinal: 2, Pod: "db-2", Exists: true, Status: "Running", Ready: true, Revision: "db-new", Updated: true,
+				Claims: []repository.OrdinalClaim{{Template: "data", Name: "data-db-2", Phase: "Pending"}}},
+		},
+	}
+	out := stripAnsiCodes(RenderStatefulSetDetails(d))
+	for _, want := range []string{"ordinals below 2 keep the current revision", "Update revision:", "1/3", "old db-old", "new db-new",
+		"pvc data-db-1", "Missing", "Pending", "10Gi, ReadWriteOnce, <default>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderStatefulSetDetails() missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPartitionActions(t *testing.T) {
+	d := &repository.StatefulSetDetails{Name: "db", Namespace: "shop", UpdateStrategy: "RollingUpdate", Partition: 2}
+	items := PartitionActions(d)
+	if len(items) != 3 || items[0].Action != "partition" || items[0].Partition != 1 || items[1].Partition != 0 {
+		t.Fatalf("PartitionActions() = %+v, want 2 → 1, 2 → 0 and copy", items)
+	}
+	if !strings.Contains(items[2].Command, "statefulset db -n shop") {
+		t.Errorf("copy command = %q", items[2].Command)
+	}
+	if disabled := DisableMutatingWorkloadActions(items); !disabled[0].Disabled {
+		t.Error("partition changes should be disabled in read-only mode")
+	}
+
+	d.Partition = 1
+	if items := PartitionActions(d); len(items) != 2 || items[0].Partition != 0 {
+		t.Errorf("PartitionActions() = %+v, want only 1 → 0", items)
+	}
+	d.Partition = 0
+	if items := PartitionActions(d); items[0].Action != "" {
+		t.Errorf("an unpartitioned update has nothing to advance, got %+v", items[0])
+	}
+}
+
+func TestContainerImageActions(t *testing.T) {
+	images := []repository.ContainerImage{
+		{Container: "migrate", Image: "app:v1", Init: true},
+		{Container: "app", Image: "app:v1"},
+	}
+	items := ContainerImageActions("shop", "Deployment", "web", images)
+	if len(items) != 3 || items[0].Label != "migrate (init)" || items[1].Container != "app" || items[1].Image != "app:v1" {
+		t.Fatalf("ContainerImageActions() = %+v, want both containers and copy", items)
+	}
+	if items[2].Command != "kubectl set image deployment/web -n shop CONTAINER=IMAGE" {
+		t.Errorf("copy command = %q", items[2].Command)
+	}
+	if disabled := DisableMutatingWorkloadActions(items); !disabled[1].Disabled || disabled[2].Disabled {
+		t.Error("image changes should be disabled in read-only mode, copying the command not")
+	}
+	if items := ContainerImageActions("shop", "Rollout", "web", images); !strings.HasPrefix(items[2].Command, "kubectl argo rollouts set image web") {
+		t.Errorf("rollout copy command = %q", items[2].Command)
+	}
+}
+
+func TestRefreshTicker(t *testing.T) {
+	ticker := NewRefreshTicker(time.Millisecond)
+	fetched := 0
+	fetch := func() tea.Msg {
+		fetched++
+		return nil
+	}
+
+	// runAll runs a command and the commands it batches
+	var runAll func(cmd tea.Cmd) []tea.Msg
+	runAll = func(cmd tea.Cmd) []tea.Msg {
+		if cmd == nil {
+			return nil
+		}
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			var msgs []tea.Msg
+			for _, c := range batch {
+				msgs = append(msgs, runAll(c)...)
+			}
+			return msgs
+		}
+		return []tea.Msg{msg}
+	}
+	hasTick := func(msgs []tea.Msg) bool {
+		for _, msg := range msgs {
+			if _, ok := msg.(RefreshTickMsg); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if msgs := runAll(ticker.Tick(fetch, fetch)); fetched != 2 || !hasTick(msgs) {
+		t.Fatalf("Tick() fetched %d times, ticked %v; want 2 fetches and the next tick", fetched, hasTick(msgs))
+	}
+
+	if !ticker.TogglePause() || !ticker.Paused() {
+		t.Fatal("TogglePause() should pause")
+	}
+	fetched = 0
+	if msgs := runAll(ticker.Tick(fetch, fetch)); fetched != 0 || !hasTick(msgs) {
+		t.Errorf("paused Tick() fetched %d times, ticked %v; want no fetches but the next tick", fetched, hasTick(msgs))
+	}
+
+	ticker.TogglePause()
+	if runAll(ticker.Tick(fetch)); fetched != 1 {
+		t.Errorf("resumed Tick() fetched %d times, want 1", fetched)
+	}
+	if ticker.Interval() != time.Millisecond {
+		t.Errorf("Interval() = %v", ticker.Interval())
+	}
+}
+
+func TestWarningsViewer(t *testing.T) {
+	v := NewWarningsViewer()
+	v.SetSize(140, 40)
+	v.Show("prod", nil)
+	if !strings.Contains(v.View(), "No warning events in the last 15m") {
+		t.Errorf("empty viewer should say there are no warnings:\n%s", v.View())
+	}
+
+	now := time.Now()
+	v.SetWarnings("prod", []repository.EventInfo{
+		{Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", Object: "Pod/api-1", Count: 5, LastSeen: now, Age: "1m"},
+		{Type: "Warning", Reason: "FailedMount", Message: "secret not found", Object: "Pod/gone", Count: 1, LastSeen: now, Age: "2m"},
+	})
+	view := v.View()
+	for _, want := range []string{"Pod/api-1", "BackOff", "Back-off restarting", "[2 objects, last 15m]"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() should contain %q:\n%s", want, view)
+		}
+	}
+
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	v, cmd := v.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should jump to the selected object")
+	}
+	if req, ok := cmd().(JumpToWarningRequest); !ok || req.Object != "Pod/gone" || req.Namespace != "prod" {
+		t.Errorf("enter sent %+v, want JumpToWarningRequest for Pod/gone in prod", req)
+	}
+
+	// A deleted object shows its events instead
+	v.ShowDetail("Pod/gone", "Pod/gone no longer exists")
+	if view := v.View(); !strings.Contains(view, "no longer exists") || !strings.Contains(view, "secret not found") {
+		t.Errorf("detail should show the note and the events:\n%s", view)
+	}
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !v.IsVisible() || strings.Contains(v.View(), "no longer exists") {
+		t.Error("esc in the detail should go back to the list")
+	}
+
+	v, cmd = v.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if v.IsVisible() || cmd == nil {
+		t.Fatal("esc should close the viewer")
+	}
+	if _, ok := cmd().(WarningsViewerClosed); !ok {
+		t.Error("closing should send WarningsViewerClosed")
+	}
+}
+
+func TestDaemonSetNodesViewer(t *testing.T) {
+	workload := &repository.WorkloadInfo{Name: "log-agent", Namespace: "infra", Type: repository.ResourceDaemonSets}
+	v := NewDaemonSetNodesViewer()
+	v.SetSize(160, 40)
+	v.Show(workload, &repository.DaemonSetNodes{
+		Name:      "log-agent",
+		Namespace: "infra",
+		Desired:   3,
+		Ready:     1,
+		Nodes: []repository.DaemonSetNode{
+			{Node: "gpu-1", Status: repository.DaemonSetNodeMissing, Reason: "taint nvidia.com/gpu=true:NoSchedule not tolerated"},
+			{Node: "node-c", Pod: "log-agent-c", Restarts: 12, Status: "CrashLoopBackOff"},
+			{Node: "node-a", Pod: "log-agent-a", Ready: true, Cordoned: true, Status: "Running"},
+		},
+		NotTargeted: 2,
+	})
+
+	view := v.View()
+	for _, want := range []string{"1/3 ready, 1 nodes without a pod", "not tolerated", "CrashLoopBackOff", "2 nodes not targeted"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// A node without a pod has nothing to open
+	if _, cmd := v.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("Enter on a node without a pod should do nothing")
+	}
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	_, cmd := v.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Enter on a pod should open it")
+	}
+	if req, ok := cmd().(OpenDaemonSetPodRequest); !ok || req.Pod != "log-agent-c" || req.Workload != workload {
+		t.Errorf("Enter sent %+v, want the pod of node-c", cmd())
+	}
+}
+
+func TestCronJobRunsViewer(t *testing.T) {
+	v := NewCronJobRunsViewer()
+	v.SetSize(160, 40)
+	schedule := &repository.CronJobSchedule{
+		Schedule:         "0 2 * * *",
+		LastScheduleTime: time.Now().Add(-8 * time.Hour),
+		NextScheduleTime: time.Now().Add(16 * time.Hour),
+	}
+	v.Show("batch", "nightly-report", schedule, []repository.JobRun{
+		{Name: "nightly-report-2", Status: "Failed", Age: "8h", Duration: 90 * time.Second, Failed: 3, Pod: "nightly-report-2-xyz"},
+		{Name: "nightly-report-1", Status: "Complete", Age: "1d", Duration: time.Minute, Succeeded: 1},
+	})
+	view := v.View()
+	for _, want := range []string{"0 2 * * *", "Next run", "(in 15h)", "nightly-report-2", "Failed", "1m30s", "0/3", "(deleted)", "[2 runs, 1 failed]"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() should contain %q:\n%s", want, view)
+		}
+	}
+
+	v, cmd := v.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should open the logs of the run's pod")
+	}
+	if req, ok := cmd().(OpenJobRunRequest); !ok || req.Pod != "nightly-report-2-xyz" || req.Namespace != "batch" {
+		t.Errorf("enter sent %+v, want OpenJobRunRequest for nightly-report-2-xyz", req)
+	}
+
+	// The pod of the older run was deleted with its Job's TTL
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	v, cmd = v.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Error("enter on a run without a pod should not open anything")
+	}
+	if !strings.Contains(v.View(), "nightly-report-1: logs unavailable (pod deleted)") {
+		t.Errorf("View() should say the logs are unavailable:\n%s", v.View())
+	}
+
+	v.Show("batch", "paused", &repository.CronJobSchedule{Schedule: "@daily", Suspended: true}, nil)
+	if view := v.View(); !strings.Contains(view, "suspended") || !strings.Contains(view, "No runs") {
+		t.Errorf("suspended CronJob without runs:\n%s", view)
+	}
+
+	v, cmd = v.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if v.IsVisible() || cmd == nil {
+		t.Fatal("esc should close the viewer")
+	}
+	if _, ok := cmd().(CronJobRunsViewerClosed); !ok {
+		t.Error("closing should send CronJobRunsViewerClosed")
+	}
+}
+
+func TestOSC52Sequence(t *testing.T) {
+	noEnv := func(string) string { return "" }
+	if got, want := osc52Sequence("hello", noEnv), "\x1b]52;c;aGVsbG8=\a"; got != want {
+		t.Errorf("osc52Sequence() = %q, want %q", got, want)
+	}
+
+	tmux := func(name string) string {
+		if name == "TMUX" {
+			return "/tmp/tmux-1000/default,1,0"
+		}
+		return ""
+	}
+	if got, want := osc52Sequence("hello", tmux), "\x1bPtmux;\x1b\x1b]52;c;aGVsbG8=\a\x1b\\"; got != want {
+		t.Errorf("osc52Sequence() in tmux = %q, want %q", got, want)
+	}
+
+	screen := func(name string) string {
+		if name == "STY" {
+			return "1234.pts-0.host"
+		}
+		return ""
+	}
+	if got, want := osc52Sequence("hello", screen), "\x1bP\x1b]52;c;aGVsbG8=\a\x1b\\"; got != want {
+		t.Errorf("osc52Sequence() in screen = %q, want %q", got, want)
+	}
+}
+
+func TestCopyToClipboardOSC52(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("STY", "")
+	var out bytes.Buffer
+	osc52Output = &out
+	defer func() {
+		osc52Output = os.Stdout
+		SetClipboard(configs.ClipboardAuto, configs.DefaultOSC52MaxBytes)
+	}()
+
+	SetClipboard(configs.ClipboardOSC52, 8)
+	result, err := CopyToClipboard("kubectl get pods")
+	if err != nil {
+		t.Fatalf("CopyToClipboard() error = %v", err)
+	}
+	if result.Method != ClipboardOSC52 || !result.Truncated || result.Bytes != 8 {
+		t.Errorf("CopyToClipboard() = %+v, want OSC52 truncated to 8 bytes", result)
+	}
+	if want := "\x1b]52;c;a3ViZWN0bCA=\a"; out.String() != want {
+		t.Errorf("wrote %q, want %q", out.String(), want)
+	}
+	if !strings.Contains(result.Status(), "via OSC52 (truncated to 8 bytes") {
+		t.Errorf("Status() = %q, want an OSC52 truncation warning", result.Status())
+	}
+
+	// Truncation never splits a character
+	out.Reset()
+	SetClipboard(configs.ClipboardOSC52, 2)
+	if result, _ := CopyToClipboard("né"); result.Bytes != 1 || out.String() != "\x1b]52;c;bg==\a" {
+		t.Errorf("CopyToClipboard(\"né\") = %+v, wrote %q; want only \"n\"", result, out.String())
+	}
+
+	out.Reset()
+	SetClipboard(configs.ClipboardOSC52, 100)
+	if result, _ := CopyToClipboard("short"); result.Truncated || result.Status() != "Copied to clipboard via OSC52" {
+		t.Errorf("CopyToClipboard() = %+v, %q", result, result.Status())
+	}
+	// Auto mode never falls back to OSC52 when the output is not a terminal
+	file, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	osc52Output = file
+	SetClipboard(configs.ClipboardAuto, 100)
+	if result, _ := CopyToClipboard("short"); result.Method == ClipboardOSC52 {
+		t.Errorf("CopyToClipboard() = %+v, want no OSC52 copy to a file", result)
+	}
+	if info, _ := file.Stat(); info.Size() != 0 {
+		t.Errorf("wrote %d bytes to a file, want none", info.Size())
+	}
 }
 
-// ============================================
-// EventsPanel Extended Tests
-// ============================================
+func TestParseColumns(t *testing.T) {
+	c, err := ParseColumns(nil)
+	if err != nil || strings.Join(c.Names(), ",") != "age,pods" {
+		t.Errorf("ParseColumns(nil) = %v, %v; want the defaults age,pods", c.Names(), err)
+	}
+
+	c, err = ParseColumns([]string{"Node", "label:app.kubernetes.io/Name", "restarts"})
+	if err != nil {
+		t.Fatalf("ParseColumns() error = %v", err)
+	}
+	// Names are in display order and keep the case of the label key
+	if got := strings.Join(c.Names(), ","); got != "restarts,node,label:app.kubernetes.io/Name" {
+		t.Errorf("Names() = %q", got)
+	}
+	if c.labelTitle() != "NAME" {
+		t.Errorf("labelTitle() = %q, want NAME", c.labelTitle())
+	}
+
+	c, err = ParseColumns([]string{})
+	if err != nil || len(c.Names()) != 0 {
+		t.Errorf("ParseColumns([]) = %v, %v; want no optional columns", c.Names(), err)
+	}
 
-func TestEventsPanel_Update_WKey(t *testing.T) {
-	ep := NewEventsPanel()
-	ep.SetEvents([]repository.EventInfo{
-		{Type: "Normal", Reason: "Scheduled"},
-		{Type: "Warning", Reason: "BackOff"},
-	})
+	for _, bad := range [][]string{{"cpu"}, {"label"}, {"label:"}, {"age:x"}} {
+		if _, err := ParseColumns(bad); err == nil {
+			t.Errorf("ParseColumns(%q) should fail", bad)
+		}
+	}
+}
 
-	// Press w to toggle warnings filter
-	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+func workloadColumnsNavigator(t *testing.T, width int, names ...string) Navigator {
+	t.Helper()
+	columns, err := ParseColumns(names)
+	if err != nil {
+		t.Fatalf("ParseColumns() error = %v", err)
+	}
+	nav := NewNavigator()
+	nav.SetSize(width, 20)
+	nav.SetMode(ModeWorkloads)
+	nav.SetColumns(columns)
+	nav.SetWorkloads([]repository.WorkloadInfo{{
+		Name: "payments-api-with-a-very-long-deployment-name", Namespace: "default",
+		Type: repository.ResourceDeployments, Ready: "2/2", Status: "Running", Age: "3d",
+		RestartCount: 4, Nodes: []string{"node-a", "node-b"},
+		Images:       []string{"registry.example.com/team/nginx:1.25", "envoy:v1.29"},
+		ObjectLabels: map[string]string{"team": "payments"},
+		Health:       &repository.PodHealth{Total: 2, Ready: 2},
+	}})
+	return nav
+}
+
+// workloadTableLines returns the header and the row of the workloads table.
+func workloadTableLines(t *testing.T, nav Navigator) (header, row string) {
+	t.Helper()
+	for _, line := range strings.Split(nav.View(), "\n") {
+		switch {
+		case strings.Contains(line, "NAME"):
+			header = line
+		case strings.Contains(line, "payments-"):
+			row = line
+		}
+	}
+	if header == "" || row == "" {
+		t.Fatalf("workloads table not found in view:\n%s", nav.View())
+	}
+	return header, row
 }
 
-func TestEventsPanel_Update_CKey(t *testing.T) {
-	ep := NewEventsPanel()
-	ep.SetEvents([]repository.EventInfo{
-		{Type: "Normal", Reason: "Scheduled"},
-	})
+func TestNavigator_WorkloadColumnsOrder(t *testing.T) {
+	nav := workloadColumnsNavigator(t, 220, "label:team", "images", "node", "restarts")
+	header, row := workloadTableLines(t, nav)
 
-	// Enter filter mode
-	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
-	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	// Optional columns follow NAME, READY and STATUS in a fixed order,
+	// whatever order they are configured in
+	last := -1
+	for _, title := range []string{"NAME", "READY", "STATUS", "RESTARTS", "NODE", "IMAGES", "TEAM"} {
+		i := strings.Index(header, title)
+		if i <= last {
+			t.Fatalf("header %q: %s out of order", header, title)
+		}
+		last = i
+	}
+	for _, title := range strings.Fields(header) {
+		if title == "AGE" || title == "PODS" {
+			t.Errorf("header %q shows %s, which is not enabled", header, title)
+		}
+	}
+	if strings.ContainsAny(header, "◂▸") {
+		t.Errorf("header %q marks hidden columns, but all fit", header)
+	}
 
-	// Press c to clear filter
-	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	last = -1
+	for _, cell := range []string{"2/2", "Running", "4", "node-a +1", "nginx:1.25,envoy:v1.29", "payments"} {
+		i := strings.Index(row[last+1:], cell)
+		if i < 0 {
+			t.Fatalf("row %q: %q missing or out of order", row, cell)
+		}
+		last += i + 1
+	}
 }
 
-func TestEventsPanel_Update_EscKey(t *testing.T) {
-	ep := NewEventsPanel()
-	ep.SetEvents([]repository.EventInfo{
-		{Type: "Normal", Reason: "Scheduled"},
-	})
+func TestNavigator_WorkloadColumnsNarrow(t *testing.T) {
+	nav := workloadColumnsNavigator(t, 60, "restarts", "age", "pods", "node")
+	header, row := workloadTableLines(t, nav)
+	for _, line := range []string{header, row} {
+		if w := lipgloss.Width(line); w > 60 {
+			t.Errorf("line is %d wide, want at most 60: %q", w, line)
+		}
+	}
+	if !strings.Contains(row, "payments-api-with-a-very-long...") {
+		t.Errorf("row %q should truncate the name to 32 characters", row)
+	}
+	// STATUS is clipped and the columns after it are hidden
+	if !strings.Contains(header, "STATUS") || strings.Contains(header, "RESTARTS") || !strings.HasSuffix(strings.TrimSpace(header), "▸") {
+		t.Errorf("header %q should end with STATUS and mark hidden columns", header)
+	}
 
-	// Enter filter mode
-	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	// Scrolling keeps NAME and shows the columns hidden on the right
+	for i := 0; i < 2; i++ {
+		nav, _ = nav.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'>'}})
+	}
+	header, row = workloadTableLines(t, nav)
+	if !strings.Contains(header, "NAME") || !strings.Contains(header, "RESTARTS") || !strings.Contains(header, "AGE") {
+		t.Errorf("scrolled header %q should show NAME, RESTARTS and AGE", header)
+	}
+	if strings.Contains(header, "READY") || !strings.Contains(header, "◂▸") {
+		t.Errorf("scrolled header %q should hide READY and mark both sides", header)
+	}
+	if strings.Contains(row, "Running") || !strings.Contains(row, "3d") {
+		t.Errorf("scrolled row %q", row)
+	}
 
-	// Press Esc
-	ep, _ = ep.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	nav, _ = nav.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'<'}})
+	nav, _ = nav.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'<'}})
+	nav, _ = nav.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'<'}})
+	if header, _ = workloadTableLines(t, nav); !strings.Contains(header, "READY") || strings.Contains(header, "◂") {
+		t.Errorf("header %q should be scrolled back to READY", header)
+	}
+
+	// Too narrow for NAME at full width: it shrinks, the row still fits
+	nav = workloadColumnsNavigator(t, 30, "age")
+	header, row = workloadTableLines(t, nav)
+	if !strings.Contains(header, "READY") || !strings.Contains(row, "2/2") {
+		t.Errorf("narrow table should keep READY: %q / %q", header, row)
+	}
+	if w := lipgloss.Width(row); w > 30 {
+		t.Errorf("row is %d wide, want at most 30: %q", w, row)
+	}
 }
 
-// ============================================
-// LogsPanel Extended Tests
-// ============================================
+func TestColumnPicker(t *testing.T) {
+	p := NewColumnPicker()
+	p.Show(DefaultColumns())
+	if !strings.Contains(p.View(), "[x] AGE") || !strings.Contains(p.View(), "[ ] RESTARTS") {
+		t.Fatalf("picker should show the current columns:\n%s", p.View())
+	}
 
-func TestLogsPanel_Update_WKey(t *testing.T) {
-	lp := NewLogsPanel()
-	lp.SetLogs([]repository.LogLine{
-		{Content: "Normal log", IsError: false},
-		{Content: "Error log", IsError: true},
-	})
+	// Toggle RESTARTS on
+	p, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	if cmd == nil {
+		t.Fatal("toggling should send ColumnsChanged")
+	}
+	changed, ok := cmd().(ColumnsChanged)
+	if !ok || !changed.Columns.Enabled(ColumnRestarts) {
+		t.Errorf("toggle = %#v, want restarts enabled", changed)
+	}
 
-	// Press w to toggle errors filter
-	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	// The label column asks for a key before it can be shown
+	for i := 0; i < len(optionalColumns)-1; i++ {
+		p, _ = p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	p, cmd = p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("toggling the label column without a key should ask for one")
+	}
+	if _, ok := cmd().(ColumnLabelKeyRequest); !ok {
+		t.Errorf("got %#v, want ColumnLabelKeyRequest", cmd())
+	}
+	p.SetColumns(changed.Columns.WithLabel("team"))
+	if !strings.Contains(p.View(), "[x] LABEL team") {
+		t.Errorf("picker should show the label key:\n%s", p.View())
+	}
+
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if p.IsVisible() {
+		t.Error("esc should close the picker")
+	}
 }
 
-func TestLogsPanel_Update_CKey(t *testing.T) {
-	lp := NewLogsPanel()
-	lp.SetLogs([]repository.LogLine{
-		{Content: "Normal log", IsError: false},
-	})
+func TestNotifications_ForbiddenListToast(t *testing.T) {
+	err := apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "",
+		errors.New(`User "dev" cannot list resource "deployments" in API group "apps" in the namespace "prod"`))
 
-	// Enter filter mode
-	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
-	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	var ns Notifications
+	if cmd := ns.Push(ErrorNotification("list deployments", "prod", err)); cmd == nil {
+		t.Fatal("Push should return a command expiring the toast")
+	}
+	toast := ns.ToastView(200)
+	for _, want := range []string{"deployments.apps", "403", "list deployments in prod"} {
+		if !strings.Contains(toast, want) {
+			t.Errorf("toast %q should contain %q", toast, want)
+		}
+	}
+	if ns.Toast().Severity != SeverityError {
+		t.Errorf("severity = %v, want error", ns.Toast().Severity)
+	}
 
-	// Press c to clear filter
-	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	// The same failure on the next refresh is counted, not logged again
+	ns.Push(ErrorNotification("list deployments", "prod", err))
+	if len(ns.Log()) != 1 || ns.Log()[0].Count != 2 {
+		t.Errorf("log = %+v, want one entry seen twice", ns.Log())
+	}
+	if !strings.Contains(ns.ToastView(200), "(x2)") {
+		t.Errorf("toast %q should show the repeat count", ns.ToastView(200))
+	}
+
+	// Only the latest toast expires it
+	ns.Push(ErrorNotification("get pod", "prod", errors.New("connection refused")))
+	ns.Expire(1)
+	if ns.Toast() == nil {
+		t.Error("an older toast's expiry should not hide the latest")
+	}
+	ns.Expire(3)
+	if ns.Toast() != nil {
+		t.Error("the toast should expire")
+	}
+	if len(ns.Log()) != 2 {
+		t.Errorf("expired toasts stay in the log, got %d entries", len(ns.Log()))
+	}
 }
 
-func TestLogsPanel_Update_GKey(t *testing.T) {
-	lp := NewLogsPanel()
-	lp.SetLogs([]repository.LogLine{
-		{Content: "Log 1", IsError: false},
-		{Content: "Log 2", IsError: false},
-	})
+func TestNotifications_Severity(t *testing.T) {
+	conflict := apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "app", errors.New("modified"))
+	if n := ErrorNotification("update configmap", "prod", conflict); n.Severity != SeverityWarning {
+		t.Errorf("conflict severity = %v, want warning", n.Severity)
+	}
+	if n := ErrorNotification("list pods", "", errors.New("dial tcp: connection refused")); n.Severity != SeverityError || n.Text() != "list pods: dial tcp: connection refused" {
+		t.Errorf("got %+v (%q), want an error with the plain message", n, n.Text())
+	}
+	timeout := fmt.Errorf("failed to list pods: %w", errors.New(`Get "https://10.0.0.1/api/v1/pods": context deadline exceeded`))
+	if n := ErrorNotification("list pods", "prod", timeout); n.Severity != SeverityWarning || n.Message != "timed out waiting for the API server" {
+		t.Errorf("timeout got %+v, want a warning that the API server timed out", n)
+	}
+}
 
-	// Press g to go to top
-	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+func TestErrorLogViewer(t *testing.T) {
+	var ns Notifications
+	ns.Push(Notification{Severity: SeverityError, Op: "list pods", Namespace: "prod", Message: "first"})
+	ns.Push(Notification{Severity: SeverityWarning, Op: "scale web", Namespace: "prod", Message: "second"})
 
-	// Press G to go to bottom
-	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	v := NewErrorLogViewer()
+	v.SetSize(160, 40)
+	v.Show(ns.Log())
+	view := v.View()
+	if strings.Index(view, "scale web") > strings.Index(view, "list pods") {
+		t.Errorf("newest entries should be listed first:\n%s", view)
+	}
+
+	v, cmd := v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if cmd == nil {
+		t.Fatal("c should clear the log")
+	}
+	if _, ok := cmd().(ErrorLogCleared); !ok {
+		t.Errorf("got %#v, want ErrorLogCleared", cmd())
+	}
+	if !strings.Contains(v.View(), "No errors this session") {
+		t.Errorf("cleared log should be empty:\n%s", v.View())
+	}
+
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+	if v.IsVisible() {
+		t.Error("E should close the viewer")
+	}
 }
 
-func TestLogsPanel_Update_EscKey(t *testing.T) {
-	lp := NewLogsPanel()
-	lp.SetLogs([]repository.LogLine{
-		{Content: "Normal log", IsError: false},
+func TestConfigProjectionViewer(t *testing.T) {
+	v := NewConfigProjectionViewer()
+	v.SetSize(140, 30)
+	v.Show(&repository.ConfigProjection{
+		Kind: "Secret", Name: "db", Namespace: "default", Found: true,
+		Data: map[string]string{"password": "hunter2"},
+		Projections: []repository.KeyProjection{
+			{Key: "password", Container: "app", Env: true, Target: "DB_PASSWORD", Source: "env"},
+			{Key: "user", Container: "app", Env: true, Target: "DB_USER", Source: "env", Missing: true, Note: "key not found: the container can't start"},
+		},
 	})
 
-	// Enter filter mode
-	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	view := stripAnsiCodes(v.View())
+	for _, want := range []string{"$DB_PASSWORD", secretMask, "key not found", "A required key is missing"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "hunter2") {
+		t.Fatal("Secret values should be masked until revealed")
+	}
 
-	// Press Esc
-	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	_, cmd := v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	if cmd == nil {
+		t.Fatal("v should ask to reveal the selected key")
+	}
+	if req, ok := cmd().(RevealSecretKeyRequest); !ok || req.Secret != "db" || req.Key != "password" {
+		t.Errorf("v should request revealing db/password, got %+v", req)
+	}
+	v.Reveal("password")
+	if !strings.Contains(stripAnsiCodes(v.View()), "hunter2") {
+		t.Error("a revealed key should show its value")
+	}
+	v, cmd = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	if cmd != nil || strings.Contains(stripAnsiCodes(v.View()), "hunter2") {
+		t.Error("v on a revealed key should mask it again without confirmation")
+	}
+
+	// A missing key has no value to reveal
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if _, cmd = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}}); cmd != nil {
+		t.Error("v on a missing key should do nothing")
+	}
+
+	v, cmd = v.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if v.IsVisible() || cmd == nil {
+		t.Fatal("esc should close the viewer")
+	}
+	if _, ok := cmd().(ConfigProjectionClosed); !ok {
+		t.Error("esc should send ConfigProjectionClosed")
+	}
 }
 
-func TestLogsPanel_Update_PageKeys(t *testing.T) {
-	lp := NewLogsPanel()
-	lp.SetSize(80, 10)
-	lp.SetLogs([]repository.LogLine{
-		{Content: "Log 1"},
-		{Content: "Log 2"},
-		{Content: "Log 3"},
+func TestWatchesViewer(t *testing.T) {
+	v := NewWatchesViewer()
+	v.SetSize(120, 40)
+	v.Show(nil)
+	if !strings.Contains(v.View(), "No watches set") {
+		t.Errorf("empty viewer should say no watches are set:\n%s", v.View())
+	}
+
+	v.SetWatches([]repository.Watch{
+		{ID: 1, Condition: repository.WatchReady, Target: repository.WorkloadInfo{Name: "web-1", Namespace: "default", Type: repository.ResourcePods}, Since: time.Now()},
+		{ID: 3, Condition: repository.WatchWarning, Target: repository.WorkloadInfo{Name: "api", Namespace: "prod", Type: repository.ResourceDeployments}, Since: time.Now()},
 	})
+	view := v.View()
+	for _, want := range []string{"pod/web-1", "deployment/api", "Next warning", "[2 waiting]"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() should contain %q:\n%s", want, view)
+		}
+	}
 
-	// Press PgDn
-	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	v, cmd := v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if cmd == nil {
+		t.Fatal("x should cancel the selected watch")
+	}
+	if req, ok := cmd().(CancelWatchRequest); !ok || req.ID != 3 {
+		t.Errorf("x sent %+v, want CancelWatchRequest{ID: 3}", req)
+	}
 
-	// Press PgUp
-	lp, _ = lp.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	// The cancelled watch is gone from the refreshed list
+	v.SetWatches(v.watches[:1])
+	if w := v.Selected(); w == nil || w.ID != 1 {
+		t.Errorf("Selected() = %+v, want the remaining watch", w)
+	}
+
+	_, cmd = v.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if _, ok := cmd().(WatchesViewerClosed); !ok {
+		t.Error("Esc should close the viewer")
+	}
 }
 
-// ============================================
-// HPA Viewer Extended Tests
-// ============================================
+func TestPodWatchActions(t *testing.T) {
+	if items := PodWatchActions(false); len(items) != 2 || items[0].Target != string(repository.WatchReady) || items[1].Target != string(repository.WatchWarning) {
+		t.Errorf("PodWatchActions(false) = %+v, want ready and warning", items)
+	}
+	if items := PodWatchActions(true); len(items) != 1 || items[0].Target != string(repository.WatchWarning) {
+		t.Errorf("a ready pod should only offer the warning watch, got %+v", items)
+	}
+	for _, item := range WorkloadWatchActions() {
+		if item.Action != "watch" || item.Condition == "" {
+			t.Errorf("workload watch action %+v should carry its condition", item)
+		}
+	}
+}
 
-func TestHPAViewer_Update_EnterKey(t *testing.T) {
-	hv := NewHPAViewer()
-	hv.SetSize(80, 40)
-	hv.Show(&repository.HPAData{
-		Name:      "web-hpa",
-		Namespace: "default",
-	}, "default")
+func TestOSC9Sequence(t *testing.T) {
+	noEnv := func(string) string { return "" }
+	if got, want := osc9Sequence("web-1 is Ready\a\x1b]", noEnv), "\x1b]9;web-1 is Ready]\a"; got != want {
+		t.Errorf("osc9Sequence() = %q, want %q", got, want)
+	}
 
-	// Press Enter (copy to clipboard)
-	hv, _ = hv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	var out strings.Builder
+	bellOutput = &out
+	defer func() { bellOutput = os.Stdout }()
+	if err := RingBell("done"); err != nil || !strings.HasPrefix(out.String(), "\a") || !strings.Contains(out.String(), "]9;done\a") {
+		t.Errorf("RingBell() wrote %q, %v", out.String(), err)
+	}
 }
 
-func TestHPAViewer_Update_PageKeys(t *testing.T) {
-	hv := NewHPAViewer()
-	hv.SetSize(80, 20)
-	hv.Show(&repository.HPAData{
-		Name:      "web-hpa",
-		Namespace: "default",
-		Metrics: []repository.HPAMetricDetail{
-			{Type: "Resource", Name: "cpu"},
-			{Type: "Resource", Name: "memory"},
-		},
-	}, "default")
-
-	// Press PgDn
-	hv, _ = hv.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+func TestNotifications_Sticky(t *testing.T) {
+	var ns Notifications
+	if cmd := ns.Push(Notification{Op: "watch", Message: "pod/web-1 is Ready", Sticky: true}); cmd != nil {
+		t.Error("a sticky toast should not expire")
+	}
+	if ns.Toast() == nil {
+		t.Fatal("the sticky toast should be shown")
+	}
+	ns.Dismiss()
+	if ns.Toast() != nil || len(ns.Log()) != 1 {
+		t.Errorf("Dismiss() should hide the toast and keep the log, got %+v", ns.Log())
+	}
+}
 
-	// Press PgUp
-	hv, _ = hv.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+func typePalette(p Palette, text string) (Palette, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, r := range text {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		if r == ' ' {
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}}
+		}
+		var c tea.Cmd
+		p, c = p.Update(msg)
+		if c != nil {
+			cmd = c
+		}
+	}
+	return p, cmd
 }
 
-// ============================================
-// Struct Tests
-// ============================================
+func TestPalette(t *testing.T) {
+	p := NewPalette()
+	p.SetSize(120, 40)
+	p.SetNamespaces([]string{"default", "prod", "staging"})
 
-func TestPodActionItem_Struct(t *testing.T) {
-	item := PodActionItem{
-		Label:       "Delete",
-		Description: "removes pod",
-		Action:      "delete",
-		Command:     "kubectl delete pod test",
+	cmd := p.Show("staging")
+	req, ok := cmd().(PaletteIndexRequest)
+	if !ok || len(req.Namespaces) != 1 || req.Namespaces[0] != "staging" {
+		t.Fatalf("Show() should ask for the current namespace's workloads, got %+v", req)
+	}
+	p.SetIndex("staging", []PaletteItem{{Kind: "deployment", Namespace: "staging", Name: "payments"}}, nil)
+	if cmd := p.Show("staging"); cmd != nil {
+		t.Error("a namespace listed just now should not be asked for again")
 	}
 
-	if item.Label != "Delete" {
-		t.Errorf("Label = %q, want %q", item.Label, "Delete")
+	// Typing a namespace's name asks for its workloads, while matches show
+	p, cmd = typePalette(p, "prod")
+	if cmd == nil {
+		t.Fatal("matching a namespace should ask for its workloads")
 	}
-	if item.Action != "delete" {
-		t.Errorf("Action = %q, want %q", item.Action, "delete")
+	if req := cmd().(PaletteIndexRequest); len(req.Namespaces) != 1 || req.Namespaces[0] != "prod" {
+		t.Errorf("asked for %+v, want prod", req.Namespaces)
 	}
-}
-
-func TestWorkloadActionItem_Struct(t *testing.T) {
-	item := WorkloadActionItem{
-		Label:    "Scale to 5",
-		Action:   "scale",
-		Replicas: 5,
-		Command:  "kubectl scale",
+	if !strings.Contains(p.View(), "Indexing prod") {
+		t.Errorf("the palette should say prod is being indexed:\n%s", p.View())
 	}
+	p.SetIndex("prod", []PaletteItem{
+		{Kind: "deployment", Namespace: "prod", Name: "payments"},
+		{Kind: "deployment", Namespace: "prod", Name: "web"},
+	}, nil)
 
-	if item.Label != "Scale to 5" {
-		t.Errorf("Label = %q, want %q", item.Label, "Scale to 5")
+	p, _ = typePalette(p, " pay")
+	if got := p.Matches(); len(got) != 1 || got[0] != (PaletteItem{Kind: "deployment", Namespace: "prod", Name: "payments"}) {
+		t.Fatalf("Matches() = %+v, want payments in prod only", got)
 	}
-	if item.Replicas != 5 {
-		t.Errorf("Replicas = %d, want %d", item.Replicas, 5)
+	_, cmd = p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if sel, ok := cmd().(PaletteSelected); !ok || sel.Item.Name != "payments" || sel.Item.Namespace != "prod" {
+		t.Errorf("Enter sent %+v, want payments in prod", sel)
 	}
 }
 
-func TestPodActionMenuResult_Struct(t *testing.T) {
-	result := PodActionMenuResult{
-		Item: PodActionItem{
-			Label:  "Delete",
-			Action: "delete",
-		},
+func TestPalette_RecentRankHigher(t *testing.T) {
+	p := NewPalette()
+	p.SetNamespaces([]string{"default"})
+	p.SetIndex("default", []PaletteItem{
+		{Kind: "deployment", Namespace: "default", Name: "api"},
+		{Kind: "deployment", Namespace: "default", Name: "api-worker"},
+	}, nil)
+	worker := PaletteItem{Kind: "deployment", Namespace: "default", Name: "api-worker"}
+	p.Visit(worker)
+	p.Visit(PaletteItem{Kind: "pod", Namespace: "default", Name: "web-1"})
+
+	p.Show("default")
+	if got := p.Matches(); len(got) < 2 || got[0].Name != "web-1" || got[1] != worker {
+		t.Errorf("with no query the recent items come first, most recent first: %+v", got)
+	}
+	p, _ = typePalette(p, "api")
+	if got := p.Matches(); got[0] != worker {
+		t.Errorf("the visited api-worker should outrank api: %+v", got)
 	}
 
-	if result.Item.Action != "delete" {
-		t.Errorf("Item.Action = %q, want %q", result.Item.Action, "delete")
+	p.Reset()
+	if p.Show("default"); len(p.Matches()) != 0 {
+		t.Errorf("Reset() should forget everything, got %+v", p.Matches())
 	}
 }
 
-func TestWorkloadActionMenuResult_Struct(t *testing.T) {
-	result := WorkloadActionMenuResult{
-		Item: WorkloadActionItem{
-			Label:    "Restart",
-			Action:   "restart",
-			Replicas: 0,
+func TestRenderHelmRelease(t *testing.T) {
+	release := &repository.HelmRelease{
+		Name: "web", Namespace: "default", Revision: 7, Status: "deployed",
+		Chart: "nginx", ChartVersion: "15.4.2", AppVersion: "1.25.3",
+		LastDeployed: time.Now().Add(-2 * time.Hour),
+		Values: map[string]any{
+			"replicaCount": float64(3),
+			"auth":         map[string]any{"adminPassword": "hunter2"},
 		},
 	}
+	out := stripAnsiCodes(RenderHelmRelease(release))
+	for _, want := range []string{"nginx-15.4.2", "1.25.3", "7 (deployed)", "2h ago", "replicaCount: 3", "adminPassword: " + secretMask} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderHelmRelease() missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("a password should be masked:\n%s", out)
+	}
 
-	if result.Item.Action != "restart" {
-		t.Errorf("Item.Action = %q, want %q", result.Item.Action, "restart")
+	release.Values = nil
+	if out := stripAnsiCodes(RenderHelmRelease(release)); !strings.Contains(out, "No values supplied") {
+		t.Errorf("without values the section should say so:\n%s", out)
 	}
 }
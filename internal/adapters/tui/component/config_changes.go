@@ -0,0 +1,84 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// findKeyChange returns the change of key, or nil when it is unchanged.
+func findKeyChange(changes []repository.KeyChange, key string) *repository.KeyChange {
+	for i := range changes {
+		if changes[i].Key == key {
+			return &changes[i]
+		}
+	}
+	return nil
+}
+
+// keyChangeMarker is appended to the header of a key that changed since
+// the object was last viewed.
+func keyChangeMarker(changes []repository.KeyChange, key string) string {
+	if c := findKeyChange(changes, key); c != nil {
+		return "  (" + c.Change + ")"
+	}
+	return ""
+}
+
+// previousValueLine shows the first line of the value a changed ConfigMap
+// key had, cut to width. Secrets carry no previous values.
+func previousValueLine(changes []repository.KeyChange, key string, width int) (string, bool) {
+	c := findKeyChange(changes, key)
+	if c == nil || c.Change != repository.KeyChanged {
+		return "", false
+	}
+	old, rest, multiline := strings.Cut(c.Old, "\n")
+	if multiline && rest != "" {
+		old += " …"
+	}
+	if old == "" {
+		old = "(empty)"
+	}
+	return "  " + style.StatusMuted.Render("was: "+style.Truncate(old, max(width-5, 10))), true
+}
+
+// changesSummary is the breadcrumb note of how many keys changed since the
+// object was last viewed, "" when none did.
+func changesSummary(changes []repository.KeyChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [%d changed since last viewed]", len(changes))
+}
+
+// configHistoryLines lists, below the keys of a ConfigMap or Secret, the
+// keys removed since it was last viewed and when each field manager last
+// wrote which keys.
+func configHistoryLines(changes []repository.KeyChange, updates []repository.FieldManagerUpdate, now time.Time) []string {
+	var lines []string
+	var removed []string
+	for _, c := range changes {
+		if c.Change == repository.KeyRemoved {
+			removed = append(removed, c.Key)
+		}
+	}
+	if len(removed) > 0 {
+		lines = append(lines, "", style.StatusError.Render("Removed since last viewed: "+strings.Join(removed, ", ")))
+	}
+	if len(updates) == 0 {
+		return lines
+	}
+	lines = append(lines, "", style.StatusMuted.Render("Last updates (managedFields)"))
+	manager := 0
+	for _, u := range updates {
+		manager = max(manager, len(u.Manager))
+	}
+	for _, u := range updates {
+		lines = append(lines, fmt.Sprintf("  %-*s  %-6s  %4s ago  %s",
+			manager, u.Manager, u.Operation, repository.FormatAge(u.Time, now), strings.Join(u.Keys, ", ")))
+	}
+	return lines
+}
@@ -0,0 +1,260 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// ConfigProjectionViewer shows where each key of a ConfigMap or Secret
+// lands in the containers of a pod, with its value. Secret values are
+// masked until revealed one key at a time.
+type ConfigProjectionViewer struct {
+	projection *repository.ConfigProjection
+	revealed   map[string]bool // Secret keys whose value is shown
+	cursor     int
+	scroll     int
+	visible    bool
+	width      int
+	height     int
+}
+
+// ConfigProjectionClosed is sent when the viewer is closed
+type ConfigProjectionClosed struct{}
+
+// RevealSecretKeyRequest asks to show the value of a Secret key, which
+// the dashboard confirms first.
+type RevealSecretKeyRequest struct {
+	Secret string
+	Key    string
+}
+
+// secretMask stands for a Secret value that is not revealed.
+const secretMask = "••••••••"
+
+func NewConfigProjectionViewer() ConfigProjectionViewer {
+	return ConfigProjectionViewer{}
+}
+
+func (v ConfigProjectionViewer) Update(msg tea.Msg) (ConfigProjectionViewer, tea.Cmd) {
+	if !v.visible {
+		return v, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return v, nil
+	}
+	switch keyMsg.String() {
+	case "esc", "q":
+		v.visible = false
+		return v, func() tea.Msg { return ConfigProjectionClosed{} }
+	case "up", "k":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "down", "j":
+		if v.projection != nil && v.cursor < len(v.projection.Projections)-1 {
+			v.cursor++
+		}
+	case "v":
+		proj := v.Selected()
+		if proj == nil || v.projection.Kind != "Secret" || proj.Key == "" || proj.Missing {
+			return v, nil
+		}
+		if v.revealed[proj.Key] {
+			// Hiding again needs no confirmation
+			delete(v.revealed, proj.Key)
+			return v, nil
+		}
+		req := RevealSecretKeyRequest{Secret: v.projection.Name, Key: proj.Key}
+		return v, func() tea.Msg { return req }
+	}
+	v.keepCursorVisible()
+	return v, nil
+}
+
+func (v ConfigProjectionViewer) View() string {
+	if !v.visible || v.projection == nil {
+		return ""
+	}
+	p := v.projection
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Secondary)
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Primary)
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Primary).
+		Background(style.Background).
+		Padding(0, 1).
+		Width(v.width - 4)
+
+	lines := v.renderRows()
+	visible := v.rowsHeight()
+	end := min(v.scroll+visible, len(lines))
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("%s %s/%s in the pod", p.Kind, p.Namespace, p.Name)))
+	b.WriteString("\n")
+	switch {
+	case !p.Found:
+		b.WriteString(style.StatusError.Render(fmt.Sprintf("%s not found", p.Kind)))
+		b.WriteString("\n")
+	case p.Broken():
+		b.WriteString(style.StatusError.Render("A required key is missing: the containers referencing it can't start"))
+		b.WriteString("\n")
+	}
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-16s %-40s %-24s %s", "CONTAINER", "TARGET", "KEY", "SOURCE")))
+	b.WriteString("\n")
+	for _, line := range lines[v.scroll:end] {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	footer := "↑↓:select  Esc:back"
+	if p.Kind == "Secret" {
+		footer = "↑↓:select  v:reveal/hide value  Esc:back"
+	}
+	b.WriteString(style.StatusMuted.Render(footer))
+	return boxStyle.Render(b.String())
+}
+
+// renderRows renders each projection on a line, followed by its value and
+// note, then the keys no container gets.
+func (v ConfigProjectionViewer) renderRows() []string {
+	p := v.projection
+	var lines []string
+	if len(p.Projections) == 0 {
+		lines = append(lines, style.StatusMuted.Render("  No container references it"))
+	}
+	for i, proj := range p.Projections {
+		target := proj.Target
+		if proj.Env {
+			target = "$" + target
+		}
+		key := proj.Key
+		if key == "" {
+			key = "(all)"
+		}
+		row := fmt.Sprintf("%-16s %-40s %-24s %s", style.Truncate(proj.Container, 16), style.Truncate(target, 40), style.Truncate(key, 24), proj.Source)
+		switch {
+		case i == v.cursor:
+			lines = append(lines, style.CursorStyle.Render("> "+row))
+		case proj.Missing && !proj.Optional:
+			lines = append(lines, style.StatusError.Render("  "+row))
+		case proj.Skipped:
+			lines = append(lines, style.StatusMuted.Render("  "+row))
+		default:
+			lines = append(lines, "  "+row)
+		}
+
+		if value, ok := v.value(proj); ok {
+			lines = append(lines, "    "+style.Truncate(value, max(v.width-14, 10)))
+		}
+		if proj.Note != "" {
+			noteStyle := style.StatusMuted
+			if proj.Missing && !proj.Optional {
+				noteStyle = style.StatusError
+			}
+			lines = append(lines, noteStyle.Render("    "+proj.Note))
+		}
+	}
+	if len(p.Unused) > 0 {
+		lines = append(lines, "", style.StatusMuted.Render("  Not used by any container: "+strings.Join(p.Unused, ", ")))
+	}
+	return lines
+}
+
+// value returns the value to show under a projection: on one line, masked
+// for a Secret key that is not revealed. False when there is none.
+func (v ConfigProjectionViewer) value(proj repository.KeyProjection) (string, bool) {
+	if proj.Key == "" || proj.Missing {
+		return "", false
+	}
+	value, ok := v.projection.Value(proj.Key)
+	if !ok {
+		return "", false
+	}
+	if v.projection.Kind == "Secret" && !v.revealed[proj.Key] {
+		return style.StatusMuted.Render(secretMask), true
+	}
+	value = strings.ReplaceAll(value, "\n", "⏎")
+	if value == "" {
+		return style.StatusMuted.Render("(empty)"), true
+	}
+	return value, true
+}
+
+// rowsHeight is how many lines of rows fit in the viewer.
+func (v ConfigProjectionViewer) rowsHeight() int {
+	return max(v.height-12, 3)
+}
+
+// keepCursorVisible scrolls so that the selected projection and its
+// value are shown.
+func (v *ConfigProjectionViewer) keepCursorVisible() {
+	if v.projection == nil {
+		return
+	}
+	// Line of the selected projection among the rendered rows
+	line := 0
+	for i := 0; i < v.cursor && i < len(v.projection.Projections); i++ {
+		line++
+		if _, ok := v.value(v.projection.Projections[i]); ok {
+			line++
+		}
+		if v.projection.Projections[i].Note != "" {
+			line++
+		}
+	}
+	visible := v.rowsHeight()
+	if line < v.scroll {
+		v.scroll = line
+	}
+	if line+2 >= v.scroll+visible {
+		v.scroll = line + 3 - visible
+	}
+	v.scroll = max(v.scroll, 0)
+}
+
+// Show opens the viewer on a resolved projection, with every Secret value
+// masked.
+func (v *ConfigProjectionViewer) Show(projection *repository.ConfigProjection) {
+	v.projection = projection
+	v.revealed = make(map[string]bool)
+	v.cursor = 0
+	v.scroll = 0
+	v.visible = true
+}
+
+// Reveal shows the value of a Secret key.
+func (v *ConfigProjectionViewer) Reveal(key string) {
+	if v.revealed != nil {
+		v.revealed[key] = true
+	}
+}
+
+// Selected returns the projection under the cursor, nil when there is none.
+func (v ConfigProjectionViewer) Selected() *repository.KeyProjection {
+	if v.projection != nil && v.cursor < len(v.projection.Projections) {
+		return &v.projection.Projections[v.cursor]
+	}
+	return nil
+}
+
+func (v *ConfigProjectionViewer) Hide() {
+	v.visible = false
+}
+
+func (v ConfigProjectionViewer) IsVisible() bool {
+	return v.visible
+}
+
+func (v *ConfigProjectionViewer) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
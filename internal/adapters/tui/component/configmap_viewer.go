@@ -2,9 +2,9 @@ package component
 
 import (
 	"fmt"
-	"os/exec"
 	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -35,6 +35,8 @@ type ConfigMapViewer struct {
 	keyLineMap map[int]int // Maps key index to first line index
 	copied     bool        // Show "copied" feedback
 
+	changes []repository.KeyChange // Keys changed since the ConfigMap was last viewed
+
 	// Action menu and namespace selector
 	mode           ConfigMapViewerMode
 	actionCursor   int      // Action menu cursor
@@ -70,6 +72,22 @@ type ConfigMapCopyResult struct {
 	Err     error
 }
 
+// ConfigMapEditRequest is sent when the user wants to edit the value of the
+// selected key. ResourceVersion is the version the value was read at.
+type ConfigMapEditRequest struct {
+	Namespace       string
+	Name            string
+	Key             string
+	Value           string
+	ResourceVersion string
+}
+
+// ConfigMapDeleteRequest is sent when the user wants to delete the ConfigMap
+type ConfigMapDeleteRequest struct {
+	Namespace string
+	Name      string
+}
+
 // ConfigMapCopyProgress is sent during multi-namespace copy to show progress
 type ConfigMapCopyProgress struct {
 	ConfigMapName    string
@@ -122,6 +140,23 @@ func (v ConfigMapViewer) updateNormal(msg tea.KeyMsg) (ConfigMapViewer, tea.Cmd)
 		v.mode = ConfigMapViewerModeAction
 		v.actionCursor = 0
 		return v, nil
+	case "e":
+		// Edit selected key's value in $EDITOR
+		if v.keyCursor >= 0 && v.keyCursor < len(v.sortedKeys) && v.configmap != nil {
+			req := ConfigMapEditRequest{
+				Namespace:       v.namespace,
+				Name:            v.configmap.Name,
+				Key:             v.sortedKeys[v.keyCursor],
+				Value:           v.configmap.Data[v.sortedKeys[v.keyCursor]],
+				ResourceVersion: v.configmap.ResourceVersion,
+			}
+			return v, func() tea.Msg { return req }
+		}
+	case "d":
+		if v.configmap != nil {
+			req := ConfigMapDeleteRequest{Namespace: v.namespace, Name: v.configmap.Name}
+			return v, func() tea.Msg { return req }
+		}
 	case "up", "k":
 		v.copied = false
 		if v.keyCursor > 0 {
@@ -329,6 +364,9 @@ func (v *ConfigMapViewer) buildLines() {
 
 	if v.configmap == nil || len(v.configmap.Data) == 0 {
 		v.lines = append(v.lines, style.StatusMuted.Render("No data in this ConfigMap"))
+		if v.configmap != nil {
+			v.lines = append(v.lines, configHistoryLines(v.changes, v.configmap.Updates, time.Now())...)
+		}
 		return
 	}
 
@@ -348,7 +386,11 @@ func (v *ConfigMapViewer) buildLines() {
 		v.keyLineMap[i] = len(v.lines)
 
 		// Key header (will be highlighted based on selection in View)
-		v.lines = append(v.lines, key)
+		v.lines = append(v.lines, key+keyChangeMarker(v.changes, key))
+
+		if was, ok := previousValueLine(v.changes, key, maxValueWidth); ok {
+			v.lines = append(v.lines, was)
+		}
 
 		// Value with word wrapping
 		value := v.configmap.Data[key]
@@ -371,6 +413,7 @@ func (v *ConfigMapViewer) buildLines() {
 			v.lines = append(v.lines, "")
 		}
 	}
+	v.lines = append(v.lines, configHistoryLines(v.changes, v.configmap.Updates, time.Now())...)
 }
 
 func (v ConfigMapViewer) wrapText(text string, maxWidth int) []string {
@@ -418,7 +461,7 @@ func (v ConfigMapViewer) View() string {
 		separatorStyle.Render(" > ") +
 		itemStyle.Render(v.configmap.Name) +
 		separatorStyle.Render(" - ") +
-		infoStyle.Render(fmt.Sprintf("[%s] [%d keys]", v.configmap.Age, len(v.configmap.Data)))
+		infoStyle.Render(fmt.Sprintf("[%s] [%d keys]", v.configmap.Age, len(v.configmap.Data))+changesSummary(v.changes))
 	header.WriteString(breadcrumb)
 	header.WriteString("\n")
 
@@ -509,9 +552,9 @@ func (v ConfigMapViewer) View() string {
 
 	if len(v.sortedKeys) > 0 {
 		keyInfo := fmt.Sprintf("[%d/%d]", v.keyCursor+1, len(v.sortedKeys))
-		footer = style.StatusMuted.Render(fmt.Sprintf("%s ↑↓:select  Enter:copy  a:actions  Esc:close", keyInfo)) + copiedIndicator + statusIndicator
+		footer = style.StatusMuted.Render(fmt.Sprintf("%s ↑↓:select  Enter:copy  e:edit  d:delete  a:actions  Esc:close", keyInfo)) + copiedIndicator + statusIndicator
 	} else {
-		footer = style.StatusMuted.Render("a:actions  Esc:close") + statusIndicator
+		footer = style.StatusMuted.Render("d:delete  a:actions  Esc:close") + statusIndicator
 	}
 
 	result := header.String() + boxedContent + "\n" + footer
@@ -654,14 +697,29 @@ func (v ConfigMapViewer) overlayContent(base, overlay string) string {
 }
 
 func (v *ConfigMapViewer) Show(cm *repository.ConfigMapData, namespace string) {
+	// Stay on the same key and status when the ConfigMap is shown again, e.g. after an edit
+	selectedKey := ""
+	same := v.configmap != nil && cm != nil && v.configmap.Name == cm.Name && v.namespace == namespace
+	if same && v.keyCursor >= 0 && v.keyCursor < len(v.sortedKeys) {
+		selectedKey = v.sortedKeys[v.keyCursor]
+	}
 	v.configmap = cm
 	v.namespace = namespace
 	v.scroll = 0
 	v.keyCursor = 0
 	v.copied = false
 	v.mode = ConfigMapViewerModeNormal
-	v.statusMsg = ""
+	if !same {
+		v.statusMsg = ""
+	}
 	v.buildLines()
+	for i, key := range v.sortedKeys {
+		if key == selectedKey {
+			v.keyCursor = i
+			v.scrollToKey()
+			break
+		}
+	}
 	v.visible = true
 }
 
@@ -695,6 +753,12 @@ func (v *ConfigMapViewer) GetPendingRequest() *ConfigMapCopyRequest {
 	return req
 }
 
+// SetChanges sets the keys that changed since the ConfigMap was last viewed,
+// marked when it is shown next.
+func (v *ConfigMapViewer) SetChanges(changes []repository.KeyChange) {
+	v.changes = changes
+}
+
 // SetStatusMsg sets the status message shown in the footer
 func (v *ConfigMapViewer) SetStatusMsg(msg string) {
 	v.statusMsg = msg
@@ -702,19 +766,6 @@ func (v *ConfigMapViewer) SetStatusMsg(msg string) {
 
 // copyToClipboard copies text to system clipboard
 func copyToClipboard(text string) error {
-	cmd := exec.Command("pbcopy")
-	pipe, err := cmd.StdinPipe()
-	if err != nil {
-		return err
-	}
-	if err := cmd.Start(); err != nil {
-		return err
-	}
-	if _, err := pipe.Write([]byte(text)); err != nil {
-		return err
-	}
-	if err := pipe.Close(); err != nil {
-		return err
-	}
-	return cmd.Wait()
+	_, err := CopyToClipboard(text)
+	return err
 }
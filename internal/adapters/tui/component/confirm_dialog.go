@@ -5,6 +5,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/configs"
 	"github.com/andrebassi/k1s/internal/adapters/tui/style"
 )
 
@@ -16,6 +17,9 @@ type ConfirmDialog struct {
 	selected bool // true = confirm (yes), false = cancel (no)
 	action   string
 	data     interface{}
+	expected string // Text to type for typed confirmation (empty for yes/no)
+	input    string // Text typed so far
+	readOnly bool   // Refuse every Request
 }
 
 // ConfirmResult is returned when a confirmation is made
@@ -23,6 +27,18 @@ type ConfirmResult struct {
 	Confirmed bool
 	Action    string
 	Data      interface{}
+	Skipped   bool // Confirmation level is "none"; no dialog was shown
+}
+
+// ReadOnlyMsg is returned by Request instead of a confirmation when the
+// dialog is read-only.
+type ReadOnlyMsg struct {
+	Title string // Title of the refused action, e.g. "Delete Pod"
+}
+
+// Status describes the refused action for the status bar.
+func (r ReadOnlyMsg) Status() string {
+	return "Read-only mode: " + r.Title + " is disabled"
 }
 
 func NewConfirmDialog() ConfirmDialog {
@@ -40,6 +56,10 @@ func (c ConfirmDialog) Update(msg tea.Msg) (ConfirmDialog, tea.Cmd) {
 		return c, nil
 	}
 
+	if c.expected != "" {
+		return c.updateTyped(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -75,6 +95,38 @@ func (c ConfirmDialog) Update(msg tea.Msg) (ConfirmDialog, tea.Cmd) {
 	return c, nil
 }
 
+// updateTyped handles input while the user types the confirmation text.
+// Enter only confirms when the input matches exactly.
+func (c ConfirmDialog) updateTyped(msg tea.Msg) (ConfirmDialog, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return c, nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEsc:
+		c.visible = false
+		return c, func() tea.Msg {
+			return ConfirmResult{Confirmed: false, Action: c.action, Data: c.data}
+		}
+	case tea.KeyEnter:
+		if c.input != c.expected {
+			return c, nil
+		}
+		c.visible = false
+		return c, func() tea.Msg {
+			return ConfirmResult{Confirmed: true, Action: c.action, Data: c.data}
+		}
+	case tea.KeyBackspace:
+		if len(c.input) > 0 {
+			c.input = c.input[:len(c.input)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		c.input += string(keyMsg.Runes)
+	}
+	return c, nil
+}
+
 func (c ConfirmDialog) View() string {
 	if !c.visible {
 		return ""
@@ -95,6 +147,10 @@ func (c ConfirmDialog) View() string {
 	b.WriteString(msgStyle.Render(c.message))
 	b.WriteString("\n\n")
 
+	if c.expected != "" {
+		return c.viewTyped(&b)
+	}
+
 	// Buttons
 	yesStyle := lipgloss.NewStyle().
 		Padding(0, 2).
@@ -147,15 +203,89 @@ func (c ConfirmDialog) View() string {
 	return boxStyle.Render(content)
 }
 
+// viewTyped renders the input box used for typed confirmation.
+func (c ConfirmDialog) viewTyped(b *strings.Builder) string {
+	promptStyle := lipgloss.NewStyle().Foreground(style.Text)
+	b.WriteString(promptStyle.Render("Type "))
+	b.WriteString(lipgloss.NewStyle().Foreground(style.Warning).Bold(true).Render(c.expected))
+	b.WriteString(promptStyle.Render(" to confirm:"))
+	b.WriteString("\n")
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Muted).
+		Padding(0, 1).
+		Width(max(len(c.expected)+4, 20))
+	if c.input == c.expected {
+		inputStyle = inputStyle.BorderForeground(style.Warning)
+	}
+	b.WriteString(inputStyle.Render(c.input + "█"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(style.Muted).
+		MarginTop(1)
+	b.WriteString("\n\n")
+	b.WriteString(hintStyle.Render("Enter to confirm • Esc to cancel"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Error).
+		Padding(1, 2).
+		Background(style.Background)
+
+	return boxStyle.Render(b.String())
+}
+
 func (c *ConfirmDialog) Show(title, message, action string, data interface{}) {
 	c.title = title
 	c.message = message
 	c.action = action
 	c.data = data
 	c.selected = false // Default to No for safety
+	c.expected = ""
+	c.input = ""
 	c.visible = true
 }
 
+// ShowTyped shows a dialog that only confirms once the user types expected,
+// usually the name of the resource being acted on.
+func (c *ConfirmDialog) ShowTyped(title, message, action, expected string, data interface{}) {
+	c.Show(title, message, action, data)
+	c.expected = expected
+}
+
+// Request asks for confirmation at the given level. With ConfirmNone no dialog
+// is shown: the returned command emits a confirmed (and Skipped) result, so
+// the action goes through the same ConfirmResult handling as a confirmed one.
+// target is the text to type for ConfirmTyped. A read-only dialog shows
+// nothing and the command emits a ReadOnlyMsg instead.
+func (c *ConfirmDialog) Request(level configs.ConfirmLevel, title, message, action, target string, data interface{}) tea.Cmd {
+	if c.readOnly {
+		return func() tea.Msg {
+			return ReadOnlyMsg{Title: title}
+		}
+	}
+	switch level {
+	case configs.ConfirmNone:
+		return func() tea.Msg {
+			return ConfirmResult{Confirmed: true, Action: action, Data: data, Skipped: true}
+		}
+	case configs.ConfirmTyped:
+		if target != "" {
+			c.ShowTyped(title, message, action, target, data)
+			return nil
+		}
+	}
+	c.Show(title, message, action, data)
+	return nil
+}
+
+// SetReadOnly makes Request refuse every action with a ReadOnlyMsg. Every
+// mutating action is requested through it, so this disables them all.
+func (c *ConfirmDialog) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
 func (c *ConfirmDialog) Hide() {
 	c.visible = false
 }
@@ -0,0 +1,246 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// podDeletedNote is shown for a run whose pod is gone, typically deleted
+// with its Job's ttlSecondsAfterFinished.
+const podDeletedNote = "logs unavailable (pod deleted)"
+
+// CronJobRunsViewer shows the schedule of a CronJob and the history of its
+// runs, and opens the logs of the pod of a selected run.
+type CronJobRunsViewer struct {
+	namespace string
+	cronJob   string
+	schedule  *repository.CronJobSchedule
+	runs      []repository.JobRun
+	cursor    int
+	note      string // Shown under the table, e.g. why logs can't be opened
+	visible   bool
+	width     int
+	height    int
+}
+
+// CronJobRunsViewerClosed is sent when the viewer is closed
+type CronJobRunsViewerClosed struct{}
+
+// OpenJobRunRequest asks app.go to open the logs of the pod of a run.
+type OpenJobRunRequest struct {
+	Namespace string
+	Job       string
+	Pod       string
+}
+
+func NewCronJobRunsViewer() CronJobRunsViewer {
+	return CronJobRunsViewer{}
+}
+
+func (v CronJobRunsViewer) Init() tea.Cmd {
+	return nil
+}
+
+func (v CronJobRunsViewer) Update(msg tea.Msg) (CronJobRunsViewer, tea.Cmd) {
+	if !v.visible {
+		return v, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			v.visible = false
+			return v, func() tea.Msg { return CronJobRunsViewerClosed{} }
+		case "enter", "l":
+			run := v.Selected()
+			if run == nil {
+				return v, nil
+			}
+			if run.Pod == "" {
+				v.PodDeleted(run.Name)
+				return v, nil
+			}
+			req := OpenJobRunRequest{Namespace: v.namespace, Job: run.Name, Pod: run.Pod}
+			return v, func() tea.Msg { return req }
+		case "up", "k":
+			if v.cursor > 0 {
+				v.cursor--
+				v.note = ""
+			}
+		case "down", "j":
+			if v.cursor < len(v.runs)-1 {
+				v.cursor++
+				v.note = ""
+			}
+		}
+	}
+
+	return v, nil
+}
+
+func (v CronJobRunsViewer) View() string {
+	if !v.visible {
+		return ""
+	}
+
+	separatorStyle := lipgloss.NewStyle().Foreground(style.TextMuted)
+	itemStyle := lipgloss.NewStyle().Foreground(style.Primary)
+	infoStyle := lipgloss.NewStyle().Foreground(style.Secondary)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Secondary)
+	labelStyle := lipgloss.NewStyle().Foreground(style.TextMuted).Width(14)
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Surface).
+		Padding(0, 1).
+		Width(v.width - 10).
+		Height(v.height - 10)
+
+	var content strings.Builder
+	if s := v.schedule; s != nil {
+		schedule := s.Schedule
+		if s.TimeZone != "" {
+			schedule += " (" + s.TimeZone + ")"
+		}
+		content.WriteString(labelStyle.Render("  Schedule") + schedule + "\n")
+		content.WriteString(labelStyle.Render("  Last run") + formatScheduleTime(s.LastScheduleTime, "never") + "\n")
+		next := formatScheduleTime(s.NextScheduleTime, "-")
+		switch {
+		case s.Suspended:
+			next = style.StatusPending.Render("suspended")
+		case s.ScheduleError != "":
+			next = style.StatusError.Render("invalid schedule: " + s.ScheduleError)
+		}
+		content.WriteString(labelStyle.Render("  Next run") + next + "\n\n")
+	}
+
+	content.WriteString(headerStyle.Render(fmt.Sprintf("  %-40s %-10s %-6s %-9s %-9s %s", "JOB", "STATUS", "AGE", "DURATION", "SUCC/FAIL", "POD")))
+	content.WriteString("\n")
+	if len(v.runs) == 0 {
+		content.WriteString(style.StatusMuted.Render("  No runs. Jobs are removed past the CronJob's history limits."))
+		content.WriteString("\n")
+	}
+	for i, run := range v.runs {
+		name := run.Name
+		if run.Manual {
+			name += " (manual)"
+		}
+		pod := run.Pod
+		if pod == "" {
+			pod = "(deleted)"
+		}
+		duration := "-"
+		if run.Duration > 0 {
+			duration = run.Duration.Round(time.Second).String()
+		}
+		row := fmt.Sprintf("%-40s %-10s %-6s %-9s %-9s %s",
+			style.Truncate(name, 40), run.Status, run.Age, duration,
+			fmt.Sprintf("%d/%d", run.Succeeded, run.Failed), pod)
+		switch {
+		case i == v.cursor:
+			content.WriteString(style.CursorStyle.Render("> " + row))
+		case run.IsFailed():
+			content.WriteString(style.StatusError.Render("  " + row))
+		case run.Status == "Running":
+			content.WriteString(style.StatusPending.Render("  " + row))
+		default:
+			content.WriteString("  " + row)
+		}
+		content.WriteString("\n")
+	}
+	if v.note != "" {
+		content.WriteString("\n")
+		content.WriteString(style.StatusMuted.Render("  " + v.note))
+		content.WriteString("\n")
+	}
+
+	failed := 0
+	for _, run := range v.runs {
+		if run.IsFailed() {
+			failed++
+		}
+	}
+	summary := fmt.Sprintf("[%d runs", len(v.runs))
+	if failed > 0 {
+		summary += fmt.Sprintf(", %d failed", failed)
+	}
+	breadcrumb := itemStyle.Render("runs") +
+		separatorStyle.Render(" - ") +
+		infoStyle.Render(v.namespace+"/"+v.cronJob) +
+		separatorStyle.Render(" - ") +
+		infoStyle.Render(summary+"]")
+	footer := style.StatusMuted.Render("↑↓:select  Enter:logs  Esc:close")
+
+	return breadcrumb + "\n" + boxStyle.Render(content.String()) + "\n" + footer
+}
+
+// formatScheduleTime shows a time with how far it is from now, e.g.
+// "2024-05-16 02:00 UTC (in 15h)", or none when it is zero.
+func formatScheduleTime(t time.Time, none string) string {
+	if t.IsZero() {
+		return none
+	}
+	d := time.Until(t)
+	relative := "in " + shortDuration(d)
+	if d < 0 {
+		relative = shortDuration(-d) + " ago"
+	}
+	return fmt.Sprintf("%s (%s)", t.Format("2006-01-02 15:04 MST"), relative)
+}
+
+// shortDuration is a duration in its largest unit, e.g. "15h" or "3d".
+func shortDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// Show opens the viewer with the schedule and runs of a CronJob.
+func (v *CronJobRunsViewer) Show(namespace, cronJob string, schedule *repository.CronJobSchedule, runs []repository.JobRun) {
+	v.namespace = namespace
+	v.cronJob = cronJob
+	v.schedule = schedule
+	v.runs = runs
+	v.cursor = 0
+	v.note = ""
+	v.visible = true
+}
+
+// PodDeleted notes that the pod of a run is gone, as found when opening it.
+func (v *CronJobRunsViewer) PodDeleted(job string) {
+	v.note = job + ": " + podDeletedNote
+}
+
+// Selected returns the run under the cursor, nil when there is none.
+func (v CronJobRunsViewer) Selected() *repository.JobRun {
+	if v.cursor < len(v.runs) {
+		return &v.runs[v.cursor]
+	}
+	return nil
+}
+
+func (v *CronJobRunsViewer) Hide() {
+	v.visible = false
+}
+
+func (v CronJobRunsViewer) IsVisible() bool {
+	return v.visible
+}
+
+func (v *CronJobRunsViewer) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
@@ -0,0 +1,182 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// DaemonSetNodesViewer lists the pod of a DaemonSet on each node, and the
+// nodes it should run on but doesn't, and opens the dashboard of a
+// selected pod.
+type DaemonSetNodesViewer struct {
+	workload *repository.WorkloadInfo
+	rollout  *repository.DaemonSetNodes
+	cursor   int
+	visible  bool
+	width    int
+	height   int
+}
+
+// DaemonSetNodesViewerClosed is sent when the viewer is closed
+type DaemonSetNodesViewerClosed struct{}
+
+// OpenDaemonSetPodRequest asks app.go to open the dashboard of the pod of
+// a DaemonSet on a node.
+type OpenDaemonSetPodRequest struct {
+	Workload *repository.WorkloadInfo
+	Pod      string
+}
+
+func NewDaemonSetNodesViewer() DaemonSetNodesViewer {
+	return DaemonSetNodesViewer{}
+}
+
+func (v DaemonSetNodesViewer) Update(msg tea.Msg) (DaemonSetNodesViewer, tea.Cmd) {
+	if !v.visible {
+		return v, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return v, nil
+	}
+	switch keyMsg.String() {
+	case "esc", "q":
+		v.visible = false
+		return v, func() tea.Msg { return DaemonSetNodesViewerClosed{} }
+	case "up", "k":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "down", "j":
+		if v.rollout != nil && v.cursor < len(v.rollout.Nodes)-1 {
+			v.cursor++
+		}
+	case "enter":
+		node := v.Selected()
+		if node == nil || node.Pod == "" {
+			return v, nil
+		}
+		req := OpenDaemonSetPodRequest{Workload: v.workload, Pod: node.Pod}
+		return v, func() tea.Msg { return req }
+	}
+	return v, nil
+}
+
+func (v DaemonSetNodesViewer) View() string {
+	if !v.visible || v.rollout == nil {
+		return ""
+	}
+
+	separatorStyle := lipgloss.NewStyle().Foreground(style.TextMuted)
+	itemStyle := lipgloss.NewStyle().Foreground(style.Primary)
+	infoStyle := lipgloss.NewStyle().Foreground(style.Secondary)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Secondary)
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Surface).
+		Padding(0, 1).
+		Width(v.width - 10).
+		Height(v.height - 10)
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render(fmt.Sprintf("  %-32s %-9s %-36s %-5s %-8s %-18s", "NODE", "CORDONED", "POD", "READY", "RESTARTS", "STATUS")))
+	content.WriteString("\n")
+	if len(v.rollout.Nodes) == 0 {
+		content.WriteString(style.StatusMuted.Render("  No nodes to run on"))
+		content.WriteString("\n")
+	}
+	for i, n := range v.rollout.Nodes {
+		cordoned, pod, ready, restarts := "", n.Pod, "no", "-"
+		if n.Cordoned {
+			cordoned = "yes"
+		}
+		if n.Pod == "" {
+			pod, ready = "-", "-"
+		} else {
+			restarts = fmt.Sprintf("%d", n.Restarts)
+			if n.Ready {
+				ready = "yes"
+			}
+		}
+		row := fmt.Sprintf("%-32s %-9s %-36s %-5s %-8s %-18s",
+			style.Truncate(n.Node, 32), cordoned, style.Truncate(pod, 36), ready, restarts, n.Status)
+		switch {
+		case i == v.cursor:
+			content.WriteString(style.CursorStyle.Render("> " + row))
+		case n.Healthy():
+			content.WriteString("  " + row)
+		case n.Pod == "" || n.Restarts > 0:
+			content.WriteString(style.StatusError.Render("  " + row))
+		default:
+			content.WriteString(style.StatusPending.Render("  " + row))
+		}
+		content.WriteString("\n")
+		// Why a node has no running pod
+		if n.Reason != "" {
+			wrapped := lipgloss.NewStyle().Width(v.width - 20).Render(n.Reason)
+			for _, line := range strings.Split(wrapped, "\n") {
+				content.WriteString(style.StatusMuted.Render("    " + line))
+				content.WriteString("\n")
+			}
+		}
+	}
+	if v.rollout.NotTargeted > 0 {
+		content.WriteString("\n")
+		content.WriteString(style.StatusMuted.Render(fmt.Sprintf("  %d nodes not targeted by the nodeSelector or node affinity", v.rollout.NotTargeted)))
+		content.WriteString("\n")
+	}
+
+	missing := 0
+	for _, n := range v.rollout.Nodes {
+		if n.Pod == "" {
+			missing++
+		}
+	}
+	summary := fmt.Sprintf("[%d/%d ready", v.rollout.Ready, v.rollout.Desired)
+	if missing > 0 {
+		summary += fmt.Sprintf(", %d nodes without a pod", missing)
+	}
+	breadcrumb := itemStyle.Render("nodes") +
+		separatorStyle.Render(" - ") +
+		infoStyle.Render(v.rollout.Namespace+"/"+v.rollout.Name) +
+		separatorStyle.Render(" - ") +
+		infoStyle.Render(summary+"]")
+	footer := style.StatusMuted.Render("↑↓:select  Enter:open pod  Esc:close")
+
+	return breadcrumb + "\n" + boxStyle.Render(content.String()) + "\n" + footer
+}
+
+// Show opens the viewer on the per-node rollout of a DaemonSet.
+func (v *DaemonSetNodesViewer) Show(workload *repository.WorkloadInfo, rollout *repository.DaemonSetNodes) {
+	v.workload = workload
+	v.rollout = rollout
+	v.cursor = 0
+	v.visible = true
+}
+
+// Selected returns the node under the cursor, nil when there is none.
+func (v DaemonSetNodesViewer) Selected() *repository.DaemonSetNode {
+	if v.rollout != nil && v.cursor < len(v.rollout.Nodes) {
+		return &v.rollout.Nodes[v.cursor]
+	}
+	return nil
+}
+
+func (v *DaemonSetNodesViewer) Hide() {
+	v.visible = false
+}
+
+func (v DaemonSetNodesViewer) IsVisible() bool {
+	return v.visible
+}
+
+func (v *DaemonSetNodesViewer) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
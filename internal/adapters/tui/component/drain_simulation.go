@@ -0,0 +1,166 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// RenderDrainSimulation formats a drain dry run for the result viewer.
+// The placement is a first-fit estimate, which the output states up front.
+func RenderDrainSimulation(sim *repository.DrainSimulation) string {
+	if sim == nil {
+		return style.StatusMuted.Render("No drain simulation available")
+	}
+
+	var b strings.Builder
+
+	b.WriteString(style.StatusPending.Render("Estimate (first-fit on requests) — the scheduler may place pods differently"))
+	b.WriteString("\n\n")
+
+	// Summary
+	b.WriteString(style.SubtitleStyle.Render("Summary"))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("  %-20s %s\n", "Node:", sim.Node))
+	b.WriteString(fmt.Sprintf("  %-20s %d\n", "Pods to evict:", len(sim.Pods)))
+	b.WriteString(fmt.Sprintf("  %-20s %s\n", "CPU requested:", formatMilliCPU(sim.TotalCPURequest)))
+	b.WriteString(fmt.Sprintf("  %-20s %s\n", "Memory requested:", formatBytes(sim.TotalMemoryRequest)))
+	b.WriteString(fmt.Sprintf("  %-20s %d\n", "Candidate nodes:", sim.CandidateNodes))
+	b.WriteString(fmt.Sprintf("  %-20s %s\n", "Reschedulable:",
+		style.StatusRunning.Render(fmt.Sprintf("%d", sim.Count(repository.DrainReschedulable)))))
+	b.WriteString(fmt.Sprintf("  %-20s %s\n", "No capacity:",
+		drainCountStyle(sim.Count(repository.DrainNoCapacity))))
+	b.WriteString(fmt.Sprintf("  %-20s %s\n", "No compatible node:",
+		drainCountStyle(sim.Count(repository.DrainNoCompatibleNode))))
+	b.WriteString("\n")
+
+	// Pods
+	b.WriteString(style.SubtitleStyle.Render("Pods"))
+	b.WriteString("\n")
+	if len(sim.Pods) == 0 {
+		b.WriteString(style.StatusMuted.Render("  No evictable pods"))
+		b.WriteString("\n")
+	}
+	for _, p := range sim.Pods {
+		status := string(p.Status)
+		switch p.Status {
+		case repository.DrainReschedulable:
+			status = style.StatusRunning.Render(fmt.Sprintf("%-18s", status))
+		default:
+			status = style.StatusError.Render(fmt.Sprintf("%-18s", status))
+		}
+		b.WriteString(fmt.Sprintf("  %s %s/%s\n", status, p.Namespace, p.Name))
+		detail := fmt.Sprintf("cpu %s, mem %s", formatMilliCPU(p.CPURequest), formatBytes(p.MemoryRequest))
+		if p.TargetNode != "" {
+			detail += " → " + p.TargetNode
+		}
+		b.WriteString(style.StatusMuted.Render("    " + detail))
+		b.WriteString("\n")
+	}
+
+	// Skipped
+	if len(sim.Skipped) > 0 {
+		b.WriteString("\n")
+		b.WriteString(style.SubtitleStyle.Render("Skipped"))
+		b.WriteString("\n")
+		b.WriteString(style.StatusMuted.Render("  DaemonSet, mirror and finished pods are not evicted"))
+		b.WriteString("\n")
+		for _, name := range sim.Skipped {
+			b.WriteString(fmt.Sprintf("    • %s\n", name))
+		}
+	}
+
+	return b.String()
+}
+
+func drainCountStyle(n int) string {
+	if n == 0 {
+		return style.StatusMuted.Render("0")
+	}
+	return style.StatusError.Render(fmt.Sprintf("%d", n))
+}
+
+func formatMilliCPU(m int64) string {
+	if m%1000 == 0 {
+		return fmt.Sprintf("%d", m/1000)
+	}
+	return fmt.Sprintf("%dm", m)
+}
+
+func formatBytes(b int64) string {
+	const mi = 1024 * 1024
+	if b >= 1024*mi {
+		return fmt.Sprintf("%.1fGi", float64(b)/float64(1024*mi))
+	}
+	return fmt.Sprintf("%dMi", b/mi)
+}
+
+// RenderDrainProgress formats a drain for the result viewer: the latest
+// state of each pod while it runs, then, once result is set, a summary
+// listing the pods that could not be evicted.
+func RenderDrainProgress(node string, pods []repository.DrainPodProgress, result *repository.DrainResult) string {
+	var b strings.Builder
+
+	if result == nil {
+		b.WriteString(style.StatusPending.Render(fmt.Sprintf("Draining %s... (Esc hides this, the drain continues)", node)))
+		b.WriteString("\n\n")
+	} else {
+		b.WriteString(style.SubtitleStyle.Render("Summary"))
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  %-20s %s\n", "Node:", node))
+		b.WriteString(fmt.Sprintf("  %-20s %s\n", "Evicted:",
+			style.StatusRunning.Render(fmt.Sprintf("%d", len(result.Evicted)))))
+		b.WriteString(fmt.Sprintf("  %-20s %d\n", "Skipped:", len(result.Skipped)))
+		b.WriteString(fmt.Sprintf("  %-20s %s\n", "Could not evict:", drainCountStyle(len(result.Failed))))
+		b.WriteString("\n")
+
+		if len(result.Failed) > 0 {
+			b.WriteString(style.SubtitleStyle.Render("Not evicted"))
+			b.WriteString("\n")
+			for _, p := range result.Failed {
+				b.WriteString(fmt.Sprintf("  • %s/%s\n", p.Namespace, p.Name))
+				b.WriteString(style.StatusMuted.Render("    " + p.Reason))
+				b.WriteString("\n")
+			}
+			b.WriteString(style.StatusMuted.Render("  The node stays cordoned; uncordon it from the node actions"))
+			b.WriteString("\n\n")
+		}
+	}
+
+	b.WriteString(style.SubtitleStyle.Render("Pods"))
+	b.WriteString("\n")
+	if len(pods) == 0 {
+		if result == nil {
+			b.WriteString(style.StatusMuted.Render("  Cordoning the node and listing its pods..."))
+		} else {
+			b.WriteString(style.StatusMuted.Render("  No pods on the node"))
+		}
+		b.WriteString("\n")
+	}
+	for _, p := range pods {
+		b.WriteString(fmt.Sprintf("  %s %s/%s", drainStateStyle(p.State), p.Namespace, p.Name))
+		if p.Reason != "" {
+			b.WriteString(style.StatusMuted.Render("  " + p.Reason))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func drainStateStyle(state repository.DrainPodState) string {
+	padded := fmt.Sprintf("%-9s", state)
+	switch state {
+	case repository.DrainPodEvicted:
+		return style.StatusRunning.Render(padded)
+	case repository.DrainPodEvicting:
+		return style.StatusPending.Render(padded)
+	case repository.DrainPodBlocked:
+		return style.EventWarning.Render(padded)
+	case repository.DrainPodFailed:
+		return style.StatusError.Render(padded)
+	}
+	return style.StatusMuted.Render(padded)
+}
@@ -2,7 +2,10 @@ package component
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -13,7 +16,8 @@ import (
 )
 
 // EventsPanel displays Kubernetes events with filtering capabilities.
-// Features include: warning-only filter, text search, and clipboard copy.
+// Features include: warning-only filter, text search, grouping of repeated
+// events, clipboard copy and CSV export.
 type EventsPanel struct {
 	events      []repository.EventInfo
 	viewport    viewport.Model
@@ -26,6 +30,29 @@ type EventsPanel struct {
 	searching   bool
 	searchInput textinput.Model
 	filter      string
+	exportDir   string         // Directory for CSV exports (current directory when empty)
+	changes     *ChangeTracker // Event counts changed since the previous refresh
+	grouped     bool            // Collapse events with the same object and reason
+	expanded    map[string]bool // Groups showing their occurrences, by eventGroupID
+	byCount     bool             // Sort by count, highest first, instead of by last seen
+	clock       func() time.Time // Ages are counted from its time, on every render
+}
+
+// eventRow is a line of the grouped view: a group, or one of the events
+// of an expanded group.
+type eventRow struct {
+	group *repository.EventGroup
+	event *repository.EventInfo // nil for the group's own row
+}
+
+// EventLogsWindow is how many minutes of logs before and after the
+// selected event "L" shows.
+const EventLogsWindow = 2
+
+// EventLogsRequest asks the dashboard to show the logs written around the
+// time of the selected event.
+type EventLogsRequest struct {
+	Time time.Time
 }
 
 // NewEventsPanel creates a new events panel with default settings.
@@ -37,6 +64,9 @@ func NewEventsPanel() EventsPanel {
 
 	return EventsPanel{
 		searchInput: ti,
+		changes:     NewChangeTracker(ChangeHighlightDuration, time.Now),
+		expanded:    make(map[string]bool),
+		clock:       time.Now,
 	}
 }
 
@@ -82,15 +112,41 @@ func (e EventsPanel) Update(msg tea.Msg) (EventsPanel, tea.Cmd) {
 		// Normal mode
 		switch msg.String() {
 		case "enter":
+			if e.grouped {
+				e.toggleGroup()
+				return e, nil
+			}
 			// Copy events to clipboard
 			content := e.getPlainTextEvents()
-			err := CopyToClipboard(content)
+			copied, err := CopyToClipboard(content)
 			if err == nil {
-				e.copyStatus = "Copied to clipboard!"
+				e.copyStatus = copied.Status()
 			} else {
 				e.copyStatus = "Copy failed: " + err.Error()
 			}
 			return e, nil
+		case "x":
+			// Export the displayed events as CSV and copy the file path
+			events := e.getDisplayedEvents()
+			path, err := ExportEventsCSV(e.exportDir, events, time.Now())
+			if err != nil {
+				e.copyStatus = "Export failed: " + err.Error()
+				return e, nil
+			}
+			e.copyStatus = fmt.Sprintf("Exported %d events to %s", len(events), path)
+			if _, err := CopyToClipboard(path); err == nil {
+				e.copyStatus += " (path copied)"
+			}
+			return e, nil
+		case "ctrl+s":
+			// Save the displayed events to a file, prompted for by app.go
+			req := SavePanelRequest{
+				Panel:   "events",
+				Filter:  e.filter,
+				Lines:   len(e.getDisplayedEvents()),
+				Content: e.getPlainTextEvents(),
+			}
+			return e, func() tea.Msg { return req }
 		case "/":
 			e.searching = true
 			e.searchInput.Focus()
@@ -98,8 +154,26 @@ func (e EventsPanel) Update(msg tea.Msg) (EventsPanel, tea.Cmd) {
 		case "w":
 			e.showAll = !e.showAll
 			e.updateContent()
+		case "u":
+			e.SetGrouped(!e.grouped)
+		case "s":
+			e.SetSortByCount(!e.byCount)
+		case "L":
+			// Logs around when the event was last seen
+			if ev := e.SelectedEvent(); ev != nil {
+				at := ev.LastSeen
+				if at.IsZero() {
+					at = ev.FirstSeen
+				}
+				if !at.IsZero() {
+					return e, func() tea.Msg {
+						return EventLogsRequest{Time: at}
+					}
+				}
+			}
+			return e, nil
 		case "j", "down":
-			if e.cursor < len(e.getDisplayedEvents())-1 {
+			if e.cursor < e.rowCount()-1 {
 				e.cursor++
 			}
 		case "k", "up":
@@ -129,6 +203,11 @@ func (e EventsPanel) View() string {
 	if !e.showAll {
 		header.WriteString(style.SubtitleStyle.Render(" (warnings only, press 'w' for all)"))
 	}
+	if e.grouped {
+		header.WriteString(style.SubtitleStyle.Render(" (grouped, enter to expand)"))
+	} else if e.byCount {
+		header.WriteString(style.SubtitleStyle.Render(" (by count)"))
+	}
 
 	// Show search input or filter indicator
 	if e.searching {
@@ -167,10 +246,122 @@ func (e EventsPanel) View() string {
 }
 
 func (e *EventsPanel) SetEvents(events []repository.EventInfo) {
+	prev := e.rowKeys()
+	prevCursor := e.cursor
 	e.events = events
-	e.cursor = 0
+	e.changes.Observe(EventSnapshot(events))
+	// Follow the selected event, scrolling by as many rows as it moved so
+	// it stays on the same screen row
+	e.cursor = RelocateCursor(prev, e.cursor, e.rowKeys())
 	e.copyStatus = "" // Clear copy status when events update
 	e.updateContent()
+	if e.ready {
+		e.viewport.SetYOffset(e.viewport.YOffset + e.cursor - prevCursor)
+	}
+}
+
+// AddEvents merges events from the event watch into the panel. An event
+// that is already listed (same Name) is replaced by its update, so a
+// recurring event moves up with its new count. The selected event keeps
+// its place unless it is the newest one: then the cursor stays on the
+// newest row and follows the events as they arrive.
+func (e *EventsPanel) AddEvents(events []repository.EventInfo) {
+	merged := append([]repository.EventInfo(nil), e.events...)
+	for _, ev := range events {
+		i := slices.IndexFunc(merged, func(m repository.EventInfo) bool {
+			return ev.Name != "" && m.Name == ev.Name
+		})
+		if i >= 0 {
+			merged[i] = ev
+		} else {
+			merged = append(merged, ev)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].LastSeen.After(merged[j].LastSeen)
+	})
+
+	atNewest := e.cursor == 0
+	e.SetEvents(merged)
+	if atNewest && e.cursor != 0 {
+		e.cursor = 0
+		e.updateContent()
+		if e.ready {
+			e.viewport.GotoTop()
+		}
+	}
+}
+
+// WarningsOnly reports whether only Warning events are shown.
+func (e EventsPanel) WarningsOnly() bool {
+	return !e.showAll
+}
+
+// SetWarningsOnly shows only Warning events, or every event.
+func (e *EventsPanel) SetWarningsOnly(enabled bool) {
+	e.showAll = !enabled
+	e.cursor = 0
+	e.updateContent()
+}
+
+// Grouped reports whether repeated events are collapsed into groups.
+func (e EventsPanel) Grouped() bool {
+	return e.grouped
+}
+
+// SetGrouped collapses the events with the same object and reason into one
+// row, or lists every event.
+func (e *EventsPanel) SetGrouped(enabled bool) {
+	e.grouped = enabled
+	e.cursor = 0
+	e.updateContent()
+	if e.ready {
+		e.viewport.GotoTop()
+	}
+}
+
+// SortByCount reports whether the events are sorted by count instead of
+// by when they were last seen.
+func (e EventsPanel) SortByCount() bool {
+	return e.byCount
+}
+
+// SetSortByCount sorts the events by count, highest first, or by when they
+// were last seen, newest first. Groups are always sorted by count.
+func (e *EventsPanel) SetSortByCount(enabled bool) {
+	e.byCount = enabled
+	e.cursor = 0
+	e.updateContent()
+	if e.ready {
+		e.viewport.GotoTop()
+	}
+}
+
+// SetHighlightChanges turns highlighting of recurring events on or off.
+func (e *EventsPanel) SetHighlightChanges(enabled bool) {
+	e.changes.SetEnabled(enabled)
+	e.updateContent()
+}
+
+// NextChangeExpiry returns how long until the next highlight expires.
+func (e EventsPanel) NextChangeExpiry() (time.Duration, bool) {
+	return e.changes.NextExpiry()
+}
+
+// RefreshHighlights re-renders the events so expired highlights disappear.
+func (e *EventsPanel) RefreshHighlights() {
+	e.updateContent()
+}
+
+// RefreshAges re-renders the events so their ages count up between
+// fetches.
+func (e *EventsPanel) RefreshAges() {
+	e.updateContent()
+}
+
+// SetExportDir sets the directory CSV exports are written to.
+func (e *EventsPanel) SetExportDir(dir string) {
+	e.exportDir = dir
 }
 
 func (e *EventsPanel) SetSize(width, height int) {
@@ -188,16 +379,34 @@ func (e *EventsPanel) SetSize(width, height int) {
 	e.updateContent()
 }
 
+// Restyle re-renders the events with the active theme.
+func (e *EventsPanel) Restyle() {
+	e.updateContent()
+}
+
 func (e *EventsPanel) updateContent() {
 	if !e.ready {
 		return
 	}
 
 	var content strings.Builder
-	events := e.getDisplayedEvents()
+	now := e.clock()
+	if e.grouped {
+		for i, row := range e.groupRows() {
+			if row.event == nil {
+				content.WriteString(e.formatGroup(*row.group, i == e.cursor, now))
+			} else {
+				content.WriteString(e.formatOccurrence(*row.event, i == e.cursor, now))
+			}
+			content.WriteString("\n")
+		}
+		e.viewport.SetContent(content.String())
+		return
+	}
 
+	events := e.getDisplayedEvents()
 	for i, event := range events {
-		line := e.formatEvent(event, i == e.cursor)
+		line := e.formatEvent(event, i == e.cursor, now)
 		content.WriteString(line)
 		content.WriteString("\n")
 	}
@@ -229,10 +438,170 @@ func (e EventsPanel) getDisplayedEvents() []repository.EventInfo {
 		filtered = searchFiltered
 	}
 
+	// Events come newest first; a recurring event with the same count
+	// stays above older ones
+	if e.byCount {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return eventCount(filtered[i]) > eventCount(filtered[j])
+		})
+	}
+
 	return filtered
 }
 
-func (e EventsPanel) formatEvent(event repository.EventInfo, selected bool) string {
+// eventCount returns how many times an event occurred; events without a
+// count occurred once.
+func eventCount(event repository.EventInfo) int32 {
+	return max(event.Count, 1)
+}
+
+// eventAge returns how long ago an event was last seen, counted from now.
+func eventAge(event repository.EventInfo, now time.Time) string {
+	if event.LastSeen.IsZero() {
+		return event.Age
+	}
+	return repository.FormatAge(event.LastSeen, now) + " ago"
+}
+
+// eventSource returns the component that reported an event, with its host.
+func eventSource(event repository.EventInfo) string {
+	if event.Host == "" {
+		return event.Source
+	}
+	return event.Source + "/" + event.Host
+}
+
+// groupRows groups the displayed events, so the warnings-only toggle and
+// the search filter apply to the occurrences being grouped.
+func (e EventsPanel) groupRows() []eventRow {
+	groups := repository.GroupEvents(e.getDisplayedEvents())
+	var rows []eventRow
+	for i := range groups {
+		g := &groups[i]
+		rows = append(rows, eventRow{group: g})
+		if e.expanded[eventGroupID(*g)] {
+			for j := range g.Events {
+				rows = append(rows, eventRow{group: g, event: &g.Events[j]})
+			}
+		}
+	}
+	return rows
+}
+
+// eventGroupID identifies a group across refreshes.
+func eventGroupID(g repository.EventGroup) string {
+	return g.Object + "/" + g.Reason
+}
+
+// rowCount is the number of lines the cursor moves over.
+func (e EventsPanel) rowCount() int {
+	if e.grouped {
+		return len(e.groupRows())
+	}
+	return len(e.getDisplayedEvents())
+}
+
+// rowKeys identifies the displayed lines, for keeping the selection when
+// the events are refreshed.
+func (e EventsPanel) rowKeys() []string {
+	if !e.grouped {
+		return eventKeys(e.getDisplayedEvents())
+	}
+	rows := e.groupRows()
+	keys := make([]string, len(rows))
+	for i, row := range rows {
+		keys[i] = "group/" + eventGroupID(*row.group)
+		if row.event != nil {
+			keys[i] += "/" + row.event.Name
+		}
+	}
+	return keys
+}
+
+// toggleGroup expands or collapses the group under the cursor. On one of
+// the group's events, the group is collapsed and selected.
+func (e *EventsPanel) toggleGroup() {
+	rows := e.groupRows()
+	if e.cursor < 0 || e.cursor >= len(rows) {
+		return
+	}
+	row := rows[e.cursor]
+	id := eventGroupID(*row.group)
+	if row.event != nil {
+		for e.cursor > 0 && rows[e.cursor].event != nil {
+			e.cursor--
+		}
+	}
+	if e.expanded[id] {
+		delete(e.expanded, id)
+	} else {
+		e.expanded[id] = true
+	}
+	e.updateContent()
+}
+
+func (e EventsPanel) formatGroup(g repository.EventGroup, selected bool, now time.Time) string {
+	var b strings.Builder
+
+	typeStyle := style.EventNormal
+	if g.Type == "Warning" {
+		typeStyle = style.EventWarning
+	}
+
+	if selected {
+		b.WriteString(style.CursorStyle.Render("> "))
+	} else {
+		b.WriteString("  ")
+	}
+
+	marker := "▸"
+	if e.expanded[eventGroupID(g)] {
+		marker = "▾"
+	}
+
+	b.WriteString(typeStyle.Render(fmt.Sprintf("%-8s", g.Type)))
+	b.WriteString(" ")
+	b.WriteString(style.LogTimestamp.Render(fmt.Sprintf("%-8s", eventAge(g.Events[0], now))))
+	b.WriteString(" ")
+	b.WriteString(marker + " ")
+	b.WriteString(style.LogContainer.Render(fmt.Sprintf("%-20s", style.Truncate(g.Reason, 20))))
+	b.WriteString(" ")
+	b.WriteString(typeStyle.Render(fmt.Sprintf("x%-5d", g.Count)))
+	b.WriteString(" ")
+
+	maxLen := e.width - 50
+	if maxLen < 20 {
+		maxLen = 20
+	}
+	b.WriteString(style.LogNormal.Render(style.Truncate(g.Object, maxLen)))
+
+	return b.String()
+}
+
+func (e EventsPanel) formatOccurrence(event repository.EventInfo, selected bool, now time.Time) string {
+	var b strings.Builder
+
+	if selected {
+		b.WriteString(style.CursorStyle.Render(">     "))
+	} else {
+		b.WriteString("      ")
+	}
+
+	b.WriteString(style.LogTimestamp.Render(fmt.Sprintf("%-8s", eventAge(event, now))))
+	b.WriteString(" ")
+	b.WriteString(style.StatusMuted.Render(fmt.Sprintf("x%-5d", eventCount(event))))
+	b.WriteString(" ")
+
+	maxMsgLen := e.width - 26
+	if maxMsgLen < 20 {
+		maxMsgLen = 20
+	}
+	b.WriteString(style.LogNormal.Render(style.Truncate(event.Message, maxMsgLen)))
+
+	return b.String()
+}
+
+func (e EventsPanel) formatEvent(event repository.EventInfo, selected bool, now time.Time) string {
 	var b strings.Builder
 
 	typeStyle := style.EventNormal
@@ -250,12 +619,27 @@ func (e EventsPanel) formatEvent(event repository.EventInfo, selected bool) stri
 
 	b.WriteString(typeStyle.Render(fmt.Sprintf("%-8s", event.Type)))
 	b.WriteString(" ")
-	b.WriteString(style.LogTimestamp.Render(fmt.Sprintf("%-6s", event.Age)))
+	b.WriteString(style.LogTimestamp.Render(fmt.Sprintf("%-8s", eventAge(event, now))))
 	b.WriteString(" ")
-	b.WriteString(style.LogContainer.Render(fmt.Sprintf("%-20s", style.Truncate(event.Reason, 20))))
+	// Tint the reason of events whose count went up since the last refresh
+	reasonStyle := style.LogContainer
+	if e.changes.Changed(eventChangeID(event), FieldCount) {
+		reasonStyle = style.StatusChanged
+	}
+	b.WriteString(reasonStyle.Render(fmt.Sprintf("%-20s", style.Truncate(event.Reason, 20))))
 	b.WriteString(" ")
 
-	maxMsgLen := e.width - 40
+	// The object and source columns take a share of what is left, the
+	// message the rest
+	rest := e.width - 42
+	objectLen := min(max(rest/4, 12), 32)
+	sourceLen := min(max(rest/6, 8), 24)
+	b.WriteString(style.LogNormal.Render(fmt.Sprintf("%-*s", objectLen, style.Truncate(event.Object, objectLen))))
+	b.WriteString(" ")
+	b.WriteString(style.StatusMuted.Render(fmt.Sprintf("%-*s", sourceLen, style.Truncate(eventSource(event), sourceLen))))
+	b.WriteString(" ")
+
+	maxMsgLen := rest - objectLen - sourceLen - 2
 	if maxMsgLen < 20 {
 		maxMsgLen = 20
 	}
@@ -275,7 +659,19 @@ func (e EventsPanel) warningCount() int {
 	return count
 }
 
+// SelectedEvent returns the event under the cursor. On a group it is the
+// group's most recent event.
 func (e EventsPanel) SelectedEvent() *repository.EventInfo {
+	if e.grouped {
+		rows := e.groupRows()
+		if e.cursor < 0 || e.cursor >= len(rows) {
+			return nil
+		}
+		if rows[e.cursor].event != nil {
+			return rows[e.cursor].event
+		}
+		return &rows[e.cursor].group.Events[0]
+	}
 	events := e.getDisplayedEvents()
 	if e.cursor >= 0 && e.cursor < len(events) {
 		return &events[e.cursor]
@@ -283,6 +679,11 @@ func (e EventsPanel) SelectedEvent() *repository.EventInfo {
 	return nil
 }
 
+// Events returns the events the panel holds, filtered or not.
+func (e EventsPanel) Events() []repository.EventInfo {
+	return e.events
+}
+
 func (e EventsPanel) EventCount() int {
 	return len(e.events)
 }
@@ -307,12 +708,15 @@ func (e *EventsPanel) ClearSearch() {
 func (e EventsPanel) getPlainTextEvents() string {
 	var content strings.Builder
 	events := e.getDisplayedEvents()
+	now := e.clock()
 
 	for _, event := range events {
-		content.WriteString(fmt.Sprintf("%-8s %-6s %-20s %s\n",
+		content.WriteString(fmt.Sprintf("%-8s %-8s %-20s %-30s %-20s %s\n",
 			event.Type,
-			event.Age,
+			eventAge(event, now),
 			event.Reason,
+			event.Object,
+			eventSource(event),
 			event.Message))
 	}
 
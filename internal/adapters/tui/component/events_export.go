@@ -0,0 +1,86 @@
+package component
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// eventsCSVHeader is the header row of exported events.
+var eventsCSVHeader = []string{"type", "reason", "objectKind", "objectName", "count", "firstSeen", "lastSeen", "message"}
+
+// WriteEventsCSV writes events as CSV with a header row. Times are RFC3339
+// in UTC and left empty when unknown. encoding/csv quotes messages that
+// contain commas, quotes or newlines.
+func WriteEventsCSV(w io.Writer, events []repository.EventInfo) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(eventsCSVHeader); err != nil {
+		return err
+	}
+	for _, e := range events {
+		kind, name := splitEventObject(e.Object)
+		record := []string{
+			e.Type,
+			e.Reason,
+			kind,
+			name,
+			strconv.Itoa(int(e.Count)),
+			formatCSVTime(e.FirstSeen),
+			formatCSVTime(e.LastSeen),
+			e.Message,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportEventsCSV writes events to a timestamped CSV file in dir (the
+// current directory when empty) and returns the file's absolute path.
+func ExportEventsCSV(dir string, events []repository.EventInfo, now time.Time) (string, error) {
+	if dir == "" {
+		dir = "."
+	}
+	path, err := filepath.Abs(filepath.Join(dir, "k1s-events-"+now.Format("20060102-150405")+".csv"))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve export path: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create export file: %w", err)
+	}
+	if err := WriteEventsCSV(f, events); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write events: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write events: %w", err)
+	}
+	return path, nil
+}
+
+// splitEventObject splits "Pod/my-pod" into kind and name.
+func splitEventObject(object string) (string, string) {
+	kind, name, ok := strings.Cut(object, "/")
+	if !ok {
+		return "", object
+	}
+	return kind, name
+}
+
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,410 @@
+package component
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// FileBrowser browses the filesystem of a container: it lists directories,
+// previews text files and copies files or directories to a local path.
+// Listing, reading and copying are done by app.go, which the browser asks
+// for with FileBrowserListRequest, FileBrowserReadRequest and
+// FileBrowserCopyRequest. Errors are shown inline, keeping the browser open.
+type FileBrowser struct {
+	namespace string
+	pod       string
+	container string
+
+	dir     string // Directory listed
+	entries []repository.ContainerFile
+	cursor  int
+	scroll  int
+	loading bool
+	err     error  // Error of the last request, shown inline
+	status  string // Result of the last copy
+
+	// Preview of the selected file
+	previewing   bool
+	previewPath  string
+	preview      *repository.ContainerFileContent
+	previewLines []string
+	previewTop   int
+
+	visible bool
+	width   int
+	height  int
+}
+
+// FileBrowserListRequest asks app.go to list a directory of the container.
+type FileBrowserListRequest struct {
+	Path string
+}
+
+// FileBrowserReadRequest asks app.go to read a file of the container.
+type FileBrowserReadRequest struct {
+	Path string
+}
+
+// FileBrowserCopyRequest asks app.go to copy a file or directory of the
+// container to a local path.
+type FileBrowserCopyRequest struct {
+	Path string
+}
+
+// FileBrowserClosed is sent when the browser is closed
+type FileBrowserClosed struct{}
+
+func NewFileBrowser() FileBrowser {
+	return FileBrowser{}
+}
+
+func (f FileBrowser) Init() tea.Cmd {
+	return nil
+}
+
+func (f FileBrowser) Update(msg tea.Msg) (FileBrowser, tea.Cmd) {
+	if !f.visible {
+		return f, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return f, nil
+	}
+	if f.previewing {
+		return f.updatePreview(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		f.visible = false
+		return f, func() tea.Msg { return FileBrowserClosed{} }
+	case "up", "k":
+		if f.cursor > 0 {
+			f.cursor--
+		}
+	case "down", "j":
+		if f.cursor < len(f.entries)-1 {
+			f.cursor++
+		}
+	case "g", "home":
+		f.cursor = 0
+	case "G", "end":
+		f.cursor = max(len(f.entries)-1, 0)
+	case "enter", "right", "l":
+		entry := f.Selected()
+		if entry == nil || f.loading {
+			return f, nil
+		}
+		f.loading = true
+		f.err = nil
+		if entry.IsDir {
+			return f, f.request(FileBrowserListRequest{Path: entry.Path})
+		}
+		// Links are read; app.go lists them instead when they point to a directory
+		return f, f.request(FileBrowserReadRequest{Path: entry.Path})
+	case "backspace", "left", "h", "-":
+		if f.dir == "/" || f.loading {
+			return f, nil
+		}
+		f.loading = true
+		f.err = nil
+		return f, f.request(FileBrowserListRequest{Path: path.Dir(f.dir)})
+	case "c":
+		if entry := f.Selected(); entry != nil {
+			return f, f.request(FileBrowserCopyRequest{Path: entry.Path})
+		}
+	}
+	f.clampScroll()
+	return f, nil
+}
+
+func (f FileBrowser) updatePreview(msg tea.KeyMsg) (FileBrowser, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "backspace", "left", "h":
+		f.previewing = false
+	case "up", "k":
+		if f.previewTop > 0 {
+			f.previewTop--
+		}
+	case "down", "j":
+		if f.previewTop < f.maxPreviewTop() {
+			f.previewTop++
+		}
+	case "pgup":
+		f.previewTop = max(f.previewTop-f.visibleRows(), 0)
+	case "pgdown", " ":
+		f.previewTop = min(f.previewTop+f.visibleRows(), f.maxPreviewTop())
+	case "g", "home":
+		f.previewTop = 0
+	case "G", "end":
+		f.previewTop = f.maxPreviewTop()
+	case "c":
+		return f, f.request(FileBrowserCopyRequest{Path: f.previewPath})
+	}
+	return f, nil
+}
+
+func (f FileBrowser) request(msg tea.Msg) tea.Cmd {
+	return func() tea.Msg { return msg }
+}
+
+// Show opens the browser on a container at dir; the listing arrives with
+// SetEntries.
+func (f *FileBrowser) Show(namespace, pod, container, dir string) tea.Cmd {
+	f.namespace = namespace
+	f.pod = pod
+	f.container = container
+	f.dir = dir
+	f.entries = nil
+	f.cursor = 0
+	f.scroll = 0
+	f.err = nil
+	f.status = ""
+	f.previewing = false
+	f.loading = true
+	f.visible = true
+	return f.request(FileBrowserListRequest{Path: dir})
+}
+
+// SetEntries shows the listing of dir. On error the current listing stays
+// and the error is shown below it.
+func (f *FileBrowser) SetEntries(dir string, entries []repository.ContainerFile, err error) {
+	f.loading = false
+	f.err = err
+	if err != nil {
+		return
+	}
+	prev := f.dir
+	f.dir = dir
+	f.entries = entries
+	f.cursor = 0
+	f.scroll = 0
+	// Going up selects the directory just left
+	for i, e := range entries {
+		if e.Path == prev {
+			f.cursor = i
+		}
+	}
+	f.clampScroll()
+}
+
+// SetPreview shows the start of a file. On error the listing stays and the
+// error is shown below it.
+func (f *FileBrowser) SetPreview(filePath string, content *repository.ContainerFileContent, err error) {
+	f.loading = false
+	f.err = err
+	if err != nil {
+		return
+	}
+	f.previewing = true
+	f.previewPath = filePath
+	f.preview = content
+	f.previewTop = 0
+	f.previewLines = nil
+	if !content.Binary {
+		text := strings.TrimSuffix(strings.ReplaceAll(string(content.Data), "\t", "    "), "\n")
+		f.previewLines = strings.Split(text, "\n")
+	}
+}
+
+// SetStatus shows the result of a copy; err is shown instead when set.
+func (f *FileBrowser) SetStatus(status string, err error) {
+	f.status = status
+	f.err = err
+}
+
+// Target returns the namespace, pod and container being browsed.
+func (f FileBrowser) Target() (namespace, pod, container string) {
+	return f.namespace, f.pod, f.container
+}
+
+// Selected returns the entry under the cursor, or nil if the directory is empty.
+func (f FileBrowser) Selected() *repository.ContainerFile {
+	if f.cursor < 0 || f.cursor >= len(f.entries) {
+		return nil
+	}
+	return &f.entries[f.cursor]
+}
+
+func (f *FileBrowser) Hide() {
+	f.visible = false
+}
+
+func (f FileBrowser) IsVisible() bool {
+	return f.visible
+}
+
+func (f *FileBrowser) SetSize(width, height int) {
+	f.width = width
+	f.height = height
+}
+
+func (f FileBrowser) visibleRows() int {
+	rows := f.height - 14
+	if rows < 5 {
+		rows = 5
+	}
+	return rows
+}
+
+func (f *FileBrowser) clampScroll() {
+	rows := f.visibleRows()
+	if f.cursor < f.scroll {
+		f.scroll = f.cursor
+	}
+	if f.cursor >= f.scroll+rows {
+		f.scroll = f.cursor - rows + 1
+	}
+}
+
+func (f FileBrowser) maxPreviewTop() int {
+	if n := len(f.previewLines) - f.visibleRows(); n > 0 {
+		return n
+	}
+	return 0
+}
+
+func (f FileBrowser) View() string {
+	if !f.visible {
+		return ""
+	}
+
+	var content strings.Builder
+	var footer string
+	if f.previewing {
+		f.renderPreview(&content)
+		footer = "↑↓/PgUp/PgDn:scroll  c:copy to local path  Esc:back"
+	} else {
+		f.renderListing(&content)
+		footer = "↑↓:select  Enter:open  ←/Backspace:parent  c:copy to local path  Esc:close"
+	}
+
+	switch {
+	case f.loading:
+		content.WriteString("\n")
+		content.WriteString(style.StatusMuted.Render("Loading..."))
+	case f.err != nil:
+		content.WriteString("\n")
+		content.WriteString(style.StatusError.Render("Error: " + fileBrowserError(f.err)))
+	case f.status != "":
+		content.WriteString("\n")
+		content.WriteString(style.StatusRunning.Render(f.status))
+	}
+
+	// Breadcrumb
+	separatorStyle := lipgloss.NewStyle().Foreground(style.TextMuted)
+	itemStyle := lipgloss.NewStyle().Foreground(style.Primary)
+	infoStyle := lipgloss.NewStyle().Foreground(style.Secondary)
+	location := f.dir
+	if f.previewing {
+		location = f.previewPath
+	}
+	breadcrumb := itemStyle.Render(f.pod) +
+		separatorStyle.Render(" > ") +
+		itemStyle.Render(f.container) +
+		separatorStyle.Render(" > ") +
+		infoStyle.Render(location)
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Surface).
+		Padding(0, 1).
+		Width(f.width - 10).
+		Height(f.height - 10)
+
+	return breadcrumb + "\n" + boxStyle.Render(content.String()) + "\n" + style.StatusMuted.Render(footer)
+}
+
+func (f FileBrowser) renderListing(b *strings.Builder) {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Secondary)
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-10s %9s  %-12s  %s", "MODE", "SIZE", "MODIFIED", "NAME")))
+	b.WriteString("\n")
+
+	if len(f.entries) == 0 {
+		if !f.loading {
+			b.WriteString(style.StatusMuted.Render("  Empty directory"))
+			b.WriteString("\n")
+		}
+		return
+	}
+
+	end := min(f.scroll+f.visibleRows(), len(f.entries))
+	for i := f.scroll; i < end; i++ {
+		e := f.entries[i]
+		name := e.Name
+		switch {
+		case e.IsDir:
+			name += "/"
+		case e.IsLink:
+			name += " -> " + e.LinkTarget
+		}
+		size := formatFileSize(e.Size)
+		if e.IsDir {
+			size = "-"
+		}
+		row := fmt.Sprintf("%-10s %9s  %-12s  %s", e.Mode, size, e.ModTime, name)
+		switch {
+		case i == f.cursor:
+			b.WriteString(style.StatusRunning.Render("▸ " + row))
+		case e.IsDir:
+			b.WriteString("  " + lipgloss.NewStyle().Foreground(style.Primary).Render(row))
+		default:
+			b.WriteString("  " + row)
+		}
+		b.WriteString("\n")
+	}
+	if len(f.entries) > end-f.scroll {
+		b.WriteString(style.StatusMuted.Render(fmt.Sprintf("  %d/%d", f.cursor+1, len(f.entries))))
+		b.WriteString("\n")
+	}
+}
+
+func (f FileBrowser) renderPreview(b *strings.Builder) {
+	if f.preview.Binary {
+		b.WriteString(style.StatusPending.Render("Binary file, not previewed."))
+		b.WriteString("\n")
+		b.WriteString(style.StatusMuted.Render("Press c to copy it to a local path."))
+		b.WriteString("\n")
+		return
+	}
+
+	end := min(f.previewTop+f.visibleRows(), len(f.previewLines))
+	for _, line := range f.previewLines[f.previewTop:end] {
+		b.WriteString(style.Truncate(line, max(f.width-14, 10)))
+		b.WriteString("\n")
+	}
+	if f.preview.Truncated {
+		b.WriteString(style.StatusMuted.Render(fmt.Sprintf("... preview limited to the first %s, press c to copy the whole file", formatFileSize(int64(len(f.preview.Data))))))
+		b.WriteString("\n")
+	}
+}
+
+// formatFileSize formats a file size like ls -h does.
+func formatFileSize(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%dB", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(b)/float64(div), "KMGT"[exp])
+}
+
+// fileBrowserError explains errors that have a known cause.
+func fileBrowserError(err error) string {
+	if errors.Is(err, repository.ErrNoFileTools) {
+		return "the image has no ls, cat or tar (nor busybox) to browse files with"
+	}
+	return err.Error()
+}
@@ -0,0 +1,75 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+	"sigs.k8s.io/yaml"
+)
+
+// HelmAction shows the Helm release that installed the workload, with
+// the values it was installed with
+func HelmAction(release string) WorkloadActionItem {
+	return WorkloadActionItem{Label: "Helm release", Description: release + ": chart, revision and values", Action: "helm"}
+}
+
+// RenderHelmRelease writes the Helm section of a workload: the chart and
+// app versions, the revision with its status and when it was deployed,
+// then the values supplied on install or upgrade as YAML, with values under
+// sensitive-looking keys masked.
+func RenderHelmRelease(release *repository.HelmRelease) string {
+	var b strings.Builder
+	row := func(label, value string) {
+		if value == "" {
+			value = "-"
+		}
+		b.WriteString(fmt.Sprintf("%-14s %s\n", label+":", value))
+	}
+
+	b.WriteString(style.SubtitleStyle.Render("Helm"))
+	b.WriteString("\n")
+	row("Release", release.Name)
+	row("Namespace", release.Namespace)
+	row("Chart", release.Chart+"-"+release.ChartVersion)
+	row("App version", release.AppVersion)
+	status := release.Status
+	switch status {
+	case "deployed":
+		status = style.StatusRunning.Render(status)
+	case "failed":
+		status = style.StatusError.Render(status)
+	default:
+		if strings.HasPrefix(status, "pending-") {
+			status = style.StatusPending.Render(status)
+		}
+	}
+	row("Revision", fmt.Sprintf("%d (%s)", release.Revision, status))
+	if !release.LastDeployed.IsZero() {
+		row("Last deployed", release.LastDeployed.Local().Format("2006-01-02 15:04:05")+
+			" ("+repository.FormatAge(release.LastDeployed, time.Now())+" ago)")
+	}
+	row("Description", release.Description)
+	b.WriteString("\n")
+
+	b.WriteString(style.SubtitleStyle.Render("Values"))
+	b.WriteString("\n")
+	if len(release.Values) == 0 {
+		b.WriteString(style.StatusMuted.Render("No values supplied, the chart's defaults apply"))
+		b.WriteString("\n")
+		return b.String()
+	}
+	values, err := yaml.Marshal(repository.MaskSensitiveValues(release.Values, secretMask))
+	if err != nil {
+		b.WriteString(style.StatusError.Render("Cannot render values: " + err.Error()))
+		b.WriteString("\n")
+		return b.String()
+	}
+	b.WriteString(HighlightYAML(strings.TrimRight(string(values), "\n")))
+	b.WriteString("\n\n")
+	b.WriteString(style.StatusMuted.Render("Values under keys that look sensitive are masked"))
+	b.WriteString("\n")
+	return b.String()
+}
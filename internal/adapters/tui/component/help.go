@@ -43,10 +43,28 @@ func defaultHelpEntries() [][]HelpEntry {
 			{Key: "/", Desc: "search/filter"},
 			{Key: "c", Desc: "clear filter"},
 			{Key: "r", Desc: "refresh"},
+			{Key: "Z", Desc: "pause refresh"},
+			{Key: "H", Desc: "highlight changes"},
 		},
 		{
 			{Key: "n", Desc: "change namespace"},
 			{Key: "t", Desc: "change resource type"},
+			{Key: "C", Desc: "switch cluster"},
+			{Key: "p", Desc: "probe failures"},
+			{Key: "T", Desc: "pod metrics (top)"},
+			{Key: "W", Desc: "worst pod of workload"},
+			{Key: "u", Desc: "undo last scale"},
+			{Key: "space", Desc: "select pod (a: all)"},
+			{Key: "x", Desc: "bulk actions on selected pods"},
+			{Key: "a", Desc: "node actions"},
+			{Key: "D", Desc: "compare namespaces"},
+			{Key: "N/d", Desc: "new/delete namespace"},
+			{Key: "F", Desc: "port-forwards"},
+			{Key: "B", Desc: "watches"},
+			{Key: "C-p", Desc: "jump anywhere"},
+			{Key: "!", Desc: "namespace warnings"},
+			{Key: "E", Desc: "error log"},
+			{Key: "o", Desc: "workload columns"},
 		},
 		{
 			{Key: "tab", Desc: "next panel"},
@@ -56,10 +74,20 @@ func defaultHelpEntries() [][]HelpEntry {
 		{
 			{Key: "f", Desc: "follow logs"},
 			{Key: "e", Desc: "next error"},
+			{Key: "M", Desc: "merge workload logs"},
+			{Key: "J", Desc: "pretty-print JSON"},
+			{Key: "R", Desc: "logs time range"},
+			{Key: "L", Desc: "logs around event"},
 			{Key: "w", Desc: "wrap lines"},
 			{Key: "v", Desc: "fullscreen"},
 		},
 		{
+			{Key: "x", Desc: "export events"},
+			{Key: "C-s", Desc: "save logs/events"},
+			{Key: "u", Desc: "group events"},
+			{Key: "s", Desc: "sort events by count"},
+			{Key: "i", Desc: "crash diagnosis"},
+			{Key: "C-t", Desc: "next color theme"},
 			{Key: "?", Desc: "toggle help"},
 			{Key: "q", Desc: "quit"},
 		},
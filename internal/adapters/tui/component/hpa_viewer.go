@@ -2,6 +2,7 @@ package component
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 
@@ -50,7 +51,7 @@ func (v HPAViewer) Update(msg tea.Msg) (HPAViewer, tea.Cmd) {
 			// Copy HPA summary to clipboard
 			if v.hpa != nil {
 				summary := v.buildClipboardContent()
-				if err := CopyToClipboard(summary); err == nil {
+				if _, err := CopyToClipboard(summary); err == nil {
 					v.copied = true
 				}
 			}
@@ -124,6 +125,14 @@ func (v HPAViewer) buildClipboardContent() string {
 		b.WriteString("\n")
 	}
 
+	if len(v.hpa.Events) > 0 {
+		b.WriteString("Scaling Timeline:\n")
+		for _, e := range v.hpa.Events {
+			b.WriteString(fmt.Sprintf("  - %s: %s\n", e.Time.Format(hpaTimelineFormat), e.Message))
+		}
+		b.WriteString("\n")
+	}
+
 	if len(v.hpa.Conditions) > 0 {
 		b.WriteString("Conditions:\n")
 		for _, c := range v.hpa.Conditions {
@@ -185,10 +194,52 @@ func (v *HPAViewer) buildLines() {
 			v.lines = append(v.lines, labelStyle.Render("      Name:     ")+valueStyle.Render(metric.Name))
 			v.lines = append(v.lines, labelStyle.Render("      Current:  ")+valueStyle.Render(metric.Current))
 			v.lines = append(v.lines, labelStyle.Render("      Target:   ")+valueStyle.Render(metric.Target))
+			if metric.Ratio > 0 {
+				v.lines = append(v.lines, labelStyle.Render("      Usage:    ")+metricBar(metric.Ratio))
+			}
 			v.lines = append(v.lines, "")
 		}
 	}
 
+	// Behavior
+	if v.hpa.Behavior != nil {
+		v.lines = append(v.lines, headerStyle.Render("Behavior"))
+		v.lines = append(v.lines, "")
+		v.lines = append(v.lines, scalingRulesLines("  Scale Up:   ", v.hpa.Behavior.ScaleUp, labelStyle, valueStyle)...)
+		v.lines = append(v.lines, scalingRulesLines("  Scale Down: ", v.hpa.Behavior.ScaleDown, labelStyle, valueStyle)...)
+		v.lines = append(v.lines, "")
+	}
+
+	// Scaling timeline, oldest first
+	v.lines = append(v.lines, headerStyle.Render("Scaling Timeline"))
+	v.lines = append(v.lines, "")
+	if len(v.hpa.Events) == 0 {
+		v.lines = append(v.lines, style.StatusMuted.Render("  No recent rescale events"))
+	}
+	var previous int32
+	for _, e := range v.hpa.Events {
+		arrow := style.StatusMuted.Render("•")
+		switch {
+		case previous != 0 && e.Replicas > previous:
+			arrow = style.StatusPending.Render("↑")
+		case previous != 0 && e.Replicas < previous:
+			arrow = style.StatusRunning.Render("↓")
+		}
+		line := "  " + style.StatusMuted.Render(e.Time.Format(hpaTimelineFormat)) + "  " + arrow + " " +
+			labelStyle.Render(fmt.Sprintf("%d replicas", e.Replicas))
+		if e.Count > 1 {
+			line += style.StatusMuted.Render(fmt.Sprintf(" (x%d)", e.Count))
+		}
+		v.lines = append(v.lines, line)
+		if e.Reason != "" {
+			v.lines = append(v.lines, "      "+valueStyle.Render(e.Reason))
+		}
+		if e.Replicas != 0 {
+			previous = e.Replicas
+		}
+	}
+	v.lines = append(v.lines, "")
+
 	// Conditions
 	if len(v.hpa.Conditions) > 0 {
 		v.lines = append(v.lines, headerStyle.Render("Conditions"))
@@ -256,6 +307,65 @@ func (v *HPAViewer) buildLines() {
 	}
 }
 
+// hpaTimelineFormat is how rescale times are shown in the timeline.
+const hpaTimelineFormat = "Jan 02 15:04:05"
+
+// metricBarWidth is the width of a metric bar; the target sits in the middle.
+const metricBarWidth = 20
+
+// metricBar draws current against target as a bar with the target marked in
+// the middle, so twice the target or more fills it.
+func metricBar(ratio float64) string {
+	filled := int(math.Round(math.Min(ratio, 2) * metricBarWidth / 2))
+	var b strings.Builder
+	for i := 0; i < metricBarWidth; i++ {
+		if i == metricBarWidth/2 {
+			b.WriteString("│")
+		}
+		if i < filled {
+			b.WriteString("█")
+		} else {
+			b.WriteString("░")
+		}
+	}
+	barStyle := style.StatusRunning
+	switch {
+	case ratio > 1:
+		barStyle = style.StatusError
+	case ratio >= 0.9:
+		barStyle = style.StatusPending
+	}
+	return barStyle.Render(b.String()) + style.StatusMuted.Render(fmt.Sprintf(" %.0f%% of target", ratio*100))
+}
+
+// scalingRulesLines renders the rules of one scaling direction. Directions
+// without rules fall back to the Kubernetes defaults.
+func scalingRulesLines(label string, rules *repository.HPAScalingRules, labelStyle, valueStyle lipgloss.Style) []string {
+	if rules == nil {
+		return []string{labelStyle.Render(label) + style.StatusMuted.Render("default")}
+	}
+	window := "default window"
+	if rules.StabilizationWindowSeconds != nil {
+		window = fmt.Sprintf("%ds window", *rules.StabilizationWindowSeconds)
+	}
+	selectPolicy := rules.SelectPolicy
+	if selectPolicy == "" {
+		selectPolicy = "Max"
+	}
+	lines := []string{labelStyle.Render(label) + valueStyle.Render(fmt.Sprintf("%s, select %s", window, selectPolicy))}
+	indent := strings.Repeat(" ", len(label))
+	for _, p := range rules.Policies {
+		value := fmt.Sprintf("%d", p.Value)
+		if p.Type == "Percent" {
+			value += "%"
+		} else {
+			value += " " + p.Type
+		}
+		lines = append(lines, indent+valueStyle.Render(fmt.Sprintf("%s per %ds", value, p.PeriodSeconds)))
+	}
+	return lines
+}
+
 func (v HPAViewer) wrapText(text string, maxWidth int) []string {
 	if maxWidth < 20 {
 		maxWidth = 20
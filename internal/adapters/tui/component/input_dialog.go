@@ -0,0 +1,133 @@
+package component
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// InputDialog is a modal dialog asking for a line of text, such as a file path
+type InputDialog struct {
+	title   string
+	prompt  string
+	action  string
+	value   string
+	data    interface{}
+	visible bool
+}
+
+// InputDialogResult is returned when the input is submitted
+type InputDialogResult struct {
+	Action string
+	Value  string
+	Data   interface{}
+}
+
+func NewInputDialog() InputDialog {
+	return InputDialog{}
+}
+
+func (d InputDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d InputDialog) Update(msg tea.Msg) (InputDialog, tea.Cmd) {
+	if !d.visible {
+		return d, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return d, nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEsc:
+		d.visible = false
+	case tea.KeyEnter:
+		value := strings.TrimSpace(d.value)
+		if value == "" {
+			return d, nil
+		}
+		d.visible = false
+		return d, func() tea.Msg {
+			return InputDialogResult{Action: d.action, Value: value, Data: d.data}
+		}
+	case tea.KeyBackspace:
+		if len(d.value) > 0 {
+			runes := []rune(d.value)
+			d.value = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		d.value = ""
+	case tea.KeyRunes, tea.KeySpace:
+		d.value += string(keyMsg.Runes)
+	}
+	return d, nil
+}
+
+func (d InputDialog) View() string {
+	if !d.visible {
+		return ""
+	}
+
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(style.Primary).
+		MarginBottom(1)
+	b.WriteString(titleStyle.Render(d.title))
+	b.WriteString("\n\n")
+
+	promptStyle := lipgloss.NewStyle().Foreground(style.Text)
+	b.WriteString(promptStyle.Render(d.prompt))
+	b.WriteString("\n")
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Primary).
+		Padding(0, 1).
+		Width(max(len(d.value)+4, 40))
+	b.WriteString(inputStyle.Render(d.value + "█"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(style.Muted).
+		MarginTop(1)
+	b.WriteString("\n\n")
+	b.WriteString(hintStyle.Render("Enter to save • Ctrl+U to clear • Esc to cancel"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Primary).
+		Padding(1, 2).
+		Background(style.Background)
+
+	return boxStyle.Render(b.String())
+}
+
+// Show opens the dialog with value pre-filled. action and data are passed
+// back in the InputDialogResult.
+func (d *InputDialog) Show(title, prompt, action, value string, data interface{}) {
+	d.title = title
+	d.prompt = prompt
+	d.action = action
+	d.value = value
+	d.data = data
+	d.visible = true
+}
+
+// Value returns the text typed so far.
+func (d InputDialog) Value() string {
+	return d.value
+}
+
+func (d *InputDialog) Hide() {
+	d.visible = false
+}
+
+func (d InputDialog) IsVisible() bool {
+	return d.visible
+}
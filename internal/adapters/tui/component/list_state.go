@@ -0,0 +1,96 @@
+package component
+
+import "github.com/andrebassi/k1s/internal/adapters/repository"
+
+// RelocateCursor returns where a list's cursor belongs after a refresh.
+// prev and next are the row keys (stable identities such as namespace/name)
+// in display order before and after the refresh, and cursor indexes prev.
+//
+// The selected row is followed by identity, so rows inserted or removed
+// above it and reordering leave the same item selected. When the selected
+// item disappeared, the nearest surviving neighbour is selected, preferring
+// the row that was below it. Lists that scroll with the cursor stay
+// anchored on the selected row as a result.
+func RelocateCursor(prev []string, cursor int, next []string) int {
+	if len(next) == 0 {
+		return 0
+	}
+
+	index := make(map[string]int, len(next))
+	for i, k := range next {
+		if _, ok := index[k]; !ok {
+			index[k] = i
+		}
+	}
+
+	if cursor >= 0 && cursor < len(prev) {
+		if i, ok := index[prev[cursor]]; ok {
+			return i
+		}
+		for d := 1; cursor+d < len(prev) || cursor-d >= 0; d++ {
+			if cursor+d < len(prev) {
+				if i, ok := index[prev[cursor+d]]; ok {
+					return i
+				}
+			}
+			if cursor-d >= 0 {
+				if i, ok := index[prev[cursor-d]]; ok {
+					return i
+				}
+			}
+		}
+	}
+
+	// Nothing known survived: stay at the same position
+	return max(0, min(cursor, len(next)-1))
+}
+
+func podKeys(pods []repository.PodInfo) []string {
+	keys := make([]string, len(pods))
+	for i, p := range pods {
+		keys[i] = podChangeID(p)
+	}
+	return keys
+}
+
+func workloadKeys(workloads []repository.WorkloadInfo) []string {
+	keys := make([]string, len(workloads))
+	for i, w := range workloads {
+		keys[i] = workloadChangeID(w)
+	}
+	return keys
+}
+
+func eventKeys(events []repository.EventInfo) []string {
+	keys := make([]string, len(events))
+	for i, e := range events {
+		keys[i] = eventChangeID(e)
+	}
+	return keys
+}
+
+// The section lists below are always of a single namespace.
+
+func hpaKeys(hpas []repository.HPAInfo) []string {
+	keys := make([]string, len(hpas))
+	for i, h := range hpas {
+		keys[i] = h.Name
+	}
+	return keys
+}
+
+func configMapKeys(cms []repository.ConfigMapInfo) []string {
+	keys := make([]string, len(cms))
+	for i, cm := range cms {
+		keys[i] = cm.Name
+	}
+	return keys
+}
+
+func secretKeys(secrets []repository.SecretInfo) []string {
+	keys := make([]string, len(secrets))
+	for i, s := range secrets {
+		keys[i] = s.Name
+	}
+	return keys
+}
@@ -0,0 +1,115 @@
+package component
+
+import (
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// logBuffer is a fixed-capacity ring of log lines: appending to a full
+// buffer overwrites the oldest line. Every line gets a sequence number,
+// counting from the first line ever appended, so an index of lines stays
+// valid while the buffer wraps: a line is gone once its number is below
+// First().
+type logBuffer struct {
+	lines   []repository.LogLine
+	start   int // Position of the oldest line in lines
+	count   int
+	first   int // Sequence number of the oldest line
+	dropped int // Lines overwritten since the last Reset
+	errors  int // Retained lines with IsError set
+}
+
+func newLogBuffer(capacity int) *logBuffer {
+	if capacity <= 0 {
+		capacity = configs.DefaultLogBufferLines
+	}
+	return &logBuffer{lines: make([]repository.LogLine, capacity)}
+}
+
+// Append adds a line, dropping the oldest one when the buffer is full.
+func (b *logBuffer) Append(line repository.LogLine) {
+	if b.count < len(b.lines) {
+		b.lines[(b.start+b.count)%len(b.lines)] = line
+		b.count++
+	} else {
+		if b.lines[b.start].IsError {
+			b.errors--
+		}
+		b.lines[b.start] = line
+		b.start = (b.start + 1) % len(b.lines)
+		b.first++
+		b.dropped++
+	}
+	if line.IsError {
+		b.errors++
+	}
+}
+
+// Reset replaces the contents with lines, keeping the newest ones when they
+// don't fit. Sequence numbers carry on from the lines replaced.
+func (b *logBuffer) Reset(lines []repository.LogLine) {
+	next := b.Next()
+	clear(b.lines)
+	b.start, b.count, b.first, b.dropped, b.errors = 0, 0, next, 0, 0
+	if over := len(lines) - len(b.lines); over > 0 {
+		b.first += over
+		b.dropped = over
+		lines = lines[over:]
+	}
+	for _, line := range lines {
+		b.lines[b.count] = line
+		b.count++
+		if line.IsError {
+			b.errors++
+		}
+	}
+}
+
+// SetCapacity resizes the buffer, keeping the newest lines that fit.
+func (b *logBuffer) SetCapacity(capacity int) {
+	if capacity <= 0 {
+		capacity = configs.DefaultLogBufferLines
+	}
+	if capacity == len(b.lines) {
+		return
+	}
+	kept := make([]repository.LogLine, b.count)
+	for i := range kept {
+		kept[i] = b.At(i)
+	}
+	dropped, next := b.dropped, b.Next()
+	b.lines = make([]repository.LogLine, capacity)
+	b.Reset(kept)
+	// Reset numbers the lines from Next; put them back where they were
+	b.first = next - b.count
+	b.dropped += dropped
+}
+
+// Len returns the number of lines retained.
+func (b *logBuffer) Len() int { return b.count }
+
+// Cap returns the most lines the buffer retains.
+func (b *logBuffer) Cap() int { return len(b.lines) }
+
+// At returns the i-th oldest retained line.
+func (b *logBuffer) At(i int) repository.LogLine {
+	return b.lines[(b.start+i)%len(b.lines)]
+}
+
+// AtSeq returns the line with sequence number seq, which must be retained.
+func (b *logBuffer) AtSeq(seq int) repository.LogLine {
+	return b.At(seq - b.first)
+}
+
+// First returns the sequence number of the oldest retained line.
+func (b *logBuffer) First() int { return b.first }
+
+// Next returns the sequence number the next appended line gets.
+func (b *logBuffer) Next() int { return b.first + b.count }
+
+// Dropped returns how many lines were dropped to make room since the
+// buffer was last reset.
+func (b *logBuffer) Dropped() int { return b.dropped }
+
+// Errors returns how many retained lines are errors.
+func (b *logBuffer) Errors() int { return b.errors }
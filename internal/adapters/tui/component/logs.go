@@ -5,10 +5,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/configs"
 	"github.com/andrebassi/k1s/internal/adapters/repository"
 	"github.com/andrebassi/k1s/internal/adapters/tui/style"
 )
@@ -34,24 +36,57 @@ var timeFilterLabels = map[TimeFilter]string{
 	TimeFilter6Hours: "6h",
 }
 
+// String returns the filter's label ("All", "5m", ...).
+func (f TimeFilter) String() string {
+	return timeFilterLabels[f]
+}
+
+// ParseTimeFilter returns the filter with the given label. Unknown and
+// empty labels give TimeFilterAll.
+func ParseTimeFilter(label string) TimeFilter {
+	for f, l := range timeFilterLabels {
+		if l == label {
+			return f
+		}
+	}
+	return TimeFilterAll
+}
+
 // LogsPanel displays container logs with filtering and search capabilities.
 // Features include: time filtering, text search, multi-container support,
 // follow mode, and error highlighting.
+//
+// Lines are kept in a ring buffer, so a long followed stream drops its
+// oldest lines, and only the lines in view are rendered: appending costs
+// the same however many lines are kept.
 type LogsPanel struct {
-	logs         []repository.LogLine
+	logs         *logBuffer // Shared by copies of the panel
+	shown        []int      // Sequence numbers of the lines passing the filters, oldest first
+	offset       int        // Index in shown of the top line in view
 	viewport     viewport.Model
 	ready        bool
 	width        int
 	height       int
 	following    bool
 	filter       string
-	containers   []string // list of container names
-	containerIdx int      // -1 = all, 0+ = specific container
+	containers   []string        // list of container names
+	initNames    map[string]bool // containers that are init containers
+	containerIdx int             // -1 = all, 0+ = specific container
 	showPrevious bool     // show previous container logs
+	comparing    bool     // show previous and current logs merged
+	noPrevious   bool     // comparing, but the container has not restarted
+	workload     string   // workload of the pod, "" when it has none
+	workloadMode bool     // show the merged logs of every pod of the workload
+	prettyJSON   bool     // show JSON lines as level, message and key=value fields
 	searching    bool     // true when search input is active
 	searchInput  textinput.Model
 	timeFilter   TimeFilter
+	timeRange    repository.LogTimeRange // Window the logs were fetched for; zero for the latest logs
+	rangeEditing bool                    // true when the time range input is active
+	rangeInput   textinput.Model
+	rangeErr     string // Why the typed range was rejected
 	copyStatus   string // Status message after copy
+	nextRestarts map[string]time.Time // Estimated restart of each container in CrashLoopBackOff
 }
 
 // NewLogsPanel creates a new logs panel with default settings.
@@ -62,10 +97,17 @@ func NewLogsPanel() LogsPanel {
 	ti.CharLimit = 100
 	ti.Width = 30
 
+	ri := textinput.New()
+	ri.Placeholder = "10:42-10:55 or RFC3339/RFC3339"
+	ri.CharLimit = 80
+	ri.Width = 40
+
 	return LogsPanel{
+		logs:         newLogBuffer(configs.DefaultLogBufferLines),
 		following:    true,
 		containerIdx: -1, // -1 means all containers
 		searchInput:  ti,
+		rangeInput:   ri,
 	}
 }
 
@@ -78,6 +120,34 @@ func (l LogsPanel) Update(msg tea.Msg) (LogsPanel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Handle time range input
+		if l.rangeEditing {
+			switch msg.String() {
+			case "esc":
+				l.closeRangeInput()
+				return l, nil
+			case "enter":
+				value := strings.TrimSpace(l.rangeInput.Value())
+				if value == "" {
+					l.closeRangeInput()
+					l.ClearTimeRange()
+					return l, nil
+				}
+				r, err := repository.ParseLogTimeRange(value, time.Now())
+				if err != nil {
+					l.rangeErr = err.Error()
+					return l, nil
+				}
+				l.closeRangeInput()
+				l.SetTimeRange(r)
+				return l, nil
+			default:
+				l.rangeInput, cmd = l.rangeInput.Update(msg)
+				l.rangeErr = ""
+				return l, cmd
+			}
+		}
+
 		// Handle search mode
 		if l.searching {
 			switch msg.String() {
@@ -113,13 +183,26 @@ func (l LogsPanel) Update(msg tea.Msg) (LogsPanel, tea.Cmd) {
 		case "enter":
 			// Copy logs to clipboard
 			content := l.getPlainTextLogs()
-			err := CopyToClipboard(content)
+			copied, err := CopyToClipboard(content)
 			if err == nil {
-				l.copyStatus = "Copied to clipboard!"
+				l.copyStatus = copied.Status()
 			} else {
 				l.copyStatus = "Copy failed: " + err.Error()
 			}
 			return l, nil
+		case "ctrl+s":
+			// Save the displayed logs to a file, prompted for by app.go
+			req := SavePanelRequest{
+				Panel:     "logs",
+				Container: l.SelectedContainer(),
+				Filter:    l.filter,
+				Lines:     len(l.getFilteredLogs()),
+				Content:   l.getPlainTextLogs(),
+			}
+			if l.workloadMode {
+				req.Workload = l.workload
+			}
+			return l, func() tea.Msg { return req }
 		case "/":
 			l.searching = true
 			l.searchInput.Focus()
@@ -131,32 +214,99 @@ func (l LogsPanel) Update(msg tea.Msg) (LogsPanel, tea.Cmd) {
 			l.updateContent()
 			return l, nil
 		case "f":
+			// Following shows the latest logs, so it ends a time range
+			if !l.timeRange.IsZero() {
+				l.ClearTimeRange()
+				return l, nil
+			}
 			l.following = !l.following
 			if l.following {
-				l.viewport.GotoBottom()
+				l.gotoBottom()
 			}
 		case "e":
 			l.jumpToNextError()
 		case "g":
-			l.viewport.GotoTop()
+			l.gotoTop()
 		case "G":
-			l.viewport.GotoBottom()
+			l.gotoBottom()
 		case "[":
 			l.prevContainer()
 		case "]":
 			l.nextContainer()
 		case "P":
 			l.showPrevious = !l.showPrevious
+			l.comparing = false
+			l.workloadMode = false
 			// Note: actual previous logs fetch handled by dashboard
+		case "D":
+			l.comparing = !l.comparing
+			l.showPrevious = false
+			l.workloadMode = false
+			// Note: fetching both instances is handled by the app
+		case "M":
+			if l.workload != "" {
+				l.workloadMode = !l.workloadMode
+				l.showPrevious = false
+				l.comparing = false
+				// Note: fetching every pod's logs is handled by the app
+			}
+		case "J":
+			l.prettyJSON = !l.prettyJSON
+			l.updateContent()
+			return l, nil
 		case "T":
+			// The presets count back from now, so they replace a time range
+			if !l.timeRange.IsZero() {
+				l.ClearTimeRange()
+			}
 			l.cycleTimeFilter()
 			l.updateContent()
 			return l, nil
+		case "R":
+			l.rangeEditing = true
+			l.rangeErr = ""
+			l.rangeInput.SetValue("")
+			l.rangeInput.Focus()
+			return l, textinput.Blink
 		}
 	}
 
-	l.viewport, cmd = l.viewport.Update(msg)
-	return l, cmd
+	l.scroll(msg)
+	return l, nil
+}
+
+// scroll moves the view for the viewport's scroll keys and the mouse wheel.
+// The viewport only holds the lines in view, so it can't scroll itself.
+func (l *LogsPanel) scroll(msg tea.Msg) {
+	keys := l.viewport.KeyMap
+	page := l.visibleLines()
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.PageDown):
+			l.setOffset(l.offset + page)
+		case key.Matches(msg, keys.PageUp):
+			l.setOffset(l.offset - page)
+		case key.Matches(msg, keys.HalfPageDown):
+			l.setOffset(l.offset + max(page/2, 1))
+		case key.Matches(msg, keys.HalfPageUp):
+			l.setOffset(l.offset - max(page/2, 1))
+		case key.Matches(msg, keys.Down):
+			l.setOffset(l.offset + 1)
+		case key.Matches(msg, keys.Up):
+			l.setOffset(l.offset - 1)
+		}
+	case tea.MouseMsg:
+		if !l.viewport.MouseWheelEnabled || msg.Action != tea.MouseActionPress {
+			return
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelDown:
+			l.setOffset(l.offset + l.viewport.MouseWheelDelta)
+		case tea.MouseButtonWheelUp:
+			l.setOffset(l.offset - l.viewport.MouseWheelDelta)
+		}
+	}
 }
 
 func (l LogsPanel) View() string {
@@ -172,6 +322,9 @@ func (l LogsPanel) View() string {
 		containerName := "all"
 		if l.containerIdx >= 0 && l.containerIdx < len(l.containers) {
 			containerName = l.containers[l.containerIdx]
+			if l.initNames[containerName] {
+				containerName += " (init)"
+			}
 		}
 		header.WriteString(style.SubtitleStyle.Render(fmt.Sprintf(" [%s]", containerName)))
 
@@ -181,15 +334,36 @@ func (l LogsPanel) View() string {
 		}
 	}
 
+	if l.workloadMode {
+		header.WriteString(style.SubtitleStyle.Render(fmt.Sprintf(" [all pods of %s]", l.workload)))
+	}
 	if l.showPrevious {
 		header.WriteString(style.EventWarning.Render(" [Previous]"))
 	}
-	if l.following && !l.showPrevious {
+	if l.comparing {
+		header.WriteString(style.EventWarning.Render(" [Compare]"))
+		if l.noPrevious {
+			header.WriteString(style.HelpDescStyle.Render(" (no previous instance)"))
+		}
+	}
+	if l.following && !l.showPrevious && !l.comparing && l.timeRange.IsZero() {
 		header.WriteString(style.StatusRunning.Render(" [Following]"))
 	}
+	if at, ok := l.nextRestart(); ok {
+		header.WriteString(style.EventWarning.Render(" " + repository.FormatRestartETA(at, time.Now())))
+	}
+	if dropped := l.logs.Dropped(); dropped > 0 {
+		header.WriteString(style.HelpDescStyle.Render(fmt.Sprintf(" [buffer full, %d oldest dropped]", dropped)))
+	}
+
+	if l.prettyJSON {
+		header.WriteString(style.SubtitleStyle.Render(" [JSON]"))
+	}
 
 	// Show time filter indicator
-	if l.timeFilter != TimeFilterAll {
+	if !l.timeRange.IsZero() {
+		header.WriteString(style.HelpKeyStyle.Render(fmt.Sprintf(" [%s]", l.timeRange)))
+	} else if l.timeFilter != TimeFilterAll {
 		header.WriteString(style.HelpKeyStyle.Render(fmt.Sprintf(" [%s]", timeFilterLabels[l.timeFilter])))
 	}
 
@@ -208,6 +382,16 @@ func (l LogsPanel) View() string {
 		header.WriteString("\n")
 	}
 
+	// Show time range input if editing
+	if l.rangeEditing {
+		header.WriteString(style.HelpKeyStyle.Render("Range: "))
+		header.WriteString(l.rangeInput.View())
+		if l.rangeErr != "" {
+			header.WriteString(style.LogError.Render(" " + l.rangeErr))
+		}
+		header.WriteString("\n")
+	}
+
 	result := header.String() + l.viewport.View()
 
 	// Show copy status at bottom right
@@ -224,11 +408,48 @@ func (l LogsPanel) View() string {
 }
 
 func (l *LogsPanel) SetLogs(logs []repository.LogLine) {
-	l.logs = logs
+	l.logs.Reset(logs)
 	l.copyStatus = "" // Clear copy status when logs update
 	l.updateContent()
 }
 
+// SetComparedLogs shows logs merged by repository.CompareLogs while in
+// compare mode. hasPrevious is false when the container has no previous
+// instance, in which case logs are the current logs alone.
+func (l *LogsPanel) SetComparedLogs(logs []repository.LogLine, hasPrevious bool) {
+	l.noPrevious = !hasPrevious
+	l.SetLogs(logs)
+}
+
+// AppendLogs adds lines from a followed log stream. Once the buffer is
+// full the oldest lines are dropped. Only the new lines are filtered, and
+// only the lines in view re-rendered.
+func (l *LogsPanel) AppendLogs(logs []repository.LogLine) {
+	now := time.Now()
+	matches := l.lineFilter(now)
+	for _, log := range logs {
+		seq := l.logs.Next()
+		l.logs.Append(log)
+		if matches(log) {
+			l.shown = append(l.shown, seq)
+		}
+	}
+	l.pruneShown(now)
+	l.render()
+}
+
+// SetBufferSize sets how many lines are kept (configs.DefaultLogBufferLines when
+// n <= 0), dropping the oldest ones that no longer fit.
+func (l *LogsPanel) SetBufferSize(n int) {
+	l.logs.SetCapacity(n)
+	l.updateContent()
+}
+
+// BufferSize returns how many lines are kept.
+func (l LogsPanel) BufferSize() int {
+	return l.logs.Cap()
+}
+
 func (l *LogsPanel) SetSize(width, height int) {
 	l.width = width
 	l.height = height - 2
@@ -241,12 +462,82 @@ func (l *LogsPanel) SetSize(width, height int) {
 		l.viewport.Height = l.height
 	}
 
-	l.updateContent()
+	l.render()
 }
 
 func (l *LogsPanel) SetContainers(containers []string) {
+	selected := l.SelectedContainer()
 	l.containers = containers
 	l.containerIdx = -1 // reset to "all" when containers change
+	// Keep the selection across refreshes while the container still exists
+	if selected != "" {
+		l.SelectContainer(selected)
+	}
+}
+
+// SetNextRestarts sets when each container in CrashLoopBackOff is
+// estimated to restart, counted down in the header.
+func (l *LogsPanel) SetNextRestarts(restarts map[string]time.Time) {
+	l.nextRestarts = restarts
+}
+
+// nextRestart returns the estimated restart of the selected container, or
+// the earliest one when all containers are shown.
+func (l LogsPanel) nextRestart() (time.Time, bool) {
+	if selected := l.SelectedContainer(); selected != "" {
+		at, ok := l.nextRestarts[selected]
+		return at, ok
+	}
+	var next time.Time
+	for _, at := range l.nextRestarts {
+		if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// SetInitContainers marks which of the containers are init containers, shown
+// with an "(init)" suffix in the container switcher.
+func (l *LogsPanel) SetInitContainers(names []string) {
+	l.initNames = make(map[string]bool, len(names))
+	for _, n := range names {
+		l.initNames[n] = true
+	}
+}
+
+// SelectContainer shows logs for the named container only. Returns false,
+// leaving the selection unchanged, if the pod has no such container.
+func (l *LogsPanel) SelectContainer(name string) bool {
+	for i, c := range l.containers {
+		if c == name {
+			l.containerIdx = i
+			l.updateContent()
+			return true
+		}
+	}
+	return false
+}
+
+// PrettyJSON reports whether JSON log lines are pretty-printed.
+func (l LogsPanel) PrettyJSON() bool {
+	return l.prettyJSON
+}
+
+// SetWorkload sets the workload the pod belongs to, enabling the merged
+// logs of all its pods ("M"). An empty name disables that mode.
+func (l *LogsPanel) SetWorkload(name string) {
+	l.workload = name
+	if name == "" && l.workloadMode {
+		l.workloadMode = false
+		l.updateContent()
+	}
+}
+
+// WorkloadMode reports whether the merged logs of every pod of the
+// workload are shown instead of the pod's own.
+func (l LogsPanel) WorkloadMode() bool {
+	return l.workloadMode
 }
 
 func (l *LogsPanel) nextContainer() {
@@ -284,6 +575,57 @@ func (l LogsPanel) ShowPrevious() bool {
 	return l.showPrevious
 }
 
+// Comparing reports whether previous and current logs are shown merged.
+func (l LogsPanel) Comparing() bool {
+	return l.comparing
+}
+
+// TimeFilter returns the time filter applied to the logs.
+func (l LogsPanel) TimeFilter() TimeFilter {
+	return l.timeFilter
+}
+
+// SetTimeFilter sets the time filter applied to the logs.
+func (l *LogsPanel) SetTimeFilter(f TimeFilter) {
+	l.timeFilter = f
+	l.updateContent()
+}
+
+// TimeRange returns the window of time the logs are shown for, zero for
+// the latest logs.
+func (l LogsPanel) TimeRange() repository.LogTimeRange {
+	return l.timeRange
+}
+
+// SetTimeRange shows the logs written within r, which the app fetches.
+// A range is history, so following stops and the time filter presets are
+// cleared; a zero r goes back to following the latest logs.
+func (l *LogsPanel) SetTimeRange(r repository.LogTimeRange) {
+	l.timeRange = r
+	l.timeFilter = TimeFilterAll
+	l.following = r.IsZero()
+	l.updateContent()
+	if !r.IsZero() {
+		l.gotoTop()
+	}
+}
+
+// ClearTimeRange goes back to following the latest logs.
+func (l *LogsPanel) ClearTimeRange() {
+	l.SetTimeRange(repository.LogTimeRange{})
+}
+
+// EditingTimeRange reports whether the time range input is open.
+func (l LogsPanel) EditingTimeRange() bool {
+	return l.rangeEditing
+}
+
+func (l *LogsPanel) closeRangeInput() {
+	l.rangeEditing = false
+	l.rangeErr = ""
+	l.rangeInput.Blur()
+}
+
 func (l *LogsPanel) cycleTimeFilter() {
 	l.timeFilter = (l.timeFilter + 1) % 5
 }
@@ -311,70 +653,137 @@ func (l *LogsPanel) SetFilter(filter string) {
 func (l *LogsPanel) ToggleFollow() {
 	l.following = !l.following
 	if l.following {
-		l.viewport.GotoBottom()
+		l.gotoBottom()
 	}
 }
 
+// Restyle re-renders the logs with the active theme.
+func (l *LogsPanel) Restyle() {
+	l.updateContent()
+}
+
+// updateContent filters the lines again, after a filter changed or the
+// lines were replaced, and renders the lines in view.
 func (l *LogsPanel) updateContent() {
+	matches := l.lineFilter(time.Now())
+	l.shown = l.shown[:0]
+	for seq := l.logs.First(); seq < l.logs.Next(); seq++ {
+		if matches(l.logs.AtSeq(seq)) {
+			l.shown = append(l.shown, seq)
+		}
+	}
+	l.render()
+}
+
+// pruneShown drops the lines that left the buffer from the shown lines,
+// and those that aged out of the time filter preset, keeping the same
+// lines in view when not following.
+func (l *LogsPanel) pruneShown(now time.Time) {
+	first := l.logs.First()
+	cutoff := time.Time{}
+	if d := l.getTimeFilterDuration(); d > 0 {
+		cutoff = now.Add(-d)
+	}
+	n := 0
+	for n < len(l.shown) {
+		if seq := l.shown[n]; seq >= first && (cutoff.IsZero() || l.logs.AtSeq(seq).Timestamp.After(cutoff)) {
+			break
+		}
+		n++
+	}
+	l.shown = l.shown[n:]
+	l.offset -= n
+}
+
+// render puts the lines in view in the viewport: following shows the
+// latest ones, otherwise the view stays at offset.
+func (l *LogsPanel) render() {
+	if l.following {
+		l.offset = l.maxOffset()
+	}
+	l.offset = min(max(l.offset, 0), l.maxOffset())
 	if !l.ready {
 		return
 	}
 
 	var content strings.Builder
-	filteredLogs := l.getFilteredLogs()
-
-	for _, log := range filteredLogs {
-		line := l.formatLogLine(log)
-		content.WriteString(line)
+	end := min(l.offset+l.visibleLines(), len(l.shown))
+	for _, seq := range l.shown[l.offset:end] {
+		content.WriteString(l.formatLogLine(l.logs.AtSeq(seq)))
 		content.WriteString("\n")
 	}
-
 	l.viewport.SetContent(content.String())
+	l.viewport.GotoTop()
+}
 
-	if l.following {
-		l.viewport.GotoBottom()
-	}
+// visibleLines returns how many lines fit in the view.
+func (l LogsPanel) visibleLines() int {
+	return max(l.viewport.Height, 1)
+}
+
+// maxOffset returns the offset that shows the latest lines.
+func (l LogsPanel) maxOffset() int {
+	return max(len(l.shown)-l.visibleLines(), 0)
+}
+
+func (l *LogsPanel) setOffset(offset int) {
+	l.offset = offset
+	l.render()
 }
 
+func (l *LogsPanel) gotoTop() {
+	l.setOffset(0)
+}
+
+func (l *LogsPanel) gotoBottom() {
+	l.setOffset(l.maxOffset())
+}
+
+// getFilteredLogs returns the lines passing the filters.
 func (l LogsPanel) getFilteredLogs() []repository.LogLine {
-	var filtered []repository.LogLine
-	now := time.Now()
-	timeDuration := l.getTimeFilterDuration()
+	filtered := make([]repository.LogLine, 0, len(l.shown))
+	for _, seq := range l.shown {
+		filtered = append(filtered, l.logs.AtSeq(seq))
+	}
+	return filtered
+}
 
-	// First filter by container if specific container selected
+// lineFilter returns whether a line passes the filters: the container, the
+// time range (as the logs shown until its fetch lands are the latest ones),
+// the time preset counted back from now and the text filter.
+func (l LogsPanel) lineFilter(now time.Time) func(repository.LogLine) bool {
 	selectedContainer := l.SelectedContainer()
-	for _, log := range l.logs {
-		if selectedContainer != "" && log.Container != selectedContainer {
-			continue
-		}
-		filtered = append(filtered, log)
+	var cutoff time.Time
+	if d := l.getTimeFilterDuration(); d > 0 {
+		cutoff = now.Add(-d)
 	}
+	// A field query such as level=error or status>=500 matches JSON lines
+	// on that field
+	filter := strings.ToLower(l.filter)
+	query, isQuery := repository.ParseLogFieldQuery(l.filter)
 
-	// Then filter by time if set
-	if timeDuration > 0 {
-		cutoff := now.Add(-timeDuration)
-		var timeFiltered []repository.LogLine
-		for _, log := range filtered {
-			if !log.Timestamp.IsZero() && log.Timestamp.After(cutoff) {
-				timeFiltered = append(timeFiltered, log)
-			}
+	return func(log repository.LogLine) bool {
+		if selectedContainer != "" && log.Container != selectedContainer {
+			return false
 		}
-		filtered = timeFiltered
-	}
-
-	// Then filter by text filter if set
-	if l.filter != "" {
-		filter := strings.ToLower(l.filter)
-		var textFiltered []repository.LogLine
-		for _, log := range filtered {
-			if strings.Contains(strings.ToLower(log.Content), filter) {
-				textFiltered = append(textFiltered, log)
+		if !l.timeRange.IsZero() && (log.Timestamp.IsZero() || !l.timeRange.Contains(log.Timestamp)) {
+			return false
+		}
+		if !cutoff.IsZero() && (log.Timestamp.IsZero() || !log.Timestamp.After(cutoff)) {
+			return false
+		}
+		if filter == "" {
+			return true
+		}
+		if isQuery {
+			if entry, ok := repository.ParseJSONLog(log.Content); ok {
+				return query.Match(entry)
 			}
 		}
-		filtered = textFiltered
+		// In workload mode a search can also pick out a pod
+		return strings.Contains(strings.ToLower(log.Content), filter) ||
+			(l.workloadMode && strings.Contains(strings.ToLower(log.Pod), filter))
 	}
-
-	return filtered
 }
 
 func (l LogsPanel) formatLogLine(log repository.LogLine) string {
@@ -386,12 +795,30 @@ func (l LogsPanel) formatLogLine(log repository.LogLine) string {
 		b.WriteString(" ")
 	}
 
+	if l.workloadMode && log.Pod != "" {
+		b.WriteString(style.GetLogPodStyle(log.Pod).Render(fmt.Sprintf("[%s]", log.Pod)))
+		b.WriteString(" ")
+	}
+
 	// Show container name when viewing all containers
 	if log.Container != "" && l.containerIdx == -1 && len(l.containers) > 1 {
 		b.WriteString(style.LogContainer.Render(fmt.Sprintf("[%s]", log.Container)))
 		b.WriteString(" ")
 	}
 
+	if l.comparing {
+		if log.Previous {
+			b.WriteString(style.LogPrevious.Render("- " + log.Content))
+			return b.String()
+		}
+		b.WriteString("  ")
+	}
+
+	if entry, ok := l.jsonLog(log); ok {
+		b.WriteString(renderJSONLog(entry))
+		return b.String()
+	}
+
 	if log.IsError {
 		b.WriteString(style.LogError.Render(log.Content))
 	} else {
@@ -401,25 +828,22 @@ func (l LogsPanel) formatLogLine(log repository.LogLine) string {
 	return b.String()
 }
 
+// jumpToNextError scrolls to the next error line after the top of the
+// view, wrapping around. It scans every shown line, not just the visible
+// page, so it also finds errors from any pod in workload mode.
 func (l *LogsPanel) jumpToNextError() {
-	content := l.viewport.View()
-	lines := strings.Split(content, "\n")
-	currentLine := l.viewport.YOffset
-
-	for i := currentLine + 1; i < len(lines); i++ {
-		if strings.Contains(strings.ToLower(lines[i]), "error") ||
-			strings.Contains(strings.ToLower(lines[i]), "fatal") ||
-			strings.Contains(strings.ToLower(lines[i]), "panic") {
-			l.viewport.SetYOffset(i)
+	currentLine := l.offset
+
+	for i := currentLine + 1; i < len(l.shown); i++ {
+		if l.logs.AtSeq(l.shown[i]).IsError {
+			l.setOffset(i)
 			return
 		}
 	}
 
-	for i := 0; i < currentLine; i++ {
-		if strings.Contains(strings.ToLower(lines[i]), "error") ||
-			strings.Contains(strings.ToLower(lines[i]), "fatal") ||
-			strings.Contains(strings.ToLower(lines[i]), "panic") {
-			l.viewport.SetYOffset(i)
+	for i := 0; i < currentLine && i < len(l.shown); i++ {
+		if l.logs.AtSeq(l.shown[i]).IsError {
+			l.setOffset(i)
 			return
 		}
 	}
@@ -430,24 +854,27 @@ func (l LogsPanel) IsFollowing() bool {
 }
 
 func (l LogsPanel) LogCount() int {
-	return len(l.logs)
+	return l.logs.Len()
 }
 
 func (l LogsPanel) ErrorCount() int {
-	count := 0
-	for _, log := range l.logs {
-		if log.IsError {
-			count++
-		}
-	}
-	return count
+	return l.logs.Errors()
+}
+
+// DroppedCount returns how many of the oldest lines were dropped because
+// the buffer was full.
+func (l LogsPanel) DroppedCount() int {
+	return l.logs.Dropped()
 }
 
+// IsSearching reports whether a text input, the search or the time range,
+// is taking the keys.
 func (l LogsPanel) IsSearching() bool {
-	return l.searching
+	return l.searching || l.rangeEditing
 }
 
 func (l *LogsPanel) ClearSearch() {
+	l.closeRangeInput()
 	l.searching = false
 	l.filter = ""
 	l.searchInput.SetValue("")
@@ -471,14 +898,87 @@ func (l LogsPanel) getPlainTextLogs() string {
 			content.WriteString(" ")
 		}
 
+		if l.workloadMode && log.Pod != "" {
+			content.WriteString(fmt.Sprintf("[%s] ", log.Pod))
+		}
+
 		// Show container name when viewing all containers
 		if log.Container != "" && l.containerIdx == -1 && len(l.containers) > 1 {
 			content.WriteString(fmt.Sprintf("[%s] ", log.Container))
 		}
 
-		content.WriteString(log.Content)
+		if l.comparing {
+			if log.Previous {
+				content.WriteString("- ")
+			} else {
+				content.WriteString("  ")
+			}
+		}
+
+		if entry, ok := l.jsonLog(log); ok && !(l.comparing && log.Previous) {
+			content.WriteString(jsonLogText(entry))
+		} else {
+			content.WriteString(log.Content)
+		}
 		content.WriteString("\n")
 	}
 
 	return content.String()
 }
+
+// jsonLog parses a line for pretty-printing. Returns false when pretty
+// mode is off or the line is not JSON.
+func (l LogsPanel) jsonLog(log repository.LogLine) (*repository.JSONLog, bool) {
+	if !l.prettyJSON {
+		return nil, false
+	}
+	return repository.ParseJSONLog(log.Content)
+}
+
+// renderJSONLog renders a JSON line as "time LEVEL message key=value ...",
+// with the level colored by severity.
+func renderJSONLog(entry *repository.JSONLog) string {
+	var parts []string
+	if entry.Time != "" {
+		parts = append(parts, style.LogTimestamp.Render(entry.Time))
+	}
+	if entry.Level != "" {
+		parts = append(parts, jsonLevelStyle(entry).Render(strings.ToUpper(entry.Level)))
+	}
+	if entry.Message != "" {
+		if entry.IsError() {
+			parts = append(parts, style.LogError.Render(entry.Message))
+		} else {
+			parts = append(parts, style.LogNormal.Render(entry.Message))
+		}
+	}
+	if fields := entry.FieldsText(); fields != "" {
+		parts = append(parts, style.HelpDescStyle.Render(fields))
+	}
+	return strings.Join(parts, " ")
+}
+
+// jsonLogText is renderJSONLog without colors, for copying.
+func jsonLogText(entry *repository.JSONLog) string {
+	var parts []string
+	for _, part := range []string{entry.Time, strings.ToUpper(entry.Level), entry.Message, entry.FieldsText()} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// jsonLevelStyle returns the style of a JSON line's level.
+func jsonLevelStyle(entry *repository.JSONLog) lipgloss.Style {
+	switch {
+	case entry.IsError():
+		return style.LogError
+	case entry.Level == "warn" || entry.Level == "warning":
+		return style.StatusPending
+	case entry.Level == "info" || entry.Level == "notice":
+		return style.StatusRunning
+	default:
+		return style.StatusMuted
+	}
+}
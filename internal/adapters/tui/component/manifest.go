@@ -3,6 +3,7 @@ package component
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,6 +23,10 @@ type ManifestPanel struct {
 	pod      *repository.PodInfo
 	related  *repository.RelatedResources
 	helpers  []repository.DebugHelper
+	crash    *repository.CrashDiagnosis // Shown as a banner above the details
+	probes   []repository.ProbeStatus
+	oom      *repository.OOMReport
+	security *repository.PodSecurityReport
 	viewport viewport.Model
 	ready    bool
 	width    int
@@ -51,6 +56,10 @@ func (m ManifestPanel) View() string {
 	var header strings.Builder
 	header.WriteString(style.PanelTitleStyle.Render("Pod Details"))
 	header.WriteString("\n")
+	if m.crash != nil {
+		header.WriteString(m.renderCrashBanner())
+		header.WriteString("\n")
+	}
 
 	content := header.String() + m.viewport.View()
 
@@ -83,6 +92,55 @@ func (m *ManifestPanel) SetHelpers(helpers []repository.DebugHelper) {
 	m.updateContent()
 }
 
+// SetDiagnosis sets the root cause shown as a one-line banner, nil to hide it.
+func (m *ManifestPanel) SetDiagnosis(diagnosis *repository.CrashDiagnosis) {
+	m.crash = diagnosis
+	m.resizeViewport()
+}
+
+// SetProbes sets the probe status shown in the Probes section.
+func (m *ManifestPanel) SetProbes(probes []repository.ProbeStatus) {
+	m.probes = probes
+	m.updateContent()
+}
+
+// SetOOM sets the OOM kills and memory headroom shown in the OOM section.
+func (m *ManifestPanel) SetOOM(report *repository.OOMReport) {
+	m.oom = report
+	m.updateContent()
+}
+
+// SetSecurity sets the security conflicts and Pod Security violations
+// shown in the Security section.
+func (m *ManifestPanel) SetSecurity(report *repository.PodSecurityReport) {
+	m.security = report
+	m.updateContent()
+}
+
+// RefreshCountdown re-renders the content so the restart countdown of a
+// container in CrashLoopBackOff stays current.
+func (m *ManifestPanel) RefreshCountdown() {
+	m.updateContent()
+}
+
+// Probes returns the probes shown in the Probes section.
+func (m ManifestPanel) Probes() []repository.ProbeStatus {
+	return m.probes
+}
+
+// Diagnosis returns the root cause shown in the banner, or nil.
+func (m ManifestPanel) Diagnosis() *repository.CrashDiagnosis {
+	return m.crash
+}
+
+// renderCrashBanner renders the diagnosis summary on one line, with the key
+// that expands the full reasoning.
+func (m ManifestPanel) renderCrashBanner() string {
+	hint := " [i] why"
+	summary := style.Truncate("⚠ "+m.crash.Summary, max(m.width-len(hint)-1, 10))
+	return style.StatusError.Render(summary) + style.StatusMuted.Render(hint)
+}
+
 // GetWorkload returns the workload kind and name if available.
 func (m *ManifestPanel) GetWorkload() (kind, name string) {
 	if m.related != nil && m.related.Owner != nil && m.related.Owner.WorkloadKind != "" {
@@ -113,9 +171,20 @@ func (m *ManifestPanel) SetSize(width, height int) {
 		m.ready = true
 	} else {
 		m.viewport.Width = width
-		m.viewport.Height = m.height
 	}
+	m.resizeViewport()
+}
 
+// resizeViewport fits the viewport below the title and the crash banner.
+func (m *ManifestPanel) resizeViewport() {
+	if !m.ready {
+		return
+	}
+	height := m.height
+	if m.crash != nil {
+		height--
+	}
+	m.viewport.Height = max(height, 1)
 	m.updateContent()
 }
 
@@ -128,8 +197,24 @@ func (m *ManifestPanel) updateContent() {
 
 	switch m.viewMode {
 	case ManifestViewSummary:
-		// Summary: Basic pod info and debug hints
+		// Summary: Basic pod info, image and pull secrets, and debug hints
 		content.WriteString(m.renderPodInfo())
+		content.WriteString("\n")
+		content.WriteString(m.renderImage())
+		// Only worth the space once a container was killed or is close to it
+		if m.oom.OOMKilled() || m.oom.NearLimit() {
+			content.WriteString("\n")
+			content.WriteString(m.renderOOM())
+		}
+		if len(m.probes) > 0 {
+			content.WriteString("\n")
+			content.WriteString(m.renderProbes())
+		}
+		// Only when a container can't run for it or admission would reject the pod
+		if m.security.HasIssues() {
+			content.WriteString("\n")
+			content.WriteString(m.renderSecurity())
+		}
 		if len(m.helpers) > 0 {
 			content.WriteString("\n")
 			content.WriteString(m.renderHelpers())
@@ -148,6 +233,9 @@ func (m *ManifestPanel) updateContent() {
 	case ManifestViewResources:
 		// Resources: Container resources and related resources
 		content.WriteString(m.renderContainerResources())
+		if m.oom != nil {
+			content.WriteString(m.renderOOM())
+		}
 		if m.related != nil {
 			content.WriteString("\n")
 			content.WriteString(m.renderRelated())
@@ -174,12 +262,30 @@ func (m ManifestPanel) renderPodInfo() string {
 		replicaStr := fmt.Sprintf("%d/%d", m.related.Owner.ReadyReplicas, m.related.Owner.Replicas)
 		scaleHint := style.StatusMuted.Render(" 🔼 🔽")
 		b.WriteString(fmt.Sprintf("  %-12s %s%s\n", "Replicas:", style.StatusRunning.Render(replicaStr), scaleHint))
+		if m.related.Owner.Rollout != nil {
+			b.WriteString(renderRolloutStatus(m.related.Owner.Rollout))
+		}
+	}
+	if m.related != nil && m.related.PDB != nil {
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "PDB:", renderPDBStatus(m.related.PDB)))
+	}
+	if m.related != nil && m.related.HPA != nil {
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "HPA:", renderHPAStatus(m.related.HPA)))
 	}
 
 	statusStyle := style.GetStatusStyle(m.pod.Status)
 	b.WriteString(fmt.Sprintf("  %-12s %s\n", "Status:", statusStyle.Render(m.pod.Status)))
 	b.WriteString(fmt.Sprintf("  %-12s %s\n", "Ready:", m.pod.Ready))
 	b.WriteString(fmt.Sprintf("  %-12s %d\n", "Restarts:", m.pod.Restarts))
+	for _, c := range m.pod.Containers {
+		if at, ok := repository.NextRestart(c); ok {
+			eta := repository.FormatRestartETA(at, time.Now())
+			if len(m.pod.Containers) > 1 {
+				eta = c.Name + ": " + eta
+			}
+			b.WriteString(fmt.Sprintf("  %-12s %s%s\n", "Backoff:", style.EventWarning.Render(eta), style.StatusMuted.Render(" (estimate)")))
+		}
+	}
 	b.WriteString(fmt.Sprintf("  %-12s %s\n", "Age:", m.pod.Age))
 
 	nodeValue := m.pod.Node
@@ -206,9 +312,207 @@ func (m ManifestPanel) renderPodInfo() string {
 		b.WriteString(fmt.Sprintf("  %-12s %s/%s\n", "Owner:", m.pod.OwnerKind, m.pod.OwnerRef))
 	}
 
+	// An unbound claim is the usual reason for a Pending pod
+	for _, pvc := range BlockingPVCs(m.pod, m.related) {
+		b.WriteString(fmt.Sprintf("  %-12s %s %s\n", "PVC:", pvc.Name, PVCPhase(pvc)))
+	}
+
 	return b.String()
 }
 
+// renderImage shows the image of each container split into registry,
+// repository and tag, with the pull policy and the pull secrets of the pod.
+// Missing pull secrets, or ones of the wrong type, are flagged in red as
+// they make pulls from private registries fail.
+func (m ManifestPanel) renderImage() string {
+	var b strings.Builder
+	b.WriteString(style.SubtitleStyle.Render("Image\n"))
+	b.WriteString("\n")
+	for _, c := range m.pod.Containers {
+		ref := repository.ParseImageReference(c.Image)
+		if len(m.pod.Containers) > 1 {
+			b.WriteString(fmt.Sprintf("  %s\n", style.LogContainer.Render(c.Name)))
+		}
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Reference:", style.Truncate(c.Image, m.width-16)))
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Registry:", ref.Registry))
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Repository:", style.Truncate(ref.Repository, m.width-16)))
+		if ref.Tag != "" {
+			b.WriteString(fmt.Sprintf("  %-12s %s\n", "Tag:", ref.Tag))
+		}
+		if ref.Digest != "" {
+			b.WriteString(fmt.Sprintf("  %-12s %s\n", "Digest:", style.Truncate(ref.Digest, m.width-16)))
+		}
+		if c.ImagePullPolicy != "" {
+			b.WriteString(fmt.Sprintf("  %-12s %s\n", "Pull Policy:", c.ImagePullPolicy))
+		}
+	}
+
+	if len(m.pod.PullSecrets) == 0 {
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Pull Secret:", style.StatusMuted.Render("none")))
+		return b.String()
+	}
+	for _, s := range m.pod.PullSecrets {
+		name := s.Name
+		if s.FromServiceAccount {
+			name += style.StatusMuted.Render(" (ServiceAccount)")
+		}
+		status := style.StatusRunning.Render("✓")
+		if problem := s.Problem(); problem != "" {
+			status = style.StatusError.Render("✗ " + problem)
+		}
+		b.WriteString(fmt.Sprintf("  %-12s %s %s\n", "Pull Secret:", name, status))
+	}
+	return b.String()
+}
+
+// renderProbes shows each container probe with what it checks, its
+// computed status and its last failure: green when passing, red when
+// failing and muted when unknown.
+func (m ManifestPanel) renderProbes() string {
+	var b strings.Builder
+	b.WriteString(style.SubtitleStyle.Render("Probes\n"))
+	b.WriteString("\n")
+	container := ""
+	for _, p := range m.probes {
+		if p.Container != container && len(m.pod.Containers) > 1 {
+			b.WriteString(fmt.Sprintf("  %s\n", style.LogContainer.Render(p.Container)))
+		}
+		container = p.Container
+
+		state := style.StatusMuted.Render("? " + string(p.State))
+		switch p.State {
+		case repository.ProbePassing:
+			state = style.StatusRunning.Render("✓ " + string(p.State))
+		case repository.ProbeFailing:
+			state = style.StatusError.Render("✗ " + string(p.State))
+		}
+		b.WriteString(fmt.Sprintf("  %-12s %s %s\n", p.Probe+":", state, style.Truncate(p.Target(), max(m.width-30, 10))))
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "", style.StatusMuted.Render(p.Timing())))
+		if p.Failures > 0 {
+			failure := fmt.Sprintf("%dx %s", p.Failures, p.LastFailure)
+			b.WriteString(fmt.Sprintf("  %-12s %s\n", "", style.EventWarning.Render(style.Truncate(failure, max(m.width-16, 10)))))
+		}
+	}
+	return b.String()
+}
+
+// renderSecurity shows the security settings keeping each container from
+// running, with their fix, and the Pod Security checks of the level the
+// namespace enforces that the pod fails: admission would reject it when it
+// is recreated. Resource Details has the full security context.
+func (m ManifestPanel) renderSecurity() string {
+	var b strings.Builder
+	b.WriteString(style.SubtitleStyle.Render("Security\n"))
+	b.WriteString("\n")
+	for _, c := range m.security.Containers {
+		for _, conflict := range c.Conflicts {
+			problem, fix, _ := strings.Cut(conflict, ": ")
+			b.WriteString(fmt.Sprintf("  %-12s %s\n", c.Container+":", style.StatusError.Render(style.Truncate("✗ "+problem, max(m.width-16, 10)))))
+			if fix != "" {
+				b.WriteString(fmt.Sprintf("  %-12s %s\n", "", style.StatusMuted.Render(style.Truncate(fix, max(m.width-16, 10)))))
+			}
+		}
+	}
+	if len(m.security.Violations) > 0 {
+		enforced := fmt.Sprintf("violates %s, a new pod would be rejected", m.security.Admission.Enforce)
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Admission:", style.StatusError.Render(style.Truncate(enforced, max(m.width-16, 10)))))
+		for _, v := range m.security.Violations {
+			b.WriteString(fmt.Sprintf("  %-12s %s\n", "", style.EventWarning.Render(style.Truncate(v.String(), max(m.width-16, 10)))))
+		}
+	}
+	return b.String()
+}
+
+// renderOOM shows for each container its restarts, its last OOM kill and
+// its memory usage against its limit, red above
+// repository.MemoryHeadroomWarnPercent, followed by the OOM kills of the
+// pod's node.
+func (m ManifestPanel) renderOOM() string {
+	var b strings.Builder
+	b.WriteString(style.SubtitleStyle.Render("OOM\n"))
+	b.WriteString("\n")
+	for _, c := range m.oom.Containers {
+		if len(m.oom.Containers) > 1 {
+			b.WriteString(fmt.Sprintf("  %s\n", style.LogContainer.Render(c.Name)))
+		}
+		b.WriteString(fmt.Sprintf("  %-12s %d\n", "Restarts:", c.Restarts))
+
+		lastOOM := style.StatusMuted.Render("none on record")
+		if c.LastOOM != nil {
+			lastOOM = style.StatusError.Render(fmt.Sprintf("OOMKilled (exit %d)", c.LastOOM.ExitCode))
+			if c.LastOOM.FinishedAt != "" {
+				lastOOM += style.StatusMuted.Render(" at " + c.LastOOM.FinishedAt)
+			}
+		}
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Last OOM:", lastOOM))
+
+		var memory string
+		switch {
+		case !c.HasUsage && c.Limit == "":
+			memory = style.StatusMuted.Render("no usage, no limit")
+		case !c.HasUsage:
+			memory = style.StatusMuted.Render("no usage") + " / " + c.Limit
+		case c.Limit == "":
+			memory = c.Usage + style.StatusMuted.Render(" (no limit)")
+		case c.NearLimit():
+			memory = style.StatusError.Render(fmt.Sprintf("● %s / %s (%.0f%%)", c.Usage, c.Limit, c.PercentOfLimit()))
+		default:
+			memory = fmt.Sprintf("%s / %s", c.Usage, c.Limit) + style.StatusMuted.Render(fmt.Sprintf(" (%.0f%%)", c.PercentOfLimit()))
+		}
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Memory:", memory))
+	}
+
+	for i, e := range m.oom.NodeEvents {
+		// The latest few are enough to tell the node is short of memory
+		if i == 3 {
+			b.WriteString(fmt.Sprintf("  %-12s %s\n", "", style.StatusMuted.Render(fmt.Sprintf("+%d more", len(m.oom.NodeEvents)-i))))
+			break
+		}
+		label := ""
+		if i == 0 {
+			label = "Node OOM:"
+		}
+		event := fmt.Sprintf("%s %s", e.Age, e.Message)
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", label, style.EventWarning.Render(style.Truncate(event, max(m.width-16, 10)))))
+	}
+	return b.String()
+}
+
+// renderRolloutStatus shows the canary step, weight and pause state of the
+// pod's Argo Rollout, to choose between promoting and aborting it.
+func renderRolloutStatus(r *repository.RolloutStatus) string {
+	var b strings.Builder
+	if r.Canary {
+		step := fmt.Sprintf("%d/%d", r.CurrentStepIndex, r.Steps)
+		b.WriteString(fmt.Sprintf("  %-12s %s %s\n", "Step:", step, style.StatusMuted.Render(fmt.Sprintf("(canary %d%%)", r.CanaryWeight))))
+	}
+	switch {
+	case r.Aborted:
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Rollout:", style.StatusError.Render("Aborted")))
+	case r.Paused:
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Rollout:", style.StatusPending.Render("Paused: "+r.PauseReason)))
+	case r.Phase != "":
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Rollout:", style.GetStatusStyle(r.Phase).Render(r.Phase)))
+	}
+	return b.String()
+}
+
+// renderPDBStatus shows a PodDisruptionBudget and the disruptions it
+// allows, in red when it allows none since that blocks drains and evictions.
+func renderPDBStatus(p *repository.PDBInfo) string {
+	allowed := fmt.Sprintf("%d disruptions allowed", p.DisruptionsAllowed)
+	if p.Blocking() {
+		return p.Name + " " + style.StatusError.Render("("+allowed+")")
+	}
+	return p.Name + " " + style.StatusMuted.Render("("+allowed+")")
+}
+
+// renderHPAStatus shows the autoscaler of the pod's workload with its
+// replica range and current/target metrics.
+func renderHPAStatus(h *repository.HPAInfo) string {
+	return h.Name + " " + style.StatusMuted.Render(fmt.Sprintf("(%d replicas, %d-%d, %s)", h.Replicas, h.MinReplicas, h.MaxReplicas, h.Targets))
+}
+
 func (m ManifestPanel) renderHelpers() string {
 	var b strings.Builder
 
@@ -260,6 +564,30 @@ func (m ManifestPanel) renderContainers() string {
 	return b.String()
 }
 
+// renderPodEndpoint renders whether the pod gets a Service's traffic,
+// nothing when that is unknown.
+func renderPodEndpoint(state repository.PodEndpointState) string {
+	switch state {
+	case "":
+		return ""
+	case repository.PodEndpointServing:
+		return " " + style.StatusRunning.Render(string(state))
+	default:
+		return " " + style.StatusError.Render(string(state))
+	}
+}
+
+// servingAll reports whether the pod is a ready endpoint of every Service
+// whose endpoints are known.
+func servingAll(services []repository.ServiceInfo) bool {
+	for _, svc := range services {
+		if svc.PodEndpoint != "" && svc.PodEndpoint != repository.PodEndpointServing {
+			return false
+		}
+	}
+	return true
+}
+
 func (m ManifestPanel) renderRelated() string {
 	var b strings.Builder
 
@@ -268,8 +596,13 @@ func (m ManifestPanel) renderRelated() string {
 	if len(m.related.Services) > 0 {
 		b.WriteString("  Services:\n")
 		for _, svc := range m.related.Services {
-			b.WriteString(fmt.Sprintf("    • %s (%s) - %s [%d endpoints]\n",
-				svc.Name, svc.Type, svc.Ports, svc.Endpoints))
+			b.WriteString(fmt.Sprintf("    • %s (%s) - %s [%d endpoints]%s\n",
+				svc.Name, svc.Type, svc.Ports, svc.Endpoints, renderPodEndpoint(svc.PodEndpoint)))
+		}
+		if reasons := repository.NotReadyReasons(m.pod); len(reasons) > 0 && !servingAll(m.related.Services) {
+			for _, reason := range reasons {
+				b.WriteString(style.StatusMuted.Render("      not ready: "+reason) + "\n")
+			}
 		}
 	}
 
@@ -303,9 +636,52 @@ func (m ManifestPanel) renderRelated() string {
 		b.WriteString(fmt.Sprintf("  Secrets: %s\n", strings.Join(m.related.Secrets, ", ")))
 	}
 
+	if len(m.related.PVCs) > 0 {
+		b.WriteString("  PersistentVolumeClaims:\n")
+		for _, pvc := range m.related.PVCs {
+			b.WriteString(fmt.Sprintf("    • %s %s", pvc.Name, PVCPhase(pvc)))
+			if !pvc.Missing && pvc.Error == "" {
+				b.WriteString(fmt.Sprintf(" - %s %s %s", pvc.Capacity, strings.Join(pvc.AccessModes, ","), pvc.StorageClass))
+			}
+			b.WriteString("\n")
+		}
+	}
+
 	return b.String()
 }
 
+// PVCPhase renders a claim's phase, highlighting claims that keep a pod
+// from starting.
+func PVCPhase(pvc repository.PVCInfo) string {
+	switch {
+	case pvc.Missing:
+		return style.StatusError.Render("[Missing]")
+	case pvc.Error != "":
+		return style.StatusMuted.Render("[Unknown]")
+	case pvc.Phase == "Lost":
+		return style.StatusError.Render("[Lost]")
+	case pvc.BlocksScheduling():
+		return style.EventWarning.Render("[" + pvc.Phase + "]")
+	default:
+		return style.StatusRunning.Render("[" + pvc.Phase + "]")
+	}
+}
+
+// BlockingPVCs returns the pod's claims that keep it Pending, or nil when
+// the pod is not Pending.
+func BlockingPVCs(pod *repository.PodInfo, related *repository.RelatedResources) []repository.PVCInfo {
+	if pod == nil || related == nil || pod.Status != "Pending" {
+		return nil
+	}
+	var blocking []repository.PVCInfo
+	for _, pvc := range related.PVCs {
+		if pvc.BlocksScheduling() {
+			blocking = append(blocking, pvc)
+		}
+	}
+	return blocking
+}
+
 func (m ManifestPanel) renderLabels() string {
 	var b strings.Builder
 
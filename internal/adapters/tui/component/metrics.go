@@ -28,6 +28,8 @@ type MetricsPanel struct {
 	leftContentLines []string // Cached content lines for left box
 	rightContentLines []string // Cached content lines for right box
 	focusedBox       int      // 0 = left (Container Resources), 1 = right (Node Info)
+	history          metricsHistory // Recent samples of the pod, drawn as sparklines
+	status           repository.MetricsStatus // Whether metrics-server answers, from the last probe
 }
 
 func NewMetricsPanel() MetricsPanel {
@@ -110,27 +112,45 @@ func (m MetricsPanel) View() string {
 	header.WriteString(style.PanelTitleStyle.Render("Resource Usage"))
 	header.WriteString("\n")
 
-	content := header.String() + m.viewport.View()
-
-	// Add metrics-server hint at bottom right if not available
-	if !m.available {
-		hint := style.StatusMuted.Render("Metrics Server not available")
-		hintLen := 28
-		padding := m.width - hintLen
-		if padding > 0 {
-			content += "\n\n" + strings.Repeat(" ", padding) + hint
-		}
-	}
-
-	return content
+	return header.String() + m.viewport.View()
 }
 
 func (m *MetricsPanel) SetMetrics(metrics *repository.PodMetrics) {
 	m.metrics = metrics
 	m.available = metrics != nil
+	if metrics != nil && m.pod != nil && metrics.Name == m.pod.Name && metrics.Namespace == m.pod.Namespace {
+		m.history.add(metrics)
+	}
+	m.updateContent()
+}
+
+// SetStatus sets whether metrics-server answers, from the last probe.
+func (m *MetricsPanel) SetStatus(status repository.MetricsStatus) {
+	m.status = status
 	m.updateContent()
 }
 
+// MetricsServerMissing reports whether the cluster has no metrics-server.
+func (m MetricsPanel) MetricsServerMissing() bool {
+	return m.status.State == repository.MetricsNotInstalled
+}
+
+// statusMessage explains why the pod has no usage yet.
+func (m MetricsPanel) statusMessage() string {
+	switch m.status.State {
+	case repository.MetricsNotInstalled:
+		return style.StatusError.Render("metrics-server not detected in this cluster") + "\n" +
+			style.StatusMuted.Render("y: copy install command")
+	case repository.MetricsFailing:
+		msg := "metrics API unavailable"
+		if m.status.Err != nil {
+			msg += ": " + m.status.Err.Error()
+		}
+		return style.StatusPending.Render(msg)
+	}
+	return style.StatusMuted.Render("Waiting for metrics...")
+}
+
 func (m *MetricsPanel) SetPod(pod *repository.PodInfo) {
 	// Only reset scroll/focus if pod actually changed
 	podChanged := m.pod == nil || pod == nil ||
@@ -142,6 +162,7 @@ func (m *MetricsPanel) SetPod(pod *repository.PodInfo) {
 		m.leftScrollOffset = 0
 		m.rightScrollOffset = 0
 		m.focusedBox = 0
+		m.history.evict()
 	}
 	m.updateContent()
 }
@@ -176,6 +197,13 @@ func (m *MetricsPanel) updateContent() {
 		return
 	}
 
+	// Sparklines fill what is left of the left column after the usage value
+	colWidth := m.width
+	if m.node != nil || m.pod.Node != "" {
+		colWidth = (m.width-3)/2 - 2
+	}
+	sparkWidth := min(colWidth-26, metricsHistorySize)
+
 	// Build left column (container resources)
 	var leftCol strings.Builder
 	for _, c := range m.pod.Containers {
@@ -192,8 +220,13 @@ func (m *MetricsPanel) updateContent() {
 		if m.metrics != nil {
 			for _, cm := range m.metrics.Containers {
 				if cm.Name == c.Name {
-					leftCol.WriteString(fmt.Sprintf("  %-14s %s\n", "CPU Usage:", style.StatusRunning.Render(cm.CPUUsage)))
-					leftCol.WriteString(fmt.Sprintf("  %-14s %s\n", "Mem Usage:", style.StatusRunning.Render(cm.MemoryUsage)))
+					cpuSpark, memSpark := "", ""
+					if sparkWidth >= 8 {
+						cpuSpark = Sparkline(m.history.cpu(c.Name), sparkWidth, float64(repository.CPUMillis(c.Resources.CPULimit)))
+						memSpark = Sparkline(m.history.memory(c.Name), sparkWidth, float64(repository.MemoryBytes(c.Resources.MemoryLimit)))
+					}
+					leftCol.WriteString(fmt.Sprintf("  %-14s %s %s\n", "CPU Usage:", style.StatusRunning.Render(fmt.Sprintf("%-8s", cm.CPUUsage)), style.LogContainer.Render(cpuSpark)))
+					leftCol.WriteString(fmt.Sprintf("  %-14s %s %s\n", "Mem Usage:", style.StatusRunning.Render(fmt.Sprintf("%-8s", cm.MemoryUsage)), style.LogContainer.Render(memSpark)))
 					break
 				}
 			}
@@ -201,8 +234,8 @@ func (m *MetricsPanel) updateContent() {
 		leftCol.WriteString("\n")
 	}
 
-	if m.metrics == nil && m.available {
-		leftCol.WriteString(style.StatusMuted.Render("Waiting for metrics..."))
+	if m.metrics == nil {
+		leftCol.WriteString(m.statusMessage())
 	}
 
 	// Build right column (node info) - without title, we add it later
@@ -238,6 +271,36 @@ func (m *MetricsPanel) updateContent() {
 		if m.node.Memory != "" {
 			rightCol.WriteString(fmt.Sprintf("%-12s %s\n", "Memory:", m.node.Memory))
 		}
+
+		// Requests committed to the node against what it can allocate
+		if m.node.RequestedCPU != "" {
+			rightCol.WriteString(fmt.Sprintf("%-12s %s / %s\n", "CPU Req:", m.node.RequestedCPU, m.node.AllocatableCPU))
+		}
+		if m.node.RequestedMemory != "" {
+			rightCol.WriteString(fmt.Sprintf("%-12s %s / %s\n", "Mem Req:", m.node.RequestedMemory, m.node.AllocatableMemory))
+		}
+
+		if m.node.Unschedulable {
+			rightCol.WriteString(fmt.Sprintf("%-12s %s\n", "Schedule:", style.EventWarning.Render("cordoned")))
+		}
+		if pressures := m.node.Pressures(); len(pressures) > 0 {
+			for i, p := range pressures {
+				label := ""
+				if i == 0 {
+					label = "Pressure:"
+				}
+				rightCol.WriteString(fmt.Sprintf("%-12s %s\n", label, style.StatusError.Render(p)))
+			}
+		} else {
+			rightCol.WriteString(fmt.Sprintf("%-12s %s\n", "Pressure:", style.StatusMuted.Render("none")))
+		}
+		for i, t := range m.node.Taints {
+			label := ""
+			if i == 0 {
+				label = "Taints:"
+			}
+			rightCol.WriteString(fmt.Sprintf("%-12s %s\n", label, style.EventWarning.Render(truncate(t.String(), maxValueWidth))))
+		}
 	} else if m.pod != nil && m.pod.Node != "" {
 		rightCol.WriteString(fmt.Sprintf("%s\n", truncate(m.pod.Node, maxValueWidth+12)))
 	}
@@ -245,7 +308,7 @@ func (m *MetricsPanel) updateContent() {
 	// Combine columns side by side if we have node info
 	if rightCol.Len() > 0 {
 		// Calculate column widths
-		colWidth := (m.width - 3) / 2 // -3 for separator and spacing
+		colWidth = (m.width - 3) / 2 // -3 for separator and spacing
 		visibleLines := m.height - 5
 
 		// Cache content lines for both columns
@@ -291,6 +354,9 @@ func (m *MetricsPanel) updateContent() {
 			rightTitle += " ▲"
 		}
 		rightContent.WriteString(rightTitleStyle.Render(rightTitle))
+		if m.focusedBox == 1 && m.node != nil {
+			rightContent.WriteString(style.StatusMuted.Render("  a:actions"))
+		}
 		rightContent.WriteString("\n\n")
 
 		rightStartLine := m.rightScrollOffset
@@ -345,6 +411,14 @@ func formatResourceValue(v string) string {
 	return v
 }
 
+// SelectedNode returns the node when the Node Info box is focused, nil otherwise.
+func (m MetricsPanel) SelectedNode() *repository.NodeInfo {
+	if m.focusedBox != 1 {
+		return nil
+	}
+	return m.node
+}
+
 func (m MetricsPanel) IsAvailable() bool {
 	return m.available
 }
@@ -0,0 +1,112 @@
+package component
+
+import (
+	"strings"
+	"time"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// metricsHistorySize is the number of samples kept per container, about
+// 15 minutes at metrics-server's default 15s resolution.
+const metricsHistorySize = 60
+
+// sparkBlocks are the bar heights of a sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// metricsSample is one metrics-server reading of a container.
+type metricsSample struct {
+	cpuMilli int64
+	memBytes int64
+}
+
+// metricsHistory keeps a rolling window of metrics-server samples for the
+// containers of one pod, keyed by "namespace/pod/container". Samples of
+// another pod evict the window.
+type metricsHistory struct {
+	pod     string    // "namespace/pod" the samples belong to
+	last    time.Time // Timestamp of the newest sample
+	samples map[string][]metricsSample
+}
+
+// add appends a sample for each container. A reading metrics-server already
+// returned on an earlier tick is skipped, so the window holds distinct
+// samples rather than one per refresh.
+func (h *metricsHistory) add(metrics *repository.PodMetrics) {
+	pod := metrics.Namespace + "/" + metrics.Name
+	if pod != h.pod {
+		h.evict()
+		h.pod = pod
+	}
+	if !metrics.Timestamp.IsZero() {
+		if !metrics.Timestamp.After(h.last) {
+			return
+		}
+		h.last = metrics.Timestamp
+	}
+	if h.samples == nil {
+		h.samples = make(map[string][]metricsSample)
+	}
+	for _, c := range metrics.Containers {
+		key := pod + "/" + c.Name
+		window := append(h.samples[key], metricsSample{cpuMilli: c.CPUMilli, memBytes: c.MemoryBytes})
+		if len(window) > metricsHistorySize {
+			window = window[len(window)-metricsHistorySize:]
+		}
+		h.samples[key] = window
+	}
+}
+
+// evict drops all samples.
+func (h *metricsHistory) evict() {
+	h.pod = ""
+	h.last = time.Time{}
+	h.samples = nil
+}
+
+// cpu returns the CPU samples of a container in millicores, oldest first.
+func (h metricsHistory) cpu(container string) []float64 {
+	window := h.samples[h.pod+"/"+container]
+	values := make([]float64, len(window))
+	for i, s := range window {
+		values[i] = float64(s.cpuMilli)
+	}
+	return values
+}
+
+// memory returns the memory samples of a container in bytes, oldest first.
+func (h metricsHistory) memory(container string) []float64 {
+	window := h.samples[h.pod+"/"+container]
+	values := make([]float64, len(window))
+	for i, s := range window {
+		values[i] = float64(s.memBytes)
+	}
+	return values
+}
+
+// Sparkline renders the last width values as a one-line bar chart. Bars
+// are scaled to ceiling, or to the largest value when ceiling is 0 or
+// smaller, so usage near a limit shows as full bars. Returns "" for fewer
+// than two values.
+func Sparkline(values []float64, width int, ceiling float64) string {
+	if len(values) < 2 || width < 1 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	for _, v := range values {
+		ceiling = max(ceiling, v)
+	}
+
+	var b strings.Builder
+	top := len(sparkBlocks) - 1
+	for _, v := range values {
+		level := 0
+		if ceiling > 0 {
+			level = min(int(v/ceiling*float64(top)+0.5), top)
+		}
+		b.WriteRune(sparkBlocks[max(level, 0)])
+	}
+	return b.String()
+}
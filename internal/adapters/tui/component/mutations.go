@@ -0,0 +1,166 @@
+package component
+
+import (
+	"fmt"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// Mutation actions shown optimistically before the cluster confirms them.
+const (
+	MutationDelete = "delete"
+	MutationScale  = "scale"
+)
+
+// MutationHint identifies the object a mutating action changed, so only the
+// affected data needs to be re-fetched.
+type MutationHint struct {
+	Kind      repository.ResourceType // ResourcePods or a workload type
+	Namespace string
+	Name      string
+}
+
+// PendingMutation is an optimistic change shown until a refresh confirms or
+// reverts it.
+type PendingMutation struct {
+	Hint     MutationHint
+	Action   string // MutationDelete or MutationScale
+	Replicas int32  // Target replica count (scale only)
+	settled  bool   // The API call succeeded; the next refresh decides
+}
+
+// MutationOutcome is the verdict on a settled mutation after a refresh.
+type MutationOutcome struct {
+	Mutation PendingMutation
+	Applied  bool  // False when the refresh shows the change did not take
+	Observed int32 // Desired replicas seen by the refresh (scale only)
+}
+
+// Message describes a reverted mutation for the status bar.
+func (o MutationOutcome) Message() string {
+	h := o.Mutation.Hint
+	if o.Mutation.Action == MutationScale {
+		return fmt.Sprintf("%s was not scaled to %d (still %d replicas)", h.Name, o.Mutation.Replicas, o.Observed)
+	}
+	return fmt.Sprintf("Pod %s was not deleted", h.Name)
+}
+
+// Mutations tracks optimistic updates. A mutation starts when the action is
+// requested, is settled when the API call returns, and is resolved by the
+// first refresh after that. Failed API calls revert immediately. A nil
+// *Mutations tracks nothing.
+type Mutations struct {
+	pending map[MutationHint]*PendingMutation
+}
+
+// NewMutations creates an empty tracker.
+func NewMutations() *Mutations {
+	return &Mutations{pending: make(map[MutationHint]*PendingMutation)}
+}
+
+// Begin records an optimistic change. It replaces any pending change to
+// the same object.
+func (m *Mutations) Begin(p PendingMutation) {
+	if m == nil {
+		return
+	}
+	p.settled = false
+	m.pending[p.Hint] = &p
+}
+
+// Settle marks the API call for hint as finished. On error the optimistic
+// change is reverted right away and Settle returns true.
+func (m *Mutations) Settle(hint MutationHint, err error) bool {
+	if m == nil {
+		return false
+	}
+	p, ok := m.pending[hint]
+	if !ok {
+		return false
+	}
+	if err != nil {
+		delete(m.pending, hint)
+		return true
+	}
+	p.settled = true
+	return false
+}
+
+// Forget drops the pending change for hint without a verdict, e.g. when
+// the refresh that would decide it failed.
+func (m *Mutations) Forget(hint MutationHint) {
+	if m == nil {
+		return
+	}
+	delete(m.pending, hint)
+}
+
+// Deleting reports whether the pod is being deleted optimistically.
+func (m *Mutations) Deleting(namespace, name string) bool {
+	if m == nil {
+		return false
+	}
+	p, ok := m.pending[MutationHint{Kind: repository.ResourcePods, Namespace: namespace, Name: name}]
+	return ok && p.Action == MutationDelete
+}
+
+// ScaleTarget returns the replica count a workload is being scaled to.
+func (m *Mutations) ScaleTarget(w repository.WorkloadInfo) (int32, bool) {
+	if m == nil {
+		return 0, false
+	}
+	p, ok := m.pending[MutationHint{Kind: w.Type, Namespace: w.Namespace, Name: w.Name}]
+	if !ok || p.Action != MutationScale {
+		return 0, false
+	}
+	return p.Replicas, true
+}
+
+// ReconcilePods resolves settled pod deletions against a fresh pod list
+// covering namespace, or every namespace when it is empty (node views).
+// A pod that is gone or Terminating was deleted; one that is still there
+// otherwise was not. Resolved mutations are removed.
+func (m *Mutations) ReconcilePods(namespace string, pods []repository.PodInfo) []MutationOutcome {
+	if m == nil {
+		return nil
+	}
+	status := make(map[MutationHint]string, len(pods))
+	for _, p := range pods {
+		status[MutationHint{Kind: repository.ResourcePods, Namespace: p.Namespace, Name: p.Name}] = p.Status
+	}
+
+	var outcomes []MutationOutcome
+	for hint, p := range m.pending {
+		if !p.settled || p.Action != MutationDelete || hint.Kind != repository.ResourcePods {
+			continue
+		}
+		if namespace != "" && hint.Namespace != namespace {
+			continue
+		}
+		st, present := status[hint]
+		outcomes = append(outcomes, MutationOutcome{
+			Mutation: *p,
+			Applied:  !present || st == "Terminating",
+		})
+		delete(m.pending, hint)
+	}
+	return outcomes
+}
+
+// ReconcileReplicas resolves a settled scale against the workload's desired
+// replica count. It returns false if no settled scale is pending for hint.
+func (m *Mutations) ReconcileReplicas(hint MutationHint, desired int32) (MutationOutcome, bool) {
+	if m == nil {
+		return MutationOutcome{}, false
+	}
+	p, ok := m.pending[hint]
+	if !ok || !p.settled || p.Action != MutationScale {
+		return MutationOutcome{}, false
+	}
+	delete(m.pending, hint)
+	return MutationOutcome{
+		Mutation: *p,
+		Applied:  desired == p.Replicas,
+		Observed: desired,
+	}, true
+}
@@ -0,0 +1,379 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// namespacePickerRows is how many namespaces the picker shows at once.
+const namespacePickerRows = 15
+
+// NamespaceCompare picks a second namespace and shows how its Deployments,
+// ConfigMaps and Secrets differ from the first one, side by side.
+type NamespaceCompare struct {
+	visible    bool
+	picking    bool // Choosing the namespace to compare with
+	loading    bool
+	base       string
+	namespaces []string
+	query      string
+	cursor     int
+	pickScroll int
+	diff       *repository.NamespaceDiff
+	err        error
+	showAll    bool // Also list identical objects
+	scroll     int
+	lines      []string
+	width      int
+	height     int
+}
+
+// NamespaceCompareRequest is sent when a namespace was picked, asking
+// app.go to load and compare both namespaces.
+type NamespaceCompareRequest struct {
+	Left  string
+	Right string
+}
+
+// NamespaceCompareClosed is sent when the comparison is closed.
+type NamespaceCompareClosed struct{}
+
+func NewNamespaceCompare() NamespaceCompare {
+	return NamespaceCompare{}
+}
+
+// ShowPicker opens the namespace picker to compare base with another
+// namespace.
+func (c *NamespaceCompare) ShowPicker(base string, namespaces []string) {
+	*c = NamespaceCompare{width: c.width, height: c.height}
+	c.visible = true
+	c.picking = true
+	c.base = base
+	for _, ns := range namespaces {
+		if ns != base {
+			c.namespaces = append(c.namespaces, ns)
+		}
+	}
+}
+
+// SetDiff shows the result of a comparison, or why it failed.
+func (c *NamespaceCompare) SetDiff(diff *repository.NamespaceDiff, err error) {
+	c.loading = false
+	c.diff = diff
+	c.err = err
+	c.scroll = 0
+	c.buildLines()
+}
+
+func (c *NamespaceCompare) Hide() {
+	c.visible = false
+}
+
+func (c NamespaceCompare) IsVisible() bool {
+	return c.visible
+}
+
+func (c *NamespaceCompare) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+	c.buildLines()
+}
+
+func (c NamespaceCompare) Update(msg tea.Msg) (NamespaceCompare, tea.Cmd) {
+	if !c.visible {
+		return c, nil
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return c, nil
+	}
+	if c.picking {
+		return c.updatePicker(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		c.visible = false
+		return c, func() tea.Msg { return NamespaceCompareClosed{} }
+	case "a":
+		c.showAll = !c.showAll
+		c.scroll = 0
+		c.buildLines()
+	case "up", "k":
+		if c.scroll > 0 {
+			c.scroll--
+		}
+	case "down", "j":
+		if c.scroll < c.maxScroll() {
+			c.scroll++
+		}
+	case "pgup", "ctrl+u":
+		c.scroll -= 10
+		if c.scroll < 0 {
+			c.scroll = 0
+		}
+	case "pgdown", "ctrl+d":
+		c.scroll += 10
+		if c.scroll > c.maxScroll() {
+			c.scroll = c.maxScroll()
+		}
+	case "g", "home":
+		c.scroll = 0
+	case "G", "end":
+		c.scroll = c.maxScroll()
+	}
+	return c, nil
+}
+
+func (c NamespaceCompare) updatePicker(msg tea.KeyMsg) (NamespaceCompare, tea.Cmd) {
+	filtered := c.filteredNamespaces()
+	switch msg.String() {
+	case "esc":
+		c.visible = false
+		return c, func() tea.Msg { return NamespaceCompareClosed{} }
+	case "up":
+		if c.cursor > 0 {
+			c.cursor--
+		}
+	case "down":
+		if c.cursor < len(filtered)-1 {
+			c.cursor++
+		}
+	case "enter":
+		if c.cursor >= 0 && c.cursor < len(filtered) {
+			req := NamespaceCompareRequest{Left: c.base, Right: filtered[c.cursor]}
+			c.picking = false
+			c.loading = true
+			c.query = ""
+			return c, func() tea.Msg { return req }
+		}
+	case "backspace":
+		if len(c.query) > 0 {
+			c.query = c.query[:len(c.query)-1]
+			c.cursor = 0
+		}
+	default:
+		// Type to filter
+		k := msg.String()
+		if len(k) == 1 && k >= " " && k <= "~" {
+			c.query += k
+			c.cursor = 0
+		}
+	}
+	if c.cursor < c.pickScroll {
+		c.pickScroll = c.cursor
+	} else if c.cursor >= c.pickScroll+namespacePickerRows {
+		c.pickScroll = c.cursor - namespacePickerRows + 1
+	}
+	return c, nil
+}
+
+func (c NamespaceCompare) filteredNamespaces() []string {
+	if c.query == "" {
+		return c.namespaces
+	}
+	var filtered []string
+	query := strings.ToLower(c.query)
+	for _, ns := range c.namespaces {
+		if strings.Contains(strings.ToLower(ns), query) {
+			filtered = append(filtered, ns)
+		}
+	}
+	return filtered
+}
+
+func (c NamespaceCompare) maxVisibleLines() int {
+	maxLines := c.height - 10
+	if maxLines < 5 {
+		maxLines = 5
+	}
+	return maxLines
+}
+
+func (c NamespaceCompare) maxScroll() int {
+	if n := len(c.lines) - c.maxVisibleLines(); n > 0 {
+		return n
+	}
+	return 0
+}
+
+// columnWidth is the width of each namespace column.
+func (c NamespaceCompare) columnWidth() int {
+	w := (c.width - 36) / 2
+	if w < 16 {
+		w = 16
+	}
+	return w
+}
+
+// row lays out a label and the values of both namespaces in columns.
+func (c NamespaceCompare) row(label, left, right string, leftStyle, rightStyle lipgloss.Style) string {
+	w := c.columnWidth()
+	return fmt.Sprintf("  %-16s ", style.Truncate(label, 16)) +
+		leftStyle.Render(style.PadRight(left, w)) + style.StatusMuted.Render(" │ ") +
+		rightStyle.Render(style.Truncate(right, w))
+}
+
+func (c *NamespaceCompare) buildLines() {
+	c.lines = nil
+	if c.diff == nil {
+		return
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Secondary)
+	kindStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Primary)
+
+	differences := c.diff.Differences()
+	c.lines = append(c.lines, c.row("", c.diff.Left, c.diff.Right, headerStyle, headerStyle))
+	c.lines = append(c.lines, "")
+	if len(differences) == 0 {
+		c.lines = append(c.lines, style.StatusRunning.Render("  No differences in Deployments, ConfigMaps or Secret keys"))
+	}
+
+	for _, obj := range c.diff.Objects {
+		if !obj.Differs() && !c.showAll {
+			continue
+		}
+		title := kindStyle.Render(obj.Kind) + " " + obj.Name
+		switch obj.Presence {
+		case repository.LeftOnly:
+			c.lines = append(c.lines, "  "+title)
+			c.lines = append(c.lines, c.row("", "present", "missing", style.StatusRunning, style.StatusError))
+		case repository.RightOnly:
+			c.lines = append(c.lines, "  "+title)
+			c.lines = append(c.lines, c.row("", "missing", "present", style.StatusError, style.StatusRunning))
+		default:
+			if len(obj.Fields) == 0 {
+				c.lines = append(c.lines, "  "+title+style.StatusMuted.Render("  identical"))
+				continue
+			}
+			c.lines = append(c.lines, "  "+title)
+			for _, f := range obj.Fields {
+				c.lines = append(c.lines, c.row(f.Field, f.Left, f.Right, style.StatusPending, style.StatusPending))
+			}
+		}
+	}
+	c.lines = append(c.lines, "")
+	c.lines = append(c.lines, style.StatusMuted.Render(fmt.Sprintf("  %d of %d objects differ; Secrets are compared by key names only",
+		len(differences), len(c.diff.Objects))))
+}
+
+func (c NamespaceCompare) View() string {
+	if !c.visible {
+		return ""
+	}
+	if c.picking {
+		return c.renderPicker()
+	}
+
+	separatorStyle := lipgloss.NewStyle().Foreground(style.TextMuted)
+	itemStyle := lipgloss.NewStyle().Foreground(style.Primary)
+	right := "..."
+	if c.diff != nil {
+		right = c.diff.Right
+	}
+	header := itemStyle.Render(c.base) + separatorStyle.Render(" ⇄ ") + itemStyle.Render(right) +
+		separatorStyle.Render(" - ") + lipgloss.NewStyle().Foreground(style.Secondary).Render("namespace comparison")
+
+	var content strings.Builder
+	maxLines := c.maxVisibleLines()
+	switch {
+	case c.loading:
+		content.WriteString(style.StatusPending.Render("Loading both namespaces..."))
+		content.WriteString("\n")
+	case c.err != nil:
+		content.WriteString(style.StatusError.Render("Comparison failed: " + c.err.Error()))
+		content.WriteString("\n")
+	default:
+		end := c.scroll + maxLines
+		if end > len(c.lines) {
+			end = len(c.lines)
+		}
+		for i := c.scroll; i < end; i++ {
+			content.WriteString(c.lines[i])
+			content.WriteString("\n")
+		}
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Surface).
+		Padding(0, 1).
+		Width(c.width - 10).
+		Height(c.height - 10)
+
+	scrollInfo := ""
+	if len(c.lines) > maxLines {
+		scrollInfo = fmt.Sprintf("[%d/%d] ", c.scroll+1, c.maxScroll()+1)
+	}
+	toggle := "a:show identical"
+	if c.showAll {
+		toggle = "a:differences only"
+	}
+	footer := style.StatusMuted.Render(scrollInfo + "↑↓:scroll  " + toggle + "  Esc:close")
+
+	return header + "\n" + boxStyle.Render(content.String()) + "\n" + footer
+}
+
+func (c NamespaceCompare) renderPicker() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Primary)
+	itemStyle := lipgloss.NewStyle().Foreground(style.Text)
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Text).Background(style.Primary)
+
+	b.WriteString(titleStyle.Render("Compare " + c.base + " with"))
+	b.WriteString("\n")
+	if c.query != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(style.Secondary).Render("Filter: " + c.query))
+	} else {
+		b.WriteString(style.StatusMuted.Render("Type to filter..."))
+	}
+	b.WriteString("\n\n")
+
+	filtered := c.filteredNamespaces()
+	if len(filtered) == 0 {
+		b.WriteString(style.StatusMuted.Render("No namespaces match filter"))
+		b.WriteString("\n")
+	}
+	end := c.pickScroll + namespacePickerRows
+	if end > len(filtered) {
+		end = len(filtered)
+	}
+	for i := c.pickScroll; i < end; i++ {
+		if i == c.cursor {
+			b.WriteString(selectedStyle.Render("> " + filtered[i]))
+		} else {
+			b.WriteString("  " + itemStyle.Render(filtered[i]))
+		}
+		b.WriteString("\n")
+	}
+	if len(filtered) > namespacePickerRows {
+		b.WriteString(style.StatusMuted.Render(fmt.Sprintf("\n[%d/%d]", c.cursor+1, len(filtered))))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(style.StatusMuted.Render("↑↓:select  Enter:compare  Esc:cancel"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Primary).
+		Padding(1, 2).
+		Width(50).
+		MaxHeight(25)
+
+	return lipgloss.Place(
+		c.width,
+		c.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(b.String()),
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(style.Background),
+	)
+}
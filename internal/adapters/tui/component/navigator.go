@@ -3,6 +3,7 @@ package component
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -59,6 +60,24 @@ type Navigator struct {
 	panelActive  bool           // Whether this panel is active (for namespace mode with nodes)
 	// Workload info for scale controls
 	scaleWorkload *repository.WorkloadInfo
+	// Readiness probe health summary for the namespace
+	probeHealth []repository.WorkloadProbeHealth
+	probeFilter bool // Show only pods affected by probe failures
+	// Optimistic deletes and scales awaiting confirmation
+	mutations *Mutations
+	// Location shown above the resources view
+	breadcrumb Breadcrumb
+	// Fields changed since the previous refresh (highlight changes mode)
+	podChanges      *ChangeTracker
+	workloadChanges *ChangeTracker
+	// Further pages of pods or workloads still being fetched
+	podsLoadingMore      bool
+	workloadsLoadingMore bool
+	// Optional columns of the workloads table and how far it is scrolled
+	columns   ColumnSet
+	colOffset int
+	// Pods selected for a bulk action, by name
+	marked map[string]bool
 }
 
 func NewNavigator() Navigator {
@@ -68,9 +87,12 @@ func NewNavigator() Navigator {
 	ti.Width = 30
 
 	return Navigator{
-		resourceType: repository.ResourceDeployments,
-		searchInput:  ti,
-		keys:         keys.DefaultKeyMap(),
+		resourceType:    repository.ResourceDeployments,
+		searchInput:     ti,
+		keys:            keys.DefaultKeyMap(),
+		podChanges:      NewChangeTracker(ChangeHighlightDuration, time.Now),
+		workloadChanges: NewChangeTracker(ChangeHighlightDuration, time.Now),
+		columns:         DefaultColumns(),
 	}
 }
 
@@ -150,12 +172,75 @@ func (n Navigator) Update(msg tea.Msg) (Navigator, tea.Cmd) {
 			return n, textinput.Blink
 		case key.Matches(msg, n.keys.Clear):
 			n.ClearSearch()
+		case key.Matches(msg, n.keys.Mark):
+			if n.mode == ModeResources && n.section == SectionPods {
+				n.toggleMark()
+			}
+		case key.Matches(msg, n.keys.PodActions):
+			if n.mode == ModeResources && n.section == SectionPods {
+				n.markAllVisible()
+			}
+		case key.Matches(msg, n.keys.ProbeHealth):
+			// Drill down into pods affected by probe failures
+			if n.mode == ModeResources && (n.probeFilter || len(n.probeHealth) > 0) {
+				n.probeFilter = !n.probeFilter
+				n.section = SectionPods
+				n.sectionCursors[SectionPods] = 0
+			}
+		case key.Matches(msg, n.keys.ColumnsLeft):
+			if n.mode == ModeWorkloads && n.colOffset > 0 {
+				n.colOffset--
+			}
+		case key.Matches(msg, n.keys.ColumnsRight):
+			if n.mode == ModeWorkloads && n.colOffset < len(n.columns.workloadColumns())-1 {
+				n.colOffset++
+			}
 		}
 	}
 
 	return n, nil
 }
 
+// toggleMark selects the pod under the cursor for a bulk action, or
+// unselects it, and moves to the next pod.
+func (n *Navigator) toggleMark() {
+	pod := n.SelectedPod()
+	if pod == nil {
+		return
+	}
+	if n.marked == nil {
+		n.marked = make(map[string]bool)
+	}
+	if n.marked[pod.Name] {
+		delete(n.marked, pod.Name)
+	} else {
+		n.marked[pod.Name] = true
+	}
+	if n.sectionCursors[SectionPods] < len(n.filteredPods())-1 {
+		n.sectionCursors[SectionPods]++
+	}
+}
+
+// markAllVisible selects every pod the filter shows, or unselects them
+// when they all are already.
+func (n *Navigator) markAllVisible() {
+	pods := n.filteredPods()
+	all := len(pods) > 0
+	for _, p := range pods {
+		all = all && n.marked[p.Name]
+	}
+	if n.marked == nil {
+		n.marked = make(map[string]bool)
+	}
+	for _, p := range pods {
+		if all {
+			delete(n.marked, p.Name)
+		} else {
+			n.marked[p.Name] = true
+		}
+	}
+}
+
 func (n *Navigator) moveUp() {
 	if n.mode == ModeResources {
 		// Move within current section, or jump to previous section
@@ -344,8 +429,8 @@ func (n Navigator) renderHeader() string {
 		icon = "◈"
 		title = strings.ToUpper(string(n.resourceType))
 	case ModeResources:
-		// No header for resources view - sections have their own headers
-		return ""
+		// Sections have their own headers; only the location is shown here
+		return n.breadcrumb.View()
 	case ModeNamespace:
 		icon = "◉"
 		title = "SELECT NAMESPACE"
@@ -375,9 +460,14 @@ func (n Navigator) renderWorkloads() string {
 	}
 
 	var b strings.Builder
+	services := n.resourceType == repository.ResourceServices
 
 	// Header
-	header := fmt.Sprintf("  %-32s %-10s %-15s %-8s", "NAME", "READY", "STATUS", "AGE")
+	layout := layoutWorkloadColumns(n.columns.workloadColumns(), n.colOffset, n.width)
+	header := layout.header()
+	if services {
+		header = fmt.Sprintf("  %-32s %-13s %-16s %-18s %-12s %-8s", "NAME", "TYPE", "CLUSTER-IP", "PORTS", "ENDPOINTS", "AGE")
+	}
 	b.WriteString(style.TableHeaderStyle.Render(header))
 	b.WriteString("\n")
 
@@ -385,39 +475,168 @@ func (n Navigator) renderWorkloads() string {
 	visible := n.visibleRange(len(workloads))
 	for i := visible.start; i < visible.end; i++ {
 		w := workloads[i]
-		b.WriteString(n.renderWorkloadRow(w, i == n.cursor))
+		if services {
+			b.WriteString(n.renderServiceRow(w, i == n.cursor))
+		} else {
+			b.WriteString(n.renderWorkloadRow(w, i == n.cursor, layout))
+		}
 		b.WriteString("\n")
 	}
 
 	// Scroll indicator
 	b.WriteString(n.renderScrollIndicator(visible, len(workloads)))
+	if n.workloadsLoadingMore {
+		b.WriteString("\n")
+		b.WriteString(renderLoadingMore())
+	}
 	return b.String()
 }
 
-func (n Navigator) renderWorkloadRow(w repository.WorkloadInfo, selected bool) string {
+// renderLoadingMore renders the footer shown while further pages of a list
+// are fetched in the background.
+func renderLoadingMore() string {
+	return style.StatusMuted.Render("  loading more…")
+}
+
+// renderServiceRow renders a Service in the services list. Services without
+// a selector show "no selector" rather than a count of ready endpoints,
+// since they have no pods to drill into.
+func (n Navigator) renderServiceRow(w repository.WorkloadInfo, selected bool) string {
+	cursor := "  "
+	if selected {
+		cursor = style.CursorStyle.Render("> ")
+	}
+
+	var svc repository.ServiceInfo
+	if w.Service != nil {
+		svc = *w.Service
+	}
+	clusterIP := svc.ClusterIP
+	if clusterIP == "" {
+		clusterIP = "-"
+	}
+	ports := svc.Ports
+	if ports == "" {
+		ports = "-"
+	}
+
+	endpoints := fmt.Sprintf("%-12s", w.Ready)
+	switch {
+	case svc.NoSelector:
+		endpoints = style.StatusMuted.Render(endpoints)
+	case n.workloadChanges.Changed(workloadChangeID(w), FieldReady):
+		endpoints = style.StatusChanged.Render(endpoints)
+	case svc.Endpoints == 0:
+		endpoints = style.StatusError.Render(endpoints)
+	}
+
+	row := fmt.Sprintf("%s%-32s %-13s %-16s %-18s %s %-8s",
+		cursor, style.Truncate(w.Name, 32), style.Truncate(svc.Type, 13), clusterIP,
+		style.Truncate(ports, 18), endpoints, w.Age)
+	if selected {
+		return lipgloss.NewStyle().Background(style.Surface).Render(row)
+	}
+	return row
+}
+
+// renderWorkloadRow renders the columns of a workload that fit the layout.
+func (n Navigator) renderWorkloadRow(w repository.WorkloadInfo, selected bool, layout workloadLayout) string {
 	cursor := "  "
 	if selected {
 		cursor = style.CursorStyle.Render("> ")
 	}
 
-	name := style.Truncate(w.Name, 32)
 	statusStyle := style.GetStatusStyle(w.Status)
+	status := w.Status
+	if target, ok := n.mutations.ScaleTarget(w); ok {
+		status = fmt.Sprintf("Scaling to %d", target)
+		statusStyle = style.StatusPending
+	}
+
+	// Tint fields that changed in the last refresh
+	id := workloadChangeID(w)
+	readyStyle := lipgloss.NewStyle()
+	if n.workloadChanges.Changed(id, FieldReady) {
+		readyStyle = style.StatusChanged
+	}
+	if n.workloadChanges.Changed(id, FieldStatus) {
+		statusStyle = style.StatusChanged
+	}
+
+	var b strings.Builder
+	b.WriteString(cursor)
+	b.WriteString(fitCell(w.Name, layout.nameWidth))
+	for _, col := range layout.columns {
+		var text string
+		cellStyle := lipgloss.NewStyle()
+		switch col.id {
+		case "ready":
+			text, cellStyle = w.Ready, readyStyle
+		case "status":
+			text, cellStyle = status, statusStyle
+		case ColumnRestarts:
+			text = fmt.Sprintf("%d", w.RestartCount)
+		case ColumnAge:
+			text = w.Age
+		case ColumnPods:
+			text, cellStyle = podHealthText(w.Health), podHealthStyle(w.Health)
+		case ColumnNode:
+			text = nodesCell(w.Nodes)
+		case ColumnImages:
+			text = imagesCell(w.Images)
+		case ColumnLabel:
+			text = w.ObjectLabels[n.columns.labelKey]
+			if text == "" {
+				text = "-"
+			}
+		}
+		b.WriteString(" ")
+		b.WriteString(cellStyle.Render(fitCell(text, col.width)))
+	}
 
+	row := strings.TrimRight(b.String(), " ")
 	if selected {
-		rowStyle := lipgloss.NewStyle().Background(style.Surface)
-		return rowStyle.Render(fmt.Sprintf("%s%-32s %-10s %-15s %-8s",
-			cursor, name, w.Ready, statusStyle.Render(w.Status), w.Age))
+		return lipgloss.NewStyle().Background(style.Surface).Render(row)
+	}
+	return row
+}
+
+// podHealthText is the pod breakdown of a workload, "" until it has pods.
+func podHealthText(h *repository.PodHealth) string {
+	if h == nil || h.Total == 0 {
+		return ""
 	}
+	return h.String()
+}
 
-	return fmt.Sprintf("%s%-32s %-10s %-15s %-8s",
-		cursor, name, w.Ready, statusStyle.Render(w.Status), w.Age)
+// podHealthStyle colors the pod breakdown of a workload, red when a pod is
+// failing and yellow while some are not ready or were recently OOM killed.
+func podHealthStyle(h *repository.PodHealth) lipgloss.Style {
+	switch {
+	case h == nil || h.Total == 0:
+		return style.StatusMuted
+	case h.Failing():
+		return style.StatusError
+	case !h.Healthy() || h.RecentOOM > 0:
+		return style.StatusPending
+	}
+	return style.StatusMuted
 }
 
 func (n Navigator) renderResources() string {
 	var b strings.Builder
 
+	// Probe health summary (only shown when something is failing)
+	probeSummary := n.renderProbeHealth()
+	summaryLines := 0
+	if probeSummary != "" {
+		b.WriteString(probeSummary)
+		b.WriteString("\n\n")
+		summaryLines = strings.Count(probeSummary, "\n") + 2
+	}
+
 	// Calculate height for each section
-	totalHeight := n.height - 10 // Reserve space for headers
+	totalHeight := n.height - 10 - summaryLines // Reserve space for headers
 	podsHeight := totalHeight * 30 / 100      // 30%
 	hpaHeight := totalHeight * 15 / 100       // 15%
 	cmHeight := totalHeight * 18 / 100        // 18%
@@ -426,7 +645,14 @@ func (n Navigator) renderResources() string {
 
 	// PODS Section
 	sectionActive := n.section == SectionPods
-	b.WriteString(n.renderSectionHeader("PODS", len(n.pods), sectionActive))
+	if n.probeFilter {
+		b.WriteString(n.renderSectionHeader("PODS · probe failures", len(n.probeAffectedPods()), sectionActive))
+	} else {
+		b.WriteString(n.renderSectionHeader("PODS", len(n.pods), sectionActive))
+	}
+	if marked := len(n.MarkedPods()); marked > 0 {
+		b.WriteString(style.StatusMuted.Render(fmt.Sprintf("  %d selected · x bulk actions · esc clear", marked)))
+	}
 	b.WriteString("\n")
 	b.WriteString(n.renderPodsTable(podsHeight, sectionActive))
 	b.WriteString("\n\n")
@@ -463,6 +689,47 @@ func (n Navigator) renderResources() string {
 	return b.String()
 }
 
+// maxProbeHealthRows limits how many workloads the probe summary lists.
+const maxProbeHealthRows = 3
+
+func (n Navigator) renderProbeHealth() string {
+	if len(n.probeHealth) == 0 {
+		return ""
+	}
+
+	failing := 0
+	for _, h := range n.probeHealth {
+		failing += h.FailingContainers
+	}
+
+	var b strings.Builder
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Warning)
+	b.WriteString("  " + titleStyle.Render("PROBE HEALTH"))
+	b.WriteString(style.StatusMuted.Render(fmt.Sprintf("  %d not ready · %d workloads", failing, len(n.probeHealth))))
+	if n.probeFilter {
+		b.WriteString(style.HelpDescStyle.Render("  (p: show all pods)"))
+	} else {
+		b.WriteString(style.HelpDescStyle.Render("  (p: show affected pods)"))
+	}
+
+	for i, h := range n.probeHealth {
+		if i >= maxProbeHealthRows {
+			b.WriteString("\n")
+			b.WriteString(style.StatusMuted.Render(fmt.Sprintf("  ... and %d more", len(n.probeHealth)-maxProbeHealthRows)))
+			break
+		}
+		notReady := fmt.Sprintf("%d not ready", h.FailingContainers)
+		if h.FailingContainers > 0 {
+			notReady = style.StatusError.Render(notReady)
+		}
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  %-32s %s  %.1f flaps/h  %d pods",
+			style.Truncate(h.Workload, 32), notReady, h.FlapRate, len(h.Pods)))
+	}
+
+	return b.String()
+}
+
 func (n Navigator) renderSectionHeader(title string, count int, active bool) string {
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Primary)
 	titleWithCount := titleStyle.Render(fmt.Sprintf("%s (%d)", title, count))
@@ -496,7 +763,11 @@ func (n Navigator) renderPodsTable(maxRows int, active bool) string {
 			b.WriteString(fmt.Sprintf("\n  %-12s %s\n", "Workload:", style.StatusRunning.Render(workloadValue)))
 			// Show 0 replicas since there are no pods running
 			scaleHint := style.StatusMuted.Render(" 🔼 🔽")
-			b.WriteString(fmt.Sprintf("  %-12s %s%s\n\n", "Replicas:", style.StatusRunning.Render("0"), scaleHint))
+			replicas := "0"
+			if target, ok := n.mutations.ScaleTarget(*n.scaleWorkload); ok {
+				replicas = fmt.Sprintf("0 → %d", target)
+			}
+			b.WriteString(fmt.Sprintf("  %-12s %s%s\n\n", "Replicas:", style.StatusRunning.Render(replicas), scaleHint))
 			b.WriteString(style.StatusMuted.Render("  Press s to scale up · d to scale down"))
 		}
 		return b.String()
@@ -533,6 +804,13 @@ func (n Navigator) renderPodsTable(maxRows int, active bool) string {
 	// Show "more below" indicator
 	if endIdx < len(pods) {
 		b.WriteString(style.StatusMuted.Render(fmt.Sprintf("  ... and %d more", len(pods)-endIdx)))
+		if n.podsLoadingMore {
+			b.WriteString("\n")
+		}
+	}
+
+	if n.podsLoadingMore {
+		b.WriteString(renderLoadingMore())
 	}
 
 	return b.String()
@@ -760,12 +1038,27 @@ func (n Navigator) renderPodRow(p repository.PodInfo, selected bool) string {
 	if selected {
 		cursor = style.CursorStyle.Render("> ")
 	}
+	if n.marked[p.Name] {
+		cursor = style.StatusRunning.Render("● ")
+		if selected {
+			cursor = style.CursorStyle.Render(">") + style.StatusRunning.Render("●")
+		}
+	}
 
 	name := style.Truncate(p.Name, 38)
 	statusStyle := style.GetStatusStyle(p.Status)
+	status := p.Status
+
+	// Strike through pods being deleted until a refresh confirms it
+	if n.mutations.Deleting(p.Namespace, p.Name) {
+		name = style.StatusDeleting.Render(fmt.Sprintf("%-38s", name))
+		status = "Deleting"
+		statusStyle = style.StatusMuted
+	}
 
 	// Pad values before styling to maintain alignment
-	statusPadded := fmt.Sprintf("%-10s", p.Status)
+	readyPadded := fmt.Sprintf("%-8s", p.Ready)
+	statusPadded := fmt.Sprintf("%-10s", status)
 	restartsPadded := fmt.Sprintf("%-8d", p.Restarts)
 
 	styledStatus := statusStyle.Render(statusPadded)
@@ -774,14 +1067,27 @@ func (n Navigator) renderPodRow(p repository.PodInfo, selected bool) string {
 		styledRestarts = style.StatusError.Render(restartsPadded)
 	}
 
+	// Tint fields that changed in the last refresh
+	id := podChangeID(p)
+	styledReady := readyPadded
+	if n.podChanges.Changed(id, FieldReady) {
+		styledReady = style.StatusChanged.Render(readyPadded)
+	}
+	if n.podChanges.Changed(id, FieldStatus) {
+		styledStatus = style.StatusChanged.Render(statusPadded)
+	}
+	if n.podChanges.Changed(id, FieldRestarts) {
+		styledRestarts = style.StatusChanged.Render(restartsPadded)
+	}
+
 	if selected {
 		rowStyle := lipgloss.NewStyle().Background(style.Surface)
-		return rowStyle.Render(fmt.Sprintf("%s%-38s %-8s %s %s %-6s",
-			cursor, name, p.Ready, styledStatus, styledRestarts, p.Age))
+		return rowStyle.Render(fmt.Sprintf("%s%-38s %s %s %s %-6s",
+			cursor, name, styledReady, styledStatus, styledRestarts, p.Age))
 	}
 
-	return fmt.Sprintf("%s%-38s %-8s %s %s %-6s",
-		cursor, name, p.Ready, styledStatus, styledRestarts, p.Age)
+	return fmt.Sprintf("%s%-38s %s %s %s %-6s",
+		cursor, name, styledReady, styledStatus, styledRestarts, p.Age)
 }
 
 func (n Navigator) renderNamespaces() string {
@@ -844,6 +1150,7 @@ func (n Navigator) renderResourceTypes() string {
 		repository.ResourceDaemonSets:   "Runs on every node",
 		repository.ResourceJobs:         "One-time batch tasks",
 		repository.ResourceCronJobs:     "Scheduled batch tasks",
+		repository.ResourceServices:     "Stable endpoint for a set of pods",
 	}
 
 	for i, rt := range repository.AllResourceTypes {
@@ -955,13 +1262,17 @@ func (n Navigator) filteredWorkloads() []repository.WorkloadInfo {
 }
 
 func (n Navigator) filteredPods() []repository.PodInfo {
+	pods := n.pods
+	if n.probeFilter {
+		pods = n.probeAffectedPods()
+	}
 	if n.searchQuery == "" {
-		return n.pods
+		return pods
 	}
 
 	query := strings.ToLower(n.searchQuery)
 	var filtered []repository.PodInfo
-	for _, p := range n.pods {
+	for _, p := range pods {
 		if strings.Contains(strings.ToLower(p.Name), query) ||
 			strings.Contains(strings.ToLower(p.Status), query) ||
 			strings.Contains(strings.ToLower(p.Node), query) {
@@ -971,6 +1282,25 @@ func (n Navigator) filteredPods() []repository.PodInfo {
 	return filtered
 }
 
+// probeAffectedPods returns pods listed in the probe health summary,
+// ordered worst workload first.
+func (n Navigator) probeAffectedPods() []repository.PodInfo {
+	byName := make(map[string]repository.PodInfo, len(n.pods))
+	for _, p := range n.pods {
+		byName[p.Name] = p
+	}
+
+	var affected []repository.PodInfo
+	for _, h := range n.probeHealth {
+		for _, name := range h.Pods {
+			if p, ok := byName[name]; ok {
+				affected = append(affected, p)
+			}
+		}
+	}
+	return affected
+}
+
 func (n Navigator) filteredNamespaces() []repository.NamespaceInfo {
 	if n.searchQuery == "" {
 		return n.namespaces
@@ -987,63 +1317,120 @@ func (n Navigator) filteredNamespaces() []repository.NamespaceInfo {
 }
 
 func (n *Navigator) SetWorkloads(workloads []repository.WorkloadInfo) {
+	prev := workloadKeys(n.filteredWorkloads())
 	n.workloads = workloads
-	if n.cursor >= len(n.filteredWorkloads()) {
+	n.workloadChanges.Observe(WorkloadSnapshot(workloads))
+	if n.mode == ModeWorkloads {
+		// Keep the selected workload selected across refreshes
+		n.cursor = RelocateCursor(prev, n.cursor, workloadKeys(n.filteredWorkloads()))
+	} else if n.cursor >= len(n.filteredWorkloads()) {
 		n.cursor = 0
 	}
 }
 
+// AppendWorkloads adds a further page of workloads below the ones already
+// listed, keeping the selected workload selected.
+func (n *Navigator) AppendWorkloads(workloads []repository.WorkloadInfo) {
+	all := make([]repository.WorkloadInfo, 0, len(n.workloads)+len(workloads))
+	all = append(all, n.workloads...)
+	n.SetWorkloads(append(all, workloads...))
+}
+
+// AttachPodHealth sets the pod breakdown of the listed workloads from pods,
+// the pods of their namespace, see repository.AttachPodHealth.
+func (n *Navigator) AttachPodHealth(pods []repository.PodInfo) {
+	repository.AttachPodHealth(n.workloads, pods)
+}
+
+// SetWorkloadsLoadingMore shows or hides the "loading more…" footer below
+// the workloads list while further pages are fetched.
+func (n *Navigator) SetWorkloadsLoadingMore(loading bool) {
+	n.workloadsLoadingMore = loading
+}
+
+// UpdateWorkload replaces a single workload row (and the scale controls'
+// workload) after a targeted refresh, leaving the rest of the list as is.
+func (n *Navigator) UpdateWorkload(w repository.WorkloadInfo) {
+	for i := range n.workloads {
+		if n.workloads[i].Name == w.Name && n.workloads[i].Namespace == w.Namespace && n.workloads[i].Type == w.Type {
+			n.workloads[i] = w
+		}
+	}
+	n.workloadChanges.Observe(WorkloadSnapshot(n.workloads))
+	if sw := n.scaleWorkload; sw != nil && sw.Name == w.Name && sw.Namespace == w.Namespace && sw.Type == w.Type {
+		updated := w
+		n.scaleWorkload = &updated
+	}
+}
+
 func (n *Navigator) SetPods(pods []repository.PodInfo) {
+	prev := podKeys(n.filteredPods())
 	n.pods = pods
-	// Keep cursor in bounds but don't reset to 0 (for real-time refresh)
-	if n.sectionCursors[SectionPods] >= len(pods) {
-		n.sectionCursors[SectionPods] = len(pods) - 1
+	n.podChanges.Observe(PodSnapshot(pods))
+	// Forget selected pods that are gone, so a StatefulSet pod recreated
+	// under the same name is not selected again
+	for name := range n.marked {
+		if !containsPod(pods, name) {
+			delete(n.marked, name)
+		}
 	}
-	if n.sectionCursors[SectionPods] < 0 {
-		n.sectionCursors[SectionPods] = 0
+	// Keep the selected pod selected across real-time refreshes
+	n.sectionCursors[SectionPods] = RelocateCursor(prev, n.sectionCursors[SectionPods], podKeys(n.filteredPods()))
+}
+
+// AppendPods adds a further page of pods below the ones already listed.
+// Pages arrive in name order, so the selected pod keeps its row.
+func (n *Navigator) AppendPods(pods []repository.PodInfo) {
+	all := make([]repository.PodInfo, 0, len(n.pods)+len(pods))
+	all = append(all, n.pods...)
+	n.SetPods(append(all, pods...))
+}
+
+// Pods returns every pod currently listed in the resources view.
+func (n Navigator) Pods() []repository.PodInfo {
+	return n.pods
+}
+
+// SetPodsLoadingMore shows or hides the "loading more…" footer below the
+// pods table while further pages are fetched.
+func (n *Navigator) SetPodsLoadingMore(loading bool) {
+	n.podsLoadingMore = loading
+}
+
+// SetProbeHealth updates the namespace readiness probe summary.
+// The affected-pods drill-down is turned off once nothing is failing.
+func (n *Navigator) SetProbeHealth(health []repository.WorkloadProbeHealth) {
+	n.probeHealth = health
+	if len(health) == 0 {
+		n.probeFilter = false
 	}
 }
 
+// ProbeFilterActive returns true when only probe-affected pods are listed
+func (n Navigator) ProbeFilterActive() bool {
+	return n.probeFilter
+}
+
 func (n *Navigator) SetHPAs(hpas []repository.HPAInfo) {
+	prev := hpaKeys(n.hpas)
 	n.hpas = hpas
-	if n.sectionCursors[SectionHPAs] >= len(hpas) {
-		n.sectionCursors[SectionHPAs] = len(hpas) - 1
-	}
-	if n.sectionCursors[SectionHPAs] < 0 {
-		n.sectionCursors[SectionHPAs] = 0
-	}
+	n.sectionCursors[SectionHPAs] = RelocateCursor(prev, n.sectionCursors[SectionHPAs], hpaKeys(hpas))
 }
 
 func (n *Navigator) SetConfigMaps(cms []repository.ConfigMapInfo) {
+	prev := configMapKeys(n.configmaps)
 	n.configmaps = cms
-	if n.sectionCursors[SectionConfigMaps] >= len(cms) {
-		n.sectionCursors[SectionConfigMaps] = len(cms) - 1
-	}
-	if n.sectionCursors[SectionConfigMaps] < 0 {
-		n.sectionCursors[SectionConfigMaps] = 0
-	}
+	n.sectionCursors[SectionConfigMaps] = RelocateCursor(prev, n.sectionCursors[SectionConfigMaps], configMapKeys(cms))
 }
 
 func (n *Navigator) SetSecrets(secrets []repository.SecretInfo) {
+	prevSecrets := secretKeys(n.filteredSecrets())
+	prevDocker := secretKeys(n.dockerRegistrySecrets())
 	n.secrets = secrets
 
-	// Handle regular secrets cursor
-	filteredCount := len(n.filteredSecrets())
-	if n.sectionCursors[SectionSecrets] >= filteredCount {
-		n.sectionCursors[SectionSecrets] = filteredCount - 1
-	}
-	if n.sectionCursors[SectionSecrets] < 0 {
-		n.sectionCursors[SectionSecrets] = 0
-	}
-
-	// Handle docker registry secrets cursor
-	dockerCount := len(n.dockerRegistrySecrets())
-	if n.sectionCursors[SectionDockerRegistry] >= dockerCount {
-		n.sectionCursors[SectionDockerRegistry] = dockerCount - 1
-	}
-	if n.sectionCursors[SectionDockerRegistry] < 0 {
-		n.sectionCursors[SectionDockerRegistry] = 0
-	}
+	// Regular and docker registry secrets are listed in separate sections
+	n.sectionCursors[SectionSecrets] = RelocateCursor(prevSecrets, n.sectionCursors[SectionSecrets], secretKeys(n.filteredSecrets()))
+	n.sectionCursors[SectionDockerRegistry] = RelocateCursor(prevDocker, n.sectionCursors[SectionDockerRegistry], secretKeys(n.dockerRegistrySecrets()))
 }
 
 func (n *Navigator) SetNamespaces(namespaces []repository.NamespaceInfo) {
@@ -1054,12 +1441,53 @@ func (n *Navigator) SetResourceType(rt repository.ResourceType) {
 	n.resourceType = rt
 }
 
+// SetColumns sets the optional columns of the workloads table.
+func (n *Navigator) SetColumns(columns ColumnSet) {
+	n.columns = columns
+	n.colOffset = 0
+}
+
+// Columns returns the optional columns of the workloads table.
+func (n Navigator) Columns() ColumnSet {
+	return n.columns
+}
+
 func (n *Navigator) SetMode(mode NavigatorMode) {
+	// Refreshes set the mode again; keep the selected pods across them
+	if mode != n.mode {
+		n.ClearMarks()
+	}
 	n.mode = mode
 	n.cursor = 0
 	n.ClearSearch()
 }
 
+// MarkedPods returns the pods selected for a bulk action, in list order.
+func (n Navigator) MarkedPods() []repository.PodInfo {
+	var marked []repository.PodInfo
+	for _, p := range n.pods {
+		if n.marked[p.Name] {
+			marked = append(marked, p)
+		}
+	}
+	return marked
+}
+
+// ClearMarks unselects every pod selected for a bulk action.
+func (n *Navigator) ClearMarks() {
+	n.marked = nil
+}
+
+// containsPod reports whether pods has a pod named name.
+func containsPod(pods []repository.PodInfo, name string) bool {
+	for _, p := range pods {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (n *Navigator) SetSize(width, height int) {
 	n.width = width
 	n.height = height
@@ -1195,6 +1623,40 @@ func (n Navigator) Render(width int) string {
 	return lipgloss.NewStyle().Width(width).Render(n.View())
 }
 
+// SetMutations sets the tracker used to render optimistic deletes and
+// scales. The tracker is shared with the app, which updates it.
+func (n *Navigator) SetMutations(m *Mutations) {
+	n.mutations = m
+}
+
+// SetHighlightChanges turns highlighting of fields that changed between
+// refreshes on or off.
+func (n *Navigator) SetHighlightChanges(enabled bool) {
+	n.podChanges.SetEnabled(enabled)
+	n.workloadChanges.SetEnabled(enabled)
+}
+
+// NextChangeExpiry returns how long until the next highlight expires.
+func (n Navigator) NextChangeExpiry() (time.Duration, bool) {
+	pods, podsOK := n.podChanges.NextExpiry()
+	workloads, workloadsOK := n.workloadChanges.NextExpiry()
+	if !podsOK || (workloadsOK && workloads < pods) {
+		return workloads, workloadsOK
+	}
+	return pods, podsOK
+}
+
+// SetLocation sets the breadcrumb shown above the resources view. A
+// protected namespace gets a "protected" badge.
+func (n *Navigator) SetLocation(protected bool, items ...string) {
+	n.breadcrumb.SetItems(items...)
+	if protected {
+		n.breadcrumb.SetBadge("protected")
+	} else {
+		n.breadcrumb.SetBadge("")
+	}
+}
+
 // SetScaleWorkload stores workload info for scale controls
 func (n *Navigator) SetScaleWorkload(workload *repository.WorkloadInfo) {
 	n.scaleWorkload = workload
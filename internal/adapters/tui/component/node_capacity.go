@@ -0,0 +1,327 @@
+package component
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// NodeCapacitySort is the column the node capacity table is sorted by.
+type NodeCapacitySort int
+
+const (
+	NodeCapacitySortCPU    NodeCapacitySort = iota // Free CPU, most first
+	NodeCapacitySortMemory                         // Free memory, most first
+	NodeCapacitySortPods                           // Free pod slots, most first
+	NodeCapacitySortName                           // Node name, A-Z
+)
+
+// nodeCapacityWarning is the percent of allocatable CPU or memory requested
+// above which a node is highlighted as nearly full.
+const nodeCapacityWarning = 90
+
+// NodeCapacityViewer is a table of every node with the requests of its pods
+// against its allocatable CPU, memory and pods. Opened for a pod, it marks
+// the nodes the pod fits on and, for the others, the predicates it fails.
+type NodeCapacityViewer struct {
+	pod     *repository.PodInfo // Pod evaluated on each node; nil for the plain table
+	rows    []repository.NodeCapacity
+	err     error
+	loaded  bool
+	sortBy  NodeCapacitySort
+	reverse bool // Flip the default order of the sort column
+	visible bool
+	scroll  int
+	width   int
+	height  int
+}
+
+// NodeCapacityViewerClosed is sent when the viewer is closed
+type NodeCapacityViewerClosed struct{}
+
+func NewNodeCapacityViewer() NodeCapacityViewer {
+	return NodeCapacityViewer{}
+}
+
+func (v NodeCapacityViewer) Update(msg tea.Msg) (NodeCapacityViewer, tea.Cmd) {
+	if !v.visible {
+		return v, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "q":
+			v.visible = false
+			return v, func() tea.Msg { return NodeCapacityViewerClosed{} }
+		case "c":
+			v.setSort(NodeCapacitySortCPU)
+		case "m":
+			v.setSort(NodeCapacitySortMemory)
+		case "p":
+			v.setSort(NodeCapacitySortPods)
+		case "n":
+			v.setSort(NodeCapacitySortName)
+		case "up", "k":
+			if v.scroll > 0 {
+				v.scroll--
+			}
+		case "down", "j":
+			if v.scroll < v.maxScroll() {
+				v.scroll++
+			}
+		case "g", "home":
+			v.scroll = 0
+		case "G", "end":
+			v.scroll = v.maxScroll()
+		}
+	}
+	return v, nil
+}
+
+// setSort sorts by column; choosing the current column again flips the order.
+func (v *NodeCapacityViewer) setSort(column NodeCapacitySort) {
+	if v.sortBy == column {
+		v.reverse = !v.reverse
+	} else {
+		v.sortBy, v.reverse = column, false
+	}
+	v.sortRows()
+}
+
+// sortRows sorts by the sort column. For a pod, the nodes it fits on come
+// first whatever the column.
+func (v *NodeCapacityViewer) sortRows() {
+	less := func(a, b repository.NodeCapacity) bool {
+		switch v.sortBy {
+		case NodeCapacitySortMemory:
+			return a.FreeMemory() > b.FreeMemory()
+		case NodeCapacitySortPods:
+			return a.MaxPods-a.Pods > b.MaxPods-b.Pods
+		case NodeCapacitySortName:
+			return a.Name < b.Name
+		}
+		return a.FreeCPU() > b.FreeCPU()
+	}
+	sort.SliceStable(v.rows, func(i, j int) bool {
+		a, b := v.rows[i], v.rows[j]
+		if v.pod != nil && a.Fits() != b.Fits() {
+			return a.Fits()
+		}
+		if v.reverse {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+}
+
+func (v NodeCapacityViewer) maxVisibleRows() int {
+	return max(v.height-12, 5)
+}
+
+func (v NodeCapacityViewer) maxScroll() int {
+	return max(len(v.rows)-v.maxVisibleRows(), 0)
+}
+
+// Column widths; the node name takes what is left, and the taints or the
+// failed predicates what is left after the other columns
+const (
+	nodeCapacityAmountWidth  = 17 // e.g. "12.50/16.00"
+	nodeCapacityPercentWidth = 5
+	nodeCapacityFreeWidth    = 9
+	nodeCapacityPodsWidth    = 8
+	nodeCapacityNameWidth    = 28
+)
+
+// nodeCapacityColumns is the header of the table. The marker shows the sort
+// column.
+func (v NodeCapacityViewer) nodeCapacityColumns() string {
+	marker := func(column NodeCapacitySort, label string) string {
+		if v.sortBy != column {
+			return label
+		}
+		// Names sort A-Z and the rest most free first, unless flipped
+		ascending := (column == NodeCapacitySortName) != v.reverse
+		if ascending {
+			return label + "▲"
+		}
+		return label + "▼"
+	}
+	last := "TAINTS"
+	if v.pod != nil {
+		last = "FITS"
+	}
+	return fmt.Sprintf("%-*s %*s %*s %*s %*s %*s %*s %*s %s",
+		nodeCapacityNameWidth, marker(NodeCapacitySortName, "NODE"),
+		nodeCapacityAmountWidth, "CPU REQ/ALLOC",
+		nodeCapacityPercentWidth, "%",
+		nodeCapacityFreeWidth, marker(NodeCapacitySortCPU, "FREE"),
+		nodeCapacityAmountWidth, "MEM REQ/ALLOC",
+		nodeCapacityPercentWidth, "%",
+		nodeCapacityFreeWidth, marker(NodeCapacitySortMemory, "FREE"),
+		nodeCapacityPodsWidth, marker(NodeCapacitySortPods, "PODS"),
+		last,
+	)
+}
+
+// nodeCapacityRow renders one node. With a pod, the last column says
+// whether it fits or which predicates it fails, and failing nodes are red;
+// otherwise nearly full nodes are yellow, and cordoned or not ready ones
+// muted.
+func (v NodeCapacityViewer) nodeCapacityRow(n repository.NodeCapacity, lastWidth int) string {
+	name := n.Name
+	switch {
+	case !n.Ready:
+		name += " (NotReady)"
+	case n.Unschedulable:
+		name += " (cordoned)"
+	}
+	last := n.TaintSummary()
+	if v.pod != nil {
+		last = "✓"
+		if !n.Fits() {
+			var reasons []string
+			for _, f := range n.Failures {
+				reasons = append(reasons, f.Reason)
+			}
+			last = "✗ " + strings.Join(reasons, "; ")
+		}
+	}
+	row := fmt.Sprintf("%-*s %*s %*s %*s %*s %*s %*s %*s %s",
+		nodeCapacityNameWidth, style.Truncate(name, nodeCapacityNameWidth),
+		nodeCapacityAmountWidth, formatMilliCPU(n.RequestedCPU)+"/"+formatMilliCPU(n.AllocatableCPU),
+		nodeCapacityPercentWidth, fmt.Sprintf("%.0f%%", n.CPUPercent()),
+		nodeCapacityFreeWidth, formatMilliCPU(max(n.FreeCPU(), 0)),
+		nodeCapacityAmountWidth, formatBytes(n.RequestedMemory)+"/"+formatBytes(n.AllocatableMemory),
+		nodeCapacityPercentWidth, fmt.Sprintf("%.0f%%", n.MemoryPercent()),
+		nodeCapacityFreeWidth, formatBytes(max(n.FreeMemory(), 0)),
+		nodeCapacityPodsWidth, fmt.Sprintf("%d/%d", n.Pods, n.MaxPods),
+		style.Truncate(last, max(lastWidth, 4)),
+	)
+	switch {
+	case v.pod != nil && !n.Fits():
+		return style.StatusError.Render(row)
+	case v.pod != nil:
+		return style.StatusRunning.Render(row)
+	case !n.Ready || n.Unschedulable:
+		return style.StatusMuted.Render(row)
+	case n.CPUPercent() >= nodeCapacityWarning || n.MemoryPercent() >= nodeCapacityWarning || n.Pods >= n.MaxPods:
+		return style.StatusPending.Render(row)
+	}
+	return row
+}
+
+func (v NodeCapacityViewer) View() string {
+	if !v.visible {
+		return ""
+	}
+
+	var content strings.Builder
+	lastWidth := v.width - 16 - nodeCapacityNameWidth - 2*nodeCapacityAmountWidth - 2*nodeCapacityPercentWidth -
+		2*nodeCapacityFreeWidth - nodeCapacityPodsWidth - 8
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Secondary)
+	content.WriteString(headerStyle.Render(v.nodeCapacityColumns()))
+	content.WriteString("\n")
+
+	switch {
+	case !v.loaded:
+		content.WriteString(style.StatusMuted.Render("Loading nodes..."))
+		content.WriteString("\n")
+	case v.err != nil:
+		content.WriteString(style.StatusError.Render("Error: " + v.err.Error()))
+		content.WriteString("\n")
+	case len(v.rows) == 0:
+		content.WriteString(style.StatusMuted.Render("No nodes"))
+		content.WriteString("\n")
+	default:
+		end := min(v.scroll+v.maxVisibleRows(), len(v.rows))
+		for _, n := range v.rows[v.scroll:end] {
+			content.WriteString(v.nodeCapacityRow(n, lastWidth))
+			content.WriteString("\n")
+		}
+	}
+
+	// Breadcrumb
+	separatorStyle := lipgloss.NewStyle().Foreground(style.TextMuted)
+	itemStyle := lipgloss.NewStyle().Foreground(style.Primary)
+	infoStyle := lipgloss.NewStyle().Foreground(style.Secondary)
+	var breadcrumb string
+	if v.pod != nil {
+		fitting := 0
+		for _, n := range v.rows {
+			if n.Fits() {
+				fitting++
+			}
+		}
+		breadcrumb = itemStyle.Render(v.pod.Namespace+"/"+v.pod.Name) +
+			separatorStyle.Render(" > ") +
+			itemStyle.Render("node capacity") +
+			separatorStyle.Render(" - ") +
+			infoStyle.Render(fmt.Sprintf("[fits %d/%d nodes]", fitting, len(v.rows)))
+	} else {
+		breadcrumb = itemStyle.Render("nodes") +
+			separatorStyle.Render(" > ") +
+			itemStyle.Render("capacity") +
+			separatorStyle.Render(" - ") +
+			infoStyle.Render(fmt.Sprintf("[%d nodes]", len(v.rows)))
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Surface).
+		Padding(0, 1).
+		Width(v.width - 10).
+		Height(v.height - 10)
+
+	scrollInfo := ""
+	if v.maxScroll() > 0 {
+		scrollInfo = fmt.Sprintf("[%d/%d] ", v.scroll+1, v.maxScroll()+1)
+	}
+	footer := style.StatusMuted.Render(scrollInfo + "↑↓:scroll  c:free cpu  m:free memory  p:free pods  n:name  Esc:close")
+
+	return breadcrumb + "\n" + boxStyle.Render(content.String()) + "\n" + footer
+}
+
+// Show opens the viewer, evaluating pod on each node when it is not nil;
+// rows arrive with SetCapacity.
+func (v *NodeCapacityViewer) Show(pod *repository.PodInfo) {
+	v.pod = pod
+	v.rows = nil
+	v.err = nil
+	v.loaded = false
+	v.scroll = 0
+	v.visible = true
+}
+
+// SetCapacity replaces the rows, keeping the sort order and scroll
+// position across refreshes.
+func (v *NodeCapacityViewer) SetCapacity(rows []repository.NodeCapacity, err error) {
+	v.rows = rows
+	v.err = err
+	v.loaded = true
+	v.sortRows()
+	v.scroll = min(v.scroll, v.maxScroll())
+}
+
+// Pod returns the pod evaluated on each node, nil for the plain table.
+func (v NodeCapacityViewer) Pod() *repository.PodInfo {
+	return v.pod
+}
+
+func (v *NodeCapacityViewer) Hide() {
+	v.visible = false
+}
+
+func (v NodeCapacityViewer) IsVisible() bool {
+	return v.visible
+}
+
+func (v *NodeCapacityViewer) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
@@ -0,0 +1,322 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+const (
+	// toastDuration is how long a toast stays in the status bar
+	toastDuration = 6 * time.Second
+	// maxErrorLog is how many notifications the error log keeps
+	maxErrorLog = 200
+)
+
+// Severity is how bad a notification is.
+type Severity int
+
+const (
+	SeverityInfo    Severity = iota
+	SeverityWarning          // Likely to pass on retry, e.g. a conflict or a busy server
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "info"
+}
+
+// Notification is a message shown as a toast in the status bar and kept
+// in the error log.
+type Notification struct {
+	Time      time.Time
+	Severity  Severity
+	Op        string // What was attempted, e.g. "list pods"
+	Namespace string // Namespace of the request, "" for cluster-wide ones
+	Message   string
+	Count     int   // Times it happened in a row, e.g. on every refresh
+	Err       error // The error reported, nil for other messages
+	Sticky    bool  // The toast stays until another replaces it or it is dismissed
+}
+
+// ErrorNotification describes a failed repository call. Errors from the
+// API server carry their HTTP status and the resource that failed, so that
+// a Forbidden error says which permission is missing.
+func ErrorNotification(op, namespace string, err error) Notification {
+	n := Notification{Severity: SeverityError, Op: op, Namespace: namespace, Message: err.Error(), Err: err}
+	if repository.IsCallTimeout(err) {
+		n.Severity = SeverityWarning
+		n.Message = "timed out waiting for the API server"
+		return n
+	}
+	if detail, ok := repository.DescribeAPIError(err); ok {
+		n.Message = detail.Summary() + ": " + detail.Message
+		if detail.Retryable() {
+			n.Severity = SeverityWarning
+		}
+	}
+	return n
+}
+
+// Text is the notification on one line, e.g.
+// "list pods in prod: HTTP 403 Forbidden, pods: ...".
+func (n Notification) Text() string {
+	var b strings.Builder
+	b.WriteString(n.Op)
+	if n.Namespace != "" {
+		b.WriteString(" in " + n.Namespace)
+	}
+	if n.Op != "" || n.Namespace != "" {
+		b.WriteString(": ")
+	}
+	b.WriteString(n.Message)
+	return b.String()
+}
+
+// sameAs reports whether o repeats n, as a request failing on every
+// refresh does.
+func (n Notification) sameAs(o Notification) bool {
+	return n.Severity == o.Severity && n.Op == o.Op && n.Namespace == o.Namespace && n.Message == o.Message
+}
+
+// Notifications holds the toast shown in the status bar and the log of
+// every notification of the session.
+type Notifications struct {
+	toast   *Notification
+	toastID int
+	log     []Notification // Oldest first, at most maxErrorLog
+}
+
+// ToastExpired is sent when the toast with ID has been shown long enough.
+type ToastExpired struct {
+	ID int
+}
+
+// Push shows n as the toast and appends it to the log, or counts it on
+// the last entry when it repeats that. The returned command expires the
+// toast, unless it is sticky.
+func (ns *Notifications) Push(n Notification) tea.Cmd {
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+	n.Count = 1
+	if last := len(ns.log) - 1; last >= 0 && ns.log[last].sameAs(n) {
+		n.Count = ns.log[last].Count + 1
+		ns.log[last] = n
+	} else {
+		ns.log = append(ns.log, n)
+		if len(ns.log) > maxErrorLog {
+			ns.log = ns.log[len(ns.log)-maxErrorLog:]
+		}
+	}
+	ns.toast = &n
+	ns.toastID++
+	id := ns.toastID
+	if n.Sticky {
+		return nil
+	}
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg { return ToastExpired{ID: id} })
+}
+
+// Expire hides the toast if it is still the one with id.
+func (ns *Notifications) Expire(id int) {
+	if id == ns.toastID {
+		ns.toast = nil
+	}
+}
+
+// Dismiss hides the toast, sticky or not.
+func (ns *Notifications) Dismiss() {
+	ns.toast = nil
+}
+
+// Toast returns the notification shown in the status bar, nil when none.
+func (ns Notifications) Toast() *Notification {
+	return ns.toast
+}
+
+// Log returns the notifications of the session, oldest first.
+func (ns Notifications) Log() []Notification {
+	return ns.log
+}
+
+// Clear empties the log and hides the toast.
+func (ns *Notifications) Clear() {
+	ns.log = nil
+	ns.toast = nil
+}
+
+// ToastView renders the toast for the status bar, "" when none is shown.
+func (ns Notifications) ToastView(width int) string {
+	n := ns.toast
+	if n == nil {
+		return ""
+	}
+	text := n.Text()
+	if n.Count > 1 {
+		text += fmt.Sprintf(" (x%d)", n.Count)
+	}
+	text = style.Truncate(text, width)
+	switch n.Severity {
+	case SeverityError:
+		return style.StatusError.Render("✗ " + text)
+	case SeverityWarning:
+		return style.StatusPending.Render("! " + text)
+	}
+	return style.StatusRunning.Render(text)
+}
+
+// ErrorLogViewer lists the notifications of the session, newest first,
+// with the full message of the selected one.
+type ErrorLogViewer struct {
+	entries []Notification // Newest first
+	cursor  int
+	visible bool
+	width   int
+	height  int
+}
+
+// ErrorLogViewerClosed is sent when the viewer is closed
+type ErrorLogViewerClosed struct{}
+
+// ErrorLogCleared is sent when the log is cleared from the viewer
+type ErrorLogCleared struct{}
+
+func NewErrorLogViewer() ErrorLogViewer {
+	return ErrorLogViewer{}
+}
+
+func (v ErrorLogViewer) Update(msg tea.Msg) (ErrorLogViewer, tea.Cmd) {
+	if !v.visible {
+		return v, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return v, nil
+	}
+	switch keyMsg.String() {
+	case "esc", "q", "E":
+		v.visible = false
+		return v, func() tea.Msg { return ErrorLogViewerClosed{} }
+	case "up", "k":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "down", "j":
+		if v.cursor < len(v.entries)-1 {
+			v.cursor++
+		}
+	case "c":
+		v.entries = nil
+		v.cursor = 0
+		return v, func() tea.Msg { return ErrorLogCleared{} }
+	}
+	return v, nil
+}
+
+func (v ErrorLogViewer) View() string {
+	if !v.visible {
+		return ""
+	}
+
+	separatorStyle := lipgloss.NewStyle().Foreground(style.TextMuted)
+	itemStyle := lipgloss.NewStyle().Foreground(style.Primary)
+	infoStyle := lipgloss.NewStyle().Foreground(style.Secondary)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Secondary)
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Surface).
+		Padding(0, 1).
+		Width(v.width - 10).
+		Height(v.height - 10)
+	msgWidth := v.width - 70
+	if msgWidth < 20 {
+		msgWidth = 20
+	}
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render(fmt.Sprintf("  %-8s %-7s %-24s %-16s %-5s %s", "TIME", "LEVEL", "OPERATION", "NAMESPACE", "COUNT", "MESSAGE")))
+	content.WriteString("\n")
+	if len(v.entries) == 0 {
+		content.WriteString(style.StatusMuted.Render("  No errors this session."))
+		content.WriteString("\n")
+	}
+	for i, n := range v.entries {
+		ns := n.Namespace
+		if ns == "" {
+			ns = "-"
+		}
+		row := fmt.Sprintf("%-8s %-7s %-24s %-16s %-5d %s",
+			n.Time.Format("15:04:05"), n.Severity, style.Truncate(n.Op, 24), style.Truncate(ns, 16),
+			n.Count, style.Truncate(n.Message, msgWidth))
+		switch {
+		case i == v.cursor:
+			content.WriteString(style.CursorStyle.Render("> " + row))
+			content.WriteString("\n")
+			// The full message has the detail needed to act on it
+			wrapped := lipgloss.NewStyle().Width(v.width - 20).Render(n.Message)
+			for _, line := range strings.Split(wrapped, "\n") {
+				content.WriteString(style.StatusMuted.Render("    " + line))
+				content.WriteString("\n")
+			}
+			continue
+		case n.Severity == SeverityError:
+			content.WriteString(style.StatusError.Render("  " + row))
+		case n.Severity == SeverityWarning:
+			content.WriteString(style.StatusPending.Render("  " + row))
+		default:
+			content.WriteString("  " + row)
+		}
+		content.WriteString("\n")
+	}
+
+	breadcrumb := itemStyle.Render("errors") +
+		separatorStyle.Render(" - ") +
+		infoStyle.Render(fmt.Sprintf("[%d this session]", len(v.entries)))
+	footer := style.StatusMuted.Render("↑↓:select  c:clear  Esc:close")
+
+	return breadcrumb + "\n" + boxStyle.Render(content.String()) + "\n" + footer
+}
+
+// Show opens the viewer on the log, given oldest first.
+func (v *ErrorLogViewer) Show(log []Notification) {
+	v.cursor = 0
+	v.visible = true
+	v.SetEntries(log)
+}
+
+// SetEntries replaces the listed notifications, given oldest first.
+func (v *ErrorLogViewer) SetEntries(log []Notification) {
+	v.entries = make([]Notification, len(log))
+	for i, n := range log {
+		v.entries[len(log)-1-i] = n
+	}
+	if v.cursor > len(v.entries)-1 {
+		v.cursor = 0
+	}
+}
+
+func (v *ErrorLogViewer) Hide() {
+	v.visible = false
+}
+
+func (v ErrorLogViewer) IsVisible() bool {
+	return v.visible
+}
+
+func (v *ErrorLogViewer) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
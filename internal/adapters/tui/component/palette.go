@@ -0,0 +1,369 @@
+package component
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/fuzzy"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+const (
+	// PaletteNamespace is the kind of a namespace item
+	PaletteNamespace = "namespace"
+	// paletteIndexTTL is how long the workloads of a namespace are kept
+	// before the palette asks for them again
+	paletteIndexTTL = 2 * time.Minute
+	// maxPaletteRecent is how many visited items rank higher
+	maxPaletteRecent = 20
+	// paletteRecentBonus is added to the score of the most recently
+	// visited item, and decreases down the list
+	paletteRecentBonus = 40
+	// paletteRows is how many matches are listed
+	paletteRows = 12
+)
+
+// PaletteItem is a place the palette jumps to: a namespace, a workload or
+// a pod.
+type PaletteItem struct {
+	Kind      string // PaletteNamespace, or a kind as kubectl takes it, e.g. "deployment" or "pod"
+	Namespace string
+	Name      string // "" for a namespace
+}
+
+// Text is what queries are matched against: "prod" for a namespace,
+// "prod/deployment/payments" for others.
+func (i PaletteItem) Text() string {
+	if i.Kind == PaletteNamespace {
+		return i.Namespace
+	}
+	return i.Namespace + "/" + i.Kind + "/" + i.Name
+}
+
+// PaletteIndexRequest asks app.go to list the workloads of namespaces for
+// the palette, answered with SetIndex.
+type PaletteIndexRequest struct {
+	Namespaces []string
+}
+
+// PaletteSelected is sent when an item is picked to jump to.
+type PaletteSelected struct {
+	Item PaletteItem
+}
+
+// PaletteClosed is sent when the palette is closed without a pick
+type PaletteClosed struct{}
+
+// paletteIndex is the workloads of a namespace, as last listed.
+type paletteIndex struct {
+	items    []PaletteItem
+	loadedAt time.Time
+}
+
+// Palette is a fuzzy finder over namespaces, their workloads and the pods
+// and workloads visited recently, which jumps to the one picked. The
+// workloads of a namespace are listed the first time it is needed, the
+// current one or one the query points at, and kept for paletteIndexTTL;
+// matches are shown while they load.
+type Palette struct {
+	query      string
+	cursor     int
+	visible    bool
+	width      int
+	height     int
+	namespaces []string
+	index      map[string]paletteIndex // Workloads by namespace
+	loading    map[string]bool         // Namespaces whose workloads are being listed
+	recent     []PaletteItem           // Visited items, most recent first
+	matches    []PaletteItem           // Items matching the query, best first
+}
+
+func NewPalette() Palette {
+	return Palette{index: map[string]paletteIndex{}, loading: map[string]bool{}}
+}
+
+func (p Palette) Init() tea.Cmd {
+	return nil
+}
+
+func (p Palette) Update(msg tea.Msg) (Palette, tea.Cmd) {
+	if !p.visible {
+		return p, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	switch keyMsg.Type {
+	case tea.KeyEsc, tea.KeyCtrlP:
+		p.visible = false
+		return p, func() tea.Msg { return PaletteClosed{} }
+	case tea.KeyEnter:
+		if p.cursor >= len(p.matches) {
+			return p, nil
+		}
+		item := p.matches[p.cursor]
+		p.visible = false
+		return p, func() tea.Msg { return PaletteSelected{Item: item} }
+	case tea.KeyUp:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return p, nil
+	case tea.KeyDown:
+		if p.cursor < min(len(p.matches), paletteRows)-1 {
+			p.cursor++
+		}
+		return p, nil
+	case tea.KeyBackspace:
+		if len(p.query) > 0 {
+			runes := []rune(p.query)
+			p.query = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		p.query = ""
+	case tea.KeyRunes, tea.KeySpace:
+		p.query += string(keyMsg.Runes)
+	default:
+		return p, nil
+	}
+	p.filter()
+	return p, p.requestIndex(p.matchedNamespaces()...)
+}
+
+// filter matches the query against every item, ranking recently visited
+// ones higher, and puts the cursor back on the best match.
+func (p *Palette) filter() {
+	items := p.items()
+	texts := make([]string, len(items))
+	for i, item := range items {
+		texts[i] = item.Text()
+	}
+	bonus := map[string]int{}
+	for rank, item := range p.recent {
+		bonus[item.Text()] = paletteRecentBonus * (maxPaletteRecent - rank) / maxPaletteRecent
+	}
+
+	matches := fuzzy.Filter(p.query, texts)
+	for i := range matches {
+		matches[i].Score += bonus[texts[matches[i].Index]]
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].Score > matches[b].Score
+	})
+	p.matches = p.matches[:0]
+	for _, m := range matches {
+		p.matches = append(p.matches, items[m.Index])
+	}
+	p.cursor = 0
+}
+
+// items merges the recent items, the namespaces and the workloads listed
+// so far, each once.
+func (p Palette) items() []PaletteItem {
+	seen := map[string]bool{}
+	var items []PaletteItem
+	add := func(item PaletteItem) {
+		if !seen[item.Text()] {
+			seen[item.Text()] = true
+			items = append(items, item)
+		}
+	}
+	for _, item := range p.recent {
+		add(item)
+	}
+	for _, ns := range p.namespaces {
+		add(PaletteItem{Kind: PaletteNamespace, Namespace: ns})
+	}
+	namespaces := make([]string, 0, len(p.index))
+	for ns := range p.index {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		for _, item := range p.index[ns].items {
+			add(item)
+		}
+	}
+	return items
+}
+
+// matchedNamespaces returns the namespaces among the listed matches, so
+// that typing a namespace's name lists its workloads.
+func (p Palette) matchedNamespaces() []string {
+	var namespaces []string
+	for _, item := range p.matches[:min(len(p.matches), paletteRows)] {
+		if item.Kind == PaletteNamespace {
+			namespaces = append(namespaces, item.Namespace)
+		}
+	}
+	return namespaces
+}
+
+// requestIndex asks for the workloads of the namespaces not listed yet or
+// listed more than paletteIndexTTL ago, nil when there are none.
+func (p *Palette) requestIndex(namespaces ...string) tea.Cmd {
+	var stale []string
+	for _, ns := range namespaces {
+		if ns == "" || p.loading[ns] {
+			continue
+		}
+		if index, ok := p.index[ns]; ok && time.Since(index.loadedAt) < paletteIndexTTL {
+			continue
+		}
+		p.loading[ns] = true
+		stale = append(stale, ns)
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	req := PaletteIndexRequest{Namespaces: stale}
+	return func() tea.Msg { return req }
+}
+
+func (p Palette) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	var b strings.Builder
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Primary).
+		Padding(0, 1).
+		Width(p.boxWidth() - 6)
+	b.WriteString(inputStyle.Render("> " + p.query + "█"))
+	b.WriteString("\n")
+
+	if len(p.matches) == 0 {
+		b.WriteString(style.StatusMuted.Render("  No matches"))
+		b.WriteString("\n")
+	}
+	nameWidth := max(p.boxWidth()-36, 20)
+	for i, item := range p.matches[:min(len(p.matches), paletteRows)] {
+		row := fmt.Sprintf("%-12s %s", item.Kind, style.Truncate(item.Namespace, nameWidth))
+		if item.Kind != PaletteNamespace {
+			row = fmt.Sprintf("%-12s %-*s %s", item.Kind, nameWidth, style.Truncate(item.Name, nameWidth), style.Truncate(item.Namespace, 18))
+		}
+		if i == p.cursor {
+			b.WriteString(style.CursorStyle.Render("> " + row))
+		} else {
+			b.WriteString("  " + row)
+		}
+		b.WriteString("\n")
+	}
+	if more := len(p.matches) - paletteRows; more > 0 {
+		b.WriteString(style.StatusMuted.Render(fmt.Sprintf("  … %d more, keep typing", more)))
+		b.WriteString("\n")
+	}
+
+	hint := "Enter jump • ↑↓ select • Esc close"
+	var loading []string
+	for ns := range p.loading {
+		loading = append(loading, ns)
+	}
+	if len(loading) > 0 {
+		sort.Strings(loading)
+		hint = "Indexing " + strings.Join(loading, ", ") + "… • " + hint
+	}
+	b.WriteString("\n")
+	b.WriteString(style.StatusMuted.Render(hint))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Primary).
+		Padding(1, 2).
+		Width(p.boxWidth()).
+		Background(style.Background)
+	return boxStyle.Render(b.String())
+}
+
+func (p Palette) boxWidth() int {
+	return min(max(p.width-10, 50), 100)
+}
+
+// Show opens the palette with an empty query, which lists the recent items
+// first. Returns the request for the workloads of namespace, the current
+// one, and of the namespaces of recent items, when they are stale.
+func (p *Palette) Show(namespace string) tea.Cmd {
+	p.query = ""
+	p.visible = true
+	p.filter()
+	namespaces := []string{namespace}
+	for _, item := range p.recent {
+		namespaces = append(namespaces, item.Namespace)
+	}
+	return p.requestIndex(namespaces...)
+}
+
+// SetNamespaces replaces the namespaces listed.
+func (p *Palette) SetNamespaces(namespaces []string) {
+	p.namespaces = namespaces
+	if p.visible {
+		p.filter()
+	}
+}
+
+// SetIndex stores the workloads listed for namespace. With err, the
+// namespace is left as it was, to be asked for again.
+func (p *Palette) SetIndex(namespace string, items []PaletteItem, err error) {
+	delete(p.loading, namespace)
+	if err != nil {
+		return
+	}
+	p.index[namespace] = paletteIndex{items: items, loadedAt: time.Now()}
+	if p.visible {
+		cursor := p.cursor
+		p.filter()
+		p.cursor = min(cursor, max(min(len(p.matches), paletteRows)-1, 0))
+	}
+}
+
+// Visit records item as visited, ranking it higher from now on.
+func (p *Palette) Visit(item PaletteItem) {
+	recent := []PaletteItem{item}
+	for _, r := range p.recent {
+		if r != item && len(recent) < maxPaletteRecent {
+			recent = append(recent, r)
+		}
+	}
+	p.recent = recent
+}
+
+// Reset forgets the namespaces, workloads and visited items, as when
+// switching to another cluster.
+func (p *Palette) Reset() {
+	p.namespaces = nil
+	p.index = map[string]paletteIndex{}
+	p.loading = map[string]bool{}
+	p.recent = nil
+	p.matches = nil
+}
+
+// Query returns the text typed so far.
+func (p Palette) Query() string {
+	return p.query
+}
+
+// Matches returns the items matching the query, best first.
+func (p Palette) Matches() []PaletteItem {
+	return p.matches
+}
+
+func (p *Palette) Hide() {
+	p.visible = false
+}
+
+func (p Palette) IsVisible() bool {
+	return p.visible
+}
+
+func (p *Palette) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
@@ -0,0 +1,95 @@
+package component
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// SavePanelRequest is sent by the logs and events panels ("ctrl+s") to
+// save what they display to a file. app.go prompts for the path.
+type SavePanelRequest struct {
+	Panel     string // "logs" or "events"
+	Container string // Container shown, "" for all
+	Workload  string // Workload whose pods' logs are merged, "" for the pod's own
+	Filter    string // Search filter applied, "" for none
+	Lines     int
+	Content   string // As displayed, without styling
+}
+
+// PanelExport is a panel's content and where it came from, as written to
+// a file.
+type PanelExport struct {
+	SavePanelRequest
+	Context   string
+	Namespace string
+	Pod       string
+	Time      time.Time
+}
+
+// FileName is the default file to save to, e.g.
+// "prod_api-7d9f_api_logs_20240516-104200.txt".
+func (e PanelExport) FileName() string {
+	parts := []string{e.Namespace, e.Pod}
+	if e.Workload != "" {
+		parts[1] = e.Workload
+	}
+	if e.Panel == "logs" {
+		container := e.Container
+		if container == "" {
+			container = "all"
+		}
+		parts = append(parts, container)
+	}
+	parts = append(parts, e.Panel, e.Time.Format("20060102-150405"))
+	return strings.Join(parts, "_") + ".txt"
+}
+
+// Header describes where the content came from, one "# key: value" line
+// each, followed by a blank line.
+func (e PanelExport) Header() string {
+	var b strings.Builder
+	line := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "# %-10s %s\n", key+":", value)
+		}
+	}
+	line("Context", e.Context)
+	line("Namespace", e.Namespace)
+	line("Pod", e.Pod)
+	line("Workload", e.Workload)
+	if e.Panel == "logs" {
+		container := e.Container
+		if container == "" {
+			container = "(all)"
+		}
+		line("Container", container)
+	}
+	line("Filter", e.Filter)
+	line("Exported", e.Time.Format(time.RFC3339))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// WritePanelExport writes the header and content of e to path and returns
+// the bytes written. An existing file is left alone, with an error
+// matching fs.ErrExist, unless overwrite is set.
+func WritePanelExport(path string, e PanelExport, overwrite bool) (int, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	n, err := f.WriteString(e.Header() + e.Content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return n, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,318 @@
+package component
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// PodTopSort is the column the pod metrics table is sorted by.
+type PodTopSort int
+
+const (
+	PodTopSortCPU         PodTopSort = iota // CPU usage, highest first
+	PodTopSortMemory                        // Memory usage, highest first
+	PodTopSortMemoryLimit                   // Memory usage as percent of limit, highest first
+	PodTopSortName                          // Pod name, A-Z
+)
+
+// memoryLimitWarning is the percent of its memory limit above which a pod
+// is highlighted, as it is close to being OOMKilled.
+const memoryLimitWarning = 90
+
+// PodTopViewer is a top-like table of the CPU and memory usage of the pods
+// of a namespace, next to their requests and limits.
+type PodTopViewer struct {
+	namespace string
+	rows      []repository.PodUsage
+	err       error
+	loaded    bool
+	sortBy    PodTopSort
+	reverse   bool // Flip the default order of the sort column
+	visible   bool
+	scroll    int
+	width     int
+	height    int
+}
+
+// PodTopViewerClosed is sent when the viewer is closed
+type PodTopViewerClosed struct{}
+
+func NewPodTopViewer() PodTopViewer {
+	return PodTopViewer{}
+}
+
+func (v PodTopViewer) Init() tea.Cmd {
+	return nil
+}
+
+func (v PodTopViewer) Update(msg tea.Msg) (PodTopViewer, tea.Cmd) {
+	if !v.visible {
+		return v, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			v.visible = false
+			return v, func() tea.Msg { return PodTopViewerClosed{} }
+		case "c":
+			v.setSort(PodTopSortCPU)
+		case "m":
+			v.setSort(PodTopSortMemory)
+		case "%":
+			v.setSort(PodTopSortMemoryLimit)
+		case "n":
+			v.setSort(PodTopSortName)
+		case "up", "k":
+			if v.scroll > 0 {
+				v.scroll--
+			}
+		case "down", "j":
+			if v.scroll < v.maxScroll() {
+				v.scroll++
+			}
+		case "g", "home":
+			v.scroll = 0
+		case "G", "end":
+			v.scroll = v.maxScroll()
+		}
+	}
+
+	return v, nil
+}
+
+// setSort sorts by column; choosing the current column again flips the order.
+func (v *PodTopViewer) setSort(column PodTopSort) {
+	if v.sortBy == column {
+		v.reverse = !v.reverse
+	} else {
+		v.sortBy, v.reverse = column, false
+	}
+	v.sortRows()
+}
+
+func (v *PodTopViewer) sortRows() {
+	less := func(a, b repository.PodUsage) bool {
+		switch v.sortBy {
+		case PodTopSortMemory:
+			return a.MemoryBytes > b.MemoryBytes
+		case PodTopSortMemoryLimit:
+			return a.MemoryPercentOfLimit() > b.MemoryPercentOfLimit()
+		case PodTopSortName:
+			return a.Name < b.Name
+		}
+		return a.CPUMilli > b.CPUMilli
+	}
+	sort.SliceStable(v.rows, func(i, j int) bool {
+		if v.reverse {
+			return less(v.rows[j], v.rows[i])
+		}
+		return less(v.rows[i], v.rows[j])
+	})
+}
+
+func (v PodTopViewer) maxVisibleRows() int {
+	maxRows := v.height - 12
+	if maxRows < 5 {
+		maxRows = 5
+	}
+	return maxRows
+}
+
+func (v PodTopViewer) maxScroll() int {
+	if n := len(v.rows) - v.maxVisibleRows(); n > 0 {
+		return n
+	}
+	return 0
+}
+
+// Column widths; the pod name takes what is left
+const (
+	podTopUsageWidth   = 8
+	podTopReqLimWidth  = 14
+	podTopPercentWidth = 6
+)
+
+// podTopColumns is the header of the table. The marker shows the sort column.
+func (v PodTopViewer) podTopColumns(nameWidth int) string {
+	marker := func(column PodTopSort, label string) string {
+		if v.sortBy != column {
+			return label
+		}
+		// Names sort A-Z and the rest highest first, unless flipped
+		ascending := (column == PodTopSortName) != v.reverse
+		if ascending {
+			return label + "▲"
+		}
+		return label + "▼"
+	}
+	return fmt.Sprintf("%-*s %*s %*s %*s %*s %*s %*s %*s %*s",
+		nameWidth, marker(PodTopSortName, "NAME"),
+		podTopUsageWidth, marker(PodTopSortCPU, "CPU"),
+		podTopReqLimWidth, "REQ/LIM",
+		podTopPercentWidth, "%REQ",
+		podTopPercentWidth, "%LIM",
+		podTopUsageWidth, marker(PodTopSortMemory, "MEM"),
+		podTopReqLimWidth, "REQ/LIM",
+		podTopPercentWidth, "%REQ",
+		podTopPercentWidth, marker(PodTopSortMemoryLimit, "%LIM"),
+	)
+}
+
+// podTopRow renders one pod. Pods close to their memory limit are red.
+func podTopRow(u repository.PodUsage, nameWidth int) string {
+	percent := func(p float64, set bool) string {
+		if !set {
+			return "-"
+		}
+		return fmt.Sprintf("%.0f%%", p)
+	}
+	reqLim := func(request, limit int64, format func(int64) string) string {
+		req, lim := "-", "-"
+		if request > 0 {
+			req = format(request)
+		}
+		if limit > 0 {
+			lim = format(limit)
+		}
+		return req + "/" + lim
+	}
+
+	cpu, mem := "-", "-"
+	if u.HasMetrics {
+		cpu, mem = formatMilliCPU(u.CPUMilli), formatBytes(u.MemoryBytes)
+	}
+	row := fmt.Sprintf("%-*s %*s %*s %*s %*s %*s %*s %*s %*s",
+		nameWidth, style.Truncate(u.Name, nameWidth),
+		podTopUsageWidth, cpu,
+		podTopReqLimWidth, reqLim(u.CPURequestMilli, u.CPULimitMilli, formatMilliCPU),
+		podTopPercentWidth, percent(u.CPUPercentOfRequest(), u.HasMetrics && u.CPURequestMilli > 0),
+		podTopPercentWidth, percent(u.CPUPercentOfLimit(), u.HasMetrics && u.CPULimitMilli > 0),
+		podTopUsageWidth, mem,
+		podTopReqLimWidth, reqLim(u.MemoryRequestBytes, u.MemoryLimitBytes, formatBytes),
+		podTopPercentWidth, percent(u.MemoryPercentOfRequest(), u.HasMetrics && u.MemoryRequestBytes > 0),
+		podTopPercentWidth, percent(u.MemoryPercentOfLimit(), u.HasMetrics && u.MemoryLimitBytes > 0),
+	)
+	switch {
+	case u.HasMetrics && u.MemoryPercentOfLimit() >= memoryLimitWarning:
+		return style.StatusError.Render(row)
+	case !u.HasMetrics:
+		return style.StatusMuted.Render(row)
+	}
+	return row
+}
+
+func (v PodTopViewer) View() string {
+	if !v.visible {
+		return ""
+	}
+
+	var content strings.Builder
+	nameWidth := v.width - 16 - 2*podTopUsageWidth - 2*podTopReqLimWidth - 4*podTopPercentWidth - 8
+	if nameWidth < 12 {
+		nameWidth = 12
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Secondary)
+	content.WriteString(headerStyle.Render(v.podTopColumns(nameWidth)))
+	content.WriteString("\n")
+
+	maxRows := v.maxVisibleRows()
+	switch {
+	case !v.loaded:
+		content.WriteString(style.StatusMuted.Render("Loading pod metrics..."))
+		content.WriteString("\n")
+	case errors.Is(v.err, repository.ErrMetricsUnavailable):
+		// Explain instead of failing: most clusters without usage lack metrics-server
+		content.WriteString(style.StatusPending.Render("No pod metrics: metrics-server is not installed or not ready in this cluster."))
+		content.WriteString("\n")
+	case v.err != nil:
+		content.WriteString(style.StatusError.Render("Error: " + v.err.Error()))
+		content.WriteString("\n")
+	case len(v.rows) == 0:
+		content.WriteString(style.StatusMuted.Render("No pods in this namespace"))
+		content.WriteString("\n")
+	default:
+		end := v.scroll + maxRows
+		if end > len(v.rows) {
+			end = len(v.rows)
+		}
+		for _, u := range v.rows[v.scroll:end] {
+			content.WriteString(podTopRow(u, nameWidth))
+			content.WriteString("\n")
+		}
+	}
+
+	// Breadcrumb
+	separatorStyle := lipgloss.NewStyle().Foreground(style.TextMuted)
+	itemStyle := lipgloss.NewStyle().Foreground(style.Primary)
+	infoStyle := lipgloss.NewStyle().Foreground(style.Secondary)
+	breadcrumb := itemStyle.Render(v.namespace) +
+		separatorStyle.Render(" > ") +
+		itemStyle.Render("top") +
+		separatorStyle.Render(" - ") +
+		infoStyle.Render(fmt.Sprintf("[%d pods]", len(v.rows)))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Surface).
+		Padding(0, 1).
+		Width(v.width - 10).
+		Height(v.height - 10)
+
+	scrollInfo := ""
+	if v.maxScroll() > 0 {
+		scrollInfo = fmt.Sprintf("[%d/%d] ", v.scroll+1, v.maxScroll()+1)
+	}
+	footer := style.StatusMuted.Render(scrollInfo + "↑↓:scroll  c:cpu  m:memory  %:mem of limit  n:name  Esc:close")
+
+	return breadcrumb + "\n" + boxStyle.Render(content.String()) + "\n" + footer
+}
+
+// Show opens the viewer for a namespace; rows arrive with SetUsage.
+func (v *PodTopViewer) Show(namespace string) {
+	v.namespace = namespace
+	v.rows = nil
+	v.err = nil
+	v.loaded = false
+	v.scroll = 0
+	v.visible = true
+}
+
+// SetUsage replaces the rows, keeping the sort order and scroll position
+// across refreshes.
+func (v *PodTopViewer) SetUsage(rows []repository.PodUsage, err error) {
+	v.rows = rows
+	v.err = err
+	v.loaded = true
+	v.sortRows()
+	if v.scroll > v.maxScroll() {
+		v.scroll = v.maxScroll()
+	}
+}
+
+// Namespace returns the namespace being shown.
+func (v PodTopViewer) Namespace() string {
+	return v.namespace
+}
+
+func (v *PodTopViewer) Hide() {
+	v.visible = false
+}
+
+func (v PodTopViewer) IsVisible() bool {
+	return v.visible
+}
+
+func (v *PodTopViewer) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
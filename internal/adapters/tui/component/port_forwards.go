@@ -0,0 +1,154 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// PortForwardsViewer lists the port-forwards running in the background and
+// stops the selected one.
+type PortForwardsViewer struct {
+	forwards []repository.PortForward
+	cursor   int
+	visible  bool
+	width    int
+	height   int
+}
+
+// PortForwardsViewerClosed is sent when the viewer is closed
+type PortForwardsViewerClosed struct{}
+
+// StopPortForwardRequest asks app.go to stop a port-forward.
+type StopPortForwardRequest struct {
+	ID int
+}
+
+func NewPortForwardsViewer() PortForwardsViewer {
+	return PortForwardsViewer{}
+}
+
+func (v PortForwardsViewer) Init() tea.Cmd {
+	return nil
+}
+
+func (v PortForwardsViewer) Update(msg tea.Msg) (PortForwardsViewer, tea.Cmd) {
+	if !v.visible {
+		return v, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "F":
+			v.visible = false
+			return v, func() tea.Msg { return PortForwardsViewerClosed{} }
+		case "x", "d", "delete":
+			if pf := v.Selected(); pf != nil {
+				req := StopPortForwardRequest{ID: pf.ID}
+				return v, func() tea.Msg { return req }
+			}
+		case "up", "k":
+			if v.cursor > 0 {
+				v.cursor--
+			}
+		case "down", "j":
+			if v.cursor < len(v.forwards)-1 {
+				v.cursor++
+			}
+		}
+	}
+
+	return v, nil
+}
+
+func (v PortForwardsViewer) View() string {
+	if !v.visible {
+		return ""
+	}
+
+	var content strings.Builder
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Secondary)
+	content.WriteString(headerStyle.Render(fmt.Sprintf("  %-7s %-7s %-40s %-20s %s", "LOCAL", "REMOTE", "TARGET", "NAMESPACE", "AGE")))
+	content.WriteString("\n")
+
+	if len(v.forwards) == 0 {
+		content.WriteString(style.StatusMuted.Render("  No port-forwards running. Start one from the pod actions menu (a)."))
+		content.WriteString("\n")
+	}
+	for i, pf := range v.forwards {
+		target := "pod/" + pf.Pod
+		if pf.Service != "" {
+			target = fmt.Sprintf("svc/%s (%s)", pf.Service, pf.Pod)
+		}
+		row := fmt.Sprintf("%-7d %-7d %-40s %-20s %s",
+			pf.LocalPort, pf.RemotePort, style.Truncate(target, 40), style.Truncate(pf.Namespace, 20), pf.Age())
+		if i == v.cursor {
+			content.WriteString(style.CursorStyle.Render("> " + row))
+		} else {
+			content.WriteString("  " + row)
+		}
+		content.WriteString("\n")
+	}
+
+	separatorStyle := lipgloss.NewStyle().Foreground(style.TextMuted)
+	itemStyle := lipgloss.NewStyle().Foreground(style.Primary)
+	infoStyle := lipgloss.NewStyle().Foreground(style.Secondary)
+	breadcrumb := itemStyle.Render("port-forwards") +
+		separatorStyle.Render(" - ") +
+		infoStyle.Render(fmt.Sprintf("[%d running]", len(v.forwards)))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Surface).
+		Padding(0, 1).
+		Width(v.width - 10).
+		Height(v.height - 10)
+
+	footer := style.StatusMuted.Render("↑↓:select  x:stop forward  Esc:close")
+
+	return breadcrumb + "\n" + boxStyle.Render(content.String()) + "\n" + footer
+}
+
+// Show opens the viewer with the running forwards.
+func (v *PortForwardsViewer) Show(forwards []repository.PortForward) {
+	v.cursor = 0
+	v.visible = true
+	v.SetForwards(forwards)
+}
+
+// SetForwards replaces the listed forwards, keeping the cursor in range.
+func (v *PortForwardsViewer) SetForwards(forwards []repository.PortForward) {
+	v.forwards = forwards
+	if v.cursor >= len(forwards) {
+		v.cursor = len(forwards) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+}
+
+// Selected returns the forward under the cursor, nil when there is none.
+func (v PortForwardsViewer) Selected() *repository.PortForward {
+	if v.cursor < len(v.forwards) {
+		return &v.forwards[v.cursor]
+	}
+	return nil
+}
+
+func (v *PortForwardsViewer) Hide() {
+	v.visible = false
+}
+
+func (v PortForwardsViewer) IsVisible() bool {
+	return v.visible
+}
+
+func (v *PortForwardsViewer) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
@@ -0,0 +1,75 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// RenderProbeRun formats a probe run by hand for the result viewer: the
+// outcome, the raw status and latency, and the start of the body. via is
+// the debug container the probe ran from, "" when it ran in the probed
+// container itself.
+func RenderProbeRun(container, probe string, config *repository.ProbeInfo, result *repository.ProbeRunResult, via string) string {
+	if result == nil {
+		return style.StatusMuted.Render("No probe result available")
+	}
+
+	var b strings.Builder
+	b.WriteString(style.SubtitleStyle.Render("Probe"))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("  %-12s %s probe of %s\n", "Probe:", probe, container))
+	if config != nil {
+		b.WriteString(fmt.Sprintf("  %-12s %s %s, timeout %ds\n", "Checks:", config.Type, repository.ProbeStatus{Config: config}.Target(), max(config.Timeout, 1)))
+	}
+	ranIn := container
+	if via != "" {
+		ranIn = via + " (no probe tools in " + container + ")"
+	}
+	b.WriteString(fmt.Sprintf("  %-12s %s\n", "Ran in:", ranIn))
+	if result.Tool != "" {
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Tool:", result.Tool))
+	}
+	b.WriteString("\n")
+
+	b.WriteString(style.SubtitleStyle.Render("Result"))
+	b.WriteString("\n")
+	outcome := style.StatusError.Render(string(result.Outcome))
+	if result.Outcome == repository.ProbeSucceeded {
+		outcome = style.StatusRunning.Render(string(result.Outcome))
+	}
+	b.WriteString(fmt.Sprintf("  %-12s %s\n", "Outcome:", outcome))
+	switch {
+	case result.Status != "":
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Status:", result.Status))
+	case result.Tool == "exec":
+		b.WriteString(fmt.Sprintf("  %-12s %d\n", "Exit code:", result.StatusCode))
+	case result.StatusCode != 0:
+		b.WriteString(fmt.Sprintf("  %-12s %d\n", "Status:", result.StatusCode))
+	}
+	if result.Latency > 0 {
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Latency:", result.Latency))
+	}
+	if result.Detail != "" && result.Tool != "exec" {
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Error:", style.StatusError.Render(result.Detail)))
+	}
+
+	if result.Body != "" {
+		title := "Body"
+		if result.Tool == "exec" {
+			title = "Output"
+		}
+		if result.Truncated {
+			title += fmt.Sprintf(" (first %d bytes)", repository.ProbeBodyLimit)
+		}
+		b.WriteString("\n")
+		b.WriteString(style.SubtitleStyle.Render(title))
+		b.WriteString("\n")
+		for _, line := range strings.Split(strings.TrimRight(result.Body, "\n"), "\n") {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// RenderPVCDetails formats a PVC with its StorageClass, bound PV and events
+// for display in the result viewer. ProvisioningFailed messages are shown
+// verbatim since they usually carry the real cause of a Pending claim.
+func RenderPVCDetails(d *repository.PVCDetails) string {
+	if d == nil {
+		return style.StatusMuted.Render("No PVC details available")
+	}
+
+	var b strings.Builder
+
+	// Claim
+	b.WriteString(style.SubtitleStyle.Render("Claim"))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("  %-16s %s\n", "Status:", style.GetStatusStyle(d.Status).Render(d.Status)))
+	b.WriteString(fmt.Sprintf("  %-16s %s\n", "Requested:", valueOrNone(d.RequestedSize)))
+	if d.Capacity != "" {
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", "Capacity:", d.Capacity))
+	}
+	b.WriteString(fmt.Sprintf("  %-16s %s\n", "Access Modes:", valueOrNone(strings.Join(d.AccessModes, ", "))))
+	b.WriteString(fmt.Sprintf("  %-16s %s\n", "Volume:", valueOrNone(d.VolumeName)))
+	if d.WaitsForFirstConsumer() {
+		b.WriteString("\n")
+		b.WriteString(style.StatusPending.Render("  WaitForFirstConsumer: will not bind until a pod using it is scheduled"))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	// Storage Class
+	b.WriteString(style.SubtitleStyle.Render("Storage Class"))
+	b.WriteString("\n")
+	switch {
+	case d.StorageClass != nil:
+		sc := d.StorageClass
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", "Name:", sc.Name))
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", "Provisioner:", sc.Provisioner))
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", "Binding Mode:", sc.VolumeBindingMode))
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", "Reclaim Policy:", sc.ReclaimPolicy))
+		b.WriteString(fmt.Sprintf("  %-16s %t\n", "Expansion:", sc.AllowVolumeExpansion))
+	case d.StorageClassName != "":
+		b.WriteString(style.StatusError.Render(fmt.Sprintf("  StorageClass '%s' not found", d.StorageClassName)))
+		b.WriteString("\n")
+	default:
+		b.WriteString(style.StatusMuted.Render("  No storage class (static binding)"))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	// Persistent Volume
+	if d.Volume != nil {
+		pv := d.Volume
+		b.WriteString(style.SubtitleStyle.Render("Persistent Volume"))
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", "Name:", pv.Name))
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", "Status:", pv.Status))
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", "Capacity:", valueOrNone(pv.Capacity)))
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", "Reclaim Policy:", pv.ReclaimPolicy))
+		if len(pv.NodeAffinity) > 0 {
+			b.WriteString("  Node Affinity:\n")
+			for _, term := range pv.NodeAffinity {
+				b.WriteString(fmt.Sprintf("    • %s\n", term))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	// Events
+	b.WriteString(style.SubtitleStyle.Render("Events"))
+	b.WriteString("\n")
+	if len(d.Events) == 0 {
+		b.WriteString(style.StatusMuted.Render("  No events"))
+		b.WriteString("\n")
+	}
+	for _, e := range d.Events {
+		typeStyle := style.EventNormal
+		if e.Type == "Warning" {
+			typeStyle = style.EventWarning
+		}
+		b.WriteString(fmt.Sprintf("  %s %-6s %s\n", typeStyle.Render(fmt.Sprintf("%-8s", e.Type)), e.Age, e.Reason))
+		b.WriteString(fmt.Sprintf("    %s\n", e.Message))
+	}
+
+	return b.String()
+}
+
+func valueOrNone(v string) string {
+	if v == "" {
+		return style.StatusMuted.Render("<none>")
+	}
+	return v
+}
@@ -0,0 +1,55 @@
+package component
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RefreshTickMsg is sent by RefreshTicker on every refresh interval.
+type RefreshTickMsg time.Time
+
+// RefreshTicker schedules the periodic refresh and holds its pause state.
+// Every refresh fetch is issued through Tick, so while paused none runs;
+// the ticks go on, and polling resumes on the first one after unpausing.
+type RefreshTicker struct {
+	interval time.Duration
+	paused   bool
+}
+
+// NewRefreshTicker creates a ticker firing every interval.
+func NewRefreshTicker(interval time.Duration) RefreshTicker {
+	return RefreshTicker{interval: interval}
+}
+
+// Next schedules the next RefreshTickMsg.
+func (t RefreshTicker) Next() tea.Cmd {
+	return tea.Tick(t.interval, func(at time.Time) tea.Msg {
+		return RefreshTickMsg(at)
+	})
+}
+
+// Tick runs the fetches of a refresh along with scheduling the next tick.
+// While paused only the next tick is scheduled.
+func (t RefreshTicker) Tick(fetches ...tea.Cmd) tea.Cmd {
+	if t.paused {
+		return t.Next()
+	}
+	return tea.Batch(append(fetches, t.Next())...)
+}
+
+// TogglePause pauses or resumes polling and reports whether it is paused.
+func (t *RefreshTicker) TogglePause() bool {
+	t.paused = !t.paused
+	return t.paused
+}
+
+// Paused reports whether polling is paused.
+func (t RefreshTicker) Paused() bool {
+	return t.paused
+}
+
+// Interval returns the time between refreshes.
+func (t RefreshTicker) Interval() time.Duration {
+	return t.interval
+}
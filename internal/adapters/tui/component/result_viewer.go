@@ -18,6 +18,37 @@ type ResultViewerCopiedMsg struct {
 	Content string // The content that was copied
 }
 
+// ResultLink is a content line that opens another view, such as a related
+// resource in the Resource Details view.
+type ResultLink struct {
+	Line int    // Content line the link is on
+	Kind string // Resource kind (Service, Ingress, ConfigMap)
+	Name string // Resource name
+}
+
+// ResultViewerOpenLinkMsg is sent when Enter is pressed on a selected link.
+type ResultViewerOpenLinkMsg struct {
+	Index int // Position of the link, to select it again with SelectLink
+	Link  ResultLink
+}
+
+// ResultViewerCheckLinkMsg is sent when t (from the pod) or T (from a
+// netshoot debug container) is pressed on a selected Service link, to test
+// whether the Service is reachable, or when t is pressed on a selected
+// Ingress link, to trace its routes to the pods.
+type ResultViewerCheckLinkMsg struct {
+	Index    int
+	Link     ResultLink
+	Netshoot bool
+}
+
+// ResultViewerProjectLinkMsg is sent when p is pressed on a selected
+// ConfigMap or Secret link, to show where its keys land in the pod.
+type ResultViewerProjectLinkMsg struct {
+	Index int
+	Link  ResultLink
+}
+
 // ResultViewer displays command output in a scrollable viewport
 type ResultViewer struct {
 	title      string
@@ -28,6 +59,15 @@ type ResultViewer struct {
 	width      int
 	height     int
 	copyStatus string // Status message after copy
+
+	// YAML mode: syntax highlighting and folding of top-level sections
+	yaml    bool
+	folded  map[string]bool // Folded section headers, kept while the same object is open
+	lineMap []int           // Content line index for each displayed line
+
+	// Links selectable with tab; Enter opens the selected one instead of copying
+	links    []ResultLink
+	selected int // Index into links, -1 when none is selected
 }
 
 func NewResultViewer() ResultViewer {
@@ -51,22 +91,54 @@ func (r ResultViewer) Update(msg tea.Msg) (ResultViewer, tea.Cmd) {
 		case "esc", "q":
 			r.visible = false
 			return r, nil
+		case "tab", "shift+tab":
+			if len(r.links) > 0 {
+				step := 1
+				if msg.String() == "shift+tab" {
+					step = -1
+				}
+				r.SelectLink((r.selected + step + len(r.links)) % len(r.links))
+				return r, nil
+			}
 		case "enter":
+			if r.selected >= 0 {
+				open := ResultViewerOpenLinkMsg{Index: r.selected, Link: r.links[r.selected]}
+				return r, func() tea.Msg { return open }
+			}
 			// Copy content to clipboard (strip ANSI codes for clean markdown)
 			content := stripAnsiCodes(r.content)
-			err := CopyToClipboard(content)
+			copied, err := CopyToClipboard(content)
 			if err == nil {
-				r.copyStatus = "Copied to clipboard!"
+				r.copyStatus = copied.Status()
 			} else {
 				r.copyStatus = "Copy failed: " + err.Error()
 			}
 			return r, nil
+		case "t", "T":
+			if link := r.SelectedLink(); link != nil && link.Kind == "Service" {
+				check := ResultViewerCheckLinkMsg{Index: r.selected, Link: *link, Netshoot: msg.String() == "T"}
+				return r, func() tea.Msg { return check }
+			}
+			if link := r.SelectedLink(); link != nil && link.Kind == "Ingress" && msg.String() == "t" {
+				trace := ResultViewerCheckLinkMsg{Index: r.selected, Link: *link}
+				return r, func() tea.Msg { return trace }
+			}
+		case "p":
+			if link := r.SelectedLink(); link != nil && (link.Kind == "ConfigMap" || link.Kind == "Secret") {
+				project := ResultViewerProjectLinkMsg{Index: r.selected, Link: *link}
+				return r, func() tea.Msg { return project }
+			}
 		case "g":
 			r.viewport.GotoTop()
 			return r, nil
 		case "G":
 			r.viewport.GotoBottom()
 			return r, nil
+		case "z":
+			if r.yaml {
+				r.toggleFold()
+				return r, nil
+			}
 		}
 	}
 
@@ -109,6 +181,19 @@ func (r ResultViewer) View() string {
 	}
 
 	footer := "j/k scroll • g/G top/bottom • enter copy • q/esc close" + scrollInfo
+	if len(r.links) > 0 {
+		footer = "j/k scroll • tab select resource • enter describe/copy • q/esc close" + scrollInfo
+		if link := r.SelectedLink(); link != nil && link.Kind == "Service" {
+			footer = "j/k scroll • tab select resource • enter describe • t/T test service • q/esc close" + scrollInfo
+		} else if link != nil && link.Kind == "Ingress" {
+			footer = "j/k scroll • tab select resource • enter describe • t trace route • q/esc close" + scrollInfo
+		} else if link != nil && (link.Kind == "ConfigMap" || link.Kind == "Secret") {
+			footer = "j/k scroll • tab select resource • enter describe • p keys in pod • q/esc close" + scrollInfo
+		}
+	}
+	if r.yaml {
+		footer = "j/k scroll • g/G top/bottom • z fold • enter copy • q/esc close" + scrollInfo
+	}
 	if r.copyStatus != "" {
 		footer = footer + " - " + lipgloss.NewStyle().Foreground(style.Success).Bold(true).Render(r.copyStatus)
 	}
@@ -124,22 +209,167 @@ func (r ResultViewer) View() string {
 }
 
 func (r *ResultViewer) Show(title, content string, width, height int) {
+	r.yaml = false
+	r.folded = nil
+	r.links = nil
+	r.show(title, content, width, height)
+}
+
+// ShowWithLinks displays content in which the given lines can be selected
+// with tab and opened with Enter (see ResultViewerOpenLinkMsg). Enter
+// copies the content while no link is selected.
+func (r *ResultViewer) ShowWithLinks(title, content string, links []ResultLink, width, height int) {
+	r.yaml = false
+	r.folded = nil
+	r.links = links
+	r.show(title, content, width, height)
+}
+
+// SetContentWithLinks replaces the displayed content and its links while
+// keeping the scroll position and the selected link, for content that
+// grows in place such as Resource Details with service check results.
+func (r *ResultViewer) SetContentWithLinks(content string, links []ResultLink) {
+	r.links = links
+	if r.selected >= len(links) {
+		r.selected = -1
+	}
+	r.SetContent(content)
+}
+
+// SelectLink selects the link at index and scrolls it into view.
+func (r *ResultViewer) SelectLink(index int) {
+	if index < 0 || index >= len(r.links) {
+		return
+	}
+	r.selected = index
+	offset := r.viewport.YOffset
+	r.viewport.SetContent(r.render())
+	line := r.links[index].Line
+	if line < offset || line >= offset+r.viewport.Height {
+		offset = max(line-r.viewport.Height/2, 0)
+	}
+	r.viewport.SetYOffset(offset)
+}
+
+// SelectedLink returns the selected link, or nil when none is selected.
+func (r ResultViewer) SelectedLink() *ResultLink {
+	if r.selected < 0 || r.selected >= len(r.links) {
+		return nil
+	}
+	return &r.links[r.selected]
+}
+
+// ShowYAML displays YAML or describe output with syntax highlighting and
+// foldable top-level sections. Reopening the same title while the viewer is
+// still visible keeps the folded sections.
+func (r *ResultViewer) ShowYAML(title, content string, width, height int) {
+	if !r.yaml || !r.visible || r.title != title {
+		r.folded = make(map[string]bool)
+	}
+	r.yaml = true
+	r.links = nil
+	r.show(title, content, width, height)
+}
+
+func (r *ResultViewer) show(title, content string, width, height int) {
 	r.title = title
 	r.content = content // Store content for clipboard copy
 	r.width = width
 	r.height = height
 	r.visible = true
 	r.copyStatus = "" // Clear previous copy status
+	r.selected = -1
 
 	// Initialize viewport
 	viewportHeight := max(height-6, 5)
 	viewportWidth := max(width-6, 20)
 
 	r.viewport = viewport.New(viewportWidth, viewportHeight)
-	r.viewport.SetContent(content)
+	r.viewport.SetContent(r.render())
 	r.ready = true
 }
 
+// SetContent replaces the displayed content while keeping the scroll position.
+// Used to refresh watched content without resetting the view.
+func (r *ResultViewer) SetContent(content string) {
+	r.content = content
+	offset := r.viewport.YOffset
+	r.viewport.SetContent(r.render())
+	r.viewport.SetYOffset(offset)
+}
+
+// render builds the displayed content, applying highlighting and folds in
+// YAML mode and recording which content line each displayed line shows.
+func (r *ResultViewer) render() string {
+	if !r.yaml {
+		r.lineMap = nil
+		if r.selected < 0 || r.selected >= len(r.links) {
+			return r.content
+		}
+		// Mark the selected link with the list cursor
+		lines := strings.Split(r.content, "\n")
+		if line := r.links[r.selected].Line; line < len(lines) {
+			lines[line] = style.CursorStyle.Render("> ") + strings.TrimPrefix(lines[line], "  ")
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	raw := strings.Split(r.content, "\n")
+	highlighted := strings.Split(HighlightYAML(r.content), "\n")
+	regions := yamlFoldRegions(raw)
+	foldMarker := lipgloss.NewStyle().Foreground(style.Muted)
+
+	r.lineMap = make([]int, 0, len(raw))
+	var out []string
+	next := 0
+	for i := 0; i < len(raw); i++ {
+		for next < len(regions) && regions[next].start < i {
+			next++
+		}
+		if next < len(regions) && regions[next].start == i && r.folded[strings.TrimSpace(raw[i])] {
+			region := regions[next]
+			out = append(out, highlighted[i]+foldMarker.Render(" ▸ "+strconv.Itoa(region.end-region.start)+" lines folded"))
+			r.lineMap = append(r.lineMap, i)
+			i = region.end
+			continue
+		}
+		out = append(out, highlighted[i])
+		r.lineMap = append(r.lineMap, i)
+	}
+	return strings.Join(out, "\n")
+}
+
+// toggleFold folds or unfolds the top-level section under the top visible
+// line, then scrolls so the section header is the top line.
+func (r *ResultViewer) toggleFold() {
+	top := r.viewport.YOffset
+	if top < 0 || top >= len(r.lineMap) {
+		return
+	}
+	line := r.lineMap[top]
+	raw := strings.Split(r.content, "\n")
+	for _, region := range yamlFoldRegions(raw) {
+		if line < region.start || line > region.end {
+			continue
+		}
+		header := strings.TrimSpace(raw[region.start])
+		r.folded[header] = !r.folded[header]
+		r.viewport.SetContent(r.render())
+		for i, l := range r.lineMap {
+			if l == region.start {
+				r.viewport.SetYOffset(i)
+				break
+			}
+		}
+		return
+	}
+}
+
+// Title returns the title of the displayed content
+func (r ResultViewer) Title() string {
+	return r.title
+}
+
 func (r *ResultViewer) Hide() {
 	r.visible = false
 }
@@ -209,7 +439,8 @@ func isKnownSection(line string) bool {
 		"Pod Info", "Network", "Services", "Ingresses", "VirtualServices",
 		"Gateways", "Tolerations", "Node Selector", "Volumes", "ConfigMaps Used",
 		"Secrets Used", "Resources", "Ports", "Probes", "Security Context",
-		"Volume Mounts", "Environment Variables",
+		"Volume Mounts", "Environment Variables", "Claim", "Storage Class",
+		"Persistent Volume", "Events", "Summary", "Pods", "Skipped",
 	}
 	for _, s := range sections {
 		if strings.HasPrefix(line, s) {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -34,6 +35,8 @@ type SecretViewer struct {
 	keyLineMap  map[int]int // Maps key index to first line index
 	copied      bool        // Show "copied" feedback
 
+	changes []repository.KeyChange // Keys changed since the Secret was last viewed
+
 	// Action menu and namespace selector
 	mode           SecretViewerMode
 	actionCursor   int      // Action menu cursor
@@ -69,6 +72,22 @@ type SecretCopyResult struct {
 	Err       error
 }
 
+// SecretEditRequest is sent when the user wants to edit the value of the
+// selected key. ResourceVersion is the version the value was read at.
+type SecretEditRequest struct {
+	Namespace       string
+	Name            string
+	Key             string
+	Value           string // Decoded
+	ResourceVersion string
+}
+
+// SecretDeleteRequest is sent when the user wants to delete the Secret
+type SecretDeleteRequest struct {
+	Namespace string
+	Name      string
+}
+
 // SecretCopyProgress is sent during multi-namespace copy to show progress
 type SecretCopyProgress struct {
 	SecretName       string
@@ -121,6 +140,23 @@ func (v SecretViewer) updateNormal(msg tea.KeyMsg) (SecretViewer, tea.Cmd) {
 		v.mode = SecretViewerModeAction
 		v.actionCursor = 0
 		return v, nil
+	case "e":
+		// Edit selected key's value in $EDITOR
+		if v.keyCursor >= 0 && v.keyCursor < len(v.sortedKeys) && v.secret != nil {
+			req := SecretEditRequest{
+				Namespace:       v.namespace,
+				Name:            v.secret.Name,
+				Key:             v.sortedKeys[v.keyCursor],
+				Value:           v.secret.Data[v.sortedKeys[v.keyCursor]],
+				ResourceVersion: v.secret.ResourceVersion,
+			}
+			return v, func() tea.Msg { return req }
+		}
+	case "d":
+		if v.secret != nil {
+			req := SecretDeleteRequest{Namespace: v.namespace, Name: v.secret.Name}
+			return v, func() tea.Msg { return req }
+		}
 	case "up", "k":
 		v.copied = false
 		if v.keyCursor > 0 {
@@ -331,6 +367,9 @@ func (v *SecretViewer) buildLines() {
 
 	if v.secret == nil || len(v.secret.Data) == 0 {
 		v.lines = append(v.lines, style.StatusMuted.Render("No data in this Secret"))
+		if v.secret != nil {
+			v.lines = append(v.lines, configHistoryLines(v.changes, v.secret.Updates, time.Now())...)
+		}
 		return
 	}
 
@@ -350,7 +389,7 @@ func (v *SecretViewer) buildLines() {
 		v.keyLineMap[i] = len(v.lines)
 
 		// Key header (will be highlighted based on selection in View)
-		v.lines = append(v.lines, key)
+		v.lines = append(v.lines, key+keyChangeMarker(v.changes, key))
 
 		// Value with word wrapping (decoded from base64)
 		value := v.secret.Data[key]
@@ -373,6 +412,7 @@ func (v *SecretViewer) buildLines() {
 			v.lines = append(v.lines, "")
 		}
 	}
+	v.lines = append(v.lines, configHistoryLines(v.changes, v.secret.Updates, time.Now())...)
 }
 
 func (v SecretViewer) wrapText(text string, maxWidth int) []string {
@@ -420,7 +460,7 @@ func (v SecretViewer) View() string {
 		separatorStyle.Render(" > ") +
 		itemStyle.Render(v.secret.Name) +
 		separatorStyle.Render(" - ") +
-		infoStyle.Render(fmt.Sprintf("[%s] [%s] [%d keys]", v.secret.Age, v.secret.Type, len(v.secret.Data)))
+		infoStyle.Render(fmt.Sprintf("[%s] [%s] [%d keys]", v.secret.Age, v.secret.Type, len(v.secret.Data))+changesSummary(v.changes))
 	header.WriteString(breadcrumb)
 	header.WriteString("\n")
 
@@ -511,9 +551,9 @@ func (v SecretViewer) View() string {
 
 	if len(v.sortedKeys) > 0 {
 		keyInfo := fmt.Sprintf("[%d/%d]", v.keyCursor+1, len(v.sortedKeys))
-		footer = style.StatusMuted.Render(fmt.Sprintf("%s ↑↓:select  Enter:copy  a:actions  Esc:close", keyInfo)) + copiedIndicator + statusIndicator
+		footer = style.StatusMuted.Render(fmt.Sprintf("%s ↑↓:select  Enter:copy  e:edit  d:delete  a:actions  Esc:close", keyInfo)) + copiedIndicator + statusIndicator
 	} else {
-		footer = style.StatusMuted.Render("a:actions  Esc:close")
+		footer = style.StatusMuted.Render("d:delete  a:actions  Esc:close")
 	}
 
 	result := header.String() + boxedContent + "\n" + footer
@@ -656,14 +696,29 @@ func (v SecretViewer) overlayContent(base, overlay string) string {
 }
 
 func (v *SecretViewer) Show(secret *repository.SecretData, namespace string) {
+	// Stay on the same key and status when the Secret is shown again, e.g. after an edit
+	selectedKey := ""
+	same := v.secret != nil && secret != nil && v.secret.Name == secret.Name && v.namespace == namespace
+	if same && v.keyCursor >= 0 && v.keyCursor < len(v.sortedKeys) {
+		selectedKey = v.sortedKeys[v.keyCursor]
+	}
 	v.secret = secret
 	v.namespace = namespace
 	v.scroll = 0
 	v.keyCursor = 0
 	v.copied = false
 	v.mode = SecretViewerModeNormal
-	v.statusMsg = ""
+	if !same {
+		v.statusMsg = ""
+	}
 	v.buildLines()
+	for i, key := range v.sortedKeys {
+		if key == selectedKey {
+			v.keyCursor = i
+			v.scrollToKey()
+			break
+		}
+	}
 	v.visible = true
 }
 
@@ -705,6 +760,12 @@ func (v *SecretViewer) GetPendingRequest() *SecretCopyRequest {
 	return req
 }
 
+// SetChanges sets the keys that changed since the Secret was last viewed,
+// marked when it is shown next.
+func (v *SecretViewer) SetChanges(changes []repository.KeyChange) {
+	v.changes = changes
+}
+
 // SetStatusMsg sets the status message shown in the footer
 func (v *SecretViewer) SetStatusMsg(msg string) {
 	v.statusMsg = msg
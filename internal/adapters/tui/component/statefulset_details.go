@@ -0,0 +1,99 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// RenderStatefulSetDetails formats the ordinals, claim templates and update
+// strategy of a StatefulSet for the result viewer. Each ordinal shows its
+// pod, whether it runs the old or the new revision, and its PVCs.
+func RenderStatefulSetDetails(d *repository.StatefulSetDetails) string {
+	if d == nil {
+		return style.StatusMuted.Render("No StatefulSet details available")
+	}
+
+	var b strings.Builder
+
+	// Update strategy
+	b.WriteString(style.SubtitleStyle.Render("Update Strategy"))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("  %-20s %s\n", "Strategy:", d.UpdateStrategy))
+	if d.UpdateStrategy == "RollingUpdate" {
+		partition := fmt.Sprintf("%d", d.Partition)
+		if d.Partitioned() {
+			partition = style.StatusPending.Render(fmt.Sprintf("%d (ordinals below %d keep the current revision)", d.Partition, d.Partition))
+		}
+		b.WriteString(fmt.Sprintf("  %-20s %s\n", "Partition:", partition))
+	}
+	b.WriteString(fmt.Sprintf("  %-20s %s\n", "Pod management:", d.PodManagementPolicy))
+	if d.ServiceName != "" {
+		b.WriteString(fmt.Sprintf("  %-20s %s\n", "Service:", d.ServiceName))
+	}
+	b.WriteString(fmt.Sprintf("  %-20s %s\n", "Current revision:", d.CurrentRevision))
+	if d.UpdateRevision != d.CurrentRevision {
+		b.WriteString(fmt.Sprintf("  %-20s %s\n", "Update revision:", style.StatusPending.Render(d.UpdateRevision)))
+	}
+	updated := fmt.Sprintf("%d/%d", d.UpdatedCount(), len(d.Ordinals))
+	if d.UpdatedCount() == len(d.Ordinals) {
+		updated = style.StatusRunning.Render(updated)
+	} else {
+		updated = style.StatusPending.Render(updated)
+	}
+	b.WriteString(fmt.Sprintf("  %-20s %s\n", "Updated ordinals:", updated))
+	b.WriteString("\n")
+
+	// Ordinals
+	b.WriteString(style.SubtitleStyle.Render("Ordinals"))
+	b.WriteString("\n")
+	if len(d.Ordinals) == 0 {
+		b.WriteString(style.StatusMuted.Render("  Scaled to 0"))
+		b.WriteString("\n")
+	}
+	for _, o := range d.Ordinals {
+		status := o.Status
+		switch {
+		case !o.Exists:
+			status = style.StatusError.Render(fmt.Sprintf("%-12s", status))
+		case o.Ready:
+			status = style.StatusRunning.Render(fmt.Sprintf("%-12s", status))
+		default:
+			status = style.StatusPending.Render(fmt.Sprintf("%-12s", status))
+		}
+		revision := style.StatusMuted.Render("-")
+		switch {
+		case o.Updated:
+			revision = style.StatusRunning.Render("new " + o.Revision)
+		case o.Revision != "":
+			revision = style.StatusPending.Render("old " + o.Revision)
+		}
+		b.WriteString(fmt.Sprintf("  %-4d %-24s %s %s\n", o.Ordinal, o.Pod, status, revision))
+		for _, c := range o.Claims {
+			phase := c.Phase
+			if phase == "Bound" {
+				phase = style.StatusRunning.Render(phase)
+			} else {
+				phase = style.StatusError.Render(phase)
+			}
+			b.WriteString(fmt.Sprintf("       %s %s\n", style.StatusMuted.Render("pvc "+c.Name), phase))
+		}
+	}
+
+	// Claim templates
+	if len(d.ClaimTemplates) > 0 {
+		b.WriteString("\n")
+		b.WriteString(style.SubtitleStyle.Render("Volume Claim Templates"))
+		b.WriteString("\n")
+		for _, t := range d.ClaimTemplates {
+			storageClass := t.StorageClass
+			if storageClass == "" {
+				storageClass = "<default>"
+			}
+			b.WriteString(fmt.Sprintf("  %-20s %s, %s, %s\n", t.Name, t.Size, t.AccessModes, storageClass))
+		}
+	}
+	return b.String()
+}
@@ -10,6 +10,7 @@ import (
 // in the application hierarchy (e.g., namespace > pods > pod-name).
 type Breadcrumb struct {
 	items []string
+	badge string // Shown after the path, e.g. "protected"
 	width int
 }
 
@@ -23,6 +24,11 @@ func (b *Breadcrumb) SetItems(items ...string) {
 	b.items = items
 }
 
+// SetBadge sets a short label shown after the path. Empty hides it.
+func (b *Breadcrumb) SetBadge(badge string) {
+	b.badge = badge
+}
+
 // SetWidth sets the available width for rendering.
 func (b *Breadcrumb) SetWidth(width int) {
 	b.width = width
@@ -45,5 +51,9 @@ func (b Breadcrumb) View() string {
 	}
 
 	sep := style.BreadcrumbStyle.Render(" > ")
-	return strings.Join(parts, sep)
+	view := strings.Join(parts, sep)
+	if b.badge != "" {
+		view += "  " + style.BreadcrumbBadgeStyle.Render(b.badge)
+	}
+	return view
 }
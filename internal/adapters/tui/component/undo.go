@@ -0,0 +1,124 @@
+package component
+
+import (
+	"fmt"
+	"time"
+)
+
+// Undo buffer defaults: how many scales are remembered, and for how long
+// each can be undone.
+const (
+	DefaultUndoSize = 10
+	DefaultUndoTTL  = 30 * time.Second
+)
+
+// ScaleUndo is a scale that can be undone by scaling back to From.
+type ScaleUndo struct {
+	Hint MutationHint
+	From int32 // Desired replicas before the scale
+	To   int32
+	At   time.Time // When the scale succeeded
+}
+
+// UndoBuffer remembers the last scales so they can be undone, newest
+// first. An entry expires after its TTL, and is dropped when the workload
+// is scaled by someone else, which a refresh shows as desired replicas
+// other than the ones it was scaled to. A nil *UndoBuffer remembers
+// nothing.
+type UndoBuffer struct {
+	entries []ScaleUndo // Oldest first
+	size    int
+	ttl     time.Duration
+}
+
+// NewUndoBuffer creates a buffer of the last size scales, each undoable for
+// ttl.
+func NewUndoBuffer(size int, ttl time.Duration) *UndoBuffer {
+	return &UndoBuffer{size: max(size, 1), ttl: ttl}
+}
+
+// Push remembers a scale, forgetting the oldest one when the buffer is
+// full.
+func (b *UndoBuffer) Push(u ScaleUndo) {
+	if b == nil {
+		return
+	}
+	b.entries = append(b.entries, u)
+	if len(b.entries) > b.size {
+		b.entries = b.entries[len(b.entries)-b.size:]
+	}
+}
+
+// Last returns the newest scale that can still be undone at now.
+func (b *UndoBuffer) Last(now time.Time) (ScaleUndo, bool) {
+	if b == nil {
+		return ScaleUndo{}, false
+	}
+	b.expire(now)
+	if len(b.entries) == 0 {
+		return ScaleUndo{}, false
+	}
+	return b.entries[len(b.entries)-1], true
+}
+
+// Pop removes and returns the newest scale that can still be undone at
+// now.
+func (b *UndoBuffer) Pop(now time.Time) (ScaleUndo, bool) {
+	u, ok := b.Last(now)
+	if ok {
+		b.entries = b.entries[:len(b.entries)-1]
+	}
+	return u, ok
+}
+
+// Remaining is how long u can still be undone at now.
+func (b *UndoBuffer) Remaining(u ScaleUndo, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	return max(u.At.Add(b.ttl).Sub(now), 0)
+}
+
+// Observe checks the desired replicas a refresh shows for a workload.
+// When they differ from the ones the newest scale of the workload set, it
+// was scaled outside k1s and undoing would overwrite that, so every scale
+// of the workload is forgotten.
+func (b *UndoBuffer) Observe(hint MutationHint, desired int32) {
+	if b == nil {
+		return
+	}
+	for i := len(b.entries) - 1; i >= 0; i-- {
+		if b.entries[i].Hint != hint {
+			continue
+		}
+		if b.entries[i].To == desired {
+			return
+		}
+		break
+	}
+	kept := b.entries[:0]
+	for _, u := range b.entries {
+		if u.Hint != hint {
+			kept = append(kept, u)
+		}
+	}
+	b.entries = kept
+}
+
+// Message describes u for the status bar at now, e.g.
+// "Scaled payments 5→2 (u to undo, 30s)".
+func (b *UndoBuffer) Message(u ScaleUndo, now time.Time) string {
+	left := (b.Remaining(u, now) + time.Second - 1).Truncate(time.Second)
+	return fmt.Sprintf("Scaled %s %d→%d (u to undo, %s)", u.Hint.Name, u.From, u.To, left)
+}
+
+// expire forgets the scales that can no longer be undone at now.
+func (b *UndoBuffer) expire(now time.Time) {
+	kept := b.entries[:0]
+	for _, u := range b.entries {
+		if b.Remaining(u, now) > 0 {
+			kept = append(kept, u)
+		}
+	}
+	b.entries = kept
+}
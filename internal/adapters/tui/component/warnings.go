@@ -0,0 +1,229 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// WarningsViewer lists the recent warning events of a namespace grouped by
+// the object they are about, and jumps to the selected object. An object
+// that can't be opened, because it was deleted or has no pods, shows its
+// events instead.
+type WarningsViewer struct {
+	namespace string
+	groups    []repository.ObjectWarnings
+	cursor    int
+	detail    *repository.ObjectWarnings // Object whose events are shown; nil for the list
+	note      string                     // Why the detail is shown instead of the object
+	visible   bool
+	width     int
+	height    int
+}
+
+// WarningsViewerClosed is sent when the viewer is closed
+type WarningsViewerClosed struct{}
+
+// JumpToWarningRequest asks app.go to open the object of a warning group.
+type JumpToWarningRequest struct {
+	Namespace string
+	Object    string
+}
+
+func NewWarningsViewer() WarningsViewer {
+	return WarningsViewer{}
+}
+
+func (v WarningsViewer) Init() tea.Cmd {
+	return nil
+}
+
+func (v WarningsViewer) Update(msg tea.Msg) (WarningsViewer, tea.Cmd) {
+	if !v.visible {
+		return v, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if v.detail != nil {
+			switch msg.String() {
+			case "esc", "backspace", "left", "h":
+				v.detail = nil
+				v.note = ""
+			case "q", "!":
+				v.visible = false
+				return v, func() tea.Msg { return WarningsViewerClosed{} }
+			}
+			return v, nil
+		}
+		switch msg.String() {
+		case "esc", "q", "!":
+			v.visible = false
+			return v, func() tea.Msg { return WarningsViewerClosed{} }
+		case "enter":
+			if g := v.Selected(); g != nil {
+				req := JumpToWarningRequest{Namespace: v.namespace, Object: g.Object}
+				return v, func() tea.Msg { return req }
+			}
+		case "e":
+			if g := v.Selected(); g != nil {
+				v.ShowDetail(g.Object, "")
+			}
+		case "up", "k":
+			if v.cursor > 0 {
+				v.cursor--
+			}
+		case "down", "j":
+			if v.cursor < len(v.groups)-1 {
+				v.cursor++
+			}
+		}
+	}
+
+	return v, nil
+}
+
+func (v WarningsViewer) View() string {
+	if !v.visible {
+		return ""
+	}
+
+	separatorStyle := lipgloss.NewStyle().Foreground(style.TextMuted)
+	itemStyle := lipgloss.NewStyle().Foreground(style.Primary)
+	infoStyle := lipgloss.NewStyle().Foreground(style.Secondary)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Secondary)
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Surface).
+		Padding(0, 1).
+		Width(v.width - 10).
+		Height(v.height - 10)
+	msgWidth := v.width - 50
+	if msgWidth < 20 {
+		msgWidth = 20
+	}
+
+	var content strings.Builder
+	var breadcrumb, footer string
+	if v.detail != nil {
+		if v.note != "" {
+			content.WriteString(style.StatusMuted.Render("  " + v.note))
+			content.WriteString("\n\n")
+		}
+		content.WriteString(headerStyle.Render(fmt.Sprintf("  %-6s %-6s %-22s %s", "AGE", "COUNT", "REASON", "MESSAGE")))
+		content.WriteString("\n")
+		for _, e := range v.detail.Events {
+			content.WriteString(fmt.Sprintf("  %-6s %-6d %-22s %s\n",
+				e.Age, e.Count, style.Truncate(e.Reason, 22), style.Truncate(e.Message, msgWidth)))
+		}
+		breadcrumb = itemStyle.Render("warnings") +
+			separatorStyle.Render(" > ") +
+			infoStyle.Render(v.detail.Object)
+		footer = style.StatusMuted.Render("Esc:back  q:close")
+		return breadcrumb + "\n" + boxStyle.Render(content.String()) + "\n" + footer
+	}
+
+	content.WriteString(headerStyle.Render(fmt.Sprintf("  %-40s %-6s %-6s %s", "OBJECT", "COUNT", "LAST", "REASONS")))
+	content.WriteString("\n")
+	if len(v.groups) == 0 {
+		content.WriteString(style.StatusMuted.Render(fmt.Sprintf("  No warning events in the last %s.", formatWindow())))
+		content.WriteString("\n")
+	}
+	for i, g := range v.groups {
+		row := fmt.Sprintf("%-40s %-6d %-6s %s",
+			style.Truncate(g.Object, 40), g.Count, g.Events[0].Age, style.Truncate(strings.Join(g.Reasons, ", "), msgWidth))
+		if i == v.cursor {
+			content.WriteString(style.CursorStyle.Render("> " + row))
+			content.WriteString("\n")
+			// The latest message says what the warning is about
+			content.WriteString(style.StatusMuted.Render("    " + style.Truncate(g.Events[0].Message, v.width-20)))
+		} else {
+			content.WriteString("  " + row)
+		}
+		content.WriteString("\n")
+	}
+
+	breadcrumb = itemStyle.Render("warnings") +
+		separatorStyle.Render(" - ") +
+		infoStyle.Render(v.namespace) +
+		separatorStyle.Render(" - ") +
+		infoStyle.Render(fmt.Sprintf("[%d objects, last %s]", len(v.groups), formatWindow()))
+	footer = style.StatusMuted.Render("↑↓:select  Enter:go to pod/workload  e:events  Esc:close")
+
+	return breadcrumb + "\n" + boxStyle.Render(content.String()) + "\n" + footer
+}
+
+// formatWindow is repository.RecentWarningsWindow as shown, e.g. "15m".
+func formatWindow() string {
+	return strings.TrimSuffix(repository.RecentWarningsWindow.String(), "0s")
+}
+
+// Show opens the viewer with the warnings of a namespace.
+func (v *WarningsViewer) Show(namespace string, events []repository.EventInfo) {
+	v.cursor = 0
+	v.detail = nil
+	v.note = ""
+	v.visible = true
+	v.SetWarnings(namespace, events)
+}
+
+// SetWarnings replaces the listed warnings, keeping the cursor on the same
+// object when it is still listed.
+func (v *WarningsViewer) SetWarnings(namespace string, events []repository.EventInfo) {
+	var selected string
+	if g := v.Selected(); g != nil && namespace == v.namespace {
+		selected = g.Object
+	}
+	v.namespace = namespace
+	v.groups = repository.GroupWarningsByObject(events)
+	v.cursor = 0
+	for i, g := range v.groups {
+		if g.Object == selected {
+			v.cursor = i
+		}
+	}
+	if v.detail != nil {
+		for i := range v.groups {
+			if v.groups[i].Object == v.detail.Object {
+				v.detail = &v.groups[i]
+			}
+		}
+	}
+}
+
+// ShowDetail shows the events of an object instead of the list, with a
+// note on why, e.g. that the object was deleted.
+func (v *WarningsViewer) ShowDetail(object, note string) {
+	for i := range v.groups {
+		if v.groups[i].Object == object {
+			v.detail = &v.groups[i]
+			v.note = note
+			return
+		}
+	}
+}
+
+// Selected returns the group under the cursor, nil when there is none.
+func (v WarningsViewer) Selected() *repository.ObjectWarnings {
+	if v.cursor < len(v.groups) {
+		return &v.groups[v.cursor]
+	}
+	return nil
+}
+
+func (v *WarningsViewer) Hide() {
+	v.visible = false
+}
+
+func (v WarningsViewer) IsVisible() bool {
+	return v.visible
+}
+
+func (v *WarningsViewer) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
@@ -0,0 +1,196 @@
+package component
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// bellOutput is where the bell and desktop notification are written: the
+// terminal
+var bellOutput io.Writer = os.Stdout
+
+// RingBell rings the terminal bell and asks the terminal for a desktop
+// notification with message, through the OSC 9 sequence iTerm2, WezTerm,
+// kitty and others understand; terminals that don't just ignore it.
+func RingBell(message string) error {
+	_, err := io.WriteString(bellOutput, "\a"+osc9Sequence(message, os.Getenv))
+	return err
+}
+
+// osc9Sequence is the escape sequence of a desktop notification, with the
+// control characters of message dropped so it can't end the sequence early.
+func osc9Sequence(message string, getenv func(string) string) string {
+	message = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, message)
+	return terminalPassthrough("\x1b]9;"+message+"\a", getenv)
+}
+
+// PodWatchActions returns the "watch" actions of a pod, which notify when
+// it becomes Ready, offered while it isn't, or on its next Warning event.
+func PodWatchActions(ready bool) []PodActionItem {
+	var items []PodActionItem
+	if !ready {
+		items = append(items, PodActionItem{Label: "Notify when Ready", Description: "bell and desktop notification", Action: "watch", Target: string(repository.WatchReady)})
+	}
+	return append(items, PodActionItem{Label: "Notify on next warning", Description: "bell and desktop notification", Action: "watch", Target: string(repository.WatchWarning)})
+}
+
+// WorkloadWatchActions returns the "watch" actions of a workload, which
+// notify when all its pods are Ready or on the next Warning event about it
+// or its pods.
+func WorkloadWatchActions() []WorkloadActionItem {
+	return []WorkloadActionItem{
+		{Label: "Notify when Ready", Description: "all replicas ready: bell and desktop notification", Action: "watch", Condition: repository.WatchReady},
+		{Label: "Notify on next warning", Description: "bell and desktop notification", Action: "watch", Condition: repository.WatchWarning},
+	}
+}
+
+// WatchesViewer lists the watches waiting for their condition and cancels
+// the selected one.
+type WatchesViewer struct {
+	watches []repository.Watch
+	cursor  int
+	visible bool
+	width   int
+	height  int
+}
+
+// WatchesViewerClosed is sent when the viewer is closed
+type WatchesViewerClosed struct{}
+
+// CancelWatchRequest asks app.go to cancel a watch.
+type CancelWatchRequest struct {
+	ID int
+}
+
+func NewWatchesViewer() WatchesViewer {
+	return WatchesViewer{}
+}
+
+func (v WatchesViewer) Init() tea.Cmd {
+	return nil
+}
+
+func (v WatchesViewer) Update(msg tea.Msg) (WatchesViewer, tea.Cmd) {
+	if !v.visible {
+		return v, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "B":
+			v.visible = false
+			return v, func() tea.Msg { return WatchesViewerClosed{} }
+		case "x", "d", "delete":
+			if w := v.Selected(); w != nil {
+				req := CancelWatchRequest{ID: w.ID}
+				return v, func() tea.Msg { return req }
+			}
+		case "up", "k":
+			if v.cursor > 0 {
+				v.cursor--
+			}
+		case "down", "j":
+			if v.cursor < len(v.watches)-1 {
+				v.cursor++
+			}
+		}
+	}
+
+	return v, nil
+}
+
+func (v WatchesViewer) View() string {
+	if !v.visible {
+		return ""
+	}
+
+	var content strings.Builder
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Secondary)
+	content.WriteString(headerStyle.Render(fmt.Sprintf("  %-14s %-40s %-20s %s", "CONDITION", "TARGET", "NAMESPACE", "AGE")))
+	content.WriteString("\n")
+
+	if len(v.watches) == 0 {
+		content.WriteString(style.StatusMuted.Render("  No watches set. Set one from the pod (a) or workload actions."))
+		content.WriteString("\n")
+	}
+	for i, w := range v.watches {
+		row := fmt.Sprintf("%-14s %-40s %-20s %s",
+			w.Condition.Label(), style.Truncate(w.TargetName(), 40), style.Truncate(w.Target.Namespace, 20), w.Age())
+		if i == v.cursor {
+			content.WriteString(style.CursorStyle.Render("> " + row))
+		} else {
+			content.WriteString("  " + row)
+		}
+		content.WriteString("\n")
+	}
+
+	separatorStyle := lipgloss.NewStyle().Foreground(style.TextMuted)
+	itemStyle := lipgloss.NewStyle().Foreground(style.Primary)
+	infoStyle := lipgloss.NewStyle().Foreground(style.Secondary)
+	breadcrumb := itemStyle.Render("watches") +
+		separatorStyle.Render(" - ") +
+		infoStyle.Render(fmt.Sprintf("[%d waiting]", len(v.watches)))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Surface).
+		Padding(0, 1).
+		Width(v.width - 10).
+		Height(v.height - 10)
+
+	footer := style.StatusMuted.Render("↑↓:select  x:cancel watch  Esc:close")
+
+	return breadcrumb + "\n" + boxStyle.Render(content.String()) + "\n" + footer
+}
+
+// Show opens the viewer with the watches set.
+func (v *WatchesViewer) Show(watches []repository.Watch) {
+	v.cursor = 0
+	v.visible = true
+	v.SetWatches(watches)
+}
+
+// SetWatches replaces the listed watches, keeping the cursor in range.
+func (v *WatchesViewer) SetWatches(watches []repository.Watch) {
+	v.watches = watches
+	if v.cursor >= len(watches) {
+		v.cursor = len(watches) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+}
+
+// Selected returns the watch under the cursor, nil when there is none.
+func (v WatchesViewer) Selected() *repository.Watch {
+	if v.cursor < len(v.watches) {
+		return &v.watches[v.cursor]
+	}
+	return nil
+}
+
+func (v *WatchesViewer) Hide() {
+	v.visible = false
+}
+
+func (v WatchesViewer) IsVisible() bool {
+	return v.visible
+}
+
+func (v *WatchesViewer) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
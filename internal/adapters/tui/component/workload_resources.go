@@ -0,0 +1,167 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// ResourcesAction lists the requests and limits of the workload's
+// containers next to their usage, to view or change them
+func ResourcesAction() WorkloadActionItem {
+	return WorkloadActionItem{Label: "Resources", Description: "requests/limits vs usage, and right-size them", Action: "resources"}
+}
+
+// ContainerResourceActions opens the table of requests, limits and usage
+// of a workload's containers, then lists the containers, each opening its
+// requests and limits for editing.
+func ContainerResourceActions(namespace, kind, name string, containers []repository.ContainerResources) []WorkloadActionItem {
+	items := []WorkloadActionItem{{Label: "Usage table", Description: "requests/limits vs usage, restarts, OOM kills", Action: "resources-table"}}
+	for i, c := range containers {
+		label := "Edit " + c.Container
+		if c.Init {
+			label += " (init)"
+		}
+		items = append(items, WorkloadActionItem{
+			Label:       label,
+			Description: FormatResourceInput(c.Requests, c.Limits),
+			Action:      "set-resources",
+			Container:   c.Container,
+			Resources:   &containers[i],
+		})
+	}
+	command := fmt.Sprintf("kubectl set resources %s/%s -n %s -c CONTAINER --requests=cpu=,memory= --limits=cpu=,memory=", strings.ToLower(kind), name, namespace)
+	return append(items, WorkloadActionItem{Label: "Copy set resources command", Action: "copy", Command: command})
+}
+
+// FormatResourceInput writes requests and limits as typed in the resources
+// prompt, "cpu=100m/500m memory=128Mi/256Mi", request then limit, with "-"
+// for none.
+func FormatResourceInput(requests, limits repository.ResourceAmounts) string {
+	amount := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	return fmt.Sprintf("cpu=%s/%s memory=%s/%s", amount(requests.CPU), amount(limits.CPU), amount(requests.Memory), amount(limits.Memory))
+}
+
+// ParseResourceInput reads requests and limits typed in the resources
+// prompt (see FormatResourceInput), starting from the current ones: a
+// resource left out keeps its values, and "-" or nothing removes one.
+// Quantities are checked by repository.ValidateResources.
+func ParseResourceInput(input string, requests, limits repository.ResourceAmounts) (repository.ResourceAmounts, repository.ResourceAmounts, error) {
+	for _, field := range strings.Fields(input) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return requests, limits, fmt.Errorf("%q is not resource=request/limit", field)
+		}
+		request, limit, hasLimit := strings.Cut(value, "/")
+		amount := func(s string) string {
+			if s == "-" {
+				return ""
+			}
+			return s
+		}
+		var req, lim *string
+		switch strings.ToLower(key) {
+		case "cpu":
+			req, lim = &requests.CPU, &limits.CPU
+		case "memory", "mem":
+			req, lim = &requests.Memory, &limits.Memory
+		default:
+			return requests, limits, fmt.Errorf("unknown resource %q, want cpu or memory", key)
+		}
+		*req = amount(request)
+		if hasLimit {
+			*lim = amount(limit)
+		}
+	}
+	return requests, limits, repository.ValidateResources(requests, limits)
+}
+
+// ResourceChanges lists the requests and limits that differ, one
+// "cpu request: 100m → 250m" line each, "none" standing for unset.
+func ResourceChanges(oldRequests, oldLimits, requests, limits repository.ResourceAmounts) []string {
+	var changes []string
+	for _, c := range []struct {
+		name     string
+		old, new string
+	}{
+		{"cpu request", oldRequests.CPU, requests.CPU},
+		{"cpu limit", oldLimits.CPU, limits.CPU},
+		{"memory request", oldRequests.Memory, requests.Memory},
+		{"memory limit", oldLimits.Memory, limits.Memory},
+	} {
+		if c.old == c.new {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s → %s", c.name, orNone(c.old), orNone(c.new)))
+	}
+	return changes
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// RenderWorkloadResources formats the requests and limits of a workload's
+// containers for the result viewer, next to the highest usage among its
+// pods and their restarts and OOM kills. Memory usage is red above
+// repository.MemoryHeadroomWarnPercent of the limit.
+func RenderWorkloadResources(containers []repository.ContainerResources) string {
+	if len(containers) == 0 {
+		return style.StatusMuted.Render("No containers in the pod template")
+	}
+
+	name := len("CONTAINER")
+	for _, c := range containers {
+		name = max(name, len(c.Container)+len(" (init)"))
+	}
+	var b strings.Builder
+	b.WriteString(style.SubtitleStyle.Render(fmt.Sprintf("%-*s  %-15s %-8s %-15s %-8s %-8s %s",
+		name, "CONTAINER", "CPU REQ/LIM", "CPU USE", "MEM REQ/LIM", "MEM USE", "RESTARTS", "OOM")))
+	b.WriteString("\n")
+	sampled := false
+	for _, c := range containers {
+		label := c.Container
+		if c.Init {
+			label += " (init)"
+		}
+		cpuUse, memUse := "-", "-"
+		if c.Sampled > 0 {
+			sampled = true
+			cpuUse = formatMilliCPU(c.CPUMilli)
+			memUse = formatBytes(c.MemoryBytes)
+		}
+		memUse = fmt.Sprintf("%-8s", memUse)
+		if limit := repository.MemoryBytes(c.Limits.Memory); limit > 0 && c.Sampled > 0 &&
+			float64(c.MemoryBytes)*100/float64(limit) >= repository.MemoryHeadroomWarnPercent {
+			memUse = style.StatusError.Render(memUse)
+		}
+		oom := fmt.Sprintf("%d", c.OOMKills)
+		if c.OOMKills > 0 {
+			oom = style.StatusError.Render(oom)
+		}
+		b.WriteString(fmt.Sprintf("%-*s  %-15s %-8s %-15s %s %-8d %s\n",
+			name, label,
+			orNone(c.Requests.CPU)+"/"+orNone(c.Limits.CPU), cpuUse,
+			orNone(c.Requests.Memory)+"/"+orNone(c.Limits.Memory), memUse,
+			c.Restarts, oom))
+	}
+	b.WriteString("\n")
+	if sampled {
+		b.WriteString(style.StatusMuted.Render("Usage is the highest among the workload's pods, as metrics-server last sampled it."))
+	} else {
+		b.WriteString(style.StatusMuted.Render("No usage: metrics-server is not available or hasn't sampled the pods yet."))
+	}
+	b.WriteString("\n")
+	b.WriteString(style.StatusMuted.Render("OOM counts pods whose container was last killed for memory."))
+	return b.String()
+}
@@ -0,0 +1,284 @@
+package component
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/tui/style"
+)
+
+// yamlTokenKind identifies how a piece of a YAML line is highlighted.
+type yamlTokenKind int
+
+const (
+	yamlText    yamlTokenKind = iota // Whitespace, plain scalars, punctuation
+	yamlKey                          // Mapping key
+	yamlString                       // Quoted string or block scalar content
+	yamlNumber                       // Integer or float scalar
+	yamlComment                      // # comment to end of line
+)
+
+// yamlToken is a slice of a line. Concatenating a line's tokens always
+// reproduces the original line exactly.
+type yamlToken struct {
+	kind yamlTokenKind
+	text string
+}
+
+// yamlFoldRegion is a top-level section spanning lines start..end (inclusive).
+// The start line is the section header, e.g. "metadata:" or "Events:".
+type yamlFoldRegion struct {
+	start int
+	end   int
+}
+
+// HighlightYAML colors keys, strings, numbers and comments in YAML-like text
+// such as kubectl get -o yaml or kubectl describe output. It is a line
+// tokenizer, not a parser: anything it does not recognize is left as is and
+// the number of lines never changes.
+func HighlightYAML(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, tokens := range tokenizeYAML(lines) {
+		var b strings.Builder
+		for _, t := range tokens {
+			b.WriteString(renderYAMLToken(t))
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderYAMLToken(t yamlToken) string {
+	if strings.TrimSpace(t.text) == "" {
+		return t.text
+	}
+	// Styles are built per token so they follow the active theme
+	switch t.kind {
+	case yamlKey:
+		return lipgloss.NewStyle().Foreground(style.Secondary).Render(t.text)
+	case yamlString:
+		return lipgloss.NewStyle().Foreground(style.Success).Render(t.text)
+	case yamlNumber:
+		return lipgloss.NewStyle().Foreground(style.Warning).Render(t.text)
+	case yamlComment:
+		return lipgloss.NewStyle().Foreground(style.Muted).Italic(true).Render(t.text)
+	default:
+		return t.text
+	}
+}
+
+// tokenizeYAML tokenizes each line, tracking block scalars (| and >) so
+// their content is treated as a string rather than parsed for keys.
+func tokenizeYAML(lines []string) [][]yamlToken {
+	result := make([][]yamlToken, len(lines))
+	blockIndent := -1 // Indent of the key that opened a block scalar
+
+	for i, line := range lines {
+		indent := leadingIndent(line)
+		if blockIndent >= 0 {
+			if strings.TrimSpace(line) == "" || indent > blockIndent {
+				result[i] = []yamlToken{{kind: yamlText, text: line[:indent]}, {kind: yamlString, text: line[indent:]}}
+				continue
+			}
+			blockIndent = -1
+		}
+
+		tokens := tokenizeYAMLLine(line)
+		if opensBlockScalar(tokens) {
+			blockIndent = indent
+		}
+		result[i] = tokens
+	}
+	return result
+}
+
+// tokenizeYAMLLine splits a single line into tokens.
+func tokenizeYAMLLine(line string) []yamlToken {
+	var tokens []yamlToken
+	indent := leadingIndent(line)
+	if indent > 0 {
+		tokens = append(tokens, yamlToken{kind: yamlText, text: line[:indent]})
+	}
+	rest := line[indent:]
+
+	// List item markers, possibly nested ("- - value")
+	for strings.HasPrefix(rest, "- ") || rest == "-" {
+		n := 1
+		for n < len(rest) && rest[n] == ' ' {
+			n++
+		}
+		tokens = append(tokens, yamlToken{kind: yamlText, text: rest[:n]})
+		rest = rest[n:]
+	}
+
+	if strings.HasPrefix(rest, "#") {
+		return append(tokens, yamlToken{kind: yamlComment, text: rest})
+	}
+
+	if idx := findKeySeparator(rest); idx > 0 {
+		tokens = append(tokens,
+			yamlToken{kind: yamlKey, text: rest[:idx]},
+			yamlToken{kind: yamlText, text: ":"},
+		)
+		rest = rest[idx+1:]
+	}
+
+	return append(tokens, tokenizeYAMLValue(rest)...)
+}
+
+// tokenizeYAMLValue tokenizes the value part of a line (after "key:" or "- ").
+func tokenizeYAMLValue(value string) []yamlToken {
+	var tokens []yamlToken
+	trimmed := strings.TrimLeft(value, " \t")
+	if lead := len(value) - len(trimmed); lead > 0 {
+		tokens = append(tokens, yamlToken{kind: yamlText, text: value[:lead]})
+	}
+	if trimmed == "" {
+		return tokens
+	}
+	if strings.HasPrefix(trimmed, "#") {
+		return append(tokens, yamlToken{kind: yamlComment, text: trimmed})
+	}
+
+	if trimmed[0] == '"' || trimmed[0] == '\'' {
+		end := closingQuote(trimmed)
+		tokens = append(tokens, yamlToken{kind: yamlString, text: trimmed[:end]})
+		if end < len(trimmed) {
+			tokens = append(tokens, tokenizeYAMLValue(trimmed[end:])...)
+		}
+		return tokens
+	}
+
+	scalar, comment := trimmed, ""
+	if idx := strings.Index(trimmed, " #"); idx >= 0 {
+		scalar, comment = trimmed[:idx], trimmed[idx:]
+	}
+	kind := yamlText
+	if isYAMLNumber(strings.TrimSpace(scalar)) {
+		kind = yamlNumber
+	}
+	tokens = append(tokens, yamlToken{kind: kind, text: scalar})
+	if comment != "" {
+		tokens = append(tokens, yamlToken{kind: yamlComment, text: comment})
+	}
+	return tokens
+}
+
+// findKeySeparator returns the index of the ':' ending a mapping key, or -1.
+// The colon must be followed by a space or end the line and must not be
+// inside quotes, so URLs and times in values are not mistaken for keys.
+func findKeySeparator(s string) int {
+	if s == "" || s[0] == '#' {
+		return -1
+	}
+	start := 0
+	if s[0] == '"' || s[0] == '\'' {
+		start = closingQuote(s)
+	}
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case ':':
+			if i+1 == len(s) || s[i+1] == ' ' || s[i+1] == '\t' {
+				return i
+			}
+		case ' ':
+			if i+1 < len(s) && s[i+1] == '#' {
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+// closingQuote returns the index just past the quote closing s[0].
+// An unterminated string runs to the end of the line.
+func closingQuote(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		if quote == '"' && s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// opensBlockScalar reports whether the line ends with a | or > indicator.
+func opensBlockScalar(tokens []yamlToken) bool {
+	for i := len(tokens) - 1; i >= 0; i-- {
+		t := tokens[i]
+		text := strings.TrimSpace(t.text)
+		if text == "" || t.kind == yamlComment {
+			continue
+		}
+		if t.kind != yamlText || (text[0] != '|' && text[0] != '>') {
+			return false
+		}
+		return strings.Trim(text[1:], "+-0123456789") == ""
+	}
+	return false
+}
+
+func isYAMLNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return true
+	}
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil && !strings.ContainsAny(s, "nN") // Reject "NaN", "Inf"
+}
+
+func leadingIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// yamlFoldRegions finds top-level sections that can be folded: an unindented
+// "key:" line with no inline value, followed by indented lines (or top-level
+// list items). Trailing blank lines are not part of a region.
+func yamlFoldRegions(lines []string) []yamlFoldRegion {
+	var regions []yamlFoldRegion
+	tokenized := tokenizeYAML(lines)
+
+	for i := 0; i < len(lines); i++ {
+		if !isYAMLSectionHeader(lines[i], tokenized[i]) {
+			continue
+		}
+		end := i
+		for j := i + 1; j < len(lines); j++ {
+			line := lines[j]
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if leadingIndent(line) == 0 && !strings.HasPrefix(line, "- ") && line != "-" {
+				break
+			}
+			end = j
+		}
+		if end > i {
+			regions = append(regions, yamlFoldRegion{start: i, end: end})
+			i = end
+		}
+	}
+	return regions
+}
+
+func isYAMLSectionHeader(line string, tokens []yamlToken) bool {
+	if line == "" || leadingIndent(line) > 0 || len(tokens) < 2 {
+		return false
+	}
+	if tokens[0].kind != yamlKey {
+		return false
+	}
+	for _, t := range tokens[2:] {
+		if t.kind != yamlComment && strings.TrimSpace(t.text) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains diffing ConfigMaps and Secrets against the version last shown.
+package tui
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// configVersion is the data of a ConfigMap or Secret as last shown in the
+// session. Secret values are kept as hashes, which is all a diff of them,
+// reported only as changed, needs.
+type configVersion struct {
+	resourceVersion string
+	data            map[string]string
+}
+
+// configChanges returns the keys of a ConfigMap or Secret that changed
+// since it was last shown in the session, and remembers this version for
+// the next time. The first view of an object, or one whose resourceVersion
+// didn't move, has no changes.
+func (m *Model) configChanges(secret bool, namespace, name, resourceVersion string, data map[string]string) []repository.KeyChange {
+	kind := "configmap"
+	if secret {
+		kind = "secret"
+		hashed := make(map[string]string, len(data))
+		for k, v := range data {
+			sum := sha256.Sum256([]byte(v))
+			hashed[k] = hex.EncodeToString(sum[:])
+		}
+		data = hashed
+	}
+	key := m.k8sClient.Context() + "/" + namespace + "/" + kind + "/" + name
+	if m.configVersions == nil {
+		m.configVersions = make(map[string]configVersion)
+	}
+	prev, seen := m.configVersions[key]
+	if seen && prev.resourceVersion == resourceVersion {
+		return nil
+	}
+	m.configVersions[key] = configVersion{resourceVersion: resourceVersion, data: data}
+	if !seen {
+		return nil
+	}
+	return repository.DiffConfigData(prev.data, data, secret)
+}
@@ -0,0 +1,92 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains running CronJobs manually and their run history.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+)
+
+// Polling for the pod of a triggered Job: once a second, for up to a minute.
+const (
+	jobPodPollInterval = time.Second
+	jobPodPollAttempts = 60
+)
+
+// requestTriggerCronJob asks for confirmation before running a CronJob
+// now. Suspended CronJobs can be run too, with a warning.
+func (m *Model) requestTriggerCronJob(workload *repository.WorkloadInfo) tea.Cmd {
+	message := fmt.Sprintf("Create a Job from '%s' and run it now?", workload.Name)
+	if workload.Status == "Suspended" {
+		message += "\nThe CronJob is suspended; this Job runs anyway."
+	}
+	return m.confirmDialog.Request(
+		m.confirmLevel(workload.Namespace, configs.ActionTriggerCronJob),
+		"Trigger CronJob",
+		message,
+		"trigger_cronjob",
+		workload.Name,
+		workload,
+	)
+}
+
+// triggerCronJob creates a Job from a CronJob's template.
+// Returns a cronJobTriggeredMsg with the name of the Job.
+func (m *Model) triggerCronJob(workload *repository.WorkloadInfo) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		job, err := repository.TriggerCronJob(ctx, m.k8sClient.Clientset(), workload.Namespace, workload.Name)
+		return cronJobTriggeredMsg{namespace: workload.Namespace, cronJob: workload.Name, job: job, err: err}
+	})
+}
+
+// waitForJobPod looks for the pod of a Job after a delay, since the Job
+// controller creates it shortly after the Job.
+// Returns a jobPodMsg with the pod, or a nil pod if there is none yet.
+func (m *Model) waitForJobPod(namespace, job string, attempt int) tea.Cmd {
+	poll := call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		pod, err := repository.GetJobPod(ctx, m.k8sClient.Clientset(), namespace, job)
+		return jobPodMsg{namespace: namespace, job: job, attempt: attempt + 1, pod: pod, err: err}
+	})
+	return tea.Tick(jobPodPollInterval, func(time.Time) tea.Msg { return poll() })
+}
+
+// jobWorkload is the workload of a triggered Job, for the dashboard
+// breadcrumb and workload logs.
+func jobWorkload(namespace, job string) *repository.WorkloadInfo {
+	return &repository.WorkloadInfo{
+		Name:      job,
+		Namespace: namespace,
+		Type:      repository.ResourceJobs,
+		Labels:    map[string]string{"job-name": job},
+	}
+}
+
+// loadCronJobRuns fetches the schedule of a CronJob and the Jobs it ran.
+// Returns a cronJobRunsMsg.
+func (m *Model) loadCronJobRuns(workload *repository.WorkloadInfo) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		msg := cronJobRunsMsg{namespace: workload.Namespace, cronJob: workload.Name}
+		msg.schedule, msg.err = repository.GetCronJobSchedule(ctx, m.k8sClient.Clientset(), workload.Namespace, workload.Name)
+		if msg.err != nil {
+			return msg
+		}
+		msg.runs, msg.err = repository.GetCronJobRuns(ctx, m.k8sClient.Clientset(), workload.Namespace, workload.Name)
+		return msg
+	})
+}
+
+// loadJobRunPod fetches the pod of a CronJob run to open its logs; the pod
+// may have completed long ago, or been deleted since the runs were listed.
+// Returns a jobRunPodMsg.
+func (m *Model) loadJobRunPod(req component.OpenJobRunRequest) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		pod, err := repository.GetJobPod(ctx, m.k8sClient.Clientset(), req.Namespace, req.Job)
+		return jobRunPodMsg{job: req.Job, pod: pod, err: err}
+	})
+}
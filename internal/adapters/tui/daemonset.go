@@ -0,0 +1,29 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains the per-node rollout of DaemonSets.
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+)
+
+// loadDaemonSetNodes joins the pods of a DaemonSet with the nodes of the
+// cluster. Returns a daemonSetNodesMsg.
+func (m *Model) loadDaemonSetNodes(workload *repository.WorkloadInfo) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		rollout, err := repository.GetDaemonSetNodes(ctx, m.k8sClient.Clientset(), workload.Namespace, workload.Name)
+		return daemonSetNodesMsg{workload: workload, rollout: rollout, err: err}
+	})
+}
+
+// loadDaemonSetPod fetches the pod picked in the DaemonSet nodes viewer to
+// open its dashboard. Returns a daemonSetPodMsg.
+func (m *Model) loadDaemonSetPod(req component.OpenDaemonSetPodRequest) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		pod, err := repository.GetPod(ctx, m.k8sClient.Clientset(), req.Workload.Namespace, req.Pod)
+		return daemonSetPodMsg{workload: req.Workload, pod: pod, err: err}
+	})
+}
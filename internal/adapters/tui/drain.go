@@ -0,0 +1,132 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains cordoning and draining nodes.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+)
+
+// drainEvictionTimeout is how long a drain keeps retrying evictions that
+// a PodDisruptionBudget blocks before reporting them.
+const drainEvictionTimeout = 2 * time.Minute
+
+// nodeDrain is a drain in progress.
+type nodeDrain struct {
+	node    string
+	pods    []repository.DrainPodProgress // Latest state of each pod, in the order first reported
+	updates <-chan tea.Msg                // drainProgressMsg values, then a drainFinishedMsg
+}
+
+// update records the new state of a pod.
+func (d *nodeDrain) update(p repository.DrainPodProgress) {
+	for i := range d.pods {
+		if d.pods[i].Namespace == p.Namespace && d.pods[i].Name == p.Name {
+			d.pods[i] = p
+			return
+		}
+	}
+	d.pods = append(d.pods, p)
+}
+
+// title is the result viewer title of the drain's progress.
+func (d *nodeDrain) title() string {
+	return "Drain: " + d.node
+}
+
+// requestNodeAction asks for confirmation before cordoning, uncordoning
+// or draining a node.
+func (m *Model) requestNodeAction(item component.NodeActionItem) tea.Cmd {
+	switch item.Action {
+	case "cordon":
+		return m.confirmDialog.Request(
+			m.confirmLevel("", configs.ActionCordonNode),
+			"Cordon Node",
+			fmt.Sprintf("Cordon '%s'? No new pods will be scheduled on it.", item.Node),
+			"cordon_node",
+			item.Node,
+			item.Node,
+		)
+	case "uncordon":
+		return m.confirmDialog.Request(
+			m.confirmLevel("", configs.ActionCordonNode),
+			"Uncordon Node",
+			fmt.Sprintf("Uncordon '%s'? Pods can be scheduled on it again.", item.Node),
+			"uncordon_node",
+			item.Node,
+			item.Node,
+		)
+	case "drain":
+		return m.confirmDialog.Request(
+			m.confirmLevel("", configs.ActionDrainNode),
+			"Drain Node",
+			fmt.Sprintf("Cordon '%s' and evict its pods?\n"+
+				"DaemonSet pods are skipped and PodDisruptionBudgets are respected.", item.Node),
+			"drain_node",
+			item.Node,
+			item.Node,
+		)
+	}
+	return nil
+}
+
+// cordonNode cordons or uncordons a node.
+// Returns a nodeCordonedMsg with the result.
+func (m *Model) cordonNode(nodeName string, cordon bool) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		var err error
+		if cordon {
+			err = repository.CordonNode(ctx, m.k8sClient.Clientset(), nodeName)
+		} else {
+			err = repository.UncordonNode(ctx, m.k8sClient.Clientset(), nodeName)
+		}
+		return nodeCordonedMsg{node: nodeName, cordon: cordon, err: err}
+	})
+}
+
+// startDrain drains a node in the background and shows its progress in
+// the result viewer. Returns a command that delivers the first
+// drainProgressMsg or the drainFinishedMsg.
+func (m *Model) startDrain(nodeName string) tea.Cmd {
+	updates := make(chan tea.Msg, 64)
+	m.drain = &nodeDrain{node: nodeName, updates: updates}
+	m.resultViewer.Show(m.drain.title(), component.RenderDrainProgress(nodeName, nil, nil), m.width-4, m.height-4)
+
+	clientset := m.k8sClient.Clientset()
+	go func() {
+		defer close(updates)
+		result, err := repository.DrainNode(context.Background(), clientset, nodeName, repository.DrainOptions{
+			Timeout: drainEvictionTimeout,
+			OnProgress: func(p repository.DrainPodProgress) {
+				updates <- drainProgressMsg{node: nodeName, pod: p}
+			},
+		})
+		updates <- drainFinishedMsg{node: nodeName, result: result, err: err}
+	}()
+	return waitForDrain(updates)
+}
+
+// waitForDrain blocks until the drain reports progress or finishes.
+func waitForDrain(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// showDrain refreshes the drain's progress in the result viewer, unless
+// the user closed it.
+func (m *Model) showDrain(result *repository.DrainResult) {
+	if m.resultViewer.IsVisible() && m.resultViewer.Title() == m.drain.title() {
+		m.resultViewer.SetContent(component.RenderDrainProgress(m.drain.node, m.drain.pods, result))
+	}
+}
@@ -0,0 +1,160 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains editing ConfigMap and Secret values in an external
+// editor.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// configEdit is a pending edit of one key of a ConfigMap or Secret. It is
+// also the ConfirmResult data for saving the edit.
+type configEdit struct {
+	secret          bool // A Secret rather than a ConfigMap
+	namespace       string
+	name            string
+	key             string
+	value           string // Decoded, for Secrets
+	resourceVersion string // "" overwrites whatever version is current
+}
+
+// editorCommand returns the command that opens path in the user's editor:
+// $VISUAL, then $EDITOR, then vi. The variables may include arguments,
+// like "code --wait".
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// editConfigValue suspends the UI and opens the value of edit in the
+// user's editor. The value is written to a private temp file named after
+// the key, so editors pick the right syntax for keys like app.yaml.
+// Returns a configValueEditedMsg with the edited value when the editor exits.
+func (m *Model) editConfigValue(edit configEdit) tea.Cmd {
+	f, err := os.CreateTemp("", "k1s-*-"+filepath.Base(edit.key))
+	if err != nil {
+		return func() tea.Msg {
+			return configValueEditedMsg{edit: edit, err: fmt.Errorf("failed to create temp file: %w", err)}
+		}
+	}
+	path := f.Name()
+	_, err = f.WriteString(edit.value)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg {
+			return configValueEditedMsg{edit: edit, err: fmt.Errorf("failed to write temp file: %w", err)}
+		}
+	}
+
+	return tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return configValueEditedMsg{edit: edit, err: fmt.Errorf("editor failed: %w", err)}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return configValueEditedMsg{edit: edit, err: fmt.Errorf("failed to read edited value: %w", err)}
+		}
+		value := string(data)
+		// Most editors end the file with a newline; don't add one the value didn't have
+		if !strings.HasSuffix(edit.value, "\n") {
+			value = strings.TrimSuffix(value, "\n")
+		}
+		return configValueEditedMsg{edit: edit, value: value}
+	})
+}
+
+// requestSaveConfigValue asks for confirmation before saving an edited value.
+func (m *Model) requestSaveConfigValue(edit configEdit) tea.Cmd {
+	return m.confirmDialog.Request(
+		m.confirmLevel(edit.namespace, configs.ActionEdit),
+		"Save "+configKindName(edit.secret),
+		fmt.Sprintf("Save the new value of '%s' in '%s'?", edit.key, edit.name),
+		"save_config_value",
+		edit.name,
+		edit,
+	)
+}
+
+// saveConfigValue applies an edited value. An edit with a resourceVersion
+// fails with a conflict if the object changed since it was read.
+// Returns a configValueSavedMsg with the result.
+func (m *Model) saveConfigValue(edit configEdit) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		var err error
+		if edit.secret {
+			err = repository.UpdateSecretData(ctx, m.k8sClient.Clientset(), edit.namespace, edit.name, edit.key, edit.value, edit.resourceVersion)
+		} else {
+			err = repository.UpdateConfigMapData(ctx, m.k8sClient.Clientset(), edit.namespace, edit.name, edit.key, edit.value, edit.resourceVersion)
+		}
+		return configValueSavedMsg{edit: edit, err: err}
+	})
+}
+
+// requestDeleteConfig asks for confirmation before deleting a ConfigMap or Secret.
+func (m *Model) requestDeleteConfig(secret bool, namespace, name string) tea.Cmd {
+	action, confirmAction := "delete_configmap", configs.ActionDeleteConfigMap
+	if secret {
+		action, confirmAction = "delete_secret", configs.ActionDeleteSecret
+	}
+	return m.confirmDialog.Request(
+		m.confirmLevel(namespace, confirmAction),
+		"Delete "+configKindName(secret),
+		fmt.Sprintf("Delete %s '%s'?", configKindName(secret), name),
+		action,
+		name,
+		configEdit{secret: secret, namespace: namespace, name: name},
+	)
+}
+
+// deleteConfig deletes a ConfigMap or Secret.
+// Returns a configDeletedMsg with the result.
+func (m *Model) deleteConfig(secret bool, namespace, name string) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		var err error
+		if secret {
+			err = repository.DeleteSecret(ctx, m.k8sClient.Clientset(), namespace, name)
+		} else {
+			err = repository.DeleteConfigMap(ctx, m.k8sClient.Clientset(), namespace, name)
+		}
+		return configDeletedMsg{secret: secret, name: name, err: err}
+	})
+}
+
+// setConfigStatus shows a status message in the status bar and in the
+// footer of the ConfigMap or Secret viewer, which covers the status bar.
+func (m *Model) setConfigStatus(secret bool, text string) {
+	m.statusMsg = text
+	if secret {
+		m.secretViewer.SetStatusMsg(text)
+	} else {
+		m.configMapViewer.SetStatusMsg(text)
+	}
+}
+
+// configKindName returns "Secret" or "ConfigMap".
+func configKindName(secret bool) string {
+	if secret {
+		return "Secret"
+	}
+	return "ConfigMap"
+}
@@ -0,0 +1,93 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains the event watch that feeds the events panel.
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// maxEventBatch caps how many events one eventsWatchedMsg carries.
+const maxEventBatch = 200
+
+// eventWatch is the watch on the dashboard pod's events.
+type eventWatch struct {
+	id        int // Distinguishes this watch's messages from stale ones
+	namespace string
+	pod       string
+	events    <-chan repository.EventInfo
+	cancel    context.CancelFunc
+}
+
+// syncEventWatch starts, restarts or stops the event watch so that it
+// follows the dashboard pod. While a watch runs, the events panel is fed
+// by it instead of by the refresh tick.
+// Returns the command that reads the next events, or nil.
+func (m *Model) syncEventWatch() tea.Cmd {
+	want := m.view == ViewDashboard && m.pod != nil
+
+	if want && m.eventWatch != nil &&
+		m.eventWatch.namespace == m.pod.Namespace &&
+		m.eventWatch.pod == m.pod.Name {
+		return nil
+	}
+
+	m.stopEventWatch()
+	if !want {
+		return nil
+	}
+
+	m.eventWatchSeq++
+	events, cancel := repository.WatchPodEvents(
+		context.Background(),
+		m.k8sClient.Clientset(),
+		m.pod.Namespace,
+		m.pod.Name,
+	)
+	m.eventWatch = &eventWatch{
+		id:        m.eventWatchSeq,
+		namespace: m.pod.Namespace,
+		pod:       m.pod.Name,
+		events:    events,
+		cancel:    cancel,
+	}
+	// The watch starts by sending the existing events
+	m.dashboard.SetEvents(nil)
+	return waitForEvents(m.eventWatch)
+}
+
+// stopEventWatch cancels the current event watch, if any.
+func (m *Model) stopEventWatch() {
+	if m.eventWatch != nil {
+		m.eventWatch.cancel()
+		m.eventWatch = nil
+	}
+}
+
+// waitForEvents blocks until the watch has an event, then takes every
+// event already buffered (up to maxEventBatch).
+// Returns an eventsWatchedMsg, with closed set once the watch has stopped.
+func waitForEvents(w *eventWatch) tea.Cmd {
+	id, ch := w.id, w.events
+	return func() tea.Msg {
+		event, ok := <-ch
+		if !ok {
+			return eventsWatchedMsg{watch: id, closed: true}
+		}
+		events := []repository.EventInfo{event}
+		for len(events) < maxEventBatch {
+			select {
+			case event, ok := <-ch:
+				if !ok {
+					return eventsWatchedMsg{watch: id, events: events, closed: true}
+				}
+				events = append(events, event)
+			default:
+				return eventsWatchedMsg{watch: id, events: events}
+			}
+		}
+		return eventsWatchedMsg{watch: id, events: events}
+	}
+}
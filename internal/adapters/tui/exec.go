@@ -0,0 +1,121 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains the in-process shell used to exec into containers.
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/view"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// terminalResizePoll is how often the terminal size is checked while a
+// shell runs. Polling works the same on every platform, unlike SIGWINCH.
+const terminalResizePoll = 250 * time.Millisecond
+
+// podShell is an interactive shell in a container. It implements
+// tea.ExecCommand, so the program releases the terminal while it runs.
+type podShell struct {
+	config    *rest.Config
+	namespace string
+	pod       string
+	container string
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (s *podShell) SetStdin(r io.Reader)  { s.stdin = r }
+func (s *podShell) SetStdout(w io.Writer) { s.stdout = w }
+func (s *podShell) SetStderr(w io.Writer) { s.stderr = w }
+
+// Run opens the shell and blocks until it exits. When stdin is a terminal
+// it is put in raw mode so keys such as Ctrl+C reach the shell, and size
+// changes are forwarded to the container's TTY.
+func (s *podShell) Run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opts := repository.ExecOptions{
+		Stdin:  s.stdin,
+		Stdout: s.stdout,
+		Stderr: s.stderr,
+	}
+	if f, ok := s.stdin.(term.File); ok && term.IsTerminal(f.Fd()) {
+		state, err := term.MakeRaw(f.Fd())
+		if err != nil {
+			return fmt.Errorf("failed to set terminal to raw mode: %w", err)
+		}
+		defer term.Restore(f.Fd(), state)
+
+		opts.TTY = true
+		opts.TerminalSizes = &terminalSizeQueue{ctx: ctx, fd: s.sizeFd(f.Fd())}
+	}
+
+	return repository.ExecShell(ctx, s.config, s.namespace, s.pod, s.container, opts)
+}
+
+// sizeFd returns the descriptor to read the terminal size from, preferring
+// stdout like the renderer does.
+func (s *podShell) sizeFd(stdinFd uintptr) uintptr {
+	if f, ok := s.stdout.(term.File); ok && term.IsTerminal(f.Fd()) {
+		return f.Fd()
+	}
+	return stdinFd
+}
+
+// terminalSizeQueue reports the terminal size to the exec stream: the
+// current size first, then every change until ctx is done.
+type terminalSizeQueue struct {
+	ctx  context.Context
+	fd   uintptr
+	last remotecommand.TerminalSize
+}
+
+// Next blocks until the size differs from the last one sent. It returns
+// nil once the shell has exited, which ends the resize stream.
+func (q *terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	for {
+		if w, h, err := term.GetSize(q.fd); err == nil && w > 0 && h > 0 {
+			size := remotecommand.TerminalSize{Width: uint16(w), Height: uint16(h)}
+			if size != q.last {
+				q.last = size
+				return &size
+			}
+		}
+		select {
+		case <-q.ctx.Done():
+			return nil
+		case <-time.After(terminalResizePoll):
+		}
+	}
+}
+
+// execShell suspends the UI and opens a shell in a container of a pod.
+// The dashboard is shown again when the shell exits.
+// Returns a view.ExecFinishedMsg with a readable error if the shell could
+// not be opened.
+func (m *Model) execShell(namespace, podName, container string) tea.Cmd {
+	shell := &podShell{
+		config:    m.k8sClient.Config(),
+		namespace: namespace,
+		pod:       podName,
+		container: container,
+	}
+	return tea.Exec(shell, func(err error) tea.Msg {
+		if errors.Is(err, repository.ErrNoShell) {
+			err = fmt.Errorf("container '%s' has no shell (tried %s)", container, strings.Join(repository.ExecShells, ", "))
+		}
+		return view.ExecFinishedMsg{Err: err}
+	})
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// fileCopyTarget is the InputDialogResult data for a pending copy out of a
+// container.
+type fileCopyTarget struct {
+	namespace string
+	pod       string
+	container string
+	path      string
+}
+
+// listContainerDir lists a directory of the container open in the file browser.
+// Returns a containerDirMsg.
+func (m *Model) listContainerDir(dir string) tea.Cmd {
+	namespace, pod, container := m.fileBrowser.Target()
+	config := m.k8sClient.Config()
+	return call(m.podCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		entries, err := repository.ListContainerDir(ctx, config, namespace, pod, container, dir)
+		return containerDirMsg{pod: pod, container: container, path: dir, entries: entries, err: err}
+	})
+}
+
+// readContainerFile reads the start of a file of the container open in the
+// file browser, for its preview.
+// Returns a containerFileMsg.
+func (m *Model) readContainerFile(filePath string) tea.Cmd {
+	namespace, pod, container := m.fileBrowser.Target()
+	config := m.k8sClient.Config()
+	return call(m.podCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		content, err := repository.ReadContainerFile(ctx, config, namespace, pod, container, filePath, repository.MaxFilePreviewBytes)
+		return containerFileMsg{pod: pod, container: container, path: filePath, content: content, err: err}
+	})
+}
+
+// requestContainerCopy prompts for the local path to copy a file or
+// directory of the container open in the file browser to.
+func (m *Model) requestContainerCopy(filePath string) {
+	namespace, pod, container := m.fileBrowser.Target()
+	m.inputDialog.Show(
+		"Copy From Container",
+		fmt.Sprintf("Copy '%s' to local path:", filePath),
+		"copy_from_container",
+		path.Base(filePath),
+		fileCopyTarget{namespace: namespace, pod: pod, container: container, path: filePath},
+	)
+}
+
+// copyFromContainer copies a file or directory out of a container with tar,
+// like kubectl cp.
+// Returns a containerCopyMsg with the local path written.
+func (m *Model) copyFromContainer(target fileCopyTarget, dest string) tea.Cmd {
+	config := m.k8sClient.Config()
+	return func() tea.Msg {
+		local, err := repository.CopyFromPod(context.Background(), config, target.namespace, target.pod, target.container, target.path, dest)
+		return containerCopyMsg{path: target.path, local: local, err: err}
+	}
+}
+
+// isFileBrowserTarget reports whether a result is for the container still
+// open in the file browser.
+func (m *Model) isFileBrowserTarget(pod, container string) bool {
+	_, openPod, openContainer := m.fileBrowser.Target()
+	return m.fileBrowser.IsVisible() && pod == openPod && container == openContainer
+}
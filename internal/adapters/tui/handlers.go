@@ -4,8 +4,10 @@ package tui
 
 import (
 	"fmt"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
 	"github.com/andrebassi/k1s/internal/adapters/tui/component"
 )
 
@@ -14,12 +16,17 @@ import (
 // - From Dashboard: Returns to Navigator in Resources mode
 // - From Resources mode: Returns to Namespace selection
 // - From ResourceType mode: Returns to Namespace selection
+// - From Context mode: Returns to Namespace selection
 // - From Namespace mode: Quit application (root level)
 func (m *Model) handleBack() (tea.Model, tea.Cmd) {
+	m.timedOut = nil
 	switch m.view {
 	case ViewDashboard:
 		m.view = ViewNavigator
 		m.pod = nil
+		m.stopLogStream()
+		m.stopEventWatch()
+		m.leavePodScope()
 		// Always go back to pods list
 		m.navigator.SetMode(component.ModeResources)
 		return m, nil
@@ -44,6 +51,152 @@ func (m *Model) handleBack() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// switchContext points the client at another kubeconfig context and probes
+// its cluster. The cached namespaces, workloads and resources are only reset
+// once the cluster answers; until then the previous client state is kept so
+// it can be restored.
+func (m *Model) switchContext(name string) tea.Cmd {
+	previous := *m.k8sClient
+	if err := m.k8sClient.SwitchContext(name); err != nil {
+		m.statusMsg = "Error switching context: " + err.Error()
+		return clearStatusAfter(5 * time.Second)
+	}
+	m.loading = true
+	return m.loadContextData(previous)
+}
+
+// openClusterSwitcher opens the cluster switcher at once and reads the
+// kubeconfig contexts for it in the background; their clusters are probed
+// once listed.
+func (m *Model) openClusterSwitcher() tea.Cmd {
+	m.clusterSwitcher.SetSize(m.width, m.height)
+	m.clusterSwitcher.Show(m.k8sClient.Context())
+	return m.loadContexts()
+}
+
+// enterSwitchedNamespace opens the namespace selected before a context
+// switch if the new cluster has it, or "default" with a notice if not, and
+// loads its resources.
+func (m *Model) enterSwitchedNamespace(msg contextSwitchedMsg) tea.Cmd {
+	m.navigator.SetMode(component.ModeNamespace)
+	exists := func(name string) bool {
+		for _, ns := range msg.namespaces {
+			if ns.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	ns := msg.previous.Namespace()
+	var notice tea.Cmd
+	if !exists(ns) {
+		if ns != "" && ns != "default" {
+			notice = m.notify(component.Notification{
+				Severity: component.SeverityInfo,
+				Op:       "switch to context " + msg.context,
+				Message:  fmt.Sprintf("namespace %s does not exist there, using default", ns),
+			})
+		}
+		ns = "default"
+		if !exists(ns) {
+			return notice
+		}
+	}
+	m.k8sClient.SetNamespace(ns)
+	m.config.SetLastNamespace(ns)
+	m.loading = true
+	return tea.Batch(notice, m.loadFirstResourcesPage())
+}
+
+// resetContextState drops everything loaded from the previous context.
+func (m *Model) resetContextState() {
+	m.stopLogStream()
+	m.stopEventWatch()
+	m.leaveNavigatorScope()
+	m.leavePodScope()
+	m.pod = nil
+	m.workload = nil
+	m.link = nil
+	m.recentWarnings = nil
+	m.selectedNode = ""
+	m.nodeCursor = 0
+	m.nodes = nil
+	m.nodesPanelActive = false
+	m.navigator.SetNamespaces(nil)
+	m.navigator.SetWorkloads(nil)
+	m.navigator.SetPods(nil)
+	m.navigator.SetHPAs(nil)
+	m.navigator.SetConfigMaps(nil)
+	m.navigator.SetSecrets(nil)
+	m.navigator.SetProbeHealth(nil)
+	m.navigator.SetScaleWorkload(nil)
+	m.palette.Reset()
+}
+
+// openPodDashboard switches to the dashboard for pod and starts loading its
+// logs, events and metrics. Used when a pod is selected in the navigator and
+// when a k1s:// link to a pod is opened.
+func (m *Model) openPodDashboard(pod *repository.PodInfo) tea.Cmd {
+	// Whatever was loading for the previous pod is stale
+	m.leavePodScope()
+	m.pod = pod
+	m.view = ViewDashboard
+	m.telemetry.View("dashboard")
+	m.palette.Visit(component.PaletteItem{Kind: "pod", Namespace: pod.Namespace, Name: pod.Name})
+	m.dashboard.SetPod(pod)
+	// Set breadcrumb: namespace > pods > podname
+	workloadName := ""
+	if m.workload != nil {
+		workloadName = m.workload.Name
+	}
+	m.dashboard.SetBreadcrumb(
+		m.k8sClient.Namespace(),
+		"pods",
+		workloadName,
+		pod.Name,
+	)
+	m.dashboard.SetProtected(m.isProtected(pod.Namespace))
+	if workload := m.podWorkload(pod); workload != nil {
+		m.dashboard.SetLogsWorkload(workload.Name)
+	} else {
+		m.dashboard.SetLogsWorkload("")
+	}
+	m.dashboard.SetContext(m.k8sClient.Context())
+	m.dashboard.SetNamespace(m.k8sClient.Namespace())
+	m.loading = true
+	// Start following logs and events first so the dashboard load skips
+	// polling them
+	stream := m.syncLogStream()
+	watch := m.syncEventWatch()
+	return tea.Batch(
+		stream,
+		watch,
+		m.loadDashboardData(pod),
+		m.loadAccessChecks(pod),
+		m.refresher.Next(),
+	)
+}
+
+// openTarget opens the pod of a target on the dashboard, with its workload
+// pods loaded underneath, where Esc leads. A workload without pods opens
+// on its (empty) pod list. Used for the kind/name given on the command
+// line and for jumps from the warnings list.
+func (m *Model) openTarget(target *repository.Target) tea.Cmd {
+	m.workload = target.Workload
+	if target.Workload == nil {
+		return m.openPodDashboard(target.Pod)
+	}
+	m.visitWorkload(target.Workload)
+	if target.Pod == nil {
+		m.view = ViewNavigator
+		m.statusMsg = fmt.Sprintf("%s has no pods", target.Workload.Name)
+		m.loading = true
+		return tea.Batch(m.loadPods(target.Workload), clearStatusAfter(3*time.Second))
+	}
+	return tea.Batch(m.loadPods(target.Workload), m.openPodDashboard(target.Pod))
+}
+
 // handleEnter handles the enter key action based on current view and selection.
 // Behavior varies by view and mode:
 //
@@ -62,8 +215,13 @@ func (m *Model) handleEnter() (tea.Model, tea.Cmd) {
 		switch m.navigator.Mode() {
 		case component.ModeWorkloads:
 			workload := m.navigator.SelectedWorkload()
+			if workload != nil && workload.Service != nil && workload.Service.NoSelector {
+				m.statusMsg = fmt.Sprintf("Service %s has no selector, so no pods back it", workload.Name)
+				return m, clearStatusAfter(3 * time.Second)
+			}
 			if workload != nil {
 				m.workload = workload
+				m.visitWorkload(workload)
 				m.loading = true
 				return m, m.loadPods(workload)
 			}
@@ -73,38 +231,20 @@ func (m *Model) handleEnter() (tea.Model, tea.Cmd) {
 			case component.SectionPods:
 				pod := m.navigator.SelectedPod()
 				if pod != nil {
-					m.pod = pod
-					m.view = ViewDashboard
-					m.dashboard.SetPod(pod)
-					// Set breadcrumb: namespace > pods > podname
-					workloadName := ""
-					if m.workload != nil {
-						workloadName = m.workload.Name
-					}
-					m.dashboard.SetBreadcrumb(
-						m.k8sClient.Namespace(),
-						"pods",
-						workloadName,
-						pod.Name,
-					)
-					m.dashboard.SetContext(m.k8sClient.Context())
-					m.dashboard.SetNamespace(m.k8sClient.Namespace())
-					m.loading = true
-					return m, tea.Batch(
-						m.loadDashboardData(pod),
-						m.tickCmd(),
-					)
+					return m, m.openPodDashboard(pod)
 				}
 			case component.SectionHPAs:
 				hpa := m.navigator.SelectedHPA()
 				if hpa != nil {
 					m.loading = true
-					return m, m.loadHPAData(hpa.Name)
+					m.telemetry.View("hpa")
+					return m, m.loadHPAData(m.k8sClient.Namespace(), hpa.Name)
 				}
 			case component.SectionConfigMaps:
 				cm := m.navigator.SelectedConfigMap()
 				if cm != nil {
 					m.loading = true
+					m.telemetry.View("configmap")
 					return m, m.loadConfigMapData(cm.Name)
 				}
 			case component.SectionSecrets:
@@ -112,6 +252,7 @@ func (m *Model) handleEnter() (tea.Model, tea.Cmd) {
 				if secret != nil {
 					m.loading = true
 					m.isDockerRegistrySecret = false
+					m.telemetry.View("secret")
 					return m, m.loadSecretData(secret.Name)
 				}
 			case component.SectionDockerRegistry:
@@ -119,6 +260,7 @@ func (m *Model) handleEnter() (tea.Model, tea.Cmd) {
 				if secret != nil {
 					m.loading = true
 					m.isDockerRegistrySecret = true
+					m.telemetry.View("docker-registry")
 					return m, m.loadSecretData(secret.Name)
 				}
 			}
@@ -136,30 +278,28 @@ func (m *Model) handleEnter() (tea.Model, tea.Cmd) {
 				}
 			}
 			// Check if namespace is not Active (e.g., Terminating)
-			// If so, show delete confirmation instead of entering
+			// If so, offer to force delete it instead of entering
 			nsInfo := m.navigator.SelectedNamespaceInfo()
 			if nsInfo != nil && nsInfo.Status != "Active" {
-				m.confirmDialog.Show(
-					fmt.Sprintf("Force delete namespace '%s'?", nsInfo.Name),
-					"This will remove all resources and finalizers.",
-					"delete_namespace",
-					nsInfo,
-				)
-				return m, nil
+				return m, m.requestDeleteNamespace(nsInfo)
 			}
 			// Otherwise, select namespace and load resources
 			ns := m.navigator.SelectedNamespace()
 			if ns != "" {
+				m.palette.Visit(component.PaletteItem{Kind: component.PaletteNamespace, Namespace: ns})
+				m.leaveNavigatorScope()
 				m.k8sClient.SetNamespace(ns)
 				m.config.SetLastNamespace(ns)
 				m.selectedNode = "" // Clear node filter
 				m.loading = true
-				// Load all resources (pods, configmaps, secrets)
-				return m, m.loadAllResources()
+				// Load all resources (pods, configmaps, secrets); further
+				// pages of pods follow once the first one is shown
+				return m, m.loadFirstResourcesPage()
 			}
 
 		case component.ModeResourceType:
 			rt := m.navigator.SelectedResourceType()
+			m.leaveNavigatorScope()
 			m.navigator.SetResourceType(rt)
 			m.config.SetLastResourceType(string(rt))
 			m.navigator.SetMode(component.ModeWorkloads)
@@ -174,6 +314,9 @@ func (m *Model) handleEnter() (tea.Model, tea.Cmd) {
 // - Navigator view: Reloads workloads for the current namespace and resource type
 // - Dashboard view: Reloads pod dashboard data (logs, events, metrics)
 func (m *Model) refresh() tea.Cmd {
+	if m.timedOut != nil {
+		return m.retryTimedOut()
+	}
 	switch m.view {
 	case ViewNavigator:
 		m.loading = true
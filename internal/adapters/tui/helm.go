@@ -0,0 +1,28 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains viewing the Helm release that installed a workload.
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+)
+
+// loadHelmRelease reads the latest revision of the Helm release that
+// installed a workload.
+// Returns a helmReleaseMsg.
+func (m *Model) loadHelmRelease(workload *repository.WorkloadInfo) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		release, err := repository.GetHelmRelease(ctx, m.k8sClient.Clientset(), workload.Namespace, workload.HelmRelease)
+		return helmReleaseMsg{workload: workload, release: release, err: err}
+	})
+}
+
+// showHelmRelease shows the Helm section of a workload in a scrollable
+// pane, values included.
+func (m *Model) showHelmRelease(release *repository.HelmRelease) {
+	m.telemetry.View("helm")
+	m.resultViewer.Show("Helm: "+release.Name, component.RenderHelmRelease(release), m.width-4, m.height-4)
+}
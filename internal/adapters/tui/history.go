@@ -0,0 +1,66 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains the revision history and rollback of Deployments.
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+)
+
+// deploymentRollback is the ConfirmResult data for a pending rollback.
+type deploymentRollback struct {
+	workload *repository.WorkloadInfo
+	revision int64
+}
+
+// loadDeploymentHistory fetches the revisions of a Deployment.
+// Returns a deploymentHistoryMsg with the revisions, newest first.
+func (m *Model) loadDeploymentHistory(workload *repository.WorkloadInfo) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		history, err := repository.GetDeploymentHistory(ctx, m.k8sClient.Clientset(), workload.Namespace, workload.Name)
+		return deploymentHistoryMsg{workload: workload, history: history, err: err}
+	})
+}
+
+// showDeploymentHistory opens the workload action menu as a picker of the
+// revisions to roll back to.
+func (m *Model) showDeploymentHistory(workload *repository.WorkloadInfo, history []repository.RevisionInfo) {
+	m.workloadMenuTarget = workload
+	m.workloadActionMenu.Show("History: "+workload.Name, component.RevisionActions(workload.Namespace, workload.Name, history))
+}
+
+// requestRollback asks for confirmation before rolling a Deployment back
+// to a revision.
+func (m *Model) requestRollback(workload *repository.WorkloadInfo, revision int64) tea.Cmd {
+	return m.confirmDialog.Request(
+		m.confirmLevel(workload.Namespace, configs.ActionRollbackDeployment),
+		"Rollback Deployment",
+		fmt.Sprintf("Roll '%s' back to revision %d? Its pods are replaced with that revision's template.", workload.Name, revision),
+		"rollback_deployment",
+		workload.Name,
+		deploymentRollback{workload: workload, revision: revision},
+	)
+}
+
+// rollbackDeployment copies the pod template of a revision back onto a
+// Deployment, which rolls it out as a new revision.
+// Returns a workloadActionMsg with the result.
+func (m *Model) rollbackDeployment(workload *repository.WorkloadInfo, revision int64) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		err := repository.RollbackDeployment(ctx, m.k8sClient.Clientset(), workload.Namespace, workload.Name, revision)
+		return workloadActionMsg{
+			action:       "rollback",
+			workloadName: workload.Name,
+			namespace:    workload.Namespace,
+			resourceType: workload.Type,
+			revision:     revision,
+			hint:         workloadHint(workload),
+			err:          err,
+		}
+	})
+}
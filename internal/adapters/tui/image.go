@@ -0,0 +1,128 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains changing the image of a workload's container and following its rollout.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+)
+
+// Following the rollout of a new image: every two seconds, for up to ten
+// minutes, as long as kubectl rollout status waits by default.
+const (
+	imageRolloutPollInterval = 2 * time.Second
+	imageRolloutPollAttempts = 300
+)
+
+// imageChange is the InputDialogResult and ConfirmResult data for a pending
+// image change.
+type imageChange struct {
+	workload  *repository.WorkloadInfo
+	container string
+	current   string // Image the container runs now
+	image     string // Image typed, "" until entered
+	warning   string // Warning of ValidateImage, shown in the confirmation
+}
+
+// loadWorkloadImages fetches the images of a workload's containers.
+// Returns a workloadImagesMsg.
+func (m *Model) loadWorkloadImages(workload *repository.WorkloadInfo) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		images, err := repository.GetWorkloadImages(ctx, m.k8sClient.Clientset(), m.k8sClient.DynamicClient(), workload.Namespace, workload.Name, workload.Type)
+		return workloadImagesMsg{workload: workload, images: images, err: err}
+	})
+}
+
+// showImages opens the workload action menu as a picker of the container
+// whose image to change.
+func (m *Model) showImages(workload *repository.WorkloadInfo, images []repository.ContainerImage) {
+	kind := repository.KindForResourceType(workload.Type)
+	m.workloadMenuTarget = workload
+	m.workloadActionMenu.Show("Image: "+workload.Name, component.ContainerImageActions(workload.Namespace, kind, workload.Name, images))
+}
+
+// editImage opens the current image of a container for editing, so only
+// the tag needs changing.
+func (m *Model) editImage(workload *repository.WorkloadInfo, container, current string) {
+	change := imageChange{workload: workload, container: container, current: current}
+	m.inputDialog.Show("Set Image", fmt.Sprintf("New image for container %s:", container), "set_image", current, change)
+}
+
+// requestSetImage validates the image typed and asks for confirmation
+// before changing it, which rolls out new pods.
+func (m *Model) requestSetImage(change imageChange) tea.Cmd {
+	warning, err := repository.ValidateImage(change.image)
+	if err != nil {
+		m.statusMsg = "Invalid image: " + err.Error()
+		return clearStatusAfter(5 * time.Second)
+	}
+	if change.image == change.current {
+		m.statusMsg = fmt.Sprintf("%s already runs %s", change.container, change.image)
+		return clearStatusAfter(3 * time.Second)
+	}
+	change.warning = warning
+	message := fmt.Sprintf("Set the image of container '%s' of '%s' to %s? This rolls out new pods.\nNow: %s", change.container, change.workload.Name, change.image, change.current)
+	if warning != "" {
+		message += "\nWarning: " + warning
+	}
+	return m.confirmDialog.Request(
+		m.confirmLevel(change.workload.Namespace, configs.ActionSetImage),
+		"Set Image",
+		message,
+		"set_image",
+		change.workload.Name,
+		change,
+	)
+}
+
+// setImage changes the image of a container.
+// Returns an imageSetMsg with the result.
+func (m *Model) setImage(change imageChange) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		w := change.workload
+		err := m.k8sClient.SetWorkloadImage(ctx, w.Namespace, w.Name, w.Type, change.container, change.image)
+		return imageSetMsg{change: change, err: err}
+	})
+}
+
+// followImageRollout checks the rollout of a new image after a delay.
+// Returns an imageRolloutMsg with the progress.
+func (m *Model) followImageRollout(change imageChange, attempt int) tea.Cmd {
+	w := change.workload
+	poll := call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		progress, err := repository.GetRolloutProgress(ctx, m.k8sClient.Clientset(), m.k8sClient.DynamicClient(), w.Namespace, w.Name, w.Type)
+		return imageRolloutMsg{change: change, attempt: attempt + 1, progress: progress, err: err}
+	})
+	return tea.Tick(imageRolloutPollInterval, func(time.Time) tea.Msg { return poll() })
+}
+
+// handleImageRollout shows the progress of the rollout being followed and
+// polls again until it is done or the attempts run out. Progress of a
+// rollout no longer followed, since another image was set, is ignored.
+func (m *Model) handleImageRollout(msg imageRolloutMsg) tea.Cmd {
+	if m.imageRollout == nil || *m.imageRollout != msg.change {
+		return nil
+	}
+	w := msg.change.workload
+	switch {
+	case msg.err != nil:
+		m.imageRollout = nil
+		return m.notifyError("follow rollout of "+w.Name, w.Namespace, msg.err)
+	case msg.progress.Done():
+		m.imageRollout = nil
+		m.statusMsg = fmt.Sprintf("%s rolled out %s", w.Name, msg.change.image)
+		return tea.Batch(m.refreshWorkload(workloadHint(w)), clearStatusAfter(5*time.Second))
+	case msg.attempt >= imageRolloutPollAttempts:
+		m.imageRollout = nil
+		m.statusMsg = fmt.Sprintf("%s is still rolling out %s: %s", w.Name, msg.change.image, msg.progress)
+		return clearStatusAfter(10 * time.Second)
+	}
+	m.statusMsg = fmt.Sprintf("Rolling out %s to %s: %s", msg.change.image, w.Name, msg.progress)
+	return tea.Batch(m.refreshWorkload(workloadHint(w)), m.followImageRollout(msg.change, msg.attempt))
+}
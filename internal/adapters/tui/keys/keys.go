@@ -40,6 +40,7 @@ type KeyMap struct {
 	// Mode switches
 	Namespace    key.Binding
 	ResourceType key.Binding
+	Context      key.Binding
 
 	// Log actions
 	ToggleFollow key.Binding
@@ -55,10 +56,49 @@ type KeyMap struct {
 	// Pod actions
 	CopyCommands key.Binding
 	PodActions   key.Binding
+	Mark         key.Binding // Select a pod of the pod list for a bulk action
+	BulkActions  key.Binding
 
 	// Workload actions
-	Scale   key.Binding
-	Restart key.Binding
+	Scale    key.Binding
+	Restart  key.Binding
+	WorstPod key.Binding
+	Undo     key.Binding
+
+	// Namespace health
+	ProbeHealth key.Binding
+	Top         key.Binding
+
+	// Highlight fields that changed between refreshes
+	HighlightChanges key.Binding
+
+	// Expand the crash diagnosis banner of Pod Details
+	Diagnosis key.Binding
+
+	// Switch to the next color theme
+	Theme key.Binding
+	// List the port-forwards running in the background
+	PortForwards key.Binding
+
+	// List the watches waiting for a pod or workload condition
+	Watches key.Binding
+
+	// Fuzzy finder jumping to a namespace, workload or pod
+	Palette key.Binding
+
+	// Freeze the periodic refresh
+	PauseRefresh key.Binding
+
+	// Namespace warnings
+	Warnings key.Binding
+
+	// Errors of the session
+	ErrorLog key.Binding
+
+	// Workload table columns
+	Columns      key.Binding
+	ColumnsLeft  key.Binding
+	ColumnsRight key.Binding
 }
 
 // DefaultKeyMap returns the standard keyboard bindings for k1s.
@@ -164,6 +204,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("t"),
 			key.WithHelp("t", "type"),
 		),
+		Context: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "context"),
+		),
 
 		// Log actions
 		ToggleFollow: key.NewBinding(
@@ -200,6 +244,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("a"),
 			key.WithHelp("a", "pod actions"),
 		),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "select pod"),
+		),
+		BulkActions: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "bulk actions"),
+		),
 
 		// Workload actions
 		Scale: key.NewBinding(
@@ -210,5 +262,90 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("R"),
 			key.WithHelp("R", "restart"),
 		),
+		WorstPod: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "worst pod"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo scale"),
+		),
+
+		// Namespace health
+		ProbeHealth: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "probe failures"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "pod metrics"),
+		),
+
+		// Refresh diff
+		HighlightChanges: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "highlight changes"),
+		),
+
+		// Crash diagnosis
+		Diagnosis: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "why is it failing"),
+		),
+
+		// Color theme
+		Theme: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", "next theme"),
+		),
+		// Port-forwards
+		PortForwards: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "port-forwards"),
+		),
+
+		// Watches
+		Watches: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "watches"),
+		),
+
+		// Command palette
+		Palette: key.NewBinding(
+			key.WithKeys("ctrl+p"),
+			key.WithHelp("ctrl+p", "jump anywhere"),
+		),
+
+		// Refresh
+		PauseRefresh: key.NewBinding(
+			key.WithKeys("Z"),
+			key.WithHelp("Z", "pause refresh"),
+		),
+
+		// Namespace warnings
+		Warnings: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "namespace warnings"),
+		),
+
+		// Errors of the session
+		ErrorLog: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "error log"),
+		),
+
+		// Workload table columns
+		Columns: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "columns"),
+		),
+		ColumnsLeft: key.NewBinding(
+			key.WithKeys("<"),
+			key.WithHelp("<", "scroll columns left"),
+		),
+		ColumnsRight: key.NewBinding(
+			key.WithKeys(">"),
+			key.WithHelp(">", "scroll columns right"),
+		),
 	}
 }
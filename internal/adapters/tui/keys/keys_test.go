@@ -102,6 +102,7 @@ func TestDefaultKeyMap(t *testing.T) {
 	}{
 		{"Namespace", km.Namespace},
 		{"ResourceType", km.ResourceType},
+		{"Context", km.Context},
 	}
 
 	for _, tt := range modeBindings {
@@ -145,8 +146,18 @@ func TestDefaultKeyMap(t *testing.T) {
 		{"ToggleFullView", km.ToggleFullView},
 		{"CopyCommands", km.CopyCommands},
 		{"PodActions", km.PodActions},
+		{"Mark", km.Mark},
+		{"BulkActions", km.BulkActions},
 		{"Scale", km.Scale},
 		{"Restart", km.Restart},
+		{"WorstPod", km.WorstPod},
+		{"Undo", km.Undo},
+		{"ProbeHealth", km.ProbeHealth},
+		{"HighlightChanges", km.HighlightChanges},
+		{"Theme", km.Theme},
+		{"Columns", km.Columns},
+		{"ColumnsLeft", km.ColumnsLeft},
+		{"ColumnsRight", km.ColumnsRight},
 	}
 
 	for _, tt := range miscBindings {
@@ -5,11 +5,16 @@ package tui
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/internal/adapters/deeplink"
 	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+	"github.com/andrebassi/k1s/internal/adapters/tui/view"
 )
 
 // loadInitialData fetches the initial data required for the application startup.
@@ -17,9 +22,7 @@ import (
 // This is used when the application starts without a specific namespace flag.
 // Returns a loadedMsg with namespaces and nodes, or an error if namespace listing fails.
 func (m *Model) loadInitialData() tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
-
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		namespaces, err := m.k8sClient.ListNamespaces(ctx)
 		if err != nil {
 			return loadedMsg{err: err}
@@ -31,6 +34,81 @@ func (m *Model) loadInitialData() tea.Cmd {
 			namespaces: namespaces,
 			nodes:      nodes,
 		}
+	})
+}
+
+// checkConnection asks the API server for its version, before anything is
+// loaded and whenever a request failed as if the cluster had gone away.
+// The first time it answers, the APIs it serves are discovered, so that
+// features it lacks are hidden from the first load on.
+// Returns a connectionCheckedMsg.
+func (m *Model) checkConnection() tea.Cmd {
+	return func() tea.Msg {
+		_, err := m.k8sClient.CheckConnection(repository.ConnectionCheckTimeout)
+		if err == nil && m.k8sClient.APIs() == nil {
+			// Without discovery every API is taken as served
+			_, _ = m.k8sClient.DiscoverAPIs()
+		}
+		return connectionCheckedMsg{err: err}
+	}
+}
+
+// loadContexts lists the kubeconfig contexts for the cluster switcher.
+// Returns a contextsLoadedMsg with the context names sorted alphabetically.
+func (m *Model) loadContexts() tea.Cmd {
+	return func() tea.Msg {
+		contexts, _, err := m.k8sClient.ListContexts()
+		sort.Strings(contexts)
+		return contextsLoadedMsg{contexts: contexts, err: err}
+	}
+}
+
+// probeContexts probes the cluster of every context at once, each with
+// its own short timeout, so that a dead cluster does not hold up the
+// others. Returns a contextProbedMsg per context.
+func (m *Model) probeContexts(contexts []string) tea.Cmd {
+	client := *m.k8sClient
+	cmds := make([]tea.Cmd, 0, len(contexts))
+	for _, name := range contexts {
+		name := name
+		cmds = append(cmds, func() tea.Msg {
+			return contextProbedMsg{health: client.ProbeContext(name, repository.ContextProbeTimeout)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// contextSwitchTimeout bounds how long the cluster of a newly selected
+// context may take to answer before the switch is abandoned.
+const contextSwitchTimeout = 10 * time.Second
+
+// loadContextData checks that the cluster of the context the client was just
+// switched to is reachable by listing its namespaces, and fetches its nodes.
+// previous is the client state to restore when it is not.
+// Returns a contextSwitchedMsg, with err set if the cluster is unreachable.
+func (m *Model) loadContextData(previous repository.Client) tea.Cmd {
+	contextName := m.k8sClient.Context()
+	clientset := m.k8sClient.Clientset()
+	client := m.k8sClient
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), contextSwitchTimeout)
+		defer cancel()
+
+		namespaces, err := repository.ListNamespaces(ctx, clientset)
+		if err != nil {
+			return contextSwitchedMsg{context: contextName, previous: previous, err: err}
+		}
+
+		nodes, _ := repository.ListNodes(ctx, clientset)
+		// The new cluster may serve other APIs
+		_, _ = client.DiscoverAPIs()
+
+		return contextSwitchedMsg{
+			context:    contextName,
+			previous:   previous,
+			namespaces: namespaces,
+			nodes:      nodes,
+		}
 	}
 }
 
@@ -39,9 +117,7 @@ func (m *Model) loadInitialData() tea.Cmd {
 // It retrieves namespaces, nodes, pods, configmaps, and secrets for the specified namespace.
 // Returns an initialResourcesLoadedMsg with all data, or an error if critical operations fail.
 func (m *Model) loadInitialDataWithResources() tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
-
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		namespaces, err := m.k8sClient.ListNamespaces(ctx)
 		if err != nil {
 			return initialResourcesLoadedMsg{err: err}
@@ -49,46 +125,80 @@ func (m *Model) loadInitialDataWithResources() tea.Cmd {
 
 		nodes, _ := repository.ListNodes(ctx, m.k8sClient.Clientset())
 
-		// Load resources for the specified namespace
-		pods, err := repository.ListAllPods(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace())
+		// Load resources for the specified namespace; further pages of pods
+		// are fetched in the background once the view has rendered
+		pods, continueToken, err := repository.ListPodsPage(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace(), repository.DefaultPageSize, "")
 		if err != nil {
 			return initialResourcesLoadedMsg{err: err}
 		}
-		hpas, _ := repository.ListHPAs(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace())
+		hpas, _ := m.k8sClient.ListHPAs(ctx, m.k8sClient.Namespace())
 		configmaps, _ := repository.ListConfigMaps(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace())
 		secrets, _ := repository.ListSecrets(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace())
+		events, _ := repository.GetNamespaceEvents(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace(), 0)
+		probeHealth := repository.AggregateProbeHealth(pods, events, repository.DefaultProbeHealthWindow, time.Now())
 
 		return initialResourcesLoadedMsg{
-			namespaces: namespaces,
-			nodes:      nodes,
-			pods:       pods,
-			hpas:       hpas,
-			configmaps: configmaps,
-			secrets:    secrets,
+			namespaces:    namespaces,
+			nodes:         nodes,
+			pods:          pods,
+			hpas:          hpas,
+			configmaps:    configmaps,
+			secrets:       secrets,
+			probeHealth:   probeHealth,
+			continueToken: continueToken,
 		}
-	}
+	})
 }
 
-// loadWorkloads fetches all workloads of the currently selected resource type.
-// The resource type (Deployments, StatefulSets, DaemonSets, Jobs, CronJobs)
-// is determined by the navigator's current selection.
+// loadWorkloads fetches the first page of workloads of the currently selected
+// resource type. The resource type (Deployments, StatefulSets, DaemonSets,
+// Jobs, CronJobs) is determined by the navigator's current selection.
 // Also refreshes the namespace list for the selector.
-// Returns a loadedMsg with workloads and namespaces.
+// Returns a loadedMsg with workloads, namespaces and the token for the next page.
 func (m *Model) loadWorkloads() tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
-		workloads, err := repository.ListWorkloads(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace(), m.navigator.ResourceType())
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		workloads, continueToken, err := repository.ListWorkloadsPage(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace(), m.navigator.ResourceType(), repository.DefaultPageSize, "")
 		if err != nil {
 			return loadedMsg{err: err}
 		}
-
 		namespaces, _ := m.k8sClient.ListNamespaces(ctx)
 
 		return loadedMsg{
-			workloads:  workloads,
-			namespaces: namespaces,
+			workloads:     workloads,
+			namespaces:    namespaces,
+			continueToken: continueToken,
 		}
-	}
+	})
+}
+
+// loadMoreWorkloads fetches the page of workloads following token in the
+// background, while the first pages are already on screen.
+// Returns a workloadsPageMsg.
+func (m *Model) loadMoreWorkloads(namespace string, resourceType repository.ResourceType, token string) tea.Cmd {
+	clientset := m.k8sClient.Clientset()
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		workloads, next, err := repository.ListWorkloadsPage(ctx, clientset, namespace, resourceType, repository.DefaultPageSize, token)
+		return workloadsPageMsg{
+			namespace:    namespace,
+			resourceType: resourceType,
+			token:        token,
+			workloads:    workloads,
+			next:         next,
+			err:          err,
+		}
+	})
+}
+
+// loadWorkloadHealth lists the pods of namespace once, in the background
+// after the first page of workloads is on screen, so the pod breakdown of
+// every page can be computed from the same listing.
+// Returns a workloadHealthMsg.
+func (m *Model) loadWorkloadHealth(namespace string) tea.Cmd {
+	clientset := m.k8sClient.Clientset()
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		pods, err := repository.ListAllPods(ctx, clientset, namespace)
+		return workloadHealthMsg{namespace: namespace, pods: pods, err: err}
+	})
 }
 
 // loadPods fetches all pods belonging to a specific workload.
@@ -96,18 +206,30 @@ func (m *Model) loadWorkloads() tea.Cmd {
 // Also loads ConfigMaps and Secrets for the namespace to populate the resources view.
 // Returns a resourcesLoadedMsg with pods, configmaps, and secrets.
 func (m *Model) loadPods(workload *repository.WorkloadInfo) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		pods, err := repository.GetWorkloadPods(ctx, m.k8sClient.Clientset(), *workload)
 		if err != nil {
 			return resourcesLoadedMsg{err: err}
 		}
 		// Also load HPAs, ConfigMaps and Secrets
-		hpas, _ := repository.ListHPAs(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace())
+		hpas, _ := m.k8sClient.ListHPAs(ctx, m.k8sClient.Namespace())
 		configmaps, _ := repository.ListConfigMaps(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace())
 		secrets, _ := repository.ListSecrets(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace())
 		return resourcesLoadedMsg{pods: pods, hpas: hpas, configmaps: configmaps, secrets: secrets}
-	}
+	})
+}
+
+// loadWorstPod fetches the pods of a workload and picks the one most worth
+// looking at: failing first, then the most restarted.
+// Returns a worstPodMsg.
+func (m *Model) loadWorstPod(workload *repository.WorkloadInfo) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		pods, err := repository.GetWorkloadPods(ctx, m.k8sClient.Clientset(), *workload)
+		if err != nil {
+			return worstPodMsg{workload: workload, err: err}
+		}
+		return worstPodMsg{workload: workload, pod: repository.WorstPod(pods)}
+	})
 }
 
 // loadAllResources fetches all pods, configmaps, and secrets in the current namespace.
@@ -116,16 +238,36 @@ func (m *Model) loadPods(workload *repository.WorkloadInfo) tea.Cmd {
 // This allows users to scale up workloads even when no pods are running.
 // Returns a resourcesLoadedMsg with all resources and optional workload for scaling.
 func (m *Model) loadAllResources() tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
+	return m.loadResources(false)
+}
+
+// loadFirstResourcesPage is loadAllResources for entering a namespace: only
+// the first page of pods is fetched so the view renders right away, and the
+// rest follow through loadMorePods.
+// Returns a resourcesLoadedMsg with the token for the next page of pods.
+func (m *Model) loadFirstResourcesPage() tea.Cmd {
+	return m.loadResources(true)
+}
+
+func (m *Model) loadResources(firstPage bool) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		ns := m.k8sClient.Namespace()
-		pods, err := repository.ListAllPods(ctx, m.k8sClient.Clientset(), ns)
+		var pods []repository.PodInfo
+		var continueToken string
+		var err error
+		if firstPage {
+			pods, continueToken, err = repository.ListPodsPage(ctx, m.k8sClient.Clientset(), ns, repository.DefaultPageSize, "")
+		} else {
+			pods, err = repository.ListAllPods(ctx, m.k8sClient.Clientset(), ns)
+		}
 		if err != nil {
 			return resourcesLoadedMsg{err: err}
 		}
-		hpas, _ := repository.ListHPAs(ctx, m.k8sClient.Clientset(), ns)
+		hpas, _ := m.k8sClient.ListHPAs(ctx, ns)
 		configmaps, _ := repository.ListConfigMaps(ctx, m.k8sClient.Clientset(), ns)
 		secrets, _ := repository.ListSecrets(ctx, m.k8sClient.Clientset(), ns)
+		events, _ := repository.GetNamespaceEvents(ctx, m.k8sClient.Clientset(), ns, 0)
+		probeHealth := repository.AggregateProbeHealth(pods, events, repository.DefaultProbeHealthWindow, time.Now())
 
 		// Fetch first scalable workload for scale controls when pods = 0
 		var workload *repository.WorkloadInfo
@@ -150,36 +292,119 @@ func (m *Model) loadAllResources() tea.Cmd {
 			}
 		}
 
-		return resourcesLoadedMsg{pods: pods, hpas: hpas, configmaps: configmaps, secrets: secrets, workload: workload}
-	}
+		return resourcesLoadedMsg{pods: pods, hpas: hpas, configmaps: configmaps, secrets: secrets, probeHealth: probeHealth, workload: workload, continueToken: continueToken}
+	})
+}
+
+// loadMorePods fetches the page of the namespace's pods following token in
+// the background, while the first pages are already on screen.
+// Returns a podsPageMsg.
+func (m *Model) loadMorePods(namespace, token string) tea.Cmd {
+	clientset := m.k8sClient.Clientset()
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		pods, next, err := repository.ListPodsPage(ctx, clientset, namespace, repository.DefaultPageSize, token)
+		return podsPageMsg{namespace: namespace, token: token, pods: pods, next: next, err: err}
+	})
 }
 
 // loadConfigMapData fetches the full data of a specific ConfigMap.
 // This is called when user selects a ConfigMap to view its contents.
 // Returns a configMapDataMsg with the ConfigMap data including all keys and values.
 func (m *Model) loadConfigMapData(name string) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		data, err := repository.GetConfigMap(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace(), name)
 		if err != nil {
 			return configMapDataMsg{err: err}
 		}
 		return configMapDataMsg{data: data}
-	}
+	})
 }
 
 // loadHPAData fetches the full data of a specific HPA.
 // This is called when user selects an HPA to view its details.
 // Returns a hpaDataMsg with the HPA data including metrics and conditions.
-func (m *Model) loadHPAData(name string) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
-		data, err := repository.GetHPA(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace(), name)
+func (m *Model) loadHPAData(namespace, name string) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		data, err := repository.GetHPA(ctx, m.k8sClient.Clientset(), namespace, name)
 		if err != nil {
 			return hpaDataMsg{err: err}
 		}
 		return hpaDataMsg{data: data}
-	}
+	})
+}
+
+// loadWorkloadHPA finds the HPA scaling a workload and fetches its data.
+// Returns a hpaDataMsg, with an error if no HPA targets the workload.
+func (m *Model) loadWorkloadHPA(workload *repository.WorkloadInfo) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		kind := repository.KindForResourceType(workload.Type)
+		hpa, err := repository.FindHPAForWorkload(ctx, m.k8sClient.Clientset(), workload.Namespace, kind, workload.Name)
+		if err != nil {
+			return hpaDataMsg{err: err}
+		}
+		if hpa == nil {
+			return hpaDataMsg{err: fmt.Errorf("no HPA targets %s/%s", kind, workload.Name)}
+		}
+		data, err := repository.GetHPA(ctx, m.k8sClient.Clientset(), workload.Namespace, hpa.Name)
+		if err != nil {
+			return hpaDataMsg{err: err}
+		}
+		return hpaDataMsg{data: data}
+	})
+}
+
+// probeMetrics checks whether metrics-server answers, so Resource Usage
+// can tell a cluster without it from metrics still being fetched.
+// Returns a metricsStatusMsg.
+func (m *Model) probeMetrics() tea.Cmd {
+	namespace := m.k8sClient.Namespace()
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		return metricsStatusMsg{status: m.k8sClient.ProbeMetrics(ctx, namespace)}
+	})
+}
+
+// loadPodUsage fetches the CPU and memory usage of every pod in a namespace
+// with their requests and limits, for the pod metrics table.
+// Returns a podUsageMsg; its error wraps repository.ErrMetricsUnavailable
+// when the cluster has no metrics-server.
+func (m *Model) loadPodUsage(namespace string) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		// A nil *Clientset would not compare equal to a nil interface
+		var metricsClient repository.MetricsClientInterface
+		if mc := m.k8sClient.MetricsClient(); mc != nil {
+			metricsClient = mc
+		}
+		usage, err := repository.GetNamespacePodUsage(ctx, m.k8sClient.Clientset(), metricsClient, namespace)
+		return podUsageMsg{namespace: namespace, usage: usage, err: err}
+	})
+}
+
+// loadNodeCapacity fetches the requests against the allocatable capacity of
+// every node, evaluating pod's scheduling predicates on each when it is not
+// nil. Returns a nodeCapacityMsg.
+func (m *Model) loadNodeCapacity(pod *repository.PodInfo) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		nodes, err := repository.GetNodeCapacity(ctx, m.k8sClient.Clientset(), pod)
+		return nodeCapacityMsg{pod: pod, nodes: nodes, err: err}
+	})
+}
+
+// compareNamespaces loads the Deployments, ConfigMaps and Secrets of two
+// namespaces and diffs them for the namespace comparison view.
+// Returns a namespaceCompareMsg.
+func (m *Model) compareNamespaces(left, right string) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		leftSnap, err := repository.FetchNamespaceSnapshot(ctx, m.k8sClient.Clientset(), left)
+		if err != nil {
+			return namespaceCompareMsg{err: err}
+		}
+		rightSnap, err := repository.FetchNamespaceSnapshot(ctx, m.k8sClient.Clientset(), right)
+		if err != nil {
+			return namespaceCompareMsg{err: err}
+		}
+		diff := repository.CompareNamespaces(*leftSnap, *rightSnap)
+		return namespaceCompareMsg{diff: &diff}
+	})
 }
 
 // loadSecretData fetches the full data of a specific Secret.
@@ -187,28 +412,237 @@ func (m *Model) loadHPAData(name string) tea.Cmd {
 // The secret data is automatically base64 decoded for display.
 // Returns a secretDataMsg with the decoded secret data.
 func (m *Model) loadSecretData(name string) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		data, err := repository.GetSecret(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace(), name)
 		if err != nil {
 			return secretDataMsg{err: err}
 		}
 		return secretDataMsg{data: data}
-	}
+	})
 }
 
 // loadPodsByNode fetches all pods running on a specific node.
 // This is used when user selects a node in the namespace/nodes view.
 // Returns a nodePodLoadedMsg with the node name and list of pods on that node.
 func (m *Model) loadPodsByNode(nodeName string) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
 		pods, err := repository.ListPodsByNode(ctx, m.k8sClient.Clientset(), nodeName)
 		if err != nil {
 			return nodePodLoadedMsg{nodeName: nodeName, err: err}
 		}
 		return nodePodLoadedMsg{nodeName: nodeName, pods: pods}
+	})
+}
+
+// simulateDrain runs a drain dry run for the given node.
+// It only reads cluster state, so it is safe to run on any node.
+// Returns a drainSimulationMsg with the estimated placements.
+func (m *Model) simulateDrain(nodeName string) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		sim, err := repository.SimulateDrain(ctx, m.k8sClient.Clientset(), nodeName)
+		return drainSimulationMsg{sim: sim, err: err}
+	})
+}
+
+// refreshPods re-fetches only the pod list currently shown (a node's pods,
+// a workload's pods or the namespace's pods). Used right after a mutation
+// so its effect shows up without waiting for the next tick.
+func (m *Model) refreshPods() tea.Cmd {
+	if m.selectedNode != "" {
+		return m.loadPodsByNode(m.selectedNode)
+	}
+	if m.workload != nil {
+		return m.loadPods(m.workload)
+	}
+	return m.loadAllResources()
+}
+
+// refreshNodes re-fetches the node list after a node action, plus the
+// dashboard when it shows a pod, whose Node Info may have changed.
+// Returns a nodesLoadedMsg with the nodes, and a dashboardDataMsg if reloaded.
+func (m *Model) refreshNodes() tea.Cmd {
+	loadNodes := call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		nodes, err := repository.ListNodes(ctx, m.k8sClient.Clientset())
+		return nodesLoadedMsg{nodes: nodes, err: err}
+	})
+	if m.view == ViewDashboard && m.pod != nil {
+		return tea.Batch(loadNodes, m.loadDashboardData(m.pod))
 	}
+	return loadNodes
+}
+
+// refreshWorkload re-fetches a single workload after a scale or restart,
+// along with its desired replicas so a pending scale can be confirmed.
+// Returns a workloadRefreshedMsg with the workload row, or the error.
+func (m *Model) refreshWorkload(hint component.MutationHint) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		var workloads []repository.WorkloadInfo
+		var err error
+		if hint.Kind == repository.ResourceRollouts {
+			workloads, err = repository.ListRollouts(ctx, m.k8sClient.DynamicClient(), hint.Namespace)
+		} else {
+			workloads, err = repository.ListWorkloads(ctx, m.k8sClient.Clientset(), hint.Namespace, hint.Kind)
+		}
+		if err != nil {
+			return workloadRefreshedMsg{hint: hint, err: err}
+		}
+
+		msg := workloadRefreshedMsg{hint: hint}
+		for i := range workloads {
+			if workloads[i].Name == hint.Name {
+				_ = repository.LoadPodHealth(ctx, m.k8sClient.Clientset(), hint.Namespace, workloads[i:i+1])
+				msg.workload = &workloads[i]
+				break
+			}
+		}
+		if msg.workload == nil {
+			msg.err = fmt.Errorf("%s %s not found in namespace %s", hint.Kind, hint.Name, hint.Namespace)
+			return msg
+		}
+
+		switch hint.Kind {
+		case repository.ResourceRollouts:
+			msg.desired = msg.workload.Replicas // Read from the Rollout spec
+		case repository.ResourceDeployments, repository.ResourceStatefulSets:
+			msg.desired, msg.err = repository.GetDesiredReplicas(ctx, m.k8sClient.Clientset(), hint.Namespace, hint.Name, hint.Kind)
+		}
+		return msg
+	})
+}
+
+// resolveLink looks up the pod or workload a k1s:// link points at.
+// Lookup failures are reported with the object and namespace so a stale
+// or mistyped link is easy to spot.
+// Returns a deepLinkResolvedMsg with the pod or workload, or the error.
+func (m *Model) resolveLink(link *deeplink.Link) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		if link.IsPod() {
+			pod, err := repository.GetPod(ctx, m.k8sClient.Clientset(), link.Namespace, link.Name)
+			if err != nil {
+				return deepLinkResolvedMsg{link: link, err: fmt.Errorf("failed to open pod %s in namespace %s: %w", link.Name, link.Namespace, err)}
+			}
+			return deepLinkResolvedMsg{link: link, pod: pod}
+		}
+
+		resourceType := linkResourceTypes[link.Kind]
+		workloads, err := repository.ListWorkloads(ctx, m.k8sClient.Clientset(), link.Namespace, resourceType)
+		if err != nil {
+			return deepLinkResolvedMsg{link: link, err: err}
+		}
+		for i := range workloads {
+			if workloads[i].Name == link.Name {
+				return deepLinkResolvedMsg{link: link, workload: &workloads[i]}
+			}
+		}
+		return deepLinkResolvedMsg{link: link, err: fmt.Errorf("%s %s not found in namespace %s", link.Kind, link.Name, link.Namespace)}
+	})
+}
+
+// resolveTarget looks up the pod or workload given as kind/name on the
+// command line in the current namespace.
+// Returns a targetResolvedMsg with the pod, or the workload and its worst
+// pod, or the error.
+func (m *Model) resolveTarget(kind, name string) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		target, err := repository.ResolveTarget(ctx, m.k8sClient.Clientset(), m.k8sClient.Namespace(), kind, name)
+		return targetResolvedMsg{target: target, err: err}
+	})
+}
+
+// loadRecentWarnings fetches the warning events of the current namespace
+// from the last repository.RecentWarningsWindow, for the status bar badge
+// and the warnings list.
+// Returns a recentWarningsMsg with the events or the error.
+func (m *Model) loadRecentWarnings() tea.Cmd {
+	namespace := m.k8sClient.Namespace()
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		events, err := repository.GetRecentWarnings(ctx, m.k8sClient.Clientset(), namespace, repository.RecentWarningsWindow)
+		return recentWarningsMsg{namespace: namespace, events: events, err: err}
+	})
+}
+
+// resolveWarning finds the pod or workload to open for the object of a
+// warning group selected in the warnings list.
+// Returns a warningTargetMsg with the target, nil when the object can't be
+// opened.
+func (m *Model) resolveWarning(req component.JumpToWarningRequest) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		target, err := repository.ResolveEventObject(ctx, m.k8sClient.Clientset(), req.Namespace, req.Object)
+		return warningTargetMsg{object: req.Object, target: target, err: err}
+	})
+}
+
+// linkResourceTypes maps deep-link workload kinds to resource types.
+var linkResourceTypes = map[string]repository.ResourceType{
+	deeplink.KindDeployment:  repository.ResourceDeployments,
+	deeplink.KindStatefulSet: repository.ResourceStatefulSets,
+	deeplink.KindDaemonSet:   repository.ResourceDaemonSets,
+}
+
+// loadPVCDetails fetches a PVC with its StorageClass, bound PV and events.
+// Called when the PVC details view is opened and on every tick while it
+// stays open, so provisioning progress shows up without reopening it.
+// Returns a PVCDetailsMsg with the details or the error.
+func (m *Model) loadPVCDetails(namespace, name string) tea.Cmd {
+	return call(m.podCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		details, err := repository.GetPVCDetails(ctx, m.k8sClient.Clientset(), namespace, name)
+		return view.PVCDetailsMsg{Name: name, Details: details, Err: err}
+	})
+}
+
+// describeResource describes a Service, Ingress, ConfigMap or Secret
+// selected in the Resource Details view.
+// Returns a DescribeOutputMsg titled "<Kind>: <Name>".
+func (m *Model) describeResource(req view.DescribeResourceRequest) tea.Cmd {
+	return call(m.podCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		clientset := m.k8sClient.Clientset()
+		var content string
+		var err error
+		switch req.Kind {
+		case "Service":
+			content, err = repository.DescribeService(ctx, clientset, req.Namespace, req.Name)
+		case "Ingress":
+			content, err = repository.DescribeIngress(ctx, clientset, req.Namespace, req.Name)
+		case "ConfigMap":
+			content, err = repository.DescribeConfigMap(ctx, clientset, req.Namespace, req.Name)
+		case "Secret":
+			content, err = repository.DescribeSecret(ctx, clientset, req.Namespace, req.Name)
+		default:
+			err = fmt.Errorf("cannot describe %s", req.Kind)
+		}
+		return view.DescribeOutputMsg{Title: req.Kind + ": " + req.Name, Content: content, Err: err}
+	})
+}
+
+// loadConfigProjection resolves where the keys of a ConfigMap or Secret
+// land in the containers of a pod.
+// Returns a view.ConfigProjectionMsg with the projection or the error.
+func (m *Model) loadConfigProjection(req view.ConfigProjectionRequest) tea.Cmd {
+	return call(m.podCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		projection, err := repository.GetConfigProjection(ctx, m.k8sClient.Clientset(), req.Namespace, req.PodName, req.Kind, req.Name)
+		return view.ConfigProjectionMsg{PodName: req.PodName, Projection: projection, Err: err}
+	})
+}
+
+// traceIngress follows the routes of an Ingress, then those of the
+// VirtualServices serving the same hosts, to the pods behind them.
+// Returns a view.IngressTraceMsg with the traces or the error.
+func (m *Model) traceIngress(req view.IngressTraceRequest) tea.Cmd {
+	clientset := m.k8sClient.Clientset()
+	return call(m.podCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		traces, err := repository.TraceIngress(ctx, clientset, req.Namespace, req.Ingress)
+		for _, vs := range req.VirtualServices {
+			for _, route := range vs.Routes {
+				if err != nil {
+					break
+				}
+				var trace repository.RouteTrace
+				trace, err = repository.TraceVirtualServiceRoute(ctx, clientset, req.Namespace, vs, route)
+				traces = append(traces, trace)
+			}
+		}
+		return view.IngressTraceMsg{PodName: req.PodName, Ingress: req.Ingress.Name, Traces: traces, Err: err}
+	})
 }
 
 // loadDashboardData fetches all data required for the pod dashboard view.
@@ -217,38 +651,122 @@ func (m *Model) loadPodsByNode(nodeName string) tea.Cmd {
 // and node information.
 // Returns a dashboardDataMsg with all dashboard components.
 func (m *Model) loadDashboardData(pod *repository.PodInfo) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
+	// Logs come from the stream while one is followed, and compare mode
+	// and time ranges load their own
+	streaming := m.logStream != nil || m.dashboard.LogsComparing() || !m.dashboard.LogsTimeRange().IsZero()
+	workload := m.logsWorkload()
+	container := m.dashboard.LogsSelectedContainer()
+	return call(m.podCalls, repository.CallLogs, func(ctx context.Context) tea.Msg {
+		var failures []component.Notification
+		failed := func(op string, err error) {
+			if err != nil {
+				failures = append(failures, component.ErrorNotification(op, pod.Namespace, err))
+			}
+		}
 
 		// Refresh pod info for real-time status updates
-		updatedPod, _ := repository.GetPod(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name)
+		updatedPod, err := repository.GetPod(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name)
+		failed("get pod "+pod.Name, err)
 		if updatedPod == nil {
 			updatedPod = pod
 		}
+		updatedPod.PullSecrets = repository.ValidateImagePullSecrets(ctx, m.k8sClient.Clientset(), updatedPod)
 
-		logs, _ := repository.GetAllContainerLogs(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name, 200)
-		events, _ := repository.GetPodEvents(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name)
-		metrics, _ := repository.GetPodMetrics(ctx, m.k8sClient.MetricsClient(), pod.Namespace, pod.Name)
+		var logs []repository.LogLine
+		if !streaming && workload != nil {
+			logs, err = repository.GetWorkloadLogs(ctx, m.k8sClient.Clientset(), *workload, repository.LogOptions{
+				Container: container,
+				TailLines: m.logTailLines(),
+			})
+			failed("get logs of "+workload.Name, err)
+		} else if !streaming {
+			logs, err = repository.GetAllContainerLogs(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name, m.logTailLines())
+			failed("get logs of "+pod.Name, err)
+		}
+		events, err := repository.GetPodEvents(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name)
+		failed("list events of "+pod.Name, err)
+		// Probe again until metrics-server answers, so installing it
+		// mid-session fills the panel without a restart
+		metricsStatus := m.k8sClient.MetricsStatus()
+		if metricsStatus.State != repository.MetricsAvailable {
+			metricsStatus = m.k8sClient.ProbeMetrics(ctx, pod.Namespace)
+		}
+		var metrics *repository.PodMetrics
+		if metricsStatus.State == repository.MetricsAvailable {
+			var err error
+			if metrics, err = repository.GetPodMetrics(ctx, m.k8sClient.MetricsClient(), pod.Namespace, pod.Name); err != nil {
+				// Not sampled yet, or metrics-server went away since
+				metricsStatus = m.k8sClient.ProbeMetrics(ctx, pod.Namespace)
+			}
+		}
 		related, _ := repository.GetRelatedResources(ctx, m.k8sClient.Clientset(), m.k8sClient.DynamicClient(), *updatedPod)
 
 		helpers := repository.AnalyzePodIssues(updatedPod, events)
 
-		// Get node info for the pod's node
+		// For unscheduled pods, show which nodes could take them and why the others can't
+		if updatedPod.Node == "" && updatedPod.Status == "Pending" {
+			if fits, err := repository.FindFittingNodes(ctx, m.k8sClient.Clientset(), *updatedPod); err == nil {
+				helpers = append(helpers, repository.NodeFitHelper(fits))
+			}
+		}
+
+		// Classify image pull failures and cross-check pull secrets
+		imagePulls := repository.DiagnoseImagePulls(ctx, m.k8sClient.Clientset(), updatedPod, events)
+		for _, d := range imagePulls {
+			helpers = append(helpers, repository.ImagePullHelper(d))
+		}
+		pullProgress := repository.ImagePullProgressFor(updatedPod, events)
+		diagnosis := repository.DiagnoseCrash(updatedPod, events)
+		probes := repository.BuildProbeStatus(updatedPod, events)
+
+		limitRanges, _ := repository.GetContainerLimitRanges(ctx, m.k8sClient.Clientset(), pod.Namespace)
+		admission, _ := repository.GetPodSecurityAdmission(ctx, m.k8sClient.Clientset(), pod.Namespace)
+		security := repository.BuildPodSecurity(updatedPod, events, admission)
+
+		// Get node info for the pod's node, and the OOM kills it recorded
 		var node *repository.NodeInfo
+		var nodeEvents []repository.EventInfo
 		if updatedPod.Node != "" {
 			node, _ = repository.GetNode(ctx, m.k8sClient.Clientset(), updatedPod.Node)
+			nodeEvents, _ = repository.GetNodeEvents(ctx, m.k8sClient.Clientset(), updatedPod.Node)
 		}
+		oom := repository.BuildOOMReport(updatedPod, metrics, nodeEvents)
 
 		return dashboardDataMsg{
-			pod:     updatedPod,
-			logs:    logs,
-			events:  events,
-			metrics: metrics,
-			related: related,
-			helpers: helpers,
-			node:    node,
+			pod:           updatedPod,
+			logs:          logs,
+			workloadLogs:  workload != nil,
+			events:        events,
+			metrics:       metrics,
+			metricsStatus: metricsStatus,
+			related:       related,
+			helpers:       helpers,
+			diagnosis:     diagnosis,
+			probes:        probes,
+			oom:           oom,
+			security:      security,
+			imagePulls:    imagePulls,
+			pullProgress:  pullProgress,
+			limitRanges:   limitRanges,
+			node:          node,
+			failures:      failures,
 		}
+	})
+}
+
+// loadAccessChecks asks the API server which of the common requests the
+// pod's ServiceAccount may make. Run once when the dashboard opens, not on
+// every refresh, since each check is a request.
+// Returns an accessChecksMsg.
+func (m *Model) loadAccessChecks(pod *repository.PodInfo) tea.Cmd {
+	serviceAccount := pod.ServiceAccount
+	if serviceAccount == "" {
+		serviceAccount = "default"
 	}
+	return call(m.podCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		checks, err := m.k8sClient.CheckAccess(ctx, pod.Namespace, serviceAccount, repository.CommonAccessChecks)
+		return accessChecksMsg{namespace: pod.Namespace, pod: pod.Name, checks: checks, err: err}
+	})
 }
 
 // loadLogsForState fetches logs based on the current dashboard state.
@@ -256,10 +774,10 @@ func (m *Model) loadDashboardData(pod *repository.PodInfo) tea.Cmd {
 // - Previous logs: fetches logs from a previous container instance (crashed/restarted)
 // - Specific container: fetches logs from a selected container in multi-container pods
 // - All containers: fetches logs from all containers when no specific one is selected
+// A non-zero timeRange limits each of them to the lines written within it.
 // Returns a logsUpdatedMsg with the fetched log lines.
-func (m *Model) loadLogsForState(pod *repository.PodInfo, container string, previous bool) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
+func (m *Model) loadLogsForState(pod *repository.PodInfo, container string, previous bool, timeRange repository.LogTimeRange) tea.Cmd {
+	return call(m.podCalls, repository.CallLogs, func(ctx context.Context) tea.Msg {
 		var logs []repository.LogLine
 		var err error
 
@@ -270,27 +788,81 @@ func (m *Model) loadLogsForState(pod *repository.PodInfo, container string, prev
 				targetContainer = pod.Containers[0].Name
 			}
 			if targetContainer != "" {
-				logs, err = repository.GetPreviousLogs(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name, targetContainer, 200)
+				opts := timeRange.Options(repository.LogOptions{
+					Container:  targetContainer,
+					TailLines:  m.logTailLines(),
+					Previous:   true,
+					Timestamps: true,
+				})
+				logs, err = repository.GetPodLogs(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name, opts)
 			}
 		} else if container != "" {
 			// Get logs for specific container
-			opts := repository.LogOptions{
+			opts := timeRange.Options(repository.LogOptions{
 				Container:  container,
-				TailLines:  200,
+				TailLines:  m.logTailLines(),
 				Timestamps: true,
-			}
+			})
 			logs, err = repository.GetPodLogs(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name, opts)
 		} else {
 			// Get all container logs
-			logs, err = repository.GetAllContainerLogs(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name, 200)
+			logs, err = repository.GetAllContainerLogsInRange(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name, m.logTailLines(), timeRange)
 		}
 
 		if err != nil {
-			return logsUpdatedMsg{logs: []repository.LogLine{{Content: "Error fetching logs: " + err.Error(), IsError: true}}}
+			return logsUpdatedMsg{logs: logsError(err), timeRange: timeRange}
 		}
 
-		return logsUpdatedMsg{logs: logs}
-	}
+		return logsUpdatedMsg{logs: logs, timeRange: timeRange}
+	})
+}
+
+// loadWorkloadLogs fetches the logs of every pod of a workload, merged by
+// timestamp, for the logs panel's workload mode. container selects one
+// container in each pod; empty fetches all of them. A non-zero timeRange
+// limits them to the lines written within it.
+// Returns a logsUpdatedMsg with the merged log lines.
+func (m *Model) loadWorkloadLogs(workload repository.WorkloadInfo, container string, timeRange repository.LogTimeRange) tea.Cmd {
+	return call(m.podCalls, repository.CallLogs, func(ctx context.Context) tea.Msg {
+		logs, err := repository.GetWorkloadLogs(ctx, m.k8sClient.Clientset(), workload, timeRange.Options(repository.LogOptions{
+			Container: container,
+			TailLines: m.logTailLines(),
+		}))
+		if err != nil {
+			return logsUpdatedMsg{logs: logsError(err), workload: true, timeRange: timeRange}
+		}
+		return logsUpdatedMsg{logs: logs, workload: true, timeRange: timeRange}
+	})
+}
+
+// loadComparedLogs fetches the previous and current logs of a container and
+// merges them for the logs panel's compare mode. Without a selected
+// container the first one is compared, as with previous logs. A container
+// that never restarted has no previous logs, so its current logs are shown
+// alone.
+// Returns a logsComparedMsg with the merged log lines.
+func (m *Model) loadComparedLogs(pod *repository.PodInfo, container string) tea.Cmd {
+	return call(m.podCalls, repository.CallLogs, func(ctx context.Context) tea.Msg {
+		if container == "" && len(pod.Containers) > 0 {
+			container = pod.Containers[0].Name
+		}
+
+		opts := repository.LogOptions{
+			Container:  container,
+			TailLines:  m.logTailLines(),
+			Timestamps: true,
+		}
+		current, err := repository.GetPodLogs(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name, opts)
+		if err != nil {
+			return logsComparedMsg{logs: logsError(err)}
+		}
+
+		previous, err := repository.GetPreviousLogs(ctx, m.k8sClient.Clientset(), pod.Namespace, pod.Name, container, m.logTailLines())
+		if err != nil {
+			return logsComparedMsg{logs: current}
+		}
+		return logsComparedMsg{logs: repository.CompareLogs(previous, current), hasPrevious: true}
+	})
 }
 
 // filteredNodes returns the list of nodes filtered by the current search query.
@@ -310,12 +882,44 @@ func (m Model) filteredNodes() []repository.NodeInfo {
 	return filtered
 }
 
-// tickCmd creates a command that sends a tickMsg after the configured refresh interval.
-// This is used for automatic dashboard refresh to keep logs and status up to date.
-// The interval is configured in the application config (default: 5 seconds).
-func (m *Model) tickCmd() tea.Cmd {
-	return tea.Tick(time.Duration(m.config.RefreshInterval)*time.Second, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+// logTailLines returns how many lines of log history to fetch, from the
+// log_line_limit setting.
+func (m *Model) logTailLines() int64 {
+	return int64(m.settings.LogLineLimit)
+}
+
+// expireChanges schedules a redraw for when the next change highlight
+// expires. Returns nil when nothing is highlighted.
+// Returns a changesExpiredMsg when the highlight expires.
+func (m *Model) expireChanges() tea.Cmd {
+	d, ok := m.navigator.NextChangeExpiry()
+	if dd, dok := m.dashboard.NextChangeExpiry(); dok && (!ok || dd < d) {
+		d, ok = dd, true
+	}
+	if !ok {
+		return nil
+	}
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return changesExpiredMsg{}
+	})
+}
+
+// restartCountdown starts the tick that keeps the countdown to the next
+// restart of a crash looping container live, unless it already runs or no
+// container of the dashboard pod is backing off.
+// Returns a restartTickMsg every second.
+func (m *Model) restartCountdown() tea.Cmd {
+	if m.restartTicking || m.view != ViewDashboard || !m.dashboard.BackingOff() {
+		return nil
+	}
+	m.restartTicking = true
+	return restartTick()
+}
+
+// restartTick schedules the next update of the restart countdown.
+func restartTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return restartTickMsg{}
 	})
 }
 
@@ -0,0 +1,161 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains the log stream that feeds the logs panel in follow mode.
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// maxLogBatch caps how many lines one logLinesMsg carries, so a burst is
+// rendered in a few frames rather than one frame per line.
+const maxLogBatch = 500
+
+// logStream is the log stream currently followed by the logs panel.
+type logStream struct {
+	id        int // Distinguishes this stream's messages from stale ones
+	namespace string
+	pod       string
+	container string // Empty when following every container
+	workload  string // Set when following every pod of this workload
+	lines     <-chan repository.LogLine
+	cancel    context.CancelFunc
+}
+
+// syncLogStream starts, restarts or stops the log stream so that it
+// follows the dashboard pod's selected container while follow mode is on
+// and current (not previous) logs are shown without a time range. In
+// workload mode it follows that container in every pod of the workload
+// instead. While a stream runs, the logs panel is fed by it instead of by
+// the refresh tick.
+// Returns the command that reads the next lines, or nil.
+func (m *Model) syncLogStream() tea.Cmd {
+	want := m.view == ViewDashboard && m.pod != nil &&
+		m.dashboard.LogsFollowing() && !m.dashboard.LogsShowPrevious() && !m.dashboard.LogsComparing() &&
+		m.dashboard.LogsTimeRange().IsZero()
+	container := m.dashboard.LogsSelectedContainer()
+	workload := m.logsWorkload()
+	workloadName := ""
+	if workload != nil {
+		workloadName = workload.Name
+	}
+
+	if want && m.logStream != nil &&
+		m.logStream.namespace == m.pod.Namespace &&
+		m.logStream.pod == m.pod.Name &&
+		m.logStream.container == container &&
+		m.logStream.workload == workloadName {
+		return nil
+	}
+
+	m.stopLogStream()
+	if !want {
+		return nil
+	}
+
+	m.logStreamSeq++
+	opts := repository.LogOptions{Container: container, TailLines: m.logTailLines()}
+	var lines <-chan repository.LogLine
+	var cancel context.CancelFunc
+	if workload != nil {
+		lines, cancel = repository.StreamWorkloadLogs(context.Background(), m.k8sClient.Clientset(), *workload, opts)
+	} else {
+		lines, cancel = repository.StreamPodLogs(context.Background(), m.k8sClient.Clientset(), m.pod.Namespace, m.pod.Name, opts)
+	}
+	m.logStream = &logStream{
+		id:        m.logStreamSeq,
+		namespace: m.pod.Namespace,
+		pod:       m.pod.Name,
+		container: container,
+		workload:  workloadName,
+		lines:     lines,
+		cancel:    cancel,
+	}
+	// The stream starts with its own history
+	m.dashboard.SetLogs(nil)
+	return waitForLogLines(m.logStream)
+}
+
+// logsWorkload returns the workload whose pods' logs the logs panel merges,
+// or nil when it shows the dashboard pod's own logs.
+func (m *Model) logsWorkload() *repository.WorkloadInfo {
+	if !m.dashboard.LogsWorkloadMode() {
+		return nil
+	}
+	return m.podWorkload(m.pod)
+}
+
+// podWorkload returns the workload selected in the navigator if pod is one
+// of its pods, or nil. A pod opened from a node or a k1s:// link has none.
+func (m *Model) podWorkload(pod *repository.PodInfo) *repository.WorkloadInfo {
+	if pod == nil || m.workload == nil || m.workload.Type == repository.ResourcePods ||
+		m.workload.Namespace != pod.Namespace || len(m.workload.Labels) == 0 {
+		return nil
+	}
+	for k, v := range m.workload.Labels {
+		if pod.Labels[k] != v {
+			return nil
+		}
+	}
+	return m.workload
+}
+
+// followedContainerRestarted reports whether a container the log stream
+// follows restarted between two refreshes of the same pod, so the stream
+// can reattach to the new instance.
+func (m *Model) followedContainerRestarted(prev, pod *repository.PodInfo) bool {
+	if m.logStream == nil || m.logStream.workload != "" || prev == nil || pod == nil ||
+		prev.Namespace != pod.Namespace || prev.Name != pod.Name {
+		return false
+	}
+	restarts := make(map[string]int32)
+	for _, c := range append(append([]repository.ContainerInfo(nil), prev.InitContainers...), prev.Containers...) {
+		restarts[c.Name] = c.RestartCount
+	}
+	for _, c := range append(append([]repository.ContainerInfo(nil), pod.InitContainers...), pod.Containers...) {
+		if m.logStream.container != "" && c.Name != m.logStream.container {
+			continue
+		}
+		if c.RestartCount > restarts[c.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+// stopLogStream cancels the current log stream, if any. Its goroutines
+// exit and its channel is closed shortly after.
+func (m *Model) stopLogStream() {
+	if m.logStream != nil {
+		m.logStream.cancel()
+		m.logStream = nil
+	}
+}
+
+// waitForLogLines blocks until the stream has lines, then takes every line
+// already buffered (up to maxLogBatch).
+// Returns a logLinesMsg, with closed set once the stream has stopped.
+func waitForLogLines(s *logStream) tea.Cmd {
+	id, ch := s.id, s.lines
+	return func() tea.Msg {
+		line, ok := <-ch
+		if !ok {
+			return logLinesMsg{stream: id, closed: true}
+		}
+		lines := []repository.LogLine{line}
+		for len(lines) < maxLogBatch {
+			select {
+			case line, ok := <-ch:
+				if !ok {
+					return logLinesMsg{stream: id, lines: lines, closed: true}
+				}
+				lines = append(lines, line)
+			default:
+				return logLinesMsg{stream: id, lines: lines}
+			}
+		}
+		return logLinesMsg{stream: id, lines: lines}
+	}
+}
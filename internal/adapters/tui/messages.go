@@ -4,79 +4,216 @@
 package tui
 
 import (
-	"time"
-
+	"github.com/andrebassi/k1s/internal/adapters/deeplink"
 	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
 )
 
 // loadedMsg is sent when initial data loading completes.
 // Contains namespace list, node list, and optionally workload list.
 // Used during application startup and namespace/resource refresh.
 type loadedMsg struct {
-	workloads  []repository.WorkloadInfo    // Workloads for current view (Deployments, StatefulSets, etc.)
-	namespaces []repository.NamespaceInfo   // Available namespaces with status in the cluster
-	nodes      []repository.NodeInfo        // Cluster nodes with status and resource info
-	err        error                        // Error if data loading failed
+	workloads     []repository.WorkloadInfo  // Workloads for current view (Deployments, StatefulSets, etc.)
+	namespaces    []repository.NamespaceInfo // Available namespaces with status in the cluster
+	nodes         []repository.NodeInfo      // Cluster nodes with status and resource info
+	continueToken string                     // Token for the next page of workloads, empty when all are loaded
+	err           error                      // Error if data loading failed
+}
+
+// connectionCheckedMsg is sent when the API server answered the connection
+// check, or failed to.
+type connectionCheckedMsg struct {
+	err error // Why the API server could not be reached, nil when it answered
+}
+
+// contextsLoadedMsg is sent when the kubeconfig contexts are listed for the
+// cluster switcher.
+type contextsLoadedMsg struct {
+	contexts []string // Context names, sorted alphabetically
+	err      error    // Error if the kubeconfig could not be read
+}
+
+// contextProbedMsg is sent when the cluster of a context answered the
+// cluster switcher's probe, or failed to.
+type contextProbedMsg struct {
+	health repository.ContextHealth
+}
+
+// contextSwitchedMsg is sent when the cluster of a newly selected context has
+// been probed. On error the client is restored to previous.
+type contextSwitchedMsg struct {
+	context    string                     // Context switched to
+	previous   repository.Client          // Client state to restore if the context is unreachable
+	namespaces []repository.NamespaceInfo // Namespaces of the new context
+	nodes      []repository.NodeInfo      // Nodes of the new context
+	err        error                      // Error if the cluster could not be reached
 }
 
 // resourcesLoadedMsg is sent when namespace resources are loaded.
 // Contains pods, HPAs, configmaps, and secrets for the selected namespace.
 // Also includes the first scalable workload when no pods exist (for scale-up feature).
 type resourcesLoadedMsg struct {
-	pods       []repository.PodInfo       // Pods in the namespace (all or filtered by workload)
-	hpas       []repository.HPAInfo       // HPAs in the namespace
-	configmaps []repository.ConfigMapInfo // ConfigMaps in the namespace
-	secrets    []repository.SecretInfo    // Secrets in the namespace
-	workload   *repository.WorkloadInfo   // First scalable workload for scale controls when pods=0
-	err        error                      // Error if resource loading failed
+	pods          []repository.PodInfo             // Pods in the namespace (all or filtered by workload)
+	hpas          []repository.HPAInfo             // HPAs in the namespace
+	configmaps    []repository.ConfigMapInfo       // ConfigMaps in the namespace
+	secrets       []repository.SecretInfo          // Secrets in the namespace
+	probeHealth   []repository.WorkloadProbeHealth // Readiness probe health per workload
+	workload      *repository.WorkloadInfo         // First scalable workload for scale controls when pods=0
+	continueToken string                           // Token for the next page of pods, empty when all are loaded
+	err           error                            // Error if resource loading failed
+}
+
+// podsPageMsg is sent when a further page of the namespace's pods has been
+// fetched in the background. Pages for a namespace or token that is no
+// longer current are dropped.
+type podsPageMsg struct {
+	namespace string               // Namespace the page was fetched from
+	token     string               // Continue token the page was requested with
+	pods      []repository.PodInfo // Pods on this page
+	next      string               // Token for the following page, empty on the last one
+	err       error                // Error if the page could not be fetched
+}
+
+// workloadsPageMsg is sent when a further page of workloads has been
+// fetched in the background.
+type workloadsPageMsg struct {
+	namespace    string                    // Namespace the page was fetched from
+	resourceType repository.ResourceType   // Resource type being listed
+	token        string                    // Continue token the page was requested with
+	workloads    []repository.WorkloadInfo // Workloads on this page
+	next         string                    // Token for the following page, empty on the last one
+	err          error                     // Error if the page could not be fetched
+}
+
+// workloadHealthMsg is sent when the pods of a namespace have been listed
+// to compute the pod breakdown of its workloads.
+type workloadHealthMsg struct {
+	namespace string               // Namespace the pods were listed from
+	pods      []repository.PodInfo // All pods of the namespace
+	err       error                // Error if the pods could not be listed
+}
+
+// accessChecksMsg is sent when the common access checks of a pod's
+// ServiceAccount have been answered.
+type accessChecksMsg struct {
+	namespace string                   // Namespace of the pod
+	pod       string                   // Pod whose ServiceAccount was checked
+	checks    []repository.AccessCheck // Checks answered before any error
+	err       error                    // Error if the checks could not all be answered
 }
 
 // dashboardDataMsg is sent when pod dashboard data is ready.
 // Contains all information needed to render the 4-panel pod debugging dashboard:
 // logs, events, metrics, related resources, debug helpers, and node info.
 type dashboardDataMsg struct {
-	pod     *repository.PodInfo         // Updated pod information with current status
-	logs    []repository.LogLine        // Container logs (last N lines from all containers)
-	events  []repository.EventInfo      // Pod events (warnings and normal events)
-	metrics *repository.PodMetrics      // CPU/Memory usage metrics from metrics-server
-	related *repository.RelatedResources // Related Services, Ingresses, VirtualServices, Gateways
-	helpers []repository.DebugHelper    // Debug hints based on pod state analysis
-	node    *repository.NodeInfo        // Node information where pod is running
+	pod           *repository.PodInfo              // Updated pod information with current status
+	logs          []repository.LogLine             // Container logs (last N lines from all containers)
+	workloadLogs  bool                             // logs are merged from every pod of the workload
+	events        []repository.EventInfo           // Pod events (warnings and normal events)
+	metrics       *repository.PodMetrics           // CPU/Memory usage metrics from metrics-server
+	metricsStatus repository.MetricsStatus         // Whether metrics-server answers
+	related       *repository.RelatedResources     // Related Services, Ingresses, VirtualServices, Gateways
+	helpers       []repository.DebugHelper         // Debug hints based on pod state analysis
+	diagnosis     *repository.CrashDiagnosis       // Most likely root cause of a failing pod (nil if healthy)
+	probes        []repository.ProbeStatus         // Status of every container probe
+	oom           *repository.OOMReport            // OOM kills and memory headroom of each container
+	security      *repository.PodSecurityReport    // Effective security context and Pod Security Admission checks
+	imagePulls    []repository.ImagePullDiagnosis  // Classified image pull failures per container
+	pullProgress  []repository.ImagePullProgress   // Image pull progress of containers being created
+	limitRanges   []repository.ContainerLimitRange // Container LimitRanges in the pod's namespace
+	node          *repository.NodeInfo             // Node information where pod is running
+	failures      []component.Notification         // Requests that failed, for the error log
 }
 
 // logsUpdatedMsg is sent when container logs are refreshed.
 // Used for log refresh operations (specific container, previous logs, time filter).
 type logsUpdatedMsg struct {
-	logs []repository.LogLine // Updated log lines
+	logs      []repository.LogLine    // Updated log lines
+	workload  bool                    // Merged from every pod of the workload
+	timeRange repository.LogTimeRange // Window fetched, zero for the latest logs
+}
+
+// logsComparedMsg is sent when previous and current logs were fetched for
+// the logs panel's compare mode.
+type logsComparedMsg struct {
+	logs        []repository.LogLine // Merged by repository.CompareLogs
+	hasPrevious bool                 // False when the container has not restarted
 }
 
 // podDeletedMsg is sent when a pod deletion operation completes.
 // Contains the result of the delete operation (success or error).
 type podDeletedMsg struct {
-	namespace string // Namespace where the pod was deleted
-	podName   string // Name of the deleted pod
-	err       error  // Error if deletion failed (nil on success)
+	namespace   string                 // Namespace where the pod was deleted
+	podName     string                 // Name of the deleted pod
+	uid         string                 // UID of the deleted pod, to check it is gone
+	gracePeriod int64                  // Seconds the pod has to stop
+	forced      bool                   // Deleted without waiting for the kubelet
+	hint        component.MutationHint // Object to re-fetch and reconcile
+	err         error                  // Error if deletion failed (nil on success)
+}
+
+// podStillTerminatingMsg is sent when a deleted pod is still there well
+// after its grace period, typically because its node is unreachable.
+type podStillTerminatingMsg struct {
+	namespace string
+	podName   string
 }
 
 // workloadActionMsg is sent when a workload action (scale/restart) completes.
 // Contains the result of the operation and details about the workload affected.
 type workloadActionMsg struct {
-	action       string                  // Action performed: "scale" or "restart"
+	action       string                  // Action performed: "scale", "restart", "rollback", "partition", or for Rollouts "promote", "abort", "retry"
 	workloadName string                  // Name of the workload
 	namespace    string                  // Namespace of the workload
 	resourceType repository.ResourceType // Type: Deployment, StatefulSet, etc.
 	replicas     int32                   // New replica count (only for scale action)
+	previous     int32                   // Replica count before the scale (only for scale action)
+	undoable     bool                    // The scale can be undone; false when it undoes one
+	revision     int64                   // Target revision (only for rollback action)
+	partition    int32                   // New partition (only for partition action)
+	hint         component.MutationHint  // Object to re-fetch and reconcile
 	err          error                   // Error if action failed (nil on success)
 }
 
-// tickMsg is sent periodically for automatic dashboard refresh.
-// The time value indicates when the tick was generated.
-type tickMsg time.Time
+// workloadRefreshedMsg is sent when a single workload is re-fetched after
+// a scale or restart, instead of reloading the whole list.
+type workloadRefreshedMsg struct {
+	hint     component.MutationHint   // Workload that was refreshed
+	workload *repository.WorkloadInfo // Fresh row for the workload list
+	desired  int32                    // Spec replicas, to confirm a scale
+	err      error                    // Error if the workload could not be fetched
+}
+
+// undoTickMsg is sent every second while the status bar counts down the
+// time left to undo a scale.
+type undoTickMsg struct{}
+
+// restartTickMsg is sent every second while a container of the dashboard
+// pod is in CrashLoopBackOff, to count down to its next restart.
+type restartTickMsg struct{}
 
 // clearStatusMsg is sent to clear the status message after a delay.
 // Used to auto-dismiss success/error messages in the status bar.
 type clearStatusMsg struct{}
 
+// logLinesMsg carries lines read from the followed log stream.
+type logLinesMsg struct {
+	stream int                  // ID of the stream the lines came from
+	lines  []repository.LogLine // New lines, oldest first
+	closed bool                 // The stream has stopped; no more lines follow
+}
+
+// eventsWatchedMsg carries events read from the dashboard pod's event watch.
+type eventsWatchedMsg struct {
+	watch  int                    // ID of the watch the events came from
+	events []repository.EventInfo // New and updated events
+	closed bool                   // The watch has stopped; no more events follow
+}
+
+// changesExpiredMsg is sent when a change highlight expires, so the
+// tint disappears without waiting for the next refresh or key press.
+type changesExpiredMsg struct{}
+
 // configMapDataMsg is sent when a ConfigMap's data is fetched.
 // Contains the full ConfigMap data with all keys and values.
 type configMapDataMsg struct {
@@ -91,6 +228,27 @@ type secretDataMsg struct {
 	err  error                  // Error if fetch failed
 }
 
+// configValueEditedMsg is sent when the editor opened on a ConfigMap or
+// Secret value exits.
+type configValueEditedMsg struct {
+	edit  configEdit // The edit, with the value before editing
+	value string     // Value saved in the editor
+	err   error      // Error if the editor could not be run
+}
+
+// configValueSavedMsg is sent when an edited value has been applied.
+type configValueSavedMsg struct {
+	edit configEdit // The applied edit
+	err  error      // Error if the update failed, possibly a conflict
+}
+
+// configDeletedMsg is sent when a ConfigMap or Secret has been deleted.
+type configDeletedMsg struct {
+	secret bool   // A Secret rather than a ConfigMap
+	name   string // Name of the deleted object
+	err    error  // Error if deletion failed
+}
+
 // nodePodLoadedMsg is sent when pods for a specific node are loaded.
 // Used when user selects a node to see all pods running on that node.
 type nodePodLoadedMsg struct {
@@ -103,20 +261,23 @@ type nodePodLoadedMsg struct {
 // Used when application starts with -n flag to go directly to resources view.
 // Contains both cluster-level data (namespaces, nodes) and namespace resources.
 type initialResourcesLoadedMsg struct {
-	namespaces []repository.NamespaceInfo // Available namespaces with status in the cluster
-	nodes      []repository.NodeInfo      // Cluster nodes with status info
-	pods       []repository.PodInfo       // Pods in the specified namespace
-	hpas       []repository.HPAInfo       // HPAs in the specified namespace
-	configmaps []repository.ConfigMapInfo // ConfigMaps in the namespace
-	secrets    []repository.SecretInfo    // Secrets in the namespace
-	err        error                      // Error if loading failed
+	namespaces    []repository.NamespaceInfo       // Available namespaces with status in the cluster
+	nodes         []repository.NodeInfo            // Cluster nodes with status info
+	pods          []repository.PodInfo             // Pods in the specified namespace
+	hpas          []repository.HPAInfo             // HPAs in the specified namespace
+	configmaps    []repository.ConfigMapInfo       // ConfigMaps in the namespace
+	secrets       []repository.SecretInfo          // Secrets in the namespace
+	probeHealth   []repository.WorkloadProbeHealth // Readiness probe health per workload
+	continueToken string                           // Token for the next page of pods, empty when all are loaded
+	err           error                            // Error if loading failed
 }
 
 // namespaceDeletedMsg is sent when a namespace force delete operation completes.
 // Used for removing stuck Terminating namespaces.
 type namespaceDeletedMsg struct {
-	namespace string // Name of the deleted namespace
-	err       error  // Error if deletion failed (nil on success)
+	namespace   string // Name of the deleted namespace
+	err         error  // Error if deletion failed (nil on success)
+	terminating bool   // Deleted the ordinary way, so still Terminating
 }
 
 // hpaDataMsg is sent when an HPA's data is fetched.
@@ -125,3 +286,329 @@ type hpaDataMsg struct {
 	data *repository.HPAData // HPA data including metrics and conditions
 	err  error               // Error if fetch failed
 }
+
+// namespaceCompareMsg is sent when two namespaces were loaded and compared.
+type namespaceCompareMsg struct {
+	diff *repository.NamespaceDiff // Objects of both namespaces and how they differ
+	err  error                     // Error if either namespace could not be read
+}
+
+// metricsStatusMsg is sent when the metrics API was probed at startup.
+type metricsStatusMsg struct {
+	status repository.MetricsStatus
+}
+
+// podUsageMsg is sent when the pod metrics table of a namespace is loaded.
+type podUsageMsg struct {
+	namespace string                // Namespace the usage was loaded for
+	usage     []repository.PodUsage // CPU and memory usage with requests and limits per pod
+	err       error                 // Error if metrics or pods could not be read
+}
+
+// nodeCapacityMsg is sent when the node capacity table is loaded.
+type nodeCapacityMsg struct {
+	pod   *repository.PodInfo       // Pod evaluated on each node, nil for none
+	nodes []repository.NodeCapacity // Requests vs allocatable per node
+	err   error                     // Error if nodes or pods could not be listed
+}
+
+// drainSimulationMsg is sent when a node drain dry run completes.
+// Nothing is changed in the cluster; the result is only displayed.
+type drainSimulationMsg struct {
+	sim *repository.DrainSimulation // Simulated evictions and placements
+	err error                       // Error if the simulation failed
+}
+
+// deploymentHistoryMsg is sent when the revisions of a Deployment are loaded.
+type deploymentHistoryMsg struct {
+	workload *repository.WorkloadInfo  // Deployment the history belongs to
+	history  []repository.RevisionInfo // Revisions, newest first
+	err      error                     // Error if the history could not be loaded
+}
+
+// statefulSetDetailsMsg is sent when the ordinals of a StatefulSet are loaded.
+type statefulSetDetailsMsg struct {
+	workload   *repository.WorkloadInfo       // StatefulSet the details belong to
+	details    *repository.StatefulSetDetails // Ordinals, claims and update strategy
+	partitions bool                           // Open the partition picker instead of the details
+	err        error                          // Error if the details could not be loaded
+}
+
+// workloadImagesMsg is sent when the images of a workload's containers are loaded.
+type workloadImagesMsg struct {
+	workload *repository.WorkloadInfo    // Workload the images belong to
+	images   []repository.ContainerImage // Image per container, init containers first
+	err      error                       // Error if the pod template could not be read
+}
+
+// imageSetMsg is sent when the image of a workload's container is changed.
+type imageSetMsg struct {
+	change imageChange // Container and the image it was set to
+	err    error       // Error if the patch failed
+}
+
+// imageRolloutMsg is sent when polling the rollout of a new image.
+type imageRolloutMsg struct {
+	change   imageChange                // Image change being rolled out
+	attempt  int                        // Number of polls so far
+	progress repository.RolloutProgress // Updated and ready replicas
+	err      error                      // Error if the workload could not be read
+}
+
+// workloadResourcesMsg is sent when the requests, limits and usage of a
+// workload's containers are loaded.
+type workloadResourcesMsg struct {
+	workload   *repository.WorkloadInfo        // Workload the containers belong to
+	containers []repository.ContainerResources // Per container, init containers first
+	err        error                           // Error if the pod template could not be read
+}
+
+// helmReleaseMsg is sent when the Helm release that installed a workload
+// is read.
+type helmReleaseMsg struct {
+	workload *repository.WorkloadInfo // Workload the release installed
+	release  *repository.HelmRelease  // Latest revision of the release
+	err      error                    // Error if its Secrets could not be read or decoded
+}
+
+// resourcesSetMsg is sent when the requests and limits of a workload's
+// container are changed.
+type resourcesSetMsg struct {
+	change resourcesChange // Container and the values it was set to
+	err    error           // Error if the patch failed
+}
+
+// resourceYAMLMsg is sent when the YAML of a pod or workload is fetched,
+// and written to a file when saving.
+type resourceYAMLMsg struct {
+	kind string // Kind of the resource, e.g. "Deployment"
+	name string // Name of the resource
+	yaml string // Serialized object
+	path string // Absolute path of the written file, "" when copying
+	err  error  // Error if the YAML could not be fetched or written
+}
+
+// panelSavedMsg is sent when the logs or events panel has been written to
+// a file, or could not be.
+type panelSavedMsg struct {
+	target panelSaveTarget // With the absolute path of the file
+	bytes  int             // Bytes written
+	exists bool            // The file exists and was left alone
+	err    error
+}
+
+// cronJobTriggeredMsg is sent when a Job has been created from a CronJob.
+type cronJobTriggeredMsg struct {
+	namespace string // Namespace of the CronJob
+	cronJob   string // Name of the CronJob
+	job       string // Name of the created Job
+	err       error  // Error if the Job could not be created
+}
+
+// cronJobRunsMsg is sent when the schedule and runs of a CronJob have been
+// fetched.
+type cronJobRunsMsg struct {
+	namespace string                      // Namespace of the CronJob
+	cronJob   string                      // Name of the CronJob
+	schedule  *repository.CronJobSchedule // Schedule with the last and next run times
+	runs      []repository.JobRun         // Jobs owned by the CronJob, newest first
+	err       error                       // Error if the CronJob or its Jobs could not be fetched
+}
+
+// daemonSetNodesMsg is sent when the pods of a DaemonSet have been joined
+// with the nodes of the cluster.
+type daemonSetNodesMsg struct {
+	workload *repository.WorkloadInfo
+	rollout  *repository.DaemonSetNodes
+	err      error
+}
+
+// daemonSetPodMsg is sent when a pod picked in the DaemonSet nodes viewer
+// has been fetched to open its dashboard.
+type daemonSetPodMsg struct {
+	workload *repository.WorkloadInfo
+	pod      *repository.PodInfo
+	err      error
+}
+
+// jobRunPodMsg is sent when the pod of a CronJob run has been fetched to
+// open its logs.
+type jobRunPodMsg struct {
+	job string              // Name of the Job
+	pod *repository.PodInfo // The Job's newest pod, nil once it was deleted
+	err error               // Error if the pods could not be listed
+}
+
+// jobPodMsg is sent when polling for the pod of a triggered Job.
+type jobPodMsg struct {
+	namespace string              // Namespace of the Job
+	job       string              // Name of the Job
+	attempt   int                 // Number of polls so far
+	pod       *repository.PodInfo // The Job's pod, nil if not created yet
+	err       error               // Error if the pods could not be listed
+}
+
+// worstPodMsg is sent when the pods of a workload have been loaded to jump
+// to the one most worth looking at.
+type worstPodMsg struct {
+	workload *repository.WorkloadInfo // The workload selected
+	pod      *repository.PodInfo      // Its worst pod, nil if it has none
+	err      error                    // Error if the pods could not be listed
+}
+
+// nodeCordonedMsg is sent when a node has been cordoned or uncordoned.
+type nodeCordonedMsg struct {
+	node   string // Name of the node
+	cordon bool   // True for cordon, false for uncordon
+	err    error  // Error if the update failed
+}
+
+// nodesLoadedMsg is sent when the node list is re-fetched after a node
+// action.
+type nodesLoadedMsg struct {
+	nodes []repository.NodeInfo // All cluster nodes
+	err   error                 // Error if listing failed
+}
+
+// snapshotProgressMsg is sent when a snapshot export starts gathering a
+// section.
+type snapshotProgressMsg struct {
+	pod     string // Pod being exported
+	section string // Section being gathered, e.g. "events"
+}
+
+// sessionRecordFailedMsg is sent when a snapshot of a session recorded
+// with --record could not be written.
+type sessionRecordFailedMsg struct {
+	err error
+}
+
+// snapshotFinishedMsg is sent when a snapshot export completes.
+type snapshotFinishedMsg struct {
+	pod      string                       // Exported pod
+	path     string                       // Absolute path of the archive or directory
+	manifest *repository.SnapshotManifest // Files written and sections skipped
+	err      error                        // Error if the pod could not be read or the snapshot written
+}
+
+// containerDirMsg is sent when a directory of the container open in the
+// file browser has been listed.
+type containerDirMsg struct {
+	pod       string                     // Pod browsed
+	container string                     // Container browsed
+	path      string                     // Directory listed
+	entries   []repository.ContainerFile // Directory entries, directories first
+	err       error                      // Error if the directory could not be listed
+}
+
+// containerFileMsg is sent when a file of the container open in the file
+// browser has been read for its preview.
+type containerFileMsg struct {
+	pod       string                           // Pod browsed
+	container string                           // Container browsed
+	path      string                           // File read
+	content   *repository.ContainerFileContent // Start of the file
+	err       error                            // Error if the file could not be read
+}
+
+// containerCopyMsg is sent when a copy out of a container completes.
+type containerCopyMsg struct {
+	path  string // Path copied in the container
+	local string // Local path written
+	err   error  // Error if the copy failed
+}
+
+// drainProgressMsg is sent when a pod of a draining node changes state.
+type drainProgressMsg struct {
+	node string                      // Node being drained
+	pod  repository.DrainPodProgress // New state of the pod
+}
+
+// drainFinishedMsg is sent when a node drain completes.
+type drainFinishedMsg struct {
+	node   string                  // Drained node
+	result *repository.DrainResult // Evicted, skipped and failed pods
+	err    error                   // Error if the node could not be cordoned or listed
+}
+
+// bulkDeleteRequest is the ConfirmResult data for deleting the pods
+// selected in the pod list.
+type bulkDeleteRequest struct {
+	namespace string
+	names     []string
+}
+
+// bulkDeleteProgressMsg is sent when the delete of one pod of a bulk
+// delete finishes.
+type bulkDeleteProgressMsg struct {
+	result repository.PodDeleteResult
+}
+
+// bulkDeleteFinishedMsg is sent when every pod of a bulk delete was
+// attempted.
+type bulkDeleteFinishedMsg struct {
+	results []repository.PodDeleteResult
+	err     error // The failures joined, nil when every pod was deleted
+}
+
+// deepLinkResolvedMsg is sent when the k1s:// link passed on the command
+// line has been looked up. Exactly one of pod and workload is set on success.
+type deepLinkResolvedMsg struct {
+	link     *deeplink.Link           // The link being opened
+	pod      *repository.PodInfo      // Linked pod (pod links)
+	workload *repository.WorkloadInfo // Linked workload (workload links)
+	err      error                    // Error if the object could not be found
+}
+
+// targetResolvedMsg is sent when the pod or workload given as kind/name
+// on the command line has been looked up.
+type targetResolvedMsg struct {
+	target *repository.Target // The pod, or the workload and its worst pod
+	err    error              // Error if the object could not be found
+}
+
+// recentWarningsMsg is sent when the recent warning events of a namespace
+// have been fetched.
+type recentWarningsMsg struct {
+	namespace string                 // Namespace the events were listed from
+	events    []repository.EventInfo // Warning events, most recent first
+	err       error                  // Error if the events could not be listed
+}
+
+// warningTargetMsg is sent when the object of a warning group has been
+// looked up to jump to it.
+type warningTargetMsg struct {
+	object string             // The object, as "Kind/name"
+	target *repository.Target // What to open; nil when the object can't be opened
+	err    error              // Error if the lookup failed
+}
+
+// portForwardStoppedMsg is sent when a port-forward has been stopped from
+// the port-forwards list.
+type portForwardStoppedMsg struct {
+	forward repository.PortForward // The forward that was stopped
+	err     error                  // Error if there was no such forward
+}
+
+// watchesCheckedMsg is sent when the watches have been checked against
+// the pods and events of their targets.
+type watchesCheckedMsg struct {
+	results []watchResult
+}
+
+// watchResult is the check of one watch.
+type watchResult struct {
+	id     int    // ID of the watch
+	fired  bool   // The condition holds
+	detail string // Why it fired, for the notification
+	gone   bool   // The watched pod no longer exists
+}
+
+// paletteIndexMsg is sent when the workloads of a namespace have been
+// listed for the command palette.
+type paletteIndexMsg struct {
+	context   string                  // Context they were listed from
+	namespace string                  // Namespace listed
+	items     []component.PaletteItem // Its workloads
+	err       error                   // Error if none of the kinds could be listed
+}
@@ -0,0 +1,135 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains creating and deleting namespaces from the namespaces list.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// namespaceCreatedMsg is sent when a namespace is created.
+type namespaceCreatedMsg struct {
+	namespace string // Name of the new namespace
+	err       error  // Error if creation failed (nil on success)
+}
+
+// showCreateNamespace asks for the name of a new namespace, optionally
+// followed by its labels.
+func (m *Model) showCreateNamespace() {
+	if m.refuseReadOnly("Create Namespace") {
+		return
+	}
+	m.inputDialog.Show("New Namespace", "Name, then optional labels (team=a,env=dev):", "create_namespace", "", nil)
+}
+
+// parseNamespaceInput reads "name [key=value,...]" as typed in the new
+// namespace prompt.
+func parseNamespaceInput(input string) (string, map[string]string, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return "", nil, repository.ValidateNamespaceName("")
+	}
+	name := fields[0]
+	if err := repository.ValidateNamespaceName(name); err != nil {
+		return "", nil, err
+	}
+	if len(fields) == 1 {
+		return name, nil, nil
+	}
+	nsLabels, err := labels.ConvertSelectorToLabelsMap(strings.Join(fields[1:], ","))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid labels: %w", err)
+	}
+	return name, nsLabels, nil
+}
+
+// createNamespace creates a namespace.
+// Returns a namespaceCreatedMsg with the result.
+func (m *Model) createNamespace(name string, nsLabels map[string]string) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		err := m.k8sClient.CreateNamespace(ctx, name, nsLabels)
+		return namespaceCreatedMsg{namespace: name, err: err}
+	})
+}
+
+// forceDeleteNamespaceAfter is how long a namespace must have been
+// Terminating before force delete is offered for it.
+func (m *Model) forceDeleteNamespaceAfter() time.Duration {
+	seconds := m.settings.ForceDeleteNamespaceAfter
+	if seconds <= 0 {
+		seconds = configs.DefaultForceDeleteNamespaceAfter
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// requestDeleteNamespace asks to delete a namespace. An active namespace
+// is deleted the ordinary way once its name is typed; a Terminating one
+// can only be force deleted, and only once it looks stuck.
+func (m *Model) requestDeleteNamespace(nsInfo *repository.NamespaceInfo) tea.Cmd {
+	if nsInfo.Status == "Active" {
+		return m.confirmDialog.Request(
+			m.confirmLevel(nsInfo.Name, configs.ActionDeleteNamespace),
+			"Delete Namespace",
+			fmt.Sprintf("Delete namespace '%s' and everything in it?\nIt stays Terminating until its resources are gone.", nsInfo.Name),
+			"delete_namespace",
+			nsInfo.Name,
+			nsInfo,
+		)
+	}
+	after := m.forceDeleteNamespaceAfter()
+	if !nsInfo.CanForceDelete(after, time.Now()) {
+		m.statusMsg = fmt.Sprintf("Namespace %s is terminating; force delete is offered after %s", nsInfo.Name, after)
+		if !nsInfo.DeletionTimestamp.IsZero() {
+			m.statusMsg = fmt.Sprintf("Namespace %s is terminating for %s; force delete is offered after %s",
+				nsInfo.Name, time.Since(nsInfo.DeletionTimestamp).Round(time.Second), after)
+		}
+		return clearStatusAfter(5 * time.Second)
+	}
+	// Ask for confirmation at the configured level
+	return m.confirmDialog.Request(
+		m.confirmLevel(nsInfo.Name, configs.ActionForceDeleteNamespace),
+		fmt.Sprintf("Force delete namespace '%s'?", nsInfo.Name),
+		"This will remove all resources and finalizers.",
+		"force_delete_namespace",
+		nsInfo.Name,
+		nsInfo,
+	)
+}
+
+// deleteNamespace deletes a namespace the ordinary way.
+// Returns a namespaceDeletedMsg with the result.
+func (m *Model) deleteNamespace(namespace string) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		err := m.k8sClient.DeleteNamespace(ctx, namespace)
+		return namespaceDeletedMsg{namespace: namespace, err: err, terminating: true}
+	})
+}
+
+// setNamespaceStatus shows a namespace in the namespaces list with status
+// until the list is loaded again, adding it when it isn't listed yet.
+func (m *Model) setNamespaceStatus(name, status string) {
+	namespaces := append([]repository.NamespaceInfo(nil), m.navigator.GetNamespaces()...)
+	for i := range namespaces {
+		if namespaces[i].Name == name {
+			namespaces[i].Status = status
+			if status == "Terminating" && namespaces[i].DeletionTimestamp.IsZero() {
+				namespaces[i].DeletionTimestamp = time.Now()
+			}
+			m.navigator.SetNamespaces(namespaces)
+			return
+		}
+	}
+	namespaces = append(namespaces, repository.NamespaceInfo{Name: name, Status: status})
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	})
+	m.navigator.SetNamespaces(namespaces)
+}
@@ -0,0 +1,110 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains the command palette that jumps to a namespace, workload or pod.
+package tui
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+)
+
+// paletteKinds are the workloads the palette lists, those
+// repository.ResolveTarget opens.
+var paletteKinds = []repository.ResourceType{
+	repository.ResourceDeployments,
+	repository.ResourceStatefulSets,
+	repository.ResourceDaemonSets,
+}
+
+// openPalette opens the command palette over the namespaces listed and
+// the workloads indexed so far, and asks for those of the current
+// namespace if they are stale.
+func (m *Model) openPalette() tea.Cmd {
+	m.palette.SetSize(m.width, m.height)
+	m.palette.SetNamespaces(m.navigator.GetActiveNamespaceNames())
+	return m.palette.Show(m.k8sClient.Namespace())
+}
+
+// loadPaletteIndex lists the workloads of each namespace for the palette,
+// one command per namespace so that each shows up as soon as it is listed.
+// A kind that can't be listed, e.g. for lack of permission, is left out.
+// Returns a paletteIndexMsg per namespace.
+func (m *Model) loadPaletteIndex(namespaces []string) tea.Cmd {
+	clientset := m.k8sClient.Clientset()
+	cluster := m.k8sClient.Context()
+	var cmds []tea.Cmd
+	for _, ns := range namespaces {
+		ns := ns
+		cmds = append(cmds, call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+			var items []component.PaletteItem
+			var lastErr error
+			listed := 0
+			for _, resourceType := range paletteKinds {
+				workloads, err := repository.ListWorkloads(ctx, clientset, ns, resourceType)
+				if err != nil {
+					lastErr = err
+					continue
+				}
+				listed++
+				kind := strings.ToLower(repository.KindForResourceType(resourceType))
+				for _, w := range workloads {
+					items = append(items, component.PaletteItem{Kind: kind, Namespace: ns, Name: w.Name})
+				}
+			}
+			if listed > 0 {
+				lastErr = nil
+			}
+			return paletteIndexMsg{context: cluster, namespace: ns, items: items, err: lastErr}
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// jumpTo opens the item picked in the palette, switching namespace first
+// if needed: a namespace opens on its resources, a pod on its dashboard
+// and a workload on its worst pod, as from the command line.
+func (m *Model) jumpTo(item component.PaletteItem) tea.Cmd {
+	m.telemetry.Action("palette")
+	switched := item.Namespace != m.k8sClient.Namespace()
+	if switched {
+		m.leaveNavigatorScope()
+		m.k8sClient.SetNamespace(item.Namespace)
+		m.config.SetLastNamespace(item.Namespace)
+		m.selectedNode = ""
+	}
+
+	if item.Kind == component.PaletteNamespace {
+		if m.view == ViewDashboard {
+			m.view = ViewNavigator
+			m.pod = nil
+			m.stopLogStream()
+			m.stopEventWatch()
+			m.leavePodScope()
+		}
+		m.workload = nil
+		m.palette.Visit(item)
+		m.loading = true
+		return m.loadFirstResourcesPage()
+	}
+	m.loading = true
+	if !switched {
+		return m.resolveTarget(item.Kind, item.Name)
+	}
+	// The namespace's resources first, for Esc to lead back to
+	return tea.Sequence(m.loadFirstResourcesPage(), m.resolveTarget(item.Kind, item.Name))
+}
+
+// visitWorkload records a workload as visited in the palette, if it is of
+// a kind the palette opens.
+func (m *Model) visitWorkload(workload *repository.WorkloadInfo) {
+	for _, resourceType := range paletteKinds {
+		if workload.Type == resourceType {
+			kind := strings.ToLower(repository.KindForResourceType(resourceType))
+			m.palette.Visit(component.PaletteItem{Kind: kind, Namespace: workload.Namespace, Name: workload.Name})
+			return
+		}
+	}
+}
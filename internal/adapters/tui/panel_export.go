@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+	"github.com/andrebassi/k1s/internal/adapters/tui/view"
+)
+
+// panelSaveTarget is the InputDialogResult and ConfirmResult data for a
+// pending save of the logs or events panel.
+type panelSaveTarget struct {
+	export component.PanelExport
+	path   string // Set once chosen, for the overwrite confirmation
+}
+
+// requestPanelSave prompts for the file to save the displayed logs or
+// events of the dashboard's pod to.
+func (m *Model) requestPanelSave(req component.SavePanelRequest) {
+	if m.pod == nil {
+		return
+	}
+	export := component.PanelExport{
+		SavePanelRequest: req,
+		Context:          m.k8sClient.Context(),
+		Namespace:        m.pod.Namespace,
+		Pod:              m.pod.Name,
+		Time:             time.Now(),
+	}
+	m.inputDialog.Show(
+		"Save "+req.Panel,
+		fmt.Sprintf("Write the %d %s lines shown to file:", req.Lines, req.Panel),
+		"save_panel",
+		export.FileName(),
+		panelSaveTarget{export: export},
+	)
+}
+
+// savePanel writes a panel export in the background, refusing to replace
+// an existing file unless overwrite. Returns a panelSavedMsg.
+func savePanel(target panelSaveTarget, path string, overwrite bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := panelSavedMsg{target: target}
+		msg.target.path, msg.err = filepath.Abs(path)
+		if msg.err != nil {
+			return msg
+		}
+		msg.bytes, msg.err = component.WritePanelExport(msg.target.path, target.export, overwrite)
+		msg.exists = errors.Is(msg.err, fs.ErrExist)
+		return msg
+	}
+}
+
+// handlePanelSaved reports a saved panel, or asks before overwriting the
+// file it would have replaced.
+func (m *Model) handlePanelSaved(msg panelSavedMsg) tea.Cmd {
+	if msg.exists {
+		m.confirmDialog.Show(
+			"File Exists",
+			fmt.Sprintf("%s already exists. Overwrite it?", msg.target.path),
+			"overwrite_panel",
+			msg.target,
+		)
+		return nil
+	}
+	result := view.PanelSaveResultMsg{Panel: msg.target.export.Panel, Path: msg.target.path, Bytes: msg.bytes, Err: msg.err}
+	if m.view == ViewDashboard {
+		var cmd tea.Cmd
+		m.dashboard, cmd = m.dashboard.Update(result)
+		return cmd
+	}
+	m.statusMsg = result.Status()
+	return clearStatusAfter(5 * time.Second)
+}
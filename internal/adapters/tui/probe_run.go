@@ -0,0 +1,33 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains running a container's probes by hand.
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/view"
+)
+
+// runProbe runs a probe once from inside the pod. A network probe of a
+// container without curl, nc or the like runs again from the pod's
+// netshoot debug container, which shares its network, when there is one.
+// Returns a view.ProbeRunMsg with the result or the error.
+func (m *Model) runProbe(req view.ProbeRunRequest) tea.Cmd {
+	config := m.k8sClient.Config()
+	return call(m.podCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		result, err := repository.RunProbe(ctx, config, req.Namespace, req.PodName, req.Container, req.Config, req.PodIP, req.Ports)
+		if !errors.Is(err, repository.ErrNoProbeTool) || req.Config.Type == "Exec" {
+			return view.ProbeRunMsg{Request: req, Result: result, Err: err}
+		}
+		if req.Netshoot == "" {
+			err = fmt.Errorf("%w; start a netshoot debug shell from the pod actions to run it from there", err)
+			return view.ProbeRunMsg{Request: req, Err: err}
+		}
+		result, err = repository.RunProbe(ctx, config, req.Namespace, req.PodName, req.Netshoot, req.Config, req.PodIP, req.Ports)
+		return view.ProbeRunMsg{Request: req, Result: result, Via: req.Netshoot, Err: err}
+	})
+}
@@ -0,0 +1,63 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains promoting, aborting and retrying Argo Rollouts.
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// requestRolloutAction asks for confirmation before promoting, aborting or
+// retrying an Argo Rollout. action is "promote", "abort" or "retry".
+func (m *Model) requestRolloutAction(workload *repository.WorkloadInfo, action string) tea.Cmd {
+	var title, message, confirmAction string
+	switch action {
+	case "promote":
+		title, confirmAction = "Promote Rollout", configs.ActionPromoteRollout
+		message = fmt.Sprintf("Promote '%s'? A paused rollout resumes, otherwise it skips its current step.", workload.Name)
+	case "abort":
+		title, confirmAction = "Abort Rollout", configs.ActionAbortRollout
+		message = fmt.Sprintf("Abort the update of '%s'? Traffic goes back to the stable version.", workload.Name)
+	case "retry":
+		title, confirmAction = "Retry Rollout", configs.ActionAbortRollout
+		message = fmt.Sprintf("Retry the aborted update of '%s' from its first step?", workload.Name)
+	default:
+		return nil
+	}
+	return m.confirmDialog.Request(
+		m.confirmLevel(workload.Namespace, confirmAction),
+		title,
+		message,
+		action+"_rollout",
+		workload.Name,
+		workload,
+	)
+}
+
+// rolloutAction promotes, aborts or retries an Argo Rollout.
+// Returns a workloadActionMsg with the result.
+func (m *Model) rolloutAction(workload *repository.WorkloadInfo, action string) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		var err error
+		switch action {
+		case "promote":
+			err = repository.PromoteRollout(ctx, m.k8sClient.DynamicClient(), workload.Namespace, workload.Name)
+		case "abort":
+			err = repository.AbortRollout(ctx, m.k8sClient.DynamicClient(), workload.Namespace, workload.Name)
+		case "retry":
+			err = repository.RetryRollout(ctx, m.k8sClient.DynamicClient(), workload.Namespace, workload.Name)
+		}
+		return workloadActionMsg{
+			action:       action,
+			workloadName: workload.Name,
+			namespace:    workload.Namespace,
+			resourceType: workload.Type,
+			hint:         workloadHint(workload),
+			err:          err,
+		}
+	})
+}
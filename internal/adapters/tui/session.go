@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newReplayClient loads a recording made with --record and returns a
+// client serving the snapshot taken at or before at, or the last one for
+// a zero at.
+func newReplayClient(path string, at time.Time) (*repository.Client, error) {
+	snapshots, err := repository.LoadSessionSnapshots(path)
+	if err != nil {
+		return nil, err
+	}
+	i, err := repository.SelectSnapshot(snapshots, at)
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewReplayClient(snapshots[i], path), nil
+}
+
+// recordSession appends a snapshot of the current namespace to the
+// --record file. Returns nil when the session is not recorded.
+// Returns a sessionRecordFailedMsg if the snapshot could not be written.
+func (m *Model) recordSession() tea.Cmd {
+	if m.sessionRecorder == nil {
+		return nil
+	}
+	recorder := m.sessionRecorder
+	clientset := m.k8sClient.Clientset()
+	metricsClient := m.k8sClient.MetricsClient()
+	contextName, namespace := m.k8sClient.Context(), m.k8sClient.Namespace()
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		snap := repository.CaptureSession(ctx, clientset, metricsClient, contextName, namespace)
+		if err := recorder.Record(snap); err != nil {
+			return sessionRecordFailedMsg{err: err}
+		}
+		return nil
+	})
+}
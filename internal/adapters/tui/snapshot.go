@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/view"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// snapshotLogLines is how many log lines per container a snapshot keeps.
+const snapshotLogLines = 1000
+
+// snapshotTarget is the InputDialogResult data for a pending snapshot.
+type snapshotTarget struct {
+	namespace string
+	pod       string
+}
+
+// requestSnapshot prompts for the file or directory to export a snapshot
+// of a pod to.
+func (m *Model) requestSnapshot(namespace, podName string) {
+	m.inputDialog.Show(
+		"Export Snapshot",
+		fmt.Sprintf("Write snapshot of '%s' to (.tar.gz or directory):", podName),
+		"save_snapshot",
+		podName+"-snapshot.tar.gz",
+		snapshotTarget{namespace: namespace, pod: podName},
+	)
+}
+
+// startSnapshot exports a snapshot of a pod in the background. A path
+// ending in .tar.gz or .tgz is written as an archive, anything else as a
+// directory. Returns a command that delivers the first snapshotProgressMsg
+// or the snapshotFinishedMsg.
+func (m *Model) startSnapshot(target snapshotTarget, path string) tea.Cmd {
+	updates := make(chan tea.Msg, 16)
+	m.snapshotUpdates = updates
+
+	clientset := m.k8sClient.Clientset()
+	dynamicClient := m.k8sClient.DynamicClient()
+	// A nil *Clientset would not compare equal to a nil interface
+	var metricsClient repository.MetricsClientInterface
+	if mc := m.k8sClient.MetricsClient(); mc != nil {
+		metricsClient = mc
+	}
+	export := call(m.appCalls, repository.CallLogs, func(ctx context.Context) tea.Msg {
+		msg := snapshotFinishedMsg{pod: target.pod}
+		msg.path, msg.err = filepath.Abs(path)
+		if msg.err == nil {
+			msg.manifest, msg.err = repository.ExportSnapshot(ctx, clientset, dynamicClient, metricsClient, target.namespace, target.pod, msg.path, repository.SnapshotOptions{
+				TailLines: snapshotLogLines,
+				Archive:   strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz"),
+				Describe:  describePod,
+				OnProgress: func(section string) {
+					updates <- snapshotProgressMsg{pod: target.pod, section: section}
+				},
+			})
+		}
+		return msg
+	})
+	go func() {
+		defer close(updates)
+		if msg := export(); msg != nil {
+			updates <- msg
+		}
+	}()
+	return waitForSnapshot(updates)
+}
+
+// waitForSnapshot blocks until the snapshot reports progress or finishes.
+func waitForSnapshot(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// describePod returns the output of kubectl describe for a pod, the same
+// text the Describe Pod action shows.
+func describePod(ctx context.Context, namespace, name string) (string, error) {
+	output, err := exec.CommandContext(ctx, "kubectl", "describe", "pod", "-n", namespace, name).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("kubectl describe failed: %w", err)
+	}
+	return string(output), nil
+}
+
+// snapshotStatus describes a finished snapshot for the status bar.
+func snapshotStatus(msg snapshotFinishedMsg) string {
+	if msg.err != nil {
+		return "Snapshot failed: " + msg.err.Error()
+	}
+	status := fmt.Sprintf("Saved snapshot of %s to %s", msg.pod, msg.path)
+	if len(msg.manifest.Skipped) > 0 {
+		var sections []string
+		for _, s := range msg.manifest.Skipped {
+			sections = append(sections, s.Section)
+		}
+		status += " (skipped " + strings.Join(sections, ", ") + ")"
+	}
+	return status
+}
+
+// showSnapshotStatus shows snapshot progress in the dashboard, or in the
+// status bar of other views.
+func (m *Model) showSnapshotStatus(status string) tea.Cmd {
+	if m.view == ViewDashboard {
+		var cmd tea.Cmd
+		m.dashboard, cmd = m.dashboard.Update(view.SnapshotStatusMsg{Status: status})
+		return cmd
+	}
+	m.statusMsg = status
+	return nil
+}
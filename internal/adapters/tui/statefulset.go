@@ -0,0 +1,68 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains the ordinal details and partitioned rollouts of StatefulSets.
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+)
+
+// statefulSetPartition is the ConfirmResult data for a pending partition change.
+type statefulSetPartition struct {
+	workload  *repository.WorkloadInfo
+	partition int32
+}
+
+// loadStatefulSetDetails fetches the ordinals, claims and update strategy
+// of a StatefulSet. With partitions set, the result opens the partition
+// picker instead of the details.
+// Returns a statefulSetDetailsMsg.
+func (m *Model) loadStatefulSetDetails(workload *repository.WorkloadInfo, partitions bool) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		details, err := repository.GetStatefulSetDetails(ctx, m.k8sClient.Clientset(), workload.Namespace, workload.Name)
+		return statefulSetDetailsMsg{workload: workload, details: details, partitions: partitions, err: err}
+	})
+}
+
+// showPartitions opens the workload action menu as a picker of the
+// partitions to advance a rolling update to.
+func (m *Model) showPartitions(workload *repository.WorkloadInfo, details *repository.StatefulSetDetails) {
+	title := fmt.Sprintf("Partition: %s (%d/%d updated)", workload.Name, details.UpdatedCount(), len(details.Ordinals))
+	m.workloadMenuTarget = workload
+	m.workloadActionMenu.Show(title, component.PartitionActions(details))
+}
+
+// requestPartition asks for confirmation before changing the partition of
+// a StatefulSet, which replaces the pods of the ordinals it releases.
+func (m *Model) requestPartition(workload *repository.WorkloadInfo, partition int32) tea.Cmd {
+	return m.confirmDialog.Request(
+		m.confirmLevel(workload.Namespace, configs.ActionSetPartition),
+		"Advance Rollout",
+		fmt.Sprintf("Set the partition of '%s' to %d? Pods of ordinals %d and above are replaced with the update revision.", workload.Name, partition, partition),
+		"set_partition",
+		workload.Name,
+		statefulSetPartition{workload: workload, partition: partition},
+	)
+}
+
+// setPartition changes the partition of a StatefulSet's rolling update.
+// Returns a workloadActionMsg with the result.
+func (m *Model) setPartition(workload *repository.WorkloadInfo, partition int32) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		err := repository.SetStatefulSetPartition(ctx, m.k8sClient.Clientset(), workload.Namespace, workload.Name, partition)
+		return workloadActionMsg{
+			action:       "partition",
+			workloadName: workload.Name,
+			namespace:    workload.Namespace,
+			resourceType: workload.Type,
+			partition:    partition,
+			hint:         workloadHint(workload),
+			err:          err,
+		}
+	})
+}
@@ -1,164 +1,269 @@
 // Package styles provides consistent visual styling for the k1s TUI.
 //
 // This package defines colors, text styles, and layout styles using lipgloss.
-// All styles are optimized for readability on dark terminal backgrounds.
-// The color palette uses accessible, high-contrast colors for status indicators.
+// Colors come from the active Theme (see Apply): the default one is
+// optimized for readability on dark terminal backgrounds, and others cater
+// for high contrast, color blindness and terminals without colors.
 package style
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"hash/fnv"
+	"strings"
 
-// Color palette - optimized for readability on dark terminals.
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Color palette of the active theme, set by Apply. The default theme is
+// optimized for readability on dark terminals.
 var (
-	Primary     = lipgloss.Color("#3B82F6") // Dark blue - primary accent
-	Secondary   = lipgloss.Color("#22D3EE") // Bright cyan - good contrast
-	Success     = lipgloss.Color("#4ADE80") // Bright green - very readable
-	Warning     = lipgloss.Color("#FBBF24") // Amber - warm and visible
-	Error       = lipgloss.Color("#F87171") // Soft red - not too harsh
-	Muted       = lipgloss.Color("#9CA3AF") // Gray - subtle but readable
-	Background  = lipgloss.Color("#111827") // Dark background
-	Surface     = lipgloss.Color("#4B5563") // Lighter surface for borders
-	Text        = lipgloss.Color("#F3F4F6") // Off-white - less eye strain
-	TextMuted   = lipgloss.Color("#D1D5DB") // Light gray - readable muted text
-	Accent      = lipgloss.Color("#F472B6") // Pink accent for special items
+	Primary    lipgloss.Color // Primary accent
+	Secondary  lipgloss.Color // Secondary accent
+	Success    lipgloss.Color // Healthy states
+	Warning    lipgloss.Color // Pending states and warnings
+	Error      lipgloss.Color // Failed states and errors
+	Muted      lipgloss.Color // Subtle text
+	Background lipgloss.Color // Dark background
+	Surface    lipgloss.Color // Lighter surface for borders
+	Text       lipgloss.Color // Regular text
+	TextMuted  lipgloss.Color // Readable muted text
+	Accent     lipgloss.Color // Special items
+
+	// Colors of the pod prefix in merged workload logs (see GetLogPodStyle)
+	LogPodColors []lipgloss.Color
+)
 
+// Styles built from the palette by Apply.
+var (
 	// Base styles
 	BaseStyle = lipgloss.NewStyle()
 
 	// Title styles
+	TitleStyle    lipgloss.Style
+	SubtitleStyle lipgloss.Style
+
+	// Panel styles
+	PanelStyle       lipgloss.Style
+	ActivePanelStyle lipgloss.Style
+	PanelTitleStyle  lipgloss.Style
+
+	// List styles
+	ListItemStyle     lipgloss.Style
+	SelectedItemStyle lipgloss.Style
+	SelectedStyle     lipgloss.Style
+	CursorStyle       lipgloss.Style
+
+	// Status styles
+	StatusRunning lipgloss.Style
+	StatusPending lipgloss.Style
+	StatusError   lipgloss.Style
+	StatusMuted   lipgloss.Style
+
+	// Rows removed optimistically, until a refresh confirms the deletion
+	StatusDeleting lipgloss.Style
+
+	// Fields that changed in the last refresh (highlight changes mode)
+	StatusChanged lipgloss.Style
+
+	// Log styles
+	LogTimestamp lipgloss.Style
+	LogContainer lipgloss.Style
+	LogError     lipgloss.Style
+	LogNormal    lipgloss.Style
+	LogPrevious  lipgloss.Style
+
+	// Table styles
+	TableHeaderStyle lipgloss.Style
+	TableCellStyle   lipgloss.Style
+
+	// Help styles
+	HelpKeyStyle  lipgloss.Style
+	HelpDescStyle lipgloss.Style
+	HelpSeparator lipgloss.Style
+
+	// Breadcrumb
+	BreadcrumbStyle       lipgloss.Style
+	BreadcrumbActiveStyle lipgloss.Style
+	BreadcrumbBadgeStyle  lipgloss.Style
+
+	// Event type styles
+	EventWarning lipgloss.Style
+	EventNormal  lipgloss.Style
+
+	// Spinner
+	SpinnerStyle lipgloss.Style
+
+	// Credit style
+	CreditStyle lipgloss.Style
+
+	// Search input style
+	SearchStyle lipgloss.Style
+)
+
+func init() {
+	Apply(DefaultTheme)
+}
+
+// buildStyles sets the styles above from the palette of t. With markers,
+// states are also told apart by bold and underline: errors and warning
+// events are underlined, pending states bold and healthy ones plain.
+// Without colors, selection and changes are shown in reverse video and the
+// focused panel with a thick border.
+func buildStyles(t Theme) {
 	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(Primary).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(Primary).
+		MarginBottom(1)
 
 	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(TextMuted).
-			Italic(true)
+		Foreground(TextMuted).
+		Italic(true)
 
-	// Panel styles
 	PanelStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(Surface).
-			Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(Surface).
+		Padding(0, 1)
 
 	ActivePanelStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(Success).
-				Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(Success).
+		Padding(0, 1)
+	if t.NoColor {
+		ActivePanelStyle = ActivePanelStyle.Border(lipgloss.ThickBorder())
+	}
 
 	PanelTitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(Primary).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(Primary).
+		MarginBottom(1)
 
-	// List styles
 	ListItemStyle = lipgloss.NewStyle().
-			PaddingLeft(2).
-			Foreground(Text)
+		PaddingLeft(2).
+		Foreground(Text)
 
 	SelectedItemStyle = lipgloss.NewStyle().
-				PaddingLeft(1).
-				Foreground(lipgloss.Color("#1F2937")).
-				Background(Primary).
-				Bold(true)
+		PaddingLeft(1).
+		Foreground(t.SelectedText).
+		Background(Primary).
+		Bold(true).
+		Reverse(t.NoColor)
 
 	SelectedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#1F2937")).
-			Background(Success).
-			Bold(true)
+		Foreground(t.SelectedText).
+		Background(Success).
+		Bold(true).
+		Reverse(t.NoColor)
 
 	CursorStyle = lipgloss.NewStyle().
-			Foreground(Primary).
-			Bold(true)
+		Foreground(Primary).
+		Bold(true)
 
-	// Status styles
 	StatusRunning = lipgloss.NewStyle().
-			Foreground(Success).
-			Bold(true)
+		Foreground(Success).
+		Bold(!t.Markers)
 
 	StatusPending = lipgloss.NewStyle().
-			Foreground(Warning).
-			Bold(true)
+		Foreground(Warning).
+		Bold(true)
 
 	StatusError = lipgloss.NewStyle().
-			Foreground(Error).
-			Bold(true)
+		Foreground(Error).
+		Bold(true).
+		Underline(t.Markers)
 
 	StatusMuted = lipgloss.NewStyle().
-			Foreground(Muted)
+		Foreground(Muted)
+
+	StatusDeleting = lipgloss.NewStyle().
+		Foreground(Muted).
+		Strikethrough(true)
+
+	StatusChanged = lipgloss.NewStyle().
+		Foreground(Background).
+		Background(Secondary).
+		Reverse(t.NoColor)
 
-	// Log styles
 	LogTimestamp = lipgloss.NewStyle().
-			Foreground(Muted)
+		Foreground(Muted)
 
 	LogContainer = lipgloss.NewStyle().
-			Foreground(Primary).
-			Bold(true)
+		Foreground(Primary).
+		Bold(true)
 
 	LogError = lipgloss.NewStyle().
-			Foreground(Error).
-			Bold(true)
+		Foreground(Error).
+		Bold(true).
+		Underline(t.Markers)
 
 	LogNormal = lipgloss.NewStyle().
-			Foreground(Text)
+		Foreground(Text)
+
+	LogPrevious = lipgloss.NewStyle().
+		Foreground(Muted).
+		Faint(true)
 
-	// Table styles
 	TableHeaderStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(Primary).
-				BorderBottom(true).
-				BorderStyle(lipgloss.NormalBorder()).
-				BorderForeground(Surface)
+		Bold(true).
+		Foreground(Primary).
+		BorderBottom(true).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(Surface)
 
 	TableCellStyle = lipgloss.NewStyle().
-			Padding(0, 1).
-			Foreground(Text)
+		Padding(0, 1).
+		Foreground(Text)
 
-	// Help styles
 	HelpKeyStyle = lipgloss.NewStyle().
-			Foreground(Primary).
-			Bold(true)
+		Foreground(Primary).
+		Bold(true)
 
 	HelpDescStyle = lipgloss.NewStyle().
-			Foreground(TextMuted)
+		Foreground(TextMuted)
 
 	HelpSeparator = lipgloss.NewStyle().
-			Foreground(Surface)
+		Foreground(Surface)
 
-	// Breadcrumb
 	BreadcrumbStyle = lipgloss.NewStyle().
-			Foreground(TextMuted)
+		Foreground(TextMuted)
 
 	BreadcrumbActiveStyle = lipgloss.NewStyle().
-				Foreground(Primary).
-				Bold(true)
+		Foreground(Primary).
+		Bold(true)
+
+	BreadcrumbBadgeStyle = lipgloss.NewStyle().
+		Foreground(Muted).
+		Italic(true)
 
-	// Event type styles
 	EventWarning = lipgloss.NewStyle().
-			Foreground(Warning).
-			Bold(true)
+		Foreground(Warning).
+		Bold(true).
+		Underline(t.Markers)
 
 	EventNormal = lipgloss.NewStyle().
-			Foreground(Success)
+		Foreground(Success)
 
-	// Spinner
 	SpinnerStyle = lipgloss.NewStyle().
-			Foreground(Primary)
+		Foreground(Primary)
 
-	// Credit style
 	CreditStyle = lipgloss.NewStyle().
-			Foreground(Muted).
-			Italic(true)
+		Foreground(Muted).
+		Italic(true)
 
-	// Search input style
 	SearchStyle = lipgloss.NewStyle().
-			Foreground(Text).
-			Background(Surface).
-			Padding(0, 1)
-)
+		Foreground(Text).
+		Background(Surface).
+		Padding(0, 1)
+}
 
 // GetStatusStyle returns the appropriate style for a Kubernetes resource status.
 // Maps status strings to color-coded styles (green=running, yellow=pending, red=error).
 func GetStatusStyle(status string) lipgloss.Style {
+	// Init:N/M is a pod still initializing, any other Init: status a failure
+	if strings.HasPrefix(status, "Init:") {
+		if strings.Contains(status, "/") {
+			return StatusPending
+		}
+		return StatusError
+	}
 	switch status {
 	case "Running", "Completed", "Active", "Ready":
 		return StatusRunning
@@ -171,6 +276,16 @@ func GetStatusStyle(status string) lipgloss.Style {
 	}
 }
 
+// GetLogPodStyle returns the style of a pod's prefix in merged workload
+// logs. The color is derived from the pod name, so a pod keeps its color
+// across refreshes and as other pods come and go.
+func GetLogPodStyle(pod string) lipgloss.Style {
+	h := fnv.New32a()
+	h.Write([]byte(pod))
+	color := LogPodColors[h.Sum32()%uint32(len(LogPodColors))]
+	return lipgloss.NewStyle().Foreground(color).Bold(true)
+}
+
 // RenderWithWidth applies a style with a fixed width and renders the content.
 func RenderWithWidth(s lipgloss.Style, content string, width int) string {
 	return s.Width(width).Render(content)
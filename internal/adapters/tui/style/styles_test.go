@@ -1,6 +1,7 @@
 package style
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -20,6 +21,7 @@ func TestGetStatusStyle(t *testing.T) {
 		{"Pending", "pending"},
 		{"Progressing", "pending"},
 		{"ContainerCreating", "pending"},
+		{"Init:1/3", "pending"},
 
 		// Error states
 		{"Failed", "error"},
@@ -30,6 +32,7 @@ func TestGetStatusStyle(t *testing.T) {
 		{"OOMKilled", "error"},
 		{"NotReady", "error"},
 		{"Terminating", "error"},
+		{"Init:CrashLoopBackOff", "error"},
 
 		// Default/Muted states
 		{"Unknown", "muted"},
@@ -47,6 +50,22 @@ func TestGetStatusStyle(t *testing.T) {
 	}
 }
 
+func TestGetLogPodStyle(t *testing.T) {
+	a := GetLogPodStyle("web-5d8f9-abcde")
+	if a.GetForeground() != GetLogPodStyle("web-5d8f9-abcde").GetForeground() {
+		t.Error("a pod should always get the same color")
+	}
+
+	// Pods of a workload should not all share one color
+	colors := make(map[string]bool)
+	for _, pod := range []string{"web-5d8f9-abcde", "web-5d8f9-fghij", "web-5d8f9-klmno", "web-5d8f9-pqrst"} {
+		colors[fmt.Sprint(GetLogPodStyle(pod).GetForeground())] = true
+	}
+	if len(colors) < 2 {
+		t.Error("different pods should get different colors")
+	}
+}
+
 func TestRenderWithWidth(t *testing.T) {
 	tests := []struct {
 		name    string
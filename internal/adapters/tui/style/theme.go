@@ -0,0 +1,185 @@
+package style
+
+import "github.com/charmbracelet/lipgloss"
+
+// Theme is a color palette for the TUI. Apply builds every style from the
+// active theme, so panels follow it without holding colors of their own.
+type Theme struct {
+	Name string
+
+	Primary    lipgloss.Color
+	Secondary  lipgloss.Color
+	Success    lipgloss.Color
+	Warning    lipgloss.Color
+	Error      lipgloss.Color
+	Muted      lipgloss.Color
+	Background lipgloss.Color
+	Surface    lipgloss.Color
+	Text       lipgloss.Color
+	TextMuted  lipgloss.Color
+	Accent     lipgloss.Color
+
+	SelectedText lipgloss.Color   // Text on the selected row's background
+	LogPodColors []lipgloss.Color // Pod prefixes in merged workload logs
+
+	Markers bool // Also tell states apart by bold and underline, not color alone
+	NoColor bool // No colors at all; markers and reverse video only
+}
+
+// Theme names, as set in the config file.
+const (
+	ThemeDefault      = "default"
+	ThemeHighContrast = "high-contrast"
+	ThemeColorBlind   = "color-blind"
+	ThemeNoColor      = "no-color"
+)
+
+// DefaultTheme is readable on dark terminals.
+var DefaultTheme = Theme{
+	Name:         ThemeDefault,
+	Primary:      lipgloss.Color("#3B82F6"), // Dark blue - primary accent
+	Secondary:    lipgloss.Color("#22D3EE"), // Bright cyan - good contrast
+	Success:      lipgloss.Color("#4ADE80"), // Bright green - very readable
+	Warning:      lipgloss.Color("#FBBF24"), // Amber - warm and visible
+	Error:        lipgloss.Color("#F87171"), // Soft red - not too harsh
+	Muted:        lipgloss.Color("#9CA3AF"), // Gray - subtle but readable
+	Background:   lipgloss.Color("#111827"), // Dark background
+	Surface:      lipgloss.Color("#4B5563"), // Lighter surface for borders
+	Text:         lipgloss.Color("#F3F4F6"), // Off-white - less eye strain
+	TextMuted:    lipgloss.Color("#D1D5DB"), // Light gray - readable muted text
+	Accent:       lipgloss.Color("#F472B6"), // Pink accent for special items
+	SelectedText: lipgloss.Color("#1F2937"),
+	LogPodColors: []lipgloss.Color{
+		lipgloss.Color("#22D3EE"), // Cyan
+		lipgloss.Color("#4ADE80"), // Green
+		lipgloss.Color("#FBBF24"), // Amber
+		lipgloss.Color("#F472B6"), // Pink
+		lipgloss.Color("#A78BFA"), // Violet
+		lipgloss.Color("#FB923C"), // Orange
+		lipgloss.Color("#2DD4BF"), // Teal
+		lipgloss.Color("#93C5FD"), // Light blue
+	},
+}
+
+// HighContrastTheme uses saturated colors on black and white text, for
+// low vision and washed-out displays.
+var HighContrastTheme = Theme{
+	Name:         ThemeHighContrast,
+	Primary:      lipgloss.Color("#5FAFFF"),
+	Secondary:    lipgloss.Color("#00FFFF"),
+	Success:      lipgloss.Color("#00FF00"),
+	Warning:      lipgloss.Color("#FFFF00"),
+	Error:        lipgloss.Color("#FF3030"),
+	Muted:        lipgloss.Color("#D0D0D0"),
+	Background:   lipgloss.Color("#000000"),
+	Surface:      lipgloss.Color("#FFFFFF"),
+	Text:         lipgloss.Color("#FFFFFF"),
+	TextMuted:    lipgloss.Color("#EEEEEE"),
+	Accent:       lipgloss.Color("#FF5FFF"),
+	SelectedText: lipgloss.Color("#000000"),
+	LogPodColors: []lipgloss.Color{
+		lipgloss.Color("#00FFFF"),
+		lipgloss.Color("#00FF00"),
+		lipgloss.Color("#FFFF00"),
+		lipgloss.Color("#FF5FFF"),
+		lipgloss.Color("#5FAFFF"),
+		lipgloss.Color("#FFAF00"),
+	},
+	Markers: true,
+}
+
+// ColorBlindTheme uses the Okabe-Ito palette, whose colors stay distinct
+// with the common forms of color blindness: healthy is bluish green and
+// failing vermillion rather than green and red.
+var ColorBlindTheme = Theme{
+	Name:         ThemeColorBlind,
+	Primary:      lipgloss.Color("#56B4E9"), // Sky blue
+	Secondary:    lipgloss.Color("#CC79A7"), // Reddish purple
+	Success:      lipgloss.Color("#009E73"), // Bluish green
+	Warning:      lipgloss.Color("#F0E442"), // Yellow
+	Error:        lipgloss.Color("#D55E00"), // Vermillion
+	Muted:        lipgloss.Color("#9CA3AF"),
+	Background:   lipgloss.Color("#111827"),
+	Surface:      lipgloss.Color("#4B5563"),
+	Text:         lipgloss.Color("#F3F4F6"),
+	TextMuted:    lipgloss.Color("#D1D5DB"),
+	Accent:       lipgloss.Color("#E69F00"), // Orange
+	SelectedText: lipgloss.Color("#1F2937"),
+	LogPodColors: []lipgloss.Color{
+		lipgloss.Color("#56B4E9"),
+		lipgloss.Color("#E69F00"),
+		lipgloss.Color("#009E73"),
+		lipgloss.Color("#F0E442"),
+		lipgloss.Color("#CC79A7"),
+		lipgloss.Color("#0072B2"),
+	},
+	Markers: true,
+}
+
+// NoColorTheme renders no colors, as asked by NO_COLOR or --no-color.
+// States are shown by bold and underline, selection in reverse video.
+var NoColorTheme = Theme{
+	Name:         ThemeNoColor,
+	LogPodColors: []lipgloss.Color{""},
+	Markers:      true,
+	NoColor:      true,
+}
+
+// Themes lists the available themes in the order CycleTheme goes through.
+var Themes = []Theme{DefaultTheme, HighContrastTheme, ColorBlindTheme, NoColorTheme}
+
+// current is the theme set by the last Apply.
+var current Theme
+
+// ThemeByName returns the theme with the given name. Returns the default
+// theme and false for an unknown name; an empty name is the default.
+func ThemeByName(name string) (Theme, bool) {
+	if name == "" {
+		return DefaultTheme, true
+	}
+	for _, t := range Themes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return DefaultTheme, false
+}
+
+// Apply makes t the active theme: the palette is set from it and every
+// style is rebuilt. Views render with the new colors from their next
+// frame on.
+func Apply(t Theme) {
+	current = t
+	Primary = t.Primary
+	Secondary = t.Secondary
+	Success = t.Success
+	Warning = t.Warning
+	Error = t.Error
+	Muted = t.Muted
+	Background = t.Background
+	Surface = t.Surface
+	Text = t.Text
+	TextMuted = t.TextMuted
+	Accent = t.Accent
+	LogPodColors = t.LogPodColors
+	buildStyles(t)
+}
+
+// CurrentTheme returns the active theme.
+func CurrentTheme() Theme {
+	return current
+}
+
+// CycleTheme applies the theme after the active one in Themes, wrapping
+// around, and returns it.
+func CycleTheme() Theme {
+	next := Themes[0]
+	for i, t := range Themes {
+		if t.Name == current.Name {
+			next = Themes[(i+1)%len(Themes)]
+			break
+		}
+	}
+	Apply(next)
+	return next
+}
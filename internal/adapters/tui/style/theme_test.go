@@ -0,0 +1,104 @@
+package style
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// withTrueColor renders with 24-bit colors for the test, so the escape
+// sequences are the same whatever terminal runs it.
+func withTrueColor(t *testing.T) {
+	t.Helper()
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(profile)
+		Apply(DefaultTheme)
+	})
+}
+
+// sgrParams returns the parameters of every SGR escape sequence in s.
+func sgrParams(s string) []string {
+	var params []string
+	for _, seq := range strings.Split(s, "\x1b[")[1:] {
+		if end := strings.IndexByte(seq, 'm'); end >= 0 {
+			params = append(params, strings.Split(seq[:end], ";")...)
+		}
+	}
+	return params
+}
+
+func TestThemeByName(t *testing.T) {
+	for _, want := range Themes {
+		if got, ok := ThemeByName(want.Name); !ok || got.Name != want.Name {
+			t.Errorf("ThemeByName(%q) = %q, %v", want.Name, got.Name, ok)
+		}
+	}
+	if got, ok := ThemeByName(""); !ok || got.Name != ThemeDefault {
+		t.Errorf("ThemeByName(\"\") = %q, %v, want the default theme", got.Name, ok)
+	}
+	if got, ok := ThemeByName("solarized"); ok || got.Name != ThemeDefault {
+		t.Errorf("ThemeByName(unknown) = %q, %v, want the default theme and false", got.Name, ok)
+	}
+}
+
+func TestCycleTheme(t *testing.T) {
+	withTrueColor(t)
+	Apply(DefaultTheme)
+
+	for i := 1; i <= len(Themes); i++ {
+		want := Themes[i%len(Themes)]
+		if got := CycleTheme(); got.Name != want.Name || CurrentTheme().Name != want.Name {
+			t.Fatalf("CycleTheme() = %q, want %q", got.Name, want.Name)
+		}
+	}
+}
+
+func TestApply_RendersThemeColors(t *testing.T) {
+	withTrueColor(t)
+
+	Apply(DefaultTheme)
+	def := StatusError.Render("CrashLoopBackOff")
+	Apply(ColorBlindTheme)
+	cb := StatusError.Render("CrashLoopBackOff")
+
+	if def == cb {
+		t.Fatal("StatusError should render differently under the default and color-blind themes")
+	}
+	if !strings.Contains(def, "38;2;248;113;113") {
+		t.Errorf("default StatusError = %q, want the default red", def)
+	}
+	if !strings.Contains(cb, "38;2;213;94;0") {
+		t.Errorf("color-blind StatusError = %q, want vermillion", cb)
+	}
+	if Error != ColorBlindTheme.Error || len(LogPodColors) != len(ColorBlindTheme.LogPodColors) {
+		t.Error("Apply() should set the palette from the theme")
+	}
+}
+
+func TestApply_NoColor(t *testing.T) {
+	withTrueColor(t)
+	Apply(NoColorTheme)
+
+	params := sgrParams(StatusError.Render("Failed") + SelectedItemStyle.Render("pod") + GetLogPodStyle("api").Render("[api]"))
+	underline, reverse := false, false
+	for _, p := range params {
+		switch p {
+		case "38", "48":
+			t.Fatalf("no-color theme rendered a color: %v", params)
+		case "4":
+			underline = true
+		case "7":
+			reverse = true
+		}
+	}
+	if !underline {
+		t.Error("no-color theme should underline errors")
+	}
+	if !reverse {
+		t.Error("no-color theme should show the selection in reverse video")
+	}
+}
@@ -3,7 +3,11 @@
 package tui
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
 	"github.com/andrebassi/k1s/internal/adapters/tui/component"
 	"github.com/andrebassi/k1s/internal/adapters/tui/style"
 )
@@ -14,7 +18,8 @@ import (
 // The rendering order (back to front):
 // 1. Error state - Shows error message if m.err is set
 // 2. Loading state - Shows centered spinner while data loads
-// 3. Main content - Navigator view or Dashboard view
+// 3. Main content - Navigator view or Dashboard view, or the load of the
+//    view that timed out, to retry with r
 // 4. Overlays (highest priority, rendered on top):
 //   - Confirm dialog (delete confirmation)
 //   - Workload action menu (scale, restart, delete options)
@@ -26,11 +31,35 @@ import (
 //
 // The main content is wrapped in a bordered box with a status bar below.
 func (m Model) View() string {
+	defer m.telemetry.CapturePanic()
+
 	// Error state takes priority
 	if m.err != nil {
 		return style.StatusError.Render("Error: " + m.err.Error())
 	}
 
+	// Credentials that re-authenticating could not fix take over the screen
+	if err := m.k8sClient.AuthFailure(); err != nil {
+		return m.renderAuthFailure(err)
+	}
+
+	// Cluster switcher (full screen, top-left aligned), also opened from the
+	// diagnostics screen
+	if m.clusterSwitcher.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Left, lipgloss.Top,
+			m.clusterSwitcher.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
+	// A cluster that can't be reached takes over the screen until it answers
+	if m.connLost != nil {
+		return m.renderConnectionFailure(m.connLost)
+	}
+
 	// Loading state shows centered spinner
 	if m.loading {
 		loadingMsg := m.spinner.View() + " Loading..."
@@ -49,6 +78,10 @@ func (m Model) View() string {
 	case ViewDashboard:
 		content = m.dashboard.View()
 	}
+	// A load that timed out replaces the view until retried
+	if m.timedOut != nil {
+		content = m.renderTimedOut(contentWidth, contentHeight-1)
+	}
 
 	// Check for overlay components (rendered on top of main content)
 	if overlay := m.renderOverlay(); overlay != "" {
@@ -105,6 +138,28 @@ func (m Model) renderOverlay() string {
 		)
 	}
 
+	// Input dialog
+	if m.inputDialog.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			m.inputDialog.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
+	// Command palette
+	if m.palette.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			m.palette.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
 	// Workload action menu
 	if m.workloadActionMenu.IsVisible() {
 		return lipgloss.Place(
@@ -116,6 +171,28 @@ func (m Model) renderOverlay() string {
 		)
 	}
 
+	// Node action menu
+	if m.nodeActionMenu.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			m.nodeActionMenu.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
+	// Result viewer (drain simulation)
+	if m.resultViewer.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			m.resultViewer.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
 	// Help panel
 	if m.help.IsVisible() {
 		return lipgloss.Place(
@@ -127,6 +204,17 @@ func (m Model) renderOverlay() string {
 		)
 	}
 
+	// Port-forwards list (full screen, top-left aligned)
+	if m.portForwardsViewer.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Left, lipgloss.Top,
+			m.portForwardsViewer.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
 	// ConfigMap viewer (full screen, top-left aligned)
 	if m.configMapViewer.IsVisible() {
 		return lipgloss.Place(
@@ -171,9 +259,180 @@ func (m Model) renderOverlay() string {
 		)
 	}
 
+	// Pod metrics table (full screen, top-left aligned)
+	if m.podTopViewer.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Left, lipgloss.Top,
+			m.podTopViewer.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
+	// Node capacity table (full screen, top-left aligned)
+	if m.nodeCapacityViewer.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Left, lipgloss.Top,
+			m.nodeCapacityViewer.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
+	// Watches list (full screen, top-left aligned)
+	if m.watchesViewer.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Left, lipgloss.Top,
+			m.watchesViewer.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
+	// Namespace warnings (full screen, top-left aligned)
+	if m.warningsViewer.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Left, lipgloss.Top,
+			m.warningsViewer.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
+	// Error log (full screen, top-left aligned)
+	if m.errorLogViewer.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Left, lipgloss.Top,
+			m.errorLogViewer.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
+	// Columns picker (centered)
+	if m.columnPicker.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			m.columnPicker.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
+	// DaemonSet pods per node (full screen, top-left aligned)
+	if m.daemonSetNodesViewer.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Left, lipgloss.Top,
+			m.daemonSetNodesViewer.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
+	// CronJob run history (full screen, top-left aligned)
+	if m.cronJobRunsViewer.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Left, lipgloss.Top,
+			m.cronJobRunsViewer.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
+	// Namespace comparison (full screen, top-left aligned)
+	if m.namespaceCompare.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Left, lipgloss.Top,
+			m.namespaceCompare.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
+	// Container file browser (full screen, top-left aligned)
+	if m.fileBrowser.IsVisible() {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Left, lipgloss.Top,
+			m.fileBrowser.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(style.Background),
+		)
+	}
+
 	return ""
 }
 
+// renderConnectionFailure renders the full-screen diagnostics shown when the
+// API server can't be reached, at startup or mid-session: where k1s tried to
+// connect, what kind of failure it was and what to check.
+func (m Model) renderConnectionFailure(err error) string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Error)
+	textStyle := lipgloss.NewStyle().Foreground(style.Text)
+	labelStyle := lipgloss.NewStyle().Foreground(style.TextMuted).Width(10)
+
+	problem := repository.ClassifyConnectionError(err)
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Cannot reach the cluster"))
+	b.WriteString("\n\n")
+	b.WriteString(labelStyle.Render("Context") + textStyle.Render(m.k8sClient.Context()) + "\n")
+	b.WriteString(labelStyle.Render("Server") + textStyle.Render(m.k8sClient.Server()) + "\n")
+	b.WriteString(labelStyle.Render("Problem") + style.StatusError.Render(problem.String()) + "\n\n")
+	b.WriteString(textStyle.Render(problem.Hint()))
+	b.WriteString("\n\n")
+	b.WriteString(style.StatusMuted.Render(err.Error()))
+	b.WriteString("\n\n")
+	if m.connChecking {
+		b.WriteString(m.spinner.View() + " Checking...")
+	} else {
+		b.WriteString(style.StatusMuted.Render("r:retry  C:switch context  q:quit"))
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Error).
+		Padding(1, 2).
+		Width(80)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, boxStyle.Render(b.String()))
+}
+
+// renderAuthFailure renders the full-screen error shown when the cluster
+// keeps rejecting the credentials, even after rebuilding them from the
+// kubeconfig. Retrying rebuilds them again, e.g. after logging in outside k1s.
+func (m Model) renderAuthFailure(err error) string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(style.Error)
+	textStyle := lipgloss.NewStyle().Foreground(style.Text)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Authentication failed"))
+	b.WriteString("\n\n")
+	b.WriteString(textStyle.Render(fmt.Sprintf("The cluster of context %q rejected the credentials.", m.k8sClient.Context())))
+	b.WriteString("\n\n")
+	b.WriteString(style.StatusError.Render(err.Error()))
+	b.WriteString("\n\n")
+	b.WriteString(style.StatusMuted.Render("Log in again (e.g. refresh your OIDC or cloud CLI session), then retry."))
+	b.WriteString("\n\n")
+	b.WriteString(style.StatusMuted.Render("r:retry  q:quit"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(style.Error).
+		Padding(1, 2).
+		Width(70)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, boxStyle.Render(b.String()))
+}
+
 // renderMainContent renders the main content area with border and status bar.
 // The content is wrapped in a rounded border box with a status message below.
 func (m Model) renderMainContent(content string, contentWidth, contentHeight int) string {
@@ -195,7 +454,33 @@ func (m Model) renderMainContent(content string, contentWidth, contentHeight int
 		Bold(true).
 		Padding(0, 2).
 		Width(contentWidth + 2) // +2 for border
-	statusBar := statusStyle.Render(m.statusMsg)
+	status := m.statusMsg
+	if toast := m.notifications.ToastView(contentWidth - 4); toast != "" {
+		status = toast + "  " + status
+	}
+	if m.k8sClient.Reauthenticating() {
+		status = m.spinner.View() + " re-authenticating… " + status
+	}
+	if apis := m.k8sClient.APIs(); apis != nil && apis.ServerVersion != nil {
+		version := apis.ServerVersion.GitVersion
+		if skew := apis.SkewWarning(); skew != "" {
+			version += " ⚠ " + skew
+		}
+		status = "[" + version + "] " + status
+	}
+	if n := len(m.recentWarnings); n > 0 {
+		status = fmt.Sprintf("[⚠ %d warnings (!)] ", n) + status
+	}
+	if m.refresher.Paused() {
+		status = "[PAUSED] " + status
+	}
+	if m.k8sClient.ReadOnly() {
+		status = "[read-only] " + status
+	}
+	if snap := m.k8sClient.Replay(); snap != nil {
+		status = "[replay " + snap.Time.Local().Format("2006-01-02 15:04:05") + "] " + status
+	}
+	statusBar := statusStyle.Render(status)
 
 	return lipgloss.JoinVertical(lipgloss.Left, boxedContent, statusBar)
 }
@@ -6,13 +6,18 @@
 package view
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/deeplink"
 	"github.com/andrebassi/k1s/internal/adapters/repository"
 	"github.com/andrebassi/k1s/internal/adapters/tui/component"
 	"github.com/andrebassi/k1s/internal/adapters/tui/keys"
@@ -46,15 +51,31 @@ type Dashboard struct {
 	podActionMenu component.PodActionMenu
 	confirmDialog component.ConfirmDialog
 	resultViewer  component.ResultViewer
+	projection    component.ConfigProjectionViewer
 	focus         PanelFocus
 	fullscreen    bool
 	width         int
 	height        int
 	keys          keys.KeyMap
-	statusMsg     string // Temporary status message (e.g., "Copied!")
-	namespace     string // Current namespace for kubectl commands
-	context       string // Current context for kubectl commands
-	pendingAction *component.PodActionItem // Action waiting for confirmation
+	statusMsg     string                                              // Temporary status message (e.g., "Copied!")
+	namespace     string                                              // Current namespace for kubectl commands
+	context       string                                              // Current context for kubectl commands
+	pendingAction *component.PodActionItem                            // Action waiting for confirmation
+	watchedPVC    string                                              // PVC shown in the result viewer, refreshed on tick
+	describing    string                                              // Title of the describe loading from Resource Details
+	detailsLink   int                                                 // Resource Details link the describe was opened from
+	detailsReturn bool                                                // Reopen Resource Details when the describe closes
+	confirmLevel  func(namespace, action string) configs.ConfirmLevel // Resolves per-action confirmation level
+	imagePulls    []repository.ImagePullDiagnosis                     // Classified image pull failures per container
+	pullProgress  []repository.ImagePullProgress                      // Image pull progress of containers being created
+	limitRanges   []repository.ContainerLimitRange                    // Container LimitRanges in the pod's namespace
+	security      *repository.PodSecurityReport                       // Effective security contexts and admission checks
+	serviceChecks map[string]ServiceCheckMsg                          // Service check results by Service, for the pod
+	checking      map[string]bool                                     // Services with a check running
+	ingressTraces map[string]IngressTraceMsg                          // Route traces by Ingress, for the pod
+	tracing       map[string]bool                                     // Ingresses with a trace running
+	accessChecks  []repository.AccessCheck                            // What the pod's ServiceAccount may do
+	accessErr     error                                               // Why the access checks are incomplete
 }
 
 // NewDashboard creates a new dashboard view with all panels initialized.
@@ -71,6 +92,7 @@ func NewDashboard() Dashboard {
 		podActionMenu: component.NewPodActionMenu(),
 		confirmDialog: component.NewConfirmDialog(),
 		resultViewer:  component.NewResultViewer(),
+		projection:    component.NewConfigProjectionViewer(),
 		focus:         FocusLogs,
 		keys:          keys.DefaultKeyMap(),
 	}
@@ -82,11 +104,74 @@ func (d Dashboard) Init() tea.Cmd {
 
 // DeletePodRequest is sent to app.go to request pod deletion
 type DeletePodRequest struct {
+	Namespace   string
+	PodName     string
+	UID         string // Tells the pod from a replacement with the same name
+	GracePeriod int64  // Seconds the pod gets to stop before it counts as stuck
+}
+
+// ForceDeletePodRequest is sent to app.go to delete a pod stuck
+// terminating without waiting for its kubelet.
+type ForceDeletePodRequest struct {
+	Namespace string
+	PodName   string
+}
+
+// ForceDeletePodMessage asks to force delete a pod that is still
+// terminating, spelling out what can go wrong.
+func ForceDeletePodMessage(podName string) string {
+	return "Pod '" + podName + "' still terminating — force delete?\n\n" +
+		"The pod is removed from the API without waiting for its node to\n" +
+		"confirm its containers stopped. If the node is only unreachable,\n" +
+		"they may keep running, and a StatefulSet may start a replacement\n" +
+		"with the same identity and volumes alongside them."
+}
+
+// ExecRequest is sent to app.go to open a shell in a pod's container
+type ExecRequest struct {
 	Namespace string
 	PodName   string
+	Container string
 }
 
-// ExecFinishedMsg is sent when an external command finishes
+// AttachRequest is sent to app.go to attach to the main process of a
+// pod's container
+type AttachRequest struct {
+	Namespace string
+	PodName   string
+	Container string
+}
+
+// ProbeRunRequest asks app.go to run a probe of a pod's container once, as
+// the kubelet would. Answered with a ProbeRunMsg.
+type ProbeRunRequest struct {
+	Namespace string
+	PodName   string
+	Container string
+	Probe     string // "Startup", "Liveness" or "Readiness"
+	Config    *repository.ProbeInfo
+	PodIP     string
+	Ports     []repository.ContainerPort
+	Netshoot  string // Running netshoot debug container to fall back to, if any
+}
+
+// ProbeRunMsg is the result of a ProbeRunRequest.
+type ProbeRunMsg struct {
+	Request ProbeRunRequest
+	Result  *repository.ProbeRunResult
+	Via     string // Debug container the probe ran from, "" for the probed container
+	Err     error
+}
+
+// WatchRequest asks app.go to notify when a pod meets a condition, checked
+// on every refresh until it does.
+type WatchRequest struct {
+	Namespace string
+	PodName   string
+	Condition repository.WatchCondition
+}
+
+// ExecFinishedMsg is sent when an external command or shell finishes
 type ExecFinishedMsg struct {
 	Err error
 }
@@ -110,9 +195,250 @@ type ScaleRequestMsg struct {
 	WorkloadKind string
 	WorkloadName string
 	Namespace    string
+	Replicas     int32 // Current desired replicas, to undo the scale
 	NewReplicas  int32
 }
 
+// RemoveSchedulingGateRequest is sent to app.go to remove a pod scheduling gate
+type RemoveSchedulingGateRequest struct {
+	Namespace string
+	PodName   string
+	Gate      string
+}
+
+// HPADetailsRequest is sent to app.go to open the HPA viewer on the
+// autoscaler of the pod's workload
+type HPADetailsRequest struct {
+	Namespace string
+	Name      string
+}
+
+// NodeCapacityRequest is sent to app.go to open the node capacity table,
+// evaluating Pod on each node when it is not nil
+type NodeCapacityRequest struct {
+	Pod *repository.PodInfo
+}
+
+// PVCDetailsRequest is sent to app.go to load PVC, StorageClass and PV details
+type PVCDetailsRequest struct {
+	Namespace string
+	Name      string
+}
+
+// DescribeResourceRequest is sent to app.go to describe a Service, Ingress
+// or ConfigMap selected in Resource Details. Answered with a DescribeOutputMsg
+// titled "<Kind>: <Name>".
+type DescribeResourceRequest struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// RolloutActionsRequest is sent to app.go to open the workload actions
+// menu for the Argo Rollout owning the pod
+type RolloutActionsRequest struct {
+	Namespace string
+	Name      string
+	Replicas  int32
+}
+
+// ResourceYAMLRequest is sent to app.go to copy the YAML of the pod or its
+// workload to the clipboard, or, with Save, to prompt for a file to write it to
+type ResourceYAMLRequest struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Save      bool
+}
+
+// NodeActionsRequest is sent to app.go to open the node actions menu for
+// the node selected in the Resource Usage panel
+type NodeActionsRequest struct {
+	Node     string
+	Cordoned bool
+}
+
+// PVCDetailsMsg contains the loaded PVC details
+type PVCDetailsMsg struct {
+	Name    string
+	Details *repository.PVCDetails
+	Err     error
+}
+
+// ResourceYAMLResultMsg contains the result of copying or saving the YAML
+// of the pod or its workload
+type ResourceYAMLResultMsg struct {
+	Kind string
+	Name string
+	Path string // File written, "" when copied to the clipboard
+	Via  string // How it was copied, as component.CopyResult.Via
+	Err  error
+}
+
+// Status describes the result for the status bar.
+func (r ResourceYAMLResultMsg) Status() string {
+	switch {
+	case r.Err != nil:
+		return "YAML export failed: " + r.Err.Error()
+	case r.Path != "":
+		return fmt.Sprintf("Saved %s %s to %s", r.Kind, r.Name, r.Path)
+	}
+	return fmt.Sprintf("Copied %s %s YAML%s", r.Kind, r.Name, r.Via)
+}
+
+// PanelSaveResultMsg contains the result of saving the logs or events
+// panel to a file
+type PanelSaveResultMsg struct {
+	Panel string // "logs" or "events"
+	Path  string
+	Bytes int
+	Err   error
+}
+
+// Status describes the result for the status bar.
+func (r PanelSaveResultMsg) Status() string {
+	if r.Err != nil {
+		return "Save failed: " + r.Err.Error()
+	}
+	return fmt.Sprintf("Saved %s (%d bytes) to %s", r.Panel, r.Bytes, r.Path)
+}
+
+// SnapshotRequest asks app.go to export a snapshot of a pod
+type SnapshotRequest struct {
+	Namespace string
+	PodName   string
+}
+
+// FileBrowserRequest asks app.go to open the file browser on a container
+type FileBrowserRequest struct {
+	Namespace string
+	PodName   string
+	Container string
+}
+
+// ServiceCheckRequest asks app.go to test a related Service from inside the
+// pod, or from a netshoot debug container injected into it. Answered with
+// a ServiceCheckMsg.
+type ServiceCheckRequest struct {
+	Namespace string
+	PodName   string
+	Container string // Container to run the check in; ignored with Netshoot
+	Service   string
+	Netshoot  bool
+}
+
+// ServiceCheckMsg is the result of a ServiceCheckRequest.
+type ServiceCheckMsg struct {
+	PodName string
+	Service string
+	Check   *repository.ServiceCheck
+	Err     error
+}
+
+// IngressTraceRequest asks app.go to trace the routes of a related
+// Ingress, and of the VirtualServices whose hosts overlap its hosts, to the
+// pods behind them. Answered with an IngressTraceMsg.
+type IngressTraceRequest struct {
+	Namespace       string
+	PodName         string
+	Ingress         repository.IngressInfo
+	VirtualServices []repository.VirtualServiceInfo
+}
+
+// IngressTraceMsg is the result of an IngressTraceRequest.
+type IngressTraceMsg struct {
+	PodName string
+	Ingress string
+	Traces  []repository.RouteTrace
+	Err     error
+}
+
+// ConfigProjectionRequest is sent to app.go to resolve where the keys of a
+// ConfigMap or Secret land in a pod. Answered with a ConfigProjectionMsg.
+type ConfigProjectionRequest struct {
+	Namespace string
+	PodName   string
+	Kind      string // "ConfigMap" or "Secret"
+	Name      string
+}
+
+// ConfigProjectionMsg is the result of a ConfigProjectionRequest.
+type ConfigProjectionMsg struct {
+	PodName    string
+	Projection *repository.ConfigProjection
+	Err        error
+}
+
+// SnapshotStatusMsg reports the progress or result of a snapshot export
+type SnapshotStatusMsg struct {
+	Status string
+}
+
+// DebugContainerRequest is sent to app.go to inject an ephemeral debug
+// container running Image into a pod, sharing the processes of Target, and
+// open a shell in it. Answered with a DebugContainerMsg.
+type DebugContainerRequest struct {
+	Namespace string
+	PodName   string
+	Target    string
+	Image     string
+}
+
+// DebugContainerMsg reports the debug container started for a
+// DebugContainerRequest.
+type DebugContainerMsg struct {
+	PodName   string
+	Container string
+	Err       error
+}
+
+// RestartPodRequest is sent to app.go to delete a pod and re-attach the
+// dashboard to its replacement. Answered with a PodRestartedMsg
+type RestartPodRequest struct {
+	Namespace string
+	PodName   string
+}
+
+// PodRestartedMsg contains the replacement of a restarted pod
+type PodRestartedMsg struct {
+	OldName string
+	Pod     *repository.PodInfo
+	Err     error
+}
+
+// RestartWorkloadRequest is sent to app.go to roll out a restart of the
+// workload owning the pod
+type RestartWorkloadRequest struct {
+	Namespace string
+	Kind      string
+	Name      string
+}
+
+// SchedulingGateRemovedMsg contains the result of a scheduling gate removal
+type SchedulingGateRemovedMsg struct {
+	Gate string
+	Err  error
+}
+
+// PortForwardRequest is sent to app.go to forward a local port to Port of
+// a pod, or to Port of a Service in front of it when Service is set. The
+// local port is LocalPort, or the same port when 0.
+type PortForwardRequest struct {
+	Namespace string
+	PodName   string
+	Service   string
+	Port      int32
+	LocalPort int
+}
+
+// PortForwardMsg reports the port-forward started for a
+// PortForwardRequest.
+type PortForwardMsg struct {
+	Request PortForwardRequest
+	Forward repository.PortForward
+	Err     error
+}
+
 func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
@@ -127,13 +453,193 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 		return d, nil
 	}
 
+	// Handle DebugContainerMsg (debug container started or failed)
+	if result, ok := msg.(DebugContainerMsg); ok {
+		if d.pod == nil || d.pod.Name != result.PodName {
+			return d, nil
+		}
+		if result.Err != nil {
+			d.statusMsg = "Debug container failed: " + result.Err.Error()
+		} else {
+			d.statusMsg = "Debug container " + result.Container + " running"
+		}
+		return d, nil
+	}
+	// Handle PortForwardMsg (port-forward started or failed)
+	if result, ok := msg.(PortForwardMsg); ok {
+		if result.Err != nil {
+			d.statusMsg = "Port-forward failed: " + result.Err.Error()
+		} else {
+			pf := result.Forward
+			d.statusMsg = fmt.Sprintf("Forwarding localhost:%d -> %s:%d (F to list)", pf.LocalPort, pf.Pod, pf.RemotePort)
+		}
+		return d, nil
+	}
+
 	// Handle DescribeOutputMsg (display describe output in result viewer)
 	if result, ok := msg.(DescribeOutputMsg); ok {
 		if result.Err != nil {
 			d.statusMsg = "Describe failed: " + result.Err.Error()
 		} else {
-			d.resultViewer.Show(result.Title, result.Content, d.width-4, d.height-4)
+			d.statusMsg = ""
+			d.resultViewer.ShowYAML(result.Title, result.Content, d.width-4, d.height-4)
+		}
+		// A resource opened from Resource Details returns there when closed
+		d.detailsReturn = result.Err == nil && result.Title != "" && result.Title == d.describing
+		d.describing = ""
+		return d, nil
+	}
+
+	// Handle ResultViewerOpenLinkMsg (describe a resource from Resource Details)
+	if result, ok := msg.(component.ResultViewerOpenLinkMsg); ok {
+		if d.pod == nil {
+			return d, nil
+		}
+		d.statusMsg = "Loading describe..."
+		d.describing = result.Link.Kind + ": " + result.Link.Name
+		d.detailsLink = result.Index
+		req := DescribeResourceRequest{Kind: result.Link.Kind, Namespace: d.pod.Namespace, Name: result.Link.Name}
+		return d, func() tea.Msg {
+			return req
+		}
+	}
+
+	// Handle ResultViewerCheckLinkMsg (test a Service from Resource Details)
+	if result, ok := msg.(component.ResultViewerCheckLinkMsg); ok {
+		if d.pod != nil && result.Link.Kind == "Ingress" {
+			return d, d.startIngressTrace(result.Link.Name)
+		}
+		if d.pod == nil || d.checking[result.Link.Name] {
+			return d, nil
+		}
+		req := ServiceCheckRequest{
+			Namespace: d.pod.Namespace,
+			PodName:   d.pod.Name,
+			Container: d.checkContainer(),
+			Service:   result.Link.Name,
+			Netshoot:  result.Netshoot,
+		}
+		if req.Netshoot {
+			// Ephemeral containers stay in the pod spec for good
+			cmd := d.confirmDialog.Request(
+				d.confirmLevelFor(configs.ActionDebugContainer),
+				"Inject Debug Container",
+				"Add a netshoot container to pod '"+d.pod.Name+"' to test service '"+req.Service+"'?\n"+
+					"Ephemeral containers can't be removed; it exits after an hour.",
+				"netshoot-check",
+				d.pod.Name,
+				req,
+			)
+			return d, cmd
+		}
+		return d, d.startServiceCheck(req)
+	}
+
+	// Handle ResultViewerProjectLinkMsg (keys of a ConfigMap or Secret in the pod)
+	if result, ok := msg.(component.ResultViewerProjectLinkMsg); ok {
+		if d.pod == nil {
+			return d, nil
+		}
+		d.statusMsg = "Resolving " + result.Link.Kind + " " + result.Link.Name + "..."
+		d.detailsLink = result.Index
+		req := ConfigProjectionRequest{Namespace: d.pod.Namespace, PodName: d.pod.Name, Kind: result.Link.Kind, Name: result.Link.Name}
+		return d, func() tea.Msg {
+			return req
+		}
+	}
+
+	// Handle ConfigProjectionMsg (projection resolved)
+	if result, ok := msg.(ConfigProjectionMsg); ok {
+		if d.pod == nil || d.pod.Name != result.PodName {
+			return d, nil
+		}
+		if result.Err != nil {
+			d.statusMsg = "Resolving keys failed: " + result.Err.Error()
+			return d, nil
+		}
+		d.statusMsg = ""
+		d.resultViewer.Hide()
+		d.projection.SetSize(d.width-4, d.height-4)
+		d.projection.Show(result.Projection)
+		return d, nil
+	}
+
+	// Closing the projection returns to Resource Details
+	if _, ok := msg.(component.ConfigProjectionClosed); ok {
+		if d.pod != nil {
+			d.showDetailedResources()
+			d.resultViewer.SelectLink(d.detailsLink)
+		}
+		return d, nil
+	}
+
+	// Handle RevealSecretKeyRequest (show a Secret value, once confirmed)
+	if req, ok := msg.(component.RevealSecretKeyRequest); ok {
+		d.confirmDialog.Show("Reveal Secret Value",
+			"Show the value of key '"+req.Key+"' of Secret '"+req.Secret+"' on screen?",
+			"reveal-secret-key", req)
+		return d, nil
+	}
+
+	// Handle ServiceCheckMsg (service check result)
+	if result, ok := msg.(ServiceCheckMsg); ok {
+		if d.pod == nil || d.pod.Name != result.PodName {
+			return d, nil
+		}
+		delete(d.checking, result.Service)
+		if d.serviceChecks == nil {
+			d.serviceChecks = make(map[string]ServiceCheckMsg)
+		}
+		d.serviceChecks[result.Service] = result
+		switch {
+		case result.Err != nil:
+			d.statusMsg = "Service check failed: " + result.Err.Error()
+		case result.Check.Reachable():
+			d.statusMsg = "Service " + result.Service + " is reachable"
+		default:
+			d.statusMsg = "Service " + result.Service + " is not reachable"
+		}
+		d.refreshDetailedResources()
+		return d, nil
+	}
+
+	// Handle ProbeRunMsg (probe run by hand)
+	if result, ok := msg.(ProbeRunMsg); ok {
+		if d.pod == nil || d.pod.Name != result.Request.PodName {
+			return d, nil
+		}
+		req := result.Request
+		if result.Err != nil {
+			d.statusMsg = "Probe run failed: " + result.Err.Error()
+			return d, nil
 		}
+		d.statusMsg = req.Probe + " probe of " + req.Container + ": " + result.Result.Summary()
+		d.resultViewer.Show("Probe: "+req.Container+" "+strings.ToLower(req.Probe),
+			component.RenderProbeRun(req.Container, req.Probe, req.Config, result.Result, result.Via), d.width-4, d.height-4)
+		return d, nil
+	}
+
+	// Handle IngressTraceMsg (routes of an Ingress traced to the pods)
+	if result, ok := msg.(IngressTraceMsg); ok {
+		if d.pod == nil || d.pod.Name != result.PodName {
+			return d, nil
+		}
+		delete(d.tracing, result.Ingress)
+		if d.ingressTraces == nil {
+			d.ingressTraces = make(map[string]IngressTraceMsg)
+		}
+		d.ingressTraces[result.Ingress] = result
+		d.statusMsg = "Ingress " + result.Ingress + ": every route reaches its pods"
+		if result.Err != nil {
+			d.statusMsg = "Ingress trace failed: " + result.Err.Error()
+		}
+		for _, trace := range result.Traces {
+			if hop := trace.FailedHop(); hop != nil {
+				d.statusMsg = "Ingress " + result.Ingress + ": traffic stops at " + strings.ToLower(hop.Kind) + " (" + hop.Detail + ")"
+				break
+			}
+		}
+		d.refreshDetailedResources()
 		return d, nil
 	}
 
@@ -147,10 +653,86 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 		return d, nil
 	}
 
+	// Handle SchedulingGateRemovedMsg (gate removal result)
+	if result, ok := msg.(SchedulingGateRemovedMsg); ok {
+		if result.Err != nil {
+			d.statusMsg = "Remove gate failed: " + result.Err.Error()
+		} else {
+			d.statusMsg = fmt.Sprintf("Removed scheduling gate '%s'", result.Gate)
+		}
+		return d, nil
+	}
+
+	// Handle PodRestartedMsg (re-attach to the replacement pod)
+	if result, ok := msg.(PodRestartedMsg); ok {
+		if d.pod == nil || d.pod.Name != result.OldName {
+			return d, nil
+		}
+		if result.Err != nil {
+			d.statusMsg = "Restart failed: " + result.Err.Error()
+			return d, nil
+		}
+		d.SetPod(result.Pod)
+		d.statusMsg = fmt.Sprintf("Restarted %s, now on %s", result.OldName, result.Pod.Name)
+		return d, nil
+	}
+
+	// Handle EventLogsRequest (logs around the selected event)
+	if result, ok := msg.(component.EventLogsRequest); ok {
+		d.logs.SetTimeRange(repository.LogTimeRangeAround(result.Time, component.EventLogsWindow))
+		d.focus = FocusLogs
+		return d, nil
+	}
+
+	// Handle ResourceYAMLResultMsg (YAML copied or saved)
+	if result, ok := msg.(ResourceYAMLResultMsg); ok {
+		d.statusMsg = result.Status()
+		return d, nil
+	}
+
+	// Handle PanelSaveResultMsg (logs or events saved to a file)
+	if result, ok := msg.(PanelSaveResultMsg); ok {
+		d.statusMsg = result.Status()
+		return d, nil
+	}
+
+	// Handle ReadOnlyMsg (mutating action refused in read-only mode)
+	if result, ok := msg.(component.ReadOnlyMsg); ok {
+		d.statusMsg = result.Status()
+		return d, nil
+	}
+
+	// Handle SnapshotStatusMsg (snapshot export progress)
+	if result, ok := msg.(SnapshotStatusMsg); ok {
+		d.statusMsg = result.Status
+		return d, nil
+	}
+
+	// Handle PVCDetailsMsg (initial load or watch refresh)
+	if result, ok := msg.(PVCDetailsMsg); ok {
+		if result.Name != d.watchedPVC {
+			return d, nil // viewer was closed or switched to another claim
+		}
+		if result.Err != nil {
+			d.watchedPVC = ""
+			d.statusMsg = "PVC details failed: " + result.Err.Error()
+			return d, nil
+		}
+		content := component.RenderPVCDetails(result.Details)
+		title := "PVC: " + result.Name
+		if d.resultViewer.IsVisible() && d.resultViewer.Title() == title {
+			d.resultViewer.SetContent(content)
+		} else {
+			d.statusMsg = ""
+			d.resultViewer.Show(title, content, d.width-4, d.height-4)
+		}
+		return d, nil
+	}
+
 	// Handle ActionMenuResult (copy commands)
 	if result, ok := msg.(component.ActionMenuResult); ok {
 		if result.Copied && result.Err == nil {
-			d.statusMsg = "Copied: " + result.Item.Label
+			d.statusMsg = "Copied: " + result.Item.Label + result.Result.Via()
 		} else if result.Err != nil {
 			d.statusMsg = "Copy failed: " + result.Err.Error()
 		}
@@ -172,34 +754,111 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 	if result, ok := msg.(component.PodActionMenuResult); ok {
 		switch result.Item.Action {
 		case "delete":
-			// Show confirmation dialog
-			d.confirmDialog.Show(
+			// A pod past its grace period won't go away by deleting it again
+			if repository.PodStuckTerminating(d.pod, time.Now()) {
+				cmd := d.confirmDialog.Request(
+					d.confirmLevelFor(configs.ActionForceDeletePod),
+					"Force Delete Pod",
+					ForceDeletePodMessage(d.pod.Name),
+					"force-delete",
+					d.pod.Name,
+					ForceDeletePodRequest{Namespace: d.pod.Namespace, PodName: d.pod.Name},
+				)
+				return d, cmd
+			}
+			// Ask for confirmation at the configured level
+			cmd := d.confirmDialog.Request(
+				d.confirmLevelFor(configs.ActionDeletePod),
 				"Delete Pod",
 				"Are you sure you want to delete pod '"+d.pod.Name+"'?",
 				"delete",
+				d.pod.Name,
 				d.pod,
 			)
-			return d, nil
+			return d, cmd
+		case "restart-pod":
+			cmd := d.confirmDialog.Request(
+				d.confirmLevelFor(configs.ActionDeletePod),
+				"Restart Pod",
+				"Delete pod '"+d.pod.Name+"' and re-attach to its replacement?",
+				"restart-pod",
+				d.pod.Name,
+				RestartPodRequest{Namespace: d.pod.Namespace, PodName: d.pod.Name},
+			)
+			return d, cmd
+		case "debug-container":
+			// Ephemeral containers stay in the pod spec for good
+			target, image, _ := strings.Cut(result.Item.Target, "/")
+			cmd := d.confirmDialog.Request(
+				d.confirmLevelFor(configs.ActionDebugContainer),
+				"Debug Container",
+				"Add a "+image+" container to pod '"+d.pod.Name+"' sharing the processes of '"+target+"'?\n"+
+					"Ephemeral containers can't be removed; it exits on its own after an hour.",
+				"debug-container",
+				d.pod.Name,
+				DebugContainerRequest{Namespace: d.pod.Namespace, PodName: d.pod.Name, Target: target, Image: image},
+			)
+			return d, cmd
+		case "restart-workload":
+			kind, name, _ := strings.Cut(result.Item.Target, "/")
+			cmd := d.confirmDialog.Request(
+				d.confirmLevelFor(configs.ActionRestart),
+				"Restart "+kind,
+				"Roll out a restart of every pod of '"+name+"'?",
+				"restart-workload",
+				name,
+				RestartWorkloadRequest{Namespace: d.pod.Namespace, Kind: kind, Name: name},
+			)
+			return d, cmd
 		case "exec":
 			// Show confirmation before exec
 			d.pendingAction = &result.Item
 			d.confirmDialog.Show(
 				"Exec into Pod",
-				"Open shell in '"+d.pod.Name+"'?\nThis will suspend the UI until you exit the shell.",
+				"Open shell in '"+d.pod.Name+"' ("+result.Item.Target+")?\nThis will suspend the UI until you exit the shell.",
 				"exec",
 				d.pod,
 			)
 			return d, nil
-		case "port-forward":
-			// Show confirmation before port-forward
+		case "attach":
 			d.pendingAction = &result.Item
 			d.confirmDialog.Show(
-				"Port Forward",
-				"Start port forwarding for '"+d.pod.Name+"'?\nPress Ctrl+C in terminal to stop and return.",
-				"port-forward",
+				"Attach to Container",
+				"Attach to the main process of '"+d.pod.Name+"' ("+result.Item.Target+")?\nInput goes to the process; Ctrl+P Ctrl+Q detaches and leaves it running.",
+				"attach",
 				d.pod,
 			)
 			return d, nil
+		case "run-probe":
+			req, ok := d.probeRunRequest(result.Item.Target)
+			if !ok {
+				return d, nil
+			}
+			d.statusMsg = "Running " + strings.ToLower(req.Probe) + " probe of " + req.Container + "..."
+			return d, func() tea.Msg {
+				return req
+			}
+		case "watch":
+			req := WatchRequest{Namespace: d.pod.Namespace, PodName: d.pod.Name, Condition: repository.WatchCondition(result.Item.Target)}
+			return d, func() tea.Msg {
+				return req
+			}
+		case "port-forward":
+			// Runs in the background; app.go keeps track of it
+			service, port, ok := component.ParsePortForwardTarget(result.Item.Target)
+			if !ok {
+				return d, nil
+			}
+			req := PortForwardRequest{
+				Namespace: d.pod.Namespace,
+				PodName:   d.pod.Name,
+				Service:   service,
+				Port:      port,
+			}
+			d.statusMsg = "Starting port-forward..."
+			return d, func() tea.Msg {
+				return req
+			}
 		case "describe":
 			// Run describe command and capture output
 			d.statusMsg = "Loading describe..."
@@ -216,11 +875,70 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 					Content: string(output),
 				}
 			}
+		case "remove-gate":
+			// Gates belong to a controller, so removing one by hand needs confirmation
+			cmd := d.confirmDialog.Request(
+				d.confirmLevelFor(configs.ActionRemoveSchedulingGate),
+				"Remove Scheduling Gate",
+				"Remove gate '"+result.Item.Target+"' from pod '"+d.pod.Name+"'?\n"+
+					"Only do this if the controller owning the gate is broken.",
+				"remove-gate",
+				d.pod.Name,
+				RemoveSchedulingGateRequest{
+					Namespace: d.pod.Namespace,
+					PodName:   d.pod.Name,
+					Gate:      result.Item.Target,
+				},
+			)
+			return d, cmd
+		case "rollout-actions":
+			req := RolloutActionsRequest{Namespace: d.pod.Namespace, Name: result.Item.Target, Replicas: d.manifest.GetReplicas()}
+			return d, func() tea.Msg {
+				return req
+			}
+		case "hpa-details":
+			req := HPADetailsRequest{Namespace: d.pod.Namespace, Name: result.Item.Target}
+			return d, func() tea.Msg {
+				return req
+			}
+		case "node-capacity":
+			req := NodeCapacityRequest{}
+			if d.pod.Node == "" {
+				pod := *d.pod
+				req.Pod = &pod
+			}
+			return d, func() tea.Msg {
+				return req
+			}
+		case "pvc-details":
+			// Load details through app.go; they refresh on every tick while open
+			d.statusMsg = "Loading PVC details..."
+			d.watchedPVC = result.Item.Target
+			req := PVCDetailsRequest{Namespace: d.pod.Namespace, Name: result.Item.Target}
+			return d, func() tea.Msg {
+				return req
+			}
+		case "copy-yaml", "save-yaml":
+			kind, name, _ := strings.Cut(result.Item.Target, "/")
+			req := ResourceYAMLRequest{Namespace: d.pod.Namespace, Kind: kind, Name: name, Save: result.Item.Action == "save-yaml"}
+			return d, func() tea.Msg {
+				return req
+			}
+		case "export-snapshot":
+			req := SnapshotRequest{Namespace: d.pod.Namespace, PodName: d.pod.Name}
+			return d, func() tea.Msg {
+				return req
+			}
+		case "browse-files":
+			req := FileBrowserRequest{Namespace: d.pod.Namespace, PodName: d.pod.Name, Container: result.Item.Target}
+			return d, func() tea.Msg {
+				return req
+			}
 		case "copy":
 			// Copy the command to clipboard
-			err := component.CopyToClipboard(result.Item.Command)
+			copied, err := component.CopyToClipboard(result.Item.Command)
 			if err == nil {
-				d.statusMsg = "Copied: " + result.Item.Label
+				d.statusMsg = "Copied: " + result.Item.Label + copied.Via()
 			} else {
 				d.statusMsg = "Copy failed: " + err.Error()
 			}
@@ -238,23 +956,80 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 					d.statusMsg = "Deleting pod..."
 					return d, func() tea.Msg {
 						return DeletePodRequest{
-							Namespace: pod.Namespace,
-							PodName:   pod.Name,
+							Namespace:   pod.Namespace,
+							PodName:     pod.Name,
+							UID:         pod.UID,
+							GracePeriod: pod.TerminationGracePeriod,
 						}
 					}
 				}
-			case "exec", "port-forward":
-				// Execute the pending action
-				if d.pendingAction != nil {
-					cmdStr := d.pendingAction.Command
+			case "force-delete":
+				if req, ok := result.Data.(ForceDeletePodRequest); ok {
+					d.statusMsg = "Force deleting pod..."
+					return d, func() tea.Msg {
+						return req
+					}
+				}
+			case "restart-pod":
+				if req, ok := result.Data.(RestartPodRequest); ok {
+					d.statusMsg = "Restarting pod, waiting for its replacement..."
+					return d, func() tea.Msg {
+						return req
+					}
+				}
+			case "reveal-secret-key":
+				if req, ok := result.Data.(component.RevealSecretKeyRequest); ok {
+					d.projection.Reveal(req.Key)
+				}
+			case "netshoot-check":
+				if req, ok := result.Data.(ServiceCheckRequest); ok {
+					return d, d.startServiceCheck(req)
+				}
+			case "debug-container":
+				if req, ok := result.Data.(DebugContainerRequest); ok {
+					d.statusMsg = "Starting debug container..."
+					return d, func() tea.Msg {
+						return req
+					}
+				}
+			case "restart-workload":
+				if req, ok := result.Data.(RestartWorkloadRequest); ok {
+					d.statusMsg = "Restarting " + req.Name + "..."
+					return d, func() tea.Msg {
+						return req
+					}
+				}
+			case "remove-gate":
+				if req, ok := result.Data.(RemoveSchedulingGateRequest); ok {
+					d.statusMsg = "Removing scheduling gate..."
+					return d, func() tea.Msg {
+						return req
+					}
+				}
+			case "exec":
+				// The shell runs in-process, which app.go sets up
+				if d.pendingAction != nil && d.pod != nil {
+					req := ExecRequest{
+						Namespace: d.pod.Namespace,
+						PodName:   d.pod.Name,
+						Container: d.pendingAction.Target,
+					}
 					d.pendingAction = nil
-					c := exec.Command("sh", "-c", cmdStr)
-					return d, tea.ExecProcess(c, func(err error) tea.Msg {
-						if err != nil {
-							return ExecFinishedMsg{Err: err}
-						}
-						return ExecFinishedMsg{}
-					})
+					return d, func() tea.Msg {
+						return req
+					}
+				}
+			case "attach":
+				if d.pendingAction != nil && d.pod != nil {
+					req := AttachRequest{
+						Namespace: d.pod.Namespace,
+						PodName:   d.pod.Name,
+						Container: d.pendingAction.Target,
+					}
+					d.pendingAction = nil
+					return d, func() tea.Msg {
+						return req
+					}
 				}
 			}
 		} else {
@@ -272,9 +1047,19 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 			return d, cmd
 		}
 
+		if d.projection.IsVisible() {
+			d.projection, cmd = d.projection.Update(msg)
+			return d, cmd
+		}
+
 		// Result viewer takes priority (for describe output etc)
 		if d.resultViewer.IsVisible() {
 			d.resultViewer, cmd = d.resultViewer.Update(msg)
+			if !d.resultViewer.IsVisible() && d.detailsReturn && d.pod != nil {
+				d.detailsReturn = false
+				d.showDetailedResources()
+				d.resultViewer.SelectLink(d.detailsLink)
+			}
 			return d, cmd
 		}
 
@@ -298,8 +1083,8 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 			return d, nil
 		}
 
-		// When in fullscreen logs mode and searching, pass all keys to logs panel
-		if d.fullscreen && d.focus == FocusLogs && d.logs.IsSearching() {
+		// When the logs panel is searching or editing its time range, pass all keys to it
+		if d.focus == FocusLogs && d.logs.IsSearching() {
 			d.logs, cmd = d.logs.Update(msg)
 			return d, cmd
 		}
@@ -332,6 +1117,14 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 		d.statusMsg = ""
 
 		switch {
+		case key.Matches(msg, d.keys.PodActions) && d.focus == FocusMetrics && d.metrics.SelectedNode() != nil:
+			// Node Info box of Resource Usage: actions on the pod's node
+			node := d.metrics.SelectedNode()
+			req := NodeActionsRequest{Node: node.Name, Cordoned: node.Unschedulable}
+			return d, func() tea.Msg {
+				return req
+			}
+
 		case key.Matches(msg, d.keys.PodActions):
 			if d.pod != nil {
 				var containers []string
@@ -339,10 +1132,36 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 					containers = append(containers, c.Name)
 				}
 				items := component.PodActions(d.namespace, d.pod.Name, containers)
+				items = append(items, component.PortForwardActions(d.namespace, d.pod.Name, d.pod.Containers, d.related)...)
+				items = append(items, component.FileBrowserActions(containers)...)
+				items = append(items, component.AttachActions(d.namespace, d.pod.Name, d.pod.Containers)...)
+				items = append(items, component.ProbeRunActions(d.manifest.Probes())...)
+				items = append(items, component.PodWatchActions(repository.PodInfoReady(*d.pod))...)
+				items = append(items, component.DebugContainerActions(d.namespace, d.pod.Name, containers, d.pod.EphemeralContainers)...)
+				ownerKind, ownerName := d.manifest.GetWorkload()
+				items = append(items, component.RestartActions(d.namespace, d.pod.Name, ownerKind, ownerName)...)
+				items = append(items, component.SchedulingGateActions(d.namespace, d.pod.Name, d.pod.SchedulingGates)...)
+				items = append(items, component.NodeCapacityAction(d.pod.Node == "")...)
+				items = append(items, component.RolloutOwnerActions(d.manifest.GetWorkload())...)
+				items = append(items, component.HPADetailActions(d.namespace, d.related)...)
+				items = append(items, component.PVCDetailActions(d.namespace, d.pod.Volumes)...)
+				items = append(items, component.ResourceYAMLActions(d.namespace, "Pod", d.pod.Name)...)
+				items = append(items, component.ResourceYAMLActions(d.namespace, ownerKind, ownerName)...)
+				items = append(items, component.SnapshotAction(d.pod.Name)...)
+				items = append(items, component.ShareLinkAction(d.ShareLink())...)
 				d.podActionMenu.Show("Pod Actions", items)
 			}
 			return d, nil
 
+		case key.Matches(msg, d.keys.CopyCommands) && d.focus == FocusMetrics && d.metrics.MetricsServerMissing():
+			copied, err := component.CopyToClipboard(repository.MetricsServerInstallCommand)
+			if err == nil {
+				d.statusMsg = "Copied: metrics-server install command" + copied.Via()
+			} else {
+				d.statusMsg = "Copy failed: " + err.Error()
+			}
+			return d, nil
+
 		case key.Matches(msg, d.keys.CopyCommands):
 			if d.pod != nil {
 				var containers []string
@@ -359,6 +1178,12 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 			d.help.Toggle()
 			return d, nil
 
+		case key.Matches(msg, d.keys.Diagnosis):
+			if diagnosis := d.manifest.Diagnosis(); diagnosis != nil && d.pod != nil {
+				d.resultViewer.Show("Diagnosis: "+d.pod.Name, renderDiagnosis(diagnosis), d.width-4, d.height-4)
+			}
+			return d, nil
+
 		case key.Matches(msg, d.keys.NextPanel):
 			d.nextPanel()
 			return d, nil
@@ -415,6 +1240,7 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 						WorkloadKind: workloadKind,
 						WorkloadName: workloadName,
 						Namespace:    d.namespace,
+						Replicas:     currentReplicas,
 						NewReplicas:  newReplicas,
 					}
 				}
@@ -432,6 +1258,7 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 							WorkloadKind: workloadKind,
 							WorkloadName: workloadName,
 							Namespace:    d.namespace,
+							Replicas:     currentReplicas,
 							NewReplicas:  newReplicas,
 						}
 					}
@@ -556,8 +1383,7 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 			}
 			// Enter on Pod Details panel shows detailed resource info
 			if d.focus == FocusManifest && d.pod != nil {
-				content := d.renderDetailedResources()
-				d.resultViewer.Show("Resource Details: "+d.pod.Name, content, d.width-4, d.height-4)
+				d.showDetailedResources()
 				return d, nil
 			}
 			// Enter on Resource Usage panel shows kubectl describe
@@ -638,6 +1464,10 @@ func (d Dashboard) View() string {
 		return d.renderFloatingDialog(d.confirmDialog.View())
 	}
 
+	if d.projection.IsVisible() {
+		return d.renderFloatingDialog(d.projection.View())
+	}
+
 	// Render result viewer as overlay (for describe output etc)
 	if d.resultViewer.IsVisible() {
 		return d.renderFloatingDialog(d.resultViewer.View())
@@ -734,16 +1564,39 @@ func (d Dashboard) renderFloatingDialog(dialogContent string) string {
 }
 
 func (d *Dashboard) SetPod(pod *repository.PodInfo) {
+	newPod := d.pod == nil || pod == nil || d.pod.Name != pod.Name || d.pod.Namespace != pod.Namespace
+	if newPod {
+		d.serviceChecks = nil
+		d.checking = nil
+		d.ingressTraces = nil
+		d.tracing = nil
+		d.accessChecks, d.accessErr = nil, nil
+	}
+	// Jump to the failing init container when the pod gets stuck in init
+	stuckInInit := pod != nil && repository.IsInitStatus(pod.Status) &&
+		(newPod || !repository.IsInitStatus(d.pod.Status))
 	d.pod = pod
 	d.manifest.SetPod(pod)
 	d.metrics.SetPod(pod)
 
-	// Extract container names for logs panel
-	var containerNames []string
+	// Extract container names for logs panel, init containers first
+	var containerNames, initNames []string
+	for _, c := range pod.InitContainers {
+		initNames = append(initNames, c.Name)
+	}
+	containerNames = append(containerNames, initNames...)
 	for _, c := range pod.Containers {
 		containerNames = append(containerNames, c.Name)
 	}
+	d.logs.SetInitContainers(initNames)
 	d.logs.SetContainers(containerNames)
+	d.logs.SetNextRestarts(nextRestarts(pod))
+
+	if stuckInInit {
+		if name := repository.FailingInitContainer(pod); name != "" {
+			d.logs.SelectContainer(name)
+		}
+	}
 }
 
 func (d *Dashboard) SetLogs(logs []repository.LogLine) {
@@ -756,6 +1609,23 @@ func (d *Dashboard) SetLogs(logs []repository.LogLine) {
 	d.logs.SetLogs(logs)
 }
 
+// SetComparedLogs shows merged previous and current logs in the logs panel
+// (see component.LogsPanel.SetComparedLogs).
+func (d *Dashboard) SetComparedLogs(logs []repository.LogLine, hasPrevious bool) {
+	if d.fullscreen && d.focus == FocusLogs {
+		d.logs.SetSize(d.width-4, d.height-8)
+	}
+	d.logs.SetComparedLogs(logs, hasPrevious)
+}
+
+// AppendLogs adds lines from the followed log stream to the logs panel.
+func (d *Dashboard) AppendLogs(logs []repository.LogLine) {
+	if d.fullscreen && d.focus == FocusLogs {
+		d.logs.SetSize(d.width-4, d.height-8)
+	}
+	d.logs.AppendLogs(logs)
+}
+
 func (d *Dashboard) SetEvents(events []repository.EventInfo) {
 	// When fullscreen, update size before setting events to ensure proper viewport
 	if d.fullscreen && d.focus == FocusEvents {
@@ -766,6 +1636,20 @@ func (d *Dashboard) SetEvents(events []repository.EventInfo) {
 	d.events.SetEvents(events)
 }
 
+// AddEvents merges live events from the event watch into the events panel.
+func (d *Dashboard) AddEvents(events []repository.EventInfo) {
+	if d.fullscreen && d.focus == FocusEvents {
+		d.events.SetSize(d.width-4, d.height-8)
+	}
+	d.events.AddEvents(events)
+}
+
+// SetMetricsStatus sets whether metrics-server answers, which Resource
+// Usage explains while it has no usage to show.
+func (d *Dashboard) SetMetricsStatus(status repository.MetricsStatus) {
+	d.metrics.SetStatus(status)
+}
+
 func (d *Dashboard) SetMetrics(metrics *repository.PodMetrics) {
 	d.metrics.SetMetrics(metrics)
 }
@@ -775,6 +1659,13 @@ func (d *Dashboard) SetRelated(related *repository.RelatedResources) {
 	d.manifest.SetRelated(related)
 }
 
+// SetAccessChecks sets the answered access checks of the pod's
+// ServiceAccount, shown under Permissions, and the error that cut them
+// short, if any.
+func (d *Dashboard) SetAccessChecks(checks []repository.AccessCheck, err error) {
+	d.accessChecks, d.accessErr = checks, err
+}
+
 func (d *Dashboard) SetNode(node *repository.NodeInfo) {
 	d.metrics.SetNode(node)
 }
@@ -783,6 +1674,61 @@ func (d *Dashboard) SetHelpers(helpers []repository.DebugHelper) {
 	d.manifest.SetHelpers(helpers)
 }
 
+// SetDiagnosis sets the root cause shown in the Pod Details banner.
+func (d *Dashboard) SetDiagnosis(diagnosis *repository.CrashDiagnosis) {
+	d.manifest.SetDiagnosis(diagnosis)
+}
+
+// SetOOM sets the OOM kills and memory headroom shown in the Memory section
+// of Pod Details.
+func (d *Dashboard) SetOOM(report *repository.OOMReport) {
+	d.manifest.SetOOM(report)
+}
+
+// SetProbes sets the probe status shown in the Probes section of Pod Details.
+func (d *Dashboard) SetProbes(probes []repository.ProbeStatus) {
+	d.manifest.SetProbes(probes)
+}
+
+// SetSecurity sets the effective security contexts and Pod Security
+// Admission checks shown in Pod Details and Resource Details.
+func (d *Dashboard) SetSecurity(report *repository.PodSecurityReport) {
+	d.security = report
+	d.manifest.SetSecurity(report)
+}
+
+func (d *Dashboard) SetImagePulls(diagnoses []repository.ImagePullDiagnosis) {
+	d.imagePulls = diagnoses
+}
+
+func (d *Dashboard) SetImagePullProgress(progress []repository.ImagePullProgress) {
+	d.pullProgress = progress
+}
+
+func (d *Dashboard) SetLimitRanges(ranges []repository.ContainerLimitRange) {
+	d.limitRanges = ranges
+}
+
+// pullProgressFor returns the image pull progress of a container, if any.
+func (d Dashboard) pullProgressFor(container string) *repository.ImagePullProgress {
+	for i := range d.pullProgress {
+		if d.pullProgress[i].Container == container {
+			return &d.pullProgress[i]
+		}
+	}
+	return nil
+}
+
+// imagePullFor returns the pull failure diagnosis for a container, if any.
+func (d Dashboard) imagePullFor(container string) *repository.ImagePullDiagnosis {
+	for i := range d.imagePulls {
+		if d.imagePulls[i].Container == container {
+			return &d.imagePulls[i]
+		}
+	}
+	return nil
+}
+
 func (d *Dashboard) SetSize(width, height int) {
 	d.width = width
 	d.height = height
@@ -794,6 +1740,105 @@ func (d *Dashboard) SetBreadcrumb(items ...string) {
 	d.breadcrumb.SetItems(items...)
 }
 
+// SetHighlightChanges turns highlighting of changes between refreshes on
+// or off.
+func (d *Dashboard) SetHighlightChanges(enabled bool) {
+	d.events.SetHighlightChanges(enabled)
+}
+
+// NextChangeExpiry returns how long until the next highlight expires.
+func (d Dashboard) NextChangeExpiry() (time.Duration, bool) {
+	return d.events.NextChangeExpiry()
+}
+
+// RefreshAges re-renders the event ages, which count up between fetches.
+func (d *Dashboard) RefreshAges() {
+	d.events.RefreshAges()
+}
+
+// RefreshHighlights re-renders panels so expired highlights disappear.
+func (d *Dashboard) RefreshHighlights() {
+	d.events.RefreshHighlights()
+}
+
+// BackingOff reports whether a container of the pod is in CrashLoopBackOff
+// with a restart estimate to count down.
+func (d Dashboard) BackingOff() bool {
+	return len(nextRestarts(d.pod)) > 0
+}
+
+// RefreshRestartCountdown re-renders the restart countdown in Pod Details;
+// the logs panel header computes its own on every render.
+func (d *Dashboard) RefreshRestartCountdown() {
+	d.manifest.RefreshCountdown()
+}
+
+// nextRestarts returns the estimated restart of each container of the pod
+// in CrashLoopBackOff, by container name.
+func nextRestarts(pod *repository.PodInfo) map[string]time.Time {
+	if pod == nil {
+		return nil
+	}
+	restarts := make(map[string]time.Time)
+	for _, containers := range [][]repository.ContainerInfo{pod.InitContainers, pod.Containers} {
+		for _, c := range containers {
+			if at, ok := repository.NextRestart(c); ok {
+				restarts[c.Name] = at
+			}
+		}
+	}
+	return restarts
+}
+
+// SetProtected marks the pod's namespace as protected with a breadcrumb badge.
+func (d *Dashboard) SetProtected(protected bool) {
+	if protected {
+		d.breadcrumb.SetBadge("protected")
+	} else {
+		d.breadcrumb.SetBadge("")
+	}
+}
+
+// SelectContainer selects the container shown in the logs panel. Returns
+// false if the pod has no such container.
+func (d *Dashboard) SelectContainer(name string) bool {
+	return d.logs.SelectContainer(name)
+}
+
+// FocusView focuses the panel for a deep-link view name (logs, events,
+// metrics or details). Unknown names leave the focus unchanged.
+func (d *Dashboard) FocusView(name string) {
+	for focus, view := range focusViews {
+		if view == name {
+			d.focus = focus
+			return
+		}
+	}
+}
+
+// focusViews maps panels to their deep-link view names.
+var focusViews = map[PanelFocus]string{
+	FocusLogs:     deeplink.ViewLogs,
+	FocusEvents:   deeplink.ViewEvents,
+	FocusMetrics:  deeplink.ViewMetrics,
+	FocusManifest: deeplink.ViewDetails,
+}
+
+// ShareLink returns a k1s:// link to the current pod, container and panel.
+func (d Dashboard) ShareLink() string {
+	if d.pod == nil {
+		return ""
+	}
+	return deeplink.Link{
+		Context:   d.context,
+		Namespace: d.pod.Namespace,
+		Kind:      deeplink.KindPod,
+		Name:      d.pod.Name,
+		Container: d.logs.SelectedContainer(),
+		View:      focusViews[d.focus],
+	}.String()
+}
+
 func (d *Dashboard) SetContext(ctx string) {
 	d.context = ctx
 }
@@ -806,21 +1851,240 @@ func (d Dashboard) Focus() PanelFocus {
 	return d.focus
 }
 
-func (d Dashboard) HelpVisible() bool {
-	return d.help.IsVisible()
+func (d Dashboard) HelpVisible() bool {
+	return d.help.IsVisible()
+}
+
+func (d Dashboard) ShortHelp() string {
+	return d.help.ShortHelp()
+}
+
+// Logs panel state getters for app to react to
+func (d Dashboard) LogsSelectedContainer() string {
+	return d.logs.SelectedContainer()
+}
+
+func (d Dashboard) LogsShowPrevious() bool {
+	return d.logs.ShowPrevious()
+}
+
+// LogsFrozen reports whether the logs panel is shown fullscreen, where
+// its text is copied from; refreshes then leave it as it is.
+func (d Dashboard) LogsFrozen() bool {
+	return d.fullscreen && d.focus == FocusLogs
+}
+
+// EventsFrozen reports whether the events panel is shown fullscreen; see
+// LogsFrozen.
+func (d Dashboard) EventsFrozen() bool {
+	return d.fullscreen && d.focus == FocusEvents
+}
+
+// LogsComparing reports whether the logs panel compares previous and current logs.
+func (d Dashboard) LogsComparing() bool {
+	return d.logs.Comparing()
+}
+
+// LogsWorkloadMode reports whether the logs panel merges the logs of every
+// pod of the workload.
+func (d Dashboard) LogsWorkloadMode() bool {
+	return d.logs.WorkloadMode()
+}
+
+// SetLogsWorkload sets the workload whose pods' logs the logs panel can
+// merge; "" when the pod has none (see component.LogsPanel.SetWorkload).
+func (d *Dashboard) SetLogsWorkload(name string) {
+	d.logs.SetWorkload(name)
+}
+
+// LogsFollowing reports whether the logs panel is in follow mode.
+func (d Dashboard) LogsFollowing() bool {
+	return d.logs.IsFollowing()
+}
+
+// LogsTimeFilter returns the logs panel time filter.
+func (d Dashboard) LogsTimeFilter() component.TimeFilter {
+	return d.logs.TimeFilter()
+}
+
+// LogsTimeRange returns the window of time the logs panel shows, zero for
+// the latest logs.
+func (d Dashboard) LogsTimeRange() repository.LogTimeRange {
+	return d.logs.TimeRange()
+}
+
+// Restyle re-renders the panels that cache rendered content, after the
+// theme changed. The others render from the styles on every frame.
+func (d *Dashboard) Restyle() {
+	d.logs.Restyle()
+	d.events.Restyle()
+}
+
+// SetLogsTimeFilter sets the logs panel time filter.
+func (d *Dashboard) SetLogsTimeFilter(f component.TimeFilter) {
+	d.logs.SetTimeFilter(f)
+}
+
+// SetLogBufferLines sets how many lines the logs panel keeps.
+func (d *Dashboard) SetLogBufferLines(n int) {
+	d.logs.SetBufferSize(n)
+}
+
+// EventsWarningsOnly reports whether the events panel shows only warnings.
+func (d Dashboard) EventsWarningsOnly() bool {
+	return d.events.WarningsOnly()
+}
+
+// SetEventsWarningsOnly sets whether the events panel shows only warnings.
+func (d *Dashboard) SetEventsWarningsOnly(enabled bool) {
+	d.events.SetWarningsOnly(enabled)
+}
+
+// SetConfirmLevelFunc sets how the dashboard resolves the confirmation
+// level of its mutating actions (see configs.Config.ConfirmLevelIn).
+func (d *Dashboard) SetConfirmLevelFunc(fn func(namespace, action string) configs.ConfirmLevel) {
+	d.confirmLevel = fn
+}
+
+// SetReadOnly disables the mutating pod actions: they are shown greyed out
+// in the action menu and refused if requested anyway.
+func (d *Dashboard) SetReadOnly(readOnly bool) {
+	d.podActionMenu.SetReadOnly(readOnly)
+	d.confirmDialog.SetReadOnly(readOnly)
+}
+
+// confirmLevelFor returns the confirmation level for an action on the
+// current pod, asking for a plain yes/no when no resolver is set.
+func (d Dashboard) confirmLevelFor(action string) configs.ConfirmLevel {
+	if d.confirmLevel == nil || d.pod == nil {
+		return configs.ConfirmYesNo
+	}
+	return d.confirmLevel(d.pod.Namespace, action)
+}
+
+// showDetailedResources opens the Resource Details view, in which related
+// Services, Ingresses and ConfigMaps can be selected and described.
+func (d *Dashboard) showDetailedResources() {
+	content, links := d.detailedResources()
+	d.resultViewer.ShowWithLinks("Resource Details: "+d.pod.Name, content, links, d.width-4, d.height-4)
+}
+
+// refreshDetailedResources re-renders Resource Details in place when it is
+// open, keeping the scroll position and the selected resource.
+func (d *Dashboard) refreshDetailedResources() {
+	if d.pod == nil || !d.resultViewer.IsVisible() || d.resultViewer.Title() != "Resource Details: "+d.pod.Name {
+		return
+	}
+	content, links := d.detailedResources()
+	d.resultViewer.SetContentWithLinks(content, links)
+}
+
+// checkContainer returns the container service checks run in: the one
+// the logs panel shows, or the pod's first container.
+func (d Dashboard) checkContainer() string {
+	if c := d.logs.SelectedContainer(); c != "" {
+		return c
+	}
+	if d.pod != nil && len(d.pod.Containers) > 0 {
+		return d.pod.Containers[0].Name
+	}
+	return ""
+}
+
+// probeRunRequest returns the request running the probe named by target,
+// "container/Probe" as in the pod actions. A running netshoot container is
+// passed along, for images without the tools to run the probe with.
+func (d *Dashboard) probeRunRequest(target string) (ProbeRunRequest, bool) {
+	if d.pod == nil {
+		return ProbeRunRequest{}, false
+	}
+	container, probe, _ := strings.Cut(target, "/")
+	for _, c := range d.pod.Containers {
+		if c.Name != container {
+			continue
+		}
+		config := map[string]*repository.ProbeInfo{
+			"Startup":   c.StartupProbe,
+			"Liveness":  c.LivenessProbe,
+			"Readiness": c.ReadinessProbe,
+		}[probe]
+		if config == nil {
+			return ProbeRunRequest{}, false
+		}
+		req := ProbeRunRequest{
+			Namespace: d.pod.Namespace,
+			PodName:   d.pod.Name,
+			Container: container,
+			Probe:     probe,
+			Config:    config,
+			PodIP:     d.pod.IP,
+			Ports:     c.Ports,
+		}
+		for _, e := range d.pod.EphemeralContainers {
+			if e.State == "Running" && (e.Image == repository.NetshootImage || strings.HasPrefix(e.Name, repository.NetshootContainerPrefix)) {
+				req.Netshoot = e.Name
+				break
+			}
+		}
+		return req, true
+	}
+	return ProbeRunRequest{}, false
+}
+
+// startServiceCheck marks a Service as being checked and sends req to
+// app.go.
+func (d *Dashboard) startServiceCheck(req ServiceCheckRequest) tea.Cmd {
+	if d.checking == nil {
+		d.checking = make(map[string]bool)
+	}
+	d.checking[req.Service] = true
+	d.statusMsg = "Testing service " + req.Service + "..."
+	d.refreshDetailedResources()
+	return func() tea.Msg {
+		return req
+	}
 }
 
-func (d Dashboard) ShortHelp() string {
-	return d.help.ShortHelp()
-}
+// startIngressTrace marks a related Ingress as being traced and sends an
+// IngressTraceRequest for it, with the VirtualServices serving the same
+// hosts, to app.go.
+func (d *Dashboard) startIngressTrace(name string) tea.Cmd {
+	if d.related == nil || d.tracing[name] {
+		return nil
+	}
+	req := IngressTraceRequest{Namespace: d.pod.Namespace, PodName: d.pod.Name}
+	found := false
+	for _, ing := range d.related.Ingresses {
+		if ing.Name == name {
+			req.Ingress, found = ing, true
+		}
+	}
+	if !found {
+		return nil
+	}
+	for _, vs := range d.related.VirtualServices {
+		if repository.HostsOverlap(vs.Hosts, req.Ingress.Hosts) {
+			req.VirtualServices = append(req.VirtualServices, vs)
+		}
+	}
 
-// Logs panel state getters for app to react to
-func (d Dashboard) LogsSelectedContainer() string {
-	return d.logs.SelectedContainer()
+	if d.tracing == nil {
+		d.tracing = make(map[string]bool)
+	}
+	d.tracing[name] = true
+	d.statusMsg = "Tracing ingress " + name + "..."
+	d.refreshDetailedResources()
+	return func() tea.Msg {
+		return req
+	}
 }
 
-func (d Dashboard) LogsShowPrevious() bool {
-	return d.logs.ShowPrevious()
+// WatchedPVC returns the PVC whose details are open, or "" when none is shown
+func (d Dashboard) WatchedPVC() string {
+	if !d.resultViewer.IsVisible() {
+		return ""
+	}
+	return d.watchedPVC
 }
 
 func (d *Dashboard) GetPod() *repository.PodInfo {
@@ -833,6 +2097,7 @@ func (d Dashboard) IsLogsSearching() bool {
 
 func (d Dashboard) HasActiveOverlay() bool {
 	return d.resultViewer.IsVisible() ||
+		d.projection.IsVisible() ||
 		d.confirmDialog.IsVisible() ||
 		d.podActionMenu.IsVisible() ||
 		d.actionMenu.IsVisible() ||
@@ -862,12 +2127,105 @@ func (d *Dashboard) CloseFullscreen() {
 	d.events.ClearSearch()
 }
 
+// renderServiceCheck renders the result of the last check of a Service,
+// with a line per port, or nothing when it was never checked.
+func (d Dashboard) renderServiceCheck(service string) string {
+	if d.checking[service] {
+		return fmt.Sprintf("    Check:      %s\n", style.StatusPending.Render("testing..."))
+	}
+	result, ok := d.serviceChecks[service]
+	if !ok {
+		return ""
+	}
+	if result.Err != nil {
+		return fmt.Sprintf("    Check:      %s\n", style.StatusError.Render("✗ "+result.Err.Error()))
+	}
+
+	var b strings.Builder
+	check := result.Check
+	from := style.StatusMuted.Render("(from " + check.Container + ")")
+	if check.DNSError != "" {
+		b.WriteString(fmt.Sprintf("    Check:      %s %s\n", style.StatusError.Render("✗ "+check.Host+": "+check.DNSError), from))
+	} else {
+		lookup := check.Host + " → " + strings.Join(check.Addresses, ", ")
+		status := style.StatusRunning.Render("✓ " + lookup)
+		if !check.Reachable() {
+			status = style.EventWarning.Render("! " + lookup)
+		}
+		b.WriteString(fmt.Sprintf("    Check:      %s %s\n", status, from))
+	}
+	for _, p := range check.Ports {
+		port := fmt.Sprintf("%d/%s %s", p.Port, p.Protocol, p.Name)
+		var state string
+		switch {
+		case p.Skipped:
+			state = style.StatusMuted.Render("- " + p.Error)
+		case p.Reachable && p.Latency > 0:
+			state = style.StatusRunning.Render("✓ " + p.Latency.Round(100*time.Microsecond).String())
+		case p.Reachable:
+			state = style.StatusRunning.Render("✓ open")
+		default:
+			state = style.StatusError.Render("✗ " + p.Error)
+		}
+		b.WriteString(fmt.Sprintf("      %-20s %s\n", port, state))
+	}
+	return b.String()
+}
+
+// renderIngressTrace renders the routes traced for an Ingress, each hop
+// marked green when traffic gets through it and red where it stops.
+func (d Dashboard) renderIngressTrace(ingress string) string {
+	if d.tracing[ingress] {
+		return fmt.Sprintf("    Trace:      %s\n", style.StatusPending.Render("tracing..."))
+	}
+	result, ok := d.ingressTraces[ingress]
+	if !ok {
+		return ""
+	}
+	if result.Err != nil {
+		return fmt.Sprintf("    Trace:      %s\n", style.StatusError.Render("✗ "+result.Err.Error()))
+	}
+
+	var b strings.Builder
+	for _, trace := range result.Traces {
+		route := trace.Route
+		if !strings.HasPrefix(trace.Source, "Ingress/") {
+			route = trace.Source + " " + route
+		}
+		b.WriteString(fmt.Sprintf("    Trace:      %s\n", route))
+		for _, hop := range trace.Hops {
+			detail := hop.Detail
+			if hop.Name != "" {
+				detail = hop.Name + ": " + detail
+			}
+			line := fmt.Sprintf("%-10s %s", hop.Kind, detail)
+			if hop.OK {
+				b.WriteString("      " + style.StatusRunning.Render("✓ "+line) + "\n")
+			} else {
+				b.WriteString("      " + style.StatusError.Render("✗ "+line) + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
 func (d Dashboard) renderDetailedResources() string {
+	content, _ := d.detailedResources()
+	return content
+}
+
+// detailedResources renders the Resource Details view and returns the
+// Service, Ingress and ConfigMap lines that can be opened with describe.
+func (d Dashboard) detailedResources() (string, []component.ResultLink) {
 	if d.pod == nil {
-		return "No pod selected"
+		return "No pod selected", nil
 	}
 
 	var b strings.Builder
+	var links []component.ResultLink
+	link := func(kind, name string) {
+		links = append(links, component.ResultLink{Line: strings.Count(b.String(), "\n"), Kind: kind, Name: name})
+	}
 
 	// Pod-level info
 	b.WriteString(style.SubtitleStyle.Render("Pod Info"))
@@ -885,6 +2243,8 @@ func (d Dashboard) renderDetailedResources() string {
 	}
 	b.WriteString("\n")
 
+	b.WriteString(d.renderPodSecurity())
+
 	// Network info
 	b.WriteString(style.SubtitleStyle.Render("Network"))
 	b.WriteString("\n")
@@ -894,12 +2254,27 @@ func (d Dashboard) renderDetailedResources() string {
 	if d.pod.StartTime != "" {
 		b.WriteString(fmt.Sprintf("  %-22s %s\n", "Started:", d.pod.StartTime))
 	}
+	// A default-deny policy with no allow rule for the pod cuts it off
+	if d.related != nil {
+		var denied []string
+		if d.related.NetworkPolicies.IngressDenied {
+			denied = append(denied, "ingress")
+		}
+		if d.related.NetworkPolicies.EgressDenied {
+			denied = append(denied, "egress")
+		}
+		if len(denied) > 0 {
+			b.WriteString(fmt.Sprintf("  %-22s %s\n", "Network Policy:",
+				style.StatusError.Render("all "+strings.Join(denied, " and ")+" denied (default deny, no allow rule)")))
+		}
+	}
 	b.WriteString("\n")
 
 	// Services (right after Network)
 	if d.related != nil && len(d.related.Services) > 0 {
 		b.WriteString(style.SubtitleStyle.Render("Services"))
 		b.WriteString("\n")
+		explained := false // Why the pod is not ready is the same for every Service
 		for _, svc := range d.related.Services {
 			typeStyle := style.StatusMuted
 			if svc.Type == "LoadBalancer" {
@@ -907,11 +2282,26 @@ func (d Dashboard) renderDetailedResources() string {
 			} else if svc.Type == "NodePort" {
 				typeStyle = style.LogContainer
 			}
+			link("Service", svc.Name)
 			b.WriteString(fmt.Sprintf("  • %s\n", style.LogContainer.Render(svc.Name)))
 			b.WriteString(fmt.Sprintf("    Type:       %s\n", typeStyle.Render(svc.Type)))
 			b.WriteString(fmt.Sprintf("    ClusterIP:  %s\n", svc.ClusterIP))
 			b.WriteString(fmt.Sprintf("    Ports:      %s\n", svc.Ports))
 			b.WriteString(fmt.Sprintf("    Endpoints:  %d\n", svc.Endpoints))
+			switch svc.PodEndpoint {
+			case "":
+			case repository.PodEndpointServing:
+				b.WriteString(fmt.Sprintf("    This pod:   %s\n", style.StatusRunning.Render(string(svc.PodEndpoint))))
+			default:
+				b.WriteString(fmt.Sprintf("    This pod:   %s\n", style.StatusError.Render(string(svc.PodEndpoint))))
+				if !explained {
+					explained = true
+					for _, reason := range repository.NotReadyReasons(d.pod) {
+						b.WriteString(fmt.Sprintf("      %s\n", style.StatusMuted.Render(reason)))
+					}
+				}
+			}
+			b.WriteString(d.renderServiceCheck(svc.Name))
 		}
 		b.WriteString("\n")
 	}
@@ -926,6 +2316,7 @@ func (d Dashboard) renderDetailedResources() string {
 			if ing.Class != "" {
 				classInfo = fmt.Sprintf(" (%s)", ing.Class)
 			}
+			link("Ingress", ing.Name)
 			b.WriteString(fmt.Sprintf("  • %s%s\n", style.LogContainer.Render(ing.Name), style.StatusMuted.Render(classInfo)))
 
 			// TLS info
@@ -975,6 +2366,7 @@ func (d Dashboard) renderDetailedResources() string {
 					b.WriteString(fmt.Sprintf("      %s: %s\n", style.StatusMuted.Render(shortKey), v))
 				}
 			}
+			b.WriteString(d.renderIngressTrace(ing.Name))
 		}
 		b.WriteString("\n")
 	}
@@ -1034,6 +2426,128 @@ func (d Dashboard) renderDetailedResources() string {
 		b.WriteString("\n")
 	}
 
+	// DestinationRules (Istio) - traffic policy for the pod's Services
+	if d.related != nil && len(d.related.DestinationRules) > 0 {
+		b.WriteString(style.SubtitleStyle.Render("DestinationRules (Istio)"))
+		b.WriteString("\n")
+		for _, dr := range d.related.DestinationRules {
+			b.WriteString(fmt.Sprintf("  • %s\n", style.LogContainer.Render(dr.Name)))
+			b.WriteString(fmt.Sprintf("    Host:      %s\n", dr.Host))
+			if dr.TLSMode != "" {
+				tlsStyle := style.StatusRunning
+				if dr.TLSMode == "DISABLE" {
+					tlsStyle = style.StatusPending
+				}
+				b.WriteString(fmt.Sprintf("    TLS:       %s\n", tlsStyle.Render(dr.TLSMode)))
+			}
+			if dr.LoadBalancer != "" {
+				b.WriteString(fmt.Sprintf("    LB:        %s\n", dr.LoadBalancer))
+			}
+			if dr.OutlierDetection != "" {
+				b.WriteString(fmt.Sprintf("    Outliers:  %s\n", dr.OutlierDetection))
+			}
+			for _, subset := range dr.Subsets {
+				var labels []string
+				for k, v := range subset.Labels {
+					labels = append(labels, k+"="+v)
+				}
+				sort.Strings(labels)
+				subsetInfo := fmt.Sprintf("%s (%s)", subset.Name, strings.Join(labels, ", "))
+				if subset.TLSMode != "" {
+					subsetInfo += fmt.Sprintf(" [TLS: %s]", subset.TLSMode)
+				}
+				if subset.MatchesPod {
+					subsetInfo += " " + style.StatusRunning.Render("← this pod")
+				}
+				b.WriteString(fmt.Sprintf("    Subset:    %s\n", subsetInfo))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	// Istio sidecar of the pod, flagged when injection should have added one
+	if d.related != nil && d.related.Sidecar != nil {
+		sidecar := d.related.Sidecar
+		b.WriteString(style.SubtitleStyle.Render("Sidecar (Istio)"))
+		b.WriteString("\n")
+		injection := "disabled"
+		if sidecar.InjectionEnabled {
+			injection = "enabled"
+		}
+		switch {
+		case sidecar.Missing():
+			b.WriteString(fmt.Sprintf("  %s\n", style.StatusError.Render("Missing: injection is enabled on the namespace but the pod has no istio-proxy")))
+			b.WriteString(fmt.Sprintf("  %s\n", style.StatusMuted.Render("Restart the pod to have the sidecar injected")))
+		case !sidecar.Present:
+			b.WriteString(fmt.Sprintf("  istio-proxy: %s\n", style.StatusMuted.Render("not injected (sidecar.istio.io/inject=false)")))
+		default:
+			readiness := style.StatusRunning.Render("ready")
+			if !sidecar.Ready {
+				readiness = style.StatusError.Render("not ready")
+			}
+			version := sidecar.Version
+			if version == "" {
+				version = "unknown version"
+			}
+			b.WriteString(fmt.Sprintf("  istio-proxy: %s, %s\n", readiness, version))
+		}
+		b.WriteString(fmt.Sprintf("  Injection:   %s on namespace %s\n", injection, d.pod.Namespace))
+		b.WriteString("\n")
+	}
+
+	// NetworkPolicies selecting the pod
+	if d.related != nil && len(d.related.NetworkPolicies.Policies) > 0 {
+		b.WriteString(style.SubtitleStyle.Render("Network Policies"))
+		b.WriteString("\n")
+		for _, np := range d.related.NetworkPolicies.Policies {
+			b.WriteString(fmt.Sprintf("  • %s %s\n", style.LogContainer.Render(np.Name),
+				style.StatusMuted.Render("["+strings.Join(np.PolicyTypes, ", ")+"]")))
+			b.WriteString(fmt.Sprintf("    Rules:     %d ingress, %d egress\n", np.IngressRules, np.EgressRules))
+			if np.DefaultDeny {
+				b.WriteString(fmt.Sprintf("    %s\n", style.StatusPending.Render("Default deny")))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	// PodDisruptionBudget selecting the pod; none allowed explains stuck drains
+	if d.related != nil && d.related.PDB != nil {
+		pdb := d.related.PDB
+		b.WriteString(style.SubtitleStyle.Render("Disruption Budget"))
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  • %s\n", style.LogContainer.Render(pdb.Name)))
+		if pdb.MinAvailable != "" {
+			b.WriteString(fmt.Sprintf("    Min Available:   %s\n", pdb.MinAvailable))
+		}
+		if pdb.MaxUnavailable != "" {
+			b.WriteString(fmt.Sprintf("    Max Unavailable: %s\n", pdb.MaxUnavailable))
+		}
+		b.WriteString(fmt.Sprintf("    Healthy:         %d/%d (%d expected)\n", pdb.CurrentHealthy, pdb.DesiredHealthy, pdb.ExpectedPods))
+		allowed := fmt.Sprintf("%d", pdb.DisruptionsAllowed)
+		if pdb.Blocking() {
+			allowed = style.StatusError.Render(allowed + " (evictions and drains are blocked)")
+		}
+		b.WriteString(fmt.Sprintf("    Disruptions:     %s\n", allowed))
+		b.WriteString("\n")
+	}
+
+	// Permissions of the ServiceAccount
+	if d.related != nil && d.related.RBAC != nil {
+		b.WriteString(style.SubtitleStyle.Render("Permissions"))
+		b.WriteString("\n")
+		b.WriteString(renderPermissions(d.related.RBAC, d.pod.Volumes))
+		b.WriteString(renderAccessChecks(d.accessChecks, d.accessErr))
+		b.WriteString("\n")
+	}
+
+	// Admission webhooks, the source of "denied the request" errors
+	if d.related != nil && len(d.related.Webhooks) > 0 {
+		b.WriteString(style.SubtitleStyle.Render("Webhooks"))
+		b.WriteString("\n")
+		b.WriteString(renderWebhooks(d.related.Webhooks, d.webhookDenials()))
+		b.WriteString("\n")
+	}
+
 	// Node Selector
 	if len(d.pod.NodeSelector) > 0 {
 		b.WriteString(style.SubtitleStyle.Render("Node Selector"))
@@ -1044,6 +2558,17 @@ func (d Dashboard) renderDetailedResources() string {
 		b.WriteString("\n")
 	}
 
+	// Scheduling Gates
+	if len(d.pod.SchedulingGates) > 0 {
+		b.WriteString(style.SubtitleStyle.Render("Scheduling Gates"))
+		b.WriteString("\n")
+		for _, gate := range d.pod.SchedulingGates {
+			b.WriteString(fmt.Sprintf("  • %s\n", style.StatusPending.Render(gate)))
+		}
+		b.WriteString(style.StatusMuted.Render("  Pod stays Pending until a controller removes these"))
+		b.WriteString("\n\n")
+	}
+
 	// Tolerations
 	if len(d.pod.Tolerations) > 0 {
 		b.WriteString(style.SubtitleStyle.Render("Tolerations"))
@@ -1077,6 +2602,28 @@ func (d Dashboard) renderDetailedResources() string {
 			}
 			b.WriteString(fmt.Sprintf("  • %s: %s\n", c.Name, stateStyle.Render(state)))
 			b.WriteString(fmt.Sprintf("    Image: %s\n", c.Image))
+			if pull := d.pullProgressFor(c.Name); pull != nil {
+				b.WriteString(fmt.Sprintf("    Pull: %s\n", pull.Summary(time.Now())))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	// Ephemeral (debug) containers
+	if len(d.pod.EphemeralContainers) > 0 {
+		b.WriteString(style.SubtitleStyle.Render("Ephemeral Containers"))
+		b.WriteString("\n")
+		for _, c := range d.pod.EphemeralContainers {
+			stateStyle := style.GetStatusStyle(c.State)
+			state := c.State
+			if c.Reason != "" {
+				state += " (" + c.Reason + ")"
+			}
+			b.WriteString(fmt.Sprintf("  • %s: %s\n", c.Name, stateStyle.Render(state)))
+			b.WriteString(fmt.Sprintf("    Image: %s\n", c.Image))
+			if c.TargetContainer != "" {
+				b.WriteString(fmt.Sprintf("    Target: %s\n", c.TargetContainer))
+			}
 		}
 		b.WriteString("\n")
 	}
@@ -1089,6 +2636,13 @@ func (d Dashboard) renderDetailedResources() string {
 		b.WriteString(fmt.Sprintf("  %-20s %s\n", "Pull Policy:", c.ImagePullPolicy))
 		stateStyle := style.GetStatusStyle(c.State)
 		b.WriteString(fmt.Sprintf("  %-20s %s\n", "State:", stateStyle.Render(c.State)))
+		if pull := d.pullProgressFor(c.Name); pull != nil {
+			b.WriteString(fmt.Sprintf("  %-20s %s\n", "Image Pull:", pull.Summary(time.Now())))
+		}
+		if pull := d.imagePullFor(c.Name); pull != nil {
+			b.WriteString(fmt.Sprintf("  %-20s %s\n", "Pull Error:", style.StatusError.Render(string(pull.Failure))))
+			b.WriteString(fmt.Sprintf("  %-20s %s\n", "Hint:", pull.Hint))
+		}
 		if c.StartedAt != "" {
 			b.WriteString(fmt.Sprintf("  %-20s %s\n", "Started:", c.StartedAt))
 		}
@@ -1106,6 +2660,14 @@ func (d Dashboard) renderDetailedResources() string {
 		b.WriteString(fmt.Sprintf("    %-18s %s\n", "CPU Limit:", formatResource(c.Resources.CPULimit)))
 		b.WriteString(fmt.Sprintf("    %-18s %s\n", "Mem Request:", formatResource(c.Resources.MemoryRequest)))
 		b.WriteString(fmt.Sprintf("    %-18s %s\n", "Mem Limit:", formatResource(c.Resources.MemoryLimit)))
+		for _, v := range repository.ValidateContainerResources(c.Resources, d.limitRanges) {
+			b.WriteString(style.StatusError.Render(fmt.Sprintf("    ✗ %s (%s)", v.Message, v.LimitRange)))
+			b.WriteString("\n")
+		}
+		for _, def := range repository.LimitRangeDefaults(c.Resources, d.limitRanges) {
+			b.WriteString(style.StatusMuted.Render(fmt.Sprintf("    default %s %s: %s (%s)", def.Resource, def.Kind, def.Value, def.LimitRange)))
+			b.WriteString("\n")
+		}
 		b.WriteString("\n")
 
 		// Ports
@@ -1140,37 +2702,10 @@ func (d Dashboard) renderDetailedResources() string {
 		}
 		b.WriteString("\n")
 
-		// Security Context
+		// Security Context, merged with the pod's
 		b.WriteString(style.SubtitleStyle.Render("  Security Context"))
 		b.WriteString("\n")
-		if c.SecurityContext != nil {
-			hasContent := false
-			if c.SecurityContext.RunAsUser != nil {
-				b.WriteString(fmt.Sprintf("    %-18s %d\n", "Run As User:", *c.SecurityContext.RunAsUser))
-				hasContent = true
-			}
-			if c.SecurityContext.RunAsGroup != nil {
-				b.WriteString(fmt.Sprintf("    %-18s %d\n", "Run As Group:", *c.SecurityContext.RunAsGroup))
-				hasContent = true
-			}
-			if c.SecurityContext.RunAsNonRoot != nil && *c.SecurityContext.RunAsNonRoot {
-				b.WriteString(fmt.Sprintf("    %-18s %s\n", "Run As Non-Root:", style.StatusRunning.Render("yes")))
-				hasContent = true
-			}
-			if c.SecurityContext.Privileged != nil && *c.SecurityContext.Privileged {
-				b.WriteString(fmt.Sprintf("    %-18s %s\n", "Privileged:", style.StatusError.Render("YES")))
-				hasContent = true
-			}
-			if c.SecurityContext.ReadOnlyRoot != nil && *c.SecurityContext.ReadOnlyRoot {
-				b.WriteString(fmt.Sprintf("    %-18s %s\n", "Read-Only Root:", style.StatusRunning.Render("yes")))
-				hasContent = true
-			}
-			if !hasContent {
-				b.WriteString("    (default)\n")
-			}
-		} else {
-			b.WriteString("    (default)\n")
-		}
+		b.WriteString(renderContainerSecurity(d.containerSecurity(c)))
 		b.WriteString("\n")
 
 		// Volume Mounts
@@ -1202,6 +2737,40 @@ func (d Dashboard) renderDetailedResources() string {
 		b.WriteString("\n")
 	}
 
+	// PersistentVolumeClaims
+	if d.related != nil && len(d.related.PVCs) > 0 {
+		b.WriteString(style.SubtitleStyle.Render("Persistent Volume Claims"))
+		b.WriteString("\n")
+		blocking := component.BlockingPVCs(d.pod, d.related)
+		for _, pvc := range d.related.PVCs {
+			b.WriteString(fmt.Sprintf("  • %s %s\n", style.LogContainer.Render(pvc.Name), component.PVCPhase(pvc)))
+			if pvc.Missing {
+				b.WriteString(fmt.Sprintf("    %-14s %s\n", "Volume:", pvc.Volume))
+				b.WriteString(fmt.Sprintf("    %s\n", style.StatusError.Render("claim not found")))
+			} else if pvc.Error != "" {
+				b.WriteString(fmt.Sprintf("    %-14s %s\n", "Volume:", pvc.Volume))
+				b.WriteString(fmt.Sprintf("    %s\n", style.StatusMuted.Render("claim could not be read: "+pvc.Error)))
+			} else {
+				storageClass := pvc.StorageClass
+				if storageClass == "" {
+					storageClass = style.StatusMuted.Render("<none>")
+				}
+				b.WriteString(fmt.Sprintf("    %-14s %s\n", "Storage Class:", storageClass))
+				b.WriteString(fmt.Sprintf("    %-14s %s\n", "Capacity:", pvc.Capacity))
+				b.WriteString(fmt.Sprintf("    %-14s %s\n", "Access Modes:", strings.Join(pvc.AccessModes, ", ")))
+				if pvc.VolumeName != "" {
+					b.WriteString(fmt.Sprintf("    %-14s %s\n", "Volume:", pvc.VolumeName))
+				}
+			}
+			for _, p := range blocking {
+				if p.Name == pvc.Name {
+					b.WriteString(fmt.Sprintf("    %s\n", style.EventWarning.Render("⚠ Likely why the pod is Pending")))
+				}
+			}
+		}
+		b.WriteString("\n")
+	}
+
 	// Related ConfigMaps and Secrets
 	if d.related != nil {
 		// ConfigMaps used
@@ -1209,6 +2778,7 @@ func (d Dashboard) renderDetailedResources() string {
 			b.WriteString(style.SubtitleStyle.Render("ConfigMaps Used"))
 			b.WriteString("\n")
 			for _, cm := range d.related.ConfigMaps {
+				link("ConfigMap", cm)
 				b.WriteString(fmt.Sprintf("  • %s\n", cm))
 			}
 			b.WriteString("\n")
@@ -1219,11 +2789,309 @@ func (d Dashboard) renderDetailedResources() string {
 			b.WriteString(style.SubtitleStyle.Render("Secrets Used"))
 			b.WriteString("\n")
 			for _, s := range d.related.Secrets {
+				link("Secret", s)
 				b.WriteString(fmt.Sprintf("  • %s\n", s))
 			}
 		}
 	}
 
+	return b.String(), links
+}
+
+// renderDiagnosis renders the full reasoning behind a crash diagnosis.
+func renderDiagnosis(diagnosis *repository.CrashDiagnosis) string {
+	var b strings.Builder
+	b.WriteString(style.StatusError.Render(diagnosis.Summary))
+	b.WriteString("\n\n")
+	if diagnosis.Container != "" {
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", "Container:", diagnosis.Container))
+	}
+	b.WriteString(fmt.Sprintf("  %-12s %s\n\n", "Cause:", diagnosis.Cause))
+	for _, reason := range diagnosis.Reasoning {
+		b.WriteString(fmt.Sprintf("  • %s\n", reason))
+	}
+	return b.String()
+}
+
+// permissionRulesShown caps the rules listed under Permissions.
+const permissionRulesShown = 12
+
+// renderPermissions renders the ServiceAccount of a pod, how its token
+// reaches the pod and the rules bound to it.
+func renderPermissions(rbac *repository.PodRBAC, volumes []repository.VolumeInfo) string {
+	var b strings.Builder
+	account := rbac.ServiceAccount
+	if !rbac.Exists {
+		account += " " + style.StatusError.Render("(not found)")
+	}
+	b.WriteString(fmt.Sprintf("  ServiceAccount:  %s\n", account))
+
+	token := style.StatusMuted.Render("not mounted")
+	for _, v := range volumes {
+		if v.Type == "Projected" && v.Source == repository.ProjectedTokenSource {
+			token = "projected (" + v.Name + ")"
+			break
+		}
+	}
+	if !rbac.AutomountToken {
+		token += style.StatusMuted.Render(", automount disabled on the ServiceAccount")
+	}
+	b.WriteString(fmt.Sprintf("  Token:           %s\n", token))
+	if len(rbac.Secrets) > 0 {
+		b.WriteString(fmt.Sprintf("  Secrets:         %s\n", strings.Join(rbac.Secrets, ", ")))
+	}
+
+	if rbac.NoBindings() {
+		// The usual state of the default ServiceAccount
+		b.WriteString(style.EventWarning.Render(fmt.Sprintf("  No RoleBindings or ClusterRoleBindings reference %s: only what all authenticated users may do applies, other API calls get 403 Forbidden", rbac.ServiceAccount)))
+		b.WriteString("\n")
+	}
+	for _, binding := range rbac.Bindings {
+		b.WriteString(fmt.Sprintf("  • %s %s → %s %s\n", binding.Kind, style.LogContainer.Render(binding.Name), binding.RoleKind, binding.RoleName))
+	}
+	for i, rule := range rbac.Rules {
+		if i == permissionRulesShown {
+			b.WriteString(style.StatusMuted.Render(fmt.Sprintf("    ... %d more rules", len(rbac.Rules)-permissionRulesShown)))
+			b.WriteString("\n")
+			break
+		}
+		b.WriteString(fmt.Sprintf("    %s\n", rule.String()))
+	}
+	for _, warning := range rbac.Warnings {
+		b.WriteString(style.StatusMuted.Render("  ! " + warning))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// webhookDenials returns the messages of the pod's events and of the
+// ReplicaFailure conditions of its workload that report an admission
+// webhook denying a request, by webhook name.
+func (d Dashboard) webhookDenials() map[string]string {
+	denials := make(map[string]string)
+	var messages []string
+	for _, e := range d.events.Events() {
+		messages = append(messages, e.Message)
+	}
+	if d.related != nil && d.related.Owner != nil {
+		messages = append(messages, d.related.Owner.Failures...)
+	}
+	for _, message := range messages {
+		if name := repository.DeniedWebhook(message); name != "" {
+			denials[name] = message
+		}
+	}
+	return denials
+}
+
+// renderWebhooks renders the admission webhooks matching the pod, with
+// the one that denied a recent request highlighted along with its error.
+// A webhook that fails closed is flagged, as requests fail while it is
+// down.
+func renderWebhooks(webhooks []repository.WebhookInfo, denials map[string]string) string {
+	var b strings.Builder
+	for _, w := range webhooks {
+		name := style.LogContainer.Render(w.Name)
+		denial, denied := denials[w.Name]
+		if denied {
+			name = style.StatusError.Render("✗ " + w.Name + " (denied a recent request)")
+		}
+		b.WriteString(fmt.Sprintf("  • %s %s\n", name, style.StatusMuted.Render("["+w.Kind+" "+w.Configuration+"]")))
+		if rules := w.Rules(); rules != "" {
+			b.WriteString(fmt.Sprintf("    Rules:     %s\n", rules))
+		}
+		policy := w.FailurePolicy
+		if policy == "Fail" {
+			policy = style.StatusPending.Render(policy)
+		}
+		b.WriteString(fmt.Sprintf("    Policy:    %s, timeout %ds\n", policy, w.Timeout))
+		if w.Backend != "" {
+			b.WriteString(fmt.Sprintf("    Backend:   %s\n", w.Backend))
+		}
+		if denied {
+			b.WriteString(fmt.Sprintf("    %s\n", style.StatusError.Render(denial)))
+		}
+	}
+	return b.String()
+}
+
+// renderAccessChecks renders what the ServiceAccount may do, as answered
+// by the API server, with the reason of each denial.
+func renderAccessChecks(checks []repository.AccessCheck, err error) string {
+	var b strings.Builder
+	if len(checks) > 0 {
+		b.WriteString("  Access:\n")
+	}
+	for _, check := range checks {
+		if check.Allowed {
+			b.WriteString("    " + style.StatusRunning.Render("✓ "+check.String()) + "\n")
+			continue
+		}
+		line := style.StatusError.Render("✗ " + check.String())
+		if check.Reason != "" {
+			line += style.StatusMuted.Render(" (" + check.Reason + ")")
+		}
+		b.WriteString("    " + line + "\n")
+	}
+	if errors.Is(err, repository.ErrImpersonationForbidden) {
+		b.WriteString(style.StatusMuted.Render("  ! Access not checked: you may not impersonate the ServiceAccount"))
+		b.WriteString("\n")
+	} else if err != nil {
+		b.WriteString(style.StatusMuted.Render("  ! Access checks: " + err.Error()))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderPodSecurity renders the pod-level security settings and the Pod
+// Security Admission levels of the namespace, with the checks of the
+// enforced level the pod fails.
+func (d Dashboard) renderPodSecurity() string {
+	var b strings.Builder
+	b.WriteString(style.SubtitleStyle.Render("Pod Security"))
+	b.WriteString("\n")
+	if sc := d.pod.SecurityContext; sc != nil {
+		if sc.FSGroup != nil {
+			b.WriteString(fmt.Sprintf("  %-22s %d\n", "FS Group:", *sc.FSGroup))
+		}
+		if len(sc.SupplementalGroups) > 0 {
+			b.WriteString(fmt.Sprintf("  %-22s %s\n", "Supplemental Groups:", strings.Trim(fmt.Sprint(sc.SupplementalGroups), "[]")))
+		}
+	}
+	if len(d.pod.HostNamespaces) > 0 {
+		b.WriteString(fmt.Sprintf("  %-22s %s\n", "Host Namespaces:", style.StatusError.Render(strings.Join(d.pod.HostNamespaces, ", "))))
+	}
+	if d.security == nil {
+		b.WriteString(fmt.Sprintf("  %-22s %s\n\n", "Admission:", style.StatusMuted.Render("loading...")))
+		return b.String()
+	}
+
+	admission := d.security.Admission
+	level := func(l repository.PodSecurityLevel) string {
+		if l == "" {
+			return style.StatusMuted.Render("not set")
+		}
+		return string(l)
+	}
+	enforce := level(admission.Enforce)
+	if admission.Enforce == "" {
+		enforce = style.StatusMuted.Render("not set (privileged)")
+	} else if admission.EnforceVersion != "" {
+		enforce += style.StatusMuted.Render(" (" + admission.EnforceVersion + ")")
+	}
+	b.WriteString(fmt.Sprintf("  %-22s %s\n", "Enforce:", enforce))
+	b.WriteString(fmt.Sprintf("  %-22s %s\n", "Warn:", level(admission.Warn)))
+	b.WriteString(fmt.Sprintf("  %-22s %s\n", "Audit:", level(admission.Audit)))
+	switch {
+	case len(d.security.Violations) > 0:
+		b.WriteString(style.StatusError.Render(fmt.Sprintf("  ✗ Violates %s: a new pod like it would be rejected", admission.Enforce)))
+		b.WriteString("\n")
+		for _, v := range d.security.Violations {
+			b.WriteString("    • " + v.String() + "\n")
+		}
+	case admission.Enforce != "" && admission.Enforce != repository.PodSecurityPrivileged:
+		b.WriteString(style.StatusRunning.Render(fmt.Sprintf("  ✓ Meets %s", admission.Enforce)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// containerSecurity returns the effective security context of a container
+// of the pod, with the conflicts found by the last load.
+func (d Dashboard) containerSecurity(c repository.ContainerInfo) repository.ContainerSecurity {
+	if d.security != nil {
+		for _, s := range d.security.Containers {
+			if s.Container == c.Name && !s.Init {
+				return s
+			}
+		}
+	}
+	return repository.EffectiveSecurityContext(d.pod, c)
+}
+
+// renderContainerSecurity renders the effective security context of a
+// container, marking the settings inherited from the pod, followed by its
+// conflicts.
+func renderContainerSecurity(s repository.ContainerSecurity) string {
+	var b strings.Builder
+	inherited := func(setting string) string {
+		for _, f := range s.FromPod {
+			if f == setting {
+				return style.StatusMuted.Render(" (pod)")
+			}
+		}
+		return ""
+	}
+	line := func(label, value string) {
+		b.WriteString(fmt.Sprintf("    %-18s %s\n", label, value))
+	}
+
+	user := style.StatusMuted.Render("image default")
+	if s.RunAsUser != nil {
+		user = fmt.Sprintf("%d", *s.RunAsUser)
+		if *s.RunAsUser == 0 {
+			user = style.StatusError.Render("0 (root)")
+		}
+	}
+	line("Run As User:", user+inherited("runAsUser"))
+	group := style.StatusMuted.Render("image default")
+	if s.RunAsGroup != nil {
+		group = fmt.Sprintf("%d", *s.RunAsGroup)
+	}
+	line("Run As Group:", group+inherited("runAsGroup"))
+	nonRoot := style.StatusMuted.Render("no")
+	if s.RunAsNonRoot {
+		nonRoot = style.StatusRunning.Render("yes")
+	}
+	line("Run As Non-Root:", nonRoot+inherited("runAsNonRoot"))
+	if s.FSGroup != nil {
+		line("FS Group:", fmt.Sprintf("%d", *s.FSGroup)+style.StatusMuted.Render(" (pod)"))
+	}
+	if s.Privileged {
+		line("Privileged:", style.StatusError.Render("YES"))
+	}
+	escalation := style.StatusMuted.Render("allowed (default)")
+	if s.AllowPrivilegeEscalation != nil {
+		escalation = "allowed"
+		if !*s.AllowPrivilegeEscalation {
+			escalation = style.StatusRunning.Render("no")
+		}
+	}
+	line("Privilege Escal.:", escalation)
+	readOnly := style.StatusMuted.Render("no")
+	if s.ReadOnlyRoot {
+		readOnly = style.StatusRunning.Render("yes")
+	}
+	line("Read-Only Root:", readOnly)
+	var caps []string
+	if len(s.CapabilitiesDrop) > 0 {
+		caps = append(caps, "drop "+strings.Join(s.CapabilitiesDrop, ", "))
+	}
+	if len(s.CapabilitiesAdd) > 0 {
+		caps = append(caps, "add "+strings.Join(s.CapabilitiesAdd, ", "))
+	}
+	capabilities := style.StatusMuted.Render("runtime default")
+	if len(caps) > 0 {
+		capabilities = strings.Join(caps, "; ")
+	}
+	line("Capabilities:", capabilities)
+	seccomp := style.StatusMuted.Render("not set")
+	if s.SeccompProfile == "Unconfined" {
+		seccomp = style.StatusError.Render(s.SeccompProfile)
+	} else if s.SeccompProfile != "" {
+		seccomp = s.SeccompProfile
+	}
+	line("Seccomp:", seccomp+inherited("seccompProfile"))
+	appArmor := style.StatusMuted.Render("not set")
+	if s.AppArmorProfile != "" {
+		appArmor = s.AppArmorProfile
+	}
+	line("AppArmor:", appArmor)
+	for _, conflict := range s.Conflicts {
+		b.WriteString(style.StatusError.Render("    ✗ " + conflict))
+		b.WriteString("\n")
+	}
 	return b.String()
 }
 
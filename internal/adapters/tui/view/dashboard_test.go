@@ -1,10 +1,20 @@
 package view
 
 import (
+	"context"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"github.com/andrebassi/k1s/configs"
 	"github.com/andrebassi/k1s/internal/adapters/repository"
 	"github.com/andrebassi/k1s/internal/adapters/tui/component"
 )
@@ -62,6 +72,34 @@ func TestDashboard_SetPod(t *testing.T) {
 	}
 }
 
+func TestDashboard_SetPod_StuckInInit(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(100, 40)
+
+	zero := int32(0)
+	pod := &repository.PodInfo{
+		Name:      "web-abc123",
+		Namespace: "default",
+		Status:    "Init:CrashLoopBackOff",
+		InitContainers: []repository.ContainerInfo{
+			{Name: "wait-db", State: "Terminated", ExitCode: &zero},
+			{Name: "migrate", State: "Waiting", Reason: "CrashLoopBackOff"},
+		},
+		Containers: []repository.ContainerInfo{{Name: "app"}},
+	}
+	d.SetPod(pod)
+	if got := d.logs.SelectedContainer(); got != "migrate" {
+		t.Fatalf("SelectedContainer() = %q, want the failing init container", got)
+	}
+
+	// A refresh keeps whatever the user switched to
+	d.SelectContainer("app")
+	d.SetPod(pod)
+	if got := d.logs.SelectedContainer(); got != "app" {
+		t.Errorf("SelectedContainer() after refresh = %q, want app", got)
+	}
+}
+
 func TestDashboard_SetLogs(t *testing.T) {
 	d := NewDashboard()
 	d.SetSize(100, 40)
@@ -137,6 +175,474 @@ func TestDashboard_SetHelpers(t *testing.T) {
 	// Just verify it doesn't panic
 }
 
+func TestDashboard_SetImagePulls(t *testing.T) {
+	d := NewDashboard()
+	d.SetPod(&repository.PodInfo{
+		Name:      "web",
+		Namespace: "default",
+		Containers: []repository.ContainerInfo{
+			{Name: "app", Image: "ghcr.io/org/app:v1", State: "Waiting", Reason: "ImagePullBackOff"},
+			{Name: "sidecar", Image: "envoyproxy/envoy:v1.30", State: "Running"},
+		},
+	})
+	d.SetImagePulls([]repository.ImagePullDiagnosis{
+		{Container: "app", Failure: repository.PullUnauthorized, Hint: "No imagePullSecrets on the pod"},
+	})
+
+	if d.imagePullFor("app") == nil {
+		t.Fatal("imagePullFor(app) = nil, want diagnosis")
+	}
+	if d.imagePullFor("sidecar") != nil {
+		t.Error("imagePullFor(sidecar) should be nil")
+	}
+
+	out := d.renderDetailedResources()
+	if !strings.Contains(out, "Pull Error:") || !strings.Contains(out, "No imagePullSecrets on the pod") {
+		t.Error("container detail should show the pull failure and hint")
+	}
+}
+
+func TestDashboard_SetImagePullProgress(t *testing.T) {
+	d := NewDashboard()
+	d.SetPod(&repository.PodInfo{
+		Name:      "web",
+		Namespace: "default",
+		Containers: []repository.ContainerInfo{
+			{Name: "app", Image: "registry/app:tag", State: "Waiting", Reason: "ContainerCreating"},
+			{Name: "sidecar", Image: "envoyproxy/envoy:v1.30", State: "Running"},
+		},
+	})
+	d.SetImagePullProgress([]repository.ImagePullProgress{
+		{Container: "app", Image: "registry/app:tag", Phase: repository.PullPhasePulling, Since: time.Now().Add(-3 * time.Minute)},
+	})
+
+	if d.pullProgressFor("sidecar") != nil {
+		t.Error("pullProgressFor(sidecar) should be nil")
+	}
+	out := d.renderDetailedResources()
+	if !strings.Contains(out, "Image Pull:") || !strings.Contains(out, "pulling image registry/app:tag — 3m0s elapsed") {
+		t.Errorf("container detail should show the pull progress, got:\n%s", out)
+	}
+}
+
+func TestDashboard_RelatedPVCs(t *testing.T) {
+	d := NewDashboard()
+	d.SetPod(&repository.PodInfo{Name: "db-0", Namespace: "default", Status: "Pending"})
+	d.SetRelated(&repository.RelatedResources{PVCs: []repository.PVCInfo{
+		{Name: "data", Phase: "Bound", StorageClass: "gp3", Capacity: "10Gi", AccessModes: []string{"ReadWriteOnce"}, VolumeName: "pv-123"},
+		{Name: "wal", Phase: "Pending", StorageClass: "gp3", Capacity: "5Gi", AccessModes: []string{"ReadWriteOnce"}},
+		{Name: "backup", Volume: "backup", Missing: true},
+	}})
+
+	out := d.renderDetailedResources()
+	for _, want := range []string{"Persistent Volume Claims", "data", "pv-123", "wal", "[Pending]", "backup", "claim not found"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("PVC section should contain %q, got:\n%s", want, out)
+		}
+	}
+	// The pending and missing claims are flagged, the bound one is not
+	if n := strings.Count(out, "Likely why the pod is Pending"); n != 2 {
+		t.Errorf("flagged %d claims as the Pending cause, want 2", n)
+	}
+}
+
+func TestDashboard_NetworkPolicies(t *testing.T) {
+	d := NewDashboard()
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default", Status: "Running"})
+	d.SetRelated(&repository.RelatedResources{NetworkPolicies: repository.NetworkPolicyResult{
+		Policies: []repository.NetworkPolicyInfo{
+			{Name: "default-deny", PolicyTypes: []string{"Ingress", "Egress"}, DefaultDeny: true},
+			{Name: "allow-web", PolicyTypes: []string{"Ingress"}, IngressRules: 2},
+		},
+		EgressDenied: true,
+	}})
+
+	out := d.renderDetailedResources()
+	for _, want := range []string{"Network Policies", "default-deny", "[Ingress, Egress]", "Default deny", "allow-web", "2 ingress, 0 egress"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Network Policies section should contain %q, got:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(out, "all egress denied") || strings.Contains(out, "ingress denied") {
+		t.Errorf("Network section should flag only egress, got:\n%s", out)
+	}
+
+	d.SetRelated(&repository.RelatedResources{})
+	if out := d.renderDetailedResources(); strings.Contains(out, "Network Policies") || strings.Contains(out, "Network Policy:") {
+		t.Error("no policies, no section or flag")
+	}
+}
+
+func TestDashboard_IstioDestinationRulesAndSidecar(t *testing.T) {
+	d := NewDashboard()
+	d.SetPod(&repository.PodInfo{Name: "reviews-1", Namespace: "default", Status: "Running"})
+	d.SetRelated(&repository.RelatedResources{
+		DestinationRules: []repository.DestinationRuleInfo{{
+			Name: "reviews", Host: "reviews", TLSMode: "ISTIO_MUTUAL",
+			Subsets: []repository.DestinationRuleSubset{
+				{Name: "v1", Labels: map[string]string{"version": "v1"}, MatchesPod: true},
+				{Name: "v2", Labels: map[string]string{"version": "v2"}},
+			},
+		}},
+		Sidecar: &repository.SidecarStatus{Present: true, Ready: true, Version: "1.20.3", InjectionEnabled: true},
+	})
+
+	out := d.renderDetailedResources()
+	for _, want := range []string{"DestinationRules (Istio)", "ISTIO_MUTUAL", "v1 (version=v1)", "this pod", "Sidecar (Istio)", "ready, 1.20.3", "enabled on namespace default"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Istio sections should contain %q, got:\n%s", want, out)
+		}
+	}
+
+	d.SetRelated(&repository.RelatedResources{Sidecar: &repository.SidecarStatus{InjectionEnabled: true}})
+	if out := d.renderDetailedResources(); !strings.Contains(out, "Missing: injection is enabled") {
+		t.Errorf("a pod without sidecar in an injection-enabled namespace should be flagged, got:\n%s", out)
+	}
+}
+
+func TestDashboard_PDB(t *testing.T) {
+	d := NewDashboard()
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default", Status: "Running"})
+	d.SetRelated(&repository.RelatedResources{PDB: &repository.PDBInfo{
+		Name: "web-pdb", MinAvailable: "2", CurrentHealthy: 2, DesiredHealthy: 2, ExpectedPods: 2,
+	}})
+
+	out := d.renderDetailedResources()
+	for _, want := range []string{"Disruption Budget", "web-pdb", "Min Available:   2", "Healthy:         2/2 (2 expected)", "evictions and drains are blocked"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Disruption Budget section should contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Max Unavailable") {
+		t.Error("a minAvailable budget should not show Max Unavailable")
+	}
+
+	d.SetRelated(&repository.RelatedResources{PDB: &repository.PDBInfo{Name: "web-pdb", MaxUnavailable: "25%", DisruptionsAllowed: 1}})
+	out = d.renderDetailedResources()
+	if !strings.Contains(out, "Max Unavailable: 25%") || strings.Contains(out, "blocked") {
+		t.Errorf("a budget allowing disruptions should not be flagged, got:\n%s", out)
+	}
+}
+
+func TestDashboard_Permissions(t *testing.T) {
+	d := NewDashboard()
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "shop", Volumes: []repository.VolumeInfo{
+		{Name: "kube-api-access-5x2kq", Type: "Projected", Source: repository.ProjectedTokenSource},
+	}})
+	d.SetRelated(&repository.RelatedResources{RBAC: &repository.PodRBAC{
+		ServiceAccount: "web", Namespace: "shop", Exists: true, AutomountToken: true,
+		Bindings: []repository.RBACBinding{{Kind: "RoleBinding", Name: "web-pods", RoleKind: "Role", RoleName: "pod-reader"}},
+		Rules:    []repository.RBACRule{{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+	}})
+
+	out := d.renderDetailedResources()
+	for _, want := range []string{"Permissions", "ServiceAccount:  web", "projected (kube-api-access-5x2kq)", "RoleBinding", "Role pod-reader", "get,list pods"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Permissions section should contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "403") {
+		t.Error("a bound ServiceAccount should not be flagged")
+	}
+
+	d.SetAccessChecks([]repository.AccessCheck{
+		{Verb: "get", Resource: "pods", Allowed: true},
+		{Verb: "get", Resource: "secrets", Reason: "no RBAC policy matched"},
+	}, nil)
+	out = d.renderDetailedResources()
+	for _, want := range []string{"Access:", "✓ get pods", "✗ get secrets", "no RBAC policy matched"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Permissions section should contain %q, got:\n%s", want, out)
+		}
+	}
+
+	d.SetAccessChecks(nil, repository.ErrImpersonationForbidden)
+	if out := d.renderDetailedResources(); !strings.Contains(out, "you may not impersonate the ServiceAccount") {
+		t.Errorf("Permissions section should say the access could not be checked, got:\n%s", out)
+	}
+
+	d.SetAccessChecks(nil, nil)
+	d.SetRelated(&repository.RelatedResources{RBAC: &repository.PodRBAC{ServiceAccount: "default", Exists: true}})
+	out = d.renderDetailedResources()
+	for _, want := range []string{"No RoleBindings or ClusterRoleBindings reference default", "automount disabled"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("default ServiceAccount without bindings should show %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestDashboard_DetailedResourcesLinks(t *testing.T) {
+	d := NewDashboard()
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default"})
+	d.SetRelated(&repository.RelatedResources{
+		Services:   []repository.ServiceInfo{{Name: "web", Type: "ClusterIP"}},
+		Ingresses:  []repository.IngressInfo{{Name: "web-ing"}},
+		ConfigMaps: []string{"web-config"},
+		Secrets:    []string{"web-secret"},
+	})
+
+	content, links := d.detailedResources()
+	if len(links) != 4 {
+		t.Fatalf("got %d links, want 4 (service, ingress, configmap, secret)", len(links))
+	}
+	lines := strings.Split(content, "\n")
+	for _, l := range links {
+		if l.Line >= len(lines) || !strings.Contains(lines[l.Line], l.Name) {
+			t.Errorf("link %s %s points at line %d, which does not show it", l.Kind, l.Name, l.Line)
+		}
+	}
+	if links[0].Kind != "Service" || links[1].Kind != "Ingress" || links[2].Kind != "ConfigMap" || links[3].Kind != "Secret" {
+		t.Errorf("unexpected link kinds: %+v", links)
+	}
+}
+
+func TestDashboard_DescribeFromDetails(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default"})
+	d.SetRelated(&repository.RelatedResources{Services: []repository.ServiceInfo{{Name: "web"}}})
+	d.showDetailedResources()
+
+	d, cmd := d.Update(component.ResultViewerOpenLinkMsg{Index: 0, Link: component.ResultLink{Kind: "Service", Name: "web"}})
+	if cmd == nil {
+		t.Fatal("opening a link should request a describe")
+	}
+	req, ok := cmd().(DescribeResourceRequest)
+	if !ok || req.Kind != "Service" || req.Namespace != "default" || req.Name != "web" {
+		t.Fatalf("unexpected request %+v", req)
+	}
+
+	d, _ = d.Update(DescribeOutputMsg{Title: "Service: web", Content: "Name: web\n"})
+	if d.resultViewer.Title() != "Service: web" {
+		t.Fatalf("describe output should be shown, title %q", d.resultViewer.Title())
+	}
+
+	// Closing the describe returns to Resource Details with the link selected
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !d.resultViewer.IsVisible() || !strings.HasPrefix(d.resultViewer.Title(), "Resource Details") {
+		t.Fatalf("closing should return to Resource Details, title %q", d.resultViewer.Title())
+	}
+	if l := d.resultViewer.SelectedLink(); l == nil || l.Name != "web" {
+		t.Errorf("the described link should stay selected, got %+v", l)
+	}
+}
+
+func TestDashboard_RunProbe(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	readiness := &repository.ProbeInfo{Type: "HTTP", Path: "/ready", PortName: "http"}
+	d.SetPod(&repository.PodInfo{
+		Name: "web-1", Namespace: "default", IP: "10.0.0.7",
+		Containers: []repository.ContainerInfo{{
+			Name: "app", ReadinessProbe: readiness,
+			Ports: []repository.ContainerPort{{Name: "http", ContainerPort: 8080}},
+		}},
+		EphemeralContainers: []repository.ContainerInfo{
+			{Name: "debugger-a", Image: repository.BusyboxImage, State: "Running"},
+			{Name: "debugger-b", Image: repository.NetshootImage, State: "Running"},
+		},
+	})
+
+	d, cmd := d.Update(component.PodActionMenuResult{Item: component.PodActionItem{Action: "run-probe", Target: "app/Readiness"}})
+	if cmd == nil {
+		t.Fatal("running a probe should send a request")
+	}
+	req, ok := cmd().(ProbeRunRequest)
+	if !ok || req.Config != readiness || req.PodIP != "10.0.0.7" || len(req.Ports) != 1 || req.Netshoot != "debugger-b" {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if _, cmd := d.Update(component.PodActionMenuResult{Item: component.PodActionItem{Action: "run-probe", Target: "app/Liveness"}}); cmd != nil {
+		t.Error("a probe the container doesn't have should send nothing")
+	}
+
+	d, _ = d.Update(ProbeRunMsg{Request: req, Result: &repository.ProbeRunResult{
+		Tool: "curl", Outcome: repository.ProbeSucceeded, StatusCode: 200, Latency: 3 * time.Millisecond,
+	}})
+	if !d.resultViewer.IsVisible() || !strings.Contains(d.statusMsg, "HTTP 200 in 3ms") {
+		t.Errorf("the result should be shown, status %q", d.statusMsg)
+	}
+}
+
+func TestDashboard_Watch(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default", Containers: []repository.ContainerInfo{{Name: "app"}}})
+
+	_, cmd := d.Update(component.PodActionMenuResult{Item: component.PodActionItem{Action: "watch", Target: string(repository.WatchReady)}})
+	if cmd == nil {
+		t.Fatal("setting a watch should send a request")
+	}
+	req, ok := cmd().(WatchRequest)
+	if !ok || req.Namespace != "default" || req.PodName != "web-1" || req.Condition != repository.WatchReady {
+		t.Errorf("unexpected request %+v", req)
+	}
+}
+
+func TestDashboard_ServiceCheck(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default", Containers: []repository.ContainerInfo{{Name: "app"}}})
+	d.SetRelated(&repository.RelatedResources{
+		Services:   []repository.ServiceInfo{{Name: "web"}},
+		ConfigMaps: []string{"web-config"},
+	})
+	d.showDetailedResources()
+
+	d, cmd := d.Update(component.ResultViewerCheckLinkMsg{Link: component.ResultLink{Kind: "Service", Name: "web"}})
+	if cmd == nil {
+		t.Fatal("checking a Service should send a request")
+	}
+	req, ok := cmd().(ServiceCheckRequest)
+	if !ok || req.Service != "web" || req.PodName != "web-1" || req.Container != "app" || req.Netshoot {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if content, _ := d.detailedResources(); !strings.Contains(content, "testing...") {
+		t.Error("Resource Details should show the check running")
+	}
+
+	d, _ = d.Update(ServiceCheckMsg{PodName: "web-1", Service: "web", Check: &repository.ServiceCheck{
+		Service:   "web",
+		Host:      "web.default.svc",
+		Container: "app",
+		Addresses: []string{"10.96.0.5"},
+		Ports: []repository.ServicePortCheck{
+			{Port: 80, Protocol: "TCP", Name: "http", Reachable: true, Latency: 2 * time.Millisecond},
+			{Port: 443, Protocol: "TCP", Name: "https", Error: "Connection refused"},
+		},
+	}})
+	content, links := d.detailedResources()
+	for _, want := range []string{"web.default.svc → 10.96.0.5", "80/TCP http", "✓ 2ms", "✗ Connection refused"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Resource Details should show %q:\n%s", want, content)
+		}
+	}
+	lines := strings.Split(content, "\n")
+	for _, l := range links {
+		if !strings.Contains(lines[l.Line], l.Name) {
+			t.Errorf("link %s points at line %d, which does not show it", l.Name, l.Line)
+		}
+	}
+	if !strings.Contains(d.resultViewer.View(), "Connection refused") {
+		t.Error("the open Resource Details should be refreshed with the result")
+	}
+
+	// Results belong to the pod they were run from
+	d.SetPod(&repository.PodInfo{Name: "web-2", Namespace: "default"})
+	if content, _ := d.detailedResources(); strings.Contains(content, "Connection refused") {
+		t.Error("another pod should not show the previous pod's results")
+	}
+
+	// Injecting netshoot changes the pod, so it asks first
+	d, _ = d.Update(component.ResultViewerCheckLinkMsg{Link: component.ResultLink{Kind: "Service", Name: "web"}, Netshoot: true})
+	if !d.confirmDialog.IsVisible() {
+		t.Error("checking from netshoot should ask for confirmation")
+	}
+}
+
+func TestDashboard_DiagnosisKey(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default"})
+
+	// Without a diagnosis the key does nothing
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	if d.resultViewer.IsVisible() {
+		t.Fatal("i without a diagnosis should not open the viewer")
+	}
+
+	d.SetDiagnosis(&repository.CrashDiagnosis{
+		Cause:     repository.CauseAppError,
+		Container: "main",
+		Summary:   "Exit 1: container main failed with an application error",
+		Reasoning: []string{"Termination message: missing DATABASE_URL"},
+	})
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	if !d.resultViewer.IsVisible() || d.resultViewer.Title() != "Diagnosis: web-1" {
+		t.Fatalf("i should open the diagnosis, title %q", d.resultViewer.Title())
+	}
+	out := renderDiagnosis(d.manifest.Diagnosis())
+	for _, want := range []string{"Exit 1: container main", "Container:", "missing DATABASE_URL"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diagnosis should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestDashboard_LogsWorkloadMode(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default"})
+	d.SetLogsWorkload("web")
+
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	if !d.LogsWorkloadMode() {
+		t.Fatal("M on the logs panel should merge the workload's pods")
+	}
+	d.SetLogsWorkload("")
+	if d.LogsWorkloadMode() {
+		t.Error("a pod without a workload should not stay in workload mode")
+	}
+}
+
+func TestDashboard_SetLimitRanges(t *testing.T) {
+	d := NewDashboard()
+	d.SetPod(&repository.PodInfo{
+		Name:      "web",
+		Namespace: "default",
+		Containers: []repository.ContainerInfo{{
+			Name:      "app",
+			Resources: repository.ResourceRequirements{CPURequest: "0", CPULimit: "0", MemoryRequest: "128Mi", MemoryLimit: "2Gi"},
+		}},
+	})
+	d.SetLimitRanges([]repository.ContainerLimitRange{{
+		Name:    "limits",
+		Max:     corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		Default: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+	}})
+
+	out := d.renderDetailedResources()
+	if !strings.Contains(out, "memory limit 2Gi exceeds LimitRange max 1Gi (limits)") {
+		t.Error("container detail should show the LimitRange violation")
+	}
+	if !strings.Contains(out, "default cpu limit: 500m (limits)") {
+		t.Error("container detail should show the defaulted cpu limit")
+	}
+}
+
+func TestDashboard_ShareLink(t *testing.T) {
+	d := NewDashboard()
+	if d.ShareLink() != "" {
+		t.Error("ShareLink() without a pod should be empty")
+	}
+
+	d.SetContext("arn:aws:eks:us-east-1:123:cluster/prod")
+	d.SetPod(&repository.PodInfo{
+		Name:       "web-abc123",
+		Namespace:  "shop",
+		Containers: []repository.ContainerInfo{{Name: "app"}, {Name: "proxy"}},
+	})
+	if got, want := d.ShareLink(), "k1s://arn:aws:eks:us-east-1:123:cluster%2Fprod/shop/pod/web-abc123?view=logs"; got != want {
+		t.Errorf("ShareLink() = %q, want %q", got, want)
+	}
+
+	if !d.SelectContainer("proxy") {
+		t.Fatal("SelectContainer() failed for an existing container")
+	}
+	d.FocusView("events")
+	if got, want := d.ShareLink(), "k1s://arn:aws:eks:us-east-1:123:cluster%2Fprod/shop/pod/web-abc123?container=proxy&view=events"; got != want {
+		t.Errorf("ShareLink() = %q, want %q", got, want)
+	}
+
+	d.FocusView("unknown")
+	if d.focus != FocusEvents {
+		t.Errorf("FocusView() with an unknown view changed focus to %v", d.focus)
+	}
+	d.FocusView("details")
+	if d.focus != FocusManifest {
+		t.Errorf("focus = %v, want FocusManifest", d.focus)
+	}
+}
+
 func TestDashboard_SetBreadcrumb(t *testing.T) {
 	d := NewDashboard()
 	d.SetSize(100, 40)
@@ -441,6 +947,353 @@ func TestPanelFocus_Constants(t *testing.T) {
 	}
 }
 
+func TestDashboard_DeletePodConfirmLevels(t *testing.T) {
+	pod := &repository.PodInfo{Name: "web-1", Namespace: "default"}
+	deleteItem := component.PodActionMenuResult{Item: component.PodActionItem{Action: "delete"}}
+
+	// Default (no resolver) asks yes/no
+	d := NewDashboard()
+	d.SetPod(pod)
+	d, cmd := d.Update(deleteItem)
+	if cmd != nil || !d.confirmDialog.IsVisible() {
+		t.Error("delete without resolver should show the confirm dialog")
+	}
+
+	// "none" skips the dialog but still goes through ConfirmResult handling
+	d = NewDashboard()
+	d.SetPod(pod)
+	var asked []string
+	d.SetConfirmLevelFunc(func(namespace, action string) configs.ConfirmLevel {
+		asked = append(asked, action)
+		return configs.ConfirmNone
+	})
+	d, cmd = d.Update(deleteItem)
+	if d.confirmDialog.IsVisible() {
+		t.Error("ConfirmNone should not show the dialog")
+	}
+	if len(asked) != 1 || asked[0] != configs.ActionDeletePod {
+		t.Errorf("resolver asked for %v, want [%s]", asked, configs.ActionDeletePod)
+	}
+	if cmd == nil {
+		t.Fatal("ConfirmNone should return a command")
+	}
+	result, ok := cmd().(component.ConfirmResult)
+	if !ok || !result.Confirmed || !result.Skipped {
+		t.Fatalf("command returned %+v, want skipped confirmation", result)
+	}
+
+	_, cmd = d.Update(result)
+	if cmd == nil {
+		t.Fatal("skipped confirmation should still run the delete")
+	}
+	req, ok := cmd().(DeletePodRequest)
+	if !ok || req.PodName != "web-1" {
+		t.Errorf("command returned %+v, want DeletePodRequest for web-1", req)
+	}
+}
+
+func TestDashboard_DeleteStuckPodOffersForceDelete(t *testing.T) {
+	pod := &repository.PodInfo{Name: "web-0", Namespace: "default", UID: "uid-1",
+		DeletionTimestamp: time.Now().Add(-time.Minute)}
+	d := NewDashboard()
+	d.SetPod(pod)
+	var asked []string
+	d.SetConfirmLevelFunc(func(namespace, action string) configs.ConfirmLevel {
+		asked = append(asked, action)
+		return configs.ConfirmYesNo
+	})
+
+	d, _ = d.Update(component.PodActionMenuResult{Item: component.PodActionItem{Action: "delete"}})
+	if !d.confirmDialog.IsVisible() {
+		t.Fatal("deleting a pod past its grace period should ask to force delete")
+	}
+	if len(asked) != 1 || asked[0] != configs.ActionForceDeletePod {
+		t.Errorf("resolver asked for %v, want [%s]", asked, configs.ActionForceDeletePod)
+	}
+	view := d.confirmDialog.View()
+	for _, want := range []string{"still terminating", "keep running", "same identity"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("confirm dialog should spell out the risks (%q):\n%s", want, view)
+		}
+	}
+
+	_, cmd := d.Update(component.ConfirmResult{Confirmed: true, Action: "force-delete",
+		Data: ForceDeletePodRequest{Namespace: "default", PodName: "web-0"}})
+	if cmd == nil {
+		t.Fatal("confirming should force delete")
+	}
+	if req, ok := cmd().(ForceDeletePodRequest); !ok || req.PodName != "web-0" {
+		t.Errorf("command returned %+v, want ForceDeletePodRequest for web-0", req)
+	}
+
+	// A pod still within its grace period is deleted normally, with what
+	// app.go needs to check on it later
+	pod = &repository.PodInfo{Name: "web-1", Namespace: "default", UID: "uid-2", TerminationGracePeriod: 30}
+	d = NewDashboard()
+	d.SetPod(pod)
+	d.SetConfirmLevelFunc(func(string, string) configs.ConfirmLevel { return configs.ConfirmNone })
+	_, cmd = d.Update(component.PodActionMenuResult{Item: component.PodActionItem{Action: "delete"}})
+	_, cmd = d.Update(cmd())
+	if req, ok := cmd().(DeletePodRequest); !ok || req.UID != "uid-2" || req.GracePeriod != 30 {
+		t.Errorf("command returned %+v, want DeletePodRequest with UID and grace period", req)
+	}
+}
+
+func TestDashboard_ExecConfirmRequestsShell(t *testing.T) {
+	pod := &repository.PodInfo{Name: "web-1", Namespace: "default"}
+	execItem := component.PodActionMenuResult{Item: component.PodActionItem{Action: "exec", Target: "sidecar"}}
+
+	d := NewDashboard()
+	d.SetPod(pod)
+	d, cmd := d.Update(execItem)
+	if cmd != nil || !d.confirmDialog.IsVisible() {
+		t.Fatal("exec should ask for confirmation first")
+	}
+
+	d, cmd = d.Update(component.ConfirmResult{Confirmed: true, Action: "exec", Data: pod})
+	if cmd == nil {
+		t.Fatal("confirmed exec should return a command")
+	}
+	req, ok := cmd().(ExecRequest)
+	if !ok || req != (ExecRequest{Namespace: "default", PodName: "web-1", Container: "sidecar"}) {
+		t.Errorf("command returned %+v, want ExecRequest for default/web-1 sidecar", req)
+	}
+	if d.pendingAction != nil {
+		t.Error("pending action should be cleared")
+	}
+}
+
+func TestDashboard_DebugContainer(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default", Containers: []repository.ContainerInfo{{Name: "app"}}})
+
+	item := component.PodActionMenuResult{Item: component.PodActionItem{Action: "debug-container", Target: "app/" + repository.NetshootImage}}
+	d, cmd := d.Update(item)
+	if cmd != nil || !d.confirmDialog.IsVisible() {
+		t.Fatal("adding a debug container should ask for confirmation first")
+	}
+
+	want := DebugContainerRequest{Namespace: "default", PodName: "web-1", Target: "app", Image: repository.NetshootImage}
+	d, cmd = d.Update(component.ConfirmResult{Confirmed: true, Action: "debug-container", Data: want})
+	if cmd == nil {
+		t.Fatal("confirming should return a command")
+	}
+	if req, ok := cmd().(DebugContainerRequest); !ok || req != want {
+		t.Errorf("command returned %+v, want %+v", req, want)
+	}
+
+	d, _ = d.Update(DebugContainerMsg{PodName: "web-1", Err: repository.ErrEphemeralContainersUnsupported})
+	if !strings.Contains(d.statusMsg, "not supported by this cluster") {
+		t.Errorf("statusMsg = %q, want the unsupported cluster explained", d.statusMsg)
+	}
+
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default", EphemeralContainers: []repository.ContainerInfo{
+		{Name: "k1s-debug-1", Image: repository.BusyboxImage, State: "Running", TargetContainer: "app"},
+	}})
+	content, _ := d.detailedResources()
+	for _, want := range []string{"Ephemeral Containers", "k1s-debug-1", "Target: app"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Resource Details should contain %q", want)
+		}
+	}
+}
+
+func TestDashboard_WebhookDenied(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default"})
+	denial := `Error creating: admission webhook "validate.policy.example.com" denied the request: image tag latest is not allowed`
+	d.SetRelated(&repository.RelatedResources{
+		Owner: &repository.OwnerInfo{Kind: "ReplicaSet", Name: "web-7d9f", Failures: []string{denial}},
+		Webhooks: []repository.WebhookInfo{
+			{Configuration: "policy", Kind: "Validating", Name: "validate.policy.example.com", FailurePolicy: "Fail", Timeout: 10, Backend: "policy/webhook:443/validate", Operations: []string{"CREATE"}, Resources: []string{"pods"}},
+			{Configuration: "sidecars", Kind: "Mutating", Name: "inject.sidecars.example.com", FailurePolicy: "Ignore", Timeout: 5},
+		},
+	})
+
+	content, _ := d.detailedResources()
+	for _, want := range []string{
+		"Webhooks", "✗ validate.policy.example.com (denied a recent request) [Validating policy]",
+		"Rules:     CREATE pods", "Policy:    Fail, timeout 10s", "Backend:   policy/webhook:443/validate", denial,
+		"• inject.sidecars.example.com [Mutating sidecars]", "Policy:    Ignore, timeout 5s",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Resource Details should contain %q, got:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "✗ inject.sidecars") {
+		t.Error("only the webhook that denied a request should be highlighted")
+	}
+}
+
+func TestDashboard_PodSecurity(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(160, 60)
+	user, group, yes, no := int64(1000), int64(2000), true, false
+	pod := &repository.PodInfo{
+		Name:            "web-1",
+		Namespace:       "prod",
+		Annotations:     map[string]string{"container.apparmor.security.beta.kubernetes.io/app": "runtime/default"},
+		SecurityContext: &repository.PodSecurityContext{RunAsUser: &user, RunAsNonRoot: &yes, FSGroup: &group, SeccompProfile: "RuntimeDefault"},
+		Containers: []repository.ContainerInfo{{
+			Name:  "app",
+			Ports: []repository.ContainerPort{{ContainerPort: 80}},
+			SecurityContext: &repository.SecurityContextInfo{
+				ReadOnlyRoot:             &yes,
+				AllowPrivilegeEscalation: &no,
+				CapabilitiesDrop:         []string{"ALL"},
+			},
+		}},
+	}
+	d.SetPod(pod)
+
+	content, _ := d.detailedResources()
+	for _, want := range []string{"Pod Security", "FS Group:              2000", "Admission:             loading...", "Run As User:       1000 (pod)"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Resource Details should contain %q before the report loads, got:\n%s", want, content)
+		}
+	}
+
+	d.SetSecurity(repository.BuildPodSecurity(pod, nil, repository.PodSecurityAdmission{Enforce: repository.PodSecurityRestricted, EnforceVersion: "v1.29"}))
+	content, _ = d.detailedResources()
+	for _, want := range []string{
+		"Enforce:               restricted (v1.29)", "Warn:                  not set", "✓ Meets restricted",
+		"Run As Non-Root:   yes (pod)", "Privilege Escal.:  no", "Read-Only Root:    yes", "Capabilities:      drop ALL",
+		"Seccomp:           RuntimeDefault (pod)", "AppArmor:          runtime/default",
+		"✗ port 80 is below 1024 without NET_BIND_SERVICE",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Resource Details should contain %q, got:\n%s", want, content)
+		}
+	}
+
+	pod.HostNamespaces = []string{"network"}
+	d.SetSecurity(repository.BuildPodSecurity(pod, nil, repository.PodSecurityAdmission{Enforce: repository.PodSecurityBaseline}))
+	content, _ = d.detailedResources()
+	for _, want := range []string{"Host Namespaces:       network", "✗ Violates baseline: a new pod like it would be rejected", "• host namespaces (hostNetwork=true)"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Resource Details should contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestDashboard_ConfigProjection(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(140, 40)
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default"})
+
+	_, cmd := d.Update(component.ResultViewerProjectLinkMsg{Index: 1, Link: component.ResultLink{Kind: "Secret", Name: "db"}})
+	if cmd == nil {
+		t.Fatal("p on a Secret link should request its projection")
+	}
+	if req, ok := cmd().(ConfigProjectionRequest); !ok || req.PodName != "web-1" || req.Kind != "Secret" || req.Name != "db" {
+		t.Fatalf("expected a ConfigProjectionRequest for Secret db, got %+v", req)
+	}
+
+	d, _ = d.Update(ConfigProjectionMsg{PodName: "web-1", Projection: &repository.ConfigProjection{
+		Kind: "Secret", Name: "db", Namespace: "default", Found: true,
+		Data:        map[string]string{"password": "hunter2"},
+		Projections: []repository.KeyProjection{{Key: "password", Container: "app", Target: "/etc/db/password", Source: "volume db"}},
+	}})
+	if !d.projection.IsVisible() {
+		t.Fatal("the projection should open once resolved")
+	}
+
+	// Revealing goes through a confirmation
+	d, _ = d.Update(component.RevealSecretKeyRequest{Secret: "db", Key: "password"})
+	if !d.confirmDialog.IsVisible() {
+		t.Fatal("revealing a Secret value should ask for confirmation")
+	}
+	if strings.Contains(d.View(), "hunter2") {
+		t.Error("the value should stay masked until confirmed")
+	}
+	d.confirmDialog.Hide()
+	d, _ = d.Update(component.ConfirmResult{Confirmed: true, Action: "reveal-secret-key", Data: component.RevealSecretKeyRequest{Secret: "db", Key: "password"}})
+	if !strings.Contains(d.projection.View(), "hunter2") {
+		t.Error("a confirmed reveal should show the value")
+	}
+}
+
+func TestDashboard_RolloutActions(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 50)
+	d.SetPod(&repository.PodInfo{Name: "web-abc", Namespace: "default", Status: "Running"})
+	d.SetRelated(&repository.RelatedResources{Owner: &repository.OwnerInfo{
+		WorkloadKind: "Rollout", WorkloadName: "web", Replicas: 3,
+	}})
+
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if !strings.Contains(d.podActionMenu.View(), "Rollout actions") {
+		t.Error("pod actions should offer the rollout actions for a pod owned by a Rollout")
+	}
+	d.podActionMenu.Hide()
+
+	item := component.PodActionMenuResult{Item: component.PodActionItem{Action: "rollout-actions", Target: "web"}}
+	_, cmd := d.Update(item)
+	if cmd == nil {
+		t.Fatal("rollout-actions should return a command")
+	}
+	req, ok := cmd().(RolloutActionsRequest)
+	if !ok || req != (RolloutActionsRequest{Namespace: "default", Name: "web", Replicas: 3}) {
+		t.Errorf("command returned %+v, want RolloutActionsRequest for default/web", req)
+	}
+}
+
+func TestDashboard_ResourceYAML(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 50)
+	d.SetPod(&repository.PodInfo{Name: "web-abc", Namespace: "default", Status: "Running"})
+	d.SetRelated(&repository.RelatedResources{Owner: &repository.OwnerInfo{
+		WorkloadKind: "Deployment", WorkloadName: "web", Replicas: 3,
+	}})
+
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	menu := d.podActionMenu.View()
+	for _, want := range []string{"Copy Pod YAML", "Save Pod YAML to file", "Copy Deployment YAML", "Save Deployment YAML to file"} {
+		if !strings.Contains(menu, want) {
+			t.Errorf("pod actions should offer %q", want)
+		}
+	}
+	d.podActionMenu.Hide()
+
+	item := component.PodActionMenuResult{Item: component.PodActionItem{Action: "save-yaml", Target: "Deployment/web"}}
+	_, cmd := d.Update(item)
+	if cmd == nil {
+		t.Fatal("save-yaml should return a command")
+	}
+	req, ok := cmd().(ResourceYAMLRequest)
+	if !ok || req != (ResourceYAMLRequest{Namespace: "default", Kind: "Deployment", Name: "web", Save: true}) {
+		t.Errorf("command returned %+v, want ResourceYAMLRequest to save default/web", req)
+	}
+
+	d, _ = d.Update(ResourceYAMLResultMsg{Kind: "Pod", Name: "web-abc"})
+	if d.statusMsg != "Copied Pod web-abc YAML" {
+		t.Errorf("statusMsg = %q", d.statusMsg)
+	}
+}
+
+func TestDashboard_ConfirmLevelUsesPodNamespace(t *testing.T) {
+	pod := &repository.PodInfo{Name: "coredns-1", Namespace: "kube-system"}
+	gateItem := component.PodActionMenuResult{Item: component.PodActionItem{Action: "remove-gate", Target: "example.com/gate"}}
+
+	d := NewDashboard()
+	d.SetPod(pod)
+	var asked []string
+	d.SetConfirmLevelFunc(func(namespace, action string) configs.ConfirmLevel {
+		asked = append(asked, namespace+"/"+action)
+		return configs.ConfirmTyped
+	})
+	d, cmd := d.Update(gateItem)
+	if cmd != nil || !d.confirmDialog.IsVisible() {
+		t.Error("typed confirmation should show the dialog")
+	}
+	want := "kube-system/" + configs.ActionRemoveSchedulingGate
+	if len(asked) != 1 || asked[0] != want {
+		t.Errorf("resolver asked for %v, want [%s]", asked, want)
+	}
+}
+
 // Struct tests
 func TestDeletePodRequest_Struct(t *testing.T) {
 	req := DeletePodRequest{
@@ -961,3 +1814,213 @@ func TestDashboard_View_Fullscreen(t *testing.T) {
 		t.Error("Fullscreen view should not be empty")
 	}
 }
+
+func TestDashboard_PortForward(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default", Containers: []repository.ContainerInfo{
+		{Name: "app", Ports: []repository.ContainerPort{{ContainerPort: 9090, Protocol: "TCP"}}},
+	}})
+	d.SetRelated(&repository.RelatedResources{Services: []repository.ServiceInfo{{Name: "web", PortNumbers: []int32{80}}}})
+
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	menu := d.podActionMenu.View()
+	for _, want := range []string{"Port Forward :8080", "Port Forward :9090 (app)", "Port Forward svc/web :80"} {
+		if !strings.Contains(menu, want) {
+			t.Errorf("pod actions should offer %q", want)
+		}
+	}
+	d.podActionMenu.Hide()
+
+	item := component.PodActionMenuResult{Item: component.PodActionItem{Action: "port-forward", Target: "svc/web:80"}}
+	d, cmd := d.Update(item)
+	if cmd == nil || d.confirmDialog.IsVisible() {
+		t.Fatal("a port-forward should start without confirmation")
+	}
+	want := PortForwardRequest{Namespace: "default", PodName: "web-1", Service: "web", Port: 80}
+	if req, ok := cmd().(PortForwardRequest); !ok || req != want {
+		t.Errorf("command returned %+v, want %+v", req, want)
+	}
+
+	d, _ = d.Update(PortForwardMsg{Request: want, Forward: repository.PortForward{Pod: "web-1", LocalPort: 80, RemotePort: 8080}})
+	if d.statusMsg != "Forwarding localhost:80 -> web-1:8080 (F to list)" {
+		t.Errorf("statusMsg = %q", d.statusMsg)
+	}
+}
+
+func TestDashboard_NodeActionsFromResourceUsage(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 50)
+	d.SetPod(&repository.PodInfo{Name: "test", Namespace: "default", Status: "Running", Node: "node-a"})
+	d.SetNode(&repository.NodeInfo{Name: "node-a", Unschedulable: true})
+	d.focus = FocusMetrics
+
+	// Container Resources focused: 'a' still opens the pod actions
+	d, cmd := d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if cmd != nil || !d.podActionMenu.IsVisible() {
+		t.Fatal("'a' on Container Resources should open the pod actions")
+	}
+	d.podActionMenu.Hide()
+
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyRight})
+	_, cmd = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if cmd == nil {
+		t.Fatal("'a' on Node Info should request the node actions")
+	}
+	req, ok := cmd().(NodeActionsRequest)
+	if !ok || req.Node != "node-a" || !req.Cordoned {
+		t.Errorf("msg = %+v, want NodeActionsRequest for cordoned node-a", req)
+	}
+}
+
+func TestDashboard_RestartPodReattachesToReplacement(t *testing.T) {
+	controller := true
+	owned := func(name string, uid types.UID) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       uid,
+				Labels:    map[string]string{"app": "web"},
+				OwnerReferences: []metav1.OwnerReference{{
+					Kind: "ReplicaSet", Name: "web-7d9f", UID: "rs-uid", Controller: &controller,
+				}},
+			},
+		}
+	}
+	clientset := fake.NewSimpleClientset(owned("web-7d9f-abcde", "old-uid"))
+	// Act as the ReplicaSet controller: deleting the pod creates its replacement
+	clientset.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gvr := corev1.SchemeGroupVersion.WithResource("pods")
+		return false, nil, clientset.Tracker().Create(gvr, owned("web-7d9f-fghij", "new-uid"), "default")
+	})
+
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	d.SetPod(&repository.PodInfo{Name: "web-7d9f-abcde", Namespace: "default", Status: "Running"})
+
+	// Confirming the restart asks app.go to restart the pod
+	d, cmd := d.Update(component.ConfirmResult{
+		Confirmed: true,
+		Action:    "restart-pod",
+		Data:      RestartPodRequest{Namespace: "default", PodName: "web-7d9f-abcde"},
+	})
+	if cmd == nil {
+		t.Fatal("confirming the restart should return a command")
+	}
+	req, ok := cmd().(RestartPodRequest)
+	if !ok || req.PodName != "web-7d9f-abcde" {
+		t.Fatalf("msg = %+v, want RestartPodRequest for web-7d9f-abcde", req)
+	}
+
+	// What app.go does with the request
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pod, err := repository.RestartPod(ctx, clientset, req.Namespace, req.PodName)
+	d, _ = d.Update(PodRestartedMsg{OldName: req.PodName, Pod: pod, Err: err})
+
+	if d.pod == nil || d.pod.Name != "web-7d9f-fghij" {
+		t.Errorf("selected pod = %v, want the replacement web-7d9f-fghij", d.pod)
+	}
+	if !strings.Contains(d.statusMsg, "web-7d9f-fghij") {
+		t.Errorf("statusMsg = %q, want it to name the replacement", d.statusMsg)
+	}
+}
+
+func TestDashboard_PodRestartedForOtherPodIgnored(t *testing.T) {
+	d := NewDashboard()
+	d.SetPod(&repository.PodInfo{Name: "api-0", Namespace: "default"})
+
+	d, _ = d.Update(PodRestartedMsg{OldName: "web-0", Pod: &repository.PodInfo{Name: "web-1"}})
+	if d.pod.Name != "api-0" {
+		t.Errorf("selected pod = %q, want api-0 kept", d.pod.Name)
+	}
+}
+
+func TestDashboard_LogsAroundEvent(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	d.focus = FocusEvents
+
+	at := time.Date(2024, 3, 10, 10, 42, 0, 0, time.UTC)
+	d, _ = d.Update(component.EventLogsRequest{Time: at})
+	r := d.LogsTimeRange()
+	if !r.Since.Equal(at.Add(-2*time.Minute)) || !r.Until.Equal(at.Add(2*time.Minute)) {
+		t.Errorf("LogsTimeRange() = %v..%v, want ±2 minutes around %v", r.Since, r.Until, at)
+	}
+	if d.focus != FocusLogs || d.LogsFollowing() {
+		t.Error("the logs panel should get the focus and stop following")
+	}
+}
+
+func TestDashboard_FrozenPanels(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default"})
+	if d.LogsFrozen() || d.EventsFrozen() {
+		t.Fatal("no panel should be frozen outside fullscreen")
+	}
+
+	d.fullscreen = true
+	if !d.LogsFrozen() || d.EventsFrozen() {
+		t.Error("the fullscreen logs panel should be frozen, and only it")
+	}
+	d.focus = FocusEvents
+	if d.LogsFrozen() || !d.EventsFrozen() {
+		t.Error("the fullscreen events panel should be frozen, and only it")
+	}
+}
+
+func TestDashboard_IngressTrace(t *testing.T) {
+	d := NewDashboard()
+	d.SetSize(120, 40)
+	d.SetPod(&repository.PodInfo{Name: "web-1", Namespace: "default"})
+	d.SetRelated(&repository.RelatedResources{
+		Services:  []repository.ServiceInfo{{Name: "web"}},
+		Ingresses: []repository.IngressInfo{{Name: "shop", Hosts: []string{"shop.example.com"}}},
+		VirtualServices: []repository.VirtualServiceInfo{
+			{Name: "shop-vs", Hosts: []string{"*.example.com"}},
+			{Name: "blog-vs", Hosts: []string{"blog.example.org"}},
+		},
+	})
+	d.showDetailedResources()
+
+	d, cmd := d.Update(component.ResultViewerCheckLinkMsg{Link: component.ResultLink{Kind: "Ingress", Name: "shop"}})
+	if cmd == nil {
+		t.Fatal("tracing an Ingress should send a request")
+	}
+	req, ok := cmd().(IngressTraceRequest)
+	if !ok || req.Ingress.Name != "shop" || req.PodName != "web-1" {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if len(req.VirtualServices) != 1 || req.VirtualServices[0].Name != "shop-vs" {
+		t.Errorf("request VirtualServices = %+v, want the one with overlapping hosts", req.VirtualServices)
+	}
+	if content, _ := d.detailedResources(); !strings.Contains(content, "tracing...") {
+		t.Error("Resource Details should show the trace running")
+	}
+
+	d, _ = d.Update(IngressTraceMsg{PodName: "web-1", Ingress: "shop", Traces: []repository.RouteTrace{{
+		Source: "Ingress/shop",
+		Route:  "shop.example.com/ → web:80",
+		Hops: []repository.TraceHop{
+			{Kind: repository.HopService, Name: "web", OK: true, Detail: "port 80 → targetPort 8080"},
+			{Kind: repository.HopEndpoints, Name: "web", Detail: "no ready endpoints (2 not ready)"},
+		},
+	}}})
+	content, links := d.detailedResources()
+	for _, want := range []string{"shop.example.com/ → web:80", "✓ Service    web: port 80", "✗ Endpoints  web: no ready endpoints"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Resource Details should show %q:\n%s", want, content)
+		}
+	}
+	lines := strings.Split(content, "\n")
+	for _, l := range links {
+		if !strings.Contains(lines[l.Line], l.Name) {
+			t.Errorf("link %s points at line %d, which does not show it", l.Name, l.Line)
+		}
+	}
+	if !strings.Contains(d.statusMsg, "traffic stops at endpoints") {
+		t.Errorf("status = %q, want where traffic stops", d.statusMsg)
+	}
+}
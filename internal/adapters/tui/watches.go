@@ -0,0 +1,115 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains the watches that notify when a pod or workload meets a condition.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// addWatch starts watching target for condition. Watches live on the
+// model, so they keep being checked whatever view is open.
+func (m *Model) addWatch(target repository.WorkloadInfo, condition repository.WatchCondition) tea.Cmd {
+	for _, w := range m.watches {
+		if w.Condition == condition && w.Target.Type == target.Type &&
+			w.Target.Namespace == target.Namespace && w.Target.Name == target.Name {
+			m.statusMsg = "Already watching " + w.TargetName()
+			return clearStatusAfter(3 * time.Second)
+		}
+	}
+	m.telemetry.Action("watch")
+	m.nextWatchID++
+	w := repository.Watch{ID: m.nextWatchID, Condition: condition, Target: target, Since: time.Now()}
+	m.watches = append(m.watches, w)
+	if m.watchesViewer.IsVisible() {
+		m.watchesViewer.SetWatches(m.watches)
+	}
+	m.statusMsg = fmt.Sprintf("Watching %s: %s (B lists watches)", w.TargetName(), w.Condition.Label())
+	return clearStatusAfter(3 * time.Second)
+}
+
+// removeWatch drops the watch with id, returning it, or false when there
+// is none.
+func (m *Model) removeWatch(id int) (repository.Watch, bool) {
+	for i, w := range m.watches {
+		if w.ID == id {
+			m.watches = append(m.watches[:i:i], m.watches[i+1:]...)
+			if m.watchesViewer.IsVisible() {
+				m.watchesViewer.SetWatches(m.watches)
+			}
+			return w, true
+		}
+	}
+	return repository.Watch{}, false
+}
+
+// checkWatches fetches the pods of every watched target, and the events
+// of their namespaces for warning watches, and evaluates the watches over
+// them. A failed fetch leaves the watch for the next refresh.
+// Returns a watchesCheckedMsg, or nil when nothing is watched.
+func (m *Model) checkWatches() tea.Cmd {
+	if len(m.watches) == 0 {
+		return nil
+	}
+	watches := append([]repository.Watch(nil), m.watches...)
+	clientset := m.k8sClient.Clientset()
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		events := map[string][]repository.EventInfo{}
+		var results []watchResult
+		for _, w := range watches {
+			pods, err := repository.GetWorkloadPods(ctx, clientset, w.Target)
+			if err != nil {
+				if w.Target.Type == repository.ResourcePods && apierrors.IsNotFound(err) {
+					results = append(results, watchResult{id: w.ID, gone: true})
+				}
+				continue
+			}
+			if w.Condition == repository.WatchWarning {
+				if _, ok := events[w.Target.Namespace]; !ok {
+					list, err := repository.GetNamespaceEvents(ctx, clientset, w.Target.Namespace, 0)
+					if err != nil {
+						continue
+					}
+					events[w.Target.Namespace] = list
+				}
+			}
+			fired, detail := repository.EvaluateWatch(w, pods, events[w.Target.Namespace])
+			results = append(results, watchResult{id: w.ID, fired: fired, detail: detail})
+		}
+		return watchesCheckedMsg{results: results}
+	})
+}
+
+// watchesChecked ends the watches whose condition holds, ringing the
+// terminal bell with a desktop notification and leaving a toast until
+// another replaces it, and those whose pod was deleted.
+func (m *Model) watchesChecked(msg watchesCheckedMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, r := range msg.results {
+		if !r.fired && !r.gone {
+			continue
+		}
+		// Cancelled while being checked
+		w, ok := m.removeWatch(r.id)
+		if !ok {
+			continue
+		}
+		n := component.Notification{Op: "watch", Namespace: w.Target.Namespace, Message: r.detail, Sticky: true}
+		if w.Condition == repository.WatchWarning {
+			n.Severity = component.SeverityWarning
+		}
+		if r.gone {
+			n.Severity = component.SeverityWarning
+			n.Message = w.TargetName() + " no longer exists; watch ended"
+		}
+		_ = component.RingBell("k1s: " + n.Message)
+		cmds = append(cmds, m.notify(n))
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,91 @@
+// Package tui provides the terminal user interface for k1s.
+// This file contains viewing and changing the requests and limits of a workload's containers.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/configs"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+	"github.com/andrebassi/k1s/internal/adapters/tui/component"
+)
+
+// resourcesChange is the InputDialogResult and ConfirmResult data for a
+// pending change of a container's requests and limits.
+type resourcesChange struct {
+	workload *repository.WorkloadInfo
+	current  repository.ContainerResources // Requests and limits now
+	requests repository.ResourceAmounts    // Requests typed
+	limits   repository.ResourceAmounts    // Limits typed
+}
+
+// loadWorkloadResources fetches the requests, limits and usage of a
+// workload's containers.
+// Returns a workloadResourcesMsg.
+func (m *Model) loadWorkloadResources(workload *repository.WorkloadInfo) tea.Cmd {
+	return call(m.navCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		containers, err := repository.GetWorkloadResources(ctx, m.k8sClient.Clientset(), m.k8sClient.DynamicClient(), m.k8sClient.MetricsClient(), *workload)
+		return workloadResourcesMsg{workload: workload, containers: containers, err: err}
+	})
+}
+
+// showResources opens the workload action menu with the usage table and
+// a picker of the container whose requests and limits to change.
+func (m *Model) showResources(workload *repository.WorkloadInfo, containers []repository.ContainerResources) {
+	kind := repository.KindForResourceType(workload.Type)
+	m.workloadMenuTarget = workload
+	m.workloadResources = containers
+	m.workloadActionMenu.Show("Resources: "+workload.Name, component.ContainerResourceActions(workload.Namespace, kind, workload.Name, containers))
+}
+
+// editResources opens the current requests and limits of a container for
+// editing.
+func (m *Model) editResources(workload *repository.WorkloadInfo, current *repository.ContainerResources) {
+	if current == nil {
+		return
+	}
+	change := resourcesChange{workload: workload, current: *current}
+	m.inputDialog.Show("Set Resources",
+		fmt.Sprintf("Requests/limits of container %s (- for none):", current.Container),
+		"set_resources", component.FormatResourceInput(current.Requests, current.Limits), change)
+}
+
+// requestSetResources validates the requests and limits typed and asks for
+// confirmation, showing each old and new value, before changing them,
+// which rolls out new pods.
+func (m *Model) requestSetResources(change resourcesChange, input string) tea.Cmd {
+	requests, limits, err := component.ParseResourceInput(input, change.current.Requests, change.current.Limits)
+	if err != nil {
+		m.statusMsg = "Invalid resources: " + err.Error()
+		return clearStatusAfter(5 * time.Second)
+	}
+	changes := component.ResourceChanges(change.current.Requests, change.current.Limits, requests, limits)
+	if len(changes) == 0 {
+		m.statusMsg = fmt.Sprintf("Resources of %s are unchanged", change.current.Container)
+		return clearStatusAfter(3 * time.Second)
+	}
+	change.requests, change.limits = requests, limits
+	return m.confirmDialog.Request(
+		m.confirmLevel(change.workload.Namespace, configs.ActionSetResources),
+		"Set Resources",
+		fmt.Sprintf("Change the resources of container '%s' of '%s'? This rolls out new pods.\n%s",
+			change.current.Container, change.workload.Name, strings.Join(changes, "\n")),
+		"set_resources",
+		change.workload.Name,
+		change,
+	)
+}
+
+// setResources changes the requests and limits of a container.
+// Returns a resourcesSetMsg with the result.
+func (m *Model) setResources(change resourcesChange) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		w := change.workload
+		err := m.k8sClient.SetWorkloadResources(ctx, w.Namespace, w.Name, w.Type, change.current.Container, change.requests, change.limits)
+		return resourcesSetMsg{change: change, err: err}
+	})
+}
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andrebassi/k1s/internal/adapters/repository"
+)
+
+// yamlTarget is the InputDialogResult data for a pending YAML export.
+type yamlTarget struct {
+	namespace string
+	kind      string
+	name      string
+}
+
+// requestResourceYAML copies the YAML of a pod or workload to the
+// clipboard, or for save prompts for the file to write it to.
+func (m *Model) requestResourceYAML(namespace, kind, name string, save bool) tea.Cmd {
+	target := yamlTarget{namespace: namespace, kind: kind, name: name}
+	if !save {
+		m.statusMsg = "Copying " + kind + " YAML..."
+		return m.exportResourceYAML(target, "")
+	}
+	m.inputDialog.Show(
+		"Save "+kind+" YAML",
+		fmt.Sprintf("Write %s '%s' to file:", kind, name),
+		"save_yaml",
+		name+".yaml",
+		target,
+	)
+	return nil
+}
+
+// exportResourceYAML fetches the YAML of a resource. Without a path it is
+// the clean manifest, meant for the clipboard; with one the full object,
+// status included, is written to that file.
+// Returns a resourceYAMLMsg.
+func (m *Model) exportResourceYAML(target yamlTarget, path string) tea.Cmd {
+	return call(m.appCalls, repository.CallList, func(ctx context.Context) tea.Msg {
+		msg := resourceYAMLMsg{kind: target.kind, name: target.name}
+		gvr, ok := repository.GVRForKind(target.kind)
+		if !ok {
+			msg.err = fmt.Errorf("cannot export %s YAML", target.kind)
+			return msg
+		}
+		msg.yaml, msg.err = repository.GetResourceYAML(ctx, m.k8sClient.DynamicClient(), gvr, target.namespace, target.name, path == "")
+		if msg.err != nil || path == "" {
+			return msg
+		}
+		if msg.path, msg.err = filepath.Abs(path); msg.err != nil {
+			return msg
+		}
+		if err := os.WriteFile(msg.path, []byte(msg.yaml), 0o644); err != nil {
+			msg.err = fmt.Errorf("failed to write %s: %w", msg.path, err)
+		}
+		return msg
+	})
+}